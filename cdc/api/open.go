@@ -15,15 +15,23 @@ package api
 
 import (
 	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/httputil"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
+	"github.com/pingcap/tiflow/cdc/puller"
+	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
@@ -39,6 +47,10 @@ const (
 	apiOpVarChangefeedID = "changefeed_id"
 	// apiOpVarCaptureID is the key of capture ID in HTTP API
 	apiOpVarCaptureID = "capture_id"
+	// apiOpVarTableID is the key of table ID in HTTP API
+	apiOpVarTableID = "table_id"
+	// apiOpVarFinalBarrier is the key of the final barrier option of removing a changefeed in HTTP API
+	apiOpVarFinalBarrier = "final_barrier"
 	// forWardFromCapture is a header to be set when a request is forwarded from another capture
 	forWardFromCapture = "TiCDC-ForwardFromCapture"
 )
@@ -78,18 +90,28 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api openAPI) {
 	v1.GET("/status", api.ServerStatus)
 	v1.GET("/health", api.Health)
 	v1.POST("/log", SetLogLevel)
+	v1.GET("/log/components", GetComponentLogConfigs)
+	v1.POST("/log/components", SetComponentLogConfig)
+	v1.GET("/hotspots", GetHotspotReport)
 
 	// changefeed API
 	changefeedGroup := v1.Group("/changefeeds")
 	changefeedGroup.GET("", api.ListChangefeed)
 	changefeedGroup.GET("/:changefeed_id", api.GetChangefeed)
 	changefeedGroup.POST("", api.CreateChangefeed)
+	changefeedGroup.POST("/:changefeed_id/clone", api.CloneChangefeed)
 	changefeedGroup.PUT("/:changefeed_id", api.UpdateChangefeed)
 	changefeedGroup.POST("/:changefeed_id/pause", api.PauseChangefeed)
 	changefeedGroup.POST("/:changefeed_id/resume", api.ResumeChangefeed)
+	changefeedGroup.POST("/:changefeed_id/rewind", api.RewindChangefeed)
 	changefeedGroup.DELETE("/:changefeed_id", api.RemoveChangefeed)
 	changefeedGroup.POST("/:changefeed_id/tables/rebalance_table", api.RebalanceTables)
 	changefeedGroup.POST("/:changefeed_id/tables/move_table", api.MoveTable)
+	changefeedGroup.POST("/:changefeed_id/tables/approve", api.ApproveTables)
+	changefeedGroup.POST("/:changefeed_id/consistency_report", api.RequestConsistencyReport)
+	changefeedGroup.GET("/:changefeed_id/consistency_report", api.GetConsistencyReport)
+	changefeedGroup.GET("/:changefeed_id/health", api.GetChangefeedHealth)
+	changefeedGroup.GET("/:changefeed_id/redo", api.GetChangefeedRedoResolvedTs)
 
 	// owner API
 	ownerGroup := v1.Group("/owner")
@@ -99,6 +121,8 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api openAPI) {
 	processorGroup := v1.Group("/processors")
 	processorGroup.GET("", api.ListProcessor)
 	processorGroup.GET("/:changefeed_id/:capture_id", api.GetProcessor)
+	processorGroup.GET("/:changefeed_id/:capture_id/tables/:table_id", api.GetTableDiagnostics)
+	processorGroup.GET("/:changefeed_id/:capture_id/redo", api.GetProcessorRedoResolvedTs)
 
 	// capture API
 	captureGroup := v1.Group("/captures")
@@ -220,6 +244,39 @@ func (h *openAPI) GetChangefeed(c *gin.Context) {
 		taskStatus = append(taskStatus, model.CaptureTaskStatus{CaptureID: captureID, Tables: tables, Operation: status.Operation})
 	}
 
+	barrier, err := h.statusProvider().GetChangeFeedBarrier(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	positions, err := h.statusProvider().GetTaskPositions(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tableDiagnoses, err := h.statusProvider().GetTableDiagnoses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	slowestCapture := ""
+	slowestCheckpointTs := uint64(math.MaxUint64)
+	var redoHealth map[model.CaptureID]*model.RedoHealth
+	for captureID, position := range positions {
+		if position.CheckPointTs < slowestCheckpointTs {
+			slowestCapture = captureID
+			slowestCheckpointTs = position.CheckPointTs
+		}
+		if position.Redo != nil {
+			if redoHealth == nil {
+				redoHealth = make(map[model.CaptureID]*model.RedoHealth)
+			}
+			redoHealth[captureID] = position.Redo
+		}
+	}
+
 	changefeedDetail := &model.ChangefeedDetail{
 		ID:             changefeedID,
 		SinkURI:        info.SinkURI,
@@ -232,6 +289,10 @@ func (h *openAPI) GetChangefeed(c *gin.Context) {
 		Engine:         info.Engine,
 		FeedState:      info.State,
 		TaskStatus:     taskStatus,
+		Barrier:        barrier,
+		SlowestCapture: slowestCapture,
+		TableDiagnoses: tableDiagnoses,
+		RedoHealth:     redoHealth,
 	}
 
 	c.IndentedJSON(http.StatusOK, changefeedDetail)
@@ -282,6 +343,65 @@ func (h *openAPI) CreateChangefeed(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// CloneChangefeed clones a changefeed
+// @Summary Clone a changefeed
+// @Description create a new changefeed with the configuration of an existing changefeed
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param changefeed body model.ChangefeedCloneConfig true "changefeed clone config"
+// @Success 202
+// @Failure 500,400 {object} model.HTTPError
+// @Router	/api/v1/changefeeds/{changefeed_id}/clone [post]
+func (h *openAPI) CloneChangefeed(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	var cloneConfig model.ChangefeedCloneConfig
+	if err := c.BindJSON(&cloneConfig); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+
+	oldInfo, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	info, err := verifyCloneChangefeedConfig(ctx, cloneConfig, oldInfo, h.capture)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	infoStr, err := info.Marshal()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = h.capture.EtcdClient.CreateChangefeedInfo(ctx, info, cloneConfig.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	log.Info("Clone changefeed successfully!", zap.String("from", changefeedID),
+		zap.String("id", cloneConfig.ID), zap.String("changefeed", infoStr))
+	c.Status(http.StatusAccepted)
+}
+
 // PauseChangefeed pauses a changefeed
 // @Summary Pause a changefeed
 // @Description Pause a changefeed
@@ -365,9 +485,74 @@ func (h *openAPI) ResumeChangefeed(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// RewindChangefeed rewinds the checkpoint of a stopped changefeed and resumes it
+// @Summary Rewind a changefeed
+// @Description rewind the checkpoint of a stopped changefeed to an earlier ts and resume it
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param rewind body model.ChangefeedRewindConfig true "changefeed rewind config"
+// @Success 202
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/rewind [post]
+func (h *openAPI) RewindChangefeed(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	var rewindConfig model.ChangefeedRewindConfig
+	if err := c.BindJSON(&rewindConfig); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = verifyRewindChangefeedConfig(ctx, changefeedID, rewindConfig, info, status, h.capture)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// the redo log meta is rewound by the owner once it accepts the job, so
+	// that a rejected job leaves the redo logs untouched.
+	job := model.AdminJob{
+		CfID:                  changefeedID,
+		Type:                  model.AdminResume,
+		OverwriteCheckpointTs: rewindConfig.CheckpointTs,
+		RewindRedo:            rewindConfig.UseRedo,
+	}
+	if err := handleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	log.Info("Rewind changefeed successfully!", zap.String("id", changefeedID),
+		zap.Uint64("checkpointTs", rewindConfig.CheckpointTs), zap.Bool("useRedo", rewindConfig.UseRedo))
+	c.Status(http.StatusAccepted)
+}
+
 // UpdateChangefeed updates a changefeed
 // @Summary Update a changefeed
 // @Description Update a changefeed
+// @Description the changefeed must be stopped unless only the throttle of sink is updated.
 // @Tags changefeed
 // @Accept json
 // @Produce json
@@ -399,10 +584,6 @@ func (h *openAPI) UpdateChangefeed(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	if info.State != model.StateStopped {
-		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs("can only update changefeed config when it is stopped"))
-		return
-	}
 
 	// can only update target-ts, sink-uri
 	// filter_rules, ignore_txn_start_ts, mounter_worker_num, sink_config
@@ -417,6 +598,24 @@ func (h *openAPI) UpdateChangefeed(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	// the throttle of sink and the limits of incremental scans can be updated
+	// without stopping the changefeed
+	if info.State != model.StateStopped && !isRuntimeUpdate(info, newInfo) {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs("can only update changefeed config when it is stopped"))
+		return
+	}
+	if changefeedConfig.SinkConfig != nil {
+		ts, logical, err := h.capture.PDClient.GetTS(ctx)
+		if err != nil {
+			_ = c.Error(cerror.ErrPDEtcdAPIError.GenWithStackByArgs("fail to get ts from pd client"))
+			return
+		}
+		err = VerifyTransforms(newInfo.Config, h.capture.Storage, oracle.ComposeTS(ts, logical))
+		if err != nil {
+			_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err))
+			return
+		}
+	}
 
 	err = h.capture.EtcdClient.SaveChangeFeedInfo(ctx, newInfo, changefeedID)
 	if err != nil {
@@ -434,6 +633,7 @@ func (h *openAPI) UpdateChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id path string true "changefeed_id"
+// @Param final_barrier query boolean false "remove after an end marker is written at the resolved ts"
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/changefeeds/{changefeed_id} [delete]
@@ -456,9 +656,19 @@ func (h *openAPI) RemoveChangefeed(c *gin.Context) {
 		return
 	}
 
+	finalBarrier := false
+	if finalBarrierStr := c.Query(apiOpVarFinalBarrier); finalBarrierStr != "" {
+		finalBarrier, err = strconv.ParseBool(finalBarrierStr)
+		if err != nil {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid final_barrier: %s", finalBarrierStr))
+			return
+		}
+	}
+
 	job := model.AdminJob{
 		CfID: changefeedID,
 		Type: model.AdminRemove,
+		Opts: &model.AdminJobOption{FinalBarrier: finalBarrier},
 	}
 
 	if err := handleOwnerJob(ctx, h.capture, job); err != nil {
@@ -560,6 +770,280 @@ func (h *openAPI) MoveTable(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// ApproveTables approves tables to be replicated by a changefeed
+// @Summary Approve tables of a frozen changefeed
+// @Description approve tables to be replicated by a changefeed created with freeze_tables,
+// @Description the changefeed must be stopped and the tables take effect after it is resumed.
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param tables body []string true "tables in the form of schema.table"
+// @Success 202
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/tables/approve [post]
+func (h *openAPI) ApproveTables(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if info.State != model.StateStopped {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs("can only approve tables when the changefeed is stopped"))
+		return
+	}
+
+	var approveConfig model.ChangefeedApproveTablesConfig
+	if err = c.BindJSON(&approveConfig); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.Wrap(err))
+		return
+	}
+
+	newInfo, err := verifyApproveTablesConfig(approveConfig, info)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = h.capture.EtcdClient.SaveChangeFeedInfo(ctx, newInfo, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	log.Info("Approve tables of changefeed successfully!", zap.String("id", changefeedID),
+		zap.Strings("tables", approveConfig.Tables))
+	c.Status(http.StatusAccepted)
+}
+
+// RequestConsistencyReport requests a consistency report of a changefeed
+// @Summary Request a consistency report
+// @Description block the changefeed at the current ts, record the corresponding downstream ts
+// @Description and checksum the downstream tables at it, the report is generated in background.
+// @Description the report in progress is returned if there is one
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Success 202 {object} model.ConsistencyReport
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/consistency_report [post]
+func (h *openAPI) RequestConsistencyReport(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	report, err := h.statusProvider().RequestConsistencyReport(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusAccepted, report)
+}
+
+// GetConsistencyReport gets the latest consistency report of a changefeed
+// @Summary Get the consistency report
+// @Description get the latest consistency report of a changefeed
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Success 200 {object} model.ConsistencyReport
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/consistency_report [get]
+func (h *openAPI) GetConsistencyReport(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	report, err := h.statusProvider().GetConsistencyReport(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// GetChangefeedHealth gets the health of a changefeed
+// @Summary Get the health of a changefeed
+// @Description get the health of a changefeed judged by the canary health check,
+// @Description it responds 200 if the changefeed is healthy and 503 otherwise
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Success 200 {object} model.ChangefeedHealth
+// @Failure 503 {object} model.ChangefeedHealth
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/health [get]
+func (h *openAPI) GetChangefeedHealth(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	health, err := h.statusProvider().GetChangefeedHealth(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !health.Healthy {
+		c.IndentedJSON(http.StatusServiceUnavailable, health)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, health)
+}
+
+// GetChangefeedRedoResolvedTs gets the resolved ts of the redo logs of each table of a changefeed
+// @Summary Get the resolved ts of the redo logs of a changefeed
+// @Description get the resolved ts of the redo logs flushed to the storage of each table of a changefeed,
+// @Description the redo logs of the changefeed can be applied up to the minimal one of them
+// @Tags changefeed
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Success 200 {object} model.RedoResolvedTs
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v1/changefeeds/{changefeed_id}/redo [get]
+func (h *openAPI) GetChangefeedRedoResolvedTs(c *gin.Context) {
+	if !h.capture.IsOwner() {
+		h.forwardToOwner(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	info, err := h.statusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if info.Config == nil || info.Config.Consistent == nil ||
+		!redo.IsConsistentEnabled(info.Config.Consistent.Level) {
+		_ = c.Error(cerror.ErrRedoNotEnabled.GenWithStackByArgs(changefeedID))
+		return
+	}
+	statuses, err := h.statusProvider().GetAllTaskStatuses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	captures, err := h.statusProvider().GetCaptures(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	status, err := h.statusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The resolved ts of the tables are collected from the processors
+	// replicating them.
+	reports := make([]*model.RedoResolvedTs, 0, len(statuses))
+	for _, captureInfo := range captures {
+		if _, ok := statuses[captureInfo.ID]; !ok {
+			continue
+		}
+		var resolvedTs *model.RedoResolvedTs
+		if captureInfo.ID == h.capture.Info().ID {
+			resolvedTs, err = h.capture.GetRedoResolvedTs(ctx, changefeedID)
+		} else {
+			resolvedTs = &model.RedoResolvedTs{}
+			err = h.getFromCapture(c, captureInfo,
+				fmt.Sprintf("/api/v1/processors/%s/%s/redo", changefeedID, captureInfo.ID), resolvedTs)
+		}
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		reports = append(reports, resolvedTs)
+	}
+	c.IndentedJSON(http.StatusOK, mergeRedoResolvedTs(statuses, reports, status.CheckpointTs))
+}
+
+// mergeRedoResolvedTs merges the resolved ts of the redo logs reported by the
+// processors. A table of the changefeed may not be reported by any processor
+// while it's moved between the captures, the resolved ts of its redo logs is
+// not less than the checkpoint ts of the changefeed then, because the events
+// are only written to the sink after their redo logs are flushed.
+func mergeRedoResolvedTs(
+	statuses map[model.CaptureID]*model.TaskStatus, reports []*model.RedoResolvedTs, checkpointTs uint64,
+) *model.RedoResolvedTs {
+	result := &model.RedoResolvedTs{Tables: make([]*model.RedoTableResolvedTs, 0)}
+	reported := make(map[model.TableID]struct{})
+	add := func(table *model.RedoTableResolvedTs) {
+		if len(result.Tables) == 0 || table.ResolvedTs < result.ResolvedTs {
+			result.ResolvedTs = table.ResolvedTs
+		}
+		result.Tables = append(result.Tables, table)
+	}
+	for _, report := range reports {
+		for _, table := range report.Tables {
+			reported[table.TableID] = struct{}{}
+			add(table)
+		}
+	}
+	for _, status := range statuses {
+		for tableID := range status.Tables {
+			if _, ok := reported[tableID]; ok {
+				continue
+			}
+			reported[tableID] = struct{}{}
+			add(&model.RedoTableResolvedTs{
+				TableID:           tableID,
+				ResolvedTs:        checkpointTs,
+				PendingResolvedTs: checkpointTs,
+			})
+		}
+	}
+	sort.Slice(result.Tables, func(i, j int) bool {
+		return result.Tables[i].TableID < result.Tables[j].TableID
+	})
+	return result
+}
+
 // ResignOwner makes the current owner resign
 // @Summary notify the owner to resign
 // @Description notify the current owner to resign
@@ -648,6 +1132,108 @@ func (h *openAPI) GetProcessor(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, &processorDetail)
 }
 
+// GetTableDiagnostics gets the diagnostics of a table replicated by a processor
+// @Summary Get the diagnostics of a table
+// @Description get the queue lengths, sorter disk usage, flow controller memory usage,
+// @Description last event ts and sink flush lag of a table replicated by a processor
+// @Tags processor
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param capture_id  path  string  true  "capture_id"
+// @Param table_id  path  integer  true  "table_id"
+// @Success 200 {object} model.TablePipelineDiagnostics
+// @Failure 500,400 {object} model.HTTPError
+// @Router	/api/v1/processors/{changefeed_id}/{capture_id}/tables/{table_id} [get]
+func (h *openAPI) GetTableDiagnostics(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	captureID := c.Param(apiOpVarCaptureID)
+	if err := model.ValidateChangefeedID(captureID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid capture_id: %s", captureID))
+		return
+	}
+
+	tableIDStr := c.Param(apiOpVarTableID)
+	tableID, err := strconv.ParseInt(tableIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid table_id: %s", tableIDStr))
+		return
+	}
+
+	// The diagnostics are collected by the capture replicating the table.
+	if captureID != h.capture.Info().ID {
+		target, err := h.capture.GetCaptureInfo(ctx, captureID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		h.forwardToCapture(c, target)
+		return
+	}
+
+	diagnostics, err := h.capture.GetTableDiagnostics(ctx, changefeedID, tableID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, diagnostics)
+}
+
+// GetProcessorRedoResolvedTs gets the resolved ts of the redo logs of the tables replicated by a processor
+// @Summary Get the resolved ts of the redo logs of a processor
+// @Description get the resolved ts of the redo logs flushed to the storage of each table replicated by a processor
+// @Tags processor
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param capture_id  path  string  true  "capture_id"
+// @Success 200 {object} model.RedoResolvedTs
+// @Failure 500,400 {object} model.HTTPError
+// @Router	/api/v1/processors/{changefeed_id}/{capture_id}/redo [get]
+func (h *openAPI) GetProcessorRedoResolvedTs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	changefeedID := c.Param(apiOpVarChangefeedID)
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID))
+		return
+	}
+
+	captureID := c.Param(apiOpVarCaptureID)
+	if err := model.ValidateChangefeedID(captureID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid capture_id: %s", captureID))
+		return
+	}
+
+	// The resolved ts are maintained by the capture replicating the tables.
+	if captureID != h.capture.Info().ID {
+		target, err := h.capture.GetCaptureInfo(ctx, captureID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		h.forwardToCapture(c, target)
+		return
+	}
+
+	resolvedTs, err := h.capture.GetRedoResolvedTs(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if resolvedTs.Tables == nil {
+		resolvedTs.Tables = make([]*model.RedoTableResolvedTs, 0)
+	}
+	c.IndentedJSON(http.StatusOK, resolvedTs)
+}
+
 // ListProcessor lists all processors in the TiCDC cluster
 // @Summary List processors
 // @Description list all processors in the TiCDC cluster
@@ -780,16 +1366,47 @@ func SetLogLevel(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
-// forwardToOwner forward an request to owner
-func (h *openAPI) forwardToOwner(c *gin.Context) {
-	ctx := c.Request.Context()
-	// every request can only forward to owner one time
-	if len(c.GetHeader(forWardFromCapture)) != 0 {
-		_ = c.Error(cerror.ErrRequestForwardErr.FastGenByArgs())
+// GetComponentLogConfigs lists the runtime log configs of the components.
+// @Summary List the log configs of components
+// @Description list the runtime log level and debug log sampling of components
+// @Tags common
+// @Produce json
+// @Success 200 {object} map[string]logutil.ComponentLogConfig
+// @Router	/api/v1/log/components [get]
+func GetComponentLogConfigs(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, logutil.ComponentLogConfigs())
+}
+
+// SetComponentLogConfig changes the log level and debug log sampling of a
+// component dynamically.
+// @Summary Change the log config of a component
+// @Description change the log level and debug log sampling of a component dynamically
+// @Tags common
+// @Accept json
+// @Produce json
+// @Param config body logutil.ComponentLogRequest true "component log config"
+// @Success 200
+// @Failure 400 {object} model.HTTPError
+// @Router	/api/v1/log/components [post]
+func SetComponentLogConfig(c *gin.Context) {
+	var req logutil.ComponentLogRequest
+	if err := c.BindJSON(&req); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid component log config: %s", err.Error()))
 		return
 	}
-	c.Header(forWardFromCapture, h.capture.Info().ID)
+	if err := logutil.SetComponentLogConfig(req.Component, req.ComponentLogConfig); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"fail to change log config of component %s: %s", req.Component, err.Error()))
+		return
+	}
+	log.Warn("component log config changed", zap.String("component", req.Component),
+		zap.String("level", req.Level), zap.Uint64("debugSamplesPerSecond", req.DebugSamplesPerSecond))
+	c.Status(http.StatusOK)
+}
 
+// forwardToOwner forward an request to owner
+func (h *openAPI) forwardToOwner(c *gin.Context) {
+	ctx := c.Request.Context()
 	var owner *model.CaptureInfo
 	// get owner
 	owner, err := h.capture.GetOwnerCaptureInfo(ctx)
@@ -798,6 +1415,17 @@ func (h *openAPI) forwardToOwner(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
+	h.forwardToCapture(c, owner)
+}
+
+// forwardToCapture forward an request to the specified capture
+func (h *openAPI) forwardToCapture(c *gin.Context, target *model.CaptureInfo) {
+	// every request can only be forwarded one time
+	if len(c.GetHeader(forWardFromCapture)) != 0 {
+		_ = c.Error(cerror.ErrRequestForwardErr.FastGenByArgs())
+		return
+	}
+	c.Header(forWardFromCapture, h.capture.Info().ID)
 
 	tslConfig, err := config.GetGlobalServerConfig().Security.ToTLSConfigWithVerify()
 	if err != nil {
@@ -807,7 +1435,7 @@ func (h *openAPI) forwardToOwner(c *gin.Context) {
 
 	// init a request
 	req, _ := http.NewRequest(c.Request.Method, c.Request.RequestURI, c.Request.Body)
-	req.URL.Host = owner.AdvertiseAddr
+	req.URL.Host = target.AdvertiseAddr
 	if tslConfig != nil {
 		req.URL.Scheme = "https"
 	} else {
@@ -819,7 +1447,7 @@ func (h *openAPI) forwardToOwner(c *gin.Context) {
 		}
 	}
 
-	// forward to owner
+	// forward to the target capture
 	cli := httputil.NewClient(tslConfig)
 	resp, err := cli.Do(req)
 	if err != nil {
@@ -845,3 +1473,49 @@ func (h *openAPI) forwardToOwner(c *gin.Context) {
 		return
 	}
 }
+
+// getFromCapture gets the response of an API of the specified capture, the
+// response is decoded to v.
+func (h *openAPI) getFromCapture(c *gin.Context, target *model.CaptureInfo, path string, v interface{}) error {
+	tslConfig, err := config.GetGlobalServerConfig().Security.ToTLSConfigWithVerify()
+	if err != nil {
+		return err
+	}
+	scheme := "http"
+	if tslConfig != nil {
+		scheme = "https"
+	}
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet,
+		fmt.Sprintf("%s://%s%s", scheme, target.AdvertiseAddr, path), nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// the request must not be forwarded again by the target capture
+	req.Header.Set(forWardFromCapture, h.capture.Info().ID)
+
+	cli := httputil.NewClient(tslConfig)
+	resp, err := cli.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var httpErr model.HTTPError
+		if err := json.NewDecoder(resp.Body).Decode(&httpErr); err != nil {
+			return errors.Errorf("capture %s responds %s", target.ID, resp.Status)
+		}
+		return errors.Errorf("capture %s responds %s: %s", target.ID, resp.Status, httpErr.Error)
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(v))
+}
+
+// GetHotspotReport gets the upstream write hotspot report of the capture.
+// @Summary Get the upstream write hotspot report
+// @Description get the tables and regions with the highest row change event rates observed by the pullers of the capture
+// @Tags common
+// @Produce json
+// @Success 200 {object} model.HotspotReport
+// @Router	/api/v1/hotspots [get]
+func GetHotspotReport(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, puller.GetHotspotReport())
+}
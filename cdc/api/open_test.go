@@ -90,6 +90,31 @@ func (p *mockStatusProvider) GetCaptures(ctx context.Context) ([]*model.CaptureI
 	return args.Get(0).([]*model.CaptureInfo), args.Error(1)
 }
 
+func (p *mockStatusProvider) GetChangeFeedBarrier(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedBarrier, error) {
+	args := p.Called(ctx)
+	return args.Get(0).(*model.ChangeFeedBarrier), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetTableDiagnoses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableDiagnosis, error) {
+	args := p.Called(ctx)
+	return args.Get(0).([]*model.TableDiagnosis), args.Error(1)
+}
+
+func (p *mockStatusProvider) RequestConsistencyReport(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ConsistencyReport, error) {
+	args := p.Called(ctx, changefeedID)
+	return args.Get(0).(*model.ConsistencyReport), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetConsistencyReport(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ConsistencyReport, error) {
+	args := p.Called(ctx, changefeedID)
+	return args.Get(0).(*model.ConsistencyReport), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetChangefeedHealth(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangefeedHealth, error) {
+	args := p.Called(ctx, changefeedID)
+	return args.Get(0).(*model.ChangefeedHealth), args.Error(1)
+}
+
 func newRouter(c *capture.Capture, p *mockStatusProvider) *gin.Engine {
 	router := gin.New()
 	RegisterOpenAPIRoutes(router, NewOpenAPI4Test(c, p))
@@ -113,6 +138,35 @@ func newStatusProvider() *mockStatusProvider {
 			captureID: {Error: &model.RunningError{Message: "test"}},
 		}, nil)
 
+	statusProvider.On("GetChangeFeedBarrier", mock.Anything).
+		Return(&model.ChangeFeedBarrier{Type: "ddl", Ts: 1}, nil)
+
+	statusProvider.On("GetTableDiagnoses", mock.Anything).
+		Return([]*model.TableDiagnosis{{
+			TableID: 1, State: model.TableScheduleAbsent, Reasons: []string{"waiting to be dispatched"},
+		}}, nil)
+
+	statusProvider.On("RequestConsistencyReport", mock.Anything, changeFeedID).
+		Return(&model.ConsistencyReport{State: model.ConsistencyReportWaiting, UpstreamTs: 1}, nil)
+
+	statusProvider.On("RequestConsistencyReport", mock.Anything, nonExistChangefeedID).
+		Return(new(model.ConsistencyReport),
+			cerror.ErrChangeFeedNotExists.GenWithStackByArgs(nonExistChangefeedID))
+
+	statusProvider.On("GetConsistencyReport", mock.Anything, changeFeedID).
+		Return(&model.ConsistencyReport{State: model.ConsistencyReportFinished, UpstreamTs: 1, DownstreamTs: 2}, nil)
+
+	statusProvider.On("GetConsistencyReport", mock.Anything, nonExistChangefeedID).
+		Return(new(model.ConsistencyReport),
+			cerror.ErrConsistencyReportNotExists.GenWithStackByArgs(nonExistChangefeedID))
+
+	statusProvider.On("GetChangefeedHealth", mock.Anything, changeFeedID).
+		Return(&model.ChangefeedHealth{Healthy: true, LatencyMs: 100}, nil)
+
+	statusProvider.On("GetChangefeedHealth", mock.Anything, nonExistChangefeedID).
+		Return(new(model.ChangefeedHealth),
+			cerror.ErrHealthCheckNotEnabled.GenWithStackByArgs(nonExistChangefeedID))
+
 	statusProvider.On("GetAllChangeFeedStatuses", mock.Anything).
 		Return(map[model.ChangeFeedID]*model.ChangeFeedStatus{
 			changeFeedID + "1": {CheckpointTs: 1},
@@ -189,6 +243,11 @@ func TestGetChangefeed(t *testing.T) {
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	require.Nil(t, err)
 	require.Equal(t, model.StateNormal, resp.FeedState)
+	require.Equal(t, &model.ChangeFeedBarrier{Type: "ddl", Ts: 1}, resp.Barrier)
+	require.Equal(t, captureID, resp.SlowestCapture)
+	require.Len(t, resp.TableDiagnoses, 1)
+	require.Equal(t, model.TableScheduleAbsent, resp.TableDiagnoses[0].State)
+	require.Equal(t, []string{"waiting to be dispatched"}, resp.TableDiagnoses[0].Reasons)
 
 	// test get changefeed failed
 	api = testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s", nonExistChangefeedID), method: "GET"}
@@ -553,6 +612,106 @@ func TestGetProcessor(t *testing.T) {
 	require.Contains(t, httpError.Error, "capture not exists, non-exist-capture")
 }
 
+func TestGetTableDiagnostics(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouter(cp, newStatusProvider())
+
+	// test get table diagnostics fail due to table ID error
+	api := testCase{
+		url:    fmt.Sprintf("/api/v1/processors/%s/%s/tables/%s", changeFeedID, cp.Info().ID, "t1"),
+		method: "GET",
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	httpError := &model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(httpError)
+	require.Nil(t, err)
+	require.Contains(t, httpError.Error, "invalid table_id: t1")
+
+	// test get table diagnostics fail due to the table is not replicated by
+	// the capture
+	api = testCase{
+		url:    fmt.Sprintf("/api/v1/processors/%s/%s/tables/%d", changeFeedID, cp.Info().ID, 1),
+		method: "GET",
+	}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	httpError = &model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(httpError)
+	require.Nil(t, err)
+	require.Contains(t, httpError.Error, "table not found in processor cache")
+}
+
+func TestGetRedoResolvedTs(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouter(cp, newStatusProvider())
+
+	// test get the redo resolved ts of a changefeed without redo log
+	api := testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/redo", changeFeedID), method: "GET"}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	httpError := &model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(httpError)
+	require.Nil(t, err)
+	require.Contains(t, httpError.Error, "redo log is not enabled")
+
+	// test get the redo resolved ts of a processor without any table
+	api = testCase{
+		url:    fmt.Sprintf("/api/v1/processors/%s/%s/redo", changeFeedID, cp.Info().ID),
+		method: "GET",
+	}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var resp model.RedoResolvedTs
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, uint64(0), resp.ResolvedTs)
+	require.Len(t, resp.Tables, 0)
+}
+
+func TestMergeRedoResolvedTs(t *testing.T) {
+	t.Parallel()
+
+	statuses := map[model.CaptureID]*model.TaskStatus{
+		"capture-1": {Tables: map[model.TableID]*model.TableReplicaInfo{1: {}, 2: {}}},
+		"capture-2": {Tables: map[model.TableID]*model.TableReplicaInfo{3: {}}},
+	}
+	reports := []*model.RedoResolvedTs{
+		{ResolvedTs: 100, Tables: []*model.RedoTableResolvedTs{
+			{TableID: 2, CaptureID: "capture-1", ResolvedTs: 110, PendingResolvedTs: 120},
+			{TableID: 1, CaptureID: "capture-1", ResolvedTs: 100, PendingResolvedTs: 120},
+		}},
+	}
+	// table 3 is being moved, it's not reported by any processor
+	result := mergeRedoResolvedTs(statuses, reports, 90)
+	require.Equal(t, &model.RedoResolvedTs{ResolvedTs: 90, Tables: []*model.RedoTableResolvedTs{
+		{TableID: 1, CaptureID: "capture-1", ResolvedTs: 100, PendingResolvedTs: 120},
+		{TableID: 2, CaptureID: "capture-1", ResolvedTs: 110, PendingResolvedTs: 120},
+		{TableID: 3, ResolvedTs: 90, PendingResolvedTs: 90},
+	}}, result)
+
+	reports = append(reports, &model.RedoResolvedTs{ResolvedTs: 95, Tables: []*model.RedoTableResolvedTs{
+		{TableID: 3, CaptureID: "capture-2", ResolvedTs: 95, PendingResolvedTs: 95},
+	}})
+	result = mergeRedoResolvedTs(statuses, reports, 90)
+	require.Equal(t, uint64(95), result.ResolvedTs)
+	require.Len(t, result.Tables, 3)
+}
+
 func TestListProcessor(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -661,6 +820,176 @@ func TestSetLogLevel(t *testing.T) {
 }
 
 // TODO: finished these test cases after we decouple those APIs from etcdClient.
+func TestCloneChangefeed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouter(cp, newStatusProvider())
+	api := testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/clone", changeFeedID), method: "POST"}
+
+	// test clone changefeed with an invalid new changefeed id
+	cloneConfig := model.ChangefeedCloneConfig{ID: "#invalid-id"}
+	body, err := json.Marshal(&cloneConfig)
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "invalid changefeed_id")
+
+	// test clone changefeed to an existing changefeed
+	cloneConfig = model.ChangefeedCloneConfig{ID: changeFeedID}
+	body, err = json.Marshal(&cloneConfig)
+	require.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 500, w.Code)
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "already exists")
+
+	// test clone changefeed with an invalid body
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, bytes.NewReader([]byte("{")))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+}
+
+func TestConsistencyReport(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouter(cp, newStatusProvider())
+
+	// test request a consistency report succeeded
+	api := testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/consistency_report", changeFeedID), method: "POST"}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 202, w.Code)
+	var report model.ConsistencyReport
+	err := json.NewDecoder(w.Body).Decode(&report)
+	require.Nil(t, err)
+	require.Equal(t, model.ConsistencyReportWaiting, report.State)
+	require.Equal(t, uint64(1), report.UpstreamTs)
+
+	// test request a consistency report of a non-exist changefeed
+	api = testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/consistency_report", nonExistChangefeedID), method: "POST"}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "changefeed not exists")
+
+	// test get a consistency report succeeded
+	api = testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/consistency_report", changeFeedID), method: "GET"}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	report = model.ConsistencyReport{}
+	err = json.NewDecoder(w.Body).Decode(&report)
+	require.Nil(t, err)
+	require.Equal(t, model.ConsistencyReportFinished, report.State)
+	require.Equal(t, uint64(2), report.DownstreamTs)
+
+	// test get a consistency report which is never requested
+	api = testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/consistency_report", nonExistChangefeedID), method: "GET"}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "consistency report")
+}
+
+func TestRewindChangefeed(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	router := newRouter(cp, newStatusProvider())
+	api := testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/rewind", changeFeedID), method: "POST"}
+
+	// test rewind a running changefeed
+	rewindConfig := model.ChangefeedRewindConfig{CheckpointTs: 1}
+	body, err := json.Marshal(&rewindConfig)
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "can only rewind the changefeed when it is stopped")
+
+	// test rewind changefeed with an invalid body
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, bytes.NewReader([]byte("{")))
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+}
+
 func TestCreateChangefeed(t *testing.T) {}
 func TestUpdateChangefeed(t *testing.T) {}
 func TestHealth(t *testing.T)           {}
+
+func TestChangefeedHealth(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mo := mock_owner.NewMockOwner(ctrl)
+	cp := capture.NewCapture4Test(mo)
+	statusProvider := newStatusProvider()
+	unhealthyChangefeedID := "unhealthy-changefeed"
+	statusProvider.On("GetChangefeedHealth", mock.Anything, unhealthyChangefeedID).
+		Return(&model.ChangefeedHealth{Healthy: false, LatencyMs: 120000, Message: "latency 2m0s exceeds 1m0s"}, nil)
+	router := newRouter(cp, statusProvider)
+
+	// test get the health of a healthy changefeed
+	api := testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/health", changeFeedID), method: "GET"}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var health model.ChangefeedHealth
+	err := json.NewDecoder(w.Body).Decode(&health)
+	require.Nil(t, err)
+	require.True(t, health.Healthy)
+	require.Equal(t, int64(100), health.LatencyMs)
+
+	// test get the health of an unhealthy changefeed
+	api = testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/health", unhealthyChangefeedID), method: "GET"}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 503, w.Code)
+	health = model.ChangefeedHealth{}
+	err = json.NewDecoder(w.Body).Decode(&health)
+	require.Nil(t, err)
+	require.False(t, health.Healthy)
+	require.Contains(t, health.Message, "exceeds")
+
+	// test get the health of a changefeed without health check
+	api = testCase{url: fmt.Sprintf("/api/v1/changefeeds/%s/health", nonExistChangefeedID), method: "GET"}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+	respErr := model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Error, "health check is not enabled")
+}
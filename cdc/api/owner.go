@@ -45,6 +45,8 @@ const (
 	OpVarTableID = "table-id"
 	// OpForceRemoveChangefeed is used when remove a changefeed
 	OpForceRemoveChangefeed = "force-remove"
+	// OpFinalBarrier is used when remove a changefeed after an end marker is written
+	OpFinalBarrier = "final-barrier"
 )
 
 type commonResp struct {
@@ -147,6 +149,15 @@ func (h *ownerAPI) handleChangefeedAdmin(w http.ResponseWriter, req *http.Reques
 		}
 		opts.ForceRemove = forceRemoveOpt
 	}
+	if finalBarrierStr := req.Form.Get(OpFinalBarrier); finalBarrierStr != "" {
+		finalBarrierOpt, err := strconv.ParseBool(finalBarrierStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest,
+				cerror.ErrAPIInvalidParam.GenWithStack("invalid final barrier option: %s", finalBarrierStr))
+			return
+		}
+		opts.FinalBarrier = finalBarrierOpt
+	}
 	job := model.AdminJob{
 		CfID: req.Form.Get(OpVarChangefeedID),
 		Type: model.AdminJobType(typ),
@@ -19,9 +19,12 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/pkg/actor"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/version"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -45,8 +48,13 @@ func RegisterStatusAPIRoutes(router *gin.Engine, capture *capture.Capture) {
 	statusAPI := statusAPI{capture: capture}
 	router.GET("/status", gin.WrapF(statusAPI.handleStatus))
 	router.GET("/debug/info", gin.WrapF(statusAPI.handleDebugInfo))
+	router.GET("/debug/actors", gin.WrapF(statusAPI.handleDebugActors))
 }
 
+// defaultDebugActorLimit is the default number of the busiest actors listed
+// for each actor system.
+const defaultDebugActorLimit = 100
+
 func (h *statusAPI) writeEtcdInfo(ctx context.Context, cli *etcd.CDCEtcdClient, w io.Writer) {
 	resp, err := cli.Client.Get(ctx, etcd.EtcdKeyBase, clientv3.WithPrefix())
 	if err != nil {
@@ -66,6 +74,23 @@ func (h *statusAPI) handleDebugInfo(w http.ResponseWriter, req *http.Request) {
 	h.writeEtcdInfo(ctx, h.capture.EtcdClient, w)
 }
 
+// handleDebugActors lists the running actor systems and their busiest actors.
+// The number of actors of each system is limited by the `limit` parameter,
+// all actors are listed if it's 0.
+func (h *statusAPI) handleDebugActors(w http.ResponseWriter, req *http.Request) {
+	limit := defaultDebugActorLimit
+	if limitStr := req.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 0 {
+			writeError(w, http.StatusBadRequest,
+				cerror.ErrAPIInvalidParam.GenWithStack("invalid limit: %s", limitStr))
+			return
+		}
+		limit = l
+	}
+	writeData(w, actor.DebugInfo(limit))
+}
+
 func (h *statusAPI) handleStatus(w http.ResponseWriter, req *http.Request) {
 	st := status{
 		Version: version.ReleaseVersion,
@@ -32,7 +32,9 @@ var httpBadRequestError = []*errors.Error{
 	cerror.ErrAPIInvalidParam, cerror.ErrSinkURIInvalid, cerror.ErrStartTsBeforeGC,
 	cerror.ErrChangeFeedNotExists, cerror.ErrTargetTsBeforeStartTs, cerror.ErrTableIneligible,
 	cerror.ErrFilterRuleInvalid, cerror.ErrChangefeedUpdateRefused, cerror.ErrMySQLConnectionError,
-	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist,
+	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist, cerror.ErrConsistencyReportNotExists,
+	cerror.ErrConsistencyReportRefused, cerror.ErrChangefeedRewindRefused, cerror.ErrHealthCheckNotEnabled,
+	cerror.ErrProcessorTableNotFound, cerror.ErrRedoNotEnabled,
 }
 
 // IsHTTPBadRequestError check if a error is a http bad request error
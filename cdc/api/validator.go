@@ -15,6 +15,7 @@ package api
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -24,7 +25,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/transform"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
@@ -47,36 +50,16 @@ func verifyCreateChangefeedConfig(
 	}
 
 	// verify changefeedID
-	if err := model.ValidateChangefeedID(changefeedConfig.ID); err != nil {
-		return nil, cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedConfig.ID)
-	}
-	// check if the changefeed exists
-	cfStatus, err := capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedConfig.ID)
-	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
+	if err := verifyNewChangefeedID(ctx, changefeedConfig.ID, capture); err != nil {
 		return nil, err
 	}
-	if cfStatus != nil {
-		return nil, cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(changefeedConfig.ID)
-	}
 
 	// verify start-ts
-	if changefeedConfig.StartTS == 0 {
-		ts, logical, err := capture.PDClient.GetTS(ctx)
-		if err != nil {
-			return nil, cerror.ErrPDEtcdAPIError.GenWithStackByArgs("fail to get ts from pd client")
-		}
-		changefeedConfig.StartTS = oracle.ComposeTS(ts, logical)
-	}
-
-	// Ensure the start ts is valid in the next 1 hour.
-	const ensureTTL = 60 * 60
-	if err := gc.EnsureChangefeedStartTsSafety(
-		ctx, capture.PDClient, changefeedConfig.ID, ensureTTL, changefeedConfig.StartTS); err != nil {
-		if !cerror.ErrStartTsBeforeGC.Equal(err) {
-			return nil, cerror.ErrPDEtcdAPIError.Wrap(err)
-		}
+	startTs, err := verifyStartTs(ctx, changefeedConfig.ID, changefeedConfig.StartTS, capture)
+	if err != nil {
 		return nil, err
 	}
+	changefeedConfig.StartTS = startTs
 
 	// verify target-ts
 	if changefeedConfig.TargetTS > 0 && changefeedConfig.TargetTS <= changefeedConfig.StartTS {
@@ -92,12 +75,19 @@ func verifyCreateChangefeedConfig(
 	if changefeedConfig.SinkConfig != nil {
 		replicaConfig.Sink = changefeedConfig.SinkConfig
 	}
+	if changefeedConfig.IncrementalScan != nil {
+		if err := verifyIncrementalScanConfig(changefeedConfig.IncrementalScan); err != nil {
+			return nil, err
+		}
+		replicaConfig.IncrementalScan = changefeedConfig.IncrementalScan
+	}
 	if len(changefeedConfig.IgnoreTxnStartTs) != 0 {
 		replicaConfig.Filter.IgnoreTxnStartTs = changefeedConfig.IgnoreTxnStartTs
 	}
 	if len(changefeedConfig.FilterRules) != 0 {
 		replicaConfig.Filter.Rules = changefeedConfig.FilterRules
 	}
+	replicaConfig.Filter.FreezeTables = changefeedConfig.FreezeTables
 
 	captureInfos, err := capture.StatusProvider().GetCaptures(ctx)
 	if err != nil {
@@ -142,6 +132,18 @@ func verifyCreateChangefeedConfig(
 		}
 	}
 
+	if replicaConfig.Filter.FreezeTables {
+		ineligibleTables, eligibleTables, err := VerifyTables(replicaConfig, capture.Storage, changefeedConfig.StartTS)
+		if err != nil {
+			return nil, err
+		}
+		info.FrozenTables = FrozenTables(replicaConfig, ineligibleTables, eligibleTables)
+	}
+
+	if err := VerifyTransforms(replicaConfig, capture.Storage, changefeedConfig.StartTS); err != nil {
+		return nil, err
+	}
+
 	tz, err := util.GetTimezone(changefeedConfig.TimeZone)
 	if err != nil {
 		return nil, cerror.ErrAPIInvalidParam.Wrap(errors.Annotatef(err, "invalid timezone:%s", changefeedConfig.TimeZone))
@@ -154,6 +156,186 @@ func verifyCreateChangefeedConfig(
 	return info, nil
 }
 
+// verifyNewChangefeedID checks the changefeed ID is valid and not used by any changefeed
+func verifyNewChangefeedID(ctx context.Context, changefeedID model.ChangeFeedID, capture *capture.Capture) error {
+	if err := model.ValidateChangefeedID(changefeedID); err != nil {
+		return cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s", changefeedID)
+	}
+	// check if the changefeed exists
+	cfStatus, err := capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
+		return err
+	}
+	if cfStatus != nil {
+		return cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(changefeedID)
+	}
+	return nil
+}
+
+// verifyStartTs returns the start-ts of a new changefeed, the current ts is used if
+// startTs is 0, and ensures the start-ts is not GC-ed in the next 1 hour.
+func verifyStartTs(ctx context.Context, changefeedID model.ChangeFeedID, startTs uint64, capture *capture.Capture) (uint64, error) {
+	if startTs == 0 {
+		ts, logical, err := capture.PDClient.GetTS(ctx)
+		if err != nil {
+			return 0, cerror.ErrPDEtcdAPIError.GenWithStackByArgs("fail to get ts from pd client")
+		}
+		startTs = oracle.ComposeTS(ts, logical)
+	}
+
+	// Ensure the start ts is valid in the next 1 hour.
+	const ensureTTL = 60 * 60
+	if err := gc.EnsureChangefeedStartTsSafety(
+		ctx, capture.PDClient, changefeedID, ensureTTL, startTs); err != nil {
+		if !cerror.ErrStartTsBeforeGC.Equal(err) {
+			return 0, cerror.ErrPDEtcdAPIError.Wrap(err)
+		}
+		return 0, err
+	}
+	return startTs, nil
+}
+
+// verifyCloneChangefeedConfig verify ChangefeedCloneConfig for clone a changefeed,
+// it returns the info of the new changefeed.
+func verifyCloneChangefeedConfig(
+	ctx context.Context,
+	cloneConfig model.ChangefeedCloneConfig,
+	oldInfo *model.ChangeFeedInfo,
+	capture *capture.Capture,
+) (*model.ChangeFeedInfo, error) {
+	if err := verifyNewChangefeedID(ctx, cloneConfig.ID, capture); err != nil {
+		return nil, err
+	}
+
+	startTs, err := verifyStartTs(ctx, cloneConfig.ID, cloneConfig.StartTS, capture)
+	if err != nil {
+		return nil, err
+	}
+	if oldInfo.TargetTs > 0 && oldInfo.TargetTs <= startTs {
+		return nil, cerror.ErrTargetTsBeforeStartTs.GenWithStackByArgs(oldInfo.TargetTs, startTs)
+	}
+
+	info, err := oldInfo.Clone()
+	if err != nil {
+		return nil, err
+	}
+	info.CreateTime = time.Now()
+	info.StartTs = startTs
+	info.CreatorVersion = version.ReleaseVersion
+	info.ErrorHis = nil
+	info.Error = nil
+	info.State = model.StateNormal
+	info.AdminJobType = model.AdminNone
+	if cloneConfig.Paused {
+		info.State = model.StateStopped
+		info.AdminJobType = model.AdminStop
+	}
+	if cloneConfig.SinkURI != "" {
+		info.SinkURI = cloneConfig.SinkURI
+	}
+
+	if !info.Config.ForceReplicate && !cloneConfig.IgnoreIneligibleTable {
+		ineligibleTables, _, err := VerifyTables(info.Config, capture.Storage, startTs)
+		if err != nil {
+			return nil, err
+		}
+		if len(ineligibleTables) != 0 {
+			return nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
+		}
+	}
+
+	if err := VerifyTransforms(info.Config, capture.Storage, startTs); err != nil {
+		return nil, err
+	}
+
+	tz, err := util.GetTimezone(cloneConfig.TimeZone)
+	if err != nil {
+		return nil, cerror.ErrAPIInvalidParam.Wrap(errors.Annotatef(err, "invalid timezone:%s", cloneConfig.TimeZone))
+	}
+	ctx = util.PutTimezoneInCtx(ctx, tz)
+	if err := sink.Validate(ctx, info.SinkURI, info.Config, info.Opts); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// verifyRewindChangefeedConfig verify ChangefeedRewindConfig for rewind a changefeed,
+// and ensures the new checkpoint is not GC-ed in the next 1 hour.
+func verifyRewindChangefeedConfig(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	rewindConfig model.ChangefeedRewindConfig,
+	info *model.ChangeFeedInfo,
+	status *model.ChangeFeedStatus,
+	capture *capture.Capture,
+) error {
+	if info.State != model.StateStopped {
+		return cerror.ErrChangefeedRewindRefused.GenWithStackByArgs(
+			"can only rewind the changefeed when it is stopped")
+	}
+	checkpointTs := info.GetCheckpointTs(status)
+	if rewindConfig.CheckpointTs >= checkpointTs {
+		return cerror.ErrChangefeedRewindRefused.GenWithStack(
+			"can not rewind checkpoint-ts to %d, it must be less than the current checkpoint-ts %d",
+			rewindConfig.CheckpointTs, checkpointTs)
+	}
+	if rewindConfig.CheckpointTs < info.StartTs {
+		return cerror.ErrChangefeedRewindRefused.GenWithStack(
+			"can not rewind checkpoint-ts to %d, it must not be less than the start-ts %d",
+			rewindConfig.CheckpointTs, info.StartTs)
+	}
+
+	redoEnabled := info.Config.Consistent != nil && redo.IsConsistentEnabled(info.Config.Consistent.Level)
+	if redoEnabled && !rewindConfig.UseRedo {
+		return cerror.ErrChangefeedRewindRefused.GenWithStackByArgs(
+			"redo log is enabled for the changefeed, use_redo must be set")
+	}
+	if !redoEnabled && rewindConfig.UseRedo {
+		return cerror.ErrChangefeedRewindRefused.GenWithStackByArgs(
+			"redo log is not enabled for the changefeed")
+	}
+
+	_, err := verifyStartTs(ctx, changefeedID, rewindConfig.CheckpointTs, capture)
+	return err
+}
+
+// verifyApproveTablesConfig verify ChangefeedApproveTablesConfig for approving
+// tables of a frozen changefeed, it returns the info with the tables appended.
+func verifyApproveTablesConfig(
+	approveConfig model.ChangefeedApproveTablesConfig,
+	oldInfo *model.ChangeFeedInfo,
+) (*model.ChangeFeedInfo, error) {
+	if !oldInfo.Config.Filter.FreezeTables {
+		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+			"the tables of the changefeed are not frozen")
+	}
+	if len(approveConfig.Tables) == 0 {
+		return nil, cerror.ErrAPIInvalidParam.GenWithStack("no table is specified")
+	}
+	info, err := oldInfo.Clone()
+	if err != nil {
+		return nil, err
+	}
+	frozen := make(map[model.TableName]struct{}, len(info.FrozenTables))
+	for _, t := range info.FrozenTables {
+		frozen[t] = struct{}{}
+	}
+	for _, name := range approveConfig.Tables {
+		parts := strings.SplitN(name, ".", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, cerror.ErrAPIInvalidParam.GenWithStack("invalid table name: %s", name)
+		}
+		t := model.TableName{Schema: parts[0], Table: parts[1]}
+		if _, ok := frozen[t]; ok {
+			continue
+		}
+		frozen[t] = struct{}{}
+		info.FrozenTables = append(info.FrozenTables, t)
+	}
+	return info, nil
+}
+
 // verifyUpdateChangefeedConfig verify ChangefeedConfig for update a changefeed
 func verifyUpdateChangefeedConfig(ctx context.Context, changefeedConfig model.ChangefeedConfig, oldInfo *model.ChangeFeedInfo) (*model.ChangeFeedInfo, error) {
 	newInfo, err := oldInfo.Clone()
@@ -185,14 +367,34 @@ func verifyUpdateChangefeedConfig(ctx context.Context, changefeedConfig model.Ch
 		newInfo.Config.Mounter.WorkerNum = changefeedConfig.MounterWorkerNum
 	}
 
+	if changefeedConfig.IncrementalScan != nil {
+		if err := verifyIncrementalScanConfig(changefeedConfig.IncrementalScan); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
+		newInfo.Config.IncrementalScan = changefeedConfig.IncrementalScan
+	}
+
+	extraSinkURIsUpdated := false
 	if changefeedConfig.SinkConfig != nil {
+		var oldExtraSinkURIs []string
+		if newInfo.Config.Sink != nil {
+			oldExtraSinkURIs = newInfo.Config.Sink.ExtraSinkURIs
+		}
+		extraSinkURIsUpdated = strings.Join(oldExtraSinkURIs, ",") !=
+			strings.Join(changefeedConfig.SinkConfig.ExtraSinkURIs, ",")
 		newInfo.Config.Sink = changefeedConfig.SinkConfig
 	}
 
-	// verify sink_uri
+	// verify sink_uri, the extra sink uris are verified together
 	if changefeedConfig.SinkURI != "" {
 		newInfo.SinkURI = changefeedConfig.SinkURI
-		if err := sink.Validate(ctx, changefeedConfig.SinkURI, newInfo.Config, newInfo.Opts); err != nil {
+	}
+	if changefeedConfig.SinkURI != "" || extraSinkURIsUpdated {
+		if err := sink.Validate(ctx, newInfo.SinkURI, newInfo.Config, newInfo.Opts); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
+	} else if changefeedConfig.SinkConfig != nil {
+		if err := sink.ValidateMySQLOnlyConfig(newInfo.SinkURI, newInfo.Config); err != nil {
 			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
 		}
 	}
@@ -204,6 +406,45 @@ func verifyUpdateChangefeedConfig(ctx context.Context, changefeedConfig model.Ch
 	return newInfo, nil
 }
 
+// verifyIncrementalScanConfig verifies the incremental scan config given by
+// the user.
+func verifyIncrementalScanConfig(cfg *config.IncrementalScanConfig) error {
+	return (&config.ReplicaConfig{IncrementalScan: cfg}).Validate()
+}
+
+// isRuntimeUpdate returns true if newInfo differs from oldInfo only in the
+// throttle of sink and the limits of incremental scans, which can be applied
+// to a running changefeed.
+func isRuntimeUpdate(oldInfo, newInfo *model.ChangeFeedInfo) bool {
+	info, err := newInfo.Clone()
+	if err != nil {
+		return false
+	}
+	if info.Config.Sink != nil && oldInfo.Config.Sink != nil {
+		info.Config.Sink.Throttle = oldInfo.Config.Sink.Throttle
+	}
+	if info.Config.IncrementalScan != nil && oldInfo.Config.IncrementalScan != nil {
+		info.Config.IncrementalScan.RegionConcurrency = oldInfo.Config.IncrementalScan.RegionConcurrency
+		info.Config.IncrementalScan.BytesPerSecond = oldInfo.Config.IncrementalScan.BytesPerSecond
+	}
+	return !diff.Changed(oldInfo, info)
+}
+
+// FrozenTables returns the tables to be recorded into a changefeed whose
+// replicated tables are frozen at creation.
+func FrozenTables(replicaConfig *config.ReplicaConfig, ineligibleTables, eligibleTables []model.TableName) []model.TableName {
+	tables := make([]model.TableName, 0, len(eligibleTables)+len(ineligibleTables))
+	for _, t := range eligibleTables {
+		tables = append(tables, model.TableName{Schema: t.Schema, Table: t.Table})
+	}
+	if replicaConfig.ForceReplicate {
+		for _, t := range ineligibleTables {
+			tables = append(tables, model.TableName{Schema: t.Schema, Table: t.Table})
+		}
+	}
+	return tables
+}
+
 // VerifyTables catalog tables specified by ReplicaConfig into
 // eligible (has an unique index or primary key) and ineligible tables.
 func VerifyTables(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage, startTs uint64) (ineligibleTables, eligibleTables []model.TableName, err error) {
@@ -237,3 +478,34 @@ func VerifyTables(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage, s
 	}
 	return
 }
+
+// VerifyTransforms checks the column transforms of ReplicaConfig don't break
+// the handle keys of the replicated tables.
+func VerifyTransforms(replicaConfig *config.ReplicaConfig, storage tidbkv.Storage, startTs uint64) error {
+	transformer, err := transform.NewTransformer(replicaConfig)
+	if err != nil || transformer == nil {
+		return errors.Trace(err)
+	}
+	filter, err := filter.NewFilter(replicaConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta, err := kv.GetSnapshotMeta(storage, startTs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	snap, err := entry.NewSingleSchemaSnapshotFromMeta(meta, startTs, false /* explicitTables */)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, tableInfo := range snap.Tables() {
+		if filter.ShouldIgnoreTable(tableInfo.TableName.Schema, tableInfo.TableName.Table) {
+			continue
+		}
+		if err := transformer.VerifyTable(tableInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -50,3 +50,108 @@ func TestVerifyUpdateChangefeedConfig(t *testing.T) {
 	require.Nil(t, err)
 	require.NotNil(t, newInfo)
 }
+
+func TestVerifyRewindChangefeedConfig(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	info := &model.ChangeFeedInfo{
+		StartTs: 10,
+		State:   model.StateNormal,
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	status := &model.ChangeFeedStatus{CheckpointTs: 100}
+
+	// test rewinding a running changefeed
+	rewindConfig := model.ChangefeedRewindConfig{CheckpointTs: 50}
+	err := verifyRewindChangefeedConfig(ctx, "test", rewindConfig, info, status, nil)
+	require.Regexp(t, ".*can only rewind the changefeed when it is stopped.*", err)
+
+	// test rewinding to a ts not less than the checkpoint
+	info.State = model.StateStopped
+	rewindConfig = model.ChangefeedRewindConfig{CheckpointTs: 100}
+	err = verifyRewindChangefeedConfig(ctx, "test", rewindConfig, info, status, nil)
+	require.Regexp(t, ".*must be less than the current checkpoint-ts 100.*", err)
+
+	// test rewinding to a ts less than the start ts
+	rewindConfig = model.ChangefeedRewindConfig{CheckpointTs: 5}
+	err = verifyRewindChangefeedConfig(ctx, "test", rewindConfig, info, status, nil)
+	require.Regexp(t, ".*must not be less than the start-ts 10.*", err)
+
+	// test rewinding with redo logs not enabled
+	rewindConfig = model.ChangefeedRewindConfig{CheckpointTs: 50, UseRedo: true}
+	err = verifyRewindChangefeedConfig(ctx, "test", rewindConfig, info, status, nil)
+	require.Regexp(t, ".*redo log is not enabled.*", err)
+
+	// test rewinding without redo logs when they are enabled
+	info.Config.Consistent.Level = "eventual"
+	rewindConfig = model.ChangefeedRewindConfig{CheckpointTs: 50}
+	err = verifyRewindChangefeedConfig(ctx, "test", rewindConfig, info, status, nil)
+	require.Regexp(t, ".*use_redo must be set.*", err)
+}
+
+func TestVerifyApproveTablesConfig(t *testing.T) {
+	t.Parallel()
+	info := &model.ChangeFeedInfo{
+		Config:       config.GetDefaultReplicaConfig(),
+		FrozenTables: []model.TableName{{Schema: "test", Table: "t1"}},
+	}
+
+	// test approving tables of a changefeed not frozen
+	approveConfig := model.ChangefeedApproveTablesConfig{Tables: []string{"test.t2"}}
+	_, err := verifyApproveTablesConfig(approveConfig, info)
+	require.Regexp(t, ".*the tables of the changefeed are not frozen.*", err)
+
+	// test invalid table names
+	info.Config.Filter.FreezeTables = true
+	approveConfig = model.ChangefeedApproveTablesConfig{Tables: []string{"t2"}}
+	_, err = verifyApproveTablesConfig(approveConfig, info)
+	require.Regexp(t, ".*invalid table name: t2.*", err)
+
+	// test verify success, the approved tables are deduplicated
+	approveConfig = model.ChangefeedApproveTablesConfig{
+		Tables: []string{"test.t1", "test.t2", "test.t2"},
+	}
+	newInfo, err := verifyApproveTablesConfig(approveConfig, info)
+	require.Nil(t, err)
+	require.Equal(t, []model.TableName{
+		{Schema: "test", Table: "t1"},
+		{Schema: "test", Table: "t2"},
+	}, newInfo.FrozenTables)
+	require.Len(t, info.FrozenTables, 1)
+}
+
+func TestIsRuntimeUpdate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	oldInfo := &model.ChangeFeedInfo{Config: config.GetDefaultReplicaConfig()}
+
+	// test updating the throttle only
+	sinkConfig := *oldInfo.Config.Sink
+	sinkConfig.Throttle = &config.ThrottleConfig{RowsPerSecond: 1000}
+	newInfo, err := verifyUpdateChangefeedConfig(ctx,
+		model.ChangefeedConfig{SinkConfig: &sinkConfig}, oldInfo)
+	require.Nil(t, err)
+	require.True(t, isRuntimeUpdate(oldInfo, newInfo))
+
+	// test updating other configs with the throttle
+	newInfo, err = verifyUpdateChangefeedConfig(ctx,
+		model.ChangefeedConfig{SinkConfig: &sinkConfig, MounterWorkerNum: 32}, oldInfo)
+	require.Nil(t, err)
+	require.False(t, isRuntimeUpdate(oldInfo, newInfo))
+
+	// test updating the limits of incremental scans
+	scanConfig := *oldInfo.Config.IncrementalScan
+	scanConfig.RegionConcurrency = 16
+	scanConfig.BytesPerSecond = 64 * 1024 * 1024
+	newInfo, err = verifyUpdateChangefeedConfig(ctx,
+		model.ChangefeedConfig{SinkConfig: &sinkConfig, IncrementalScan: &scanConfig}, oldInfo)
+	require.Nil(t, err)
+	require.True(t, isRuntimeUpdate(oldInfo, newInfo))
+	require.Equal(t, 16, newInfo.Config.IncrementalScan.RegionConcurrency)
+
+	// test updating invalid limits
+	scanConfig.BytesPerSecond = -1
+	_, err = verifyUpdateChangefeedConfig(ctx,
+		model.ChangefeedConfig{IncrementalScan: &scanConfig}, oldInfo)
+	require.Regexp(t, ".*bytes-per-second must not be negative.*", err)
+}
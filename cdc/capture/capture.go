@@ -32,11 +32,14 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 
+	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/processor"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
+	"github.com/pingcap/tiflow/cdc/puller"
+	"github.com/pingcap/tiflow/cdc/sink/common"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/leveldb/system"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
@@ -147,7 +150,7 @@ func (c *Capture) reset(ctx context.Context) error {
 		c.pdClock.Stop()
 	}
 
-	c.pdClock, err = pdtime.NewClock(ctx, c.PDClient)
+	c.pdClock, err = pdtime.NewClock(ctx, c.PDClient, time.Duration(conf.PDStalenessBudget))
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -264,6 +267,15 @@ func (c *Capture) Run(ctx context.Context) error {
 }
 
 func (c *Capture) run(stdCtx context.Context) error {
+	var memoryQuotaManager *common.MemoryQuotaManager
+	if memoryQuota := config.GetGlobalServerConfig().CaptureMemoryQuota; memoryQuota != 0 {
+		memoryQuotaManager = common.NewMemoryQuotaManager(memoryQuota)
+	}
+	mounterWorkerPool := entry.NewMounterWorkerPool(config.GetGlobalServerConfig().MounterPool)
+	var sharedPullerManager *puller.SharedPullerManager
+	if config.GetGlobalServerConfig().KVClient.EnableSharedPuller {
+		sharedPullerManager = puller.NewSharedPullerManager()
+	}
 	ctx := cdcContext.NewContext(stdCtx, &cdcContext.GlobalVars{
 		PDClient:         c.PDClient,
 		KVStorage:        c.Storage,
@@ -276,7 +288,13 @@ func (c *Capture) run(stdCtx context.Context) error {
 		SorterSystem:     c.sorterSystem,
 		MessageServer:    c.MessageServer,
 		MessageRouter:    c.MessageRouter,
+
+		MemoryQuotaManager: memoryQuotaManager,
+		MounterWorkerPool:  mounterWorkerPool,
+
+		SharedPullerManager: sharedPullerManager,
 	})
+
 	err := c.register(ctx)
 	if err != nil {
 		return errors.Trace(err)
@@ -334,6 +352,23 @@ func (c *Capture) run(stdCtx context.Context) error {
 		defer wg.Done()
 		c.grpcPool.RecycleConn(ctx)
 	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = puller.RunHotspotReporter(ctx)
+	}()
+	if memoryQuotaManager != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			memoryQuotaManager.Run(ctx)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		mounterWorkerPool.Run(ctx)
+	}()
 	if c.enableNewScheduler {
 		wg.Add(1)
 		go func() {
@@ -608,6 +643,53 @@ func (c *Capture) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	wait(doneM)
 }
 
+// GetTableDiagnostics returns the diagnostics of a table of a changefeed
+// replicated by the capture.
+func (c *Capture) GetTableDiagnostics(
+	ctx context.Context, changefeedID model.ChangeFeedID, tableID model.TableID,
+) (*model.TablePipelineDiagnostics, error) {
+	c.captureMu.Lock()
+	processorManager := c.processorManager
+	// Like WriteDebugInfo, the lock must be released before waiting for the
+	// processor manager.
+	c.captureMu.Unlock()
+	if processorManager == nil {
+		return nil, cerror.ErrProcessorTableNotFound.GenWithStack(
+			"table(%d) of changefeed(%s)", tableID, changefeedID)
+	}
+	return processorManager.QueryTableDiagnostics(ctx, changefeedID, tableID)
+}
+
+// GetRedoResolvedTs returns the resolved ts of the redo logs of the tables of
+// a changefeed replicated by the capture.
+func (c *Capture) GetRedoResolvedTs(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+) (*model.RedoResolvedTs, error) {
+	c.captureMu.Lock()
+	processorManager := c.processorManager
+	// Like WriteDebugInfo, the lock must be released before waiting for the
+	// processor manager.
+	c.captureMu.Unlock()
+	if processorManager == nil {
+		return &model.RedoResolvedTs{}, nil
+	}
+	return processorManager.QueryRedoResolvedTs(ctx, changefeedID)
+}
+
+// GetCaptureInfo returns the info of a capture of current TiCDC cluster
+func (c *Capture) GetCaptureInfo(ctx context.Context, captureID model.CaptureID) (*model.CaptureInfo, error) {
+	_, captureInfos, err := c.EtcdClient.GetCaptures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, captureInfo := range captureInfos {
+		if captureInfo.ID == captureID {
+			return captureInfo, nil
+		}
+	}
+	return nil, cerror.ErrCaptureNotExist.GenWithStackByArgs(captureID)
+}
+
 // IsOwner returns whether the capture is an owner
 func (c *Capture) IsOwner() bool {
 	c.ownerMu.Lock()
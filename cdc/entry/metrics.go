@@ -33,10 +33,35 @@ var (
 			Name:      "total_rows_count",
 			Help:      "The total count of rows that are processed by mounter",
 		}, []string{"changefeed"})
+	decodeDurationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "mounter",
+			Name:      "decode_duration_seconds_total",
+			Help:      "The total time (s) spent on decoding events by mounter, it approximates the CPU time of mounter.",
+		}, []string{"changefeed"})
+	mounterWorkerNumGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "mounter",
+			Name:      "worker_num",
+			Help:      "The number of workers of the mounter worker pool",
+		})
+	mounterQueueLatencyHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "mounter",
+			Name:      "queue_latency",
+			Help:      "Bucketed histogram of the latency (s) an event waits for a mounter worker.",
+			Buckets:   prometheus.ExponentialBuckets(0.000001, 4, 14),
+		})
 )
 
 // InitMetrics registers all metrics in this file
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(mountDuration)
 	registry.MustRegister(totalRowsCountGauge)
+	registry.MustRegister(decodeDurationCounter)
+	registry.MustRegister(mounterWorkerNumGauge)
+	registry.MustRegister(mounterQueueLatencyHistogram)
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -71,27 +72,39 @@ type mounterImpl struct {
 	workerNum      int
 	enableOldValue bool
 	changefeedID   string
+	columnSelector *filter.ColumnSelector
+	// workerPool is shared by all the mounters of the capture, nil means the
+	// concurrency of decoding is not limited.
+	workerPool *MounterWorkerPool
 
 	// index is an atomic variable to dispatch input events to workers.
 	index int64
 
-	metricMountDuration prometheus.Observer
-	metricTotalRows     prometheus.Gauge
+	metricMountDuration  prometheus.Observer
+	metricTotalRows      prometheus.Gauge
+	metricDecodeDuration prometheus.Counter
 }
 
-// NewMounter creates a mounter
+// NewMounter creates a mounter, the columns not selected by columnSelector
+// are removed from the mounted rows. The events are decoded by the workers of
+// workerPool, which can be nil.
 func NewMounter(schemaStorage SchemaStorage,
 	changefeedID string,
 	tz *time.Location,
 	enableOldValue bool,
+	columnSelector *filter.ColumnSelector,
+	workerPool *MounterWorkerPool,
 ) Mounter {
 	return &mounterImpl{
-		schemaStorage:       schemaStorage,
-		changefeedID:        changefeedID,
-		enableOldValue:      enableOldValue,
-		metricMountDuration: mountDuration.WithLabelValues(changefeedID),
-		metricTotalRows:     totalRowsCountGauge.WithLabelValues(changefeedID),
-		tz:                  tz,
+		schemaStorage:        schemaStorage,
+		changefeedID:         changefeedID,
+		enableOldValue:       enableOldValue,
+		columnSelector:       columnSelector,
+		workerPool:           workerPool,
+		metricMountDuration:  mountDuration.WithLabelValues(changefeedID),
+		metricTotalRows:      totalRowsCountGauge.WithLabelValues(changefeedID),
+		metricDecodeDuration: decodeDurationCounter.WithLabelValues(changefeedID),
+		tz:                   tz,
 	}
 }
 
@@ -137,6 +150,12 @@ func (m *mounterImpl) unmarshalAndMountRowChanged(ctx context.Context, raw *mode
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The worker is acquired after the snapshot is got, so waiting for the
+	// DDL puller doesn't hold a worker.
+	if err := m.workerPool.acquire(ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
+	start := time.Now()
 	row, err := func() (*model.RowChangedEvent, error) {
 		if snap.IsIneligibleTableID(physicalTableID) {
 			log.Debug("skip the DML of ineligible table", zap.Uint64("ts", raw.CRTs), zap.Int64("tableID", physicalTableID))
@@ -162,6 +181,8 @@ func (m *mounterImpl) unmarshalAndMountRowChanged(ctx context.Context, raw *mode
 		}
 		return nil, nil
 	}()
+	m.metricDecodeDuration.Add(time.Since(start).Seconds())
+	m.workerPool.release()
 	if err != nil {
 		log.Error("failed to mount and unmarshals entry, start to print debug info", zap.Error(err))
 		snap.PrintStatus(log.Error)
@@ -336,7 +357,7 @@ func (m *mounterImpl) mountRowKVEntry(tableInfo *model.TableInfo, row *rowKVEntr
 
 	_, _, colInfos := tableInfo.GetRowColInfos()
 
-	return &model.RowChangedEvent{
+	rowEvent := &model.RowChangedEvent{
 		StartTs:          row.StartTs,
 		CommitTs:         row.CRTs,
 		RowID:            intRowID,
@@ -352,7 +373,11 @@ func (m *mounterImpl) mountRowKVEntry(tableInfo *model.TableInfo, row *rowKVEntr
 		PreColumns:          preCols,
 		IndexColumns:        tableInfo.IndexColumnsOffset,
 		ApproximateDataSize: dataSize,
-	}, nil
+	}
+	if err := m.columnSelector.Apply(rowEvent); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rowEvent, nil
 }
 
 var emptyBytes = make([]byte, 0)
@@ -0,0 +1,204 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
+)
+
+// mounterPoolTuneInterval is the interval to tune the number of workers.
+const mounterPoolTuneInterval = time.Second
+
+// MounterWorkerPool limits the number of events decoded concurrently by all
+// the mounters of a capture. The events are still decoded by the goroutines
+// of the tables, a worker is a slot an event must hold while it's decoded.
+//
+// If the auto tuning is enabled, workers are added if the events wait for a
+// worker longer than the target latency on average, and removed if most of
+// them are idle, so decoding-heavy changefeeds get more workers and CPU is not
+// wasted on the others.
+//
+// A nil *MounterWorkerPool doesn't limit the concurrency.
+type MounterWorkerPool struct {
+	// sem has maxWorkerNum slots, the slots beyond workerNum are held by the
+	// pool itself, and they're released to add workers.
+	sem           *semaphore.Weighted
+	autoTune      bool
+	minWorkerNum  int64
+	maxWorkerNum  int64
+	targetLatency time.Duration
+
+	// workerNum is only changed by tune, it's accessed atomically.
+	workerNum int64
+	// busy is the number of workers decoding events, peakBusy is the max of
+	// busy since the last tuning.
+	busy     int64
+	peakBusy int64
+	// waitNanos and waitCount accumulate the latency the events wait for a
+	// worker since the last tuning.
+	waitNanos int64
+	waitCount int64
+}
+
+// NewMounterWorkerPool creates a MounterWorkerPool, or returns nil if neither
+// the number of workers nor the auto tuning is configured, so the concurrency
+// is not limited by default.
+func NewMounterWorkerPool(cfg *config.MounterPoolConfig) *MounterWorkerPool {
+	workerNum := int64(cfg.WorkerNum)
+	if workerNum == 0 {
+		if !cfg.EnableAutoTune {
+			log.Info("mounter worker pool is disabled")
+			return nil
+		}
+		workerNum = int64(runtime.NumCPU())
+	}
+	p := &MounterWorkerPool{
+		autoTune:      cfg.EnableAutoTune,
+		minWorkerNum:  workerNum,
+		maxWorkerNum:  workerNum,
+		targetLatency: time.Duration(cfg.TargetQueueLatency),
+	}
+	if p.autoTune {
+		p.minWorkerNum = int64(cfg.MinWorkerNum)
+		p.maxWorkerNum = int64(cfg.MaxWorkerNum)
+		if p.maxWorkerNum == 0 {
+			p.maxWorkerNum = int64(runtime.NumCPU()) * 4
+		}
+		if p.maxWorkerNum < p.minWorkerNum {
+			p.maxWorkerNum = p.minWorkerNum
+		}
+		if workerNum < p.minWorkerNum {
+			workerNum = p.minWorkerNum
+		}
+		if workerNum > p.maxWorkerNum {
+			workerNum = p.maxWorkerNum
+		}
+	}
+	p.workerNum = workerNum
+	p.sem = semaphore.NewWeighted(p.maxWorkerNum)
+	if reserved := p.maxWorkerNum - workerNum; reserved > 0 {
+		p.sem.TryAcquire(reserved)
+	}
+	mounterWorkerNumGauge.Set(float64(workerNum))
+	log.Info("mounter worker pool created",
+		zap.Int64("workerNum", workerNum),
+		zap.Bool("autoTune", p.autoTune),
+		zap.Int64("minWorkerNum", p.minWorkerNum),
+		zap.Int64("maxWorkerNum", p.maxWorkerNum))
+	return p
+}
+
+// acquire blocks until a worker is available.
+func (p *MounterWorkerPool) acquire(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	start := time.Now()
+	if err := p.sem.Acquire(ctx, 1); err != nil {
+		return errors.Trace(err)
+	}
+	wait := time.Since(start)
+	mounterQueueLatencyHistogram.Observe(wait.Seconds())
+	atomic.AddInt64(&p.waitNanos, int64(wait))
+	atomic.AddInt64(&p.waitCount, 1)
+
+	busy := atomic.AddInt64(&p.busy, 1)
+	for {
+		peak := atomic.LoadInt64(&p.peakBusy)
+		if busy <= peak || atomic.CompareAndSwapInt64(&p.peakBusy, peak, busy) {
+			break
+		}
+	}
+	return nil
+}
+
+// release returns the worker acquired by acquire.
+func (p *MounterWorkerPool) release() {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.busy, -1)
+	p.sem.Release(1)
+}
+
+// Run tunes the number of workers periodically until the context is done,
+// it returns immediately if the auto tuning is disabled.
+func (p *MounterWorkerPool) Run(ctx context.Context) {
+	if p == nil || !p.autoTune {
+		return
+	}
+	ticker := time.NewTicker(mounterPoolTuneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.tune()
+		}
+	}
+}
+
+// tune adds a quarter of workers if the events wait for a worker longer than
+// the target latency on average, and removes a worker if less than half of
+// the workers are busy at the peak, since the last tuning.
+func (p *MounterWorkerPool) tune() {
+	waitNanos := atomic.SwapInt64(&p.waitNanos, 0)
+	waitCount := atomic.SwapInt64(&p.waitCount, 0)
+	peakBusy := atomic.SwapInt64(&p.peakBusy, atomic.LoadInt64(&p.busy))
+	workerNum := atomic.LoadInt64(&p.workerNum)
+	var avgWait time.Duration
+	if waitCount > 0 {
+		avgWait = time.Duration(waitNanos / waitCount)
+	}
+
+	newWorkerNum := workerNum
+	switch {
+	case avgWait > p.targetLatency:
+		delta := workerNum / 4
+		if delta == 0 {
+			delta = 1
+		}
+		if newWorkerNum += delta; newWorkerNum > p.maxWorkerNum {
+			newWorkerNum = p.maxWorkerNum
+		}
+		if newWorkerNum > workerNum {
+			p.sem.Release(newWorkerNum - workerNum)
+		}
+	case peakBusy*2 < workerNum && workerNum > p.minWorkerNum:
+		// the worker is removed in the next round if all of them are busy now.
+		if p.sem.TryAcquire(1) {
+			newWorkerNum--
+		}
+	}
+	if newWorkerNum == workerNum {
+		return
+	}
+	atomic.StoreInt64(&p.workerNum, newWorkerNum)
+	mounterWorkerNumGauge.Set(float64(newWorkerNum))
+	log.Debug("mounter worker pool tuned",
+		zap.Int64("workerNum", newWorkerNum),
+		zap.Int64("peakBusy", peakBusy),
+		zap.Int64("waitCount", waitCount),
+		zap.Duration("avgWait", avgWait))
+}
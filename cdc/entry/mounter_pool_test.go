@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMounterWorkerPoolFixed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := NewMounterWorkerPool(&config.MounterPoolConfig{WorkerNum: 2})
+	require.Nil(t, p.acquire(ctx))
+	require.Nil(t, p.acquire(ctx))
+	// all workers are busy.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, p.acquire(timeoutCtx))
+	p.release()
+	require.Nil(t, p.acquire(ctx))
+	p.release()
+	p.release()
+
+	// a nil pool doesn't limit the concurrency, it's the default.
+	nilPool := NewMounterWorkerPool(&config.MounterPoolConfig{})
+	require.Nil(t, nilPool)
+	for i := 0; i < 10; i++ {
+		require.Nil(t, nilPool.acquire(ctx))
+	}
+	nilPool.release()
+}
+
+func TestMounterWorkerPoolAutoTune(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := NewMounterWorkerPool(&config.MounterPoolConfig{
+		WorkerNum:          4,
+		EnableAutoTune:     true,
+		MinWorkerNum:       2,
+		MaxWorkerNum:       6,
+		TargetQueueLatency: config.TomlDuration(time.Millisecond),
+	})
+	require.Equal(t, int64(4), p.workerNum)
+
+	// the events wait too long, workers are added up to the max.
+	p.waitNanos, p.waitCount = int64(10*time.Millisecond), 1
+	p.tune()
+	require.Equal(t, int64(5), p.workerNum)
+	p.waitNanos, p.waitCount = int64(10*time.Millisecond), 1
+	p.tune()
+	p.waitNanos, p.waitCount = int64(10*time.Millisecond), 1
+	p.tune()
+	require.Equal(t, int64(6), p.workerNum)
+	for i := 0; i < 6; i++ {
+		require.Nil(t, p.acquire(ctx))
+	}
+
+	// all workers are busy, no worker is removed.
+	p.tune()
+	require.Equal(t, int64(6), p.workerNum)
+	for i := 0; i < 6; i++ {
+		p.release()
+	}
+
+	// most workers are idle, workers are removed down to the min.
+	for i := 0; i < 10; i++ {
+		p.tune()
+	}
+	require.Equal(t, int64(2), p.workerNum)
+	require.Nil(t, p.acquire(ctx))
+	require.Nil(t, p.acquire(ctx))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, p.acquire(timeoutCtx))
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/pdtime"
 	"github.com/pingcap/tiflow/pkg/regionspan"
 	"github.com/pingcap/tiflow/pkg/retry"
@@ -128,6 +129,12 @@ var (
 	logPanic     = log.Panic
 )
 
+// logger returns the logger of the kv client, whose level can be changed at
+// runtime.
+func logger() *zap.Logger {
+	return logutil.ComponentLogger(logutil.ComponentKVClient)
+}
+
 func newSingleRegionInfo(verID tikv.RegionVerID, span regionspan.ComparableSpan, ts uint64, rpcCtx *tikv.RPCContext) singleRegionInfo {
 	return singleRegionInfo{
 		verID:  verID,
@@ -179,6 +186,10 @@ type regionFeedState struct {
 	matcher        *matcher
 	startFeedTime  time.Time
 	lastResolvedTs uint64
+
+	// scanSlot is released once the region is initialized or failed, it's
+	// nil if the incremental scans are not limited.
+	scanSlot *scanSlot
 }
 
 func newRegionFeedState(sri singleRegionInfo, requestID uint64) *regionFeedState {
@@ -359,14 +370,14 @@ func (c *CDCClient) newStream(ctx context.Context, addr string, storeID uint64)
 		}()
 		conn, err = c.grpcPool.GetConn(addr)
 		if err != nil {
-			log.Info("get connection to store failed, retry later",
+			logger().Info("get connection to store failed, retry later",
 				zap.String("addr", addr), zap.Error(err),
 				zap.String("changefeed", c.changefeed))
 			return
 		}
 		err = version.CheckStoreVersion(ctx, c.pd, storeID)
 		if err != nil {
-			log.Error("check tikv version failed",
+			logger().Error("check tikv version failed",
 				zap.Error(err), zap.Uint64("storeID", storeID),
 				zap.String("changefeed", c.changefeed))
 			return
@@ -376,7 +387,7 @@ func (c *CDCClient) newStream(ctx context.Context, addr string, storeID uint64)
 		streamClient, err = client.EventFeed(ctx)
 		if err != nil {
 			err = cerror.WrapError(cerror.ErrTiKVEventFeed, err)
-			log.Info("establish stream to store failed, retry later",
+			logger().Info("establish stream to store failed, retry later",
 				zap.String("addr", addr), zap.Error(err),
 				zap.String("changefeed", c.changefeed))
 			return
@@ -385,7 +396,7 @@ func (c *CDCClient) newStream(ctx context.Context, addr string, storeID uint64)
 			client: streamClient,
 			conn:   conn,
 		}
-		log.Debug("created stream to store",
+		logger().Debug("created stream to store",
 			zap.String("addr", addr),
 			zap.String("changefeed", c.changefeed))
 		return nil
@@ -440,6 +451,11 @@ type eventFeedSession struct {
 	// The token based region router, it controls the uninitialized regions with
 	// a given size limit.
 	regionRouter LimitRegionRouter
+	// scanLimiter limits the incremental scans of all the sessions of the
+	// changefeed, scanSlots are the slots held by the regions of this session.
+	scanLimiter *IncrementalScanLimiter
+	scanSlotsMu sync.Mutex
+	scanSlots   map[*scanSlot]struct{}
 	// The channel to put the region that will be sent requests.
 	regionCh chan singleRegionInfo
 	// The channel to notify that an error is happening, so that the error will be handled and the affected region
@@ -488,6 +504,8 @@ func newEventFeedSession(
 		totalSpan:         totalSpan,
 		eventCh:           eventCh,
 		regionRouter:      NewSizedRegionRouter(ctx, kvClientCfg.RegionScanLimit),
+		scanLimiter:       IncrementalScanLimiterFromCtx(ctx),
+		scanSlots:         make(map[*scanSlot]struct{}),
 		regionCh:          make(chan singleRegionInfo, defaultRegionChanSize),
 		errCh:             make(chan regionErrorInfo, defaultRegionChanSize),
 		requestRangeCh:    make(chan rangeRequestTask, defaultRegionChanSize),
@@ -508,8 +526,9 @@ func newEventFeedSession(
 func (s *eventFeedSession) eventFeed(ctx context.Context, ts uint64) error {
 	eventFeedGauge.Inc()
 	defer eventFeedGauge.Dec()
+	defer s.releaseScanSlots()
 
-	log.Info("event feed started",
+	logger().Info("event feed started",
 		zap.Stringer("span", s.totalSpan), zap.Uint64("startTs", ts),
 		zap.String("changefeed", s.client.changefeed))
 
@@ -568,7 +587,7 @@ func (s *eventFeedSession) eventFeed(ctx context.Context, ts uint64) error {
 							// has been merged.
 							zapFieldAddr = zap.String("addr", errInfo.singleRegionInfo.rpcCtx.Addr)
 						}
-						log.Info("EventFeed retry rate limited",
+						logger().Info("EventFeed retry rate limited",
 							zap.String("changefeed", s.client.changefeed),
 							zap.Uint64("regionID", errInfo.singleRegionInfo.verID.GetID()),
 							zap.Uint64("ts", errInfo.singleRegionInfo.ts),
@@ -622,7 +641,7 @@ func (s *eventFeedSession) scheduleRegionRequest(ctx context.Context, sri single
 			case <-ctx.Done():
 			}
 		case regionspan.LockRangeStatusStale:
-			log.Info("request expired",
+			logger().Info("request expired",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("regionID", sri.verID.GetID()),
 				zap.Stringer("span", sri.span),
@@ -672,7 +691,7 @@ func (s *eventFeedSession) scheduleRegionRequest(ctx context.Context, sri single
 // error handling. This function is non blocking even if error channel is full.
 // CAUTION: Note that this should only be called in a context that the region has locked it's range.
 func (s *eventFeedSession) onRegionFail(ctx context.Context, errorInfo regionErrorInfo, revokeToken bool) {
-	log.Debug("region failed",
+	logger().Debug("region failed",
 		zap.String("changefeed", s.client.changefeed),
 		zap.Uint64("regionID", errorInfo.verID.GetID()),
 		zap.Error(errorInfo.err),
@@ -706,6 +725,12 @@ func (s *eventFeedSession) requestRegionToStore(
 			return errors.Trace(ctx.Err())
 		case sri = <-s.regionRouter.Chan():
 		}
+		// The region waits here if too many regions of the changefeed are
+		// being scanned.
+		slot, err := s.acquireScanSlot(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
 		requestID := allocID()
 
 		extraOp := kvrpcpb.ExtraOp_Noop
@@ -734,14 +759,13 @@ func (s *eventFeedSession) requestRegionToStore(
 		// each TiKV store has an independent pendingRegions.
 		var pendingRegions *syncRegionFeedStateMap
 
-		var err error
 		stream, ok := s.getStream(rpcCtx.Addr)
 		if ok {
 			var ok bool
 			pendingRegions, ok = storePendingRegions[rpcCtx.Addr]
 			if !ok {
 				// Should never happen
-				log.Panic("pending regions is not found for store",
+				logger().Panic("pending regions is not found for store",
 					zap.String("changefeed", s.client.changefeed),
 					zap.String("store", rpcCtx.Addr))
 			}
@@ -752,7 +776,7 @@ func (s *eventFeedSession) requestRegionToStore(
 			pendingRegions = newSyncRegionFeedStateMap()
 			storePendingRegions[rpcCtx.Addr] = pendingRegions
 			storeID := rpcCtx.Peer.GetStoreId()
-			log.Info("creating new stream to store to send request",
+			logger().Info("creating new stream to store to send request",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("regionID", sri.verID.GetID()),
 				zap.Uint64("requestID", requestID),
@@ -763,7 +787,7 @@ func (s *eventFeedSession) requestRegionToStore(
 			stream, err = s.client.newStream(streamCtx, rpcCtx.Addr, storeID)
 			if err != nil {
 				// if get stream failed, maybe the store is down permanently, we should try to relocate the active store
-				log.Warn("get grpc stream client failed",
+				logger().Warn("get grpc stream client failed",
 					zap.String("changefeed", s.client.changefeed),
 					zap.Uint64("regionID", sri.verID.GetID()),
 					zap.Uint64("requestID", requestID),
@@ -779,6 +803,7 @@ func (s *eventFeedSession) requestRegionToStore(
 				}
 				bo := tikv.NewBackoffer(ctx, tikvRequestMaxBackoff)
 				s.client.regionCache.OnSendFail(bo, rpcCtx, regionScheduleReload, err)
+				slot.release()
 				errInfo := newRegionErrorInfo(sri, &connectToStoreErr{})
 				s.onRegionFail(ctx, errInfo, false /* revokeToken */)
 				continue
@@ -792,11 +817,12 @@ func (s *eventFeedSession) requestRegionToStore(
 		}
 
 		state := newRegionFeedState(sri, requestID)
+		state.scanSlot = slot
 		pendingRegions.insert(requestID, state)
 
-		logReq := log.Debug
+		logReq := logger().Debug
 		if s.isPullerInit.IsInitialized() {
-			logReq = log.Info
+			logReq = logger().Info
 		}
 		logReq("start new request",
 			zap.String("changefeed", s.client.changefeed),
@@ -807,7 +833,7 @@ func (s *eventFeedSession) requestRegionToStore(
 		// If Send error, the receiver should have received error too or will receive error soon. So we doesn't need
 		// to do extra work here.
 		if err != nil {
-			log.Warn("send request to stream failed",
+			logger().Warn("send request to stream failed",
 				zap.String("changefeed", s.client.changefeed),
 				zap.String("addr", rpcCtx.Addr),
 				zap.Uint64("storeID", getStoreID(rpcCtx)),
@@ -816,7 +842,7 @@ func (s *eventFeedSession) requestRegionToStore(
 				zap.Error(err))
 			err1 := stream.client.CloseSend()
 			if err1 != nil {
-				log.Warn("failed to close stream",
+				logger().Warn("failed to close stream",
 					zap.Error(err1), zap.String("changefeed", s.client.changefeed))
 			}
 			// Delete the stream from the map so that the next time the store is accessed, the stream will be
@@ -838,6 +864,7 @@ func (s *eventFeedSession) requestRegionToStore(
 				continue
 			}
 
+			slot.release()
 			errInfo := newRegionErrorInfo(sri, &sendRequestToStoreErr{})
 			s.onRegionFail(ctx, errInfo, false /* revokeToken */)
 		} else {
@@ -866,7 +893,7 @@ func (s *eventFeedSession) dispatchRequest(
 			s.regionChSizeGauge.Dec()
 		}
 
-		log.Debug("dispatching region",
+		logger().Debug("dispatching region",
 			zap.String("changefeed", s.client.changefeed),
 			zap.Uint64("regionID", sri.verID.GetID()))
 
@@ -899,7 +926,7 @@ func (s *eventFeedSession) dispatchRequest(
 		}
 		if rpcCtx == nil {
 			// The region info is invalid. Retry the span.
-			log.Info("cannot get rpcCtx, retry span",
+			logger().Info("cannot get rpcCtx, retry span",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("regionID", sri.verID.GetID()),
 				zap.Stringer("span", sri.span),
@@ -939,7 +966,7 @@ func (s *eventFeedSession) divideAndSendEventFeedToRegions(
 			for _, region := range regions {
 				if region.GetMeta() == nil {
 					err = cerror.ErrMetaNotInRegion.GenWithStackByArgs()
-					log.Warn("batch load region",
+					logger().Warn("batch load region",
 						zap.Stringer("span", nextSpan), zap.Error(err),
 						zap.String("changefeed", s.client.changefeed),
 					)
@@ -949,14 +976,14 @@ func (s *eventFeedSession) divideAndSendEventFeedToRegions(
 			}
 			if !regionspan.CheckRegionsLeftCover(metas, nextSpan) {
 				err = cerror.ErrRegionsNotCoverSpan.GenWithStackByArgs(nextSpan, metas)
-				log.Warn("ScanRegions",
+				logger().Warn("ScanRegions",
 					zap.Stringer("span", nextSpan),
 					zap.Reflect("regions", metas), zap.Error(err),
 					zap.String("changefeed", s.client.changefeed),
 				)
 				return err
 			}
-			log.Debug("ScanRegions",
+			logger().Debug("ScanRegions",
 				zap.Stringer("span", nextSpan),
 				zap.Reflect("regions", metas),
 				zap.String("changefeed", s.client.changefeed))
@@ -972,7 +999,7 @@ func (s *eventFeedSession) divideAndSendEventFeedToRegions(
 			if err != nil {
 				return errors.Trace(err)
 			}
-			log.Debug("get partialSpan",
+			logger().Debug("get partialSpan",
 				zap.Stringer("span", partialSpan),
 				zap.Uint64("regionID", region.Id),
 				zap.String("changefeed", s.client.changefeed))
@@ -981,7 +1008,7 @@ func (s *eventFeedSession) divideAndSendEventFeedToRegions(
 
 			sri := newSingleRegionInfo(tiRegion.VerID(), partialSpan, ts, nil)
 			s.scheduleRegionRequest(ctx, sri)
-			log.Debug("partialSpan scheduled",
+			logger().Debug("partialSpan scheduled",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Stringer("span", partialSpan),
 				zap.Uint64("regionID", region.Id),
@@ -1070,20 +1097,20 @@ func (s *eventFeedSession) handleError(ctx context.Context, errInfo regionErrorI
 				zap.Uint64("regionID", duplicatedRequest.RegionId))
 			return errUnreachable
 		} else if compatibility := innerErr.GetCompatibility(); compatibility != nil {
-			log.Error("tikv reported compatibility error, which is not expected",
+			logger().Error("tikv reported compatibility error, which is not expected",
 				zap.String("changefeed", s.client.changefeed),
 				zap.String("rpcCtx", errInfo.rpcCtx.String()),
 				zap.Stringer("error", compatibility))
 			return cerror.ErrVersionIncompatible.GenWithStackByArgs(compatibility)
 		} else if mismatch := innerErr.GetClusterIdMismatch(); mismatch != nil {
-			log.Error("tikv reported the request cluster ID mismatch error, which is not expected",
+			logger().Error("tikv reported the request cluster ID mismatch error, which is not expected",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("tikvCurrentClusterID", mismatch.Current),
 				zap.Uint64("requestClusterID", mismatch.Request))
 			return cerror.ErrClusterIDMismatch.GenWithStackByArgs(mismatch.Current, mismatch.Request)
 		} else {
 			metricFeedUnknownErrorCounter.Inc()
-			log.Warn("receive empty or unknown error msg",
+			logger().Warn("receive empty or unknown error msg",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Stringer("error", innerErr))
 		}
@@ -1136,7 +1163,7 @@ func (s *eventFeedSession) receiveFromStream(
 	// Cancel the pending regions if the stream failed. Otherwise it will remain unhandled in the pendingRegions list
 	// however not registered in the new reconnected stream.
 	defer func() {
-		log.Info("stream to store closed",
+		logger().Info("stream to store closed",
 			zap.String("changefeed", s.client.changefeed),
 			zap.String("addr", addr), zap.Uint64("storeID", storeID))
 
@@ -1144,6 +1171,7 @@ func (s *eventFeedSession) receiveFromStream(
 
 		remainingRegions := pendingRegions.takeAll()
 		for _, state := range remainingRegions {
+			state.scanSlot.release()
 			errInfo := newRegionErrorInfo(state.sri, cerror.ErrPendingRegionCancel.FastGenByArgs())
 			s.onRegionFail(ctx, errInfo, true /* revokeToken */)
 		}
@@ -1180,14 +1208,14 @@ func (s *eventFeedSession) receiveFromStream(
 		})
 		if err != nil {
 			if status.Code(errors.Cause(err)) == codes.Canceled {
-				log.Debug(
+				logger().Debug(
 					"receive from stream canceled",
 					zap.String("changefeed", s.client.changefeed),
 					zap.String("addr", addr),
 					zap.Uint64("storeID", storeID),
 				)
 			} else {
-				log.Warn(
+				logger().Warn(
 					"failed to receive from stream",
 					zap.String("changefeed", s.client.changefeed),
 					zap.String("addr", addr),
@@ -1228,7 +1256,7 @@ func (s *eventFeedSession) receiveFromStream(
 			if cevent.ResolvedTs != nil {
 				regionCount = len(cevent.ResolvedTs.Regions)
 			}
-			log.Warn("change data event size too large",
+			logger().Warn("change data event size too large",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Int("size", size), zap.Int("eventLen", len(cevent.Events)),
 				zap.Int("resolved region count", regionCount))
@@ -1264,7 +1292,7 @@ func (s *eventFeedSession) sendRegionChangeEvent(
 	isNewSubscription := !ok
 	if ok {
 		if state.requestID < event.RequestId {
-			log.Debug("region state entry will be replaced because received message of newer requestID",
+			logger().Debug("region state entry will be replaced because received message of newer requestID",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("regionID", event.RegionId),
 				zap.Uint64("oldRequestID", state.requestID),
@@ -1272,7 +1300,7 @@ func (s *eventFeedSession) sendRegionChangeEvent(
 				zap.String("addr", addr))
 			isNewSubscription = true
 		} else if state.requestID > event.RequestId {
-			log.Warn("drop event due to event belongs to a stale request",
+			logger().Warn("drop event due to event belongs to a stale request",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("regionID", event.RegionId),
 				zap.Uint64("requestID", event.RequestId),
@@ -1289,7 +1317,7 @@ func (s *eventFeedSession) sendRegionChangeEvent(
 		// Firstly load the region info.
 		state, ok = pendingRegions.take(event.RequestId)
 		if !ok {
-			log.Warn("drop event due to region feed is removed",
+			logger().Warn("drop event due to region feed is removed",
 				zap.String("changefeed", s.client.changefeed),
 				zap.Uint64("regionID", event.RegionId),
 				zap.Uint64("requestID", event.RequestId),
@@ -1300,7 +1328,7 @@ func (s *eventFeedSession) sendRegionChangeEvent(
 		state.start()
 		worker.setRegionState(event.RegionId, state)
 	} else if state.isStopped() {
-		log.Warn("drop event due to region feed stopped",
+		logger().Warn("drop event due to region feed stopped",
 			zap.String("changefeed", s.client.changefeed),
 			zap.Uint64("regionID", event.RegionId),
 			zap.Uint64("requestID", event.RequestId),
@@ -1330,7 +1358,7 @@ func (s *eventFeedSession) sendResolvedTs(
 		state, ok := worker.getRegionState(regionID)
 		if ok {
 			if state.isStopped() {
-				log.Debug("drop resolved ts due to region feed stopped",
+				logger().Debug("drop resolved ts due to region feed stopped",
 					zap.String("changefeed", s.client.changefeed),
 					zap.Uint64("regionID", regionID),
 					zap.Uint64("requestID", state.requestID),
@@ -18,7 +18,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/pingcap/log"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
 	"go.uber.org/zap"
@@ -69,7 +68,7 @@ func (ca *connArray) resize(ctx context.Context, credential *security.Credential
 }
 
 func createClientConn(ctx context.Context, credential *security.Credential, target string) (*grpc.ClientConn, error) {
-	grpcTLSOption, err := credential.ToGRPCDialOption()
+	grpcTLSOption, err := credential.ToGRPCDialOptionWithPinning()
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +217,7 @@ func (pool *GrpcPoolImpl) ReleaseConn(sc *sharedConn, addr string) {
 	pool.poolMu.RLock()
 	defer pool.poolMu.RUnlock()
 	if bucket, ok := pool.bucketConns[addr]; !ok {
-		log.Warn("resource is not found in grpc pool", zap.String("addr", addr))
+		logger().Warn("resource is not found in grpc pool", zap.String("addr", addr))
 	} else {
 		bucket.mu.Lock()
 		sc.active--
@@ -241,7 +240,7 @@ func (pool *GrpcPoolImpl) RecycleConn(ctx context.Context) {
 			for addr, bucket := range pool.bucketConns {
 				empty := bucket.recycle()
 				if empty {
-					log.Info("recycle connections in grpc pool", zap.String("address", addr))
+					logger().Info("recycle connections in grpc pool", zap.String("address", addr))
 					delete(pool.bucketConns, addr)
 					grpcPoolStreamGauge.DeleteLabelValues(addr)
 				}
@@ -15,7 +15,6 @@ package kv
 
 import (
 	"github.com/pingcap/kvproto/pkg/cdcpb"
-	"github.com/pingcap/log"
 	"go.uber.org/zap"
 )
 
@@ -83,7 +82,7 @@ func (m *matcher) matchCachedRow() []*cdcpb.Event_Row {
 			// when cdc receives a commit log without a corresponding
 			// prewrite log before initialized, a committed log  with
 			// the same key and start-ts must have been received.
-			log.Info("ignore commit event without prewrite",
+			logger().Info("ignore commit event without prewrite",
 				zap.Binary("key", cacheEntry.GetKey()),
 				zap.Uint64("ts", cacheEntry.GetStartTs()))
 			continue
@@ -101,6 +101,34 @@ var (
 			Name:      "grpc_stream_count",
 			Help:      "active stream count of each gRPC connection",
 		}, []string{"store"})
+	incrementalScanRegionGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "incremental_scan_region_count",
+			Help:      "The number of regions being scanned or waiting in the queue of incremental scans",
+		}, []string{"changefeed", "state"})
+	incrementalScanFinishedRegionCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "incremental_scan_finished_region_count",
+			Help:      "The number of regions whose incremental scans are finished",
+		}, []string{"changefeed"})
+	incrementalScanBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "incremental_scan_bytes",
+			Help:      "The bytes of the events received by incremental scans",
+		}, []string{"changefeed"})
+	incrementalScanWaitDurationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "kvclient",
+			Name:      "incremental_scan_wait_duration",
+			Help:      "The total seconds incremental scans wait for the limits",
+		}, []string{"changefeed", "type"})
 )
 
 // InitMetrics registers all metrics in the kv package
@@ -116,6 +144,10 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(cachedRegionSize)
 	registry.MustRegister(batchResolvedEventSize)
 	registry.MustRegister(grpcPoolStreamGauge)
+	registry.MustRegister(incrementalScanRegionGauge)
+	registry.MustRegister(incrementalScanFinishedRegionCounter)
+	registry.MustRegister(incrementalScanBytesCounter)
+	registry.MustRegister(incrementalScanWaitDurationCounter)
 
 	// Register client metrics to registry.
 	registry.MustRegister(grpcMetrics)
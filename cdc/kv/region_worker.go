@@ -24,7 +24,6 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/cdcpb"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -239,7 +238,7 @@ func (w *regionWorker) handleSingleRegionError(err error, state *regionFeedState
 		state.sri.ts = state.lastResolvedTs
 	}
 	regionID := state.sri.verID.GetID()
-	log.Info("single region event feed disconnected",
+	logger().Info("single region event feed disconnected",
 		zap.String("changefeed", w.session.client.changefeed),
 		zap.Uint64("regionID", regionID),
 		zap.Uint64("requestID", state.requestID),
@@ -274,6 +273,7 @@ func (w *regionWorker) handleSingleRegionError(err error, state *regionFeedState
 	}
 
 	revokeToken := !state.initialized
+	state.scanSlot.release()
 	// since the context used in region worker will be cancelled after region
 	// worker exits, we must use the parent context to prevent regionErrorInfo loss.
 	errInfo := newRegionErrorInfo(state.sri, err)
@@ -302,7 +302,7 @@ func (w *regionWorker) resolveLock(ctx context.Context) error {
 		case <-advanceCheckTicker.C:
 			currentTimeFromPD, err := w.session.client.pdClock.CurrentTime()
 			if err != nil {
-				log.Warn("failed to get current version from PD",
+				logger().Warn("failed to get current version from PD",
 					zap.Error(err), zap.String("changefeed", w.session.client.changefeed))
 				continue
 			}
@@ -337,7 +337,7 @@ func (w *regionWorker) resolveLock(ctx context.Context) error {
 				if sinceLastResolvedTs >= resolveLockInterval {
 					sinceLastEvent := time.Since(rts.ts.eventTime)
 					if sinceLastResolvedTs > reconnectInterval && sinceLastEvent > reconnectInterval {
-						log.Warn("kv client reconnect triggered", zap.String("changefeed", w.session.client.changefeed),
+						logger().Warn("kv client reconnect triggered", zap.String("changefeed", w.session.client.changefeed),
 							zap.Duration("duration", sinceLastResolvedTs), zap.Duration("since last event", sinceLastResolvedTs))
 						return errReconnect
 					}
@@ -352,7 +352,7 @@ func (w *regionWorker) resolveLock(ctx context.Context) error {
 						w.rtsManager.Upsert(rts)
 						continue
 					}
-					log.Warn("region not receiving resolved event from tikv or resolved ts is not pushing for too long time, try to resolve lock",
+					logger().Warn("region not receiving resolved event from tikv or resolved ts is not pushing for too long time, try to resolve lock",
 						zap.String("changefeed", w.session.client.changefeed),
 						zap.String("addr", w.storeAddr),
 						zap.Uint64("regionID", rts.regionID),
@@ -363,7 +363,7 @@ func (w *regionWorker) resolveLock(ctx context.Context) error {
 					)
 					err = w.session.lockResolver.Resolve(ctx, rts.regionID, maxVersion)
 					if err != nil {
-						log.Warn("failed to resolve lock",
+						logger().Warn("failed to resolve lock",
 							zap.Uint64("regionID", rts.regionID), zap.Error(err),
 							zap.String("changefeed", w.session.client.changefeed))
 						continue
@@ -393,7 +393,7 @@ func (w *regionWorker) processEvent(ctx context.Context, event *regionStatefulEv
 				err = w.handleSingleRegionError(err, event.state)
 			}
 		case *cdcpb.Event_Admin_:
-			log.Info("receive admin event",
+			logger().Info("receive admin event",
 				zap.Stringer("event", event.changeEvent),
 				zap.String("changefeed", w.session.client.changefeed))
 		case *cdcpb.Event_Error:
@@ -446,7 +446,7 @@ func (w *regionWorker) eventHandler(ctx context.Context) error {
 		// event == nil means the region worker should exit and re-establish
 		// all existing regions.
 		if !ok || event == nil {
-			log.Info("region worker closed by error",
+			logger().Info("region worker closed by error",
 				zap.String("changefeed", w.session.client.changefeed))
 			exitEventHandler = true
 			return
@@ -588,7 +588,7 @@ func (w *regionWorker) cancelStream(delay time.Duration) {
 		// to avoid too frequent region rebuilt.
 		time.Sleep(delay)
 	} else {
-		log.Warn("gRPC stream cancel func not found",
+		logger().Warn("gRPC stream cancel func not found",
 			zap.String("addr", w.storeAddr),
 			zap.String("changefeed", w.session.client.changefeed))
 	}
@@ -650,10 +650,17 @@ func (w *regionWorker) handleEventEntry(
 			w.metrics.metricDroppedEventSize.Observe(float64(entry.Size()))
 			continue
 		}
+		if !state.initialized && entry.Type != cdcpb.Event_INITIALIZED {
+			// The events received before the region is initialized are
+			// scanned, they're limited by the scan limiter of the changefeed.
+			if err := w.session.scanLimiter.waitBytes(ctx, entry.Size()); err != nil {
+				return errors.Trace(err)
+			}
+		}
 		switch entry.Type {
 		case cdcpb.Event_INITIALIZED:
 			if time.Since(state.startFeedTime) > 20*time.Second {
-				log.Warn("The time cost of initializing is too much",
+				logger().Warn("The time cost of initializing is too much",
 					zap.String("changefeed", w.session.client.changefeed),
 					zap.Duration("duration", time.Since(state.startFeedTime)),
 					zap.Uint64("regionID", regionID))
@@ -662,6 +669,8 @@ func (w *regionWorker) handleEventEntry(
 
 			state.initialized = true
 			w.session.regionRouter.Release(state.sri.rpcCtx.Addr)
+			state.scanSlot.release()
+			w.session.scanLimiter.regionScanned()
 			cachedEvents := state.matcher.matchCachedRow()
 			for _, cachedEvent := range cachedEvents {
 				revent, err := assembleRowEvent(regionID, cachedEvent, w.enableOldValue)
@@ -760,7 +769,7 @@ func (w *regionWorker) handleResolvedTs(
 	}
 
 	if resolvedTs < state.lastResolvedTs {
-		log.Warn("The resolvedTs is fallen back in kvclient",
+		logger().Warn("The resolvedTs is fallen back in kvclient",
 			zap.String("changefeed", w.session.client.changefeed),
 			zap.String("EventType", "RESOLVED"),
 			zap.Uint64("resolvedTs", resolvedTs),
@@ -806,6 +815,7 @@ func (w *regionWorker) evictAllRegions() {
 			}
 			revokeToken := !state.initialized
 			state.lock.Unlock()
+			state.scanSlot.release()
 			// since the context used in region worker will be cancelled after
 			// region worker exits, we must use the parent context to prevent
 			// regionErrorInfo loss.
@@ -0,0 +1,289 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+type scanLimiterCtxKey struct{}
+
+// PutIncrementalScanLimiterInCtx returns a new child context with the
+// specified incremental scan limiter stored, the event feeds started with the
+// context are limited by it.
+func PutIncrementalScanLimiterInCtx(ctx context.Context, l *IncrementalScanLimiter) context.Context {
+	return context.WithValue(ctx, scanLimiterCtxKey{}, l)
+}
+
+// IncrementalScanLimiterFromCtx returns the incremental scan limiter stored in
+// the context, nil is returned if there's none.
+func IncrementalScanLimiterFromCtx(ctx context.Context) *IncrementalScanLimiter {
+	l, _ := ctx.Value(scanLimiterCtxKey{}).(*IncrementalScanLimiter)
+	return l
+}
+
+// IncrementalScanLimiter limits the incremental scans of all the tables of a
+// changefeed on a capture, so large backfills, e.g. right after the changefeed
+// is created, don't overload TiKV. It limits the number of regions scanned
+// concurrently, the regions exceeding the limit wait in a FIFO queue, and the
+// bytes of the scanned events per second. The limits can be adjusted by
+// SetLimits when the changefeed is running.
+//
+// A nil *IncrementalScanLimiter doesn't limit the scans.
+type IncrementalScanLimiter struct {
+	changefeed   string
+	bytesLimiter *rate.Limiter
+
+	mu                sync.Mutex
+	regionConcurrency int
+	bytesPerSecond    int64
+	scanning          int
+	// queue holds the channels of the waiting regions, a channel is closed
+	// when the region is allowed to scan.
+	queue *list.List
+
+	metricScanningRegions    prometheus.Gauge
+	metricQueuedRegions      prometheus.Gauge
+	metricFinishedRegions    prometheus.Counter
+	metricScannedBytes       prometheus.Counter
+	metricRegionWaitDuration prometheus.Counter
+	metricBytesWaitDuration  prometheus.Counter
+}
+
+// NewIncrementalScanLimiter creates an IncrementalScanLimiter, the scans are
+// not limited if cfg is nil.
+func NewIncrementalScanLimiter(changefeed string, cfg *config.IncrementalScanConfig) *IncrementalScanLimiter {
+	l := &IncrementalScanLimiter{
+		changefeed:   changefeed,
+		bytesLimiter: rate.NewLimiter(rate.Inf, 0),
+		queue:        list.New(),
+
+		metricScanningRegions:    incrementalScanRegionGauge.WithLabelValues(changefeed, "scanning"),
+		metricQueuedRegions:      incrementalScanRegionGauge.WithLabelValues(changefeed, "queued"),
+		metricFinishedRegions:    incrementalScanFinishedRegionCounter.WithLabelValues(changefeed),
+		metricScannedBytes:       incrementalScanBytesCounter.WithLabelValues(changefeed),
+		metricRegionWaitDuration: incrementalScanWaitDurationCounter.WithLabelValues(changefeed, "region"),
+		metricBytesWaitDuration:  incrementalScanWaitDurationCounter.WithLabelValues(changefeed, "bytes"),
+	}
+	l.SetLimits(cfg)
+	return l
+}
+
+// SetLimits updates the limits of the scans, nil removes all the limits.
+func (l *IncrementalScanLimiter) SetLimits(cfg *config.IncrementalScanConfig) {
+	if l == nil {
+		return
+	}
+	var regionConcurrency int
+	var bytesPerSecond int64
+	if cfg != nil {
+		regionConcurrency, bytesPerSecond = cfg.RegionConcurrency, cfg.BytesPerSecond
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if regionConcurrency == l.regionConcurrency && bytesPerSecond == l.bytesPerSecond {
+		return
+	}
+	logger().Info("update the limits of incremental scans",
+		zap.String("changefeed", l.changefeed),
+		zap.Int("regionConcurrency", regionConcurrency),
+		zap.Int64("bytesPerSecond", bytesPerSecond))
+	l.regionConcurrency = regionConcurrency
+	l.bytesPerSecond = bytesPerSecond
+	if bytesPerSecond <= 0 {
+		l.bytesLimiter.SetLimit(rate.Inf)
+	} else {
+		l.bytesLimiter.SetLimit(rate.Limit(bytesPerSecond))
+		l.bytesLimiter.SetBurst(int(bytesPerSecond))
+	}
+	// The waiting regions may be allowed by the new limit.
+	l.dispatchLocked()
+}
+
+// acquire waits until a region is allowed to scan, the region must call
+// release after it's scanned or failed.
+func (l *IncrementalScanLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.queue.Len() == 0 && l.allowLocked() {
+		l.scanning++
+		l.metricScanningRegions.Set(float64(l.scanning))
+		l.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	elem := l.queue.PushBack(ready)
+	l.metricQueuedRegions.Set(float64(l.queue.Len()))
+	l.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-ready:
+		l.metricRegionWaitDuration.Add(time.Since(start).Seconds())
+		return nil
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-ready:
+		// The region has been allowed, give the slot back.
+		l.releaseLocked()
+	default:
+		l.queue.Remove(elem)
+		l.metricQueuedRegions.Set(float64(l.queue.Len()))
+	}
+	return errors.Trace(ctx.Err())
+}
+
+// release gives back the slot of a scanning region.
+func (l *IncrementalScanLimiter) release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+func (l *IncrementalScanLimiter) releaseLocked() {
+	l.scanning--
+	l.dispatchLocked()
+}
+
+func (l *IncrementalScanLimiter) allowLocked() bool {
+	return l.regionConcurrency <= 0 || l.scanning < l.regionConcurrency
+}
+
+// dispatchLocked allows the waiting regions in order as long as the limit
+// allows.
+func (l *IncrementalScanLimiter) dispatchLocked() {
+	for l.queue.Len() > 0 && l.allowLocked() {
+		ready := l.queue.Remove(l.queue.Front()).(chan struct{})
+		close(ready)
+		l.scanning++
+	}
+	l.metricScanningRegions.Set(float64(l.scanning))
+	l.metricQueuedRegions.Set(float64(l.queue.Len()))
+}
+
+// regionScanned records a region whose incremental scan is finished.
+func (l *IncrementalScanLimiter) regionScanned() {
+	if l == nil {
+		return
+	}
+	l.metricFinishedRegions.Inc()
+}
+
+// waitBytes waits until n bytes of scanned events are allowed.
+func (l *IncrementalScanLimiter) waitBytes(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.metricScannedBytes.Add(float64(n))
+	if l.bytesLimiter.Limit() == rate.Inf {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		l.metricBytesWaitDuration.Add(time.Since(start).Seconds())
+	}()
+	// n larger than the burst is split into several waits.
+	for n > 0 {
+		chunk := n
+		if burst := l.bytesLimiter.Burst(); chunk > burst {
+			chunk = burst
+		}
+		if err := l.bytesLimiter.WaitN(ctx, chunk); err != nil {
+			return errors.Trace(err)
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// Close removes the metrics of the limiter.
+func (l *IncrementalScanLimiter) Close() {
+	if l == nil {
+		return
+	}
+	incrementalScanRegionGauge.DeleteLabelValues(l.changefeed, "scanning")
+	incrementalScanRegionGauge.DeleteLabelValues(l.changefeed, "queued")
+	incrementalScanFinishedRegionCounter.DeleteLabelValues(l.changefeed)
+	incrementalScanBytesCounter.DeleteLabelValues(l.changefeed)
+	incrementalScanWaitDurationCounter.DeleteLabelValues(l.changefeed, "region")
+	incrementalScanWaitDurationCounter.DeleteLabelValues(l.changefeed, "bytes")
+}
+
+// scanSlot is the slot of a region allowed to scan by the limiter of an event
+// feed session, it's released once the region is initialized or failed.
+type scanSlot struct {
+	once    sync.Once
+	session *eventFeedSession
+}
+
+// acquireScanSlot waits until a region of the session is allowed to scan, nil
+// is returned if the session is not limited.
+func (s *eventFeedSession) acquireScanSlot(ctx context.Context) (*scanSlot, error) {
+	if s.scanLimiter == nil {
+		return nil, nil
+	}
+	if err := s.scanLimiter.acquire(ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
+	slot := &scanSlot{session: s}
+	s.scanSlotsMu.Lock()
+	s.scanSlots[slot] = struct{}{}
+	s.scanSlotsMu.Unlock()
+	return slot, nil
+}
+
+// release gives back the slot, it's safe to be called more than once.
+func (slot *scanSlot) release() {
+	if slot == nil {
+		return
+	}
+	slot.once.Do(func() {
+		s := slot.session
+		s.scanSlotsMu.Lock()
+		delete(s.scanSlots, slot)
+		s.scanSlotsMu.Unlock()
+		s.scanLimiter.release()
+	})
+}
+
+// releaseScanSlots gives back the slots of the regions still scanning when
+// the session exits.
+func (s *eventFeedSession) releaseScanSlots() {
+	s.scanSlotsMu.Lock()
+	slots := make([]*scanSlot, 0, len(s.scanSlots))
+	for slot := range s.scanSlots {
+		slots = append(slots, slot)
+	}
+	s.scanSlotsMu.Unlock()
+	for _, slot := range slots {
+		slot.release()
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func acquireAsync(ctx context.Context, l *IncrementalScanLimiter) <-chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- l.acquire(ctx)
+	}()
+	return errCh
+}
+
+func requireQueued(t *testing.T, l *IncrementalScanLimiter, n int) {
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.queue.Len() == n
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestIncrementalScanLimiterRegionConcurrency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := NewIncrementalScanLimiter("test-region-concurrency",
+		&config.IncrementalScanConfig{RegionConcurrency: 2})
+	defer l.Close()
+	require.Nil(t, l.acquire(ctx))
+	require.Nil(t, l.acquire(ctx))
+
+	// The regions exceeding the limit wait in order.
+	errCh1 := acquireAsync(ctx, l)
+	requireQueued(t, l, 1)
+	errCh2 := acquireAsync(ctx, l)
+	requireQueued(t, l, 2)
+	l.release()
+	require.Nil(t, <-errCh1)
+	requireQueued(t, l, 1)
+
+	// The canceled region leaves the queue.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	errCh3 := acquireAsync(cancelCtx, l)
+	requireQueued(t, l, 2)
+	cancel()
+	require.Equal(t, context.Canceled, errors.Cause(<-errCh3))
+	requireQueued(t, l, 1)
+
+	// The waiting regions are allowed by a higher limit.
+	l.SetLimits(&config.IncrementalScanConfig{RegionConcurrency: 3})
+	require.Nil(t, <-errCh2)
+	requireQueued(t, l, 0)
+	require.Equal(t, 3, l.scanning)
+
+	// No limit.
+	l.SetLimits(nil)
+	for i := 0; i < 10; i++ {
+		require.Nil(t, l.acquire(ctx))
+	}
+	for i := 0; i < 13; i++ {
+		l.release()
+	}
+	require.Equal(t, 0, l.scanning)
+
+	var nilLimiter *IncrementalScanLimiter
+	require.Nil(t, nilLimiter.acquire(ctx))
+	nilLimiter.release()
+	nilLimiter.SetLimits(&config.IncrementalScanConfig{RegionConcurrency: 1})
+}
+
+func TestIncrementalScanLimiterBytes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := NewIncrementalScanLimiter("test-bytes", nil)
+	defer l.Close()
+	require.Nil(t, l.waitBytes(ctx, 1<<30))
+
+	l.SetLimits(&config.IncrementalScanConfig{BytesPerSecond: 100})
+	require.Nil(t, l.waitBytes(ctx, 100))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.NotNil(t, l.waitBytes(timeoutCtx, 100))
+
+	var nilLimiter *IncrementalScanLimiter
+	require.Nil(t, nilLimiter.waitBytes(ctx, 1<<30))
+}
+
+func TestScanSlot(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := NewIncrementalScanLimiter("test-scan-slot",
+		&config.IncrementalScanConfig{RegionConcurrency: 2})
+	defer l.Close()
+	s := &eventFeedSession{scanLimiter: l, scanSlots: make(map[*scanSlot]struct{})}
+	slot1, err := s.acquireScanSlot(ctx)
+	require.Nil(t, err)
+	_, err = s.acquireScanSlot(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 2, l.scanning)
+
+	// A slot is released only once.
+	slot1.release()
+	slot1.release()
+	require.Equal(t, 1, l.scanning)
+
+	// The slots still held are released when the session exits.
+	s.releaseScanSlots()
+	require.Equal(t, 0, l.scanning)
+	require.Empty(t, s.scanSlots)
+
+	// The regions of a session without limiter don't hold slots.
+	s = &eventFeedSession{}
+	slot, err := s.acquireScanSlot(ctx)
+	require.Nil(t, err)
+	require.Nil(t, slot)
+	slot.release()
+	s.releaseScanSlots()
+}
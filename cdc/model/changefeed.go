@@ -18,6 +18,7 @@ import (
 	"math"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/cyclic/mark"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/secret"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
@@ -116,6 +118,17 @@ type ChangeFeedInfo struct {
 	SyncPointEnabled  bool          `json:"sync-point-enabled"`
 	SyncPointInterval time.Duration `json:"sync-point-interval"`
 	CreatorVersion    string        `json:"creator-version"`
+	// Labels are the user-defined labels attached to the metrics of the changefeed.
+	Labels map[string]string `json:"labels,omitempty"`
+	// FrozenTables are the tables allowed to be replicated when
+	// `filter.freeze-tables` is enabled.
+	FrozenTables []TableName `json:"frozen-tables,omitempty"`
+	// RedoRewindTs is the ts the redo log meta must be rewound to before the
+	// changefeed runs again, it's 0 if there is nothing to rewind.
+	RedoRewindTs uint64 `json:"redo-rewind-ts,omitempty"`
+	// FinalBarrier is true if the changefeed is requested to be removed after
+	// the final barrier is reached and the end marker is written.
+	FinalBarrier bool `json:"final-barrier,omitempty"`
 }
 
 const changeFeedIDMaxLen = 128
@@ -131,6 +144,19 @@ func ValidateChangefeedID(changefeedID string) error {
 	return nil
 }
 
+var changefeedLabelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateChangefeedLabels returns an error if any of the label names is not
+// a valid Prometheus label name.
+func ValidateChangefeedLabels(labels map[string]string) error {
+	for name := range labels {
+		if !changefeedLabelNameRe.MatchString(name) || strings.HasPrefix(name, "__") {
+			return cerror.ErrInvalidChangefeedLabel.GenWithStackByArgs(name)
+		}
+	}
+	return nil
+}
+
 // String implements fmt.Stringer interface, but hide some sensitive information
 func (info *ChangeFeedInfo) String() (str string) {
 	var err error
@@ -145,7 +171,7 @@ func (info *ChangeFeedInfo) String() (str string) {
 		log.Error("failed to unmarshal changefeed info", zap.Error(err))
 		return
 	}
-	sinkURIParsed, err := url.Parse(clone.SinkURI)
+	sinkURIParsed, err := url.Parse(secret.MaskURI(clone.SinkURI))
 	if err != nil {
 		log.Error("failed to parse sink URI", zap.Error(err))
 		return
@@ -313,9 +339,10 @@ func (info *ChangeFeedInfo) fixState() {
 // We no longer support the acceptance of protocols that are not known.
 // The ones that were already accepted need to be fixed.
 func (info *ChangeFeedInfo) fixSinkProtocol() {
-	sinkURIParsed, err := url.Parse(info.SinkURI)
+	sinkURIParsed, err := url.Parse(secret.MaskURI(info.SinkURI))
 	if err != nil {
-		log.Warn("parse sink URI failed", zap.Error(err))
+		// The error contains the sink URI, which may contain credentials.
+		log.Warn("parse sink URI failed")
 		// SAFETY: It is safe to ignore this unresolvable sink URI here,
 		// as it is almost impossible for this to happen.
 		// If we ignore it when fixing it after it happens,
@@ -340,15 +367,21 @@ func (info *ChangeFeedInfo) fixSinkProtocol() {
 	// The sinkURI always has a higher priority.
 	if protocolStr != "" {
 		if needsFix(protocolStr) {
+			if secret.HasPlaceholder(info.SinkURI) {
+				// The placeholders are masked in sinkURIParsed, and the sink
+				// URIs with unknown protocols are from the versions which
+				// don't support secrets.
+				log.Warn("can't fix incompatible protocol of sink URI with secrets",
+					zap.String("protocol", protocolStr))
+				return
+			}
 			rawQuery.Set(config.ProtocolKey, openProtocolStr)
-			oldRawQuery := sinkURIParsed.RawQuery
-			newRawQuery := rawQuery.Encode()
-			sinkURIParsed.RawQuery = newRawQuery
-			fixedSinkURI := sinkURIParsed.String()
+			sinkURIParsed.RawQuery = rawQuery.Encode()
+			// The query may contain credentials, only the protocol is logged.
 			log.Info("handle incompatible protocol from sink URI",
-				zap.String("old URI query", oldRawQuery),
-				zap.String("fixed URI query", newRawQuery))
-			info.SinkURI = fixedSinkURI
+				zap.String("oldProtocol", protocolStr),
+				zap.String("fixedProtocol", openProtocolStr))
+			info.SinkURI = sinkURIParsed.String()
 		}
 	} else {
 		if needsFix(info.Config.Sink.Protocol) {
@@ -608,6 +608,16 @@ func TestFixSinkProtocol(t *testing.T) {
 			},
 			expectedSinkURI: "kafka://127.0.0.1:9092/ticdc-test2?max-message-size=15&protocol=open-protocol",
 		},
+		{
+			// the secrets are not replaced by the masks
+			info: &ChangeFeedInfo{
+				SinkURI: "kafka://127.0.0.1:9092/ticdc-test2?protocol=random&sasl-password=${secret:vault:kv/kafka#password}",
+				Config: &config.ReplicaConfig{
+					Sink: &config.SinkConfig{Protocol: config.ProtocolDefault.String()},
+				},
+			},
+			expectedSinkURI: "kafka://127.0.0.1:9092/ticdc-test2?protocol=random&sasl-password=${secret:vault:kv/kafka#password}",
+		},
 	}
 
 	for _, tc := range sinkURITestCases {
@@ -769,6 +779,17 @@ func TestValidateChangefeedID(t *testing.T) {
 	}
 }
 
+func TestValidateChangefeedLabels(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, ValidateChangefeedLabels(nil))
+	require.Nil(t, ValidateChangefeedLabels(map[string]string{"team": "a", "_env2": ""}))
+	for _, name := range []string{"", "2env", "team-name", "__team"} {
+		err := ValidateChangefeedLabels(map[string]string{name: "a"})
+		require.True(t, cerror.ErrInvalidChangefeedLabel.Equal(err), name)
+	}
+}
+
 func TestGetTs(t *testing.T) {
 	t.Parallel()
 
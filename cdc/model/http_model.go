@@ -107,6 +107,141 @@ type ChangefeedDetail struct {
 	ErrorHis       []int64             `json:"error_history"`
 	CreatorVersion string              `json:"creator_version"`
 	TaskStatus     []CaptureTaskStatus `json:"task_status"`
+	// Barrier is the minimal barrier of the changefeed, the checkpoint can't
+	// advance beyond it.
+	Barrier *ChangeFeedBarrier `json:"barrier,omitempty"`
+	// SlowestCapture is the capture whose checkpoint is the minimal one,
+	// its tables are lagging if the checkpoint is behind the barrier.
+	SlowestCapture string `json:"slowest_capture,omitempty"`
+	// TableDiagnoses explain the tables which are not replicating or keep
+	// being rescheduled.
+	TableDiagnoses []*TableDiagnosis `json:"table_diagnoses,omitempty"`
+	// RedoHealth is the health of the redo logs written by the processor of
+	// each capture, it's empty if the redo log is disabled.
+	RedoHealth map[CaptureID]*RedoHealth `json:"redo_health,omitempty"`
+}
+
+// RedoResolvedTs is the resolved ts of the redo logs of a changefeed, the
+// logs before which are flushed to the storage
+type RedoResolvedTs struct {
+	// ResolvedTs is the minimal resolved ts of the tables, the redo logs of
+	// all the tables can be applied up to it. It's 0 if there is no table.
+	ResolvedTs uint64                 `json:"resolved_ts"`
+	Tables     []*RedoTableResolvedTs `json:"tables"`
+}
+
+// RedoTableResolvedTs is the resolved ts of the redo logs of a table
+type RedoTableResolvedTs struct {
+	TableID   TableID   `json:"table_id"`
+	CaptureID CaptureID `json:"capture_id"`
+	// ResolvedTs is the resolved ts of the table whose redo logs are flushed
+	// to the storage.
+	ResolvedTs uint64 `json:"resolved_ts"`
+	// PendingResolvedTs is the resolved ts of the table sent to the redo log
+	// but not flushed yet.
+	PendingResolvedTs uint64 `json:"pending_resolved_ts"`
+}
+
+// ChangeFeedBarrier holds the minimal barrier of a changefeed
+type ChangeFeedBarrier struct {
+	// Type is the barrier type, ddl, sync-point, finish or consistency-report
+	Type string `json:"type"`
+	Ts   uint64 `json:"ts"`
+}
+
+// TableScheduleState is the scheduling state of a table
+type TableScheduleState string
+
+// All TableScheduleStates
+const (
+	// TableScheduleAbsent means the table is not dispatched to any capture
+	TableScheduleAbsent   TableScheduleState = "absent"
+	TableScheduleAdding   TableScheduleState = "adding"
+	TableScheduleRunning  TableScheduleState = "running"
+	TableScheduleRemoving TableScheduleState = "removing"
+)
+
+// TableDiagnosis aggregates the reasons from the scheduler and processors why
+// a table is not replicating or keeps being rescheduled
+type TableDiagnosis struct {
+	TableID   TableID            `json:"table_id"`
+	CaptureID CaptureID          `json:"capture_id,omitempty"`
+	State     TableScheduleState `json:"state"`
+	// Bouncing is true if the table is added and removed repeatedly recently.
+	Bouncing bool     `json:"bouncing"`
+	Reasons  []string `json:"reasons"`
+	// History is the recent scheduling operations of the table, the oldest first.
+	History []*TableScheduleRecord `json:"history,omitempty"`
+}
+
+// TableScheduleRecord is a scheduling operation of a table
+type TableScheduleRecord struct {
+	Time      JSONTime  `json:"time"`
+	Operation string    `json:"operation"`
+	CaptureID CaptureID `json:"capture_id"`
+	Reason    string    `json:"reason"`
+}
+
+// ConsistencyReportState is the state of a consistency report
+type ConsistencyReportState string
+
+// All ConsistencyReportStates
+const (
+	// ConsistencyReportWaiting means the changefeed hasn't reached the report ts
+	ConsistencyReportWaiting ConsistencyReportState = "waiting"
+	// ConsistencyReportChecksumming means the downstream tables are being checksummed
+	ConsistencyReportChecksumming ConsistencyReportState = "checksumming"
+	ConsistencyReportFinished     ConsistencyReportState = "finished"
+	ConsistencyReportFailed       ConsistencyReportState = "failed"
+)
+
+// ConsistencyReport is the report of the consistency between upstream at
+// UpstreamTs and downstream at DownstreamTs of a changefeed
+type ConsistencyReport struct {
+	State       ConsistencyReportState `json:"state"`
+	RequestTime JSONTime               `json:"request_time"`
+	// UpstreamTs is the ts of upstream which the report is requested at
+	UpstreamTs uint64 `json:"upstream_ts"`
+	// DownstreamTs is the ts of downstream when the changefeed reaches UpstreamTs,
+	// it's recorded in the syncpoint table of downstream
+	DownstreamTs uint64 `json:"downstream_ts"`
+	// Tables is the checksum of the tables in downstream at DownstreamTs, the users
+	// can compare it with ADMIN CHECKSUM TABLE in upstream at UpstreamTs
+	Tables []*TableChecksum `json:"tables"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// TableChecksum is the checksum of a table
+type TableChecksum struct {
+	Schema     string `json:"schema"`
+	Table      string `json:"table"`
+	Checksum   uint64 `json:"checksum"`
+	TotalKvs   uint64 `json:"total_kvs"`
+	TotalBytes uint64 `json:"total_bytes"`
+}
+
+// Clone returns a deep copy of the ConsistencyReport
+func (r *ConsistencyReport) Clone() *ConsistencyReport {
+	clone := *r
+	clone.Tables = make([]*TableChecksum, 0, len(r.Tables))
+	for _, table := range r.Tables {
+		t := *table
+		clone.Tables = append(clone.Tables, &t)
+	}
+	return &clone
+}
+
+// ChangefeedHealth is the health of a changefeed judged by the canary health
+// check
+type ChangefeedHealth struct {
+	Healthy bool `json:"healthy"`
+	// LatencyMs is the end-to-end latency of the changefeed in milliseconds,
+	// it's the larger one of the latency of the last canary row and the time
+	// the canary row in flight has taken
+	LatencyMs int64    `json:"latency_ms"`
+	CheckTime JSONTime `json:"check_time"`
+	// Message tells why the changefeed is unhealthy
+	Message string `json:"message,omitempty"`
 }
 
 // MarshalJSON use to marshal ChangefeedDetail
@@ -138,6 +273,44 @@ type ChangefeedConfig struct {
 	IgnoreTxnStartTs      []uint64           `json:"ignore_txn_start_ts"`
 	MounterWorkerNum      int                `json:"mounter_worker_num" default:"16"`
 	SinkConfig            *config.SinkConfig `json:"sink_config"`
+	// if true, only the tables matched at creation are replicated,
+	// tables created later must be approved explicitly.
+	FreezeTables bool `json:"freeze_tables" default:"false"`
+	// the limits of incremental scans can be updated when the changefeed is running.
+	IncrementalScan *config.IncrementalScanConfig `json:"incremental_scan"`
+}
+
+// ChangefeedCloneConfig is the config used to clone a changefeed, the new changefeed
+// uses the same configuration as the original one except the fields below.
+type ChangefeedCloneConfig struct {
+	// ID is the ID of the new changefeed
+	ID string `json:"changefeed_id"`
+	// StartTS is the start ts of the new changefeed, the current ts is used if it's 0
+	StartTS uint64 `json:"start_ts"`
+	// SinkURI is the sink uri of the new changefeed, the original one is used if it's empty
+	SinkURI string `json:"sink_uri"`
+	// timezone used when checking sink uri
+	TimeZone              string `json:"timezone" default:"system"`
+	IgnoreIneligibleTable bool   `json:"ignore_ineligible_table" default:"false"`
+	// Paused indicates whether the new changefeed is created in stopped state
+	Paused bool `json:"paused" default:"false"`
+}
+
+// ChangefeedRewindConfig is the config used to rewind the checkpoint of a
+// stopped changefeed, the changefeed is resumed from the new checkpoint and
+// re-emits all changes after it.
+type ChangefeedRewindConfig struct {
+	// CheckpointTs is the new checkpoint, it must be less than the current one
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	// UseRedo indicates whether to rewind the redo logs together
+	UseRedo bool `json:"use_redo" default:"false"`
+}
+
+// ChangefeedApproveTablesConfig is the config used to approve tables to be
+// replicated by a changefeed whose replicated tables are frozen.
+type ChangefeedApproveTablesConfig struct {
+	// Tables are the approved tables in the form of "schema.table"
+	Tables []string `json:"tables"`
 }
 
 // ProcessorCommonInfo holds the common info of a processor
@@ -160,6 +333,45 @@ type ProcessorDetail struct {
 	Error *RunningError `json:"error"`
 }
 
+// TablePipelineDiagnostics holds the runtime state of a table replicated by a
+// processor, collected from the nodes of its pipeline
+type TablePipelineDiagnostics struct {
+	ChangefeedID string    `json:"changefeed_id"`
+	CaptureID    CaptureID `json:"capture_id"`
+	TableID      TableID   `json:"table_id"`
+	TableName    string    `json:"table_name"`
+	Status       string    `json:"status"`
+	ResolvedTs   uint64    `json:"resolved_ts"`
+	CheckpointTs uint64    `json:"checkpoint_ts"`
+	// Queues are the events pending in the nodes of the pipeline
+	Queues TablePipelineQueues `json:"queues"`
+	// SorterDiskBytes is the size of the files written by the sorter, it's
+	// only reported by the unified sorter
+	SorterDiskBytes int64 `json:"sorter_disk_bytes"`
+	// FlowControllerMemoryBytes is the memory consumed by the events of the
+	// table in the flow controller
+	FlowControllerMemoryBytes uint64 `json:"flow_controller_memory_bytes"`
+	// LastEventTs is the commit ts of the last row change event received by
+	// the sorter
+	LastEventTs uint64 `json:"last_event_ts"`
+	// SinkFlushLagMs is how far the checkpoint of the sink lags behind the
+	// resolved ts received by the sink node in milliseconds
+	SinkFlushLagMs int64 `json:"sink_flush_lag_ms"`
+}
+
+// TablePipelineQueues holds the lengths of the queues of a table pipeline
+type TablePipelineQueues struct {
+	// PullerOutputEvents and PullerOutputBytes are the events pulled by the
+	// puller but not accepted by the sorter yet, only in table actor mode
+	PullerOutputEvents int   `json:"puller_output_events"`
+	PullerOutputBytes  int64 `json:"puller_output_bytes"`
+	// SorterEvents are the events received but not output by the sorter
+	SorterEvents int64 `json:"sorter_events"`
+	// SinkBufferedRows are the rows buffered by the sink node but not
+	// emitted to the sink yet
+	SinkBufferedRows int64 `json:"sink_buffered_rows"`
+}
+
 // CaptureTaskStatus holds TaskStatus of a capture
 type CaptureTaskStatus struct {
 	CaptureID string `json:"capture_id"`
@@ -174,3 +386,27 @@ type Capture struct {
 	IsOwner       bool   `json:"is_owner"`
 	AdvertiseAddr string `json:"address"`
 }
+
+// HotspotReport is the upstream write hotspot report of a capture, derived
+// from the row change events received by the pullers of the capture.
+type HotspotReport struct {
+	// GeneratedAt is the time the report is generated at
+	GeneratedAt JSONTime `json:"generated_at"`
+	// Tables are the tables sorted by the event rate in the descending order
+	Tables []TableHotspot `json:"tables"`
+}
+
+// TableHotspot holds the event rate of a table and its hottest regions
+type TableHotspot struct {
+	ChangefeedID    string          `json:"changefeed_id"`
+	TableID         int64           `json:"table_id"`
+	TableName       string          `json:"table_name"`
+	EventsPerSecond float64         `json:"events_per_second"`
+	Regions         []RegionHotspot `json:"regions"`
+}
+
+// RegionHotspot holds the event rate of a region
+type RegionHotspot struct {
+	RegionID        uint64  `json:"region_id"`
+	EventsPerSecond float64 `json:"events_per_second"`
+}
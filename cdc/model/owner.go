@@ -30,6 +30,10 @@ type AdminJobType int
 // AdminJobOption records addition options of an admin job
 type AdminJobOption struct {
 	ForceRemove bool
+	// FinalBarrier indicates that a running changefeed is removed only after
+	// all the events before its resolved ts are flushed to the downstream and
+	// an end marker is written, so that consumers know where the stream ends.
+	FinalBarrier bool
 }
 
 // AdminJob holds an admin job
@@ -38,6 +42,13 @@ type AdminJob struct {
 	Type  AdminJobType
 	Opts  *AdminJobOption
 	Error *RunningError
+	// OverwriteCheckpointTs rewinds the checkpoint of the changefeed
+	// to it when the changefeed is resumed, zero means not to rewind.
+	OverwriteCheckpointTs uint64
+	// RewindRedo indicates whether to rewind the redo log meta to
+	// OverwriteCheckpointTs as well, it is done by the owner once the job
+	// is accepted.
+	RewindRedo bool
 }
 
 // All AdminJob types
@@ -85,6 +96,25 @@ type TaskPosition struct {
 	Count uint64 `json:"count"`
 	// Error when error happens
 	Error *RunningError `json:"error"`
+	// Redo is the health of the redo logs written by the processor, it's nil
+	// if the redo log is disabled.
+	Redo *RedoHealth `json:"redo,omitempty"`
+}
+
+// RedoHealth is the health of the redo logs written by a processor
+type RedoHealth struct {
+	Healthy bool `json:"healthy"`
+	// Message tells why the redo logs are unhealthy
+	Message string `json:"message,omitempty"`
+}
+
+// RedoTableBacklog is the redo logs of a table not flushed yet
+type RedoTableBacklog struct {
+	// ResolvedTs is the resolved ts of the table sent to the redo log.
+	ResolvedTs uint64 `json:"resolved_ts"`
+	// FlushedTs is the resolved ts of the table whose logs are flushed to
+	// the storage, the logs of the table before it are durable.
+	FlushedTs uint64 `json:"flushed_ts"`
 }
 
 // Marshal returns the json marshal format of a TaskStatus
@@ -120,6 +150,10 @@ func (tp *TaskPosition) Clone() *TaskPosition {
 			Message: tp.Error.Message,
 		}
 	}
+	if tp.Redo != nil {
+		redo := *tp.Redo
+		ret.Redo = &redo
+	}
 	return ret
 }
 
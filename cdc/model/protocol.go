@@ -35,6 +35,10 @@ type DispatchTableMessage struct {
 	Epoch    ProcessorEpoch `json:"epoch"`
 	ID       TableID        `json:"id"`
 	IsDelete bool           `json:"is-delete"`
+	// IsPrepare indicates that the processor should only start the pipeline
+	// of the table in advance without replicating it, so that the table can
+	// be moved to the processor without waiting for the pipeline to catch up.
+	IsPrepare bool `json:"is-prepare,omitempty"`
 }
 
 // DispatchTableResponseTopic returns a message topic for the result of
@@ -47,6 +51,8 @@ func DispatchTableResponseTopic(changefeedID ChangeFeedID) p2p.Topic {
 type DispatchTableResponseMessage struct {
 	ID    TableID        `json:"id"`
 	Epoch ProcessorEpoch `json:"epoch"`
+	// IsPrepare indicates that the table is prepared rather than added or removed.
+	IsPrepare bool `json:"is-prepare,omitempty"`
 }
 
 // AnnounceTopic returns a message topic for announcing an ownership change.
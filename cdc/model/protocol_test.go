@@ -72,6 +72,16 @@ func TestMarshalDispatchTableMessage(t *testing.T) {
 	bytes, err := json.Marshal(msg)
 	require.NoError(t, err)
 	require.Equal(t, `{"owner-rev":1,"epoch":"test-epoch","id":1,"is-delete":true}`, string(bytes))
+
+	msg = &DispatchTableMessage{
+		OwnerRev:  1,
+		Epoch:     "test-epoch",
+		ID:        TableID(1),
+		IsPrepare: true,
+	}
+	bytes, err = json.Marshal(msg)
+	require.NoError(t, err)
+	require.Equal(t, `{"owner-rev":1,"epoch":"test-epoch","id":1,"is-delete":false,"is-prepare":true}`, string(bytes))
 }
 
 func TestMarshalDispatchTableResponseMessage(t *testing.T) {
@@ -82,6 +92,11 @@ func TestMarshalDispatchTableResponseMessage(t *testing.T) {
 	bytes, err := json.Marshal(msg)
 	require.NoError(t, err)
 	require.Equal(t, `{"id":1,"epoch":"test-epoch"}`, string(bytes))
+
+	msg.IsPrepare = true
+	bytes, err = json.Marshal(msg)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1,"epoch":"test-epoch","is-prepare":true}`, string(bytes))
 }
 
 func TestMarshalAnnounceMessage(t *testing.T) {
@@ -344,3 +344,20 @@ func (ti *TableInfo) IsIndexUnique(indexInfo *model.IndexInfo) bool {
 func (ti *TableInfo) Clone() *TableInfo {
 	return WrapTableInfo(ti.SchemaID, ti.TableName.Schema, ti.TableInfoVersion, ti.TableInfo.Clone())
 }
+
+// RedoSchemaSnapshot is the structures of the tables replicated by a
+// changefeed at Ts, it's written to the redo logs each time the schema of the
+// changefeed changes. The latest snapshot before the ts the redo logs are
+// applied from, together with the DDL logs after it, reconstructs the tables
+// in the downstream without the upstream.
+type RedoSchemaSnapshot struct {
+	Ts     uint64             `json:"ts"`
+	Tables []*RedoTableSchema `json:"tables"`
+}
+
+// RedoTableSchema is the structure of a table in RedoSchemaSnapshot
+type RedoTableSchema struct {
+	Schema    string           `json:"schema"`
+	Table     string           `json:"table"`
+	TableInfo *model.TableInfo `json:"table-info"`
+}
@@ -41,6 +41,9 @@ const (
 	MqMessageTypeDDL
 	// MqMessageTypeResolved is resolved type of message key
 	MqMessageTypeResolved
+	// MqMessageTypeEndOfStream is the type of the end-of-stream message of a
+	// removed changefeed
+	MqMessageTypeEndOfStream
 )
 
 // ColumnFlagType is for encapsulating the flag operations for different flags.
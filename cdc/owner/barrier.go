@@ -29,8 +29,28 @@ const (
 	syncPointBarrier
 	// finishBarrier denotes a barrier for changefeed finished.
 	finishBarrier
+	// consistencyReportBarrier denotes a barrier for an on-demand consistency report.
+	consistencyReportBarrier
+	// finalBarrier denotes a barrier for changefeed removed with an end marker.
+	finalBarrier
 )
 
+func (t barrierType) String() string {
+	switch t {
+	case ddlJobBarrier:
+		return "ddl"
+	case syncPointBarrier:
+		return "sync-point"
+	case finishBarrier:
+		return "finish"
+	case consistencyReportBarrier:
+		return "consistency-report"
+	case finalBarrier:
+		return "final"
+	}
+	return "unknown"
+}
+
 // barriers stores some barrierType and barrierTs, and can calculate the min barrierTs
 // barriers is NOT-THREAD-SAFE
 type barriers struct {
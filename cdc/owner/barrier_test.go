@@ -85,3 +85,11 @@ func TestBarrierRandom(t *testing.T) {
 		require.Equal(t, expectedBarriers[tp], expectedMinTs)
 	}
 }
+
+func TestBarrierTypeString(t *testing.T) {
+	require.Equal(t, "ddl", ddlJobBarrier.String())
+	require.Equal(t, "sync-point", syncPointBarrier.String())
+	require.Equal(t, "finish", finishBarrier.String())
+	require.Equal(t, "consistency-report", consistencyReportBarrier.String())
+	require.Equal(t, "unknown", barrierType(100).String())
+}
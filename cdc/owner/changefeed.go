@@ -15,6 +15,7 @@ package owner
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -23,11 +24,14 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/format"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	schedulerv2 "github.com/pingcap/tiflow/cdc/scheduler"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
@@ -47,6 +51,12 @@ type changefeed struct {
 	feedStateManager *feedStateManager
 	gcManager        gc.Manager
 	redoManager      redo.LogManager
+	// redoSchemaWriter writes the schema snapshots to the redo logs in the
+	// background, it's nil if the redo log is disabled. redoSchemaTs is the ts
+	// of the last schema snapshot passed to it, a new snapshot is passed once
+	// the schema changes.
+	redoSchemaWriter *redoSchemaWriter
+	redoSchemaTs     model.Ts
 
 	schema      *schemaWrap4Owner
 	sink        DDLSink
@@ -64,6 +74,35 @@ type changefeed struct {
 	// The ones that have not been executed yet do not have.
 	currentTableNames []model.TableName
 
+	// minBarrierTp and minBarrierTs are the minimal barrier of the last tick,
+	// they are used to diagnose why the checkpoint doesn't advance.
+	minBarrierTp barrierType
+	minBarrierTs model.Ts
+
+	consistencyReporter *consistencyReporter
+	// finalBarrierTs is the ts of the final barrier, it's 0 if the changefeed
+	// is not requested to be removed with a final barrier.
+	finalBarrierTs model.Ts
+	// schemaDriftDetector is nil if the schema drift detection is disabled.
+	schemaDriftDetector *schemaDriftDetector
+	// healthChecker is nil if the health check is disabled.
+	healthChecker *healthChecker
+	// dataContractChecker is nil if no data contract is declared.
+	dataContractChecker *dataContractChecker
+	// ddlLimiter is shared by all changefeeds of the owner, it's set by the
+	// owner once the changefeed is created.
+	ddlLimiter *ddlConcurrencyLimiter
+	// ddlSlot is the downstream the changefeed holds a DDL slot of, it's
+	// empty if the changefeed holds no slot.
+	ddlSlot string
+	// ddlThrottled is true if the DDL being executed is throttled by ddlLimiter,
+	// it's used to log once for a throttled DDL.
+	ddlThrottled bool
+	// alignedCheckpointTs is the minimal checkpoint ts of the watermark group
+	// of the changefeed, it's set by the owner before every tick, and it's 0 if
+	// the watermark of the changefeed is not aligned.
+	alignedCheckpointTs model.Ts
+
 	errCh chan error
 	// cancel the running goroutine start by `DDLPuller`
 	cancel context.CancelFunc
@@ -74,6 +113,7 @@ type changefeed struct {
 	wg sync.WaitGroup
 
 	metricsChangefeedBarrierTsGauge       prometheus.Gauge
+	metricsChangefeedBarrierTypeGauge     prometheus.Gauge
 	metricsChangefeedCheckpointTsGauge    prometheus.Gauge
 	metricsChangefeedCheckpointTsLagGauge prometheus.Gauge
 	metricsChangefeedResolvedTsGauge      prometheus.Gauge
@@ -94,6 +134,8 @@ func newChangefeed(id model.ChangeFeedID, gcManager gc.Manager) *changefeed {
 		feedStateManager: newFeedStateManager(),
 		gcManager:        gcManager,
 
+		consistencyReporter: newConsistencyReporter(id),
+
 		errCh:  make(chan error, defaultErrChSize),
 		cancel: func() {},
 
@@ -168,6 +210,16 @@ func (c *changefeed) tick(ctx cdcContext.Context, state *orchestrator.Changefeed
 	c.state = state
 	c.feedStateManager.Tick(state)
 
+	// The redo log meta is rewound only after the rewind job is accepted, and
+	// before the changefeed starts again from the rewound checkpoint. It is
+	// retried in the next tick if it fails.
+	if ts := c.feedStateManager.pendingRedoRewind(); ts != 0 {
+		if err := redo.RewindMeta(ctx, c.state.Info.Config.Consistent, c.id, ts); err != nil {
+			return errors.Trace(err)
+		}
+		c.feedStateManager.redoRewound()
+	}
+
 	checkpointTs := c.state.Info.GetCheckpointTs(c.state.Status)
 	// check stale checkPointTs must be called before `feedStateManager.ShouldRunning()`
 	// to ensure an error or stopped changefeed also be checked
@@ -203,12 +255,32 @@ func (c *changefeed) tick(ctx cdcContext.Context, state *orchestrator.Changefeed
 			zap.Any("tables", c.currentTableNames),
 		)
 	}
-	c.sink.emitCheckpointTs(checkpointTs, c.currentTableNames)
+	if c.feedStateManager.finalBarrierRequested() && c.finalBarrierTs == 0 {
+		// the resolved ts may have been sent to processors, the barrier can't be less than it.
+		c.finalBarrierTs = c.state.Status.ResolvedTs
+		c.barriers.Update(finalBarrier, c.finalBarrierTs)
+		log.Info("final barrier is set", zap.String("changefeed", c.id),
+			zap.Uint64("finalBarrierTs", c.finalBarrierTs))
+	}
+	emittedTs := checkpointTs
+	if c.alignedCheckpointTs != 0 && c.alignedCheckpointTs < emittedTs {
+		emittedTs = c.alignedCheckpointTs
+	}
+	c.sink.emitCheckpointTs(emittedTs, c.currentTableNames)
 
 	barrierTs, err := c.handleBarrier(ctx)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.schemaDriftDetector != nil {
+		c.schemaDriftDetector.tick(c.schema, c.ddlEventCache != nil)
+	}
+	c.writeRedoSchemaSnapshot()
+	if c.healthChecker != nil {
+		if pdTime, err := ctx.GlobalVars().PDClock.CurrentTime(); err == nil {
+			c.healthChecker.tick(checkpointTs, pdTime)
+		}
+	}
 	if barrierTs < checkpointTs {
 		// This condition implies that the DDL resolved-ts has not yet reached checkpointTs,
 		// which implies that it would be premature to schedule tables or to update status.
@@ -288,8 +360,7 @@ LOOP:
 		//
 		// See more gc doc.
 		ensureTTL := int64(10 * 60)
-		err := gc.EnsureChangefeedStartTsSafety(
-			ctx, ctx.GlobalVars().PDClient, c.state.ID, ensureTTL, checkpointTs)
+		err := c.gcManager.EnsureChangefeedStartTsSafety(ctx, c.state.ID, ensureTTL, checkpointTs)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -312,6 +383,9 @@ LOOP:
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.state.Info.Config.Filter.FreezeTables {
+		c.schema.FreezeTables(c.state.Info.FrozenTables)
+	}
 
 	cancelCtx, cancel := cdcContext.WithCancel(ctx)
 	c.cancel = cancel
@@ -337,9 +411,18 @@ LOOP:
 		return err
 	}
 	c.redoManager = redoManager
+	if c.redoManager.Enabled() {
+		c.redoSchemaWriter = newRedoSchemaWriter(c.id, c.redoManager, defaultRedoSchemaSnapshotInterval)
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.redoSchemaWriter.run(stdCtx)
+		}()
+	}
 
 	// init metrics
 	c.metricsChangefeedBarrierTsGauge = changefeedBarrierTsGauge.WithLabelValues(c.id)
+	c.metricsChangefeedBarrierTypeGauge = changefeedBarrierTypeGauge.WithLabelValues(c.id)
 	c.metricsChangefeedCheckpointTsGauge = changefeedCheckpointTsGauge.WithLabelValues(c.id)
 	c.metricsChangefeedCheckpointTsLagGauge = changefeedCheckpointTsLagGauge.WithLabelValues(c.id)
 	c.metricsChangefeedResolvedTsGauge = changefeedResolvedTsGauge.WithLabelValues(c.id)
@@ -352,6 +435,14 @@ LOOP:
 		return errors.Trace(err)
 	}
 
+	c.schemaDriftDetector = newSchemaDriftDetector(c.id, c.state.Info.Config.SchemaDrift, c.state.Info.SinkURI)
+	c.healthChecker = newHealthChecker(c.id, c.state.Info.Config.HealthCheck)
+	c.dataContractChecker, err = newDataContractChecker(
+		c.state.Info.Config.DataContract, c.state.Info.Config.CaseSensitive)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	c.initialized = true
 	return nil
 }
@@ -376,6 +467,23 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 	}
 	c.wg.Wait()
 	c.scheduler.Close(ctx)
+	// the barrier is set again if a new report is requested after the changefeed is initialized.
+	c.barriers.Remove(consistencyReportBarrier)
+	c.consistencyReporter.close(errors.New("changefeed is closed"))
+	c.barriers.Remove(finalBarrier)
+	c.finalBarrierTs = 0
+	c.releaseDDLSlot()
+	c.ddlThrottled = false
+	c.redoSchemaWriter = nil
+	c.redoSchemaTs = 0
+	if c.schemaDriftDetector != nil {
+		c.schemaDriftDetector.close()
+		c.schemaDriftDetector = nil
+	}
+	if c.healthChecker != nil {
+		c.healthChecker.close()
+		c.healthChecker = nil
+	}
 
 	changefeedCheckpointTsGauge.DeleteLabelValues(c.id)
 	changefeedCheckpointTsLagGauge.DeleteLabelValues(c.id)
@@ -392,10 +500,28 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 
 	changefeedBarrierTsGauge.DeleteLabelValues(c.id)
 	c.metricsChangefeedBarrierTsGauge = nil
+	changefeedBarrierTypeGauge.DeleteLabelValues(c.id)
+	c.metricsChangefeedBarrierTypeGauge = nil
+
+	changefeedDroppedDDLClauseCounter.DeleteLabelValues(c.id)
 
 	c.initialized = false
 }
 
+// writeRedoSchemaSnapshot passes the schema snapshot to the redo schema writer
+// if the schema is changed by a DDL since the last snapshot, or the changefeed
+// is initialized. The snapshots are written in the background and rate
+// limited, so the latest snapshot before a ts may be older than the schema at
+// it, the redo logs are applied from it and the DDL logs after it.
+func (c *changefeed) writeRedoSchemaSnapshot() {
+	if c.redoSchemaWriter == nil || c.schema.ddlHandledTs == c.redoSchemaTs {
+		return
+	}
+	snap := c.schema.BuildSchemaSnapshot()
+	c.redoSchemaWriter.update(snap)
+	c.redoSchemaTs = snap.Ts
+}
+
 // redoManagerCleanup cleanups redo logs if changefeed is removed and redo log is enabled
 func (c *changefeed) redoManagerCleanup(ctx context.Context) {
 	if c.isRemoved {
@@ -486,8 +612,10 @@ func (c *changefeed) preflightCheck(captures map[model.CaptureID]*model.CaptureI
 
 func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 	barrierTp, barrierTs := c.barriers.Min()
+	c.minBarrierTp, c.minBarrierTs = barrierTp, barrierTs
 	phyBarrierTs := oracle.ExtractPhysical(barrierTs)
 	c.metricsChangefeedBarrierTsGauge.Set(float64(phyBarrierTs))
+	c.metricsChangefeedBarrierTypeGauge.Set(float64(barrierTp))
 	blocked := (barrierTs == c.state.Status.CheckpointTs) && (barrierTs == c.state.Status.ResolvedTs)
 	switch barrierTp {
 	case ddlJobBarrier:
@@ -502,13 +630,18 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 		if !blocked {
 			return barrierTs, nil
 		}
+		if !c.acquireDDLSlot(ddlJob) {
+			return barrierTs, nil
+		}
 		done, err := c.asyncExecDDL(ctx, ddlJob)
 		if err != nil {
+			c.releaseDDLSlot()
 			return 0, errors.Trace(err)
 		}
 		if !done {
 			return barrierTs, nil
 		}
+		c.releaseDDLSlot()
 		c.ddlPuller.PopFrontDDL()
 		newDDLResolvedTs, _ := c.ddlPuller.FrontDDL()
 		c.barriers.Update(ddlJobBarrier, newDDLResolvedTs)
@@ -528,12 +661,67 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 			return barrierTs, nil
 		}
 		c.feedStateManager.MarkFinished()
+
+	case consistencyReportBarrier:
+		if !blocked {
+			return barrierTs, nil
+		}
+		// a failed report doesn't affect the replication.
+		c.barriers.Remove(consistencyReportBarrier)
+		if err := c.sink.emitSyncPoint(ctx, barrierTs); err != nil {
+			c.consistencyReporter.fail(err)
+			return barrierTs, nil
+		}
+		c.consistencyReporter.startChecksum(ctx, c.sink, c.schema.AllTableNames())
+
+	case finalBarrier:
+		if !blocked {
+			return barrierTs, nil
+		}
+		// all the events before the barrier are flushed to the downstream,
+		// the changefeed is removed once the end marker is written.
+		done, err := c.sink.emitEndMarker(ctx, barrierTs)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if done {
+			c.feedStateManager.finalBarrierReached()
+		}
 	default:
 		log.Panic("Unknown barrier type", zap.Int("barrierType", int(barrierTp)))
 	}
 	return barrierTs, nil
 }
 
+// acquireDDLSlot returns whether the changefeed can execute the DDL now, it
+// may be throttled because too many DDLs are executed on the downstream.
+func (c *changefeed) acquireDDLSlot(job *timodel.Job) bool {
+	downstream := downstreamOf(c.state.Info.SinkURI)
+	if downstream == "" {
+		return true
+	}
+	if !c.ddlLimiter.acquire(downstream, c.id) {
+		if !c.ddlThrottled {
+			log.Info("DDL is throttled because too many DDLs are executed on the downstream",
+				zap.String("changefeed", c.id), zap.String("downstream", downstream),
+				zap.String("query", job.Query))
+			c.ddlThrottled = true
+		}
+		return false
+	}
+	c.ddlSlot = downstream
+	c.ddlThrottled = false
+	return true
+}
+
+// releaseDDLSlot releases the DDL slot held by the changefeed.
+func (c *changefeed) releaseDDLSlot() {
+	if c.ddlSlot != "" {
+		c.ddlLimiter.release(c.ddlSlot, c.id)
+		c.ddlSlot = ""
+	}
+}
+
 func (c *changefeed) asyncExecDDL(ctx cdcContext.Context, job *timodel.Job) (done bool, err error) {
 	if job.BinlogInfo == nil {
 		log.Warn("ignore the invalid DDL job", zap.String("changefeed", c.id),
@@ -557,18 +745,78 @@ func (c *changefeed) asyncExecDDL(ctx cdcContext.Context, job *timodel.Job) (don
 		// we need to make sure we receive the ddl before we start or stop broadcasting checkpoint ts.
 		// So let's remember the name of the table before processing and cache the DDL.
 		c.currentTableNames = c.schema.AllTableNames()
+		var (
+			contracts        []*tableContract
+			violationsBefore map[string]struct{}
+		)
+		if c.dataContractChecker != nil {
+			var ready bool
+			contracts, ready, err = c.dataContractChecker.contracts(ctx)
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			if !ready {
+				// the DDL is executed once the contracts are fetched from
+				// the registry.
+				return false, nil
+			}
+			violationsBefore = contractViolations(contracts, c.schema, c.currentTableNames)
+		}
 		err = c.schema.HandleDDL(job)
 		if err != nil {
 			return false, errors.Trace(err)
 		}
+		if isRenameDDL(job) && c.state.Info.Config.Filter.FreezeTables {
+			c.updateFrozenTableNames()
+		}
+		if c.dataContractChecker != nil {
+			// The tables dropped or renamed by the DDL are only in the table
+			// names before it, and the tables created by it are only after it.
+			tables := append(c.schema.AllTableNames(), c.currentTableNames...)
+			violations := newViolations(violationsBefore,
+				contractViolations(contracts, c.schema, tables))
+			if len(violations) > 0 {
+				log.Error("DDL breaks the data contracts of the downstream",
+					zap.String("changefeed", c.id), zap.String("query", job.Query),
+					zap.Strings("violations", violations))
+				return false, cerror.ErrDataContractViolation.GenWithStackByArgs(
+					job.Query, strings.Join(violations, "; "))
+			}
+		}
+		if c.schemaDriftDetector != nil {
+			c.schemaDriftDetector.onDDLApplied()
+		}
 		ddlEvent.Query, err = addSpecialComment(ddlEvent.Query)
 		if err != nil {
 			log.Error("add special comment fail", zap.String("changefeed", c.id),
 				zap.String("Query", ddlEvent.Query), zap.Error(err))
 			return false, errors.Trace(err)
 		}
+		sinkConfig := c.state.Info.Config.Sink
+		if sinkConfig != nil && sinkConfig.DDLCompatibility == config.DDLCompatibilityMySQL {
+			query, dropped, err := rewriteDDLForMySQL(ddlEvent.Query)
+			if err != nil {
+				log.Error("rewrite DDL for MySQL fail", zap.String("changefeed", c.id),
+					zap.String("Query", ddlEvent.Query), zap.Error(err))
+				return false, errors.Trace(err)
+			}
+			if len(dropped) > 0 {
+				log.Warn("drop the clauses of DDL which MySQL doesn't support",
+					zap.String("changefeed", c.id), zap.String("query", ddlEvent.Query),
+					zap.String("rewrittenQuery", query), zap.Strings("droppedClauses", dropped))
+				changefeedDroppedDDLClauseCounter.WithLabelValues(c.id).Add(float64(len(dropped)))
+			}
+			ddlEvent.Query = query
+		}
 
 		c.ddlEventCache = ddlEvent
+		if ddlEvent.Query == "" {
+			log.Warn("ignore the DDL job because MySQL can't execute it",
+				zap.String("changefeed", c.id), zap.Reflect("job", job))
+			c.ddlEventCache = nil
+			c.currentTableNames = nil
+			return true, nil
+		}
 		if c.redoManager.Enabled() {
 			err = c.redoManager.EmitDDLEvent(ctx, ddlEvent)
 			if err != nil {
@@ -581,6 +829,16 @@ func (c *changefeed) asyncExecDDL(ctx cdcContext.Context, job *timodel.Job) (don
 			zap.String("changefeed", c.id), zap.Reflect("job", job))
 		return true, nil
 	}
+	// a renamed table may be an approved one, or be renamed to the name of
+	// one, so the rename DDLs are never skipped.
+	if !isRenameDDL(job) && job.BinlogInfo.TableInfo != nil &&
+		c.schema.IsFrozenOutTable(job.BinlogInfo.TableInfo.ID) {
+		log.Info("ignore the DDL job of table not approved by the frozen changefeed",
+			zap.String("changefeed", c.id), zap.Reflect("job", job))
+		c.ddlEventCache = nil
+		c.currentTableNames = nil
+		return true, nil
+	}
 	done, err = c.sink.emitDDLEvent(ctx, c.ddlEventCache)
 	if err != nil {
 		return false, err
@@ -594,6 +852,35 @@ func (c *changefeed) asyncExecDDL(ctx cdcContext.Context, job *timodel.Job) (don
 	return done, nil
 }
 
+func isRenameDDL(job *timodel.Job) bool {
+	return job.Type == timodel.ActionRenameTable || job.Type == timodel.ActionRenameTables
+}
+
+// updateFrozenTableNames persists the current names of the frozen tables,
+// so that the renamed tables are still approved after the changefeed restarts.
+func (c *changefeed) updateFrozenTableNames() {
+	names := c.schema.FrozenTableNames()
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		if len(info.FrozenTables) == len(names) {
+			changed := false
+			for i := range names {
+				if info.FrozenTables[i] != names[i] {
+					changed = true
+					break
+				}
+			}
+			if !changed {
+				return info, false, nil
+			}
+		}
+		info.FrozenTables = names
+		return info, true, nil
+	})
+}
+
 func (c *changefeed) updateMetrics(currentTs int64, checkpointTs, resolvedTs model.Ts) {
 	phyCkpTs := oracle.ExtractPhysical(checkpointTs)
 	c.metricsChangefeedCheckpointTsGauge.Set(float64(phyCkpTs))
@@ -633,6 +920,74 @@ func (c *changefeed) Close(ctx cdcContext.Context) {
 	changefeedCloseDuration.Observe(costTime.Seconds())
 }
 
+// barrier returns the minimal barrier of the changefeed, nil if the changefeed
+// is not initialized.
+func (c *changefeed) barrier() *model.ChangeFeedBarrier {
+	if !c.initialized {
+		return nil
+	}
+	return &model.ChangeFeedBarrier{
+		Type: c.minBarrierTp.String(),
+		Ts:   c.minBarrierTs,
+	}
+}
+
+// tableDiagnoses returns the diagnoses of the tables which are not running or
+// keep bouncing, the errors reported by the processors are attached to them.
+func (c *changefeed) tableDiagnoses() []*model.TableDiagnosis {
+	if !c.initialized || c.scheduler == nil {
+		return nil
+	}
+	diagnoses := c.scheduler.TableDiagnoses()
+	for _, diagnosis := range diagnoses {
+		if diagnosis.CaptureID == "" {
+			continue
+		}
+		position, ok := c.state.TaskPositions[diagnosis.CaptureID]
+		if !ok || position.Error == nil {
+			continue
+		}
+		diagnosis.Reasons = append(diagnosis.Reasons, fmt.Sprintf(
+			"the processor on capture %s reported error %s: %s",
+			diagnosis.CaptureID, position.Error.Code, position.Error.Message))
+	}
+	return diagnoses
+}
+
+// requestConsistencyReport requests a consistency report as of now, the
+// report in progress is returned if there is one.
+func (c *changefeed) requestConsistencyReport(ctx cdcContext.Context) (*model.ConsistencyReport, error) {
+	if !c.initialized {
+		return nil, cerror.ErrConsistencyReportRefused.GenWithStackByArgs(c.id, "changefeed is not running")
+	}
+	if !sink.IsSyncpointSupported(c.state.Info.SinkURI) {
+		return nil, cerror.ErrConsistencyReportRefused.GenWithStackByArgs(c.id, "only mysql compatible sinks are supported")
+	}
+	if c.consistencyReporter.inProgress() {
+		return c.consistencyReporter.get(), nil
+	}
+
+	now, err := ctx.GlobalVars().PDClock.CurrentTime()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// the resolved ts may have been sent to processors, the barrier can't be less than it.
+	ts := oracle.GoTimeToTS(now)
+	if c.state.Status != nil && ts < c.state.Status.ResolvedTs {
+		ts = c.state.Status.ResolvedTs
+	}
+	c.barriers.Update(consistencyReportBarrier, ts)
+	return c.consistencyReporter.request(ts), nil
+}
+
+// health returns the health of the changefeed judged by the health check.
+func (c *changefeed) health() *model.ChangefeedHealth {
+	if c.healthChecker == nil {
+		return &model.ChangefeedHealth{Message: "changefeed is not running"}
+	}
+	return c.healthChecker.get()
+}
+
 // GetInfoProvider returns an InfoProvider if one is available.
 func (c *changefeed) GetInfoProvider() schedulerv2.InfoProvider {
 	if provider, ok := c.scheduler.(schedulerv2.InfoProvider); ok {
@@ -650,6 +1005,11 @@ func addSpecialComment(ddlQuery string) (string, error) {
 	if len(stms) != 1 {
 		log.Panic("invalid ddlQuery statement size", zap.String("ddlQuery", ddlQuery))
 	}
+	return restoreDDL(stms[0])
+}
+
+// restoreDDL restores the DDL statement with TiDB features translated to comment.
+func restoreDDL(stmt ast.StmtNode) (string, error) {
 	var sb strings.Builder
 	// translate TiDB feature to special comment
 	restoreFlags := format.RestoreTiDBSpecialComment
@@ -661,7 +1021,7 @@ func addSpecialComment(ddlQuery string) (string, error) {
 	restoreFlags |= format.RestoreStringSingleQuotes
 	// remove placement rule
 	restoreFlags |= format.SkipPlacementRuleForRestore
-	if err = stms[0].Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+	if err := stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
 		return "", errors.Trace(err)
 	}
 	return sb.String(), nil
@@ -76,6 +76,7 @@ type mockDDLSink struct {
 	}
 	syncPoint    model.Ts
 	syncPointHis []model.Ts
+	endMarker    model.Ts
 
 	wg sync.WaitGroup
 }
@@ -103,6 +104,21 @@ func (m *mockDDLSink) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64)
 	return nil
 }
 
+func (m *mockDDLSink) emitEndMarker(ctx cdcContext.Context, checkpointTs uint64) (bool, error) {
+	m.endMarker = checkpointTs
+	return true, nil
+}
+
+func (m *mockDDLSink) checksum(
+	ctx context.Context, _ model.ChangeFeedID, checkpointTs uint64, tables []model.TableName,
+) (uint64, []*model.TableChecksum, error) {
+	checksums := make([]*model.TableChecksum, 0, len(tables))
+	for _, table := range tables {
+		checksums = append(checksums, &model.TableChecksum{Schema: table.Schema, Table: table.Table})
+	}
+	return checkpointTs + 1, checksums, nil
+}
+
 func (m *mockDDLSink) emitCheckpointTs(ts uint64, tableNames []model.TableName) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -384,6 +400,13 @@ func TestEmitCheckpointTs(t *testing.T) {
 	ts, names = mockDDLSink.getCheckpointTsAndTableNames()
 	require.Equal(t, ts, mockDDLPuller.resolvedTs)
 	require.Len(t, names, 0)
+
+	// the emitted checkpoint ts is held back by the watermark group.
+	cf.alignedCheckpointTs = mockDDLPuller.resolvedTs - 100
+	tickThreeTime()
+	require.Equal(t, state.Status.CheckpointTs, mockDDLPuller.resolvedTs)
+	ts, _ = mockDDLSink.getCheckpointTsAndTableNames()
+	require.Equal(t, ts, cf.alignedCheckpointTs)
 }
 
 func TestSyncPoint(t *testing.T) {
@@ -443,6 +466,52 @@ func TestFinished(t *testing.T) {
 	require.Equal(t, state.Info.State, model.StateFinished)
 }
 
+func TestRemoveChangefeedWithFinalBarrier(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, state, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+
+	// pre check
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+
+	// initialize
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+
+	mockDDLPuller := cf.ddlPuller.(*mockDDLPuller)
+	mockDDLPuller.resolvedTs += 1000
+	for i := 0; i <= 3; i++ {
+		cf.Tick(ctx, state, captures)
+		tester.MustApplyPatches()
+	}
+	finalBarrierTs := state.Status.ResolvedTs
+
+	cf.feedStateManager.PushAdminJob(&model.AdminJob{
+		CfID: cf.id,
+		Type: model.AdminRemove,
+		Opts: &model.AdminJobOption{FinalBarrier: true},
+	})
+	// the changefeed keeps running until the final barrier is reached.
+	mockDDLPuller.resolvedTs += 1000
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	require.NotNil(t, state.Info)
+	require.Equal(t, finalBarrierTs, cf.finalBarrierTs)
+
+	mockDDLSink := cf.sink.(*mockDDLSink)
+	for i := 0; i <= 10 && state.Info != nil; i++ {
+		require.LessOrEqual(t, state.Status.CheckpointTs, finalBarrierTs)
+		require.LessOrEqual(t, state.Status.ResolvedTs, finalBarrierTs)
+		cf.Tick(ctx, state, captures)
+		tester.MustApplyPatches()
+	}
+	require.Equal(t, finalBarrierTs, mockDDLSink.endMarker)
+	require.Nil(t, state.Info)
+	require.Nil(t, state.Status)
+	require.True(t, cf.isRemoved)
+}
+
 func TestRemoveChangefeed(t *testing.T) {
 	baseCtx, cancel := context.WithCancel(context.Background())
 	ctx := cdcContext.NewContext4Test(baseCtx, true)
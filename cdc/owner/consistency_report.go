@@ -0,0 +1,135 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// consistencyReporter holds the on-demand consistency report of a changefeed.
+// A report goes through the following steps:
+//  1. waiting: a consistencyReportBarrier is set at the requested ts.
+//  2. checksumming: the changefeed is blocked at the barrier, a syncpoint is
+//     emitted to record the downstream ts, and the barrier is removed. Then the
+//     downstream tables are checksummed at the downstream ts in background.
+//  3. finished or failed.
+//
+// The report is kept in the memory of the owner only.
+type consistencyReporter struct {
+	changefeedID model.ChangeFeedID
+
+	mu     sync.Mutex
+	report *model.ConsistencyReport
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newConsistencyReporter(changefeedID model.ChangeFeedID) *consistencyReporter {
+	return &consistencyReporter{
+		changefeedID: changefeedID,
+		cancel:       func() {},
+	}
+}
+
+// get returns a copy of the latest report, nil if no report is requested.
+func (r *consistencyReporter) get() *model.ConsistencyReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report == nil {
+		return nil
+	}
+	return r.report.Clone()
+}
+
+// inProgress returns whether there is a report not finished or failed.
+func (r *consistencyReporter) inProgress() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.report != nil &&
+		(r.report.State == model.ConsistencyReportWaiting ||
+			r.report.State == model.ConsistencyReportChecksumming)
+}
+
+// request starts a new report at upstreamTs.
+func (r *consistencyReporter) request(upstreamTs model.Ts) *model.ConsistencyReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report = &model.ConsistencyReport{
+		State:       model.ConsistencyReportWaiting,
+		RequestTime: model.JSONTime(time.Now()),
+		UpstreamTs:  upstreamTs,
+	}
+	log.Info("consistency report requested",
+		zap.String("changefeed", r.changefeedID), zap.Uint64("upstreamTs", upstreamTs))
+	return r.report.Clone()
+}
+
+// startChecksum checksums the tables in background, it's called after the
+// syncpoint of the report is emitted.
+func (r *consistencyReporter) startChecksum(ctx context.Context, sink DDLSink, tables []model.TableName) {
+	r.mu.Lock()
+	r.report.State = model.ConsistencyReportChecksumming
+	upstreamTs := r.report.UpstreamTs
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		downstreamTs, checksums, err := sink.checksum(ctx, r.changefeedID, upstreamTs, tables)
+		if err != nil {
+			r.fail(err)
+			return
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.report.State = model.ConsistencyReportFinished
+		r.report.DownstreamTs = downstreamTs
+		r.report.Tables = checksums
+		log.Info("consistency report finished",
+			zap.String("changefeed", r.changefeedID), zap.Any("report", r.report))
+	}()
+}
+
+// fail marks the report in progress as failed.
+func (r *consistencyReporter) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report == nil || r.report.State == model.ConsistencyReportFinished {
+		return
+	}
+	r.report.State = model.ConsistencyReportFailed
+	r.report.Error = err.Error()
+	log.Warn("consistency report failed",
+		zap.String("changefeed", r.changefeedID), zap.Error(err))
+}
+
+// close stops the background checksum, the report in progress is marked as failed.
+func (r *consistencyReporter) close(err error) {
+	r.cancel()
+	r.wg.Wait()
+	r.cancel = func() {}
+	if r.inProgress() {
+		r.fail(err)
+	}
+}
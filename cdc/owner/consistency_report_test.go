@@ -0,0 +1,63 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsistencyReporter(t *testing.T) {
+	t.Parallel()
+
+	r := newConsistencyReporter("test-changefeed")
+	require.Nil(t, r.get())
+	require.False(t, r.inProgress())
+
+	report := r.request(10)
+	require.Equal(t, model.ConsistencyReportWaiting, report.State)
+	require.Equal(t, uint64(10), report.UpstreamTs)
+	require.True(t, r.inProgress())
+
+	tables := []model.TableName{{Schema: "test", Table: "t1"}, {Schema: "test", Table: "t2"}}
+	r.startChecksum(context.Background(), &mockDDLSink{}, tables)
+	require.Eventually(t, func() bool {
+		return !r.inProgress()
+	}, 5*time.Second, 10*time.Millisecond)
+	report = r.get()
+	require.Equal(t, model.ConsistencyReportFinished, report.State)
+	require.Equal(t, uint64(11), report.DownstreamTs)
+	require.Len(t, report.Tables, 2)
+
+	// a finished report is not failed when the changefeed is closed.
+	r.close(errors.New("changefeed is closed"))
+	require.Equal(t, model.ConsistencyReportFinished, r.get().State)
+}
+
+func TestConsistencyReporterClose(t *testing.T) {
+	t.Parallel()
+
+	r := newConsistencyReporter("test-changefeed")
+	r.request(10)
+	r.close(errors.New("changefeed is closed"))
+	report := r.get()
+	require.Equal(t, model.ConsistencyReportFailed, report.State)
+	require.Equal(t, "changefeed is closed", report.Error)
+	require.False(t, r.inProgress())
+}
@@ -0,0 +1,304 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/types"
+	filterV2 "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"go.uber.org/zap"
+)
+
+// dataContractRegistryTimeout is the timeout of fetching the contracts from
+// an external schema registry.
+const dataContractRegistryTimeout = 10 * time.Second
+
+// dataContractRefreshInterval is the interval of refreshing the contracts
+// served by an external schema registry.
+const dataContractRefreshInterval = time.Minute
+
+// dataContractRegistry serves the data contracts of the downstream, it's the
+// integration point of external schema registries.
+type dataContractRegistry interface {
+	Contracts(ctx context.Context) ([]*config.TableContract, error)
+}
+
+// dataContractRegistryResponse is the JSON document served by registries.
+type dataContractRegistryResponse struct {
+	Tables []*config.TableContract `json:"tables"`
+}
+
+func decodeDataContracts(r io.Reader) ([]*config.TableContract, error) {
+	var resp dataContractRegistryResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(
+			fmt.Sprintf("decode the contracts from registry: %s", err))
+	}
+	if err := config.ValidateTableContracts(resp.Tables); err != nil {
+		return nil, err
+	}
+	return resp.Tables, nil
+}
+
+// httpDataContractRegistry fetches the contracts from an http(s) endpoint.
+type httpDataContractRegistry struct {
+	uri    string
+	client *http.Client
+}
+
+func (r *httpDataContractRegistry) Contracts(ctx context.Context) ([]*config.TableContract, error) {
+	ctx, cancel := context.WithTimeout(ctx, dataContractRegistryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.uri, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fetch the data contracts from %s failed, status: %s", r.uri, resp.Status)
+	}
+	return decodeDataContracts(resp.Body)
+}
+
+// fileDataContractRegistry reads the contracts from a local file.
+type fileDataContractRegistry struct {
+	path string
+}
+
+func (r *fileDataContractRegistry) Contracts(ctx context.Context) ([]*config.TableContract, error) {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	return decodeDataContracts(f)
+}
+
+func newDataContractRegistry(registry string) (dataContractRegistry, error) {
+	uri, err := url.Parse(registry)
+	if err != nil {
+		return nil, cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(err.Error())
+	}
+	switch uri.Scheme {
+	case "http", "https":
+		return &httpDataContractRegistry{uri: registry, client: &http.Client{}}, nil
+	case "file":
+		return &fileDataContractRegistry{path: uri.Path}, nil
+	default:
+		return nil, cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(
+			"the scheme of registry should be one of http, https and file")
+	}
+}
+
+// tableContract is a parsed config.TableContract.
+type tableContract struct {
+	matcher filterV2.Filter
+	columns []*config.ColumnContract
+}
+
+// dataContractChecker validates the DDLs against the data contracts of the
+// downstream. A DDL breaks the contracts if any table required by them is
+// dropped or renamed, or any column required by them is dropped, renamed or
+// changed to another type. Violations existing before the DDL are not caused
+// by it and are ignored, so contracts can be added for tables which don't
+// exist yet.
+type dataContractChecker struct {
+	caseSensitive bool
+	static        []*tableContract
+	// registry is nil if no external schema registry is configured.
+	registry        dataContractRegistry
+	refreshInterval time.Duration
+
+	// The contracts served by the registry are fetched in the background and
+	// cached, so the owner is never blocked by the registry.
+	mu struct {
+		sync.Mutex
+		fetched   []*tableContract
+		fetchedAt time.Time
+		fetching  bool
+		// err is the error of fetching the contracts for the first time, it's
+		// reported once.
+		err error
+	}
+}
+
+// newDataContractChecker creates a dataContractChecker, nil is returned if no
+// contract is declared.
+func newDataContractChecker(
+	cfg *config.DataContractConfig, caseSensitive bool,
+) (*dataContractChecker, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+	c := &dataContractChecker{
+		caseSensitive:   caseSensitive,
+		refreshInterval: dataContractRefreshInterval,
+	}
+	static, err := parseTableContracts(cfg.Tables, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	c.static = static
+	if cfg.Registry != "" {
+		c.registry, err = newDataContractRegistry(cfg.Registry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parseTableContracts(
+	contracts []*config.TableContract, caseSensitive bool,
+) ([]*tableContract, error) {
+	parsed := make([]*tableContract, 0, len(contracts))
+	for _, contract := range contracts {
+		matcher, err := filter.NewTableMatcher(contract.Matcher, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, &tableContract{matcher: matcher, columns: contract.Columns})
+	}
+	return parsed, nil
+}
+
+// contracts returns the contracts declared in the config and served by the
+// registry. The contracts served by the registry are refreshed in the
+// background, false is returned if they are not fetched yet.
+func (c *dataContractChecker) contracts(ctx context.Context) ([]*tableContract, bool, error) {
+	if c.registry == nil {
+		return c.static, true, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.err != nil {
+		err := c.mu.err
+		c.mu.err = nil
+		return nil, false, err
+	}
+	if !c.mu.fetching && time.Since(c.mu.fetchedAt) >= c.refreshInterval {
+		c.mu.fetching = true
+		go c.fetch(ctx)
+	}
+	if c.mu.fetchedAt.IsZero() {
+		return nil, false, nil
+	}
+	contracts := make([]*tableContract, 0, len(c.mu.fetched)+len(c.static))
+	contracts = append(contracts, c.mu.fetched...)
+	return append(contracts, c.static...), true, nil
+}
+
+// fetch fetches the contracts from the registry. The cached ones are still
+// used if the registry fails after they are fetched.
+func (c *dataContractChecker) fetch(ctx context.Context) {
+	fetched, err := c.registry.Contracts(ctx)
+	var parsed []*tableContract
+	if err == nil {
+		parsed, err = parseTableContracts(fetched, c.caseSensitive)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.fetching = false
+	if err != nil {
+		if c.mu.fetchedAt.IsZero() {
+			c.mu.err = err
+			return
+		}
+		log.Warn("refresh the data contracts from registry failed, the cached ones are used",
+			zap.Error(err))
+		return
+	}
+	c.mu.fetched = parsed
+	c.mu.fetchedAt = time.Now()
+}
+
+// contractViolations returns the violations of the given tables in the schema
+// of the owner, each one is described as "<table>: <reason>".
+func contractViolations(
+	contracts []*tableContract, schema *schemaWrap4Owner, tables []model.TableName,
+) map[string]struct{} {
+	violations := make(map[string]struct{})
+	for _, table := range tables {
+		var (
+			matched  bool
+			required []*config.ColumnContract
+		)
+		for _, contract := range contracts {
+			if contract.matcher.MatchTable(table.Schema, table.Table) {
+				matched = true
+				required = append(required, contract.columns...)
+			}
+		}
+		if !matched {
+			continue
+		}
+		tblInfo, ok := schema.schemaSnapshot.GetTableByName(table.Schema, table.Table)
+		if !ok {
+			violations[fmt.Sprintf("%s: table doesn't exist", table)] = struct{}{}
+			continue
+		}
+		colTypes := make(map[string]string, len(tblInfo.Columns))
+		for _, col := range tblInfo.Columns {
+			if col.Hidden {
+				continue
+			}
+			colTypes[col.Name.L] = types.TypeToStr(col.Tp, col.Charset)
+		}
+		for _, col := range required {
+			tp, ok := colTypes[strings.ToLower(col.Name)]
+			if !ok {
+				violations[fmt.Sprintf("%s: column %s doesn't exist", table, col.Name)] = struct{}{}
+				continue
+			}
+			if col.Type != "" && !strings.EqualFold(col.Type, tp) {
+				violations[fmt.Sprintf("%s: column %s is %s, %s is required",
+					table, col.Name, tp, col.Type)] = struct{}{}
+			}
+		}
+	}
+	return violations
+}
+
+// newViolations returns the violations only in after, sorted.
+func newViolations(before, after map[string]struct{}) []string {
+	var violations []string
+	for v := range after {
+		if _, ok := before[v]; !ok {
+			violations = append(violations, v)
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/atomic"
+)
+
+func TestDataContractViolations(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	schema, err := newSchemaWrap4Owner(helper.Storage(), ver.Ver,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+	require.Nil(t, schema.HandleDDL(helper.DDL2Job(
+		"create table test.orders(id bigint primary key, amount int, note text, v int)")))
+
+	checker, err := newDataContractChecker(&config.DataContractConfig{}, true)
+	require.Nil(t, err)
+	require.Nil(t, checker)
+	checker, err = newDataContractChecker(&config.DataContractConfig{
+		Tables: []*config.TableContract{
+			{
+				Matcher: []string{"test.orders"},
+				Columns: []*config.ColumnContract{
+					{Name: "id", Type: "bigint"}, {Name: "Amount"}, {Name: "v", Type: "int"},
+				},
+			},
+			{Matcher: []string{"test.customers"}},
+		},
+	}, true)
+	require.Nil(t, err)
+	contracts, ready, err := checker.contracts(context.Background())
+	require.Nil(t, err)
+	require.True(t, ready)
+
+	// execDDL applies the DDL and returns the violations caused by it.
+	execDDL := func(query string) []string {
+		before := schema.AllTableNames()
+		violationsBefore := contractViolations(contracts, schema, before)
+		require.Nil(t, schema.HandleDDL(helper.DDL2Job(query)))
+		tables := append(schema.AllTableNames(), before...)
+		return newViolations(violationsBefore, contractViolations(contracts, schema, tables))
+	}
+
+	require.Empty(t, execDDL("alter table test.orders drop column note"))
+	require.Empty(t, execDDL("alter table test.orders modify amount bigint"))
+	// the contract of a table not created yet is not violated by other DDLs.
+	require.Empty(t, execDDL("create table test.t1(id int primary key)"))
+	require.Equal(t, []string{"test.orders: column v is bigint, int is required"},
+		execDDL("alter table test.orders modify v bigint"))
+	require.Equal(t, []string{"test.orders: column Amount doesn't exist"},
+		execDDL("alter table test.orders drop column amount"))
+	require.Equal(t, []string{"test.orders: table doesn't exist"},
+		execDDL("rename table test.orders to test.orders_old"))
+}
+
+func TestDataContractRegistry(t *testing.T) {
+	t.Parallel()
+
+	contracts := atomic.NewString(
+		`{"tables": [{"matcher": ["test.*"], "columns": [{"name": "id", "type": "int"}]}]}`)
+	status := atomic.NewInt32(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		fmt.Fprint(w, contracts.Load())
+	}))
+	defer server.Close()
+
+	newChecker := func(registry string, caseSensitive bool) *dataContractChecker {
+		checker, err := newDataContractChecker(&config.DataContractConfig{
+			Registry: registry,
+			Tables:   []*config.TableContract{{Matcher: []string{"test2.t"}}},
+		}, caseSensitive)
+		require.Nil(t, err)
+		return checker
+	}
+	// the contracts are fetched in the background
+	fetch := func(checker *dataContractChecker) (parsed []*tableContract, err error) {
+		require.Eventually(t, func() bool {
+			var ready bool
+			parsed, ready, err = checker.contracts(context.Background())
+			return ready || err != nil
+		}, 5*time.Second, 10*time.Millisecond)
+		return parsed, err
+	}
+
+	checker := newChecker(server.URL, false)
+	parsed, err := fetch(checker)
+	require.Nil(t, err)
+	require.Len(t, parsed, 2)
+	require.True(t, parsed[0].matcher.MatchTable("TEST", "t"))
+	require.Equal(t, []*config.ColumnContract{{Name: "id", Type: "int"}}, parsed[0].columns)
+	require.True(t, parsed[1].matcher.MatchTable("test2", "t"))
+
+	// the cached contracts are used if the registry fails after they are fetched
+	checker.refreshInterval = 0
+	status.Store(http.StatusNotFound)
+	parsed, err = fetch(checker)
+	require.Nil(t, err)
+	require.Len(t, parsed, 2)
+
+	contracts.Store(`{"tables": [{"columns": [{"name": "id"}]}]}`)
+	status.Store(http.StatusOK)
+	_, err = fetch(newChecker(server.URL, false))
+	require.True(t, cerror.ErrInvalidDataContractConfig.Equal(err))
+	status.Store(http.StatusNotFound)
+	_, err = fetch(newChecker(server.URL, false))
+	require.Regexp(t, ".*status: 404.*", err)
+
+	path := filepath.Join(t.TempDir(), "contracts.json")
+	require.Nil(t, os.WriteFile(path, []byte(`{"tables": [{"matcher": ["test.t"]}]}`), 0o644))
+	checker, err = newDataContractChecker(&config.DataContractConfig{Registry: "file://" + path}, true)
+	require.Nil(t, err)
+	parsed, err = fetch(checker)
+	require.Nil(t, err)
+	require.Len(t, parsed, 1)
+	require.True(t, parsed[0].matcher.MatchTable("test", "t"))
+	require.False(t, parsed[0].matcher.MatchTable("TEST", "t"))
+}
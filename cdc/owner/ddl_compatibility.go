@@ -0,0 +1,76 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"go.uber.org/zap"
+)
+
+// mysqlUnsupportedAlterTableSpecs are the ALTER TABLE clauses which TiDB doesn't
+// translate to special comments, and MySQL can't execute. EXCHANGE PARTITION
+// is executed by MySQL as well, so it's kept.
+var mysqlUnsupportedAlterTableSpecs = map[ast.AlterTableType]string{
+	ast.AlterTableSetTiFlashReplica:   "SET TIFLASH REPLICA",
+	ast.AlterTableCache:               "CACHE",
+	ast.AlterTableNoCache:             "NOCACHE",
+	ast.AlterTableAttributes:          "ATTRIBUTES",
+	ast.AlterTablePartitionAttributes: "PARTITION ATTRIBUTES",
+}
+
+// rewriteDDLForMySQL rewrites a DDL query restored by addSpecialComment for MySQL
+// downstreams. It returns the rewritten query and the dropped clauses, the query is
+// empty if the whole DDL can't be executed by MySQL.
+func rewriteDDLForMySQL(ddlQuery string) (string, []string, error) {
+	stmts, _, err := parser.New().ParseSQL(ddlQuery)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(stmts) != 1 {
+		log.Panic("invalid ddlQuery statement size", zap.String("ddlQuery", ddlQuery))
+	}
+
+	var dropped []string
+	switch stmt := stmts[0].(type) {
+	case *ast.AlterTableStmt:
+		specs := make([]*ast.AlterTableSpec, 0, len(stmt.Specs))
+		for _, spec := range stmt.Specs {
+			if clause, ok := mysqlUnsupportedAlterTableSpecs[spec.Tp]; ok {
+				dropped = append(dropped, clause)
+				continue
+			}
+			specs = append(specs, spec)
+		}
+		if len(specs) == 0 {
+			return "", dropped, nil
+		}
+		stmt.Specs = specs
+	case *ast.FlashBackTableStmt, *ast.RecoverTableStmt,
+		*ast.CreateSequenceStmt, *ast.AlterSequenceStmt, *ast.DropSequenceStmt,
+		*ast.CreatePlacementPolicyStmt, *ast.AlterPlacementPolicyStmt, *ast.DropPlacementPolicyStmt:
+		return "", []string{ddlQuery}, nil
+	}
+
+	if len(dropped) == 0 {
+		return ddlQuery, nil, nil
+	}
+	query, err := restoreDDL(stmts[0])
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	return query, dropped, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteDDLForMySQL(t *testing.T) {
+	testCases := []struct {
+		input   string
+		result  string
+		dropped []string
+	}{
+		{
+			input:  "ALTER TABLE `t1` ADD COLUMN `c` INT",
+			result: "ALTER TABLE `t1` ADD COLUMN `c` INT",
+		},
+		{
+			input:  "CREATE TABLE `t1` (`id` INT) /*T! SHARD_ROW_ID_BITS = 2 */",
+			result: "CREATE TABLE `t1` (`id` INT) /*T! SHARD_ROW_ID_BITS = 2 */",
+		},
+		{
+			input:  "ALTER TABLE `t1` EXCHANGE PARTITION `p0` WITH TABLE `t2`",
+			result: "ALTER TABLE `t1` EXCHANGE PARTITION `p0` WITH TABLE `t2`",
+		},
+		{
+			input:   "ALTER TABLE `t1` SET TIFLASH REPLICA 1",
+			dropped: []string{"SET TIFLASH REPLICA"},
+		},
+		{
+			input:   "ALTER TABLE `t1` CACHE",
+			dropped: []string{"CACHE"},
+		},
+		{
+			input:   "CREATE SEQUENCE `seq`",
+			dropped: []string{"CREATE SEQUENCE `seq`"},
+		},
+		{
+			input:   "FLASHBACK TABLE `t1`",
+			dropped: []string{"FLASHBACK TABLE `t1`"},
+		},
+	}
+	for _, tc := range testCases {
+		query, dropped, err := rewriteDDLForMySQL(tc.input)
+		require.Nil(t, err)
+		require.Equal(t, tc.result, query, tc.input)
+		require.Equal(t, tc.dropped, dropped, tc.input)
+	}
+
+	_, _, err := rewriteDDLForMySQL("alter table t1 add column")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/secret"
+)
+
+// ddlConcurrencyLimiter limits the number of DDLs executed concurrently by the
+// changefeeds replicating to the same downstream, so the DDL queue of the
+// downstream doesn't pile up and stall all the changefeeds. A changefeed holds
+// a slot from the time it starts executing a DDL until the DDL is done.
+//
+// It's only accessed by the owner tick, so it's not thread-safe.
+type ddlConcurrencyLimiter struct {
+	// limit is the max number of concurrent DDLs of a downstream, 0 means
+	// unlimited.
+	limit int
+	// running is the changefeeds executing DDLs of each downstream.
+	running map[string]map[model.ChangeFeedID]struct{}
+}
+
+func newDDLConcurrencyLimiter(limit int) *ddlConcurrencyLimiter {
+	return &ddlConcurrencyLimiter{
+		limit:   limit,
+		running: make(map[string]map[model.ChangeFeedID]struct{}),
+	}
+}
+
+// acquire returns whether the changefeed can execute a DDL on the downstream.
+// It's ok to acquire a slot held by the changefeed already.
+func (l *ddlConcurrencyLimiter) acquire(downstream string, changefeedID model.ChangeFeedID) bool {
+	if l == nil || l.limit <= 0 {
+		return true
+	}
+	changefeeds, ok := l.running[downstream]
+	if !ok {
+		changefeeds = make(map[model.ChangeFeedID]struct{})
+		l.running[downstream] = changefeeds
+	}
+	if _, ok := changefeeds[changefeedID]; ok {
+		return true
+	}
+	if len(changefeeds) >= l.limit {
+		return false
+	}
+	changefeeds[changefeedID] = struct{}{}
+	ddlConcurrencyRunningGauge.WithLabelValues(downstream).Set(float64(len(changefeeds)))
+	return true
+}
+
+// release releases the slot held by the changefeed, it's a no-op if the
+// changefeed doesn't hold one.
+func (l *ddlConcurrencyLimiter) release(downstream string, changefeedID model.ChangeFeedID) {
+	if l == nil || l.limit <= 0 {
+		return
+	}
+	changefeeds, ok := l.running[downstream]
+	if !ok {
+		return
+	}
+	delete(changefeeds, changefeedID)
+	if len(changefeeds) == 0 {
+		delete(l.running, downstream)
+		ddlConcurrencyRunningGauge.DeleteLabelValues(downstream)
+		return
+	}
+	ddlConcurrencyRunningGauge.WithLabelValues(downstream).Set(float64(len(changefeeds)))
+}
+
+// downstreamOf identifies the downstream of a changefeed by the scheme and the
+// host of its sink URI, the user info and the parameters are ignored, so the
+// changefeeds replicating to the same downstream with different options share
+// the limit. The sink URI has been validated, it's empty if the sink URI is
+// malformed anyway. The secret placeholders are not resolved, the downstreams
+// referenced by different secrets are regarded as different ones.
+func downstreamOf(sinkURI string) string {
+	u, err := url.Parse(secret.MaskURI(sinkURI))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
+}
@@ -50,6 +50,15 @@ type DDLSink interface {
 	// the caller of this function can call again and again until a true returned
 	emitDDLEvent(ctx cdcContext.Context, ddl *model.DDLEvent) (bool, error)
 	emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64) error
+	// checksum returns the downstream ts of the syncpoint of checkpointTs and the checksums
+	// of the tables at the downstream ts, it can be called concurrently with other methods
+	// once the syncpoint is emitted.
+	checksum(ctx context.Context, id model.ChangeFeedID, checkpointTs uint64, tables []model.TableName) (uint64, []*model.TableChecksum, error)
+	// emitEndMarker writes an end marker of the changefeed at checkpointTs to the downstream
+	// and returns true once it's written, it should be called after all the events before
+	// checkpointTs are flushed. Like emitDDLEvent, the caller can call it again and again
+	// until a true returned.
+	emitEndMarker(ctx cdcContext.Context, checkpointTs uint64) (bool, error)
 	// close the sink, cancel running goroutine.
 	close(ctx context.Context) error
 }
@@ -70,6 +79,10 @@ type ddlSinkImpl struct {
 	ddlCh chan *model.DDLEvent
 	errCh chan error
 
+	endMarkerCh         chan model.Ts
+	endMarkerSentTs     model.Ts
+	endMarkerFinishedTs model.Ts
+
 	sink sink.Sink
 	// `sinkInitHandler` can be helpful in unit testing.
 	sinkInitHandler ddlSinkInitHandler
@@ -83,6 +96,7 @@ func newDDLSink() DDLSink {
 	return &ddlSinkImpl{
 		ddlCh:           make(chan *model.DDLEvent, 1),
 		errCh:           make(chan error, defaultErrChSize),
+		endMarkerCh:     make(chan model.Ts, 1),
 		sinkInitHandler: ddlSinkInitializer,
 		cancel:          func() {},
 	}
@@ -199,11 +213,51 @@ func (s *ddlSinkImpl) run(ctx cdcContext.Context, id model.ChangeFeedID, info *m
 					zap.Any("ddl", ddl))
 				ctx.Throw(errors.Trace(err))
 				return
+			case ts := <-s.endMarkerCh:
+				if err := s.writeEndMarker(ctx, id, info, ts); err != nil {
+					log.Error("Write end marker failed",
+						zap.String("changefeed", ctx.ChangefeedVars().ID),
+						zap.Uint64("checkpointTs", ts), zap.Error(err))
+					ctx.Throw(errors.Trace(err))
+					return
+				}
+				log.Info("Write end marker succeeded",
+					zap.String("changefeed", ctx.ChangefeedVars().ID),
+					zap.Uint64("checkpointTs", ts))
+				atomic.StoreUint64(&s.endMarkerFinishedTs, ts)
 			}
 		}
 	}()
 }
 
+// writeEndMarker writes the end marker to the downstream synchronously. A row is
+// written to the end marker table for the mysql compatible sinks, and an
+// end-of-stream message is broadcast to all the partitions for the MQ sinks.
+// Nothing is written to the other sinks.
+func (s *ddlSinkImpl) writeEndMarker(
+	ctx context.Context, id model.ChangeFeedID, info *model.ChangeFeedInfo, checkpointTs model.Ts,
+) error {
+	if !sink.IsSyncpointSupported(info.SinkURI) {
+		endMarkerSink, ok := s.sink.(sink.EndMarkerSink)
+		if !ok {
+			log.Warn("the sink doesn't support the end marker, nothing is written",
+				zap.String("changefeed", id), zap.Uint64("checkpointTs", checkpointTs))
+			return nil
+		}
+		s.mu.Lock()
+		tables := s.mu.currentTableNames
+		s.mu.Unlock()
+		return endMarkerSink.EmitEndMarker(ctx, checkpointTs, tables)
+	}
+	stdCtx := util.PutChangefeedIDInCtx(ctx, id)
+	syncPointStore, err := sink.NewSyncpointStore(stdCtx, id, info.SinkURI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = syncPointStore.Close() }()
+	return syncPointStore.SinkEndMarker(stdCtx, id, checkpointTs)
+}
+
 func (s *ddlSinkImpl) emitCheckpointTs(ts uint64, tableNames []model.TableName) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -250,11 +304,51 @@ func (s *ddlSinkImpl) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64)
 	if checkpointTs == s.lastSyncPoint {
 		return nil
 	}
+	if s.syncPointStore == nil && !ctx.ChangefeedVars().Info.SyncPointEnabled {
+		// the syncpoint store is created on demand if the syncpoint is not enabled,
+		// e.g., a consistency report is requested.
+		stdCtx := util.PutChangefeedIDInCtx(ctx, ctx.ChangefeedVars().ID)
+		syncPointStore, err := sink.NewSyncpointStore(stdCtx, ctx.ChangefeedVars().ID, ctx.ChangefeedVars().Info.SinkURI)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := syncPointStore.CreateSynctable(stdCtx); err != nil {
+			_ = syncPointStore.Close()
+			return errors.Trace(err)
+		}
+		s.syncPointStore = syncPointStore
+	}
 	s.lastSyncPoint = checkpointTs
 	// TODO implement async sink syncPoint
 	return s.syncPointStore.SinkSyncpoint(ctx, ctx.ChangefeedVars().ID, checkpointTs)
 }
 
+func (s *ddlSinkImpl) emitEndMarker(ctx cdcContext.Context, checkpointTs uint64) (bool, error) {
+	if atomic.LoadUint64(&s.endMarkerFinishedTs) == checkpointTs {
+		return true, nil
+	}
+	if s.endMarkerSentTs == checkpointTs {
+		return false, nil
+	}
+	select {
+	case <-ctx.Done():
+		return false, errors.Trace(ctx.Err())
+	case s.endMarkerCh <- checkpointTs:
+		s.endMarkerSentTs = checkpointTs
+		log.Info("end marker is sent",
+			zap.String("changefeed", ctx.ChangefeedVars().ID),
+			zap.Uint64("checkpointTs", checkpointTs))
+	default:
+	}
+	return false, nil
+}
+
+func (s *ddlSinkImpl) checksum(
+	ctx context.Context, id model.ChangeFeedID, checkpointTs uint64, tables []model.TableName,
+) (uint64, []*model.TableChecksum, error) {
+	return s.syncPointStore.Checksum(ctx, id, checkpointTs, tables)
+}
+
 func (s *ddlSinkImpl) close(ctx context.Context) (err error) {
 	s.cancel()
 	if s.sink != nil {
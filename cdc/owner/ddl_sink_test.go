@@ -32,6 +32,7 @@ import (
 type mockSink struct {
 	sink.Sink
 	checkpointTs model.Ts
+	endMarker    model.Ts
 	ddl          *model.DDLEvent
 	ddlMu        sync.Mutex
 	ddlError     error
@@ -42,6 +43,11 @@ func (m *mockSink) EmitCheckpointTs(_ context.Context, ts uint64, _ []model.Tabl
 	return nil
 }
 
+func (m *mockSink) EmitEndMarker(_ context.Context, ts uint64, _ []model.TableName) error {
+	atomic.StoreUint64(&m.endMarker, ts)
+	return nil
+}
+
 func (m *mockSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	m.ddlMu.Lock()
 	defer m.ddlMu.Unlock()
@@ -126,6 +132,26 @@ func TestExecDDLEvents(t *testing.T) {
 	}
 }
 
+func TestEmitEndMarker(t *testing.T) {
+	ddlSink, mSink := newDDLSink4Test()
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx, cancel := cdcContext.WithCancel(ctx)
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx, ctx.ChangefeedVars().ID, ctx.ChangefeedVars().Info)
+
+	// a dedicated end-of-stream message is the end marker of a non-mysql sink
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitEndMarker(ctx, 100)
+		require.Nil(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, uint64(100), atomic.LoadUint64(&mSink.endMarker))
+	require.Equal(t, uint64(0), atomic.LoadUint64(&mSink.checkpointTs))
+}
+
 func TestExecDDLError(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 
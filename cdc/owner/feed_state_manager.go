@@ -58,6 +58,20 @@ type feedStateManager struct {
 	lastErrorTime   time.Time                   // time of last error for a changefeed
 	backoffInterval time.Duration               // the interval for restarting a changefeed in 'error' state
 	errBackoff      *backoff.ExponentialBackOff // an exponential backoff for restarting a changefeed
+
+	// redoRewindTs is the ts that the redo log meta must be rewound to before
+	// the changefeed runs again, zero means there is nothing to rewind. It's
+	// persisted in the changefeed info as well, so the rewind is not lost if
+	// the owner crashes before it's done.
+	redoRewindTs uint64
+	// finalBarrierPending is true if the changefeed is requested to be removed
+	// after the final barrier is reached and the end marker is written. It's
+	// persisted in the changefeed info as well, so the request is not lost if
+	// the owner is changed before the barrier is reached.
+	finalBarrierPending bool
+	// finalBarrierDone is true once the final barrier is reached, the
+	// changefeed is being removed then.
+	finalBarrierDone bool
 }
 
 // newFeedStateManager creates feedStateManager and initialize the exponential backoff
@@ -205,8 +219,27 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 				zap.String("changefeedState", string(m.state.Info.State)), zap.Any("job", job))
 			return
 		}
+		if job.Opts != nil && job.Opts.FinalBarrier && m.state.Info.State == model.StateNormal {
+			// the changefeed keeps running until the final barrier is reached,
+			// and it is removed by a plain remove job then.
+			if !m.finalBarrierRequested() {
+				log.Info("the changefeed will be removed after the final barrier is reached",
+					zap.String("changefeed", m.state.ID))
+			}
+			m.finalBarrierPending = true
+			m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+				if info == nil || info.FinalBarrier {
+					return info, false, nil
+				}
+				info.FinalBarrier = true
+				return info, true, nil
+			})
+			return
+		}
 		m.shouldBeRunning = false
 		m.shouldBeRemoved = true
+		m.finalBarrierPending = false
+		m.finalBarrierDone = false
 		jobsPending = true
 
 		// remove changefeedInfo
@@ -227,6 +260,11 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 				zap.String("changefeedState", string(m.state.Info.State)), zap.Any("job", job))
 			return
 		}
+		if job.OverwriteCheckpointTs != 0 && m.state.Info.State != model.StateStopped {
+			log.Warn("can not rewind the changefeed in the current state", zap.String("changefeed", m.state.ID),
+				zap.String("changefeedState", string(m.state.Info.State)), zap.Any("job", job))
+			return
+		}
 		m.shouldBeRunning = true
 		// when the changefeed is manually resumed, we must reset the backoff
 		m.resetErrBackoff()
@@ -244,6 +282,28 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 			}
 			return info, false, nil
 		})
+		if job.OverwriteCheckpointTs != 0 {
+			log.Info("rewind the checkpoint of the changefeed", zap.String("changefeed", m.state.ID),
+				zap.Uint64("checkpointTs", job.OverwriteCheckpointTs), zap.Bool("rewindRedo", job.RewindRedo))
+			if job.RewindRedo {
+				m.redoRewindTs = job.OverwriteCheckpointTs
+				m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+					if info == nil {
+						return nil, false, nil
+					}
+					info.RedoRewindTs = job.OverwriteCheckpointTs
+					return info, true, nil
+				})
+			}
+			m.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+				if status == nil {
+					status = &model.ChangeFeedStatus{}
+				}
+				status.CheckpointTs = job.OverwriteCheckpointTs
+				status.ResolvedTs = job.OverwriteCheckpointTs
+				return status, true, nil
+			})
+		}
 	case model.AdminFinish:
 		switch m.state.Info.State {
 		case model.StateNormal:
@@ -261,6 +321,60 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 	return
 }
 
+// pendingRedoRewind returns the ts that the redo log meta must be rewound to,
+// zero means there is nothing to rewind. The rewind requested before the owner
+// is changed is read from the changefeed info.
+func (m *feedStateManager) pendingRedoRewind() uint64 {
+	if m.redoRewindTs != 0 || m.state == nil || m.state.Info == nil {
+		return m.redoRewindTs
+	}
+	return m.state.Info.RedoRewindTs
+}
+
+// redoRewound marks the pending rewind of the redo log meta as done.
+func (m *feedStateManager) redoRewound() {
+	m.redoRewindTs = 0
+	if m.state == nil {
+		return
+	}
+	m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.RedoRewindTs == 0 {
+			return info, false, nil
+		}
+		info.RedoRewindTs = 0
+		return info, true, nil
+	})
+}
+
+// finalBarrierRequested returns whether the changefeed is waiting for the final
+// barrier to be removed. The request made before the owner is changed is read
+// from the changefeed info.
+func (m *feedStateManager) finalBarrierRequested() bool {
+	if m.finalBarrierDone {
+		return false
+	}
+	if m.finalBarrierPending || m.state == nil || m.state.Info == nil {
+		return m.finalBarrierPending
+	}
+	return m.state.Info.FinalBarrier
+}
+
+// finalBarrierReached removes the changefeed after the final barrier is reached
+// and the end marker is written.
+func (m *feedStateManager) finalBarrierReached() {
+	if m.state == nil || !m.finalBarrierRequested() {
+		return
+	}
+	// the changefeed info, including the persisted request, is deleted by the
+	// remove job.
+	m.finalBarrierPending = false
+	m.finalBarrierDone = true
+	m.pushAdminJob(&model.AdminJob{
+		CfID: m.state.ID,
+		Type: model.AdminRemove,
+	})
+}
+
 func (m *feedStateManager) popAdminJob() *model.AdminJob {
 	if len(m.adminJobQueue) == 0 {
 		return nil
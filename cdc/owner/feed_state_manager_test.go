@@ -100,6 +100,68 @@ func TestHandleJob(t *testing.T) {
 	require.False(t, state.Exist())
 }
 
+func TestResumeWithOverwriteCheckpointTs(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test()
+	state := orchestrator.NewChangefeedReactorState(ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{CheckpointTs: 200, ResolvedTs: 300}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	// a running changefeed can not be rewound
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:                  ctx.ChangefeedVars().ID,
+		Type:                  model.AdminResume,
+		OverwriteCheckpointTs: 100,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(200), state.Status.CheckpointTs)
+	require.Equal(t, uint64(0), manager.pendingRedoRewind())
+
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminStop,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, state.Info.State, model.StateStopped)
+
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:                  ctx.ChangefeedVars().ID,
+		Type:                  model.AdminResume,
+		OverwriteCheckpointTs: 100,
+		RewindRedo:            true,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(100), manager.pendingRedoRewind())
+	require.Equal(t, uint64(100), state.Info.RedoRewindTs)
+	// the rewind is not lost if the owner is changed before it's done
+	manager2 := newFeedStateManager4Test()
+	manager2.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(100), manager2.pendingRedoRewind())
+	manager.redoRewound()
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(0), manager.pendingRedoRewind())
+	require.Equal(t, uint64(0), state.Info.RedoRewindTs)
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, state.Info.State, model.StateNormal)
+	require.Equal(t, uint64(100), state.Status.CheckpointTs)
+	require.Equal(t, uint64(100), state.Status.ResolvedTs)
+	require.Equal(t, state.Status.AdminJobType, model.AdminNone)
+}
+
 func TestMarkFinished(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	manager := newFeedStateManager4Test()
@@ -128,6 +190,83 @@ func TestMarkFinished(t *testing.T) {
 	require.Equal(t, state.Status.AdminJobType, model.AdminFinish)
 }
 
+func TestRemoveWithFinalBarrier(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test()
+	state := orchestrator.NewChangefeedReactorState(ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.False(t, manager.finalBarrierRequested())
+
+	// the changefeed keeps running until the final barrier is reached
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminRemove,
+		Opts: &model.AdminJobOption{FinalBarrier: true},
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.True(t, manager.finalBarrierRequested())
+	require.NotNil(t, state.Info)
+	require.True(t, state.Info.FinalBarrier)
+
+	// the request is not lost if the owner is changed
+	manager = newFeedStateManager4Test()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.True(t, manager.finalBarrierRequested())
+
+	manager.finalBarrierReached()
+	require.False(t, manager.finalBarrierRequested())
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.True(t, manager.ShouldRemoved())
+	require.Nil(t, state.Info)
+	require.Nil(t, state.Status)
+}
+
+func TestRemoveStoppedWithFinalBarrier(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test()
+	state := orchestrator.NewChangefeedReactorState(ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}, State: model.StateStopped}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+
+	// a stopped changefeed can't reach the final barrier, it's removed immediately
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminRemove,
+		Opts: &model.AdminJobOption{FinalBarrier: true},
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.True(t, manager.ShouldRemoved())
+	require.False(t, manager.finalBarrierRequested())
+	require.Nil(t, state.Info)
+}
+
 func TestCleanUpInfos(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	manager := newFeedStateManager4Test()
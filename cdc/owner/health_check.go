@@ -0,0 +1,295 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// canaryWriter writes canary rows to the upstream.
+type canaryWriter interface {
+	// write writes a canary row and returns its commit ts.
+	write(ctx context.Context) (model.Ts, error)
+	Close() error
+}
+
+// mysqlCanaryWriter writes canary rows through the upstream TiDB, every
+// changefeed has its own row in the canary table.
+type mysqlCanaryWriter struct {
+	changefeedID model.ChangeFeedID
+	db           *sql.DB
+	schema       string
+	table        string
+	tableCreated bool
+}
+
+func newMySQLCanaryWriter(
+	changefeedID model.ChangeFeedID, dsn string, schema, table string,
+) (canaryWriter, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLConnectionError, err)
+	}
+	return &mysqlCanaryWriter{
+		changefeedID: changefeedID,
+		db:           db,
+		schema:       schema,
+		table:        table,
+	}, nil
+}
+
+func (w *mysqlCanaryWriter) write(ctx context.Context) (model.Ts, error) {
+	// The commit ts is read from the session writing the row.
+	conn, err := w.db.Conn(ctx)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrMySQLConnectionError, err)
+	}
+	defer conn.Close()
+
+	if !w.tableCreated {
+		_, err = conn.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+quotes.QuoteName(w.schema))
+		if err != nil {
+			return 0, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		_, err = conn.ExecContext(ctx, fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (changefeed VARCHAR(255) PRIMARY KEY, write_time DATETIME(6))",
+			quotes.QuoteSchema(w.schema, w.table)))
+		if err != nil {
+			return 0, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		w.tableCreated = true
+	}
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(
+		"REPLACE INTO %s (changefeed, write_time) VALUES (?, NOW(6))",
+		quotes.QuoteSchema(w.schema, w.table)), w.changefeedID)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+
+	var txnInfo string
+	if err := conn.QueryRowContext(ctx, "SELECT @@tidb_last_txn_info").Scan(&txnInfo); err != nil {
+		return 0, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	var info struct {
+		CommitTs model.Ts `json:"commit_ts"`
+	}
+	if err := json.Unmarshal([]byte(txnInfo), &info); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if info.CommitTs == 0 {
+		return 0, errors.Errorf("invalid last txn info %s", txnInfo)
+	}
+	return info.CommitTs, nil
+}
+
+func (w *mysqlCanaryWriter) Close() error {
+	return w.db.Close()
+}
+
+// healthChecker judges the health of a changefeed by its end-to-end latency.
+//
+// If the upstream DSN is configured, a canary row is written periodically in
+// background, and the latency is measured once the checkpoint of the
+// changefeed passes the commit ts of the row. The canary row in flight is
+// taken into account, so a stuck changefeed turns unhealthy even if no
+// canary row passes. Otherwise the latency is the lag of the checkpoint.
+type healthChecker struct {
+	changefeedID model.ChangeFeedID
+	interval     time.Duration
+	maxLatency   time.Duration
+
+	// newWriter is nil if no canary row is written.
+	newWriter func() (canaryWriter, error)
+	// writer is only accessed by the background write.
+	writer  canaryWriter
+	writing atomic.Bool
+
+	mu sync.Mutex
+	// canaryCommitTs is the commit ts of the canary row in flight, it's 0 if
+	// there is no canary row in flight.
+	canaryCommitTs model.Ts
+	lastWrite      time.Time
+	lastLatency    time.Duration
+	writeErr       error
+	health         *model.ChangefeedHealth
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	metricHealth  prometheus.Gauge
+	metricLatency prometheus.Gauge
+}
+
+// newHealthChecker creates a healthChecker, nil is returned if the health
+// check is disabled.
+func newHealthChecker(changefeedID model.ChangeFeedID, cfg *config.HealthCheckConfig) *healthChecker {
+	if cfg == nil || !cfg.Enable {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &healthChecker{
+		changefeedID:  changefeedID,
+		interval:      time.Duration(cfg.IntervalInSec) * time.Second,
+		maxLatency:    time.Duration(cfg.MaxLatencyInSec) * time.Second,
+		ctx:           ctx,
+		cancel:        cancel,
+		metricHealth:  changefeedHealthGauge.WithLabelValues(changefeedID),
+		metricLatency: changefeedHealthCheckLatencyGauge.WithLabelValues(changefeedID),
+	}
+	if cfg.UpstreamDSN != "" {
+		schema, table, _ := cfg.CanaryTable()
+		h.newWriter = func() (canaryWriter, error) {
+			return newMySQLCanaryWriter(changefeedID, cfg.UpstreamDSN, schema, table)
+		}
+	}
+	return h
+}
+
+// tick updates the health by the checkpoint of the changefeed, and starts
+// writing a canary row in background if it's time to do it. now is the
+// current time of PD.
+func (h *healthChecker) tick(checkpointTs model.Ts, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var latency time.Duration
+	if h.newWriter == nil {
+		latency = now.Sub(oracle.GetTimeFromTS(checkpointTs))
+	} else {
+		if h.canaryCommitTs != 0 && checkpointTs >= h.canaryCommitTs {
+			h.lastLatency = now.Sub(oracle.GetTimeFromTS(h.canaryCommitTs))
+			h.canaryCommitTs = 0
+		}
+		latency = h.lastLatency
+		if h.canaryCommitTs != 0 {
+			if inFlight := now.Sub(oracle.GetTimeFromTS(h.canaryCommitTs)); inFlight > latency {
+				latency = inFlight
+			}
+		}
+		if h.canaryCommitTs == 0 && !h.writing.Load() && now.Sub(h.lastWrite) >= h.interval {
+			h.lastWrite = now
+			h.startWrite()
+		}
+	}
+	if latency < 0 {
+		latency = 0
+	}
+
+	health := &model.ChangefeedHealth{
+		Healthy:   true,
+		LatencyMs: latency.Milliseconds(),
+		CheckTime: model.JSONTime(now),
+	}
+	if h.writeErr != nil {
+		health.Healthy = false
+		health.Message = fmt.Sprintf("write canary row failed: %s", h.writeErr.Error())
+	} else if latency > h.maxLatency {
+		health.Healthy = false
+		health.Message = fmt.Sprintf("latency %s exceeds %s", latency, h.maxLatency)
+	}
+	if h.health == nil || h.health.Healthy != health.Healthy {
+		log.Info("changefeed health changed",
+			zap.String("changefeed", h.changefeedID),
+			zap.Bool("healthy", health.Healthy),
+			zap.Duration("latency", latency),
+			zap.String("message", health.Message))
+	}
+	h.health = health
+
+	if health.Healthy {
+		h.metricHealth.Set(1)
+	} else {
+		h.metricHealth.Set(0)
+	}
+	h.metricLatency.Set(latency.Seconds())
+}
+
+// startWrite writes a canary row in background, it must be called with the
+// lock held.
+func (h *healthChecker) startWrite() {
+	h.writing.Store(true)
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer h.writing.Store(false)
+		commitTs, err := h.write()
+		if err != nil && errors.Cause(err) != context.Canceled {
+			log.Warn("write canary row failed",
+				zap.String("changefeed", h.changefeedID), zap.Error(err))
+		}
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.writeErr = err
+		if err == nil {
+			h.canaryCommitTs = commitTs
+		}
+	}()
+}
+
+func (h *healthChecker) write() (model.Ts, error) {
+	if h.writer == nil {
+		writer, err := h.newWriter()
+		if err != nil {
+			return 0, err
+		}
+		h.writer = writer
+	}
+	// A write slower than the max latency makes the changefeed unhealthy.
+	ctx, cancel := context.WithTimeout(h.ctx, h.maxLatency)
+	defer cancel()
+	return h.writer.write(ctx)
+}
+
+// get returns the latest health of the changefeed.
+func (h *healthChecker) get() *model.ChangefeedHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.health == nil {
+		return &model.ChangefeedHealth{Message: "not checked yet"}
+	}
+	health := *h.health
+	return &health
+}
+
+// close stops the running write and releases the resources.
+func (h *healthChecker) close() {
+	h.cancel()
+	h.wg.Wait()
+	if h.writer != nil {
+		if err := h.writer.Close(); err != nil {
+			log.Warn("close canary writer failed",
+				zap.String("changefeed", h.changefeedID), zap.Error(err))
+		}
+		h.writer = nil
+	}
+	changefeedHealthGauge.DeleteLabelValues(h.changefeedID)
+	changefeedHealthCheckLatencyGauge.DeleteLabelValues(h.changefeedID)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type mockCanaryWriter struct {
+	commitTs chan model.Ts
+	errs     chan error
+	closed   bool
+}
+
+func (w *mockCanaryWriter) write(ctx context.Context) (model.Ts, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case ts := <-w.commitTs:
+		return ts, nil
+	case err := <-w.errs:
+		return 0, err
+	}
+}
+
+func (w *mockCanaryWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestHealthCheckerDisabled(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newHealthChecker("test", nil))
+	require.Nil(t, newHealthChecker("test", &config.HealthCheckConfig{Enable: false}))
+}
+
+func TestHealthCheckerCheckpointLag(t *testing.T) {
+	t.Parallel()
+
+	h := newHealthChecker("test-lag", &config.HealthCheckConfig{
+		Enable: true, IntervalInSec: 1, MaxLatencyInSec: 10,
+	})
+	defer h.close()
+	require.False(t, h.get().Healthy)
+
+	now := time.Now()
+	h.tick(oracle.GoTimeToTS(now.Add(-5*time.Second)), now)
+	health := h.get()
+	require.True(t, health.Healthy)
+	require.Equal(t, int64(5000), health.LatencyMs)
+	require.Equal(t, float64(1), testutil.ToFloat64(h.metricHealth))
+
+	h.tick(oracle.GoTimeToTS(now.Add(-5*time.Second)), now.Add(10*time.Second))
+	health = h.get()
+	require.False(t, health.Healthy)
+	require.Contains(t, health.Message, "exceeds")
+	require.Equal(t, float64(0), testutil.ToFloat64(h.metricHealth))
+	require.InDelta(t, float64(15), testutil.ToFloat64(h.metricLatency), 0.01)
+}
+
+func TestHealthCheckerCanary(t *testing.T) {
+	t.Parallel()
+
+	h := newHealthChecker("test-canary", &config.HealthCheckConfig{
+		Enable: true, UpstreamDSN: "dsn", Table: "test.canary", IntervalInSec: 10, MaxLatencyInSec: 30,
+	})
+	writer := &mockCanaryWriter{commitTs: make(chan model.Ts, 1), errs: make(chan error, 1)}
+	h.newWriter = func() (canaryWriter, error) {
+		return writer, nil
+	}
+
+	// The first tick starts writing a canary row.
+	start := time.Now()
+	h.tick(0, start)
+	require.True(t, h.get().Healthy)
+	commitTs := oracle.GoTimeToTS(start)
+	writer.commitTs <- commitTs
+	require.Eventually(t, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.canaryCommitTs == commitTs
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The canary row in flight makes the changefeed unhealthy once it takes
+	// longer than the max latency.
+	h.tick(commitTs-1, start.Add(20*time.Second))
+	health := h.get()
+	require.True(t, health.Healthy)
+	require.Equal(t, int64(20000), health.LatencyMs)
+	h.tick(commitTs-1, start.Add(40*time.Second))
+	require.False(t, h.get().Healthy)
+
+	// The changefeed recovers once the checkpoint passes the canary row, and
+	// the latency of the canary row is kept until the next one passes.
+	h.tick(commitTs, start.Add(25*time.Second))
+	health = h.get()
+	require.True(t, health.Healthy)
+	require.Equal(t, int64(25000), health.LatencyMs)
+
+	// A failed write makes the changefeed unhealthy.
+	require.True(t, h.writing.Load())
+	writer.errs <- errors.New("injected error")
+	require.Eventually(t, func() bool {
+		return !h.writing.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+	h.tick(commitTs, start.Add(26*time.Second))
+	health = h.get()
+	require.False(t, health.Healthy)
+	require.Contains(t, health.Message, "injected error")
+
+	h.close()
+	require.True(t, writer.closed)
+}
@@ -14,8 +14,11 @@
 package owner
 
 import (
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -28,6 +31,14 @@ var (
 			Help:      "barrier ts of changefeeds",
 		}, []string{"changefeed"})
 
+	changefeedBarrierTypeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "owner",
+			Name:      "barrier_type",
+			Help:      "type of the minimal barrier of changefeeds, 0: ddl, 1: sync-point, 2: finish",
+		}, []string{"changefeed"})
+
 	changefeedCheckpointTsGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "ticdc",
@@ -93,8 +104,94 @@ var (
 			Help:      "Bucketed histogram of owner close changefeed reactor time (s).",
 			Buckets:   prometheus.ExponentialBuckets(0.01 /* 10 ms */, 2, 18),
 		})
+	changefeedDroppedDDLClauseCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "owner",
+			Name:      "dropped_ddl_clause_count",
+			Help:      "The number of DDL clauses dropped because the downstream doesn't support them",
+		}, []string{"changefeed"})
+	changefeedSchemaDriftTablesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "owner",
+			Name:      "schema_drift_tables",
+			Help:      "The number of tables whose downstream schema differs from the replicated schema",
+		}, []string{"changefeed"})
+	ddlConcurrencyRunningGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "owner",
+			Name:      "downstream_running_ddl",
+			Help:      "The number of DDLs being executed on each downstream if the concurrent DDLs are limited",
+		}, []string{"downstream"})
+	changefeedHealthGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "owner",
+			Name:      "changefeed_health",
+			Help:      "The health of changefeeds judged by the health check, 1 for healthy and 0 for unhealthy",
+		}, []string{"changefeed"})
+	changefeedHealthCheckLatencyGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "owner",
+			Name:      "health_check_latency",
+			Help:      "The end-to-end latency of changefeeds measured by the health check (s)",
+		}, []string{"changefeed"})
+	changefeedLabels = newChangefeedLabelsCollector()
 )
 
+// changefeedLabelsCollector exports the user-defined labels of changefeeds as
+// an info metric whose value is always 1. The label names differ between
+// changefeeds, so it can't be a GaugeVec. All metrics of a changefeed can be
+// joined with its labels, e.g.
+// `ticdc_owner_checkpoint_ts_lag * on(changefeed) group_left(label_team) ticdc_owner_changefeed_labels`.
+type changefeedLabelsCollector struct {
+	mu     sync.Mutex
+	labels map[model.ChangeFeedID]map[string]string
+}
+
+func newChangefeedLabelsCollector() *changefeedLabelsCollector {
+	return &changefeedLabelsCollector{labels: make(map[model.ChangeFeedID]map[string]string)}
+}
+
+// update replaces the labels of all changefeeds.
+func (c *changefeedLabelsCollector) update(labels map[model.ChangeFeedID]map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.labels = labels
+}
+
+// Describe implements prometheus.Collector. It sends no descriptors, which
+// makes it an unchecked collector, because the label names are dynamic.
+func (c *changefeedLabelsCollector) Describe(_ chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *changefeedLabelsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, labels := range c.labels {
+		names := make([]string, 0, len(labels))
+		for name := range labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		labelNames := make([]string, 0, len(labels)+1)
+		labelValues := make([]string, 0, len(labels)+1)
+		labelNames = append(labelNames, "changefeed")
+		labelValues = append(labelValues, id)
+		for _, name := range names {
+			// prefix the names, so they never conflict with the labels of TiCDC
+			labelNames = append(labelNames, "label_"+name)
+			labelValues = append(labelValues, labels[name])
+		}
+		desc := prometheus.NewDesc("ticdc_owner_changefeed_labels",
+			"user-defined labels of changefeeds", labelNames, nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, labelValues...)
+	}
+}
+
 const (
 	// total tables that have been dispatched to a single processor
 	maintainTableTypeTotal string = "total"
@@ -110,6 +207,7 @@ const (
 // InitMetrics registers all metrics used in owner
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(changefeedBarrierTsGauge)
+	registry.MustRegister(changefeedBarrierTypeGauge)
 	registry.MustRegister(changefeedCheckpointTsGauge)
 	registry.MustRegister(changefeedResolvedTsGauge)
 	registry.MustRegister(changefeedCheckpointTsLagGauge)
@@ -119,4 +217,10 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(changefeedStatusGauge)
 	registry.MustRegister(changefeedTickDuration)
 	registry.MustRegister(changefeedCloseDuration)
+	registry.MustRegister(changefeedDroppedDDLClauseCounter)
+	registry.MustRegister(changefeedSchemaDriftTablesGauge)
+	registry.MustRegister(ddlConcurrencyRunningGauge)
+	registry.MustRegister(changefeedHealthGauge)
+	registry.MustRegister(changefeedHealthCheckLatencyGauge)
+	registry.MustRegister(changefeedLabels)
 }
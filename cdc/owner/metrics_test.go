@@ -0,0 +1,45 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedLabelsCollector(t *testing.T) {
+	t.Parallel()
+
+	c := newChangefeedLabelsCollector()
+	require.Equal(t, 0, testutil.CollectAndCount(c))
+
+	c.update(map[model.ChangeFeedID]map[string]string{
+		"cf1": {"team": "a", "env": "prod"},
+		"cf2": {"team": "b"},
+	})
+	expected := `
+# HELP ticdc_owner_changefeed_labels user-defined labels of changefeeds
+# TYPE ticdc_owner_changefeed_labels gauge
+ticdc_owner_changefeed_labels{changefeed="cf1",label_env="prod",label_team="a"} 1
+ticdc_owner_changefeed_labels{changefeed="cf2",label_team="b"} 1
+`
+	require.Nil(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+
+	c.update(nil)
+	require.Equal(t, 0, testutil.CollectAndCount(c))
+}
@@ -103,11 +103,17 @@ type ownerImpl struct {
 	logLimiter   *rate.Limiter
 	lastTickTime time.Time
 	closed       int32
+	// lastMinCheckpointTs is the min checkpoint ts of changefeeds used to
+	// update the service GC safepoint in the last tick.
+	lastMinCheckpointTs uint64
 	// bootstrapped specifies whether the owner has been initialized.
 	// This will only be done when the owner starts the first Tick.
 	// NOTICE: Do not use it in a method other than tick unexpectedly,
 	//         as it is not a thread-safe value.
 	bootstrapped bool
+	// ddlLimiter limits the concurrent DDLs of the changefeeds on the same
+	// downstream.
+	ddlLimiter *ddlConcurrencyLimiter
 
 	newChangefeed func(id model.ChangeFeedID, gcManager gc.Manager) *changefeed
 }
@@ -120,6 +126,8 @@ func NewOwner(pdClient pd.Client) Owner {
 		lastTickTime:  time.Now(),
 		newChangefeed: newChangefeed,
 		logLimiter:    rate.NewLimiter(versionInconsistentLogRate, versionInconsistentLogRate),
+		ddlLimiter: newDDLConcurrencyLimiter(
+			config.GetGlobalServerConfig().MaxConcurrentDDLPerDownstream),
 	}
 }
 
@@ -155,12 +163,13 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 
 	o.captures = state.Captures
 	o.updateMetrics(state)
+	ctx := stdCtx.(cdcContext.Context)
 
 	// handleJobs() should be called before clusterVersionConsistent(), because
 	// when there are different versions of cdc nodes in the cluster,
 	// the admin job may not be processed all the time. And http api relies on
 	// admin job, which will cause all http api unavailable.
-	o.handleJobs()
+	o.handleJobs(ctx)
 
 	if !o.clusterVersionConsistent(state.Captures) {
 		return state, nil
@@ -175,7 +184,7 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	}
 
 	// Tick all changefeeds.
-	ctx := stdCtx.(cdcContext.Context)
+	alignedCheckpointTs := alignWatermarks(state.Changefeeds)
 	for changefeedID, changefeedState := range state.Changefeeds {
 		if changefeedState.Info == nil {
 			o.cleanUpChangefeed(changefeedState)
@@ -191,8 +200,10 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		cfReactor, exist := o.changefeeds[changefeedID]
 		if !exist {
 			cfReactor = o.newChangefeed(changefeedID, o.gcManager)
+			cfReactor.ddlLimiter = o.ddlLimiter
 			o.changefeeds[changefeedID] = cfReactor
 		}
+		cfReactor.alignedCheckpointTs = alignedCheckpointTs[watermarkGroup(changefeedState.Info)]
 		cfReactor.Tick(ctx, changefeedState, state.Captures)
 	}
 
@@ -340,6 +351,7 @@ func (o *ownerImpl) cleanStaleMetrics() {
 	changefeedResolvedTsLagGauge.Reset()
 	ownerMaintainTableNumGauge.Reset()
 	changefeedStatusGauge.Reset()
+	changefeedLabels.update(nil)
 }
 
 func (o *ownerImpl) updateMetrics(state *orchestrator.GlobalReactorState) {
@@ -349,6 +361,14 @@ func (o *ownerImpl) updateMetrics(state *orchestrator.GlobalReactorState) {
 	ownershipCounter.Add(float64(now.Sub(o.lastTickTime)) / float64(time.Second))
 	o.lastTickTime = now
 
+	labels := make(map[model.ChangeFeedID]map[string]string)
+	for changefeedID, changefeedState := range state.Changefeeds {
+		if changefeedState.Info != nil && len(changefeedState.Info.Labels) > 0 {
+			labels[changefeedID] = changefeedState.Info.Labels
+		}
+	}
+	changefeedLabels.update(labels)
+
 	conf := config.GetGlobalServerConfig()
 
 	// TODO refactor this piece of code when the new scheduler is stabilized,
@@ -415,7 +435,7 @@ func (o *ownerImpl) clusterVersionConsistent(captures map[model.CaptureID]*model
 	return true
 }
 
-func (o *ownerImpl) handleJobs() {
+func (o *ownerImpl) handleJobs(ctx cdcContext.Context) {
 	jobs := o.takeOwnerJobs()
 	for _, job := range jobs {
 		changefeedID := job.ChangefeedID
@@ -434,7 +454,7 @@ func (o *ownerImpl) handleJobs() {
 		case ownerJobTypeRebalance:
 			cfReactor.scheduler.Rebalance()
 		case ownerJobTypeQuery:
-			job.done <- o.handleQueries(job.query)
+			job.done <- o.handleQueries(ctx, job.query)
 		case ownerJobTypeDebugInfo:
 			// TODO: implement this function
 		}
@@ -442,7 +462,7 @@ func (o *ownerImpl) handleJobs() {
 	}
 }
 
-func (o *ownerImpl) handleQueries(query *Query) error {
+func (o *ownerImpl) handleQueries(ctx cdcContext.Context, query *Query) error {
 	switch query.Tp {
 	case QueryAllChangeFeedStatuses:
 		ret := map[model.ChangeFeedID]*model.ChangeFeedStatus{}
@@ -548,6 +568,48 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			})
 		}
 		query.Data = ret
+	case QueryChangeFeedBarrier:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		query.Data = cfReactor.barrier()
+	case QueryTableDiagnoses:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		query.Data = cfReactor.tableDiagnoses()
+	case QueryRequestConsistencyReport:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		report, err := cfReactor.requestConsistencyReport(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		query.Data = report
+	case QueryConsistencyReport:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		report := cfReactor.consistencyReporter.get()
+		if report == nil {
+			return cerror.ErrConsistencyReportNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		query.Data = report
+	case QueryChangefeedHealth:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil || cfReactor.state.Info == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		cfg := cfReactor.state.Info.Config.HealthCheck
+		if cfg == nil || !cfg.Enable {
+			return cerror.ErrHealthCheckNotEnabled.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		query.Data = cfReactor.health()
 	}
 	return nil
 }
@@ -591,6 +653,13 @@ func (o *ownerImpl) updateGCSafepoint(
 			forceUpdate = true
 		}
 	}
+	// Force update when the min checkpoint ts goes backwards, which happens
+	// when a changefeed is rewound, to avoid checking the rewound checkpoint
+	// against a stale GC safepoint.
+	if minCheckpointTs < o.lastMinCheckpointTs {
+		forceUpdate = true
+	}
+	o.lastMinCheckpointTs = minCheckpointTs
 	// When the changefeed starts up, CDC will do a snapshot read at
 	// (checkpointTs - 1) from TiKV, so (checkpointTs - 1) should be an upper
 	// bound for the GC safepoint.
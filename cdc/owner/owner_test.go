@@ -452,6 +452,30 @@ func TestUpdateGCSafePoint(t *testing.T) {
 		t.Fatal("timeout")
 	case <-ch:
 	}
+
+	// rewind the checkpoint of a running changefeed, it must update GC
+	// safepoint even if no changefeed is added.
+	o.changefeeds[changefeedID1] = nil
+	o.changefeeds[changefeedID2] = nil
+	state.Changefeeds[changefeedID1].PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			return &model.ChangeFeedStatus{CheckpointTs: 10}, true, nil
+		})
+	tester.MustApplyPatches()
+	mockPDClient.UpdateServiceGCSafePointFunc = func(
+		ctx context.Context, serviceID string, ttl int64, safePoint uint64,
+	) (uint64, error) {
+		require.Equal(t, safePoint, uint64(9))
+		ch <- struct{}{}
+		return 0, nil
+	}
+	err = o.updateGCSafepoint(ctx, state)
+	require.Nil(t, err)
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	case <-ch:
+	}
 }
 
 // make sure handleJobs works well even if there is two different
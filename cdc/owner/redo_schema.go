@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// defaultRedoSchemaSnapshotInterval is the min interval between two schema
+// snapshots written to the redo logs.
+var defaultRedoSchemaSnapshotInterval = 30 * time.Second
+
+// schemaSnapshotEmitter writes the schema snapshots, it's implemented by the
+// redo log manager.
+type schemaSnapshotEmitter interface {
+	EmitSchemaSnapshot(ctx context.Context, snap *model.RedoSchemaSnapshot) error
+}
+
+// redoSchemaWriter writes the schema snapshots to the redo logs in the
+// background, so the owner isn't blocked by the storage. At most one snapshot
+// is written at a time and once per interval, the snapshots updated in
+// between are merged into the latest one. A snapshot failed to be written is
+// retried after the interval unless a newer one is updated.
+type redoSchemaWriter struct {
+	changefeedID model.ChangeFeedID
+	emitter      schemaSnapshotEmitter
+	interval     time.Duration
+
+	mu      sync.Mutex
+	pending *model.RedoSchemaSnapshot
+	notify  chan struct{}
+}
+
+func newRedoSchemaWriter(
+	changefeedID model.ChangeFeedID, emitter schemaSnapshotEmitter, interval time.Duration,
+) *redoSchemaWriter {
+	return &redoSchemaWriter{
+		changefeedID: changefeedID,
+		emitter:      emitter,
+		interval:     interval,
+		notify:       make(chan struct{}, 1),
+	}
+}
+
+// update replaces the snapshot to be written with snap.
+func (w *redoSchemaWriter) update(snap *model.RedoSchemaSnapshot) {
+	w.mu.Lock()
+	w.pending = snap
+	w.mu.Unlock()
+	w.wakeup()
+}
+
+func (w *redoSchemaWriter) wakeup() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run writes the snapshots updated until the ctx is canceled.
+func (w *redoSchemaWriter) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.notify:
+		}
+		w.mu.Lock()
+		snap := w.pending
+		w.pending = nil
+		w.mu.Unlock()
+		if snap == nil {
+			continue
+		}
+
+		if err := w.emitter.EmitSchemaSnapshot(ctx, snap); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warn("write redo schema snapshot failed, it will be retried",
+				zap.String("changefeed", w.changefeedID),
+				zap.Uint64("ts", snap.Ts), zap.Error(err))
+			w.mu.Lock()
+			if w.pending == nil {
+				w.pending = snap
+			}
+			w.mu.Unlock()
+			w.wakeup()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.interval):
+		}
+	}
+}
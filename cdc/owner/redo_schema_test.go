@@ -0,0 +1,84 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSchemaSnapshotEmitter struct {
+	mu      sync.Mutex
+	emitted []model.Ts
+	// failures is the number of the snapshots failed to be emitted first.
+	failures int
+}
+
+func (e *mockSchemaSnapshotEmitter) EmitSchemaSnapshot(
+	ctx context.Context, snap *model.RedoSchemaSnapshot,
+) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.failures > 0 {
+		e.failures--
+		return errors.New("storage is unavailable")
+	}
+	e.emitted = append(e.emitted, snap.Ts)
+	return nil
+}
+
+func (e *mockSchemaSnapshotEmitter) getEmitted() []model.Ts {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]model.Ts{}, e.emitted...)
+}
+
+func TestRedoSchemaWriter(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	emitter := &mockSchemaSnapshotEmitter{failures: 1}
+	w := newRedoSchemaWriter("test", emitter, 200*time.Millisecond)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.run(ctx)
+	}()
+
+	// the failed snapshot is retried after the interval.
+	w.update(&model.RedoSchemaSnapshot{Ts: 1})
+	require.Eventually(t, func() bool {
+		return len(emitter.getEmitted()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []model.Ts{1}, emitter.getEmitted())
+
+	// the snapshots updated in the interval are merged into the latest one.
+	for ts := model.Ts(2); ts <= 5; ts++ {
+		w.update(&model.RedoSchemaSnapshot{Ts: ts})
+	}
+	require.Eventually(t, func() bool {
+		return len(emitter.getEmitted()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []model.Ts{1, 5}, emitter.getEmitted())
+
+	cancel()
+	wg.Wait()
+}
@@ -50,6 +50,10 @@ type scheduler interface {
 	// Rebalance is used to trigger manual workload rebalances.
 	Rebalance()
 
+	// TableDiagnoses returns the diagnoses of the tables which are not
+	// running or keep bouncing between captures.
+	TableDiagnoses() []*model.TableDiagnosis
+
 	// Close closes the scheduler and releases resources.
 	Close(ctx context.Context)
 }
@@ -164,6 +168,38 @@ func (s *schedulerV2) DispatchTable(
 	return true, nil
 }
 
+func (s *schedulerV2) PrepareTable(
+	ctx context.Context,
+	changeFeedID model.ChangeFeedID,
+	tableID model.TableID,
+	captureID model.CaptureID,
+	epoch model.ProcessorEpoch,
+) (done bool, err error) {
+	topic := model.DispatchTableTopic(changeFeedID)
+	message := &model.DispatchTableMessage{
+		OwnerRev:  ctx.GlobalVars().OwnerRevision,
+		ID:        tableID,
+		IsPrepare: true,
+		Epoch:     epoch,
+	}
+
+	ok, err := s.trySendMessage(ctx, captureID, topic, message)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	log.Info("schedulerV2: PrepareTable",
+		zap.Any("message", message),
+		zap.Bool("successful", ok),
+		zap.String("changefeedID", changeFeedID),
+		zap.String("captureID", captureID))
+	if !ok {
+		return false, nil
+	}
+
+	s.stats.RecordDispatch()
+	return true, nil
+}
+
 func (s *schedulerV2) Announce(
 	ctx context.Context,
 	changeFeedID model.ChangeFeedID,
@@ -263,6 +299,10 @@ func (s *schedulerV2) registerPeerMessageHandlers(ctx context.Context) (ret erro
 		func(sender string, messageI interface{}) error {
 			message := messageI.(*model.DispatchTableResponseMessage)
 			s.stats.RecordDispatchResponse()
+			if message.IsPrepare {
+				s.OnAgentPreparedTable(sender, message.ID, message.Epoch)
+				return nil
+			}
 			s.OnAgentFinishedTableOperation(sender, message.ID, message.Epoch)
 			return nil
 		})
@@ -14,6 +14,7 @@
 package owner
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/pingcap/errors"
@@ -41,6 +42,8 @@ type schedulerJob struct {
 	// if the operation is an add operation, boundaryTs is start ts
 	BoundaryTs    uint64
 	TargetCapture model.CaptureID
+	// Reason is why the job is generated, it's recorded in the table history.
+	Reason string
 }
 
 type moveTableJob struct {
@@ -57,11 +60,21 @@ type oldScheduler struct {
 	moveTableJobQueue     []*moveTableJob
 	needRebalanceNextTick bool
 	lastTickCaptureCount  int
+
+	// removedTableBoundaries are the final checkpoint ts of the tables removed
+	// gracefully, the tables are added again from them, so the moved tables
+	// are neither lost nor replicated twice.
+	removedTableBoundaries map[model.TableID]model.Ts
+
+	// history records the scheduling operations of tables to diagnose them.
+	history *schedulerv2.TableHistory
 }
 
 func newSchedulerV1() scheduler {
 	return &schedulerV1CompatWrapper{&oldScheduler{
-		moveTableTargets: make(map[model.TableID]model.CaptureID),
+		moveTableTargets:       make(map[model.TableID]model.CaptureID),
+		removedTableBoundaries: make(map[model.TableID]model.Ts),
+		history:                schedulerv2.NewTableHistory(),
 	}}
 }
 
@@ -137,9 +150,12 @@ func (s *oldScheduler) handleMoveTableJob() (shouldUpdateState bool, err error)
 				// skip removing this table to avoid the remove operation created by the rebalance function interfering with the operation created by another function
 				return status, false, nil
 			}
-			status.RemoveTable(job.tableID, s.state.Status.CheckpointTs, false)
+			// the table is stopped after it's flushed to the global resolved ts,
+			// which is no less than its checkpoint ts.
+			status.RemoveTable(job.tableID, s.state.Status.ResolvedTs, false)
 			return status, true, nil
 		})
+		s.history.Record(job.tableID, schedulerv2.TableOperationRemove, source, "moved by user")
 	}
 	s.moveTableJobQueue = nil
 	return
@@ -149,6 +165,62 @@ func (s *oldScheduler) Rebalance() {
 	s.needRebalanceNextTick = true
 }
 
+// TableDiagnoses returns the diagnoses of the tables which are not running
+// or keep bouncing between captures.
+func (s *oldScheduler) TableDiagnoses() []*model.TableDiagnosis {
+	if s.state == nil {
+		return nil
+	}
+	// the error of duplicated tables is reported by Tick.
+	table2CaptureIndex, _ := s.table2CaptureIndex()
+
+	var diagnoses []*model.TableDiagnosis
+	for _, tableID := range s.currentTables {
+		history, bouncing := s.history.Diagnose(tableID)
+		diagnosis := &model.TableDiagnosis{
+			TableID:  tableID,
+			State:    model.TableScheduleRunning,
+			Bouncing: bouncing,
+			History:  history,
+		}
+		captureID, ok := table2CaptureIndex[tableID]
+		if !ok {
+			diagnosis.State = model.TableScheduleAbsent
+			if len(s.captures) == 0 {
+				diagnosis.Reasons = append(diagnosis.Reasons, "no active capture to dispatch the table to")
+			} else {
+				diagnosis.Reasons = append(diagnosis.Reasons, "waiting to be dispatched")
+			}
+		} else {
+			diagnosis.CaptureID = captureID
+			status := s.state.TaskStatuses[captureID]
+			if op, ok := status.Operation[tableID]; ok && op.Status != model.OperFinished {
+				diagnosis.State = model.TableScheduleAdding
+				action := "add"
+				if op.Delete {
+					diagnosis.State = model.TableScheduleRemoving
+					action = "remove"
+				}
+				progress := "the operation is not processed yet"
+				if op.Status == model.OperProcessed {
+					progress = "the operation is processed, waiting for the table to catch up"
+				}
+				diagnosis.Reasons = append(diagnosis.Reasons, fmt.Sprintf(
+					"waiting for the processor on capture %s to %s the table, %s", captureID, action, progress))
+			}
+		}
+		if diagnosis.State == model.TableScheduleRunning && !bouncing {
+			continue
+		}
+		if bouncing {
+			diagnosis.Reasons = append(diagnosis.Reasons,
+				"the table is removed repeatedly recently, see the history for the reasons")
+		}
+		diagnoses = append(diagnoses, diagnosis)
+	}
+	return diagnoses
+}
+
 func (s *oldScheduler) table2CaptureIndex() (map[model.TableID]model.CaptureID, error) {
 	table2CaptureIndex := make(map[model.TableID]model.CaptureID)
 	for captureID, taskStatus := range s.state.TaskStatuses {
@@ -192,6 +264,7 @@ func (s *oldScheduler) dispatchToTargetCaptures(pendingJobs []*schedulerJob) {
 				continue
 			}
 			pendingJob.TargetCapture = target
+			pendingJob.Reason = "moved by user"
 			delete(s.moveTableTargets, pendingJob.TableID)
 			continue
 		}
@@ -228,6 +301,7 @@ func (s *oldScheduler) dispatchToTargetCaptures(pendingJobs []*schedulerJob) {
 		}
 		minCapture := getMinWorkloadCapture()
 		pendingJob.TargetCapture = minCapture
+		pendingJob.Reason = "dispatched to the capture with the least workload"
 		workloads[minCapture] += 1
 	}
 }
@@ -241,18 +315,33 @@ func (s *oldScheduler) syncTablesWithCurrentTables() ([]*schedulerJob, error) {
 		return nil, errors.Trace(err)
 	}
 	globalCheckpointTs := s.state.Status.CheckpointTs
+	currentTables := make(map[model.TableID]struct{}, len(s.currentTables))
 	for _, tableID := range s.currentTables {
+		currentTables[tableID] = struct{}{}
 		if _, exist := allTableListeningNow[tableID]; exist {
 			delete(allTableListeningNow, tableID)
 			continue
 		}
-		// For each table which should be listened but is not, add an adding-table job to the pending job list
+		// For each table which should be listened but is not, add an adding-table job to the pending job list.
+		// A table removed gracefully is added from its final checkpoint ts.
+		startTs := globalCheckpointTs
+		if boundaryTs, ok := s.removedTableBoundaries[tableID]; ok {
+			if boundaryTs > startTs {
+				startTs = boundaryTs
+			}
+			delete(s.removedTableBoundaries, tableID)
+		}
 		pendingJob = append(pendingJob, &schedulerJob{
 			Tp:         schedulerJobTypeAddTable,
 			TableID:    tableID,
-			BoundaryTs: globalCheckpointTs,
+			BoundaryTs: startTs,
 		})
 	}
+	for tableID := range s.removedTableBoundaries {
+		if _, ok := currentTables[tableID]; !ok {
+			delete(s.removedTableBoundaries, tableID)
+		}
+	}
 	// The remaining tables are the tables which should be not listened
 	tablesThatShouldNotBeListened := allTableListeningNow
 	for tableID, captureID := range tablesThatShouldNotBeListened {
@@ -301,11 +390,27 @@ func (s *oldScheduler) handleJobs(jobs []*schedulerJob) {
 			}
 			return status, true, nil
 		})
+		switch job.Tp {
+		case schedulerJobTypeAddTable:
+			s.history.Record(job.TableID, schedulerv2.TableOperationAdd, job.TargetCapture, job.Reason)
+		case schedulerJobTypeRemoveTable:
+			// the table is not replicated anymore, no need to diagnose it.
+			s.history.Drop(job.TableID)
+		}
 	}
 }
 
 // cleanUpFinishedOperations clean up the finished operations.
 func (s *oldScheduler) cleanUpFinishedOperations() {
+	for _, status := range s.state.TaskStatuses {
+		for tableID, operation := range status.Operation {
+			// the processor sets the BoundaryTs of a finished remove operation
+			// to the final checkpoint ts of the table.
+			if operation.Delete && operation.Status == model.OperFinished {
+				s.removedTableBoundaries[tableID] = operation.BoundaryTs
+			}
+		}
+	}
 	for captureID := range s.state.TaskStatuses {
 		s.state.PatchTaskStatus(captureID, func(status *model.TaskStatus) (*model.TaskStatus, bool, error) {
 			if status == nil {
@@ -388,13 +493,14 @@ func (s *oldScheduler) rebalanceByTableNum() (shouldUpdateState bool) {
 					// skip remove this table to avoid the remove operation created by rebalance function to influence the operation created by other function
 					return status, false, nil
 				}
-				status.RemoveTable(tableID, s.state.Status.CheckpointTs, false)
+				status.RemoveTable(tableID, s.state.Status.ResolvedTs, false)
 				log.Info("Rebalance: Move table",
 					zap.Int64("tableID", tableID),
 					zap.String("capture", captureID),
 					zap.String("changefeed", s.state.ID))
 				return status, true, nil
 			})
+			s.history.Record(tableID, schedulerv2.TableOperationRemove, captureID, "rebalance")
 			tableNum2Remove--
 		}
 	}
@@ -436,6 +542,10 @@ func (w *schedulerV1CompatWrapper) Rebalance() {
 	w.inner.Rebalance()
 }
 
+func (w *schedulerV1CompatWrapper) TableDiagnoses() []*model.TableDiagnosis {
+	return w.inner.TableDiagnoses()
+}
+
 func (w *schedulerV1CompatWrapper) Close(_ cdcContext.Context) {
 	// No-op for the old scheduler
 }
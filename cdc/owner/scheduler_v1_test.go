@@ -279,6 +279,62 @@ func TestScheduleMoveTable(t *testing.T) {
 	require.Equal(t, s.state.TaskStatuses[captureID2].Operation, map[model.TableID]*model.TableOperation{})
 }
 
+func TestScheduleMoveTableFromBoundary(t *testing.T) {
+	s := &schedulerTester{}
+	s.reset(t)
+	captureID1 := "test-capture-1"
+	captureID2 := "test-capture-2"
+	s.addCapture(captureID1)
+	s.addCapture(captureID2)
+
+	shouldUpdateState, err := s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	source := captureID1
+	target := captureID2
+	if _, ok := s.state.TaskStatuses[captureID2].Tables[1]; ok {
+		source, target = target, source
+	}
+	s.finishTableOperation(source, 1)
+	s.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 10
+		status.ResolvedTs = 20
+		return status, true, nil
+	})
+	s.tester.MustApplyPatches()
+
+	// the table is stopped at the global resolved ts.
+	s.scheduler.MoveTable(1, target)
+	shouldUpdateState, err = s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	require.False(t, shouldUpdateState)
+	s.tester.MustApplyPatches()
+	require.Equal(t, s.state.TaskStatuses[source].Operation, map[model.TableID]*model.TableOperation{
+		1: {Delete: true, BoundaryTs: 20, Status: model.OperDispatched},
+	})
+
+	// the processor reports the final checkpoint ts of the table.
+	s.state.PatchTaskStatus(source, func(status *model.TaskStatus) (*model.TaskStatus, bool, error) {
+		status.Operation[1].BoundaryTs = 20
+		status.Operation[1].Status = model.OperFinished
+		return status, true, nil
+	})
+	s.tester.MustApplyPatches()
+	_, err = s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	s.tester.MustApplyPatches()
+
+	// the table is added to the target capture from the final checkpoint ts.
+	_, err = s.scheduler.Tick(s.state, []model.TableID{1}, s.captures)
+	require.Nil(t, err)
+	s.tester.MustApplyPatches()
+	require.Equal(t, s.state.TaskStatuses[target].Tables, map[model.TableID]*model.TableReplicaInfo{
+		1: {StartTs: 20},
+	})
+	require.Empty(t, s.scheduler.removedTableBoundaries)
+}
+
 func TestScheduleRebalance(t *testing.T) {
 	s := &schedulerTester{}
 	s.reset(t)
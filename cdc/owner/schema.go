@@ -14,6 +14,8 @@
 package owner
 
 import (
+	"sort"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/kv"
@@ -37,6 +39,15 @@ type schemaWrap4Owner struct {
 	allPhysicalTablesCache []model.TableID
 	ddlHandledTs           model.Ts
 
+	// frozenTables is the set of IDs of the tables allowed to be replicated,
+	// it is nil if the tables of the changefeed are not frozen. The tables are
+	// tracked by ID so that a renamed table stays approved, and a new table
+	// reusing the name of a dropped one is not.
+	frozenTables map[model.TableID]struct{}
+	// unresolvedFrozenTables are the approved tables not found in the schema
+	// when the tables are frozen, they are resolved the next time.
+	unresolvedFrozenTables []model.TableName
+
 	id model.ChangeFeedID
 }
 
@@ -107,6 +118,30 @@ func (s *schemaWrap4Owner) AllTableNames() []model.TableName {
 	return names
 }
 
+// BuildSchemaSnapshot returns the structures of all tables that are being
+// replicated, at the ts the last DDL is handled.
+func (s *schemaWrap4Owner) BuildSchemaSnapshot() *model.RedoSchemaSnapshot {
+	tables := s.schemaSnapshot.Tables()
+	snap := &model.RedoSchemaSnapshot{
+		Ts:     s.ddlHandledTs,
+		Tables: make([]*model.RedoTableSchema, 0, len(tables)),
+	}
+	for _, tblInfo := range tables {
+		if s.shouldIgnoreTable(tblInfo) {
+			continue
+		}
+		snap.Tables = append(snap.Tables, &model.RedoTableSchema{
+			Schema:    tblInfo.TableName.Schema,
+			Table:     tblInfo.TableName.Table,
+			TableInfo: tblInfo.TableInfo,
+		})
+	}
+	sort.Slice(snap.Tables, func(i, j int) bool {
+		return snap.Tables[i].TableInfo.ID < snap.Tables[j].TableInfo.ID
+	})
+	return snap
+}
+
 func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 	if job.BinlogInfo.FinishedTS <= s.ddlHandledTs {
 		log.Warn("job finishTs is less than schema handleTs, discard invalid job",
@@ -123,6 +158,12 @@ func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 			zap.Any("role", util.RoleOwner))
 		return errors.Trace(err)
 	}
+	// truncating a table creates a new table ID for it
+	if _, ok := s.frozenTables[job.TableID]; ok &&
+		job.Type == timodel.ActionTruncateTable && job.BinlogInfo.TableInfo != nil {
+		delete(s.frozenTables, job.TableID)
+		s.frozenTables[job.BinlogInfo.TableInfo.ID] = struct{}{}
+	}
 	log.Info("handle DDL", zap.String("changefeed", s.id),
 		zap.String("DDL", job.Query), zap.Stringer("job", job),
 		zap.Any("role", util.RoleOwner))
@@ -131,6 +172,62 @@ func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 	return nil
 }
 
+// FreezeTables restricts the replicated tables to the given ones,
+// the tables not in the list will be ignored even if they match the filter.
+// The names are resolved to table IDs in the current schema.
+func (s *schemaWrap4Owner) FreezeTables(tables []model.TableName) {
+	s.frozenTables = make(map[model.TableID]struct{}, len(tables))
+	s.unresolvedFrozenTables = nil
+	for _, t := range tables {
+		id, ok := s.schemaSnapshot.GetTableIDByName(t.Schema, t.Table)
+		if !ok {
+			log.Warn("approved table of the frozen changefeed is not found",
+				zap.String("changefeed", s.id), zap.Stringer("table", t))
+			s.unresolvedFrozenTables = append(s.unresolvedFrozenTables,
+				model.TableName{Schema: t.Schema, Table: t.Table})
+			continue
+		}
+		s.frozenTables[id] = struct{}{}
+	}
+	s.allPhysicalTablesCache = nil
+}
+
+// FrozenTableNames returns the current names of the frozen tables, which
+// differ from the approved ones once the tables are renamed.
+func (s *schemaWrap4Owner) FrozenTableNames() []model.TableName {
+	if s.frozenTables == nil {
+		return nil
+	}
+	names := make([]model.TableName, 0, len(s.frozenTables)+len(s.unresolvedFrozenTables))
+	for id := range s.frozenTables {
+		tableInfo, ok := s.schemaSnapshot.TableByID(id)
+		if !ok {
+			continue
+		}
+		names = append(names, model.TableName{
+			Schema: tableInfo.TableName.Schema,
+			Table:  tableInfo.TableName.Table,
+		})
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Schema != names[j].Schema {
+			return names[i].Schema < names[j].Schema
+		}
+		return names[i].Table < names[j].Table
+	})
+	return append(names, s.unresolvedFrozenTables...)
+}
+
+// IsFrozenOutTable returns true if the tables of the changefeed are frozen
+// and the given table is not one of them.
+func (s *schemaWrap4Owner) IsFrozenOutTable(tableID model.TableID) bool {
+	if s.frozenTables == nil {
+		return false
+	}
+	_, ok := s.frozenTables[tableID]
+	return !ok
+}
+
 func (s *schemaWrap4Owner) IsIneligibleTableID(tableID model.TableID) bool {
 	return s.schemaSnapshot.IsIneligibleTableID(tableID)
 }
@@ -156,6 +253,9 @@ func (s *schemaWrap4Owner) shouldIgnoreTable(t *model.TableInfo) bool {
 	if s.filter.ShouldIgnoreTable(schemaName, tableName) {
 		return true
 	}
+	if s.IsFrozenOutTable(t.ID) {
+		return true
+	}
 	if s.config.Cyclic.IsEnabled() && mark.IsMarkTable(schemaName, tableName) {
 		// skip the mark table if cyclic is enabled
 		return true
@@ -0,0 +1,272 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// downstreamSchemaReader reads the schemas of downstream tables.
+type downstreamSchemaReader interface {
+	Columns(ctx context.Context, schemas []string) (map[model.TableName][]*sink.DownstreamColumn, error)
+	Close() error
+}
+
+// expectedColumn is a column the downstream table is expected to have.
+type expectedColumn struct {
+	name     string
+	dataType string
+}
+
+// schemaDrift describes how the downstream schema of a table differs from
+// the replicated schema.
+type schemaDrift struct {
+	table   model.TableName
+	reasons []string
+}
+
+// schemaDriftDetector compares the schemas of the replicated tables in the
+// owner with the downstream schemas periodically, and warns about the
+// differences, which are usually caused by changes to the downstream not
+// made by the changefeed.
+//
+// The check runs in background, it's started only when there is no DDL being
+// executed, and its result is discarded if a DDL is applied to the schema of
+// the owner before it finishes, so DDLs replicated by the changefeed are never
+// reported as drifts.
+type schemaDriftDetector struct {
+	changefeedID model.ChangeFeedID
+	interval     time.Duration
+	lastCheck    time.Time
+
+	newReader func(ctx context.Context) (downstreamSchemaReader, error)
+	reader    downstreamSchemaReader
+
+	// ddlSeq increases once a DDL is applied to the schema of the owner.
+	ddlSeq  atomic.Uint64
+	running atomic.Bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	metricDriftTables prometheus.Gauge
+}
+
+// newSchemaDriftDetector creates a schemaDriftDetector, nil is returned if the
+// detection is disabled or the sink doesn't support it.
+func newSchemaDriftDetector(
+	changefeedID model.ChangeFeedID, cfg *config.SchemaDriftConfig, sinkURI string,
+) *schemaDriftDetector {
+	if cfg == nil || !cfg.Enable {
+		return nil
+	}
+	if !sink.IsSyncpointSupported(sinkURI) {
+		log.Warn("schema drift detection is not supported by the sink, ignore it",
+			zap.String("changefeed", changefeedID))
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &schemaDriftDetector{
+		changefeedID: changefeedID,
+		interval:     time.Duration(cfg.CheckIntervalInSec) * time.Second,
+		lastCheck:    time.Now(),
+		newReader: func(ctx context.Context) (downstreamSchemaReader, error) {
+			ctx = util.PutChangefeedIDInCtx(ctx, changefeedID)
+			return sink.NewSyncpointStore(ctx, changefeedID, sinkURI)
+		},
+		ctx:               ctx,
+		cancel:            cancel,
+		metricDriftTables: changefeedSchemaDriftTablesGauge.WithLabelValues(changefeedID),
+	}
+}
+
+// onDDLApplied is called once a DDL is applied to the schema of the owner.
+func (d *schemaDriftDetector) onDDLApplied() {
+	d.ddlSeq.Inc()
+}
+
+// tick starts a check in background if it's time to do it.
+func (d *schemaDriftDetector) tick(schema *schemaWrap4Owner, ddlExecuting bool) {
+	if ddlExecuting || d.running.Load() || time.Since(d.lastCheck) < d.interval {
+		return
+	}
+	d.lastCheck = time.Now()
+	expected := expectedTableColumns(schema)
+	seq := d.ddlSeq.Load()
+
+	d.running.Store(true)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer d.running.Store(false)
+		drifts, err := d.check(d.ctx, expected)
+		if err != nil {
+			log.Warn("check downstream schema drift failed",
+				zap.String("changefeed", d.changefeedID), zap.Error(err))
+			return
+		}
+		if d.ddlSeq.Load() != seq {
+			log.Info("discard the schema drift check overlapping with DDLs",
+				zap.String("changefeed", d.changefeedID))
+			return
+		}
+		for _, drift := range drifts {
+			log.Warn("downstream schema drift detected, the downstream table may be changed out-of-band",
+				zap.String("changefeed", d.changefeedID),
+				zap.Stringer("table", drift.table),
+				zap.Strings("reasons", drift.reasons))
+		}
+		d.metricDriftTables.Set(float64(len(drifts)))
+	}()
+}
+
+func (d *schemaDriftDetector) check(
+	ctx context.Context, expected map[model.TableName][]expectedColumn,
+) ([]*schemaDrift, error) {
+	if d.reader == nil {
+		reader, err := d.newReader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		d.reader = reader
+	}
+	schemaSet := make(map[string]struct{})
+	for table := range expected {
+		schemaSet[table.Schema] = struct{}{}
+	}
+	schemas := make([]string, 0, len(schemaSet))
+	for schema := range schemaSet {
+		schemas = append(schemas, schema)
+	}
+	sort.Strings(schemas)
+	actual, err := d.reader.Columns(ctx, schemas)
+	if err != nil {
+		return nil, err
+	}
+	return compareTableColumns(expected, actual), nil
+}
+
+// close stops the running check and releases the resources.
+func (d *schemaDriftDetector) close() {
+	d.cancel()
+	d.wg.Wait()
+	if d.reader != nil {
+		if err := d.reader.Close(); err != nil {
+			log.Warn("close downstream schema reader failed",
+				zap.String("changefeed", d.changefeedID), zap.Error(err))
+		}
+		d.reader = nil
+	}
+	changefeedSchemaDriftTablesGauge.DeleteLabelValues(d.changefeedID)
+}
+
+// expectedTableColumns returns the columns of the replicated tables.
+func expectedTableColumns(schema *schemaWrap4Owner) map[model.TableName][]expectedColumn {
+	tables := schema.schemaSnapshot.Tables()
+	expected := make(map[model.TableName][]expectedColumn, len(tables))
+	for _, tblInfo := range tables {
+		if schema.shouldIgnoreTable(tblInfo) {
+			continue
+		}
+		columns := make([]expectedColumn, 0, len(tblInfo.Columns))
+		for _, col := range tblInfo.Columns {
+			if col.Hidden {
+				continue
+			}
+			columns = append(columns, expectedColumn{
+				name:     col.Name.O,
+				dataType: types.TypeToStr(col.Tp, col.Charset),
+			})
+		}
+		expected[tblInfo.TableName] = columns
+	}
+	return expected
+}
+
+// compareTableColumns returns the drifts of the tables, sorted by table names.
+// Names are compared case-insensitively, as the downstream may be configured
+// with lower_case_table_names.
+func compareTableColumns(
+	expected map[model.TableName][]expectedColumn,
+	actual map[model.TableName][]*sink.DownstreamColumn,
+) []*schemaDrift {
+	lowerName := func(t model.TableName) model.TableName {
+		return model.TableName{Schema: strings.ToLower(t.Schema), Table: strings.ToLower(t.Table)}
+	}
+	actualTables := make(map[model.TableName][]*sink.DownstreamColumn, len(actual))
+	for table, columns := range actual {
+		actualTables[lowerName(table)] = columns
+	}
+
+	var drifts []*schemaDrift
+	for table, columns := range expected {
+		actualColumns, ok := actualTables[lowerName(table)]
+		if !ok {
+			drifts = append(drifts, &schemaDrift{
+				table:   table,
+				reasons: []string{"table doesn't exist in downstream"},
+			})
+			continue
+		}
+		actualTypes := make(map[string]string, len(actualColumns))
+		for _, col := range actualColumns {
+			actualTypes[strings.ToLower(col.Name)] = strings.ToLower(col.DataType)
+		}
+		var reasons []string
+		for _, col := range columns {
+			name := strings.ToLower(col.name)
+			dataType, ok := actualTypes[name]
+			if !ok {
+				reasons = append(reasons, fmt.Sprintf("column %s doesn't exist in downstream", col.name))
+				continue
+			}
+			delete(actualTypes, name)
+			if dataType != col.dataType {
+				reasons = append(reasons, fmt.Sprintf(
+					"column %s is %s in downstream, %s is expected", col.name, dataType, col.dataType))
+			}
+		}
+		for _, col := range actualColumns {
+			if _, ok := actualTypes[strings.ToLower(col.Name)]; ok {
+				reasons = append(reasons, fmt.Sprintf("column %s only exists in downstream", col.Name))
+			}
+		}
+		if len(reasons) > 0 {
+			drifts = append(drifts, &schemaDrift{table: table, reasons: reasons})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].table.Schema != drifts[j].table.Schema {
+			return drifts[i].table.Schema < drifts[j].table.Schema
+		}
+		return drifts[i].table.Table < drifts[j].table.Table
+	})
+	return drifts
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type mockDownstreamSchemaReader struct {
+	columns map[model.TableName][]*sink.DownstreamColumn
+	// block is closed to let Columns return
+	block chan struct{}
+}
+
+func (r *mockDownstreamSchemaReader) Columns(
+	ctx context.Context, schemas []string,
+) (map[model.TableName][]*sink.DownstreamColumn, error) {
+	if r.block != nil {
+		<-r.block
+	}
+	return r.columns, nil
+}
+
+func (r *mockDownstreamSchemaReader) Close() error {
+	return nil
+}
+
+func TestCompareTableColumns(t *testing.T) {
+	t.Parallel()
+
+	expected := map[model.TableName][]expectedColumn{
+		{Schema: "test", Table: "T1"}: {{"id", "int"}, {"Name", "varchar"}},
+		{Schema: "test", Table: "t2"}: {{"id", "int"}, {"v", "bigint"}},
+		{Schema: "test", Table: "t3"}: {{"id", "int"}},
+	}
+	actual := map[model.TableName][]*sink.DownstreamColumn{
+		{Schema: "test", Table: "t1"}: {{Name: "id", DataType: "int"}, {Name: "name", DataType: "VARCHAR"}},
+		{Schema: "test", Table: "t2"}: {{Name: "id", DataType: "int"}, {Name: "v", DataType: "int"}, {Name: "extra", DataType: "text"}},
+	}
+	drifts := compareTableColumns(expected, actual)
+	require.Equal(t, []*schemaDrift{
+		{
+			table: model.TableName{Schema: "test", Table: "t2"},
+			reasons: []string{
+				"column v is int in downstream, bigint is expected",
+				"column extra only exists in downstream",
+			},
+		},
+		{
+			table:   model.TableName{Schema: "test", Table: "t3"},
+			reasons: []string{"table doesn't exist in downstream"},
+		},
+	}, drifts)
+}
+
+func TestSchemaDriftDetector(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	schema, err := newSchemaWrap4Owner(helper.Storage(), ver.Ver,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+	require.Nil(t, schema.HandleDDL(helper.DDL2Job("create table test.t1(id int primary key, v varchar(10))")))
+
+	cfg := &config.SchemaDriftConfig{Enable: true, CheckIntervalInSec: 1}
+	require.Nil(t, newSchemaDriftDetector(dummyChangeFeedID, cfg, "kafka://127.0.0.1:9092/topic"))
+	require.Nil(t, newSchemaDriftDetector(dummyChangeFeedID, &config.SchemaDriftConfig{}, "mysql://127.0.0.1:3306/"))
+	d := newSchemaDriftDetector(dummyChangeFeedID, cfg, "mysql://127.0.0.1:3306/")
+	require.NotNil(t, d)
+	defer d.close()
+	reader := &mockDownstreamSchemaReader{
+		columns: map[model.TableName][]*sink.DownstreamColumn{
+			{Schema: "test", Table: "t1"}: {{Name: "id", DataType: "int"}},
+		},
+	}
+	d.newReader = func(ctx context.Context) (downstreamSchemaReader, error) {
+		return reader, nil
+	}
+	metric := changefeedSchemaDriftTablesGauge.WithLabelValues(dummyChangeFeedID)
+
+	// not checked before the interval passes or when a DDL is executing.
+	d.tick(schema, false)
+	require.False(t, d.running.Load())
+	d.lastCheck = time.Now().Add(-time.Second)
+	d.tick(schema, true)
+	require.False(t, d.running.Load())
+
+	d.tick(schema, false)
+	require.Eventually(t, func() bool {
+		return !d.running.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, float64(1), testutil.ToFloat64(metric))
+
+	// the check overlapping with a DDL is discarded.
+	reader.columns[model.TableName{Schema: "test", Table: "t1"}] = []*sink.DownstreamColumn{
+		{Name: "id", DataType: "int"}, {Name: "v", DataType: "varchar"},
+	}
+	reader.block = make(chan struct{})
+	d.lastCheck = time.Now().Add(-time.Second)
+	d.tick(schema, false)
+	require.True(t, d.running.Load())
+	d.onDDLApplied()
+	close(reader.block)
+	require.Eventually(t, func() bool {
+		return !d.running.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, float64(1), testutil.ToFloat64(metric))
+
+	d.lastCheck = time.Now().Add(-time.Second)
+	d.tick(schema, false)
+	require.Eventually(t, func() bool {
+		return !d.running.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, float64(0), testutil.ToFloat64(metric))
+}
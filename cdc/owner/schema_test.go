@@ -95,6 +95,67 @@ func TestAllTableNames(t *testing.T) {
 	require.Equal(t, []model.TableName{{Schema: "test", Table: "t1"}}, schema.AllTableNames())
 }
 
+func TestBuildSchemaSnapshot(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	schema, err := newSchemaWrap4Owner(helper.Storage(), ver.Ver,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+	snap := schema.BuildSchemaSnapshot()
+	require.Equal(t, ver.Ver, snap.Ts)
+	require.Len(t, snap.Tables, 0)
+
+	job := helper.DDL2Job("create table test.t1(id int primary key)")
+	require.Nil(t, schema.HandleDDL(job))
+	// the ineligible table is not in the snapshot
+	require.Nil(t, schema.HandleDDL(helper.DDL2Job("create table test.t2(id int)")))
+	snap = schema.BuildSchemaSnapshot()
+	require.Equal(t, schema.ddlHandledTs, snap.Ts)
+	require.Len(t, snap.Tables, 1)
+	require.Equal(t, "test", snap.Tables[0].Schema)
+	require.Equal(t, "t1", snap.Tables[0].Table)
+	require.Equal(t, job.BinlogInfo.TableInfo.ID, snap.Tables[0].TableInfo.ID)
+}
+
+func TestFreezeTables(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	schema, err := newSchemaWrap4Owner(helper.Storage(), ver.Ver,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+	job := helper.DDL2Job("create table test.t1(id int primary key)")
+	require.Nil(t, schema.HandleDDL(job))
+	schema.FreezeTables([]model.TableName{{Schema: "test", Table: "t1"}, {Schema: "test", Table: "t3"}})
+	// the table created later is not replicated unless it's approved
+	job = helper.DDL2Job("create table test.t2(id int primary key)")
+	require.Nil(t, schema.HandleDDL(job))
+	tableIDT2 := job.BinlogInfo.TableInfo.ID
+	require.Equal(t, []model.TableName{{Schema: "test", Table: "t1"}}, schema.AllTableNames())
+	require.Len(t, schema.AllPhysicalTables(), 1)
+	require.True(t, schema.IsFrozenOutTable(tableIDT2))
+
+	// the approved table is tracked by ID after it's renamed
+	job = helper.DDL2Job("rename table test.t1 to test.t4")
+	require.Nil(t, schema.HandleDDL(job))
+	require.Equal(t, []model.TableName{{Schema: "test", Table: "t4"}}, schema.AllTableNames())
+	require.Equal(t, []model.TableName{
+		{Schema: "test", Table: "t4"}, {Schema: "test", Table: "t3"},
+	}, schema.FrozenTableNames())
+	// and after it's truncated
+	job = helper.DDL2Job("truncate table test.t4")
+	require.Nil(t, schema.HandleDDL(job))
+	require.False(t, schema.IsFrozenOutTable(job.BinlogInfo.TableInfo.ID))
+	require.Equal(t, []model.TableName{{Schema: "test", Table: "t4"}}, schema.AllTableNames())
+	// a new table reusing the name of the renamed one is not approved
+	job = helper.DDL2Job("create table test.t1(id int primary key)")
+	require.Nil(t, schema.HandleDDL(job))
+	require.True(t, schema.IsFrozenOutTable(job.BinlogInfo.TableInfo.ID))
+}
+
 func TestIsIneligibleTableID(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -48,6 +48,22 @@ type StatusProvider interface {
 
 	// GetCaptures returns the information about all captures.
 	GetCaptures(ctx context.Context) ([]*model.CaptureInfo, error)
+
+	// GetChangeFeedBarrier returns the minimal barrier of a changefeed.
+	GetChangeFeedBarrier(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedBarrier, error)
+
+	// GetTableDiagnoses returns the diagnoses of the tables of a changefeed
+	// which are not running or keep bouncing.
+	GetTableDiagnoses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableDiagnosis, error)
+
+	// RequestConsistencyReport requests a consistency report of a changefeed.
+	RequestConsistencyReport(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ConsistencyReport, error)
+
+	// GetConsistencyReport returns the latest consistency report of a changefeed.
+	GetConsistencyReport(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ConsistencyReport, error)
+
+	// GetChangefeedHealth returns the health of a changefeed judged by the health check.
+	GetChangefeedHealth(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangefeedHealth, error)
 }
 
 // QueryType is the type of different queries.
@@ -66,6 +82,16 @@ const (
 	QueryProcessors
 	// QueryCaptures is the type of query captures info.
 	QueryCaptures
+	// QueryChangeFeedBarrier is the type of query the minimal barrier of a changefeed.
+	QueryChangeFeedBarrier
+	// QueryRequestConsistencyReport is the type of request a consistency report of a changefeed.
+	QueryRequestConsistencyReport
+	// QueryConsistencyReport is the type of query the latest consistency report of a changefeed.
+	QueryConsistencyReport
+	// QueryTableDiagnoses is the type of query the table diagnoses of a changefeed.
+	QueryTableDiagnoses
+	// QueryChangefeedHealth is the type of query the health of a changefeed.
+	QueryChangefeedHealth
 )
 
 // Query wraps query command and return results.
@@ -171,6 +197,61 @@ func (p *ownerStatusProvider) GetCaptures(ctx context.Context) ([]*model.Capture
 	return query.Data.([]*model.CaptureInfo), nil
 }
 
+func (p *ownerStatusProvider) GetChangeFeedBarrier(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedBarrier, error) {
+	query := &Query{
+		Tp:           QueryChangeFeedBarrier,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.(*model.ChangeFeedBarrier), nil
+}
+
+func (p *ownerStatusProvider) GetTableDiagnoses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableDiagnosis, error) {
+	query := &Query{
+		Tp:           QueryTableDiagnoses,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.([]*model.TableDiagnosis), nil
+}
+
+func (p *ownerStatusProvider) RequestConsistencyReport(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ConsistencyReport, error) {
+	query := &Query{
+		Tp:           QueryRequestConsistencyReport,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.(*model.ConsistencyReport), nil
+}
+
+func (p *ownerStatusProvider) GetConsistencyReport(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ConsistencyReport, error) {
+	query := &Query{
+		Tp:           QueryConsistencyReport,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.(*model.ConsistencyReport), nil
+}
+
+func (p *ownerStatusProvider) GetChangefeedHealth(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangefeedHealth, error) {
+	query := &Query{
+		Tp:           QueryChangefeedHealth,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.(*model.ChangefeedHealth), nil
+}
+
 func (p *ownerStatusProvider) sendQueryToOwner(ctx context.Context, query *Query) error {
 	doneCh := make(chan error, 1)
 	p.owner.Query(query, doneCh)
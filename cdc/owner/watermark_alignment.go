@@ -0,0 +1,55 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/orchestrator"
+)
+
+// watermarkGroup returns the watermark group of the changefeed, it's empty if
+// the watermark of the changefeed is not aligned.
+func watermarkGroup(info *model.ChangeFeedInfo) string {
+	if info == nil || info.Config == nil || !info.Config.WatermarkAlignment.IsEnabled() {
+		return ""
+	}
+	return info.Config.WatermarkAlignment.Group
+}
+
+// alignWatermarks returns the minimal checkpoint ts of the changefeeds of each
+// watermark group, the changefeeds of a group emit the minimal checkpoint ts
+// instead of their own ones, so they advance the downstream watermark together.
+//
+// The removed and finished changefeeds leave their groups, while a stopped or
+// failed changefeed holds back the watermark of its group until it's resumed
+// or removed, since the watermark must not pass the events it doesn't emit.
+func alignWatermarks(
+	changefeeds map[model.ChangeFeedID]*orchestrator.ChangefeedReactorState,
+) map[string]model.Ts {
+	aligned := make(map[string]model.Ts)
+	for _, state := range changefeeds {
+		group := watermarkGroup(state.Info)
+		if group == "" {
+			continue
+		}
+		if state.Info.State == model.StateRemoved || state.Info.State == model.StateFinished {
+			continue
+		}
+		checkpointTs := state.Info.GetCheckpointTs(state.Status)
+		if ts, ok := aligned[group]; !ok || checkpointTs < ts {
+			aligned[group] = checkpointTs
+		}
+	}
+	return aligned
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owner
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignWatermarks(t *testing.T) {
+	t.Parallel()
+
+	changefeed := func(
+		group string, state model.FeedState, status *model.ChangeFeedStatus,
+	) *orchestrator.ChangefeedReactorState {
+		cfg := config.GetDefaultReplicaConfig()
+		cfg.WatermarkAlignment.Group = group
+		return &orchestrator.ChangefeedReactorState{
+			Info:   &model.ChangeFeedInfo{StartTs: 50, State: state, Config: cfg},
+			Status: status,
+		}
+	}
+	changefeeds := map[model.ChangeFeedID]*orchestrator.ChangefeedReactorState{
+		"cf1": changefeed("g1", model.StateNormal, &model.ChangeFeedStatus{CheckpointTs: 100}),
+		"cf2": changefeed("g1", model.StateNormal, &model.ChangeFeedStatus{CheckpointTs: 200}),
+		// the stopped changefeed holds back its group.
+		"cf3": changefeed("g2", model.StateStopped, &model.ChangeFeedStatus{CheckpointTs: 300}),
+		"cf4": changefeed("g2", model.StateNormal, &model.ChangeFeedStatus{CheckpointTs: 400}),
+		// the finished and removed changefeeds leave their group.
+		"cf5": changefeed("g1", model.StateFinished, &model.ChangeFeedStatus{CheckpointTs: 10}),
+		"cf6": changefeed("g2", model.StateRemoved, &model.ChangeFeedStatus{CheckpointTs: 10}),
+		// the start ts is used before the status is initialized.
+		"cf7": changefeed("g3", model.StateNormal, nil),
+		// the watermark of the changefeed is not aligned.
+		"cf8": changefeed("", model.StateNormal, &model.ChangeFeedStatus{CheckpointTs: 1}),
+	}
+	changefeeds["cf9"] = changefeed("", model.StateNormal, nil)
+	changefeeds["cf9"].Info.Config.WatermarkAlignment = nil
+	changefeeds["cf10"] = &orchestrator.ChangefeedReactorState{}
+
+	require.Equal(t, map[string]model.Ts{
+		"g1": 100,
+		"g2": 300,
+		"g3": 50,
+	}, alignWatermarks(changefeeds))
+	require.Equal(t, "g1", watermarkGroup(changefeeds["cf1"].Info))
+	require.Equal(t, "", watermarkGroup(changefeeds["cf9"].Info))
+	require.Equal(t, "", watermarkGroup(nil))
+}
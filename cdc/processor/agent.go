@@ -191,6 +191,23 @@ func (a *agentImpl) FinishTableOperation(
 	ctx context.Context,
 	tableID model.TableID,
 	epoch model.ProcessorEpoch,
+) (done bool, err error) {
+	message := &model.DispatchTableResponseMessage{ID: tableID, Epoch: epoch}
+	return a.sendDispatchTableResponse(ctx, message)
+}
+
+func (a *agentImpl) FinishPrepareTable(
+	ctx context.Context,
+	tableID model.TableID,
+	epoch model.ProcessorEpoch,
+) (done bool, err error) {
+	message := &model.DispatchTableResponseMessage{ID: tableID, Epoch: epoch, IsPrepare: true}
+	return a.sendDispatchTableResponse(ctx, message)
+}
+
+func (a *agentImpl) sendDispatchTableResponse(
+	ctx context.Context,
+	message *model.DispatchTableResponseMessage,
 ) (done bool, err error) {
 	topic := model.SyncTopic(a.changeFeed)
 	if !a.Barrier(ctx) {
@@ -198,13 +215,12 @@ func (a *agentImpl) FinishTableOperation(
 			log.L().Info("Delay sending FinishTableOperation due to pending sync",
 				zap.String("changefeedID", a.changeFeed),
 				zap.String("ownerID", a.ownerCaptureID),
-				zap.Int64("tableID", tableID),
-				zap.String("epoch", epoch))
+				zap.Int64("tableID", message.ID),
+				zap.String("epoch", message.Epoch))
 			return false, nil
 		}
 	}
 
-	message := &model.DispatchTableResponseMessage{ID: tableID, Epoch: epoch}
 	defer func() {
 		if err != nil {
 			return
@@ -441,6 +457,14 @@ func (a *agentImpl) registerPeerMessageHandlers() (ret error) {
 		func(sender string, value interface{}) error {
 			ownerCapture := sender
 			message := value.(*model.DispatchTableMessage)
+			if message.IsPrepare {
+				a.OnOwnerPreparedTask(
+					ownerCapture,
+					message.OwnerRev,
+					message.ID,
+					message.Epoch)
+				return nil
+			}
 			a.OnOwnerDispatchedTask(
 				ownerCapture,
 				message.OwnerRev,
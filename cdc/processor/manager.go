@@ -37,6 +37,8 @@ const (
 	commandTpUnknow commandTp = iota //nolint:varcheck,deadcode
 	commandTpClose
 	commandTpWriteDebugInfo
+	commandTpQueryTableDiagnostics
+	commandTpQueryRedoResolvedTs
 	processorLogsWarnDuration = 1 * time.Second
 )
 
@@ -46,6 +48,23 @@ type command struct {
 	done    chan<- error
 }
 
+// tableDiagnosticsQuery is the payload of commandTpQueryTableDiagnostics.
+type tableDiagnosticsQuery struct {
+	changefeedID model.ChangeFeedID
+	tableID      model.TableID
+
+	result *model.TablePipelineDiagnostics
+	err    error
+}
+
+// redoResolvedTsQuery is the payload of commandTpQueryRedoResolvedTs.
+type redoResolvedTsQuery struct {
+	changefeedID model.ChangeFeedID
+
+	result *model.RedoResolvedTs
+	err    error
+}
+
 // Manager is a manager of processor, which maintains the state and behavior of processors
 type Manager struct {
 	processors map[model.ChangeFeedID]*processor
@@ -174,6 +193,48 @@ func (m *Manager) WriteDebugInfo(
 	}
 }
 
+// QueryTableDiagnostics returns the diagnostics of a table of a changefeed
+// replicated by the processor of the capture.
+func (m *Manager) QueryTableDiagnostics(
+	ctx context.Context, changefeedID model.ChangeFeedID, tableID model.TableID,
+) (*model.TablePipelineDiagnostics, error) {
+	query := &tableDiagnosticsQuery{changefeedID: changefeedID, tableID: tableID}
+	done := make(chan error, 1)
+	if err := m.sendCommand(ctx, commandTpQueryTableDiagnostics, query, done); err != nil {
+		return nil, errors.Trace(err)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case <-done:
+	}
+	if query.err != nil {
+		return nil, query.err
+	}
+	return query.result, nil
+}
+
+// QueryRedoResolvedTs returns the resolved ts of the redo logs of the tables
+// of a changefeed replicated by the processor of the capture.
+func (m *Manager) QueryRedoResolvedTs(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+) (*model.RedoResolvedTs, error) {
+	query := &redoResolvedTsQuery{changefeedID: changefeedID}
+	done := make(chan error, 1)
+	if err := m.sendCommand(ctx, commandTpQueryRedoResolvedTs, query, done); err != nil {
+		return nil, errors.Trace(err)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	case <-done:
+	}
+	if query.err != nil {
+		return nil, query.err
+	}
+	return query.result, nil
+}
+
 // sendCommands sends command to manager.
 // `done` is closed upon command completion or sendCommand returns error.
 func (m *Manager) sendCommand(
@@ -208,6 +269,12 @@ func (m *Manager) handleCommand() error {
 	case commandTpWriteDebugInfo:
 		w := cmd.payload.(io.Writer)
 		m.writeDebugInfo(w)
+	case commandTpQueryTableDiagnostics:
+		query := cmd.payload.(*tableDiagnosticsQuery)
+		m.queryTableDiagnostics(query)
+	case commandTpQueryRedoResolvedTs:
+		query := cmd.payload.(*redoResolvedTsQuery)
+		m.queryRedoResolvedTs(query)
 	default:
 		log.Warn("Unknown command in processor manager", zap.Any("command", cmd))
 	}
@@ -221,3 +288,29 @@ func (m *Manager) writeDebugInfo(w io.Writer) {
 		fmt.Fprintf(w, "\n")
 	}
 }
+
+func (m *Manager) queryTableDiagnostics(query *tableDiagnosticsQuery) {
+	if processor, ok := m.processors[query.changefeedID]; ok {
+		if diagnostics, ok := processor.tableDiagnostics(query.tableID); ok {
+			query.result = diagnostics
+			return
+		}
+	}
+	query.err = cerrors.ErrProcessorTableNotFound.GenWithStack(
+		"table(%d) of changefeed(%s)", query.tableID, query.changefeedID)
+}
+
+func (m *Manager) queryRedoResolvedTs(query *redoResolvedTsQuery) {
+	processor, ok := m.processors[query.changefeedID]
+	if !ok {
+		// the capture doesn't replicate any table of the changefeed
+		query.result = &model.RedoResolvedTs{}
+		return
+	}
+	result, ok := processor.redoResolvedTs()
+	if !ok {
+		query.err = cerrors.ErrRedoNotEnabled.GenWithStackByArgs(query.changefeedID)
+		return
+	}
+	query.result = result
+}
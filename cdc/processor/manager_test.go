@@ -176,6 +176,20 @@ func TestDebugInfo(t *testing.T) {
 	s.manager.WriteDebugInfo(ctx, buf, doneM)
 	<-doneM
 	require.Greater(t, len(buf.String()), 0)
+
+	var diagnostics *model.TablePipelineDiagnostics
+	require.Eventually(t, func() bool {
+		var queryErr error
+		diagnostics, queryErr = s.manager.QueryTableDiagnostics(ctx, "test-changefeed", 1)
+		return queryErr == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, model.TableID(1), diagnostics.TableID)
+	require.Equal(t, "test-changefeed", diagnostics.ChangefeedID)
+	require.Equal(t, ctx.GlobalVars().CaptureInfo.ID, diagnostics.CaptureID)
+	_, queryErr := s.manager.QueryTableDiagnostics(ctx, "test-changefeed", 2)
+	require.True(t, cerrors.ErrProcessorTableNotFound.Equal(queryErr))
+	_, queryErr = s.manager.QueryTableDiagnostics(ctx, "non-exist-changefeed", 1)
+	require.True(t, cerrors.ErrProcessorTableNotFound.Equal(queryErr))
 	s.manager.AsyncClose()
 	<-done
 }
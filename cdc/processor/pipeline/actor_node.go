@@ -15,26 +15,51 @@ package pipeline
 
 import (
 	"context"
+	"time"
 
 	"github.com/pingcap/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Types of actor nodes, they are used as the labels of metrics.
+const (
+	actorNodeTypeCyclic = "cyclic"
+	actorNodeTypeSink   = "sink"
 )
 
 // ActorNode is an async message process node, it fetches and handle table message non-blocking
 // if processing is blocked, the message will be cached and wait next run
 type ActorNode struct {
-	messageStash     *pmessage.Message
+	messageStash *pmessage.Message
+	// stashedAt is the time when handling messageStash is blocked at the first
+	// time, it's zero if messageStash is not blocked.
+	stashedAt        time.Time
 	parentNode       AsyncMessageHolder
 	messageProcessor AsyncMessageProcessor
+	// hasMore is true if the last TryRun returned because the batch is
+	// full, there may be more messages in the parent node.
+	hasMore bool
+
+	metricStashedMessageCount  prometheus.Counter
+	metricStashedMessageGauge  prometheus.Gauge
+	metricRequeueCount         prometheus.Counter
+	metricStashDurationHistory prometheus.Observer
 }
 
 // NewActorNode create a new ActorNode
 func NewActorNode(
+	changefeedID string, nodeType string,
 	parentNode AsyncMessageHolder, messageProcessor AsyncMessageProcessor,
 ) *ActorNode {
 	return &ActorNode{
 		parentNode:       parentNode,
 		messageProcessor: messageProcessor,
+
+		metricStashedMessageCount:  actorNodeStashedMessageCount.WithLabelValues(changefeedID, nodeType),
+		metricStashedMessageGauge:  actorNodeStashedMessageGauge.WithLabelValues(changefeedID, nodeType),
+		metricRequeueCount:         actorNodeRequeueCount.WithLabelValues(changefeedID, nodeType),
+		metricStashDurationHistory: actorNodeStashDuration.WithLabelValues(changefeedID, nodeType),
 	}
 }
 
@@ -42,6 +67,7 @@ func NewActorNode(
 //  or message handling is blocking
 // only one message will be cached
 func (n *ActorNode) TryRun(ctx context.Context) error {
+	n.hasMore = false
 	processedCount := 0
 	for {
 		// batch?
@@ -51,6 +77,9 @@ func (n *ActorNode) TryRun(ctx context.Context) error {
 		if n.messageStash == nil {
 			return nil
 		}
+		if !n.stashedAt.IsZero() {
+			n.metricRequeueCount.Inc()
+		}
 		ok, err := n.messageProcessor.TryHandleDataMessage(ctx, *n.messageStash)
 		// process message failed, stop table actor
 		if err != nil {
@@ -59,19 +88,40 @@ func (n *ActorNode) TryRun(ctx context.Context) error {
 
 		// node is blocked
 		if !ok {
+			if n.stashedAt.IsZero() {
+				n.stashedAt = time.Now()
+				n.metricStashedMessageCount.Inc()
+				n.metricStashedMessageGauge.Inc()
+			}
 			return nil
 		}
 
+		if !n.stashedAt.IsZero() {
+			n.metricStashDurationHistory.Observe(time.Since(n.stashedAt).Seconds())
+			n.metricStashedMessageGauge.Dec()
+			n.stashedAt = time.Time{}
+		}
 		n.messageStash = nil
 		processedCount++
 		// processed too many messages may consume more than 1 second,
 		// return here to allow actor system poll other tables, and avoid dead loop
 		if processedCount >= defaultOutputChannelSize {
+			n.hasMore = true
 			return nil
 		}
 	}
 }
 
+// releaseResource releases the stashed message if it's blocked, the node
+// can't be used any more after it's called.
+func (n *ActorNode) releaseResource() {
+	if !n.stashedAt.IsZero() {
+		n.metricStashedMessageGauge.Dec()
+		n.stashedAt = time.Time{}
+	}
+	n.messageStash = nil
+}
+
 // AsyncMessageProcessor is an interface to handle message non-blocking
 type AsyncMessageProcessor interface {
 	TryHandleDataMessage(ctx context.Context, msg pmessage.Message) (bool, error)
@@ -18,6 +18,7 @@ import (
 	"sync/atomic"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	"github.com/pingcap/tiflow/pkg/actor"
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -29,6 +30,41 @@ import (
 // defaultEventBatchSize specifies that if we get 32 pipeline events, a tick message is sent to the actor.
 const defaultEventBatchSize = uint32(32)
 
+// actorTicker sends Tick messages to a table actor. The Ticks are coalesced,
+// i.e. no Tick is sent if there is one not handled by the table actor yet, so
+// the nodes and the puller of a table don't flood the mailbox with Ticks.
+type actorTicker struct {
+	router  *system.Router
+	actorID actor.ID
+	// pending is 1 if a Tick has been sent but not handled yet.
+	pending int32
+}
+
+func newActorTicker(router *system.Router, actorID actor.ID) *actorTicker {
+	return &actorTicker{router: router, actorID: actorID}
+}
+
+// tick sends a Tick to the table actor if there is no pending one.
+func (t *actorTicker) tick() {
+	if t == nil || !atomic.CompareAndSwapInt32(&t.pending, 0, 1) {
+		return
+	}
+	if err := t.router.Send(t.actorID, message.ValueMessage(pmessage.TickMessage())); err != nil {
+		// The mailbox is full or the actor is stopped. The actor will be
+		// polled anyway if the mailbox is full, so it's fine to retry with
+		// the next Tick.
+		atomic.StoreInt32(&t.pending, 0)
+	}
+}
+
+// reset is called by the table actor before it handles the messages, so the
+// Ticks sent since then are not coalesced with the handled ones.
+func (t *actorTicker) reset() {
+	if t != nil {
+		atomic.StoreInt32(&t.pending, 0)
+	}
+}
+
 // actorNodeContext implements the NodeContext interface, with this we do not need
 // to change too much logic to implement the table actor.
 // the SendToNextNode buffer the pipeline message and tick the actor system
@@ -36,11 +72,13 @@ const defaultEventBatchSize = uint32(32)
 type actorNodeContext struct {
 	sdtContext.Context
 	outputCh         chan pmessage.Message
-	tableActorRouter *actor.Router[pmessage.Message]
+	tableActorRouter *system.Router
 	tableActorID     actor.ID
-	changefeedVars   *context.ChangefeedVars
-	globalVars       *context.GlobalVars
-	eventBatchSize   uint32
+	// ticker may be shared by the contexts of a table actor.
+	ticker         *actorTicker
+	changefeedVars *context.ChangefeedVars
+	globalVars     *context.GlobalVars
+	eventBatchSize uint32
 	// eventCount is the count of pipeline event that no tick message is sent to actor
 	eventCount uint32
 	tableName  string
@@ -49,7 +87,7 @@ type actorNodeContext struct {
 
 func newContext(stdCtx sdtContext.Context,
 	tableName string,
-	tableActorRouter *actor.Router[pmessage.Message],
+	tableActorRouter *system.Router,
 	tableActorID actor.ID,
 	changefeedVars *context.ChangefeedVars,
 	globalVars *context.GlobalVars,
@@ -64,6 +102,7 @@ func newContext(stdCtx sdtContext.Context,
 		outputCh:         make(chan pmessage.Message, defaultOutputChannelSize),
 		tableActorRouter: tableActorRouter,
 		tableActorID:     tableActorID,
+		ticker:           newActorTicker(tableActorRouter, tableActorID),
 		changefeedVars:   changefeedVars,
 		globalVars:       globalVars,
 		eventBatchSize:   batchSize,
@@ -140,7 +179,7 @@ func (c *actorNodeContext) trySendTickMessage() {
 	count := atomic.LoadUint32(&c.eventCount)
 	// resolvedTs event will be sent by puller periodically
 	if count >= threshold {
-		_ = c.tableActorRouter.Send(c.tableActorID, message.ValueMessage(pmessage.TickMessage()))
+		c.ticker.tick()
 		atomic.StoreUint32(&c.eventCount, 0)
 	}
 }
@@ -89,7 +89,7 @@ func TestSendToNextNodeNoTickMessage(t *testing.T) {
 	mb := actor.NewMailbox[pmessage.Message](actorID, defaultOutputChannelSize)
 	ch := make(chan message.Message[pmessage.Message], defaultOutputChannelSize)
 	fa := &forwardActor{ch: ch}
-	require.Nil(t, sys.System().Spawn(mb, fa))
+	require.Nil(t, sys.Spawn(mb, fa))
 	actorContext := newContext(ctx, t.Name(), sys.Router(), actorID, &context.ChangefeedVars{ID: "abc"}, &context.GlobalVars{}, throwDoNothing)
 	actorContext.setEventBatchSize(2)
 	actorContext.SendToNextNode(pmessage.BarrierMessage(1))
@@ -108,6 +108,51 @@ func TestSendToNextNodeNoTickMessage(t *testing.T) {
 	require.Equal(t, 0, len(ch))
 }
 
+func TestActorTickerCoalesce(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := sdtContext.WithCancel(sdtContext.TODO())
+	sys := system.NewSystem()
+	defer func() {
+		cancel()
+		sys.Stop()
+	}()
+
+	require.Nil(t, sys.Start(ctx))
+	actorID := sys.ActorID()
+	mb := actor.NewMailbox[pmessage.Message](actorID, defaultOutputChannelSize)
+	ch := make(chan message.Message[pmessage.Message], defaultOutputChannelSize)
+	fa := &forwardActor{ch: ch}
+	require.Nil(t, sys.Spawn(mb, fa))
+
+	ticker := newActorTicker(sys.Router(), actorID)
+	for i := 0; i < 10; i++ {
+		ticker.tick()
+	}
+	select {
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timeout")
+	case m := <-ch:
+		require.Equal(t, pmessage.MessageTypeTick, m.Value.Tp)
+	}
+	// the Ticks are coalesced until the actor resets the ticker
+	ticker.tick()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 0, len(ch))
+	ticker.reset()
+	ticker.tick()
+	select {
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timeout")
+	case m := <-ch:
+		require.Equal(t, pmessage.MessageTypeTick, m.Value.Tp)
+	}
+
+	// a nil ticker is a no-op
+	var nilTicker *actorTicker
+	nilTicker.tick()
+	nilTicker.reset()
+}
+
 type forwardActor struct {
 	contextAware bool
 
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,7 +33,7 @@ func TestTryRun(t *testing.T) {
 	) (bool, error) {
 		return false, errors.New("error")
 	}
-	n := NewActorNode(pN, dp)
+	n := NewActorNode("changefeed", actorNodeTypeSink, pN, dp)
 	require.Nil(t, n.TryRun(context.TODO()))
 	require.Nil(t, n.messageStash)
 	// process failed
@@ -42,7 +43,7 @@ func TestTryRun(t *testing.T) {
 			BarrierTs: 1,
 		}
 	}
-	n = NewActorNode(pN, dp)
+	n = NewActorNode("changefeed", actorNodeTypeSink, pN, dp)
 	require.NotNil(t, n.TryRun(context.TODO()))
 	require.NotNil(t, n.messageStash)
 	require.Equal(t, pmessage.MessageTypeBarrier, n.messageStash.Tp)
@@ -70,7 +71,7 @@ func TestTryRun(t *testing.T) {
 			BarrierTs: 1,
 		}
 	}
-	n = NewActorNode(pN, dp)
+	n = NewActorNode("changefeed", actorNodeTypeSink, pN, dp)
 	n.parentNode = pN
 	n.messageProcessor = dp
 	require.Nil(t, n.TryRun(context.TODO()))
@@ -93,7 +94,68 @@ func TestTryRunLimited(t *testing.T) {
 		processedCount++
 		return true, nil
 	}
-	n := NewActorNode(pN, dp)
+	n := NewActorNode("changefeed", actorNodeTypeSink, pN, dp)
 	require.Nil(t, n.TryRun(context.TODO()))
 	require.Equal(t, defaultOutputChannelSize, processedCount)
+	require.True(t, n.hasMore)
+
+	// the parent node is drained
+	pN = func() *pmessage.Message { return nil }
+	n.parentNode = pN
+	require.Nil(t, n.TryRun(context.TODO()))
+	require.False(t, n.hasMore)
+}
+
+func TestTryRunStashMetrics(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := "changefeed-stash-metrics"
+	var pN asyncMessageHolderFunc = func() *pmessage.Message {
+		return &pmessage.Message{
+			Tp:        pmessage.MessageTypeBarrier,
+			BarrierTs: 1,
+		}
+	}
+	blocked := true
+	var dp asyncMessageProcessorFunc = func(
+		ctx context.Context, msg pmessage.Message,
+	) (bool, error) {
+		if blocked {
+			return false, nil
+		}
+		// block the next message once this one is handled
+		blocked = true
+		return true, nil
+	}
+	n := NewActorNode(changefeedID, actorNodeTypeCyclic, pN, dp)
+	stashed := actorNodeStashedMessageCount.WithLabelValues(changefeedID, actorNodeTypeCyclic)
+	stashing := actorNodeStashedMessageGauge.WithLabelValues(changefeedID, actorNodeTypeCyclic)
+	requeue := actorNodeRequeueCount.WithLabelValues(changefeedID, actorNodeTypeCyclic)
+
+	// the message is stashed
+	require.Nil(t, n.TryRun(context.TODO()))
+	require.False(t, n.stashedAt.IsZero())
+	require.Equal(t, float64(1), testutil.ToFloat64(stashed))
+	require.Equal(t, float64(1), testutil.ToFloat64(stashing))
+	require.Equal(t, float64(0), testutil.ToFloat64(requeue))
+
+	// the stashed message is still blocked
+	require.Nil(t, n.TryRun(context.TODO()))
+	require.Equal(t, float64(1), testutil.ToFloat64(stashed))
+	require.Equal(t, float64(1), testutil.ToFloat64(requeue))
+
+	// the stashed message is handled and the next one is stashed
+	blocked = false
+	require.Nil(t, n.TryRun(context.TODO()))
+	require.Equal(t, float64(2), testutil.ToFloat64(stashed))
+	require.Equal(t, float64(1), testutil.ToFloat64(stashing))
+	require.Equal(t, float64(2), testutil.ToFloat64(requeue))
+
+	n.releaseResource()
+	require.True(t, n.stashedAt.IsZero())
+	require.Nil(t, n.messageStash)
+	require.Equal(t, float64(0), testutil.ToFloat64(stashing))
+	// it's idempotent
+	n.releaseResource()
+	require.Equal(t, float64(0), testutil.ToFloat64(stashing))
 }
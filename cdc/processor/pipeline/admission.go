@@ -0,0 +1,253 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"go.uber.org/zap"
+)
+
+const (
+	admissionCheckInterval = 50 * time.Millisecond
+	// admissionRateWindow is the window the ingest and drain rates are measured in.
+	admissionRateWindow = time.Second
+)
+
+// Admission levels of the table priorities, a table is paused if its level is
+// not greater than the paused level of the AdmissionManager.
+const (
+	admissionLevelNone int32 = iota
+	admissionLevelLow
+	admissionLevelNormal
+	admissionLevelHigh
+)
+
+func admissionLevel(priority string) int32 {
+	switch priority {
+	case config.TablePriorityLow:
+		return admissionLevelLow
+	case config.TablePriorityHigh:
+		return admissionLevelHigh
+	default:
+		return admissionLevelNormal
+	}
+}
+
+// AdmissionManager pauses pulling the tables of a changefeed when the events are
+// ingested faster than the sink drains them for a sustained period, instead of
+// letting the sorters grow unboundedly. The ingest rate is the number of events
+// received by the sorters in a window, and the drain rate is the number of events
+// output by them, which is bounded by the sink because of the flow control.
+//
+// The low priority tables are paused after the overload lasts for
+// overloadDuration, and the normal priority tables are paused after it lasts
+// twice as long. High priority tables are never paused. The tables are resumed
+// once the events piled up during the overload are drained.
+type AdmissionManager struct {
+	changefeed       string
+	overloadDuration time.Duration
+
+	// input and output are the number of events received and output by the
+	// sorters of all the tables.
+	input  int64
+	output int64
+	// pausedLevel is the highest admission level of the paused tables.
+	pausedLevel int32
+
+	mu           sync.Mutex
+	windowStart  time.Time
+	windowInput  int64
+	windowOutput int64
+	// overloadStart is the time the ingest rate exceeds the drain rate, and
+	// pendingAtStart is the number of pending events at that time.
+	overloadStart  time.Time
+	pendingAtStart int64
+}
+
+// NewAdmissionManager creates an AdmissionManager for the tables of a
+// changefeed, nil is returned if admission control is disabled.
+func NewAdmissionManager(changefeed string, cfg *config.AdmissionControlConfig) *AdmissionManager {
+	overloadDuration := cfg.OverloadDuration()
+	if overloadDuration <= 0 {
+		return nil
+	}
+	return &AdmissionManager{
+		changefeed:       changefeed,
+		overloadDuration: overloadDuration,
+		windowStart:      time.Now(),
+	}
+}
+
+// isPaused returns whether the tables of the level are paused.
+func (m *AdmissionManager) isPaused(level int32) bool {
+	if m == nil {
+		return false
+	}
+	m.update(time.Now())
+	return level <= atomic.LoadInt32(&m.pausedLevel)
+}
+
+// update compares the ingest rate with the drain rate once a window, and
+// pauses or resumes the tables.
+func (m *AdmissionManager) update(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.windowStart) < admissionRateWindow {
+		return
+	}
+	input, output := atomic.LoadInt64(&m.input), atomic.LoadInt64(&m.output)
+	ingest, drain := input-m.windowInput, output-m.windowOutput
+	pending := input - output
+	m.windowStart, m.windowInput, m.windowOutput = now, input, output
+
+	pausedLevel := atomic.LoadInt32(&m.pausedLevel)
+	if pausedLevel != admissionLevelNone && pending <= m.pendingAtStart {
+		log.Info("resume pulling tables, the events piled up during the overload are drained",
+			zap.String("changefeed", m.changefeed),
+			zap.Int64("pending", pending),
+			zap.Duration("overload", now.Sub(m.overloadStart)))
+		atomic.StoreInt32(&m.pausedLevel, admissionLevelNone)
+		m.overloadStart = time.Time{}
+		return
+	}
+	if ingest <= drain {
+		if pausedLevel == admissionLevelNone {
+			m.overloadStart = time.Time{}
+		}
+		return
+	}
+	if m.overloadStart.IsZero() {
+		m.overloadStart = now
+		m.pendingAtStart = pending - ingest + drain
+		return
+	}
+	level := admissionLevelNone
+	overload := now.Sub(m.overloadStart)
+	if overload >= 2*m.overloadDuration {
+		level = admissionLevelNormal
+	} else if overload >= m.overloadDuration {
+		level = admissionLevelLow
+	}
+	if level > pausedLevel {
+		atomic.StoreInt32(&m.pausedLevel, level)
+		log.Warn("pause pulling tables, the events are ingested faster than the sink drains them",
+			zap.String("changefeed", m.changefeed),
+			zap.Int32("level", level),
+			zap.Int64("ingest", ingest),
+			zap.Int64("drain", drain),
+			zap.Int64("pending", pending),
+			zap.Duration("overload", overload))
+	}
+}
+
+// admissionController is the admission control of a table. The puller node
+// waits on it after forwarding resolved events, so all the pending events
+// can be output by the sorter. When the puller node is paused, the puller
+// output channel is full and the region streams of the table are paused as
+// well.
+type admissionController struct {
+	manager *AdmissionManager
+	tableID model.TableID
+	level   int32
+	// pending is the number of events received but not output by the sorter.
+	pending int64
+}
+
+func newAdmissionController(
+	manager *AdmissionManager, tableID model.TableID, priority string,
+) *admissionController {
+	return &admissionController{
+		manager: manager,
+		tableID: tableID,
+		level:   admissionLevel(priority),
+	}
+}
+
+func (c *admissionController) onSorterInput() {
+	if c == nil {
+		return
+	}
+	atomic.AddInt64(&c.pending, 1)
+	if c.manager != nil {
+		atomic.AddInt64(&c.manager.input, 1)
+	}
+}
+
+func (c *admissionController) onSorterOutput() {
+	if c == nil {
+		return
+	}
+	atomic.AddInt64(&c.pending, -1)
+	if c.manager != nil {
+		atomic.AddInt64(&c.manager.output, 1)
+	}
+}
+
+// close drops the pending events of the removed table from the manager.
+func (c *admissionController) close() {
+	if c == nil {
+		return
+	}
+	pending := atomic.SwapInt64(&c.pending, 0)
+	if c.manager != nil {
+		atomic.AddInt64(&c.manager.output, pending)
+	}
+}
+
+// pendingEvents returns the number of events received but not output by the sorter.
+func (c *admissionController) pendingEvents() int64 {
+	if c == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&c.pending)
+}
+
+// wait blocks while the tables of the priority are paused. It also returns
+// once all the pending events of the table are output by the sorter, so the
+// pause never blocks the resolved ts of the table.
+func (c *admissionController) wait(ctx context.Context) error {
+	if c == nil || !c.manager.isPaused(c.level) || atomic.LoadInt64(&c.pending) == 0 {
+		return nil
+	}
+	changefeed := c.manager.changefeed
+	start := time.Now()
+	log.Info("pause pulling table because the sink can't keep up with the changefeed",
+		zap.String("changefeed", changefeed), zap.Int64("tableID", c.tableID),
+		zap.Int64("pending", atomic.LoadInt64(&c.pending)))
+	defer func() {
+		duration := time.Since(start)
+		tableAdmissionPausedDuration.WithLabelValues(changefeed).Add(duration.Seconds())
+		log.Info("resume pulling table",
+			zap.String("changefeed", changefeed), zap.Int64("tableID", c.tableID),
+			zap.Duration("duration", duration))
+	}()
+
+	ticker := time.NewTicker(admissionCheckInterval)
+	defer ticker.Stop()
+	for c.manager.isPaused(c.level) && atomic.LoadInt64(&c.pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
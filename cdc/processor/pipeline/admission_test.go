@@ -0,0 +1,138 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionManager(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewAdmissionManager("changefeed", &config.AdmissionControlConfig{
+		OverloadDurationInSec: 10,
+	}))
+	m := NewAdmissionManager("changefeed", &config.AdmissionControlConfig{
+		Enable:                true,
+		OverloadDurationInSec: 10,
+	})
+	c := newAdmissionController(m, 1, config.TablePriorityNormal)
+	now := m.windowStart
+	tick := func(ingest, drain int) {
+		for i := 0; i < ingest; i++ {
+			c.onSorterInput()
+		}
+		for i := 0; i < drain; i++ {
+			c.onSorterOutput()
+		}
+		now = now.Add(admissionRateWindow)
+		m.update(now)
+	}
+
+	// A short overload pauses nothing.
+	for i := 0; i < 5; i++ {
+		tick(10, 5)
+	}
+	tick(5, 10)
+	require.Equal(t, admissionLevelNone, atomic.LoadInt32(&m.pausedLevel))
+	require.True(t, m.overloadStart.IsZero())
+
+	// Low priority tables are paused after the overload lasts for the
+	// overload duration, and normal ones after twice as long.
+	for i := 0; i < 11; i++ {
+		tick(10, 5)
+	}
+	require.Equal(t, admissionLevelLow, atomic.LoadInt32(&m.pausedLevel))
+	for i := 0; i < 10; i++ {
+		tick(10, 5)
+	}
+	require.Equal(t, admissionLevelNormal, atomic.LoadInt32(&m.pausedLevel))
+
+	// The tables are resumed after the events piled up during the overload
+	// are drained, the drain rate exceeding the ingest rate is not enough.
+	tick(0, 50)
+	require.Equal(t, admissionLevelNormal, atomic.LoadInt32(&m.pausedLevel))
+	tick(0, int(c.pendingEvents())-20)
+	require.Equal(t, admissionLevelNone, atomic.LoadInt32(&m.pausedLevel))
+	require.True(t, m.overloadStart.IsZero())
+
+	// The pending events of a removed table are dropped.
+	c.close()
+	require.Equal(t, int64(0), c.pendingEvents())
+	require.Equal(t, atomic.LoadInt64(&m.input), atomic.LoadInt64(&m.output))
+}
+
+func TestAdmissionControllerWait(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	// nil controllers or controllers without a manager never block.
+	var c *admissionController
+	c.onSorterInput()
+	require.Nil(t, c.wait(ctx))
+	c = newAdmissionController(nil, 1, config.TablePriorityLow)
+	c.onSorterInput()
+	require.Nil(t, c.wait(ctx))
+
+	m := NewAdmissionManager("changefeed", &config.AdmissionControlConfig{
+		Enable:                true,
+		OverloadDurationInSec: 10,
+	})
+	// the paused level is not updated in the test.
+	m.windowStart = time.Now().Add(time.Hour)
+	atomic.StoreInt32(&m.pausedLevel, admissionLevelLow)
+
+	high := newAdmissionController(m, 1, config.TablePriorityHigh)
+	high.onSorterInput()
+	require.Nil(t, high.wait(ctx))
+	// tables without pending events are not paused.
+	low := newAdmissionController(m, 2, config.TablePriorityLow)
+	require.Nil(t, low.wait(ctx))
+
+	low.onSorterInput()
+	low.onSorterInput()
+	done := make(chan error, 1)
+	go func() {
+		done <- low.wait(ctx)
+	}()
+	select {
+	case <-done:
+		require.FailNow(t, "admission controller should block")
+	case <-time.After(2 * admissionCheckInterval):
+	}
+	// resume after the pending events of the table are drained.
+	low.onSorterOutput()
+	low.onSorterOutput()
+	require.Nil(t, <-done)
+
+	// resume after the tables are resumed.
+	low.onSorterInput()
+	go func() {
+		done <- low.wait(ctx)
+	}()
+	atomic.StoreInt32(&m.pausedLevel, admissionLevelNone)
+	require.Nil(t, <-done)
+
+	// canceled
+	atomic.StoreInt32(&m.pausedLevel, admissionLevelNormal)
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Equal(t, context.Canceled, low.wait(cctx))
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// diagnoseTable collects the diagnostics of a table pipeline from its nodes
+// except the resolved ts, output is nil if the table doesn't run in actor
+// mode. It must be threadsafe.
+func diagnoseTable(
+	tableID model.TableID, tableName string,
+	output *pullerOutput, sorter *sorterNode, sink *sinkNode,
+) *model.TablePipelineDiagnostics {
+	d := &model.TablePipelineDiagnostics{
+		TableID:                   tableID,
+		TableName:                 tableName,
+		Status:                    sink.Status().String(),
+		CheckpointTs:              sink.CheckpointTs(),
+		SorterDiskBytes:           sorter.OnDiskDataSize(),
+		FlowControllerMemoryBytes: sorter.flowController.GetConsumption(),
+		LastEventTs:               sorter.LastEventTs(),
+	}
+	if output != nil {
+		d.Queues.PullerOutputEvents, d.Queues.PullerOutputBytes = output.stats()
+	}
+	d.Queues.SorterEvents = sorter.admission.pendingEvents()
+	d.Queues.SinkBufferedRows = sink.BufferedRows()
+
+	sinkResolvedTs := sink.ResolvedTs()
+	if sinkResolvedTs > d.CheckpointTs {
+		d.SinkFlushLagMs = oracle.ExtractPhysical(sinkResolvedTs) - oracle.ExtractPhysical(d.CheckpointTs)
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type mockSorterDiskUsage int64
+
+func (u mockSorterDiskUsage) OnDiskDataSize() int64 {
+	return int64(u)
+}
+
+func TestDiagnoseTable(t *testing.T) {
+	t.Parallel()
+
+	sorter := &sorterNode{
+		flowController: &mockFlowController{},
+		admission:      newAdmissionController(nil, 1, config.TablePriorityNormal),
+		lastEventTs:    oracle.ComposeTS(2500, 0),
+		diskUsage:      mockSorterDiskUsage(4096),
+	}
+	sorter.admission.onSorterInput()
+	sorter.admission.onSorterInput()
+	sink := newSinkNode(1, nil, oracle.ComposeTS(1000, 0), math.MaxUint64, &mockFlowController{})
+	sink.resolvedTs = oracle.ComposeTS(3000, 0)
+	sink.bufferedRows = 3
+	output := newPullerOutput("changefeed-diagnose", 1, 1024, func() {})
+	defer output.close()
+	require.Nil(t, output.push(context.Background(), newRowEvent(2, 10)))
+
+	d := diagnoseTable(1, "`test`.`t`", output, sorter, sink)
+	require.Equal(t, &model.TablePipelineDiagnostics{
+		TableID:      1,
+		TableName:    "`test`.`t`",
+		Status:       "Initializing",
+		CheckpointTs: oracle.ComposeTS(1000, 0),
+		Queues: model.TablePipelineQueues{
+			PullerOutputEvents: 1,
+			PullerOutputBytes:  pullerEventSize(newRowEvent(2, 10)),
+			SorterEvents:       2,
+			SinkBufferedRows:   3,
+		},
+		SorterDiskBytes: 4096,
+		LastEventTs:     oracle.ComposeTS(2500, 0),
+		SinkFlushLagMs:  2000,
+	}, d)
+
+	// The hibernated sorter doesn't write to disk, and the tables not in
+	// actor mode have no puller output.
+	sorter.diskUsage = nil
+	d = diagnoseTable(1, "`test`.`t`", nil, sorter, sink)
+	require.Zero(t, d.SorterDiskBytes)
+	require.Zero(t, d.Queues.PullerOutputEvents)
+	require.Zero(t, d.Queues.PullerOutputBytes)
+}
@@ -26,7 +26,104 @@ var tableMemoryHistogram = prometheus.NewHistogramVec(
 		Buckets:   prometheus.ExponentialBuckets(1*1024*1024 /* mb */, 2, 10),
 	}, []string{"changefeed"})
 
+var tableAdmissionPausedDuration = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "processor",
+		Name:      "table_admission_paused_duration",
+		Help:      "total duration (s) that tables are paused by admission control",
+	}, []string{"changefeed"})
+
+var (
+	pullerOutputPendingBytesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "puller_output_pending_bytes",
+			Help:      "the bytes of the events pulled but not accepted by the sorter yet",
+		}, []string{"changefeed"})
+
+	pullerOutputBlockedDuration = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "puller_output_blocked_duration",
+			Help:      "total duration (s) that pullers are blocked because of no credit",
+		}, []string{"changefeed"})
+)
+
+var (
+	hibernatedTableGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "hibernated_table_count",
+			Help:      "the number of tables hibernated because they are idle",
+		}, []string{"changefeed", "table"})
+
+	tableWakeUpCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "table_wake_up_count",
+			Help:      "the number of times hibernated tables are woken up by row changes",
+		}, []string{"changefeed", "table"})
+)
+
+var noopUpdatesDroppedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "processor",
+		Name:      "noop_updates_dropped_count",
+		Help:      "the number of update events dropped because they don't change any column",
+	}, []string{"changefeed"})
+
+var (
+	actorNodeStashedMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "actor_node_stashed_message_count",
+			Help:      "the number of messages stashed by actor nodes because the next node is blocked",
+		}, []string{"changefeed", "node"})
+
+	actorNodeStashedMessageGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "actor_node_stashed_message",
+			Help:      "the number of messages being stashed by actor nodes",
+		}, []string{"changefeed", "node"})
+
+	actorNodeRequeueCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "actor_node_requeue_count",
+			Help:      "the number of attempts to handle stashed messages again",
+		}, []string{"changefeed", "node"})
+
+	actorNodeStashDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "processor",
+			Name:      "actor_node_stash_duration_seconds",
+			Help:      "bucketed histogram of the time (s) messages spent in the stash of actor nodes",
+			Buckets:   prometheus.ExponentialBuckets(0.001 /* 1 ms */, 2, 18),
+		}, []string{"changefeed", "node"})
+)
+
 // InitMetrics registers all metrics used in processor
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(tableMemoryHistogram)
+	registry.MustRegister(tableAdmissionPausedDuration)
+	registry.MustRegister(pullerOutputPendingBytesGauge)
+	registry.MustRegister(pullerOutputBlockedDuration)
+	registry.MustRegister(hibernatedTableGauge)
+	registry.MustRegister(tableWakeUpCount)
+	registry.MustRegister(noopUpdatesDroppedCounter)
+	registry.MustRegister(actorNodeStashedMessageCount)
+	registry.MustRegister(actorNodeStashedMessageGauge)
+	registry.MustRegister(actorNodeRequeueCount)
+	registry.MustRegister(actorNodeStashDuration)
 }
@@ -15,18 +15,29 @@ package pipeline
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/puller"
+	serverConfig "github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"github.com/pingcap/tiflow/pkg/regionspan"
 	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
+// hibernatedTableProbeInterval is the interval the resolved ts of a hibernated
+// table is advanced at.
+const hibernatedTableProbeInterval = 3 * time.Second
+
 type pullerNode struct {
 	tableName string // quoted schema and table, used in metircs only
 
@@ -35,17 +46,28 @@ type pullerNode struct {
 	changefeed  string
 	cancel      context.CancelFunc
 	wg          *errgroup.Group
+
+	// admission is shared with the sorter node, it pauses pulling
+	// when the sink of the changefeed can't keep up with the tables.
+	admission *admissionController
+
+	// hibernated is 1 if the table is hibernated, the puller is replaced by
+	// the probes then, see runPuller. Only used in actor mode.
+	hibernated int32
+	wakeCh     chan struct{}
 }
 
 func newPullerNode(
 	tableID model.TableID, replicaInfo *model.TableReplicaInfo,
-	tableName, changefeed string,
+	tableName, changefeed string, admission *admissionController,
 ) *pullerNode {
 	return &pullerNode{
 		tableID:     tableID,
 		replicaInfo: replicaInfo,
 		tableName:   tableName,
 		changefeed:  changefeed,
+		admission:   admission,
+		wakeCh:      make(chan struct{}, 1),
 	}
 }
 
@@ -65,48 +87,162 @@ func (n *pullerNode) Init(ctx pipeline.NodeContext) error {
 	return n.start(ctx, new(errgroup.Group), false, nil)
 }
 
-func (n *pullerNode) start(ctx pipeline.NodeContext, wg *errgroup.Group, isActorMode bool, sorter *sorterNode) error {
+// start starts the puller, output is the puller output handed over to the
+// sorter node in actor mode.
+func (n *pullerNode) start(ctx pipeline.NodeContext, wg *errgroup.Group, isActorMode bool, output *pullerOutput) error {
 	n.wg = wg
 	ctxC, cancel := context.WithCancel(ctx)
 	ctxC = util.PutTableInfoInCtx(ctxC, n.tableID, n.tableName)
 	ctxC = util.PutCaptureAddrInCtx(ctxC, ctx.GlobalVars().CaptureInfo.AdvertiseAddr)
 	ctxC = util.PutChangefeedIDInCtx(ctxC, ctx.ChangefeedVars().ID)
 	ctxC = util.PutRoleInCtx(ctxC, util.RoleProcessor)
+	ctxC = kv.PutIncrementalScanLimiterInCtx(ctxC, ctx.ChangefeedVars().ScanLimiter)
+	rec, err := newRecorder(serverConfig.GetGlobalServerConfig().Debug.Recorder, n.changefeed, recordHeader{
+		TableID:     n.tableID,
+		TableName:   n.tableName,
+		StartTs:     n.replicaInfo.StartTs,
+		MarkTableID: n.replicaInfo.MarkTableID,
+	})
+	if err != nil {
+		log.Warn("create the recorder of table pipeline failed, the table is not recorded",
+			zap.String("changefeed", n.changefeed), zap.Int64("tableID", n.tableID), zap.Error(err))
+		rec = nil
+	}
+	n.wg.Go(func() error {
+		defer func() {
+			if rec != nil {
+				rec.close()
+			}
+		}()
+		startTs := n.replicaInfo.StartTs
+		for {
+			// The puller is started as a probe if the table is hibernated.
+			probe := atomic.LoadInt32(&n.hibernated) == 1
+			resolvedTs, ok := n.runPuller(ctx, ctxC, startTs, probe, isActorMode, output, &rec)
+			if !ok {
+				return nil
+			}
+			// The table is hibernated, no puller runs until the next probe
+			// or the table wakes up.
+			startTs = resolvedTs
+			select {
+			case <-ctxC.Done():
+				return nil
+			case <-n.wakeCh:
+			case <-time.After(hibernatedTableProbeInterval):
+			}
+		}
+	})
+	n.cancel = cancel
+	return nil
+}
+
+// runPuller pulls the events committed after startTs. It returns false if ctx
+// is done, or true and the latest resolved ts if the puller is closed because
+// the table is hibernated, the events not after it have all been output.
+//
+// A probe is a puller started while the table is hibernated, which works as
+// a lightweight resolved ts watcher: it's closed once its resolved ts reaches
+// the current ts, or it becomes the puller of the table if it pulls any row
+// change, which wakes the table up.
+func (n *pullerNode) runPuller(
+	ctx pipeline.NodeContext, ctxC context.Context, startTs model.Ts, probe bool,
+	isActorMode bool, output *pullerOutput, rec **recorder,
+) (model.Ts, bool) {
+	var probeTargetTs model.Ts
+	if probe {
+		now, err := ctx.GlobalVars().PDClock.CurrentTime()
+		if err != nil {
+			now = time.Now()
+		}
+		probeTargetTs = oracle.GoTimeToTS(now)
+	}
+	plrCtx, cancel := context.WithCancel(ctxC)
+	defer cancel()
 	// NOTICE: always pull the old value internally
 	// See also: https://github.com/pingcap/tiflow/issues/2301.
-	plr := puller.NewPuller(
-		ctxC,
+	plr := ctx.GlobalVars().SharedPullerManager.NewPuller(
+		plrCtx,
 		ctx.GlobalVars().PDClient,
 		ctx.GlobalVars().GrpcPool,
 		ctx.GlobalVars().RegionCache,
 		ctx.GlobalVars().KVStorage,
 		ctx.GlobalVars().PDClock,
 		n.changefeed,
-		n.replicaInfo.StartTs, n.tableSpan(ctx), true)
+		startTs, n.tableSpan(ctx), true)
 	n.wg.Go(func() error {
-		ctx.Throw(errors.Trace(plr.Run(ctxC)))
+		err := plr.Run(plrCtx)
+		if ctxC.Err() == nil && plrCtx.Err() != nil {
+			// The puller is closed because the table is hibernated.
+			return nil
+		}
+		ctx.Throw(errors.Trace(err))
 		return nil
 	})
-	n.wg.Go(func() error {
-		for {
-			select {
-			case <-ctxC.Done():
-				return nil
-			case rawKV := <-plr.Output():
-				if rawKV == nil {
-					continue
+	for {
+		select {
+		case <-ctxC.Done():
+			return 0, false
+		case rawKV := <-plr.Output():
+			if rawKV == nil {
+				continue
+			}
+			if *rec != nil {
+				ok, err := (*rec).record(rawKV)
+				if err != nil {
+					log.Warn("record the event of table pipeline failed, stop recording",
+						zap.String("changefeed", n.changefeed), zap.Int64("tableID", n.tableID), zap.Error(err))
+					(*rec).close()
+				}
+				if !ok {
+					*rec = nil
+				}
+			}
+			pEvent := model.NewPolymorphicEvent(rawKV)
+			if isActorMode {
+				if err := output.push(ctxC, pEvent); err != nil {
+					return 0, false
 				}
-				pEvent := model.NewPolymorphicEvent(rawKV)
-				if isActorMode {
-					sorter.handleRawEvent(ctx, pEvent)
-				} else {
-					ctx.SendToNextNode(pmessage.PolymorphicEventMessage(pEvent))
+			} else {
+				ctx.SendToNextNode(pmessage.PolymorphicEventMessage(pEvent))
+			}
+			if rawKV.OpType != model.OpTypeResolved {
+				if probe {
+					// The table is woken up by the row change, the probe keeps
+					// running as the puller of the table.
+					probe = false
+					atomic.StoreInt32(&n.hibernated, 0)
 				}
+				continue
+			}
+			if atomic.LoadInt32(&n.hibernated) == 1 && (!probe || rawKV.CRTs >= probeTargetTs) {
+				return rawKV.CRTs, true
+			}
+			// Only pause after resolved events, so all the pending events
+			// in the sorter can be output and the pause can end.
+			if err := n.admission.wait(ctxC); err != nil {
+				return 0, false
 			}
 		}
-	})
-	n.cancel = cancel
-	return nil
+	}
+}
+
+// hibernate closes the puller after the next resolved event, and probes the
+// table by a new puller every hibernatedTableProbeInterval until it wakes up.
+// It's called by the table actor when the sorter node is hibernated.
+func (n *pullerNode) hibernate() {
+	atomic.StoreInt32(&n.hibernated, 1)
+}
+
+// wakeUp runs the puller of the table again if it's closed by hibernate.
+func (n *pullerNode) wakeUp() {
+	if atomic.SwapInt32(&n.hibernated, 0) == 0 {
+		return
+	}
+	select {
+	case n.wakeCh <- struct{}{}:
+	default:
+	}
 }
 
 // Receive receives the message from the previous node
@@ -0,0 +1,199 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// defaultPullerOutputCredit is the max bytes of the events pending in the
+	// puller output of a table.
+	defaultPullerOutputCredit = 16 * 1024 * 1024 // 16MB
+	// pullerOutputRenotifyInterval is the interval of notifying the table
+	// actor again while the puller node is blocked, in case the tick is
+	// dropped because the mailbox is full.
+	pullerOutputRenotifyInterval = 50 * time.Millisecond
+)
+
+// pullerOutput hands the events of the puller node over to the sorter node of
+// a table actor in batches, with credit based backpressure.
+//
+// The puller node pushes events to it, and is blocked while the bytes of the
+// pending events exceed the credit, so the puller output channel and then the
+// region streams of the table are paused as well. The table actor is ticked
+// once for a batch rather than once for an event, it pulls all the pending
+// events and adds them to the sorter without blocking. The credit of the
+// events is released once they are accepted by the sorter.
+type pullerOutput struct {
+	changefeed string
+	tableID    model.TableID
+	credit     int64
+	// notify ticks the table actor.
+	notify func()
+
+	mu     sync.Mutex
+	events []*model.PolymorphicEvent
+	// pendingBytes is the bytes of the events pushed but not accepted by the
+	// sorter yet.
+	pendingBytes int64
+	// notified is true if the table actor has been ticked for the events and
+	// they haven't been pulled yet.
+	notified bool
+	closed   bool
+
+	// released is signaled when the credit is released.
+	released chan struct{}
+
+	metricPendingBytes    prometheus.Gauge
+	metricBlockedDuration prometheus.Counter
+}
+
+func newPullerOutput(
+	changefeed string, tableID model.TableID, credit int64, notify func(),
+) *pullerOutput {
+	return &pullerOutput{
+		changefeed:            changefeed,
+		tableID:               tableID,
+		credit:                credit,
+		notify:                notify,
+		released:              make(chan struct{}, 1),
+		metricPendingBytes:    pullerOutputPendingBytesGauge.WithLabelValues(changefeed),
+		metricBlockedDuration: pullerOutputBlockedDuration.WithLabelValues(changefeed),
+	}
+}
+
+// push adds an event to the pending events, it blocks until there is enough
+// credit. An event is always accepted if nothing is pending, so an event
+// larger than the credit never blocks forever.
+func (o *pullerOutput) push(ctx context.Context, event *model.PolymorphicEvent) error {
+	size := pullerEventSize(event)
+	var ticker *time.Ticker
+	var start time.Time
+	for {
+		o.mu.Lock()
+		if o.closed {
+			// The table is being removed, the event is dropped.
+			o.mu.Unlock()
+			return nil
+		}
+		if o.pendingBytes < o.credit || o.pendingBytes == 0 {
+			o.events = append(o.events, event)
+			o.pendingBytes += size
+			needNotify := !o.notified
+			o.notified = true
+			o.mu.Unlock()
+
+			o.metricPendingBytes.Add(float64(size))
+			if ticker != nil {
+				ticker.Stop()
+				o.metricBlockedDuration.Add(time.Since(start).Seconds())
+			}
+			if needNotify {
+				o.notify()
+			}
+			return nil
+		}
+		o.mu.Unlock()
+
+		if ticker == nil {
+			ticker = time.NewTicker(pullerOutputRenotifyInterval)
+			start = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			o.metricBlockedDuration.Add(time.Since(start).Seconds())
+			return ctx.Err()
+		case <-o.released:
+		case <-ticker.C:
+			o.notify()
+		}
+	}
+}
+
+// pull takes all the pending events out, their credit is not released until
+// release is called.
+func (o *pullerOutput) pull() []*model.PolymorphicEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	events := o.events
+	o.events = nil
+	o.notified = false
+	return events
+}
+
+// release releases the credit of the events accepted by the sorter.
+func (o *pullerOutput) release(events []*model.PolymorphicEvent) {
+	if len(events) == 0 {
+		return
+	}
+	size := int64(0)
+	for _, event := range events {
+		size += pullerEventSize(event)
+	}
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return
+	}
+	o.pendingBytes -= size
+	o.mu.Unlock()
+	o.metricPendingBytes.Sub(float64(size))
+
+	select {
+	case o.released <- struct{}{}:
+	default:
+	}
+}
+
+// stats returns the number and the bytes of the events pushed but not
+// accepted by the sorter yet, the events pulled but not accepted are counted
+// in the bytes only.
+func (o *pullerOutput) stats() (events int, bytes int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.events), o.pendingBytes
+}
+
+// close releases the credit of all the pending events, so they are not
+// counted in the metrics any more.
+func (o *pullerOutput) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return
+	}
+	o.closed = true
+	o.metricPendingBytes.Sub(float64(o.pendingBytes))
+	o.pendingBytes = 0
+	o.events = nil
+}
+
+// pullerEventSize returns the approximate size of an event in the puller
+// output, a resolved event is counted as one byte.
+func pullerEventSize(event *model.PolymorphicEvent) int64 {
+	if event.RawKV == nil {
+		return 1
+	}
+	if size := event.RawKV.ApproximateDataSize(); size > 0 {
+		return size
+	}
+	return 1
+}
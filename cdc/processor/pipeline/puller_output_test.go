@@ -0,0 +1,119 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func newRowEvent(ts model.Ts, size int) *model.PolymorphicEvent {
+	return model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("k"),
+		Value:   make([]byte, size-1),
+		StartTs: ts - 1,
+		CRTs:    ts,
+	})
+}
+
+func TestPullerOutputBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	notified := atomic.NewInt32(0)
+	o := newPullerOutput("changefeed-batch", 1, 1024, func() {
+		notified.Inc()
+	})
+	defer o.close()
+
+	// The table actor is ticked once for a batch.
+	for i := 1; i <= 3; i++ {
+		require.Nil(t, o.push(ctx, newRowEvent(model.Ts(i+1), 10)))
+	}
+	require.Nil(t, o.push(ctx, model.NewResolvedPolymorphicEvent(0, 4)))
+	require.Equal(t, int32(1), notified.Load())
+	require.Equal(t, float64(31), testutil.ToFloat64(o.metricPendingBytes))
+
+	events := o.pull()
+	require.Len(t, events, 4)
+	require.Empty(t, o.pull())
+	require.Nil(t, o.push(ctx, newRowEvent(5, 10)))
+	require.Equal(t, int32(2), notified.Load())
+
+	// The credit is released once the events are accepted by the sorter.
+	o.release(events)
+	require.Equal(t, float64(10), testutil.ToFloat64(o.metricPendingBytes))
+}
+
+func TestPullerOutputBackpressure(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	o := newPullerOutput("changefeed-backpressure", 1, 100, func() {})
+	defer o.close()
+
+	// An event larger than the credit is accepted if nothing is pending.
+	require.Nil(t, o.push(ctx, newRowEvent(2, 200)))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- o.push(ctx, newRowEvent(3, 10))
+	}()
+	select {
+	case <-done:
+		require.FailNow(t, "the puller output should block")
+	case <-time.After(2 * pullerOutputRenotifyInterval):
+	}
+	o.release(o.pull())
+	require.Nil(t, <-done)
+
+	// A blocked push is canceled with the context.
+	require.Nil(t, o.push(ctx, newRowEvent(4, 100)))
+	go func() {
+		done <- o.push(ctx, newRowEvent(5, 10))
+	}()
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+}
+
+func TestPullerOutputRenotify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	notified := make(chan struct{}, 16)
+	o := newPullerOutput("changefeed-renotify", 1, 10, func() {
+		notified <- struct{}{}
+	})
+	defer o.close()
+
+	require.Nil(t, o.push(ctx, newRowEvent(2, 10)))
+	<-notified
+	// The table actor is ticked again while the puller is blocked, in case
+	// the tick is dropped.
+	done := make(chan error, 1)
+	go func() {
+		done <- o.push(ctx, newRowEvent(3, 10))
+	}()
+	<-notified
+	o.release(o.pull())
+	require.Nil(t, <-done)
+}
@@ -0,0 +1,249 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/tinylib/msgp/msgp"
+	"go.uber.org/zap"
+)
+
+// recordMagic is written at the beginning of the record files.
+const recordMagic = "ticdc-table-pipeline-record-v1"
+
+// recordFileSuffix is the suffix of the record files, they are named after the
+// IDs and the start ts of the recorded tables.
+const recordFileSuffix = ".record"
+
+// recordHeader describes the table pipeline whose events are recorded.
+type recordHeader struct {
+	TableID     model.TableID
+	TableName   string
+	StartTs     model.Ts
+	MarkTableID model.TableID
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// recorder records the raw events fed to a table pipeline into a local file,
+// the file is a header followed by the events encoded with msgp. The events
+// are flushed to the file on every resolved event, so the file is complete up
+// to the last resolved event if the capture crashes.
+type recorder struct {
+	path    string
+	file    *os.File
+	cw      *countingWriter
+	w       *msgp.Writer
+	maxSize int64
+}
+
+// newRecorder creates a recorder for the table pipeline, nil is returned if the
+// changefeed is not recorded.
+func newRecorder(
+	cfg *config.RecorderConfig, changefeedID string, header recordHeader,
+) (*recorder, error) {
+	if !cfg.IsRecorded(changefeedID) {
+		return nil, nil
+	}
+	dir := filepath.Join(cfg.Dir, changefeedID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-%d%s", header.TableID, header.StartTs, recordFileSuffix))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cw := &countingWriter{w: file}
+	r := &recorder{
+		path:    path,
+		file:    file,
+		cw:      cw,
+		w:       msgp.NewWriter(cw),
+		maxSize: cfg.MaxFileSize,
+	}
+	if err := r.writeHeader(header); err != nil {
+		r.close()
+		return nil, err
+	}
+	log.Info("start recording table pipeline",
+		zap.String("changefeed", changefeedID),
+		zap.Int64("tableID", header.TableID),
+		zap.String("path", path))
+	return r, nil
+}
+
+func (r *recorder) writeHeader(header recordHeader) error {
+	for _, err := range []error{
+		r.w.WriteString(recordMagic),
+		r.w.WriteInt64(header.TableID),
+		r.w.WriteString(header.TableName),
+		r.w.WriteUint64(header.StartTs),
+		r.w.WriteInt64(header.MarkTableID),
+		r.w.Flush(),
+	} {
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// record records the raw event. It returns false once the file reaches its max
+// size, then the recorder is closed and must not be used anymore.
+func (r *recorder) record(raw *model.RawKVEntry) (bool, error) {
+	if err := raw.EncodeMsg(r.w); err != nil {
+		return false, errors.Trace(err)
+	}
+	if raw.OpType != model.OpTypeResolved {
+		return true, nil
+	}
+	if err := r.w.Flush(); err != nil {
+		return false, errors.Trace(err)
+	}
+	if r.maxSize > 0 && r.cw.n >= r.maxSize {
+		log.Info("stop recording table pipeline since the record file reaches its max size",
+			zap.String("path", r.path), zap.Int64("size", r.cw.n))
+		r.close()
+		return false, nil
+	}
+	return true, nil
+}
+
+func (r *recorder) close() {
+	if err := r.w.Flush(); err != nil {
+		log.Warn("flush record file failed", zap.String("path", r.path), zap.Error(err))
+	}
+	if err := r.file.Close(); err != nil {
+		log.Warn("close record file failed", zap.String("path", r.path), zap.Error(err))
+	}
+}
+
+// findRecordFile returns the record file of the table of the changefeed in the
+// directory, the earliest one is returned if the table is recorded many times.
+func findRecordFile(dir, changefeedID string, tableID model.TableID) (string, error) {
+	pattern := filepath.Join(dir, changefeedID, fmt.Sprintf("%d-*%s", tableID, recordFileSuffix))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var (
+		path     string
+		earliest model.Ts
+	)
+	for _, match := range matches {
+		var (
+			id      model.TableID
+			startTs model.Ts
+		)
+		_, err := fmt.Sscanf(filepath.Base(match), "%d-%d"+recordFileSuffix, &id, &startTs)
+		if err != nil || id != tableID {
+			continue
+		}
+		if path == "" || startTs < earliest {
+			path, earliest = match, startTs
+		}
+	}
+	if path == "" {
+		return "", errors.Errorf("no record file of table %d of changefeed %s in %s",
+			tableID, changefeedID, dir)
+	}
+	return path, nil
+}
+
+// recordReader reads the events recorded by recorder.
+type recordReader struct {
+	file   *os.File
+	r      *msgp.Reader
+	header recordHeader
+}
+
+func openRecord(path string) (*recordReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r := &recordReader{file: file, r: msgp.NewReader(file)}
+	if err := r.readHeader(); err != nil {
+		file.Close()
+		return nil, errors.Annotatef(err, "read the header of record file %s", path)
+	}
+	return r, nil
+}
+
+func (r *recordReader) readHeader() error {
+	magic, err := r.r.ReadString()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if magic != recordMagic {
+		return errors.Errorf("unknown record file format %s", magic)
+	}
+	if r.header.TableID, err = r.r.ReadInt64(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.header.TableName, err = r.r.ReadString(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.header.StartTs, err = r.r.ReadUint64(); err != nil {
+		return errors.Trace(err)
+	}
+	if r.header.MarkTableID, err = r.r.ReadInt64(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// next returns the next recorded event, io.EOF is returned at the end of the
+// file. An event truncated by a crash is treated as the end of the file.
+func (r *recordReader) next() (*model.RawKVEntry, error) {
+	if _, err := r.r.NextType(); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, errors.Trace(err)
+	}
+	raw := new(model.RawKVEntry)
+	if err := raw.DecodeMsg(r.r); err != nil {
+		if cause := msgp.Cause(err); cause == io.EOF || cause == io.ErrUnexpectedEOF {
+			log.Warn("the last event of record file is truncated",
+				zap.String("path", r.file.Name()))
+			return nil, io.EOF
+		}
+		return nil, errors.Trace(err)
+	}
+	return raw, nil
+}
+
+func (r *recordReader) close() {
+	r.file.Close()
+}
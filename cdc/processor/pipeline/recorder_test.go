@@ -0,0 +1,167 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	"github.com/pingcap/tiflow/pkg/pipeline"
+	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedEvents returns the events to record, the byte slices are never nil
+// since empty ones are decoded as non-nil slices.
+func recordedEvents() []*model.RawKVEntry {
+	event := func(opType model.OpType, key, value, oldValue string, startTs, crts uint64) *model.RawKVEntry {
+		return &model.RawKVEntry{
+			OpType:   opType,
+			Key:      []byte(key),
+			Value:    []byte(value),
+			OldValue: []byte(oldValue),
+			StartTs:  startTs,
+			CRTs:     crts,
+		}
+	}
+	return []*model.RawKVEntry{
+		event(model.OpTypePut, "k1", "v1", "", 1, 2),
+		event(model.OpTypeDelete, "k2", "", "v2", 3, 4),
+		event(model.OpTypeResolved, "", "", "", 0, 5),
+		event(model.OpTypePut, "k3", "v3", "", 6, 7),
+		event(model.OpTypeResolved, "", "", "", 0, 8),
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	header := recordHeader{TableID: 1, TableName: "`test`.`t`", StartTs: 1, MarkTableID: 2}
+	rec, err := newRecorder(&config.RecorderConfig{Dir: dir, Changefeeds: []string{"cf2"}}, "cf1", header)
+	require.Nil(t, err)
+	require.Nil(t, rec)
+
+	rec, err = newRecorder(&config.RecorderConfig{Dir: dir}, "cf1", header)
+	require.Nil(t, err)
+	events := recordedEvents()
+	for _, event := range events {
+		ok, err := rec.record(event)
+		require.Nil(t, err)
+		require.True(t, ok)
+	}
+	rec.close()
+
+	path := filepath.Join(dir, "cf1", "1-1.record")
+	reader, err := openRecord(path)
+	require.Nil(t, err)
+	require.Equal(t, header, reader.header)
+	for _, event := range events {
+		raw, err := reader.next()
+		require.Nil(t, err)
+		require.Equal(t, event, raw)
+	}
+	_, err = reader.next()
+	require.Equal(t, io.EOF, err)
+	reader.close()
+
+	// a truncated event is treated as the end of the file.
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Nil(t, os.Truncate(path, info.Size()-1))
+	reader, err = openRecord(path)
+	require.Nil(t, err)
+	for _, event := range events[:len(events)-1] {
+		raw, err := reader.next()
+		require.Nil(t, err)
+		require.Equal(t, event, raw)
+	}
+	_, err = reader.next()
+	require.Equal(t, io.EOF, err)
+	reader.close()
+
+	// the recording stops once the file reaches its max size.
+	rec, err = newRecorder(&config.RecorderConfig{Dir: dir, MaxFileSize: 1}, "cf2", header)
+	require.Nil(t, err)
+	ok, err := rec.record(events[0])
+	require.Nil(t, err)
+	require.True(t, ok)
+	ok, err = rec.record(events[2])
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	require.Nil(t, os.WriteFile(path, []byte("invalid"), 0o644))
+	_, err = openRecord(path)
+	require.Regexp(t, ".*read the header of record file.*", err)
+}
+
+func TestReplayNode(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	rec, err := newRecorder(&config.RecorderConfig{Dir: dir}, "cf1", recordHeader{TableID: 1, StartTs: 1})
+	require.Nil(t, err)
+	events := recordedEvents()
+	for _, event := range events {
+		_, err := rec.record(event)
+		require.Nil(t, err)
+	}
+	rec.close()
+
+	ctx := cdcContext.NewBackendContext4Test(true)
+	outputCh := make(chan pmessage.Message, len(events))
+	n := newReplayNode(filepath.Join(dir, "cf1", "1-1.record"), nil)
+	require.Nil(t, n.Init(pipeline.MockNodeContext4Test(ctx, pmessage.Message{}, outputCh)))
+	for _, event := range events {
+		msg := <-outputCh
+		require.Equal(t, pmessage.MessageTypePolymorphicEvent, msg.Tp)
+		require.Equal(t, event, msg.PolymorphicEvent.RawKV)
+	}
+	require.Nil(t, n.Destroy(pipeline.MockNodeContext4Test(ctx, pmessage.Message{}, outputCh)))
+}
+
+func TestFindRecordFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := findRecordFile(dir, "cf1", 1)
+	require.Regexp(t, ".*no record file of table 1 of changefeed cf1.*", err)
+
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "cf1"), 0o755))
+	for _, name := range []string{"1-20.record", "1-10.record", "11-5.record", "1-invalid.record"} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, "cf1", name), nil, 0o644))
+	}
+	// the earliest record file of the table is returned
+	path, err := findRecordFile(dir, "cf1", 1)
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(dir, "cf1", "1-10.record"), path)
+	path, err = findRecordFile(dir, "cf1", 11)
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(dir, "cf1", "11-5.record"), path)
+	_, err = findRecordFile(dir, "cf2", 1)
+	require.Regexp(t, ".*no record file of table 1 of changefeed cf2.*", err)
+}
+
+func TestNewReplayTablePipeline(t *testing.T) {
+	t.Parallel()
+
+	ctx := cdcContext.NewBackendContext4Test(true)
+	_, err := NewReplayTablePipeline(ctx, nil, t.TempDir(), 1, nil, 0, nil, "")
+	require.Regexp(t, ".*no record file of table 1.*", err)
+}
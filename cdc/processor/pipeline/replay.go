@@ -0,0 +1,121 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"io"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	"github.com/pingcap/tiflow/pkg/pipeline"
+	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"golang.org/x/sync/errgroup"
+)
+
+// replayNode takes the place of the puller node when a table pipeline is
+// replayed, it feeds the pipeline with the events in a record file in the order
+// they were recorded.
+type replayNode struct {
+	path      string
+	admission *admissionController
+	cancel    context.CancelFunc
+	wg        *errgroup.Group
+}
+
+func newReplayNode(path string, admission *admissionController) *replayNode {
+	return &replayNode{path: path, admission: admission}
+}
+
+func (n *replayNode) Init(ctx pipeline.NodeContext) error {
+	reader, err := openRecord(n.path)
+	if err != nil {
+		return err
+	}
+	ctxC, cancel := context.WithCancel(ctx)
+	n.cancel = cancel
+	n.wg = new(errgroup.Group)
+	n.wg.Go(func() error {
+		defer reader.close()
+		for {
+			rawKV, err := reader.next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				ctx.Throw(err)
+				return nil
+			}
+			select {
+			case <-ctxC.Done():
+				return nil
+			default:
+			}
+			ctx.SendToNextNode(pmessage.PolymorphicEventMessage(model.NewPolymorphicEvent(rawKV)))
+			if rawKV.OpType == model.OpTypeResolved {
+				if err := n.admission.wait(ctxC); err != nil {
+					return nil
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// Receive receives the message from the previous node
+func (n *replayNode) Receive(ctx pipeline.NodeContext) error {
+	// just forward any messages to the next node
+	ctx.SendToNextNode(ctx.Message())
+	return nil
+}
+
+func (n *replayNode) Destroy(ctx pipeline.NodeContext) error {
+	n.cancel()
+	return n.wg.Wait()
+}
+
+// NewReplayTablePipeline creates a table pipeline which replays the events
+// recorded in the record file of the table in recordDir instead of pulling them
+// from TiKV, so the bugs of sorters and sinks met in production can be
+// reproduced. The events are fed to the pipeline in the recorded order, and
+// like a normal table pipeline, they are output to the sink only after the
+// barrier ts is updated beyond them.
+func NewReplayTablePipeline(ctx cdcContext.Context,
+	mounter entry.Mounter,
+	recordDir string,
+	tableID model.TableID,
+	sink sink.Sink,
+	targetTs model.Ts,
+	admission *AdmissionManager,
+	admissionPriority string,
+) (TablePipeline, error) {
+	recordPath, err := findRecordFile(recordDir, ctx.ChangefeedVars().ID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := openRecord(recordPath)
+	if err != nil {
+		return nil, err
+	}
+	header := reader.header
+	reader.close()
+	replicaInfo := &model.TableReplicaInfo{
+		StartTs:     header.StartTs,
+		MarkTableID: header.MarkTableID,
+	}
+	return newTablePipeline(ctx, mounter, header.TableID, header.TableName,
+		replicaInfo, sink, targetTs, admission, admissionPriority, recordPath), nil
+}
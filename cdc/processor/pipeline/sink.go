@@ -23,10 +23,13 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/transform"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -77,11 +80,21 @@ type sinkNode struct {
 	barrierTs    model.Ts
 
 	rowBuffer []*model.RowChangedEvent
+	// bufferedRows is the length of rowBuffer, it's used in diagnostics only.
+	bufferedRows int64
 
 	flowController tableFlowController
 
 	replicaConfig    *config.ReplicaConfig
 	isTableActorMode bool
+
+	// eventFilter drops the rows of the event types ignored by the filter config.
+	eventFilter *filter.EventFilter
+	// transformer transforms the column values of rows before they are emitted to the sink.
+	transformer *transform.Transformer
+	// skipNoopUpdates drops the update events that don't change any column.
+	skipNoopUpdates          bool
+	metricNoopUpdatesDropped prometheus.Counter
 }
 
 func newSinkNode(tableID model.TableID, sink sink.Sink, startTs model.Ts, targetTs model.Ts, flowController tableFlowController) *sinkNode {
@@ -102,16 +115,32 @@ func (n *sinkNode) ResolvedTs() model.Ts   { return atomic.LoadUint64(&n.resolve
 func (n *sinkNode) CheckpointTs() model.Ts { return atomic.LoadUint64(&n.checkpointTs) }
 func (n *sinkNode) BarrierTs() model.Ts    { return atomic.LoadUint64(&n.barrierTs) }
 func (n *sinkNode) Status() TableStatus    { return n.status.Load() }
+func (n *sinkNode) BufferedRows() int64    { return atomic.LoadInt64(&n.bufferedRows) }
 
 func (n *sinkNode) Init(ctx pipeline.NodeContext) error {
 	n.replicaConfig = ctx.ChangefeedVars().Info.Config
-	n.initWithReplicaConfig(false, ctx.ChangefeedVars().Info.Config)
-	return nil
+	return n.initWithReplicaConfig(false, ctx.ChangefeedVars().ID, ctx.ChangefeedVars().Info.Config)
 }
 
-func (n *sinkNode) initWithReplicaConfig(isTableActorMode bool, replicaConfig *config.ReplicaConfig) {
+func (n *sinkNode) initWithReplicaConfig(
+	isTableActorMode bool, changefeedID model.ChangeFeedID, replicaConfig *config.ReplicaConfig,
+) error {
 	n.isTableActorMode = isTableActorMode
 	n.replicaConfig = replicaConfig
+	n.skipNoopUpdates = replicaConfig.EnableOldValue &&
+		replicaConfig.Sink != nil && replicaConfig.Sink.SkipNoopUpdates
+	n.metricNoopUpdatesDropped = noopUpdatesDroppedCounter.WithLabelValues(changefeedID)
+	eventFilter, err := filter.NewEventFilter(replicaConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	n.eventFilter = eventFilter
+	transformer, err := transform.NewTransformer(replicaConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	n.transformer = transformer
+	return nil
 }
 
 // stop is called when sink receives a stop command or checkpointTs reaches targetTs.
@@ -129,6 +158,22 @@ func (n *sinkNode) stop(ctx context.Context) (err error) {
 	return
 }
 
+// stopAtTs makes the sink node stop once its checkpoint ts reaches stopTs.
+// The rows committed after stopTs are never flushed to the sink, so the table
+// can be replicated from its final checkpoint ts somewhere else without loss
+// or duplication. If the checkpoint ts is already stopTs or larger, the sink
+// node stops at once and the final checkpoint ts is larger than stopTs.
+func (n *sinkNode) stopAtTs(ctx context.Context, stopTs model.Ts) error {
+	if stopTs < atomic.LoadUint64(&n.targetTs) {
+		atomic.StoreUint64(&n.targetTs, stopTs)
+	}
+	log.Info("table is stopping at the stop ts",
+		zap.Int64("tableID", n.tableID),
+		zap.Uint64("stopTs", stopTs),
+		zap.Uint64("checkpointTs", atomic.LoadUint64(&n.checkpointTs)))
+	return n.flushSink(ctx, atomic.LoadUint64(&n.resolvedTs))
+}
+
 // flushSink emits all rows in rowBuffer to the backend sink and flushes
 // the backend sink.
 func (n *sinkNode) flushSink(ctx context.Context, resolvedTs model.Ts) (err error) {
@@ -137,17 +182,18 @@ func (n *sinkNode) flushSink(ctx context.Context, resolvedTs model.Ts) (err erro
 			n.status.Store(TableStatusStopped)
 			return
 		}
-		if atomic.LoadUint64(&n.checkpointTs) >= n.targetTs {
+		if atomic.LoadUint64(&n.checkpointTs) >= atomic.LoadUint64(&n.targetTs) {
 			err = n.stop(ctx)
 		}
 	}()
 	currentBarrierTs := atomic.LoadUint64(&n.barrierTs)
 	currentCheckpointTs := atomic.LoadUint64(&n.checkpointTs)
+	currentTargetTs := atomic.LoadUint64(&n.targetTs)
 	if resolvedTs > currentBarrierTs {
 		resolvedTs = currentBarrierTs
 	}
-	if resolvedTs > n.targetTs {
-		resolvedTs = n.targetTs
+	if resolvedTs > currentTargetTs {
+		resolvedTs = currentTargetTs
 	}
 	if resolvedTs <= currentCheckpointTs {
 		return nil
@@ -194,6 +240,19 @@ func (n *sinkNode) addRowToBuffer(ctx context.Context, event *model.PolymorphicE
 		return nil
 	}
 
+	if n.eventFilter.ShouldIgnoreRow(event.Row) {
+		log.Debug("skip emit row event ignored by event filter", zap.Any("event", event))
+		return nil
+	}
+	if err := n.transformer.Apply(event.Row); err != nil {
+		return errors.Trace(err)
+	}
+	if n.skipNoopUpdates && isNoopUpdate(event.Row) {
+		log.Debug("skip emit no-op update event", zap.Any("event", event))
+		n.metricNoopUpdatesDropped.Inc()
+		return nil
+	}
+
 	// This indicates that it is an update event,
 	// and after enable old value internally by default(but disable in the configuration).
 	// We need to handle the update event to be compatible with the old format.
@@ -214,6 +273,8 @@ func (n *sinkNode) addRowToBuffer(ctx context.Context, event *model.PolymorphicE
 		n.rowBuffer = append(n.rowBuffer, event.Row)
 	}
 
+	atomic.StoreInt64(&n.bufferedRows, int64(len(n.rowBuffer)))
+
 	if len(n.rowBuffer) >= defaultSyncResolvedBatch {
 		if err := n.emitRowToSink(ctx); err != nil {
 			return errors.Trace(err)
@@ -222,6 +283,29 @@ func (n *sinkNode) addRowToBuffer(ctx context.Context, event *model.PolymorphicE
 	return nil
 }
 
+// isNoopUpdate returns true if the row is an update event whose pre and post
+// images are equal, it happens when only the columns removed by the column
+// selector are changed, or the values are updated to the same ones.
+func isNoopUpdate(row *model.RowChangedEvent) bool {
+	if !row.IsUpdate() || len(row.Columns) != len(row.PreColumns) {
+		return false
+	}
+	for i, col := range row.Columns {
+		preCol := row.PreColumns[i]
+		if col == nil || preCol == nil {
+			if col != preCol {
+				return false
+			}
+			continue
+		}
+		if col.Name != preCol.Name || (col.Value == nil) != (preCol.Value == nil) ||
+			model.ColumnValueString(col.Value) != model.ColumnValueString(preCol.Value) {
+			return false
+		}
+	}
+	return true
+}
+
 // shouldSplitUpdateEvent determines if the split event is needed to align the old format based on
 // whether the handle key column has been modified.
 // If the handle key column is modified,
@@ -298,6 +382,7 @@ func (n *sinkNode) clearBuffers() {
 		}
 		n.rowBuffer = n.rowBuffer[:0]
 	}
+	atomic.StoreInt64(&n.bufferedRows, 0)
 }
 
 // emitRowToSink emits the rows in rowBuffer to backend sink.
@@ -349,10 +434,15 @@ func (n *sinkNode) HandleMessage(ctx context.Context, msg pmessage.Message) (boo
 			return false, errors.Trace(err)
 		}
 	case pmessage.MessageTypeCommand:
-		if msg.Command.Tp == pmessage.CommandTypeStop {
+		switch msg.Command.Tp {
+		case pmessage.CommandTypeStop:
 			if err := n.stop(ctx); err != nil {
 				return false, errors.Trace(err)
 			}
+		case pmessage.CommandTypeStopAtTs:
+			if err := n.stopAtTs(ctx, msg.Command.StopTs); err != nil {
+				return false, errors.Trace(err)
+			}
 		}
 	case pmessage.MessageTypeBarrier:
 		if err := n.updateBarrierTs(ctx, msg.BarrierTs); err != nil {
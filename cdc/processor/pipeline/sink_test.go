@@ -54,6 +54,12 @@ func (c *mockFlowController) GetConsumption() uint64 {
 	return 0
 }
 
+func (c *mockFlowController) Hibernate() {
+}
+
+func (c *mockFlowController) WakeUp() {
+}
+
 func (s *mockSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
 	_ = s.EmitRowChangedEvents(ctx, rows...)
 	return true, nil
@@ -231,6 +237,63 @@ func TestStatus(t *testing.T) {
 	require.Equal(t, uint64(7), node.CheckpointTs())
 }
 
+func TestStopAtTs(t *testing.T) {
+	ctx := cdcContext.NewContext(context.Background(), &cdcContext.GlobalVars{})
+	ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
+		ID: "changefeed-id-test-stop-at-ts",
+		Info: &model.ChangeFeedInfo{
+			StartTs: oracle.GoTimeToTS(time.Now()),
+			Config:  config.GetDefaultReplicaConfig(),
+		},
+	})
+	resolvedMsg := func(ts model.Ts) pmessage.Message {
+		return pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
+			CRTs: ts, RawKV: &model.RawKVEntry{OpType: model.OpTypeResolved},
+			Row: &model.RowChangedEvent{},
+		})
+	}
+	rowMsg := func(ts model.Ts) pmessage.Message {
+		return pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
+			CRTs: ts, RawKV: &model.RawKVEntry{OpType: model.OpTypePut},
+			Row: &model.RowChangedEvent{
+				CommitTs: ts,
+				Columns:  []*model.Column{{Name: "id", Flag: model.HandleKeyFlag, Value: 1}},
+			},
+		})
+	}
+	stopAtTsMsg := func(ts model.Ts) pmessage.Message {
+		return pmessage.CommandMessage(&pmessage.Command{Tp: pmessage.CommandTypeStopAtTs, StopTs: ts})
+	}
+
+	// the sink is drained to the stop ts.
+	node := newSinkNode(1, &mockSink{}, 0, 100, &mockFlowController{})
+	require.Nil(t, node.Init(pipeline.MockNodeContext4Test(ctx, pmessage.Message{}, nil)))
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, pmessage.BarrierMessage(20), nil)))
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, resolvedMsg(2), nil)))
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, rowMsg(3), nil)))
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, rowMsg(6), nil)))
+
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, stopAtTsMsg(5), nil)))
+	require.Equal(t, TableStatusRunning, node.Status())
+	require.Equal(t, uint64(2), node.CheckpointTs())
+
+	err := node.Receive(pipeline.MockNodeContext4Test(ctx, resolvedMsg(8), nil))
+	require.True(t, cerrors.ErrTableProcessorStoppedSafely.Equal(err))
+	require.Equal(t, TableStatusStopped, node.Status())
+	require.Equal(t, uint64(5), node.CheckpointTs())
+
+	// the sink stops at once if it's already beyond the stop ts.
+	node = newSinkNode(1, &mockSink{}, 0, 100, &mockFlowController{})
+	require.Nil(t, node.Init(pipeline.MockNodeContext4Test(ctx, pmessage.Message{}, nil)))
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, pmessage.BarrierMessage(20), nil)))
+	require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(ctx, resolvedMsg(7), nil)))
+
+	err = node.Receive(pipeline.MockNodeContext4Test(ctx, stopAtTsMsg(5), nil))
+	require.True(t, cerrors.ErrTableProcessorStoppedSafely.Equal(err))
+	require.Equal(t, TableStatusStopped, node.Status())
+	require.Equal(t, uint64(7), node.CheckpointTs())
+}
+
 // TestStopStatus tests the table status of a pipeline is not set to stopped
 // until the underlying sink is closed
 func TestStopStatus(t *testing.T) {
@@ -586,6 +649,56 @@ func TestSplitUpdateEventWhenDisableOldValue(t *testing.T) {
 	require.Equal(t, 0, len(node.rowBuffer[insertEventIndex].PreColumns))
 }
 
+func TestSkipNoopUpdates(t *testing.T) {
+	ctx := cdcContext.NewContext(context.Background(), &cdcContext.GlobalVars{})
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.SkipNoopUpdates = true
+	ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
+		ID: "changefeed-id-test-skip-noop-updates",
+		Info: &model.ChangeFeedInfo{
+			StartTs: oracle.GoTimeToTS(time.Now()),
+			Config:  cfg,
+		},
+	})
+	sink := &mockSink{}
+	node := newSinkNode(1, sink, 0, 10, &mockFlowController{})
+	require.Nil(t, node.Init(pipeline.MockNodeContext4Test(ctx, pmessage.Message{}, nil)))
+
+	newColumns := func(value interface{}) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Flag: model.HandleKeyFlag, Value: 1},
+			{Name: "col1", Value: value},
+		}
+	}
+	receive := func(columns, preColumns []*model.Column) {
+		require.Nil(t, node.Receive(pipeline.MockNodeContext4Test(
+			ctx,
+			pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
+				CRTs:  1,
+				RawKV: &model.RawKVEntry{OpType: model.OpTypePut},
+				Row:   &model.RowChangedEvent{CommitTs: 1, Columns: columns, PreColumns: preColumns},
+			}), nil)))
+	}
+
+	// no-op updates are dropped.
+	receive(newColumns("a"), newColumns("a"))
+	receive(newColumns(nil), newColumns(nil))
+	require.Equal(t, 0, len(node.rowBuffer))
+
+	// updates changing any column, inserts and deletes are kept.
+	receive(newColumns("b"), newColumns("a"))
+	receive(newColumns(nil), newColumns("null"))
+	receive(newColumns("a"), nil)
+	receive(nil, newColumns("a"))
+	require.Equal(t, 4, len(node.rowBuffer))
+
+	// no-op updates are kept if the option is disabled.
+	cfg.Sink.SkipNoopUpdates = false
+	require.Nil(t, node.Init(pipeline.MockNodeContext4Test(ctx, pmessage.Message{}, nil)))
+	receive(newColumns("a"), newColumns("a"))
+	require.Equal(t, 5, len(node.rowBuffer))
+}
+
 type flushFlowController struct {
 	mockFlowController
 	releaseCounter int
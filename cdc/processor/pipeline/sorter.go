@@ -15,6 +15,7 @@ package pipeline
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -25,11 +26,6 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sorter"
-	"github.com/pingcap/tiflow/cdc/sorter/leveldb"
-	"github.com/pingcap/tiflow/cdc/sorter/memory"
-	"github.com/pingcap/tiflow/cdc/sorter/unified"
-	"github.com/pingcap/tiflow/pkg/actor"
-	"github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pipeline"
@@ -50,93 +46,110 @@ type sorterNode struct {
 
 	// for per-table flow control
 	flowController tableFlowController
+	// for per-table admission control, it's shared with the puller node
+	admission *admissionController
 
 	mounter entry.Mounter
 
 	eg     *errgroup.Group
 	cancel context.CancelFunc
 
+	// nodeCtx and stdCtx are kept to run a new sorter when the table wakes up.
+	nodeCtx pipeline.NodeContext
+	stdCtx  context.Context
+	// cancelSorter stops the running sorter only.
+	cancelSorter     context.CancelFunc
+	tableActorTicker *actorTicker
+
 	// The latest resolved ts that sorter has received.
 	resolvedTs model.Ts
+	// inputResolvedTs is the latest resolved ts added to the sorter, and
+	// outputResolvedTs is the latest resolved ts sent by the sorter, the
+	// sorter is drained if they are equal and no event is pending.
+	inputResolvedTs  model.Ts
+	outputResolvedTs model.Ts
 
 	// The latest barrier ts that sorter has received.
 	barrierTs model.Ts
+	// resendResolved is set in actor mode when the barrier ts advances and
+	// the last resolved event added to the sorter was capped by the old
+	// barrier ts, it's resent by handlePullerOutput.
+	resendResolved bool
 
 	replConfig *config.ReplicaConfig
 
 	// isTableActorMode identify if the sorter node is run is actor mode, todo: remove it after GA
 	isTableActorMode bool
+	// pendingEvents are the events pulled from the puller output but not
+	// accepted by the sorter yet, only used in actor mode.
+	pendingEvents []*model.PolymorphicEvent
+
+	// idleTimeout is how long the table receives no row changes before it's
+	// hibernated, 0 means it's never hibernated. Only used in actor mode.
+	idleTimeout time.Duration
+	// lastRowTime is the time the last row change is pulled.
+	lastRowTime time.Time
+	// hibernated is true if the sorter is closed because the table is idle,
+	// the resolved events are sent to the next node directly then.
+	hibernated bool
+
+	// lastEventTs is the commit ts of the last row change added to the
+	// sorter, it's used in diagnostics only.
+	lastEventTs model.Ts
+	// diskUsage reports the disk usage of the running sorter, it's nil if the
+	// sorter doesn't write to disk or the table is hibernated.
+	diskUsageMu sync.Mutex
+	diskUsage   sorterDiskUsage
+}
+
+// sorterDiskUsage is implemented by the sorters which write events to disk,
+// e.g. the unified sorter.
+type sorterDiskUsage interface {
+	OnDiskDataSize() int64
 }
 
 func newSorterNode(
 	tableName string, tableID model.TableID, startTs model.Ts,
 	flowController tableFlowController, mounter entry.Mounter,
-	replConfig *config.ReplicaConfig,
+	replConfig *config.ReplicaConfig, admission *admissionController,
 ) *sorterNode {
 	return &sorterNode{
 		tableName:      tableName,
 		tableID:        tableID,
 		flowController: flowController,
+		admission:      admission,
 		mounter:        mounter,
 		resolvedTs:     startTs,
 		barrierTs:      startTs,
 		replConfig:     replConfig,
+		idleTimeout:    replConfig.Hibernation.IdleTimeout(),
+		lastRowTime:    time.Now(),
 	}
 }
 
 func (n *sorterNode) Init(ctx pipeline.NodeContext) error {
 	wg := errgroup.Group{}
-	return n.start(ctx, false, &wg, 0, nil)
-}
-
-func createSorter(ctx pipeline.NodeContext, tableName string, tableID model.TableID) (sorter.EventSorter, error) {
-	sortEngine := ctx.ChangefeedVars().Info.Engine
-	switch sortEngine {
-	case model.SortInMemory:
-		return memory.NewEntrySorter(), nil
-	case model.SortUnified, model.SortInFile /* `file` becomes an alias of `unified` for backward compatibility */ :
-		if sortEngine == model.SortInFile {
-			log.Warn("File sorter is obsolete and replaced by unified sorter. Please revise your changefeed settings",
-				zap.String("changefeed", ctx.ChangefeedVars().ID), zap.String("tableName", tableName))
-		}
-
-		if config.GetGlobalServerConfig().Debug.EnableDBSorter {
-			startTs := ctx.ChangefeedVars().Info.StartTs
-			ssystem := ctx.GlobalVars().SorterSystem
-			dbActorID := ssystem.DBActorID(uint64(tableID))
-			compactScheduler := ctx.GlobalVars().SorterSystem.CompactScheduler()
-			levelSorter, err := leveldb.NewSorter(
-				ctx, tableID, startTs, ssystem.DBRouter, dbActorID,
-				ssystem.WriterSystem, ssystem.WriterRouter,
-				ssystem.ReaderSystem, ssystem.ReaderRouter,
-				compactScheduler, config.GetGlobalServerConfig().Debug.DB)
-			if err != nil {
-				return nil, err
-			}
-			return levelSorter, nil
-		}
-		// Sorter dir has been set and checked when server starts.
-		// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
-		sortDir := config.GetGlobalServerConfig().Sorter.SortDir
-		unifiedSorter, err := unified.NewUnifiedSorter(sortDir, ctx.ChangefeedVars().ID, tableName, tableID)
-		if err != nil {
-			return nil, err
-		}
-		return unifiedSorter, nil
-	default:
-		return nil, cerror.ErrUnknownSortEngine.GenWithStackByArgs(sortEngine)
-	}
+	return n.start(ctx, false, &wg, nil)
 }
 
 func (n *sorterNode) start(
 	ctx pipeline.NodeContext, isTableActorMode bool, eg *errgroup.Group,
-	tableActorID actor.ID, tableActorRouter *actor.Router[pmessage.Message],
+	tableActorTicker *actorTicker,
 ) error {
 	n.isTableActorMode = isTableActorMode
 	n.eg = eg
+	n.nodeCtx = ctx
+	n.tableActorTicker = tableActorTicker
 	stdCtx, cancel := context.WithCancel(ctx)
+	n.stdCtx = stdCtx
 	n.cancel = cancel
+	return n.runSorter()
+}
 
+// runSorter creates a sorter and spawns the goroutines running it, it's
+// called again when the table wakes up from hibernation.
+func (n *sorterNode) runSorter() error {
+	ctx, isTableActorMode, tableActorTicker := n.nodeCtx, n.isTableActorMode, n.tableActorTicker
 	eventSorter, err := createSorter(ctx, n.tableName, n.tableID)
 	if err != nil {
 		return errors.Trace(err)
@@ -145,8 +158,15 @@ func (n *sorterNode) start(
 	failpoint.Inject("ProcessorAddTableError", func() {
 		failpoint.Return(errors.New("processor add table injected error"))
 	})
+	stdCtx, cancel := context.WithCancel(n.stdCtx)
+	n.cancelSorter = cancel
 	n.eg.Go(func() error {
-		ctx.Throw(errors.Trace(eventSorter.Run(stdCtx)))
+		err := eventSorter.Run(stdCtx)
+		if n.stdCtx.Err() == nil && stdCtx.Err() != nil {
+			// The sorter is closed because the table is hibernated.
+			return nil
+		}
+		ctx.Throw(errors.Trace(err))
 		return nil
 	})
 	n.eg.Go(func() error {
@@ -177,6 +197,7 @@ func (n *sorterNode) start(
 					log.Panic("unexpected empty msg", zap.Reflect("msg", msg))
 				}
 				if msg.RawKV.OpType != model.OpTypeResolved {
+					n.admission.onSorterOutput()
 					err := n.mounter.DecodeEvent(ctx, msg)
 					if err != nil {
 						return errors.Trace(err)
@@ -230,18 +251,26 @@ func (n *sorterNode) start(
 					if msg.CRTs < lastSentResolvedTs {
 						continue
 					}
-					if isTableActorMode {
-						msg := message.ValueMessage(pmessage.TickMessage())
-						_ = tableActorRouter.Send(tableActorID, msg)
-					}
 					lastSentResolvedTs = msg.CRTs
 					lastSendResolvedTsTime = time.Now()
 				}
 				ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
+				if msg.RawKV.OpType == model.OpTypeResolved {
+					atomic.StoreUint64(&n.outputResolvedTs, msg.CRTs)
+					if isTableActorMode {
+						// Tick the table actor after the resolved event is sent,
+						// so it's handled by the coalesced Tick.
+						tableActorTicker.tick()
+					}
+				}
 			}
 		}
 	})
 	n.sorter = eventSorter
+	diskUsage, _ := eventSorter.(sorterDiskUsage)
+	n.diskUsageMu.Lock()
+	n.diskUsage = diskUsage
+	n.diskUsageMu.Unlock()
 	return nil
 }
 
@@ -253,34 +282,216 @@ func (n *sorterNode) Receive(ctx pipeline.NodeContext) error {
 
 // handleRawEvent process the raw kv event,send it to sorter
 func (n *sorterNode) handleRawEvent(ctx context.Context, event *model.PolymorphicEvent) {
+	event = n.adjustRawEvent(event)
+	if event.RawKV == nil || event.RawKV.OpType != model.OpTypeResolved {
+		n.admission.onSorterInput()
+		atomic.StoreUint64(&n.lastEventTs, event.CRTs)
+	}
+	n.sorter.AddEntry(ctx, event)
+}
+
+// tryHandleRawEvent is like handleRawEvent, but it returns false instead of
+// blocking if the sorter can't accept the event for now.
+func (n *sorterNode) tryHandleRawEvent(ctx context.Context, event *model.PolymorphicEvent) (bool, error) {
+	event = n.adjustRawEvent(event)
+	ok, err := n.sorter.TryAddEntry(ctx, event)
+	if err != nil || !ok {
+		return false, err
+	}
+	if event.RawKV == nil || event.RawKV.OpType != model.OpTypeResolved {
+		n.admission.onSorterInput()
+		atomic.StoreUint64(&n.lastEventTs, event.CRTs)
+	} else {
+		n.inputResolvedTs = event.CRTs
+	}
+	return true, nil
+}
+
+// adjustRawEvent updates the resolved ts by a resolved event, and replaces
+// it with a resolved event of the barrier ts if it exceeds the barrier ts.
+// It's idempotent, so an event not accepted by the sorter can be retried.
+func (n *sorterNode) adjustRawEvent(event *model.PolymorphicEvent) *model.PolymorphicEvent {
 	rawKV := event.RawKV
-	if rawKV != nil && rawKV.OpType == model.OpTypeResolved {
-		// Puller resolved ts should not fall back.
-		resolvedTs := rawKV.CRTs
-		oldResolvedTs := atomic.SwapUint64(&n.resolvedTs, resolvedTs)
-		if oldResolvedTs > resolvedTs {
-			log.Panic("resolved ts regression",
-				zap.Int64("tableID", n.tableID),
-				zap.Uint64("resolvedTs", resolvedTs),
-				zap.Uint64("oldResolvedTs", oldResolvedTs))
+	if rawKV == nil || rawKV.OpType != model.OpTypeResolved {
+		return event
+	}
+	// Puller resolved ts should not fall back.
+	resolvedTs := rawKV.CRTs
+	oldResolvedTs := atomic.SwapUint64(&n.resolvedTs, resolvedTs)
+	if oldResolvedTs > resolvedTs {
+		log.Panic("resolved ts regression",
+			zap.Int64("tableID", n.tableID),
+			zap.Uint64("resolvedTs", resolvedTs),
+			zap.Uint64("oldResolvedTs", oldResolvedTs))
+	}
+	atomic.StoreUint64(&n.resolvedTs, rawKV.CRTs)
+
+	if resolvedTs > n.BarrierTs() &&
+		!redo.IsConsistentEnabled(n.replConfig.Consistent.Level) {
+		// Do not send resolved ts events that is larger than
+		// barrier ts.
+		// When DDL puller stall, resolved events that outputted by
+		// sorter may pile up in memory, as they have to wait DDL.
+		//
+		// Disabled if redolog is on, it requires sink reports
+		// resolved ts, conflicts to this change.
+		// TODO: Remove redolog check once redolog decouples for global
+		//       resolved ts.
+		event = model.NewResolvedPolymorphicEvent(0, n.BarrierTs())
+	}
+	return event
+}
+
+// handlePullerOutput adds the events pulled from the puller output to the
+// sorter without blocking, the events not accepted by the sorter are kept and
+// retried next time. It's called by the table actor.
+func (n *sorterNode) handlePullerOutput(ctx context.Context, output *pullerOutput) error {
+	if n.resendResolved {
+		ok, err := n.tryResendResolvedEvent(ctx)
+		if err != nil || !ok {
+			return errors.Trace(err)
+		}
+		n.resendResolved = false
+	}
+	retried := len(n.pendingEvents) > 0
+	if !retried {
+		n.pendingEvents = output.pull()
+	}
+	drained, err := n.addPendingEvents(ctx, output)
+	if err != nil || !drained || !retried {
+		return errors.Trace(err)
+	}
+	// The puller output doesn't tick the table actor again for the events
+	// pushed while the retried events were pending, so they're pulled now.
+	n.pendingEvents = output.pull()
+	_, err = n.addPendingEvents(ctx, output)
+	return errors.Trace(err)
+}
+
+// addPendingEvents adds the pending events to the sorter, or sends the
+// resolved events to the next node if the table is hibernated. It returns
+// true if all the pending events are accepted.
+func (n *sorterNode) addPendingEvents(ctx context.Context, output *pullerOutput) (bool, error) {
+	accepted := 0
+	for _, event := range n.pendingEvents {
+		var (
+			ok  bool
+			err error
+		)
+		if event.RawKV == nil || event.RawKV.OpType != model.OpTypeResolved {
+			n.lastRowTime = time.Now()
+			if n.hibernated {
+				if err := n.wakeUp(); err != nil {
+					return false, errors.Trace(err)
+				}
+			}
+		}
+		if n.hibernated {
+			ok = n.tryForwardResolvedEvent(event)
+		} else {
+			ok, err = n.tryHandleRawEvent(ctx, event)
+		}
+		if err != nil {
+			return false, errors.Trace(err)
 		}
-		atomic.StoreUint64(&n.resolvedTs, rawKV.CRTs)
-
-		if resolvedTs > n.BarrierTs() &&
-			!redo.IsConsistentEnabled(n.replConfig.Consistent.Level) {
-			// Do not send resolved ts events that is larger than
-			// barrier ts.
-			// When DDL puller stall, resolved events that outputted by
-			// sorter may pile up in memory, as they have to wait DDL.
-			//
-			// Disabled if redolog is on, it requires sink reports
-			// resolved ts, conflicts to this change.
-			// TODO: Remove redolog check once redolog decouples for global
-			//       resolved ts.
-			event = model.NewResolvedPolymorphicEvent(0, n.BarrierTs())
+		if !ok {
+			// The sorter or the next node of a hibernated table is full, the
+			// rest are retried when the table actor is ticked next time.
+			break
 		}
+		accepted++
 	}
-	n.sorter.AddEntry(ctx, event)
+	output.release(n.pendingEvents[:accepted])
+	n.pendingEvents = n.pendingEvents[accepted:]
+	return len(n.pendingEvents) == 0, nil
+}
+
+// tryResendResolvedEvent adds a resolved event of the latest resolved ts to
+// the sorter again, it's capped by the new barrier ts. All the events before
+// the latest resolved event have been accepted by the sorter, so it's safe
+// even if the resolved event itself is still pending.
+func (n *sorterNode) tryResendResolvedEvent(ctx context.Context) (bool, error) {
+	event := model.NewResolvedPolymorphicEvent(0, n.ResolvedTs())
+	if n.hibernated {
+		return n.tryForwardResolvedEvent(event), nil
+	}
+	return n.tryHandleRawEvent(ctx, event)
+}
+
+// tryHibernate closes the sorter if the table has received no row changes
+// for idleTimeout and all the events have been output by the sorter, so the
+// sorter resources and the memory quota are released. The puller is replaced
+// by the probes of the puller node then, and the resolved events are forwarded
+// by tryForwardResolvedEvent. It returns true if the table is hibernated.
+func (n *sorterNode) tryHibernate(now time.Time) bool {
+	if !n.isTableActorMode || n.idleTimeout <= 0 || n.hibernated ||
+		len(n.pendingEvents) > 0 || now.Sub(n.lastRowTime) < n.idleTimeout {
+		return false
+	}
+	if n.admission.pendingEvents() > 0 ||
+		atomic.LoadUint64(&n.outputResolvedTs) < n.inputResolvedTs {
+		// Wait until the sorter is drained, so no event is lost and the
+		// resolved ts never regresses after the sorter is closed.
+		return false
+	}
+	n.cancelSorter()
+	n.sorter = nil
+	n.diskUsageMu.Lock()
+	n.diskUsage = nil
+	n.diskUsageMu.Unlock()
+	n.hibernated = true
+	n.flowController.Hibernate()
+	hibernatedTableGauge.WithLabelValues(n.nodeCtx.ChangefeedVars().ID, n.tableName).Set(1)
+	log.Info("table hibernated",
+		zap.String("changefeed", n.nodeCtx.ChangefeedVars().ID),
+		zap.Int64("tableID", n.tableID),
+		zap.String("tableName", n.tableName),
+		zap.Duration("idle", now.Sub(n.lastRowTime)),
+		zap.Uint64("resolvedTs", n.ResolvedTs()))
+	return true
+}
+
+// wakeUp runs a new sorter for the hibernated table.
+func (n *sorterNode) wakeUp() error {
+	if err := n.runSorter(); err != nil {
+		return errors.Trace(err)
+	}
+	n.hibernated = false
+	n.flowController.WakeUp()
+	changefeed := n.nodeCtx.ChangefeedVars().ID
+	hibernatedTableGauge.WithLabelValues(changefeed, n.tableName).Set(0)
+	tableWakeUpCount.WithLabelValues(changefeed, n.tableName).Inc()
+	log.Info("table woken up",
+		zap.String("changefeed", changefeed),
+		zap.Int64("tableID", n.tableID),
+		zap.String("tableName", n.tableName),
+		zap.Uint64("resolvedTs", n.ResolvedTs()))
+	return nil
+}
+
+// tryForwardResolvedEvent sends a resolved event to the next node directly
+// while the table is hibernated, it returns false if the next node is busy.
+func (n *sorterNode) tryForwardResolvedEvent(event *model.PolymorphicEvent) bool {
+	event = n.adjustRawEvent(event)
+	msg := pmessage.PolymorphicEventMessage(model.NewResolvedPolymorphicEvent(0, event.CRTs))
+	if !n.nodeCtx.(*actorNodeContext).TrySendToNextNode(msg) {
+		return false
+	}
+	n.inputResolvedTs = event.CRTs
+	atomic.StoreUint64(&n.outputResolvedTs, event.CRTs)
+	return true
+}
+
+// onClose is called when the table actor is closed, the hibernation metrics
+// of the table are removed.
+func (n *sorterNode) onClose() {
+	n.hibernated = false
+	if n.nodeCtx == nil {
+		return
+	}
+	changefeed := n.nodeCtx.ChangefeedVars().ID
+	hibernatedTableGauge.DeleteLabelValues(changefeed, n.tableName)
+	tableWakeUpCount.DeleteLabelValues(changefeed, n.tableName)
 }
 
 func (n *sorterNode) TryHandleDataMessage(
@@ -291,7 +502,9 @@ func (n *sorterNode) TryHandleDataMessage(
 		n.handleRawEvent(ctx, msg.PolymorphicEvent)
 		return true, nil
 	case pmessage.MessageTypeBarrier:
-		n.updateBarrierTs(msg.BarrierTs)
+		if n.updateBarrierTs(msg.BarrierTs) {
+			n.handleRawEvent(ctx, model.NewResolvedPolymorphicEvent(0, n.ResolvedTs()))
+		}
 		fallthrough
 	default:
 		ctx.(pipeline.NodeContext).SendToNextNode(msg)
@@ -299,14 +512,25 @@ func (n *sorterNode) TryHandleDataMessage(
 	}
 }
 
-func (n *sorterNode) updateBarrierTs(barrierTs model.Ts) {
-	if barrierTs > n.BarrierTs() {
-		atomic.StoreUint64(&n.barrierTs, barrierTs)
+// updateBarrierTs updates the barrier ts, it returns true if the resolved
+// events added to the sorter were capped by the old barrier ts. The puller
+// may be paused by admission control and send no more resolved events, so
+// the latest resolved ts must be added to the sorter again, or the table
+// never reaches the new barrier ts.
+func (n *sorterNode) updateBarrierTs(barrierTs model.Ts) bool {
+	oldBarrierTs := n.BarrierTs()
+	if barrierTs <= oldBarrierTs {
+		return false
 	}
+	atomic.StoreUint64(&n.barrierTs, barrierTs)
+	return n.ResolvedTs() > oldBarrierTs &&
+		!redo.IsConsistentEnabled(n.replConfig.Consistent.Level)
 }
 
 func (n *sorterNode) releaseResource(_ context.Context, changefeedID string) {
 	defer tableMemoryHistogram.DeleteLabelValues(changefeedID)
+	defer tableAdmissionPausedDuration.DeleteLabelValues(changefeedID)
+	n.admission.close()
 	// Since the flowController is implemented by `Cond`, it is not cancelable by a context
 	// the flowController will be blocked in a background goroutine,
 	// We need to abort the flowController manually in the nodeRunner
@@ -327,3 +551,19 @@ func (n *sorterNode) ResolvedTs() model.Ts {
 func (n *sorterNode) BarrierTs() model.Ts {
 	return atomic.LoadUint64(&n.barrierTs)
 }
+
+// LastEventTs returns the commit ts of the last row change added to the sorter.
+func (n *sorterNode) LastEventTs() model.Ts {
+	return atomic.LoadUint64(&n.lastEventTs)
+}
+
+// OnDiskDataSize returns the size of the files written by the sorter, 0 is
+// returned if the sorter doesn't write to disk.
+func (n *sorterNode) OnDiskDataSize() int64 {
+	n.diskUsageMu.Lock()
+	defer n.diskUsageMu.Unlock()
+	if n.diskUsage == nil {
+		return 0
+	}
+	return n.diskUsage.OnDiskDataSize()
+}
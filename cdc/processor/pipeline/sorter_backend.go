@@ -0,0 +1,113 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sorter"
+	"github.com/pingcap/tiflow/cdc/sorter/leveldb"
+	"github.com/pingcap/tiflow/cdc/sorter/memory"
+	"github.com/pingcap/tiflow/cdc/sorter/unified"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/pipeline"
+	"go.uber.org/zap"
+)
+
+// SorterBackend creates the sorters of the tables in a sorter node.
+type SorterBackend interface {
+	// NewSorter creates the sorter of a table.
+	NewSorter(ctx pipeline.NodeContext, tableName string, tableID model.TableID) (sorter.EventSorter, error)
+}
+
+// newSorterBackend returns the sorter backend of the sort engine of a
+// changefeed. The unified sort engine is backed by the db sorter if it's
+// enabled in the server config, whose storage engine is configured by
+// debug.db.engine.
+func newSorterBackend(sortEngine model.SortEngine, serverCfg *config.ServerConfig) (SorterBackend, error) {
+	switch sortEngine {
+	case model.SortInMemory:
+		return memorySorterBackend{}, nil
+	case model.SortUnified, model.SortInFile /* `file` becomes an alias of `unified` for backward compatibility */ :
+		if serverCfg.Debug.EnableDBSorter {
+			return &dbSorterBackend{cfg: serverCfg.Debug.DB}, nil
+		}
+		// Sorter dir has been set and checked when server starts.
+		// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
+		return &unifiedSorterBackend{sortDir: serverCfg.Sorter.SortDir}, nil
+	default:
+		return nil, cerror.ErrUnknownSortEngine.GenWithStackByArgs(sortEngine)
+	}
+}
+
+// memorySorterBackend sorts the events of tables in memory.
+type memorySorterBackend struct{}
+
+func (memorySorterBackend) NewSorter(
+	ctx pipeline.NodeContext, tableName string, tableID model.TableID,
+) (sorter.EventSorter, error) {
+	return memory.NewEntrySorter(), nil
+}
+
+// unifiedSorterBackend sorts the events of tables in memory, and spills them
+// to files in sortDir if there are too many. The spill thresholds and sortDir
+// can be overridden by the sorter config of the changefeed.
+type unifiedSorterBackend struct {
+	sortDir string
+}
+
+func (b *unifiedSorterBackend) NewSorter(
+	ctx pipeline.NodeContext, tableName string, tableID model.TableID,
+) (sorter.EventSorter, error) {
+	var cfg *config.ChangefeedSorterConfig
+	if replicaConfig := ctx.ChangefeedVars().Info.Config; replicaConfig != nil {
+		cfg = replicaConfig.Sorter
+	}
+	return unified.NewUnifiedSorterWithConfig(
+		b.sortDir, ctx.ChangefeedVars().ID, tableName, tableID, cfg)
+}
+
+// dbSorterBackend sorts the events of tables in the dbs of the sorter system
+// of the capture.
+type dbSorterBackend struct {
+	cfg *config.DBConfig
+}
+
+func (b *dbSorterBackend) NewSorter(
+	ctx pipeline.NodeContext, tableName string, tableID model.TableID,
+) (sorter.EventSorter, error) {
+	startTs := ctx.ChangefeedVars().Info.StartTs
+	ssystem := ctx.GlobalVars().SorterSystem
+	dbActorID := ssystem.DBActorID(uint64(tableID))
+	compactScheduler := ssystem.CompactScheduler()
+	return leveldb.NewSorter(
+		ctx, tableID, startTs, ssystem.DBRouter, dbActorID,
+		ssystem.WriterSystem, ssystem.WriterRouter,
+		ssystem.ReaderSystem, ssystem.ReaderRouter,
+		compactScheduler, b.cfg)
+}
+
+func createSorter(ctx pipeline.NodeContext, tableName string, tableID model.TableID) (sorter.EventSorter, error) {
+	sortEngine := ctx.ChangefeedVars().Info.Engine
+	if sortEngine == model.SortInFile {
+		log.Warn("File sorter is obsolete and replaced by unified sorter. Please revise your changefeed settings",
+			zap.String("changefeed", ctx.ChangefeedVars().ID), zap.String("tableName", tableName))
+	}
+	backend, err := newSorterBackend(sortEngine, config.GetGlobalServerConfig())
+	if err != nil {
+		return nil, err
+	}
+	return backend.NewSorter(ctx, tableName, tableID)
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
@@ -25,9 +26,12 @@ import (
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pipeline"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 )
 
 func TestUnifiedSorterFileLockConflict(t *testing.T) {
@@ -57,7 +61,7 @@ func TestSorterResolvedTs(t *testing.T) {
 	t.Parallel()
 	sn := newSorterNode("tableName", 1, 1, nil, nil, &config.ReplicaConfig{
 		Consistent: &config.ConsistentConfig{},
-	})
+	}, nil)
 	sn.sorter = memory.NewEntrySorter()
 	require.EqualValues(t, 1, sn.ResolvedTs())
 	nctx := pipeline.NewNodeContext(
@@ -105,7 +109,7 @@ func TestSorterResolvedTsLessEqualBarrierTs(t *testing.T) {
 	s := &checkSorter{ch: sch}
 	sn := newSorterNode("tableName", 1, 1, nil, nil, &config.ReplicaConfig{
 		Consistent: &config.ConsistentConfig{},
-	})
+	}, nil)
 	sn.sorter = s
 
 	ch := make(chan pmessage.Message, 1)
@@ -155,11 +159,146 @@ func TestSorterResolvedTsLessEqualBarrierTs(t *testing.T) {
 	require.EqualValues(t, resolvedTs4.PolymorphicEvent, <-s.Output())
 }
 
+func TestSorterHandlePullerOutput(t *testing.T) {
+	t.Parallel()
+	sch := make(chan *model.PolymorphicEvent, 2)
+	s := &checkSorter{ch: sch}
+	sn := newSorterNode("tableName", 1, 1, nil, nil, &config.ReplicaConfig{
+		Consistent: &config.ConsistentConfig{},
+	}, nil)
+	sn.sorter = s
+	sn.updateBarrierTs(10)
+	output := newPullerOutput("changefeed-sorter", 1, 1024, func() {})
+	defer output.close()
+
+	ctx := context.Background()
+	require.Nil(t, output.push(ctx, newRowEvent(2, 10)))
+	require.Nil(t, output.push(ctx, newRowEvent(3, 10)))
+	require.Nil(t, output.push(ctx, model.NewResolvedPolymorphicEvent(0, 3)))
+
+	// The sorter accepts two events only, the rest is kept.
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.Len(t, sn.pendingEvents, 1)
+	require.Equal(t, float64(1), testutil.ToFloat64(output.metricPendingBytes))
+	require.EqualValues(t, 2, (<-sch).CRTs)
+	require.EqualValues(t, 3, (<-sch).CRTs)
+
+	// The kept events are retried before pulling new events, and the events
+	// pushed meanwhile are pulled once the kept events are accepted.
+	require.Nil(t, output.push(ctx, newRowEvent(4, 10)))
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.Empty(t, sn.pendingEvents)
+	require.EqualValues(t, 3, sn.ResolvedTs())
+	require.EqualValues(t, model.NewResolvedPolymorphicEvent(0, 3), <-sch)
+	require.EqualValues(t, 4, (<-sch).CRTs)
+	require.Equal(t, float64(0), testutil.ToFloat64(output.metricPendingBytes))
+
+	// The resolved ts capped by the barrier ts is resent once the barrier
+	// ts advances, even if the puller sends no more resolved events.
+	require.Nil(t, output.push(ctx, model.NewResolvedPolymorphicEvent(0, 12)))
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.EqualValues(t, model.NewResolvedPolymorphicEvent(0, 10), <-sch)
+	sn.resendResolved = sn.updateBarrierTs(15)
+	require.True(t, sn.resendResolved)
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.False(t, sn.resendResolved)
+	require.EqualValues(t, model.NewResolvedPolymorphicEvent(0, 12), <-sch)
+}
+
+func TestSorterHibernate(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx.ChangefeedVars().Info.Engine = model.SortInMemory
+	nodeCtx := newContext(ctx, "tableName", nil, 1,
+		ctx.ChangefeedVars(), ctx.GlobalVars(), throwDoNothing)
+	sn := newSorterNode("tableName", 1, 1, &mockFlowController{}, nil, &config.ReplicaConfig{
+		Consistent:  &config.ConsistentConfig{},
+		Hibernation: &config.HibernationConfig{Enable: true, IdleTimeoutInSec: 60},
+	}, newAdmissionController(nil, 1, config.TablePriorityNormal))
+	eg := &errgroup.Group{}
+	require.Nil(t, sn.start(nodeCtx, true, eg, nil))
+	defer func() {
+		sn.cancel()
+		_ = eg.Wait()
+	}()
+	output := newPullerOutput("changefeed-sorter", 1, 1024, func() {})
+	defer output.close()
+	gauge := hibernatedTableGauge.WithLabelValues(ctx.ChangefeedVars().ID, "tableName")
+
+	// The table is not hibernated until it's idle and the sorter is drained.
+	require.Nil(t, output.push(ctx, model.NewResolvedPolymorphicEvent(0, 2)))
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.False(t, sn.tryHibernate(time.Now()))
+	require.Eventually(t, func() bool {
+		return sn.tryHibernate(time.Now().Add(time.Minute))
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, sn.sorter)
+	require.Equal(t, float64(1), testutil.ToFloat64(gauge))
+	require.EqualValues(t, 2, nodeCtx.Message().PolymorphicEvent.CRTs)
+
+	// Resolved events are sent to the next node directly.
+	require.Nil(t, output.push(ctx, model.NewResolvedPolymorphicEvent(0, 3)))
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.EqualValues(t, 3, sn.ResolvedTs())
+	msg := nodeCtx.Message()
+	require.Equal(t, model.OpTypeResolved, msg.PolymorphicEvent.RawKV.OpType)
+	require.EqualValues(t, 3, msg.PolymorphicEvent.CRTs)
+
+	// A row change wakes the table up.
+	require.Nil(t, output.push(ctx, newRowEvent(4, 10)))
+	require.Nil(t, sn.handlePullerOutput(ctx, output))
+	require.False(t, sn.hibernated)
+	require.NotNil(t, sn.sorter)
+	require.Empty(t, sn.pendingEvents)
+	require.Equal(t, float64(0), testutil.ToFloat64(gauge))
+	// The row change is pending in the sorter.
+	require.False(t, sn.tryHibernate(time.Now().Add(time.Minute)))
+
+	// The metrics of the table are removed when it's closed.
+	sn.onClose()
+	require.Equal(t, 0, testutil.CollectAndCount(hibernatedTableGauge))
+}
+
 func TestSorterUpdateBarrierTs(t *testing.T) {
 	t.Parallel()
-	s := &sorterNode{barrierTs: 1}
-	s.updateBarrierTs(model.Ts(2))
+	s := &sorterNode{barrierTs: 1, replConfig: &config.ReplicaConfig{
+		Consistent: &config.ConsistentConfig{},
+	}}
+	require.False(t, s.updateBarrierTs(model.Ts(2)))
 	require.Equal(t, model.Ts(2), s.BarrierTs())
-	s.updateBarrierTs(model.Ts(1))
+	require.False(t, s.updateBarrierTs(model.Ts(1)))
 	require.Equal(t, model.Ts(2), s.BarrierTs())
+
+	// The resolved events after the old barrier ts were capped.
+	s.resolvedTs = 5
+	require.True(t, s.updateBarrierTs(model.Ts(3)))
+	require.True(t, s.updateBarrierTs(model.Ts(6)))
+	require.False(t, s.updateBarrierTs(model.Ts(7)))
+	s.replConfig.Consistent.Level = string(redo.ConsistentLevelEventual)
+	s.resolvedTs = 10
+	require.False(t, s.updateBarrierTs(model.Ts(8)))
+}
+
+func TestNewSorterBackend(t *testing.T) {
+	t.Parallel()
+	cfg := config.GetDefaultServerConfig()
+	cfg.Sorter.SortDir = "/tmp/sorter"
+
+	backend, err := newSorterBackend(model.SortInMemory, cfg)
+	require.Nil(t, err)
+	require.IsType(t, memorySorterBackend{}, backend)
+
+	cfg.Debug.EnableDBSorter = true
+	for _, engine := range []model.SortEngine{model.SortUnified, model.SortInFile} {
+		backend, err = newSorterBackend(engine, cfg)
+		require.Nil(t, err)
+		require.Equal(t, &dbSorterBackend{cfg: cfg.Debug.DB}, backend)
+	}
+
+	cfg.Debug.EnableDBSorter = false
+	backend, err = newSorterBackend(model.SortUnified, cfg)
+	require.Nil(t, err)
+	require.Equal(t, &unifiedSorterBackend{sortDir: "/tmp/sorter"}, backend)
+
+	_, err = newSorterBackend("unknown", cfg)
+	require.True(t, cerror.ErrUnknownSortEngine.Equal(err))
 }
@@ -15,52 +15,340 @@ package system
 
 import (
 	"context"
+	"fmt"
+	"runtime"
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/actor"
+	"github.com/pingcap/tiflow/pkg/actor/message"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
+	"go.uber.org/zap"
 )
 
+const (
+	// procsPerSystem is the GOMAXPROCS served by a table actor system if the
+	// count of systems is not configured.
+	procsPerSystem = 8
+	// maxWorkerNum is the max number of workers of all table actor systems,
+	// it's the same as the default of a single actor system.
+	maxWorkerNum = 64
+	// resizeInterval is the interval of checking GOMAXPROCS to add systems.
+	resizeInterval = 10 * time.Second
+	// transferInterval is the interval of transferring the actors that are
+	// busy in the last try.
+	transferInterval = 100 * time.Millisecond
+)
+
+// shard is a table actor system and its router.
+type shard struct {
+	system  *actor.System[pmessage.Message]
+	router  *actor.Router[pmessage.Message]
+	workers int
+}
+
 // System manages table pipeline global resource.
+//
+// Table actors are sharded across multiple actor systems, so a single system
+// doesn't become the scheduling bottleneck with lots of tables. An actor is
+// routed to a system by defaultRouter, and if the count of systems follows
+// GOMAXPROCS, the systems are replaced by more systems once GOMAXPROCS grows,
+// so the total workers of them stays within maxWorkerNum. The actors are
+// transferred to the new systems in background, and the old systems are
+// stopped once they have no actor.
 type System struct {
-	tableActorSystem *actor.System[pmessage.Message]
-	tableActorRouter *actor.Router[pmessage.Message]
+	// count is the configured count of systems, 0 means it follows GOMAXPROCS.
+	count int
+
+	mu     sync.RWMutex
+	shards []*shard
+	// moving is the actors that stay in the replaced systems, they are routed
+	// to the replaced systems until being transferred.
+	moving map[actor.ID]*shard
+	// retired is the replaced systems that still have actors.
+	retired []*shard
 
+	router *Router
 	lastID uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // NewSystem returns a system.
 func NewSystem() *System {
-	return &System{
+	s := &System{
+		moving: make(map[actor.ID]*shard),
 		lastID: 1,
 	}
+	if cfg := config.GetGlobalServerConfig().Debug.TableActor; cfg != nil {
+		s.count = cfg.SystemCount
+	}
+	s.router = &Router{sys: s}
+	return s
 }
 
 // Start starts a system.
 func (s *System) Start(ctx context.Context) error {
-	// todo: make the table actor system configurable
-	sys, router := actor.NewSystemBuilder[pmessage.Message]("table").Build()
-	s.tableActorSystem, s.tableActorRouter = sys, router
-	s.tableActorSystem.Start(ctx)
+	ctx, s.cancel = context.WithCancel(ctx)
+	count := s.count
+	if count <= 0 {
+		count = systemCount(runtime.GOMAXPROCS(0))
+	}
+	s.resize(ctx, count)
+	log.Info("table actor systems started",
+		zap.Int("count", count), zap.Int("configuredCount", s.count))
+	if s.count <= 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.run(ctx)
+		}()
+	}
 	return nil
 }
 
 // Stop stops a system.
 func (s *System) Stop() {
-	s.tableActorSystem.Stop()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sh := range s.retired {
+		sh.system.Stop()
+	}
+	for _, sh := range s.shards {
+		sh.system.Stop()
+	}
 }
 
 // Router returns the table actor router.
-func (s *System) Router() *actor.Router[pmessage.Message] {
-	return s.tableActorRouter
+func (s *System) Router() *Router {
+	return s.router
 }
 
-// System returns the system.
-func (s *System) System() *actor.System[pmessage.Message] {
-	return s.tableActorSystem
+// Spawn spawns a table actor in the system that it's routed to.
+func (s *System) Spawn(
+	mb actor.Mailbox[pmessage.Message], a actor.Actor[pmessage.Message],
+) error {
+	// Hold the read lock, so actors are not spawned while systems are being
+	// added, otherwise they may be spawned in the old systems but routed to
+	// the new ones.
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.routeLocked(mb.ID()).system.Spawn(mb, a)
 }
 
 // ActorID returns an ActorID correspond with tableID.
 func (s *System) ActorID() actor.ID {
 	return actor.ID(atomic.AddUint64(&s.lastID, 1))
 }
+
+// route returns the system of an actor.
+func (s *System) route(id actor.ID) *shard {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.routeLocked(id)
+}
+
+func (s *System) routeLocked(id actor.ID) *shard {
+	if sh, ok := s.moving[id]; ok {
+		return sh
+	}
+	return s.shards[defaultRouter(id, len(s.shards))]
+}
+
+// run replaces the systems once GOMAXPROCS grows and transfers actors to the
+// new systems until the context is canceled.
+func (s *System) run(ctx context.Context) {
+	resizeTicker := time.NewTicker(resizeInterval)
+	defer resizeTicker.Stop()
+	transferTicker := time.NewTicker(transferInterval)
+	defer transferTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resizeTicker.C:
+			s.resize(ctx, systemCount(runtime.GOMAXPROCS(0)))
+		case <-transferTicker.C:
+		}
+		s.transferActors()
+	}
+}
+
+// resize replaces the systems with n systems if there are less than n, the
+// workers are divided among the new systems. The actors stay in the replaced
+// systems until they are transferred. Shrinking is not supported, as idle
+// systems cost little.
+func (s *System) resize(ctx context.Context, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := len(s.shards)
+	if n <= old {
+		return
+	}
+	workers := maxWorkerNum
+	if procs := runtime.GOMAXPROCS(0); procs*8 < workers {
+		workers = procs * 8
+	}
+	shards := make([]*shard, 0, n)
+	for i := 0; i < n; i++ {
+		name := "table"
+		if i != 0 {
+			name = fmt.Sprintf("table-%d", i)
+		}
+		sh := &shard{workers: workers / n}
+		if sh.workers < 1 {
+			sh.workers = 1
+		}
+		sh.system, sh.router = actor.NewSystemBuilder[pmessage.Message](name).
+			WorkerNumber(sh.workers).Build()
+		sh.system.Start(ctx)
+		shards = append(shards, sh)
+	}
+	for _, sh := range s.shards {
+		for _, id := range sh.system.ActorIDs() {
+			s.moving[id] = sh
+		}
+	}
+	s.retired = append(s.retired, s.shards...)
+	s.shards = shards
+	if old != 0 {
+		log.Info("table actor systems replaced",
+			zap.Int("oldCount", old), zap.Int("newCount", n),
+			zap.Int("workersPerSystem", shards[0].workers),
+			zap.Int("movingActors", len(s.moving)))
+	}
+}
+
+// transferActors transfers the moving actors to the systems they are routed
+// to, the busy ones are left to the next try. The replaced systems are
+// stopped once all their actors are transferred or stopped.
+func (s *System) transferActors() {
+	type move struct {
+		id       actor.ID
+		from, to *actor.System[pmessage.Message]
+	}
+	s.mu.RLock()
+	moves := make([]move, 0, len(s.moving))
+	for id, from := range s.moving {
+		to := s.shards[defaultRouter(id, len(s.shards))]
+		moves = append(moves, move{id: id, from: from.system, to: to.system})
+	}
+	s.mu.RUnlock()
+
+	done := func(id actor.ID) func() {
+		return func() {
+			s.mu.Lock()
+			delete(s.moving, id)
+			s.mu.Unlock()
+		}
+	}
+	for _, m := range moves {
+		_, err := m.from.Transfer(m.id, m.to, done(m.id))
+		if err != nil {
+			// The actor has been stopped.
+			done(m.id)()
+		}
+	}
+
+	s.mu.Lock()
+	var stopped []*shard
+	retired := s.retired[:0]
+	for _, sh := range s.retired {
+		if len(sh.system.ActorIDs()) == 0 {
+			stopped = append(stopped, sh)
+		} else {
+			retired = append(retired, sh)
+		}
+	}
+	s.retired = retired
+	s.mu.Unlock()
+	for _, sh := range stopped {
+		sh.system.Stop()
+	}
+}
+
+// systemCount returns the count of systems for GOMAXPROCS.
+func systemCount(procs int) int {
+	if procs < procsPerSystem {
+		return 1
+	}
+	return procs / procsPerSystem
+}
+
+// defaultRouter routes an actor to one of n systems by jump consistent hash,
+// which spreads the sequential actor IDs evenly.
+func defaultRouter(id actor.ID, n int) int {
+	// Actor IDs are sequential, mix them before hashing.
+	key := uint64(id)
+	key ^= key >> 33
+	key *= 0xff51afd7ed558ccd
+	key ^= key >> 33
+	key *= 0xc4ceb9fe1a85ec53
+	key ^= key >> 33
+
+	b, j := int64(-1), int64(0)
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// Router sends messages to the table actors in all systems.
+type Router struct {
+	sys *System
+}
+
+// Send a message to an actor. It's a non-blocking send.
+// ErrMailboxFull when the actor full.
+// ErrActorNotFound when the actor not found.
+func (r *Router) Send(id actor.ID, msg message.Message[pmessage.Message]) error {
+	return r.SendPriority(id, msg, actor.PriorityNormal)
+}
+
+// SendPriority sends a message to an actor at the priority.
+// It's a non-blocking send.
+// ErrMailboxFull when the lane of the priority is full.
+// ErrActorNotFound when the actor not found.
+func (r *Router) SendPriority(id actor.ID, msg message.Message[pmessage.Message], priority actor.Priority) error {
+	return r.send(id, func(router *actor.Router[pmessage.Message]) error {
+		return router.SendPriority(id, msg, priority)
+	})
+}
+
+// SendPriorityB sends a message to an actor at the priority, blocks when
+// the lane of the priority is full.
+// ErrActorNotFound when the actor not found.
+// Canceled or DeadlineExceeded when the context is canceled or done.
+func (r *Router) SendPriorityB(
+	ctx context.Context, id actor.ID, msg message.Message[pmessage.Message], priority actor.Priority,
+) error {
+	return r.send(id, func(router *actor.Router[pmessage.Message]) error {
+		return router.SendPriorityB(ctx, id, msg, priority)
+	})
+}
+
+// send sends a message with the router of the system of the actor, it retries
+// if the actor is transferred to another system during sending.
+func (r *Router) send(id actor.ID, send func(*actor.Router[pmessage.Message]) error) error {
+	for {
+		sh := r.sys.route(id)
+		err := send(sh.router)
+		if err == nil || !cerror.ErrActorNotFound.Equal(err) {
+			return err
+		}
+		if r.sys.route(id) == sh {
+			return err
+		}
+	}
+}
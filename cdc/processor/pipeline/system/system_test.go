@@ -17,7 +17,11 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/pingcap/tiflow/pkg/actor"
+	"github.com/pingcap/tiflow/pkg/actor/message"
+	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,3 +55,105 @@ func TestActorID(t *testing.T) {
 		idMap[id] = struct{}{}
 	}
 }
+
+func TestDefaultRouter(t *testing.T) {
+	t.Parallel()
+
+	for n := 1; n < 16; n++ {
+		counts := make([]int, n)
+		for id := actor.ID(0); id < 10000; id++ {
+			i := defaultRouter(id, n)
+			counts[i]++
+			// Actors are either kept or moved to the added system.
+			j := defaultRouter(id, n+1)
+			require.True(t, j == i || j == n, "id %d, n %d", id, n)
+		}
+		for _, c := range counts {
+			require.InDelta(t, 10000/n, c, float64(10000/n)*0.2)
+		}
+	}
+}
+
+func TestSystemCount(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 1, systemCount(1))
+	require.Equal(t, 1, systemCount(8))
+	require.Equal(t, 2, systemCount(16))
+	require.Equal(t, 12, systemCount(96))
+}
+
+type forwardActor struct {
+	ch chan<- message.Message[pmessage.Message]
+}
+
+func (f *forwardActor) Poll(ctx context.Context, msgs []message.Message[pmessage.Message]) bool {
+	for _, msg := range msgs {
+		f.ch <- msg
+	}
+	return true
+}
+
+func (f *forwardActor) OnClose() {}
+
+func TestResize(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sys := NewSystem()
+	sys.count = 1
+	require.Nil(t, sys.Start(ctx))
+	defer sys.Stop()
+
+	ch := make(chan message.Message[pmessage.Message], 128)
+	ids := make([]actor.ID, 0, 100)
+	for i := 0; i < 100; i++ {
+		id := sys.ActorID()
+		require.Nil(t, sys.Spawn(actor.NewMailbox[pmessage.Message](id, 1), &forwardActor{ch: ch}))
+		ids = append(ids, id)
+	}
+	send := func() {
+		for _, id := range ids {
+			msg := message.ValueMessage(pmessage.TickMessage())
+			require.Nil(t, sys.Router().Send(id, msg))
+			select {
+			case <-ch:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+		}
+	}
+	send()
+
+	// Actors stay in the replaced system before they are transferred.
+	sys.resize(ctx, 4)
+	require.Len(t, sys.shards, 4)
+	require.Len(t, sys.moving, 100)
+	require.Len(t, sys.retired, 1)
+	require.Len(t, sys.retired[0].system.ActorIDs(), 100)
+	// The workers are divided among the new systems.
+	totalWorkers := 0
+	for _, sh := range sys.shards {
+		totalWorkers += sh.workers
+	}
+	require.LessOrEqual(t, totalWorkers, maxWorkerNum)
+	send()
+
+	// The replaced system is stopped once its actors are transferred.
+	sys.transferActors()
+	require.Empty(t, sys.moving)
+	require.Empty(t, sys.retired)
+	for i, sh := range sys.shards {
+		actorIDs := sh.system.ActorIDs()
+		require.NotEmpty(t, actorIDs)
+		for _, id := range actorIDs {
+			require.Equal(t, i, defaultRouter(id, 4))
+		}
+	}
+	send()
+
+	// Shrinking is not supported.
+	sys.resize(ctx, 2)
+	require.Len(t, sys.shards, 4)
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
-	"github.com/pingcap/tiflow/cdc/sink/common"
 	serverConfig "github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -49,10 +48,16 @@ type TablePipeline interface {
 	CheckpointTs() model.Ts
 	// UpdateBarrierTs updates the barrier ts in this table pipeline
 	UpdateBarrierTs(ts model.Ts)
-	// AsyncStop tells the pipeline to stop, and returns true is the pipeline is already stopped.
+	// AsyncStop tells the pipeline to stop after its sink is flushed exactly to
+	// targetTs, and returns true if the stop signal is sent or the pipeline is
+	// already stopped. The pipeline stops at once if its checkpoint ts is
+	// already targetTs or larger. The final checkpoint ts is reported by
+	// CheckpointTs once Status is TableStatusStopped.
 	AsyncStop(targetTs model.Ts) bool
 	// Workload returns the workload of this table
 	Workload() model.WorkloadInfo
+	// Diagnostics returns the runtime state of the nodes of this table pipeline
+	Diagnostics() *model.TablePipelineDiagnostics
 	// Status returns the status of this table pipeline
 	Status() TableStatus
 	// Cancel stops this table pipeline immediately and destroy all resources created by this table pipeline
@@ -82,6 +87,8 @@ type tableFlowController interface {
 	Release(resolvedTs uint64)
 	Abort()
 	GetConsumption() uint64
+	Hibernate()
+	WakeUp()
 }
 
 // ResolvedTs returns the resolved ts in this table pipeline
@@ -112,7 +119,8 @@ func (t *tablePipelineImpl) UpdateBarrierTs(ts model.Ts) {
 // AsyncStop tells the pipeline to stop, and returns true if the pipeline is already stopped.
 func (t *tablePipelineImpl) AsyncStop(targetTs model.Ts) bool {
 	err := t.p.SendToFirstNode(pmessage.CommandMessage(&pmessage.Command{
-		Tp: pmessage.CommandTypeStop,
+		Tp:     pmessage.CommandTypeStopAtTs,
+		StopTs: targetTs,
 	}))
 	log.Info("send async stop signal to table", zap.Int64("tableID", t.tableID), zap.Uint64("targetTs", targetTs))
 	if err != nil {
@@ -136,6 +144,13 @@ func (t *tablePipelineImpl) Workload() model.WorkloadInfo {
 	return workload
 }
 
+// Diagnostics returns the runtime state of the nodes of this table pipeline
+func (t *tablePipelineImpl) Diagnostics() *model.TablePipelineDiagnostics {
+	d := diagnoseTable(t.tableID, t.tableName, nil, t.sorterNode, t.sinkNode)
+	d.ResolvedTs = t.ResolvedTs()
+	return d
+}
+
 // Status returns the status of this table pipeline, sinkNode maintains the table status
 func (t *tablePipelineImpl) Status() TableStatus {
 	return t.sinkNode.Status()
@@ -178,6 +193,25 @@ func NewTablePipeline(ctx cdcContext.Context,
 	replicaInfo *model.TableReplicaInfo,
 	sink sink.Sink,
 	targetTs model.Ts,
+	admission *AdmissionManager,
+	admissionPriority string,
+) TablePipeline {
+	return newTablePipeline(ctx, mounter, tableID, tableName, replicaInfo, sink, targetTs,
+		admission, admissionPriority, "")
+}
+
+// newTablePipeline creates a table pipeline, the events are pulled from TiKV
+// if recordPath is empty, otherwise they are replayed from the record file.
+func newTablePipeline(ctx cdcContext.Context,
+	mounter entry.Mounter,
+	tableID model.TableID,
+	tableName string,
+	replicaInfo *model.TableReplicaInfo,
+	sink sink.Sink,
+	targetTs model.Ts,
+	admission *AdmissionManager,
+	admissionPriority string,
+	recordPath string,
 ) TablePipeline {
 	ctx, cancel := cdcContext.WithCancel(ctx)
 	changefeed := ctx.ChangefeedVars().ID
@@ -196,7 +230,7 @@ func NewTablePipeline(ctx cdcContext.Context,
 		zap.String("tableName", tableName),
 		zap.Int64("tableID", tableID),
 		zap.Uint64("quota", perTableMemoryQuota))
-	flowController := common.NewTableFlowController(perTableMemoryQuota)
+	flowController := ctx.GlobalVars().MemoryQuotaManager.NewTableFlowController(perTableMemoryQuota)
 	config := ctx.ChangefeedVars().Info.Config
 	cyclicEnabled := config.Cyclic != nil && config.Cyclic.IsEnabled()
 	runnerSize := defaultRunnersSize
@@ -205,11 +239,16 @@ func NewTablePipeline(ctx cdcContext.Context,
 	}
 
 	p := pipeline.NewPipeline(ctx, 500*time.Millisecond, runnerSize, defaultOutputChannelSize)
+	admissionController := newAdmissionController(admission, tableID, admissionPriority)
 	sorterNode := newSorterNode(tableName, tableID, replicaInfo.StartTs,
-		flowController, mounter, replConfig)
+		flowController, mounter, replConfig, admissionController)
 	sinkNode := newSinkNode(tableID, sink, replicaInfo.StartTs, targetTs, flowController)
 
-	p.AppendNode(ctx, "puller", newPullerNode(tableID, replicaInfo, tableName, changefeed))
+	if recordPath == "" {
+		p.AppendNode(ctx, "puller", newPullerNode(tableID, replicaInfo, tableName, changefeed, admissionController))
+	} else {
+		p.AppendNode(ctx, "replay", newReplayNode(recordPath, admissionController))
+	}
 	p.AppendNode(ctx, "sorter", sorterNode)
 	if cyclicEnabled {
 		p.AppendNode(ctx, "cyclic", newCyclicMarkNode(replicaInfo.MarkTableID))
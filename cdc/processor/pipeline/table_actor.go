@@ -23,9 +23,9 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
-	"github.com/pingcap/tiflow/cdc/sink/common"
 	"github.com/pingcap/tiflow/pkg/actor"
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	serverConfig "github.com/pingcap/tiflow/pkg/config"
@@ -47,7 +47,10 @@ const sinkFlushInterval = 500 * time.Millisecond
 type tableActor struct {
 	actorID actor.ID
 	mb      actor.Mailbox[pmessage.Message]
-	router  *actor.Router[pmessage.Message]
+	router  *system.Router
+	// ticker is shared by the nodes and the puller output of the table, so
+	// the Ticks sent to the actor are coalesced.
+	ticker *actorTicker
 	// all goroutines in tableActor should be spawned from this wg
 	wg *errgroup.Group
 	// backend mounter
@@ -56,8 +59,10 @@ type tableActor struct {
 	tableSink sink.Sink
 
 	pullerNode *pullerNode
-	sortNode   *sorterNode
-	sinkNode   *sinkNode
+	// pullerOutput hands the events of pullerNode over to sortNode
+	pullerOutput *pullerOutput
+	sortNode     *sorterNode
+	sinkNode     *sinkNode
 	// contains all nodes except pullerNode
 	nodes []*ActorNode
 
@@ -73,6 +78,7 @@ type tableActor struct {
 	memoryQuota    uint64
 	replicaInfo    *model.TableReplicaInfo
 	replicaConfig  *serverConfig.ReplicaConfig
+	admission      *admissionController
 	changefeedVars *cdcContext.ChangefeedVars
 	globalVars     *cdcContext.GlobalVars
 	// these fields below are used in logs and metrics only
@@ -97,6 +103,8 @@ func NewTableActor(cdcCtx cdcContext.Context,
 	replicaInfo *model.TableReplicaInfo,
 	sink sink.Sink,
 	targetTs model.Ts,
+	admission *AdmissionManager,
+	admissionPriority string,
 ) (TablePipeline, error) {
 	config := cdcCtx.ChangefeedVars().Info.Config
 	cyclicEnabled := config.Cyclic != nil && config.Cyclic.IsEnabled()
@@ -129,6 +137,8 @@ func NewTableActor(cdcCtx cdcContext.Context,
 		targetTs:      targetTs,
 		started:       false,
 
+		admission: newAdmissionController(admission, tableID, admissionPriority),
+
 		changefeedID:   changefeedVars.ID,
 		changefeedVars: changefeedVars,
 		globalVars:     globalVars,
@@ -147,7 +157,7 @@ func NewTableActor(cdcCtx cdcContext.Context,
 		table.stop(err)
 		return nil, errors.Trace(err)
 	}
-	err := globalVars.TableActorSystem.System().Spawn(mb, table)
+	err := globalVars.TableActorSystem.Spawn(mb, table)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -162,44 +172,89 @@ func NewTableActor(cdcCtx cdcContext.Context,
 // OnClose implements Actor interface.
 // TODO: implements table actor stop here.
 func (t *tableActor) OnClose() {
+	// nodes are only accessed in the actor, release them here to avoid races
+	// with Poll.
+	for _, n := range t.nodes {
+		n.releaseResource()
+	}
+	if t.sortNode != nil {
+		t.sortNode.onClose()
+	}
 }
 
+// Poll handles the messages in batch. Ticks are coalesced into one, and the
+// latest Barrier is handled before the data, so the data are handled at most
+// once per Poll no matter how many messages there are.
 func (t *tableActor) Poll(ctx context.Context, msgs []message.Message[pmessage.Message]) bool {
+	if atomic.LoadUint32(&t.stopped) == stopped {
+		// No need to handle remaining messages.
+		return false
+	}
+	// Ticks sent from now on are not coalesced with the handled ones.
+	t.ticker.reset()
+
+	var (
+		barrierTs   model.Ts
+		hasBarrier  bool
+		hasTick     bool
+		hasStop     bool
+		stopTs      model.Ts
+		hasStopAtTs bool
+	)
 	for i := range msgs {
-		if atomic.LoadUint32(&t.stopped) == stopped {
-			// No need to handle remaining messages.
-			return false
-		}
-
-		var err error
 		switch msgs[i].Tp {
 		case message.TypeValue:
 			switch msgs[i].Value.Tp {
 			case pmessage.MessageTypeBarrier:
-				err = t.handleBarrierMsg(ctx, msgs[i].Value.BarrierTs)
+				// The barrier ts may go backwards, so the latest one is taken
+				// rather than the max one.
+				barrierTs = msgs[i].Value.BarrierTs
+				hasBarrier = true
 			case pmessage.MessageTypeTick:
-				err = t.handleTickMsg(ctx)
+				hasTick = true
+			case pmessage.MessageTypeCommand:
+				cmd := msgs[i].Value.Command
+				if cmd.Tp == pmessage.CommandTypeStopAtTs && (!hasStopAtTs || cmd.StopTs < stopTs) {
+					stopTs = cmd.StopTs
+					hasStopAtTs = true
+				}
 			}
 		case message.TypeStop:
-			t.handleStopMsg(ctx)
+			hasStop = true
 		}
-		if err != nil {
+	}
+
+	var err error
+	if hasBarrier {
+		err = t.handleBarrierMsg(ctx, barrierTs)
+	}
+	if err == nil && hasTick {
+		err = t.handleTickMsg(ctx)
+	}
+	if err == nil && hasStopAtTs {
+		err = t.handleStopAtTsMsg(ctx, stopTs)
+	}
+	if err != nil {
+		if !cerror.ErrTableProcessorStoppedSafely.Equal(err) {
 			log.Error("failed to process message, stop table actor ",
 				zap.String("tableName", t.tableName),
 				zap.Int64("tableID", t.tableID),
-				zap.Any("message", msgs[i]),
+				zap.Uint64("barrierTs", barrierTs),
 				zap.Error(err))
-			t.handleError(err)
-			break
 		}
-
+		t.handleError(err)
+	} else {
+		if hasStop {
+			t.handleStopMsg(ctx)
+		}
 		// process message for each node, pull message from parent node and then send it to next node
-		if err := t.handleDataMsg(ctx); err != nil {
-			log.Error("failed to process message, stop table actor ",
-				zap.String("tableName", t.tableName),
-				zap.Int64("tableID", t.tableID), zap.Error(err))
-			t.handleError(err)
-			break
+		if len(msgs) > 0 {
+			if err := t.handleDataMsg(ctx); err != nil {
+				log.Error("failed to process message, stop table actor ",
+					zap.String("tableName", t.tableName),
+					zap.Int64("tableID", t.tableID), zap.Error(err))
+				t.handleError(err)
+			}
 		}
 	}
 	if atomic.LoadUint32(&t.stopped) == stopped {
@@ -213,16 +268,37 @@ func (t *tableActor) Poll(ctx context.Context, msgs []message.Message[pmessage.M
 }
 
 func (t *tableActor) handleDataMsg(ctx context.Context) error {
+	if t.pullerOutput != nil {
+		if err := t.sortNode.handlePullerOutput(ctx, t.pullerOutput); err != nil {
+			return err
+		}
+		// The puller is replaced by the probes while the table is hibernated.
+		if t.sortNode.tryHibernate(time.Now()) {
+			t.pullerNode.hibernate()
+		} else if !t.sortNode.hibernated {
+			t.pullerNode.wakeUp()
+		}
+	}
+	hasMore := false
 	for _, n := range t.nodes {
 		if err := n.TryRun(ctx); err != nil {
 			return err
 		}
+		hasMore = hasMore || n.hasMore
+	}
+	if hasMore {
+		// The batch of some node is full, tick itself to handle the rest in
+		// the next Poll, so other tables get a chance to run in between.
+		t.ticker.tick()
 	}
 	return nil
 }
 
 func (t *tableActor) handleBarrierMsg(ctx context.Context, barrierTs model.Ts) error {
-	t.sortNode.updateBarrierTs(barrierTs)
+	if t.sortNode.updateBarrierTs(barrierTs) {
+		// the resolved event is resent by handleDataMsg in the same Poll.
+		t.sortNode.resendResolved = true
+	}
 	return t.sinkNode.updateBarrierTs(ctx, barrierTs)
 }
 
@@ -238,6 +314,17 @@ func (t *tableActor) handleTickMsg(ctx context.Context) error {
 	return nil
 }
 
+// handleStopAtTsMsg stops the sinkNode once it's flushed to stopTs. Unlike
+// handleStopMsg, the sinkNode is handled synchronously, so it isn't flushed
+// beyond stopTs by other messages concurrently.
+func (t *tableActor) handleStopAtTsMsg(ctx context.Context, stopTs model.Ts) error {
+	_, err := t.sinkNode.HandleMessage(ctx, pmessage.CommandMessage(&pmessage.Command{
+		Tp:     pmessage.CommandTypeStopAtTs,
+		StopTs: stopTs,
+	}))
+	return err
+}
+
 func (t *tableActor) handleStopMsg(ctx context.Context) {
 	// async stops sinkNode and tableSink
 	go func() {
@@ -263,15 +350,14 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 		zap.String("tableName", t.tableName),
 		zap.Uint64("quota", t.memoryQuota))
 
-	flowController := common.NewTableFlowController(t.memoryQuota)
+	t.ticker = newActorTicker(t.globalVars.TableActorSystem.Router(), t.actorID)
+	flowController := t.globalVars.MemoryQuotaManager.NewTableFlowController(t.memoryQuota)
 	sorterNode := newSorterNode(t.tableName, t.tableID,
 		t.replicaInfo.StartTs, flowController,
-		t.mounter, t.replicaConfig,
+		t.mounter, t.replicaConfig, t.admission,
 	)
 	t.sortNode = sorterNode
-	sortActorNodeContext := newContext(sdtTableContext, t.tableName,
-		t.globalVars.TableActorSystem.Router(),
-		t.actorID, t.changefeedVars, t.globalVars, t.reportErr)
+	sortActorNodeContext := t.newNodeContext(sdtTableContext)
 	if err := startSorter(t, sortActorNodeContext); err != nil {
 		log.Error("sorter fails to start",
 			zap.String("tableName", t.tableName),
@@ -280,11 +366,12 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 		return err
 	}
 
-	pullerNode := newPullerNode(t.tableID, t.replicaInfo, t.tableName, t.changefeedVars.ID)
-	pullerActorNodeContext := newContext(sdtTableContext,
-		t.tableName,
-		t.globalVars.TableActorSystem.Router(),
-		t.actorID, t.changefeedVars, t.globalVars, t.reportErr)
+	// The tick may be dropped if the mailbox is full, it's fine as the table
+	// actor will handle the puller output after handling the messages in the
+	// mailbox.
+	t.pullerOutput = newPullerOutput(t.changefeedID, t.tableID, defaultPullerOutputCredit, t.ticker.tick)
+	pullerNode := newPullerNode(t.tableID, t.replicaInfo, t.tableName, t.changefeedVars.ID, t.admission)
+	pullerActorNodeContext := t.newNodeContext(sdtTableContext)
 	t.pullerNode = pullerNode
 	if err := startPuller(t, pullerActorNodeContext); err != nil {
 		log.Error("puller fails to start",
@@ -302,7 +389,9 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	actorSinkNode := newSinkNode(t.tableID, t.tableSink,
 		t.replicaInfo.StartTs,
 		t.targetTs, flowController)
-	actorSinkNode.initWithReplicaConfig(true, t.replicaConfig)
+	if err := actorSinkNode.initWithReplicaConfig(true, t.changefeedID, t.replicaConfig); err != nil {
+		return errors.Trace(err)
+	}
 	t.sinkNode = actorSinkNode
 
 	// construct sink actor node, it gets message from sortNode or cyclicNode
@@ -311,7 +400,8 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	) (bool, error) {
 		return actorSinkNode.HandleMessage(sdtTableContext, msg)
 	}
-	t.nodes = append(t.nodes, NewActorNode(messageFetchFunc, messageProcessFunc))
+	t.nodes = append(t.nodes, NewActorNode(t.changefeedID, actorNodeTypeSink,
+		messageFetchFunc, messageProcessFunc))
 
 	t.started = true
 	log.Info("table actor is started",
@@ -320,6 +410,16 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	return nil
 }
 
+// newNodeContext creates a context of a node, the Ticks sent by it are
+// coalesced with the other nodes of the table.
+func (t *tableActor) newNodeContext(ctx context.Context) *actorNodeContext {
+	nodeCtx := newContext(ctx, t.tableName,
+		t.globalVars.TableActorSystem.Router(),
+		t.actorID, t.changefeedVars, t.globalVars, t.reportErr)
+	nodeCtx.ticker = t.ticker
+	return nodeCtx
+}
+
 func (t *tableActor) getSinkAsyncMessageHolder(
 	sdtTableContext context.Context,
 	sortActorNodeContext *actorNodeContext) (AsyncMessageHolder, error,
@@ -330,11 +430,7 @@ func (t *tableActor) getSinkAsyncMessageHolder(
 	// check if cyclic feature is enabled
 	if t.cyclicEnabled {
 		cyclicNode := newCyclicMarkNode(t.markTableID)
-		cyclicActorNodeContext := newCyclicNodeContext(
-			newContext(sdtTableContext, t.tableName,
-				t.globalVars.TableActorSystem.Router(),
-				t.actorID, t.changefeedVars,
-				t.globalVars, t.reportErr))
+		cyclicActorNodeContext := newCyclicNodeContext(t.newNodeContext(sdtTableContext))
 		if err := cyclicNode.Init(cyclicActorNodeContext); err != nil {
 			log.Error("failed to start cyclic node",
 				zap.String("tableName", t.tableName),
@@ -349,7 +445,8 @@ func (t *tableActor) getSinkAsyncMessageHolder(
 		) (bool, error) {
 			return cyclicNode.TryHandleDataMessage(cyclicActorNodeContext, msg)
 		}
-		t.nodes = append(t.nodes, NewActorNode(messageFetchFunc, messageProcessFunc))
+		t.nodes = append(t.nodes, NewActorNode(t.changefeedID, actorNodeTypeCyclic,
+			messageFetchFunc, messageProcessFunc))
 		messageFetchFunc = func() *pmessage.Message {
 			return cyclicActorNodeContext.tryGetProcessedMessage()
 		}
@@ -377,6 +474,9 @@ func (t *tableActor) stop(err error) {
 		t.sortNode.releaseResource(t.stopCtx, t.changefeedID)
 	}
 	t.cancel()
+	if t.pullerOutput != nil {
+		t.pullerOutput.close()
+	}
 	if t.sinkNode != nil {
 		if err := t.sinkNode.releaseResource(t.stopCtx); err != nil {
 			log.Warn("close sink failed",
@@ -422,7 +522,9 @@ func (t *tableActor) CheckpointTs() model.Ts {
 // UpdateBarrierTs updates the barrier ts in this table pipeline
 func (t *tableActor) UpdateBarrierTs(ts model.Ts) {
 	msg := pmessage.BarrierMessage(ts)
-	err := t.router.Send(t.actorID, message.ValueMessage(msg))
+	// Barriers are sent at high priority, so they are not delayed by a
+	// mailbox full of Ticks.
+	err := t.router.SendPriority(t.actorID, message.ValueMessage(msg), actor.PriorityHigh)
 	if err != nil {
 		log.Warn("send fails",
 			zap.Reflect("msg", msg),
@@ -434,10 +536,13 @@ func (t *tableActor) UpdateBarrierTs(ts model.Ts) {
 
 // AsyncStop tells the pipeline to stop, and returns true if the pipeline is already stopped.
 func (t *tableActor) AsyncStop(targetTs model.Ts) bool {
-	// TypeStop stop the sinkNode only ,the processor stop the sink to release some resource
-	// and then stop the whole table pipeline by call Cancel
-	msg := message.StopMessage[pmessage.Message]()
-	err := t.router.Send(t.actorID, msg)
+	// The sinkNode is flushed to targetTs and then stopped, the processor waits
+	// for the stopped status and then stops the whole table pipeline by call Cancel
+	msg := message.ValueMessage(pmessage.CommandMessage(&pmessage.Command{
+		Tp:     pmessage.CommandTypeStopAtTs,
+		StopTs: targetTs,
+	}))
+	err := t.router.SendPriority(t.actorID, msg, actor.PriorityHigh)
 	log.Info("send async stop signal to table",
 		zap.String("tableName", t.tableName),
 		zap.Int64("tableID", t.tableID),
@@ -460,6 +565,13 @@ func (t *tableActor) Workload() model.WorkloadInfo {
 	return workload
 }
 
+// Diagnostics returns the runtime state of the nodes of this table pipeline
+func (t *tableActor) Diagnostics() *model.TablePipelineDiagnostics {
+	d := diagnoseTable(t.tableID, t.tableName, t.pullerOutput, t.sortNode, t.sinkNode)
+	d.ResolvedTs = t.ResolvedTs()
+	return d
+}
+
 // Status returns the status of this table pipeline
 func (t *tableActor) Status() TableStatus {
 	return t.sinkNode.Status()
@@ -497,9 +609,9 @@ func (t *tableActor) Wait() {
 
 // for ut
 var startPuller = func(t *tableActor, ctx *actorNodeContext) error {
-	return t.pullerNode.start(ctx, t.wg, true, t.sortNode)
+	return t.pullerNode.start(ctx, t.wg, true, t.pullerOutput)
 }
 
 var startSorter = func(t *tableActor, ctx *actorNodeContext) error {
-	return t.sortNode.start(ctx, true, t.wg, t.actorID, t.router)
+	return t.sortNode.start(ctx, true, t.wg, t.ticker)
 }
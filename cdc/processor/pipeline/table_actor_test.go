@@ -36,9 +36,9 @@ import (
 
 func TestAsyncStopFailed(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.TODO())
-	sys, router := actor.NewSystemBuilder[pmessage.Message](t.Name()).Build()
-	tableActorSystem, tableActorRouter := sys, router
-	tableActorSystem.Start(ctx)
+	tableActorSystem := system.NewSystem()
+	require.Nil(t, tableActorSystem.Start(ctx))
+	tableActorRouter := tableActorSystem.Router()
 	defer func() {
 		cancel()
 		tableActorSystem.Stop()
@@ -97,9 +97,9 @@ func TestTableActorInterface(t *testing.T) {
 
 func TestTableActorCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.TODO())
-	sys, router := actor.NewSystemBuilder[pmessage.Message](t.Name()).Build()
-	tableActorSystem, tableActorRouter := sys, router
-	tableActorSystem.Start(ctx)
+	tableActorSystem := system.NewSystem()
+	require.Nil(t, tableActorSystem.Start(ctx))
+	tableActorRouter := tableActorSystem.Router()
 	defer func() {
 		cancel()
 		tableActorSystem.Stop()
@@ -253,6 +253,50 @@ func TestPollBarrierTsMessage(t *testing.T) {
 	require.Equal(t, model.Ts(8), tbl.sortNode.barrierTs)
 }
 
+func TestPollBatchMessages(t *testing.T) {
+	dataCount := 0
+	var pN asyncMessageHolderFunc = func() *pmessage.Message {
+		return &pmessage.Message{
+			Tp:        pmessage.MessageTypeBarrier,
+			BarrierTs: 1,
+		}
+	}
+	var dp asyncMessageProcessorFunc = func(
+		ctx context.Context, msg pmessage.Message,
+	) (bool, error) {
+		dataCount++
+		return true, nil
+	}
+	tbl := tableActor{
+		sinkNode: &sinkNode{
+			targetTs:     10,
+			checkpointTs: 5,
+			resolvedTs:   5,
+			barrierTs:    8,
+		},
+		sortNode: &sorterNode{
+			barrierTs: 8,
+		},
+		lastFlushSinkTime: time.Now(),
+		nodes: []*ActorNode{
+			NewActorNode("changefeed", actorNodeTypeSink, pN, dp),
+		},
+	}
+	// the latest barrier is taken, and the data are handled once for all
+	// the messages
+	require.True(t, tbl.Poll(context.TODO(), []message.Message[pmessage.Message]{
+		message.ValueMessage(pmessage.TickMessage()),
+		message.ValueMessage(pmessage.BarrierMessage(9)),
+		message.ValueMessage(pmessage.TickMessage()),
+		message.ValueMessage(pmessage.BarrierMessage(7)),
+		message.ValueMessage(pmessage.TickMessage()),
+	}))
+	require.Equal(t, model.Ts(7), tbl.sinkNode.BarrierTs())
+	require.Equal(t, model.Ts(8), tbl.sortNode.barrierTs)
+	require.Equal(t, defaultOutputChannelSize, dataCount)
+	require.True(t, tbl.nodes[0].hasMore)
+}
+
 func TestPollDataFailed(t *testing.T) {
 	// process failed
 	var pN asyncMessageHolderFunc = func() *pmessage.Message {
@@ -319,7 +363,7 @@ func TestNewTableActor(t *testing.T) {
 		&model.TableReplicaInfo{
 			StartTs:     0,
 			MarkTableID: 1,
-		}, &mockSink{}, 10)
+		}, &mockSink{}, 10, nil, config.TablePriorityNormal)
 	require.NotNil(t, tbl)
 	require.Nil(t, err)
 	require.NotPanics(t, func() {
@@ -335,7 +379,7 @@ func TestNewTableActor(t *testing.T) {
 		&model.TableReplicaInfo{
 			StartTs:     0,
 			MarkTableID: 1,
-		}, &mockSink{}, 10)
+		}, &mockSink{}, 10, nil, config.TablePriorityNormal)
 	require.Nil(t, tbl)
 	require.NotNil(t, err)
 
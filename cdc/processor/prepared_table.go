@@ -0,0 +1,155 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	tablepipeline "github.com/pingcap/tiflow/cdc/processor/pipeline"
+	"github.com/pingcap/tiflow/cdc/sink"
+)
+
+// preparedTableTTL is how long a prepared table is kept after it has caught
+// up, it's dropped if the owner doesn't add it in time, e.g. the owner has
+// changed or the table is moved to another capture instead.
+const preparedTableTTL = 5 * time.Minute
+
+// preparedTableCatchUpTimeout is how long a prepared table is kept before it
+// catches up, it's dropped if it doesn't catch up in time, e.g. the owner has
+// changed and the table is never added. A table dropped is replicated from
+// scratch if it's added later.
+const preparedTableCatchUpTimeout = 30 * time.Minute
+
+// preparedTable is a table whose pipeline is started before the table is
+// moved to the processor. The puller and the sorter of the table run as
+// usual, but its barrier ts is not updated, so nothing is written to the
+// downstream until the table is added.
+type preparedTable struct {
+	table   tablepipeline.TablePipeline
+	sink    *preparedTableSink
+	startTs model.Ts
+	// prepareTime is the time when the table is prepared.
+	prepareTime time.Time
+	// readyTime is the time when the table catches up, it's zero if the
+	// table hasn't caught up.
+	readyTime time.Time
+}
+
+// expired returns whether the table is kept too long without being added.
+func (t *preparedTable) expired(now time.Time) bool {
+	if t.readyTime.IsZero() {
+		return now.Sub(t.prepareTime) >= preparedTableCatchUpTimeout
+	}
+	return now.Sub(t.readyTime) >= preparedTableTTL
+}
+
+// addedPreparedTable is a prepared table added to the processor. The
+// checkpoint ts of its pipeline is the one when the table was prepared until
+// the sink is flushed again, which may be before the checkpoint ts the table
+// is added at, so the latter is reported instead to keep the global
+// checkpoint ts from regressing.
+type addedPreparedTable struct {
+	tablepipeline.TablePipeline
+	startTs model.Ts
+}
+
+// CheckpointTs implements TablePipeline interface.
+func (t *addedPreparedTable) CheckpointTs() model.Ts {
+	if checkpointTs := t.TablePipeline.CheckpointTs(); checkpointTs > t.startTs {
+		return checkpointTs
+	}
+	return t.startTs
+}
+
+// preparedTableSink holds the rows of a prepared table until the table is
+// added. The rows committed at or before the checkpoint ts when the table is
+// added are dropped then, since they have been written to the downstream by
+// the capture the table is moved from.
+type preparedTableSink struct {
+	sink.Sink
+
+	mu       sync.Mutex
+	released bool
+	skipTs   model.Ts
+	rows     []*model.RowChangedEvent
+}
+
+func newPreparedTableSink(s sink.Sink) *preparedTableSink {
+	return &preparedTableSink{Sink: s}
+}
+
+// release makes the sink drop the rows committed at or before skipTs and
+// emit the others.
+func (s *preparedTableSink) release(skipTs model.Ts) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = true
+	s.skipTs = skipTs
+}
+
+// filterRows returns the rows to emit, ok is false if the rows are held.
+func (s *preparedTableSink) filterRows(rows []*model.RowChangedEvent) (_ []*model.RowChangedEvent, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.released {
+		s.rows = append(s.rows, rows...)
+		return nil, false
+	}
+	if len(s.rows) > 0 {
+		rows = append(s.rows, rows...)
+		s.rows = nil
+	}
+	i := 0
+	for i < len(rows) && rows[i].CommitTs <= s.skipTs {
+		i++
+	}
+	return rows[i:], true
+}
+
+// EmitRowChangedEvents implements sink.Sink.
+func (s *preparedTableSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	rows, ok := s.filterRows(rows)
+	if !ok || len(rows) == 0 {
+		return nil
+	}
+	return s.Sink.EmitRowChangedEvents(ctx, rows...)
+}
+
+// TryEmitRowChangedEvents implements sink.Sink.
+func (s *preparedTableSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	rows, ok := s.filterRows(rows)
+	if !ok || len(rows) == 0 {
+		return true, nil
+	}
+	return s.Sink.TryEmitRowChangedEvents(ctx, rows...)
+}
+
+// FlushRowChangedEvents implements sink.Sink.
+func (s *preparedTableSink) FlushRowChangedEvents(
+	ctx context.Context, tableID model.TableID, resolvedTs uint64,
+) (uint64, error) {
+	// The held rows are emitted here if no rows are emitted after the table
+	// is added.
+	if err := s.EmitRowChangedEvents(ctx); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return s.Sink.FlushRowChangedEvents(ctx, tableID, resolvedTs)
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	// dummy to provide default versions of unimplemented interface methods.
+	sink.Sink
+
+	rows       []*model.RowChangedEvent
+	resolvedTs model.Ts
+}
+
+func (s *recordingSink) EmitRowChangedEvents(_ context.Context, rows ...*model.RowChangedEvent) error {
+	s.rows = append(s.rows, rows...)
+	return nil
+}
+
+func (s *recordingSink) TryEmitRowChangedEvents(_ context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	s.rows = append(s.rows, rows...)
+	return true, nil
+}
+
+func (s *recordingSink) FlushRowChangedEvents(_ context.Context, _ model.TableID, resolvedTs uint64) (uint64, error) {
+	s.resolvedTs = resolvedTs
+	return resolvedTs, nil
+}
+
+func TestPreparedTableSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	backend := &recordingSink{}
+	s := newPreparedTableSink(backend)
+
+	// The rows are held until the sink is released.
+	ok, err := s.TryEmitRowChangedEvents(ctx, &model.RowChangedEvent{CommitTs: 11}, &model.RowChangedEvent{CommitTs: 12})
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Nil(t, s.EmitRowChangedEvents(ctx, &model.RowChangedEvent{CommitTs: 13}))
+	require.Empty(t, backend.rows)
+
+	// The rows committed at or before the release ts are dropped.
+	s.release(12)
+	require.Nil(t, s.EmitRowChangedEvents(ctx, &model.RowChangedEvent{CommitTs: 14}))
+	require.Len(t, backend.rows, 2)
+	require.Equal(t, model.Ts(13), backend.rows[0].CommitTs)
+	require.Equal(t, model.Ts(14), backend.rows[1].CommitTs)
+
+	ok, err = s.TryEmitRowChangedEvents(ctx, &model.RowChangedEvent{CommitTs: 15})
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Len(t, backend.rows, 3)
+
+	// The held rows are emitted by a flush too.
+	backend = &recordingSink{}
+	s = newPreparedTableSink(backend)
+	require.Nil(t, s.EmitRowChangedEvents(ctx, &model.RowChangedEvent{CommitTs: 11}, &model.RowChangedEvent{CommitTs: 21}))
+	s.release(20)
+	checkpointTs, err := s.FlushRowChangedEvents(ctx, 1, 30)
+	require.Nil(t, err)
+	require.Equal(t, uint64(30), checkpointTs)
+	require.Len(t, backend.rows, 1)
+	require.Equal(t, model.Ts(21), backend.rows[0].CommitTs)
+
+	// A nil sink can be released.
+	var nilSink *preparedTableSink
+	nilSink.release(10)
+}
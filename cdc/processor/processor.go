@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -58,6 +59,13 @@ type processor struct {
 	changefeed   *orchestrator.ChangefeedReactorState
 
 	tables map[model.TableID]tablepipeline.TablePipeline
+	// resolvedTsHeap and checkpointTsHeap maintain the min resolved ts and
+	// the min checkpoint ts of the tables.
+	resolvedTsHeap   *tableTsHeap
+	checkpointTsHeap *tableTsHeap
+	// preparedTables are the tables prepared to be moved to the processor,
+	// they're not in tables until they're added.
+	preparedTables map[model.TableID]*preparedTable
 
 	schemaStorage entry.SchemaStorage
 	lastSchemaTs  model.Ts
@@ -65,8 +73,11 @@ type processor struct {
 	filter        *filter.Filter
 	mounter       entry.Mounter
 	sinkManager   *sink.Manager
+	throttleSink  *sink.ThrottleSink
+	scanLimiter   *kv.IncrementalScanLimiter
 	redoManager   redo.LogManager
 	lastRedoFlush time.Time
+	admission     *tablepipeline.AdmissionManager
 
 	initialized bool
 	errCh       chan error
@@ -142,6 +153,74 @@ func (p *processor) RemoveTable(ctx cdcContext.Context, tableID model.TableID) (
 	return true, nil
 }
 
+// PrepareTable implements TableExecutor interface.
+func (p *processor) PrepareTable(ctx cdcContext.Context, tableID model.TableID) (bool, error) {
+	if !p.checkReadyForMessages() {
+		return false, nil
+	}
+
+	if _, ok := p.tables[tableID]; ok {
+		log.Warn("table to prepare is running already",
+			cdcContext.ZapFieldChangefeed(ctx), zap.Int64("tableID", tableID))
+		return true, nil
+	}
+	if _, ok := p.preparedTables[tableID]; ok {
+		return true, nil
+	}
+	if p.redoManager.Enabled() {
+		// The redo log manager waits for all the tables it knows to advance,
+		// so a prepared table would block the redo log of the others. The
+		// table is not prepared and it starts from scratch when it's added.
+		log.Info("skip preparing table since the redo log is enabled",
+			cdcContext.ZapFieldChangefeed(ctx), zap.Int64("tableID", tableID))
+		return true, nil
+	}
+
+	log.Info("preparing table",
+		zap.Int64("tableID", tableID),
+		cdcContext.ZapFieldChangefeed(ctx))
+	startTs := p.changefeed.Status.CheckpointTs
+	prepared := &preparedTable{startTs: startTs, prepareTime: time.Now()}
+	// The entry is added before the pipeline is created, so the sink of the
+	// pipeline is wrapped by createTablePipeline.
+	p.preparedTables[tableID] = prepared
+	table, err := p.createTablePipeline(ctx, tableID, &model.TableReplicaInfo{StartTs: startTs})
+	if err != nil {
+		delete(p.preparedTables, tableID)
+		return false, errors.Trace(err)
+	}
+	prepared.table = table
+	return true, nil
+}
+
+// IsPrepareTableFinished implements TableExecutor interface.
+func (p *processor) IsPrepareTableFinished(ctx cdcContext.Context, tableID model.TableID) bool {
+	if !p.checkReadyForMessages() {
+		return false
+	}
+
+	prepared, ok := p.preparedTables[tableID]
+	if !ok {
+		// The table is not prepared since the redo log is enabled, or it has
+		// been added already.
+		return true
+	}
+	// The table has caught up once its resolved ts reaches the global one,
+	// which means the incremental scan of the puller has finished.
+	if prepared.table.ResolvedTs() < p.changefeed.Status.ResolvedTs {
+		return false
+	}
+	if prepared.readyTime.IsZero() {
+		prepared.readyTime = time.Now()
+		log.Info("Prepare Table finished",
+			cdcContext.ZapFieldChangefeed(ctx),
+			zap.Int64("tableID", tableID),
+			zap.Uint64("startTs", prepared.startTs),
+			zap.Uint64("resolvedTs", prepared.table.ResolvedTs()))
+	}
+	return true
+}
+
 // IsAddTableFinished implements TableExecutor interface.
 func (p *processor) IsAddTableFinished(ctx cdcContext.Context, tableID model.TableID) bool {
 	if !p.checkReadyForMessages() {
@@ -201,7 +280,7 @@ func (p *processor) IsRemoveTableFinished(ctx cdcContext.Context, tableID model.
 
 	table.Cancel()
 	table.Wait()
-	delete(p.tables, tableID)
+	p.deleteTable(tableID)
 	log.Info("Remove Table finished",
 		cdcContext.ZapFieldChangefeed(ctx),
 		zap.Int64("tableID", tableID))
@@ -228,12 +307,16 @@ func newProcessor(ctx cdcContext.Context) *processor {
 	changefeedID := ctx.ChangefeedVars().ID
 	conf := config.GetGlobalServerConfig()
 	p := &processor{
-		tables:        make(map[model.TableID]tablepipeline.TablePipeline),
-		errCh:         make(chan error, 1),
-		changefeedID:  changefeedID,
-		captureInfo:   ctx.GlobalVars().CaptureInfo,
-		cancel:        func() {},
-		lastRedoFlush: time.Now(),
+		tables:           make(map[model.TableID]tablepipeline.TablePipeline),
+		preparedTables:   make(map[model.TableID]*preparedTable),
+		resolvedTsHeap:   newTableTsHeap(tablepipeline.TablePipeline.ResolvedTs),
+		checkpointTsHeap: newTableTsHeap(tablepipeline.TablePipeline.CheckpointTs),
+		errCh:            make(chan error, 1),
+		changefeedID:     changefeedID,
+		captureInfo:      ctx.GlobalVars().CaptureInfo,
+		cancel:           func() {},
+		lastRedoFlush:    time.Now(),
+		scanLimiter:      kv.NewIncrementalScanLimiter(changefeedID, nil),
 
 		newSchedulerEnabled: conf.Debug.EnableNewScheduler,
 
@@ -285,8 +368,9 @@ func (p *processor) Tick(ctx cdcContext.Context, state *orchestrator.ChangefeedR
 	p.changefeed = state
 	state.CheckCaptureAlive(ctx.GlobalVars().CaptureInfo.ID)
 	ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
-		ID:   state.ID,
-		Info: state.Info,
+		ID:          state.ID,
+		Info:        state.Info,
+		ScanLimiter: p.scanLimiter,
 	})
 	_, err := p.tick(ctx, state)
 
@@ -347,6 +431,12 @@ func (p *processor) tick(ctx cdcContext.Context, state *orchestrator.ChangefeedR
 	}
 	// sink manager will return this checkpointTs to sink node if sink node resolvedTs flush failed
 	p.sinkManager.UpdateChangeFeedCheckpointTs(state.Info.GetCheckpointTs(state.Status))
+	// the throttle of sink can be updated when the changefeed is running
+	if p.throttleSink != nil {
+		p.throttleSink.SetThrottle(state.Info.Config.Sink.Throttle)
+	}
+	// so are the limits of incremental scans
+	p.scanLimiter.SetLimits(state.Info.Config.IncrementalScan)
 	if err := p.handleTableOperation(ctx); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -356,6 +446,7 @@ func (p *processor) tick(ctx cdcContext.Context, state *orchestrator.ChangefeedR
 	if err := p.flushRedoLogMeta(ctx); err != nil {
 		return nil, err
 	}
+	p.handleRedoHealth()
 	// it is no need to check the err here, because we will use
 	// local time when an error return, which is acceptable
 	pdTime, _ := ctx.GlobalVars().PDClock.CurrentTime()
@@ -372,6 +463,7 @@ func (p *processor) tick(ctx cdcContext.Context, state *orchestrator.ChangefeedR
 	if !p.newSchedulerEnabled {
 		p.handleWorkload()
 	}
+	p.dropExpiredPreparedTables(ctx)
 	p.doGCSchemaStorage(ctx)
 	p.metricSyncTableNumGauge.Set(float64(len(p.tables)))
 
@@ -462,10 +554,17 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	stdCtx := util.PutChangefeedIDInCtx(ctx, p.changefeed.ID)
 	stdCtx = util.PutRoleInCtx(stdCtx, util.RoleProcessor)
 
+	columnSelector, err := filter.NewColumnSelector(p.changefeed.Info.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	p.mounter = entry.NewMounter(p.schemaStorage,
 		p.changefeedID,
 		util.TimezoneFromCtx(ctx),
-		p.changefeed.Info.Config.EnableOldValue)
+		p.changefeed.Info.Config.EnableOldValue,
+		columnSelector,
+		ctx.GlobalVars().MounterWorkerPool)
+	p.admission = tablepipeline.NewAdmissionManager(p.changefeedID, p.changefeed.Info.Config.AdmissionControl)
 
 	opts := make(map[string]string, len(p.changefeed.Info.Opts)+2)
 	for k, v := range p.changefeed.Info.Opts {
@@ -482,6 +581,11 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	}
 	opts[sink.OptChangefeedID] = p.changefeed.ID
 	opts[sink.OptCaptureAddr] = ctx.GlobalVars().CaptureInfo.AdvertiseAddr
+	physical, logical, err := ctx.GlobalVars().PDClient.GetTS(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	opts[sink.OptCurrentTs] = strconv.FormatUint(oracle.ComposeTS(physical, logical), 10)
 	log.Info("processor try new sink", zap.String("changefeed", p.changefeed.ID))
 
 	start := time.Now()
@@ -495,9 +599,11 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	log.Info("processor try new sink success",
 		zap.Duration("duration", time.Since(start)))
 
+	p.throttleSink = sink.NewThrottleSink(p.changefeed.ID, s, p.changefeed.Info.Config.Sink.Throttle)
+
 	checkpointTs := p.changefeed.Info.GetCheckpointTs(p.changefeed.Status)
 	captureAddr := ctx.GlobalVars().CaptureInfo.AdvertiseAddr
-	p.sinkManager = sink.NewManager(stdCtx, s, errCh, checkpointTs, captureAddr, p.changefeedID)
+	p.sinkManager = sink.NewManager(stdCtx, p.throttleSink, errCh, checkpointTs, captureAddr, p.changefeedID)
 	redoManagerOpts := &redo.ManagerOptions{EnableBgRunner: true, ErrCh: errCh}
 	p.redoManager, err = redo.NewManager(stdCtx, p.changefeed.Info.Config.Consistent, redoManagerOpts)
 	if err != nil {
@@ -802,22 +908,18 @@ func (p *processor) handlePosition(currentTs int64) {
 	if p.schemaStorage != nil {
 		minResolvedTs = p.schemaStorage.ResolvedTs()
 	}
-	for _, table := range p.tables {
-		ts := table.ResolvedTs()
-		if ts < minResolvedTs {
-			minResolvedTs = ts
-			minResolvedTableID, _ = table.ID()
-		}
+	// The min ts are maintained incrementally, scanning all tables in every
+	// tick is expensive if there are a lot of tables.
+	if ts, tableID, ok := p.resolvedTsHeap.min(); ok && ts < minResolvedTs {
+		minResolvedTs = ts
+		minResolvedTableID = tableID
 	}
 
 	minCheckpointTs := minResolvedTs
 	minCheckpointTableID := int64(0)
-	for _, table := range p.tables {
-		ts := table.CheckpointTs()
-		if ts < minCheckpointTs {
-			minCheckpointTs = ts
-			minCheckpointTableID, _ = table.ID()
-		}
+	if ts, tableID, ok := p.checkpointTsHeap.min(); ok && ts < minCheckpointTs {
+		minCheckpointTs = ts
+		minCheckpointTableID = tableID
 	}
 
 	resolvedPhyTs := oracle.ExtractPhysical(minResolvedTs)
@@ -909,6 +1011,24 @@ func (p *processor) addTable(ctx cdcContext.Context, tableID model.TableID, repl
 		}
 	}
 
+	if prepared, ok := p.preparedTables[tableID]; ok {
+		delete(p.preparedTables, tableID)
+		// The rows replicated by the capture the table is moved from are
+		// dropped, the table is replicated from the same ts as a new one.
+		prepared.sink.release(replicaInfo.StartTs)
+		log.Info("add prepared table",
+			cdcContext.ZapFieldChangefeed(ctx),
+			zap.Int64("tableID", tableID),
+			zap.Uint64("preparedStartTs", prepared.startTs),
+			zap.Uint64("startTs", replicaInfo.StartTs),
+			zap.Uint64("resolvedTs", prepared.table.ResolvedTs()))
+		table := &addedPreparedTable{TablePipeline: prepared.table, startTs: replicaInfo.StartTs}
+		p.tables[tableID] = table
+		p.resolvedTsHeap.add(tableID, table)
+		p.checkpointTsHeap.add(tableID, table)
+		return nil
+	}
+
 	globalCheckpointTs := p.changefeed.Status.CheckpointTs
 
 	if replicaInfo.StartTs < globalCheckpointTs {
@@ -923,9 +1043,18 @@ func (p *processor) addTable(ctx cdcContext.Context, tableID model.TableID, repl
 		return errors.Trace(err)
 	}
 	p.tables[tableID] = table
+	p.resolvedTsHeap.add(tableID, table)
+	p.checkpointTsHeap.add(tableID, table)
 	return nil
 }
 
+// deleteTable deletes a table from the `p.tables`
+func (p *processor) deleteTable(tableID model.TableID) {
+	delete(p.tables, tableID)
+	p.resolvedTsHeap.remove(tableID)
+	p.checkpointTsHeap.remove(tableID)
+}
+
 func (p *processor) createTablePipelineImpl(ctx cdcContext.Context, tableID model.TableID, replicaInfo *model.TableReplicaInfo) (tablepipeline.TablePipeline, error) {
 	ctx = cdcContext.WithErrorHandler(ctx, func(err error) error {
 		if cerror.ErrTableProcessorStoppedSafely.Equal(err) ||
@@ -979,9 +1108,36 @@ func (p *processor) createTablePipelineImpl(ctx cdcContext.Context, tableID mode
 		tableNameStr = tableName.QuoteString()
 	}
 
+	var schemaName, tblName string
+	if tableName != nil {
+		schemaName, tblName = tableName.Schema, tableName.Table
+	}
+	replicaConfig := p.changefeed.Info.Config
+	admissionPriority := replicaConfig.AdmissionControl.TablePriority(
+		replicaConfig.CaseSensitive, schemaName, tblName)
+
 	sink := p.sinkManager.CreateTableSink(tableID, replicaInfo.StartTs, p.redoManager)
+	if prepared, ok := p.preparedTables[tableID]; ok {
+		// The rows of a prepared table are held until the table is added.
+		prepared.sink = newPreparedTableSink(sink)
+		sink = prepared.sink
+	}
 	var table tablepipeline.TablePipeline
-	if config.GetGlobalServerConfig().Debug.EnableTableActor {
+	if recorderCfg := config.GetGlobalServerConfig().Debug.Recorder; recorderCfg.IsReplayed(p.changefeedID) {
+		var err error
+		table, err = tablepipeline.NewReplayTablePipeline(
+			ctx,
+			p.mounter,
+			recorderCfg.Dir,
+			tableID,
+			sink,
+			p.changefeed.Info.GetTargetTs(),
+			p.admission,
+			admissionPriority)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if config.GetGlobalServerConfig().Debug.EnableTableActor {
 		var err error
 		table, err = tablepipeline.NewTableActor(
 			ctx,
@@ -990,7 +1146,9 @@ func (p *processor) createTablePipelineImpl(ctx cdcContext.Context, tableID mode
 			tableNameStr,
 			replicaInfo,
 			sink,
-			p.changefeed.Info.GetTargetTs())
+			p.changefeed.Info.GetTargetTs(),
+			p.admission,
+			admissionPriority)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -1003,6 +1161,8 @@ func (p *processor) createTablePipelineImpl(ctx cdcContext.Context, tableID mode
 			replicaInfo,
 			sink,
 			p.changefeed.Info.GetTargetTs(),
+			p.admission,
+			admissionPriority,
 		)
 	}
 
@@ -1022,12 +1182,30 @@ func (p *processor) createTablePipelineImpl(ctx cdcContext.Context, tableID mode
 func (p *processor) removeTable(table tablepipeline.TablePipeline, tableID model.TableID) {
 	table.Cancel()
 	table.Wait()
-	delete(p.tables, tableID)
+	p.deleteTable(tableID)
 	if p.redoManager.Enabled() {
 		p.redoManager.RemoveTable(tableID)
 	}
 }
 
+// dropExpiredPreparedTables drops the prepared tables not added in time.
+func (p *processor) dropExpiredPreparedTables(ctx cdcContext.Context) {
+	now := time.Now()
+	for tableID, prepared := range p.preparedTables {
+		if !prepared.expired(now) {
+			continue
+		}
+		log.Info("drop prepared table since it's not added in time",
+			cdcContext.ZapFieldChangefeed(ctx),
+			zap.Int64("tableID", tableID),
+			zap.Time("prepareTime", prepared.prepareTime),
+			zap.Time("readyTime", prepared.readyTime))
+		prepared.table.Cancel()
+		prepared.table.Wait()
+		delete(p.preparedTables, tableID)
+	}
+}
+
 // doGCSchemaStorage trigger the schema storage GC
 func (p *processor) doGCSchemaStorage(ctx cdcContext.Context) {
 	if p.schemaStorage == nil {
@@ -1040,9 +1218,17 @@ func (p *processor) doGCSchemaStorage(ctx cdcContext.Context) {
 		return
 	}
 
+	// The prepared tables are replicated from the checkpoint ts when they're
+	// prepared, so the schema needed by them must be kept.
+	gcTs := p.changefeed.Status.CheckpointTs
+	for _, prepared := range p.preparedTables {
+		if prepared.startTs < gcTs {
+			gcTs = prepared.startTs
+		}
+	}
 	// Please refer to `unmarshalAndMountRowChanged` in cdc/entry/mounter.go
 	// for why we need -1.
-	lastSchemaTs := p.schemaStorage.DoGC(p.changefeed.Status.CheckpointTs - 1)
+	lastSchemaTs := p.schemaStorage.DoGC(gcTs - 1)
 	if p.lastSchemaTs == lastSchemaTs {
 		return
 	}
@@ -1069,14 +1255,46 @@ func (p *processor) flushRedoLogMeta(ctx context.Context) error {
 	return nil
 }
 
+// handleRedoHealth reports the health of the redo logs in the task position,
+// it's only updated once the health or the reason of it changes.
+func (p *processor) handleRedoHealth() {
+	if !p.redoManager.Enabled() {
+		return
+	}
+	health := p.redoManager.GetHealth()
+	position, ok := p.changefeed.TaskPositions[p.captureInfo.ID]
+	if !ok || (position.Redo != nil &&
+		position.Redo.Healthy == health.Healthy && position.Redo.Message == health.Message) {
+		return
+	}
+	if !health.Healthy {
+		log.Warn("redo logs are unhealthy", zap.String("changefeed", p.changefeedID),
+			zap.String("message", health.Message))
+	}
+	p.changefeed.PatchTaskPosition(p.captureInfo.ID, func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+		if position == nil {
+			log.Warn("task position is not exist, skip to update redo health", zap.String("changefeed", p.changefeedID))
+			return nil, false, nil
+		}
+		position.Redo = health
+		return position, true, nil
+	})
+}
+
 func (p *processor) Close() error {
 	log.Info("processor closing ...", zap.String("changefeed", p.changefeedID))
 	for _, tbl := range p.tables {
 		tbl.Cancel()
 	}
+	for _, prepared := range p.preparedTables {
+		prepared.table.Cancel()
+	}
 	for _, tbl := range p.tables {
 		tbl.Wait()
 	}
+	for _, prepared := range p.preparedTables {
+		prepared.table.Wait()
+	}
 	p.cancel()
 	p.wg.Wait()
 
@@ -1118,6 +1336,7 @@ func (p *processor) Close() error {
 	syncTableNumGauge.DeleteLabelValues(p.changefeedID)
 	processorErrorCounter.DeleteLabelValues(p.changefeedID)
 	processorSchemaStorageGcTsGauge.DeleteLabelValues(p.changefeedID)
+	p.scanLimiter.Close()
 
 	return nil
 }
@@ -1130,3 +1349,47 @@ func (p *processor) WriteDebugInfo(w io.Writer) {
 			tableID, tablePipeline.Name(), tablePipeline.ResolvedTs(), tablePipeline.CheckpointTs(), tablePipeline.Status())
 	}
 }
+
+// tableDiagnostics returns the diagnostics of a table replicated or prepared
+// by the processor, false is returned if there's no such table.
+func (p *processor) tableDiagnostics(tableID model.TableID) (*model.TablePipelineDiagnostics, bool) {
+	table, ok := p.tables[tableID]
+	if !ok {
+		prepared, ok := p.preparedTables[tableID]
+		if !ok {
+			return nil, false
+		}
+		table = prepared.table
+	}
+	diagnostics := table.Diagnostics()
+	diagnostics.ChangefeedID = p.changefeedID
+	diagnostics.CaptureID = p.captureInfo.ID
+	return diagnostics, true
+}
+
+// redoResolvedTs returns the resolved ts of the redo logs of the tables, it
+// returns false if the redo log is not enabled.
+func (p *processor) redoResolvedTs() (*model.RedoResolvedTs, bool) {
+	if p.redoManager == nil || !p.redoManager.Enabled() {
+		return nil, false
+	}
+	result := &model.RedoResolvedTs{ResolvedTs: math.MaxUint64}
+	for tableID, backlog := range p.redoManager.GetTableBacklogs() {
+		result.Tables = append(result.Tables, &model.RedoTableResolvedTs{
+			TableID:           tableID,
+			CaptureID:         p.captureInfo.ID,
+			ResolvedTs:        backlog.FlushedTs,
+			PendingResolvedTs: backlog.ResolvedTs,
+		})
+		if backlog.FlushedTs < result.ResolvedTs {
+			result.ResolvedTs = backlog.FlushedTs
+		}
+	}
+	if len(result.Tables) == 0 {
+		result.ResolvedTs = 0
+	}
+	sort.Slice(result.Tables, func(i, j int) bool {
+		return result.Tables[i].TableID < result.Tables[j].TableID
+	})
+	return result, true
+}
@@ -20,6 +20,7 @@ import (
 	"math"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -115,6 +116,16 @@ func (m *mockTablePipeline) Workload() model.WorkloadInfo {
 	return model.WorkloadInfo{Workload: 1}
 }
 
+func (m *mockTablePipeline) Diagnostics() *model.TablePipelineDiagnostics {
+	return &model.TablePipelineDiagnostics{
+		TableID:      m.tableID,
+		TableName:    m.name,
+		Status:       m.status.String(),
+		ResolvedTs:   m.resolvedTs,
+		CheckpointTs: m.checkpointTs,
+	}
+}
+
 func (m *mockTablePipeline) Status() tablepipeline.TableStatus {
 	return m.status
 }
@@ -668,6 +679,95 @@ func TestTableExecutor(t *testing.T) {
 	require.Nil(t, p.agent)
 }
 
+func TestPrepareTable(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	p, tester := initProcessor4Test(ctx, t)
+	p.newSchedulerEnabled = true
+	p.lazyInit = func(ctx cdcContext.Context) error {
+		p.agent = &mockAgent{executor: p}
+		return nil
+	}
+
+	// init tick
+	_, err := p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	updateChangeFeedPosition(t, tester, ctx.ChangefeedVars().ID, 30, 20)
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+
+	// The prepared table is not replicated.
+	ok, err := p.PrepareTable(ctx, 1)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Empty(t, p.tables)
+	require.Empty(t, p.GetAllCurrentTables())
+	require.Contains(t, p.preparedTables, model.TableID(1))
+	table1 := p.preparedTables[1].table.(*mockTablePipeline)
+	require.Equal(t, model.Ts(20), table1.resolvedTs)
+
+	require.False(t, p.IsPrepareTableFinished(ctx, 1))
+	table1.resolvedTs = 30
+	require.True(t, p.IsPrepareTableFinished(ctx, 1))
+
+	// The schema needed by the prepared table is kept while the checkpoint
+	// advances, and the barrier ts of the table is not updated.
+	updateChangeFeedPosition(t, tester, ctx.ChangefeedVars().ID, 50, 40)
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(19), atomic.LoadUint64(&p.schemaStorage.(*mockSchemaStorage).lastGcTs))
+	require.Equal(t, model.Ts(0), table1.barrierTs)
+
+	// The prepared table is added without creating a new pipeline.
+	ok, err = p.AddTable(ctx, 1)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Empty(t, p.preparedTables)
+	require.Same(t, table1, p.tables[1].(*addedPreparedTable).TablePipeline)
+	require.True(t, p.IsPrepareTableFinished(ctx, 1))
+	// The checkpoint ts of the table when it was prepared is not reported.
+	table1.checkpointTs = 20
+	require.Equal(t, model.Ts(40), p.tables[1].CheckpointTs())
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Equal(t, model.Ts(50), table1.barrierTs)
+
+	// A prepared table not added in time is dropped.
+	ok, err = p.PrepareTable(ctx, 2)
+	require.Nil(t, err)
+	require.True(t, ok)
+	table2 := p.preparedTables[2].table.(*mockTablePipeline)
+	table2.resolvedTs = 50
+	require.True(t, p.IsPrepareTableFinished(ctx, 2))
+	p.preparedTables[2].readyTime = time.Now().Add(-preparedTableTTL)
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Empty(t, p.preparedTables)
+	require.True(t, table2.canceled)
+	require.False(t, table1.canceled)
+
+	// A prepared table not catching up in time is dropped as well.
+	ok, err = p.PrepareTable(ctx, 3)
+	require.Nil(t, err)
+	require.True(t, ok)
+	table3 := p.preparedTables[3].table.(*mockTablePipeline)
+	require.False(t, p.IsPrepareTableFinished(ctx, 3))
+	p.preparedTables[3].prepareTime = time.Now().Add(-preparedTableCatchUpTimeout)
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Empty(t, p.preparedTables)
+	require.True(t, table3.canceled)
+
+	err = p.Close()
+	require.Nil(t, err)
+	require.True(t, table1.canceled)
+}
+
 func TestInitTable(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	p, tester := initProcessor4Test(ctx, t)
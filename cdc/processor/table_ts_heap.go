@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"container/heap"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	tablepipeline "github.com/pingcap/tiflow/cdc/processor/pipeline"
+)
+
+// tableTsHeap maintains the min ts of tables incrementally, such as the min
+// resolved ts or the min checkpoint ts, instead of scanning all tables in
+// every tick.
+//
+// The ts of a table never decreases, so the ts cached in the heap is a lower
+// bound of the current one. To find the min, only the tables at the top of
+// the heap are refreshed, until the top one is up to date, then it's the min
+// of all tables. Tables advanced far beyond the min are not visited at all.
+type tableTsHeap struct {
+	getTs func(tablepipeline.TablePipeline) model.Ts
+	items tableTsItems
+	index map[model.TableID]*tableTsItem
+}
+
+type tableTsItem struct {
+	tableID model.TableID
+	table   tablepipeline.TablePipeline
+	// ts is the ts of the table when it's refreshed last time.
+	ts model.Ts
+	// index is the index of the item in the heap.
+	index int
+}
+
+func newTableTsHeap(getTs func(tablepipeline.TablePipeline) model.Ts) *tableTsHeap {
+	return &tableTsHeap{
+		getTs: getTs,
+		index: make(map[model.TableID]*tableTsItem),
+	}
+}
+
+// add adds a table to the heap, or replaces the table with the same ID.
+func (h *tableTsHeap) add(tableID model.TableID, table tablepipeline.TablePipeline) {
+	if item, ok := h.index[tableID]; ok {
+		item.table = table
+		item.ts = h.getTs(table)
+		heap.Fix(&h.items, item.index)
+		return
+	}
+	item := &tableTsItem{tableID: tableID, table: table, ts: h.getTs(table)}
+	heap.Push(&h.items, item)
+	h.index[tableID] = item
+}
+
+// remove removes a table from the heap.
+func (h *tableTsHeap) remove(tableID model.TableID) {
+	item, ok := h.index[tableID]
+	if !ok {
+		return
+	}
+	heap.Remove(&h.items, item.index)
+	delete(h.index, tableID)
+}
+
+// min returns the min ts of all tables and the table with the min ts, ok is
+// false if there is no table.
+func (h *tableTsHeap) min() (ts model.Ts, tableID model.TableID, ok bool) {
+	if len(h.items) == 0 {
+		return 0, 0, false
+	}
+	// Every table is refreshed at most once in theory, the bound guards
+	// against tables advancing concurrently. The cached ts of the top is
+	// still a lower bound of the min if the bound is reached.
+	for i := 0; i <= len(h.items); i++ {
+		top := h.items[0]
+		ts := h.getTs(top.table)
+		if ts == top.ts {
+			break
+		}
+		top.ts = ts
+		heap.Fix(&h.items, 0)
+	}
+	top := h.items[0]
+	return top.ts, top.tableID, true
+}
+
+// tableTsItems implements heap.Interface.
+type tableTsItems []*tableTsItem
+
+func (items tableTsItems) Len() int { return len(items) }
+
+func (items tableTsItems) Less(i, j int) bool { return items[i].ts < items[j].ts }
+
+func (items tableTsItems) Swap(i, j int) {
+	items[i], items[j] = items[j], items[i]
+	items[i].index = i
+	items[j].index = j
+}
+
+func (items *tableTsItems) Push(x interface{}) {
+	item := x.(*tableTsItem)
+	item.index = len(*items)
+	*items = append(*items, item)
+}
+
+func (items *tableTsItems) Pop() interface{} {
+	old := *items
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*items = old[:n-1]
+	return item
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	tablepipeline "github.com/pingcap/tiflow/cdc/processor/pipeline"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableTsHeap(t *testing.T) {
+	t.Parallel()
+
+	h := newTableTsHeap(tablepipeline.TablePipeline.ResolvedTs)
+	_, _, ok := h.min()
+	require.False(t, ok)
+
+	table1 := &mockTablePipeline{tableID: 1, resolvedTs: 10}
+	table2 := &mockTablePipeline{tableID: 2, resolvedTs: 20}
+	table3 := &mockTablePipeline{tableID: 3, resolvedTs: 30}
+	h.add(1, table1)
+	h.add(2, table2)
+	h.add(3, table3)
+	ts, tableID, ok := h.min()
+	require.True(t, ok)
+	require.Equal(t, model.Ts(10), ts)
+	require.Equal(t, model.TableID(1), tableID)
+
+	// the min table advances.
+	table1.resolvedTs = 25
+	ts, tableID, _ = h.min()
+	require.Equal(t, model.Ts(20), ts)
+	require.Equal(t, model.TableID(2), tableID)
+
+	// all tables advance beyond the cached ts.
+	table1.resolvedTs = 40
+	table2.resolvedTs = 50
+	table3.resolvedTs = 35
+	ts, tableID, _ = h.min()
+	require.Equal(t, model.Ts(35), ts)
+	require.Equal(t, model.TableID(3), tableID)
+
+	// a table is replaced by a new pipeline with a smaller ts.
+	h.add(2, &mockTablePipeline{tableID: 2, resolvedTs: 15})
+	ts, tableID, _ = h.min()
+	require.Equal(t, model.Ts(15), ts)
+	require.Equal(t, model.TableID(2), tableID)
+
+	h.remove(2)
+	h.remove(2)
+	ts, tableID, _ = h.min()
+	require.Equal(t, model.Ts(35), ts)
+	require.Equal(t, model.TableID(3), tableID)
+
+	h.remove(1)
+	h.remove(3)
+	_, _, ok = h.min()
+	require.False(t, ok)
+}
+
+func TestTableTsHeapRandom(t *testing.T) {
+	t.Parallel()
+
+	h := newTableTsHeap(tablepipeline.TablePipeline.CheckpointTs)
+	tables := make(map[model.TableID]*mockTablePipeline)
+	for i := 0; i < 1000; i++ {
+		tableID := model.TableID(i)
+		tables[tableID] = &mockTablePipeline{tableID: tableID, checkpointTs: uint64(rand.Intn(1000))}
+		h.add(tableID, tables[tableID])
+	}
+	for round := 0; round < 100; round++ {
+		// some tables advance, and some are removed and added again.
+		for tableID, table := range tables {
+			switch rand.Intn(10) {
+			case 0:
+				table.checkpointTs += uint64(rand.Intn(100))
+			case 1:
+				h.remove(tableID)
+				h.add(tableID, table)
+			}
+		}
+		expected := model.Ts(math.MaxUint64)
+		for _, table := range tables {
+			if table.checkpointTs < expected {
+				expected = table.checkpointTs
+			}
+		}
+		ts, tableID, ok := h.min()
+		require.True(t, ok)
+		require.Equal(t, expected, ts)
+		require.Equal(t, expected, tables[tableID].checkpointTs)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+const (
+	// hotspotReportInterval is the interval of generating the hotspot report.
+	hotspotReportInterval = 30 * time.Second
+	// hotspotRegionsLimit is the max number of the hottest regions of a table
+	// in the hotspot report.
+	hotspotRegionsLimit = 5
+)
+
+// hotspotCollector counts the row change events of a table by regions.
+type hotspotCollector struct {
+	changefeedID string
+	tableID      int64
+	tableName    string
+
+	// regions maps the region IDs to the *uint64 counts of their events, the
+	// counts are updated atomically, so the puller never blocks on the
+	// reporter. since is only accessed by the reporter.
+	regions sync.Map
+	since   time.Time
+
+	// mu protects closed, the gauge of the table is only set if it's not
+	// closed, so the gauge deleted by unregister isn't set again.
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *hotspotCollector) record(regionID uint64) {
+	if count, ok := c.regions.Load(regionID); ok {
+		atomic.AddUint64(count.(*uint64), 1)
+		return
+	}
+	count, _ := c.regions.LoadOrStore(regionID, new(uint64))
+	atomic.AddUint64(count.(*uint64), 1)
+}
+
+// take returns the counted events by regions and the time the counting
+// started at, and restarts the counting at now. The regions without events
+// are removed, an event recorded for such a region at the same time may be
+// lost, which is fine for the report.
+func (c *hotspotCollector) take(now time.Time) (map[uint64]uint64, time.Time) {
+	regions := make(map[uint64]uint64)
+	c.regions.Range(func(key, value interface{}) bool {
+		count := atomic.SwapUint64(value.(*uint64), 0)
+		if count == 0 {
+			c.regions.Delete(key)
+			return true
+		}
+		regions[key.(uint64)] = count
+		return true
+	})
+	since := c.since
+	c.since = now
+	return regions, since
+}
+
+// setEventRate sets the gauge of the event rate of the table.
+func (c *hotspotCollector) setEventRate(eventsPerSecond float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		tableEventRateGauge.WithLabelValues(c.changefeedID, c.tableName).Set(eventsPerSecond)
+	}
+}
+
+// hotspotRegistry holds the collectors of the running table pullers of the
+// capture and the latest hotspot report.
+type hotspotRegistry struct {
+	mu         sync.Mutex
+	collectors map[*hotspotCollector]struct{}
+	report     *model.HotspotReport
+}
+
+var hotspots = &hotspotRegistry{
+	collectors: make(map[*hotspotCollector]struct{}),
+	report:     &model.HotspotReport{},
+}
+
+func (r *hotspotRegistry) register(changefeedID string, tableID int64, tableName string) *hotspotCollector {
+	c := &hotspotCollector{
+		changefeedID: changefeedID,
+		tableID:      tableID,
+		tableName:    tableName,
+		since:        time.Now(),
+	}
+	r.mu.Lock()
+	r.collectors[c] = struct{}{}
+	r.mu.Unlock()
+	return c
+}
+
+func (r *hotspotRegistry) unregister(c *hotspotCollector) {
+	r.mu.Lock()
+	delete(r.collectors, c)
+	r.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	tableEventRateGauge.DeleteLabelValues(c.changefeedID, c.tableName)
+}
+
+// generate computes the event rates of the tables and their regions since
+// the last report, and replaces the latest report.
+func (r *hotspotRegistry) generate(now time.Time) *model.HotspotReport {
+	r.mu.Lock()
+	collectors := make([]*hotspotCollector, 0, len(r.collectors))
+	for c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mu.Unlock()
+
+	report := &model.HotspotReport{
+		GeneratedAt: model.JSONTime(now),
+		Tables:      make([]model.TableHotspot, 0, len(collectors)),
+	}
+	for _, c := range collectors {
+		regions, since := c.take(now)
+		seconds := now.Sub(since).Seconds()
+		if seconds <= 0 {
+			continue
+		}
+		table := model.TableHotspot{
+			ChangefeedID: c.changefeedID,
+			TableID:      c.tableID,
+			TableName:    c.tableName,
+			Regions:      make([]model.RegionHotspot, 0, len(regions)),
+		}
+		var total uint64
+		for regionID, count := range regions {
+			total += count
+			table.Regions = append(table.Regions, model.RegionHotspot{
+				RegionID:        regionID,
+				EventsPerSecond: float64(count) / seconds,
+			})
+		}
+		sort.Slice(table.Regions, func(i, j int) bool {
+			if table.Regions[i].EventsPerSecond != table.Regions[j].EventsPerSecond {
+				return table.Regions[i].EventsPerSecond > table.Regions[j].EventsPerSecond
+			}
+			return table.Regions[i].RegionID < table.Regions[j].RegionID
+		})
+		if len(table.Regions) > hotspotRegionsLimit {
+			table.Regions = table.Regions[:hotspotRegionsLimit]
+		}
+		table.EventsPerSecond = float64(total) / seconds
+		c.setEventRate(table.EventsPerSecond)
+		report.Tables = append(report.Tables, table)
+	}
+	sort.Slice(report.Tables, func(i, j int) bool {
+		if report.Tables[i].EventsPerSecond != report.Tables[j].EventsPerSecond {
+			return report.Tables[i].EventsPerSecond > report.Tables[j].EventsPerSecond
+		}
+		if report.Tables[i].ChangefeedID != report.Tables[j].ChangefeedID {
+			return report.Tables[i].ChangefeedID < report.Tables[j].ChangefeedID
+		}
+		return report.Tables[i].TableID < report.Tables[j].TableID
+	})
+
+	r.mu.Lock()
+	r.report = report
+	r.mu.Unlock()
+	return report
+}
+
+// RunHotspotReporter generates the upstream write hotspot report of the
+// capture periodically until the context is canceled.
+func RunHotspotReporter(ctx context.Context) error {
+	ticker := time.NewTicker(hotspotReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case now := <-ticker.C:
+			hotspots.generate(now)
+		}
+	}
+}
+
+// GetHotspotReport returns the latest upstream write hotspot report of the
+// capture. The report must not be modified.
+func GetHotspotReport() *model.HotspotReport {
+	hotspots.mu.Lock()
+	defer hotspots.mu.Unlock()
+	return hotspots.report
+}
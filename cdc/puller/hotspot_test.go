@@ -0,0 +1,74 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotspotReport(t *testing.T) {
+	t.Parallel()
+
+	r := &hotspotRegistry{
+		collectors: make(map[*hotspotCollector]struct{}),
+		report:     &model.HotspotReport{},
+	}
+	cold := r.register("test", 1, "test.cold")
+	hot := r.register("test", 2, "test.hot")
+	defer r.unregister(cold)
+	defer r.unregister(hot)
+	start := time.Now()
+	cold.since = start
+	hot.since = start
+
+	cold.record(1)
+	for regionID := uint64(1); regionID <= hotspotRegionsLimit+2; regionID++ {
+		for i := uint64(0); i < regionID*10; i++ {
+			hot.record(regionID)
+		}
+	}
+
+	report := r.generate(start.Add(10 * time.Second))
+	require.Equal(t, report, r.report)
+	require.Len(t, report.Tables, 2)
+	require.Equal(t, int64(2), report.Tables[0].TableID)
+	require.Equal(t, "test.hot", report.Tables[0].TableName)
+	require.Equal(t, float64(28), report.Tables[0].EventsPerSecond)
+	require.Len(t, report.Tables[0].Regions, hotspotRegionsLimit)
+	require.Equal(t, model.RegionHotspot{RegionID: 7, EventsPerSecond: 7}, report.Tables[0].Regions[0])
+	require.Equal(t, model.RegionHotspot{RegionID: 3, EventsPerSecond: 3}, report.Tables[0].Regions[4])
+	require.Equal(t, int64(1), report.Tables[1].TableID)
+	require.Equal(t, 0.1, report.Tables[1].EventsPerSecond)
+
+	// the counting restarts after a report.
+	report = r.generate(start.Add(20 * time.Second))
+	require.Len(t, report.Tables, 2)
+	for _, table := range report.Tables {
+		require.Zero(t, table.EventsPerSecond)
+		require.Empty(t, table.Regions)
+	}
+
+	r.unregister(hot)
+	report = r.generate(start.Add(30 * time.Second))
+	require.Len(t, report.Tables, 1)
+	require.Equal(t, int64(1), report.Tables[0].TableID)
+
+	// the gauge of an unregistered table isn't set again.
+	hot.setEventRate(1)
+	require.False(t, tableEventRateGauge.DeleteLabelValues("test", "test.hot"))
+}
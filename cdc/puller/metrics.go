@@ -62,6 +62,27 @@ var (
 			Help:      "Puller event channel size",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
 		}, []string{"changefeed"})
+	tableEventRateGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "puller",
+			Name:      "table_event_rate",
+			Help:      "The rate of row change events received by the table puller in the last hotspot report",
+		}, []string{"changefeed", "table"})
+	sharedFeedGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "puller",
+			Name:      "shared_feed_count",
+			Help:      "The number of region subscriptions shared by table pullers",
+		})
+	sharedPullerGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "puller",
+			Name:      "shared_puller_count",
+			Help:      "The number of table pullers reading from shared region subscriptions",
+		})
 )
 
 // InitMetrics registers all metrics in this file
@@ -72,4 +93,7 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(memBufferSizeGauge)
 	registry.MustRegister(outputChanSizeHistogram)
 	registry.MustRegister(eventChanSizeHistogram)
+	registry.MustRegister(tableEventRateGauge)
+	registry.MustRegister(sharedFeedGauge)
+	registry.MustRegister(sharedPullerGauge)
 }
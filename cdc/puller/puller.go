@@ -124,7 +124,12 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 	}
 
 	changefeedID := util.ChangefeedIDFromCtx(ctx)
-	tableID, _ := util.TableIDFromCtx(ctx)
+	tableID, tableName := util.TableIDFromCtx(ctx)
+	var hotspot *hotspotCollector
+	if tableName != "" && tableName != DDLPullerTableName {
+		hotspot = hotspots.register(changefeedID, tableID, tableName)
+		defer hotspots.unregister(hotspot)
+	}
 	metricOutputChanSize := outputChanSizeHistogram.WithLabelValues(changefeedID)
 	metricEventChanSize := eventChanSizeHistogram.WithLabelValues(changefeedID)
 	metricPullerResolvedTs := pullerResolvedTsGauge.WithLabelValues(changefeedID)
@@ -185,6 +190,9 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 
 			if e.Val != nil {
 				metricTxnCollectCounterKv.Inc()
+				if hotspot != nil {
+					hotspot.record(e.RegionID)
+				}
 				if err := output(e.Val); err != nil {
 					return errors.Trace(err)
 				}
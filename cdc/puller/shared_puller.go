@@ -0,0 +1,396 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/pdtime"
+	"github.com/pingcap/tiflow/pkg/regionspan"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/tikv/client-go/v2/tikv"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// sharedPullerChangefeedID is the changefeed label of the metrics of the
+	// shared region subscriptions, since a subscription may serve several
+	// changefeeds.
+	sharedPullerChangefeedID = "shared-puller"
+	// sharedFeedMaxPendingEvents bounds the events kept by a shared region
+	// subscription for the pullers joining it later.
+	sharedFeedMaxPendingEvents = 4096
+)
+
+// SharedPullerManager multiplexes the region subscriptions of the table pullers
+// of a capture. The pullers of the same spans with the same options read from
+// one subscription instead of pulling the same region deltas from TiKV
+// separately, e.g. when several changefeeds replicate the same table. Every
+// puller keeps its own start ts, the events committed at or before it are
+// filtered out.
+//
+// A puller joins an existing subscription only if no event it needs has been
+// dropped by the subscription, otherwise a new subscription is created for it.
+// The events are shared by the pullers and must not be modified, and a slow
+// puller slows down the other pullers of the subscription.
+//
+// A nil *SharedPullerManager creates the pullers without sharing.
+type SharedPullerManager struct {
+	mu    sync.Mutex
+	feeds map[string][]*sharedFeed
+}
+
+// NewSharedPullerManager creates a SharedPullerManager.
+func NewSharedPullerManager() *SharedPullerManager {
+	return &SharedPullerManager{feeds: make(map[string][]*sharedFeed)}
+}
+
+// NewPuller is like the package level NewPuller, but the returned puller
+// shares the region subscription with the other pullers of the same spans.
+func (m *SharedPullerManager) NewPuller(
+	ctx context.Context,
+	pdCli pd.Client,
+	grpcPool kv.GrpcPool,
+	regionCache *tikv.RegionCache,
+	kvStorage tidbkv.Storage,
+	pdClock pdtime.Clock,
+	changefeed string,
+	checkpointTs uint64,
+	spans []regionspan.Span,
+	enableOldValue bool,
+) Puller {
+	if m == nil {
+		return NewPuller(ctx, pdCli, grpcPool, regionCache, kvStorage, pdClock,
+			changefeed, checkpointTs, spans, enableOldValue)
+	}
+	key := sharedFeedKey(spans, enableOldValue)
+	return m.newSharedPuller(key, checkpointTs, func(ctx context.Context, startTs uint64) Puller {
+		return NewPuller(ctx, pdCli, grpcPool, regionCache, kvStorage, pdClock,
+			sharedPullerChangefeedID, startTs, spans, enableOldValue)
+	})
+}
+
+func (m *SharedPullerManager) newSharedPuller(
+	key string, checkpointTs uint64, newPuller func(ctx context.Context, startTs uint64) Puller,
+) *sharedPuller {
+	return &sharedPuller{
+		manager:      m,
+		key:          key,
+		checkpointTs: checkpointTs,
+		newPuller:    newPuller,
+		inputCh:      make(chan *model.RawKVEntry, defaultPullerOutputChanSize),
+		outputCh:     make(chan *model.RawKVEntry, defaultPullerOutputChanSize),
+		closed:       make(chan struct{}),
+		resolvedTs:   checkpointTs,
+	}
+}
+
+// sharedFeedKey returns the key of the subscriptions that can be shared by the
+// pullers of the given spans and options.
+func sharedFeedKey(spans []regionspan.Span, enableOldValue bool) string {
+	var b strings.Builder
+	for _, span := range spans {
+		b.WriteString(span.String())
+	}
+	fmt.Fprintf(&b, "/%t", enableOldValue)
+	return b.String()
+}
+
+// join subscribes the puller to a subscription it can join, a new
+// subscription is created if there's none. The events of the subscription
+// received before the puller joins are returned.
+func (m *SharedPullerManager) join(ctx context.Context, p *sharedPuller) (*sharedFeed, []*model.RawKVEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.feeds[p.key] {
+		if replay, ok := f.subscribe(p); ok {
+			return f, replay
+		}
+	}
+
+	// The subscription doesn't belong to any changefeed, it lives until all
+	// the pullers leave.
+	tableID, tableName := util.TableIDFromCtx(ctx)
+	feedCtx := util.PutTableInfoInCtx(context.Background(), tableID, tableName)
+	feedCtx = util.PutCaptureAddrInCtx(feedCtx, util.CaptureAddrFromCtx(ctx))
+	feedCtx = util.PutChangefeedIDInCtx(feedCtx, sharedPullerChangefeedID)
+	feedCtx = util.PutRoleInCtx(feedCtx, util.RoleFromCtx(ctx))
+	// The incremental scans of the subscription are limited by the limiter of
+	// the changefeed creating it.
+	feedCtx = kv.PutIncrementalScanLimiterInCtx(feedCtx, kv.IncrementalScanLimiterFromCtx(ctx))
+	feedCtx, cancel := context.WithCancel(feedCtx)
+
+	f := newSharedFeed(p.key, p.checkpointTs, cancel)
+	f.subscribe(p)
+	m.feeds[p.key] = append(m.feeds[p.key], f)
+	sharedFeedGauge.Inc()
+	log.Info("shared region subscription created",
+		zap.Int64("tableID", tableID),
+		zap.String("tableName", tableName),
+		zap.Uint64("startTs", p.checkpointTs))
+	go m.runFeed(feedCtx, f, p.newPuller)
+	return f, nil
+}
+
+// leave unsubscribes the puller, the subscription is stopped if it has no
+// pullers anymore.
+func (m *SharedPullerManager) leave(f *sharedFeed, p *sharedPuller) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	close(p.closed)
+	f.mu.Lock()
+	delete(f.subscribers, p)
+	empty := len(f.subscribers) == 0
+	f.mu.Unlock()
+	if empty {
+		m.removeFeed(f)
+		f.cancel()
+	}
+}
+
+func (m *SharedPullerManager) runFeed(
+	ctx context.Context, f *sharedFeed, newPuller func(ctx context.Context, startTs uint64) Puller,
+) {
+	err := f.run(ctx, newPuller(ctx, f.startTs))
+	if errors.Cause(err) != context.Canceled {
+		log.Warn("shared region subscription exited", zap.Error(err))
+	}
+	m.mu.Lock()
+	m.removeFeed(f)
+	m.mu.Unlock()
+	f.err = err
+	close(f.done)
+}
+
+// removeFeed makes the subscription unavailable to the pullers joining later,
+// it must be called with m.mu held.
+func (m *SharedPullerManager) removeFeed(f *sharedFeed) {
+	feeds := m.feeds[f.key]
+	for i := range feeds {
+		if feeds[i] == f {
+			feeds = append(feeds[:i], feeds[i+1:]...)
+			sharedFeedGauge.Dec()
+			break
+		}
+	}
+	if len(feeds) == 0 {
+		delete(m.feeds, f.key)
+	} else {
+		m.feeds[f.key] = feeds
+	}
+}
+
+// sharedFeed is a region subscription shared by pullers.
+type sharedFeed struct {
+	key     string
+	startTs uint64
+	cancel  context.CancelFunc
+	// done is closed after the subscription exits, and err is the error it
+	// exits with.
+	done chan struct{}
+	err  error
+
+	mu          sync.Mutex
+	subscribers map[*sharedPuller]struct{}
+	resolvedTs  uint64
+	// pending are the events received after resolvedTs, they're replayed to
+	// the pullers joining later. droppedTs is the max commit ts of the events
+	// dropped when there're too many pending events, the pullers starting
+	// before it can't join.
+	pending   []*model.RawKVEntry
+	droppedTs uint64
+}
+
+func newSharedFeed(key string, startTs uint64, cancel context.CancelFunc) *sharedFeed {
+	return &sharedFeed{
+		key:         key,
+		startTs:     startTs,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		subscribers: make(map[*sharedPuller]struct{}),
+		resolvedTs:  startTs,
+	}
+}
+
+// subscribe adds the puller to the subscription if no event it needs has been
+// dropped, and returns the pending events it needs.
+func (f *sharedFeed) subscribe(p *sharedPuller) ([]*model.RawKVEntry, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	select {
+	case <-f.done:
+		return nil, false
+	default:
+	}
+	if p.checkpointTs < f.resolvedTs || p.checkpointTs < f.droppedTs {
+		return nil, false
+	}
+	var replay []*model.RawKVEntry
+	for _, raw := range f.pending {
+		if raw.CRTs > p.checkpointTs {
+			replay = append(replay, raw)
+		}
+	}
+	f.subscribers[p] = struct{}{}
+	return replay, true
+}
+
+// dispatch records the event and returns the pullers to send it to.
+func (f *sharedFeed) dispatch(raw *model.RawKVEntry) []*sharedPuller {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if raw.OpType == model.OpTypeResolved {
+		f.resolvedTs = raw.CRTs
+		pending := f.pending[:0]
+		for _, e := range f.pending {
+			if e.CRTs > raw.CRTs {
+				pending = append(pending, e)
+			}
+		}
+		for i := len(pending); i < len(f.pending); i++ {
+			f.pending[i] = nil
+		}
+		f.pending = pending
+	} else {
+		if len(f.pending) >= sharedFeedMaxPendingEvents {
+			for _, e := range f.pending {
+				if e.CRTs > f.droppedTs {
+					f.droppedTs = e.CRTs
+				}
+			}
+			f.pending = nil
+		}
+		f.pending = append(f.pending, raw)
+	}
+
+	subscribers := make([]*sharedPuller, 0, len(f.subscribers))
+	for p := range f.subscribers {
+		subscribers = append(subscribers, p)
+	}
+	return subscribers
+}
+
+func (f *sharedFeed) run(ctx context.Context, plr Puller) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return plr.Run(ctx)
+	})
+	g.Go(func() error {
+		for {
+			var raw *model.RawKVEntry
+			select {
+			case <-ctx.Done():
+				return errors.Trace(ctx.Err())
+			case raw = <-plr.Output():
+			}
+			if raw == nil {
+				continue
+			}
+			for _, p := range f.dispatch(raw) {
+				select {
+				case <-ctx.Done():
+					return errors.Trace(ctx.Err())
+				case <-p.closed:
+				case p.inputCh <- raw:
+				}
+			}
+		}
+	})
+	return g.Wait()
+}
+
+// sharedPuller is a Puller reading from a shared region subscription.
+type sharedPuller struct {
+	manager      *SharedPullerManager
+	key          string
+	checkpointTs uint64
+	newPuller    func(ctx context.Context, startTs uint64) Puller
+
+	inputCh  chan *model.RawKVEntry
+	outputCh chan *model.RawKVEntry
+	// closed is closed after the puller leaves the subscription.
+	closed chan struct{}
+
+	resolvedTs  uint64
+	initialized int64
+}
+
+// Run implements Puller.
+func (p *sharedPuller) Run(ctx context.Context) error {
+	f, replay := p.manager.join(ctx, p)
+	defer p.manager.leave(f, p)
+	sharedPullerGauge.Inc()
+	defer sharedPullerGauge.Dec()
+
+	for _, raw := range replay {
+		if err := p.output(ctx, raw); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-f.done:
+			return errors.Trace(f.err)
+		case raw := <-p.inputCh:
+			if err := p.output(ctx, raw); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (p *sharedPuller) output(ctx context.Context, raw *model.RawKVEntry) error {
+	isResolved := raw.OpType == model.OpTypeResolved
+	if raw.CRTs < p.checkpointTs || (raw.CRTs == p.checkpointTs && !isResolved) {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case p.outputCh <- raw:
+	}
+	if isResolved {
+		atomic.StoreUint64(&p.resolvedTs, raw.CRTs)
+		atomic.StoreInt64(&p.initialized, 1)
+	}
+	return nil
+}
+
+// GetResolvedTs implements Puller.
+func (p *sharedPuller) GetResolvedTs() uint64 {
+	return atomic.LoadUint64(&p.resolvedTs)
+}
+
+// Output implements Puller.
+func (p *sharedPuller) Output() <-chan *model.RawKVEntry {
+	return p.outputCh
+}
+
+// IsInitialized implements Puller.
+func (p *sharedPuller) IsInitialized() bool {
+	return atomic.LoadInt64(&p.initialized) > 0
+}
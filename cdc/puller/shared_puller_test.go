@@ -0,0 +1,193 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeedPuller struct {
+	startTs  uint64
+	outputCh chan *model.RawKVEntry
+	errCh    chan error
+	exited   chan struct{}
+}
+
+func (p *fakeFeedPuller) Run(ctx context.Context) error {
+	defer close(p.exited)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-p.errCh:
+		return err
+	}
+}
+
+func (p *fakeFeedPuller) GetResolvedTs() uint64 {
+	return 0
+}
+
+func (p *fakeFeedPuller) Output() <-chan *model.RawKVEntry {
+	return p.outputCh
+}
+
+func (p *fakeFeedPuller) IsInitialized() bool {
+	return true
+}
+
+type fakeFeedPullers chan *fakeFeedPuller
+
+func (c fakeFeedPullers) newPuller(_ context.Context, startTs uint64) Puller {
+	p := &fakeFeedPuller{
+		startTs:  startTs,
+		outputCh: make(chan *model.RawKVEntry),
+		errCh:    make(chan error, 1),
+		exited:   make(chan struct{}),
+	}
+	c <- p
+	return p
+}
+
+func runSharedPuller(ctx context.Context, p *sharedPuller) <-chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.Run(ctx)
+	}()
+	return errCh
+}
+
+func kvEntry(ts uint64) *model.RawKVEntry {
+	return &model.RawKVEntry{OpType: model.OpTypePut, CRTs: ts}
+}
+
+func resolvedEntry(ts uint64) *model.RawKVEntry {
+	return &model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: ts}
+}
+
+func requireOutput(t *testing.T, p *sharedPuller, expected ...*model.RawKVEntry) {
+	for _, e := range expected {
+		select {
+		case raw := <-p.Output():
+			require.Equal(t, e, raw)
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "no event is output")
+		}
+	}
+}
+
+func TestSharedPuller(t *testing.T) {
+	t.Parallel()
+
+	m := NewSharedPullerManager()
+	pullers := make(fakeFeedPullers, 2)
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	p1 := m.newSharedPuller("t1", 10, pullers.newPuller)
+	errCh1 := runSharedPuller(ctx1, p1)
+	feed := <-pullers
+	require.Equal(t, uint64(10), feed.startTs)
+
+	feed.outputCh <- kvEntry(12)
+	feed.outputCh <- kvEntry(20)
+	feed.outputCh <- resolvedEntry(15)
+	requireOutput(t, p1, kvEntry(12), kvEntry(20), resolvedEntry(15))
+	require.True(t, p1.IsInitialized())
+	require.Equal(t, uint64(15), p1.GetResolvedTs())
+
+	// The puller starting at the resolved ts joins the subscription, the
+	// events after its start ts are replayed.
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	p2 := m.newSharedPuller("t1", 15, pullers.newPuller)
+	require.False(t, p2.IsInitialized())
+	errCh2 := runSharedPuller(ctx2, p2)
+	requireOutput(t, p2, kvEntry(20))
+	feed.outputCh <- kvEntry(16)
+	feed.outputCh <- resolvedEntry(25)
+	requireOutput(t, p1, kvEntry(16), resolvedEntry(25))
+	requireOutput(t, p2, kvEntry(16), resolvedEntry(25))
+	require.True(t, p2.IsInitialized())
+	require.Equal(t, uint64(25), p2.GetResolvedTs())
+
+	// The puller starting before the resolved ts or with other spans gets a
+	// new subscription.
+	ctx3, cancel3 := context.WithCancel(context.Background())
+	p3 := m.newSharedPuller("t1", 20, pullers.newPuller)
+	errCh3 := runSharedPuller(ctx3, p3)
+	feed3 := <-pullers
+	require.Equal(t, uint64(20), feed3.startTs)
+	ctx4, cancel4 := context.WithCancel(context.Background())
+	p4 := m.newSharedPuller("t2", 30, pullers.newPuller)
+	errCh4 := runSharedPuller(ctx4, p4)
+	feed4 := <-pullers
+
+	// The subscription is stopped after all the pullers leave.
+	cancel1()
+	require.Equal(t, context.Canceled, errors.Cause(<-errCh1))
+	feed.outputCh <- resolvedEntry(30)
+	requireOutput(t, p2, resolvedEntry(30))
+	cancel2()
+	require.Equal(t, context.Canceled, errors.Cause(<-errCh2))
+	<-feed.exited
+
+	// The error of the subscription is returned by its pullers.
+	feed3.errCh <- errors.New("injected error")
+	require.Regexp(t, "injected error", <-errCh3)
+	cancel3()
+
+	cancel4()
+	require.Equal(t, context.Canceled, errors.Cause(<-errCh4))
+	<-feed4.exited
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.feeds) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestSharedFeedPendingEvents(t *testing.T) {
+	t.Parallel()
+
+	f := newSharedFeed("t1", 10, func() {})
+	for i := 0; i < sharedFeedMaxPendingEvents; i++ {
+		f.dispatch(kvEntry(uint64(11 + i)))
+	}
+	_, ok := f.subscribe(&sharedPuller{checkpointTs: 10})
+	require.True(t, ok)
+
+	// The events are dropped once there're too many of them, the pullers
+	// need them can't join.
+	f.dispatch(kvEntry(5000))
+	require.Len(t, f.pending, 1)
+	_, ok = f.subscribe(&sharedPuller{checkpointTs: 10})
+	require.False(t, ok)
+	replay, ok := f.subscribe(&sharedPuller{checkpointTs: 10 + sharedFeedMaxPendingEvents})
+	require.True(t, ok)
+	require.Equal(t, []*model.RawKVEntry{kvEntry(5000)}, replay)
+
+	// The resolved events drop the events before them.
+	f.dispatch(kvEntry(6000))
+	f.dispatch(resolvedEntry(5500))
+	require.Equal(t, []*model.RawKVEntry{kvEntry(6000)}, f.pending)
+	_, ok = f.subscribe(&sharedPuller{checkpointTs: 5000})
+	require.False(t, ok)
+	replay, ok = f.subscribe(&sharedPuller{checkpointTs: 5500})
+	require.True(t, ok)
+	require.Equal(t, []*model.RawKVEntry{kvEntry(6000)}, replay)
+	require.Len(t, f.subscribers, 3)
+}
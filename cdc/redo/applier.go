@@ -25,7 +25,8 @@ func NewRedoReader(ctx context.Context, storage string, cfg *reader.LogReaderCon
 	switch consistentStorage(storage) {
 	case consistentStorageBlackhole:
 		rd = reader.NewBlackHoleReader()
-	case consistentStorageLocal, consistentStorageNFS, consistentStorageS3:
+	case consistentStorageLocal, consistentStorageNFS, consistentStorageS3,
+		consistentStorageGCS, consistentStorageAzure:
 		rd, err = reader.NewLogReader(ctx, cfg)
 	default:
 		err = cerror.ErrConsistentStorage.GenWithStackByArgs(storage)
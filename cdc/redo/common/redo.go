@@ -32,6 +32,8 @@ const (
 	MetaTmpEXT = ".mtmp"
 	// SortLogEXT is the sorted log file ext of log file after safely wrote to disk
 	SortLogEXT = ".sort"
+	// SchemaEXT is the file ext of schema snapshot file
+	SchemaEXT = ".schema"
 )
 
 const (
@@ -48,11 +50,16 @@ const (
 	DefaultRowLogFileType = "row"
 	// DefaultDDLLogFileType is the default file type of ddl log file
 	DefaultDDLLogFileType = "ddl"
+	// DefaultSchemaFileType is the default file type of schema snapshot file
+	DefaultSchemaFileType = "schema"
 )
 
 // LogMeta is used for store meta info.
 type LogMeta struct {
-	CheckPointTs   uint64           `msg:"checkPointTs"`
-	ResolvedTs     uint64           `msg:"resolvedTs"`
+	CheckPointTs uint64 `msg:"checkPointTs"`
+	ResolvedTs   uint64 `msg:"resolvedTs"`
+	// GCTs is the max commit ts of the log files removed by GC, the logs not
+	// after it may be incomplete.
+	GCTs           uint64           `msg:"gcTs"`
 	ResolvedTsList map[int64]uint64 `msg:"-"`
 }
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -57,12 +58,63 @@ var InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStor
 	return s3storage, nil
 }
 
+// InitExternalStorage init a storage used for the redo logs, the uri can be
+// s3, gcs or azure blob storage, e.g.
+//
+//	s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/
+//	gcs://logbucket/test-changefeed?credentials-file=/path/to/key.json
+//	azure://logbucket/test-changefeed?account-name=xxx&account-key=xxx
+//
+// The credentials of gcs and azure blob storage are taken from the environment
+// if they are not in the uri, see br for details.
+var InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+	if uri.Scheme == "s3" {
+		return InitS3storage(ctx, uri)
+	}
+	if len(uri.Host) == 0 {
+		return nil, cerror.WrapError(cerror.ErrS3StorageInitialize,
+			errors.Errorf("please specify the bucket for %s in %v", uri.Scheme, uri))
+	}
+	backend, err := storage.ParseBackend(uri.String(), &storage.BackendOptions{})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrS3StorageInitialize, err)
+	}
+	extStorage, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+		HTTPClient:      nil,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrS3StorageInitialize, err)
+	}
+	return extStorage, nil
+}
+
+// InitArchiveStorage init the storage the expired redo logs are archived to,
+// uri can be any external storage supported by br, such as s3 and local.
+var InitArchiveStorage = func(ctx context.Context, uri string) (storage.ExternalStorage, error) {
+	backend, err := storage.ParseBackend(uri, &storage.BackendOptions{})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRedoConfigInvalid, err)
+	}
+	archiveStorage, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRedoConfigInvalid, err)
+	}
+	return archiveStorage, nil
+}
+
 // ParseLogFileName extract the commitTs, fileType from log fileName
 func ParseLogFileName(name string) (uint64, string, error) {
 	ext := filepath.Ext(name)
 	if ext == MetaEXT {
 		return 0, DefaultMetaFileType, nil
 	}
+	if ext == SchemaEXT {
+		ts, err := ParseSchemaFileName(name)
+		return ts, DefaultSchemaFileType, err
+	}
 
 	// if .sort, the name should be like
 	// fmt.Sprintf("%s_%s_%d_%s_%d%s", w.cfg.captureID, w.cfg.changeFeedID, w.cfg.createTime.Unix(), w.cfg.fileType, w.commitTS.Load(), LogEXT)+SortLogEXT
@@ -89,3 +141,23 @@ func ParseLogFileName(name string) (uint64, string, error) {
 
 	return commitTs, fileType, nil
 }
+
+// SchemaFileName returns the name of the schema snapshot file of the
+// changefeed at ts.
+func SchemaFileName(changefeedID string, ts uint64) string {
+	return fmt.Sprintf("%s_%s_%d%s", changefeedID, DefaultSchemaFileType, ts, SchemaEXT)
+}
+
+// ParseSchemaFileName extracts the ts from schema snapshot fileName
+func ParseSchemaFileName(name string) (uint64, error) {
+	name = strings.TrimSuffix(filepath.Base(name), SchemaEXT)
+	idx := strings.LastIndex(name, "_")
+	if idx < 0 {
+		return 0, errors.Errorf("bad schema snapshot name: %s", name)
+	}
+	ts, err := strconv.ParseUint(name[idx+1:], 10, 64)
+	if err != nil {
+		return 0, errors.Annotatef(err, "bad schema snapshot name: %s", name)
+	}
+	return ts, nil
+}
@@ -81,6 +81,21 @@ func TestParseLogFileName(t *testing.T) {
 			wantTs:       0,
 			wantFileType: DefaultMetaFileType,
 		},
+		{
+			name: "happy .schema",
+			args: arg{
+				name: SchemaFileName("test_cf", 1),
+			},
+			wantTs:       1,
+			wantFileType: DefaultSchemaFileType,
+		},
+		{
+			name: "err wrong format .schema",
+			args: arg{
+				name: "sdfsdfsf" + SchemaEXT,
+			},
+			wantErr: ".*bad schema snapshot name*.",
+		},
 		{
 			name: "not supported fileType",
 			args: arg{
@@ -17,8 +17,14 @@ Package redo provide a redo log for cdc.
 There are three types of log file: meta log file, row log file, ddl log file.
 meta file used to store common.LogMeta info (CheckPointTs, ResolvedTs), atomic updated is guaranteed. A rotated file writer is used for other log files.
 All files will flush to disk or upload to s3 if enabled every defaultFlushIntervalInMs 1000ms or file size larger than defaultMaxLogSize 64 MB by default.
+The log files are flushed by group commit, i.e. every flush interval or once the logs written since the last flush exceed max-batch-size, and the resolved ts
+of a table in the meta file is moved forward only after the logs before it are flushed.
 The log file name is formatted as CaptureID_ChangeFeedID_CreateTime_FileType_MaxCommitTSOfAllEventInTheFile.log if safely wrote or end up with .log.tmp is not.
 meta file name is like CaptureID_ChangeFeedID_meta.meta
+Besides, the owner writes a schema snapshot file in the background after the schema of the changefeed is changed by DDLs, at most once per interval,
+it's a JSON encoded model.RedoSchemaSnapshot of the replicated tables, named like ChangeFeedID_schema_Ts.schema. The latest snapshot before the ts the logs
+are applied from and the DDL logs after it reconstruct the tables without the upstream, so the DDL logs after the latest snapshot before the checkpoint
+are kept from the GC.
 
 Each log file contains batch of model.RedoRowChangedEvent or model.RedoDDLEvent records wrote into different file with defaultMaxLogSize 64 MB.
 If larger than 64 MB will auto rotated to a new file.
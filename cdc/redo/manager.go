@@ -15,9 +15,11 @@ package redo
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,12 +29,20 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
-var updateRtsInterval = time.Second
+var (
+	updateRtsInterval = time.Second
+	// unhealthyFlushLag is the flush lag of a table making the redo logs
+	// unhealthy.
+	unhealthyFlushLag = time.Minute
+)
 
 // ConsistentLevelType is the level of redo log consistent level.
 type ConsistentLevelType string
@@ -50,6 +60,8 @@ const (
 	consistentStorageLocal     consistentStorage = "local"
 	consistentStorageNFS       consistentStorage = "nfs"
 	consistentStorageS3        consistentStorage = "s3"
+	consistentStorageGCS       consistentStorage = "gcs"
+	consistentStorageAzure     consistentStorage = "azure"
 	consistentStorageBlackhole consistentStorage = "blackhole"
 )
 
@@ -72,8 +84,8 @@ func IsValidConsistentLevel(level string) bool {
 // IsValidConsistentStorage checks whether a give consistent storage is valid
 func IsValidConsistentStorage(storage string) bool {
 	switch consistentStorage(storage) {
-	case consistentStorageLocal, consistentStorageNFS,
-		consistentStorageS3, consistentStorageBlackhole:
+	case consistentStorageLocal, consistentStorageNFS, consistentStorageS3,
+		consistentStorageGCS, consistentStorageAzure, consistentStorageBlackhole:
 		return true
 	default:
 		return false
@@ -90,21 +102,36 @@ func IsS3StorageEnabled(storage string) bool {
 	return consistentStorage(storage) == consistentStorageS3
 }
 
+// IsExternalStorageEnabled returns whether the redo logs are uploaded to an
+// external storage, i.e. s3, gcs or azure blob storage.
+func IsExternalStorageEnabled(storage string) bool {
+	switch consistentStorage(storage) {
+	case consistentStorageS3, consistentStorageGCS, consistentStorageAzure:
+		return true
+	default:
+		return false
+	}
+}
+
 // LogManager defines an interface that is used to manage redo log
 type LogManager interface {
 	// Enabled returns whether the log manager is enabled
 	Enabled() bool
 
-	// The following 6 APIs are called from processor only
+	// The following 8 APIs are called from processor only
 	TryEmitRowChangedEvents(ctx context.Context, tableID model.TableID, rows ...*model.RowChangedEvent) (bool, error)
 	EmitRowChangedEvents(ctx context.Context, tableID model.TableID, rows ...*model.RowChangedEvent) error
 	FlushLog(ctx context.Context, tableID model.TableID, resolvedTs uint64) error
 	AddTable(tableID model.TableID, startTs uint64)
 	RemoveTable(tableID model.TableID)
 	GetMinResolvedTs() uint64
+	GetTableBacklogs() map[model.TableID]*model.RedoTableBacklog
+	GetHealth() *model.RedoHealth
 
-	// EmitDDLEvent and FlushResolvedAndCheckpointTs are called from owner only
+	// EmitDDLEvent, EmitSchemaSnapshot and FlushResolvedAndCheckpointTs are
+	// called from owner only
 	EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
+	EmitSchemaSnapshot(ctx context.Context, snap *model.RedoSchemaSnapshot) error
 	FlushResolvedAndCheckpointTs(ctx context.Context, resolvedTs, checkpointTs uint64) (err error)
 
 	// Cleanup removes all redo logs
@@ -133,13 +160,16 @@ type ManagerImpl struct {
 	logBuffer chan cacheRows
 	writer    writer.RedoLogWriter
 
+	changefeedID  model.ChangeFeedID
 	minResolvedTs uint64
 	tableIDs      []model.TableID
 	rtsMap        map[model.TableID]uint64
-	rtsMapMu      sync.RWMutex
+	// emittedTsMap is the resolved ts of the tables sent to the redo log
+	// writer, the tables lag behind if their logs aren't flushed yet.
+	emittedTsMap map[model.TableID]uint64
+	rtsMapMu     sync.RWMutex
 
-	// record whether there exists a table being flushing resolved ts
-	flushing int64
+	metricFlushLag prometheus.Gauge
 }
 
 // NewManager creates a new Manager
@@ -152,21 +182,25 @@ func NewManager(ctx context.Context, cfg *config.ConsistentConfig, opts *Manager
 	if err != nil {
 		return nil, err
 	}
+	changeFeedID := util.ChangefeedIDFromCtx(ctx)
 	m := &ManagerImpl{
-		enabled:     true,
-		level:       ConsistentLevelType(cfg.Level),
-		storageType: consistentStorage(uri.Scheme),
-		rtsMap:      make(map[model.TableID]uint64),
-		logBuffer:   make(chan cacheRows, logBufferChanSize),
+		enabled:        true,
+		level:          ConsistentLevelType(cfg.Level),
+		storageType:    consistentStorage(uri.Scheme),
+		changefeedID:   changeFeedID,
+		rtsMap:         make(map[model.TableID]uint64),
+		emittedTsMap:   make(map[model.TableID]uint64),
+		logBuffer:      make(chan cacheRows, logBufferChanSize),
+		metricFlushLag: redoFlushLagGauge.WithLabelValues(changeFeedID),
 	}
 
 	switch m.storageType {
 	case consistentStorageBlackhole:
 		m.writer = writer.NewBlackHoleWriter()
-	case consistentStorageLocal, consistentStorageNFS, consistentStorageS3:
+	case consistentStorageLocal, consistentStorageNFS, consistentStorageS3,
+		consistentStorageGCS, consistentStorageAzure:
 		globalConf := config.GetGlobalServerConfig()
-		changeFeedID := util.ChangefeedIDFromCtx(ctx)
-		// We use a temporary dir to storage redo logs before flushing to other backends, such as S3
+		// We use a temporary dir to storage redo logs before flushing to other backends, such as S3 and GCS
 		redoDir := filepath.Join(globalConf.DataDir, config.DefaultRedoDir, changeFeedID)
 		if m.storageType == consistentStorageLocal || m.storageType == consistentStorageNFS {
 			// When using local or nfs as backend, store redo logs to redoDir directly.
@@ -174,16 +208,21 @@ func NewManager(ctx context.Context, cfg *config.ConsistentConfig, opts *Manager
 		}
 
 		writerCfg := &writer.LogWriterConfig{
-			Dir:               redoDir,
-			CaptureID:         util.CaptureAddrFromCtx(ctx),
-			ChangeFeedID:      changeFeedID,
-			CreateTime:        time.Now(),
-			MaxLogSize:        cfg.MaxLogSize,
-			FlushIntervalInMs: cfg.FlushIntervalInMs,
-			S3Storage:         m.storageType == consistentStorageS3,
+			Dir:                redoDir,
+			CaptureID:          util.CaptureAddrFromCtx(ctx),
+			ChangeFeedID:       changeFeedID,
+			CreateTime:         time.Now(),
+			MaxLogSize:         cfg.MaxLogSize,
+			FlushIntervalInMs:  cfg.FlushIntervalInMs,
+			MaxBatchSize:       cfg.MaxBatchSize,
+			UseExternalStorage: IsExternalStorageEnabled(string(m.storageType)),
+			Retention:          time.Duration(cfg.RetentionHours) * time.Hour,
+			RetentionSize:      cfg.RetentionSize,
+			ArchiveStorage:     cfg.ArchiveStorage,
+			Cipher:             encryption.GlobalCipher(),
 		}
-		if writerCfg.S3Storage {
-			writerCfg.S3URI = *uri
+		if writerCfg.UseExternalStorage {
+			writerCfg.URI = *uri
 		}
 		writer, err := writer.NewLogWriter(ctx, writerCfg)
 		if err != nil {
@@ -267,17 +306,18 @@ func (m *ManagerImpl) EmitRowChangedEvents(
 	return nil
 }
 
-// FlushLog emits resolved ts of a single table
+// FlushLog emits resolved ts of a single table, it's cheap since the logs are
+// flushed by the group commit of log writer in background.
 func (m *ManagerImpl) FlushLog(
 	ctx context.Context,
 	tableID model.TableID,
 	resolvedTs uint64,
 ) error {
-	// Use flushing as a lightweight lock to reduce log contention in log writer.
-	if !atomic.CompareAndSwapInt64(&m.flushing, 0, 1) {
-		return nil
+	m.rtsMapMu.Lock()
+	if ts, ok := m.emittedTsMap[tableID]; ok && ts < resolvedTs {
+		m.emittedTsMap[tableID] = resolvedTs
 	}
-	defer atomic.StoreInt64(&m.flushing, 0)
+	m.rtsMapMu.Unlock()
 	return m.writer.FlushLog(ctx, tableID, resolvedTs)
 }
 
@@ -286,11 +326,56 @@ func (m *ManagerImpl) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) err
 	return m.writer.SendDDL(ctx, DDLToRedo(ddl))
 }
 
+// EmitSchemaSnapshot sends schema snapshot to redo log writer
+func (m *ManagerImpl) EmitSchemaSnapshot(ctx context.Context, snap *model.RedoSchemaSnapshot) error {
+	return m.writer.WriteSchemaSnapshot(ctx, snap)
+}
+
 // GetMinResolvedTs returns the minimum resolved ts of all tables in this redo log manager
 func (m *ManagerImpl) GetMinResolvedTs() uint64 {
 	return atomic.LoadUint64(&m.minResolvedTs)
 }
 
+// GetTableBacklogs returns the resolved ts of each table sent to the redo log
+// and the one flushed to the storage.
+func (m *ManagerImpl) GetTableBacklogs() map[model.TableID]*model.RedoTableBacklog {
+	m.rtsMapMu.RLock()
+	defer m.rtsMapMu.RUnlock()
+	backlogs := make(map[model.TableID]*model.RedoTableBacklog, len(m.tableIDs))
+	for _, tableID := range m.tableIDs {
+		backlogs[tableID] = &model.RedoTableBacklog{
+			ResolvedTs: m.emittedTsMap[tableID],
+			FlushedTs:  m.rtsMap[tableID],
+		}
+	}
+	return backlogs
+}
+
+// GetHealth returns the health of the redo logs, they are unhealthy if the
+// last group commit failed, or the logs of a table are not flushed for a long
+// time. The health is persisted in the task position once it changes, so the
+// message doesn't carry the live lag.
+func (m *ManagerImpl) GetHealth() *model.RedoHealth {
+	if !m.enabled {
+		return nil
+	}
+	if err := m.writer.GetFlushError(); err != nil {
+		return &model.RedoHealth{Message: "failed to flush redo logs: " + err.Error()}
+	}
+	m.rtsMapMu.RLock()
+	defer m.rtsMapMu.RUnlock()
+	for _, tableID := range m.tableIDs {
+		lag := flushLag(m.emittedTsMap[tableID], m.rtsMap[tableID])
+		if lag >= unhealthyFlushLag {
+			return &model.RedoHealth{
+				Message: fmt.Sprintf("redo logs of table %d are not flushed for more than %s",
+					tableID, unhealthyFlushLag),
+			}
+		}
+	}
+	return &model.RedoHealth{Healthy: true}
+}
+
 // FlushResolvedAndCheckpointTs flushes resolved-ts and checkpoint-ts to redo log writer
 func (m *ManagerImpl) FlushResolvedAndCheckpointTs(ctx context.Context, resolvedTs, checkpointTs uint64) (err error) {
 	err = m.writer.EmitResolvedTs(ctx, resolvedTs)
@@ -319,6 +404,7 @@ func (m *ManagerImpl) AddTable(tableID model.TableID, startTs uint64) {
 		m.tableIDs[i] = tableID
 	}
 	m.rtsMap[tableID] = startTs
+	m.emittedTsMap[tableID] = startTs
 }
 
 // RemoveTable removes a table from redo log manager
@@ -332,6 +418,8 @@ func (m *ManagerImpl) RemoveTable(tableID model.TableID) {
 		copy(m.tableIDs[i:], m.tableIDs[i+1:])
 		m.tableIDs = m.tableIDs[:len(m.tableIDs)-1]
 		delete(m.rtsMap, tableID)
+		delete(m.emittedTsMap, tableID)
+		redoTableBacklogGauge.DeleteLabelValues(m.changefeedID, strconv.FormatInt(tableID, 10))
 	} else {
 		log.Warn("remove a table not maintained in redo log manager", zap.Int64("tableID", tableID))
 	}
@@ -359,12 +447,44 @@ func (m *ManagerImpl) updateTableResolvedTs(ctx context.Context) error {
 		}
 	}
 	atomic.StoreUint64(&m.minResolvedTs, minResolvedTs)
+
+	var maxLag time.Duration
+	for _, tableID := range m.tableIDs {
+		lag := flushLag(m.emittedTsMap[tableID], m.rtsMap[tableID])
+		redoTableBacklogGauge.WithLabelValues(m.changefeedID, strconv.FormatInt(tableID, 10)).
+			Set(lag.Seconds())
+		if lag > maxLag {
+			maxLag = lag
+		}
+	}
+	if m.metricFlushLag != nil {
+		m.metricFlushLag.Set(maxLag.Seconds())
+	}
 	return nil
 }
 
+// flushLag returns how long the resolved ts flushed lags behind the one sent
+// to the redo log writer.
+func flushLag(emittedTs, flushedTs uint64) time.Duration {
+	if emittedTs <= flushedTs {
+		return 0
+	}
+	return oracle.GetTimeFromTS(emittedTs).Sub(oracle.GetTimeFromTS(flushedTs))
+}
+
+func (m *ManagerImpl) cleanMetrics() {
+	redoFlushLagGauge.DeleteLabelValues(m.changefeedID)
+	m.rtsMapMu.RLock()
+	defer m.rtsMapMu.RUnlock()
+	for _, tableID := range m.tableIDs {
+		redoTableBacklogGauge.DeleteLabelValues(m.changefeedID, strconv.FormatInt(tableID, 10))
+	}
+}
+
 func (m *ManagerImpl) bgUpdateResolvedTs(ctx context.Context, errCh chan<- error) {
 	ticker := time.NewTicker(updateRtsInterval)
 	defer ticker.Stop()
+	defer m.cleanMetrics()
 	for {
 		select {
 		case <-ctx.Done():
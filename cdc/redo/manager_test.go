@@ -18,9 +18,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func TestConsistentConfig(t *testing.T) {
@@ -57,6 +61,8 @@ func TestConsistentConfig(t *testing.T) {
 		{"local", true},
 		{"nfs", true},
 		{"s3", true},
+		{"gcs", true},
+		{"azure", true},
 		{"blackhole", true},
 		{"Local", false},
 		{"", false},
@@ -77,6 +83,21 @@ func TestConsistentConfig(t *testing.T) {
 	for _, sc := range s3StorageCases {
 		require.Equal(t, sc.s3Enabled, IsS3StorageEnabled(sc.storage))
 	}
+
+	externalStorageCases := []struct {
+		storage string
+		enabled bool
+	}{
+		{"local", false},
+		{"nfs", false},
+		{"s3", true},
+		{"gcs", true},
+		{"azure", true},
+		{"blackhole", false},
+	}
+	for _, sc := range externalStorageCases {
+		require.Equal(t, sc.enabled, IsExternalStorageEnabled(sc.storage))
+	}
 }
 
 // TestLogManagerInProcessor tests how redo log manager is used in processor,
@@ -197,3 +218,50 @@ func TestLogManagerInOwner(t *testing.T) {
 	err = logMgr.writer.DeleteAllLogs(ctx)
 	require.Nil(t, err)
 }
+
+func TestLogManagerHealth(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	mockWriter := &writer.MockRedoLogWriter{}
+	logMgr := &ManagerImpl{
+		enabled:      true,
+		writer:       mockWriter,
+		rtsMap:       make(map[model.TableID]uint64),
+		emittedTsMap: make(map[model.TableID]uint64),
+	}
+	now := time.Now()
+	startTs := oracle.GoTimeToTS(now.Add(-2 * unhealthyFlushLag))
+	logMgr.AddTable(53, startTs)
+	logMgr.AddTable(55, startTs)
+
+	resolvedTs := oracle.GoTimeToTS(now)
+	mockWriter.On("FlushLog", mock.Anything, mock.Anything, resolvedTs).Return(nil)
+	require.Nil(t, logMgr.FlushLog(ctx, 53, resolvedTs))
+	require.Nil(t, logMgr.FlushLog(ctx, 55, resolvedTs))
+	// the logs of table 55 are not flushed yet
+	mockWriter.On("GetCurrentResolvedTs", mock.Anything, []model.TableID{53, 55}).
+		Return(map[int64]uint64{53: resolvedTs, 55: startTs}, nil).Once()
+	require.Nil(t, logMgr.updateTableResolvedTs(ctx))
+	require.Equal(t, map[model.TableID]*model.RedoTableBacklog{
+		53: {ResolvedTs: resolvedTs, FlushedTs: resolvedTs},
+		55: {ResolvedTs: resolvedTs, FlushedTs: startTs},
+	}, logMgr.GetTableBacklogs())
+	mockWriter.On("GetFlushError").Return(nil).Times(3)
+	health := logMgr.GetHealth()
+	require.False(t, health.Healthy)
+	require.Equal(t, "redo logs of table 55 are not flushed for more than 1m0s", health.Message)
+	// the health doesn't change with the lag, so it isn't persisted again
+	require.Equal(t, health, logMgr.GetHealth())
+
+	mockWriter.On("GetCurrentResolvedTs", mock.Anything, []model.TableID{53, 55}).
+		Return(map[int64]uint64{53: resolvedTs, 55: resolvedTs}, nil).Once()
+	require.Nil(t, logMgr.updateTableResolvedTs(ctx))
+	require.Equal(t, &model.RedoHealth{Healthy: true}, logMgr.GetHealth())
+
+	mockWriter.On("GetFlushError").Return(errors.New("upload failed")).Once()
+	health = logMgr.GetHealth()
+	require.False(t, health.Healthy)
+	require.Regexp(t, "failed to flush redo logs: upload failed", health.Message)
+	mockWriter.AssertExpectations(t)
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// RewindMeta rewinds the checkpoint ts and resolved ts recorded in all redo
+// log meta files of the changefeed to ts. It is used when the checkpoint of
+// the changefeed is rewound, so that applying the redo logs starts from ts and
+// covers the changes re-emitted after it, instead of a stale meta skipping them.
+//
+// NOTICE: with the local storage, only meta files on this capture are rewound,
+// the others are rewound by their captures when the changefeed runs again.
+func RewindMeta(ctx context.Context, cfg *config.ConsistentConfig, changefeedID string, ts uint64) error {
+	if cfg == nil || !IsConsistentEnabled(cfg.Level) {
+		return nil
+	}
+	uri, err := storage.ParseRawURL(cfg.Storage)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoConfigInvalid, err)
+	}
+	metaSuffix := "_" + changefeedID + "_" + common.DefaultMetaFileType + common.MetaEXT
+
+	switch consistentStorage(uri.Scheme) {
+	case consistentStorageBlackhole:
+		return nil
+	case consistentStorageLocal, consistentStorageNFS:
+		files, err := os.ReadDir(uri.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), metaSuffix) {
+				continue
+			}
+			path := filepath.Join(uri.Path, file.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrRedoFileOp, err)
+			}
+			if data, err = rewindLogMeta(data, ts); err != nil {
+				return errors.Trace(err)
+			}
+			tmpPath := path + common.MetaTmpEXT
+			if err := os.WriteFile(tmpPath, data, common.DefaultFileMode); err != nil {
+				return cerror.WrapError(cerror.ErrRedoFileOp, err)
+			}
+			if err := os.Rename(tmpPath, path); err != nil {
+				return cerror.WrapError(cerror.ErrRedoFileOp, err)
+			}
+			log.Info("redo log meta rewound", zap.String("changefeed", changefeedID),
+				zap.String("path", path), zap.Uint64("ts", ts))
+		}
+	case consistentStorageS3, consistentStorageGCS, consistentStorageAzure:
+		extStorage, err := common.InitExternalStorage(ctx, *uri)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var names []string
+		err = extStorage.WalkDir(ctx, &storage.WalkOption{}, func(path string, size int64) error {
+			if strings.HasSuffix(path, metaSuffix) {
+				names = append(names, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+		}
+		for _, name := range names {
+			data, err := extStorage.ReadFile(ctx, name)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+			}
+			if data, err = rewindLogMeta(data, ts); err != nil {
+				return errors.Trace(err)
+			}
+			if err := extStorage.WriteFile(ctx, name, data); err != nil {
+				return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+			}
+			log.Info("redo log meta rewound", zap.String("changefeed", changefeedID),
+				zap.String("name", name), zap.Uint64("ts", ts))
+		}
+	default:
+		return cerror.ErrConsistentStorage.GenWithStackByArgs(uri.Scheme)
+	}
+	return nil
+}
+
+// rewindLogMeta caps the checkpoint ts and resolved ts of an encoded meta to ts.
+func rewindLogMeta(data []byte, ts uint64) ([]byte, error) {
+	meta := &common.LogMeta{}
+	if _, err := meta.UnmarshalMsg(data); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	if meta.CheckPointTs > ts {
+		meta.CheckPointTs = ts
+	}
+	if meta.ResolvedTs > ts {
+		meta.ResolvedTs = ts
+	}
+	data, err := meta.MarshalMsg(nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewindMeta(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeMeta := func(name string, checkpointTs, resolvedTs uint64) {
+		meta := &common.LogMeta{CheckPointTs: checkpointTs, ResolvedTs: resolvedTs}
+		data, err := meta.MarshalMsg(nil)
+		require.Nil(t, err)
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), data, common.DefaultFileMode))
+	}
+	readMeta := func(name string) *common.LogMeta {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.Nil(t, err)
+		meta := &common.LogMeta{}
+		_, err = meta.UnmarshalMsg(data)
+		require.Nil(t, err)
+		return meta
+	}
+	writeMeta("capture1_cf_meta.meta", 200, 300)
+	writeMeta("capture2_cf_meta.meta", 50, 80)
+	writeMeta("capture1_cf2_meta.meta", 200, 300)
+
+	cfg := &config.ConsistentConfig{Level: "eventual", Storage: "nfs://" + dir}
+	require.Nil(t, RewindMeta(context.Background(), cfg, "cf", 100))
+
+	meta := readMeta("capture1_cf_meta.meta")
+	require.Equal(t, uint64(100), meta.CheckPointTs)
+	require.Equal(t, uint64(100), meta.ResolvedTs)
+	meta = readMeta("capture2_cf_meta.meta")
+	require.Equal(t, uint64(50), meta.CheckPointTs)
+	require.Equal(t, uint64(80), meta.ResolvedTs)
+	// meta files of other changefeeds are untouched
+	meta = readMeta("capture1_cf2_meta.meta")
+	require.Equal(t, uint64(200), meta.CheckPointTs)
+
+	// redo logs disabled
+	cfg = &config.ConsistentConfig{Level: "none", Storage: "nfs://" + dir}
+	require.Nil(t, RewindMeta(context.Background(), cfg, "cf2", 100))
+	meta = readMeta("capture1_cf2_meta.meta")
+	require.Equal(t, uint64(200), meta.CheckPointTs)
+}
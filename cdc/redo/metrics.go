@@ -0,0 +1,40 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	redoFlushLagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "flush_lag_seconds",
+		Help:      "The lag between the resolved ts sent to the redo log and the one flushed, of the slowest table",
+	}, []string{"changefeed"})
+
+	redoTableBacklogGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "redo",
+		Name:      "table_backlog_seconds",
+		Help:      "The lag between the resolved ts sent to the redo log and the one flushed of a table",
+	}, []string{"changefeed", "table"})
+)
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(redoFlushLagGauge)
+	registry.MustRegister(redoTableBacklogGauge)
+}
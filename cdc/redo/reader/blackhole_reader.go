@@ -47,6 +47,11 @@ func (br *BlackHoleReader) ReadMeta(ctx context.Context) (checkpointTs, resolved
 	return 0, 1, nil
 }
 
+// ReadSchemaSnapshot implements LogReader.ReadSchemaSnapshot
+func (br *BlackHoleReader) ReadSchemaSnapshot(ctx context.Context, ts uint64) (*model.RedoSchemaSnapshot, error) {
+	return nil, nil
+}
+
 // Close implement the Close interface
 func (br *BlackHoleReader) Close() error {
 	return nil
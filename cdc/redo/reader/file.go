@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -57,13 +58,16 @@ type fileReader interface {
 }
 
 type readerConfig struct {
-	dir        string
-	fileType   string
-	startTs    uint64
-	endTs      uint64
-	s3Storage  bool
-	s3URI      url.URL
-	workerNums int
+	dir                string
+	fileType           string
+	startTs            uint64
+	endTs              uint64
+	useExternalStorage bool
+	uri                url.URL
+	workerNums         int
+	// cipher decrypts the records of the log files, it's nil if they are not
+	// encrypted.
+	cipher *encryption.Cipher
 }
 
 type reader struct {
@@ -72,6 +76,7 @@ type reader struct {
 	br       *bufio.Reader
 	fileName string
 	closer   io.Closer
+	cipher   *encryption.Cipher
 	// lastValidOff file offset following the last valid decoded record
 	lastValidOff int64
 }
@@ -81,8 +86,8 @@ func newReader(ctx context.Context, cfg *readerConfig) ([]fileReader, error) {
 		return nil, cerror.WrapError(cerror.ErrRedoConfigInvalid, errors.New("readerConfig can not be nil"))
 	}
 
-	if cfg.s3Storage {
-		s3storage, err := common.InitS3storage(ctx, cfg.s3URI)
+	if cfg.useExternalStorage {
+		s3storage, err := common.InitExternalStorage(ctx, cfg.uri)
 		if err != nil {
 			return nil, err
 		}
@@ -96,7 +101,7 @@ func newReader(ctx context.Context, cfg *readerConfig) ([]fileReader, error) {
 		cfg.workerNums = defaultWorkerNum
 	}
 
-	rr, err := openSelectedFiles(ctx, cfg.dir, cfg.fileType, cfg.startTs, cfg.workerNums)
+	rr, err := openSelectedFiles(ctx, cfg.dir, cfg.fileType, cfg.startTs, cfg.workerNums, cfg.cipher)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +114,7 @@ func newReader(ctx context.Context, cfg *readerConfig) ([]fileReader, error) {
 				br:       bufio.NewReader(rr[i]),
 				fileName: rr[i].(*os.File).Name(),
 				closer:   rr[i],
+				cipher:   cfg.cipher,
 			})
 	}
 
@@ -164,7 +170,9 @@ func downLoadToLocal(ctx context.Context, dir string, s3storage storage.External
 	return eg.Wait()
 }
 
-func openSelectedFiles(ctx context.Context, dir, fixedType string, startTs uint64, workerNum int) ([]io.ReadCloser, error) {
+func openSelectedFiles(
+	ctx context.Context, dir, fixedType string, startTs uint64, workerNum int, cipher *encryption.Cipher,
+) ([]io.ReadCloser, error) {
 	files, err := ioutil.ReadDir(dir)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrRedoFileOp, errors.Annotatef(err, "can't read log file directory: %s", dir))
@@ -212,7 +220,7 @@ func openSelectedFiles(ctx context.Context, dir, fixedType string, startTs uint6
 		}
 	}
 
-	sortFiles, err := createSortedFiles(ctx, dir, unSortedFile, workerNum)
+	sortFiles, err := createSortedFiles(ctx, dir, unSortedFile, workerNum, cipher)
 	if err != nil {
 		return nil, err
 	}
@@ -224,11 +232,12 @@ func openReadFile(name string) (*os.File, error) {
 	return os.OpenFile(name, os.O_RDONLY, common.DefaultFileMode)
 }
 
-func readFile(file *os.File) (logHeap, error) {
+func readFile(file *os.File, cipher *encryption.Cipher) (logHeap, error) {
 	r := &reader{
 		br:       bufio.NewReader(file),
 		fileName: file.Name(),
 		closer:   file,
+		cipher:   cipher,
 	}
 	defer r.Close()
 
@@ -249,10 +258,11 @@ func readFile(file *os.File) (logHeap, error) {
 }
 
 // writFile if not safely closed, the sorted file will end up with .sort.tmp as the file name suffix
-func writFile(ctx context.Context, dir, name string, h logHeap) error {
+func writFile(ctx context.Context, dir, name string, cipher *encryption.Cipher, h logHeap) error {
 	cfg := &writer.FileWriterConfig{
 		Dir:        dir,
 		MaxLogSize: math.MaxInt32,
+		Cipher:     cipher,
 	}
 	w, err := writer.NewWriter(ctx, cfg, writer.WithLogFileName(func() string { return name }))
 	if err != nil {
@@ -274,7 +284,9 @@ func writFile(ctx context.Context, dir, name string, h logHeap) error {
 	return w.Close()
 }
 
-func createSortedFiles(ctx context.Context, dir string, names []string, workerNum int) ([]io.ReadCloser, error) {
+func createSortedFiles(
+	ctx context.Context, dir string, names []string, workerNum int, cipher *encryption.Cipher,
+) ([]io.ReadCloser, error) {
 	logFiles := []io.ReadCloser{}
 	errCh := make(chan error)
 	retCh := make(chan io.ReadCloser)
@@ -293,7 +305,7 @@ func createSortedFiles(ctx context.Context, dir string, names []string, workerNu
 		}
 
 		for i := 0; i < len(nn); i++ {
-			go createSortedFile(ctx, dir, nn[i], errCh, retCh)
+			go createSortedFile(ctx, dir, nn[i], cipher, errCh, retCh)
 		}
 		for i := 0; i < len(nn); i++ {
 			select {
@@ -313,7 +325,10 @@ func createSortedFiles(ctx context.Context, dir string, names []string, workerNu
 	return logFiles, nil
 }
 
-func createSortedFile(ctx context.Context, dir string, name string, errCh chan error, retCh chan io.ReadCloser) {
+func createSortedFile(
+	ctx context.Context, dir string, name string, cipher *encryption.Cipher,
+	errCh chan error, retCh chan io.ReadCloser,
+) {
 	path := filepath.Join(dir, name)
 	file, err := openReadFile(path)
 	if err != nil {
@@ -321,7 +336,7 @@ func createSortedFile(ctx context.Context, dir string, name string, errCh chan e
 		return
 	}
 
-	h, err := readFile(file)
+	h, err := readFile(file, cipher)
 	if err != nil {
 		errCh <- err
 		return
@@ -334,7 +349,7 @@ func createSortedFile(ctx context.Context, dir string, name string, errCh chan e
 	}
 
 	sortFileName := name + common.SortLogEXT
-	err = writFile(ctx, dir, sortFileName, h)
+	err = writFile(ctx, dir, sortFileName, cipher, h)
 	if err != nil {
 		errCh <- err
 		return
@@ -393,7 +408,17 @@ func (r *reader) Read(redoLog *model.RedoLog) error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	_, err = redoLog.UnmarshalMsg(data[:recBytes])
+	rec := data[:recBytes]
+	if r.cipher != nil {
+		rec, err = r.cipher.Open(nil, rec)
+		if err != nil {
+			if r.isTornEntry(data) {
+				return io.EOF
+			}
+			return err
+		}
+	}
+	_, err = redoLog.UnmarshalMsg(rec)
 	if err != nil {
 		if r.isTornEntry(data) {
 			// just return io.EOF, since if torn write it is the last redoLog entry
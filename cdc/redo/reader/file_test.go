@@ -15,6 +15,7 @@ package reader
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/leakutil"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
@@ -97,6 +99,79 @@ func TestReaderRead(t *testing.T) {
 	time.Sleep(1001 * time.Millisecond)
 }
 
+func TestReaderReadEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-reader-encrypted")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cipher, err := encryption.NewCipher(bytes.Repeat([]byte{1}, encryption.KeySize))
+	require.Nil(t, err)
+	cfg := &writer.FileWriterConfig{
+		MaxLogSize:   100000,
+		Dir:          dir,
+		ChangeFeedID: "test-cf",
+		CaptureID:    "cp",
+		FileType:     common.DefaultRowLogFileType,
+		CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		Cipher:       cipher,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w, err := writer.NewWriter(ctx, cfg)
+	require.Nil(t, err)
+	log := &model.RedoLog{
+		RedoRow: &model.RedoRowChangedEvent{Row: &model.RowChangedEvent{
+			CommitTs: 1123,
+			Table:    &model.TableName{Schema: "test", Table: "encrypted_table"},
+		}},
+	}
+	data, err := log.MarshalMsg(nil)
+	require.Nil(t, err)
+	w.AdvanceTs(11)
+	_, err = w.Write(data)
+	require.Nil(t, err)
+	err = w.Close()
+	require.Nil(t, err)
+	fileName := fmt.Sprintf("%s_%s_%d_%s_%d%s", cfg.CaptureID, cfg.ChangeFeedID, cfg.CreateTime.Unix(), cfg.FileType, 11, common.LogEXT)
+	content, err := ioutil.ReadFile(filepath.Join(cfg.Dir, fileName))
+	require.Nil(t, err)
+	require.False(t, bytes.Contains(content, []byte("encrypted_table")))
+
+	readerCfg := &readerConfig{
+		dir:      dir,
+		startTs:  1,
+		endTs:    12,
+		fileType: common.DefaultRowLogFileType,
+		cipher:   cipher,
+	}
+	r, err := newReader(ctx, readerCfg)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(r))
+	log = &model.RedoLog{}
+	err = r[0].Read(log)
+	require.Nil(t, err)
+	require.EqualValues(t, 1123, log.RedoRow.Row.CommitTs)
+	require.Equal(t, "encrypted_table", log.RedoRow.Row.Table.Table)
+	require.Nil(t, r[0].Close())
+
+	// The sorted file is encrypted too.
+	content, err = ioutil.ReadFile(filepath.Join(cfg.Dir, fileName+common.SortLogEXT))
+	require.Nil(t, err)
+	require.False(t, bytes.Contains(content, []byte("encrypted_table")))
+
+	// The logs can't be read with another key.
+	readerCfg.cipher, err = encryption.NewCipher(bytes.Repeat([]byte{2}, encryption.KeySize))
+	require.Nil(t, err)
+	r, err = newReader(ctx, readerCfg)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(r))
+	defer r[0].Close() //nolint:errcheck
+	err = r[0].Read(&model.RedoLog{})
+	require.Regexp(t, ".*ErrDataDecryptionFailed.*", err)
+	time.Sleep(1001 * time.Millisecond)
+}
+
 func TestReaderOpenSelectedFiles(t *testing.T) {
 	dir, err := ioutil.TempDir("", "redo-openSelectedFiles")
 	require.Nil(t, err)
@@ -210,7 +285,7 @@ func TestReaderOpenSelectedFiles(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		ret, err := openSelectedFiles(ctx, tt.args.dir, tt.args.fixedName, tt.args.startTs, 100)
+		ret, err := openSelectedFiles(ctx, tt.args.dir, tt.args.fixedName, tt.args.startTs, 100, nil)
 		if tt.wantErr == "" {
 			require.Nil(t, err, tt.name)
 			require.Equal(t, len(tt.wantRet), len(ret), tt.name)
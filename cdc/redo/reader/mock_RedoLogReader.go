@@ -115,6 +115,29 @@ func (_m *MockRedoLogReader) ReadNextLog(ctx context.Context, maxNumberOfEvents
 	return r0, r1
 }
 
+// ReadSchemaSnapshot provides a mock function with given fields: ctx, ts
+func (_m *MockRedoLogReader) ReadSchemaSnapshot(ctx context.Context, ts uint64) (*model.RedoSchemaSnapshot, error) {
+	ret := _m.Called(ctx, ts)
+
+	var r0 *model.RedoSchemaSnapshot
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.RedoSchemaSnapshot); ok {
+		r0 = rf(ctx, ts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RedoSchemaSnapshot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, ts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResetReader provides a mock function with given fields: ctx, startTs, endTs
 func (_m *MockRedoLogReader) ResetReader(ctx context.Context, startTs uint64, endTs uint64) error {
 	ret := _m.Called(ctx, startTs, endTs)
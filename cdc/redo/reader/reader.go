@@ -16,6 +16,7 @@ package reader
 import (
 	"container/heap"
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net/url"
@@ -26,12 +27,14 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/multierr"
 )
 
-//go:generate mockery --name=RedoLogReader --inpackage
 // RedoLogReader is a reader abstraction for redo log storage layer
+//
+//go:generate mockery --name=RedoLogReader --inpackage
 type RedoLogReader interface {
 	io.Closer
 
@@ -47,22 +50,32 @@ type RedoLogReader interface {
 
 	// ReadMeta reads meta from redo logs and returns the latest checkpointTs and resolvedTs
 	ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint64, err error)
+
+	// ReadSchemaSnapshot reads the latest schema snapshot not after ts, it
+	// returns nil if there is no such snapshot
+	ReadSchemaSnapshot(ctx context.Context, ts uint64) (*model.RedoSchemaSnapshot, error)
 }
 
 // LogReaderConfig is the config for LogReader
 type LogReaderConfig struct {
 	// Dir is the folder contains the redo logs need to apply when OP environment or
-	// the folder used to download redo logs to if s3 enabled
-	Dir       string
-	S3Storage bool
-	// S3URI should be like S3URI="s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/"
-	S3URI url.URL
+	// the folder used to download redo logs to if external storage is enabled
+	Dir                string
+	UseExternalStorage bool
+	// URI is the external storage of the redo logs if UseExternalStorage is
+	// true, e.g. "s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/",
+	// "gcs://logbucket/test-changefeed?credentials-file=/path/to/key.json" or
+	// "azure://logbucket/test-changefeed?account-name=xxx&account-key=xxx".
+	URI url.URL
 	// WorkerNums is the num of workers used to sort the log file to sorted file,
 	// will load the file to memory first then write the sorted file to disk
 	// the memory used is WorkerNums * defaultMaxLogSize (64 * megabyte) total
 	WorkerNums int
-	startTs    uint64
-	endTs      uint64
+	// Cipher decrypts the records of the log files, it's nil if they are not
+	// encrypted.
+	Cipher  *encryption.Cipher
+	startTs uint64
+	endTs   uint64
 }
 
 // LogReader implement RedoLogReader interface
@@ -90,8 +103,8 @@ func NewLogReader(ctx context.Context, cfg *LogReaderConfig) (*LogReader, error)
 	logReader := &LogReader{
 		cfg: cfg,
 	}
-	if cfg.S3Storage {
-		s3storage, err := common.InitS3storage(ctx, cfg.S3URI)
+	if cfg.UseExternalStorage {
+		s3storage, err := common.InitExternalStorage(ctx, cfg.URI)
 		if err != nil {
 			return nil, err
 		}
@@ -104,6 +117,10 @@ func NewLogReader(ctx context.Context, cfg *LogReaderConfig) (*LogReader, error)
 		if err != nil {
 			return nil, cerror.WrapError(cerror.ErrRedoDownloadFailed, err)
 		}
+		err = downLoadToLocal(ctx, cfg.Dir, s3storage, common.DefaultSchemaFileType)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrRedoDownloadFailed, err)
+		}
 	}
 	return logReader, nil
 }
@@ -122,10 +139,13 @@ func (l *LogReader) ResetReader(ctx context.Context, startTs, endTs uint64) erro
 			return err
 		}
 	}
-	if startTs > endTs || startTs > l.meta.ResolvedTs || endTs <= l.meta.CheckPointTs {
+	// The range before the checkpoint ts is allowed, since the logs passed by
+	// the checkpoint can be retained in the storage, the caller must make sure
+	// the logs in the range are not garbage collected.
+	if startTs > endTs || startTs > l.meta.ResolvedTs {
 		return errors.Errorf(
-			"startTs, endTs (%d, %d] should match the boundary: (%d, %d]",
-			startTs, endTs, l.meta.CheckPointTs, l.meta.ResolvedTs)
+			"startTs, endTs (%d, %d] should match the boundary: startTs <= endTs, startTs <= %d",
+			startTs, endTs, l.meta.ResolvedTs)
 	}
 	return l.setUpReader(ctx, startTs, endTs)
 }
@@ -151,13 +171,14 @@ func (l *LogReader) setUpRowReader(ctx context.Context, startTs, endTs uint64) e
 	}
 
 	rowCfg := &readerConfig{
-		dir:        l.cfg.Dir,
-		fileType:   common.DefaultRowLogFileType,
-		startTs:    startTs,
-		endTs:      endTs,
-		s3Storage:  l.cfg.S3Storage,
-		s3URI:      l.cfg.S3URI,
-		workerNums: l.cfg.WorkerNums,
+		dir:                l.cfg.Dir,
+		fileType:           common.DefaultRowLogFileType,
+		startTs:            startTs,
+		endTs:              endTs,
+		useExternalStorage: l.cfg.UseExternalStorage,
+		uri:                l.cfg.URI,
+		workerNums:         l.cfg.WorkerNums,
+		cipher:             l.cfg.Cipher,
 	}
 	l.rowReader, err = newReader(ctx, rowCfg)
 	if err != nil {
@@ -180,13 +201,14 @@ func (l *LogReader) setUpDDLReader(ctx context.Context, startTs, endTs uint64) e
 	}
 
 	ddlCfg := &readerConfig{
-		dir:        l.cfg.Dir,
-		fileType:   common.DefaultDDLLogFileType,
-		startTs:    startTs,
-		endTs:      endTs,
-		s3Storage:  l.cfg.S3Storage,
-		s3URI:      l.cfg.S3URI,
-		workerNums: l.cfg.WorkerNums,
+		dir:                l.cfg.Dir,
+		fileType:           common.DefaultDDLLogFileType,
+		startTs:            startTs,
+		endTs:              endTs,
+		useExternalStorage: l.cfg.UseExternalStorage,
+		uri:                l.cfg.URI,
+		workerNums:         l.cfg.WorkerNums,
+		cipher:             l.cfg.Cipher,
 	}
 	l.ddlReader, err = newReader(ctx, ddlCfg)
 	if err != nil {
@@ -380,6 +402,47 @@ func (l *LogReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint
 	return l.meta.CheckPointTs, l.meta.ResolvedTs, nil
 }
 
+// ReadSchemaSnapshot implement ReadSchemaSnapshot interface
+func (l *LogReader) ReadSchemaSnapshot(ctx context.Context, ts uint64) (*model.RedoSchemaSnapshot, error) {
+	select {
+	case <-ctx.Done():
+		return nil, errors.Trace(ctx.Err())
+	default:
+	}
+
+	files, err := ioutil.ReadDir(l.cfg.Dir)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRedoFileOp, errors.Annotate(err, "can't read log file directory"))
+	}
+	var latest string
+	var latestTs uint64
+	for _, file := range files {
+		if filepath.Ext(file.Name()) != common.SchemaEXT {
+			continue
+		}
+		snapTs, err := common.ParseSchemaFileName(file.Name())
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+		if snapTs <= ts && (latest == "" || snapTs > latestTs) {
+			latest, latestTs = file.Name(), snapTs
+		}
+	}
+	if latest == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(l.cfg.Dir, latest))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	snap := &model.RedoSchemaSnapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	return snap, nil
+}
+
 func (l *LogReader) closeRowReader() error {
 	var errs error
 	for _, r := range l.rowReader {
@@ -15,6 +15,7 @@ package reader
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -50,23 +51,23 @@ func TestNewLogReader(t *testing.T) {
 	s3URI, err := url.Parse("s3://logbucket/test-changefeed?endpoint=http://111/")
 	require.Nil(t, err)
 
-	origin := common.InitS3storage
+	origin := common.InitExternalStorage
 	defer func() {
-		common.InitS3storage = origin
+		common.InitExternalStorage = origin
 	}()
 	controller := gomock.NewController(t)
 	mockStorage := mockstorage.NewMockExternalStorage(controller)
-	// no file to download
-	mockStorage.EXPECT().WalkDir(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-	common.InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+	// no meta file or schema snapshot to download
+	mockStorage.EXPECT().WalkDir(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	common.InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
 		return mockStorage, nil
 	}
 
 	// after init should rm the dir
 	_, err = NewLogReader(context.Background(), &LogReaderConfig{
-		S3Storage: true,
-		Dir:       dir,
-		S3URI:     *s3URI,
+		UseExternalStorage: true,
+		Dir:                dir,
+		URI:                *s3URI,
 	})
 	require.Nil(t, err)
 	_, err = os.Stat(dir)
@@ -149,6 +150,20 @@ func TestLogReaderResetReader(t *testing.T) {
 			rowFleName:  f1.Name(),
 			ddlFleName:  f.Name(),
 		},
+		{
+			name: "before checkpoint",
+			args: arg{
+				ctx:          context.Background(),
+				startTs:      1,
+				endTs:        101,
+				checkPointTs: 150,
+				resolvedTs:   200,
+			},
+			wantStartTs: 1,
+			wantEndTs:   101,
+			rowFleName:  f1.Name(),
+			ddlFleName:  f.Name(),
+		},
 		{
 			name: "context cancel",
 			args: arg{
@@ -231,6 +246,30 @@ func TestLogReaderResetReader(t *testing.T) {
 	time.Sleep(1001 * time.Millisecond)
 }
 
+func TestLogReaderReadSchemaSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-ReadSchemaSnapshot")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, ts := range []uint64{10, 20} {
+		data, err := json.Marshal(&model.RedoSchemaSnapshot{Ts: ts})
+		require.Nil(t, err)
+		path := filepath.Join(dir, common.SchemaFileName("test-changefeed", ts))
+		require.Nil(t, os.WriteFile(path, data, common.DefaultFileMode))
+	}
+	r := &LogReader{cfg: &LogReaderConfig{Dir: dir}}
+
+	snap, err := r.ReadSchemaSnapshot(context.Background(), 5)
+	require.Nil(t, err)
+	require.Nil(t, snap)
+	snap, err = r.ReadSchemaSnapshot(context.Background(), 19)
+	require.Nil(t, err)
+	require.EqualValues(t, 10, snap.Ts)
+	snap, err = r.ReadSchemaSnapshot(context.Background(), 20)
+	require.Nil(t, err)
+	require.EqualValues(t, 20, snap.Ts)
+}
+
 func TestLogReaderReadMeta(t *testing.T) {
 	dir, err := ioutil.TempDir("", "redo-ReadMeta")
 	require.Nil(t, err)
@@ -68,6 +68,11 @@ func (bs *blackHoleWriter) SendDDL(_ context.Context, ddl *model.RedoDDLEvent) e
 	return nil
 }
 
+func (bs *blackHoleWriter) WriteSchemaSnapshot(_ context.Context, snap *model.RedoSchemaSnapshot) error {
+	log.Debug("write schema snapshot", zap.Uint64("ts", snap.Ts), zap.Int("tables", len(snap.Tables)))
+	return nil
+}
+
 func (bs *blackHoleWriter) EmitResolvedTs(_ context.Context, ts uint64) error {
 	bs.resolvedTs = ts
 	return nil
@@ -88,6 +93,10 @@ func (bs *blackHoleWriter) GetCurrentResolvedTs(_ context.Context, tableIDs []in
 	return rtsMap, nil
 }
 
+func (bs *blackHoleWriter) GetFlushError() error {
+	return nil
+}
+
 func (bs *blackHoleWriter) Close() error {
 	return nil
 }
@@ -30,12 +30,14 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/uber-go/atomic"
 	pioutil "go.etcd.io/etcd/pkg/v3/ioutil"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -48,7 +50,18 @@ const (
 
 const (
 	defaultFlushIntervalInMs = 1000
-	defaultS3Timeout         = 3 * time.Second
+	defaultStorageTimeout    = 3 * time.Second
+	// archiveBufferSize is the size of the chunks a log file is streamed to
+	// the archive storage in.
+	archiveBufferSize = 1024 * 1024
+	// multipartUploadThreshold is the size since which a log file is uploaded
+	// to the external storage in parts rather than read into memory at once,
+	// the parts are uploaded by multipart upload on s3, resumable upload on
+	// gcs and staged blocks on azure.
+	multipartUploadThreshold = 8 * 1024 * 1024
+	// uploadPartSize is the size of the parts a log file is uploaded in, it
+	// can't be smaller than the minimum part size of s3 multipart upload.
+	uploadPartSize = 5 * 1024 * 1024
 )
 
 var (
@@ -65,7 +78,7 @@ type fileWriter interface {
 	// AdvanceTs receive the commitTs in the event from caller
 	AdvanceTs(commitTs uint64)
 	// GC run gc to remove useless files base on the checkPointTs
-	GC(checkPointTs uint64) error
+	GC(ctx context.Context, checkPointTs uint64) error
 	// IsRunning check the fileWriter status
 	IsRunning() bool
 }
@@ -82,10 +95,18 @@ type FileWriterConfig struct {
 	FileType     string
 	CreateTime   time.Time
 	// MaxLogSize is the maximum size of log in megabyte, defaults to defaultMaxLogSize.
-	MaxLogSize        int64
-	FlushIntervalInMs int64
-	S3Storage         bool
-	S3URI             url.URL
+	MaxLogSize         int64
+	FlushIntervalInMs  int64
+	UseExternalStorage bool
+	URI                url.URL
+	// Retention is how long the log files are kept after the checkpoint passes them.
+	Retention time.Duration
+	// ArchiveStorage is the storage the expired log files are archived to before
+	// they are removed, they are not archived if it's empty.
+	ArchiveStorage string
+	// Cipher encrypts the records of the log files, they are not encrypted if
+	// it's nil.
+	Cipher *encryption.Cipher
 }
 
 // Option define the writerOptions
@@ -93,6 +114,12 @@ type Option func(writer *writerOptions)
 
 type writerOptions struct {
 	getLogFileName func() string
+	// noBgFlush is true if the writer is flushed by its owner rather than
+	// every flush interval in background.
+	noBgFlush bool
+	// recordGCTs is called with the max commit ts of the log files before
+	// they're removed by GC.
+	recordGCTs func(gcTs uint64) error
 }
 
 // WithLogFileName provide the Option for fileName
@@ -104,6 +131,22 @@ func WithLogFileName(f func() string) Option {
 	}
 }
 
+// WithoutBackgroundFlush provide the Option to flush the writer by its owner
+// only, e.g. the group commit of LogWriter.
+func WithoutBackgroundFlush() Option {
+	return func(o *writerOptions) {
+		o.noBgFlush = true
+	}
+}
+
+// WithGCTsRecorder provide the Option to record the max commit ts of the log
+// files before they're removed by GC, the files are kept if it fails.
+func WithGCTsRecorder(f func(gcTs uint64) error) Option {
+	return func(o *writerOptions) {
+		o.recordGCTs = f
+	}
+}
+
 // Writer is a redo log event Writer which writes redo log events to a file.
 type Writer struct {
 	cfg *FileWriterConfig
@@ -121,6 +164,8 @@ type Writer struct {
 	bw            *pioutil.PageWriter
 	uint64buf     []byte
 	storage       storage.ExternalStorage
+	// archive is the storage expired log files are archived to, nil if not configured
+	archive storage.ExternalStorage
 	sync.RWMutex
 
 	metricFsyncDuration    prometheus.Observer
@@ -141,10 +186,18 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 	if cfg.MaxLogSize == 0 {
 		cfg.MaxLogSize = defaultMaxLogSize
 	}
-	var s3storage storage.ExternalStorage
-	if cfg.S3Storage {
+	var extStorage storage.ExternalStorage
+	if cfg.UseExternalStorage {
 		var err error
-		s3storage, err = common.InitS3storage(ctx, cfg.S3URI)
+		extStorage, err = common.InitExternalStorage(ctx, cfg.URI)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var archiveStorage storage.ExternalStorage
+	if cfg.ArchiveStorage != "" {
+		var err error
+		archiveStorage, err = common.InitArchiveStorage(ctx, cfg.ArchiveStorage)
 		if err != nil {
 			return nil, err
 		}
@@ -158,7 +211,8 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 		cfg:       cfg,
 		op:        op,
 		uint64buf: make([]byte, 8),
-		storage:   s3storage,
+		storage:   extStorage,
+		archive:   archiveStorage,
 
 		metricFsyncDuration:    redoFsyncDurationHistogram.WithLabelValues(cfg.ChangeFeedID),
 		metricFlushAllDuration: redoFlushAllDurationHistogram.WithLabelValues(cfg.ChangeFeedID),
@@ -166,7 +220,9 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 	}
 
 	w.running.Store(true)
-	go w.runFlushToDisk(ctx, cfg.FlushIntervalInMs)
+	if !op.noBgFlush {
+		go w.runFlushToDisk(ctx, cfg.FlushIntervalInMs)
+	}
 
 	return w, nil
 }
@@ -201,6 +257,14 @@ func (w *Writer) Write(rawData []byte) (int, error) {
 	w.Lock()
 	defer w.Unlock()
 
+	if w.cfg.Cipher != nil {
+		var err error
+		rawData, err = w.cfg.Cipher.Seal(nil, rawData)
+		if err != nil {
+			return 0, cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+	}
+
 	writeLen := int64(len(rawData))
 	if writeLen > w.cfg.MaxLogSize {
 		return 0, cerror.ErrFileSizeExceed.GenWithStackByArgs(writeLen, w.cfg.MaxLogSize)
@@ -305,11 +369,11 @@ func (w *Writer) close() error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	if w.cfg.S3Storage {
-		ctx, cancel := context.WithTimeout(context.Background(), defaultS3Timeout)
+	if w.cfg.UseExternalStorage {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultStorageTimeout)
 		defer cancel()
 
-		err = w.renameInS3(ctx, w.file.Name(), w.filePath())
+		err = w.renameInExternalStorage(ctx, w.file.Name(), w.filePath())
 		if err != nil {
 			return cerror.WrapError(cerror.ErrS3StorageAPI, err)
 		}
@@ -320,8 +384,8 @@ func (w *Writer) close() error {
 	return cerror.WrapError(cerror.ErrRedoFileOp, err)
 }
 
-func (w *Writer) renameInS3(ctx context.Context, oldPath, newPath string) error {
-	err := w.writeToS3(ctx, newPath)
+func (w *Writer) renameInExternalStorage(ctx context.Context, oldPath, newPath string) error {
+	err := w.writeToExternalStorage(ctx, newPath)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrS3StorageAPI, err)
 	}
@@ -413,7 +477,7 @@ func (w *Writer) rotate() error {
 }
 
 // GC implement GC interface
-func (w *Writer) GC(checkPointTs uint64) error {
+func (w *Writer) GC(ctx context.Context, checkPointTs uint64) error {
 	if !w.IsRunning() || w.isGCRunning() {
 		return nil
 	}
@@ -427,20 +491,47 @@ func (w *Writer) GC(checkPointTs uint64) error {
 	}
 
 	var errs error
-	for _, f := range remove {
-		err := os.Remove(filepath.Join(w.cfg.Dir, f.Name()))
-		errs = multierr.Append(errs, err)
+	if w.archive != nil {
+		// the files failed to be archived are kept and retried in the next round
+		var archived []os.FileInfo
+		for _, f := range remove {
+			if err := w.archiveFile(ctx, f.Name()); err != nil {
+				errs = multierr.Append(errs, err)
+				continue
+			}
+			archived = append(archived, f)
+		}
+		remove = archived
+	}
+	if len(remove) > 0 && w.op != nil && w.op.recordGCTs != nil {
+		var gcTs uint64
+		for _, f := range remove {
+			// the name has been parsed by shouldRemoved
+			commitTs, _, _ := common.ParseLogFileName(f.Name())
+			if commitTs > gcTs {
+				gcTs = commitTs
+			}
+		}
+		if err := w.op.recordGCTs(gcTs); err != nil {
+			return cerror.WrapError(cerror.ErrRedoFileOp, multierr.Append(errs, err))
+		}
 	}
-
-	if errs != nil {
-		return cerror.WrapError(cerror.ErrRedoFileOp, errs)
+	// the files removed locally are never listed again, so they must be deleted
+	// in s3 even if other files fail to be archived or removed.
+	var removed []os.FileInfo
+	for _, f := range remove {
+		if err := os.Remove(filepath.Join(w.cfg.Dir, f.Name())); err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		removed = append(removed, f)
 	}
 
-	if w.cfg.S3Storage {
+	if w.cfg.UseExternalStorage && len(removed) > 0 {
 		// since if fail delete in s3, do not block any path, so just log the error if any
 		go func() {
 			var errs error
-			for _, f := range remove {
+			for _, f := range removed {
 				err := w.storage.DeleteFile(context.Background(), f.Name())
 				errs = multierr.Append(errs, err)
 			}
@@ -451,11 +542,50 @@ func (w *Writer) GC(checkPointTs uint64) error {
 		}()
 	}
 
+	if errs != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, errs)
+	}
+	return nil
+}
+
+// archiveFile streams the log file to the archive storage.
+func (w *Writer) archiveFile(ctx context.Context, name string) error {
+	f, err := os.Open(filepath.Join(w.cfg.Dir, name))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	defer f.Close()
+
+	writer, err := w.archive.Create(ctx, name)
+	if err != nil {
+		return cerror.ErrRedoArchive.Wrap(err).GenWithStackByArgs(name)
+	}
+	buf := make([]byte, archiveBufferSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if _, err := writer.Write(ctx, buf[:n]); err != nil {
+				_ = writer.Close(ctx)
+				return cerror.ErrRedoArchive.Wrap(err).GenWithStackByArgs(name)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = writer.Close(ctx)
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+	}
+	if err := writer.Close(ctx); err != nil {
+		return cerror.ErrRedoArchive.Wrap(err).GenWithStackByArgs(name)
+	}
 	return nil
 }
 
 // shouldRemoved remove the file which commitTs in file name (max commitTs of all event ts in the file) < checkPointTs,
-// since all event ts < checkPointTs already sent to sink, the log is not needed any more for recovery
+// since all event ts < checkPointTs already sent to sink, the log is not needed any more for recovery.
+// If retention is set, the file is kept until the checkpoint is ahead of its commitTs by the retention.
 func (w *Writer) shouldRemoved(checkPointTs uint64, f os.FileInfo) (bool, error) {
 	if filepath.Ext(f.Name()) != common.LogEXT {
 		return false, nil
@@ -466,7 +596,13 @@ func (w *Writer) shouldRemoved(checkPointTs uint64, f os.FileInfo) (bool, error)
 		return false, err
 	}
 
-	return commitTs < checkPointTs && fileType == w.cfg.FileType, nil
+	if commitTs >= checkPointTs || fileType != w.cfg.FileType {
+		return false, nil
+	}
+	if w.cfg.Retention <= 0 {
+		return true, nil
+	}
+	return oracle.GetTimeFromTS(checkPointTs).Sub(oracle.GetTimeFromTS(commitTs)) >= w.cfg.Retention, nil
 }
 
 func (w *Writer) getShouldRemovedFiles(checkPointTs uint64) ([]os.FileInfo, error) {
@@ -507,14 +643,14 @@ func (w *Writer) flushAll() error {
 	if err != nil {
 		return err
 	}
-	if !w.cfg.S3Storage {
+	if !w.cfg.UseExternalStorage {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultS3Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultStorageTimeout)
 	defer cancel()
 
-	err = w.writeToS3(ctx, w.file.Name())
+	err = w.writeToExternalStorage(ctx, w.file.Name())
 	w.metricFlushAllDuration.Observe(time.Since(start).Seconds())
 
 	return err
@@ -546,12 +682,54 @@ func (w *Writer) flush() error {
 	return cerror.WrapError(cerror.ErrRedoFileOp, err)
 }
 
-func (w *Writer) writeToS3(ctx context.Context, name string) error {
+func (w *Writer) writeToExternalStorage(ctx context.Context, name string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	if info.Size() >= multipartUploadThreshold {
+		return w.uploadInParts(ctx, name)
+	}
+
 	fileData, err := os.ReadFile(name)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	// Key in s3: aws.String(rs.options.Prefix + name), prefix should be changefeed name
+	// Key in storage: rs.options.Prefix + name, prefix should be changefeed name
 	return cerror.WrapError(cerror.ErrS3StorageAPI, w.storage.WriteFile(ctx, filepath.Base(name), fileData))
 }
+
+// uploadInParts streams the log file to the external storage in parts of
+// uploadPartSize, the file is visible in the storage once all the parts are
+// uploaded.
+func (w *Writer) uploadInParts(ctx context.Context, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	defer f.Close()
+
+	writer, err := w.storage.Create(ctx, filepath.Base(name))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+	}
+	buf := make([]byte, uploadPartSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			if _, err := writer.Write(ctx, buf[:n]); err != nil {
+				_ = writer.Close(ctx)
+				return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			_ = writer.Close(ctx)
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+	}
+	return cerror.WrapError(cerror.ErrS3StorageAPI, writer.Close(ctx))
+}
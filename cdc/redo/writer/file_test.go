@@ -26,9 +26,11 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/errors"
 	mockstorage "github.com/pingcap/tidb/br/pkg/mock/storage"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/pkg/leakutil"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/uber-go/atomic"
 )
 
@@ -160,14 +162,14 @@ func TestWriterGC(t *testing.T) {
 
 	megabyte = 1
 	cfg := &FileWriterConfig{
-		Dir:               dir,
-		ChangeFeedID:      "test",
-		CaptureID:         "cp",
-		MaxLogSize:        10,
-		FileType:          common.DefaultRowLogFileType,
-		CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-		FlushIntervalInMs: 5,
-		S3Storage:         true,
+		Dir:                dir,
+		ChangeFeedID:       "test",
+		CaptureID:          "cp",
+		MaxLogSize:         10,
+		FileType:           common.DefaultRowLogFileType,
+		CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		FlushIntervalInMs:  5,
+		UseExternalStorage: true,
 	}
 	w := &Writer{
 		cfg:                    cfg,
@@ -192,7 +194,7 @@ func TestWriterGC(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, 3, len(files), "should have 3 log file")
 
-	err = w.GC(3)
+	err = w.GC(context.Background(), 3)
 	require.Nil(t, err)
 
 	err = w.Close()
@@ -215,10 +217,146 @@ func TestWriterGC(t *testing.T) {
 	}
 	w1.cfg.Dir += "not-exist"
 	w1.running.Store(true)
-	err = w1.GC(111)
+	err = w1.GC(context.Background(), 111)
 	require.Nil(t, err)
 }
 
+func TestWriterGCWithRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-GC-retention")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	archiveDir, err := ioutil.TempDir("", "redo-archive")
+	require.Nil(t, err)
+	defer os.RemoveAll(archiveDir)
+
+	archiveStorage, err := storage.NewLocalStorage(archiveDir)
+	require.Nil(t, err)
+	cfg := &FileWriterConfig{
+		Dir:       dir,
+		FileType:  common.DefaultRowLogFileType,
+		Retention: time.Hour,
+	}
+	w := &Writer{cfg: cfg, archive: archiveStorage}
+	w.running.Store(true)
+
+	now := time.Now()
+	oldTs := oracle.GoTimeToTS(now.Add(-2 * time.Hour))
+	recentTs := oracle.GoTimeToTS(now.Add(-30 * time.Minute))
+	checkpointTs := oracle.GoTimeToTS(now)
+	oldFile := fmt.Sprintf("cp_test_946688461_row_%d.log", oldTs)
+	recentFile := fmt.Sprintf("cp_test_946688461_row_%d.log", recentTs)
+	for _, name := range []string{oldFile, recentFile} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("redo"), 0o644))
+	}
+
+	// only the file older than the checkpoint by the retention is removed,
+	// and it's archived before removed.
+	require.Nil(t, w.GC(context.Background(), checkpointTs))
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, recentFile, files[0].Name())
+	data, err := os.ReadFile(filepath.Join(archiveDir, oldFile))
+	require.Nil(t, err)
+	require.Equal(t, []byte("redo"), data)
+
+	// the files not passed by the checkpoint are always kept.
+	require.Nil(t, w.GC(context.Background(), recentTs))
+	files, err = ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestWriterGCRecordGCTs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-GC-record")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{
+		"cp_test_946688461_row_1.log",
+		"cp_test_946688461_row_2.log",
+		"cp_test_946688461_row_3.log",
+	} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("redo"), 0o644))
+	}
+	cfg := &FileWriterConfig{Dir: dir, FileType: common.DefaultRowLogFileType}
+	var recorded uint64
+	recordErr := errors.New("record fail")
+	op := &writerOptions{}
+	WithGCTsRecorder(func(gcTs uint64) error {
+		recorded = gcTs
+		return recordErr
+	})(op)
+	w := &Writer{cfg: cfg, op: op}
+	w.running.Store(true)
+
+	// the files are kept if the gc ts fails to be recorded
+	require.NotNil(t, w.GC(context.Background(), 3))
+	require.EqualValues(t, 2, recorded)
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 3)
+
+	recordErr = nil
+	require.Nil(t, w.GC(context.Background(), 3))
+	require.EqualValues(t, 2, recorded)
+	files, err = ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestWriterGCArchiveFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-GC-archive-fail")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	archiveDir, err := ioutil.TempDir("", "redo-archive")
+	require.Nil(t, err)
+	defer os.RemoveAll(archiveDir)
+
+	archivedFile := "cp_test_946688461_row_1.log"
+	failedFile := "cp_test_946688461_row_2.log"
+	for _, name := range []string{archivedFile, failedFile} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("redo"), 0o644))
+	}
+	// a directory of the same name makes the archive of the file fail
+	require.Nil(t, os.Mkdir(filepath.Join(archiveDir, failedFile), 0o755))
+	archiveStorage, err := storage.NewLocalStorage(archiveDir)
+	require.Nil(t, err)
+
+	deleted := make(chan struct{})
+	controller := gomock.NewController(t)
+	mockStorage := mockstorage.NewMockExternalStorage(controller)
+	mockStorage.EXPECT().DeleteFile(gomock.Any(), archivedFile).DoAndReturn(
+		func(context.Context, string) error {
+			close(deleted)
+			return nil
+		}).Times(1)
+
+	cfg := &FileWriterConfig{
+		Dir:                dir,
+		FileType:           common.DefaultRowLogFileType,
+		UseExternalStorage: true,
+	}
+	w := &Writer{cfg: cfg, storage: mockStorage, archive: archiveStorage}
+	w.running.Store(true)
+
+	// the archived file is removed both locally and in s3 though the other
+	// one fails to be archived, which is kept for the next round.
+	require.NotNil(t, w.GC(context.Background(), 3))
+	select {
+	case <-deleted:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the archived file is not deleted in s3")
+	}
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, failedFile, files[0].Name())
+	data, err := os.ReadFile(filepath.Join(archiveDir, archivedFile))
+	require.Nil(t, err)
+	require.Equal(t, []byte("redo"), data)
+}
+
 func TestAdvanceTs(t *testing.T) {
 	w := &Writer{}
 	w.AdvanceTs(111)
@@ -237,9 +375,9 @@ func TestNewWriter(t *testing.T) {
 	defer os.RemoveAll(dir)
 
 	w, err := NewWriter(context.Background(), &FileWriterConfig{
-		Dir:       "sdfsf",
-		S3Storage: true,
-		S3URI:     *s3URI,
+		Dir:                "sdfsf",
+		UseExternalStorage: true,
+		URI:                *s3URI,
 	})
 	require.Nil(t, err)
 	time.Sleep(time.Duration(defaultFlushIntervalInMs+1) * time.Millisecond)
@@ -255,13 +393,13 @@ func TestNewWriter(t *testing.T) {
 
 	w = &Writer{
 		cfg: &FileWriterConfig{
-			Dir:          dir,
-			CaptureID:    "cp",
-			ChangeFeedID: "test",
-			FileType:     common.DefaultDDLLogFileType,
-			CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			S3Storage:    true,
-			MaxLogSize:   defaultMaxLogSize,
+			Dir:                dir,
+			CaptureID:          "cp",
+			ChangeFeedID:       "test",
+			FileType:           common.DefaultDDLLogFileType,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			UseExternalStorage: true,
+			MaxLogSize:         defaultMaxLogSize,
 		},
 		uint64buf:              make([]byte, 8),
 		storage:                mockStorage,
@@ -281,3 +419,32 @@ func TestNewWriter(t *testing.T) {
 	require.Equal(t, w.running.Load(), false)
 	time.Sleep(time.Duration(defaultFlushIntervalInMs+1) * time.Millisecond)
 }
+
+func TestWriteToExternalStorageInParts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-upload")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	storageDir, err := ioutil.TempDir("", "redo-storage")
+	require.Nil(t, err)
+	defer os.RemoveAll(storageDir)
+
+	extStorage, err := storage.NewLocalStorage(storageDir)
+	require.Nil(t, err)
+	w := &Writer{cfg: &FileWriterConfig{Dir: dir}, storage: extStorage}
+
+	// the small file is written at once, and the large one is uploaded in
+	// parts, the last part of which is smaller than the part size.
+	small := []byte("redo")
+	large := make([]byte, multipartUploadThreshold+uploadPartSize/2)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	for name, data := range map[string][]byte{"small.log": small, "large.log": large} {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.WriteFile(path, data, 0o644))
+		require.Nil(t, w.writeToExternalStorage(context.Background(), path))
+		uploaded, err := os.ReadFile(filepath.Join(storageDir, name))
+		require.Nil(t, err)
+		require.Equal(t, data, uploaded)
+	}
+}
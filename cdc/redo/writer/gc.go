@@ -0,0 +1,263 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// defaultStorageGCIntervalInMs is the interval of the GC of the redo logs in
+// the storage, it's longer than the GC of the local files since the storage
+// is listed entirely.
+var defaultStorageGCIntervalInMs = 60000
+
+// logFile is a redo log file in the storage of the changefeed.
+type logFile struct {
+	name     string
+	size     int64
+	commitTs uint64
+	fileType string
+}
+
+// storageGC removes the redo logs in the storage of the changefeed by the
+// retention, no matter which capture writes them. Unlike the GC of the file
+// writers, which only removes the files written by this capture, it also
+// removes the files left by the captures gone, so the storage doesn't grow
+// unboundedly. It's a no-op until the checkpoint ts is emitted to the writer,
+// i.e. only the writer of the owner lists the storage, removes the files and
+// reports the storage usage.
+func (l *LogWriter) storageGC(ctx context.Context) error {
+	l.metaLock.RLock()
+	checkpointTs := l.meta.CheckPointTs
+	l.metaLock.RUnlock()
+
+	if checkpointTs == 0 {
+		return nil
+	}
+	files, snapshots, usage, err := l.listLogFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	expired := expiredLogFiles(files, checkpointTs, l.cfg.Retention, l.cfg.RetentionSize*megabyte)
+	if len(expired) > 0 {
+		// the files are sorted by commitTs
+		if err := l.advanceGCTs(expired[len(expired)-1].commitTs); err != nil {
+			return err
+		}
+	}
+	expired = retainDDLLogFiles(expired, snapshots, checkpointTs)
+	expired = append(expired, expiredSchemaSnapshots(snapshots, checkpointTs, l.cfg.Retention)...)
+	for _, f := range expired {
+		if err := l.removeLogFile(ctx, f); err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		usage -= f.size
+		if l.metricGCRemovedFiles != nil {
+			l.metricGCRemovedFiles.Inc()
+		}
+	}
+	if l.metricStorageUsage != nil {
+		l.metricStorageUsage.Set(float64(usage))
+	}
+	if len(expired) > 0 {
+		log.Info("redo logs removed by retention",
+			zap.String("changefeed", l.cfg.ChangeFeedID),
+			zap.Uint64("checkpointTs", checkpointTs),
+			zap.Int("files", len(expired)),
+			zap.Int64("usage", usage))
+	}
+	return errs
+}
+
+// listLogFiles returns the log files and the schema snapshot files in the
+// storage sorted by commitTs, and the size of all the files in the storage.
+func (l *LogWriter) listLogFiles(ctx context.Context) ([]logFile, []logFile, int64, error) {
+	var files, snapshots []logFile
+	var usage int64
+	add := func(name string, size int64) {
+		usage += size
+		ext := filepath.Ext(name)
+		if ext != common.LogEXT && ext != common.SchemaEXT {
+			return
+		}
+		commitTs, fileType, err := common.ParseLogFileName(name)
+		if err != nil {
+			log.Warn("parse redo log file name fail", zap.String("logFile", name), zap.Error(err))
+			return
+		}
+		if ext == common.SchemaEXT {
+			snapshots = append(snapshots, logFile{name: name, size: size, commitTs: commitTs})
+			return
+		}
+		files = append(files, logFile{name: name, size: size, commitTs: commitTs, fileType: fileType})
+	}
+
+	if l.cfg.UseExternalStorage {
+		err := l.storage.WalkDir(ctx, &storage.WalkOption{}, func(path string, size int64) error {
+			add(path, size)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, 0, cerror.WrapError(cerror.ErrS3StorageAPI, err)
+		}
+	} else {
+		infos, err := ioutil.ReadDir(l.cfg.Dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil, 0, nil
+			}
+			return nil, nil, 0, cerror.WrapError(cerror.ErrRedoFileOp,
+				errors.Annotatef(err, "can't read log file directory: %s", l.cfg.Dir))
+		}
+		for _, info := range infos {
+			if !info.IsDir() {
+				add(info.Name(), info.Size())
+			}
+		}
+	}
+	for _, fs := range [][]logFile{files, snapshots} {
+		fs := fs
+		sort.Slice(fs, func(i, j int) bool {
+			return fs[i].commitTs < fs[j].commitTs
+		})
+	}
+	return files, snapshots, usage, nil
+}
+
+// expiredLogFiles returns the log files to be removed, the files passed by the
+// checkpoint are removed from the oldest one, if they're older than the
+// checkpoint by the retention, or the total size of the files exceeds
+// maxSize. The files not passed by the checkpoint are always kept, since they
+// are needed for recovery.
+func expiredLogFiles(files []logFile, checkpointTs uint64, retention time.Duration, maxSize int64) []logFile {
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	var expired []logFile
+	for _, f := range files {
+		if f.commitTs >= checkpointTs {
+			break
+		}
+		timeExpired := retention <= 0 ||
+			oracle.GetTimeFromTS(checkpointTs).Sub(oracle.GetTimeFromTS(f.commitTs)) >= retention
+		sizeExceeded := maxSize > 0 && total > maxSize
+		if !timeExpired && !sizeExceeded {
+			break
+		}
+		expired = append(expired, f)
+		total -= f.size
+	}
+	return expired
+}
+
+// expiredSchemaSnapshots returns the schema snapshot files to be removed, they
+// are removed by the retention like the log files, except the latest one not
+// after the checkpoint, which is the schema the redo logs are applied from.
+func expiredSchemaSnapshots(snapshots []logFile, checkpointTs uint64, retention time.Duration) []logFile {
+	latest := sort.Search(len(snapshots), func(i int) bool {
+		return snapshots[i].commitTs > checkpointTs
+	}) - 1
+	if latest <= 0 {
+		return nil
+	}
+	return expiredLogFiles(snapshots[:latest], checkpointTs, retention, 0)
+}
+
+// retainDDLLogFiles keeps the DDL log files after the latest schema snapshot
+// not after the checkpoint out of the expired files. The snapshots are rate
+// limited, so the schema is reconstructed from the snapshot and the DDL logs
+// after it.
+func retainDDLLogFiles(expired []logFile, snapshots []logFile, checkpointTs uint64) []logFile {
+	latest := sort.Search(len(snapshots), func(i int) bool {
+		return snapshots[i].commitTs > checkpointTs
+	}) - 1
+	if latest < 0 {
+		return expired
+	}
+	retained := expired[:0]
+	for _, f := range expired {
+		if f.fileType == common.DefaultDDLLogFileType && f.commitTs > snapshots[latest].commitTs {
+			continue
+		}
+		retained = append(retained, f)
+	}
+	return retained
+}
+
+// removeLogFile removes a log file from the storage, and the local copy of it
+// if any. It's archived first if the archive storage is configured.
+func (l *LogWriter) removeLogFile(ctx context.Context, f logFile) error {
+	if !l.cfg.UseExternalStorage {
+		if l.archive != nil {
+			data, err := os.ReadFile(filepath.Join(l.cfg.Dir, f.name))
+			if err != nil {
+				if os.IsNotExist(err) {
+					// removed by the file writer already
+					return nil
+				}
+				return cerror.WrapError(cerror.ErrRedoFileOp, err)
+			}
+			if err := l.archive.WriteFile(ctx, filepath.Base(f.name), data); err != nil {
+				return cerror.ErrRedoArchive.Wrap(err).GenWithStackByArgs(f.name)
+			}
+		}
+		err := os.Remove(filepath.Join(l.cfg.Dir, f.name))
+		if err != nil && !os.IsNotExist(err) {
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+		return nil
+	}
+
+	if l.archive != nil {
+		data, err := l.storage.ReadFile(ctx, f.name)
+		if err != nil {
+			if isNotExistInS3(err) {
+				return nil
+			}
+			return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+		}
+		if err := l.archive.WriteFile(ctx, filepath.Base(f.name), data); err != nil {
+			return cerror.ErrRedoArchive.Wrap(err).GenWithStackByArgs(f.name)
+		}
+	}
+	err := l.storage.DeleteFile(ctx, f.name)
+	if err != nil && !isNotExistInS3(err) {
+		return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+	}
+	// the local copy is removed by the file writer later if the logs are
+	// written by this capture, remove it now since it's expired by size.
+	err = os.Remove(filepath.Join(l.cfg.Dir, filepath.Base(f.name)))
+	if err != nil && !os.IsNotExist(err) {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	return nil
+}
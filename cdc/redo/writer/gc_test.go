@@ -0,0 +1,160 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockstorage "github.com/pingcap/tidb/br/pkg/mock/storage"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestExpiredLogFiles(t *testing.T) {
+	now := time.Now()
+	checkpointTs := oracle.GoTimeToTS(now)
+	files := []logFile{
+		{name: "a", size: 10, commitTs: oracle.GoTimeToTS(now.Add(-3 * time.Hour))},
+		{name: "b", size: 10, commitTs: oracle.GoTimeToTS(now.Add(-2 * time.Hour))},
+		{name: "c", size: 10, commitTs: oracle.GoTimeToTS(now.Add(-30 * time.Minute))},
+		{name: "d", size: 10, commitTs: checkpointTs},
+	}
+
+	// all the files passed by the checkpoint are removed without retention.
+	require.Equal(t, files[:3], expiredLogFiles(files, checkpointTs, 0, 0))
+	// only the files older than the checkpoint by the retention are removed.
+	require.Equal(t, files[:2], expiredLogFiles(files, checkpointTs, time.Hour, 0))
+	// the oldest files are removed until the size is within the limit.
+	require.Equal(t, files[:1], expiredLogFiles(files, checkpointTs, 24*time.Hour, 30))
+	require.Equal(t, files[:2], expiredLogFiles(files, checkpointTs, time.Hour, 30))
+	// the files not passed by the checkpoint are kept even if the size exceeds.
+	require.Equal(t, files[:3], expiredLogFiles(files, checkpointTs, 24*time.Hour, 5))
+}
+
+func TestExpiredSchemaSnapshots(t *testing.T) {
+	now := time.Now()
+	checkpointTs := oracle.GoTimeToTS(now)
+	snapshots := []logFile{
+		{name: "a", commitTs: oracle.GoTimeToTS(now.Add(-3 * time.Hour))},
+		{name: "b", commitTs: oracle.GoTimeToTS(now.Add(-2 * time.Hour))},
+		{name: "c", commitTs: oracle.GoTimeToTS(now.Add(time.Minute))},
+	}
+
+	// the latest snapshot not after the checkpoint is always kept.
+	require.Equal(t, snapshots[:1], expiredSchemaSnapshots(snapshots, checkpointTs, 0))
+	require.Len(t, expiredSchemaSnapshots(snapshots, checkpointTs, 4*time.Hour), 0)
+	require.Len(t, expiredSchemaSnapshots(snapshots[2:], checkpointTs, 0), 0)
+}
+
+func TestRetainDDLLogFiles(t *testing.T) {
+	expired := []logFile{
+		{name: "a", commitTs: 100, fileType: common.DefaultDDLLogFileType},
+		{name: "b", commitTs: 200, fileType: common.DefaultRowLogFileType},
+		{name: "c", commitTs: 300, fileType: common.DefaultDDLLogFileType},
+		{name: "d", commitTs: 400, fileType: common.DefaultRowLogFileType},
+	}
+	snapshots := []logFile{{name: "s1", commitTs: 150}, {name: "s2", commitTs: 500}}
+
+	// the DDL logs after the latest snapshot not after the checkpoint are kept.
+	require.Equal(t, []logFile{expired[0], expired[1], expired[3]},
+		retainDDLLogFiles(append([]logFile{}, expired...), snapshots, 450))
+	// all the files are removed without snapshots.
+	require.Equal(t, expired, retainDDLLogFiles(append([]logFile{}, expired...), nil, 450))
+}
+
+func TestLogWriterStorageGC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-storage-GC")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	archiveDir, err := ioutil.TempDir("", "redo-storage-archive")
+	require.Nil(t, err)
+	defer os.RemoveAll(archiveDir)
+
+	now := time.Now()
+	oldTs := oracle.GoTimeToTS(now.Add(-2 * time.Hour))
+	recentTs := oracle.GoTimeToTS(now.Add(-30 * time.Minute))
+	oldFile := fmt.Sprintf("cp1_test_946688461_row_%d.log", oldTs)
+	recentFile := fmt.Sprintf("cp2_test_946688461_ddl_%d.log", recentTs)
+	for _, name := range []string{oldFile, recentFile, "cp1_test_meta.meta"} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte("redo"), 0o644))
+	}
+	archiveStorage, err := storage.NewLocalStorage(archiveDir)
+	require.Nil(t, err)
+	l := &LogWriter{
+		cfg: &LogWriterConfig{
+			Dir:          dir,
+			CaptureID:    "cp1",
+			ChangeFeedID: "test",
+			Retention:    time.Hour,
+		},
+		archive:              archiveStorage,
+		meta:                 &common.LogMeta{ResolvedTsList: map[int64]uint64{}},
+		metricStorageUsage:   redoStorageUsageGauge.WithLabelValues("test"),
+		metricGCRemovedFiles: redoGCRemovedFilesCounter.WithLabelValues("test"),
+	}
+
+	// nothing is removed until the checkpoint is emitted.
+	require.Nil(t, l.storageGC(context.Background()))
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 3)
+
+	// the files of any capture are removed by the retention, and archived.
+	l.meta.CheckPointTs = oracle.GoTimeToTS(now)
+	require.Nil(t, l.storageGC(context.Background()))
+	files, err = ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 2)
+	_, err = os.Stat(filepath.Join(dir, oldFile))
+	require.True(t, os.IsNotExist(err))
+	data, err := os.ReadFile(filepath.Join(archiveDir, oldFile))
+	require.Nil(t, err)
+	require.Equal(t, []byte("redo"), data)
+	// the gc ts is persisted in the meta before the files are removed.
+	data, err = os.ReadFile(filepath.Join(dir, "cp1_test_meta.meta"))
+	require.Nil(t, err)
+	meta := &common.LogMeta{}
+	_, err = meta.UnmarshalMsg(data)
+	require.Nil(t, err)
+	require.Equal(t, oldTs, meta.GCTs)
+
+	// the files in the external storage are removed by size.
+	controller := gomock.NewController(t)
+	mockStorage := mockstorage.NewMockExternalStorage(controller)
+	mockStorage.EXPECT().WalkDir(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, opt *storage.WalkOption, fn func(string, int64) error) error {
+			require.Nil(t, fn(recentFile, 2*megabyte))
+			return fn("cp1_test_meta.meta", 1)
+		})
+	mockStorage.EXPECT().WriteFile(gomock.Any(), "cp1_test_meta.meta", gomock.Any()).Return(nil)
+	mockStorage.EXPECT().DeleteFile(gomock.Any(), recentFile).Return(nil)
+	l.cfg.UseExternalStorage = true
+	l.cfg.RetentionSize = 1
+	l.storage = mockStorage
+	l.archive = nil
+	require.Nil(t, l.storageGC(context.Background()))
+	// the local copy is removed as well.
+	_, err = os.Stat(filepath.Join(dir, recentFile))
+	require.True(t, os.IsNotExist(err))
+	require.Equal(t, recentTs, l.meta.GCTs)
+}
@@ -46,6 +46,35 @@ var (
 		Buckets:   prometheus.ExponentialBuckets(0.001, 2.0, 13),
 	}, []string{"changefeed"})
 
+	redoFlushBatchBytesHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "flush_batch_bytes",
+		Help:      "The size distributions of the redo logs flushed by a group commit",
+		Buckets:   prometheus.ExponentialBuckets(1024, 2.0, 16),
+	}, []string{"changefeed"})
+
+	redoStorageUsageGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "storage_usage_bytes",
+		Help:      "The size of the redo log files in the storage of the changefeed",
+	}, []string{"changefeed"})
+
+	redoGCRemovedFilesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "gc_removed_files_total",
+		Help:      "Total number of redo log files removed from the storage by retention",
+	}, []string{"changefeed"})
+
+	redoFlushFailuresCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "flush_failures_total",
+		Help:      "Total number of failed group commits, e.g. the logs can't be uploaded to the external storage",
+	}, []string{"changefeed"})
+
 	redoTotalRowsCountGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: subsystem,
@@ -60,4 +89,8 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(redoTotalRowsCountGauge)
 	registry.MustRegister(redoWriteBytesGauge)
 	registry.MustRegister(redoFlushAllDurationHistogram)
+	registry.MustRegister(redoFlushBatchBytesHistogram)
+	registry.MustRegister(redoStorageUsageGauge)
+	registry.MustRegister(redoGCRemovedFilesCounter)
+	registry.MustRegister(redoFlushFailuresCounter)
 }
@@ -106,6 +106,20 @@ func (_m *MockRedoLogWriter) GetCurrentResolvedTs(ctx context.Context, tableIDs
 	return r0, r1
 }
 
+// GetFlushError provides a mock function with given fields:
+func (_m *MockRedoLogWriter) GetFlushError() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendDDL provides a mock function with given fields: ctx, ddl
 func (_m *MockRedoLogWriter) SendDDL(ctx context.Context, ddl *model.RedoDDLEvent) error {
 	ret := _m.Called(ctx, ddl)
@@ -140,3 +154,17 @@ func (_m *MockRedoLogWriter) WriteLog(ctx context.Context, tableID int64, rows [
 
 	return r0, r1
 }
+
+// WriteSchemaSnapshot provides a mock function with given fields: ctx, snap
+func (_m *MockRedoLogWriter) WriteSchemaSnapshot(ctx context.Context, snap *model.RedoSchemaSnapshot) error {
+	ret := _m.Called(ctx, snap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RedoSchemaSnapshot) error); ok {
+		r0 = rf(ctx, snap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
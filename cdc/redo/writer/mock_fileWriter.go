@@ -15,7 +15,11 @@
 
 package writer
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // mockFileWriter is an autogenerated mock type for the fileWriter type
 type mockFileWriter struct {
@@ -55,13 +59,13 @@ func (_m *mockFileWriter) Flush() error {
 	return r0
 }
 
-// GC provides a mock function with given fields: checkPointTs
-func (_m *mockFileWriter) GC(checkPointTs uint64) error {
-	ret := _m.Called(checkPointTs)
+// GC provides a mock function with given fields: ctx, checkPointTs
+func (_m *mockFileWriter) GC(ctx context.Context, checkPointTs uint64) error {
+	ret := _m.Called(ctx, checkPointTs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint64) error); ok {
-		r0 = rf(checkPointTs)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, checkPointTs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -15,6 +15,7 @@ package writer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -24,6 +25,8 @@ import (
 	"sync"
 	"time"
 
+	gcs "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pingcap/errors"
@@ -31,15 +34,18 @@ import (
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/atomic"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
-//go:generate mockery --name=RedoLogWriter --inpackage
 // RedoLogWriter defines the interfaces used to write redo log, all operations are thread-safe
+//
+//go:generate mockery --name=RedoLogWriter --inpackage
 type RedoLogWriter interface {
 	io.Closer
 
@@ -50,9 +56,15 @@ type RedoLogWriter interface {
 	// SendDDL writer RedoDDLEvent to ddl log file
 	SendDDL(ctx context.Context, ddl *model.RedoDDLEvent) error
 
+	// WriteSchemaSnapshot writes the schema snapshot to a file of its own,
+	// called from owner only.
+	WriteSchemaSnapshot(ctx context.Context, snap *model.RedoSchemaSnapshot) error
+
 	// FlushLog sends resolved-ts from table pipeline to log writer, it is
 	// essential to flush when a table doesn't have any row change event for
 	// some time, and the resolved ts of this table should be moved forward.
+	// The resolved ts takes effect once the logs written before it are
+	// flushed by the next group commit.
 	FlushLog(ctx context.Context, tableID int64, ts uint64) error
 
 	// EmitCheckpointTs write CheckpointTs to meta file
@@ -61,9 +73,14 @@ type RedoLogWriter interface {
 	// EmitResolvedTs write ResolvedTs to meta file
 	EmitResolvedTs(ctx context.Context, ts uint64) error
 
-	// GetCurrentResolvedTs return all the ResolvedTs list for given tableIDs
+	// GetCurrentResolvedTs return all the ResolvedTs list for given tableIDs,
+	// the logs before which are durable.
 	GetCurrentResolvedTs(ctx context.Context, tableIDs []int64) (resolvedTsList map[int64]uint64, err error)
 
+	// GetFlushError returns the error of the last group commit, it's nil if
+	// the last group commit succeeded.
+	GetFlushError() error
+
 	// DeleteAllLogs delete all log files related to the changefeed, called from owner only when delete changefeed
 	DeleteAllLogs(ctx context.Context) error
 }
@@ -90,9 +107,27 @@ type LogWriterConfig struct {
 	// MaxLogSize is the maximum size of log in megabyte, defaults to defaultMaxLogSize.
 	MaxLogSize        int64
 	FlushIntervalInMs int64
-	S3Storage         bool
-	// S3URI should be like S3URI="s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/"
-	S3URI url.URL
+	// MaxBatchSize is the size of the logs in megabyte written since the last
+	// group commit, which triggers a group commit before the flush interval
+	// elapses, 0 means the logs are only flushed every flush interval.
+	MaxBatchSize       int64
+	UseExternalStorage bool
+	// URI is the external storage of the redo logs if UseExternalStorage is
+	// true, e.g. "s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/",
+	// "gcs://logbucket/test-changefeed?credentials-file=/path/to/key.json" or
+	// "azure://logbucket/test-changefeed?account-name=xxx&account-key=xxx".
+	URI url.URL
+	// Retention is how long the log files are kept after the checkpoint passes them.
+	Retention time.Duration
+	// RetentionSize is the max size in megabyte of the log files in the storage,
+	// the oldest files passed by the checkpoint are removed once it's exceeded
+	// even if they're in the retention. 0 means unlimited.
+	RetentionSize int64
+	// ArchiveStorage is the storage the expired log files are archived to.
+	ArchiveStorage string
+	// Cipher encrypts the records of the log files, they are not encrypted if
+	// it's nil.
+	Cipher *encryption.Cipher
 }
 
 // LogWriter implement the RedoLogWriter interface
@@ -101,10 +136,29 @@ type LogWriter struct {
 	rowWriter fileWriter
 	ddlWriter fileWriter
 	storage   storage.ExternalStorage
-	meta      *common.LogMeta
-	metaLock  sync.RWMutex
+	// archive is the storage expired log files are archived to, nil if not configured
+	archive  storage.ExternalStorage
+	meta     *common.LogMeta
+	metaLock sync.RWMutex
+
+	// pendingTs is the max commitTs or resolved ts of the tables, whose logs
+	// are written but not flushed yet. They're moved to the meta by the group
+	// commit once the logs are durable.
+	pendingTs   map[int64]uint64
+	pendingLock sync.Mutex
+	// batchBytes is the size of the logs written since the last group commit.
+	batchBytes    atomic.Int64
+	maxBatchBytes int64
+	// commitCh triggers a group commit once the batch is full.
+	commitCh chan struct{}
+	// flushErr is the error of the last group commit.
+	flushErr atomic.Error
 
 	metricTotalRowsCount prometheus.Gauge
+	metricBatchBytes     prometheus.Observer
+	metricStorageUsage   prometheus.Gauge
+	metricGCRemovedFiles prometheus.Counter
+	metricFlushFailures  prometheus.Counter
 }
 
 // NewLogWriter creates a LogWriter instance. It is guaranteed only one LogWriter per changefeed
@@ -126,35 +180,47 @@ func NewLogWriter(ctx context.Context, cfg *LogWriterConfig) (*LogWriter, error)
 	var err error
 	var logWriter *LogWriter
 	rowCfg := &FileWriterConfig{
-		Dir:               cfg.Dir,
-		ChangeFeedID:      cfg.ChangeFeedID,
-		CaptureID:         cfg.CaptureID,
-		FileType:          common.DefaultRowLogFileType,
-		CreateTime:        cfg.CreateTime,
-		MaxLogSize:        cfg.MaxLogSize,
-		FlushIntervalInMs: cfg.FlushIntervalInMs,
-		S3Storage:         cfg.S3Storage,
-		S3URI:             cfg.S3URI,
+		Dir:                cfg.Dir,
+		ChangeFeedID:       cfg.ChangeFeedID,
+		CaptureID:          cfg.CaptureID,
+		FileType:           common.DefaultRowLogFileType,
+		CreateTime:         cfg.CreateTime,
+		MaxLogSize:         cfg.MaxLogSize,
+		FlushIntervalInMs:  cfg.FlushIntervalInMs,
+		UseExternalStorage: cfg.UseExternalStorage,
+		URI:                cfg.URI,
+		Retention:          cfg.Retention,
+		ArchiveStorage:     cfg.ArchiveStorage,
+		Cipher:             cfg.Cipher,
 	}
 	ddlCfg := &FileWriterConfig{
-		Dir:               cfg.Dir,
-		ChangeFeedID:      cfg.ChangeFeedID,
-		CaptureID:         cfg.CaptureID,
-		FileType:          common.DefaultDDLLogFileType,
-		CreateTime:        cfg.CreateTime,
-		MaxLogSize:        cfg.MaxLogSize,
-		FlushIntervalInMs: cfg.FlushIntervalInMs,
-		S3Storage:         cfg.S3Storage,
-		S3URI:             cfg.S3URI,
+		Dir:                cfg.Dir,
+		ChangeFeedID:       cfg.ChangeFeedID,
+		CaptureID:          cfg.CaptureID,
+		FileType:           common.DefaultDDLLogFileType,
+		CreateTime:         cfg.CreateTime,
+		MaxLogSize:         cfg.MaxLogSize,
+		FlushIntervalInMs:  cfg.FlushIntervalInMs,
+		UseExternalStorage: cfg.UseExternalStorage,
+		URI:                cfg.URI,
+		Retention:          cfg.Retention,
+		ArchiveStorage:     cfg.ArchiveStorage,
+		Cipher:             cfg.Cipher,
 	}
 	logWriter = &LogWriter{
-		cfg: cfg,
-	}
-	logWriter.rowWriter, err = NewWriter(ctx, rowCfg)
+		cfg:           cfg,
+		pendingTs:     map[int64]uint64{},
+		maxBatchBytes: cfg.MaxBatchSize * megabyte,
+		commitCh:      make(chan struct{}, 1),
+	}
+	// the file writers are flushed by the group commit only
+	logWriter.rowWriter, err = NewWriter(ctx, rowCfg, WithoutBackgroundFlush(),
+		WithGCTsRecorder(logWriter.advanceGCTs))
 	if err != nil {
 		return nil, err
 	}
-	logWriter.ddlWriter, err = NewWriter(ctx, ddlCfg)
+	logWriter.ddlWriter, err = NewWriter(ctx, ddlCfg, WithoutBackgroundFlush(),
+		WithGCTsRecorder(logWriter.advanceGCTs))
 	if err != nil {
 		return nil, err
 	}
@@ -166,8 +232,14 @@ func NewLogWriter(ctx context.Context, cfg *LogWriterConfig) (*LogWriter, error)
 			zap.String("changefeed", cfg.ChangeFeedID),
 			zap.Error(err))
 	}
-	if cfg.S3Storage {
-		logWriter.storage, err = common.InitS3storage(ctx, cfg.S3URI)
+	if cfg.UseExternalStorage {
+		logWriter.storage, err = common.InitExternalStorage(ctx, cfg.URI)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.ArchiveStorage != "" {
+		logWriter.archive, err = common.InitArchiveStorage(ctx, cfg.ArchiveStorage)
 		if err != nil {
 			return nil, err
 		}
@@ -179,7 +251,7 @@ func NewLogWriter(ctx context.Context, cfg *LogWriterConfig) (*LogWriter, error)
 			return nil, err
 		}
 	} else {
-		if cfg.S3Storage {
+		if cfg.UseExternalStorage {
 			// since other process get the remove changefeed job async, may still write some logs after owner delete the log
 			err = logWriter.preCleanUpS3(ctx)
 			if err != nil {
@@ -189,8 +261,13 @@ func NewLogWriter(ctx context.Context, cfg *LogWriterConfig) (*LogWriter, error)
 	}
 
 	logWriter.metricTotalRowsCount = redoTotalRowsCountGauge.WithLabelValues(cfg.ChangeFeedID)
+	logWriter.metricBatchBytes = redoFlushBatchBytesHistogram.WithLabelValues(cfg.ChangeFeedID)
+	logWriter.metricStorageUsage = redoStorageUsageGauge.WithLabelValues(cfg.ChangeFeedID)
+	logWriter.metricGCRemovedFiles = redoGCRemovedFilesCounter.WithLabelValues(cfg.ChangeFeedID)
+	logWriter.metricFlushFailures = redoFlushFailuresCounter.WithLabelValues(cfg.ChangeFeedID)
 	logWriters[cfg.ChangeFeedID] = logWriter
 	go logWriter.runGC(ctx)
+	go logWriter.runGroupCommit(ctx)
 	return logWriter, nil
 }
 
@@ -264,6 +341,8 @@ func (l *LogWriter) initMeta(ctx context.Context) error {
 func (l *LogWriter) runGC(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(defaultGCIntervalInMs) * time.Millisecond)
 	defer ticker.Stop()
+	storageTicker := time.NewTicker(time.Duration(defaultStorageGCIntervalInMs) * time.Millisecond)
+	defer storageTicker.Stop()
 
 	for {
 		if l.isStopped() {
@@ -277,25 +356,126 @@ func (l *LogWriter) runGC(ctx context.Context) {
 				log.Error("runGC close fail", zap.String("changefeed", l.cfg.ChangeFeedID), zap.Error(err))
 			}
 		case <-ticker.C:
-			err := l.gc()
+			err := l.gc(ctx)
 			if err != nil {
 				log.Error("redo log GC fail", zap.String("changefeed", l.cfg.ChangeFeedID), zap.Error(err))
 			}
+		case <-storageTicker.C:
+			err := l.storageGC(ctx)
+			if err != nil {
+				log.Error("redo log storage GC fail", zap.String("changefeed", l.cfg.ChangeFeedID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// runGroupCommit flushes the logs written every flush interval, or once the
+// logs written since the last group commit exceed the max batch size, so the
+// logs of many transactions and tables are flushed and uploaded together.
+func (l *LogWriter) runGroupCommit(ctx context.Context) {
+	flushInterval := l.cfg.FlushIntervalInMs
+	if flushInterval == 0 {
+		flushInterval = defaultFlushIntervalInMs
+	}
+	ticker := time.NewTicker(time.Duration(flushInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if l.isStopped() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-l.commitCh:
 		}
+		err := l.groupCommit()
+		l.flushErr.Store(err)
+		if err != nil {
+			l.metricFlushFailures.Inc()
+			log.Error("redo log group commit fail", zap.String("changefeed", l.cfg.ChangeFeedID), zap.Error(err))
+		}
+	}
+}
+
+// groupCommit flushes the logs written so far, and then moves the resolved ts
+// of the tables recorded before the flush to the meta, so the resolved ts is
+// advanced only if the logs before it are durable.
+func (l *LogWriter) groupCommit() error {
+	l.pendingLock.Lock()
+	pending := l.pendingTs
+	l.pendingTs = map[int64]uint64{}
+	l.pendingLock.Unlock()
+	batchBytes := l.batchBytes.Swap(0)
+	if len(pending) == 0 && batchBytes == 0 {
+		// nothing to commit, save the requests to the storage
+		return nil
+	}
+
+	var err error
+	err = multierr.Append(err, l.ddlWriter.Flush())
+	err = multierr.Append(err, l.rowWriter.Flush())
+	if err != nil {
+		// the resolved ts is retried by the next group commit
+		l.pendingLock.Lock()
+		for tableID, ts := range pending {
+			mergeTs(l.pendingTs, tableID, ts)
+		}
+		l.pendingLock.Unlock()
+		l.batchBytes.Add(batchBytes)
+		return err
+	}
+	if l.metricBatchBytes != nil {
+		l.metricBatchBytes.Observe(float64(batchBytes))
+	}
+
+	l.metaLock.Lock()
+	for tableID, ts := range pending {
+		mergeTs(l.meta.ResolvedTsList, tableID, ts)
+	}
+	l.metaLock.Unlock()
+	return l.flushLogMeta(0, 0)
+}
+
+// addBatchBytes records the size of the logs written, and triggers a group
+// commit if the batch is full.
+func (l *LogWriter) addBatchBytes(n int) {
+	if l.maxBatchBytes <= 0 || l.batchBytes.Add(int64(n)) < l.maxBatchBytes {
+		return
+	}
+	select {
+	case l.commitCh <- struct{}{}:
+	default:
 	}
 }
 
-func (l *LogWriter) gc() error {
+func (l *LogWriter) gc(ctx context.Context) error {
 	l.metaLock.RLock()
 	ts := l.meta.CheckPointTs
 	l.metaLock.RUnlock()
 
 	var err error
-	err = multierr.Append(err, l.rowWriter.GC(ts))
-	err = multierr.Append(err, l.ddlWriter.GC(ts))
+	err = multierr.Append(err, l.rowWriter.GC(ctx, ts))
+	err = multierr.Append(err, l.ddlWriter.GC(ctx, ts))
 	return err
 }
 
+// advanceGCTs records the max commit ts of the log files to be removed by GC
+// in the meta before they're removed, so the logs are never applied from a ts
+// before it, at which they're incomplete.
+func (l *LogWriter) advanceGCTs(gcTs uint64) error {
+	l.metaLock.Lock()
+	if gcTs <= l.meta.GCTs {
+		l.metaLock.Unlock()
+		return nil
+	}
+	l.meta.GCTs = gcTs
+	l.metaLock.Unlock()
+	return l.flushLogMeta(0, 0)
+}
+
 // WriteLog implement WriteLog api
 func (l *LogWriter) WriteLog(ctx context.Context, tableID int64, rows []*model.RedoRowChangedEvent) (uint64, error) {
 	select {
@@ -334,6 +514,7 @@ func (l *LogWriter) WriteLog(ctx context.Context, tableID int64, rows []*model.R
 			l.metricTotalRowsCount.Add(float64(i))
 			return maxCommitTs, err
 		}
+		l.addBatchBytes(len(data))
 
 		maxCommitTs = l.setMaxCommitTs(tableID, r.Row.CommitTs)
 		redoLogPool.Put(rl)
@@ -370,7 +551,56 @@ func (l *LogWriter) SendDDL(ctx context.Context, ddl *model.RedoDDLEvent) error
 
 	l.ddlWriter.AdvanceTs(ddl.DDL.CommitTs)
 	_, err = l.ddlWriter.Write(data)
-	return err
+	if err != nil {
+		return err
+	}
+	l.addBatchBytes(len(data))
+	return nil
+}
+
+// WriteSchemaSnapshot implement WriteSchemaSnapshot api
+func (l *LogWriter) WriteSchemaSnapshot(ctx context.Context, snap *model.RedoSchemaSnapshot) error {
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	default:
+	}
+
+	if l.isStopped() {
+		return cerror.ErrRedoWriterStopped.GenWithStackByArgs()
+	}
+	if snap == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	err = os.MkdirAll(l.cfg.Dir, common.DefaultDirMode)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, errors.Annotate(err, "can't make dir for new redo logfile"))
+	}
+	name := common.SchemaFileName(l.cfg.ChangeFeedID, snap.Ts)
+	path := filepath.Join(l.cfg.Dir, name)
+	err = os.WriteFile(path+common.MetaTmpEXT, data, common.DefaultFileMode)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	err = os.Rename(path+common.MetaTmpEXT, path)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+
+	if l.cfg.UseExternalStorage {
+		err = l.storage.WriteFile(ctx, name, data)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrS3StorageAPI, err)
+		}
+	}
+	log.Info("redo schema snapshot written", zap.String("changefeed", l.cfg.ChangeFeedID),
+		zap.Uint64("ts", snap.Ts), zap.Int("tables", len(snap.Tables)))
+	return nil
 }
 
 // FlushLog implement FlushLog api
@@ -385,9 +615,6 @@ func (l *LogWriter) FlushLog(ctx context.Context, tableID int64, ts uint64) erro
 		return cerror.ErrRedoWriterStopped.GenWithStackByArgs()
 	}
 
-	if err := l.flush(); err != nil {
-		return err
-	}
 	l.setMaxCommitTs(tableID, ts)
 	return nil
 }
@@ -433,15 +660,11 @@ func (l *LogWriter) GetCurrentResolvedTs(ctx context.Context, tableIDs []int64)
 		return nil, nil
 	}
 
+	// the resolved ts in meta is moved forward only after the data received
+	// before it got saved by the group commit
 	l.metaLock.RLock()
 	defer l.metaLock.RUnlock()
 
-	// need to make sure all data received got saved already
-	err := l.rowWriter.Flush()
-	if err != nil {
-		return nil, err
-	}
-
 	ret := map[int64]uint64{}
 	for i := 0; i < len(tableIDs); i++ {
 		id := tableIDs[i]
@@ -453,6 +676,11 @@ func (l *LogWriter) GetCurrentResolvedTs(ctx context.Context, tableIDs []int64)
 	return ret, nil
 }
 
+// GetFlushError implement GetFlushError api
+func (l *LogWriter) GetFlushError() error {
+	return l.flushErr.Load()
+}
+
 // DeleteAllLogs implement DeleteAllLogs api
 func (l *LogWriter) DeleteAllLogs(ctx context.Context) error {
 	err := l.Close()
@@ -460,7 +688,7 @@ func (l *LogWriter) DeleteAllLogs(ctx context.Context) error {
 		return err
 	}
 
-	if !l.cfg.S3Storage {
+	if !l.cfg.UseExternalStorage {
 		err = os.RemoveAll(l.cfg.Dir)
 		if err != nil {
 			return cerror.WrapError(cerror.ErrRedoFileOp, err)
@@ -519,15 +747,25 @@ func (l *LogWriter) deleteFilesInS3(ctx context.Context, files []string) error {
 	return eg.Wait()
 }
 
+// isNotExistInS3 returns true if the error means the file doesn't exist in the
+// external storage, which is s3, gcs or azure blob storage.
 func isNotExistInS3(err error) bool {
-	if err != nil {
-		if aerr, ok := errors.Cause(err).(awserr.Error); ok { // nolint:errorlint
-			switch aerr.Code() {
-			case s3.ErrCodeNoSuchKey:
-				return true
-			}
+	if err == nil {
+		return false
+	}
+	cause := errors.Cause(err)
+	if aerr, ok := cause.(awserr.Error); ok { // nolint:errorlint
+		switch aerr.Code() {
+		case s3.ErrCodeNoSuchKey:
+			return true
 		}
 	}
+	if cause == gcs.ErrObjectNotExist { // nolint:errorlint
+		return true
+	}
+	if serr, ok := cause.(*azblob.StorageError); ok { // nolint:errorlint
+		return serr.ErrorCode == azblob.StorageErrorCodeBlobNotFound
+	}
 	return false
 }
 
@@ -547,6 +785,10 @@ var getAllFilesInS3 = func(ctx context.Context, l *LogWriter) ([]string, error)
 // Close implements RedoLogWriter.Close.
 func (l *LogWriter) Close() error {
 	redoTotalRowsCountGauge.DeleteLabelValues(l.cfg.ChangeFeedID)
+	redoFlushBatchBytesHistogram.DeleteLabelValues(l.cfg.ChangeFeedID)
+	redoStorageUsageGauge.DeleteLabelValues(l.cfg.ChangeFeedID)
+	redoGCRemovedFilesCounter.DeleteLabelValues(l.cfg.ChangeFeedID)
+	redoFlushFailuresCounter.DeleteLabelValues(l.cfg.ChangeFeedID)
 
 	var err error
 	err = multierr.Append(err, l.rowWriter.Close())
@@ -554,30 +796,23 @@ func (l *LogWriter) Close() error {
 	return err
 }
 
+// setMaxCommitTs records the max commitTs or resolved ts of a table, it's moved
+// to the meta by the next group commit.
 func (l *LogWriter) setMaxCommitTs(tableID int64, commitTs uint64) uint64 {
-	l.metaLock.Lock()
-	defer l.metaLock.Unlock()
+	l.pendingLock.Lock()
+	defer l.pendingLock.Unlock()
 
-	if v, ok := l.meta.ResolvedTsList[tableID]; ok {
-		if v < commitTs {
-			l.meta.ResolvedTsList[tableID] = commitTs
-		}
-	} else {
-		l.meta.ResolvedTsList[tableID] = commitTs
+	if l.pendingTs == nil {
+		l.pendingTs = map[int64]uint64{}
 	}
-
-	return l.meta.ResolvedTsList[tableID]
+	return mergeTs(l.pendingTs, tableID, commitTs)
 }
 
-// flush flushes all the buffered data to the disk.
-func (l *LogWriter) flush() error {
-	err1 := l.flushLogMeta(0, 0)
-	err2 := l.ddlWriter.Flush()
-	err3 := l.rowWriter.Flush()
-
-	err := multierr.Append(err1, err2)
-	err = multierr.Append(err, err3)
-	return err
+func mergeTs(tsList map[int64]uint64, tableID int64, ts uint64) uint64 {
+	if v, ok := tsList[tableID]; !ok || v < ts {
+		tsList[tableID] = ts
+	}
+	return tsList[tableID]
 }
 
 func (l *LogWriter) isStopped() bool {
@@ -632,11 +867,11 @@ func (l *LogWriter) flushLogMeta(checkPointTs, resolvedTs uint64) error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	if !l.cfg.S3Storage {
+	if !l.cfg.UseExternalStorage {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultS3Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultStorageTimeout)
 	defer cancel()
 	return l.writeMetaToS3(ctx)
 }
@@ -656,5 +891,7 @@ func (l *LogWriter) filePath() string {
 }
 
 func (cfg LogWriterConfig) String() string {
-	return fmt.Sprintf("%s:%s:%s:%d:%d:%s:%t", cfg.ChangeFeedID, cfg.CaptureID, cfg.Dir, cfg.MaxLogSize, cfg.FlushIntervalInMs, cfg.S3URI.String(), cfg.S3Storage)
+	return fmt.Sprintf("%s:%s:%s:%d:%d:%d:%s:%t:%s:%d:%s", cfg.ChangeFeedID, cfg.CaptureID, cfg.Dir, cfg.MaxLogSize,
+		cfg.FlushIntervalInMs, cfg.MaxBatchSize, cfg.URI.String(), cfg.UseExternalStorage, cfg.Retention,
+		cfg.RetentionSize, cfg.ArchiveStorage)
 }
@@ -15,6 +15,7 @@ package writer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -31,12 +32,12 @@ import (
 	"github.com/pingcap/errors"
 	mockstorage "github.com/pingcap/tidb/br/pkg/mock/storage"
 	"github.com/pingcap/tidb/br/pkg/storage"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
-	"go.uber.org/multierr"
 )
 
 func TestLogWriterWriteLog(t *testing.T) {
@@ -253,7 +254,6 @@ func TestLogWriterFlushLog(t *testing.T) {
 		args      arg
 		wantTs    uint64
 		isRunning bool
-		flushErr  error
 		wantErr   error
 	}{
 		{
@@ -264,18 +264,6 @@ func TestLogWriterFlushLog(t *testing.T) {
 				ts:      1,
 			},
 			isRunning: true,
-			flushErr:  nil,
-		},
-		{
-			name: "flush err",
-			args: arg{
-				ctx:     context.Background(),
-				tableID: 1,
-				ts:      1,
-			},
-			flushErr:  errors.New("err"),
-			wantErr:   multierr.Append(errors.New("err"), errors.New("err")),
-			isRunning: true,
 		},
 		{
 			name: "isStopped",
@@ -284,7 +272,6 @@ func TestLogWriterFlushLog(t *testing.T) {
 				tableID: 1,
 				ts:      1,
 			},
-			flushErr:  cerror.ErrRedoWriterStopped,
 			isRunning: false,
 			wantErr:   cerror.ErrRedoWriterStopped,
 		},
@@ -295,40 +282,19 @@ func TestLogWriterFlushLog(t *testing.T) {
 				tableID: 1,
 				ts:      1,
 			},
-			flushErr:  nil,
 			isRunning: true,
 			wantErr:   context.Canceled,
 		},
 	}
 
-	dir, err := ioutil.TempDir("", "redo-FlushLog")
-	require.Nil(t, err)
-	defer os.RemoveAll(dir)
-
 	for _, tt := range tests {
-		controller := gomock.NewController(t)
-		mockStorage := mockstorage.NewMockExternalStorage(controller)
-		if tt.isRunning && tt.name != "context cancel" {
-			mockStorage.EXPECT().WriteFile(gomock.Any(), "cp_test-cf_meta.meta", gomock.Any()).Return(nil).Times(1)
-		}
 		mockWriter := &mockFileWriter{}
-		mockWriter.On("Flush", mock.Anything).Return(tt.flushErr)
 		mockWriter.On("IsRunning").Return(tt.isRunning)
-		cfg := &LogWriterConfig{
-			Dir:               dir,
-			ChangeFeedID:      "test-cf",
-			CaptureID:         "cp",
-			MaxLogSize:        10,
-			CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			FlushIntervalInMs: 5,
-			S3Storage:         true,
-		}
 		writer := LogWriter{
 			rowWriter: mockWriter,
 			ddlWriter: mockWriter,
 			meta:      &common.LogMeta{ResolvedTsList: map[int64]uint64{}},
-			cfg:       cfg,
-			storage:   mockStorage,
+			cfg:       &LogWriterConfig{ChangeFeedID: "test-cf"},
 		}
 
 		if tt.name == "context cancel" {
@@ -341,11 +307,73 @@ func TestLogWriterFlushLog(t *testing.T) {
 			require.True(t, errors.ErrorEqual(tt.wantErr, err), err.Error()+tt.wantErr.Error())
 		} else {
 			require.Nil(t, err, tt.name)
-			require.Equal(t, tt.args.ts, writer.meta.ResolvedTsList[tt.args.tableID], tt.name)
+			// the resolved ts doesn't take effect until the group commit
+			require.Equal(t, tt.args.ts, writer.pendingTs[tt.args.tableID], tt.name)
+			require.NotContains(t, writer.meta.ResolvedTsList, tt.args.tableID, tt.name)
 		}
 	}
 }
 
+func TestLogWriterGroupCommit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-GroupCommit")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := &LogWriterConfig{
+		Dir:                dir,
+		ChangeFeedID:       "test-cf",
+		CaptureID:          "cp",
+		MaxLogSize:         10,
+		CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		FlushIntervalInMs:  5,
+		UseExternalStorage: true,
+	}
+	newWriter := func(fileWriter fileWriter, extStorage storage.ExternalStorage) *LogWriter {
+		return &LogWriter{
+			rowWriter:     fileWriter,
+			ddlWriter:     fileWriter,
+			meta:          &common.LogMeta{ResolvedTsList: map[int64]uint64{}},
+			cfg:           cfg,
+			storage:       extStorage,
+			maxBatchBytes: 10,
+			commitCh:      make(chan struct{}, 1),
+		}
+	}
+
+	// nothing is flushed if nothing is written.
+	controller := gomock.NewController(t)
+	mockStorage := mockstorage.NewMockExternalStorage(controller)
+	mockWriter := &mockFileWriter{}
+	writer := newWriter(mockWriter, mockStorage)
+	require.Nil(t, writer.groupCommit())
+	mockWriter.AssertNotCalled(t, "Flush")
+
+	// the resolved ts is moved to the meta once the logs are flushed, and
+	// the batch exceeding the max batch size triggers a group commit.
+	mockStorage.EXPECT().WriteFile(gomock.Any(), "cp_test-cf_meta.meta", gomock.Any()).Return(nil).Times(1)
+	mockWriter.On("Flush").Return(nil)
+	writer.setMaxCommitTs(1, 10)
+	writer.addBatchBytes(6)
+	require.Len(t, writer.commitCh, 0)
+	writer.addBatchBytes(6)
+	require.Len(t, writer.commitCh, 1)
+	require.Nil(t, writer.groupCommit())
+	require.Equal(t, uint64(10), writer.meta.ResolvedTsList[1])
+	require.Empty(t, writer.pendingTs)
+	require.Zero(t, writer.batchBytes.Load())
+
+	// the resolved ts is kept pending if the flush fails.
+	mockWriter = &mockFileWriter{}
+	mockWriter.On("Flush").Return(errors.New("flush err"))
+	writer = newWriter(mockWriter, mockStorage)
+	writer.setMaxCommitTs(1, 20)
+	writer.addBatchBytes(6)
+	require.NotNil(t, writer.groupCommit())
+	require.NotContains(t, writer.meta.ResolvedTsList, int64(1))
+	require.Equal(t, uint64(20), writer.pendingTs[1])
+	require.Equal(t, int64(6), writer.batchBytes.Load())
+}
+
 func TestLogWriterEmitCheckpointTs(t *testing.T) {
 	type arg struct {
 		ctx context.Context
@@ -404,13 +432,13 @@ func TestLogWriterEmitCheckpointTs(t *testing.T) {
 		mockWriter := &mockFileWriter{}
 		mockWriter.On("IsRunning").Return(tt.isRunning)
 		cfg := &LogWriterConfig{
-			Dir:               dir,
-			ChangeFeedID:      "test-cf",
-			CaptureID:         "cp",
-			MaxLogSize:        10,
-			CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			FlushIntervalInMs: 5,
-			S3Storage:         true,
+			Dir:                dir,
+			ChangeFeedID:       "test-cf",
+			CaptureID:          "cp",
+			MaxLogSize:         10,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			FlushIntervalInMs:  5,
+			UseExternalStorage: true,
 		}
 		writer := LogWriter{
 			rowWriter: mockWriter,
@@ -435,6 +463,46 @@ func TestLogWriterEmitCheckpointTs(t *testing.T) {
 	}
 }
 
+func TestLogWriterWriteSchemaSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redo-WriteSchemaSnapshot")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	snap := &model.RedoSchemaSnapshot{
+		Ts: 100,
+		Tables: []*model.RedoTableSchema{{
+			Schema:    "test",
+			Table:     "t1",
+			TableInfo: &timodel.TableInfo{ID: 1, Name: timodel.NewCIStr("t1")},
+		}},
+	}
+	name := common.SchemaFileName("test-cf", snap.Ts)
+	controller := gomock.NewController(t)
+	mockStorage := mockstorage.NewMockExternalStorage(controller)
+	mockStorage.EXPECT().WriteFile(gomock.Any(), name, gomock.Any()).Return(nil).Times(1)
+	mockWriter := &mockFileWriter{}
+	mockWriter.On("IsRunning").Return(true)
+	writer := LogWriter{
+		rowWriter: mockWriter,
+		ddlWriter: mockWriter,
+		cfg: &LogWriterConfig{
+			Dir:                dir,
+			ChangeFeedID:       "test-cf",
+			UseExternalStorage: true,
+		},
+		storage: mockStorage,
+	}
+	require.Nil(t, writer.WriteSchemaSnapshot(context.Background(), snap))
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.Nil(t, err)
+	got := &model.RedoSchemaSnapshot{}
+	require.Nil(t, json.Unmarshal(data, got))
+	require.Equal(t, snap.Ts, got.Ts)
+	require.Len(t, got.Tables, 1)
+	require.Equal(t, "t1", got.Tables[0].TableInfo.Name.O)
+}
+
 func TestLogWriterEmitResolvedTs(t *testing.T) {
 	type arg struct {
 		ctx context.Context
@@ -493,13 +561,13 @@ func TestLogWriterEmitResolvedTs(t *testing.T) {
 		mockWriter := &mockFileWriter{}
 		mockWriter.On("IsRunning").Return(tt.isRunning)
 		cfg := &LogWriterConfig{
-			Dir:               dir,
-			ChangeFeedID:      "test-cf",
-			CaptureID:         "cp",
-			MaxLogSize:        10,
-			CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			FlushIntervalInMs: 5,
-			S3Storage:         true,
+			Dir:                dir,
+			ChangeFeedID:       "test-cf",
+			CaptureID:          "cp",
+			MaxLogSize:         10,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			FlushIntervalInMs:  5,
+			UseExternalStorage: true,
 		}
 		writer := LogWriter{
 			rowWriter: mockWriter,
@@ -591,6 +659,7 @@ func TestLogWriterGetCurrentResolvedTs(t *testing.T) {
 		for k, v := range tt.args.ts {
 			_ = writer.FlushLog(tt.args.ctx, k, v)
 		}
+		require.Nil(t, writer.groupCommit())
 		ret, err := writer.GetCurrentResolvedTs(tt.args.ctx, tt.args.tableIDs)
 		if tt.wantErr != nil {
 			require.True(t, errors.ErrorEqual(tt.wantErr, err), tt.name, err.Error())
@@ -679,25 +748,25 @@ func TestNewLogWriter(t *testing.T) {
 	require.Equal(t, map[int64]uint64{}, l.meta.ResolvedTsList)
 	time.Sleep(time.Millisecond * time.Duration(math.Max(float64(defaultFlushIntervalInMs), float64(defaultGCIntervalInMs))+1))
 
-	origin := common.InitS3storage
+	origin := common.InitExternalStorage
 	defer func() {
-		common.InitS3storage = origin
+		common.InitExternalStorage = origin
 	}()
 	controller := gomock.NewController(t)
 	mockStorage := mockstorage.NewMockExternalStorage(controller)
 	// skip pre cleanup
 	mockStorage.EXPECT().FileExists(gomock.Any(), gomock.Any()).Return(false, nil)
-	common.InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+	common.InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
 		return mockStorage, nil
 	}
 	cfg3 := &LogWriterConfig{
-		Dir:               dir,
-		ChangeFeedID:      "test-cf112232",
-		CaptureID:         "cp",
-		MaxLogSize:        10,
-		CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-		FlushIntervalInMs: 5,
-		S3Storage:         true,
+		Dir:                dir,
+		ChangeFeedID:       "test-cf112232",
+		CaptureID:          "cp",
+		MaxLogSize:         10,
+		CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		FlushIntervalInMs:  5,
+		UseExternalStorage: true,
 	}
 	l3, err := NewLogWriter(ctx, cfg3)
 	require.Nil(t, err)
@@ -742,7 +811,7 @@ func TestWriterRedoGC(t *testing.T) {
 		mockWriter.On("IsRunning").Return(false)
 
 		if tt.args.isRunning {
-			mockWriter.On("GC", mock.Anything).Return(nil)
+			mockWriter.On("GC", mock.Anything, mock.Anything).Return(nil)
 		}
 		writer := LogWriter{
 			rowWriter: mockWriter,
@@ -757,9 +826,9 @@ func TestWriterRedoGC(t *testing.T) {
 		mockWriter.AssertNumberOfCalls(t, "Close", 2)
 
 		if tt.args.isRunning {
-			mockWriter.AssertCalled(t, "GC", mock.Anything)
+			mockWriter.AssertCalled(t, "GC", mock.Anything, mock.Anything)
 		} else {
-			mockWriter.AssertNotCalled(t, "GC", mock.Anything)
+			mockWriter.AssertNotCalled(t, "GC", mock.Anything, mock.Anything)
 		}
 	}
 }
@@ -843,13 +912,13 @@ func TestDeleteAllLogs(t *testing.T) {
 		mockWriter := &mockFileWriter{}
 		mockWriter.On("Close").Return(tt.closeErr)
 		cfg := &LogWriterConfig{
-			Dir:               dir,
-			ChangeFeedID:      "test-cf",
-			CaptureID:         "cp",
-			MaxLogSize:        10,
-			CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			FlushIntervalInMs: 5,
-			S3Storage:         tt.args.enableS3,
+			Dir:                dir,
+			ChangeFeedID:       "test-cf",
+			CaptureID:          "cp",
+			MaxLogSize:         10,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			FlushIntervalInMs:  5,
+			UseExternalStorage: tt.args.enableS3,
 		}
 		writer := LogWriter{
 			rowWriter: mockWriter,
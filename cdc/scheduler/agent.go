@@ -51,6 +51,14 @@ type TableExecutor interface {
 	IsAddTableFinished(ctx context.Context, tableID model.TableID) (done bool)
 	IsRemoveTableFinished(ctx context.Context, tableID model.TableID) (done bool)
 
+	// PrepareTable starts the pipeline of a table in advance without
+	// replicating it, so that a later AddTable of the table finishes quickly.
+	// A prepared table is not returned by GetAllCurrentTables.
+	PrepareTable(ctx context.Context, tableID model.TableID) (done bool, err error)
+	// IsPrepareTableFinished returns whether the pipeline of a prepared table
+	// has caught up.
+	IsPrepareTableFinished(ctx context.Context, tableID model.TableID) (done bool)
+
 	// GetAllCurrentTables should return all tables that are being run,
 	// being added and being removed.
 	//
@@ -72,6 +80,8 @@ type TableExecutor interface {
 type ProcessorMessenger interface {
 	// FinishTableOperation notifies the owner that a table operation has finished.
 	FinishTableOperation(ctx context.Context, tableID model.TableID, epoch model.ProcessorEpoch) (done bool, err error)
+	// FinishPrepareTable notifies the owner that a table has been prepared.
+	FinishPrepareTable(ctx context.Context, tableID model.TableID, epoch model.ProcessorEpoch) (done bool, err error)
 	// SyncTaskStatuses informs the owner of the processor's current internal state.
 	SyncTaskStatuses(ctx context.Context, epoch model.ProcessorEpoch, adding, removing, running []model.TableID) (done bool, err error)
 	// SendCheckpoint sends the owner the processor's local watermarks, i.e., checkpoint-ts and resolved-ts.
@@ -167,9 +177,10 @@ const (
 )
 
 type agentOperation struct {
-	TableID  model.TableID
-	IsDelete bool
-	Epoch    model.ProcessorEpoch
+	TableID   model.TableID
+	IsDelete  bool
+	IsPrepare bool
+	Epoch     model.ProcessorEpoch
 
 	// FromOwnerID is for debugging purposesFromOwnerID
 	FromOwnerID model.CaptureID
@@ -224,9 +235,16 @@ func (a *BaseAgent) Tick(ctx context.Context) error {
 				zap.String("expectedEpoch", a.getEpoch()))
 			continue
 		}
-		if _, ok := a.tableOperations[op.TableID]; ok {
-			a.logger.DPanic("duplicate operation", zap.Any("op", op))
-			return cerrors.ErrProcessorDuplicateOperations.GenWithStackByArgs(op.TableID)
+		if existing, ok := a.tableOperations[op.TableID]; ok {
+			if !existing.IsPrepare || op.IsPrepare {
+				a.logger.DPanic("duplicate operation", zap.Any("op", op))
+				return cerrors.ErrProcessorDuplicateOperations.GenWithStackByArgs(op.TableID)
+			}
+			// Preparing a table is only an optimization, it's superseded by
+			// the other operations of the table, such as an add operation
+			// from a new owner which doesn't know the table is being prepared.
+			a.logger.Info("prepare operation superseded",
+				zap.Any("prepareOp", existing), zap.Any("op", op))
 		}
 		a.tableOperations[op.TableID] = op
 	}
@@ -260,9 +278,12 @@ func (a *BaseAgent) popPendingOps() (opsToApply []*agentOperation) {
 func (a *BaseAgent) sendSync(ctx context.Context) (bool, error) {
 	var adding, removing, running []model.TableID
 	for _, op := range a.tableOperations {
-		if !op.IsDelete {
+		switch {
+		case op.IsPrepare:
+			// A table being prepared is not replicated by the processor.
+		case !op.IsDelete:
 			adding = append(adding, op.TableID)
-		} else {
+		default:
 			removing = append(removing, op.TableID)
 		}
 	}
@@ -293,32 +314,37 @@ func (a *BaseAgent) processOperations(ctx context.Context) error {
 		switch op.status {
 		case operationReceived:
 			a.logger.Info("Agent start processing operation", zap.Any("op", op))
-			if !op.IsDelete {
+			var (
+				done bool
+				err  error
+			)
+			switch {
+			case op.IsPrepare:
+				// prepare table
+				done, err = a.executor.PrepareTable(ctx, op.TableID)
+			case !op.IsDelete:
 				// add table
-				done, err := a.executor.AddTable(ctx, op.TableID)
-				if err != nil {
-					return errors.Trace(err)
-				}
-				if !done {
-					break
-				}
-			} else {
+				done, err = a.executor.AddTable(ctx, op.TableID)
+			default:
 				// delete table
-				done, err := a.executor.RemoveTable(ctx, op.TableID)
-				if err != nil {
-					return errors.Trace(err)
-				}
-				if !done {
-					break
-				}
+				done, err = a.executor.RemoveTable(ctx, op.TableID)
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !done {
+				break
 			}
 			op.status = operationProcessed
 			fallthrough
 		case operationProcessed:
 			var done bool
-			if !op.IsDelete {
+			switch {
+			case op.IsPrepare:
+				done = a.executor.IsPrepareTableFinished(ctx, op.TableID)
+			case !op.IsDelete:
 				done = a.executor.IsAddTableFinished(ctx, op.TableID)
-			} else {
+			default:
 				done = a.executor.IsRemoveTableFinished(ctx, op.TableID)
 			}
 			if !done {
@@ -328,7 +354,15 @@ func (a *BaseAgent) processOperations(ctx context.Context) error {
 			fallthrough
 		case operationFinished:
 			a.logger.Info("Agent finish processing operation", zap.Any("op", op))
-			done, err := a.communicator.FinishTableOperation(ctx, op.TableID, a.getEpoch())
+			var (
+				done bool
+				err  error
+			)
+			if op.IsPrepare {
+				done, err = a.communicator.FinishPrepareTable(ctx, op.TableID, a.getEpoch())
+			} else {
+				done, err = a.communicator.FinishTableOperation(ctx, op.TableID, a.getEpoch())
+			}
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -392,6 +426,39 @@ func (a *BaseAgent) OnOwnerDispatchedTask(
 		zap.Any("op", op))
 }
 
+// OnOwnerPreparedTask should be called when the Owner asks the processor to
+// prepare a table before moving the table to it.
+// The Processor is responsible for calling this function when appropriate.
+func (a *BaseAgent) OnOwnerPreparedTask(
+	ownerCaptureID model.CaptureID,
+	ownerRev int64,
+	tableID model.TableID,
+	epoch model.ProcessorEpoch,
+) {
+	if !a.updateOwnerInfo(ownerCaptureID, ownerRev) {
+		a.logger.Info("prepare task from stale owner ignored",
+			zap.Int64("tableID", tableID))
+		return
+	}
+
+	a.pendingOpsMu.Lock()
+	defer a.pendingOpsMu.Unlock()
+
+	op := &agentOperation{
+		TableID:     tableID,
+		IsPrepare:   true,
+		Epoch:       epoch,
+		FromOwnerID: ownerCaptureID,
+		status:      operationReceived,
+	}
+	a.pendingOps.PushBack(op)
+
+	a.logger.Info("OnOwnerPreparedTask",
+		zap.String("ownerCaptureID", ownerCaptureID),
+		zap.Int64("ownerRev", ownerRev),
+		zap.Any("op", op))
+}
+
 // OnOwnerAnnounce should be called when a new Owner announces its ownership.
 // The Processor is responsible for calling this function when appropriate.
 //
@@ -35,6 +35,12 @@ func (m *MockProcessorMessenger) FinishTableOperation(ctx cdcContext.Context, ta
 	return args.Bool(0), args.Error(1)
 }
 
+// FinishPrepareTable marks this function as being called.
+func (m *MockProcessorMessenger) FinishPrepareTable(ctx cdcContext.Context, tableID model.TableID, epoch model.ProcessorEpoch) (bool, error) {
+	args := m.Called(ctx, tableID, epoch)
+	return args.Bool(0), args.Error(1)
+}
+
 // SyncTaskStatuses marks this function as being called.
 func (m *MockProcessorMessenger) SyncTaskStatuses(ctx cdcContext.Context, epoch model.ProcessorEpoch, adding, removing, running []model.TableID) (bool, error) {
 	args := m.Called(ctx, epoch, running, adding, removing)
@@ -96,6 +102,8 @@ type MockTableExecutor struct {
 	t *testing.T
 
 	Adding, Running, Removing map[model.TableID]struct{}
+	// Prepared are the tables prepared but not added yet.
+	Prepared map[model.TableID]struct{}
 }
 
 // NewMockTableExecutor creates a new mock table executor.
@@ -105,6 +113,7 @@ func NewMockTableExecutor(t *testing.T) *MockTableExecutor {
 		Adding:   map[model.TableID]struct{}{},
 		Running:  map[model.TableID]struct{}{},
 		Removing: map[model.TableID]struct{}{},
+		Prepared: map[model.TableID]struct{}{},
 	}
 }
 
@@ -118,6 +127,20 @@ func (e *MockTableExecutor) AddTable(ctx cdcContext.Context, tableID model.Table
 	if args.Bool(0) {
 		// If the mock return value indicates a success, then we record the added table.
 		e.Adding[tableID] = struct{}{}
+		delete(e.Prepared, tableID)
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+// PrepareTable prepares a table in the executor.
+func (e *MockTableExecutor) PrepareTable(ctx cdcContext.Context, tableID model.TableID) (bool, error) {
+	log.Info("PrepareTable", zap.Int64("tableID", tableID))
+	require.NotContains(e.t, e.Adding, tableID)
+	require.NotContains(e.t, e.Running, tableID)
+	require.NotContains(e.t, e.Removing, tableID)
+	args := e.Called(ctx, tableID)
+	if args.Bool(0) {
+		e.Prepared[tableID] = struct{}{}
 	}
 	return args.Bool(0), args.Error(1)
 }
@@ -139,6 +162,12 @@ func (e *MockTableExecutor) IsAddTableFinished(ctx cdcContext.Context, tableID m
 	return ok
 }
 
+// IsPrepareTableFinished determines if the table has been prepared.
+func (e *MockTableExecutor) IsPrepareTableFinished(ctx cdcContext.Context, tableID model.TableID) bool {
+	args := e.Called(ctx, tableID)
+	return args.Bool(0)
+}
+
 // IsRemoveTableFinished determines if the table has been removed.
 func (e *MockTableExecutor) IsRemoveTableFinished(ctx cdcContext.Context, tableID model.TableID) bool {
 	_, ok := e.Removing[tableID]
@@ -75,6 +75,65 @@ func TestAgentAddTable(t *testing.T) {
 	messenger.AssertExpectations(t)
 }
 
+func TestAgentPrepareTable(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(false)
+
+	executor := NewMockTableExecutor(t)
+	messenger := &MockProcessorMessenger{}
+	agent := NewBaseAgent("test-cf", executor, messenger, agentConfigForTesting)
+	var epoch model.ProcessorEpoch
+	messenger.On("SyncTaskStatuses", mock.Anything, mock.AnythingOfType("string"), []model.TableID(nil), []model.TableID(nil), []model.TableID(nil)).
+		Return(true, nil).
+		Run(func(args mock.Arguments) {
+			epoch = args.String(1)
+		})
+	err := agent.Tick(ctx)
+	require.NoError(t, err)
+	messenger.AssertExpectations(t)
+
+	// The prepared tables are not reported to the owner, and no checkpoint
+	// is sent for them.
+	executor.ExpectedCalls = nil
+	messenger.ExpectedCalls = nil
+	agent.OnOwnerPreparedTask("capture-1", 1, model.TableID(1), epoch)
+	agent.OnOwnerPreparedTask("capture-1", 1, model.TableID(2), epoch)
+	executor.On("PrepareTable", mock.Anything, model.TableID(1)).Return(true, nil)
+	executor.On("PrepareTable", mock.Anything, model.TableID(2)).Return(true, nil)
+	executor.On("IsPrepareTableFinished", mock.Anything, mock.Anything).Return(false)
+	messenger.On("OnOwnerChanged", mock.Anything, "capture-1", int64(1))
+	err = agent.Tick(ctx)
+	require.NoError(t, err)
+	messenger.AssertExpectations(t)
+	executor.AssertExpectations(t)
+	require.Contains(t, executor.Prepared, model.TableID(1))
+	require.Contains(t, executor.Prepared, model.TableID(2))
+
+	executor.ExpectedCalls = nil
+	messenger.ExpectedCalls = nil
+	executor.On("IsPrepareTableFinished", mock.Anything, model.TableID(1)).Return(true)
+	executor.On("IsPrepareTableFinished", mock.Anything, model.TableID(2)).Return(false)
+	messenger.On("FinishPrepareTable", mock.Anything, model.TableID(1), epoch).Return(true, nil)
+	err = agent.Tick(ctx)
+	require.NoError(t, err)
+	messenger.AssertExpectations(t)
+
+	// Adding a prepared table, or a table still being prepared, is allowed.
+	executor.ExpectedCalls = nil
+	messenger.ExpectedCalls = nil
+	agent.OnOwnerDispatchedTask("capture-1", 1, model.TableID(1), false, epoch)
+	agent.OnOwnerDispatchedTask("capture-1", 1, model.TableID(2), false, epoch)
+	executor.On("AddTable", mock.Anything, model.TableID(1)).Return(true, nil)
+	executor.On("AddTable", mock.Anything, model.TableID(2)).Return(true, nil)
+	executor.On("GetCheckpoint").Return(model.Ts(1000), model.Ts(1000))
+	messenger.On("SendCheckpoint", mock.Anything, model.Ts(1000), model.Ts(1000)).Return(true, nil)
+	err = agent.Tick(ctx)
+	require.NoError(t, err)
+	messenger.AssertExpectations(t)
+	executor.AssertExpectations(t)
+	require.Empty(t, executor.Prepared)
+	require.Len(t, executor.Adding, 2)
+}
+
 func TestAgentRemoveTable(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(false)
 
@@ -14,6 +14,7 @@
 package scheduler
 
 import (
+	"fmt"
 	"math"
 	"sync"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/scheduler/util"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/context"
 	"go.uber.org/zap"
 )
@@ -49,6 +51,11 @@ type ScheduleDispatcher interface {
 	// Rebalance triggers a rebalance operation.
 	// It should be thread-safe
 	Rebalance()
+
+	// TableDiagnoses returns the diagnoses of the tables which are not running
+	// or keep bouncing between captures.
+	// It should be thread-safe.
+	TableDiagnoses() []*model.TableDiagnosis
 }
 
 // ScheduleDispatcherCommunicator is an interface for the BaseScheduleDispatcher to
@@ -65,6 +72,15 @@ type ScheduleDispatcherCommunicator interface {
 		epoch model.ProcessorEpoch,
 	) (done bool, err error)
 
+	// PrepareTable should ask the Processor to start the pipeline of a table in
+	// advance, before the table is moved to the Processor.
+	PrepareTable(ctx context.Context,
+		changeFeedID model.ChangeFeedID,
+		tableID model.TableID,
+		captureID model.CaptureID,
+		epoch model.ProcessorEpoch,
+	) (done bool, err error)
+
 	// Announce announces to the specified capture that the current node has become the Owner.
 	Announce(ctx context.Context,
 		changeFeedID model.ChangeFeedID,
@@ -92,6 +108,19 @@ type BaseScheduleDispatcher struct {
 	lastTickCaptureCount int
 	needRebalance        bool
 
+	// twoPhaseMove indicates that a table is moved only after its pipeline
+	// has been prepared on the target capture, and handoffs are the tables
+	// being moved in this way.
+	twoPhaseMove bool
+	handoffs     map[model.TableID]*tableHandoff
+
+	// currentTables are the tables that should be replicated in the last tick.
+	currentTables []model.TableID
+	// history records the scheduling operations of tables, and pendingReasons
+	// record why the tables can't be dispatched, they're used to diagnose tables.
+	history        *TableHistory
+	pendingReasons map[model.TableID]string
+
 	// read only fields
 	changeFeedID model.ChangeFeedID
 	communicator ScheduleDispatcherCommunicator
@@ -117,9 +146,23 @@ func NewBaseScheduleDispatcher(
 		communicator:         communicator,
 		checkpointTs:         checkpointTs,
 		lastTickCaptureCount: captureCountUninitialized,
+		history:              NewTableHistory(),
+		pendingReasons:       map[model.TableID]string{},
+		twoPhaseMove:         config.GetGlobalServerConfig().Debug.EnableTwoPhaseTableMove,
+		handoffs:             map[model.TableID]*tableHandoff{},
 	}
 }
 
+// tableHandoff is a table being moved to the target capture. The table is
+// removed from its current capture only after the target capture has
+// prepared it, i.e. the puller and the sorter of the table have caught up on
+// the target, so the resolved ts doesn't fall behind while the table is moved.
+type tableHandoff struct {
+	target   model.CaptureID
+	reason   string
+	prepared bool
+}
+
 type captureStatus struct {
 	// SyncStatus indicates what we know about the capture's internal state.
 	// We need to know this before we can make decision whether to
@@ -187,6 +230,7 @@ func (s *BaseScheduleDispatcher) Tick(
 	}
 	// Updates the internally maintained last checkpoint-ts.
 	s.checkpointTs = checkpointTs
+	s.currentTables = currentTables
 
 	// Makes sure that captures have all been synchronized before proceeding.
 	done, err := s.syncCaptures(ctx)
@@ -234,13 +278,17 @@ func (s *BaseScheduleDispatcher) Tick(
 			continue
 		}
 
-		ok, err := s.removeTable(ctx, tableID)
+		ok, err := s.removeTable(ctx, tableID, "table is not replicated anymore")
 		if err != nil {
 			return CheckpointCannotProceed, CheckpointCannotProceed, errors.Trace(err)
 		}
 		if !ok {
 			return CheckpointCannotProceed, CheckpointCannotProceed, nil
 		}
+		// the table is not replicated anymore, no need to diagnose it.
+		s.history.Drop(tableID)
+		delete(s.pendingReasons, tableID)
+		delete(s.handoffs, tableID)
 	}
 
 	checkAllTasksNormal := func() bool {
@@ -252,8 +300,21 @@ func (s *BaseScheduleDispatcher) Tick(
 		return CheckpointCannotProceed, CheckpointCannotProceed, nil
 	}
 
+	// handleHandoffs removes the tables that have been prepared on the
+	// captures they're moved to.
+	ok, err := s.handleHandoffs(ctx)
+	if err != nil {
+		return CheckpointCannotProceed, CheckpointCannotProceed, errors.Trace(err)
+	}
+	if !ok {
+		return CheckpointCannotProceed, CheckpointCannotProceed, nil
+	}
+	if !checkAllTasksNormal() {
+		return CheckpointCannotProceed, CheckpointCannotProceed, nil
+	}
+
 	// handleMoveTableJobs tries to execute user-specified manual move table jobs.
-	ok, err := s.handleMoveTableJobs(ctx)
+	ok, err = s.handleMoveTableJobs(ctx)
 	if err != nil {
 		return CheckpointCannotProceed, CheckpointCannotProceed, errors.Trace(err)
 	}
@@ -364,9 +425,20 @@ func (s *BaseScheduleDispatcher) descheduleTablesFromDownCaptures() {
 			s.logger.Info("capture down, removing tables",
 				zap.String("captureID", captureID),
 				zap.Any("removedTables", removed))
+			for _, record := range removed {
+				s.history.Record(record.TableID, TableOperationRemove, captureID, "capture is down")
+			}
 			s.moveTableManager.OnCaptureRemoved(captureID)
 		}
 	}
+	for tableID, handoff := range s.handoffs {
+		if _, ok := s.captures[handoff.target]; !ok {
+			s.logger.Info("capture down, stop moving table to it",
+				zap.String("captureID", handoff.target),
+				zap.Int64("tableID", tableID))
+			delete(s.handoffs, tableID)
+		}
+	}
 }
 
 func (s *BaseScheduleDispatcher) findDiffTables(
@@ -397,10 +469,16 @@ func (s *BaseScheduleDispatcher) addTable(
 	// A user triggered move-table will have had the target recorded.
 	target, ok := s.moveTableManager.GetTargetByTableID(tableID)
 	isManualMove := ok
+	handoff, isHandoff := s.handoffs[tableID]
+	if !ok && isHandoff {
+		// The table has been prepared on the target.
+		target, ok = handoff.target, true
+	}
 	if !ok {
 		target, ok = s.balancer.FindTarget(s.tables, s.captures)
 		if !ok {
 			s.logger.Warn("no active capture")
+			s.pendingReasons[tableID] = "no active capture to dispatch the table to"
 			return true, nil
 		}
 	}
@@ -413,11 +491,21 @@ func (s *BaseScheduleDispatcher) addTable(
 	}
 
 	if !ok {
+		s.pendingReasons[tableID] = fmt.Sprintf(
+			"the dispatch message to capture %s can't be sent, the peer may be unreachable", target)
 		return false, nil
 	}
+	delete(s.pendingReasons, tableID)
+	reason := "dispatched to the capture with the least tables"
+	if isHandoff {
+		delete(s.handoffs, tableID)
+		reason = handoff.reason
+	}
 	if isManualMove {
 		s.moveTableManager.MarkDone(tableID)
+		reason = "moved by user"
 	}
+	s.history.Record(tableID, TableOperationAdd, target, reason)
 
 	if ok := s.tables.AddTableRecord(&util.TableRecord{
 		TableID:   tableID,
@@ -432,6 +520,7 @@ func (s *BaseScheduleDispatcher) addTable(
 func (s *BaseScheduleDispatcher) removeTable(
 	ctx context.Context,
 	tableID model.TableID,
+	reason string,
 ) (done bool, err error) {
 	record, ok := s.tables.GetTableRecord(tableID)
 	if !ok {
@@ -450,6 +539,7 @@ func (s *BaseScheduleDispatcher) removeTable(
 
 	record.Status = util.RemovingTable
 	s.tables.UpdateTableRecord(record)
+	s.history.Record(tableID, TableOperationRemove, captureID, reason)
 	return true, nil
 }
 
@@ -466,7 +556,7 @@ func (s *BaseScheduleDispatcher) MoveTable(tableID model.TableID, target model.C
 func (s *BaseScheduleDispatcher) handleMoveTableJobs(ctx context.Context) (bool, error) {
 	removeAllDone, err := s.moveTableManager.DoRemove(ctx,
 		func(ctx context.Context, tableID model.TableID, target model.CaptureID) (removeTableResult, error) {
-			_, ok := s.tables.GetTableRecord(tableID)
+			record, ok := s.tables.GetTableRecord(tableID)
 			if !ok {
 				s.logger.Warn("table does not exist", zap.Int64("tableID", tableID))
 				return removeTableResultGiveUp, nil
@@ -479,7 +569,20 @@ func (s *BaseScheduleDispatcher) handleMoveTableJobs(ctx context.Context) (bool,
 				return removeTableResultGiveUp, nil
 			}
 
-			ok, err := s.removeTable(ctx, tableID)
+			if s.twoPhaseMove && record.CaptureID != target {
+				// The table is removed by handleHandoffs after it's prepared
+				// on the target.
+				ok, err := s.startHandoff(ctx, record, target, "moved by user")
+				if err != nil {
+					return removeTableResultUnavailable, errors.Trace(err)
+				}
+				if !ok {
+					return removeTableResultUnavailable, nil
+				}
+				return removeTableResultOK, nil
+			}
+
+			ok, err := s.removeTable(ctx, tableID, "moved by user")
 			if err != nil {
 				return removeTableResultUnavailable, errors.Trace(err)
 			}
@@ -508,6 +611,25 @@ func (s *BaseScheduleDispatcher) rebalance(ctx context.Context) (done bool, err
 				zap.Any("tableRecord", record))
 		}
 
+		if s.twoPhaseMove {
+			if _, ok := s.handoffs[record.TableID]; ok {
+				// The table is being moved.
+				continue
+			}
+			target, ok := s.findHandoffTarget(record.CaptureID)
+			if !ok {
+				continue
+			}
+			ok, err := s.startHandoff(ctx, record, target, "rebalance")
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			if !ok {
+				return false, nil
+			}
+			continue
+		}
+
 		epoch := s.captureStatus[record.CaptureID].Epoch
 		// Removes the table from the current capture
 		ok, err := s.communicator.DispatchTable(
@@ -521,10 +643,135 @@ func (s *BaseScheduleDispatcher) rebalance(ctx context.Context) (done bool, err
 
 		record.Status = util.RemovingTable
 		s.tables.UpdateTableRecord(record)
+		s.history.Record(record.TableID, TableOperationRemove, record.CaptureID, "rebalance")
+	}
+	return true, nil
+}
+
+// findHandoffTarget returns the capture with the least tables other than
+// source to move a table to, the tables being moved are counted on their
+// targets.
+func (s *BaseScheduleDispatcher) findHandoffTarget(source model.CaptureID) (model.CaptureID, bool) {
+	workloads := make(map[model.CaptureID]int, len(s.captures))
+	for captureID := range s.captures {
+		workloads[captureID] = s.tables.CountTableByCaptureID(captureID)
+	}
+	for tableID, handoff := range s.handoffs {
+		if _, ok := workloads[handoff.target]; ok {
+			workloads[handoff.target]++
+		}
+		if record, ok := s.tables.GetTableRecord(tableID); ok {
+			if _, ok := workloads[record.CaptureID]; ok {
+				workloads[record.CaptureID]--
+			}
+		}
+	}
+
+	target := ""
+	minWorkload := math.MaxInt64
+	for captureID, workload := range workloads {
+		if captureID == source {
+			continue
+		}
+		// Ties are broken by the capture ID to make the result deterministic.
+		if workload < minWorkload || (workload == minWorkload && captureID < target) {
+			target, minWorkload = captureID, workload
+		}
 	}
+	return target, target != ""
+}
+
+// startHandoff asks the target capture to prepare a table running on another
+// capture, the table is removed from its current capture by handleHandoffs
+// after it's prepared.
+func (s *BaseScheduleDispatcher) startHandoff(
+	ctx context.Context,
+	record *util.TableRecord,
+	target model.CaptureID,
+	reason string,
+) (done bool, err error) {
+	epoch := s.captureStatus[target].Epoch
+	ok, err := s.communicator.PrepareTable(ctx, s.changeFeedID, record.TableID, target, epoch)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if !ok {
+		return false, nil
+	}
+	s.handoffs[record.TableID] = &tableHandoff{target: target, reason: reason}
+	s.logger.Info("start moving table",
+		zap.Int64("tableID", record.TableID),
+		zap.String("source", record.CaptureID),
+		zap.String("target", target),
+		zap.String("reason", reason))
 	return true, nil
 }
 
+// handleHandoffs removes the tables prepared on their targets from their
+// current captures, and the tables are added to the targets once they're
+// removed.
+func (s *BaseScheduleDispatcher) handleHandoffs(ctx context.Context) (done bool, err error) {
+	for tableID, handoff := range s.handoffs {
+		if !handoff.prepared {
+			continue
+		}
+		record, ok := s.tables.GetTableRecord(tableID)
+		if !ok || record.Status != util.RunningTable {
+			// The table is removed already.
+			continue
+		}
+		if record.CaptureID == handoff.target {
+			delete(s.handoffs, tableID)
+			continue
+		}
+		ok, err := s.removeTable(ctx, tableID, handoff.reason)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// OnAgentPreparedTable is called when a table has been prepared by the processor.
+func (s *BaseScheduleDispatcher) OnAgentPreparedTable(
+	captureID model.CaptureID,
+	tableID model.TableID,
+	epoch model.ProcessorEpoch,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logger := s.logger.With(
+		zap.String("captureID", captureID),
+		zap.Int64("tableID", tableID),
+		zap.String("epoch", epoch),
+	)
+
+	captureSt, ok := s.captureStatus[captureID]
+	if !ok {
+		logger.Warn("Message from an unknown processor, ignore")
+		return
+	}
+	if captureSt.Epoch != epoch {
+		logger.Warn("Processor epoch does not match",
+			zap.String("expected", captureSt.Epoch))
+		return
+	}
+
+	handoff, ok := s.handoffs[tableID]
+	if !ok || handoff.target != captureID {
+		// The table is not being moved to the capture anymore, the prepared
+		// pipeline is dropped by the processor later.
+		logger.Info("response about a stale prepared table, ignore")
+		return
+	}
+	logger.Info("owner received table prepared")
+	handoff.prepared = true
+}
+
 // OnAgentFinishedTableOperation is called when a table operation has been finished by
 // the processor.
 func (s *BaseScheduleDispatcher) OnAgentFinishedTableOperation(
@@ -608,7 +855,7 @@ func (s *BaseScheduleDispatcher) OnAgentSyncTaskStatuses(
 	// Clear all tables previously run by the sender capture,
 	// because `Sync` tells the Owner to reset its state regarding
 	// the sender capture.
-	s.tables.RemoveTableRecordByCaptureID(captureID)
+	removed := s.tables.RemoveTableRecordByCaptureID(captureID)
 
 	if _, ok := s.captureStatus[captureID]; !ok {
 		logger.Warn("received sync from a capture not previously tracked, ignore",
@@ -641,11 +888,86 @@ func (s *BaseScheduleDispatcher) OnAgentSyncTaskStatuses(
 		s.tables.AddTableRecord(&util.TableRecord{TableID: tableID, CaptureID: captureID, Status: util.RemovingTable})
 	}
 
+	// The tables lost by the processor are rescheduled, it's usually caused
+	// by the restart of the processor.
+	for _, record := range removed {
+		if _, ok := s.tables.GetTableRecord(record.TableID); !ok {
+			s.history.Record(record.TableID, TableOperationRemove, captureID,
+				fmt.Sprintf("processor reset its tables, new epoch %s", epoch))
+		}
+	}
+
+	// The tables being prepared by the processor are lost, they're moved
+	// without waiting for the processor to prepare them.
+	for _, handoff := range s.handoffs {
+		if handoff.target == captureID {
+			handoff.prepared = true
+		}
+	}
+
 	status := s.captureStatus[captureID]
 	status.SyncStatus = captureSyncFinished
 	status.Epoch = epoch
 }
 
+// TableDiagnoses returns the diagnoses of the tables which are not running
+// or keep bouncing between captures.
+func (s *BaseScheduleDispatcher) TableDiagnoses() []*model.TableDiagnosis {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var unsynced []model.CaptureID
+	for captureID, status := range s.captureStatus {
+		if status.SyncStatus != captureSyncFinished {
+			unsynced = append(unsynced, captureID)
+		}
+	}
+
+	var diagnoses []*model.TableDiagnosis
+	for _, tableID := range s.currentTables {
+		history, bouncing := s.history.Diagnose(tableID)
+		diagnosis := &model.TableDiagnosis{
+			TableID:  tableID,
+			State:    model.TableScheduleRunning,
+			Bouncing: bouncing,
+			History:  history,
+		}
+		record, ok := s.tables.GetTableRecord(tableID)
+		switch {
+		case !ok:
+			diagnosis.State = model.TableScheduleAbsent
+			if len(unsynced) > 0 {
+				diagnosis.Reasons = append(diagnosis.Reasons, fmt.Sprintf(
+					"waiting for captures %v to report their tables", unsynced))
+			} else if reason, ok := s.pendingReasons[tableID]; ok {
+				diagnosis.Reasons = append(diagnosis.Reasons, reason)
+			} else {
+				diagnosis.Reasons = append(diagnosis.Reasons, "waiting to be dispatched")
+			}
+		case record.Status == util.AddingTable:
+			diagnosis.State = model.TableScheduleAdding
+			diagnosis.Reasons = append(diagnosis.Reasons, fmt.Sprintf(
+				"waiting for the processor on capture %s to add the table", record.CaptureID))
+		case record.Status == util.RemovingTable:
+			diagnosis.State = model.TableScheduleRemoving
+			diagnosis.Reasons = append(diagnosis.Reasons, fmt.Sprintf(
+				"waiting for the processor on capture %s to remove the table", record.CaptureID))
+		}
+		if ok {
+			diagnosis.CaptureID = record.CaptureID
+		}
+		if diagnosis.State == model.TableScheduleRunning && !bouncing {
+			continue
+		}
+		if bouncing {
+			diagnosis.Reasons = append(diagnosis.Reasons,
+				"the table is removed repeatedly recently, see the history for the reasons")
+		}
+		diagnoses = append(diagnoses, diagnosis)
+	}
+	return diagnoses
+}
+
 // OnAgentCheckpoint is called when the processor sends a checkpoint.
 func (s *BaseScheduleDispatcher) OnAgentCheckpoint(captureID model.CaptureID, checkpointTs model.Ts, resolvedTs model.Ts) {
 	s.mu.Lock()
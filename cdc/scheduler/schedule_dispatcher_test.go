@@ -35,22 +35,25 @@ const (
 
 type mockScheduleDispatcherCommunicator struct {
 	mock.Mock
-	addTableRecords    map[model.CaptureID][]model.TableID
-	removeTableRecords map[model.CaptureID][]model.TableID
+	addTableRecords     map[model.CaptureID][]model.TableID
+	removeTableRecords  map[model.CaptureID][]model.TableID
+	prepareTableRecords map[model.CaptureID][]model.TableID
 
 	isBenchmark bool
 }
 
 func NewMockScheduleDispatcherCommunicator() *mockScheduleDispatcherCommunicator {
 	return &mockScheduleDispatcherCommunicator{
-		addTableRecords:    map[model.CaptureID][]model.TableID{},
-		removeTableRecords: map[model.CaptureID][]model.TableID{},
+		addTableRecords:     map[model.CaptureID][]model.TableID{},
+		removeTableRecords:  map[model.CaptureID][]model.TableID{},
+		prepareTableRecords: map[model.CaptureID][]model.TableID{},
 	}
 }
 
 func (m *mockScheduleDispatcherCommunicator) Reset() {
 	m.addTableRecords = map[model.CaptureID][]model.TableID{}
 	m.removeTableRecords = map[model.CaptureID][]model.TableID{}
+	m.prepareTableRecords = map[model.CaptureID][]model.TableID{}
 	m.Mock.ExpectedCalls = nil
 	m.Mock.Calls = nil
 }
@@ -80,6 +83,18 @@ func (m *mockScheduleDispatcherCommunicator) DispatchTable(
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockScheduleDispatcherCommunicator) PrepareTable(
+	ctx cdcContext.Context,
+	changeFeedID model.ChangeFeedID,
+	tableID model.TableID,
+	captureID model.CaptureID,
+	epoch model.ProcessorEpoch,
+) (done bool, err error) {
+	m.prepareTableRecords[captureID] = append(m.prepareTableRecords[captureID], tableID)
+	args := m.Called(ctx, changeFeedID, tableID, captureID, epoch)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *mockScheduleDispatcherCommunicator) Announce(
 	ctx cdcContext.Context,
 	changeFeedID model.ChangeFeedID,
@@ -551,6 +566,185 @@ func TestRebalance(t *testing.T) {
 	communicator.AssertExpectations(t)
 }
 
+func TestTwoPhaseMoveTable(t *testing.T) {
+	t.Parallel()
+
+	ctx := cdcContext.NewBackendContext4Test(false)
+	communicator := NewMockScheduleDispatcherCommunicator()
+	dispatcher := NewBaseScheduleDispatcher("cf-1", communicator, 1000)
+	dispatcher.twoPhaseMove = true
+	dispatcher.captureStatus = map[model.CaptureID]*captureStatus{
+		"capture-1": {
+			SyncStatus:   captureSyncFinished,
+			CheckpointTs: 1300,
+			ResolvedTs:   1600,
+			Epoch:        defaultEpoch,
+		},
+		"capture-2": {
+			SyncStatus:   captureSyncFinished,
+			CheckpointTs: 1500,
+			ResolvedTs:   1550,
+			Epoch:        defaultEpoch,
+		},
+	}
+	dispatcher.tables.AddTableRecord(&util.TableRecord{
+		TableID:   1,
+		CaptureID: "capture-1",
+		Status:    util.RunningTable,
+	})
+	dispatcher.tables.AddTableRecord(&util.TableRecord{
+		TableID:   2,
+		CaptureID: "capture-2",
+		Status:    util.RunningTable,
+	})
+
+	// The table is prepared on the target first, and the checkpoint keeps
+	// advancing meanwhile.
+	dispatcher.MoveTable(1, "capture-2")
+	communicator.On("PrepareTable", mock.Anything, "cf-1", model.TableID(1), "capture-2", defaultEpoch).
+		Return(true, nil)
+	checkpointTs, resolvedTs, err := dispatcher.Tick(ctx, 1300, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(1300), checkpointTs)
+	require.Equal(t, model.Ts(1550), resolvedTs)
+	communicator.AssertExpectations(t)
+
+	communicator.Reset()
+	checkpointTs, _, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(1300), checkpointTs)
+	communicator.AssertNotCalled(t, "DispatchTable")
+
+	// A response from another capture is ignored.
+	dispatcher.OnAgentPreparedTable("capture-1", 1, defaultEpoch)
+	checkpointTs, _, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(1300), checkpointTs)
+	communicator.AssertNotCalled(t, "DispatchTable")
+
+	// The table is removed from the source after it's prepared.
+	dispatcher.OnAgentPreparedTable("capture-2", 1, defaultEpoch)
+	communicator.On("DispatchTable", mock.Anything, "cf-1", model.TableID(1), "capture-1", true, defaultEpoch).
+		Return(true, nil)
+	checkpointTs, resolvedTs, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, CheckpointCannotProceed, checkpointTs)
+	require.Equal(t, CheckpointCannotProceed, resolvedTs)
+	communicator.AssertExpectations(t)
+
+	// And then added to the target.
+	dispatcher.OnAgentFinishedTableOperation("capture-1", 1, defaultEpoch)
+	communicator.Reset()
+	communicator.On("DispatchTable", mock.Anything, "cf-1", model.TableID(1), "capture-2", false, defaultEpoch).
+		Return(true, nil)
+	checkpointTs, _, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, CheckpointCannotProceed, checkpointTs)
+	communicator.AssertExpectations(t)
+	require.Empty(t, dispatcher.handoffs)
+
+	dispatcher.OnAgentFinishedTableOperation("capture-2", 1, defaultEpoch)
+	communicator.Reset()
+	checkpointTs, _, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(1500), checkpointTs)
+	record, ok := dispatcher.tables.GetTableRecord(1)
+	require.True(t, ok)
+	require.Equal(t, "capture-2", record.CaptureID)
+	require.Equal(t, util.RunningTable, record.Status)
+}
+
+func TestTwoPhaseRebalance(t *testing.T) {
+	t.Parallel()
+
+	mockCaptureInfos := map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {
+			ID:            "capture-1",
+			AdvertiseAddr: "fakeip:1",
+		},
+		"capture-2": {
+			ID:            "capture-2",
+			AdvertiseAddr: "fakeip:2",
+		},
+		"capture-3": {
+			ID:            "capture-3",
+			AdvertiseAddr: "fakeip:3",
+		},
+	}
+
+	ctx := cdcContext.NewBackendContext4Test(false)
+	communicator := NewMockScheduleDispatcherCommunicator()
+	dispatcher := NewBaseScheduleDispatcher("cf-1", communicator, 1000)
+	dispatcher.twoPhaseMove = true
+	dispatcher.captureStatus = map[model.CaptureID]*captureStatus{
+		"capture-1": {
+			SyncStatus:   captureSyncFinished,
+			CheckpointTs: 1300,
+			ResolvedTs:   1600,
+			Epoch:        defaultEpoch,
+		},
+		"capture-2": {
+			SyncStatus:   captureSyncFinished,
+			CheckpointTs: 1500,
+			ResolvedTs:   1550,
+			Epoch:        defaultEpoch,
+		},
+		"capture-3": {
+			SyncStatus:   captureSyncFinished,
+			CheckpointTs: 1400,
+			ResolvedTs:   1650,
+			Epoch:        defaultEpoch,
+		},
+	}
+	for i := 1; i <= 6; i++ {
+		dispatcher.tables.AddTableRecord(&util.TableRecord{
+			TableID:   model.TableID(i),
+			CaptureID: fmt.Sprintf("capture-%d", (i+1)%2+1),
+			Status:    util.RunningTable,
+		})
+	}
+
+	// Both victims are prepared on the empty capture.
+	dispatcher.Rebalance()
+	communicator.On("PrepareTable", mock.Anything, "cf-1", mock.Anything, "capture-3", defaultEpoch).
+		Return(true, nil)
+	checkpointTs, _, err := dispatcher.Tick(ctx, 1300, []model.TableID{1, 2, 3, 4, 5, 6}, mockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(1300), checkpointTs)
+	communicator.AssertNumberOfCalls(t, "PrepareTable", 2)
+	require.Len(t, dispatcher.handoffs, 2)
+	victims := communicator.prepareTableRecords["capture-3"]
+	require.Len(t, victims, 2)
+
+	// The processor on the target restarts, the tables are moved without
+	// waiting for them to be prepared.
+	dispatcher.OnAgentSyncTaskStatuses("capture-3", nextEpoch, nil, nil, nil)
+	communicator.Reset()
+	communicator.On("DispatchTable", mock.Anything, "cf-1", mock.Anything, mock.Anything, true, defaultEpoch).
+		Return(true, nil)
+	checkpointTs, _, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2, 3, 4, 5, 6}, mockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, CheckpointCannotProceed, checkpointTs)
+	communicator.AssertNumberOfCalls(t, "DispatchTable", 2)
+	for _, tableID := range victims {
+		record, ok := dispatcher.tables.GetTableRecord(tableID)
+		require.True(t, ok)
+		require.Equal(t, util.RemovingTable, record.Status)
+		dispatcher.OnAgentFinishedTableOperation(record.CaptureID, tableID, defaultEpoch)
+	}
+
+	// The victims are added to the target.
+	communicator.Reset()
+	communicator.On("DispatchTable", mock.Anything, "cf-1", mock.Anything, "capture-3", false, nextEpoch).
+		Return(true, nil)
+	checkpointTs, _, err = dispatcher.Tick(ctx, 1300, []model.TableID{1, 2, 3, 4, 5, 6}, mockCaptureInfos)
+	require.NoError(t, err)
+	require.Equal(t, CheckpointCannotProceed, checkpointTs)
+	communicator.AssertNumberOfCalls(t, "DispatchTable", 2)
+	communicator.AssertExpectations(t)
+	require.Empty(t, dispatcher.handoffs)
+}
+
 func TestIgnoreEmptyCapture(t *testing.T) {
 	t.Parallel()
 
@@ -1035,3 +1229,75 @@ func BenchmarkAddTable(b *testing.B) {
 		}
 	}
 }
+
+func TestTableDiagnoses(t *testing.T) {
+	t.Parallel()
+
+	ctx := cdcContext.NewBackendContext4Test(false)
+	communicator := NewMockScheduleDispatcherCommunicator()
+	dispatcher := NewBaseScheduleDispatcher("cf-1", communicator, 1000)
+
+	communicator.On("Announce", mock.Anything, "cf-1", "capture-1").Return(true, nil)
+	communicator.On("Announce", mock.Anything, "cf-1", "capture-2").Return(true, nil)
+	_, _, err := dispatcher.Tick(ctx, 1000, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	diagnoses := dispatcher.TableDiagnoses()
+	require.Len(t, diagnoses, 2)
+	for _, diagnosis := range diagnoses {
+		require.Equal(t, model.TableScheduleAbsent, diagnosis.State)
+		require.Regexp(t, "waiting for captures .* to report their tables", diagnosis.Reasons[0])
+	}
+
+	dispatcher.OnAgentSyncTaskStatuses("capture-1", defaultEpoch, []model.TableID{}, []model.TableID{}, []model.TableID{})
+	dispatcher.OnAgentSyncTaskStatuses("capture-2", defaultEpoch, []model.TableID{}, []model.TableID{}, []model.TableID{})
+
+	communicator.Reset()
+	communicator.On("DispatchTable", mock.Anything, "cf-1", model.TableID(1), mock.Anything, false, defaultEpoch).
+		Return(false, nil)
+	_, _, err = dispatcher.Tick(ctx, 1000, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	diagnoses = dispatcher.TableDiagnoses()
+	require.Len(t, diagnoses, 2)
+	for _, diagnosis := range diagnoses {
+		require.Equal(t, model.TableScheduleAbsent, diagnosis.State)
+		if diagnosis.TableID == 1 {
+			require.Regexp(t, "dispatch message to capture .* can't be sent", diagnosis.Reasons[0])
+		}
+	}
+
+	communicator.Reset()
+	communicator.On("DispatchTable", mock.Anything, "cf-1", mock.Anything, mock.Anything, false, defaultEpoch).
+		Return(true, nil)
+	_, _, err = dispatcher.Tick(ctx, 1000, []model.TableID{1, 2}, defaultMockCaptureInfos)
+	require.NoError(t, err)
+	diagnoses = dispatcher.TableDiagnoses()
+	require.Len(t, diagnoses, 2)
+	for _, diagnosis := range diagnoses {
+		require.Equal(t, model.TableScheduleAdding, diagnosis.State)
+		require.Regexp(t, "waiting for the processor on capture .* to add the table", diagnosis.Reasons[0])
+		require.Len(t, diagnosis.History, 1)
+		require.Equal(t, TableOperationAdd, diagnosis.History[0].Operation)
+		require.Equal(t, diagnosis.CaptureID, diagnosis.History[0].CaptureID)
+	}
+
+	for _, diagnosis := range diagnoses {
+		dispatcher.OnAgentFinishedTableOperation(diagnosis.CaptureID, diagnosis.TableID, defaultEpoch)
+	}
+	require.Empty(t, dispatcher.TableDiagnoses())
+
+	// the tables are bouncing if they are reset by the processor repeatedly.
+	communicator.On("DispatchTable", mock.Anything, "cf-1", mock.Anything, mock.Anything, false, nextEpoch).
+		Return(true, nil)
+	for i := 0; i < tableBounceThreshold; i++ {
+		dispatcher.OnAgentSyncTaskStatuses("capture-1", nextEpoch, []model.TableID{}, []model.TableID{}, []model.TableID{})
+		dispatcher.OnAgentSyncTaskStatuses("capture-2", nextEpoch, []model.TableID{}, []model.TableID{}, []model.TableID{})
+		_, _, err = dispatcher.Tick(ctx, 1000, []model.TableID{1, 2}, defaultMockCaptureInfos)
+		require.NoError(t, err)
+	}
+	diagnoses = dispatcher.TableDiagnoses()
+	require.Len(t, diagnoses, 2)
+	for _, diagnosis := range diagnoses {
+		require.True(t, diagnosis.Bouncing)
+		require.Regexp(t, "processor reset its tables", diagnosis.History[1].Reason)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+const (
+	// maxTableHistorySize is the max number of operations recorded for a table.
+	maxTableHistorySize = 16
+	// tableBounceWindow and tableBounceThreshold decide whether a table is
+	// bouncing, that is, removed at least tableBounceThreshold times in the
+	// last tableBounceWindow.
+	tableBounceWindow    = 10 * time.Minute
+	tableBounceThreshold = 3
+)
+
+// Table scheduling operations.
+const (
+	TableOperationAdd    = "add"
+	TableOperationRemove = "remove"
+)
+
+type tableOperationRecord struct {
+	time      time.Time
+	operation string
+	captureID model.CaptureID
+	reason    string
+}
+
+// TableHistory records the recent scheduling operations of tables, they are used
+// to diagnose the tables which can't be scheduled or keep bouncing between captures.
+// It's thread-safe.
+type TableHistory struct {
+	mu      sync.Mutex
+	records map[model.TableID][]*tableOperationRecord
+
+	now func() time.Time
+}
+
+// NewTableHistory creates a new TableHistory.
+func NewTableHistory() *TableHistory {
+	return &TableHistory{
+		records: make(map[model.TableID][]*tableOperationRecord),
+		now:     time.Now,
+	}
+}
+
+// Record records a scheduling operation of the table.
+func (h *TableHistory) Record(
+	tableID model.TableID, operation string, captureID model.CaptureID, reason string,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := append(h.records[tableID], &tableOperationRecord{
+		time:      h.now(),
+		operation: operation,
+		captureID: captureID,
+		reason:    reason,
+	})
+	if len(records) > maxTableHistorySize {
+		records = records[len(records)-maxTableHistorySize:]
+	}
+	h.records[tableID] = records
+}
+
+// Drop drops the records of the table, it's called when the table is not
+// replicated anymore.
+func (h *TableHistory) Drop(tableID model.TableID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.records, tableID)
+}
+
+// Diagnose returns the recent operations of the table, and whether it's bouncing.
+func (h *TableHistory) Diagnose(tableID model.TableID) (history []*model.TableScheduleRecord, bouncing bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := h.records[tableID]
+	removed := 0
+	since := h.now().Add(-tableBounceWindow)
+	for _, r := range records {
+		history = append(history, &model.TableScheduleRecord{
+			Time:      model.JSONTime(r.time),
+			Operation: r.operation,
+			CaptureID: r.captureID,
+			Reason:    r.reason,
+		})
+		if r.operation == TableOperationRemove && r.time.After(since) {
+			removed++
+		}
+	}
+	return history, removed >= tableBounceThreshold
+}
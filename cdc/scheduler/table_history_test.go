@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableHistory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	h := NewTableHistory()
+	h.now = func() time.Time { return now }
+
+	history, bouncing := h.Diagnose(1)
+	require.Empty(t, history)
+	require.False(t, bouncing)
+
+	// the removes out of the bounce window are ignored.
+	h.Record(1, TableOperationAdd, "capture-1", "dispatched")
+	h.Record(1, TableOperationRemove, "capture-1", "rebalance")
+	now = now.Add(tableBounceWindow + time.Second)
+	for i := 0; i < tableBounceThreshold-1; i++ {
+		h.Record(1, TableOperationAdd, "capture-2", "dispatched")
+		h.Record(1, TableOperationRemove, "capture-2", "capture is down")
+	}
+	history, bouncing = h.Diagnose(1)
+	require.Len(t, history, 2*tableBounceThreshold)
+	require.False(t, bouncing)
+	require.Equal(t, &model.TableScheduleRecord{
+		Time:      history[0].Time,
+		Operation: TableOperationAdd,
+		CaptureID: "capture-1",
+		Reason:    "dispatched",
+	}, history[0])
+
+	h.Record(1, TableOperationRemove, "capture-2", "capture is down")
+	_, bouncing = h.Diagnose(1)
+	require.True(t, bouncing)
+
+	// the history is truncated.
+	for i := 0; i < maxTableHistorySize; i++ {
+		h.Record(1, TableOperationAdd, "capture-1", "dispatched")
+	}
+	history, _ = h.Diagnose(1)
+	require.Len(t, history, maxTableHistorySize)
+
+	h.Drop(1)
+	history, _ = h.Diagnose(1)
+	require.Empty(t, history)
+}
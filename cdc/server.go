@@ -42,6 +42,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/fsutil"
@@ -113,7 +114,7 @@ func NewServer(pdEndpoints []string) (*Server, error) {
 func (s *Server) Run(ctx context.Context) error {
 	conf := config.GetGlobalServerConfig()
 
-	grpcTLSOption, err := conf.Security.ToGRPCDialOption()
+	grpcTLSOption, err := conf.Security.ToGRPCDialOptionWithPinning()
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -138,7 +139,7 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 	s.pdClient = pdClient
 
-	tlsConfig, err := conf.Security.ToTLSConfig()
+	tlsConfig, err := conf.Security.ToTLSConfigWithPinning()
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -178,6 +179,11 @@ func (s *Server) Run(ctx context.Context) error {
 		return errors.Trace(err)
 	}
 
+	err = encryption.InitGlobalCipher(ctx, conf.DataEncryption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// To not block CDC server startup, we need to warn instead of error
 	// when TiKV is incompatible.
 	errorTiKVIncompatible := false
@@ -17,7 +17,6 @@ import (
 	"context"
 	"sync/atomic"
 
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"go.uber.org/zap"
 )
@@ -46,7 +45,7 @@ func (b *blackHoleSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*mo
 
 func (b *blackHoleSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
 	for _, row := range rows {
-		log.Debug("BlockHoleSink: EmitRowChangedEvents", zap.Any("row", row))
+		logger().Debug("BlockHoleSink: EmitRowChangedEvents", zap.Any("row", row))
 	}
 	rowsCount := len(rows)
 	atomic.AddUint64(&b.accumulated, uint64(rowsCount))
@@ -55,7 +54,7 @@ func (b *blackHoleSink) EmitRowChangedEvents(ctx context.Context, rows ...*model
 }
 
 func (b *blackHoleSink) FlushRowChangedEvents(ctx context.Context, _ model.TableID, resolvedTs uint64) (uint64, error) {
-	log.Debug("BlockHoleSink: FlushRowChangedEvents", zap.Uint64("resolvedTs", resolvedTs))
+	logger().Debug("BlockHoleSink: FlushRowChangedEvents", zap.Uint64("resolvedTs", resolvedTs))
 	err := b.statistics.RecordBatchExecution(func() (int, error) {
 		// TODO: add some random replication latency
 		accumulated := atomic.LoadUint64(&b.accumulated)
@@ -68,12 +67,12 @@ func (b *blackHoleSink) FlushRowChangedEvents(ctx context.Context, _ model.Table
 }
 
 func (b *blackHoleSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
-	log.Debug("BlockHoleSink: Checkpoint Event", zap.Uint64("ts", ts), zap.Any("tables", tables))
+	logger().Debug("BlockHoleSink: Checkpoint Event", zap.Uint64("ts", ts), zap.Any("tables", tables))
 	return nil
 }
 
 func (b *blackHoleSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
-	log.Debug("BlockHoleSink: DDL Event", zap.Any("ddl", ddl))
+	logger().Debug("BlockHoleSink: DDL Event", zap.Any("ddl", ddl))
 	return nil
 }
 
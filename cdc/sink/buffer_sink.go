@@ -21,7 +21,6 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -148,7 +147,7 @@ func (b *bufferSink) runOnce(ctx context.Context, state *runState) (bool, error)
 	}
 	elapsed := time.Since(start)
 	if elapsed > time.Second {
-		log.Warn("flush row changed events too slow",
+		logger().Warn("flush row changed events too slow",
 			zap.Int("batchSize", batchSize),
 			zap.Duration("duration", elapsed),
 			util.ZapFieldChangefeed(ctx))
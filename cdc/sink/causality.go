@@ -16,7 +16,6 @@ package sink
 import (
 	"encoding/binary"
 
-	"github.com/pingcap/log"
 	"go.uber.org/zap"
 
 	"github.com/pingcap/tiflow/cdc/model"
@@ -24,7 +23,8 @@ import (
 
 // causality provides a simple mechanism to improve the concurrency of SQLs execution under the premise of ensuring correctness.
 // causality groups sqls that maybe contain causal relationships, and syncer executes them linearly.
-// if some conflicts exist in more than one groups, then syncer waits all SQLs that are grouped be executed and reset causality.
+// if some conflicts exist in more than one groups, then syncer waits the SQLs of these groups be executed and forgets
+// their relations, other groups are not affected and keep batching SQLs into larger transactions.
 // this mechanism meets quiescent consistency to ensure correctness.
 type causality struct {
 	relations map[string]int
@@ -50,6 +50,41 @@ func (c *causality) reset() {
 	c.relations = make(map[string]int)
 }
 
+// removeWorkers forgets the keys added to the given workers, it must be called
+// after all the txns of these workers are executed.
+func (c *causality) removeWorkers(idxs []int) {
+	for key, idx := range c.relations {
+		for _, i := range idxs {
+			if idx == i {
+				delete(c.relations, key)
+				break
+			}
+		}
+	}
+}
+
+// conflictWorkers returns all the distinct workers which the keys conflict with.
+func (c *causality) conflictWorkers(keys [][]byte) []int {
+	var idxs []int
+	for _, key := range keys {
+		idx, ok := c.relations[string(key)]
+		if !ok {
+			continue
+		}
+		found := false
+		for _, i := range idxs {
+			if i == idx {
+				found = true
+				break
+			}
+		}
+		if !found {
+			idxs = append(idxs, idx)
+		}
+	}
+	return idxs
+}
+
 // detectConflict detects whether there is a conflict
 func (c *causality) detectConflict(keys [][]byte) (bool, int) {
 	if len(keys) == 0 {
@@ -111,7 +146,7 @@ func genRowKeys(row *model.RowChangedEvent) [][]byte {
 	if len(keys) == 0 {
 		// use table ID as key if no key generated (no PK/UK),
 		// no concurrence for rows in the same table.
-		log.Debug("use table id as the key", zap.Int64("tableID", row.Table.TableID))
+		logger().Debug("use table id as the key", zap.Int64("tableID", row.Table.TableID))
 		tableKey := make([]byte, 8)
 		binary.BigEndian.PutUint64(tableKey, uint64(row.Table.TableID))
 		keys = [][]byte{tableKey}
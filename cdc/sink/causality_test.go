@@ -72,6 +72,28 @@ func (s *testCausalitySuite) TestCausality(c *check.C) {
 	c.Assert(len(ca.relations), check.Equals, 0)
 }
 
+func (s *testCausalitySuite) TestCausalityRemoveWorkers(c *check.C) {
+	defer testleak.AfterTest(c)()
+	ca := newCausality()
+	ca.add([][]byte{[]byte("a"), []byte("aa")}, 0)
+	ca.add([][]byte{[]byte("b")}, 1)
+	ca.add([][]byte{[]byte("c")}, 2)
+
+	keys := [][]byte{[]byte("a"), []byte("aa"), []byte("b"), []byte("d")}
+	conflict, idx := ca.detectConflict(keys)
+	c.Assert(conflict, check.IsTrue)
+	c.Assert(idx, check.Equals, -1)
+	idxs := ca.conflictWorkers(keys)
+	c.Assert(idxs, check.DeepEquals, []int{0, 1})
+
+	// the keys of other workers are kept.
+	ca.removeWorkers(idxs)
+	c.Assert(ca.relations, check.DeepEquals, map[string]int{"c": 2})
+	conflict, _ = ca.detectConflict(keys)
+	c.Assert(conflict, check.IsFalse)
+	c.Assert(ca.conflictWorkers(keys), check.HasLen, 0)
+}
+
 func (s *testCausalitySuite) TestGenKeys(c *check.C) {
 	defer testleak.AfterTest(c)()
 	testCases := []struct {
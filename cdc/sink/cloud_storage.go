@@ -0,0 +1,698 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	storageProtocolCSV     = "csv"
+	storageProtocolJSON    = "json"
+	storageProtocolParquet = "parquet"
+
+	storagePartitionNone = "none"
+	storagePartitionDay  = "day"
+	storagePartitionHour = "hour"
+
+	// storageMetadataFile records the checkpoint ts of the whole changefeed.
+	storageMetadataFile = "metadata"
+	// storageManifestDir holds the manifests of a table, each of them indexes
+	// a range of the finalized data files of the table.
+	storageManifestDir = "manifest"
+	// storageManifestMaxFiles is the max number of files listed in a manifest,
+	// a new manifest is rolled once it's exceeded, so a flush only rewrites a
+	// bounded manifest.
+	storageManifestMaxFiles = 1024
+	// storageNullValue is how NULL is written in csv files, it is the same
+	// as the default of `LOAD DATA`.
+	storageNullValue = `\N`
+)
+
+// storageSinkParams are the parameters of a cloud storage sink, they are
+// read from the query of the sink uri, e.g.
+// s3://bucket/prefix?protocol=json&partition=hour
+type storageSinkParams struct {
+	protocol  string
+	partition string
+}
+
+// parseStorageSinkParams extracts the storage sink parameters from sinkURI,
+// it returns the parameters and the uri left for the external storage.
+func parseStorageSinkParams(sinkURI *url.URL) (*storageSinkParams, *url.URL, error) {
+	params := &storageSinkParams{
+		protocol:  storageProtocolCSV,
+		partition: storagePartitionDay,
+	}
+	query := sinkURI.Query()
+	if s := query.Get("protocol"); s != "" {
+		params.protocol = strings.ToLower(s)
+	}
+	switch params.protocol {
+	case storageProtocolCSV, storageProtocolJSON, storageProtocolParquet:
+	default:
+		return nil, nil, cerror.ErrCloudStorageInvalidConfig.GenWithStack(
+			"protocol %s is not supported, valid values are %s, %s and %s",
+			params.protocol, storageProtocolCSV, storageProtocolJSON, storageProtocolParquet)
+	}
+	if s := query.Get("partition"); s != "" {
+		params.partition = strings.ToLower(s)
+	}
+	switch params.partition {
+	case storagePartitionNone, storagePartitionDay, storagePartitionHour:
+	default:
+		return nil, nil, cerror.ErrCloudStorageInvalidConfig.GenWithStack(
+			"partition %s is not supported, valid values are %s, %s and %s",
+			params.partition, storagePartitionNone, storagePartitionDay, storagePartitionHour)
+	}
+
+	query.Del("protocol")
+	query.Del("partition")
+	storageURI := *sinkURI
+	storageURI.RawQuery = query.Encode()
+	return params, &storageURI, nil
+}
+
+// storageFileMeta describes a finalized data file in a table manifest.
+type storageFileMeta struct {
+	Path        string `json:"path"`
+	MinCommitTs uint64 `json:"min-commit-ts"`
+	MaxCommitTs uint64 `json:"max-commit-ts"`
+	Rows        int    `json:"rows"`
+}
+
+// storageManifest is the content of a table manifest file. All files with
+// commit ts not greater than ResolvedTs have been listed in this manifest or
+// the manifests before it.
+type storageManifest struct {
+	ResolvedTs uint64             `json:"resolved-ts"`
+	Files      []*storageFileMeta `json:"files"`
+}
+
+// storageSchema is the content of a table schema file.
+type storageSchema struct {
+	Schema   string               `json:"schema"`
+	Table    string               `json:"table"`
+	Version  uint64               `json:"version"`
+	Query    string               `json:"query,omitempty"`
+	Columns  []*storageSchemaItem `json:"columns"`
+	CommitTs uint64               `json:"commit-ts,omitempty"`
+}
+
+type storageSchemaItem struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// storageTable holds the pending rows of a table.
+type storageTable struct {
+	mu          sync.Mutex
+	dir         string
+	rows        []*model.RowChangedEvent
+	manifest    *storageManifest
+	manifestSeq int
+	versions    map[uint64]struct{}
+	checkpoint  uint64
+}
+
+// storageSink writes row changes to an external storage (s3, gcs or a local
+// directory) as csv, json or parquet files. Rows are buffered in memory and
+// written out only when their commit ts is flushed.
+//
+// Files of a table are committed by the table manifests:
+//  1. data files of a flush are written first, named by the resolved ts of
+//     the flush, they are not visible to consumers reading the manifests yet;
+//  2. the latest manifest, listing the new files and the resolved ts, is
+//     written with a single atomic put, which commits the files.
+//
+// Manifests are named by their sequence numbers in the manifest directory of
+// the table, the latest one is rewritten by each flush until it lists
+// storageManifestMaxFiles files, and then the next one is started.
+//
+// When a table is (re)started, data files newer than the latest manifest are
+// left by a crash between the two steps and are deleted, and replayed rows
+// which have been committed are skipped, so consumers reading through
+// manifests see every row exactly once.
+type storageSink struct {
+	id               model.ChangeFeedID
+	params           *storageSinkParams
+	storage          storage.ExternalStorage
+	filter           *filter.Filter
+	manifestMaxFiles int
+	// tz is the timezone of the changefeed, timestamp values are in it.
+	tz *time.Location
+	// mu only protects tables, the rows of a table are protected by its own
+	// lock, so the tables are flushed concurrently.
+	mu         sync.Mutex
+	tables     map[model.TableID]*storageTable
+	statistics *Statistics
+}
+
+func newStorageSink(
+	ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+	filter *filter.Filter,
+) (*storageSink, error) {
+	params, storageURI, err := parseStorageSinkParams(sinkURI)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := storage.ParseBackend(storageURI.String(), &storage.BackendOptions{})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrCloudStorageInvalidConfig, err)
+	}
+	extStorage, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrCloudStorageInvalidConfig, err)
+	}
+	tz := util.TimezoneFromCtx(ctx)
+	if tz == nil {
+		tz = time.UTC
+	}
+	return &storageSink{
+		id:               changefeedID,
+		params:           params,
+		storage:          extStorage,
+		filter:           filter,
+		manifestMaxFiles: storageManifestMaxFiles,
+		tz:               tz,
+		tables:           make(map[model.TableID]*storageTable),
+		statistics:       NewStatistics(ctx, sinkTypeStorage),
+	}, nil
+}
+
+func (s *storageSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	if err := s.EmitRowChangedEvents(ctx, rows...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *storageSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	var tables []*storageTable
+	tableRows := make(map[*storageTable][]*model.RowChangedEvent)
+	s.mu.Lock()
+	rowsCount := 0
+	for _, row := range rows {
+		if s.filter != nil && s.filter.ShouldIgnoreDMLEvent(row.StartTs, row.Table.Schema, row.Table.Table) {
+			logger().Info("Row changed event ignored",
+				zap.Uint64("start-ts", row.StartTs),
+				zap.String("changefeed", s.id))
+			continue
+		}
+		table, ok := s.tables[row.Table.TableID]
+		if !ok {
+			table = &storageTable{
+				dir:      storageTableDir(row.Table),
+				versions: make(map[uint64]struct{}),
+			}
+			s.tables[row.Table.TableID] = table
+		}
+		if _, ok := tableRows[table]; !ok {
+			tables = append(tables, table)
+		}
+		tableRows[table] = append(tableRows[table], row)
+		rowsCount++
+	}
+	s.mu.Unlock()
+
+	for _, table := range tables {
+		table.mu.Lock()
+		table.rows = append(table.rows, tableRows[table]...)
+		table.mu.Unlock()
+	}
+	s.statistics.AddRowsCount(rowsCount)
+	return nil
+}
+
+func (s *storageSink) FlushRowChangedEvents(ctx context.Context, tableID model.TableID, resolvedTs uint64) (uint64, error) {
+	s.mu.Lock()
+	table, ok := s.tables[tableID]
+	s.mu.Unlock()
+	if !ok {
+		return resolvedTs, nil
+	}
+	table.mu.Lock()
+	defer table.mu.Unlock()
+	if resolvedTs <= table.checkpoint {
+		return table.checkpoint, nil
+	}
+
+	// rows of a table are emitted in commit ts order.
+	idx := sort.Search(len(table.rows), func(i int) bool {
+		return table.rows[i].CommitTs > resolvedTs
+	})
+	err := s.statistics.RecordBatchExecution(func() (int, error) {
+		if err := s.writeTable(ctx, table, table.rows[:idx], resolvedTs); err != nil {
+			return 0, err
+		}
+		return idx, nil
+	})
+	if err != nil {
+		return table.checkpoint, err
+	}
+	table.rows = append(table.rows[:0], table.rows[idx:]...)
+	table.checkpoint = resolvedTs
+	return resolvedTs, nil
+}
+
+// writeTable writes rows into data files, grouped by their table info version
+// and the time window of their commit ts, and then records the files in the
+// latest table manifest. Files of a table info version are put in a directory
+// named by the version, so a file never mixes rows of different schemas.
+func (s *storageSink) writeTable(
+	ctx context.Context, table *storageTable, rows []*model.RowChangedEvent, resolvedTs uint64,
+) error {
+	if table.manifest == nil {
+		manifest, seq, err := s.loadManifest(ctx, table.dir)
+		if err != nil {
+			return err
+		}
+		if err := s.cleanUncommittedFiles(ctx, table.dir, manifest); err != nil {
+			return err
+		}
+		table.manifest, table.manifestSeq = manifest, seq
+	}
+	// rows are replayed from the changefeed checkpoint after a restart, skip
+	// those already committed.
+	skip := sort.Search(len(rows), func(i int) bool {
+		return rows[i].CommitTs > table.manifest.ResolvedTs
+	})
+	rows = rows[skip:]
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var dirs []string
+	groups := make(map[string][]*model.RowChangedEvent)
+	for _, row := range rows {
+		if err := s.writeSchemaIfNotExist(ctx, table, row); err != nil {
+			return err
+		}
+		dir := path.Join(strconv.FormatUint(row.TableInfoVersion, 10), s.timeWindow(row.CommitTs))
+		if _, ok := groups[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		groups[dir] = append(groups[dir], row)
+	}
+
+	// the manifest of the table is only replaced after the new one is written,
+	// so a failed flush can be retried with the rows it skips unchanged.
+	seq := table.manifestSeq
+	manifest := &storageManifest{ResolvedTs: resolvedTs}
+	if len(table.manifest.Files) > 0 && len(table.manifest.Files)+len(dirs) > s.manifestMaxFiles {
+		seq++
+	} else {
+		manifest.Files = append(manifest.Files, table.manifest.Files...)
+	}
+
+	for _, dir := range dirs {
+		group := groups[dir]
+		data, unrepresentable, err := encodeStorageRows(s.params.protocol, group, s.tz)
+		if err != nil {
+			return err
+		}
+		if unrepresentable > 0 {
+			storageUnrepresentableValueCounter.WithLabelValues(s.id).Add(float64(unrepresentable))
+		}
+		name := storageDataFileName(resolvedTs, s.params.protocol)
+		filePath := path.Join(table.dir, dir, name)
+		if err := s.storage.WriteFile(ctx, filePath, data); err != nil {
+			return cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+		}
+		manifest.Files = append(manifest.Files, &storageFileMeta{
+			Path:        filePath,
+			MinCommitTs: group[0].CommitTs,
+			MaxCommitTs: group[len(group)-1].CommitTs,
+			Rows:        len(group),
+		})
+	}
+
+	if err := s.writeJSON(ctx, storageManifestPath(table.dir, seq), manifest); err != nil {
+		return err
+	}
+	table.manifest, table.manifestSeq = manifest, seq
+	return nil
+}
+
+// loadManifest reads the latest manifest of a table and its sequence number,
+// so a table moved from another capture keeps its file index.
+func (s *storageSink) loadManifest(ctx context.Context, dir string) (*storageManifest, int, error) {
+	seq, found := 0, false
+	err := s.storage.WalkDir(ctx, &storage.WalkOption{SubDir: path.Join(dir, storageManifestDir)},
+		func(filePath string, _ int64) error {
+			if n, ok := parseStorageManifestFileName(path.Base(filePath)); ok && (!found || n > seq) {
+				seq, found = n, true
+			}
+			return nil
+		})
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	manifest := &storageManifest{}
+	if !found {
+		return manifest, 0, nil
+	}
+	data, err := s.storage.ReadFile(ctx, storageManifestPath(dir, seq))
+	if err != nil {
+		return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	return manifest, seq, nil
+}
+
+// cleanUncommittedFiles deletes data files of the table which were written
+// but not committed into the manifest, they will be written again when the
+// rows are replayed. Data files are named by the resolved ts of their flush,
+// so the files newer than the resolved ts of the latest manifest are exactly
+// the uncommitted ones.
+func (s *storageSink) cleanUncommittedFiles(ctx context.Context, dir string, manifest *storageManifest) error {
+	var uncommitted []string
+	err := s.storage.WalkDir(ctx, &storage.WalkOption{SubDir: dir}, func(filePath string, _ int64) error {
+		filePath = strings.TrimPrefix(filePath, "/")
+		ts, ok := parseStorageDataFileName(path.Base(filePath))
+		if !ok || ts <= manifest.ResolvedTs {
+			return nil
+		}
+		uncommitted = append(uncommitted, filePath)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	for _, filePath := range uncommitted {
+		logger().Warn("delete uncommitted file of storage sink",
+			zap.String("changefeed", s.id), zap.String("path", filePath),
+			zap.Uint64("manifestResolvedTs", manifest.ResolvedTs))
+		if err := s.storage.DeleteFile(ctx, filePath); err != nil {
+			return cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+		}
+	}
+	return nil
+}
+
+// writeSchemaIfNotExist emits the schema file of the table info version of
+// row, unless it has already been written by the DDL sink or earlier flushes.
+func (s *storageSink) writeSchemaIfNotExist(
+	ctx context.Context, table *storageTable, row *model.RowChangedEvent,
+) error {
+	version := row.TableInfoVersion
+	if _, ok := table.versions[version]; ok {
+		return nil
+	}
+	name := path.Join(table.dir, storageSchemaFileName(version))
+	exists, err := s.storage.FileExists(ctx, name)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	if !exists {
+		columns := row.Columns
+		if len(columns) == 0 {
+			columns = row.PreColumns
+		}
+		schema := &storageSchema{
+			Schema:  row.Table.Schema,
+			Table:   row.Table.Table,
+			Version: version,
+			Columns: make([]*storageSchemaItem, 0, len(columns)),
+		}
+		for _, col := range columns {
+			if col == nil {
+				continue
+			}
+			schema.Columns = append(schema.Columns, &storageSchemaItem{
+				Name: col.Name,
+				Type: types.TypeStr(col.Type),
+			})
+		}
+		if err := s.writeJSON(ctx, name, schema); err != nil {
+			return err
+		}
+	}
+	table.versions[version] = struct{}{}
+	return nil
+}
+
+func (s *storageSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if s.filter != nil && s.filter.ShouldIgnoreDDLEvent(ddl.StartTs, ddl.Type, ddl.TableInfo.Schema, ddl.TableInfo.Table) {
+		logger().Info(
+			"DDL event ignored",
+			zap.String("query", ddl.Query),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("changefeed", s.id),
+		)
+		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
+	}
+	if ddl.TableInfo == nil || ddl.TableInfo.Table == "" {
+		// schema level DDLs have no table files to describe.
+		return nil
+	}
+	s.statistics.AddDDLCount()
+	schema := &storageSchema{
+		Schema:   ddl.TableInfo.Schema,
+		Table:    ddl.TableInfo.Table,
+		Version:  ddl.CommitTs,
+		Query:    ddl.Query,
+		Columns:  make([]*storageSchemaItem, 0, len(ddl.TableInfo.ColumnInfo)),
+		CommitTs: ddl.CommitTs,
+	}
+	for _, col := range ddl.TableInfo.ColumnInfo {
+		schema.Columns = append(schema.Columns, &storageSchemaItem{
+			Name: col.Name,
+			Type: types.TypeStr(col.Type),
+		})
+	}
+	dir := storageTableDir(&model.TableName{
+		Schema: ddl.TableInfo.Schema,
+		Table:  ddl.TableInfo.Table,
+	})
+	return s.statistics.RecordDDLExecution(func() error {
+		return s.writeJSON(ctx, path.Join(dir, storageSchemaFileName(ddl.CommitTs)), schema)
+	})
+}
+
+// EmitCheckpointTs writes the changefeed checkpoint into the metadata file,
+// every file with commit ts not greater than it is complete.
+func (s *storageSink) EmitCheckpointTs(ctx context.Context, ts uint64, _ []model.TableName) error {
+	return s.writeJSON(ctx, storageMetadataFile, map[string]uint64{"checkpoint-ts": ts})
+}
+
+func (s *storageSink) Close(ctx context.Context) error {
+	storageUnrepresentableValueCounter.DeleteLabelValues(s.id)
+	return nil
+}
+
+// Barrier is a no-op since rows are written synchronously when flushed.
+func (s *storageSink) Barrier(ctx context.Context, tableID model.TableID) error {
+	return nil
+}
+
+func (s *storageSink) writeJSON(ctx context.Context, name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	if err := s.storage.WriteFile(ctx, name, data); err != nil {
+		return cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+	}
+	return nil
+}
+
+// timeWindow returns the directory of the time window commitTs falls in.
+func (s *storageSink) timeWindow(commitTs uint64) string {
+	t := oracle.GetTimeFromTS(commitTs).UTC()
+	switch s.params.partition {
+	case storagePartitionDay:
+		return t.Format("2006-01-02")
+	case storagePartitionHour:
+		return t.Format("2006-01-02-15")
+	}
+	return ""
+}
+
+func storageTableDir(table *model.TableName) string {
+	dir := path.Join(table.Schema, table.Table)
+	if table.IsPartition {
+		dir = path.Join(dir, strconv.FormatInt(table.TableID, 10))
+	}
+	return dir
+}
+
+// storageDataFileName returns the name of a data file written by the flush
+// of resolvedTs.
+func storageDataFileName(resolvedTs uint64, protocol string) string {
+	return fmt.Sprintf("CDC%020d.%s", resolvedTs, protocol)
+}
+
+// parseStorageDataFileName returns the resolved ts of a data file name.
+func parseStorageDataFileName(name string) (uint64, bool) {
+	if !strings.HasPrefix(name, "CDC") || len(name) < 24 || name[23] != '.' {
+		return 0, false
+	}
+	ts, err := strconv.ParseUint(name[3:23], 10, 64)
+	return ts, err == nil
+}
+
+// storageManifestPath returns the path of the manifest of sequence number seq
+// of the table in dir.
+func storageManifestPath(dir string, seq int) string {
+	return path.Join(dir, storageManifestDir, fmt.Sprintf("manifest_%06d.json", seq))
+}
+
+// parseStorageManifestFileName returns the sequence number of a manifest name.
+func parseStorageManifestFileName(name string) (int, bool) {
+	if !strings.HasPrefix(name, "manifest_") || !strings.HasSuffix(name, ".json") {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "manifest_"), ".json"))
+	return seq, err == nil
+}
+
+func storageSchemaFileName(version uint64) string {
+	return fmt.Sprintf("schema_%d.json", version)
+}
+
+func storageRowOp(row *model.RowChangedEvent) string {
+	if row.IsDelete() {
+		return "D"
+	}
+	if len(row.PreColumns) != 0 {
+		return "U"
+	}
+	return "I"
+}
+
+// encodeStorageRows encodes rows in the given protocol. A csv line is the
+// operation type, the commit ts and then the column values; a json line is
+// an object with the same information keyed by column names; a parquet file
+// has the same columns as csv, see codec.EncodeParquet. It also returns the
+// number of values written as NULL since they can't be represented.
+func encodeStorageRows(
+	protocol string, rows []*model.RowChangedEvent, tz *time.Location,
+) ([]byte, int, error) {
+	buf := &bytes.Buffer{}
+	switch protocol {
+	case storageProtocolCSV:
+		w := csv.NewWriter(buf)
+		for _, row := range rows {
+			columns := row.Columns
+			if row.IsDelete() {
+				columns = row.PreColumns
+			}
+			record := []string{storageRowOp(row), strconv.FormatUint(row.CommitTs, 10)}
+			for _, col := range columns {
+				if col == nil {
+					continue
+				}
+				if col.Value == nil {
+					record = append(record, storageNullValue)
+					continue
+				}
+				record = append(record, storageColumnString(col.Value))
+			}
+			if err := w.Write(record); err != nil {
+				return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+		}
+	case storageProtocolParquet:
+		data, unrepresentable, err := codec.EncodeParquet(rows, tz)
+		if err != nil {
+			return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+		}
+		return data, unrepresentable, nil
+	case storageProtocolJSON:
+		enc := json.NewEncoder(buf)
+		for _, row := range rows {
+			msg := &storageJSONRow{
+				Op:       storageRowOp(row),
+				CommitTs: row.CommitTs,
+				Schema:   row.Table.Schema,
+				Table:    row.Table.Table,
+				Data:     storageColumnMap(row.Columns),
+				Old:      storageColumnMap(row.PreColumns),
+			}
+			if err := enc.Encode(msg); err != nil {
+				return nil, 0, cerror.WrapError(cerror.ErrCloudStorageWrite, err)
+			}
+		}
+	default:
+		return nil, 0, cerror.ErrCloudStorageInvalidConfig.GenWithStack("unknown protocol %s", protocol)
+	}
+	return buf.Bytes(), 0, nil
+}
+
+type storageJSONRow struct {
+	Op       string                 `json:"op"`
+	CommitTs uint64                 `json:"commit-ts"`
+	Schema   string                 `json:"schema"`
+	Table    string                 `json:"table"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Old      map[string]interface{} `json:"old,omitempty"`
+}
+
+func storageColumnMap(columns []*model.Column) map[string]interface{} {
+	if len(columns) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(columns))
+	for _, col := range columns {
+		if col == nil {
+			continue
+		}
+		if b, ok := col.Value.([]byte); ok {
+			m[col.Name] = string(b)
+			continue
+		}
+		m[col.Name] = col.Value
+	}
+	return m
+}
+
+func storageColumnString(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,300 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestParseStorageSinkParams(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	uri, err := url.Parse("s3://bucket/prefix?protocol=json&partition=hour&endpoint=http://127.0.0.1:9000")
+	require.Nil(t, err)
+	params, storageURI, err := parseStorageSinkParams(uri)
+	require.Nil(t, err)
+	require.Equal(t, storageProtocolJSON, params.protocol)
+	require.Equal(t, storagePartitionHour, params.partition)
+	require.Equal(t, "http://127.0.0.1:9000", storageURI.Query().Get("endpoint"))
+	require.Empty(t, storageURI.Query().Get("protocol"))
+
+	uri, err = url.Parse("file:///tmp/cdc")
+	require.Nil(t, err)
+	params, _, err = parseStorageSinkParams(uri)
+	require.Nil(t, err)
+	require.Equal(t, storageProtocolCSV, params.protocol)
+	require.Equal(t, storagePartitionDay, params.partition)
+
+	uri, err = url.Parse("file:///tmp/cdc?protocol=avro")
+	require.Nil(t, err)
+	_, _, err = parseStorageSinkParams(uri)
+	require.Regexp(t, ".*protocol avro is not supported.*", err)
+
+	uri, err = url.Parse("file:///tmp/cdc?partition=minute")
+	require.Nil(t, err)
+	_, _, err = parseStorageSinkParams(uri)
+	require.Regexp(t, ".*partition minute is not supported.*", err)
+}
+
+func TestStorageSinkFlush(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	uri, err := url.Parse("file://" + dir + "?partition=day")
+	require.Nil(t, err)
+	s, err := newStorageSink(ctx, "test", uri, nil)
+	require.Nil(t, err)
+
+	day := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	ts1 := oracle.ComposeTS(oracle.GetPhysical(day), 0)
+	ts2 := oracle.ComposeTS(oracle.GetPhysical(day.Add(24*time.Hour)), 0)
+	table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+	rows := []*model.RowChangedEvent{
+		{
+			CommitTs:         ts1,
+			Table:            table,
+			TableInfoVersion: 100,
+			Columns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: 1},
+				{Name: "name", Type: mysql.TypeVarchar, Value: []byte("a,b")},
+			},
+		},
+		{
+			CommitTs:         ts2,
+			Table:            table,
+			TableInfoVersion: 100,
+			PreColumns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: 1},
+				{Name: "name", Type: mysql.TypeVarchar, Value: nil},
+			},
+		},
+	}
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+
+	// nothing is written before the rows are flushed.
+	checkpoint, err := s.FlushRowChangedEvents(ctx, 1, ts1-1)
+	require.Nil(t, err)
+	require.Equal(t, ts1-1, checkpoint)
+	_, err = os.Stat(filepath.Join(dir, "test", "t", "100", "2022-06-01"))
+	require.True(t, os.IsNotExist(err))
+
+	checkpoint, err = s.FlushRowChangedEvents(ctx, 1, ts2)
+	require.Nil(t, err)
+	require.Equal(t, ts2, checkpoint)
+
+	data, err := os.ReadFile(filepath.Join(dir, "test", "t", "100", "2022-06-01", fmt.Sprintf("CDC%020d.csv", ts2)))
+	require.Nil(t, err)
+	require.Equal(t, fmt.Sprintf("I,%d,1,\"a,b\"\n", ts1), string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "test", "t", "100", "2022-06-02", fmt.Sprintf("CDC%020d.csv", ts2)))
+	require.Nil(t, err)
+	require.Equal(t, fmt.Sprintf("D,%d,1,\\N\n", ts2), string(data))
+
+	data, err = os.ReadFile(storageManifestPath(filepath.Join(dir, "test", "t"), 0))
+	require.Nil(t, err)
+	manifest := &storageManifest{}
+	require.Nil(t, json.Unmarshal(data, manifest))
+	require.Equal(t, ts2, manifest.ResolvedTs)
+	require.Len(t, manifest.Files, 2)
+	require.Equal(t, 1, manifest.Files[0].Rows)
+
+	data, err = os.ReadFile(filepath.Join(dir, "test", "t", "schema_100.json"))
+	require.Nil(t, err)
+	schema := &storageSchema{}
+	require.Nil(t, json.Unmarshal(data, schema))
+	require.Equal(t, []*storageSchemaItem{
+		{Name: "id", Type: "int"},
+		{Name: "name", Type: "varchar"},
+	}, schema.Columns)
+
+	// a new sink on the same storage keeps the existing file index.
+	s2, err := newStorageSink(ctx, "test", uri, nil)
+	require.Nil(t, err)
+	ts3 := ts2 + 1
+	require.Nil(t, s2.EmitRowChangedEvents(ctx, &model.RowChangedEvent{
+		CommitTs:         ts3,
+		Table:            table,
+		TableInfoVersion: 100,
+		Columns:          []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: 2}},
+	}))
+	_, err = s2.FlushRowChangedEvents(ctx, 1, ts3)
+	require.Nil(t, err)
+	data, err = os.ReadFile(storageManifestPath(filepath.Join(dir, "test", "t"), 0))
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(data, manifest))
+	require.Len(t, manifest.Files, 3)
+
+	require.Nil(t, s.EmitCheckpointTs(ctx, ts3, nil))
+	data, err = os.ReadFile(filepath.Join(dir, storageMetadataFile))
+	require.Nil(t, err)
+	require.Equal(t, fmt.Sprintf(`{"checkpoint-ts":%d}`, ts3), string(data))
+}
+
+func TestEncodeStorageRowsJSON(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	data, _, err := encodeStorageRows(storageProtocolJSON, []*model.RowChangedEvent{{
+		CommitTs: 10,
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		PreColumns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: 1},
+		},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: 2},
+		},
+	}}, time.UTC)
+	require.Nil(t, err)
+	require.Equal(t,
+		`{"op":"U","commit-ts":10,"schema":"test","table":"t","data":{"id":2},"old":{"id":1}}`+"\n",
+		string(data))
+}
+
+func TestStorageSinkReplayAfterCrash(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	uri, err := url.Parse("file://" + dir)
+	require.Nil(t, err)
+
+	ts1 := oracle.ComposeTS(oracle.GetPhysical(time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)), 0)
+	table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+	newRow := func(ts uint64, id int64) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			CommitTs:         ts,
+			Table:            table,
+			TableInfoVersion: 100,
+			Columns:          []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: id}},
+		}
+	}
+
+	s, err := newStorageSink(ctx, "test", uri, nil)
+	require.Nil(t, err)
+	require.Nil(t, s.EmitRowChangedEvents(ctx, newRow(ts1, 1)))
+	_, err = s.FlushRowChangedEvents(ctx, 1, ts1)
+	require.Nil(t, err)
+
+	// a crash happens after the data file of the next flush is written but
+	// before the manifest is committed.
+	windowDir := filepath.Join(dir, "test", "t", "100", "2022-06-01")
+	uncommitted := filepath.Join(windowDir, storageDataFileName(ts1+10, storageProtocolCSV))
+	require.Nil(t, os.WriteFile(uncommitted, []byte("partial"), 0o644))
+
+	// rows are replayed from the checkpoint by a new sink.
+	s2, err := newStorageSink(ctx, "test", uri, nil)
+	require.Nil(t, err)
+	require.Nil(t, s2.EmitRowChangedEvents(ctx, newRow(ts1, 1), newRow(ts1+1, 2)))
+	_, err = s2.FlushRowChangedEvents(ctx, 1, ts1+2)
+	require.Nil(t, err)
+
+	_, err = os.Stat(uncommitted)
+	require.True(t, os.IsNotExist(err))
+	data, err := os.ReadFile(filepath.Join(windowDir, storageDataFileName(ts1+2, storageProtocolCSV)))
+	require.Nil(t, err)
+	require.Equal(t, fmt.Sprintf("I,%d,2\n", ts1+1), string(data))
+
+	data, err = os.ReadFile(storageManifestPath(filepath.Join(dir, "test", "t"), 0))
+	require.Nil(t, err)
+	manifest := &storageManifest{}
+	require.Nil(t, json.Unmarshal(data, manifest))
+	require.Equal(t, ts1+2, manifest.ResolvedTs)
+	require.Len(t, manifest.Files, 2)
+}
+
+func TestStorageSinkManifest(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	uri, err := url.Parse("file://" + dir + "?partition=none")
+	require.Nil(t, err)
+
+	ts1 := oracle.ComposeTS(oracle.GetPhysical(time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)), 0)
+	table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+	tableDir := filepath.Join(dir, "test", "t")
+	newRow := func(ts uint64, id int64) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			CommitTs:         ts,
+			Table:            table,
+			TableInfoVersion: 100,
+			Columns:          []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: id}},
+		}
+	}
+	readManifest := func(seq int) *storageManifest {
+		data, err := os.ReadFile(storageManifestPath(tableDir, seq))
+		require.Nil(t, err)
+		manifest := &storageManifest{}
+		require.Nil(t, json.Unmarshal(data, manifest))
+		return manifest
+	}
+
+	s, err := newStorageSink(ctx, "test", uri, nil)
+	require.Nil(t, err)
+	s.manifestMaxFiles = 2
+
+	// the rows are not lost if the manifest fails to be written.
+	manifestPath := storageManifestPath(tableDir, 0)
+	require.Nil(t, os.MkdirAll(manifestPath, 0o755))
+	require.Nil(t, s.EmitRowChangedEvents(ctx, newRow(ts1, 1)))
+	_, err = s.FlushRowChangedEvents(ctx, 1, ts1)
+	require.NotNil(t, err)
+	require.Nil(t, os.Remove(manifestPath))
+	checkpoint, err := s.FlushRowChangedEvents(ctx, 1, ts1)
+	require.Nil(t, err)
+	require.Equal(t, ts1, checkpoint)
+	manifest := readManifest(0)
+	require.Equal(t, ts1, manifest.ResolvedTs)
+	require.Len(t, manifest.Files, 1)
+	require.Equal(t, 1, manifest.Files[0].Rows)
+
+	// a new manifest is rolled once the latest one is full.
+	for _, ts := range []uint64{ts1 + 1, ts1 + 2} {
+		require.Nil(t, s.EmitRowChangedEvents(ctx, newRow(ts, int64(ts-ts1+1))))
+		_, err = s.FlushRowChangedEvents(ctx, 1, ts)
+		require.Nil(t, err)
+	}
+	manifest = readManifest(0)
+	require.Equal(t, ts1+1, manifest.ResolvedTs)
+	require.Len(t, manifest.Files, 2)
+	manifest = readManifest(1)
+	require.Equal(t, ts1+2, manifest.ResolvedTs)
+	require.Len(t, manifest.Files, 1)
+
+	// a new sink continues with the latest manifest.
+	s2, err := newStorageSink(ctx, "test", uri, nil)
+	require.Nil(t, err)
+	require.Nil(t, s2.EmitRowChangedEvents(ctx, newRow(ts1+2, 3), newRow(ts1+3, 4)))
+	_, err = s2.FlushRowChangedEvents(ctx, 1, ts1+3)
+	require.Nil(t, err)
+	manifest = readManifest(1)
+	require.Equal(t, ts1+3, manifest.ResolvedTs)
+	require.Len(t, manifest.Files, 2)
+	require.Equal(t, 1, manifest.Files[1].Rows)
+}
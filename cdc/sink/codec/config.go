@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -37,11 +38,43 @@ type Config struct {
 	// canal-json only
 	enableTiDBExtension bool
 
-	// avro only
+	// avro and protobuf
 	avroRegistry string
-	tz           *time.Location
+	// avro only
+	tz *time.Location
+
+	// protobuf only
+	subjectNameStrategy SubjectNameStrategy
+	topicResolver       func(table *model.TableName) string
+
+	// debezium only
+	debeziumDisableSchema bool
+
+	// largeMessageHandle decides how to handle a single row exceeding
+	// max-message-bytes, the row is sent to deadLetterTopic by "dead-letter".
+	largeMessageHandle LargeMessageHandle
+	deadLetterTopic    string
+
+	// watermarkTopic receives the checkpoint watermarks of the changefeed,
+	// empty means the watermarks are not emitted.
+	watermarkTopic string
 }
 
+// LargeMessageHandle decides how to handle a single row whose message exceeds
+// max-message-bytes.
+type LargeMessageHandle string
+
+const (
+	// LargeMessageHandleError fails the changefeed.
+	LargeMessageHandleError LargeMessageHandle = "error"
+	// LargeMessageHandleTruncate truncates the values of the blob columns until
+	// the message fits.
+	LargeMessageHandleTruncate LargeMessageHandle = "truncate"
+	// LargeMessageHandleDeadLetter sends a pointer record of the row, which
+	// locates the row in the upstream, to the dead letter topic.
+	LargeMessageHandleDeadLetter LargeMessageHandle = "dead-letter"
+)
+
 // NewConfig return a Config for codec
 func NewConfig(protocol config.Protocol, tz *time.Location) *Config {
 	return &Config{
@@ -53,6 +86,8 @@ func NewConfig(protocol config.Protocol, tz *time.Location) *Config {
 		enableTiDBExtension: false,
 		avroRegistry:        "",
 		tz:                  tz,
+		subjectNameStrategy: SubjectNameStrategyTable,
+		largeMessageHandle:  LargeMessageHandleError,
 	}
 }
 
@@ -61,6 +96,11 @@ const (
 	codecOPTMaxBatchSize        = "max-batch-size"
 	codecOPTMaxMessageBytes     = "max-message-bytes"
 	codecAvroRegistry           = "registry"
+	codecSubjectNameStrategy    = "subject-name-strategy"
+	codecDebeziumDisableSchema  = "debezium-disable-schema"
+	codecLargeMessageHandle     = "large-message-handle"
+	codecDeadLetterTopic        = "dead-letter-topic"
+	codecWatermarkTopic         = "watermark-topic"
 )
 
 // Apply fill the Config
@@ -94,6 +134,30 @@ func (c *Config) Apply(sinkURI *url.URL, opts map[string]string) error {
 		c.avroRegistry = s
 	}
 
+	if s := params.Get(codecSubjectNameStrategy); s != "" {
+		c.subjectNameStrategy = SubjectNameStrategy(s)
+	}
+
+	if s := params.Get(codecDebeziumDisableSchema); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		c.debeziumDisableSchema = b
+	}
+
+	if s := params.Get(codecLargeMessageHandle); s != "" {
+		c.largeMessageHandle = LargeMessageHandle(s)
+	}
+
+	if s := params.Get(codecDeadLetterTopic); s != "" {
+		c.deadLetterTopic = s
+	}
+
+	if s := params.Get(codecWatermarkTopic); s != "" {
+		c.watermarkTopic = s
+	}
+
 	return nil
 }
 
@@ -103,6 +167,13 @@ func (c *Config) WithMaxMessageBytes(bytes int) *Config {
 	return c
 }
 
+// WithTopicResolver sets the function used to find the topic a table is
+// dispatched to, it is required by the topic based subject name strategies.
+func (c *Config) WithTopicResolver(resolver func(table *model.TableName) string) *Config {
+	c.topicResolver = resolver
+	return c
+}
+
 // Validate the Config
 func (c *Config) Validate() error {
 	if c.protocol != config.ProtocolCanalJSON && c.enableTiDBExtension {
@@ -119,6 +190,22 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.protocol == config.ProtocolProtobuf {
+		if c.avroRegistry == "" {
+			return cerror.ErrMQCodecInvalidConfig.GenWithStack(`Protobuf protocol requires parameter "registry"`)
+		}
+
+		if err := c.subjectNameStrategy.validate(); err != nil {
+			return err
+		}
+	} else if c.subjectNameStrategy != SubjectNameStrategyTable {
+		return cerror.ErrMQCodecInvalidConfig.GenWithStack(`subject-name-strategy only support protobuf protocol`)
+	}
+
+	if c.protocol != config.ProtocolDebezium && c.debeziumDisableSchema {
+		return cerror.ErrMQCodecInvalidConfig.GenWithStack(`debezium-disable-schema only support debezium protocol`)
+	}
+
 	if c.maxMessageBytes <= 0 {
 		return cerror.ErrMQCodecInvalidConfig.Wrap(errors.Errorf("invalid max-message-bytes %d", c.maxMessageBytes))
 	}
@@ -127,6 +214,22 @@ func (c *Config) Validate() error {
 		return cerror.ErrMQCodecInvalidConfig.Wrap(errors.Errorf("invalid max-batch-size %d", c.maxBatchSize))
 	}
 
+	switch c.largeMessageHandle {
+	case LargeMessageHandleError, LargeMessageHandleTruncate:
+		if c.deadLetterTopic != "" {
+			return cerror.ErrMQCodecInvalidConfig.GenWithStack(
+				`dead-letter-topic only support large-message-handle "dead-letter"`)
+		}
+	case LargeMessageHandleDeadLetter:
+		if c.deadLetterTopic == "" {
+			return cerror.ErrMQCodecInvalidConfig.GenWithStack(
+				`large-message-handle "dead-letter" requires parameter "dead-letter-topic"`)
+		}
+	default:
+		return cerror.ErrMQCodecInvalidConfig.Wrap(
+			errors.Errorf("invalid large-message-handle %s", c.largeMessageHandle))
+	}
+
 	return nil
 }
 
@@ -135,6 +238,21 @@ func (c *Config) MaxMessageBytes() int {
 	return c.maxMessageBytes
 }
 
+// LargeMessageHandle returns how to handle a single row exceeding max-message-bytes.
+func (c *Config) LargeMessageHandle() LargeMessageHandle {
+	return c.largeMessageHandle
+}
+
+// DeadLetterTopic returns the topic the rows exceeding max-message-bytes are sent to.
+func (c *Config) DeadLetterTopic() string {
+	return c.deadLetterTopic
+}
+
+// WatermarkTopic returns the topic the checkpoint watermarks are sent to.
+func (c *Config) WatermarkTopic() string {
+	return c.watermarkTopic
+}
+
 // Protocol return the protocol for the codec
 func (c *Config) Protocol() config.Protocol {
 	return c.protocol
@@ -105,6 +105,44 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.Nil(t, err)
 
+	// protobuf
+	uri = "kafka://127.0.0.1:9092/abc?protocol=protobuf&subject-name-strategy=record-name"
+	sinkURI, err = url.Parse(uri)
+	require.Nil(t, err)
+	err = p.FromString(sinkURI.Query().Get("protocol"))
+	require.Nil(t, err)
+	c = NewConfig(p, timeutil.SystemLocation())
+	err = c.Apply(sinkURI, map[string]string{})
+	require.Nil(t, err)
+	require.Equal(t, SubjectNameStrategyRecord, c.subjectNameStrategy)
+	err = c.Validate()
+	require.Error(t, err, `Protobuf protocol requires parameter "registry"`)
+	err = c.Apply(sinkURI, opts)
+	require.Nil(t, err)
+	require.Nil(t, c.Validate())
+
+	c.subjectNameStrategy = "unknown"
+	require.Error(t, c.Validate(), "unknown subject-name-strategy unknown")
+	c.protocol = config.ProtocolAvro
+	c.subjectNameStrategy = SubjectNameStrategyRecord
+	require.Error(t, c.Validate(), "subject-name-strategy only support protobuf protocol")
+	c.subjectNameStrategy = SubjectNameStrategyTable
+
+	// debezium
+	uri = "kafka://127.0.0.1:9092/abc?protocol=debezium&debezium-disable-schema=true"
+	sinkURI, err = url.Parse(uri)
+	require.Nil(t, err)
+	err = p.FromString(sinkURI.Query().Get("protocol"))
+	require.Nil(t, err)
+	c = NewConfig(p, timeutil.SystemLocation())
+	err = c.Apply(sinkURI, map[string]string{})
+	require.Nil(t, err)
+	require.True(t, c.debeziumDisableSchema)
+	require.Nil(t, c.Validate())
+	c.protocol = config.ProtocolCanalJSON
+	require.Error(t, c.Validate(), "debezium-disable-schema only support debezium protocol")
+	c.debeziumDisableSchema = false
+
 	// Illegal max-message-bytes.
 	uri = "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&max-message-bytes=a"
 	sinkURI, err = url.Parse(uri)
@@ -139,3 +177,24 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.Error(t, err, cerror.ErrMQCodecInvalidConfig)
 }
+
+func TestConfigLargeMessageHandle(t *testing.T) {
+	c := NewConfig(config.ProtocolOpen, timeutil.SystemLocation())
+	require.Equal(t, LargeMessageHandleError, c.LargeMessageHandle())
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?large-message-handle=dead-letter&dead-letter-topic=dlq")
+	require.Nil(t, err)
+	require.Nil(t, c.Apply(sinkURI, map[string]string{}))
+	require.Equal(t, LargeMessageHandleDeadLetter, c.LargeMessageHandle())
+	require.Equal(t, "dlq", c.DeadLetterTopic())
+	require.Nil(t, c.Validate())
+
+	c.largeMessageHandle = LargeMessageHandleTruncate
+	require.Regexp(t, `.*dead-letter-topic only support large-message-handle "dead-letter".*`, c.Validate())
+	c.deadLetterTopic = ""
+	require.Nil(t, c.Validate())
+	c.largeMessageHandle = LargeMessageHandleDeadLetter
+	require.Regexp(t, `.*requires parameter "dead-letter-topic".*`, c.Validate())
+	c.largeMessageHandle = "unknown"
+	require.Regexp(t, ".*invalid large-message-handle unknown.*", c.Validate())
+}
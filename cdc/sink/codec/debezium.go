@@ -0,0 +1,488 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	debeziumConnectorName = "tidb"
+	debeziumServerName    = "ticdc"
+
+	debeziumOpCreate = "c"
+	debeziumOpUpdate = "u"
+	debeziumOpDelete = "d"
+)
+
+// Semantic type names used by Debezium for columns whose Kafka Connect type
+// does not fully describe the value.
+const (
+	debeziumDate           = "io.debezium.time.Date"
+	debeziumTimestamp      = "io.debezium.time.Timestamp"
+	debeziumMicroTimestamp = "io.debezium.time.MicroTimestamp"
+	debeziumZonedTimestamp = "io.debezium.time.ZonedTimestamp"
+	debeziumMicroTime      = "io.debezium.time.MicroTime"
+	debeziumYear           = "io.debezium.time.Year"
+	debeziumEnum           = "io.debezium.data.Enum"
+	debeziumEnumSet        = "io.debezium.data.EnumSet"
+	debeziumBits           = "io.debezium.data.Bits"
+	debeziumJSON           = "io.debezium.data.Json"
+)
+
+type debeziumEventBatchEncoderBuilder struct {
+	config *Config
+}
+
+func newDebeziumEventBatchEncoderBuilder(config *Config) EncoderBuilder {
+	return &debeziumEventBatchEncoderBuilder{config: config}
+}
+
+// Build a `DebeziumEventBatchEncoder`
+func (b *debeziumEventBatchEncoderBuilder) Build() EventBatchEncoder {
+	encoder := NewDebeziumEventBatchEncoder()
+	encoder.(*DebeziumEventBatchEncoder).tz = b.config.tz
+	encoder.(*DebeziumEventBatchEncoder).disableSchema = b.config.debeziumDisableSchema
+	return encoder
+}
+
+// DebeziumEventBatchEncoder encodes row changed events into the JSON envelope
+// of Debezium, so that the sink connectors of Debezium can consume them
+// directly. Every row is encoded into a single message.
+type DebeziumEventBatchEncoder struct {
+	messageBuf []*MQMessage
+	tz         *time.Location
+	// disableSchema is the counterpart of `schemas.enable=false` of the
+	// JsonConverter of Kafka Connect, only the payload is encoded if it's true.
+	disableSchema bool
+}
+
+// NewDebeziumEventBatchEncoder creates a new DebeziumEventBatchEncoder.
+func NewDebeziumEventBatchEncoder() EventBatchEncoder {
+	return &DebeziumEventBatchEncoder{
+		messageBuf: make([]*MQMessage, 0),
+		tz:         time.UTC,
+	}
+}
+
+// debeziumMessage is the message of JsonConverter, the schema is omitted if
+// schemas are disabled.
+type debeziumMessage struct {
+	Schema  *debeziumSchema `json:"schema,omitempty"`
+	Payload interface{}     `json:"payload"`
+}
+
+// debeziumSchema is a Kafka Connect schema in JSON.
+type debeziumSchema struct {
+	Type     string            `json:"type"`
+	Optional bool              `json:"optional"`
+	Name     string            `json:"name,omitempty"`
+	Field    string            `json:"field,omitempty"`
+	Fields   []*debeziumSchema `json:"fields,omitempty"`
+}
+
+type debeziumSource struct {
+	Version   string `json:"version"`
+	Connector string `json:"connector"`
+	Name      string `json:"name"`
+	TsMs      int64  `json:"ts_ms"`
+	Snapshot  string `json:"snapshot"`
+	DB        string `json:"db"`
+	Table     string `json:"table"`
+	CommitTs  uint64 `json:"commit_ts"`
+}
+
+type debeziumPayload struct {
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+	Source *debeziumSource        `json:"source"`
+	Op     string                 `json:"op"`
+	TsMs   int64                  `json:"ts_ms"`
+}
+
+var debeziumSourceSchema = &debeziumSchema{
+	Type:     "struct",
+	Optional: false,
+	Name:     "io.debezium.connector.tidb.Source",
+	Field:    "source",
+	Fields: []*debeziumSchema{
+		{Type: "string", Field: "version"},
+		{Type: "string", Field: "connector"},
+		{Type: "string", Field: "name"},
+		{Type: "int64", Field: "ts_ms"},
+		{Type: "string", Optional: true, Field: "snapshot"},
+		{Type: "string", Field: "db"},
+		{Type: "string", Optional: true, Field: "table"},
+		{Type: "int64", Field: "commit_ts"},
+	},
+}
+
+// EncodeCheckpointEvent implements the EventBatchEncoder interface
+func (d *DebeziumEventBatchEncoder) EncodeCheckpointEvent(ts uint64) (*MQMessage, error) {
+	// Debezium has no counterpart of the resolved event, it's ignored.
+	return nil, nil
+}
+
+// EncodeDDLEvent implements the EventBatchEncoder interface
+func (d *DebeziumEventBatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*MQMessage, error) {
+	// The sink connectors of Debezium do not consume schema change events from
+	// the data topics, DDLs are not sent to keep them working.
+	return nil, nil
+}
+
+// AppendRowChangedEvent implements the EventBatchEncoder interface
+func (d *DebeziumEventBatchEncoder) AppendRowChangedEvent(e *model.RowChangedEvent) error {
+	key, err := d.encodeKey(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	value, err := d.encodeValue(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m := NewMQMessage(config.ProtocolDebezium, key, value, e.CommitTs,
+		model.MqMessageTypeRow, &e.Table.Schema, &e.Table.Table)
+	m.IncRowsCount()
+	d.messageBuf = append(d.messageBuf, m)
+	return nil
+}
+
+// Build implements the EventBatchEncoder interface
+func (d *DebeziumEventBatchEncoder) Build() []*MQMessage {
+	if len(d.messageBuf) == 0 {
+		return nil
+	}
+	ret := d.messageBuf
+	d.messageBuf = make([]*MQMessage, 0)
+	return ret
+}
+
+// Size implements the EventBatchEncoder interface
+func (d *DebeziumEventBatchEncoder) Size() int {
+	return -1
+}
+
+func (d *DebeziumEventBatchEncoder) encodeKey(e *model.RowChangedEvent) ([]byte, error) {
+	cols := e.Columns
+	if e.IsDelete() {
+		cols = e.PreColumns
+	}
+	fields, data, err := d.encodeColumns(cols, e.ColInfos, true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The key is null for tables without a handle key, like Debezium does for
+	// tables without a primary key.
+	if len(data) == 0 {
+		return nil, nil
+	}
+	msg := &debeziumMessage{Payload: data}
+	if !d.disableSchema {
+		msg.Schema = &debeziumSchema{
+			Type:   "struct",
+			Name:   debeziumRecordName(e.Table, "Key"),
+			Fields: fields,
+		}
+	}
+	return d.marshal(msg)
+}
+
+func (d *DebeziumEventBatchEncoder) encodeValue(e *model.RowChangedEvent) ([]byte, error) {
+	commitTime := oracle.ExtractPhysical(e.CommitTs)
+	payload := &debeziumPayload{
+		Source: &debeziumSource{
+			Version:   version.ReleaseVersion,
+			Connector: debeziumConnectorName,
+			Name:      debeziumServerName,
+			TsMs:      commitTime,
+			Snapshot:  "false",
+			DB:        e.Table.Schema,
+			Table:     e.Table.Table,
+			CommitTs:  e.CommitTs,
+		},
+		TsMs: time.Now().UnixNano() / int64(time.Millisecond),
+	}
+
+	var (
+		rowFields []*debeziumSchema
+		err       error
+	)
+	if e.IsDelete() {
+		payload.Op = debeziumOpDelete
+		rowFields, payload.Before, err = d.encodeColumns(e.PreColumns, e.ColInfos, false)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else {
+		payload.Op = debeziumOpCreate
+		rowFields, payload.After, err = d.encodeColumns(e.Columns, e.ColInfos, false)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if e.IsUpdate() {
+			payload.Op = debeziumOpUpdate
+			_, payload.Before, err = d.encodeColumns(e.PreColumns, e.ColInfos, false)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+
+	msg := &debeziumMessage{Payload: payload}
+	if !d.disableSchema {
+		rowName := debeziumRecordName(e.Table, "Value")
+		msg.Schema = &debeziumSchema{
+			Type: "struct",
+			Name: debeziumRecordName(e.Table, "Envelope"),
+			Fields: []*debeziumSchema{
+				{Type: "struct", Optional: true, Name: rowName, Field: "before", Fields: rowFields},
+				{Type: "struct", Optional: true, Name: rowName, Field: "after", Fields: rowFields},
+				debeziumSourceSchema,
+				{Type: "string", Field: "op"},
+				{Type: "int64", Optional: true, Field: "ts_ms"},
+			},
+		}
+	}
+	return d.marshal(msg)
+}
+
+func (d *DebeziumEventBatchEncoder) marshal(msg *debeziumMessage) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+	}
+	return data, nil
+}
+
+// encodeColumns returns the Kafka Connect schemas and the values of the
+// columns, only handle key columns are encoded if onlyHandleKey is true.
+func (d *DebeziumEventBatchEncoder) encodeColumns(
+	cols []*model.Column, colInfos []rowcodec.ColInfo, onlyHandleKey bool,
+) ([]*debeziumSchema, map[string]interface{}, error) {
+	fields := make([]*debeziumSchema, 0, len(cols))
+	data := make(map[string]interface{}, len(cols))
+	for i, col := range cols {
+		if col == nil || (onlyHandleKey && !col.Flag.IsHandleKey()) {
+			continue
+		}
+		var ft *types.FieldType
+		if i < len(colInfos) {
+			ft = colInfos[i].Ft
+		}
+		field, value, err := columnToDebeziumData(col, ft, d.tz)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		fields = append(fields, field)
+		data[col.Name] = value
+	}
+	return fields, data, nil
+}
+
+func debeziumRecordName(table *model.TableName, suffix string) string {
+	return debeziumServerName + "." + table.Schema + "." + table.Table + "." + suffix
+}
+
+// columnToDebeziumData converts the column into the value used by Debezium
+// with its default `time.precision.mode`, `decimal.handling.mode=string` and
+// `bigint.unsigned.handling.mode=long`.
+func columnToDebeziumData(
+	col *model.Column, ft *types.FieldType, tz *time.Location,
+) (*debeziumSchema, interface{}, error) {
+	field := &debeziumSchema{
+		Field:    col.Name,
+		Optional: col.Flag.IsNullable(),
+	}
+	fsp := 0
+	if ft != nil {
+		fsp = ft.Decimal
+	}
+
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort:
+		field.Type = "int16"
+		if col.Type == mysql.TypeShort && col.Flag.IsUnsigned() {
+			field.Type = "int32"
+		}
+	case mysql.TypeInt24:
+		field.Type = "int32"
+	case mysql.TypeLong:
+		field.Type = "int32"
+		if col.Flag.IsUnsigned() {
+			field.Type = "int64"
+		}
+	case mysql.TypeLonglong:
+		field.Type = "int64"
+	case mysql.TypeFloat:
+		field.Type = "float"
+	case mysql.TypeDouble:
+		field.Type = "double"
+	case mysql.TypeNewDecimal:
+		field.Type = "string"
+	case mysql.TypeDate, mysql.TypeNewDate:
+		field.Type, field.Name = "int32", debeziumDate
+	case mysql.TypeDatetime:
+		field.Type, field.Name = "int64", debeziumTimestamp
+		if fsp > 3 {
+			field.Name = debeziumMicroTimestamp
+		}
+	case mysql.TypeTimestamp:
+		field.Type, field.Name = "string", debeziumZonedTimestamp
+	case mysql.TypeDuration:
+		field.Type, field.Name = "int64", debeziumMicroTime
+	case mysql.TypeYear:
+		field.Type, field.Name = "int32", debeziumYear
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		field.Type = "string"
+		if col.Flag.IsBinary() {
+			field.Type = "bytes"
+		}
+	case mysql.TypeEnum:
+		field.Type, field.Name = "string", debeziumEnum
+	case mysql.TypeSet:
+		field.Type, field.Name = "string", debeziumEnumSet
+	case mysql.TypeBit:
+		field.Type, field.Name = "bytes", debeziumBits
+		if ft != nil && ft.Flen == 1 {
+			field.Type, field.Name = "boolean", ""
+		}
+	case mysql.TypeJSON:
+		field.Type, field.Name = "string", debeziumJSON
+	default:
+		return nil, nil, cerror.ErrDebeziumEncodeFailed.GenWithStack(
+			"unsupported column type %d of column %s", col.Type, col.Name)
+	}
+
+	if col.Value == nil {
+		return field, nil, nil
+	}
+
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		switch v := col.Value.(type) {
+		case uint64:
+			// Unsigned bigint values larger than the max int64 overflow, which
+			// is the same as Debezium in the `long` handling mode.
+			return field, int64(v), nil
+		case int64:
+			return field, v, nil
+		}
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return field, col.Value, nil
+	case mysql.TypeDate, mysql.TypeNewDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t, err := parseDebeziumTime(col, tz)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		switch field.Name {
+		case debeziumDate:
+			return field, int32(t.Unix() / 86400), nil
+		case debeziumTimestamp:
+			return field, t.UnixNano() / int64(time.Millisecond), nil
+		case debeziumMicroTimestamp:
+			return field, t.UnixNano() / int64(time.Microsecond), nil
+		default:
+			return field, t.UTC().Format(time.RFC3339Nano), nil
+		}
+	case mysql.TypeDuration:
+		d, err := types.ParseDuration(nil, col.Value.(string), types.MaxFsp)
+		if err != nil {
+			return nil, nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+		}
+		return field, d.Duration.Microseconds(), nil
+	case mysql.TypeYear:
+		return field, int32(col.Value.(int64)), nil
+	case mysql.TypeEnum, mysql.TypeSet:
+		if ft == nil {
+			return nil, nil, cerror.ErrDebeziumEncodeFailed.GenWithStack(
+				"elements of column %s are unknown", col.Name)
+		}
+		if col.Type == mysql.TypeEnum {
+			enum, err := types.ParseEnumValue(ft.Elems, col.Value.(uint64))
+			if err != nil {
+				return nil, nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+			}
+			return field, enum.Name, nil
+		}
+		set, err := types.ParseSetValue(ft.Elems, col.Value.(uint64))
+		if err != nil {
+			return nil, nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+		}
+		return field, set.Name, nil
+	case mysql.TypeBit:
+		v := col.Value.(uint64)
+		if field.Type == "boolean" {
+			return field, v != 0, nil
+		}
+		// Bits are encoded in little endian, trimmed to the width of the column.
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, v)
+		if ft != nil && ft.Flen > 0 {
+			buf = buf[:(ft.Flen+7)/8]
+		}
+		return field, buf, nil
+	default:
+		switch v := col.Value.(type) {
+		case []byte:
+			if field.Type == "bytes" {
+				return field, v, nil
+			}
+			return field, string(v), nil
+		case string:
+			if field.Type == "bytes" {
+				return field, []byte(v), nil
+			}
+			return field, v, nil
+		}
+	}
+	log.Panic("Debezium could not process the column", zap.Reflect("col", col))
+	return nil, nil, nil
+}
+
+func parseDebeziumTime(col *model.Column, tz *time.Location) (time.Time, error) {
+	str := col.Value.(string)
+	// Zero dates are encoded as the epoch.
+	if str == zeroDateStr || str == zeroTimeStr {
+		return time.Unix(0, 0), nil
+	}
+	// Only timestamps are stored in the time zone of the changefeed.
+	loc := time.UTC
+	if col.Type == mysql.TypeTimestamp {
+		loc = tz
+	}
+	layout := types.TimeFormat
+	if col.Type == mysql.TypeDate || col.Type == mysql.TypeNewDate {
+		layout = types.DateFormat
+	}
+	t, err := time.ParseInLocation(layout, str, loc)
+	if err != nil {
+		return time.Time{}, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+	}
+	return t, nil
+}
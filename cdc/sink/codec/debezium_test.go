@@ -0,0 +1,190 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebeziumTestRow() *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		CommitTs: 417318403368288260,
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Flag: model.NullableFlag, Value: []byte("abc")},
+			{Name: "price", Type: mysql.TypeNewDecimal, Flag: model.NullableFlag, Value: "1.50"},
+			{Name: "born", Type: mysql.TypeDate, Flag: model.NullableFlag, Value: "1970-01-11"},
+			{Name: "created", Type: mysql.TypeTimestamp, Flag: model.NullableFlag, Value: "2022-01-01 08:00:00"},
+			{Name: "status", Type: mysql.TypeEnum, Flag: model.NullableFlag, Value: uint64(2)},
+			{Name: "note", Type: mysql.TypeBlob, Flag: model.NullableFlag | model.BinaryFlag, Value: nil},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+			{ID: 3, Ft: types.NewFieldType(mysql.TypeNewDecimal)},
+			{ID: 4, Ft: types.NewFieldType(mysql.TypeDate)},
+			{ID: 5, Ft: types.NewFieldType(mysql.TypeTimestamp)},
+			{ID: 6, Ft: setElems(types.NewFieldType(mysql.TypeEnum), []string{"on", "off"})},
+			{ID: 7, Ft: types.NewFieldType(mysql.TypeBlob)},
+		},
+	}
+}
+
+type debeziumTestMessage struct {
+	Schema  *debeziumSchema `json:"schema"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func TestDebeziumEncodeRowChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	tz, err := time.LoadLocation("Asia/Shanghai")
+	require.Nil(t, err)
+	encoder := newDebeziumEventBatchEncoderBuilder(&Config{tz: tz}).Build()
+
+	insert := newDebeziumTestRow()
+	require.Nil(t, encoder.AppendRowChangedEvent(insert))
+	update := newDebeziumTestRow()
+	update.PreColumns = newDebeziumTestRow().Columns
+	update.PreColumns[1].Value = []byte("abd")
+	require.Nil(t, encoder.AppendRowChangedEvent(update))
+	del := newDebeziumTestRow()
+	del.PreColumns, del.Columns = del.Columns, nil
+	require.Nil(t, encoder.AppendRowChangedEvent(del))
+
+	msgs := encoder.Build()
+	require.Len(t, msgs, 3)
+	require.Nil(t, encoder.Build())
+
+	for i, op := range []string{"c", "u", "d"} {
+		require.Equal(t, 1, msgs[i].GetRowsCount())
+		require.Equal(t, insert.CommitTs, msgs[i].Ts)
+
+		var key debeziumTestMessage
+		require.Nil(t, json.Unmarshal(msgs[i].Key, &key))
+		require.Equal(t, "ticdc.test.t.Key", key.Schema.Name)
+		require.Len(t, key.Schema.Fields, 1)
+		require.JSONEq(t, `{"id":1}`, string(key.Payload))
+
+		var value debeziumTestMessage
+		require.Nil(t, json.Unmarshal(msgs[i].Value, &value))
+		require.Equal(t, "ticdc.test.t.Envelope", value.Schema.Name)
+		var payload debeziumPayload
+		require.Nil(t, json.Unmarshal(value.Payload, &payload))
+		require.Equal(t, op, payload.Op)
+		require.Equal(t, "test", payload.Source.DB)
+		require.Equal(t, "t", payload.Source.Table)
+		require.Equal(t, insert.CommitTs, payload.Source.CommitTs)
+	}
+
+	var value debeziumTestMessage
+	require.Nil(t, json.Unmarshal(msgs[1].Value, &value))
+	rowSchema := value.Schema.Fields[0]
+	require.Equal(t, "before", rowSchema.Field)
+	require.Equal(t, "ticdc.test.t.Value", rowSchema.Name)
+	require.Equal(t, []*debeziumSchema{
+		{Type: "int32", Field: "id"},
+		{Type: "string", Optional: true, Field: "name"},
+		{Type: "string", Optional: true, Field: "price"},
+		{Type: "int32", Optional: true, Name: debeziumDate, Field: "born"},
+		{Type: "string", Optional: true, Name: debeziumZonedTimestamp, Field: "created"},
+		{Type: "string", Optional: true, Name: debeziumEnum, Field: "status"},
+		{Type: "bytes", Optional: true, Field: "note"},
+	}, rowSchema.Fields)
+
+	var payload struct {
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}
+	require.Nil(t, json.Unmarshal(value.Payload, &payload))
+	require.JSONEq(t, `{"id":1,"name":"abd","price":"1.50","born":10,`+
+		`"created":"2022-01-01T00:00:00Z","status":"off","note":null}`, string(payload.Before))
+	require.JSONEq(t, `{"id":1,"name":"abc","price":"1.50","born":10,`+
+		`"created":"2022-01-01T00:00:00Z","status":"off","note":null}`, string(payload.After))
+
+	require.Nil(t, json.Unmarshal(msgs[2].Value, &value))
+	require.Nil(t, json.Unmarshal(value.Payload, &payload))
+	require.Equal(t, "null", string(payload.After))
+}
+
+func TestDebeziumDisableSchema(t *testing.T) {
+	t.Parallel()
+
+	encoder := newDebeziumEventBatchEncoderBuilder(&Config{
+		tz:                    time.UTC,
+		debeziumDisableSchema: true,
+	}).Build()
+	row := newDebeziumTestRow()
+	row.Columns[0].Flag = model.NullableFlag
+	require.Nil(t, encoder.AppendRowChangedEvent(row))
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+	// Tables without handle keys have null keys.
+	require.Nil(t, msgs[0].Key)
+
+	var value map[string]json.RawMessage
+	require.Nil(t, json.Unmarshal(msgs[0].Value, &value))
+	require.NotContains(t, value, "schema")
+	require.Contains(t, value, "payload")
+}
+
+func TestDebeziumColumnData(t *testing.T) {
+	t.Parallel()
+
+	bit := types.NewFieldType(mysql.TypeBit)
+	bit.Flen = 10
+	bool1 := types.NewFieldType(mysql.TypeBit)
+	bool1.Flen = 1
+	datetime := types.NewFieldType(mysql.TypeDatetime)
+	datetime.Decimal = 6
+
+	testCases := []struct {
+		col      *model.Column
+		ft       *types.FieldType
+		typ      string
+		name     string
+		expected interface{}
+	}{
+		{&model.Column{Type: mysql.TypeTiny, Value: int64(-1)}, nil, "int16", "", int64(-1)},
+		{&model.Column{Type: mysql.TypeLonglong, Flag: model.UnsignedFlag, Value: uint64(1)}, nil, "int64", "", int64(1)},
+		{&model.Column{Type: mysql.TypeDouble, Value: 1.5}, nil, "double", "", 1.5},
+		{&model.Column{Type: mysql.TypeDatetime, Value: "1970-01-01 00:00:01"}, nil, "int64", debeziumTimestamp, int64(1000)},
+		{&model.Column{Type: mysql.TypeDatetime, Value: "1970-01-01 00:00:01.000002"}, datetime, "int64", debeziumMicroTimestamp, int64(1000002)},
+		{&model.Column{Type: mysql.TypeDuration, Value: "-01:00:00.5"}, nil, "int64", debeziumMicroTime, int64(-3600500000)},
+		{&model.Column{Type: mysql.TypeYear, Value: int64(2022)}, nil, "int32", debeziumYear, int32(2022)},
+		{&model.Column{Type: mysql.TypeBit, Value: uint64(0x201)}, bit, "bytes", debeziumBits, []byte{0x01, 0x02}},
+		{&model.Column{Type: mysql.TypeBit, Value: uint64(1)}, bool1, "boolean", "", true},
+		{&model.Column{Type: mysql.TypeJSON, Value: `{"a":1}`}, nil, "string", debeziumJSON, `{"a":1}`},
+		{&model.Column{Type: mysql.TypeString, Value: []byte("abc")}, nil, "string", "", "abc"},
+	}
+	for _, tc := range testCases {
+		field, value, err := columnToDebeziumData(tc.col, tc.ft, time.UTC)
+		require.Nil(t, err)
+		require.Equal(t, tc.typ, field.Type)
+		require.Equal(t, tc.name, field.Name)
+		require.Equal(t, tc.expected, value)
+	}
+
+	_, _, err := columnToDebeziumData(&model.Column{Type: mysql.TypeGeometry}, nil, time.UTC)
+	require.Regexp(t, ".*unsupported column type.*", err)
+}
@@ -14,6 +14,7 @@
 package codec
 
 import (
+	"bytes"
 	"encoding/binary"
 	"time"
 
@@ -92,6 +93,22 @@ func newResolvedMQMessage(proto config.Protocol, key, value []byte, ts uint64) *
 	return NewMQMessage(proto, key, value, ts, model.MqMessageTypeResolved, nil, nil)
 }
 
+// endOfStreamKey is the key of the end-of-stream messages, it's the same for
+// all the protocols so a consumer can recognize them before decoding.
+var endOfStreamKey = []byte("ticdc-end-of-stream")
+
+// NewEndOfStreamMessage creates the end-of-stream message of a changefeed
+// removed with the final barrier, no event is sent after it.
+func NewEndOfStreamMessage(proto config.Protocol, value []byte, ts uint64) *MQMessage {
+	return NewMQMessage(proto, endOfStreamKey, value, ts, model.MqMessageTypeEndOfStream, nil, nil)
+}
+
+// IsEndOfStreamMessage returns whether the message with the key is an
+// end-of-stream message.
+func IsEndOfStreamMessage(key []byte) bool {
+	return bytes.Equal(key, endOfStreamKey)
+}
+
 // NewMQMessage should be used when creating a MQMessage struct.
 // It copies the input byte slices to avoid any surprises in asynchronous MQ writes.
 func NewMQMessage(proto config.Protocol, key []byte, value []byte, ts uint64, ty model.MqMessageType, schema, table *string) *MQMessage {
@@ -164,6 +181,10 @@ func NewEventBatchEncoderBuilder(c *Config, credential *security.Credential) (En
 		return newCanalFlatEventBatchEncoderBuilder(c), nil
 	case config.ProtocolCraft:
 		return newCraftEventBatchEncoderBuilder(c), nil
+	case config.ProtocolProtobuf:
+		return newProtobufEventBatchEncoderBuilder(credential, c)
+	case config.ProtocolDebezium:
+		return newDebeziumEventBatchEncoderBuilder(c), nil
 	default:
 		log.Warn("unknown codec protocol value of EventBatchEncoder, use open-protocol as the default", zap.Any("protocolValue", int(c.protocol)))
 		return newJSONEventBatchEncoderBuilder(c), nil
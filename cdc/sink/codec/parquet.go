@@ -0,0 +1,309 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	// ParquetOpColumn is the column of the operation type (I, U or D) of a row in parquet files.
+	ParquetOpColumn = "_tidb_op"
+	// ParquetCommitTsColumn is the column of the commit ts of a row in parquet files.
+	ParquetCommitTsColumn = "_tidb_commit_ts"
+
+	parquetDateLayout     = "2006-01-02"
+	parquetDatetimeLayout = "2006-01-02 15:04:05.999999"
+)
+
+// parquetType is how a TiDB column is stored in parquet.
+type parquetType int
+
+const (
+	parquetInt32 parquetType = iota
+	parquetInt64
+	parquetUint64
+	parquetDouble
+	parquetString
+	parquetBinary
+	parquetDate
+	parquetDatetime
+	parquetTimestamp
+)
+
+// errParquetUnrepresentable means the value can't be represented in parquet,
+// e.g. zero dates, it's written as NULL.
+var errParquetUnrepresentable = errors.New("value can't be represented in parquet")
+
+// parquetTypeOf maps a TiDB column type to its parquet type.
+// Decimal, time, JSON and other textual values are stored as UTF8 strings,
+// enum, set and bit values are stored as their integer representations.
+func parquetTypeOf(col *model.Column) parquetType {
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeYear:
+		return parquetInt32
+	case mysql.TypeLong:
+		if col.Flag.IsUnsigned() {
+			return parquetInt64
+		}
+		return parquetInt32
+	case mysql.TypeLonglong:
+		if col.Flag.IsUnsigned() {
+			return parquetUint64
+		}
+		return parquetInt64
+	case mysql.TypeEnum, mysql.TypeSet, mysql.TypeBit:
+		return parquetUint64
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return parquetDouble
+	case mysql.TypeDate, mysql.TypeNewDate:
+		return parquetDate
+	case mysql.TypeDatetime:
+		return parquetDatetime
+	case mysql.TypeTimestamp:
+		return parquetTimestamp
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if col.Flag.IsBinary() {
+			return parquetBinary
+		}
+		return parquetString
+	}
+	return parquetString
+}
+
+// tag returns the parquet-go schema tag of a column of the type.
+func (t parquetType) tag(name string) string {
+	var typ string
+	switch t {
+	case parquetInt32:
+		typ = "type=INT32"
+	case parquetInt64:
+		typ = "type=INT64"
+	case parquetUint64:
+		typ = "type=INT64, convertedtype=UINT_64"
+	case parquetDouble:
+		typ = "type=DOUBLE"
+	case parquetBinary:
+		typ = "type=BYTE_ARRAY"
+	case parquetDate:
+		typ = "type=INT32, convertedtype=DATE"
+	case parquetDatetime, parquetTimestamp:
+		typ = "type=INT64, convertedtype=TIMESTAMP_MICROS"
+	default:
+		typ = "type=BYTE_ARRAY, convertedtype=UTF8"
+	}
+	return fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", name, typ)
+}
+
+// value converts a column value to the go type parquet-go expects. Datetime
+// values are stored as if they were in UTC, while timestamp values are in the
+// timezone loc of the changefeed and stored as UTC instants.
+// errParquetUnrepresentable is returned for zero dates.
+func (t parquetType) value(v interface{}, loc *time.Location) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch t {
+	case parquetInt32:
+		n, err := parquetInt(v)
+		if err != nil {
+			return nil, err
+		}
+		return int32(n), nil
+	case parquetInt64, parquetUint64:
+		return parquetInt(v)
+	case parquetDouble:
+		switch f := v.(type) {
+		case float64:
+			return f, nil
+		case float32:
+			return float64(f), nil
+		}
+		f, err := strconv.ParseFloat(parquetString(v), 64)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return f, nil
+	case parquetDate:
+		d, err := parquetParseTime(parquetDateLayout, parquetString(v), time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		return int32(d.Unix() / 86400), nil
+	case parquetDatetime, parquetTimestamp:
+		if t == parquetDatetime {
+			loc = time.UTC
+		}
+		ts, err := parquetParseTime(parquetDatetimeLayout, parquetString(v), loc)
+		if err != nil {
+			return nil, err
+		}
+		return ts.UnixMicro(), nil
+	}
+	return parquetString(v), nil
+}
+
+// parquetParseTime parses a date or datetime string, dates with zero month or
+// day are unrepresentable.
+func parquetParseTime(layout, s string, loc *time.Location) (time.Time, error) {
+	if len(s) >= len(parquetDateLayout) && (s[5:7] == "00" || s[8:10] == "00") {
+		return time.Time{}, errParquetUnrepresentable
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	return t, errors.Trace(err)
+}
+
+func parquetInt(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case uint64:
+		// unsigned bigint is stored with UINT_64 annotation, keep the bits.
+		return int64(n), nil
+	case int:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	}
+	n, err := strconv.ParseInt(parquetString(v), 10, 64)
+	return n, errors.Trace(err)
+}
+
+func parquetString(v interface{}) string {
+	switch s := v.(type) {
+	case []byte:
+		return string(s)
+	case string:
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// parquetFile is an in memory write only source.ParquetFile.
+type parquetFile struct {
+	bytes.Buffer
+}
+
+func (f *parquetFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("seek is not supported by parquet buffer")
+}
+
+func (f *parquetFile) Close() error {
+	return nil
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	return nil, errors.New("open is not supported by parquet buffer")
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return &parquetFile{}, nil
+}
+
+// EncodeParquet encodes rows into a snappy compressed parquet file. The schema
+// is derived from the columns of the first row, so all rows must be of the
+// same table info version; callers should start a new file when the schema
+// of a table evolves.
+// Besides the table columns, the operation type and the commit ts of every
+// row are stored in ParquetOpColumn and ParquetCommitTsColumn.
+// Timestamp values are parsed in loc, the timezone of the changefeed. Values
+// which can't be represented in parquet, like zero dates, are written as NULL
+// and their number is returned, other values failing to convert fail the
+// encoding.
+func EncodeParquet(rows []*model.RowChangedEvent, loc *time.Location) ([]byte, int, error) {
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	columns := rows[0].Columns
+	if len(columns) == 0 {
+		columns = rows[0].PreColumns
+	}
+	types := make([]parquetType, 0, len(columns))
+	schema := []string{
+		fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8", ParquetOpColumn),
+		fmt.Sprintf("name=%s, type=INT64, convertedtype=UINT_64", ParquetCommitTsColumn),
+	}
+	for _, col := range columns {
+		if col == nil {
+			continue
+		}
+		// parquet-go parses the schema tags by commas and equal signs.
+		if col.Name == "" || strings.ContainsAny(col.Name, ",=") ||
+			strings.TrimSpace(col.Name) != col.Name {
+			return nil, 0, errors.Errorf("column name %q of %s can't be used in parquet", col.Name, rows[0].Table)
+		}
+		t := parquetTypeOf(col)
+		types = append(types, t)
+		schema = append(schema, t.tag(col.Name))
+	}
+
+	file := &parquetFile{}
+	w, err := writer.NewCSVWriter(schema, file, 1)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	unrepresentable := 0
+	w.CompressionType = parquet.CompressionCodec_SNAPPY
+	for _, row := range rows {
+		op, cols := "I", row.Columns
+		if row.IsDelete() {
+			op, cols = "D", row.PreColumns
+		} else if len(row.PreColumns) != 0 {
+			op = "U"
+		}
+		record := make([]interface{}, 0, len(schema))
+		record = append(record, op, int64(row.CommitTs))
+		idx := 0
+		for _, col := range cols {
+			if col == nil {
+				continue
+			}
+			if idx >= len(types) {
+				return nil, 0, errors.Errorf("row of %s has more columns than the parquet schema", row.Table)
+			}
+			value, err := types[idx].value(col.Value, loc)
+			if err == errParquetUnrepresentable {
+				unrepresentable++
+			} else if err != nil {
+				return nil, 0, errors.Annotatef(err, "convert column %s of %s", col.Name, row.Table)
+			}
+			record = append(record, value)
+			idx++
+		}
+		if idx != len(types) {
+			return nil, 0, errors.Errorf("row of %s has less columns than the parquet schema", row.Table)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+	}
+	if err := w.WriteStop(); err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	return file.Bytes(), unrepresentable, nil
+}
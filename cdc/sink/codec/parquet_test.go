@@ -0,0 +1,172 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// parquetReadFile is an in memory read only source.ParquetFile for tests.
+type parquetReadFile struct {
+	*bytes.Reader
+	data []byte
+}
+
+func (f *parquetReadFile) Write(p []byte) (int, error) { return 0, nil }
+
+func (f *parquetReadFile) Close() error { return nil }
+
+func (f *parquetReadFile) Open(name string) (source.ParquetFile, error) {
+	return &parquetReadFile{Reader: bytes.NewReader(f.data), data: f.data}, nil
+}
+
+func (f *parquetReadFile) Create(name string) (source.ParquetFile, error) { return nil, nil }
+
+func TestParquetTypeMapping(t *testing.T) {
+	t.Parallel()
+
+	unsigned := model.UnsignedFlag
+	binary := model.BinaryFlag
+	cases := []struct {
+		col      *model.Column
+		expected parquetType
+		value    interface{}
+	}{
+		{&model.Column{Type: mysql.TypeLong, Value: int64(-1)}, parquetInt32, int32(-1)},
+		{&model.Column{Type: mysql.TypeLong, Flag: unsigned, Value: uint64(1 << 31)}, parquetInt64, int64(1 << 31)},
+		{&model.Column{Type: mysql.TypeLonglong, Flag: unsigned, Value: uint64(1<<64 - 1)}, parquetUint64, int64(-1)},
+		{&model.Column{Type: mysql.TypeDouble, Value: 1.5}, parquetDouble, 1.5},
+		{&model.Column{Type: mysql.TypeNewDecimal, Value: "3.14"}, parquetString, "3.14"},
+		{&model.Column{Type: mysql.TypeVarchar, Value: []byte("abc")}, parquetString, "abc"},
+		{&model.Column{Type: mysql.TypeBlob, Flag: binary, Value: []byte{0x1}}, parquetBinary, "\x01"},
+		{&model.Column{Type: mysql.TypeDate, Value: "1970-01-02"}, parquetDate, int32(1)},
+		{&model.Column{Type: mysql.TypeDatetime, Value: "1970-01-01 00:00:01.5"}, parquetDatetime, int64(1500000)},
+		// timestamps are in the timezone of the changefeed.
+		{&model.Column{Type: mysql.TypeTimestamp, Value: "1970-01-01 08:00:01"}, parquetTimestamp, int64(1000000)},
+		{&model.Column{Type: mysql.TypeEnum, Value: uint64(2)}, parquetUint64, int64(2)},
+		{&model.Column{Type: mysql.TypeLong, Value: nil}, parquetInt32, nil},
+	}
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.Nil(t, err)
+	for _, cs := range cases {
+		typ := parquetTypeOf(cs.col)
+		require.Equal(t, cs.expected, typ, "%+v", cs.col)
+		value, err := typ.value(cs.col.Value, loc)
+		require.Nil(t, err, "%+v", cs.col)
+		require.Equal(t, cs.value, value, "%+v", cs.col)
+	}
+
+	// zero dates can't be represented, other invalid values fail.
+	_, err = parquetDate.value("0000-00-00", loc)
+	require.Equal(t, errParquetUnrepresentable, err)
+	_, err = parquetDatetime.value("2022-01-00 00:00:00", loc)
+	require.Equal(t, errParquetUnrepresentable, err)
+	_, err = parquetInt32.value("abc", loc)
+	require.NotNil(t, err)
+	_, err = parquetDate.value("2022/01/01", loc)
+	require.NotNil(t, err)
+}
+
+func TestEncodeParquet(t *testing.T) {
+	t.Parallel()
+
+	data, unrepresentable, err := EncodeParquet(nil, time.UTC)
+	require.Nil(t, err)
+	require.Nil(t, data)
+	require.Equal(t, 0, unrepresentable)
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	rows := []*model.RowChangedEvent{
+		{
+			CommitTs: 1,
+			Table:    table,
+			Columns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+				{Name: "name", Type: mysql.TypeVarchar, Value: []byte("a")},
+				{Name: "birthday", Type: mysql.TypeDate, Value: "0000-00-00"},
+			},
+		},
+		{
+			CommitTs: 2,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+				{Name: "name", Type: mysql.TypeVarchar, Value: []byte("a")},
+				{Name: "birthday", Type: mysql.TypeDate, Value: "0000-00-00"},
+			},
+			Columns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+				{Name: "name", Type: mysql.TypeVarchar, Value: nil},
+				{Name: "birthday", Type: mysql.TypeDate, Value: "1970-01-02"},
+			},
+		},
+		{
+			CommitTs: 3,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+				{Name: "name", Type: mysql.TypeVarchar, Value: nil},
+				{Name: "birthday", Type: mysql.TypeDate, Value: nil},
+			},
+		},
+	}
+	data, unrepresentable, err = EncodeParquet(rows, time.UTC)
+	require.Nil(t, err)
+	require.Equal(t, 1, unrepresentable)
+	require.Equal(t, []byte("PAR1"), data[:4])
+	require.Equal(t, []byte("PAR1"), data[len(data)-4:])
+
+	pr, err := reader.NewParquetColumnReader(&parquetReadFile{Reader: bytes.NewReader(data), data: data}, 1)
+	require.Nil(t, err)
+	defer pr.ReadStop()
+	require.EqualValues(t, 3, pr.GetNumRows())
+
+	ops, _, _, err := pr.ReadColumnByIndex(0, 3)
+	require.Nil(t, err)
+	require.Equal(t, []interface{}{"I", "U", "D"}, ops)
+	ids, _, _, err := pr.ReadColumnByIndex(2, 3)
+	require.Nil(t, err)
+	require.Equal(t, []interface{}{int32(1), int32(1), int32(1)}, ids)
+	names, _, _, err := pr.ReadColumnByIndex(3, 3)
+	require.Nil(t, err)
+	require.Equal(t, []interface{}{"a", nil, nil}, names)
+	birthdays, _, _, err := pr.ReadColumnByIndex(4, 3)
+	require.Nil(t, err)
+	require.Equal(t, []interface{}{nil, int32(1), nil}, birthdays)
+
+	// values which fail to convert fail the encoding.
+	rows[2].PreColumns[0].Value = "abc"
+	_, _, err = EncodeParquet(rows, time.UTC)
+	require.Regexp(t, ".*convert column id.*", err)
+	rows[2].PreColumns[0].Value = int64(1)
+
+	// column names which break the parquet schema are rejected.
+	rows[0].Columns[1].Name = "a,b"
+	_, _, err = EncodeParquet(rows, time.UTC)
+	require.Regexp(t, ".*can't be used in parquet.*", err)
+	rows[0].Columns[1].Name = "name"
+
+	// rows of a different schema can't be put into the same file.
+	rows[2].PreColumns = rows[2].PreColumns[:1]
+	_, _, err = EncodeParquet(rows, time.UTC)
+	require.Regexp(t, ".*less columns than the parquet schema.*", err)
+}
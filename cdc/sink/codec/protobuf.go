@@ -0,0 +1,512 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	protobufSchemaType = "PROTOBUF"
+
+	protobufKeyMessageSuffix   = "Key"
+	protobufValueMessageSuffix = "Value"
+)
+
+// ProtobufSchemaManager generates protobuf schemas of tables, registers them
+// to the Registry server and caches the designated IDs by table versions.
+type ProtobufSchemaManager struct {
+	registryURL   string
+	subjectSuffix string
+	strategy      SubjectNameStrategy
+	topicResolver func(table *model.TableName) string
+
+	credential *security.Credential
+
+	cacheRWLock sync.RWMutex
+	cache       map[string]*protobufSchemaCacheEntry
+}
+
+type protobufSchemaCacheEntry struct {
+	tiSchemaID uint64
+	registryID int
+}
+
+// NewProtobufSchemaManager creates a new ProtobufSchemaManager
+func NewProtobufSchemaManager(
+	ctx context.Context, credential *security.Credential, registryURL string, subjectSuffix string,
+	strategy SubjectNameStrategy, topicResolver func(table *model.TableName) string,
+) (*ProtobufSchemaManager, error) {
+	if strategy.needTopic() && topicResolver == nil {
+		return nil, cerror.ErrMQCodecInvalidConfig.GenWithStack(
+			"subject-name-strategy %s is not supported by this sink", strategy)
+	}
+	registryURL = strings.TrimRight(registryURL, "/")
+	if err := testRegistryConnectivity(ctx, credential, registryURL); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &ProtobufSchemaManager{
+		registryURL:   registryURL,
+		subjectSuffix: subjectSuffix,
+		strategy:      strategy,
+		topicResolver: topicResolver,
+		credential:    credential,
+		cache:         make(map[string]*protobufSchemaCacheEntry),
+	}, nil
+}
+
+// subject returns the subject the schema of the table is registered under.
+func (m *ProtobufSchemaManager) subject(table *model.TableName, recordName string) string {
+	switch m.strategy {
+	case SubjectNameStrategyTopic:
+		return m.topicResolver(table) + m.subjectSuffix
+	case SubjectNameStrategyRecord:
+		return recordName
+	case SubjectNameStrategyTopicRecord:
+		return m.topicResolver(table) + "-" + recordName
+	default:
+		return table.Schema + "_" + table.Table + m.subjectSuffix
+	}
+}
+
+// GetCachedOrRegister returns the Registry designated ID of the table schema
+// with the given version, the schema is generated and registered when the
+// version is not cached.
+func (m *ProtobufSchemaManager) GetCachedOrRegister(
+	ctx context.Context, table *model.TableName, tiSchemaID uint64, message *protobufMessage,
+) (int, error) {
+	m.cacheRWLock.RLock()
+	if entry, exists := m.cache[table.QuoteString()]; exists && entry.tiSchemaID == tiSchemaID {
+		m.cacheRWLock.RUnlock()
+		return entry.registryID, nil
+	}
+	m.cacheRWLock.RUnlock()
+
+	subject := m.subject(table, message.recordName())
+	log.Info("Protobuf schema lookup cache miss",
+		zap.String("table", table.String()),
+		zap.String("subject", subject),
+		zap.Uint64("tiSchemaID", tiSchemaID))
+
+	id, err := registerSchema(ctx, m.credential, m.registryURL, subject, message.schema(), protobufSchemaType)
+	if err != nil {
+		return 0, errors.Annotate(err, "GetCachedOrRegister: Could not register schema")
+	}
+
+	m.cacheRWLock.Lock()
+	m.cache[table.QuoteString()] = &protobufSchemaCacheEntry{tiSchemaID: tiSchemaID, registryID: id}
+	m.cacheRWLock.Unlock()
+	return id, nil
+}
+
+// Evict drops the cached schema of the table, the schema is registered
+// again by the next row of the table.
+func (m *ProtobufSchemaManager) Evict(table *model.TableName) {
+	m.cacheRWLock.Lock()
+	delete(m.cache, table.QuoteString())
+	m.cacheRWLock.Unlock()
+}
+
+// ProtobufEventBatchEncoder converts the events to protobuf messages framed
+// by the Confluent wire format.
+type ProtobufEventBatchEncoder struct {
+	keySchemaManager   *ProtobufSchemaManager
+	valueSchemaManager *ProtobufSchemaManager
+	resultBuf          []*MQMessage
+}
+
+// AppendRowChangedEvent appends a row change event to the encoder
+func (p *ProtobufEventBatchEncoder) AppendRowChangedEvent(e *model.RowChangedEvent) error {
+	mqMessage := NewMQMessage(config.ProtocolProtobuf, nil, nil, e.CommitTs, model.MqMessageTypeRow, &e.Table.Schema, &e.Table.Table)
+
+	// TODO pass ctx from the upper function. Need to modify the EventBatchEncoder interface.
+	ctx := context.Background()
+	if !e.IsDelete() {
+		value, err := protobufEncode(ctx, e, p.valueSchemaManager, protobufValueMessageSuffix, false)
+		if err != nil {
+			return errors.Annotate(err, "AppendRowChangedEvent could not encode to Protobuf")
+		}
+		mqMessage.Value = value
+	}
+
+	key, err := protobufEncode(ctx, e, p.keySchemaManager, protobufKeyMessageSuffix, true)
+	if err != nil {
+		return errors.Annotate(err, "AppendRowChangedEvent could not encode to Protobuf")
+	}
+	mqMessage.Key = key
+	mqMessage.IncRowsCount()
+	p.resultBuf = append(p.resultBuf, mqMessage)
+	return nil
+}
+
+// EncodeCheckpointEvent is no-op for now
+func (p *ProtobufEventBatchEncoder) EncodeCheckpointEvent(ts uint64) (*MQMessage, error) {
+	return nil, nil
+}
+
+// EncodeDDLEvent does not send anything, it evicts the cached schemas of the
+// table so that the evolved schema is registered with the next row.
+func (p *ProtobufEventBatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*MQMessage, error) {
+	for _, info := range []*model.SimpleTableInfo{e.PreTableInfo, e.TableInfo} {
+		if info == nil || info.Table == "" {
+			continue
+		}
+		table := &model.TableName{Schema: info.Schema, Table: info.Table}
+		p.keySchemaManager.Evict(table)
+		p.valueSchemaManager.Evict(table)
+	}
+	return nil, nil
+}
+
+// Build MQ Messages
+func (p *ProtobufEventBatchEncoder) Build() (mqMessages []*MQMessage) {
+	old := p.resultBuf
+	p.resultBuf = nil
+	return old
+}
+
+// Size is the current size of resultBuf
+func (p *ProtobufEventBatchEncoder) Size() int {
+	sum := 0
+	for _, msg := range p.resultBuf {
+		sum += len(msg.Key)
+		sum += len(msg.Value)
+	}
+	return sum
+}
+
+// protobufEncode encodes the row, or its handle key columns if onlyHandleKey
+// is set, into the Confluent wire format: a magic byte, the big endian schema
+// ID, the message indexes and the protobuf message.
+func protobufEncode(
+	ctx context.Context, e *model.RowChangedEvent, manager *ProtobufSchemaManager,
+	messageSuffix string, onlyHandleKey bool,
+) ([]byte, error) {
+	message, err := newProtobufMessage(e, messageSuffix, onlyHandleKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	registryID, err := manager.GetCachedOrRegister(ctx, e.Table, e.TableInfoVersion, message)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	buf := make([]byte, 5, 64)
+	buf[0] = magicByte
+	buf[1] = byte(registryID >> 24)
+	buf[2] = byte(registryID >> 16)
+	buf[3] = byte(registryID >> 8)
+	buf[4] = byte(registryID)
+	// The schema holds a single message, whose index list [0] is encoded
+	// as a single zero byte.
+	buf = append(buf, 0)
+	return message.appendTo(buf)
+}
+
+// protobufMessage is a protobuf message generated from the columns of a row.
+// Fields are numbered by the column IDs, which are never reused by TiDB, so
+// the schema evolves compatibly when columns are added or dropped.
+type protobufMessage struct {
+	pkg    string
+	name   string
+	fields []*protobufField
+}
+
+type protobufField struct {
+	name     string
+	tp       string
+	number   protowire.Number
+	optional bool
+
+	col *model.Column
+	ft  *types.FieldType
+}
+
+func newProtobufMessage(e *model.RowChangedEvent, messageSuffix string, onlyHandleKey bool) (*protobufMessage, error) {
+	m := &protobufMessage{
+		pkg:  protobufIdentifier(e.Table.Schema),
+		name: protobufIdentifier(e.Table.Table) + messageSuffix,
+	}
+	cols := e.Columns
+	if e.IsDelete() {
+		cols = e.PreColumns
+	}
+	names := make(map[string]struct{}, len(cols))
+	for i, col := range cols {
+		if col == nil || (onlyHandleKey && !col.Flag.IsHandleKey()) {
+			continue
+		}
+		var colInfo rowcodec.ColInfo
+		if i < len(e.ColInfos) {
+			colInfo = e.ColInfos[i]
+		}
+		number := protowire.Number(colInfo.ID)
+		if colInfo.ID == 0 {
+			number = protowire.Number(i + 1)
+		}
+		if !number.IsValid() {
+			return nil, cerror.ErrProtobufEncodeFailed.GenWithStack(
+				"column %s of %s has an invalid field number %d", col.Name, e.Table, number)
+		}
+		tp, err := protobufTypeOf(col)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		name := protobufIdentifier(col.Name)
+		if _, ok := names[name]; ok {
+			name = fmt.Sprintf("%s_%d", name, number)
+		}
+		names[name] = struct{}{}
+		m.fields = append(m.fields, &protobufField{
+			name:     name,
+			tp:       tp,
+			number:   number,
+			optional: col.Flag.IsNullable(),
+			col:      col,
+			ft:       colInfo.Ft,
+		})
+	}
+	return m, nil
+}
+
+func (m *protobufMessage) recordName() string {
+	return m.pkg + "." + m.name
+}
+
+// schema returns the proto3 definition of the message.
+func (m *protobufMessage) schema() string {
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", m.pkg)
+	fmt.Fprintf(&b, "message %s {\n", m.name)
+	for _, f := range m.fields {
+		b.WriteString("  ")
+		if f.optional {
+			b.WriteString("optional ")
+		}
+		fmt.Fprintf(&b, "%s %s = %d;\n", f.tp, f.name, f.number)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// appendTo appends the protobuf encoding of the message to buf,
+// NULL values are left unset.
+func (m *protobufMessage) appendTo(buf []byte) ([]byte, error) {
+	for _, f := range m.fields {
+		v := f.col.Value
+		if v == nil {
+			continue
+		}
+		switch f.tp {
+		case "int32", "int64", "uint32", "uint64":
+			n, err := protobufVarint(f.col)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			buf = protowire.AppendTag(buf, f.number, protowire.VarintType)
+			buf = protowire.AppendVarint(buf, n)
+		case "float":
+			var x float32
+			switch val := v.(type) {
+			case float32:
+				x = val
+			case float64:
+				x = float32(val)
+			default:
+				return nil, cerror.ErrProtobufUnknownType.GenWithStackByArgs(v)
+			}
+			buf = protowire.AppendTag(buf, f.number, protowire.Fixed32Type)
+			buf = protowire.AppendFixed32(buf, math.Float32bits(x))
+		case "double":
+			var x float64
+			switch val := v.(type) {
+			case float32:
+				x = float64(val)
+			case float64:
+				x = val
+			default:
+				return nil, cerror.ErrProtobufUnknownType.GenWithStackByArgs(v)
+			}
+			buf = protowire.AppendTag(buf, f.number, protowire.Fixed64Type)
+			buf = protowire.AppendFixed64(buf, math.Float64bits(x))
+		default:
+			data, err := protobufBytes(f.col, f.ft)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			buf = protowire.AppendTag(buf, f.number, protowire.BytesType)
+			buf = protowire.AppendBytes(buf, data)
+		}
+	}
+	return buf, nil
+}
+
+// protobufTypeOf maps a TiDB column type to its protobuf scalar type.
+// Decimal, time and JSON values are sent as strings, enum and set values are
+// sent as their names.
+func protobufTypeOf(col *model.Column) (string, error) {
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong:
+		if col.Flag.IsUnsigned() {
+			return "uint32", nil
+		}
+		return "int32", nil
+	case mysql.TypeLonglong:
+		if col.Flag.IsUnsigned() {
+			return "uint64", nil
+		}
+		return "int64", nil
+	case mysql.TypeYear:
+		return "int32", nil
+	case mysql.TypeBit:
+		return "uint64", nil
+	case mysql.TypeFloat:
+		return "float", nil
+	case mysql.TypeDouble:
+		return "double", nil
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if col.Flag.IsBinary() {
+			return "bytes", nil
+		}
+		return "string", nil
+	case mysql.TypeNewDecimal, mysql.TypeJSON, mysql.TypeEnum, mysql.TypeSet,
+		mysql.TypeDate, mysql.TypeNewDate, mysql.TypeDatetime, mysql.TypeTimestamp,
+		mysql.TypeDuration, mysql.TypeNull:
+		return "string", nil
+	default:
+		return "", cerror.ErrProtobufUnknownType.GenWithStackByArgs(col.Type)
+	}
+}
+
+// protobufVarint returns the varint representation of an integer column,
+// negative values are sign extended as protobuf does for int32 and int64.
+func protobufVarint(col *model.Column) (uint64, error) {
+	switch v := col.Value.(type) {
+	case int64:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case int:
+		return uint64(v), nil
+	case int32:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	}
+	return 0, cerror.ErrProtobufUnknownType.GenWithStackByArgs(col.Value)
+}
+
+// protobufBytes returns the content of a string or bytes column.
+func protobufBytes(col *model.Column, ft *types.FieldType) ([]byte, error) {
+	switch col.Type {
+	case mysql.TypeEnum, mysql.TypeSet:
+		n, ok := col.Value.(uint64)
+		if !ok || ft == nil {
+			return nil, cerror.ErrProtobufUnknownType.GenWithStackByArgs(col.Value)
+		}
+		if col.Type == mysql.TypeEnum {
+			enum, err := types.ParseEnumValue(ft.Elems, n)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrProtobufEncodeFailed, err)
+			}
+			return []byte(enum.Name), nil
+		}
+		set, err := types.ParseSetValue(ft.Elems, n)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrProtobufEncodeFailed, err)
+		}
+		return []byte(set.Name), nil
+	}
+	switch v := col.Value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return []byte(fmt.Sprintf("%v", col.Value)), nil
+}
+
+// protobufIdentifier replaces characters not allowed in protobuf identifiers
+// with underscores.
+func protobufIdentifier(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+type protobufEventBatchEncoderBuilder struct {
+	keySchemaManager   *ProtobufSchemaManager
+	valueSchemaManager *ProtobufSchemaManager
+}
+
+func newProtobufEventBatchEncoderBuilder(credential *security.Credential, config *Config) (EncoderBuilder, error) {
+	ctx := context.Background()
+	keySchemaManager, err := NewProtobufSchemaManager(ctx, credential, config.avroRegistry,
+		keySchemaSuffix, config.subjectNameStrategy, config.topicResolver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	valueSchemaManager, err := NewProtobufSchemaManager(ctx, credential, config.avroRegistry,
+		valueSchemaSuffix, config.subjectNameStrategy, config.topicResolver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &protobufEventBatchEncoderBuilder{
+		keySchemaManager:   keySchemaManager,
+		valueSchemaManager: valueSchemaManager,
+	}, nil
+}
+
+// Build a ProtobufEventBatchEncoder.
+func (b *protobufEventBatchEncoderBuilder) Build() EventBatchEncoder {
+	return &ProtobufEventBatchEncoder{
+		keySchemaManager:   b.keySchemaManager,
+		valueSchemaManager: b.valueSchemaManager,
+	}
+}
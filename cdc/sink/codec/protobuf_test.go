@@ -0,0 +1,223 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func newProtobufTestRow() *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		CommitTs:         417318403368288260,
+		Table:            &model.TableName{Schema: "test", Table: "t-1"},
+		TableInfoVersion: 1,
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: int64(-1)},
+			{Name: "name", Type: mysql.TypeVarchar, Flag: model.NullableFlag, Value: "abc"},
+			{Name: "score", Type: mysql.TypeDouble, Flag: model.NullableFlag, Value: 1.5},
+			{Name: "status", Type: mysql.TypeEnum, Flag: model.NullableFlag, Value: uint64(2)},
+			{Name: "note", Type: mysql.TypeBlob, Flag: model.NullableFlag | model.BinaryFlag, Value: nil},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, Ft: types.NewFieldType(mysql.TypeLonglong)},
+			{ID: 3, Ft: types.NewFieldType(mysql.TypeVarchar)},
+			{ID: 4, Ft: types.NewFieldType(mysql.TypeDouble)},
+			{ID: 5, Ft: setElems(types.NewFieldType(mysql.TypeEnum), []string{"on", "off"})},
+			{ID: 7, Ft: types.NewFieldType(mysql.TypeBlob)},
+		},
+	}
+}
+
+func TestProtobufSchema(t *testing.T) {
+	t.Parallel()
+
+	row := newProtobufTestRow()
+	value, err := newProtobufMessage(row, protobufValueMessageSuffix, false)
+	require.Nil(t, err)
+	require.Equal(t, "test.t_1Value", value.recordName())
+	require.Equal(t, `syntax = "proto3";
+
+package test;
+
+message t_1Value {
+  int64 id = 1;
+  optional string name = 3;
+  optional double score = 4;
+  optional string status = 5;
+  optional bytes note = 7;
+}
+`, value.schema())
+
+	key, err := newProtobufMessage(row, protobufKeyMessageSuffix, true)
+	require.Nil(t, err)
+	require.Equal(t, `syntax = "proto3";
+
+package test;
+
+message t_1Key {
+  int64 id = 1;
+}
+`, key.schema())
+
+	require.Equal(t, "_1a_b", protobufIdentifier("1a b"))
+	require.Equal(t, "_", protobufIdentifier(""))
+}
+
+type protobufTestRegistry struct {
+	subjects    []string
+	schemaTypes []string
+}
+
+func startProtobufTestRegistry(t *testing.T) *protobufTestRegistry {
+	httpmock.Activate()
+	t.Cleanup(httpmock.DeactivateAndReset)
+
+	registry := &protobufTestRegistry{}
+	httpmock.RegisterResponder("GET", "http://127.0.0.1:8081", httpmock.NewStringResponder(200, "{}"))
+	httpmock.RegisterResponder("POST", `=~^http://127.0.0.1:8081/subjects/(.+)/versions`,
+		func(req *http.Request) (*http.Response, error) {
+			subject, err := httpmock.GetSubmatch(req, 1)
+			if err != nil {
+				return nil, err
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			var reqData registerRequest
+			if err := json.Unmarshal(body, &reqData); err != nil {
+				return nil, err
+			}
+			registry.subjects = append(registry.subjects, subject)
+			registry.schemaTypes = append(registry.schemaTypes, reqData.SchemaType)
+			return httpmock.NewJsonResponse(200, &registerResponse{ID: len(registry.subjects)})
+		})
+	return registry
+}
+
+func TestProtobufEncode(t *testing.T) {
+	registry := startProtobufTestRegistry(t)
+
+	config := NewConfig(0, nil)
+	config.avroRegistry = "http://127.0.0.1:8081"
+	builder, err := newProtobufEventBatchEncoderBuilder(&security.Credential{}, config)
+	require.Nil(t, err)
+	encoder := builder.Build()
+
+	row := newProtobufTestRow()
+	require.Nil(t, encoder.AppendRowChangedEvent(row))
+	require.Nil(t, encoder.AppendRowChangedEvent(row))
+	require.Equal(t, []string{"test_t-1-value", "test_t-1-key"}, registry.subjects)
+	require.Equal(t, []string{"PROTOBUF", "PROTOBUF"}, registry.schemaTypes)
+
+	messages := encoder.Build()
+	require.Len(t, messages, 2)
+	value := messages[0].Value
+	require.Equal(t, byte(0), value[0])
+	require.Equal(t, uint32(1), binary.BigEndian.Uint32(value[1:5]))
+	require.Equal(t, byte(0), value[5])
+
+	fields := make(map[protowire.Number]interface{})
+	data := value[6:]
+	for len(data) > 0 {
+		num, tp, n := protowire.ConsumeTag(data)
+		require.Greater(t, n, 0)
+		data = data[n:]
+		switch tp {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			fields[num] = int64(v)
+			data = data[n:]
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(data)
+			fields[num] = v
+			data = data[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			fields[num] = string(v)
+			data = data[n:]
+		}
+	}
+	require.Equal(t, map[protowire.Number]interface{}{
+		1: int64(-1),
+		3: "abc",
+		4: uint64(0x3ff8000000000000),
+		5: "off",
+	}, fields)
+	require.Equal(t, uint32(2), binary.BigEndian.Uint32(messages[0].Key[1:5]))
+
+	// The table version changes after a DDL, the evolved schema is registered.
+	row.TableInfoVersion = 2
+	row.Columns = row.Columns[:2]
+	row.ColInfos = row.ColInfos[:2]
+	require.Nil(t, encoder.AppendRowChangedEvent(row))
+	require.Len(t, registry.subjects, 4)
+
+	// Deletes only carry keys.
+	row.PreColumns, row.Columns = row.Columns, nil
+	require.Nil(t, encoder.AppendRowChangedEvent(row))
+	require.Len(t, registry.subjects, 4)
+	messages = encoder.Build()
+	require.Nil(t, messages[1].Value)
+	require.NotNil(t, messages[1].Key)
+
+	// DDLs evict the cached schemas of the table.
+	_, err = encoder.EncodeDDLEvent(&model.DDLEvent{
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t-1"},
+	})
+	require.Nil(t, err)
+	require.Nil(t, encoder.AppendRowChangedEvent(row))
+	require.Len(t, registry.subjects, 5)
+}
+
+func TestProtobufSubjectNameStrategy(t *testing.T) {
+	startProtobufTestRegistry(t)
+
+	ctx := context.Background()
+	table := &model.TableName{Schema: "test", Table: "t"}
+	topicResolver := func(table *model.TableName) string { return "topic_" + table.Table }
+	cases := []struct {
+		strategy SubjectNameStrategy
+		expected string
+	}{
+		{SubjectNameStrategyTable, "test_t-value"},
+		{SubjectNameStrategyTopic, "topic_t-value"},
+		{SubjectNameStrategyRecord, "test.tValue"},
+		{SubjectNameStrategyTopicRecord, "topic_t-test.tValue"},
+	}
+	for _, cs := range cases {
+		m, err := NewProtobufSchemaManager(ctx, &security.Credential{}, "http://127.0.0.1:8081",
+			valueSchemaSuffix, cs.strategy, topicResolver)
+		require.Nil(t, err)
+		require.Equal(t, cs.expected, m.subject(table, "test.tValue"))
+	}
+
+	_, err := NewProtobufSchemaManager(ctx, &security.Credential{}, "http://127.0.0.1:8081",
+		valueSchemaSuffix, SubjectNameStrategyTopic, nil)
+	require.Error(t, err)
+}
@@ -57,8 +57,8 @@ type schemaCacheEntry struct {
 
 type registerRequest struct {
 	Schema string `json:"schema"`
-	// Commented out for compatibility with Confluent 5.4.x
-	// SchemaType string `json:"schemaType"`
+	// Omitted when empty for compatibility with Confluent 5.4.x
+	SchemaType string `json:"schemaType,omitempty"`
 }
 
 type registerResponse struct {
@@ -76,40 +76,47 @@ func NewAvroSchemaManager(
 	ctx context.Context, credential *security.Credential, registryURL string, subjectSuffix string,
 ) (*AvroSchemaManager, error) {
 	registryURL = strings.TrimRight(registryURL, "/")
-	// Test connectivity to the Schema Registry
+	if err := testRegistryConnectivity(ctx, credential, registryURL); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &AvroSchemaManager{
+		registryURL:   registryURL,
+		cache:         make(map[string]*schemaCacheEntry, 1),
+		subjectSuffix: subjectSuffix,
+		credential:    credential,
+	}, nil
+}
+
+// testRegistryConnectivity tests connectivity to the Schema Registry.
+func testRegistryConnectivity(ctx context.Context, credential *security.Credential, registryURL string) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", registryURL, nil)
 	if err != nil {
-		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 	httpCli, err := httputil.NewClient(credential)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return errors.Trace(err)
 	}
 	resp, err := httpCli.Do(req)
 	if err != nil {
-		return nil, errors.Annotate(
+		return errors.Annotate(
 			cerror.WrapError(cerror.ErrAvroSchemaAPIError, err), "Test connection to Schema Registry failed")
 	}
 	defer resp.Body.Close()
 
 	text, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.Annotate(
+		return errors.Annotate(
 			cerror.WrapError(cerror.ErrAvroSchemaAPIError, err), "Reading response from Schema Registry failed")
 	}
 
 	if string(text[:]) != "{}" {
-		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack("Unexpected response from Schema Registry")
+		return cerror.ErrAvroSchemaAPIError.GenWithStack("Unexpected response from Schema Registry")
 	}
 
 	log.Info("Successfully tested connectivity to Schema Registry", zap.String("registryURL", registryURL))
-
-	return &AvroSchemaManager{
-		registryURL:   registryURL,
-		cache:         make(map[string]*schemaCacheEntry, 1),
-		subjectSuffix: subjectSuffix,
-		credential:    credential,
-	}, nil
+	return nil
 }
 
 var regexRemoveSpaces = regexp.MustCompile(`\s`)
@@ -118,17 +125,28 @@ var regexRemoveSpaces = regexp.MustCompile(`\s`)
 // Returns the Schema's ID and err
 func (m *AvroSchemaManager) Register(ctx context.Context, tableName model.TableName, codec *goavro.Codec) (int, error) {
 	// The Schema Registry expects the JSON to be without newline characters
+	schema := regexRemoveSpaces.ReplaceAllString(codec.Schema(), "")
+	// The schema type is left empty for compatibility with Confluent 5.4.x,
+	// the Registry treats it as AVRO.
+	return registerSchema(ctx, m.credential, m.registryURL, m.tableNameToSchemaSubject(tableName), schema, "")
+}
+
+// registerSchema registers the schema under the subject and returns the
+// Registry designated ID for that schema.
+func registerSchema(
+	ctx context.Context, credential *security.Credential,
+	registryURL, subject, schema, schemaType string,
+) (int, error) {
 	reqBody := registerRequest{
-		Schema: regexRemoveSpaces.ReplaceAllString(codec.Schema(), ""),
-		// Commented out for compatibility with Confluent 5.4.x
-		// SchemaType: "AVRO",
+		Schema:     schema,
+		SchemaType: schemaType,
 	}
 	payload, err := json.Marshal(&reqBody)
 	if err != nil {
 		return 0, errors.Annotate(
 			cerror.WrapError(cerror.ErrAvroSchemaAPIError, err), "Could not marshal request to the Registry")
 	}
-	uri := m.registryURL + "/subjects/" + url.QueryEscape(m.tableNameToSchemaSubject(tableName)) + "/versions"
+	uri := registryURL + "/subjects/" + url.QueryEscape(subject) + "/versions"
 	log.Debug("Registering schema", zap.String("uri", uri), zap.ByteString("payload", payload))
 
 	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(payload))
@@ -136,7 +154,7 @@ func (m *AvroSchemaManager) Register(ctx context.Context, tableName model.TableN
 		return 0, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs()
 	}
 	req.Header.Add("Accept", "application/vnd.schemaregistry.v1+json")
-	resp, err := httpRetry(ctx, m.credential, req, false)
+	resp, err := httpRetry(ctx, credential, req, false)
 	if err != nil {
 		return 0, err
 	}
@@ -406,3 +424,33 @@ func (m *AvroSchemaManager) tableNameToSchemaSubject(tableName model.TableName)
 	// We should guarantee unique names for subjects
 	return tableName.Schema + "_" + tableName.Table + m.subjectSuffix
 }
+
+// SubjectNameStrategy decides the subject a schema is registered under.
+type SubjectNameStrategy string
+
+const (
+	// SubjectNameStrategyTable registers schemas under `{schema}_{table}-{key|value}`,
+	// it is what the Avro protocol always uses.
+	SubjectNameStrategyTable SubjectNameStrategy = "table"
+	// SubjectNameStrategyTopic registers schemas under `{topic}-{key|value}`,
+	// which requires each table to be dispatched to its own topic.
+	SubjectNameStrategyTopic SubjectNameStrategy = "topic-name"
+	// SubjectNameStrategyRecord registers schemas under the fully qualified record name.
+	SubjectNameStrategyRecord SubjectNameStrategy = "record-name"
+	// SubjectNameStrategyTopicRecord registers schemas under `{topic}-{record name}`.
+	SubjectNameStrategyTopicRecord SubjectNameStrategy = "topic-record-name"
+)
+
+func (s SubjectNameStrategy) validate() error {
+	switch s {
+	case SubjectNameStrategyTable, SubjectNameStrategyTopic,
+		SubjectNameStrategyRecord, SubjectNameStrategyTopicRecord:
+		return nil
+	}
+	return cerror.ErrMQCodecInvalidConfig.GenWithStack("unknown subject-name-strategy %s", s)
+}
+
+// needTopic returns whether the strategy names subjects by topics.
+func (s SubjectNameStrategy) needTopic() bool {
+	return s == SubjectNameStrategyTopic || s == SubjectNameStrategyTopicRecord
+}
@@ -28,13 +28,25 @@ import (
 // the event streams in a table.
 // A higher-level controller more suitable for direct use by the processor is TableFlowController.
 type TableMemoryQuota struct {
-	Quota uint64 // should not be changed once intialized
+	// Quota should not be changed once intialized, unless the quota is managed
+	// by a MemoryQuotaManager, which changes it by SetQuota.
+	Quota uint64
+	// maxQuota is the upper bound of Quota.
+	maxQuota uint64
 
 	IsAborted uint32
+	// hibernated is 1 if the table is hibernated, its quota is not
+	// distributed by MemoryQuotaManager until it wakes up.
+	hibernated uint32
 
 	mu       sync.Mutex
 	Consumed uint64
 
+	// peak and blocked are the statistics since the last call of resetStats,
+	// which are used by MemoryQuotaManager to distribute the memory quota.
+	peak    uint64
+	blocked bool
+
 	cond *sync.Cond
 }
 
@@ -43,6 +55,7 @@ type TableMemoryQuota struct {
 func NewTableMemoryQuota(quota uint64) *TableMemoryQuota {
 	ret := &TableMemoryQuota{
 		Quota:    quota,
+		maxQuota: quota,
 		mu:       sync.Mutex{},
 		Consumed: 0,
 	}
@@ -56,12 +69,13 @@ func NewTableMemoryQuota(quota uint64) *TableMemoryQuota {
 // blockCallBack will be called if the function will block.
 // Should be used with care to prevent deadlock.
 func (c *TableMemoryQuota) ConsumeWithBlocking(nBytes uint64, blockCallBack func() error) error {
-	if nBytes >= c.Quota {
-		return cerrors.ErrFlowControllerEventLargerThanQuota.GenWithStackByArgs(nBytes, c.Quota)
+	if nBytes >= c.maxQuota {
+		return cerrors.ErrFlowControllerEventLargerThanQuota.GenWithStackByArgs(nBytes, c.maxQuota)
 	}
 
 	c.mu.Lock()
 	if c.Consumed+nBytes >= c.Quota {
+		c.blocked = true
 		c.mu.Unlock()
 		err := blockCallBack()
 		if err != nil {
@@ -79,13 +93,15 @@ func (c *TableMemoryQuota) ConsumeWithBlocking(nBytes uint64, blockCallBack func
 			return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
 		}
 
-		if c.Consumed+nBytes < c.Quota {
+		// an event larger than a shrunk quota is accepted when nothing is consumed,
+		// it's guaranteed to be smaller than maxQuota.
+		if c.Consumed+nBytes < c.Quota || c.Consumed == 0 {
 			break
 		}
 		c.cond.Wait()
 	}
 
-	c.Consumed += nBytes
+	c.consume(nBytes)
 	return nil
 }
 
@@ -99,10 +115,18 @@ func (c *TableMemoryQuota) ForceConsume(nBytes uint64) error {
 		return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
 	}
 
-	c.Consumed += nBytes
+	c.consume(nBytes)
 	return nil
 }
 
+// consume records the memory consumption, c.mu must be held.
+func (c *TableMemoryQuota) consume(nBytes uint64) {
+	c.Consumed += nBytes
+	if c.Consumed > c.peak {
+		c.peak = c.Consumed
+	}
+}
+
 // Release is called when a chuck of memory is done being used.
 func (c *TableMemoryQuota) Release(nBytes uint64) {
 	c.mu.Lock()
@@ -130,6 +154,20 @@ func (c *TableMemoryQuota) Abort() {
 	c.cond.Signal()
 }
 
+// setHibernated marks the table as hibernated or not.
+func (c *TableMemoryQuota) setHibernated(hibernated bool) {
+	var v uint32
+	if hibernated {
+		v = 1
+	}
+	atomic.StoreUint32(&c.hibernated, v)
+}
+
+// isHibernated returns true if the table is hibernated.
+func (c *TableMemoryQuota) isHibernated() bool {
+	return atomic.LoadUint32(&c.hibernated) == 1
+}
+
 // GetConsumption returns the current memory consumption
 func (c *TableMemoryQuota) GetConsumption() uint64 {
 	c.mu.Lock()
@@ -138,6 +176,36 @@ func (c *TableMemoryQuota) GetConsumption() uint64 {
 	return c.Consumed
 }
 
+// SetQuota changes the quota, it should not be larger than the initial quota.
+func (c *TableMemoryQuota) SetQuota(quota uint64) {
+	c.mu.Lock()
+	if quota > c.maxQuota {
+		quota = c.maxQuota
+	}
+	c.Quota = quota
+	c.mu.Unlock()
+	c.cond.Broadcast()
+}
+
+// GetQuota returns the current quota.
+func (c *TableMemoryQuota) GetQuota() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.Quota
+}
+
+// resetStats returns the peak consumption and whether ConsumeWithBlocking has
+// been blocked since the last call, and resets them.
+func (c *TableMemoryQuota) resetStats() (peak uint64, blocked bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peak, blocked = c.peak, c.blocked
+	c.peak, c.blocked = c.Consumed, false
+	return
+}
+
 // TableFlowController provides a convenient interface to control the memory consumption of a per table event stream
 type TableFlowController struct {
 	memoryQuota *TableMemoryQuota
@@ -223,6 +291,18 @@ func (c *TableFlowController) Abort() {
 	c.memoryQuota.Abort()
 }
 
+// Hibernate is called when the table is hibernated, the quota of a managed
+// controller is shrunk to the minimum until it wakes up.
+func (c *TableFlowController) Hibernate() {
+	c.memoryQuota.setHibernated(true)
+}
+
+// WakeUp is called when the table wakes up from hibernation, a managed
+// controller gets its share of the quota in the next rebalance round.
+func (c *TableFlowController) WakeUp() {
+	c.memoryQuota.setHibernated(false)
+}
+
 // GetConsumption returns the current memory consumption
 func (c *TableFlowController) GetConsumption() uint64 {
 	return c.memoryQuota.GetConsumption()
@@ -0,0 +1,137 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// memoryQuotaRebalanceInterval is the interval of redistributing the memory quota.
+	memoryQuotaRebalanceInterval = time.Second
+	// minTableMemoryQuota is the minimum quota of a table,
+	// to make sure every table can make progress.
+	minTableMemoryQuota = 64 * 1024 // 64KB
+)
+
+// MemoryQuotaManager distributes a capture level memory quota across the table
+// flow controllers of all changefeeds, based on their recent memory consumption.
+// Half of the quota is shared evenly by all tables, and the other half is
+// distributed in proportion to the peak consumption of every table in the last
+// interval, tables blocked by the quota are given a double weight to grow faster.
+// Hibernated tables are given the minimum quota and take no share.
+type MemoryQuotaManager struct {
+	totalQuota uint64
+
+	mu     sync.Mutex
+	quotas map[*TableMemoryQuota]struct{}
+}
+
+// NewMemoryQuotaManager creates a new MemoryQuotaManager.
+// totalQuota: max advised memory consumption in bytes of all tables.
+func NewMemoryQuotaManager(totalQuota uint64) *MemoryQuotaManager {
+	return &MemoryQuotaManager{
+		totalQuota: totalQuota,
+		quotas:     make(map[*TableMemoryQuota]struct{}),
+	}
+}
+
+// NewTableFlowController creates a new TableFlowController whose quota is managed
+// by the MemoryQuotaManager. The quota is released once the controller is aborted.
+// If the manager is nil, a TableFlowController with the static quota is returned.
+func (m *MemoryQuotaManager) NewTableFlowController(staticQuota uint64) *TableFlowController {
+	if m == nil {
+		return NewTableFlowController(staticQuota)
+	}
+
+	c := NewTableFlowController(m.totalQuota)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quotas[c.memoryQuota] = struct{}{}
+	// the quota will be adjusted in the next rebalance round.
+	c.memoryQuota.SetQuota(m.evenQuota(len(m.quotas)))
+	return c
+}
+
+// Run redistributes the memory quota periodically until the context is done.
+func (m *MemoryQuotaManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(memoryQuotaRebalanceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.rebalance()
+		}
+	}
+}
+
+// evenQuota returns the quota of a table if the quota is shared evenly by n tables.
+func (m *MemoryQuotaManager) evenQuota(n int) uint64 {
+	quota := m.totalQuota / uint64(n)
+	if quota < minTableMemoryQuota {
+		quota = minTableMemoryQuota
+	}
+	return quota
+}
+
+func (m *MemoryQuotaManager) rebalance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	weights := make(map[*TableMemoryQuota]uint64, len(m.quotas))
+	var totalWeight uint64
+	for q := range m.quotas {
+		if atomic.LoadUint32(&q.IsAborted) == 1 {
+			delete(m.quotas, q)
+			continue
+		}
+		if q.isHibernated() {
+			// hibernated tables take no share, they are woken up with the
+			// minimum quota and grow in the next round.
+			q.resetStats()
+			q.SetQuota(minTableMemoryQuota)
+			continue
+		}
+		peak, blocked := q.resetStats()
+		// the weight is at least 1 to make sure every table has a share.
+		weight := peak + 1
+		if blocked {
+			weight *= 2
+		}
+		weights[q] = weight
+		totalWeight += weight
+	}
+	if len(weights) == 0 {
+		return
+	}
+
+	shared := m.evenQuota(2 * len(weights))
+	dynamicQuota := m.totalQuota / 2
+	for q, weight := range weights {
+		// use float64 to avoid overflow.
+		quota := shared + uint64(float64(dynamicQuota)*float64(weight)/float64(totalWeight))
+		q.SetQuota(quota)
+	}
+	log.Debug("memory quota rebalanced",
+		zap.Uint64("totalQuota", m.totalQuota),
+		zap.Int("tableCount", len(weights)))
+}
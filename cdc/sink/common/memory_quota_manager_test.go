@@ -0,0 +1,106 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryQuotaManagerNil(t *testing.T) {
+	t.Parallel()
+
+	var m *MemoryQuotaManager
+	c := m.NewTableFlowController(1024)
+	require.Equal(t, uint64(1024), c.memoryQuota.GetQuota())
+}
+
+func TestMemoryQuotaManagerRebalance(t *testing.T) {
+	t.Parallel()
+
+	totalQuota := uint64(1024 * 1024)
+	m := NewMemoryQuotaManager(totalQuota)
+	hot := m.NewTableFlowController(0)
+	require.Equal(t, totalQuota, hot.memoryQuota.GetQuota())
+	idle := m.NewTableFlowController(0)
+	require.Equal(t, totalQuota/2, hot.memoryQuota.GetQuota())
+	require.Equal(t, totalQuota/2, idle.memoryQuota.GetQuota())
+
+	// the hot table gets more quota than the idle table.
+	require.Nil(t, hot.Consume(1, 256*1024, dummyCallBack))
+	m.rebalance()
+	require.Greater(t, hot.memoryQuota.GetQuota(), totalQuota/2)
+	require.Less(t, idle.memoryQuota.GetQuota(), totalQuota/2)
+	require.LessOrEqual(t, hot.memoryQuota.GetQuota()+idle.memoryQuota.GetQuota(), totalQuota)
+
+	// quota of aborted tables is released.
+	hot.Release(1)
+	hot.Abort()
+	m.rebalance()
+	require.Len(t, m.quotas, 1)
+	require.Equal(t, totalQuota, idle.memoryQuota.GetQuota())
+}
+
+func TestMemoryQuotaShrink(t *testing.T) {
+	t.Parallel()
+
+	m := NewMemoryQuotaManager(1024)
+	c := m.NewTableFlowController(0)
+	c.memoryQuota.SetQuota(128)
+
+	// an event larger than the current quota is accepted if nothing is consumed.
+	require.Nil(t, c.Consume(1, 256, dummyCallBack))
+
+	// blocked until the consumed memory is released.
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Consume(2, 64, dummyCallBack)
+	}()
+	select {
+	case <-done:
+		require.FailNow(t, "consume should be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	c.Release(1)
+	require.Nil(t, <-done)
+	_, blocked := c.memoryQuota.resetStats()
+	require.True(t, blocked)
+
+	// the quota can not exceed the total quota.
+	c.memoryQuota.SetQuota(2048)
+	require.Equal(t, uint64(1024), c.memoryQuota.GetQuota())
+	require.Error(t, c.Consume(3, 1024, dummyCallBack))
+}
+
+func TestMemoryQuotaManagerHibernate(t *testing.T) {
+	t.Parallel()
+
+	totalQuota := uint64(1024 * 1024)
+	m := NewMemoryQuotaManager(totalQuota)
+	busy := m.NewTableFlowController(0)
+	idle := m.NewTableFlowController(0)
+
+	// the quota of hibernated tables is given to the busy tables.
+	idle.Hibernate()
+	m.rebalance()
+	require.Equal(t, uint64(minTableMemoryQuota), idle.memoryQuota.GetQuota())
+	require.Equal(t, totalQuota, busy.memoryQuota.GetQuota())
+
+	idle.WakeUp()
+	m.rebalance()
+	require.Equal(t, totalQuota/2, idle.memoryQuota.GetQuota())
+	require.Equal(t, totalQuota/2, busy.memoryQuota.GetQuota())
+}
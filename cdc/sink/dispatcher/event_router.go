@@ -45,9 +45,14 @@ const (
 	partitionDispatchRuleTS
 	partitionDispatchRuleTable
 	partitionDispatchRuleIndexValue
+	partitionDispatchRuleColumns
 )
 
 func (r *partitionDispatchRule) fromString(rule string) {
+	if partition.IsColumnsExpression(rule) {
+		*r = partitionDispatchRuleColumns
+		return
+	}
 	switch strings.ToLower(rule) {
 	case "default":
 		*r = partitionDispatchRuleDefault
@@ -101,7 +106,10 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 			f = filter.CaseInsensitive(f)
 		}
 
-		d := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		d, err := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		if err != nil {
+			return nil, err
+		}
 		t, err := getTopicDispatcher(ruleConfig, defaultTopic)
 		if err != nil {
 			return nil, err
@@ -222,7 +230,7 @@ func (s *EventRouter) matchDispatcher(schema, table string) (topic.Dispatcher, p
 // getPartitionDispatcher returns the partition dispatcher for a specific partition rule.
 func getPartitionDispatcher(
 	ruleConfig *config.DispatchRule, enableOldValue bool,
-) partition.Dispatcher {
+) (partition.Dispatcher, error) {
 	var (
 		d    partition.Dispatcher
 		rule partitionDispatchRule
@@ -240,11 +248,17 @@ func getPartitionDispatcher(
 		d = partition.NewTsDispatcher()
 	case partitionDispatchRuleTable:
 		d = partition.NewTableDispatcher()
+	case partitionDispatchRuleColumns:
+		columns, err := partition.ColumnsExpression(ruleConfig.PartitionRule).Columns()
+		if err != nil {
+			return nil, err
+		}
+		d = partition.NewColumnsDispatcher(columns)
 	case partitionDispatchRuleDefault:
 		d = partition.NewDefaultDispatcher(enableOldValue)
 	}
 
-	return d
+	return d, nil
 }
 
 // getTopicDispatcher returns the topic dispatcher for a specific topic rule (aka topic expression).
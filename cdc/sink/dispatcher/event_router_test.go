@@ -300,6 +300,52 @@ func TestGetPartitionForRowChange(t *testing.T) {
 	require.Equal(t, int32(1), p)
 }
 
+func TestGetPartitionForRowChangeByColumns(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"test.*"},
+					PartitionRule: "hash(columns: [tenant_id])",
+				},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+	_, partitionDispatcher := d.matchDispatcher("test", "t1")
+	require.IsType(t, &partition.ColumnsDispatcher{}, partitionDispatcher)
+
+	p1 := d.GetPartitionForRowChange(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t1"},
+		Columns: []*model.Column{
+			{Name: "id", Value: 1, Flag: model.HandleKeyFlag},
+			{Name: "tenant_id", Value: 10},
+		},
+	}, 16)
+	p2 := d.GetPartitionForRowChange(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t2"},
+		Columns: []*model.Column{
+			{Name: "id", Value: 2, Flag: model.HandleKeyFlag},
+			{Name: "tenant_id", Value: 10},
+		},
+	}, 16)
+	require.Equal(t, p1, p2)
+
+	_, err = NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"test.*"},
+					PartitionRule: "hash(columns: [])",
+				},
+			},
+		},
+	}, "test")
+	require.Regexp(t, ".*invalid partition expression.*", err)
+}
+
 func TestGetDLLDispatchRuleByProtocol(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,94 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/hash"
+)
+
+// columnsExprRE is used to match a partition expression like
+// `hash(columns: [tenant_id, region])`.
+var columnsExprRE = regexp.MustCompile(`(?i)^\s*hash\s*\(\s*columns\s*:\s*\[(.*)\]\s*\)\s*$`)
+
+// ColumnsExpression represents a partition expression that dispatches events
+// by the values of the given columns.
+type ColumnsExpression string
+
+// IsColumnsExpression checks whether the rule is a columns expression, it
+// may still be invalid.
+func IsColumnsExpression(rule string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(rule)), "hash")
+}
+
+// Columns parses the expression and returns the names of the columns.
+func (e ColumnsExpression) Columns() ([]string, error) {
+	matches := columnsExprRE.FindStringSubmatch(string(e))
+	if matches == nil {
+		return nil, errors.ErrKafkaInvalidPartitionExpression.GenWithStackByArgs(e)
+	}
+	parts := strings.Split(matches[1], ",")
+	columns := make([]string, 0, len(parts))
+	for _, part := range parts {
+		column := strings.Trim(strings.TrimSpace(part), "`")
+		if column == "" {
+			return nil, errors.ErrKafkaInvalidPartitionExpression.GenWithStackByArgs(e)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// ColumnsDispatcher is a partition dispatcher which dispatches events based on
+// the values of the given columns. Unlike IndexValueDispatcher, the table name
+// is not hashed, so events of different tables with the same values are
+// dispatched to the same partition.
+type ColumnsDispatcher struct {
+	hasher  *hash.PositionInertia
+	columns []string
+}
+
+// NewColumnsDispatcher creates a ColumnsDispatcher.
+func NewColumnsDispatcher(columns []string) *ColumnsDispatcher {
+	return &ColumnsDispatcher{
+		hasher:  hash.NewPositionInertia(),
+		columns: columns,
+	}
+}
+
+// DispatchRowChangedEvent returns the target partition to which
+// a row changed event should be dispatched.
+func (r *ColumnsDispatcher) DispatchRowChangedEvent(row *model.RowChangedEvent, partitionNum int32) int32 {
+	r.hasher.Reset()
+	dispatchCols := row.Columns
+	if len(row.Columns) == 0 {
+		dispatchCols = row.PreColumns
+	}
+	for _, name := range r.columns {
+		// Columns missing from the row are hashed as NULL values.
+		var value interface{}
+		for _, col := range dispatchCols {
+			if col != nil && strings.EqualFold(col.Name, name) {
+				value = col.Value
+				break
+			}
+		}
+		r.hasher.Write([]byte(strings.ToLower(name)), []byte(model.ColumnValueString(value)))
+	}
+	return int32(r.hasher.Sum32() % uint32(partitionNum))
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnsExpression(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		expr    string
+		columns []string
+	}{
+		{expr: "hash(columns: [tenant_id])", columns: []string{"tenant_id"}},
+		{expr: " HASH ( columns:[a, `b` ,c] ) ", columns: []string{"a", "b", "c"}},
+		{expr: "hash(columns: [])"},
+		{expr: "hash(columns: [a,,b])"},
+		{expr: "hash(tenant_id)"},
+	}
+	for _, tc := range testCases {
+		require.True(t, IsColumnsExpression(tc.expr))
+		columns, err := ColumnsExpression(tc.expr).Columns()
+		if tc.columns == nil {
+			require.Regexp(t, ".*invalid partition expression.*", err)
+			continue
+		}
+		require.Nil(t, err)
+		require.Equal(t, tc.columns, columns)
+	}
+	require.False(t, IsColumnsExpression("index-value"))
+}
+
+func TestColumnsDispatcher(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(table string, tenantID interface{}, id int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: table},
+			Columns: []*model.Column{
+				{Name: "id", Value: id, Flag: model.HandleKeyFlag},
+				{Name: "Tenant_ID", Value: tenantID},
+			},
+		}
+	}
+	d := NewColumnsDispatcher([]string{"tenant_id"})
+
+	p := d.DispatchRowChangedEvent(newRow("t1", 1, 1), 16)
+	// events of the same tenant are dispatched to the same partition across tables
+	require.Equal(t, p, d.DispatchRowChangedEvent(newRow("t1", 1, 2), 16))
+	require.Equal(t, p, d.DispatchRowChangedEvent(newRow("t2", 1, 3), 16))
+	deleted := newRow("t3", 1, 4)
+	deleted.PreColumns, deleted.Columns = deleted.Columns, nil
+	require.Equal(t, p, d.DispatchRowChangedEvent(deleted, 16))
+
+	// columns missing from the row are hashed as NULL values
+	missing := &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t4"},
+		Columns: []*model.Column{{Name: "id", Value: 1, Flag: model.HandleKeyFlag}},
+	}
+	require.Equal(t, d.DispatchRowChangedEvent(newRow("t1", nil, 1), 16),
+		d.DispatchRowChangedEvent(missing, 16))
+
+	partitions := make(map[int32]struct{})
+	for i := 0; i < 100; i++ {
+		partitions[d.DispatchRowChangedEvent(newRow("t1", i, 1), 16)] = struct{}{}
+	}
+	require.Greater(t, len(partitions), 1)
+}
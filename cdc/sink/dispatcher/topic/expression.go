@@ -22,7 +22,7 @@ import (
 
 var (
 	// topicNameRE is used to match a valid topic expression
-	topicNameRE = regexp.MustCompile(`^[A-Za-z0-9\._\-]*\{schema\}[A-Za-z0-9\._\-]*$|^\{schema\}_\{table\}$`)
+	topicNameRE = regexp.MustCompile(`^[A-Za-z0-9\._\-]*\{schema\}([A-Za-z0-9\._\-]*\{table\})?[A-Za-z0-9\._\-]*$`)
 	// kafkaForbidRE is used to reject the characters which are forbidden in kafka topic name
 	kafkaForbidRE = regexp.MustCompile(`[^a-zA-Z0-9\._\-]`)
 	// schemaRE is used to match substring '{schema}' in topic expression
@@ -38,7 +38,8 @@ const kafkaTopicNameMaxLength = 249
 // Expression represent a kafka topic expression.
 // Only two types of expression are allowed:
 //   1. [prefix]{schema}[suffix], the prefix/suffix is optional and matches [A-Za-z0-9\._\-]*
-//   2. {schema}_{table}
+//   2. [prefix]{schema}[separator]{table}[suffix], such as `cdc_{schema}_{table}`, the
+//      prefix/separator/suffix is optional and matches [A-Za-z0-9\._\-]*
 type Expression string
 
 // Validate checks whether a kafka topic name is valid or not.
@@ -51,6 +52,16 @@ func (e Expression) Validate() error {
 	return nil
 }
 
+// Regexp returns a regular expression which matches all the topic names
+// substituted from the expression.
+func (e Expression) Regexp() *regexp.Regexp {
+	pattern := regexp.QuoteMeta(string(e))
+	for _, placeholder := range []string{"{schema}", "{table}"} {
+		pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(placeholder), `[a-z0-9\._\-]*`)
+	}
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
 // Substitute converts schema/table name in a topic expression to kafka topic name.
 // When doing conversion, the special characters other than [A-Za-z0-9\._\-] in schema/table
 // will be substituted for underscore '_'.
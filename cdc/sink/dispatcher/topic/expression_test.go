@@ -168,6 +168,29 @@ func TestSubstituteTopicExpression(t *testing.T) {
 			wantErr:    "invalid topic expression",
 			expected:   "",
 		},
+		{
+			name:       "valid expression containing '{schema}' and '{table}', with prefix, separator and suffix",
+			expression: "cdc_{schema}.{table}-v1",
+			schema:     "Hello",
+			table:      "World",
+			expected:   "cdc_hello.world-v1",
+		},
+		{
+			name:       "invalid expression containing '{table}' before '{schema}'",
+			expression: "{table}_{schema}",
+			schema:     "hello",
+			table:      "world",
+			wantErr:    "invalid topic expression",
+			expected:   "",
+		},
+		{
+			name:       "invalid expression containing '{table}' twice",
+			expression: "{schema}_{table}_{table}",
+			schema:     "hello",
+			table:      "world",
+			wantErr:    "invalid topic expression",
+			expected:   "",
+		},
 		{
 			name:       "invalid topic name '.'",
 			expression: "{schema}",
@@ -197,6 +220,20 @@ func TestSubstituteTopicExpression(t *testing.T) {
 	}
 }
 
+func TestExpressionRegexp(t *testing.T) {
+	t.Parallel()
+
+	re := Expression("cdc_{schema}_{table}").Regexp()
+	require.True(t, re.MatchString(Expression("cdc_{schema}_{table}").Substitute("Test", "T1!")))
+	require.True(t, re.MatchString("cdc__"))
+	require.False(t, re.MatchString("cdc_test"))
+	require.False(t, re.MatchString("xcdc_test_t1"))
+
+	re = Expression("a.b{schema}").Regexp()
+	require.True(t, re.MatchString("a.btest"))
+	require.False(t, re.MatchString("axbtest"))
+}
+
 // cmd: go test -run='^$' -bench '^(BenchmarkSubstitute)$' github.com/pingcap/tiflow/cdc/sink/dispatcher/topic
 // goos: linux
 // goarch: amd64
@@ -0,0 +1,128 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// fanOutSink writes every event to all of its underlying sinks, it's used when
+// a changefeed replicates to several downstreams with one pulling of the upstream.
+// Note that events are shared by the underlying sinks, so sinks must not modify them.
+//
+// The delivery is at-least-once across the underlying sinks: the progress of each
+// sink isn't tracked, so if a sink fails after others have written the events,
+// the retry of the changefeed writes them to all the sinks again.
+type fanOutSink struct {
+	sinks []Sink
+}
+
+var _ Sink = (*fanOutSink)(nil)
+
+func newFanOutSink(sinks ...Sink) *fanOutSink {
+	return &fanOutSink{sinks: sinks}
+}
+
+// TryEmitRowChangedEvents just calls EmitRowChangedEvents internally, because
+// a partial success of the underlying sinks can't be retried safely.
+func (s *fanOutSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	err := s.EmitRowChangedEvents(ctx, rows...)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EmitRowChangedEvents writes rows to the underlying sinks in order, and returns
+// at the first error, the sinks before the failed one have already got the rows.
+func (s *fanOutSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	for _, sink := range s.sinks {
+		if err := sink.EmitRowChangedEvents(ctx, rows...); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (s *fanOutSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	for _, sink := range s.sinks {
+		if err := sink.EmitDDLEvent(ctx, ddl); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// FlushRowChangedEvents flushes all underlying sinks and returns the minimal
+// checkpoint of them, so the checkpoint only advances when all sinks have flushed.
+func (s *fanOutSink) FlushRowChangedEvents(ctx context.Context, tableID model.TableID, resolvedTs uint64) (uint64, error) {
+	checkpointTs := resolvedTs
+	for _, sink := range s.sinks {
+		ts, err := sink.FlushRowChangedEvents(ctx, tableID, resolvedTs)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if ts < checkpointTs {
+			checkpointTs = ts
+		}
+	}
+	return checkpointTs, nil
+}
+
+func (s *fanOutSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	for _, sink := range s.sinks {
+		if err := sink.EmitCheckpointTs(ctx, ts, tables); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// EmitEndMarker emits the end marker to the underlying sinks writing one, it's
+// a no-op for the others.
+func (s *fanOutSink) EmitEndMarker(ctx context.Context, ts uint64, tables []model.TableName) error {
+	for _, sink := range s.sinks {
+		endMarkerSink, ok := sink.(EndMarkerSink)
+		if !ok {
+			continue
+		}
+		if err := endMarkerSink.EmitEndMarker(ctx, ts, tables); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Close closes all underlying sinks and returns the first error if any.
+func (s *fanOutSink) Close(ctx context.Context) error {
+	var firstErr error
+	for _, sink := range s.sinks {
+		if err := sink.Close(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return errors.Trace(firstErr)
+}
+
+func (s *fanOutSink) Barrier(ctx context.Context, tableID model.TableID) error {
+	for _, sink := range s.sinks {
+		if err := sink.Barrier(ctx, tableID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
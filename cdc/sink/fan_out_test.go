@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util/testleak"
+	"github.com/stretchr/testify/require"
+)
+
+type recordSink struct {
+	rows         []*model.RowChangedEvent
+	ddls         []*model.DDLEvent
+	checkpointTs uint64
+	flushErr     error
+	closed       bool
+}
+
+func (r *recordSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	return true, r.EmitRowChangedEvents(ctx, rows...)
+}
+
+func (r *recordSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	r.rows = append(r.rows, rows...)
+	return nil
+}
+
+func (r *recordSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	r.ddls = append(r.ddls, ddl)
+	return nil
+}
+
+func (r *recordSink) FlushRowChangedEvents(ctx context.Context, tableID model.TableID, resolvedTs uint64) (uint64, error) {
+	if r.flushErr != nil {
+		return 0, r.flushErr
+	}
+	if r.checkpointTs == 0 || r.checkpointTs > resolvedTs {
+		return resolvedTs, nil
+	}
+	return r.checkpointTs, nil
+}
+
+func (r *recordSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	return nil
+}
+
+func (r *recordSink) Close(ctx context.Context) error {
+	r.closed = true
+	return nil
+}
+
+func (r *recordSink) Barrier(ctx context.Context, tableID model.TableID) error {
+	return nil
+}
+
+func TestFanOutSink(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	ctx := context.Background()
+	s1, s2 := &recordSink{}, &recordSink{checkpointTs: 5}
+	s := newFanOutSink(s1, s2)
+
+	rows := []*model.RowChangedEvent{{CommitTs: 1}, {CommitTs: 3}}
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+	require.Equal(t, rows, s1.rows)
+	require.Equal(t, rows, s2.rows)
+
+	ddl := &model.DDLEvent{CommitTs: 4}
+	require.Nil(t, s.EmitDDLEvent(ctx, ddl))
+	require.Equal(t, []*model.DDLEvent{ddl}, s1.ddls)
+	require.Equal(t, []*model.DDLEvent{ddl}, s2.ddls)
+
+	// the checkpoint is the minimal one of all sinks
+	checkpointTs, err := s.FlushRowChangedEvents(ctx, 1, 4)
+	require.Nil(t, err)
+	require.Equal(t, uint64(4), checkpointTs)
+	checkpointTs, err = s.FlushRowChangedEvents(ctx, 1, 10)
+	require.Nil(t, err)
+	require.Equal(t, uint64(5), checkpointTs)
+
+	s2.flushErr = errors.New("flush error")
+	_, err = s.FlushRowChangedEvents(ctx, 1, 10)
+	require.Regexp(t, "flush error", err)
+
+	require.Nil(t, s.Close(ctx))
+	require.True(t, s1.closed)
+	require.True(t, s2.closed)
+}
+
+func TestNewWithExtraSinkURIs(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.ExtraSinkURIs = []string{"blackhole://"}
+	errCh := make(chan error, 1)
+
+	s, err := New(ctx, "test", "blackhole://", nil, cfg, map[string]string{}, errCh)
+	require.Nil(t, err)
+	require.IsType(t, &fanOutSink{}, s)
+	require.Len(t, s.(*fanOutSink).sinks, 2)
+	require.Nil(t, s.Close(ctx))
+
+	cfg.Sink.ExtraSinkURIs = []string{"unknown://"}
+	_, err = New(ctx, "test", "blackhole://", nil, cfg, map[string]string{}, errCh)
+	require.Regexp(t, ".*the sink scheme \\(unknown\\) is not supported.*", err)
+}
@@ -20,7 +20,6 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/prometheus/client_golang/prometheus"
@@ -65,7 +64,7 @@ func (m *Manager) CreateTableSink(tableID model.TableID, checkpointTs model.Ts,
 	m.tableSinksMu.Lock()
 	defer m.tableSinksMu.Unlock()
 	if _, exist := m.tableSinks[tableID]; exist {
-		log.Panic("the table sink already exists", zap.Uint64("tableID", uint64(tableID)))
+		logger().Panic("the table sink already exists", zap.Uint64("tableID", uint64(tableID)))
 	}
 	sink := &tableSink{
 		tableID:     tableID,
@@ -83,16 +82,16 @@ func (m *Manager) Close(ctx context.Context) error {
 	defer m.tableSinksMu.Unlock()
 	tableSinkTotalRowsCountCounter.DeleteLabelValues(m.changefeedID)
 	if m.bufSink != nil {
-		log.Info("sinkManager try close bufSink",
+		logger().Info("sinkManager try close bufSink",
 			zap.String("changefeed", m.changefeedID))
 		start := time.Now()
 		if err := m.bufSink.Close(ctx); err != nil {
-			log.Info("close bufSink failed",
+			logger().Info("close bufSink failed",
 				zap.String("changefeed", m.changefeedID),
 				zap.Duration("duration", time.Since(start)))
 			return err
 		}
-		log.Info("close bufSink success",
+		logger().Info("close bufSink success",
 			zap.String("changefeed", m.changefeedID),
 			zap.Duration("duration", time.Since(start)))
 	}
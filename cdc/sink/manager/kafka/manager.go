@@ -153,17 +153,15 @@ func (m *TopicManager) CreateTopic(topicName string) (int32, error) {
 	}
 
 	start = time.Now()
-	err = m.admin.CreateTopic(topicName, &sarama.TopicDetail{
-		NumPartitions:     m.cfg.PartitionNum,
-		ReplicationFactor: m.cfg.ReplicationFactor,
-	}, false)
+	detail := m.cfg.TopicDetail(topicName)
+	err = m.admin.CreateTopic(topicName, detail, false)
 	// Ignore topic already exists error.
 	if err != nil && errors.Cause(err) != sarama.ErrTopicAlreadyExists {
 		log.Error(
 			"Kafka admin client create the topic failed",
 			zap.String("topic", topicName),
-			zap.Int32("partitionNumber", m.cfg.PartitionNum),
-			zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+			zap.Int32("partitionNumber", detail.NumPartitions),
+			zap.Int16("replicationFactor", detail.ReplicationFactor),
 			zap.Error(err),
 			zap.Duration("duration", time.Since(start)),
 		)
@@ -172,11 +170,11 @@ func (m *TopicManager) CreateTopic(topicName string) (int32, error) {
 	log.Info(
 		"Kafka admin client create the topic success",
 		zap.String("topic", topicName),
-		zap.Int32("partitionNumber", m.cfg.PartitionNum),
-		zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+		zap.Int32("partitionNumber", detail.NumPartitions),
+		zap.Int16("replicationFactor", detail.ReplicationFactor),
 		zap.Duration("duration", time.Since(start)),
 	)
-	m.tryUpdatePartitionsAndLogging(topicName, m.cfg.PartitionNum)
+	m.tryUpdatePartitionsAndLogging(topicName, detail.NumPartitions)
 
-	return m.cfg.PartitionNum, nil
+	return detail.NumPartitions, nil
 }
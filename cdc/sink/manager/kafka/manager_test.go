@@ -18,6 +18,7 @@ import (
 	"time"
 
 	kafkaconfig "github.com/pingcap/tiflow/cdc/sink/producer/kafka"
+	"github.com/pingcap/tiflow/pkg/config"
 	kafkamock "github.com/pingcap/tiflow/pkg/kafka"
 	"github.com/stretchr/testify/require"
 )
@@ -112,3 +113,43 @@ func TestCreateTopic(t *testing.T) {
 		err,
 	)
 }
+
+func TestCreateTopicWithTopicConfig(t *testing.T) {
+	t.Parallel()
+
+	client := kafkamock.NewClientMockImpl()
+	adminClient := kafkamock.NewClusterAdminClientMockImpl()
+	defer func(adminClient *kafkamock.ClusterAdminClientMockImpl) {
+		_ = adminClient.Close()
+	}(adminClient)
+	cfg := (&kafkaconfig.AutoCreateTopicConfig{
+		AutoCreate:        true,
+		PartitionNum:      2,
+		ReplicationFactor: 1,
+	}).WithDispatchRules([]*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "cdc_{schema}"},
+		{
+			Matcher:     []string{"test1.*"},
+			TopicRule:   "cdc_{schema}_{table}",
+			TopicConfig: &config.TopicConfig{PartitionNum: 4, RetentionMs: 86400000},
+		},
+	})
+
+	manager := NewTopicManager(client, adminClient, cfg)
+	partitionNum, err := manager.CreateTopic("cdc_test1_t1")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+	topics, err := adminClient.ListTopics()
+	require.Nil(t, err)
+	detail := topics["cdc_test1_t1"]
+	require.Equal(t, int16(1), detail.ReplicationFactor)
+	require.Equal(t, "86400000", *detail.ConfigEntries["retention.ms"])
+
+	// the topics not matching any topic config use the default settings
+	partitionNum, err = manager.CreateTopic("cdc_test")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), partitionNum)
+	topics, err = adminClient.ListTopics()
+	require.Nil(t, err)
+	require.Nil(t, topics["cdc_test"].ConfigEntries)
+}
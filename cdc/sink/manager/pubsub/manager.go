@@ -0,0 +1,45 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+// topicPartitionsGetter makes sure a pubsub topic exists and gets the number
+// of virtual partitions of it.
+type topicPartitionsGetter interface {
+	TopicPartitions(topic string) (int32, error)
+}
+
+// TopicManager is the interface
+// that wraps the basic Pub/Sub topic management operations.
+// Pub/Sub topics have no partitions, the partitions are virtual ones
+// which decide the ordering keys of messages.
+type TopicManager struct {
+	getter topicPartitionsGetter
+}
+
+// NewTopicManager creates a new TopicManager.
+func NewTopicManager(getter topicPartitionsGetter) *TopicManager {
+	return &TopicManager{
+		getter: getter,
+	}
+}
+
+// Partitions returns the number of partitions of the topic.
+func (m *TopicManager) Partitions(topic string) (int32, error) {
+	return m.getter.TopicPartitions(topic)
+}
+
+// CreateTopic creates the topic if not exists and returns its partition number.
+func (m *TopicManager) CreateTopic(topic string) (int32, error) {
+	return m.getter.TopicPartitions(topic)
+}
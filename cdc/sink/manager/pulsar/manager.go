@@ -13,28 +13,32 @@
 
 package pulsar
 
+// partitionsGetter gets the partition number of a pulsar topic.
+type partitionsGetter interface {
+	TopicPartitions(topic string) (int32, error)
+}
+
 // TopicManager is the interface
 // that wraps the basic Pulsar topic management operations.
-// Right now it doesn't have any implementation,
-// Pulsar doesn't support multiple topics yet.
-// So it now just returns a fixed number of partitions for a fixed topic.
+// Pulsar creates topics automatically when a producer is created,
+// so both operations just query the partitions of the topic.
 type TopicManager struct {
-	partitionNum int32
+	getter partitionsGetter
 }
 
 // NewTopicManager creates a new TopicManager.
-func NewTopicManager(partitionNum int32) *TopicManager {
+func NewTopicManager(getter partitionsGetter) *TopicManager {
 	return &TopicManager{
-		partitionNum: partitionNum,
+		getter: getter,
 	}
 }
 
 // Partitions returns the number of partitions of the topic.
-func (m *TopicManager) Partitions(_ string) (int32, error) {
-	return m.partitionNum, nil
+func (m *TopicManager) Partitions(topic string) (int32, error) {
+	return m.getter.TopicPartitions(topic)
 }
 
-// CreateTopic do nothing.
-func (m *TopicManager) CreateTopic(_ string) (int32, error) {
-	return m.partitionNum, nil
+// CreateTopic creates the topic if not exists and returns its partition number.
+func (m *TopicManager) CreateTopic(topic string) (int32, error) {
+	return m.getter.TopicPartitions(topic)
 }
@@ -78,6 +78,69 @@ var (
 			Name:      "total_flushed_rows_count",
 			Help:      "The total count of rows that are flushed by sink",
 		}, []string{"changefeed"})
+	conflictCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_conflict_count",
+			Help:      "The count of txns serialized by conflicts, type is serialize for conflicting with one worker and flush for conflicting with multiple workers",
+		}, []string{"changefeed", "type"})
+	activeWorkerGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_active_worker_count",
+			Help:      "The number of mysql sink workers which txns are dispatched to",
+		}, []string{"changefeed"})
+	safeModeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_safe_mode",
+			Help:      "Whether the mysql sink writes rows in the safe mode, 1 for enabled and 0 for disabled",
+		}, []string{"changefeed"})
+	deadLetterRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_dead_letter_rows_count",
+			Help:      "The count of rows written into the dead letter queue since the mysql sink fails to apply them",
+		}, []string{"changefeed"})
+	ignoredRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_ignored_rows_count",
+			Help:      "The count of rows skipped since the mysql sink fails to apply them with the ignored errors",
+		}, []string{"changefeed"})
+	schemaPausedTablesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mysql_schema_paused_table_count",
+			Help:      "The number of schema-managed tables paused until the downstream schema matches",
+		}, []string{"changefeed"})
+	throttleDurationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "throttle_duration_seconds",
+			Help:      "The total time (s) rows are delayed by the throttle of sink, type is rows or bytes",
+		}, []string{"changefeed", "type"})
+	storageUnrepresentableValueCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "storage_unrepresentable_value_count",
+			Help:      "The count of values written as NULL by the storage sink since they can't be represented, e.g. zero dates in parquet",
+		}, []string{"changefeed"})
+	mqLargeMessageRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mq_large_message_rows_count",
+			Help:      "The count of rows exceeding max-message-bytes of the mq sink, handle is truncate or dead-letter",
+		}, []string{"changefeed", "handle"})
 
 	tableSinkTotalRowsCountCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -106,6 +169,15 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(bucketSizeCounter)
 	registry.MustRegister(totalRowsCountGauge)
 	registry.MustRegister(totalFlushedRowsCountGauge)
+	registry.MustRegister(safeModeGauge)
+	registry.MustRegister(conflictCounter)
+	registry.MustRegister(activeWorkerGauge)
+	registry.MustRegister(deadLetterRowsCounter)
+	registry.MustRegister(ignoredRowsCounter)
+	registry.MustRegister(schemaPausedTablesGauge)
+	registry.MustRegister(throttleDurationCounter)
+	registry.MustRegister(storageUnrepresentableValueCounter)
+	registry.MustRegister(mqLargeMessageRowsCounter)
 	registry.MustRegister(tableSinkTotalRowsCountCounter)
 	registry.MustRegister(bufferSinkTotalRowsCountCounter)
 }
@@ -19,17 +19,17 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/Shopify/sarama"
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/manager"
 	kafkamanager "github.com/pingcap/tiflow/cdc/sink/manager/kafka"
+	pubsubmanager "github.com/pingcap/tiflow/cdc/sink/manager/pubsub"
 	pulsarmanager "github.com/pingcap/tiflow/cdc/sink/manager/pulsar"
 	"github.com/pingcap/tiflow/cdc/sink/producer"
 	"github.com/pingcap/tiflow/cdc/sink/producer/kafka"
+	"github.com/pingcap/tiflow/cdc/sink/producer/pubsub"
 	"github.com/pingcap/tiflow/cdc/sink/producer/pulsar"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -63,6 +63,11 @@ type mqSink struct {
 	tableCheckpointTsMap sync.Map
 	resolvedBuffer       chan resolvedTsEvent
 
+	// watermarkTopic is empty if the checkpoint watermarks are not emitted,
+	// lastWatermarkTs is only accessed by EmitCheckpointTs.
+	watermarkTopic  string
+	lastWatermarkTs uint64
+
 	statistics *Statistics
 
 	role util.Role
@@ -79,14 +84,30 @@ func newMqSink(
 	replicaConfig *config.ReplicaConfig, encoderConfig *codec.Config,
 	errCh chan error,
 ) (*mqSink, error) {
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, defaultTopic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	encoderConfig = encoderConfig.WithTopicResolver(func(table *model.TableName) string {
+		return eventRouter.GetTopicForRowChange(&model.RowChangedEvent{Table: table})
+	})
 	encoderBuilder, err := codec.NewEventBatchEncoderBuilder(encoderConfig, credential)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 	}
 
-	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, defaultTopic)
-	if err != nil {
-		return nil, errors.Trace(err)
+	// make sure the dead letter topic and the watermark topic exist before
+	// any message is sent to them.
+	if encoderConfig.LargeMessageHandle() == codec.LargeMessageHandleDeadLetter {
+		if _, err := topicManager.Partitions(encoderConfig.DeadLetterTopic()); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if topic := encoderConfig.WatermarkTopic(); topic != "" {
+		if _, err := topicManager.Partitions(topic); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	changefeedID := util.ChangefeedIDFromCtx(ctx)
@@ -94,7 +115,8 @@ func newMqSink(
 
 	encoder := encoderBuilder.Build()
 	statistics := NewStatistics(ctx, sinkTypeMQ)
-	flushWorker := newFlushWorker(encoder, mqProducer, statistics)
+	largeMessage := newLargeMessageHandler(changefeedID, encoderConfig)
+	flushWorker := newFlushWorker(encoder, mqProducer, statistics, largeMessage)
 
 	s := &mqSink{
 		mqProducer:     mqProducer,
@@ -105,6 +127,7 @@ func newMqSink(
 		topicManager:   topicManager,
 		flushWorker:    flushWorker,
 		resolvedBuffer: make(chan resolvedTsEvent, defaultResolvedTsEventBufferSize),
+		watermarkTopic: encoderConfig.WatermarkTopic(),
 		statistics:     statistics,
 		role:           role,
 		id:             changefeedID,
@@ -117,7 +140,7 @@ func newMqSink(
 				return
 			case errCh <- err:
 			default:
-				log.Error("error channel is full", zap.Error(err),
+				logger().Error("error channel is full", zap.Error(err),
 					zap.String("changefeed", changefeedID), zap.Any("role", s.role))
 			}
 		}
@@ -140,7 +163,7 @@ func (k *mqSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowCha
 	rowsCount := 0
 	for _, row := range rows {
 		if k.filter.ShouldIgnoreDMLEvent(row.StartTs, row.Table.Schema, row.Table.Table) {
-			log.Info("Row changed event ignored",
+			logger().Info("Row changed event ignored",
 				zap.Uint64("start-ts", row.StartTs),
 				zap.String("changefeed", k.id),
 				zap.Any("role", k.role))
@@ -212,9 +235,9 @@ func (k *mqSink) bgFlushTs(ctx context.Context) error {
 func (k *mqSink) flushTsToWorker(ctx context.Context, resolvedTs model.Ts) error {
 	if err := k.flushWorker.addEvent(ctx, mqEvent{resolvedTs: resolvedTs}); err != nil {
 		if errors.Cause(err) != context.Canceled {
-			log.Warn("failed to flush TS to worker", zap.Error(err))
+			logger().Warn("failed to flush TS to worker", zap.Error(err))
 		} else {
-			log.Debug("flushing TS to worker has been canceled", zap.Error(err))
+			logger().Debug("flushing TS to worker has been canceled", zap.Error(err))
 		}
 		return err
 	}
@@ -222,6 +245,9 @@ func (k *mqSink) flushTsToWorker(ctx context.Context, resolvedTs model.Ts) error
 }
 
 func (k *mqSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	if err := k.emitWatermark(ctx, ts, tables); err != nil {
+		return err
+	}
 	encoder := k.encoderBuilder.Build()
 	msg, err := encoder.EncodeCheckpointEvent(ts)
 	if err != nil {
@@ -230,6 +256,12 @@ func (k *mqSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model
 	if msg == nil {
 		return nil
 	}
+	return k.broadcastToTopics(ctx, tables, msg)
+}
+
+// broadcastToTopics sends the message to all the partitions of the topics of
+// the tables.
+func (k *mqSink) broadcastToTopics(ctx context.Context, tables []model.TableName, msg *codec.MQMessage) error {
 	// NOTICE: When there is no table sync,
 	// we need to send checkpoint ts to the default topic. T
 	// This will be compatible with the old behavior.
@@ -239,20 +271,20 @@ func (k *mqSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model
 		if err != nil {
 			return errors.Trace(err)
 		}
-		log.Debug("emit checkpointTs to default topic",
-			zap.String("topic", topic), zap.Uint64("checkpointTs", ts))
+		logger().Debug("broadcast message to default topic",
+			zap.String("topic", topic), zap.Uint64("ts", msg.Ts))
 		err = k.mqProducer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
 		return errors.Trace(err)
 	}
 	topics := k.eventRouter.GetActiveTopics(tables)
-	log.Debug("MQ sink current active topics", zap.Any("topics", topics))
+	logger().Debug("MQ sink current active topics", zap.Any("topics", topics))
 	for _, topic := range topics {
 		partitionNum, err := k.topicManager.Partitions(topic)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		log.Debug("emit checkpointTs to active topic",
-			zap.String("topic", topic), zap.Uint64("checkpointTs", ts))
+		logger().Debug("broadcast message to active topic",
+			zap.String("topic", topic), zap.Uint64("ts", msg.Ts))
 		err = k.mqProducer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
 		if err != nil {
 			return errors.Trace(err)
@@ -263,7 +295,7 @@ func (k *mqSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model
 
 func (k *mqSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	if k.filter.ShouldIgnoreDDLEvent(ddl.StartTs, ddl.Type, ddl.TableInfo.Schema, ddl.TableInfo.Table) {
-		log.Info(
+		logger().Info(
 			"DDL event ignored",
 			zap.String("query", ddl.Query),
 			zap.Uint64("startTs", ddl.StartTs),
@@ -286,7 +318,7 @@ func (k *mqSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	topic := k.eventRouter.GetTopicForDDL(ddl)
 	partitionRule := k.eventRouter.GetDLLDispatchRuleByProtocol(k.protocol)
 	k.statistics.AddDDLCount()
-	log.Debug("emit ddl event",
+	logger().Debug("emit ddl event",
 		zap.Uint64("commitTs", ddl.CommitTs), zap.String("query", ddl.Query),
 		zap.String("changefeed", k.id), zap.Any("role", k.role))
 	if partitionRule == dispatcher.PartitionAll {
@@ -310,6 +342,7 @@ func (k *mqSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 }
 
 func (k *mqSink) Close(ctx context.Context) error {
+	k.flushWorker.largeMessage.close()
 	err := k.mqProducer.Close()
 	return errors.Trace(err)
 }
@@ -394,7 +427,7 @@ func newKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 	}
 
-	client, err := sarama.NewClient(baseConfig.BrokerEndpoints, saramaConfig)
+	client, err := kafka.NewSaramaClient(ctx, baseConfig, saramaConfig)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
@@ -402,9 +435,11 @@ func newKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 	topicManager := kafkamanager.NewTopicManager(
 		client,
 		adminClient,
-		baseConfig.DeriveTopicConfig(),
+		baseConfig.DeriveTopicConfig().WithDispatchRules(replicaConfig.Sink.DispatchRules),
 	)
 	if _, err := topicManager.CreateTopic(topic); err != nil {
+		// Release the client, otherwise a shared client is never closed.
+		_ = client.Close()
 		return nil, cerror.WrapError(cerror.ErrKafkaCreateTopic, err)
 	}
 
@@ -417,6 +452,7 @@ func newKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 		errCh,
 	)
 	if err != nil {
+		_ = client.Close()
 		return nil, errors.Trace(err)
 	}
 
@@ -451,17 +487,16 @@ func newPulsarSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
 
 	var protocol config.Protocol
 	if err := protocol.FromString(replicaConfig.Sink.Protocol); err != nil {
-		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		return nil, cerror.WrapError(cerror.ErrPulsarInvalidConfig, err)
 	}
 
-	encoderConfig := codec.NewConfig(protocol, util.TimezoneFromCtx(ctx))
+	encoderConfig := codec.NewConfig(protocol, util.TimezoneFromCtx(ctx)).
+		WithMaxMessageBytes(pulsar.DefaultMaxMessageBytes)
 	if err := encoderConfig.Apply(sinkURI, opts); err != nil {
-		return nil, errors.Trace(err)
+		return nil, cerror.WrapError(cerror.ErrPulsarInvalidConfig, err)
 	}
-	// todo: set by pulsar producer's `max.message.bytes`
-	// encoderConfig = encoderConfig.WithMaxMessageBytes()
 	if err := encoderConfig.Validate(); err != nil {
-		return nil, errors.Trace(err)
+		return nil, cerror.WrapError(cerror.ErrPulsarInvalidConfig, err)
 	}
 
 	producer, err := pulsar.NewProducer(sinkURI, errCh)
@@ -471,16 +506,69 @@ func newPulsarSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
 	// For now, it's a placeholder. Avro format have to make connection to Schema Registry,
 	// and it may need credential.
 	credential := &security.Credential{}
-	fakeTopicManager := pulsarmanager.NewTopicManager(
-		producer.GetPartitionNum(),
+	topicManager := pulsarmanager.NewTopicManager(producer)
+	sink, err := newMqSink(
+		ctx,
+		credential,
+		topicManager,
+		producer,
+		filter,
+		producer.DefaultTopic(),
+		replicaConfig,
+		encoderConfig,
+		errCh,
 	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return sink, nil
+}
+
+func newPubSubSink(ctx context.Context, sinkURI *url.URL, filter *filter.Filter,
+	replicaConfig *config.ReplicaConfig, opts map[string]string, errCh chan error,
+) (*mqSink, error) {
+	s := sinkURI.Query().Get(config.ProtocolKey)
+	if s != "" {
+		replicaConfig.Sink.Protocol = s
+	}
+	err := replicaConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var protocol config.Protocol
+	if err := protocol.FromString(replicaConfig.Sink.Protocol); err != nil {
+		return nil, cerror.WrapError(cerror.ErrPubSubInvalidConfig, err)
+	}
+
+	encoderConfig := codec.NewConfig(protocol, util.TimezoneFromCtx(ctx)).
+		WithMaxMessageBytes(pubsub.DefaultMaxMessageBytes)
+	if err := encoderConfig.Apply(sinkURI, opts); err != nil {
+		return nil, cerror.WrapError(cerror.ErrPubSubInvalidConfig, err)
+	}
+	if encoderConfig.MaxMessageBytes() > pubsub.DefaultMaxMessageBytes {
+		return nil, cerror.ErrPubSubInvalidConfig.GenWithStack(
+			"max-message-bytes should not be greater than %d", pubsub.DefaultMaxMessageBytes)
+	}
+	if err := encoderConfig.Validate(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrPubSubInvalidConfig, err)
+	}
+
+	producer, err := pubsub.NewProducer(ctx, sinkURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// For now, it's a placeholder. Avro format have to make connection to Schema Registry,
+	// and it may need credential.
+	credential := &security.Credential{}
+	topicManager := pubsubmanager.NewTopicManager(producer)
 	sink, err := newMqSink(
 		ctx,
 		credential,
-		fakeTopicManager,
+		topicManager,
 		producer,
 		filter,
-		"",
+		producer.DefaultTopic(),
 		replicaConfig,
 		encoderConfig,
 		errCh,
@@ -0,0 +1,42 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// endOfStreamEvent is the value of the end-of-stream message, all the events
+// of the changefeed committed before CheckpointTs have been sent, and no event
+// is sent after it.
+type endOfStreamEvent struct {
+	Changefeed   string `json:"changefeed"`
+	CheckpointTs uint64 `json:"checkpoint-ts"`
+}
+
+// EmitEndMarker broadcasts the end-of-stream message to all the partitions of
+// the topics of the tables.
+func (k *mqSink) EmitEndMarker(ctx context.Context, ts uint64, tables []model.TableName) error {
+	value, err := json.Marshal(&endOfStreamEvent{Changefeed: k.id, CheckpointTs: ts})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	msg := codec.NewEndOfStreamMessage(k.protocol, value, ts)
+	return k.broadcastToTopics(ctx, tables, msg)
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/dispatcher"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTopicManager struct {
+	partitions map[string]int32
+}
+
+func (m *mockTopicManager) Partitions(topic string) (int32, error) {
+	return m.partitions[topic], nil
+}
+
+func (m *mockTopicManager) CreateTopic(topic string) (int32, error) {
+	return m.partitions[topic], nil
+}
+
+func TestEmitEndMarker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	producer := NewMockProducer()
+	eventRouter, err := dispatcher.NewEventRouter(config.GetDefaultReplicaConfig(), "test-topic")
+	require.Nil(t, err)
+	s := &mqSink{
+		mqProducer:   producer,
+		protocol:     config.ProtocolOpen,
+		id:           "test",
+		eventRouter:  eventRouter,
+		topicManager: &mockTopicManager{partitions: map[string]int32{"test-topic": 2}},
+	}
+
+	// the end-of-stream message is broadcast to all the partitions
+	require.Nil(t, s.EmitEndMarker(ctx, 100, []model.TableName{{Schema: "test", Table: "t1"}}))
+	for partition := int32(0); partition < 2; partition++ {
+		msgs := producer.mqEvent[topicPartitionKey{topic: "test-topic", partition: partition}]
+		require.Len(t, msgs, 1)
+		require.Equal(t, model.MqMessageTypeEndOfStream, msgs[0].Type)
+		require.True(t, codec.IsEndOfStreamMessage(msgs[0].Key))
+		event := &endOfStreamEvent{}
+		require.Nil(t, json.Unmarshal(msgs[0].Value, event))
+		require.Equal(t, &endOfStreamEvent{Changefeed: "test", CheckpointTs: 100}, event)
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/producer"
@@ -62,19 +61,26 @@ type flushWorker struct {
 	encoder    codec.EventBatchEncoder
 	producer   producer.Producer
 	statistics *Statistics
+	// largeMessage is nil if the messages exceeding max-message-bytes are
+	// sent as is.
+	largeMessage *largeMessageHandler
 }
 
 // newFlushWorker creates a new flush worker.
-func newFlushWorker(encoder codec.EventBatchEncoder, producer producer.Producer, statistics *Statistics) *flushWorker {
+func newFlushWorker(
+	encoder codec.EventBatchEncoder, producer producer.Producer,
+	statistics *Statistics, largeMessage *largeMessageHandler,
+) *flushWorker {
 	w := &flushWorker{
 		msgChan: make(chan mqEvent),
 		ticker:  time.NewTicker(flushInterval),
 		// errCh must be a buffered channel, or otherwise sending error to it will
 		// almost certainly go to the default branch, making errCh useless.
-		errCh:      make(chan error, 1),
-		encoder:    encoder,
-		producer:   producer,
-		statistics: statistics,
+		errCh:        make(chan error, 1),
+		encoder:      encoder,
+		producer:     producer,
+		statistics:   statistics,
+		largeMessage: largeMessage,
 	}
 	return w
 }
@@ -148,23 +154,21 @@ func (w *flushWorker) asyncSend(
 	paritionedRows map[topicPartitionKey][]*model.RowChangedEvent,
 ) error {
 	for key, events := range paritionedRows {
-		for _, event := range events {
-			err := w.encoder.AppendRowChangedEvent(event)
-			if err != nil {
-				return err
-			}
+		messages, err := w.encode(ctx, events)
+		if err != nil {
+			return err
 		}
 
-		err := w.statistics.RecordBatchExecution(func() (int, error) {
+		err = w.statistics.RecordBatchExecution(func() (int, error) {
 			thisBatchSize := 0
-			for _, message := range w.encoder.Build() {
+			for _, message := range messages {
 				err := w.producer.AsyncSendMessage(ctx, key.topic, key.partition, message)
 				if err != nil {
 					return 0, err
 				}
 				thisBatchSize += message.GetRowsCount()
 			}
-			log.Debug("MQSink flush worker flushed", zap.Int("thisBatchSize", thisBatchSize))
+			logger().Debug("MQSink flush worker flushed", zap.Int("thisBatchSize", thisBatchSize))
 			return thisBatchSize, nil
 		})
 		if err != nil {
@@ -179,12 +183,58 @@ func (w *flushWorker) asyncSend(
 			return err
 		}
 		w.needSyncFlush = false
-		log.Debug("flush worker flushed", zap.Duration("duration", time.Since(start)))
+		logger().Debug("flush worker flushed", zap.Duration("duration", time.Since(start)))
 	}
 
 	return nil
 }
 
+// encode encodes the rows into messages in the order of the rows. If any
+// message exceeds max-message-bytes, the rows are split in halves and encoded
+// again, until the single rows exceeding it are handled by the large message
+// handler.
+func (w *flushWorker) encode(
+	ctx context.Context, rows []*model.RowChangedEvent,
+) ([]*codec.MQMessage, error) {
+	messages, err := w.encodeRows(rows)
+	if w.largeMessage == nil {
+		return messages, err
+	}
+	if err != nil && !cerror.ErrJSONCodecRowTooLarge.Equal(errors.Cause(err)) {
+		return nil, err
+	}
+	if err == nil && w.largeMessage.fits(messages) {
+		return messages, nil
+	}
+
+	if len(rows) == 1 {
+		return w.largeMessage.handleRow(ctx, rows[0], func(row *model.RowChangedEvent) ([]*codec.MQMessage, error) {
+			return w.encodeRows([]*model.RowChangedEvent{row})
+		}, w.producer)
+	}
+	half := len(rows) / 2
+	left, err := w.encode(ctx, rows[:half])
+	if err != nil {
+		return nil, err
+	}
+	right, err := w.encode(ctx, rows[half:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+func (w *flushWorker) encodeRows(rows []*model.RowChangedEvent) ([]*codec.MQMessage, error) {
+	for _, row := range rows {
+		if err := w.encoder.AppendRowChangedEvent(row); err != nil {
+			// drop the rows appended to the encoder.
+			w.encoder.Build()
+			return nil, err
+		}
+	}
+	return w.encoder.Build(), nil
+}
+
 // run starts a loop that keeps collecting, sorting and sending messages
 // until it encounters an error or is interrupted.
 func (w *flushWorker) run(ctx context.Context) (retErr error) {
@@ -193,7 +243,7 @@ func (w *flushWorker) run(ctx context.Context) (retErr error) {
 		w.errCh <- retErr
 		close(w.errCh)
 		// TODO: log changefeed ID here
-		log.Info("flushWorker exited", zap.Error(retErr))
+		logger().Info("flushWorker exited", zap.Error(retErr))
 	}()
 	defer w.ticker.Stop()
 	eventsBuf := make([]mqEvent, flushBatchSize)
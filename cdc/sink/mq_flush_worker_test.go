@@ -57,7 +57,12 @@ func (m *mockProducer) AsyncSendMessage(
 func (m *mockProducer) SyncBroadcastMessage(
 	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
 ) error {
-	panic("Not used")
+	for partition := int32(0); partition < partitionsNum; partition++ {
+		if err := m.AsyncSendMessage(ctx, topic, partition, message); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (m *mockProducer) Flush(ctx context.Context) error {
@@ -93,7 +98,7 @@ func newTestWorker() (*flushWorker, *mockProducer) {
 		panic(err)
 	}
 	producer := NewMockProducer()
-	return newFlushWorker(encoder, producer, NewStatistics(context.Background(), sinkTypeMQ)), producer
+	return newFlushWorker(encoder, producer, NewStatistics(context.Background(), sinkTypeMQ), nil), producer
 }
 
 func TestBatch(t *testing.T) {
@@ -0,0 +1,238 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/producer"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// largeMessageHandler handles the single rows whose messages exceed
+// max-message-bytes, the batches exceeding it are split by the flush worker
+// until every message holds a single row.
+type largeMessageHandler struct {
+	changefeedID    model.ChangeFeedID
+	maxMessageBytes int
+	handle          codec.LargeMessageHandle
+	deadLetterTopic string
+	protocol        config.Protocol
+
+	metricRowsCounter prometheus.Counter
+}
+
+func newLargeMessageHandler(changefeedID model.ChangeFeedID, encoderConfig *codec.Config) *largeMessageHandler {
+	return &largeMessageHandler{
+		changefeedID:    changefeedID,
+		maxMessageBytes: encoderConfig.MaxMessageBytes(),
+		handle:          encoderConfig.LargeMessageHandle(),
+		deadLetterTopic: encoderConfig.DeadLetterTopic(),
+		protocol:        encoderConfig.Protocol(),
+		metricRowsCounter: mqLargeMessageRowsCounter.
+			WithLabelValues(changefeedID, string(encoderConfig.LargeMessageHandle())),
+	}
+}
+
+// fits returns true if all the messages are within max-message-bytes.
+func (h *largeMessageHandler) fits(messages []*codec.MQMessage) bool {
+	for _, message := range messages {
+		if message.Length() > h.maxMessageBytes {
+			return false
+		}
+	}
+	return true
+}
+
+// handleRow handles the row whose message exceeds max-message-bytes, encode
+// encodes a single row into messages. It returns the messages of the truncated
+// row, or nothing if a pointer record of the row is sent to the dead letter
+// topic instead.
+func (h *largeMessageHandler) handleRow(
+	ctx context.Context,
+	row *model.RowChangedEvent,
+	encode func(row *model.RowChangedEvent) ([]*codec.MQMessage, error),
+	producer producer.Producer,
+) ([]*codec.MQMessage, error) {
+	switch h.handle {
+	case codec.LargeMessageHandleTruncate:
+		truncated := row
+		for {
+			var ok bool
+			truncated, ok = truncateLongestBlob(truncated)
+			if !ok {
+				return nil, cerror.ErrMQRowTooLarge.GenWithStackByArgs(row.Table.String(), h.maxMessageBytes)
+			}
+			messages, err := encode(truncated)
+			if err != nil && !cerror.ErrJSONCodecRowTooLarge.Equal(errors.Cause(err)) {
+				return nil, err
+			}
+			if err == nil && h.fits(messages) {
+				logger().Warn("the blob values of a row are truncated since its message exceeds max-message-bytes",
+					zap.String("changefeed", h.changefeedID), zap.Stringer("table", row.Table),
+					zap.Uint64("commitTs", row.CommitTs), zap.Int("maxMessageBytes", h.maxMessageBytes))
+				h.metricRowsCounter.Inc()
+				return messages, nil
+			}
+		}
+	case codec.LargeMessageHandleDeadLetter:
+		message, err := h.pointerMessage(row)
+		if err != nil {
+			return nil, err
+		}
+		if err := producer.AsyncSendMessage(ctx, h.deadLetterTopic, 0, message); err != nil {
+			return nil, err
+		}
+		logger().Warn("a row is sent to the dead letter topic since its message exceeds max-message-bytes",
+			zap.String("changefeed", h.changefeedID), zap.Stringer("table", row.Table),
+			zap.Uint64("commitTs", row.CommitTs), zap.String("topic", h.deadLetterTopic))
+		h.metricRowsCounter.Inc()
+		return nil, nil
+	default:
+		return nil, cerror.ErrMQRowTooLarge.GenWithStackByArgs(row.Table.String(), h.maxMessageBytes)
+	}
+}
+
+// largeMessagePointer is the record sent to the dead letter topic instead of a
+// row exceeding max-message-bytes, it locates the row in the upstream by the
+// handle key and the commit ts.
+type largeMessagePointer struct {
+	Type     string                 `json:"type"`
+	Schema   string                 `json:"schema"`
+	Table    string                 `json:"table"`
+	CommitTs uint64                 `json:"commit-ts"`
+	Keys     map[string]interface{} `json:"keys"`
+}
+
+func (h *largeMessageHandler) pointerMessage(row *model.RowChangedEvent) (*codec.MQMessage, error) {
+	pointer := &largeMessagePointer{
+		Type:     "update",
+		Schema:   row.Table.Schema,
+		Table:    row.Table.Table,
+		CommitTs: row.CommitTs,
+	}
+	columns := row.Columns
+	if row.IsInsert() {
+		pointer.Type = "insert"
+	} else if row.IsDelete() {
+		pointer.Type = "delete"
+		columns = row.PreColumns
+	}
+	keys := make([]*model.Column, 0, 1)
+	for _, col := range columns {
+		if col != nil && col.Flag.IsHandleKey() {
+			keys = append(keys, col)
+		}
+	}
+	pointer.Keys = esDocument(keys, nil)
+	value, err := json.Marshal(pointer)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return codec.NewMQMessage(h.protocol, nil, value, row.CommitTs,
+		model.MqMessageTypeRow, &row.Table.Schema, &row.Table.Table), nil
+}
+
+func (h *largeMessageHandler) close() {
+	mqLargeMessageRowsCounter.DeleteLabelValues(h.changefeedID, string(h.handle))
+}
+
+// truncateLongestBlob returns a copy of the row whose longest blob value, in
+// either the columns or the pre-columns, is truncated to half of its length.
+// It returns false if there is no blob value to truncate.
+func truncateLongestBlob(row *model.RowChangedEvent) (*model.RowChangedEvent, bool) {
+	var (
+		longest    *model.Column
+		longestLen int
+	)
+	for _, columns := range [][]*model.Column{row.Columns, row.PreColumns} {
+		for _, col := range columns {
+			if col == nil || !isBlobType(col.Type) {
+				continue
+			}
+			if n := blobLen(col.Value); n > longestLen {
+				longest, longestLen = col, n
+			}
+		}
+	}
+	if longest == nil {
+		return nil, false
+	}
+
+	truncated := *longest
+	switch v := longest.Value.(type) {
+	case []byte:
+		n := len(v) / 2
+		if !longest.Flag.IsBinary() {
+			n = utf8Boundary(v, n)
+		}
+		truncated.Value = v[:n]
+	case string:
+		truncated.Value = v[:utf8Boundary([]byte(v), len(v)/2)]
+	}
+	copied := *row
+	copied.Columns = replaceColumn(row.Columns, longest, &truncated)
+	copied.PreColumns = replaceColumn(row.PreColumns, longest, &truncated)
+	return &copied, true
+}
+
+func isBlobType(tp byte) bool {
+	switch tp {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		return true
+	}
+	return false
+}
+
+func blobLen(value interface{}) int {
+	switch v := value.(type) {
+	case []byte:
+		return len(v)
+	case string:
+		return len(v)
+	}
+	return 0
+}
+
+// utf8Boundary returns the largest index not greater than n which doesn't
+// split a UTF-8 encoded rune.
+func utf8Boundary(b []byte, n int) int {
+	for n > 0 && n < len(b) && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	return n
+}
+
+// replaceColumn returns a copy of the columns with old replaced by new, the
+// columns are returned as is if old is not in them.
+func replaceColumn(columns []*model.Column, old, new *model.Column) []*model.Column {
+	for i, col := range columns {
+		if col == old {
+			copied := make([]*model.Column, len(columns))
+			copy(copied, columns)
+			copied[i] = new
+			return copied
+		}
+	}
+	return columns
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+)
+
+func largeMessageRow(id int64, comment string) *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		CommitTs: uint64(100 + id),
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: id, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "comment", Type: mysql.TypeBlob, Value: []byte(comment)},
+		},
+	}
+}
+
+// newLargeMessageTestWorker creates a flush worker with the canal protocol,
+// which encodes all the appended rows into a single message.
+func newLargeMessageTestWorker(
+	t *testing.T, maxMessageBytes int, query string,
+) (*flushWorker, *mockProducer) {
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/test?" + query)
+	require.Nil(t, err)
+	encoderConfig := codec.NewConfig(config.ProtocolCanal, timeutil.SystemLocation())
+	require.Nil(t, encoderConfig.Apply(sinkURI, nil))
+	encoderConfig = encoderConfig.WithMaxMessageBytes(maxMessageBytes)
+	require.Nil(t, encoderConfig.Validate())
+	builder, err := codec.NewEventBatchEncoderBuilder(encoderConfig, &security.Credential{})
+	require.Nil(t, err)
+	producer := NewMockProducer()
+	worker := newFlushWorker(builder.Build(), producer,
+		NewStatistics(context.Background(), sinkTypeMQ), newLargeMessageHandler("test", encoderConfig))
+	return worker, producer
+}
+
+// rowMessageLength returns the length of the message of a single row.
+func rowMessageLength(t *testing.T, row *model.RowChangedEvent) int {
+	worker, _ := newLargeMessageTestWorker(t, 1<<30, "")
+	messages, err := worker.encodeRows([]*model.RowChangedEvent{row})
+	require.Nil(t, err)
+	require.Len(t, messages, 1)
+	return messages[0].Length()
+}
+
+func TestFlushWorkerSplitLargeBatch(t *testing.T) {
+	t.Parallel()
+
+	rows := []*model.RowChangedEvent{
+		largeMessageRow(1, "a"), largeMessageRow(2, "b"),
+		largeMessageRow(3, "c"), largeMessageRow(4, "d"),
+		largeMessageRow(5, "e"),
+	}
+	// only a single row fits into a message.
+	worker, _ := newLargeMessageTestWorker(t, rowMessageLength(t, rows[0])+10, "")
+	messages, err := worker.encode(context.Background(), rows)
+	require.Nil(t, err)
+	require.Len(t, messages, len(rows))
+	for _, message := range messages {
+		require.Equal(t, 1, message.GetRowsCount())
+	}
+
+	// all rows fit into a message.
+	worker, _ = newLargeMessageTestWorker(t, 1<<30, "")
+	messages, err = worker.encode(context.Background(), rows)
+	require.Nil(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, len(rows), messages[0].GetRowsCount())
+}
+
+func TestFlushWorkerLargeRow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	small := largeMessageRow(1, "a")
+	large := largeMessageRow(2, strings.Repeat("测试", 1000))
+	maxMessageBytes := rowMessageLength(t, small) + 100
+
+	// the row fails the changefeed by default.
+	worker, _ := newLargeMessageTestWorker(t, maxMessageBytes, "")
+	_, err := worker.encode(ctx, []*model.RowChangedEvent{small, large})
+	require.Regexp(t, ".*exceeds max-message-bytes.*", err)
+
+	// the blob value is truncated until the row fits.
+	worker, _ = newLargeMessageTestWorker(t, maxMessageBytes, "large-message-handle=truncate")
+	messages, err := worker.encode(ctx, []*model.RowChangedEvent{small, large})
+	require.Nil(t, err)
+	require.Len(t, messages, 2)
+	for _, message := range messages {
+		require.LessOrEqual(t, message.Length(), maxMessageBytes)
+	}
+	// the row itself is not changed.
+	require.Len(t, large.Columns[1].Value, 6000)
+
+	// a pointer record is sent to the dead letter topic.
+	worker, producer := newLargeMessageTestWorker(t, maxMessageBytes,
+		"large-message-handle=dead-letter&dead-letter-topic=dlq")
+	messages, err = worker.encode(ctx, []*model.RowChangedEvent{small, large})
+	require.Nil(t, err)
+	require.Len(t, messages, 1)
+	dlq := producer.mqEvent[topicPartitionKey{topic: "dlq", partition: 0}]
+	require.Len(t, dlq, 1)
+	pointer := &largeMessagePointer{}
+	require.Nil(t, json.Unmarshal(dlq[0].Value, pointer))
+	require.Equal(t, &largeMessagePointer{
+		Type:     "insert",
+		Schema:   "test",
+		Table:    "t",
+		CommitTs: 102,
+		Keys:     map[string]interface{}{"id": float64(2)},
+	}, pointer)
+}
+
+func TestTruncateLongestBlob(t *testing.T) {
+	t.Parallel()
+
+	row := &model.RowChangedEvent{
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "text", Type: mysql.TypeBlob, Value: []byte("测试测试")},
+			{Name: "blob", Type: mysql.TypeBlob, Value: []byte("1234567890abcd"), Flag: model.BinaryFlag},
+		},
+		PreColumns: []*model.Column{
+			{Name: "text", Type: mysql.TypeMediumBlob, Value: "abc"},
+		},
+	}
+	truncated, ok := truncateLongestBlob(row)
+	require.True(t, ok)
+	require.Equal(t, []byte("1234567"), truncated.Columns[2].Value)
+	// the text value is truncated at the boundary of runes.
+	truncated, ok = truncateLongestBlob(truncated)
+	require.True(t, ok)
+	require.Equal(t, []byte("测试"), truncated.Columns[1].Value)
+	// the original row is not changed.
+	require.Equal(t, []byte("测试测试"), row.Columns[1].Value)
+	require.Equal(t, []byte("1234567890abcd"), row.Columns[2].Value)
+
+	for ok {
+		truncated, ok = truncateLongestBlob(truncated)
+	}
+	require.Equal(t, "abc", row.PreColumns[0].Value)
+	_, ok = truncateLongestBlob(&model.RowChangedEvent{Columns: row.Columns[:1]})
+	require.False(t, ok)
+}
@@ -237,6 +237,29 @@ func (s mqSinkSuite) TestPulsarSinkEncoderConfig(c *check.C) {
 	c.Assert(encoder, check.FitsTypeOf, &codec.JSONEventBatchEncoder{})
 	c.Assert(encoder.(*codec.JSONEventBatchEncoder).GetMaxBatchSize(), check.Equals, 1)
 	c.Assert(encoder.(*codec.JSONEventBatchEncoder).GetMaxMessageBytes(), check.Equals, 4194304)
+
+	partitions, err := sink.topicManager.Partitions("kafka-test")
+	c.Assert(err, check.IsNil)
+	c.Assert(partitions, check.Equals, int32(4))
+	c.Assert(sink.Close(ctx), check.IsNil)
+
+	// the max message bytes is set to the default value of pulsar brokers if not specified.
+	sinkURI, err = url.Parse("pulsar://127.0.0.1:1234/kafka-test?protocol=canal-json")
+	c.Assert(err, check.IsNil)
+	sink, err = newPulsarSink(ctx, sinkURI, fr, replicaConfig, opts, errCh)
+	c.Assert(err, check.IsNil)
+	encoder = sink.encoderBuilder.Build()
+	c.Assert(encoder, check.FitsTypeOf, &codec.CanalFlatEventBatchEncoder{})
+	c.Assert(sink.Close(ctx), check.IsNil)
+
+	// topic is required.
+	sinkURI, err = url.Parse("pulsar://127.0.0.1:1234/")
+	c.Assert(err, check.IsNil)
+	_, err = newPulsarSink(ctx, sinkURI, fr, config.GetDefaultReplicaConfig(), opts, errCh)
+	c.Assert(cerror.ErrPulsarNewProducer.Equal(err), check.IsTrue)
+
+	err = failpoint.Disable("github.com/pingcap/tiflow/cdc/sink/producer/pulsar/MockPulsar")
+	c.Assert(err, check.IsNil)
 }
 
 func (s mqSinkSuite) TestFlushRowChangedEvents(c *check.C) {
@@ -0,0 +1,65 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// watermarkEvent is the checkpoint watermark sent to the watermark topic. All
+// the events of the tables committed before CheckpointTs have been sent to
+// their topics, so a consumer can close its windows at the watermark without
+// reading the resolved events of every partition.
+type watermarkEvent struct {
+	Changefeed   string   `json:"changefeed"`
+	CheckpointTs uint64   `json:"checkpoint-ts"`
+	Tables       []string `json:"tables"`
+}
+
+// emitWatermark sends the checkpoint watermark to partition zero of the
+// watermark topic, if the watermark topic is set and the checkpoint advances.
+func (k *mqSink) emitWatermark(ctx context.Context, ts uint64, tables []model.TableName) error {
+	if k.watermarkTopic == "" || ts <= k.lastWatermarkTs {
+		return nil
+	}
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.String())
+	}
+	sort.Strings(names)
+	value, err := json.Marshal(&watermarkEvent{
+		Changefeed:   k.id,
+		CheckpointTs: ts,
+		Tables:       names,
+	})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	msg := codec.NewMQMessage(k.protocol, nil, value, ts, model.MqMessageTypeResolved, nil, nil)
+	logger().Debug("emit checkpointTs to watermark topic",
+		zap.String("topic", k.watermarkTopic), zap.Uint64("checkpointTs", ts))
+	if err := k.asyncFlushToPartitionZero(ctx, k.watermarkTopic, msg); err != nil {
+		return errors.Trace(err)
+	}
+	k.lastWatermarkTs = ts
+	return nil
+}
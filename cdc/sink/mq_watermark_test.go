@@ -0,0 +1,61 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitWatermark(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	producer := NewMockProducer()
+	s := &mqSink{mqProducer: producer, protocol: config.ProtocolOpen, id: "test"}
+	tables := []model.TableName{{Schema: "test", Table: "t2"}, {Schema: "test", Table: "t1"}}
+
+	// the watermark topic isn't set.
+	require.Nil(t, s.emitWatermark(ctx, 100, tables))
+	require.Empty(t, producer.mqEvent)
+
+	s.watermarkTopic = "watermark"
+	key := topicPartitionKey{topic: "watermark", partition: 0}
+	require.Nil(t, s.emitWatermark(ctx, 100, tables))
+	require.Len(t, producer.mqEvent[key], 1)
+	require.True(t, producer.flushed)
+	msg := producer.mqEvent[key][0]
+	require.Equal(t, model.MqMessageTypeResolved, msg.Type)
+	require.Equal(t, uint64(100), msg.Ts)
+	event := &watermarkEvent{}
+	require.Nil(t, json.Unmarshal(msg.Value, event))
+	require.Equal(t, &watermarkEvent{
+		Changefeed:   "test",
+		CheckpointTs: 100,
+		Tables:       []string{"test.t1", "test.t2"},
+	}, event)
+
+	// the watermark is only sent when the checkpoint advances.
+	require.Nil(t, s.emitWatermark(ctx, 100, tables))
+	require.Len(t, producer.mqEvent[key], 1)
+	require.Nil(t, s.emitWatermark(ctx, 120, nil))
+	require.Len(t, producer.mqEvent[key], 2)
+	require.Nil(t, json.Unmarshal(producer.mqEvent[key][1].Value, event))
+	require.Empty(t, event.Tables)
+}
@@ -26,7 +26,6 @@ import (
 	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/parser/charset"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -43,6 +42,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -55,6 +55,8 @@ const (
 type mysqlSink struct {
 	db     *sql.DB
 	params *sinkParams
+	// stmtCache is nil if the prepared statement cache is disabled.
+	stmtCache *stmtCache
 
 	filter *tifilter.Filter
 	cyclic *cyclic.Cyclic
@@ -74,6 +76,37 @@ type mysqlSink struct {
 	// metrics used by mysql sink only
 	metricConflictDetectDurationHis prometheus.Observer
 	metricBucketSizeCounters        []prometheus.Counter
+	metricSafeModeGauge             prometheus.Gauge
+	metricSerializeConflictCounter  prometheus.Counter
+	metricFlushConflictCounter      prometheus.Counter
+	metricActiveWorkerGauge         prometheus.Gauge
+
+	// activeWorkers is the number of workers which txns are dispatched to,
+	// it's only accessed by the dispatching goroutine.
+	activeWorkers int
+	// idleRounds is the number of consecutive idle dispatching rounds.
+	idleRounds int
+	// busyRounds is the number of consecutive busy dispatching rounds.
+	busyRounds int
+	// cooldownRounds is the number of dispatching rounds left during which
+	// the active workers are kept after they are scaled.
+	cooldownRounds int
+
+	// safeModeEndTs is the commit ts until which the safe mode lasts, it's
+	// only used when the safe mode has a limited duration.
+	safeModeEndTs uint64
+
+	// errorPolicy is nil if the changefeed doesn't configure the error policy.
+	errorPolicy *sinkErrorPolicy
+	// deadLetterQueue is nil if the changefeed doesn't configure the dead
+	// letter queue.
+	deadLetterQueue *deadLetterQueue
+	// errorIgnorer is nil if the changefeed doesn't configure the ignored
+	// errors.
+	errorIgnorer *errorIgnorer
+	// schemaReconciler is nil if the changefeed doesn't configure the
+	// schema-managed tables.
+	schemaReconciler *schemaReconciler
 
 	forceReplicate bool
 	cancel         func()
@@ -98,36 +131,11 @@ func newMySQLSink(
 
 	params.enableOldValue = replicaConfig.EnableOldValue
 
-	// dsn format of the driver:
-	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
-	username := sinkURI.User.Username()
-	password, _ := sinkURI.User.Password()
-	hostName := sinkURI.Hostname()
-	port := sinkURI.Port()
-	if username == "" {
-		username = "root"
-	}
-	if port == "" {
-		port = "4000"
-	}
-
-	dsnStr := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", username, password, hostName, port, params.tls)
-	dsn, err := dmysql.ParseDSN(dsnStr)
+	dsn, err := buildDSN(sinkURI, params)
 	if err != nil {
-		return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		return nil, err
 	}
-
 	// create test db used for parameter detection
-	// Refer https://github.com/go-sql-driver/mysql#parameters
-	if dsn.Params == nil {
-		dsn.Params = make(map[string]string, 1)
-	}
-	if params.timezone != "" {
-		dsn.Params["time_zone"] = params.timezone
-	}
-	dsn.Params["readTimeout"] = params.readTimeout
-	dsn.Params["writeTimeout"] = params.writeTimeout
-	dsn.Params["timeout"] = params.dialTimeout
 	testDB, err := GetDBConnImpl(ctx, dsn.FormatDSN())
 	if err != nil {
 		return nil, err
@@ -158,7 +166,7 @@ func newMySQLSink(
 	// NOTE: quote the string is necessary to avoid ambiguities.
 	dsn.Params["sql_mode"] = strconv.Quote(dsn.Params["sql_mode"])
 
-	dsnStr, err = generateDSNByParams(ctx, dsn, params, testDB)
+	dsnStr, err := generateDSNByParams(ctx, dsn, params, testDB)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -168,16 +176,16 @@ func newMySQLSink(
 		return nil, errors.Trace(err)
 	}
 	if !gbkSupported {
-		log.Warn("gbk charset is not supported by downstream, "+
+		logger().Warn("gbk charset is not supported by downstream, "+
 			"some types of DDL may fail to be executed",
-			zap.String("hostname", hostName), zap.String("port", port))
+			zap.String("addr", dsn.Addr))
 	}
 	db, err := GetDBConnImpl(ctx, dsnStr)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Info("Start mysql sink")
+	logger().Info("Start mysql sink")
 
 	db.SetMaxIdleConns(params.workerCount)
 	db.SetMaxOpenConns(params.workerCount)
@@ -200,10 +208,53 @@ func newMySQLSink(
 		statistics:                      NewStatistics(ctx, sinkTypeDB),
 		metricConflictDetectDurationHis: metricConflictDetectDurationHis,
 		metricBucketSizeCounters:        metricBucketSizeCounters,
+		metricSafeModeGauge:             safeModeGauge.WithLabelValues(params.changefeedID),
+		metricSerializeConflictCounter:  conflictCounter.WithLabelValues(params.changefeedID, "serialize"),
+		metricFlushConflictCounter:      conflictCounter.WithLabelValues(params.changefeedID, "flush"),
+		metricActiveWorkerGauge:         activeWorkerGauge.WithLabelValues(params.changefeedID),
+		activeWorkers:                   params.workerCount,
 		errCh:                           make(chan error, 1),
 		forceReplicate:                  replicaConfig.ForceReplicate,
 		cancel:                          cancel,
 	}
+	if replicaConfig.Sink != nil {
+		sink.errorPolicy = newSinkErrorPolicy(replicaConfig.Sink.ErrorPolicy)
+		sink.deadLetterQueue, err = newDeadLetterQueue(
+			ctx, db, params.changefeedID, replicaConfig.Sink.DeadLetterQueue)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		sink.errorIgnorer = newErrorIgnorer(params.changefeedID, replicaConfig.Sink.IgnoreErrors)
+		sink.schemaReconciler, err = newSchemaReconciler(
+			db, params.changefeedID, replicaConfig.Sink.SchemaManagedTables)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	if params.safeMode && params.safeModeDuration > 0 && params.currentTs != 0 {
+		// the rows committed before the sink starts may have been written
+		// before the restart, so the safe mode must cover them at least.
+		// The end ts is based on the TSO from PD rather than the local clock,
+		// so that all captures agree on it.
+		sink.safeModeEndTs = oracle.GoTimeToTS(
+			oracle.GetTimeFromTS(params.currentTs).Add(params.safeModeDuration))
+		logger().Info("safe mode is enabled for a limited duration",
+			zap.String("changefeed", params.changefeedID),
+			zap.Duration("duration", params.safeModeDuration),
+			zap.Uint64("endTs", sink.safeModeEndTs))
+	}
+	if params.safeMode {
+		sink.metricSafeModeGauge.Set(1)
+	} else {
+		sink.metricSafeModeGauge.Set(0)
+	}
+
+	if params.preparedStmtCacheSize > 0 {
+		sink.stmtCache = newStmtCache(params.preparedStmtCacheSize)
+	}
 
 	sink.execWaitNotifier = new(notify.Notifier)
 	sink.resolvedNotifier = new(notify.Notifier)
@@ -271,9 +322,21 @@ func (s *mysqlSink) flushRowChangedEvents(ctx context.Context, receiver *notify.
 		case <-receiver.C:
 		}
 		flushedResolvedTsMap, resolvedTxnsMap := s.txnCache.Resolved(&s.tableMaxResolvedTs)
+		if s.schemaReconciler != nil {
+			if err := s.schemaReconciler.reconcile(ctx, resolvedTxnsMap); err != nil {
+				if errors.Cause(err) != context.Canceled {
+					select {
+					case s.errCh <- err:
+					default:
+						logger().Info("mysql sink receives redundant error", zap.Error(err))
+					}
+				}
+				return
+			}
+		}
 		if len(resolvedTxnsMap) == 0 {
 			s.tableMaxResolvedTs.Range(func(key, value interface{}) bool {
-				s.tableCheckpointTs.Store(key, value)
+				s.tableCheckpointTs.Store(key, s.schemaReconciler.checkpointTs(key.(model.TableID), value.(uint64)))
 				return true
 			})
 			continue
@@ -288,20 +351,20 @@ func (s *mysqlSink) flushRowChangedEvents(ctx context.Context, receiver *notify.
 
 		s.dispatchAndExecTxns(ctx, resolvedTxnsMap)
 		for tableID, resolvedTs := range flushedResolvedTsMap {
-			s.tableCheckpointTs.Store(tableID, resolvedTs)
+			s.tableCheckpointTs.Store(tableID, s.schemaReconciler.checkpointTs(tableID, resolvedTs))
 		}
 	}
 }
 
 func (s *mysqlSink) EmitCheckpointTs(_ context.Context, ts uint64, _ []model.TableName) error {
 	// do nothing
-	log.Debug("emit checkpointTs", zap.Uint64("checkpointTs", ts))
+	logger().Debug("emit checkpointTs", zap.Uint64("checkpointTs", ts))
 	return nil
 }
 
 func (s *mysqlSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	if s.filter.ShouldIgnoreDDLEvent(ddl.StartTs, ddl.Type, ddl.TableInfo.Schema, ddl.TableInfo.Table) {
-		log.Info(
+		logger().Info(
 			"DDL event ignored",
 			zap.String("query", ddl.Query),
 			zap.Uint64("startTs", ddl.StartTs),
@@ -309,26 +372,33 @@ func (s *mysqlSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 		)
 		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
 	}
+	if s.schemaReconciler.manages(ddl.TableInfo.Schema, ddl.TableInfo.Table) {
+		logger().Info(
+			"DDL event of the schema-managed table is not executed",
+			zap.String("query", ddl.Query),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.Uint64("commitTs", ddl.CommitTs),
+		)
+		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
+	}
 	s.statistics.AddDDLCount()
 	err := s.execDDLWithMaxRetries(ctx, ddl)
 	return errors.Trace(err)
 }
 
 func (s *mysqlSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEvent) error {
-	return retry.Do(ctx, func() error {
+	err := retry.Do(ctx, func() error {
 		err := s.execDDL(ctx, ddl)
 		if errorutil.IsIgnorableMySQLDDLError(err) {
-			log.Info("execute DDL failed, but error can be ignored", zap.String("query", ddl.Query), zap.Error(err))
+			logger().Info("execute DDL failed, but error can be ignored", zap.String("query", ddl.Query), zap.Error(err))
 			return nil
 		}
 		if err != nil {
-			log.Warn("execute DDL with error, retry later", zap.String("query", ddl.Query), zap.Error(err))
+			logger().Warn("execute DDL with error, retry later", zap.String("query", ddl.Query), zap.Error(err))
 		}
 		return err
-	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
-		retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
-		retry.WithMaxTries(defaultDDLMaxRetryTime),
-		retry.WithIsRetryableErr(cerror.IsRetryableError))
+	}, s.errorPolicy.retryOptions(defaultDDLMaxRetryTime, cerror.IsRetryableError)...)
+	return s.errorPolicy.check(err)
 }
 
 func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
@@ -342,7 +412,7 @@ func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
 		}
 		failpoint.Return(nil)
 	})
-	log.Info("start exec DDL", zap.Any("DDL", ddl))
+	logger().Info("start exec DDL", zap.Any("DDL", ddl))
 	err := s.statistics.RecordDDLExecution(func() error {
 		tx, err := s.db.BeginTx(ctx, nil)
 		if err != nil {
@@ -353,7 +423,7 @@ func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
 			_, err = tx.ExecContext(ctx, "USE "+quotes.QuoteName(ddl.TableInfo.Schema)+";")
 			if err != nil {
 				if rbErr := tx.Rollback(); rbErr != nil {
-					log.Error("Failed to rollback", zap.Error(err))
+					logger().Error("Failed to rollback", zap.Error(err))
 				}
 				return err
 			}
@@ -361,7 +431,7 @@ func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
 
 		if _, err = tx.ExecContext(ctx, ddl.Query); err != nil {
 			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Error("Failed to rollback", zap.String("sql", ddl.Query), zap.Error(err))
+				logger().Error("Failed to rollback", zap.String("sql", ddl.Query), zap.Error(err))
 			}
 			return err
 		}
@@ -372,7 +442,7 @@ func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
 
-	log.Info("Exec DDL succeeded", zap.String("sql", ddl.Query))
+	logger().Info("Exec DDL succeeded", zap.String("sql", ddl.Query))
 	return nil
 }
 
@@ -425,7 +495,7 @@ func (s *mysqlSink) createSinkWorkers(ctx context.Context) error {
 			return err
 		}
 		worker := newMySQLSinkWorker(
-			s.params.maxTxnRow, i, s.metricBucketSizeCounters[i], receiver, s.execDMLs)
+			s.params.maxTxnRow, s.params.maxBatchSize, i, s.metricBucketSizeCounters[i], receiver, s.execDMLs)
 		s.workers[i] = worker
 		go func() {
 			err := worker.run(ctx)
@@ -433,7 +503,7 @@ func (s *mysqlSink) createSinkWorkers(ctx context.Context) error {
 				select {
 				case s.errCh <- err:
 				default:
-					log.Info("mysql sink receives redundant error", zap.Error(err))
+					logger().Info("mysql sink receives redundant error", zap.Error(err))
 				}
 			}
 			worker.cleanup()
@@ -448,7 +518,7 @@ func (s *mysqlSink) notifyAndWaitExec(ctx context.Context) {
 	// avoid data race
 	select {
 	case <-ctx.Done():
-		log.Warn("context is done", zap.Error(ctx.Err()))
+		logger().Warn("context is done", zap.Error(ctx.Err()))
 		return
 	default:
 	}
@@ -469,6 +539,30 @@ func (s *mysqlSink) notifyAndWaitExec(ctx context.Context) {
 	}
 }
 
+// waitWorkersExec flushes the given workers and waits until all the txns
+// dispatched to them are executed.
+func (s *mysqlSink) waitWorkersExec(ctx context.Context, idxs []int) {
+	select {
+	case <-ctx.Done():
+		logger().Warn("context is done", zap.Error(ctx.Err()))
+		return
+	default:
+	}
+	var wg sync.WaitGroup
+	for _, idx := range idxs {
+		s.workers[idx].appendFinishTxn(&wg)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
+}
+
 func (s *mysqlSink) broadcastFinishTxn() {
 	// Note all data txn is sent via channel, the control txn must come after all
 	// data txns in each worker. So after worker receives the control txn, it can
@@ -479,9 +573,10 @@ func (s *mysqlSink) broadcastFinishTxn() {
 }
 
 func (s *mysqlSink) dispatchAndExecTxns(ctx context.Context, txnsGroup map[model.TableID][]*model.SingleTableTxn) {
-	nWorkers := s.params.workerCount
+	nWorkers := s.activeWorkers
 	causality := newCausality()
 	rowsChIdx := 0
+	rows := 0
 
 	sendFn := func(txn *model.SingleTableTxn, keys [][]byte, idx int) {
 		causality.add(keys, idx)
@@ -491,11 +586,16 @@ func (s *mysqlSink) dispatchAndExecTxns(ctx context.Context, txnsGroup map[model
 		keys := genTxnKeys(txn)
 		if conflict, idx := causality.detectConflict(keys); conflict {
 			if idx >= 0 {
+				s.metricSerializeConflictCounter.Inc()
 				sendFn(txn, keys, idx)
 				return
 			}
-			s.notifyAndWaitExec(ctx)
-			causality.reset()
+			s.metricFlushConflictCounter.Inc()
+			// only flush the conflicting workers, so that the others can keep
+			// grouping txns into larger downstream transactions.
+			idxs := causality.conflictWorkers(keys)
+			s.waitWorkersExec(ctx, idxs)
+			causality.removeWorkers(idxs)
 		}
 		sendFn(txn, keys, rowsChIdx)
 		rowsChIdx++
@@ -506,15 +606,89 @@ func (s *mysqlSink) dispatchAndExecTxns(ctx context.Context, txnsGroup map[model
 		startTime := time.Now()
 		resolveConflict(txn)
 		s.metricConflictDetectDurationHis.Observe(time.Since(startTime).Seconds())
+		rows += len(txn.Rows)
 	})
 	s.notifyAndWaitExec(ctx)
+	s.adjustActiveWorkers(rows)
+}
+
+// scaleDownIdleRounds is the number of consecutive idle dispatching rounds
+// before the active workers are scaled down.
+const scaleDownIdleRounds = 16
+
+// scaleUpBusyRounds is the number of consecutive busy dispatching rounds
+// before the active workers are scaled up.
+const scaleUpBusyRounds = 2
+
+// scaleCooldownRounds is the number of dispatching rounds after scaling during
+// which the active workers are kept, so that a bursty backlog doesn't make the
+// number of active workers oscillate.
+const scaleCooldownRounds = 8
+
+// adjustActiveWorkers scales the active workers by the rows dispatched in the
+// last round. It's called after all the workers are flushed and the causality
+// of the round is dropped, so the txns of the next round can be sharded to a
+// different number of workers safely.
+func (s *mysqlSink) adjustActiveWorkers(rows int) {
+	if s.params.minWorkerCount == 0 {
+		return
+	}
+	if s.cooldownRounds > 0 {
+		s.cooldownRounds--
+		return
+	}
+	capacity := s.activeWorkers * s.params.maxTxnRow
+	switch {
+	case rows >= capacity && s.activeWorkers < s.params.workerCount:
+		// every active worker has got a full batch, more workers help.
+		s.idleRounds = 0
+		s.busyRounds++
+		if s.busyRounds >= scaleUpBusyRounds {
+			s.scaleActiveWorkers(s.activeWorkers * 2)
+		}
+	case rows < capacity/4 && s.activeWorkers > s.params.minWorkerCount:
+		s.busyRounds = 0
+		s.idleRounds++
+		if s.idleRounds >= scaleDownIdleRounds {
+			s.scaleActiveWorkers(s.activeWorkers / 2)
+		}
+	default:
+		s.busyRounds = 0
+		s.idleRounds = 0
+	}
+}
+
+// scaleActiveWorkers sets the active workers to n within the bounds, and
+// starts the cooldown.
+func (s *mysqlSink) scaleActiveWorkers(n int) {
+	if n > s.params.workerCount {
+		n = s.params.workerCount
+	}
+	if n < s.params.minWorkerCount {
+		n = s.params.minWorkerCount
+	}
+	s.activeWorkers = n
+	s.busyRounds = 0
+	s.idleRounds = 0
+	s.cooldownRounds = scaleCooldownRounds
+	s.metricActiveWorkerGauge.Set(float64(n))
 }
 
 func (s *mysqlSink) Close(ctx context.Context) error {
 	s.execWaitNotifier.Close()
 	s.resolvedNotifier.Close()
+	if s.stmtCache != nil {
+		s.stmtCache.close()
+	}
 	err := s.db.Close()
 	s.cancel()
+	safeModeGauge.DeleteLabelValues(s.params.changefeedID)
+	conflictCounter.DeleteLabelValues(s.params.changefeedID, "serialize")
+	conflictCounter.DeleteLabelValues(s.params.changefeedID, "flush")
+	activeWorkerGauge.DeleteLabelValues(s.params.changefeedID)
+	deadLetterRowsCounter.DeleteLabelValues(s.params.changefeedID)
+	ignoredRowsCounter.DeleteLabelValues(s.params.changefeedID)
+	s.schemaReconciler.close()
 	return cerror.WrapError(cerror.ErrMySQLConnectionError, err)
 }
 
@@ -525,12 +699,12 @@ func (s *mysqlSink) Barrier(ctx context.Context, tableID model.TableID) error {
 	// See: https://github.com/pingcap/tiflow/issues/4464#issuecomment-1085385382.
 	defer func() {
 		if resolvedTs, loaded := s.tableMaxResolvedTs.LoadAndDelete(tableID); loaded {
-			log.Info("clean up table max resolved ts",
+			logger().Info("clean up table max resolved ts",
 				zap.Int64("tableID", tableID),
 				zap.Uint64("resolvedTs", resolvedTs.(uint64)))
 		}
 		if checkpointTs, loaded := s.tableCheckpointTs.LoadAndDelete(tableID); loaded {
-			log.Info("clean up table checkpoint ts",
+			logger().Info("clean up table checkpoint ts",
 				zap.Int64("tableID", tableID),
 				zap.Uint64("checkpointTs", checkpointTs.(uint64)))
 		}
@@ -544,7 +718,7 @@ func (s *mysqlSink) Barrier(ctx context.Context, tableID model.TableID) error {
 			return errors.Trace(ctx.Err())
 		case <-ticker.C:
 			maxResolvedTs, ok := s.tableMaxResolvedTs.Load(tableID)
-			log.Warn("Barrier doesn't return in time, may be stuck",
+			logger().Warn("Barrier doesn't return in time, may be stuck",
 				zap.Int64("tableID", tableID),
 				zap.Bool("hasResolvedTs", ok),
 				zap.Any("resolvedTs", maxResolvedTs),
@@ -552,7 +726,7 @@ func (s *mysqlSink) Barrier(ctx context.Context, tableID model.TableID) error {
 		default:
 			v, ok := s.tableMaxResolvedTs.Load(tableID)
 			if !ok {
-				log.Info("No table resolvedTs is found", zap.Int64("tableID", tableID))
+				logger().Info("No table resolvedTs is found", zap.Int64("tableID", tableID))
 				return nil
 			}
 			maxResolvedTs := v.(uint64)
@@ -582,7 +756,7 @@ func (s *mysqlSink) getTableCheckpointTs(tableID model.TableID) uint64 {
 
 func logDMLTxnErr(err error) error {
 	if isRetryableDMLError(err) {
-		log.Warn("execute DMLs with error, retry later", zap.Error(err))
+		logger().Warn("execute DMLs with error, retry later", zap.Error(err))
 	}
 	return err
 }
@@ -604,14 +778,16 @@ func isRetryableDMLError(err error) bool {
 	return true
 }
 
-func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDMLs, bucket int) error {
+func (s *mysqlSink) execDMLWithMaxRetries(
+	ctx context.Context, dmls *preparedDMLs, bucket int, isRetryable retry.IsRetryable,
+) error {
 	if len(dmls.sqls) != len(dmls.values) {
-		log.Panic("unexpected number of sqls and values",
+		logger().Panic("unexpected number of sqls and values",
 			zap.Strings("sqls", dmls.sqls),
 			zap.Any("values", dmls.values))
 	}
 
-	return retry.Do(ctx, func() error {
+	err := retry.Do(ctx, func() error {
 		failpoint.Inject("MySQLSinkTxnRandomError", func() {
 			failpoint.Return(logDMLTxnErr(errors.Trace(dmysql.ErrInvalidConn)))
 		})
@@ -627,20 +803,20 @@ func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDML
 
 			for i, query := range dmls.sqls {
 				args := dmls.values[i]
-				log.Debug("exec row", zap.String("sql", query), zap.Any("args", args))
-				if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				logger().Debug("exec row", zap.String("sql", query), zap.Any("args", args))
+				if err := s.execDML(ctx, tx, query, args); err != nil {
 					if rbErr := tx.Rollback(); rbErr != nil {
-						log.Warn("failed to rollback txn", zap.Error(err))
+						logger().Warn("failed to rollback txn", zap.Error(err))
 					}
 					return 0, logDMLTxnErr(cerror.WrapError(cerror.ErrMySQLTxnError, err))
 				}
 			}
 
 			if len(dmls.markSQL) != 0 {
-				log.Debug("exec row", zap.String("sql", dmls.markSQL))
+				logger().Debug("exec row", zap.String("sql", dmls.markSQL))
 				if _, err := tx.ExecContext(ctx, dmls.markSQL); err != nil {
 					if rbErr := tx.Rollback(); rbErr != nil {
-						log.Warn("failed to rollback txn", zap.Error(err))
+						logger().Warn("failed to rollback txn", zap.Error(err))
 					}
 					return 0, logDMLTxnErr(cerror.WrapError(cerror.ErrMySQLTxnError, err))
 				}
@@ -654,12 +830,33 @@ func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDML
 		if err != nil {
 			return errors.Trace(err)
 		}
-		log.Debug("Exec Rows succeeded",
+		logger().Debug("Exec Rows succeeded",
 			zap.String("changefeed", s.params.changefeedID),
 			zap.Int("num of Rows", dmls.rowCount),
 			zap.Int("bucket", bucket))
 		return nil
-	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs), retry.WithBackoffMaxDelay(backoffMaxDelayInMs), retry.WithMaxTries(defaultDMLMaxRetryTime), retry.WithIsRetryableErr(isRetryableDMLError))
+	}, s.errorPolicy.retryOptions(defaultDMLMaxRetryTime, isRetryable)...)
+	return s.errorPolicy.check(err)
+}
+
+// execDML executes the DML in the transaction, with the binary protocol if the
+// prepared statement cache is enabled.
+func (s *mysqlSink) execDML(ctx context.Context, tx *sql.Tx, query string, args []interface{}) error {
+	if s.stmtCache == nil {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	}
+	stmt, release, err := s.stmtCache.get(ctx, s.db, query)
+	if err != nil {
+		// e.g. the downstream reaches `max_prepared_stmt_count`, fall back to textual SQL.
+		logger().Warn("failed to prepare statement, execute it as textual SQL",
+			zap.String("sql", query), zap.Error(err))
+		_, err = tx.ExecContext(ctx, query, args...)
+		return err
+	}
+	defer release()
+	_, err = tx.StmtContext(ctx, stmt).ExecContext(ctx, args...)
+	return err
 }
 
 type preparedDMLs struct {
@@ -675,8 +872,7 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, replicaID uint64,
 	values := make([][]interface{}, 0, len(rows))
 	replaces := make(map[string][][]interface{})
 	rowCount := 0
-	// translateToInsert control the update and insert behavior
-	translateToInsert := s.params.enableOldValue && !s.params.safeMode
+	inSafeMode := false
 
 	// flush cached batch replace or insert, to keep the sequence of DMLs
 	flushCacheDMLs := func() {
@@ -692,6 +888,9 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, replicaID uint64,
 		var query string
 		var args []interface{}
 		quoteTable := quotes.QuoteSchema(row.Table.Schema, row.Table.Table)
+		inSafeMode = s.inSafeMode(row.CommitTs)
+		// translateToInsert control the update and insert behavior
+		translateToInsert := s.params.enableOldValue && !inSafeMode
 
 		// If the old value is enabled, is not in safe mode and is an update event, then translate to UPDATE.
 		// NOTICE: Only update events with the old value feature enabled will have both columns and preColumns.
@@ -751,6 +950,9 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, replicaID uint64,
 		}
 	}
 	flushCacheDMLs()
+	if s.safeModeEndTs != 0 && len(rows) > 0 && !inSafeMode {
+		s.metricSafeModeGauge.Set(0)
+	}
 
 	dmls := &preparedDMLs{
 		sqls:   sqls,
@@ -769,10 +971,19 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, replicaID uint64,
 	return dmls
 }
 
+// inSafeMode returns whether the row committed at commitTs is written in the
+// safe mode.
+func (s *mysqlSink) inSafeMode(commitTs uint64) bool {
+	if !s.params.safeMode {
+		return false
+	}
+	return s.safeModeEndTs == 0 || commitTs <= s.safeModeEndTs
+}
+
 func (s *mysqlSink) execDMLs(ctx context.Context, rows []*model.RowChangedEvent, replicaID uint64, bucket int) error {
 	failpoint.Inject("SinkFlushDMLPanic", func() {
 		time.Sleep(time.Second)
-		log.Fatal("SinkFlushDMLPanic")
+		logger().Fatal("SinkFlushDMLPanic")
 	})
 	failpoint.Inject("MySQLSinkExecDMLError", func() {
 		// Add a delay to ensure the sink worker with `MySQLSinkHangLongTime`
@@ -781,14 +992,59 @@ func (s *mysqlSink) execDMLs(ctx context.Context, rows []*model.RowChangedEvent,
 		failpoint.Return(errors.Trace(dmysql.ErrInvalidConn))
 	})
 	dmls := s.prepareDMLs(rows, replicaID, bucket)
-	log.Debug("prepare DMLs", zap.Any("rows", rows), zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
-	if err := s.execDMLWithMaxRetries(ctx, dmls, bucket); err != nil {
-		log.Error("execute DMLs failed", zap.String("err", err.Error()))
+	logger().Debug("prepare DMLs", zap.Any("rows", rows), zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+	err := s.execDMLWithMaxRetries(ctx, dmls, bucket, isRetryableDMLError)
+	if err != nil && (s.deadLetterQueue.accepts(err) || s.errorIgnorer.accepts(err)) {
+		logger().Warn("execute DMLs failed, execute the rows one by one to find the rows to skip",
+			zap.String("changefeed", s.params.changefeedID), zap.Int("bucket", bucket), zap.Error(err))
+		err = s.execDMLsOneByOne(ctx, rows, replicaID, bucket)
+	}
+	if err != nil {
+		logger().Error("execute DMLs failed", zap.String("err", err.Error()))
 		return errors.Trace(err)
 	}
 	return nil
 }
 
+// execDMLsOneByOne executes the rows in their own transactions, writes the
+// rows failed with the errors of the dead letter queue into the queue, and
+// skips the rows failed with the ignored errors. These errors were retried
+// when the rows were executed together, so they are not retried again.
+func (s *mysqlSink) execDMLsOneByOne(
+	ctx context.Context, rows []*model.RowChangedEvent, replicaID uint64, bucket int,
+) error {
+	isRetryable := func(err error) bool {
+		return !s.deadLetterQueue.accepts(err) && !s.errorIgnorer.accepts(err) &&
+			isRetryableDMLError(err)
+	}
+	for _, row := range rows {
+		dmls := s.prepareDMLs([]*model.RowChangedEvent{row}, replicaID, bucket)
+		err := s.execDMLWithMaxRetries(ctx, dmls, bucket, isRetryable)
+		if err == nil {
+			continue
+		}
+		if s.errorIgnorer.accepts(err) {
+			if err = s.errorIgnorer.ignore(row, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !s.deadLetterQueue.accepts(err) {
+			return err
+		}
+		logger().Warn("write the row into the dead letter queue",
+			zap.String("changefeed", s.params.changefeedID),
+			zap.String("schema", row.Table.Schema),
+			zap.String("table", row.Table.Table),
+			zap.Uint64("commitTs", row.CommitTs),
+			zap.Error(err))
+		if err = s.deadLetterQueue.write(ctx, s.db, row, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // if the column value type is []byte and charset is not binary, we get its string
 // representation. Because if we use the byte array respresentation, the go-sql-driver
 // will automatically set `_binary` charset for that column, which is not expected.
@@ -992,6 +1248,68 @@ func buildColumnList(names []string) string {
 	return b.String()
 }
 
+// buildDSN builds the dsn connecting to the downstream of the sink uri, the
+// params detected from the downstream are not set, see generateDSNByParams.
+func buildDSN(sinkURI *url.URL, params *sinkParams) (*dmysql.Config, error) {
+	// dsn format of the driver:
+	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
+	username := sinkURI.User.Username()
+	password, _ := sinkURI.User.Password()
+	port := sinkURI.Port()
+	if username == "" {
+		username = "root"
+	}
+	if port == "" {
+		port = "4000"
+	}
+
+	dsnStr := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", username, password, sinkURI.Hostname(), port, params.tls)
+	dsn, err := dmysql.ParseDSN(dsnStr)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+	}
+
+	// Refer https://github.com/go-sql-driver/mysql#parameters
+	if dsn.Params == nil {
+		dsn.Params = make(map[string]string, 1)
+	}
+	if params.timezone != "" {
+		dsn.Params["time_zone"] = params.timezone
+	}
+	dsn.Params["readTimeout"] = params.readTimeout
+	dsn.Params["writeTimeout"] = params.writeTimeout
+	dsn.Params["timeout"] = params.dialTimeout
+	return dsn, nil
+}
+
+// OpenMySQLDB opens the downstream of the mysql sink uri with the dsn the
+// mysql sink connects with, e.g. the TLS and time zone in the sink uri are
+// applied. The id distinguishes the TLS config registered for it.
+func OpenMySQLDB(ctx context.Context, id string, sinkURI string) (*sql.DB, error) {
+	uri, err := url.Parse(sinkURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	params, err := parseSinkURIToParams(ctx, uri, map[string]string{OptChangefeedID: id})
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := buildDSN(uri, params)
+	if err != nil {
+		return nil, err
+	}
+	testDB, err := GetDBConnImpl(ctx, dsn.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	defer testDB.Close()
+	dsnStr, err := generateDSNByParams(ctx, dsn, params, testDB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return GetDBConnImpl(ctx, dsnStr)
+}
+
 // GetDBConnImpl is the implement holder to get db connection. Export it for tests
 var GetDBConnImpl = getDBConn
 
@@ -1004,7 +1322,7 @@ func getDBConn(ctx context.Context, dsnStr string) (*sql.DB, error) {
 	if err != nil {
 		// close db to recycle resources
 		if closeErr := db.Close(); closeErr != nil {
-			log.Warn("close db failed", zap.Error(err))
+			logger().Warn("close db failed", zap.Error(err))
 		}
 		return nil, cerror.ErrMySQLConnectionError.Wrap(err).GenWithStack("fail to open MySQL connection")
 	}
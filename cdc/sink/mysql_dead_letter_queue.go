@@ -0,0 +1,141 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// deadLetterQueue writes the rows the MySQL sink fails to apply into a table
+// of the downstream, so the changefeed can skip them.
+type deadLetterQueue struct {
+	changefeedID string
+	quoteTable   string
+	classes      map[string]struct{}
+
+	metricRowsCounter prometheus.Counter
+}
+
+func newDeadLetterQueue(
+	ctx context.Context, db *sql.DB, changefeedID string, cfg *config.DeadLetterQueueConfig,
+) (*deadLetterQueue, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	schema, table := cfg.SchemaTable()
+	q := &deadLetterQueue{
+		changefeedID:      changefeedID,
+		quoteTable:        quotes.QuoteSchema(schema, table),
+		classes:           make(map[string]struct{}, len(cfg.Errors)),
+		metricRowsCounter: deadLetterRowsCounter.WithLabelValues(changefeedID),
+	}
+	for _, class := range cfg.Errors {
+		q.classes[class] = struct{}{}
+	}
+	if _, err := db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+quotes.QuoteName(schema)); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	changefeed VARCHAR(255) NOT NULL,
+	source_schema VARCHAR(255) NOT NULL,
+	source_table VARCHAR(255) NOT NULL,
+	commit_ts BIGINT UNSIGNED NOT NULL,
+	op VARCHAR(16) NOT NULL,
+	error TEXT NOT NULL,
+	row_data LONGTEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`, q.quoteTable)); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	return q, nil
+}
+
+// accepts returns whether the row failed with the error goes to the queue.
+func (q *deadLetterQueue) accepts(err error) bool {
+	if q == nil {
+		return false
+	}
+	for _, class := range sinkErrorClasses(err) {
+		if _, ok := q.classes[class]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// write writes the row failed with the error into the queue.
+func (q *deadLetterQueue) write(
+	ctx context.Context, db *sql.DB, row *model.RowChangedEvent, rowErr error,
+) error {
+	data, err := deadLetterRowData(row)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	op := "update"
+	if row.IsInsert() {
+		op = "insert"
+	} else if row.IsDelete() {
+		op = "delete"
+	}
+	_, err = db.ExecContext(ctx, "INSERT INTO "+q.quoteTable+
+		" (changefeed, source_schema, source_table, commit_ts, op, error, row_data) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		q.changefeedID, row.Table.Schema, row.Table.Table, row.CommitTs, op, rowErr.Error(), data)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	q.metricRowsCounter.Inc()
+	return nil
+}
+
+// deadLetterRowData encodes the columns of the row as a JSON document.
+func deadLetterRowData(row *model.RowChangedEvent) (string, error) {
+	encode := func(cols []*model.Column) map[string]interface{} {
+		if len(cols) == 0 {
+			return nil
+		}
+		values := make(map[string]interface{}, len(cols))
+		for _, col := range cols {
+			if col == nil {
+				continue
+			}
+			// the values of binary columns are encoded with base64 by JSON.
+			if b, ok := col.Value.([]byte); ok && col.Charset != "" && col.Charset != charset.CharsetBin {
+				values[col.Name] = string(b)
+			} else {
+				values[col.Name] = col.Value
+			}
+		}
+		return values
+	}
+	data, err := json.Marshal(struct {
+		Columns    map[string]interface{} `json:"columns,omitempty"`
+		PreColumns map[string]interface{} `json:"pre-columns,omitempty"`
+	}{
+		Columns:    encode(row.Columns),
+		PreColumns: encode(row.PreColumns),
+	})
+	return string(data), err
+}
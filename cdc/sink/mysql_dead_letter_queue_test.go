@@ -0,0 +1,175 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterRowData(t *testing.T) {
+	t.Parallel()
+
+	data, err := deadLetterRowData(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "s1", Table: "t1"},
+		PreColumns: []*model.Column{
+			{Name: "a", Type: mysql.TypeLong, Value: 1},
+			{Name: "b", Type: mysql.TypeVarchar, Charset: charset.CharsetUTF8MB4, Value: []byte("old")},
+		},
+		Columns: []*model.Column{
+			{Name: "a", Type: mysql.TypeLong, Value: 1},
+			{Name: "b", Type: mysql.TypeVarchar, Charset: charset.CharsetUTF8MB4, Value: []byte("new")},
+			{Name: "c", Type: mysql.TypeBlob, Charset: charset.CharsetBin, Value: []byte("bin")},
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t,
+		`{"columns":{"a":1,"b":"new","c":"Ymlu"},"pre-columns":{"a":1,"b":"old"}}`, data)
+}
+
+func TestDeadLetterQueueAccepts(t *testing.T) {
+	t.Parallel()
+
+	var q *deadLetterQueue
+	dataTooLong := &dmysql.MySQLError{Number: mysql.ErrDataTooLong, Message: "Data too long"}
+	require.False(t, q.accepts(dataTooLong))
+
+	q = &deadLetterQueue{classes: map[string]struct{}{
+		config.SinkErrorClassDataTooLong: {},
+		"1452":                           {},
+	}}
+	require.True(t, q.accepts(cerror.WrapError(cerror.ErrMySQLTxnError, dataTooLong)))
+	require.True(t, q.accepts(errors.Trace(&dmysql.MySQLError{Number: mysql.ErrNoReferencedRow2})))
+	require.False(t, q.accepts(&dmysql.MySQLError{Number: mysql.ErrDupEntry}))
+	require.False(t, q.accepts(errors.New("unknown")))
+}
+
+func TestExecDMLDeadLetterQueue(t *testing.T) {
+	rows := []*model.RowChangedEvent{
+		{
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			CommitTs: 10,
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				},
+			},
+		},
+		{
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			CommitTs: 10,
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 2,
+				},
+			},
+		},
+	}
+
+	errDataTooLong := &dmysql.MySQLError{
+		Number:  mysql.ErrDataTooLong,
+		Message: "Data too long for column 'a'",
+	}
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() {
+			dbIndex++
+		}()
+		if dbIndex == 0 {
+			// test db
+			db, err := mockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+		// normal db
+		db, mock, err := sqlmock.New()
+		require.Nil(t, err)
+		mock.ExpectExec(regexp.QuoteMeta("CREATE DATABASE IF NOT EXISTS `dlq`")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS `dlq`.`rows`")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		// the rows are executed together first.
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `s1`.`t1`(`a`) VALUES (?),(?)")).
+			WithArgs(1, 2).
+			WillReturnError(errDataTooLong)
+		mock.ExpectRollback()
+		// then they are executed one by one.
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `s1`.`t1`(`a`) VALUES (?)")).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `s1`.`t1`(`a`) VALUES (?)")).
+			WithArgs(2).
+			WillReturnError(errDataTooLong)
+		mock.ExpectRollback()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `dlq`.`rows` (changefeed, source_schema, source_table, "+
+			"commit_ts, op, error, row_data) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+			WithArgs("test-changefeed", "s1", "t1", sqlmock.AnyArg(), "insert",
+				sqlmock.AnyArg(), `{"columns":{"a":2}}`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectClose()
+		return db, nil
+	}
+	backupGetDBConn := GetDBConnImpl
+	GetDBConnImpl = mockGetDBConn
+	defer func() {
+		GetDBConnImpl = backupGetDBConn
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeed := "test-changefeed"
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1")
+	require.Nil(t, err)
+	rc := config.GetDefaultReplicaConfig()
+	rc.Sink.ErrorPolicy = &config.SinkErrorPolicy{MaxTries: 1}
+	rc.Sink.DeadLetterQueue = &config.DeadLetterQueueConfig{
+		Table:  "dlq.rows",
+		Errors: []string{config.SinkErrorClassDataTooLong},
+	}
+	f, err := filter.NewFilter(rc)
+	require.Nil(t, err)
+	sink, err := newMySQLSink(ctx, changefeed, sinkURI, f, rc, map[string]string{})
+	require.Nil(t, err)
+
+	err = sink.(*mysqlSink).execDMLs(ctx, rows, 1 /* replicaID */, 1 /* bucket */)
+	require.Nil(t, err)
+
+	err = sink.Close(ctx)
+	require.Nil(t, err)
+}
@@ -0,0 +1,162 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"syscall"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+)
+
+// sinkErrorPolicy classifies the errors of the MySQL sink by the error policy
+// of the changefeed, a nil sinkErrorPolicy keeps the default policy.
+type sinkErrorPolicy struct {
+	retriable            map[string]struct{}
+	fatal                map[string]struct{}
+	maxTries             int64
+	backoffBaseDelayInMs int64
+	backoffMaxDelayInMs  int64
+}
+
+func newSinkErrorPolicy(cfg *config.SinkErrorPolicy) *sinkErrorPolicy {
+	if cfg == nil {
+		return nil
+	}
+	p := &sinkErrorPolicy{
+		retriable:            make(map[string]struct{}, len(cfg.Retriable)),
+		fatal:                make(map[string]struct{}, len(cfg.Fatal)),
+		maxTries:             cfg.MaxTries,
+		backoffBaseDelayInMs: cfg.BackoffBaseDelayInMs,
+		backoffMaxDelayInMs:  cfg.BackoffMaxDelayInMs,
+	}
+	for _, class := range cfg.Retriable {
+		p.retriable[class] = struct{}{}
+	}
+	for _, class := range cfg.Fatal {
+		p.fatal[class] = struct{}{}
+	}
+	return p
+}
+
+// sinkErrorClasses returns the classes of the error, including its MySQL
+// error code if it's returned by the downstream.
+func sinkErrorClasses(err error) []string {
+	if code, ok := getSQLErrCode(err); ok {
+		classes := []string{strconv.Itoa(int(code))}
+		switch code {
+		case mysql.ErrLockDeadlock:
+			classes = append(classes, config.SinkErrorClassDeadlock)
+		case mysql.ErrLockWaitTimeout:
+			classes = append(classes, config.SinkErrorClassLockWaitTimeout)
+		case mysql.ErrDupEntry:
+			classes = append(classes, config.SinkErrorClassDuplicateKey)
+		case mysql.ErrDataTooLong:
+			classes = append(classes, config.SinkErrorClassDataTooLong)
+		case mysql.ErrNoReferencedRow, mysql.ErrRowIsReferenced,
+			mysql.ErrNoReferencedRow2, mysql.ErrRowIsReferenced2:
+			classes = append(classes, config.SinkErrorClassForeignKey)
+		case mysql.ErrNoSuchTable, mysql.ErrBadDB:
+			classes = append(classes, config.SinkErrorClassNoSuchTable)
+		case mysql.ErrBadField:
+			classes = append(classes, config.SinkErrorClassUnknownColumn)
+		}
+		return classes
+	}
+	cause := errors.Cause(err)
+	if cause == driver.ErrBadConn || cause == dmysql.ErrInvalidConn || cause == io.EOF ||
+		errors.Is(cause, syscall.ECONNRESET) || errors.Is(cause, syscall.ECONNREFUSED) ||
+		errors.Is(cause, syscall.EPIPE) {
+		return []string{config.SinkErrorClassConnection}
+	}
+	return nil
+}
+
+// classify returns the class of the error which is fatal or retriable by the
+// policy, and whether it's fatal.
+func (p *sinkErrorPolicy) classify(err error) (class string, fatal bool) {
+	for _, class := range sinkErrorClasses(err) {
+		if _, ok := p.fatal[class]; ok {
+			return class, true
+		}
+		if _, ok := p.retriable[class]; ok {
+			return class, false
+		}
+	}
+	return "", false
+}
+
+// retryOptions returns the options of retrying the operations of the sink,
+// the defaults are used for the errors not classified by the policy.
+func (p *sinkErrorPolicy) retryOptions(
+	defaultMaxTries int64, defaultIsRetryable retry.IsRetryable,
+) []retry.Option {
+	if p == nil {
+		return []retry.Option{
+			retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
+			retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
+			retry.WithMaxTries(defaultMaxTries),
+			retry.WithIsRetryableErr(defaultIsRetryable),
+		}
+	}
+	maxTries, baseDelay, maxDelay := p.maxTries, p.backoffBaseDelayInMs, p.backoffMaxDelayInMs
+	if maxTries == 0 {
+		maxTries = defaultMaxTries
+	}
+	if baseDelay == 0 {
+		baseDelay = backoffBaseDelayInMs
+	}
+	if maxDelay == 0 {
+		maxDelay = backoffMaxDelayInMs
+		if maxDelay < baseDelay {
+			maxDelay = baseDelay
+		}
+	}
+	return []retry.Option{
+		retry.WithBackoffBaseDelay(baseDelay),
+		retry.WithBackoffMaxDelay(maxDelay),
+		retry.WithMaxTries(maxTries),
+		retry.WithIsRetryableErr(func(err error) bool {
+			if !cerror.IsRetryableError(err) {
+				return false
+			}
+			class, fatal := p.classify(err)
+			if class == "" {
+				return defaultIsRetryable(err)
+			}
+			return !fatal
+		}),
+	}
+}
+
+// check returns the error failing the changefeed at once if the error is fatal
+// by the policy, otherwise the error is returned as it is.
+func (p *sinkErrorPolicy) check(err error) error {
+	if p == nil || err == nil {
+		return err
+	}
+	if class, fatal := p.classify(err); fatal {
+		// The error is generated without the cause, so its code isn't lost
+		// when the cause of it is taken.
+		return cerror.ErrSinkFatalByErrorPolicy.GenWithStackByArgs(class, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinkErrorClasses(t *testing.T) {
+	t.Parallel()
+
+	deadlock := cerror.WrapError(cerror.ErrMySQLTxnError,
+		&dmysql.MySQLError{Number: mysql.ErrLockDeadlock, Message: "Deadlock found"})
+	require.Equal(t, []string{"1213", config.SinkErrorClassDeadlock}, sinkErrorClasses(deadlock))
+	require.Equal(t, []string{"1452", config.SinkErrorClassForeignKey},
+		sinkErrorClasses(&dmysql.MySQLError{Number: mysql.ErrNoReferencedRow2}))
+	require.Equal(t, []string{"1054", config.SinkErrorClassUnknownColumn},
+		sinkErrorClasses(&dmysql.MySQLError{Number: mysql.ErrBadField}))
+	require.Equal(t, []string{"1105"}, sinkErrorClasses(&dmysql.MySQLError{Number: mysql.ErrUnknown}))
+	require.Equal(t, []string{config.SinkErrorClassConnection},
+		sinkErrorClasses(errors.Trace(driver.ErrBadConn)))
+	require.Nil(t, sinkErrorClasses(errors.New("unknown")))
+}
+
+func TestSinkErrorPolicy(t *testing.T) {
+	t.Parallel()
+
+	dupEntry := &dmysql.MySQLError{Number: mysql.ErrDupEntry, Message: "Duplicate entry"}
+	dataTooLong := &dmysql.MySQLError{Number: mysql.ErrDataTooLong, Message: "Data too long"}
+	noSuchTable := &dmysql.MySQLError{Number: mysql.ErrNoSuchTable, Message: "Table doesn't exist"}
+
+	// run returns the number of tries and the error checked by the policy.
+	run := func(p *sinkErrorPolicy, opErr error) (int, error) {
+		tries := 0
+		err := retry.Do(context.Background(), func() error {
+			tries++
+			return cerror.WrapError(cerror.ErrMySQLTxnError, opErr)
+		}, p.retryOptions(3, isRetryableDMLError)...)
+		return tries, p.check(err)
+	}
+
+	// the default policy.
+	var p *sinkErrorPolicy
+	tries, err := run(p, noSuchTable)
+	require.Equal(t, 1, tries)
+	require.Regexp(t, ".*Table doesn't exist.*", err)
+	tries, _ = run(p, dataTooLong)
+	require.Equal(t, 3, tries)
+
+	p = newSinkErrorPolicy(&config.SinkErrorPolicy{
+		Retriable:            []string{config.SinkErrorClassNoSuchTable},
+		Fatal:                []string{config.SinkErrorClassDataTooLong, "1062"},
+		MaxTries:             5,
+		BackoffBaseDelayInMs: 1,
+		BackoffMaxDelayInMs:  1,
+	})
+	tries, _ = run(p, noSuchTable)
+	require.Equal(t, 5, tries)
+	tries, err = run(p, dataTooLong)
+	require.Equal(t, 1, tries)
+	require.True(t, cerror.ErrSinkFatalByErrorPolicy.Equal(err))
+	require.True(t, cerror.ChangefeedFastFailError(errors.Trace(err)))
+	require.Regexp(t, ".*the data-too-long error of sink is fatal.*Data too long.*", err)
+	tries, err = run(p, dupEntry)
+	require.Equal(t, 1, tries)
+	require.Regexp(t, ".*the 1062 error of sink is fatal.*", err)
+	// the errors not classified by the policy follow the default policy.
+	tries, err = run(p, errors.New("unknown"))
+	require.Equal(t, 5, tries)
+	require.Regexp(t, ".*reach maximum try: 5.*", err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// errorIgnorer skips the rows the MySQL sink fails to apply with the ignored
+// errors, and fails the changefeed once too many rows are skipped.
+type errorIgnorer struct {
+	changefeedID string
+	classes      map[string]struct{}
+	maxRows      int64
+	// ignoredRows is the count of the skipped rows, it's accessed atomically
+	// as the rows are executed by the workers concurrently.
+	ignoredRows int64
+
+	metricRowsCounter prometheus.Counter
+}
+
+func newErrorIgnorer(changefeedID string, cfg *config.IgnoreErrorsConfig) *errorIgnorer {
+	if cfg == nil {
+		return nil
+	}
+	i := &errorIgnorer{
+		changefeedID:      changefeedID,
+		classes:           make(map[string]struct{}, len(cfg.Errors)),
+		maxRows:           cfg.MaxIgnoredRows,
+		metricRowsCounter: ignoredRowsCounter.WithLabelValues(changefeedID),
+	}
+	if i.maxRows == 0 {
+		i.maxRows = config.DefaultMaxIgnoredRows
+	}
+	for _, class := range cfg.Errors {
+		i.classes[class] = struct{}{}
+	}
+	return i
+}
+
+// accepts returns whether the row failed with the error can be skipped.
+func (i *errorIgnorer) accepts(err error) bool {
+	if i == nil {
+		return false
+	}
+	for _, class := range sinkErrorClasses(err) {
+		if _, ok := i.classes[class]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ignore skips the row failed with the error, it returns an error failing
+// the changefeed if too many rows have been skipped.
+func (i *errorIgnorer) ignore(row *model.RowChangedEvent, rowErr error) error {
+	if n := atomic.AddInt64(&i.ignoredRows, 1); n > i.maxRows {
+		// The error is generated without the cause, so its code isn't lost
+		// when the cause of it is taken.
+		return cerror.ErrSinkIgnoredRowsExceeded.GenWithStackByArgs(i.maxRows, rowErr.Error())
+	}
+	logger().Warn("skip the row failed with the ignored error",
+		zap.String("changefeed", i.changefeedID),
+		zap.String("schema", row.Table.Schema),
+		zap.String("table", row.Table.Table),
+		zap.Uint64("commitTs", row.CommitTs),
+		zap.Error(rowErr))
+	i.metricRowsCounter.Inc()
+	return nil
+}
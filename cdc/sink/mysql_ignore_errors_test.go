@@ -0,0 +1,157 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorIgnorer(t *testing.T) {
+	t.Parallel()
+
+	var i *errorIgnorer
+	dupEntry := &dmysql.MySQLError{Number: mysql.ErrDupEntry, Message: "Duplicate entry"}
+	require.False(t, i.accepts(dupEntry))
+
+	i = newErrorIgnorer("test-ignorer", &config.IgnoreErrorsConfig{
+		Errors:         []string{config.SinkErrorClassDuplicateKey, "1054"},
+		MaxIgnoredRows: 2,
+	})
+	require.True(t, i.accepts(cerror.WrapError(cerror.ErrMySQLTxnError, dupEntry)))
+	require.True(t, i.accepts(errors.Trace(&dmysql.MySQLError{Number: mysql.ErrBadField})))
+	require.False(t, i.accepts(&dmysql.MySQLError{Number: mysql.ErrDataTooLong}))
+	require.False(t, i.accepts(errors.New("unknown")))
+
+	row := &model.RowChangedEvent{Table: &model.TableName{Schema: "s1", Table: "t1"}}
+	require.Nil(t, i.ignore(row, dupEntry))
+	require.Nil(t, i.ignore(row, dupEntry))
+	err := i.ignore(row, dupEntry)
+	require.True(t, cerror.ErrSinkIgnoredRowsExceeded.Equal(err))
+	require.True(t, cerror.ChangefeedFastFailError(err))
+
+	i = newErrorIgnorer("test-ignorer", &config.IgnoreErrorsConfig{
+		Errors: []string{config.SinkErrorClassDuplicateKey},
+	})
+	require.Equal(t, int64(config.DefaultMaxIgnoredRows), i.maxRows)
+}
+
+func TestExecDMLIgnoreErrors(t *testing.T) {
+	rows := []*model.RowChangedEvent{
+		{
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			CommitTs: 10,
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				},
+			},
+		},
+		{
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			CommitTs: 10,
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 2,
+				},
+			},
+		},
+	}
+
+	errUnknownColumn := &dmysql.MySQLError{
+		Number:  mysql.ErrBadField,
+		Message: "Unknown column 'a' in 'field list'",
+	}
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() {
+			dbIndex++
+		}()
+		if dbIndex == 0 {
+			// test db
+			db, err := mockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+		// normal db
+		db, mock, err := sqlmock.New()
+		require.Nil(t, err)
+		// the rows are executed together first.
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `s1`.`t1`(`a`) VALUES (?),(?)")).
+			WithArgs(1, 2).
+			WillReturnError(errUnknownColumn)
+		mock.ExpectRollback()
+		// then they are executed one by one, and the failed one is skipped.
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `s1`.`t1`(`a`) VALUES (?)")).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `s1`.`t1`(`a`) VALUES (?)")).
+			WithArgs(2).
+			WillReturnError(errUnknownColumn)
+		mock.ExpectRollback()
+		mock.ExpectClose()
+		return db, nil
+	}
+	backupGetDBConn := GetDBConnImpl
+	GetDBConnImpl = mockGetDBConn
+	defer func() {
+		GetDBConnImpl = backupGetDBConn
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeed := "test-changefeed"
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1")
+	require.Nil(t, err)
+	rc := config.GetDefaultReplicaConfig()
+	rc.Sink.ErrorPolicy = &config.SinkErrorPolicy{MaxTries: 1}
+	rc.Sink.IgnoreErrors = &config.IgnoreErrorsConfig{
+		Errors: []string{config.SinkErrorClassUnknownColumn},
+	}
+	f, err := filter.NewFilter(rc)
+	require.Nil(t, err)
+	sink, err := newMySQLSink(ctx, changefeed, sinkURI, f, rc, map[string]string{})
+	require.Nil(t, err)
+
+	err = sink.(*mysqlSink).execDMLs(ctx, rows, 1 /* replicaID */, 1 /* bucket */)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), sink.(*mysqlSink).errorIgnorer.ignoredRows)
+
+	err = sink.Close(ctx)
+	require.Nil(t, err)
+}
@@ -24,7 +24,6 @@ import (
 
 	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -47,6 +46,7 @@ const (
 	defaultFlushInterval       = time.Millisecond * 50
 	defaultBatchReplaceEnabled = true
 	defaultBatchReplaceSize    = 20
+	defaultMaxBatchSize        = 256
 	defaultReadTimeout         = "2m"
 	defaultWriteTimeout        = "2m"
 	defaultDialTimeout         = "2m"
@@ -58,6 +58,7 @@ const (
 var defaultParams = &sinkParams{
 	workerCount:         DefaultWorkerCount,
 	maxTxnRow:           DefaultMaxTxnRow,
+	maxBatchSize:        defaultMaxBatchSize,
 	tidbTxnMode:         defaultTiDBTxnMode,
 	batchReplaceEnabled: defaultBatchReplaceEnabled,
 	batchReplaceSize:    defaultBatchReplaceSize,
@@ -89,6 +90,24 @@ type sinkParams struct {
 	safeMode            bool
 	timezone            string
 	tls                 string
+	// safeModeDuration is how long the safe mode lasts after the sink starts,
+	// 0 means the safe mode is always enabled if safeMode is true.
+	safeModeDuration time.Duration
+	// currentTs is the TSO from PD when the sink is created, the safe mode
+	// lasts for safeModeDuration after it. 0 means it's unknown, and the safe
+	// mode is always enabled if safeMode is true.
+	currentTs uint64
+	// minWorkerCount is the minimum number of active workers, the active
+	// workers are scaled between it and workerCount by the backlog. 0 means
+	// all the workers are always active.
+	minWorkerCount int
+	// preparedStmtCacheSize is the capacity of the prepared statement cache,
+	// 0 means executing DMLs as textual SQLs.
+	preparedStmtCacheSize int
+	// maxBatchSize is the max number of upstream txns a worker groups into
+	// one downstream txn, the txns are grouped until they conflict with the
+	// txns of other workers, or their rows exceed maxTxnRow.
+	maxBatchSize int
 }
 
 func (s *sinkParams) Clone() *sinkParams {
@@ -105,6 +124,13 @@ func parseSinkURIToParams(ctx context.Context, sinkURI *url.URL, opts map[string
 	if caddr, ok := opts[OptCaptureAddr]; ok {
 		params.captureAddr = caddr
 	}
+	if ts, ok := opts[OptCurrentTs]; ok {
+		currentTs, err := strconv.ParseUint(ts, 10, 64)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		params.currentTs = currentTs
+	}
 
 	if sinkURI == nil {
 		return nil, cerror.ErrMySQLConnectionError.GenWithStack("fail to open MySQL sink, empty URL")
@@ -124,12 +150,24 @@ func parseSinkURIToParams(ctx context.Context, sinkURI *url.URL, opts map[string
 				fmt.Errorf("invalid worker-count %d, which must be greater than 0", c))
 		}
 		if c > maxWorkerCount {
-			log.Warn("worker-count too large",
+			logger().Warn("worker-count too large",
 				zap.Int("original", c), zap.Int("override", maxWorkerCount))
 			c = maxWorkerCount
 		}
 		params.workerCount = c
 	}
+	s = sinkURI.Query().Get("min-worker-count")
+	if s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if c <= 0 || c > params.workerCount {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid min-worker-count %d, which must be in [1, %d]", c, params.workerCount))
+		}
+		params.minWorkerCount = c
+	}
 	s = sinkURI.Query().Get("max-txn-row")
 	if s != "" {
 		c, err := strconv.Atoi(s)
@@ -141,27 +179,41 @@ func parseSinkURIToParams(ctx context.Context, sinkURI *url.URL, opts map[string
 				fmt.Errorf("invalid max-txn-row %d, which must be greater than 0", c))
 		}
 		if c > maxMaxTxnRow {
-			log.Warn("max-txn-row too large",
+			logger().Warn("max-txn-row too large",
 				zap.Int("original", c), zap.Int("override", maxMaxTxnRow))
 			c = maxMaxTxnRow
 		}
 		params.maxTxnRow = c
 	}
+	s = sinkURI.Query().Get("max-batch-size")
+	if s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if c <= 0 {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid max-batch-size %d, which must be greater than 0", c))
+		}
+		params.maxBatchSize = c
+	}
 	s = sinkURI.Query().Get("tidb-txn-mode")
 	if s != "" {
 		if s == "pessimistic" || s == "optimistic" {
 			params.tidbTxnMode = s
 		} else {
-			log.Warn("invalid tidb-txn-mode, should be pessimistic or optimistic, use optimistic as default")
+			logger().Warn("invalid tidb-txn-mode, should be pessimistic or optimistic, use optimistic as default")
 		}
 	}
 	if sinkURI.Query().Get("ssl-ca") != "" {
 		credential := security.Credential{
-			CAPath:   sinkURI.Query().Get("ssl-ca"),
-			CertPath: sinkURI.Query().Get("ssl-cert"),
-			KeyPath:  sinkURI.Query().Get("ssl-key"),
+			CAPath:          sinkURI.Query().Get("ssl-ca"),
+			CertPath:        sinkURI.Query().Get("ssl-cert"),
+			KeyPath:         sinkURI.Query().Get("ssl-key"),
+			CertAllowedSAN:  security.SplitPins(sinkURI.Query().Get("ssl-san")),
+			CertAllowedSPKI: security.SplitPins(sinkURI.Query().Get("ssl-spki")),
 		}
-		tlsCfg, err := credential.ToTLSConfig()
+		tlsCfg, err := credential.ToTLSConfigWithPinning()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -189,7 +241,19 @@ func parseSinkURIToParams(ctx context.Context, sinkURI *url.URL, opts map[string
 		params.batchReplaceSize = size
 	}
 
-	// TODO: force safe mode in startup phase
+	s = sinkURI.Query().Get("prepared-stmt-cache-size")
+	if s != "" {
+		size, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if size < 0 {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid prepared-stmt-cache-size %d, which must not be negative", size))
+		}
+		params.preparedStmtCacheSize = size
+	}
+
 	s = sinkURI.Query().Get("safe-mode")
 	if s != "" {
 		safeModeEnabled, err := strconv.ParseBool(s)
@@ -198,6 +262,18 @@ func parseSinkURIToParams(ctx context.Context, sinkURI *url.URL, opts map[string
 		}
 		params.safeMode = safeModeEnabled
 	}
+	s = sinkURI.Query().Get("safe-mode-duration")
+	if s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if d < 0 {
+			return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid safe-mode-duration %s, which must not be negative", s))
+		}
+		params.safeModeDuration = d
+	}
 
 	if _, ok := sinkURI.Query()["time-zone"]; ok {
 		s = sinkURI.Query().Get("time-zone")
@@ -311,7 +387,7 @@ func generateDSNByParams(
 	}
 	dsnClone := dsnCfg.Clone()
 	dsnClone.Passwd = "******"
-	log.Info("sink uri is configured", zap.String("dsn", dsnClone.FormatDSN()))
+	logger().Info("sink uri is configured", zap.String("dsn", dsnClone.FormatDSN()))
 
 	return dsnCfg.FormatDSN(), nil
 }
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	dmysql "github.com/go-sql-driver/mysql"
@@ -35,6 +36,7 @@ func TestSinkParamsClone(t *testing.T) {
 	require.Equal(t, &sinkParams{
 		workerCount:         DefaultWorkerCount,
 		maxTxnRow:           DefaultMaxTxnRow,
+		maxBatchSize:        defaultMaxBatchSize,
 		tidbTxnMode:         defaultTiDBTxnMode,
 		batchReplaceEnabled: defaultBatchReplaceEnabled,
 		batchReplaceSize:    defaultBatchReplaceSize,
@@ -47,6 +49,7 @@ func TestSinkParamsClone(t *testing.T) {
 		changefeedID:        "123",
 		workerCount:         DefaultWorkerCount,
 		maxTxnRow:           1,
+		maxBatchSize:        defaultMaxBatchSize,
 		tidbTxnMode:         defaultTiDBTxnMode,
 		batchReplaceEnabled: false,
 		batchReplaceSize:    defaultBatchReplaceSize,
@@ -204,18 +207,29 @@ func TestParseSinkURIToParams(t *testing.T) {
 	expected.changefeedID = "cf-id"
 	expected.captureAddr = "127.0.0.1:8300"
 	expected.tidbTxnMode = "pessimistic"
+	expected.preparedStmtCacheSize = 128
+	expected.safeModeDuration = 5 * time.Minute
+	expected.minWorkerCount = 16
+	expected.maxBatchSize = 32
+	expected.currentTs = 418658114257813516
 	uriStr := "mysql://127.0.0.1:3306/?worker-count=64&max-txn-row=20" +
 		"&batch-replace-enable=true&batch-replace-size=50&safe-mode=true" +
-		"&tidb-txn-mode=pessimistic"
+		"&tidb-txn-mode=pessimistic&prepared-stmt-cache-size=128&safe-mode-duration=5m&min-worker-count=16" +
+		"&max-batch-size=32"
 	opts := map[string]string{
 		OptChangefeedID: expected.changefeedID,
 		OptCaptureAddr:  expected.captureAddr,
+		OptCurrentTs:    "418658114257813516",
 	}
 	uri, err := url.Parse(uriStr)
 	require.Nil(t, err)
 	params, err := parseSinkURIToParams(context.TODO(), uri, opts)
 	require.Nil(t, err)
 	require.Equal(t, expected, params)
+
+	opts[OptCurrentTs] = "invalid"
+	_, err = parseSinkURIToParams(context.TODO(), uri, opts)
+	require.Regexp(t, ".*invalid syntax.*", err)
 }
 
 func TestParseSinkURITimezone(t *testing.T) {
@@ -298,6 +312,13 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 		"mysql://127.0.0.1:3306/?write-timeout=badduration",
 		"mysql://127.0.0.1:3306/?read-timeout=badduration",
 		"mysql://127.0.0.1:3306/?timeout=badduration",
+		"mysql://127.0.0.1:3306/?prepared-stmt-cache-size=not-number",
+		"mysql://127.0.0.1:3306/?prepared-stmt-cache-size=-1",
+		"mysql://127.0.0.1:3306/?safe-mode-duration=badduration",
+		"mysql://127.0.0.1:3306/?safe-mode-duration=-1m",
+		"mysql://127.0.0.1:3306/?min-worker-count=0",
+		"mysql://127.0.0.1:3306/?worker-count=4&min-worker-count=5",
+		"mysql://127.0.0.1:3306/?max-batch-size=0",
 	}
 	ctx := context.TODO()
 	opts := map[string]string{OptChangefeedID: "changefeed-01"}
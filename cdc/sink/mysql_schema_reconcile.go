@@ -0,0 +1,222 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// schemaCheckInterval is the interval of checking the downstream schemas of
+// the paused tables.
+var schemaCheckInterval = 5 * time.Second
+
+// schemaReconciler reconciles the tables whose schemas are managed in the
+// downstream. The sink doesn't execute their DDLs, so after a DDL the rows of
+// such a table may not match the downstream schema until it's changed by the
+// downstream. The reconciler pauses the table then, by holding its resolved
+// txns, and resumes it once the downstream schema digest matches the rows.
+type schemaReconciler struct {
+	changefeedID string
+	db           *sql.DB
+	filter       tfilter.Filter
+
+	// matched is the schema digest of each table which the downstream is
+	// known to match.
+	matched map[model.TableID]string
+	paused  map[model.TableID]*pausedTable
+
+	metricPausedTablesGauge prometheus.Gauge
+}
+
+// pausedTable holds the txns of a paused table in the commit ts order.
+type pausedTable struct {
+	txns      []*model.SingleTableTxn
+	digest    string
+	lastCheck time.Time
+}
+
+func newSchemaReconciler(db *sql.DB, changefeedID string, rules []string) (*schemaReconciler, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	f, err := tfilter.Parse(rules)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+	}
+	return &schemaReconciler{
+		changefeedID:            changefeedID,
+		db:                      db,
+		filter:                  tfilter.CaseInsensitive(f),
+		matched:                 make(map[model.TableID]string),
+		paused:                  make(map[model.TableID]*pausedTable),
+		metricPausedTablesGauge: schemaPausedTablesGauge.WithLabelValues(changefeedID),
+	}, nil
+}
+
+// manages returns whether the schema of the table is managed in the downstream.
+func (r *schemaReconciler) manages(schema, table string) bool {
+	return r != nil && table != "" && r.filter.MatchTable(schema, table)
+}
+
+// reconcile resumes the paused tables whose downstream schemas match, and
+// pauses the tables whose txns don't match the downstream schemas. The txns of
+// the paused tables are removed from txnsMap, and the held txns of the resumed
+// tables are put back before their new txns.
+func (r *schemaReconciler) reconcile(
+	ctx context.Context, txnsMap map[model.TableID][]*model.SingleTableTxn,
+) error {
+	now := time.Now()
+	for tableID, table := range r.paused {
+		if now.Sub(table.lastCheck) < schemaCheckInterval {
+			continue
+		}
+		table.lastCheck = now
+		digest, err := r.downstreamDigest(ctx, table.txns[0].Table)
+		if err != nil {
+			return err
+		}
+		if digest != table.digest {
+			continue
+		}
+		logger().Info("the downstream schema matches, resume the table",
+			zap.String("changefeed", r.changefeedID), zap.Stringer("table", table.txns[0].Table),
+			zap.Uint64("commitTs", table.txns[0].CommitTs))
+		delete(r.paused, tableID)
+		r.matched[tableID] = digest
+		txnsMap[tableID] = append(table.txns, txnsMap[tableID]...)
+	}
+
+	for tableID, txns := range txnsMap {
+		if table, ok := r.paused[tableID]; ok {
+			table.txns = append(table.txns, txns...)
+			delete(txnsMap, tableID)
+			continue
+		}
+		if !r.manages(txns[0].Table.Schema, txns[0].Table.Table) {
+			continue
+		}
+		for i, txn := range txns {
+			digest := rowsDigest(txn.Rows)
+			if digest == r.matched[tableID] {
+				continue
+			}
+			downstream, err := r.downstreamDigest(ctx, txn.Table)
+			if err != nil {
+				return err
+			}
+			if downstream == digest {
+				r.matched[tableID] = digest
+				continue
+			}
+			logger().Warn("the rows don't match the downstream schema, pause the table until it matches",
+				zap.String("changefeed", r.changefeedID), zap.Stringer("table", txn.Table),
+				zap.Uint64("commitTs", txn.CommitTs))
+			r.paused[tableID] = &pausedTable{
+				txns:      append([]*model.SingleTableTxn(nil), txns[i:]...),
+				digest:    digest,
+				lastCheck: now,
+			}
+			if i == 0 {
+				delete(txnsMap, tableID)
+			} else {
+				txnsMap[tableID] = txns[:i]
+			}
+			break
+		}
+	}
+	r.metricPausedTablesGauge.Set(float64(len(r.paused)))
+	return nil
+}
+
+// checkpointTs returns the checkpoint ts of the table whose txns are resolved
+// at resolvedTs, which is before the held txns if the table is paused.
+func (r *schemaReconciler) checkpointTs(tableID model.TableID, resolvedTs uint64) uint64 {
+	if r == nil {
+		return resolvedTs
+	}
+	if table, ok := r.paused[tableID]; ok && table.txns[0].CommitTs <= resolvedTs {
+		return table.txns[0].CommitTs - 1
+	}
+	return resolvedTs
+}
+
+// downstreamDigest returns the digest of the column names of the table in the
+// downstream, the generated columns are excluded since they are not written.
+func (r *schemaReconciler) downstreamDigest(ctx context.Context, table *model.TableName) (string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT COLUMN_NAME FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND EXTRA NOT LIKE '%GENERATED%'`, table.Schema, table.Table)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		names = append(names, name)
+	}
+	if err = rows.Err(); err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return schemaDigest(names), nil
+}
+
+// rowsDigest returns the digest of the column names of the rows, the rows of
+// a txn share the same schema.
+func rowsDigest(rows []*model.RowChangedEvent) string {
+	columns := rows[0].Columns
+	if len(columns) == 0 {
+		columns = rows[0].PreColumns
+	}
+	names := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if col != nil && !col.Flag.IsGeneratedColumn() {
+			names = append(names, col.Name)
+		}
+	}
+	return schemaDigest(names)
+}
+
+func schemaDigest(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	lower := make([]string, 0, len(names))
+	for _, name := range names {
+		lower = append(lower, strings.ToLower(name))
+	}
+	sort.Strings(lower)
+	sum := sha256.Sum256([]byte(strings.Join(lower, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *schemaReconciler) close() {
+	if r != nil {
+		schemaPausedTablesGauge.DeleteLabelValues(r.changefeedID)
+	}
+}
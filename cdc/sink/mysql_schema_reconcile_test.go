@@ -0,0 +1,128 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDigest(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", schemaDigest(nil))
+	require.Equal(t, schemaDigest([]string{"a", "B"}), schemaDigest([]string{"b", "A"}))
+	require.NotEqual(t, schemaDigest([]string{"a"}), schemaDigest([]string{"a", "b"}))
+
+	rows := []*model.RowChangedEvent{{
+		PreColumns: []*model.Column{
+			{Name: "a", Type: mysql.TypeLong},
+			{Name: "c", Type: mysql.TypeLong, Flag: model.GeneratedColumnFlag},
+			{Name: "b", Type: mysql.TypeLong},
+		},
+	}}
+	require.Equal(t, schemaDigest([]string{"a", "b"}), rowsDigest(rows))
+}
+
+func TestSchemaReconciler(t *testing.T) {
+	backupInterval := schemaCheckInterval
+	schemaCheckInterval = 0
+	defer func() {
+		schemaCheckInterval = backupInterval
+	}()
+
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer db.Close()
+	r, err := newSchemaReconciler(db, "test", []string{"app.*"})
+	require.Nil(t, err)
+	defer r.close()
+	require.True(t, r.manages("app", "t"))
+	require.True(t, r.manages("APP", "T"))
+	require.False(t, r.manages("app", ""))
+	require.False(t, r.manages("other", "t"))
+
+	managed := &model.TableName{Schema: "app", Table: "t", TableID: 1}
+	other := &model.TableName{Schema: "other", Table: "t", TableID: 2}
+	txn := func(table *model.TableName, commitTs uint64, columns ...string) *model.SingleTableTxn {
+		row := &model.RowChangedEvent{Table: table, CommitTs: commitTs}
+		for _, name := range columns {
+			row.Columns = append(row.Columns, &model.Column{Name: name, Type: mysql.TypeLong, Value: 1})
+		}
+		return &model.SingleTableTxn{Table: table, CommitTs: commitTs, Rows: []*model.RowChangedEvent{row}}
+	}
+	expectColumns := func(names ...string) {
+		rows := sqlmock.NewRows([]string{"COLUMN_NAME"})
+		for _, name := range names {
+			rows.AddRow(name)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COLUMN_NAME FROM information_schema.COLUMNS")).
+			WithArgs("app", "t").WillReturnRows(rows)
+	}
+
+	// a column is added in the upstream but not in the downstream yet.
+	expectColumns("a")
+	expectColumns("a")
+	txns := map[model.TableID][]*model.SingleTableTxn{
+		1: {txn(managed, 10, "a"), txn(managed, 11, "a"), txn(managed, 20, "a", "b")},
+		2: {txn(other, 15, "x")},
+	}
+	require.Nil(t, r.reconcile(ctx, txns))
+	require.Len(t, txns[1], 2)
+	require.Equal(t, uint64(11), txns[1][1].CommitTs)
+	require.Len(t, txns[2], 1)
+	require.Equal(t, uint64(19), r.checkpointTs(1, 30))
+	require.Equal(t, uint64(15), r.checkpointTs(1, 15))
+	require.Equal(t, uint64(30), r.checkpointTs(2, 30))
+
+	// the new txns are held while the table is paused.
+	expectColumns("a")
+	txns = map[model.TableID][]*model.SingleTableTxn{
+		1: {txn(managed, 30, "a", "b")},
+	}
+	require.Nil(t, r.reconcile(ctx, txns))
+	require.Empty(t, txns)
+	require.Equal(t, uint64(19), r.checkpointTs(1, 40))
+
+	// the table is resumed once the downstream schema matches.
+	expectColumns("A", "b")
+	txns = map[model.TableID][]*model.SingleTableTxn{
+		1: {txn(managed, 40, "a", "b")},
+	}
+	require.Nil(t, r.reconcile(ctx, txns))
+	require.Len(t, txns[1], 3)
+	for i, commitTs := range []uint64{20, 30, 40} {
+		require.Equal(t, commitTs, txns[1][i].CommitTs)
+	}
+	require.Equal(t, uint64(40), r.checkpointTs(1, 40))
+
+	// the matched schema isn't checked again.
+	txns = map[model.TableID][]*model.SingleTableTxn{
+		1: {txn(managed, 50, "a", "b")},
+	}
+	require.Nil(t, r.reconcile(ctx, txns))
+	require.Len(t, txns[1], 1)
+	require.Nil(t, mock.ExpectationsWereMet())
+
+	var nilReconciler *schemaReconciler
+	require.False(t, nilReconciler.manages("app", "t"))
+	require.Equal(t, uint64(40), nilReconciler.checkpointTs(1, 40))
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// stmtCache is a LRU cache of prepared statements keyed by the SQL text, which
+// is determined by the table, the type and the column set of a DML. Executing
+// the cached statements with the binary protocol saves the parsing and the
+// planning of downstream.
+type stmtCache struct {
+	mu       sync.Mutex
+	capacity int
+	lru      *list.List // front is the most recently used
+	stmts    map[string]*list.Element
+}
+
+type cachedStmt struct {
+	query string
+	stmt  *sql.Stmt
+	// refs is the number of executions using the statement, the statement is
+	// closed after it's evicted and refs drops to zero.
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(capacity int) *stmtCache {
+	return &stmtCache{
+		capacity: capacity,
+		lru:      list.New(),
+		stmts:    make(map[string]*list.Element, capacity),
+	}
+}
+
+// get returns the prepared statement of the query, the statement is prepared
+// if it's not cached. The returned release function must be called after the
+// statement is used.
+func (c *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, func(), error) {
+	c.mu.Lock()
+	if elem, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(elem)
+		cached := elem.Value.(*cachedStmt)
+		cached.refs++
+		c.mu.Unlock()
+		return cached.stmt, func() { c.release(cached) }, nil
+	}
+	c.mu.Unlock()
+
+	// prepare outside the lock, the statement may be prepared by multiple
+	// workers at the same time, only one of them is cached.
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(elem)
+		cached := elem.Value.(*cachedStmt)
+		cached.refs++
+		closeStmt(stmt)
+		return cached.stmt, func() { c.release(cached) }, nil
+	}
+	cached := &cachedStmt{query: query, stmt: stmt, refs: 1}
+	c.stmts[query] = c.lru.PushFront(cached)
+	for c.lru.Len() > c.capacity {
+		c.evict(c.lru.Back())
+	}
+	return stmt, func() { c.release(cached) }, nil
+}
+
+func (c *stmtCache) release(cached *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached.refs--
+	if cached.evicted && cached.refs == 0 {
+		closeStmt(cached.stmt)
+	}
+}
+
+// evict removes the element from the cache, the caller must hold the lock.
+func (c *stmtCache) evict(elem *list.Element) {
+	cached := c.lru.Remove(elem).(*cachedStmt)
+	delete(c.stmts, cached.query)
+	cached.evicted = true
+	if cached.refs == 0 {
+		closeStmt(cached.stmt)
+	}
+}
+
+// close evicts all the statements.
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back())
+	}
+}
+
+// len returns the number of cached statements.
+func (c *stmtCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func closeStmt(stmt *sql.Stmt) {
+	if err := stmt.Close(); err != nil {
+		logger().Warn("failed to close prepared statement", zap.Error(err))
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStmtCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.Nil(t, err)
+	defer db.Close() //nolint:errcheck
+
+	queryA := "INSERT INTO `s`.`a`(`id`) VALUES (?)"
+	queryB := "INSERT INTO `s`.`b`(`id`) VALUES (?)"
+	mock.ExpectPrepare(queryA).WillBeClosed()
+	mock.ExpectPrepare(queryB).WillBeClosed()
+
+	cache := newStmtCache(1)
+	stmtA, releaseA, err := cache.get(ctx, db, queryA)
+	require.Nil(t, err)
+	// a cache hit doesn't prepare the statement again.
+	stmtA2, releaseA2, err := cache.get(ctx, db, queryA)
+	require.Nil(t, err)
+	require.Equal(t, stmtA, stmtA2)
+	releaseA2()
+	require.Equal(t, 1, cache.len())
+
+	// queryA is evicted but still in use, so it's not closed yet.
+	_, releaseB, err := cache.get(ctx, db, queryB)
+	require.Nil(t, err)
+	require.Equal(t, 1, cache.len())
+	releaseB()
+	releaseA()
+
+	cache.close()
+	require.Equal(t, 0, cache.len())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
@@ -22,7 +22,7 @@ import (
 
 	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/cyclic/mark"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
@@ -31,7 +31,10 @@ import (
 )
 
 // SyncpointTableName is the name of table where all syncpoint maps sit
-const syncpointTableName string = "syncpoint_v1"
+const SyncpointTableName string = "syncpoint_v1"
+
+// endMarkerTableName is the name of table where the end markers of removed changefeeds sit
+const endMarkerTableName string = "end_marker_v1"
 
 type mysqlSyncpointStore struct {
 	db *sql.DB
@@ -52,17 +55,19 @@ func newMySQLSyncpointStore(ctx context.Context, id string, sinkURI *url.URL) (S
 		if s == "pessimistic" || s == "optimistic" {
 			params.tidbTxnMode = s
 		} else {
-			log.Warn("invalid tidb-txn-mode, should be pessimistic or optimistic, use optimistic as default")
+			logger().Warn("invalid tidb-txn-mode, should be pessimistic or optimistic, use optimistic as default")
 		}
 	}
 	var tlsParam string
 	if sinkURI.Query().Get("ssl-ca") != "" {
 		credential := security.Credential{
-			CAPath:   sinkURI.Query().Get("ssl-ca"),
-			CertPath: sinkURI.Query().Get("ssl-cert"),
-			KeyPath:  sinkURI.Query().Get("ssl-key"),
+			CAPath:          sinkURI.Query().Get("ssl-ca"),
+			CertPath:        sinkURI.Query().Get("ssl-cert"),
+			KeyPath:         sinkURI.Query().Get("ssl-key"),
+			CertAllowedSAN:  security.SplitPins(sinkURI.Query().Get("ssl-san")),
+			CertAllowedSPKI: security.SplitPins(sinkURI.Query().Get("ssl-spki")),
 		}
-		tlsCfg, err := credential.ToTLSConfig()
+		tlsCfg, err := credential.ToTLSConfigWithPinning()
 		if err != nil {
 			return nil, cerror.ErrMySQLConnectionError.Wrap(err).GenWithStack("fail to open MySQL connection")
 		}
@@ -125,7 +130,7 @@ func newMySQLSyncpointStore(ctx context.Context, id string, sinkURI *url.URL) (S
 		return nil, cerror.ErrMySQLConnectionError.Wrap(err).GenWithStack("fail to open MySQL connection")
 	}
 
-	log.Info("Start mysql syncpoint sink")
+	logger().Info("Start mysql syncpoint sink")
 	syncpointStore := &mysqlSyncpointStore{
 		db: syncDB,
 	}
@@ -137,14 +142,14 @@ func (s *mysqlSyncpointStore) CreateSynctable(ctx context.Context) error {
 	database := mark.SchemaName
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Error("create sync table: begin Tx fail", zap.Error(err))
+		logger().Error("create sync table: begin Tx fail", zap.Error(err))
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
 	_, err = tx.Exec("CREATE DATABASE IF NOT EXISTS " + database)
 	if err != nil {
 		err2 := tx.Rollback()
 		if err2 != nil {
-			log.Error("failed to create syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
+			logger().Error("failed to create syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
 		}
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
@@ -152,15 +157,15 @@ func (s *mysqlSyncpointStore) CreateSynctable(ctx context.Context) error {
 	if err != nil {
 		err2 := tx.Rollback()
 		if err2 != nil {
-			log.Error("failed to create syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
+			logger().Error("failed to create syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
 		}
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
-	_, err = tx.Exec("CREATE TABLE  IF NOT EXISTS " + syncpointTableName + " (cf varchar(255),primary_ts varchar(18),secondary_ts varchar(18),PRIMARY KEY ( `cf`, `primary_ts` ) )")
+	_, err = tx.Exec("CREATE TABLE  IF NOT EXISTS " + SyncpointTableName + " (cf varchar(255),primary_ts varchar(18),secondary_ts varchar(18),PRIMARY KEY ( `cf`, `primary_ts` ) )")
 	if err != nil {
 		err2 := tx.Rollback()
 		if err2 != nil {
-			log.Error("failed to create syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
+			logger().Error("failed to create syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
 		}
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
@@ -171,27 +176,27 @@ func (s *mysqlSyncpointStore) CreateSynctable(ctx context.Context) error {
 func (s *mysqlSyncpointStore) SinkSyncpoint(ctx context.Context, id string, checkpointTs uint64) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Error("sync table: begin Tx fail", zap.Error(err))
+		logger().Error("sync table: begin Tx fail", zap.Error(err))
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
 	row := tx.QueryRow("select @@tidb_current_ts")
 	var secondaryTs string
 	err = row.Scan(&secondaryTs)
 	if err != nil {
-		log.Info("sync table: get tidb_current_ts err")
+		logger().Info("sync table: get tidb_current_ts err")
 		err2 := tx.Rollback()
 		if err2 != nil {
-			log.Error("failed to write syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
+			logger().Error("failed to write syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
 		}
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
-	query := "insert ignore into " + mark.SchemaName + "." + syncpointTableName +
+	query := "insert ignore into " + mark.SchemaName + "." + SyncpointTableName +
 		"(cf, primary_ts, secondary_ts) VALUES (?,?,?)"
 	_, err = tx.Exec(query, id, checkpointTs, secondaryTs)
 	if err != nil {
 		err2 := tx.Rollback()
 		if err2 != nil {
-			log.Error("failed to write syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
+			logger().Error("failed to write syncpoint table", zap.Error(cerror.WrapError(cerror.ErrMySQLTxnError, err2)))
 		}
 		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 	}
@@ -199,6 +204,94 @@ func (s *mysqlSyncpointStore) SinkSyncpoint(ctx context.Context, id string, chec
 	return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 }
 
+func (s *mysqlSyncpointStore) SinkEndMarker(ctx context.Context, id string, checkpointTs uint64) error {
+	_, err := s.db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+mark.SchemaName)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	_, err = s.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+mark.SchemaName+"."+endMarkerTableName+
+		" (cf varchar(255), checkpoint_ts varchar(18), end_time datetime, PRIMARY KEY ( `cf` ) )")
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	query := "replace into " + mark.SchemaName + "." + endMarkerTableName +
+		"(cf, checkpoint_ts, end_time) VALUES (?,?,NOW())"
+	_, err = s.db.ExecContext(ctx, query, id, checkpointTs)
+	return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+}
+
+func (s *mysqlSyncpointStore) Checksum(
+	ctx context.Context, id string, checkpointTs uint64, tables []model.TableName,
+) (uint64, []*model.TableChecksum, error) {
+	// use a dedicated connection, because the snapshot is a session variable
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return 0, nil, cerror.WrapError(cerror.ErrMySQLConnectionError, err)
+	}
+	defer conn.Close()
+
+	var secondaryTs uint64
+	query := "select secondary_ts from " + mark.SchemaName + "." + SyncpointTableName +
+		" where cf = ? and primary_ts = ?"
+	err = conn.QueryRowContext(ctx, query, id, checkpointTs).Scan(&secondaryTs)
+	if err != nil {
+		return 0, nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+
+	_, err = conn.ExecContext(ctx, "set @@tidb_snapshot = ?", secondaryTs)
+	if err != nil {
+		return 0, nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer func() {
+		// reset the snapshot before the connection is put back to the pool
+		if _, err := conn.ExecContext(context.Background(), "set @@tidb_snapshot = ''"); err != nil {
+			logger().Warn("failed to reset tidb_snapshot", zap.Error(err))
+		}
+	}()
+
+	checksums := make([]*model.TableChecksum, 0, len(tables))
+	for _, table := range tables {
+		checksum := &model.TableChecksum{}
+		row := conn.QueryRowContext(ctx, "admin checksum table "+table.QuoteString())
+		err = row.Scan(&checksum.Schema, &checksum.Table, &checksum.Checksum, &checksum.TotalKvs, &checksum.TotalBytes)
+		if err != nil {
+			return 0, nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		checksums = append(checksums, checksum)
+	}
+	return secondaryTs, checksums, nil
+}
+
+func (s *mysqlSyncpointStore) Columns(
+	ctx context.Context, schemas []string,
+) (map[model.TableName][]*DownstreamColumn, error) {
+	columns := make(map[model.TableName][]*DownstreamColumn)
+	query := "select table_name, column_name, data_type from information_schema.columns" +
+		" where table_schema = ? order by table_name, ordinal_position"
+	for _, schema := range schemas {
+		rows, err := s.db.QueryContext(ctx, query, schema)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		for rows.Next() {
+			var table string
+			column := &DownstreamColumn{}
+			if err := rows.Scan(&table, &column.Name, &column.DataType); err != nil {
+				_ = rows.Close()
+				return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+			}
+			name := model.TableName{Schema: schema, Table: table}
+			columns[name] = append(columns[name], column)
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+	}
+	return columns, nil
+}
+
 func (s *mysqlSyncpointStore) Close() error {
 	err := s.db.Close()
 	return cerror.WrapError(cerror.ErrMySQLConnectionError, err)
@@ -126,6 +126,135 @@ func TestPrepareDML(t *testing.T) {
 	}
 }
 
+func TestPrepareDMLSafeModeDuration(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLSink4Test(ctx, t)
+	ms.params.enableOldValue = true
+	ms.safeModeEndTs = 418658114257813516
+	ms.metricSafeModeGauge = safeModeGauge.WithLabelValues("test-safe-mode")
+
+	newRow := func(commitTs uint64) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			CommitTs: commitTs,
+			Table:    &model.TableName{Schema: "common_1", Table: "pk"},
+			Columns: []*model.Column{{
+				Name:  "a1",
+				Type:  mysql.TypeLong,
+				Flag:  model.BinaryFlag | model.PrimaryKeyFlag | model.HandleKeyFlag,
+				Value: 1,
+			}},
+			IndexColumns: [][]int{{0}},
+		}
+	}
+	// rows committed before the end of the safe mode are replaced.
+	dmls := ms.prepareDMLs([]*model.RowChangedEvent{newRow(418658114257813515)}, 0, 0)
+	require.Equal(t, []string{"REPLACE INTO `common_1`.`pk`(`a1`) VALUES (?);"}, dmls.sqls)
+	dmls = ms.prepareDMLs([]*model.RowChangedEvent{newRow(418658114257813517)}, 0, 0)
+	require.Equal(t, []string{"INSERT INTO `common_1`.`pk`(`a1`) VALUES (?);"}, dmls.sqls)
+
+	ms.params.safeMode = false
+	require.False(t, ms.inSafeMode(418658114257813515))
+}
+
+func TestAdjustActiveWorkers(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLSink4Test(ctx, t)
+	ms.params.workerCount = 8
+	ms.params.minWorkerCount = 2
+	ms.params.maxTxnRow = 10
+	ms.activeWorkers = 2
+	ms.metricActiveWorkerGauge = activeWorkerGauge.WithLabelValues("test-active-worker")
+
+	cooldown := func() {
+		for i := 0; i < scaleCooldownRounds; i++ {
+			ms.adjustActiveWorkers(0)
+		}
+	}
+
+	// scale up under a steady backlog, but never exceed the worker count.
+	ms.adjustActiveWorkers(20)
+	require.Equal(t, 2, ms.activeWorkers)
+	ms.adjustActiveWorkers(20)
+	require.Equal(t, 4, ms.activeWorkers)
+	// the workers are kept during the cooldown.
+	ms.adjustActiveWorkers(1000)
+	require.Equal(t, 4, ms.activeWorkers)
+	cooldown()
+	ms.adjustActiveWorkers(40)
+	ms.adjustActiveWorkers(40)
+	require.Equal(t, 8, ms.activeWorkers)
+	cooldown()
+	ms.adjustActiveWorkers(1000)
+	ms.adjustActiveWorkers(1000)
+	require.Equal(t, 8, ms.activeWorkers)
+
+	// scale down after being idle for a while.
+	for i := 0; i < scaleDownIdleRounds-1; i++ {
+		ms.adjustActiveWorkers(1)
+	}
+	require.Equal(t, 8, ms.activeWorkers)
+	ms.adjustActiveWorkers(1)
+	require.Equal(t, 4, ms.activeWorkers)
+	cooldown()
+	// a busy round resets the idle rounds.
+	for i := 0; i < scaleDownIdleRounds-1; i++ {
+		ms.adjustActiveWorkers(1)
+	}
+	ms.adjustActiveWorkers(40)
+	for i := 0; i < scaleDownIdleRounds-1; i++ {
+		ms.adjustActiveWorkers(1)
+	}
+	require.Equal(t, 4, ms.activeWorkers)
+	for i := 0; i < 3*scaleDownIdleRounds; i++ {
+		ms.adjustActiveWorkers(0)
+	}
+	require.Equal(t, 2, ms.activeWorkers)
+}
+
+func TestAdjustActiveWorkersNoOscillation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLSink4Test(ctx, t)
+	ms.params.workerCount = 8
+	ms.params.minWorkerCount = 2
+	ms.params.maxTxnRow = 10
+	ms.activeWorkers = 4
+	ms.metricActiveWorkerGauge = activeWorkerGauge.WithLabelValues("test-no-oscillation")
+
+	// a backlog which alternates between busy and idle rounds never scales.
+	for i := 0; i < 100; i++ {
+		ms.adjustActiveWorkers(1000)
+		ms.adjustActiveWorkers(0)
+	}
+	require.Equal(t, 4, ms.activeWorkers)
+
+	// a single busy round after each idle period doesn't scale up, so the
+	// active workers are only scaled down once instead of going back and forth.
+	changes := 0
+	last := ms.activeWorkers
+	for i := 0; i < 20; i++ {
+		ms.adjustActiveWorkers(1000)
+		for j := 0; j < scaleDownIdleRounds; j++ {
+			ms.adjustActiveWorkers(0)
+		}
+		require.LessOrEqual(t, ms.activeWorkers, last)
+		if ms.activeWorkers != last {
+			changes++
+			last = ms.activeWorkers
+		}
+	}
+	require.Equal(t, 2, ms.activeWorkers)
+	require.Equal(t, 1, changes)
+}
+
 func TestPrepareUpdate(t *testing.T) {
 	testCases := []struct {
 		quoteTable   string
@@ -19,7 +19,6 @@ import (
 	"sync"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/notify"
@@ -27,9 +26,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// mysqlSinkWorker groups the upstream txns dispatched to it into larger
+// downstream txns. The txns of a worker never conflict with the txns of the
+// other workers, and a group is executed when the worker is flushed for a
+// conflict or a resolved ts, or the group reaches maxTxnRow rows or
+// maxBatchSize txns.
 type mysqlSinkWorker struct {
 	txnCh            chan *model.SingleTableTxn
 	maxTxnRow        int
+	maxBatchSize     int
 	bucket           int
 	execDMLs         func(context.Context, []*model.RowChangedEvent, uint64, int) error
 	metricBucketSize prometheus.Counter
@@ -39,6 +44,7 @@ type mysqlSinkWorker struct {
 
 func newMySQLSinkWorker(
 	maxTxnRow int,
+	maxBatchSize int,
 	bucket int,
 	metricBucketSize prometheus.Counter,
 	receiver *notify.Receiver,
@@ -47,6 +53,7 @@ func newMySQLSinkWorker(
 	return &mysqlSinkWorker{
 		txnCh:            make(chan *model.SingleTableTxn, 1024),
 		maxTxnRow:        maxTxnRow,
+		maxBatchSize:     maxBatchSize,
 		bucket:           bucket,
 		metricBucketSize: metricBucketSize,
 		execDMLs:         execDMLs,
@@ -101,7 +108,7 @@ func (w *mysqlSinkWorker) run(ctx context.Context) (err error) {
 			stackSize := runtime.Stack(buf, false)
 			buf = buf[:stackSize]
 			err = cerror.ErrMySQLWorkerPanic.GenWithStack("mysql sink concurrent execute panic, stack: %v", string(buf))
-			log.Error("mysql sink worker panic", zap.Reflect("r", r), zap.Stack("stacktrace"))
+			logger().Error("mysql sink worker panic", zap.Reflect("r", r), zap.Stack("stacktrace"))
 		}
 	}()
 
@@ -135,7 +142,8 @@ func (w *mysqlSinkWorker) run(ctx context.Context) (err error) {
 				txn.FinishWg.Done()
 				continue
 			}
-			if txn.ReplicaID != replicaID || len(toExecRows)+len(txn.Rows) > w.maxTxnRow {
+			if txn.ReplicaID != replicaID || len(toExecRows)+len(txn.Rows) > w.maxTxnRow ||
+				txnNum >= w.maxBatchSize {
 				if err := flushRows(); err != nil {
 					txnNum++
 					return errors.Trace(err)
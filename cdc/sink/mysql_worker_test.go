@@ -177,7 +177,7 @@ func TestMysqlSinkWorker(t *testing.T) {
 		var outputReplicaIDs []uint64
 		receiver, err := notifier.NewReceiver(-1)
 		require.Nil(t, err)
-		w := newMySQLSinkWorker(tc.maxTxnRow, 1,
+		w := newMySQLSinkWorker(tc.maxTxnRow, defaultMaxBatchSize, 1,
 			bucketSizeCounter.WithLabelValues("changefeed", "1"),
 			receiver,
 			func(ctx context.Context, events []*model.RowChangedEvent, replicaID uint64, bucket int) error {
@@ -209,6 +209,59 @@ func TestMysqlSinkWorker(t *testing.T) {
 	}
 }
 
+func TestMySQLSinkWorkerBatch(t *testing.T) {
+	defer testleak.AfterTestT(t)()
+	tbl := &model.TableName{Schema: "test", Table: "user", TableID: 1}
+	newTxn := func(commitTs uint64) *model.SingleTableTxn {
+		return &model.SingleTableTxn{
+			Table:     tbl,
+			CommitTs:  commitTs,
+			Rows:      []*model.RowChangedEvent{{CommitTs: commitTs}},
+			ReplicaID: 1,
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	notifier := new(notify.Notifier)
+	receiver, err := notifier.NewReceiver(-1)
+	require.Nil(t, err)
+	var outputRows [][]*model.RowChangedEvent
+	w := newMySQLSinkWorker(10, 2, 1,
+		bucketSizeCounter.WithLabelValues("changefeed", "1"),
+		receiver,
+		func(ctx context.Context, events []*model.RowChangedEvent, replicaID uint64, bucket int) error {
+			rows := make([]*model.RowChangedEvent, len(events))
+			copy(rows, events)
+			outputRows = append(outputRows, rows)
+			return nil
+		})
+	errg, cctx := errgroup.WithContext(ctx)
+	errg.Go(func() error {
+		return w.run(cctx)
+	})
+
+	// the txns are grouped up to the max batch size.
+	for _, ts := range []uint64{1, 2, 3} {
+		w.appendTxn(cctx, newTxn(ts))
+	}
+	// the worker is flushed for a conflict, which splits the batch.
+	var wg sync.WaitGroup
+	w.appendFinishTxn(&wg)
+	wg.Wait()
+	for _, ts := range []uint64{4, 5} {
+		w.appendTxn(cctx, newTxn(ts))
+	}
+	w.appendFinishTxn(&wg)
+	wg.Wait()
+	cancel()
+	require.Equal(t, context.Canceled, errors.Cause(errg.Wait()))
+	require.Equal(t, [][]*model.RowChangedEvent{
+		{{CommitTs: 1}, {CommitTs: 2}},
+		{{CommitTs: 3}},
+		{{CommitTs: 4}, {CommitTs: 5}},
+	}, outputRows)
+}
+
 func TestMySQLSinkWorkerExitWithError(t *testing.T) {
 	defer testleak.AfterTestT(t)()
 	tbl := &model.TableName{
@@ -259,7 +312,7 @@ func TestMySQLSinkWorkerExitWithError(t *testing.T) {
 	cctx, cancel := context.WithCancel(ctx)
 	receiver, err := notifier.NewReceiver(-1)
 	require.Nil(t, err)
-	w := newMySQLSinkWorker(maxTxnRow, 1, /*bucket*/
+	w := newMySQLSinkWorker(maxTxnRow, defaultMaxBatchSize, 1, /*bucket*/
 		bucketSizeCounter.WithLabelValues("changefeed", "1"),
 		receiver,
 		func(ctx context.Context, events []*model.RowChangedEvent, replicaID uint64, bucket int) error {
@@ -331,7 +384,7 @@ func TestMySQLSinkWorkerExitCleanup(t *testing.T) {
 	cctx, cancel := context.WithCancel(ctx)
 	receiver, err := notifier.NewReceiver(-1)
 	require.Nil(t, err)
-	w := newMySQLSinkWorker(maxTxnRow, 1, /*bucket*/
+	w := newMySQLSinkWorker(maxTxnRow, defaultMaxBatchSize, 1, /*bucket*/
 		bucketSizeCounter.WithLabelValues("changefeed", "1"),
 		receiver,
 		func(ctx context.Context, events []*model.RowChangedEvent, replicaID uint64, bucket int) error {
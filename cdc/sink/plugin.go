@@ -0,0 +1,551 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/proto/sinkplugin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// pluginServiceName is the service name of the sink plugins in the gRPC
+	// health checking protocol.
+	pluginServiceName = "sinkplugin.SinkPlugin"
+
+	pluginDefaultBatchSize = 500
+	pluginMaxBatchSize     = 10000
+	pluginDefaultTimeout   = 30 * time.Second
+	pluginDialTimeout      = 10 * time.Second
+
+	pluginBackoffBaseDelayInMs = 500
+	pluginBackoffMaxDelayInMs  = 10 * 1000
+)
+
+var (
+	// pluginHealthCheckInterval is the interval of checking the health of the
+	// plugin.
+	pluginHealthCheckInterval = 10 * time.Second
+	// pluginUnreachableTimeout is how long the plugin can be unreachable
+	// before the changefeed fails.
+	pluginUnreachableTimeout = time.Minute
+	// pluginBusyBackoff is how long the rows are not sent to the plugin after
+	// it asks TiCDC to slow down.
+	pluginBusyBackoff = time.Second
+)
+
+// pluginSinkParams are the parameters of a plugin sink, e.g.
+// plugin://127.0.0.1:9000?batch-size=100&timeout=10s or
+// plugin:///var/run/cdc-plugin.sock
+type pluginSinkParams struct {
+	target     string
+	batchSize  int
+	timeout    time.Duration
+	credential *security.Credential
+}
+
+func parsePluginSinkParams(sinkURI *url.URL) (*pluginSinkParams, error) {
+	params := &pluginSinkParams{
+		batchSize:  pluginDefaultBatchSize,
+		timeout:    pluginDefaultTimeout,
+		credential: &security.Credential{},
+	}
+	switch {
+	case sinkURI.Host != "":
+		params.target = sinkURI.Host
+	case sinkURI.Path != "":
+		params.target = "unix://" + sinkURI.Path
+	default:
+		return nil, cerror.ErrPluginSinkInvalidConfig.GenWithStack("no host or unix socket path in sink uri")
+	}
+
+	query := sinkURI.Query()
+	if s := query.Get("batch-size"); s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrPluginSinkInvalidConfig, err)
+		}
+		if c <= 0 || c > pluginMaxBatchSize {
+			return nil, cerror.ErrPluginSinkInvalidConfig.GenWithStack(
+				"batch-size %d is out of range (0, %d]", c, pluginMaxBatchSize)
+		}
+		params.batchSize = c
+	}
+	if s := query.Get("timeout"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrPluginSinkInvalidConfig, err)
+		}
+		if d <= 0 {
+			return nil, cerror.ErrPluginSinkInvalidConfig.GenWithStack("timeout %s should be positive", s)
+		}
+		params.timeout = d
+	}
+	if query.Get("ssl-ca") != "" {
+		params.credential = &security.Credential{
+			CAPath:   query.Get("ssl-ca"),
+			CertPath: query.Get("ssl-cert"),
+			KeyPath:  query.Get("ssl-key"),
+		}
+	}
+	return params, nil
+}
+
+// isPluginBusy returns whether the plugin asks TiCDC to send the request
+// again later, a request timed out is also sent again since the plugin may
+// be too slow to keep up.
+func isPluginBusy(err error) bool {
+	switch status.Code(errors.Cause(err)) {
+	case codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// pluginTable holds the pending rows of a table.
+type pluginTable struct {
+	// flushMu serializes the flushes of the table, which are sent to the
+	// plugin without holding the lock of the sink.
+	flushMu    sync.Mutex
+	rows       []*model.RowChangedEvent
+	resolvedTs uint64
+	checkpoint uint64
+}
+
+// pluginSink sends change events to an out-of-process sink plugin through
+// the gRPC protocol defined in proto/CDCSinkPlugin.proto.
+//
+// Rows are buffered and sent in batches when they are flushed, and the
+// checkpoint of a table is the one returned by the plugin. While the plugin
+// is busy or not serving, rows are held and the checkpoints don't advance,
+// so the memory quota of the tables is used up and the processor stops
+// pulling their changes until the plugin catches up.
+type pluginSink struct {
+	id         model.ChangeFeedID
+	params     *pluginSinkParams
+	conn       *grpc.ClientConn
+	client     sinkplugin.SinkPluginClient
+	health     healthpb.HealthClient
+	filter     *filter.Filter
+	statistics *Statistics
+	cancel     context.CancelFunc
+
+	mu        sync.Mutex
+	tables    map[model.TableID]*pluginTable
+	serving   bool
+	busyUntil time.Time
+}
+
+func newPluginSink(
+	ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+	filter *filter.Filter, errCh chan error,
+) (*pluginSink, error) {
+	params, err := parsePluginSinkParams(sinkURI)
+	if err != nil {
+		return nil, err
+	}
+	tlsOption, err := params.credential.ToGRPCDialOption()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPluginSinkInvalidConfig, err)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, pluginDialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(dialCtx, params.target, tlsOption, grpc.WithBlock())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPluginSinkRequest, err)
+	}
+
+	s := &pluginSink{
+		id:         changefeedID,
+		params:     params,
+		conn:       conn,
+		client:     sinkplugin.NewSinkPluginClient(conn),
+		health:     healthpb.NewHealthClient(conn),
+		filter:     filter,
+		statistics: NewStatistics(ctx, sinkTypePlugin),
+		tables:     make(map[model.TableID]*pluginTable),
+	}
+	serving, err := s.checkHealth(ctx)
+	if err == nil && !serving {
+		err = cerror.ErrPluginSinkUnhealthy.GenWithStackByArgs("not serving")
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	s.serving = true
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go func() {
+		if err := s.watchHealth(ctx); err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case <-ctx.Done():
+			case errCh <- err:
+			default:
+				logger().Error("error channel is full", zap.Error(err),
+					zap.String("changefeed", changefeedID))
+			}
+		}
+	}()
+	return s, nil
+}
+
+func (s *pluginSink) checkHealth(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.params.timeout)
+	defer cancel()
+	resp, err := s.health.Check(ctx, &healthpb.HealthCheckRequest{Service: pluginServiceName})
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrPluginSinkRequest, err)
+	}
+	return resp.Status == healthpb.HealthCheckResponse_SERVING, nil
+}
+
+// watchHealth checks the health of the plugin periodically, rows are not sent
+// to the plugin while it's not serving. It returns an error once the plugin
+// is unreachable for longer than pluginUnreachableTimeout.
+func (s *pluginSink) watchHealth(ctx context.Context) error {
+	ticker := time.NewTicker(pluginHealthCheckInterval)
+	defer ticker.Stop()
+	lastReached := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		serving, err := s.checkHealth(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			unreachable := time.Since(lastReached)
+			if unreachable > pluginUnreachableTimeout {
+				return cerror.ErrPluginSinkUnhealthy.GenWithStackByArgs(
+					"unreachable for " + unreachable.String())
+			}
+			logger().Warn("fail to check the health of the sink plugin",
+				zap.String("changefeed", s.id), zap.Duration("unreachable", unreachable), zap.Error(err))
+			continue
+		}
+		lastReached = time.Now()
+		s.mu.Lock()
+		if serving != s.serving {
+			logger().Info("the serving status of the sink plugin changes",
+				zap.String("changefeed", s.id), zap.Bool("serving", serving))
+		}
+		s.serving = serving
+		s.mu.Unlock()
+	}
+}
+
+func (s *pluginSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	if err := s.EmitRowChangedEvents(ctx, rows...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *pluginSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rowsCount := 0
+	for _, row := range rows {
+		if s.filter != nil && s.filter.ShouldIgnoreDMLEvent(row.StartTs, row.Table.Schema, row.Table.Table) {
+			logger().Info("Row changed event ignored",
+				zap.Uint64("start-ts", row.StartTs),
+				zap.String("changefeed", s.id))
+			continue
+		}
+		table, ok := s.tables[row.Table.TableID]
+		if !ok {
+			table = &pluginTable{}
+			s.tables[row.Table.TableID] = table
+		}
+		table.rows = append(table.rows, row)
+		rowsCount++
+	}
+	s.statistics.AddRowsCount(rowsCount)
+	return nil
+}
+
+// FlushRowChangedEvents sends the rows before resolvedTs to the plugin and
+// returns the checkpoint of the table returned by the plugin. If the plugin
+// is busy or not serving, the rows are held and the old checkpoint is
+// returned without an error.
+func (s *pluginSink) FlushRowChangedEvents(ctx context.Context, tableID model.TableID, resolvedTs uint64) (uint64, error) {
+	s.mu.Lock()
+	table, ok := s.tables[tableID]
+	if !ok {
+		s.mu.Unlock()
+		return resolvedTs, nil
+	}
+	s.mu.Unlock()
+
+	table.flushMu.Lock()
+	defer table.flushMu.Unlock()
+	// the lock of the sink is not held while the requests are sent, so the
+	// rows of other tables can be emitted and flushed meanwhile.
+	s.mu.Lock()
+	checkpoint := table.checkpoint
+	if resolvedTs <= checkpoint || !s.serving || time.Now().Before(s.busyUntil) {
+		s.mu.Unlock()
+		return checkpoint, nil
+	}
+	// rows of a table are emitted in commit ts order, and the rows emitted
+	// meanwhile are only appended.
+	idx := sort.Search(len(table.rows), func(i int) bool {
+		return table.rows[i].CommitTs > resolvedTs
+	})
+	rows := append([]*model.RowChangedEvent(nil), table.rows[:idx]...)
+	if resolvedTs > table.resolvedTs {
+		table.resolvedTs = resolvedTs
+	}
+	s.mu.Unlock()
+
+	sent, err := s.sendRows(ctx, tableID, rows)
+	s.mu.Lock()
+	// drop the delivered batches, so they are not sent again before the
+	// changefeed restarts.
+	table.rows = append(table.rows[:0], table.rows[sent:]...)
+	s.mu.Unlock()
+	if err != nil {
+		return checkpoint, s.handleError(ctx, err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, s.params.timeout)
+	defer cancel()
+	resp, err := s.client.Flush(callCtx, &sinkplugin.FlushRequest{
+		ChangefeedId: s.id,
+		TableId:      tableID,
+		ResolvedTs:   resolvedTs,
+	})
+	if err != nil {
+		return checkpoint, s.handleError(ctx, err)
+	}
+	if resp.CheckpointTs < resolvedTs {
+		resolvedTs = resp.CheckpointTs
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resolvedTs > table.checkpoint {
+		table.checkpoint = resolvedTs
+	}
+	return table.checkpoint, nil
+}
+
+// sendRows sends the rows in batches to the plugin, it returns the number of
+// the rows delivered.
+func (s *pluginSink) sendRows(ctx context.Context, tableID model.TableID, rows []*model.RowChangedEvent) (int, error) {
+	sent := 0
+	for sent < len(rows) {
+		end := sent + s.params.batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		batch := rows[sent:end]
+		err := s.statistics.RecordBatchExecution(func() (int, error) {
+			req := &sinkplugin.EmitRowChangesRequest{
+				ChangefeedId: s.id,
+				TableId:      tableID,
+				Rows:         make([]*sinkplugin.RowChange, 0, len(batch)),
+			}
+			for _, row := range batch {
+				req.Rows = append(req.Rows, pluginRowChange(row))
+			}
+			callCtx, cancel := context.WithTimeout(ctx, s.params.timeout)
+			defer cancel()
+			if _, err := s.client.EmitRowChanges(callCtx, req); err != nil {
+				return 0, err
+			}
+			return len(batch), nil
+		})
+		if err != nil {
+			return sent, err
+		}
+		sent = end
+	}
+	return sent, nil
+}
+
+// handleError holds the rows for a while if the plugin is busy, otherwise
+// the error is returned to fail the changefeed.
+func (s *pluginSink) handleError(ctx context.Context, err error) error {
+	if ctx.Err() != nil || !isPluginBusy(err) {
+		return cerror.WrapError(cerror.ErrPluginSinkRequest, err)
+	}
+	logger().Warn("the sink plugin is busy, hold the rows for a while",
+		zap.String("changefeed", s.id), zap.Duration("backoff", pluginBusyBackoff), zap.Error(err))
+	s.mu.Lock()
+	s.busyUntil = time.Now().Add(pluginBusyBackoff)
+	s.mu.Unlock()
+	return nil
+}
+
+func pluginRowChange(row *model.RowChangedEvent) *sinkplugin.RowChange {
+	return &sinkplugin.RowChange{
+		Schema:     row.Table.Schema,
+		Table:      row.Table.Table,
+		TableId:    row.Table.TableID,
+		StartTs:    row.StartTs,
+		CommitTs:   row.CommitTs,
+		Columns:    pluginColumns(row.Columns),
+		PreColumns: pluginColumns(row.PreColumns),
+	}
+}
+
+func pluginColumns(columns []*model.Column) []*sinkplugin.Column {
+	if len(columns) == 0 {
+		return nil
+	}
+	result := make([]*sinkplugin.Column, 0, len(columns))
+	for _, col := range columns {
+		if col == nil {
+			continue
+		}
+		c := &sinkplugin.Column{
+			Name:   col.Name,
+			Type:   uint32(col.Type),
+			Flag:   uint64(col.Flag),
+			IsNull: col.Value == nil,
+		}
+		if col.Value != nil {
+			c.Value = []byte(storageColumnString(col.Value))
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// EmitDDLEvent sends the DDL to the plugin, it's sent again while the plugin
+// is busy.
+func (s *pluginSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if s.filter != nil && s.filter.ShouldIgnoreDDLEvent(ddl.StartTs, ddl.Type, ddl.TableInfo.Schema, ddl.TableInfo.Table) {
+		logger().Info(
+			"DDL event ignored",
+			zap.String("query", ddl.Query),
+			zap.Uint64("startTs", ddl.StartTs),
+			zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("changefeed", s.id),
+		)
+		return cerror.ErrDDLEventIgnored.GenWithStackByArgs()
+	}
+	req := &sinkplugin.EmitDDLRequest{
+		ChangefeedId: s.id,
+		Schema:       ddl.TableInfo.Schema,
+		Table:        ddl.TableInfo.Table,
+		StartTs:      ddl.StartTs,
+		CommitTs:     ddl.CommitTs,
+		Type:         int32(ddl.Type),
+		Query:        ddl.Query,
+	}
+	err := retry.Do(ctx, func() error {
+		callCtx, cancel := context.WithTimeout(ctx, s.params.timeout)
+		defer cancel()
+		_, err := s.client.EmitDDL(callCtx, req)
+		if err != nil && isPluginBusy(err) {
+			logger().Warn("the sink plugin is busy, send the DDL again later",
+				zap.String("changefeed", s.id), zap.String("query", ddl.Query), zap.Error(err))
+		}
+		return err
+	}, retry.WithBackoffBaseDelay(pluginBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(pluginBackoffMaxDelayInMs),
+		retry.WithInfiniteTries(),
+		retry.WithIsRetryableErr(isPluginBusy))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPluginSinkRequest, err)
+	}
+	return nil
+}
+
+// EmitCheckpointTs sends the checkpoint to the plugin, it's skipped while the
+// plugin is busy since a later checkpoint will be sent.
+func (s *pluginSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	req := &sinkplugin.EmitCheckpointRequest{
+		ChangefeedId: s.id,
+		CheckpointTs: ts,
+		Tables:       make([]*sinkplugin.TableName, 0, len(tables)),
+	}
+	for _, table := range tables {
+		req.Tables = append(req.Tables, &sinkplugin.TableName{Schema: table.Schema, Table: table.Table})
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.params.timeout)
+	defer cancel()
+	if _, err := s.client.EmitCheckpoint(ctx, req); err != nil && !isPluginBusy(err) {
+		return cerror.WrapError(cerror.ErrPluginSinkRequest, err)
+	}
+	return nil
+}
+
+func (s *pluginSink) Close(ctx context.Context) error {
+	s.cancel()
+	err := s.conn.Close()
+	if err != nil && status.Code(err) != codes.Canceled {
+		return cerror.WrapError(cerror.ErrPluginSinkRequest, err)
+	}
+	return nil
+}
+
+// Barrier sends the pending rows of the table to the plugin and waits until
+// the plugin has flushed all the rows of it, the table is then removed from
+// the sink.
+func (s *pluginSink) Barrier(ctx context.Context, tableID model.TableID) error {
+	s.mu.Lock()
+	table, ok := s.tables[tableID]
+	if !ok {
+		s.mu.Unlock()
+		return nil
+	}
+	targetTs := table.resolvedTs
+	if n := len(table.rows); n > 0 && table.rows[n-1].CommitTs > targetTs {
+		targetTs = table.rows[n-1].CommitTs
+	}
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(pluginBusyBackoff)
+	defer ticker.Stop()
+	for {
+		checkpoint, err := s.FlushRowChangedEvents(ctx, tableID, targetTs)
+		if err != nil {
+			return err
+		}
+		if checkpoint >= targetTs {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	s.mu.Lock()
+	delete(s.tables, tableID)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/proto/sinkplugin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestParsePluginSinkParams(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse("plugin://127.0.0.1:9000?batch-size=100&timeout=5s")
+	require.Nil(t, err)
+	params, err := parsePluginSinkParams(uri)
+	require.Nil(t, err)
+	require.Equal(t, "127.0.0.1:9000", params.target)
+	require.Equal(t, 100, params.batchSize)
+	require.Equal(t, 5*time.Second, params.timeout)
+
+	uri, err = url.Parse("plugin:///var/run/cdc-plugin.sock")
+	require.Nil(t, err)
+	params, err = parsePluginSinkParams(uri)
+	require.Nil(t, err)
+	require.Equal(t, "unix:///var/run/cdc-plugin.sock", params.target)
+	require.Equal(t, pluginDefaultBatchSize, params.batchSize)
+	require.Equal(t, pluginDefaultTimeout, params.timeout)
+
+	for uri, expected := range map[string]string{
+		"plugin://":                             ".*no host or unix socket path.*",
+		"plugin://127.0.0.1:9000?batch-size=0":  ".*batch-size 0 is out of range.*",
+		"plugin://127.0.0.1:9000?timeout=-1s":   ".*timeout -1s should be positive.*",
+		"plugin://127.0.0.1:9000?batch-size=ab": ".*invalid syntax.*",
+	} {
+		u, err := url.Parse(uri)
+		require.Nil(t, err)
+		_, err = parsePluginSinkParams(u)
+		require.Regexp(t, expected, err)
+	}
+}
+
+// pluginRecorder is a sink plugin recording the requests, the first
+// len(errs) requests of EmitRowChanges and EmitDDL fail with the errors.
+type pluginRecorder struct {
+	sinkplugin.UnimplementedSinkPluginServer
+
+	mu          sync.Mutex
+	rows        []*sinkplugin.EmitRowChangesRequest
+	ddls        []*sinkplugin.EmitDDLRequest
+	checkpoints []*sinkplugin.EmitCheckpointRequest
+	flushes     []*sinkplugin.FlushRequest
+	errs        []error
+	// lag is subtracted from the resolved ts as the checkpoint of a flush.
+	lag uint64
+}
+
+func (r *pluginRecorder) fail(errs ...error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = errs
+}
+
+func (r *pluginRecorder) nextErr() error {
+	if len(r.errs) == 0 {
+		return nil
+	}
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func (r *pluginRecorder) EmitRowChanges(
+	ctx context.Context, req *sinkplugin.EmitRowChangesRequest,
+) (*sinkplugin.EmitRowChangesResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.nextErr(); err != nil {
+		return nil, err
+	}
+	r.rows = append(r.rows, req)
+	return &sinkplugin.EmitRowChangesResponse{}, nil
+}
+
+func (r *pluginRecorder) EmitDDL(
+	ctx context.Context, req *sinkplugin.EmitDDLRequest,
+) (*sinkplugin.EmitDDLResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.nextErr(); err != nil {
+		return nil, err
+	}
+	r.ddls = append(r.ddls, req)
+	return &sinkplugin.EmitDDLResponse{}, nil
+}
+
+func (r *pluginRecorder) EmitCheckpoint(
+	ctx context.Context, req *sinkplugin.EmitCheckpointRequest,
+) (*sinkplugin.EmitCheckpointResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkpoints = append(r.checkpoints, req)
+	return &sinkplugin.EmitCheckpointResponse{}, nil
+}
+
+func (r *pluginRecorder) Flush(
+	ctx context.Context, req *sinkplugin.FlushRequest,
+) (*sinkplugin.FlushResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushes = append(r.flushes, req)
+	return &sinkplugin.FlushResponse{CheckpointTs: req.ResolvedTs - r.lag}, nil
+}
+
+func startSinkPlugin(t *testing.T) (*pluginRecorder, *health.Server, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	recorder := &pluginRecorder{}
+	healthServer := health.NewServer()
+	srv := grpc.NewServer()
+	sinkplugin.RegisterSinkPluginServer(srv, recorder)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+	return recorder, healthServer, lis.Addr().String()
+}
+
+func TestPluginSink(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder, healthServer, addr := startSinkPlugin(t)
+
+	uri, err := url.Parse("plugin://" + addr + "?batch-size=2")
+	require.Nil(t, err)
+	// the sink can't be created before the plugin is serving.
+	healthServer.SetServingStatus(pluginServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	_, err = newPluginSink(ctx, "test", uri, nil, make(chan error, 1))
+	require.Regexp(t, ".*not serving.*", err)
+	healthServer.SetServingStatus(pluginServiceName, healthpb.HealthCheckResponse_SERVING)
+	s, err := newPluginSink(ctx, "test", uri, nil, make(chan error, 1))
+	require.Nil(t, err)
+	defer s.Close(ctx) //nolint:errcheck
+
+	table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+	columns := func(id int64, name string) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: id, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte(name)},
+			{Name: "note", Type: mysql.TypeVarchar},
+		}
+	}
+	rows := []*model.RowChangedEvent{
+		{StartTs: 99, CommitTs: 100, Table: table, Columns: columns(1, "a")},
+		{StartTs: 100, CommitTs: 101, Table: table, PreColumns: columns(1, "a"), Columns: columns(1, "b")},
+		{StartTs: 101, CommitTs: 102, Table: table, PreColumns: columns(1, "b")},
+		{StartTs: 199, CommitTs: 200, Table: table, Columns: columns(2, "c")},
+	}
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+	checkpoint, err := s.FlushRowChangedEvents(ctx, table.TableID, 150)
+	require.Nil(t, err)
+	require.Equal(t, uint64(150), checkpoint)
+
+	require.Len(t, recorder.rows, 2)
+	require.Equal(t, "test", recorder.rows[0].ChangefeedId)
+	require.Equal(t, int64(1), recorder.rows[0].TableId)
+	require.Len(t, recorder.rows[0].Rows, 2)
+	require.Equal(t, &sinkplugin.RowChange{
+		Schema: "test", Table: "t", TableId: 1, StartTs: 99, CommitTs: 100,
+		Columns: []*sinkplugin.Column{
+			{
+				Name: "id", Type: uint32(mysql.TypeLonglong),
+				Flag: uint64(model.HandleKeyFlag | model.PrimaryKeyFlag), Value: []byte("1"),
+			},
+			{Name: "name", Type: uint32(mysql.TypeVarchar), Value: []byte("a")},
+			{Name: "note", Type: uint32(mysql.TypeVarchar), IsNull: true},
+		},
+	}, recorder.rows[0].Rows[0])
+	require.Len(t, recorder.rows[0].Rows[1].PreColumns, 3)
+	require.Empty(t, recorder.rows[1].Rows[0].Columns)
+	require.Equal(t, []*sinkplugin.FlushRequest{
+		{ChangefeedId: "test", TableId: 1, ResolvedTs: 150},
+	}, recorder.flushes)
+
+	// the rows are held while the plugin is busy.
+	recorder.fail(status.Error(codes.ResourceExhausted, "busy"))
+	checkpoint, err = s.FlushRowChangedEvents(ctx, table.TableID, 300)
+	require.Nil(t, err)
+	require.Equal(t, uint64(150), checkpoint)
+	require.Len(t, recorder.flushes, 1)
+	checkpoint, err = s.FlushRowChangedEvents(ctx, table.TableID, 300)
+	require.Nil(t, err)
+	require.Equal(t, uint64(150), checkpoint)
+	require.Len(t, recorder.rows, 2)
+
+	// the checkpoint returned by the plugin is used.
+	s.mu.Lock()
+	s.busyUntil = time.Time{}
+	s.mu.Unlock()
+	recorder.mu.Lock()
+	recorder.lag = 50
+	recorder.mu.Unlock()
+	checkpoint, err = s.FlushRowChangedEvents(ctx, table.TableID, 300)
+	require.Nil(t, err)
+	require.Equal(t, uint64(250), checkpoint)
+	require.Len(t, recorder.rows, 3)
+	require.Equal(t, uint64(200), recorder.rows[2].Rows[0].CommitTs)
+
+	// the rows are held while the plugin is not serving.
+	s.mu.Lock()
+	s.serving = false
+	s.mu.Unlock()
+	recorder.mu.Lock()
+	recorder.lag = 0
+	recorder.mu.Unlock()
+	checkpoint, err = s.FlushRowChangedEvents(ctx, table.TableID, 300)
+	require.Nil(t, err)
+	require.Equal(t, uint64(250), checkpoint)
+	require.Len(t, recorder.flushes, 2)
+	s.mu.Lock()
+	s.serving = true
+	s.mu.Unlock()
+	checkpoint, err = s.FlushRowChangedEvents(ctx, table.TableID, 300)
+	require.Nil(t, err)
+	require.Equal(t, uint64(300), checkpoint)
+
+	// other errors fail the changefeed.
+	require.Nil(t, s.EmitRowChangedEvents(ctx, &model.RowChangedEvent{
+		StartTs: 399, CommitTs: 400, Table: table, Columns: columns(3, "d"),
+	}))
+	recorder.fail(status.Error(codes.InvalidArgument, "bad rows"))
+	checkpoint, err = s.FlushRowChangedEvents(ctx, table.TableID, 500)
+	require.Regexp(t, ".*bad rows.*", err)
+	require.Equal(t, uint64(300), checkpoint)
+
+	// the DDL is sent again while the plugin is busy.
+	recorder.fail(status.Error(codes.Unavailable, "busy"))
+	ddl := &model.DDLEvent{
+		StartTs:   500,
+		CommitTs:  501,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t"},
+		Query:     "ALTER TABLE test.t ADD COLUMN age INT",
+	}
+	require.Nil(t, s.EmitDDLEvent(ctx, ddl))
+	require.Len(t, recorder.ddls, 1)
+	require.Equal(t, "ALTER TABLE test.t ADD COLUMN age INT", recorder.ddls[0].Query)
+	require.Equal(t, uint64(501), recorder.ddls[0].CommitTs)
+
+	require.Nil(t, s.EmitCheckpointTs(ctx, 501, []model.TableName{*table}))
+	require.Equal(t, []*sinkplugin.TableName{{Schema: "test", Table: "t"}}, recorder.checkpoints[0].Tables)
+
+	// the barrier sends the held rows and waits until they are flushed, and
+	// the table is removed then.
+	require.Nil(t, s.Barrier(ctx, table.TableID))
+	require.Len(t, recorder.rows, 4)
+	require.Equal(t, uint64(400), recorder.rows[3].Rows[0].CommitTs)
+	require.Equal(t, uint64(500), recorder.flushes[len(recorder.flushes)-1].ResolvedTs)
+	s.mu.Lock()
+	require.NotContains(t, s.tables, table.TableID)
+	s.mu.Unlock()
+}
+
+func TestPluginSinkUnreachable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pluginServiceName, healthpb.HealthCheckResponse_SERVING)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	originalInterval, originalTimeout := pluginHealthCheckInterval, pluginUnreachableTimeout
+	pluginHealthCheckInterval, pluginUnreachableTimeout = 10*time.Millisecond, 100*time.Millisecond
+	defer func() {
+		pluginHealthCheckInterval, pluginUnreachableTimeout = originalInterval, originalTimeout
+	}()
+
+	uri, err := url.Parse("plugin://" + lis.Addr().String() + "?timeout=50ms")
+	require.Nil(t, err)
+	errCh := make(chan error, 1)
+	s, err := newPluginSink(ctx, "test", uri, nil, errCh)
+	require.Nil(t, err)
+	defer s.Close(ctx) //nolint:errcheck
+
+	// the health checks fail once the plugin is stopped.
+	srv.Stop()
+	select {
+	case err = <-errCh:
+		require.Regexp(t, ".*unreachable.*", err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "the unreachable plugin is not reported")
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/kafka"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+// sharedClients are the clients shared by the changefeeds of the capture with
+// the shared client policy, keyed by sharedClientKey.
+var sharedClients = struct {
+	sync.Mutex
+	clients map[string]*sharedClient
+}{clients: make(map[string]*sharedClient)}
+
+type sharedClient struct {
+	sarama.Client
+	key  string
+	refs int
+	// admin and cancelMonitor are used by the metrics monitor of the client,
+	// its metrics are labelled by the client ID since they are the ones of
+	// all the changefeeds sharing it.
+	admin         kafka.ClusterAdminClient
+	cancelMonitor context.CancelFunc
+}
+
+// sharedClientRef is a reference of a shared client held by a changefeed,
+// closing it only closes the client when it's the last reference.
+type sharedClientRef struct {
+	*sharedClient
+	closeOnce sync.Once
+}
+
+// Close implements sarama.Client.
+func (r *sharedClientRef) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		sharedClients.Lock()
+		defer sharedClients.Unlock()
+
+		r.refs--
+		if r.refs > 0 {
+			return
+		}
+		delete(sharedClients.clients, r.key)
+		log.Info("close the shared sarama client",
+			zap.String("clientID", r.Config().ClientID))
+		r.cancelMonitor()
+		if r.admin != nil {
+			_ = r.admin.Close()
+		}
+		err = r.sharedClient.Client.Close()
+	})
+	return err
+}
+
+// NewSaramaClient creates the sarama client of a changefeed by the client
+// policy in config. With the shared policy, the changefeeds whose sarama
+// configs are the same share a client, the client is closed after all of
+// them close it.
+func NewSaramaClient(ctx context.Context, config *Config, saramaConfig *sarama.Config) (sarama.Client, error) {
+	if config.ClientPolicy != ClientPolicyShared {
+		return sarama.NewClient(config.BrokerEndpoints, saramaConfig)
+	}
+
+	key := sharedClientKey(config, saramaConfig)
+	sharedClients.Lock()
+	defer sharedClients.Unlock()
+
+	client, ok := sharedClients.clients[key]
+	if !ok {
+		if config.ClientID == "" {
+			// the shared clients with different configs are told apart by
+			// the hash of their configs.
+			saramaConfig.ClientID += "-" + key[:8]
+		}
+		c, err := sarama.NewClient(config.BrokerEndpoints, saramaConfig)
+		if err != nil {
+			return nil, err
+		}
+		client = &sharedClient{Client: c, key: key}
+		// the broker metrics are not collected if the admin client can't be
+		// created, which doesn't affect the replication.
+		client.admin, err = NewAdminClientImpl(config.BrokerEndpoints, saramaConfig)
+		if err != nil {
+			log.Warn("fail to create the admin client of the shared sarama client",
+				zap.String("clientID", saramaConfig.ClientID), zap.Error(err))
+			client.admin = nil
+		}
+		var monitorCtx context.Context
+		monitorCtx, client.cancelMonitor = context.WithCancel(context.Background())
+		runSaramaMetricsMonitor(monitorCtx, c.Config().MetricRegistry,
+			saramaConfig.ClientID, util.RoleFromCtx(ctx), client.admin)
+		sharedClients.clients[key] = client
+		log.Info("create a shared sarama client",
+			zap.String("clientID", saramaConfig.ClientID),
+			zap.Strings("brokers", config.BrokerEndpoints),
+			zap.String("changefeed", util.ChangefeedIDFromCtx(ctx)))
+	}
+	client.refs++
+	return &sharedClientRef{sharedClient: client}, nil
+}
+
+// sharedClientKey identifies the sarama config of a client, the changefeeds
+// share a client only if all the settings of their producers and connections
+// are the same. It's hashed since it contains the credential.
+func sharedClientKey(config *Config, saramaConfig *sarama.Config) string {
+	var b strings.Builder
+	producer := saramaConfig.Producer
+	fmt.Fprintf(&b, "brokers=%s|client-id=%s|version=%s|channel-buffer-size=%d|",
+		strings.Join(config.BrokerEndpoints, ","), saramaConfig.ClientID,
+		saramaConfig.Version, saramaConfig.ChannelBufferSize)
+	fmt.Fprintf(&b, "max-message-bytes=%d|required-acks=%d|timeout=%s|compression=%s|"+
+		"compression-level=%d|idempotent=%t|flush=%d,%d,%d,%s|retry=%d,%s|",
+		producer.MaxMessageBytes, producer.RequiredAcks, producer.Timeout, producer.Compression,
+		producer.CompressionLevel, producer.Idempotent,
+		producer.Flush.Bytes, producer.Flush.Messages, producer.Flush.MaxMessages, producer.Flush.Frequency,
+		producer.Retry.Max, producer.Retry.Backoff)
+	fmt.Fprintf(&b, "metadata=%d,%s,%s,%s|",
+		saramaConfig.Metadata.Retry.Max, saramaConfig.Metadata.Retry.Backoff,
+		saramaConfig.Metadata.RefreshFrequency, saramaConfig.Metadata.Timeout)
+	net := saramaConfig.Net
+	fmt.Fprintf(&b, "net=%d,%s,%s,%s,%s|",
+		net.MaxOpenRequests, net.DialTimeout, net.ReadTimeout, net.WriteTimeout, net.KeepAlive)
+	fmt.Fprintf(&b, "tls=%t", net.TLS.Enable)
+	if config.Credential != nil {
+		fmt.Fprintf(&b, ",%s,%s,%s,%v",
+			config.Credential.CAPath, config.Credential.CertPath, config.Credential.KeyPath,
+			config.Credential.CertAllowedCN)
+	}
+	fmt.Fprintf(&b, "|sasl=%t,%s,%s,%s,%+v",
+		net.SASL.Enable, net.SASL.Mechanism, net.SASL.User, net.SASL.Password, net.SASL.GSSAPI)
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
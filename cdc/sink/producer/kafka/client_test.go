@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/tiflow/pkg/kafka"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSaramaClient(t *testing.T) {
+	leader := sarama.NewMockBroker(t, 1)
+	defer leader.Close()
+	leader.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(leader.Addr(), leader.BrokerID()).
+			SetLeader(kafka.DefaultMockTopicName, 0, leader.BrokerID()),
+	})
+
+	defer func(impl kafka.ClusterAdminClientCreator) {
+		NewAdminClientImpl = impl
+	}(NewAdminClientImpl)
+	NewAdminClientImpl = kafka.NewMockAdminClient
+
+	newClient := func(changefeedID, policy, compression string) sarama.Client {
+		config := NewConfig()
+		config.Compression = compression
+		config.Version = "0.9.0.0"
+		config.BrokerEndpoints = []string{leader.Addr()}
+		config.ClientPolicy = policy
+		ctx := util.PutRoleInCtx(context.Background(), util.RoleTester)
+		ctx = util.PutCaptureAddrInCtx(ctx, "127.0.0.1:8300")
+		ctx = util.PutChangefeedIDInCtx(ctx, changefeedID)
+		saramaConfig, err := NewSaramaConfig(ctx, config)
+		require.Nil(t, err)
+		client, err := NewSaramaClient(ctx, config, saramaConfig)
+		require.Nil(t, err)
+		return client
+	}
+
+	// Isolated clients.
+	client1 := newClient("changefeed-1", ClientPolicyIsolated, "none")
+	client2 := newClient("changefeed-2", ClientPolicyIsolated, "none")
+	require.NotEqual(t, client1.Config().ClientID, client2.Config().ClientID)
+	require.Nil(t, client1.Close())
+	require.True(t, client1.Closed())
+	require.False(t, client2.Closed())
+	require.Nil(t, client2.Close())
+
+	// Shared clients.
+	client1 = newClient("changefeed-1", ClientPolicyShared, "none")
+	client2 = newClient("changefeed-2", ClientPolicyShared, "none")
+	require.Regexp(t, "^TiCDC_sarama_producer_tester_127.0.0.1_8300_shared-[0-9a-f]{8}$", client1.Config().ClientID)
+	require.Equal(t, client1.(*sharedClientRef).sharedClient, client2.(*sharedClientRef).sharedClient)
+	require.Len(t, sharedClients.clients, 1)
+
+	// The changefeeds with different producer settings don't share a client.
+	client3 := newClient("changefeed-3", ClientPolicyShared, "lz4")
+	require.NotEqual(t, client1.(*sharedClientRef).sharedClient, client3.(*sharedClientRef).sharedClient)
+	require.NotEqual(t, client1.Config().ClientID, client3.Config().ClientID)
+	require.Equal(t, sarama.CompressionLZ4, client3.Config().Producer.Compression)
+	require.Len(t, sharedClients.clients, 2)
+	require.Nil(t, client3.Close())
+	require.Len(t, sharedClients.clients, 1)
+
+	// Closing a reference more than once doesn't release others.
+	require.Nil(t, client1.Close())
+	require.Nil(t, client1.Close())
+	require.False(t, client2.Closed())
+	require.Nil(t, client2.Close())
+	require.True(t, client2.Closed())
+	require.Len(t, sharedClients.clients, 0)
+}
@@ -16,6 +16,7 @@ package kafka
 import (
 	"context"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/dispatcher/topic"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
@@ -30,6 +32,19 @@ import (
 	"go.uber.org/zap"
 )
 
+// The client policies decide whether the changefeeds of a capture share sarama clients.
+const (
+	// ClientPolicyIsolated creates a client for each changefeed, whose client ID
+	// contains the changefeed ID, so the changefeed has its own quotas, metrics and
+	// connections to the brokers.
+	ClientPolicyIsolated = "isolated"
+	// ClientPolicyShared shares a client among the changefeeds of a capture which
+	// use the same client and producer config, to reduce the connections to the
+	// brokers. The changefeeds share the quotas, metrics and failures of the
+	// client, whose client ID contains the hash of the config instead.
+	ClientPolicyShared = "shared"
+)
+
 // Config stores user specified Kafka producer configuration
 type Config struct {
 	BrokerEndpoints []string
@@ -42,6 +57,7 @@ type Config struct {
 	MaxMessageBytes int
 	Compression     string
 	ClientID        string
+	ClientPolicy    string
 	Credential      *security.Credential
 	SASL            *security.SASL
 	// control whether to create topic
@@ -61,6 +77,7 @@ func NewConfig() *Config {
 		MaxMessageBytes:   config.DefaultMaxMessageBytes,
 		ReplicationFactor: 1,
 		Compression:       "none",
+		ClientPolicy:      ClientPolicyIsolated,
 		Credential:        &security.Credential{},
 		SASL:              &security.SASL{},
 		AutoCreate:        true,
@@ -143,6 +160,15 @@ func (c *Config) Apply(sinkURI *url.URL) error {
 
 	c.ClientID = params.Get("kafka-client-id")
 
+	s = params.Get("kafka-client-policy")
+	if s != "" {
+		if s != ClientPolicyIsolated && s != ClientPolicyShared {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"kafka-client-policy must be %s or %s", ClientPolicyIsolated, ClientPolicyShared)
+		}
+		c.ClientPolicy = s
+	}
+
 	s = params.Get("ca")
 	if s != "" {
 		c.Credential.CAPath = s
@@ -158,6 +184,9 @@ func (c *Config) Apply(sinkURI *url.URL) error {
 		c.Credential.KeyPath = s
 	}
 
+	c.Credential.CertAllowedSAN = security.SplitPins(params.Get("san"))
+	c.Credential.CertAllowedSPKI = security.SplitPins(params.Get("spki"))
+
 	s = params.Get("auto-create-topic")
 	if s != "" {
 		autoCreate, err := strconv.ParseBool(s)
@@ -278,6 +307,56 @@ type AutoCreateTopicConfig struct {
 	AutoCreate        bool
 	PartitionNum      int32
 	ReplicationFactor int16
+	// TopicRules override the settings above for the topics matching them,
+	// the first matched one takes effect.
+	TopicRules []*AutoCreateTopicRule
+}
+
+// AutoCreateTopicRule is the settings of the auto-created topics whose names
+// match Pattern.
+type AutoCreateTopicRule struct {
+	Pattern *regexp.Regexp
+	config.TopicConfig
+}
+
+// WithDispatchRules adds the topic configs of the dispatch rules to the
+// AutoCreateTopicConfig, the topics of a rule are matched by its topic expression.
+func (c *AutoCreateTopicConfig) WithDispatchRules(rules []*config.DispatchRule) *AutoCreateTopicConfig {
+	for _, rule := range rules {
+		if rule.TopicConfig == nil || rule.TopicRule == "" {
+			continue
+		}
+		c.TopicRules = append(c.TopicRules, &AutoCreateTopicRule{
+			Pattern:     topic.Expression(rule.TopicRule).Regexp(),
+			TopicConfig: *rule.TopicConfig,
+		})
+	}
+	return c
+}
+
+// TopicDetail returns the detail used to create the topic.
+func (c *AutoCreateTopicConfig) TopicDetail(topicName string) *sarama.TopicDetail {
+	detail := &sarama.TopicDetail{
+		NumPartitions:     c.PartitionNum,
+		ReplicationFactor: c.ReplicationFactor,
+	}
+	for _, rule := range c.TopicRules {
+		if !rule.Pattern.MatchString(topicName) {
+			continue
+		}
+		if rule.PartitionNum > 0 {
+			detail.NumPartitions = rule.PartitionNum
+		}
+		if rule.ReplicationFactor > 0 {
+			detail.ReplicationFactor = rule.ReplicationFactor
+		}
+		if rule.RetentionMs != 0 {
+			retention := strconv.FormatInt(rule.RetentionMs, 10)
+			detail.ConfigEntries = map[string]*string{"retention.ms": &retention}
+		}
+		break
+	}
+	return detail
 }
 
 // DeriveTopicConfig derive a `topicConfig` from the `Config`
@@ -305,6 +384,10 @@ func NewSaramaConfig(ctx context.Context, c *Config) (*sarama.Config, error) {
 	}
 	captureAddr := util.CaptureAddrFromCtx(ctx)
 	changefeedID := util.ChangefeedIDFromCtx(ctx)
+	if c.ClientPolicy == ClientPolicyShared {
+		// the client is shared by changefeeds.
+		changefeedID = ClientPolicyShared
+	}
 
 	config.ClientID, err = kafkaClientID(role, captureAddr, changefeedID, c.ClientID)
 	if err != nil {
@@ -368,7 +451,7 @@ func NewSaramaConfig(ctx context.Context, c *Config) (*sarama.Config, error) {
 
 	if c.Credential != nil && len(c.Credential.CAPath) != 0 {
 		config.Net.TLS.Enable = true
-		config.Net.TLS.Config, err = c.Credential.ToTLSConfig()
+		config.Net.TLS.Config, err = c.Credential.ToTLSConfigWithPinning()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
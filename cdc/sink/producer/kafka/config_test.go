@@ -137,6 +137,16 @@ func TestCompleteConfigByOpts(t *testing.T) {
 	require.Equal(t, "2.6.0", cfg.Version)
 	require.Equal(t, 4096, cfg.MaxMessageBytes)
 
+	// certificate pinning
+	uri = "kafka://127.0.0.1:9092/kafka-test?ca=ca.pem&san=kafka.example.com,127.0.0.1&spki=AAAA%2B"
+	sinkURI, err = url.Parse(uri)
+	require.Nil(t, err)
+	cfg = NewConfig()
+	err = cfg.Apply(sinkURI)
+	require.Nil(t, err)
+	require.Equal(t, []string{"kafka.example.com", "127.0.0.1"}, cfg.Credential.CertAllowedSAN)
+	require.Equal(t, []string{"AAAA+"}, cfg.Credential.CertAllowedSPKI)
+
 	// multiple kafka broker endpoints
 	uri = "kafka://127.0.0.1:9092,127.0.0.1:9091,127.0.0.1:9090/kafka-test?"
 	sinkURI, err = url.Parse(uri)
@@ -177,6 +187,24 @@ func TestCompleteConfigByOpts(t *testing.T) {
 	cfg = NewConfig()
 	err = cfg.Apply(sinkURI)
 	require.Regexp(t, ".*invalid partition num.*", errors.Cause(err))
+
+	// Client policy.
+	require.Equal(t, ClientPolicyIsolated, NewConfig().ClientPolicy)
+	uri = "kafka://127.0.0.1:9092/abc?kafka-client-policy=shared"
+	sinkURI, err = url.Parse(uri)
+	require.Nil(t, err)
+	cfg = NewConfig()
+	err = cfg.Apply(sinkURI)
+	require.Nil(t, err)
+	require.Equal(t, ClientPolicyShared, cfg.ClientPolicy)
+
+	// Illegal kafka-client-policy.
+	uri = "kafka://127.0.0.1:9092/abc?kafka-client-policy=a"
+	sinkURI, err = url.Parse(uri)
+	require.Nil(t, err)
+	cfg = NewConfig()
+	err = cfg.Apply(sinkURI)
+	require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err))
 }
 
 func TestSetPartitionNum(t *testing.T) {
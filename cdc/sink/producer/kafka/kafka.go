@@ -331,7 +331,11 @@ func NewKafkaSaramaProducer(
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
 
-	runSaramaMetricsMonitor(ctx, saramaConfig.MetricRegistry, changefeedID, role, admin)
+	// The metrics of a shared client are the ones of all the changefeeds
+	// sharing it, they are collected by the client itself.
+	if _, ok := client.(*sharedClientRef); !ok {
+		runSaramaMetricsMonitor(ctx, saramaConfig.MetricRegistry, changefeedID, role, admin)
+	}
 
 	k := &kafkaSaramaProducer{
 		admin:         admin,
@@ -0,0 +1,34 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pubsub provides a Google Cloud Pub/Sub based mq Producer implementation.
+//
+// SinkURL format like:
+// gcpubsub://{project}/{topic}?protocol=canal-json&ordering-key=partition
+//
+// Options:
+//  1. `credentials-file`: the service account key file, the application default
+//     credentials are used if it's not specified.
+//  2. `endpoint`: the Pub/Sub API endpoint, e.g. a regional endpoint
+//     https://us-east1-pubsub.googleapis.com/, which keeps the order of messages
+//     with the same ordering key better.
+//  3. `ordering-key`: how the ordering key of a message is decided, `partition`
+//     uses the partition dispatched by the `dispatchers` rules, so the messages
+//     are ordered by table with the `table` dispatcher and by row key with the
+//     `index-value` dispatcher, `table` uses the schema and table name of the
+//     message, `none` publishes messages without ordering key.
+//  4. `partition-num`: the number of virtual partitions messages are dispatched to.
+//  5. `auto-create-topic`: whether to create the topic if it doesn't exist.
+//
+// The Pub/Sub emulator is used without authentication if PUBSUB_EMULATOR_HOST is set.
+package pubsub
@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultMaxMessageBytes is the default max message size of the pubsub sink.
+	// Pub/Sub limits a publish request to 10MB, and the payload is base64 encoded
+	// in the request, so a 7MB message fits in it.
+	DefaultMaxMessageBytes = 7 * 1024 * 1024
+
+	defaultPartitionNum = 16
+	// maxPublishBatchMessages is the max number of messages in a publish request.
+	maxPublishBatchMessages = 1000
+	// maxPublishBatchBytes is the max size of the payload in a publish request.
+	maxPublishBatchBytes = DefaultMaxMessageBytes
+	// maxPendingMessages is the max number of messages buffered by the producer,
+	// they are published when it's exceeded.
+	maxPendingMessages = 10000
+
+	emulatorHostEnv = "PUBSUB_EMULATOR_HOST"
+)
+
+// Ordering key modes.
+const (
+	orderingKeyPartition = "partition"
+	orderingKeyTable     = "table"
+	orderingKeyNone      = "none"
+)
+
+type options struct {
+	project         string
+	topic           string
+	credentialsFile string
+	endpoint        string
+	// withoutAuth is set if the emulator is used.
+	withoutAuth     bool
+	orderingKey     string
+	partitionNum    int32
+	autoCreateTopic bool
+}
+
+func parseSinkOptions(u *url.URL) (*options, error) {
+	if u.Scheme != "gcpubsub" {
+		return nil, fmt.Errorf("unsupported pubsub scheme: %s", u.Scheme)
+	}
+	opt := &options{
+		project:         u.Host,
+		topic:           strings.Trim(u.Path, "/"),
+		orderingKey:     orderingKeyPartition,
+		partitionNum:    defaultPartitionNum,
+		autoCreateTopic: true,
+	}
+	if opt.project == "" {
+		return nil, fmt.Errorf("no project is specified in pubsub sink uri")
+	}
+	if opt.topic == "" {
+		return nil, fmt.Errorf("no topic is specified in pubsub sink uri")
+	}
+
+	params := u.Query()
+	opt.credentialsFile = params.Get("credentials-file")
+	opt.endpoint = params.Get("endpoint")
+	if host := os.Getenv(emulatorHostEnv); host != "" && opt.endpoint == "" {
+		opt.endpoint = "http://" + host + "/"
+		opt.withoutAuth = true
+	}
+	if s := params.Get("ordering-key"); s != "" {
+		switch s {
+		case orderingKeyPartition, orderingKeyTable, orderingKeyNone:
+			opt.orderingKey = s
+		default:
+			return nil, fmt.Errorf("unknown ordering key %s", s)
+		}
+	}
+	if s := params.Get("partition-num"); s != "" {
+		c, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		if c <= 0 {
+			return nil, fmt.Errorf("invalid partition-num %d", c)
+		}
+		opt.partitionNum = int32(c)
+	}
+	if s := params.Get("auto-create-topic"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		opt.autoCreateTopic = b
+	}
+	return opt, nil
+}
+
+// topicName returns the full name of the topic.
+func (o *options) topicName(topic string) string {
+	return fmt.Sprintf("projects/%s/topics/%s", o.project, topic)
+}
@@ -0,0 +1,303 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	pubsubapi "google.golang.org/api/pubsub/v1"
+)
+
+const (
+	publishRetryBackoffBaseInMs = 100
+	publishRetryBackoffMaxInMs  = 5000
+	publishMaxTries             = 5
+
+	topicOperationTimeout = 10 * time.Second
+)
+
+// Producer provide a way to send msg to Google Cloud Pub/Sub.
+type Producer struct {
+	opt *options
+	svc *pubsubapi.Service
+
+	mu sync.Mutex
+	// pending buffers the messages of every topic in sending order.
+	pending      map[string][]*pubsubapi.PubsubMessage
+	pendingCount int
+	// topics are the topics known to exist.
+	topics map[string]struct{}
+
+	// flushMu makes sure messages with the same ordering key are published in order.
+	flushMu sync.Mutex
+}
+
+// NewProducer creates a pubsub producer.
+func NewProducer(ctx context.Context, u *url.URL) (*Producer, error) {
+	opt, err := parseSinkOptions(u)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPubSubInvalidConfig, err)
+	}
+
+	var clientOpts []option.ClientOption
+	if opt.endpoint != "" {
+		clientOpts = append(clientOpts, option.WithEndpoint(opt.endpoint))
+	}
+	if opt.withoutAuth {
+		clientOpts = append(clientOpts, option.WithoutAuthentication())
+	} else if opt.credentialsFile != "" {
+		clientOpts = append(clientOpts, option.WithCredentialsFile(opt.credentialsFile))
+	}
+	// The application default credentials are used if no credentials are specified.
+	svc, err := pubsubapi.NewService(ctx, clientOpts...)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPubSubNewProducer, err)
+	}
+
+	p := &Producer{
+		opt:     opt,
+		svc:     svc,
+		pending: make(map[string][]*pubsubapi.PubsubMessage),
+		topics:  make(map[string]struct{}),
+	}
+	if _, err := p.TopicPartitions(opt.topic); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return p, nil
+}
+
+// DefaultTopic returns the topic specified in the sink uri.
+func (p *Producer) DefaultTopic() string {
+	return p.opt.topic
+}
+
+// TopicPartitions makes sure the topic exists and returns the number of virtual
+// partitions of it. Pub/Sub topics have no partitions, messages are dispatched
+// to partitions to decide their ordering keys.
+func (p *Producer) TopicPartitions(topic string) (int32, error) {
+	p.mu.Lock()
+	_, ok := p.topics[topic]
+	p.mu.Unlock()
+	if ok {
+		return p.opt.partitionNum, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), topicOperationTimeout)
+	defer cancel()
+	name := p.opt.topicName(topic)
+	_, err := p.svc.Projects.Topics.Get(name).Context(ctx).Do()
+	if err != nil {
+		if !isNotFound(err) || !p.opt.autoCreateTopic {
+			return 0, cerror.WrapError(cerror.ErrPubSubCreateTopic, err)
+		}
+		_, err = p.svc.Projects.Topics.Create(name, &pubsubapi.Topic{}).Context(ctx).Do()
+		// the topic may be created by others concurrently.
+		if err != nil && !isAlreadyExists(err) {
+			return 0, cerror.WrapError(cerror.ErrPubSubCreateTopic, err)
+		}
+		log.Info("pubsub topic created", zap.String("topic", name))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.topics[topic] = struct{}{}
+	return p.opt.partitionNum, nil
+}
+
+func (p *Producer) newMessage(message *codec.MQMessage, partition int32) *pubsubapi.PubsubMessage {
+	attrs := map[string]string{
+		"ts":       strconv.FormatUint(message.Ts, 10),
+		"type":     strconv.Itoa(int(message.Type)),
+		"protocol": strconv.Itoa(int(message.Protocol)),
+	}
+	if len(message.Key) > 0 {
+		// keys of some protocols are binary, while attributes must be strings.
+		attrs["key"] = base64.StdEncoding.EncodeToString(message.Key)
+	}
+	if message.Schema != nil {
+		attrs["schema"] = *message.Schema
+	}
+	if message.Table != nil {
+		attrs["table"] = *message.Table
+	}
+
+	var orderingKey string
+	switch p.opt.orderingKey {
+	case orderingKeyPartition:
+		orderingKey = strconv.Itoa(int(partition))
+	case orderingKeyTable:
+		if message.Schema != nil && message.Table != nil {
+			orderingKey = *message.Schema + "." + *message.Table
+		}
+	}
+	return &pubsubapi.PubsubMessage{
+		Data:        base64.StdEncoding.EncodeToString(message.Value),
+		Attributes:  attrs,
+		OrderingKey: orderingKey,
+	}
+}
+
+// AsyncSendMessage buffers the message, buffered messages are published by Flush.
+func (p *Producer) AsyncSendMessage(
+	ctx context.Context, topic string, partition int32, message *codec.MQMessage,
+) error {
+	if topic == "" {
+		topic = p.opt.topic
+	}
+	msg := p.newMessage(message, partition)
+
+	p.mu.Lock()
+	p.pending[topic] = append(p.pending[topic], msg)
+	p.pendingCount++
+	full := p.pendingCount >= maxPendingMessages
+	p.mu.Unlock()
+
+	if full {
+		return p.Flush(ctx)
+	}
+	return nil
+}
+
+// SyncBroadcastMessage publishes the message to all partitions, that is, with
+// the ordering key of every partition. The message is published only once if
+// the ordering keys are not decided by partitions.
+func (p *Producer) SyncBroadcastMessage(
+	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
+) error {
+	if topic == "" {
+		topic = p.opt.topic
+	}
+	if p.opt.orderingKey != orderingKeyPartition {
+		partitionsNum = 1
+	}
+	msgs := make([]*pubsubapi.PubsubMessage, 0, partitionsNum)
+	for partition := int32(0); partition < partitionsNum; partition++ {
+		msgs = append(msgs, p.newMessage(message, partition))
+	}
+	return p.publish(ctx, topic, msgs)
+}
+
+// Flush publishes all buffered messages. Messages with different ordering keys
+// are published concurrently.
+func (p *Producer) Flush(ctx context.Context) error {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = make(map[string][]*pubsubapi.PubsubMessage)
+	p.pendingCount = 0
+	p.mu.Unlock()
+
+	g, ctx := errgroup.WithContext(ctx)
+	for topic, msgs := range pending {
+		topic := topic
+		for _, keyMsgs := range groupByOrderingKey(msgs) {
+			keyMsgs := keyMsgs
+			g.Go(func() error {
+				return p.publish(ctx, topic, keyMsgs)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// publish publishes messages in order, they're split into batches to meet
+// the limits of publish requests.
+func (p *Producer) publish(ctx context.Context, topic string, msgs []*pubsubapi.PubsubMessage) error {
+	name := p.opt.topicName(topic)
+	for len(msgs) > 0 {
+		n, size := 0, 0
+		for n < len(msgs) && n < maxPublishBatchMessages {
+			size += len(msgs[n].Data)
+			if n > 0 && size > maxPublishBatchBytes {
+				break
+			}
+			n++
+		}
+		req := &pubsubapi.PublishRequest{Messages: msgs[:n]}
+		err := retry.Do(ctx, func() error {
+			_, err := p.svc.Projects.Topics.Publish(name, req).Context(ctx).Do()
+			return err
+		}, retry.WithBackoffBaseDelay(publishRetryBackoffBaseInMs),
+			retry.WithBackoffMaxDelay(publishRetryBackoffMaxInMs),
+			retry.WithMaxTries(publishMaxTries),
+			retry.WithIsRetryableErr(isRetryable))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPubSubSendMessage, err)
+		}
+		msgs = msgs[n:]
+	}
+	return nil
+}
+
+// Close discards the buffered messages, the REST client needs no closing.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = make(map[string][]*pubsubapi.PubsubMessage)
+	p.pendingCount = 0
+	return nil
+}
+
+// groupByOrderingKey groups messages by ordering key, the order of messages in
+// every group is kept.
+func groupByOrderingKey(msgs []*pubsubapi.PubsubMessage) [][]*pubsubapi.PubsubMessage {
+	index := make(map[string]int)
+	var groups [][]*pubsubapi.PubsubMessage
+	for _, msg := range msgs {
+		i, ok := index[msg.OrderingKey]
+		if !ok {
+			i = len(groups)
+			index[msg.OrderingKey] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], msg)
+	}
+	return groups
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := errors.Cause(err).(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusNotFound
+}
+
+func isAlreadyExists(err error) bool {
+	apiErr, ok := errors.Cause(err).(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusConflict
+}
+
+func isRetryable(err error) bool {
+	apiErr, ok := errors.Cause(err).(*googleapi.Error)
+	if !ok {
+		// network errors are retryable.
+		return errors.Cause(err) != context.Canceled
+	}
+	return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	pubsubapi "google.golang.org/api/pubsub/v1"
+)
+
+// mockPubSubServer imitates the REST API of Pub/Sub.
+type mockPubSubServer struct {
+	mu        sync.Mutex
+	topics    map[string]struct{}
+	published map[string][]*pubsubapi.PubsubMessage
+}
+
+func (s *mockPubSubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(name, ":publish"):
+		topic := strings.TrimSuffix(name, ":publish")
+		if _, ok := s.topics[topic]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		req := &pubsubapi.PublishRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.published[topic] = append(s.published[topic], req.Messages...)
+		resp := &pubsubapi.PublishResponse{MessageIds: make([]string, len(req.Messages))}
+		_ = json.NewEncoder(w).Encode(resp)
+	case r.Method == http.MethodGet:
+		if _, ok := s.topics[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&pubsubapi.Topic{Name: name})
+	case r.Method == http.MethodPut:
+		if _, ok := s.topics[name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		s.topics[name] = struct{}{}
+		_ = json.NewEncoder(w).Encode(&pubsubapi.Topic{Name: name})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newMockPubSubServer(t *testing.T) *mockPubSubServer {
+	s := &mockPubSubServer{
+		topics:    make(map[string]struct{}),
+		published: make(map[string][]*pubsubapi.PubsubMessage),
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	t.Setenv(emulatorHostEnv, server.Listener.Addr().String())
+	return s
+}
+
+func TestParseSinkOptions(t *testing.T) {
+	t.Setenv(emulatorHostEnv, "")
+
+	u, err := url.Parse("gcpubsub://project/topic?credentials-file=/tmp/key.json" +
+		"&ordering-key=table&partition-num=4&auto-create-topic=false")
+	require.Nil(t, err)
+	opt, err := parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, &options{
+		project:         "project",
+		topic:           "topic",
+		credentialsFile: "/tmp/key.json",
+		orderingKey:     orderingKeyTable,
+		partitionNum:    4,
+	}, opt)
+	require.Equal(t, "projects/project/topics/topic", opt.topicName(opt.topic))
+
+	t.Setenv(emulatorHostEnv, "127.0.0.1:8085")
+	u, err = url.Parse("gcpubsub://project/topic")
+	require.Nil(t, err)
+	opt, err = parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, "http://127.0.0.1:8085/", opt.endpoint)
+	require.True(t, opt.withoutAuth)
+	require.Equal(t, orderingKeyPartition, opt.orderingKey)
+	require.Equal(t, int32(defaultPartitionNum), opt.partitionNum)
+
+	for uri, errMsg := range map[string]string{
+		"gcpubsub:///topic":                         "no project",
+		"gcpubsub://project":                        "no topic",
+		"gcpubsub://project/topic?ordering-key=row": "unknown ordering key",
+		"gcpubsub://project/topic?partition-num=0":  "invalid partition-num",
+		"pubsub://project/topic":                    "unsupported pubsub scheme",
+	} {
+		u, err = url.Parse(uri)
+		require.Nil(t, err)
+		_, err = parseSinkOptions(u)
+		require.Regexp(t, errMsg, err)
+	}
+}
+
+func TestProducer(t *testing.T) {
+	server := newMockPubSubServer(t)
+	ctx := context.Background()
+
+	u, err := url.Parse("gcpubsub://project/topic?auto-create-topic=false")
+	require.Nil(t, err)
+	_, err = NewProducer(ctx, u)
+	require.Regexp(t, "pubsub create topic failed", err)
+
+	u, err = url.Parse("gcpubsub://project/topic?partition-num=2")
+	require.Nil(t, err)
+	p, err := NewProducer(ctx, u)
+	require.Nil(t, err)
+	require.Contains(t, server.topics, "projects/project/topics/topic")
+
+	schema, table := "test", "t"
+	for i := 0; i < 4; i++ {
+		msg := codec.NewMQMessage(config.ProtocolCanalJSON, []byte("key"), []byte{byte(i)},
+			uint64(i), model.MqMessageTypeRow, &schema, &table)
+		require.Nil(t, p.AsyncSendMessage(ctx, "", int32(i%2), msg))
+	}
+	require.Empty(t, server.published)
+	require.Nil(t, p.Flush(ctx))
+	msgs := server.published["projects/project/topics/topic"]
+	require.Len(t, msgs, 4)
+	// messages with the same ordering key are published in order.
+	var partition0 []string
+	for _, msg := range msgs {
+		require.Equal(t, "test", msg.Attributes["schema"])
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("key")), msg.Attributes["key"])
+		if msg.OrderingKey == "0" {
+			partition0 = append(partition0, msg.Data)
+		}
+	}
+	require.Equal(t, []string{
+		base64.StdEncoding.EncodeToString([]byte{0}),
+		base64.StdEncoding.EncodeToString([]byte{2}),
+	}, partition0)
+
+	// broadcast to a new topic.
+	partitions, err := p.TopicPartitions("topic2")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), partitions)
+	msg := codec.NewMQMessage(config.ProtocolCanalJSON, nil, []byte("resolved"),
+		10, model.MqMessageTypeResolved, nil, nil)
+	require.Nil(t, p.SyncBroadcastMessage(ctx, "topic2", partitions, msg))
+	msgs = server.published["projects/project/topics/topic2"]
+	require.Len(t, msgs, 2)
+	require.Equal(t, "0", msgs[0].OrderingKey)
+	require.Equal(t, "1", msgs[1].OrderingKey)
+	require.Nil(t, p.Close())
+}
+
+func TestGroupByOrderingKey(t *testing.T) {
+	t.Parallel()
+
+	msgs := []*pubsubapi.PubsubMessage{
+		{Data: "1", OrderingKey: "a"},
+		{Data: "2", OrderingKey: "b"},
+		{Data: "3", OrderingKey: "a"},
+		{Data: "4", OrderingKey: ""},
+	}
+	groups := groupByOrderingKey(msgs)
+	require.Equal(t, [][]*pubsubapi.PubsubMessage{
+		{msgs[0], msgs[2]},
+		{msgs[1]},
+		{msgs[3]},
+	}, groups)
+}
@@ -43,6 +43,9 @@ func parseSinkOptions(u *url.URL) (opt *Option, err error) {
 		return nil, err
 	}
 	p := parseProducerOptions(u)
+	if p.Topic == "" {
+		return nil, fmt.Errorf("no topic is specified in pulsar sink uri: %s", u.Redacted())
+	}
 	opt = &Option{
 		clientOptions:   c,
 		producerOptions: p,
@@ -92,7 +95,9 @@ func parseProducerOptions(u *url.URL) (opt *pulsar.ProducerOptions) {
 		MaxPendingMessages:      vs.Int("maxPendingMessages"),
 		DisableBatching:         vs.Bool("disableBatching"),
 		BatchingMaxPublishDelay: vs.Duration("batchingMaxPublishDelay"),
-		BatchingMaxMessages:     uint(vs.Int("tlsAllowInsecureConnection")),
+		BatchingMaxMessages:     uint(vs.Int("batchingMaxMessages")),
+		BatchingMaxSize:         uint(vs.Int("batchingMaxSize")),
+		SendTimeout:             vs.Duration("sendTimeout"),
 		Properties:              vs.SubPathKV("properties"),
 	}
 	hashingScheme := vs.Str("hashingScheme")
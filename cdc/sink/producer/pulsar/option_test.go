@@ -0,0 +1,54 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSinkOptions(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse("pulsar://127.0.0.1:6650/persistent://public/default/test?" +
+		"batchingMaxMessages=100&batchingMaxSize=1024&batchingMaxPublishDelay=10ms&sendTimeout=5s")
+	require.Nil(t, err)
+	opt, err := parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, "pulsar://127.0.0.1:6650", opt.clientOptions.URL)
+	require.Equal(t, "persistent://public/default/test", opt.producerOptions.Topic)
+	require.Equal(t, uint(100), opt.producerOptions.BatchingMaxMessages)
+	require.Equal(t, uint(1024), opt.producerOptions.BatchingMaxSize)
+	require.Equal(t, 10*time.Millisecond, opt.producerOptions.BatchingMaxPublishDelay)
+	require.Equal(t, 5*time.Second, opt.producerOptions.SendTimeout)
+
+	u, err = url.Parse("pulsar://127.0.0.1:6650?topic=test")
+	require.Nil(t, err)
+	opt, err = parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, "test", opt.producerOptions.Topic)
+
+	u, err = url.Parse("pulsar://127.0.0.1:6650/")
+	require.Nil(t, err)
+	_, err = parseSinkOptions(u)
+	require.Regexp(t, "no topic is specified", err)
+
+	u, err = url.Parse("kafka://127.0.0.1:6650/test")
+	require.Nil(t, err)
+	_, err = parseSinkOptions(u)
+	require.Regexp(t, "unsupported pulsar scheme", err)
+}
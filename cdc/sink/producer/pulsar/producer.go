@@ -17,8 +17,10 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"sync"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
@@ -26,49 +28,118 @@ import (
 	"go.uber.org/zap"
 )
 
+// DefaultMaxMessageBytes is the default max message size of pulsar brokers,
+// which is set by `maxMessageSize` in broker.conf.
+const DefaultMaxMessageBytes = 5 * 1024 * 1024 // 5MB
+
 // NewProducer create a pulsar producer.
 func NewProducer(u *url.URL, errCh chan error) (*Producer, error) {
+	opt, err := parseSinkOptions(u)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
 	failpoint.Inject("MockPulsar", func() {
 		failpoint.Return(&Producer{
+			opt:          *opt,
 			errCh:        errCh,
-			partitionNum: 4,
+			producers:    make(map[string]pulsar.Producer),
+			partitionNum: map[string]int32{},
 		}, nil)
 	})
 
-	opt, err := parseSinkOptions(u)
-	if err != nil {
-		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
-	}
 	client, err := pulsar.NewClient(*opt.clientOptions)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
-	producer, err := client.CreateProducer(*opt.producerOptions)
-	if err != nil {
-		client.Close()
-		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	p := &Producer{
+		opt:          *opt,
+		client:       client,
+		errCh:        errCh,
+		producers:    make(map[string]pulsar.Producer),
+		partitionNum: make(map[string]int32),
 	}
-	partitions, err := client.TopicPartitions(opt.producerOptions.Topic)
-	if err != nil {
+	// make sure the default topic is available.
+	if _, err := p.TopicPartitions(opt.producerOptions.Topic); err != nil {
 		client.Close()
-		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+		return nil, errors.Trace(err)
 	}
-	return &Producer{
-		errCh:        errCh,
-		opt:          *opt,
-		client:       client,
-		producer:     producer,
-		partitionNum: len(partitions),
-	}, nil
+	return p, nil
 }
 
 // Producer provide a way to send msg to pulsar.
+// A pulsar producer can only send messages to one topic, so a producer is created
+// for every topic the changefeed dispatches messages to.
 type Producer struct {
-	opt          Option
-	client       pulsar.Client
-	producer     pulsar.Producer
-	errCh        chan error
-	partitionNum int
+	opt    Option
+	client pulsar.Client
+	errCh  chan error
+
+	mu           sync.RWMutex
+	producers    map[string]pulsar.Producer
+	partitionNum map[string]int32
+}
+
+// DefaultTopic returns the topic specified in the sink uri.
+func (p *Producer) DefaultTopic() string {
+	return p.opt.producerOptions.Topic
+}
+
+// TopicPartitions returns the partition number of the topic, the topic is
+// created by pulsar automatically if it does not exist.
+func (p *Producer) TopicPartitions(topic string) (int32, error) {
+	failpoint.Inject("MockPulsar", func() {
+		failpoint.Return(int32(4), nil)
+	})
+
+	p.mu.RLock()
+	num, ok := p.partitionNum[topic]
+	p.mu.RUnlock()
+	if ok {
+		return num, nil
+	}
+
+	// create the producer first to trigger the auto creation of the topic.
+	if _, err := p.getProducer(topic); err != nil {
+		return 0, errors.Trace(err)
+	}
+	partitions, err := p.client.TopicPartitions(topic)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	num = int32(len(partitions))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.partitionNum[topic] = num
+	return num, nil
+}
+
+// getProducer returns the producer of the topic, it's created if not exists.
+func (p *Producer) getProducer(topic string) (pulsar.Producer, error) {
+	if topic == "" {
+		topic = p.DefaultTopic()
+	}
+	p.mu.RLock()
+	producer, ok := p.producers[topic]
+	p.mu.RUnlock()
+	if ok {
+		return producer, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if producer, ok := p.producers[topic]; ok {
+		return producer, nil
+	}
+	opt := *p.opt.producerOptions
+	opt.Topic = topic
+	producer, err := p.client.CreateProducer(opt)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	p.producers[topic] = producer
+	log.Info("pulsar producer created", zap.String("topic", topic))
+	return producer, nil
 }
 
 func createProperties(message *codec.MQMessage, partition int32) map[string]string {
@@ -87,9 +158,13 @@ func createProperties(message *codec.MQMessage, partition int32) map[string]stri
 
 // AsyncSendMessage send key-value msg to target partition.
 func (p *Producer) AsyncSendMessage(
-	ctx context.Context, _ string, partition int32, message *codec.MQMessage,
+	ctx context.Context, topic string, partition int32, message *codec.MQMessage,
 ) error {
-	p.producer.SendAsync(ctx, &pulsar.ProducerMessage{
+	producer, err := p.getProducer(topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	producer.SendAsync(ctx, &pulsar.ProducerMessage{
 		Payload:    message.Value,
 		Key:        string(message.Key),
 		Properties: createProperties(message, partition),
@@ -110,17 +185,21 @@ func (p *Producer) errors(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err err
 
 // SyncBroadcastMessage send key-value msg to all partition.
 func (p *Producer) SyncBroadcastMessage(
-	ctx context.Context, _ string, _ int32, message *codec.MQMessage,
+	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
 ) error {
-	for partition := 0; partition < p.partitionNum; partition++ {
-		_, err := p.producer.Send(ctx, &pulsar.ProducerMessage{
+	producer, err := p.getProducer(topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for partition := int32(0); partition < partitionsNum; partition++ {
+		_, err := producer.Send(ctx, &pulsar.ProducerMessage{
 			Payload:    message.Value,
 			Key:        string(message.Key),
-			Properties: createProperties(message, int32(partition)),
+			Properties: createProperties(message, partition),
 			EventTime:  message.PhysicalTime(),
 		})
 		if err != nil {
-			return cerror.WrapError(cerror.ErrPulsarSendMessage, p.producer.Flush())
+			return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
 		}
 	}
 	return nil
@@ -128,21 +207,30 @@ func (p *Producer) SyncBroadcastMessage(
 
 // Flush flushes all in memory msgs to server.
 func (p *Producer) Flush(_ context.Context) error {
-	return cerror.WrapError(cerror.ErrPulsarSendMessage, p.producer.Flush())
-}
-
-// GetPartitionNum got current topic's partition size.
-func (p *Producer) GetPartitionNum() int32 {
-	return int32(p.partitionNum)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, producer := range p.producers {
+		if err := producer.Flush(); err != nil {
+			return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+		}
+	}
+	return nil
 }
 
-// Close closes the producer and client.
+// Close closes all the producers and the client.
 func (p *Producer) Close() error {
-	err := p.producer.Flush()
-	if err != nil {
-		return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for topic, producer := range p.producers {
+		if err := producer.Flush(); err != nil && firstErr == nil {
+			firstErr = cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+		}
+		producer.Close()
+		delete(p.producers, topic)
 	}
-	p.producer.Close()
-	p.client.Close()
-	return nil
+	if p.client != nil {
+		p.client.Close()
+	}
+	return firstErr
 }
@@ -0,0 +1,229 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	filterV2 "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	dmparser "github.com/pingcap/tiflow/dm/pkg/parser"
+	dmutils "github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+)
+
+// tableRouter decides the names of the tables in the downstream by the routes
+// in the sink config.
+type tableRouter struct {
+	routes []*tableRoute
+}
+
+type tableRoute struct {
+	tableFilter  filterV2.Filter
+	targetSchema string
+	targetTable  string
+}
+
+func newTableRouter(routes []*config.TableRoute, caseSensitive bool) (*tableRouter, error) {
+	r := &tableRouter{routes: make([]*tableRoute, 0, len(routes))}
+	for _, route := range routes {
+		f, err := filter.NewTableMatcher(route.Matcher, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		r.routes = append(r.routes, &tableRoute{
+			tableFilter:  f,
+			targetSchema: route.TargetSchema,
+			targetTable:  route.TargetTable,
+		})
+	}
+	return r, nil
+}
+
+// route returns the names of the table in the downstream, the table is empty
+// for the DDLs of schemas, which are only routed by the routes keeping the
+// names of the tables.
+func (r *tableRouter) route(schema, table string) (string, string) {
+	for _, route := range r.routes {
+		if table == "" {
+			if route.targetTable != "" || !route.tableFilter.MatchSchema(schema) {
+				continue
+			}
+		} else if !route.tableFilter.MatchTable(schema, table) {
+			continue
+		}
+		if route.targetSchema != "" {
+			schema = route.targetSchema
+		}
+		if route.targetTable != "" && table != "" {
+			table = route.targetTable
+		}
+		return schema, table
+	}
+	return schema, table
+}
+
+// routeSink renames the tables of the events by the routes before they are
+// written to the underlying sink. The events are copied rather than modified,
+// since they may be shared with others, e.g. the redo log.
+type routeSink struct {
+	Sink
+	router *tableRouter
+
+	mu sync.Mutex
+	// tables caches the routed names of the tables of rows, it's nil for the
+	// tables not routed.
+	tables map[model.TableName]*model.TableName
+}
+
+var _ Sink = (*routeSink)(nil)
+
+func newRouteSink(s Sink, router *tableRouter) *routeSink {
+	return &routeSink{
+		Sink:   s,
+		router: router,
+		tables: make(map[model.TableName]*model.TableName),
+	}
+}
+
+func (s *routeSink) routeTable(table *model.TableName) *model.TableName {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	routed, ok := s.tables[*table]
+	if !ok {
+		schema, name := s.router.route(table.Schema, table.Table)
+		if schema != table.Schema || name != table.Table {
+			routed = &model.TableName{
+				Schema:      schema,
+				Table:       name,
+				TableID:     table.TableID,
+				IsPartition: table.IsPartition,
+			}
+		}
+		s.tables[*table] = routed
+	}
+	if routed == nil {
+		return table
+	}
+	return routed
+}
+
+func (s *routeSink) routeRows(rows []*model.RowChangedEvent) []*model.RowChangedEvent {
+	routedRows := make([]*model.RowChangedEvent, 0, len(rows))
+	for _, row := range rows {
+		table := s.routeTable(row.Table)
+		if table != row.Table {
+			routed := *row
+			routed.Table = table
+			row = &routed
+		}
+		routedRows = append(routedRows, row)
+	}
+	return routedRows
+}
+
+func (s *routeSink) routeTables(tables []model.TableName) []model.TableName {
+	routedTables := make([]model.TableName, 0, len(tables))
+	for i := range tables {
+		routedTables = append(routedTables, *s.routeTable(&tables[i]))
+	}
+	return routedTables
+}
+
+func (s *routeSink) routeTableInfo(info *model.SimpleTableInfo) *model.SimpleTableInfo {
+	if info == nil {
+		return nil
+	}
+	routed := *info
+	routed.Schema, routed.Table = s.router.route(info.Schema, info.Table)
+	return &routed
+}
+
+// routeDDL renames the tables in the DDL and its query.
+func (s *routeSink) routeDDL(ddl *model.DDLEvent) (*model.DDLEvent, error) {
+	routed := *ddl
+	routed.TableInfo = s.routeTableInfo(ddl.TableInfo)
+	routed.PreTableInfo = s.routeTableInfo(ddl.PreTableInfo)
+	if ddl.Query == "" {
+		return &routed, nil
+	}
+
+	stmt, err := parser.New().ParseOneStmt(ddl.Query, "", "")
+	if err != nil {
+		return nil, errors.Annotatef(err, "parse ddl %s", ddl.Query)
+	}
+	schema := ""
+	if ddl.TableInfo != nil {
+		schema = ddl.TableInfo.Schema
+	}
+	// the names are matched by the routes, so they are kept as they are.
+	tables, err := dmparser.FetchDDLTables(schema, stmt, dmutils.LCTableNamesSensitive)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	renamed := false
+	for _, table := range tables {
+		targetSchema, targetTable := s.router.route(table.Schema, table.Name)
+		if targetSchema != table.Schema || targetTable != table.Name {
+			table.Schema, table.Name = targetSchema, targetTable
+			renamed = true
+		}
+	}
+	if !renamed {
+		return &routed, nil
+	}
+	routed.Query, err = dmparser.RenameDDLTable(stmt, tables)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &routed, nil
+}
+
+// TryEmitRowChangedEvents implements Sink.
+func (s *routeSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	return s.Sink.TryEmitRowChangedEvents(ctx, s.routeRows(rows)...)
+}
+
+// EmitRowChangedEvents implements Sink.
+func (s *routeSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	return s.Sink.EmitRowChangedEvents(ctx, s.routeRows(rows)...)
+}
+
+// EmitDDLEvent implements Sink.
+func (s *routeSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	routed, err := s.routeDDL(ddl)
+	if err != nil {
+		return err
+	}
+	return s.Sink.EmitDDLEvent(ctx, routed)
+}
+
+// EmitCheckpointTs implements Sink.
+func (s *routeSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []model.TableName) error {
+	return s.Sink.EmitCheckpointTs(ctx, ts, s.routeTables(tables))
+}
+
+// EmitEndMarker emits the end marker to the underlying sink if it writes one,
+// it's a no-op otherwise.
+func (s *routeSink) EmitEndMarker(ctx context.Context, ts uint64, tables []model.TableName) error {
+	endMarkerSink, ok := s.Sink.(EndMarkerSink)
+	if !ok {
+		return nil
+	}
+	return endMarkerSink.EmitEndMarker(ctx, ts, s.routeTables(tables))
+}
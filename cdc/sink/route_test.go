@@ -0,0 +1,80 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	router, err := newTableRouter([]*config.TableRoute{
+		{Matcher: []string{"db1.*"}, TargetSchema: "db2"},
+		{Matcher: []string{"db3.t1"}, TargetSchema: "db4", TargetTable: "t2"},
+	}, false)
+	require.Nil(t, err)
+	record := &recordSink{}
+	s := newRouteSink(record, router)
+
+	rows := []*model.RowChangedEvent{
+		{Table: &model.TableName{Schema: "DB1", Table: "t1", TableID: 1}},
+		{Table: &model.TableName{Schema: "db3", Table: "t1", TableID: 2}},
+		{Table: &model.TableName{Schema: "db5", Table: "t1", TableID: 3}},
+	}
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+	require.Equal(t, &model.TableName{Schema: "db2", Table: "t1", TableID: 1}, record.rows[0].Table)
+	require.Equal(t, &model.TableName{Schema: "db4", Table: "t2", TableID: 2}, record.rows[1].Table)
+	require.Same(t, rows[2], record.rows[2])
+	// the rows emitted are not modified
+	require.Equal(t, "DB1", rows[0].Table.Schema)
+
+	ddls := []*model.DDLEvent{
+		{
+			TableInfo: &model.SimpleTableInfo{Schema: "db1", Table: "t1"},
+			Query:     "create table t1 (a int)",
+		},
+		{
+			TableInfo:    &model.SimpleTableInfo{Schema: "db3", Table: "t3"},
+			PreTableInfo: &model.SimpleTableInfo{Schema: "db3", Table: "t1"},
+			Query:        "rename table db3.t1 to db3.t3",
+		},
+		{
+			TableInfo: &model.SimpleTableInfo{Schema: "db1"},
+			Query:     "create database db1",
+		},
+		// the schema isn't routed by the route renaming a table
+		{
+			TableInfo: &model.SimpleTableInfo{Schema: "db3"},
+			Query:     "create database db3",
+		},
+	}
+	for _, ddl := range ddls {
+		require.Nil(t, s.EmitDDLEvent(ctx, ddl))
+	}
+	require.Equal(t, "CREATE TABLE `db2`.`t1` (`a` INT)", record.ddls[0].Query)
+	require.Equal(t, "db2", record.ddls[0].TableInfo.Schema)
+	require.Equal(t, "db1", ddls[0].TableInfo.Schema)
+	require.Equal(t, "RENAME TABLE `db4`.`t2` TO `db3`.`t3`", record.ddls[1].Query)
+	require.Equal(t, &model.SimpleTableInfo{Schema: "db3", Table: "t3"}, record.ddls[1].TableInfo)
+	require.Equal(t, &model.SimpleTableInfo{Schema: "db4", Table: "t2"}, record.ddls[1].PreTableInfo)
+	require.Equal(t, "CREATE DATABASE `db2`", record.ddls[2].Query)
+	require.Equal(t, "create database db3", record.ddls[3].Query)
+}
@@ -22,15 +22,26 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/logutil"
+	"github.com/pingcap/tiflow/pkg/secret"
 	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
 )
 
 // Sink options keys
 const (
 	OptChangefeedID = "_changefeed_id"
 	OptCaptureAddr  = "_capture_addr"
+	// OptCurrentTs is the TSO fetched from PD when the sink is created
+	OptCurrentTs = "_current_ts"
 )
 
+// logger returns the logger of the sinks, whose level can be changed at
+// runtime.
+func logger() *zap.Logger {
+	return logutil.ComponentLogger(logutil.ComponentSink)
+}
+
 // Sink is an abstraction for anything that a changefeed may emit into.
 type Sink interface {
 	// EmitRowChangedEvents sends Row Changed Event to Sink
@@ -81,6 +92,14 @@ type Sink interface {
 	Barrier(ctx context.Context, tableID model.TableID) error
 }
 
+// EndMarkerSink is implemented by the sinks writing a dedicated end-of-stream
+// message when a changefeed is removed with the final barrier.
+type EndMarkerSink interface {
+	// EmitEndMarker writes the end-of-stream message of the changefeed at ts,
+	// the tables are the ones replicated by the changefeed.
+	EmitEndMarker(ctx context.Context, ts uint64, tables []model.TableName) error
+}
+
 var sinkIniterMap = make(map[string]sinkInitFunc)
 
 type sinkInitFunc func(context.Context, model.ChangeFeedID, *url.URL, *filter.Filter, *config.ReplicaConfig, map[string]string, chan error) (Sink, error)
@@ -126,14 +145,112 @@ func init() {
 		return newPulsarSink(ctx, sinkURI, filter, config, opts, errCh)
 	}
 	sinkIniterMap["pulsar+ssl"] = sinkIniterMap["pulsar"]
+
+	// register pubsub sink
+	sinkIniterMap["gcpubsub"] = func(
+		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+		filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+		errCh chan error,
+	) (Sink, error) {
+		return newPubSubSink(ctx, sinkURI, filter, config, opts, errCh)
+	}
+
+	// register cloud storage sink
+	sinkIniterMap["s3"] = func(
+		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+		filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+		errCh chan error,
+	) (Sink, error) {
+		return newStorageSink(ctx, changefeedID, sinkURI, filter)
+	}
+	sinkIniterMap["gcs"] = sinkIniterMap["s3"]
+	sinkIniterMap["gs"] = sinkIniterMap["s3"]
+	sinkIniterMap["file"] = sinkIniterMap["s3"]
+
+	// register elasticsearch sink
+	sinkIniterMap["elasticsearch"] = func(
+		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+		filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+		errCh chan error,
+	) (Sink, error) {
+		return newElasticsearchSink(ctx, changefeedID, sinkURI, filter, config)
+	}
+	sinkIniterMap["elasticsearch+ssl"] = sinkIniterMap["elasticsearch"]
+	sinkIniterMap["opensearch"] = sinkIniterMap["elasticsearch"]
+	sinkIniterMap["opensearch+ssl"] = sinkIniterMap["elasticsearch"]
+
+	// register webhook sink
+	sinkIniterMap["webhook"] = func(
+		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+		filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+		errCh chan error,
+	) (Sink, error) {
+		return newWebhookSink(ctx, changefeedID, sinkURI, filter)
+	}
+	sinkIniterMap["webhook+ssl"] = sinkIniterMap["webhook"]
+
+	// register plugin sink
+	sinkIniterMap["plugin"] = func(
+		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
+		filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+		errCh chan error,
+	) (Sink, error) {
+		return newPluginSink(ctx, changefeedID, sinkURI, filter, errCh)
+	}
 }
 
-// New creates a new sink with the sink-uri
+// New creates a new sink with the sink-uri, if extra sink uris are configured,
+// a sink writing events to all of them is returned. The tables of the events
+// are renamed by the routes in the sink config.
 func New(
 	ctx context.Context, changefeedID model.ChangeFeedID, sinkURIStr string,
 	filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
 	errCh chan error,
 ) (Sink, error) {
+	s, err := newSink(ctx, changefeedID, sinkURIStr, filter, config, opts, errCh)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || config.Sink == nil {
+		return s, nil
+	}
+
+	if len(config.Sink.ExtraSinkURIs) > 0 {
+		sinks := []Sink{s}
+		for _, uri := range config.Sink.ExtraSinkURIs {
+			extra, err := newSink(ctx, changefeedID, uri, filter, config, opts, errCh)
+			if err != nil {
+				for _, s := range sinks {
+					_ = s.Close(ctx)
+				}
+				return nil, err
+			}
+			sinks = append(sinks, extra)
+		}
+		s = newFanOutSink(sinks...)
+	}
+	if len(config.Sink.Routes) > 0 {
+		router, err := newTableRouter(config.Sink.Routes, config.CaseSensitive)
+		if err != nil {
+			_ = s.Close(ctx)
+			return nil, err
+		}
+		s = newRouteSink(s, router)
+	}
+	return s, nil
+}
+
+func newSink(
+	ctx context.Context, changefeedID model.ChangeFeedID, sinkURIStr string,
+	filter *filter.Filter, config *config.ReplicaConfig, opts map[string]string,
+	errCh chan error,
+) (Sink, error) {
+	// the secrets are resolved only when the sink is created, so they are
+	// never persisted with the changefeed info
+	sinkURIStr, err := secret.ResolveURI(ctx, sinkURIStr)
+	if err != nil {
+		return nil, err
+	}
 	// parse sinkURI as a URI
 	sinkURI, err := url.Parse(sinkURIStr)
 	if err != nil {
@@ -146,15 +263,84 @@ func New(
 }
 
 // Validate sink if given valid parameters.
+// The extra sink uris are validated one by one like the sink uri.
 func Validate(ctx context.Context, sinkURI string, cfg *config.ReplicaConfig, opts map[string]string) error {
 	sinkFilter, err := filter.NewFilter(cfg)
 	if err != nil {
 		return err
 	}
-	errCh := make(chan error)
+	uris := []string{sinkURI}
+	if cfg != nil && cfg.Sink != nil {
+		for _, uri := range cfg.Sink.ExtraSinkURIs {
+			if uri == sinkURI {
+				return cerror.ErrSinkURIInvalid.GenWithStack(
+					"extra sink uri %s is the same as the sink uri", uri)
+			}
+			uris = append(uris, uri)
+		}
+	}
+	if err := ValidateMySQLOnlyConfig(sinkURI, cfg); err != nil {
+		return err
+	}
 	ctx = util.PutRoleInCtx(ctx, util.RoleClient)
+	for _, uri := range uris {
+		if err := validateSink(ctx, uri, sinkFilter, cfg, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mysqlSchemes are the schemes of the sinks writing to MySQL compatible
+// databases.
+var mysqlSchemes = map[string]struct{}{
+	"mysql":     {},
+	"tidb":      {},
+	"mysql+ssl": {},
+	"tidb+ssl":  {},
+}
+
+// ValidateMySQLOnlyConfig rejects the sink configs handling the errors of the
+// MySQL sink, i.e. error-policy, dead-letter-queue and ignore-errors, if the
+// sink uri or an extra sink uri isn't a MySQL sink, which would ignore them.
+func ValidateMySQLOnlyConfig(sinkURI string, cfg *config.ReplicaConfig) error {
+	if cfg == nil || cfg.Sink == nil {
+		return nil
+	}
+	var options []string
+	if cfg.Sink.ErrorPolicy != nil {
+		options = append(options, "error-policy")
+	}
+	if cfg.Sink.DeadLetterQueue != nil {
+		options = append(options, "dead-letter-queue")
+	}
+	if cfg.Sink.IgnoreErrors != nil {
+		options = append(options, "ignore-errors")
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	for _, uri := range append([]string{sinkURI}, cfg.Sink.ExtraSinkURIs...) {
+		u, err := url.Parse(secret.MaskURI(uri))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+		}
+		if _, ok := mysqlSchemes[strings.ToLower(u.Scheme)]; !ok {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"%s can only be used with MySQL sinks, but the scheme of a sink is %s",
+				strings.Join(options, ", "), u.Scheme)
+		}
+	}
+	return nil
+}
+
+func validateSink(
+	ctx context.Context, sinkURI string, sinkFilter *filter.Filter,
+	cfg *config.ReplicaConfig, opts map[string]string,
+) error {
+	errCh := make(chan error)
 	// TODO: find a better way to verify a sinkURI is valid
-	s, err := New(ctx, "sink-verify", sinkURI, sinkFilter, cfg, opts, errCh)
+	s, err := newSink(ctx, "sink-verify", sinkURI, sinkFilter, cfg, opts, errCh)
 	if err != nil {
 		return err
 	}
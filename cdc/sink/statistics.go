@@ -18,7 +18,6 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -34,6 +33,10 @@ type sinkType int
 const (
 	sinkTypeDB sinkType = iota
 	sinkTypeMQ
+	sinkTypeStorage
+	sinkTypeElasticsearch
+	sinkTypeWebhook
+	sinkTypePlugin
 )
 
 func (t sinkType) String() string {
@@ -42,6 +45,14 @@ func (t sinkType) String() string {
 		return "DB"
 	case sinkTypeMQ:
 		return "MQ"
+	case sinkTypeStorage:
+		return "Storage"
+	case sinkTypeElasticsearch:
+		return "Elasticsearch"
+	case sinkTypeWebhook:
+		return "Webhook"
+	case sinkTypePlugin:
+		return "Plugin"
 	}
 	return "unknown"
 }
@@ -166,7 +177,7 @@ func (b *Statistics) PrintStatus(ctx context.Context) {
 	totalDDLCount := atomic.LoadUint64(&b.totalDDLCount)
 	atomic.StoreUint64(&b.totalDDLCount, 0)
 
-	log.Info("sink replication status",
+	logger().Info("sink replication status",
 		zap.Stringer("sinkType", b.sinkType),
 		zap.String("changefeed", b.changefeedID),
 		util.ZapFieldCapture(ctx),
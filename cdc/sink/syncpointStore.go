@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/secret"
 )
 
 // SyncpointStore is an abstraction for anything that a changefeed may emit into.
@@ -30,12 +31,47 @@ type SyncpointStore interface {
 	// SinkSyncpoint record the syncpoint(a map with ts) in downstream db
 	SinkSyncpoint(ctx context.Context, id string, checkpointTs uint64) error
 
+	// SinkEndMarker record the end of the changefeed at checkpointTs in downstream db
+	SinkEndMarker(ctx context.Context, id string, checkpointTs uint64) error
+
+	// Checksum returns the secondary ts of the syncpoint of checkpointTs and the
+	// checksums of the tables in downstream db at the secondary ts
+	Checksum(ctx context.Context, id string, checkpointTs uint64, tables []model.TableName) (uint64, []*model.TableChecksum, error)
+
+	// Columns returns the columns of all tables in the schemas in downstream db
+	Columns(ctx context.Context, schemas []string) (map[model.TableName][]*DownstreamColumn, error)
+
 	// Close closes the SyncpointSink
 	Close() error
 }
 
+// DownstreamColumn is a column of a table in downstream db.
+type DownstreamColumn struct {
+	Name string
+	// DataType is the data type without length and attributes, e.g. varchar
+	DataType string
+}
+
+// IsSyncpointSupported returns whether the sink of sinkURIStr supports recording syncpoints
+func IsSyncpointSupported(sinkURIStr string) bool {
+	sinkURI, err := url.Parse(secret.MaskURI(sinkURIStr))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(sinkURI.Scheme) {
+	case "mysql", "tidb", "mysql+ssl", "tidb+ssl":
+		return true
+	default:
+		return false
+	}
+}
+
 // NewSyncpointStore creates a new Spyncpoint sink with the sink-uri
 func NewSyncpointStore(ctx context.Context, changefeedID model.ChangeFeedID, sinkURIStr string) (SyncpointStore, error) {
+	sinkURIStr, err := secret.ResolveURI(ctx, sinkURIStr)
+	if err != nil {
+		return nil, err
+	}
 	// parse sinkURI as a URI
 	sinkURI, err := url.Parse(sinkURIStr)
 	if err != nil {
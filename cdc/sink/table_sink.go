@@ -18,7 +18,6 @@ import (
 	"sort"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"go.uber.org/zap"
@@ -61,7 +60,7 @@ func (t *tableSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 // redo log watermarkTs.
 func (t *tableSink) FlushRowChangedEvents(ctx context.Context, tableID model.TableID, resolvedTs uint64) (uint64, error) {
 	if tableID != t.tableID {
-		log.Panic("inconsistent table sink",
+		logger().Panic("inconsistent table sink",
 			zap.Int64("tableID", tableID), zap.Int64("sinkTableID", t.tableID))
 	}
 	i := sort.Search(len(t.buffer), func(i int) bool {
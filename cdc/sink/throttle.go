@@ -0,0 +1,167 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// ThrottleSink limits the throughput of rows emitted to the underlying sink,
+// so a backfilling changefeed doesn't saturate the downstream. The limits can
+// be adjusted by SetThrottle when the sink is running.
+type ThrottleSink struct {
+	Sink
+
+	id           model.ChangeFeedID
+	rowsLimiter  *rate.Limiter
+	bytesLimiter *rate.Limiter
+
+	mu  sync.Mutex
+	cfg config.ThrottleConfig
+
+	metricRowsThrottleDuration  prometheus.Counter
+	metricBytesThrottleDuration prometheus.Counter
+}
+
+var _ Sink = (*ThrottleSink)(nil)
+
+// NewThrottleSink creates a ThrottleSink, the rows are not throttled if cfg is nil.
+func NewThrottleSink(id model.ChangeFeedID, s Sink, cfg *config.ThrottleConfig) *ThrottleSink {
+	t := &ThrottleSink{
+		Sink:         s,
+		id:           id,
+		rowsLimiter:  rate.NewLimiter(rate.Inf, 0),
+		bytesLimiter: rate.NewLimiter(rate.Inf, 0),
+
+		metricRowsThrottleDuration:  throttleDurationCounter.WithLabelValues(id, "rows"),
+		metricBytesThrottleDuration: throttleDurationCounter.WithLabelValues(id, "bytes"),
+	}
+	t.SetThrottle(cfg)
+	return t
+}
+
+// SetThrottle updates the limits of the sink, nil removes all the limits.
+func (s *ThrottleSink) SetThrottle(cfg *config.ThrottleConfig) {
+	var newCfg config.ThrottleConfig
+	if cfg != nil {
+		newCfg = *cfg
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if newCfg == s.cfg {
+		return
+	}
+	logger().Info("update the throttle of sink", zap.String("changefeed", s.id),
+		zap.Any("old", s.cfg), zap.Any("new", newCfg))
+	s.cfg = newCfg
+	setLimit(s.rowsLimiter, newCfg.RowsPerSecond)
+	setLimit(s.bytesLimiter, newCfg.BytesPerSecond)
+}
+
+// setLimit sets the limit per second of the limiter, 0 means unlimited. The
+// burst is the limit so at most one second of events can be emitted at once.
+func setLimit(limiter *rate.Limiter, limit int64) {
+	if limit <= 0 {
+		limiter.SetLimit(rate.Inf)
+		return
+	}
+	limiter.SetLimit(rate.Limit(limit))
+	limiter.SetBurst(int(limit))
+}
+
+// TryEmitRowChangedEvents just calls EmitRowChangedEvents internally, since
+// the rows have to wait for the limiters anyway.
+func (s *ThrottleSink) TryEmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) (bool, error) {
+	err := s.EmitRowChangedEvents(ctx, rows...)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EmitRowChangedEvents waits until the rows are allowed by the limiters and
+// emits them to the underlying sink.
+func (s *ThrottleSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	if len(rows) == 0 {
+		return s.Sink.EmitRowChangedEvents(ctx, rows...)
+	}
+	waited, err := waitN(ctx, s.rowsLimiter, len(rows))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.metricRowsThrottleDuration.Add(waited.Seconds())
+
+	var size int64
+	for _, row := range rows {
+		size += row.ApproximateDataSize
+	}
+	waited, err = waitN(ctx, s.bytesLimiter, int(size))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.metricBytesThrottleDuration.Add(waited.Seconds())
+	return s.Sink.EmitRowChangedEvents(ctx, rows...)
+}
+
+// waitN waits until n events are allowed by the limiter and returns the time
+// it waited, n larger than the burst is split into several waits. The burst
+// may be lowered by SetThrottle after it's read, so a chunk exceeding the new
+// burst is split again rather than failing the wait.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) (time.Duration, error) {
+	if limiter.Limit() == rate.Inf || n <= 0 {
+		return 0, nil
+	}
+	start := time.Now()
+	for n > 0 {
+		if err := ctx.Err(); err != nil {
+			return time.Since(start), errors.Trace(err)
+		}
+		chunk := n
+		if burst := limiter.Burst(); chunk > burst {
+			chunk = burst
+		}
+		r := limiter.ReserveN(time.Now(), chunk)
+		if !r.OK() {
+			continue
+		}
+		if delay := r.Delay(); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				r.Cancel()
+				return time.Since(start), errors.Trace(ctx.Err())
+			case <-timer.C:
+			}
+		}
+		n -= chunk
+	}
+	return time.Since(start), nil
+}
+
+// Close closes the underlying sink and removes the metrics of the sink.
+func (s *ThrottleSink) Close(ctx context.Context) error {
+	throttleDurationCounter.DeleteLabelValues(s.id, "rows")
+	throttleDurationCounter.DeleteLabelValues(s.id, "bytes")
+	return s.Sink.Close(ctx)
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestThrottleSinkSetThrottle(t *testing.T) {
+	t.Parallel()
+
+	s := NewThrottleSink("changefeed-test", &recordSink{}, nil)
+	require.Equal(t, rate.Inf, s.rowsLimiter.Limit())
+	require.Equal(t, rate.Inf, s.bytesLimiter.Limit())
+
+	s.SetThrottle(&config.ThrottleConfig{RowsPerSecond: 100, BytesPerSecond: 1024})
+	require.Equal(t, rate.Limit(100), s.rowsLimiter.Limit())
+	require.Equal(t, 100, s.rowsLimiter.Burst())
+	require.Equal(t, rate.Limit(1024), s.bytesLimiter.Limit())
+	require.Equal(t, 1024, s.bytesLimiter.Burst())
+
+	s.SetThrottle(nil)
+	require.Equal(t, rate.Inf, s.rowsLimiter.Limit())
+	require.Equal(t, rate.Inf, s.bytesLimiter.Limit())
+	require.Nil(t, s.Close(context.Background()))
+}
+
+func TestThrottleSinkEmitRowChangedEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	backend := &recordSink{}
+	s := NewThrottleSink("changefeed-test", backend,
+		&config.ThrottleConfig{RowsPerSecond: 10})
+
+	rows := make([]*model.RowChangedEvent, 0, 15)
+	for i := 0; i < 15; i++ {
+		rows = append(rows, &model.RowChangedEvent{CommitTs: uint64(i)})
+	}
+	// the burst is 10 rows, the rest 5 rows are delayed about 0.5s
+	start := time.Now()
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	require.Len(t, backend.rows, 15)
+
+	// the rows are not delayed when the throttle is removed
+	s.SetThrottle(nil)
+	start = time.Now()
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows...))
+	require.Less(t, time.Since(start), 400*time.Millisecond)
+	require.Len(t, backend.rows, 30)
+
+	// the waiting is canceled with the context
+	s.SetThrottle(&config.ThrottleConfig{RowsPerSecond: 1})
+	require.Nil(t, s.EmitRowChangedEvents(ctx, rows[0]))
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NotNil(t, s.EmitRowChangedEvents(cctx, rows[0]))
+	require.Len(t, backend.rows, 31)
+	require.Nil(t, s.Close(ctx))
+}
+
+func TestWaitNBurstLowered(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	setLimit(limiter, 1000000)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			setLimit(limiter, 1000000)
+			setLimit(limiter, 500000)
+		}
+	}()
+	// the wait never fails even if the burst is lowered while it's split.
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		_, err := waitN(ctx, limiter, 800000)
+		require.Nil(t, err)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	filterV2 "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+)
+
+// Transformer transforms the column values of rows by the transforms in the
+// sink config before the rows are written to the sink, so sensitive data can
+// be masked. All the transforms matching a table are applied in order.
+type Transformer struct {
+	transforms []*columnTransform
+}
+
+type columnTransform struct {
+	tableFilter filterV2.Filter
+	columns     *filter.ColumnMatcher
+	tp          string
+	length      int
+	value       string
+	hashKey     []byte
+}
+
+// NewTransformer creates a Transformer, or returns nil if the sink config has
+// no transform.
+func NewTransformer(cfg *config.ReplicaConfig) (*Transformer, error) {
+	if cfg.Sink == nil || len(cfg.Sink.Transforms) == 0 {
+		return nil, nil
+	}
+	transforms := make([]*columnTransform, 0, len(cfg.Sink.Transforms))
+	for _, rule := range cfg.Sink.Transforms {
+		f, err := filter.NewTableMatcher(rule.Matcher, cfg.CaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		columns, err := filter.NewColumnMatcher(rule.Columns, cfg.CaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		transforms = append(transforms, &columnTransform{
+			tableFilter: f,
+			columns:     columns,
+			tp:          rule.Type,
+			length:      rule.Length,
+			value:       rule.Value,
+			hashKey:     []byte(rule.HashKey),
+		})
+	}
+	return &Transformer{transforms: transforms}, nil
+}
+
+// VerifyTable checks the transforms matching the table don't break its
+// handle keys. Only hash keeps distinct values distinct, so the other
+// transforms are rejected on the primary and unique key columns, otherwise
+// different rows would collide downstream.
+func (t *Transformer) VerifyTable(ti *model.TableInfo) error {
+	if t == nil {
+		return nil
+	}
+	for _, transform := range t.transforms {
+		if transform.tp == config.TransformHash ||
+			!transform.tableFilter.MatchTable(ti.TableName.Schema, ti.TableName.Table) {
+			continue
+		}
+		for _, key := range ti.GetUniqueKeys() {
+			for _, col := range key {
+				if transform.columns.Match(col) {
+					return cerror.ErrSinkInvalidConfig.GenWithStack(
+						"%s transform can't be applied to the key column %s of table %s",
+						transform.tp, col, ti.TableName.String())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Apply transforms the values of the matched columns of the row in place.
+func (t *Transformer) Apply(row *model.RowChangedEvent) error {
+	if t == nil {
+		return nil
+	}
+	for _, transform := range t.transforms {
+		if !transform.tableFilter.MatchTable(row.Table.Schema, row.Table.Table) {
+			continue
+		}
+		for _, cols := range [][]*model.Column{row.Columns, row.PreColumns} {
+			for _, col := range cols {
+				if col == nil || col.Value == nil || !transform.columns.Match(col.Name) {
+					continue
+				}
+				value, err := transform.apply(col.Value)
+				if err != nil {
+					return cerror.ErrColumnTransformFailed.Wrap(err).GenWithStackByArgs(
+						col.Name, row.Table.String())
+				}
+				col.Value = value
+			}
+		}
+	}
+	return nil
+}
+
+// apply returns the transformed value, the type of the value is kept so the
+// sinks and encoders can handle it in the same way as the original one.
+func (t *columnTransform) apply(value interface{}) (interface{}, error) {
+	if t.tp == config.TransformConstant {
+		return convertConstant(t.value, value)
+	}
+
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return nil, cerror.ErrSinkInvalidConfig.GenWithStack(
+			"%s transform only supports string columns", t.tp)
+	}
+	switch t.tp {
+	case config.TransformHash:
+		mac := hmac.New(sha256.New, t.hashKey)
+		mac.Write([]byte(str))
+		str = hex.EncodeToString(mac.Sum(nil))
+	case config.TransformRedact:
+		str = strings.Repeat("*", len([]rune(str)))
+	case config.TransformTruncate:
+		if runes := []rune(str); len(runes) > t.length {
+			str = string(runes[:t.length])
+		}
+	}
+	if _, ok := value.([]byte); ok {
+		return []byte(str), nil
+	}
+	return str, nil
+}
+
+// convertConstant converts the constant to the type of the original value.
+func convertConstant(constant string, value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case string:
+		return constant, nil
+	case []byte:
+		return []byte(constant), nil
+	case int64:
+		return strconv.ParseInt(constant, 10, 64)
+	case uint64:
+		return strconv.ParseUint(constant, 10, 64)
+	case float64:
+		return strconv.ParseFloat(constant, 64)
+	case float32:
+		v, err := strconv.ParseFloat(constant, 32)
+		return float32(v), err
+	default:
+		return nil, cerror.ErrSinkInvalidConfig.GenWithStack(
+			"constant transform doesn't support values of type %T", value)
+	}
+}
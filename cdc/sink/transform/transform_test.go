@@ -0,0 +1,158 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	parser_types "github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformer(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	transformer, err := NewTransformer(cfg)
+	require.Nil(t, err)
+	require.Nil(t, transformer)
+	// a nil transformer keeps the row unchanged.
+	require.Nil(t, transformer.Apply(&model.RowChangedEvent{}))
+
+	cfg.CaseSensitive = false
+	cfg.Sink.Transforms = []*config.ColumnTransform{
+		{Matcher: []string{"test.users"}, Columns: []string{"Phone"}, Type: config.TransformHash, HashKey: "secret"},
+		{Matcher: []string{"test.users"}, Columns: []string{"card_*"}, Type: config.TransformRedact},
+		{Matcher: []string{"test.*"}, Columns: []string{"address"}, Type: config.TransformTruncate, Length: 2},
+		{Matcher: []string{"test.*"}, Columns: []string{"salary"}, Type: config.TransformConstant, Value: "0"},
+	}
+	transformer, err = NewTransformer(cfg)
+	require.Nil(t, err)
+
+	newRow := func(table string) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: table},
+			Columns: []*model.Column{
+				{Name: "id", Value: int64(1)},
+				{Name: "phone", Value: []byte("123")},
+				{Name: "card_no", Value: "中文4"},
+				{Name: "address", Value: []byte("中文地址")},
+				{Name: "salary", Value: int64(100)},
+			},
+			PreColumns: []*model.Column{
+				{Name: "id", Value: int64(1)},
+				{Name: "phone", Value: nil},
+				{Name: "card_no", Value: "5"},
+				{Name: "address", Value: []byte("a")},
+				{Name: "salary", Value: float64(1.5)},
+			},
+		}
+	}
+
+	row := newRow("users")
+	require.Nil(t, transformer.Apply(row))
+	require.Equal(t, []interface{}{
+		int64(1),
+		[]byte("77de38e4b50e618a0ebb95db61e2f42697391659d82c064a5f81b9f48d85ccd5"),
+		"***",
+		[]byte("中文"),
+		int64(0),
+	}, values(row.Columns))
+	require.Equal(t, []interface{}{int64(1), nil, "*", []byte("a"), float64(0)}, values(row.PreColumns))
+
+	// only the transforms matching the table are applied.
+	row = newRow("orders")
+	require.Nil(t, transformer.Apply(row))
+	require.Equal(t, []interface{}{
+		int64(1), []byte("123"), "中文4", []byte("中文"), int64(0),
+	}, values(row.Columns))
+
+	// string transforms fail on non-string columns.
+	cfg.Sink.Transforms = []*config.ColumnTransform{
+		{Matcher: []string{"test.*"}, Columns: []string{"id"}, Type: config.TransformHash},
+	}
+	transformer, err = NewTransformer(cfg)
+	require.Nil(t, err)
+	err = transformer.Apply(newRow("users"))
+	require.True(t, cerror.ErrColumnTransformFailed.Equal(err))
+
+	// the constant must be compatible with the column.
+	cfg.Sink.Transforms = []*config.ColumnTransform{
+		{Matcher: []string{"test.*"}, Columns: []string{"salary"}, Type: config.TransformConstant, Value: "x"},
+	}
+	transformer, err = NewTransformer(cfg)
+	require.Nil(t, err)
+	err = transformer.Apply(newRow("users"))
+	require.True(t, cerror.ErrColumnTransformFailed.Equal(err))
+
+	// invalid column patterns are rejected.
+	cfg.Sink.Transforms = []*config.ColumnTransform{
+		{Matcher: []string{"test.*"}, Columns: []string{"["}, Type: config.TransformHash},
+	}
+	_, err = NewTransformer(cfg)
+	require.Regexp(t, "invalid column pattern", err)
+}
+
+func TestVerifyTable(t *testing.T) {
+	t.Parallel()
+
+	ti := model.WrapTableInfo(1, "test", 0, &timodel.TableInfo{
+		Name: timodel.NewCIStr("users"),
+		Columns: []*timodel.ColumnInfo{
+			{
+				Name:      timodel.NewCIStr("id"),
+				FieldType: parser_types.FieldType{Flag: mysql.PriKeyFlag},
+				State:     timodel.StatePublic,
+			},
+			{
+				Name:  timodel.NewCIStr("phone"),
+				State: timodel.StatePublic,
+			},
+		},
+		PKIsHandle: true,
+	})
+
+	var transformer *Transformer
+	require.Nil(t, transformer.VerifyTable(ti))
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Transforms = []*config.ColumnTransform{
+		{Matcher: []string{"test.*"}, Columns: []string{"*"}, Type: config.TransformHash, HashKey: "secret"},
+		{Matcher: []string{"test.*"}, Columns: []string{"phone"}, Type: config.TransformRedact},
+		{Matcher: []string{"test.orders"}, Columns: []string{"id"}, Type: config.TransformConstant, Value: "0"},
+	}
+	transformer, err := NewTransformer(cfg)
+	require.Nil(t, err)
+	require.Nil(t, transformer.VerifyTable(ti))
+
+	// the key column would be collapsed by redact.
+	cfg.Sink.Transforms[1].Columns = []string{"*"}
+	transformer, err = NewTransformer(cfg)
+	require.Nil(t, err)
+	err = transformer.VerifyTable(ti)
+	require.Regexp(t, "redact transform can't be applied to the key column id", err)
+}
+
+func values(cols []*model.Column) []interface{} {
+	vals := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		vals = append(vals, col.Value)
+	}
+	return vals
+}
@@ -170,8 +170,16 @@ func (r *reader) outputIterEvents(
 		if iter.Error() != nil {
 			return false, 0, errors.Trace(iter.Error())
 		}
+		value := iter.Value()
+		if r.cipher != nil {
+			var err error
+			value, err = r.cipher.Open(nil, value)
+			if err != nil {
+				return false, 0, errors.Trace(err)
+			}
+		}
 		event := new(model.PolymorphicEvent)
-		_, err := r.serde.Unmarshal(event, iter.Value())
+		_, err := r.serde.Unmarshal(event, value)
 		if err != nil {
 			return false, 0, errors.Trace(err)
 		}
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -56,6 +57,10 @@ type common struct {
 	serde    *encoding.MsgPackGenSerde
 	errCh    chan error
 	closedWg *sync.WaitGroup
+
+	// cipher encrypts the values of events written to the db, it's nil if
+	// data isn't encrypted. Keys are not encrypted as they must be ordered.
+	cipher *encryption.Cipher
 }
 
 // reportError notifies Sorter to return an error and close.
@@ -109,6 +114,7 @@ func NewSorter(
 		uid:       uid,
 		tableID:   uint64(tableID),
 		serde:     &encoding.MsgPackGenSerde{},
+		cipher:    encryption.GlobalCipher(),
 		errCh:     make(chan error, 1),
 		closedWg:  &sync.WaitGroup{},
 	}
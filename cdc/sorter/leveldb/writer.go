@@ -76,6 +76,12 @@ func (w *writer) Poll(ctx context.Context, msgs []actormsg.Message[message.Task]
 		if err != nil {
 			log.Panic("failed to marshal events", zap.Error(err))
 		}
+		if w.cipher != nil {
+			value, err = w.cipher.Seal(nil, value)
+			if err != nil {
+				log.Panic("failed to encrypt events", zap.Error(err))
+			}
+		}
 		writes[message.Key(key)] = value
 	}
 	w.metricTotalEventsKV.Add(float64(kvEventCount))
@@ -14,6 +14,7 @@
 package leveldb
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb/message"
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/stretchr/testify/require"
 )
 
@@ -201,3 +203,45 @@ func TestWriterPoll(t *testing.T) {
 	msg := actormsg.StopMessage[message.Task]()
 	require.False(t, writer.Poll(ctx, []actormsg.Message[message.Task]{msg}))
 }
+
+func TestWriterPollEncrypted(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	capacity := 4
+	router := actor.NewRouter[message.Task](t.Name())
+	readerID := actor.ID(1)
+	readerMB := actor.NewMailbox[message.Task](readerID, capacity)
+	router.InsertMailbox4Test(readerID, readerMB)
+	dbID := actor.ID(2)
+	dbMB := actor.NewMailbox[message.Task](dbID, capacity)
+	router.InsertMailbox4Test(dbID, dbMB)
+	cipher, err := encryption.NewCipher(bytes.Repeat([]byte{1}, encryption.KeySize))
+	require.Nil(t, err)
+	c := common{
+		dbActorID: dbID, dbRouter: router,
+		serde: &encoding.MsgPackGenSerde{}, cipher: cipher,
+	}
+	writer := newTestWriter(c, router, readerID)
+
+	event := newTestEvent(3, 1, 0)
+	event.RawKV.Value = []byte("row data")
+	require.True(t, writer.Poll(ctx, []actormsg.Message[message.Task]{
+		actormsg.ValueMessage(message.Task{InputEvent: event}),
+	}))
+	msg, ok := dbMB.Receive()
+	require.True(t, ok)
+	value, ok := msg.Value.WriteReq[message.Key(encoding.EncodeKey(c.uid, c.tableID, event))]
+	require.True(t, ok)
+	require.False(t, bytes.Contains(value, []byte("row data")))
+
+	// The value can be decrypted and decoded.
+	value, err = cipher.Open(nil, value)
+	require.Nil(t, err)
+	decoded := new(model.PolymorphicEvent)
+	_, err = c.serde.Unmarshal(decoded, value)
+	require.Nil(t, err)
+	require.Equal(t, []byte("row data"), decoded.RawKV.Value)
+}
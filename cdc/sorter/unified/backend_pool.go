@@ -19,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,9 +29,11 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter"
 	sorterencoding "github.com/pingcap/tiflow/cdc/sorter/encoding"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/fsutil"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -60,6 +63,15 @@ type backEndPool struct {
 	// to prevent `dir` from being accidentally used by another TiCDC server process.
 	fileLock *fsutil.FileLock
 
+	// changefeedMemoryUse is the memory used by the in-memory backEnds of each
+	// changefeed, model.ChangeFeedID -> *int64.
+	changefeedMemoryUse sync.Map
+
+	// sortDirsMu protects sortDirs.
+	sortDirsMu sync.Mutex
+	// sortDirs are the sort-dirs set by changefeeds, keyed by their paths.
+	sortDirs map[string]*changefeedSortDir
+
 	// cancelCh needs to be unbuffered to prevent races
 	cancelCh chan struct{}
 	// cancelRWLock protects cache against races when the backEnd is exiting
@@ -67,24 +79,35 @@ type backEndPool struct {
 	isTerminating bool
 }
 
+// changefeedSortDir is a sort-dir set by changefeeds. Its files are not cached
+// for reuse, because the cache is shared by all changefeeds.
+type changefeedSortDir struct {
+	dir        string
+	filePrefix string
+	// refs is the number of the sorters using the sort-dir.
+	refs int
+}
+
 func newBackEndPool(dir string) (*backEndPool, error) {
 	ret := &backEndPool{
 		memoryUseEstimate: 0,
 		fileNameCounter:   0,
 		dir:               dir,
+		sortDirs:          make(map[string]*changefeedSortDir),
 		cancelCh:          make(chan struct{}),
-		filePrefix:        fmt.Sprintf("%s/%s-%d-", dir, sortDirDataFileMagicPrefix, os.Getpid()),
+		filePrefix:        sortDirFilePrefix(dir),
 	}
 
-	err := ret.lockSortDir()
+	fileLock, err := lockSortDir(dir)
 	if err != nil {
 		log.Warn("failed to lock file prefix",
 			zap.String("prefix", ret.filePrefix),
 			zap.Error(err))
 		return nil, errors.Trace(err)
 	}
+	ret.fileLock = fileLock
 
-	err = ret.cleanUpStaleFiles()
+	err = cleanUpStaleFiles(dir)
 	if err != nil {
 		log.Warn("Unified Sorter: failed to clean up stale temporary files. Report a bug if you believe this is unexpected", zap.Error(err))
 		return nil, errors.Trace(err)
@@ -163,11 +186,18 @@ func newBackEndPool(dir string) (*backEndPool, error) {
 }
 
 func (p *backEndPool) alloc(ctx context.Context) (backEnd, error) {
+	// s is nil if the backEnd is not allocated by a Sorter, e.g. in tests.
+	s, _ := ctx.Value(ctxKey{}).(*Sorter)
+
 	sorterConfig := config.GetGlobalServerConfig().Sorter
 	if p.sorterMemoryUsage() < int64(sorterConfig.MaxMemoryConsumption) &&
-		p.memoryPressure() < int32(sorterConfig.MaxMemoryPercentage) {
+		p.memoryPressure() < int32(sorterConfig.MaxMemoryPercentage) &&
+		(s == nil || !s.changefeedMemoryExceeded()) {
 
 		ret := newMemoryBackEnd()
+		if s != nil {
+			ret.changefeedMemoryUse = s.changefeedMemoryUse
+		}
 		return ret, nil
 	}
 
@@ -178,16 +208,37 @@ func (p *backEndPool) alloc(ctx context.Context) (backEnd, error) {
 		return nil, cerrors.ErrUnifiedSorterBackendTerminating.GenWithStackByArgs()
 	}
 
-	for i := range p.cache {
-		ptr := &p.cache[i]
-		ret := atomic.SwapPointer(ptr, nil)
-		if ret != nil {
-			return (*fileBackEnd)(ret), nil
+	tableID, tableName := util.TableIDFromCtx(ctx)
+	filePrefix := p.filePrefix
+	var tableDiskUse *int64
+	var changefeedID model.ChangeFeedID
+	if s != nil {
+		if err := s.checkDiskQuota(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		tableDiskUse = &s.onDiskDataSize
+		changefeedID = s.metricsInfo.changeFeedID
+		if s.sortDir != nil {
+			filePrefix = s.sortDir.filePrefix
 		}
 	}
 
-	fname := fmt.Sprintf("%s%d.tmp", p.filePrefix, atomic.AddUint64(&p.fileNameCounter, 1))
-	tableID, tableName := util.TableIDFromCtx(ctx)
+	if filePrefix == p.filePrefix {
+		for i := range p.cache {
+			ptr := &p.cache[i]
+			ret := atomic.SwapPointer(ptr, nil)
+			if ret != nil {
+				backEnd := (*fileBackEnd)(ret)
+				backEnd.tableDiskUse = tableDiskUse
+				backEnd.compression = sorterConfig.Compression
+				backEnd.cipher = encryption.GlobalCipher()
+				backEnd.changefeedID = changefeedID
+				return backEnd, nil
+			}
+		}
+	}
+
+	fname := fmt.Sprintf("%s%d.tmp", filePrefix, atomic.AddUint64(&p.fileNameCounter, 1))
 	log.Debug("Unified Sorter: trying to create file backEnd",
 		zap.String("filename", fname),
 		zap.Int64("tableID", tableID),
@@ -201,6 +252,10 @@ func (p *backEndPool) alloc(ctx context.Context) (backEnd, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	ret.tableDiskUse = tableDiskUse
+	ret.compression = sorterConfig.Compression
+	ret.cipher = encryption.GlobalCipher()
+	ret.changefeedID = changefeedID
 
 	return ret, nil
 }
@@ -231,10 +286,12 @@ func (p *backEndPool) dealloc(backEnd backEnd) error {
 			return cerrors.ErrUnifiedSorterBackendTerminating.GenWithStackByArgs()
 		}
 
-		for i := range p.cache {
-			ptr := &p.cache[i]
-			if atomic.CompareAndSwapPointer(ptr, nil, unsafe.Pointer(b)) {
-				return nil
+		if strings.HasPrefix(b.fileName, p.filePrefix) {
+			for i := range p.cache {
+				ptr := &p.cache[i]
+				if atomic.CompareAndSwapPointer(ptr, nil, unsafe.Pointer(b)) {
+					return nil
+				}
 			}
 		}
 		// Cache is full.
@@ -283,12 +340,23 @@ func (p *backEndPool) terminate() {
 		_ = backend.free()
 	}
 
-	if p.filePrefix == "" {
+	removeFilesWithPrefix(p.filePrefix)
+	p.sortDirsMu.Lock()
+	defer p.sortDirsMu.Unlock()
+	for _, sortDir := range p.sortDirs {
+		removeFilesWithPrefix(sortDir.filePrefix)
+	}
+
+	log.Debug("Unified Sorter backEnd terminated")
+}
+
+func removeFilesWithPrefix(filePrefix string) {
+	if filePrefix == "" {
 		// This should not happen. But to prevent accidents in production, we add this anyway.
 		log.Panic("Empty filePrefix, please report a bug")
 	}
 
-	files, err := filepath.Glob(p.filePrefix + "*")
+	files, err := filepath.Glob(filePrefix + "*")
 	if err != nil {
 		log.Warn("Unified Sorter clean-up failed", zap.Error(err))
 	}
@@ -300,8 +368,66 @@ func (p *backEndPool) terminate() {
 				zap.String("fileName", file), zap.Error(err))
 		}
 	}
+}
 
-	log.Debug("Unified Sorter backEnd terminated")
+// acquireSortDir returns the sort-dir set by a changefeed, which is a sub
+// directory of the sort-dir of the server, so it's protected by the lock of
+// the server's. The sort-dir is cleaned up when it's used for the first time,
+// and its files are removed once no sorter uses it.
+func (p *backEndPool) acquireSortDir(sortDir string) (*changefeedSortDir, error) {
+	dir := filepath.Join(p.dir, sortDir)
+	rel, err := filepath.Rel(p.dir, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, cerrors.ErrIllegalSorterParameter.GenWithStackByArgs(
+			"sorter.sort-dir should be a sub directory relative to the sort-dir of the server")
+	}
+
+	p.sortDirsMu.Lock()
+	defer p.sortDirsMu.Unlock()
+
+	if ret, ok := p.sortDirs[dir]; ok {
+		ret.refs++
+		return ret, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, cerrors.ErrUnifiedSorterIOError.Wrap(err).GenWithStackByArgs(err.Error())
+	}
+	if err := cleanUpStaleFiles(dir); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ret := &changefeedSortDir{
+		dir:        dir,
+		filePrefix: sortDirFilePrefix(dir),
+		refs:       1,
+	}
+	p.sortDirs[dir] = ret
+	log.Info("Unified Sorter: use the sort-dir set by changefeed", zap.String("sortDir", dir))
+	return ret, nil
+}
+
+// releaseSortDir releases the sort-dir used by a sorter, the files in it are
+// removed once no sorter uses it, e.g. the changefeed is removed.
+func (p *backEndPool) releaseSortDir(sortDir *changefeedSortDir) {
+	p.sortDirsMu.Lock()
+	defer p.sortDirsMu.Unlock()
+
+	sortDir.refs--
+	if sortDir.refs > 0 {
+		return
+	}
+	if p.sortDirs[sortDir.dir] == sortDir {
+		delete(p.sortDirs, sortDir.dir)
+	}
+	removeFilesWithPrefix(sortDir.filePrefix)
+	log.Info("Unified Sorter: the sort-dir set by changefeed is released", zap.String("sortDir", sortDir.dir))
+}
+
+// changefeedMemoryUsage returns the memory usage counter of a changefeed,
+// which should be accessed atomically.
+func (p *backEndPool) changefeedMemoryUsage(changefeedID model.ChangeFeedID) *int64 {
+	usage, _ := p.changefeedMemoryUse.LoadOrStore(changefeedID, new(int64))
+	return usage.(*int64)
 }
 
 func (p *backEndPool) sorterMemoryUsage() int64 {
@@ -318,11 +444,15 @@ func (p *backEndPool) memoryPressure() int32 {
 	return atomic.LoadInt32(&p.memPressure)
 }
 
-func (p *backEndPool) lockSortDir() error {
-	lockFileName := fmt.Sprintf("%s/%s", p.dir, sortDirLockFileName)
+func sortDirFilePrefix(dir string) string {
+	return fmt.Sprintf("%s/%s-%d-", dir, sortDirDataFileMagicPrefix, os.Getpid())
+}
+
+func lockSortDir(dir string) (*fsutil.FileLock, error) {
+	lockFileName := fmt.Sprintf("%s/%s", dir, sortDirLockFileName)
 	fileLock, err := fsutil.NewFileLock(lockFileName)
 	if err != nil {
-		return cerrors.ErrSortDirLockError.Wrap(err).GenWithStackByCause()
+		return nil, cerrors.ErrSortDirLockError.Wrap(err).GenWithStackByCause()
 	}
 
 	err = fileLock.Lock()
@@ -333,13 +463,12 @@ func (p *backEndPool) lockSortDir() error {
 				"If you believe you should not see this error, try deleting the lock file and resume the changefeed. "+
 				"Report a bug or contact support if the problem persists.",
 				zap.String("lockFile", lockFileName))
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
-		return cerrors.ErrSortDirLockError.Wrap(err).GenWithStackByCause()
+		return nil, cerrors.ErrSortDirLockError.Wrap(err).GenWithStackByCause()
 	}
 
-	p.fileLock = fileLock
-	return nil
+	return fileLock, nil
 }
 
 func (p *backEndPool) unlockSortDir() error {
@@ -350,13 +479,13 @@ func (p *backEndPool) unlockSortDir() error {
 	return nil
 }
 
-func (p *backEndPool) cleanUpStaleFiles() error {
-	if p.dir == "" {
+func cleanUpStaleFiles(dir string) error {
+	if dir == "" {
 		// guard against programmer error. Must be careful when we are deleting user files.
-		log.Panic("unexpected sort-dir", zap.String("sortDir", p.dir))
+		log.Panic("unexpected sort-dir", zap.String("sortDir", dir))
 	}
 
-	files, err := filepath.Glob(filepath.Join(p.dir, fmt.Sprintf("%s-*", sortDirDataFileMagicPrefix)))
+	files, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("%s-*", sortDirDataFileMagicPrefix)))
 	if err != nil {
 		return errors.Trace(err)
 	}
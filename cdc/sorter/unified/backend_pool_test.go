@@ -24,7 +24,9 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/fsutil"
 	"github.com/stretchr/testify/require"
 )
@@ -352,3 +354,88 @@ func TestCheckDataDirSatisfied(t *testing.T) {
 	require.Nil(t, err)
 	require.Nil(t, failpoint.Disable(p))
 }
+
+// TestChangefeedSorterConfig verifies that the backendPool respects the sorter config of changefeeds.
+func TestChangefeedSorterConfig(t *testing.T) {
+	defer CleanUp()
+
+	dataDir := t.TempDir()
+	sortDir := filepath.Join(dataDir, config.DefaultSortDir)
+	err := os.MkdirAll(sortDir, 0o755)
+	require.Nil(t, err)
+
+	conf := config.GetDefaultServerConfig()
+	conf.DataDir = dataDir
+	conf.AdvertiseAddr = "127.0.0.1:8300"
+	conf.Sorter.SortDir = sortDir
+	conf.Sorter.MaxMemoryPercentage = 90                       // 90%
+	conf.Sorter.MaxMemoryConsumption = 16 * 1024 * 1024 * 1024 // 16G
+	config.StoreGlobalServerConfig(conf)
+
+	err = failpoint.Enable("github.com/pingcap/tiflow/cdc/sorter/unified/memoryPressureInjectPoint", "return(0)")
+	require.Nil(t, err)
+	defer failpoint.Disable("github.com/pingcap/tiflow/cdc/sorter/unified/memoryPressureInjectPoint") //nolint:errcheck
+
+	s, err := NewUnifiedSorterWithConfig(sortDir, "test-cf", "test", 1, &config.ChangefeedSorterConfig{
+		MaxMemoryConsumption:       1024,
+		MaxDiskConsumptionPerTable: 1024,
+		SortDir:                    "changefeed",
+	})
+	require.Nil(t, err)
+	ctx := context.WithValue(context.Background(), ctxKey{}, s)
+
+	writeEvent := func(backEnd backEnd, size int) {
+		writer, err := backEnd.writer()
+		require.Nil(t, err)
+		rawKV := generateMockRawKV(10)
+		rawKV.Value = make([]byte, size)
+		err = writer.writeNext(model.NewPolymorphicEvent(rawKV))
+		require.Nil(t, err)
+		err = writer.flushAndClose()
+		require.Nil(t, err)
+	}
+
+	// Spills to files once the changefeed uses up its memory.
+	memBackEnd, err := pool.alloc(ctx)
+	require.Nil(t, err)
+	require.IsType(t, &memoryBackEnd{}, memBackEnd)
+	writeEvent(memBackEnd, 1024)
+	require.True(t, s.changefeedMemoryExceeded())
+
+	fileBackEnd1, err := pool.alloc(ctx)
+	require.Nil(t, err)
+	require.IsType(t, &fileBackEnd{}, fileBackEnd1)
+	fileName := fileBackEnd1.(*fileBackEnd).fileName
+	require.Equal(t, filepath.Join(sortDir, "changefeed"), filepath.Dir(fileName))
+	writeEvent(fileBackEnd1, 2048)
+
+	// Fails once the table uses up its disk quota.
+	_, err = pool.alloc(ctx)
+	require.True(t, cerrors.ErrUnifiedSorterDiskQuotaExceeded.Equal(err))
+
+	// The files in the sort-dir of the changefeed are not cached.
+	err = pool.dealloc(fileBackEnd1)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), s.onDiskDataSize)
+	_, err = os.Stat(fileName)
+	require.True(t, os.IsNotExist(err))
+	fileBackEnd2, err := pool.alloc(ctx)
+	require.Nil(t, err)
+	require.IsType(t, &fileBackEnd{}, fileBackEnd2)
+	err = pool.dealloc(fileBackEnd2)
+	require.Nil(t, err)
+
+	err = pool.dealloc(memBackEnd)
+	require.Nil(t, err)
+	require.False(t, s.changefeedMemoryExceeded())
+
+	// The sort-dir is released once no sorter uses it.
+	pool.releaseSortDir(s.sortDir)
+	require.Empty(t, pool.sortDirs)
+
+	// The sort-dir can't be out of the sort-dir of the server.
+	_, err = NewUnifiedSorterWithConfig(sortDir, "test-cf", "test", 2, &config.ChangefeedSorterConfig{
+		SortDir: "../changefeed",
+	})
+	require.True(t, cerrors.ErrIllegalSorterParameter.Equal(err))
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unified
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
+// The file magics tell the compression of the events following the file
+// header, the header itself is never compressed.
+const (
+	snappyFileMagic = 0x12345679
+	zstdFileMagic   = 0x1234567a
+)
+
+func fileMagicOf(compression string) uint32 {
+	switch compression {
+	case config.SorterCompressionSnappy:
+		return snappyFileMagic
+	case config.SorterCompressionZstd:
+		return zstdFileMagic
+	default:
+		return fileMagic
+	}
+}
+
+// compressionOf returns the compression of a file by its magic, ok is false
+// if the magic is unknown.
+func compressionOf(magic uint32) (compression string, ok bool) {
+	switch magic {
+	case fileMagic:
+		return config.SorterCompressionNone, true
+	case snappyFileMagic:
+		return config.SorterCompressionSnappy, true
+	case zstdFileMagic:
+		return config.SorterCompressionZstd, true
+	default:
+		return "", false
+	}
+}
+
+// newCompressWriter returns a writer compressing data written to w. The
+// returned writer must be closed to flush the compressed data, it doesn't
+// close w. It returns nil if compression is none.
+func newCompressWriter(compression string, w io.Writer) (io.WriteCloser, error) {
+	switch compression {
+	case config.SorterCompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case config.SorterCompressionZstd:
+		// Sorter files are written by many heap sorters concurrently, so
+		// an encoder uses a single goroutine.
+		encoder, err := zstd.NewWriter(w,
+			zstd.WithEncoderLevel(zstd.SpeedFastest),
+			zstd.WithEncoderConcurrency(1))
+		return encoder, errors.Trace(err)
+	default:
+		return nil, nil
+	}
+}
+
+// newDecompressReader returns a reader decompressing data read from r, and a
+// function releasing the resources of the reader.
+func newDecompressReader(compression string, r io.Reader) (io.Reader, func(), error) {
+	switch compression {
+	case config.SorterCompressionSnappy:
+		return snappy.NewReader(r), func() {}, nil
+	case config.SorterCompressionZstd:
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return decoder, decoder.Close, nil
+	default:
+		return r, func() {}, nil
+	}
+}
+
+// countingWriter counts the bytes written to a file, which are the physical
+// bytes of the events if they are compressed.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
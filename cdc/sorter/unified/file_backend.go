@@ -25,6 +25,8 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter/encoding"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -33,7 +35,12 @@ const (
 	fileBufferSize       = 4 * 1024 // 4KB
 	fileMagic            = 0x12345678
 	numFileEntriesOffset = 4
+	fileHeaderSize       = 12
 	blockMagic           = 0xbeefbeef
+	blockHeaderSize      = 8
+	// encryptedFileMagicFlag is set in the file magic if the events following
+	// the file header are encrypted, which are preceded by the IV.
+	encryptedFileMagicFlag = 0x80000000
 )
 
 var openFDCount int64
@@ -42,7 +49,20 @@ type fileBackEnd struct {
 	fileName string
 	serde    encoding.SerializerDeserializer
 	borrowed int32
-	size     int64
+	// size is the physical size of the file.
+	size int64
+	// compression is the compression of the events written to the file, it's
+	// one of the config.SorterCompression values, "" means none.
+	compression string
+	// cipher encrypts the events written to the file, it's nil if data isn't
+	// encrypted.
+	cipher *encryption.Cipher
+	// changefeedID labels the metrics of the file.
+	changefeedID model.ChangeFeedID
+	// tableDiskUse is the disk usage of the sorter using the file, which
+	// should be accessed atomically. It's nil if the file is not used by a
+	// sorter.
+	tableDiskUse *int64
 }
 
 func newFileBackEnd(fileName string, serde encoding.SerializerDeserializer) (*fileBackEnd, error) {
@@ -88,10 +108,11 @@ func (f *fileBackEnd) reader() (backEndReader, error) {
 	})
 
 	ret := &fileBackEndReader{
-		backEnd:   f,
-		f:         fd,
-		reader:    bufio.NewReaderSize(fd, fileBufferSize),
-		totalSize: totalSize,
+		backEnd:       f,
+		f:             fd,
+		reader:        bufio.NewReaderSize(fd, fileBufferSize),
+		releaseStream: func() {},
+		totalSize:     totalSize,
 	}
 
 	err = ret.readHeader()
@@ -116,10 +137,14 @@ func (f *fileBackEnd) writer() (backEndWriter, error) {
 		}
 	})
 
+	counter := &countingWriter{w: fd}
+	writer := bufio.NewWriterSize(counter, fileBufferSize)
 	ret := &fileBackEndWriter{
 		backEnd: f,
 		f:       fd,
-		writer:  bufio.NewWriterSize(fd, fileBufferSize),
+		counter: counter,
+		writer:  writer,
+		stream:  writer,
 	}
 
 	err = ret.writeFileHeader()
@@ -127,6 +152,24 @@ func (f *fileBackEnd) writer() (backEndWriter, error) {
 		return nil, errors.Trace(wrapIOError(err))
 	}
 
+	// Events are compressed before encrypted, since encrypted data can't be
+	// compressed.
+	var encrypted io.Writer = writer
+	if f.cipher != nil {
+		encrypted, err = f.cipher.NewWriter(writer)
+		if err != nil {
+			return nil, errors.Trace(wrapIOError(err))
+		}
+		ret.stream = encrypted
+	}
+	ret.compressor, err = newCompressWriter(f.compression, encrypted)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ret.compressor != nil {
+		ret.stream = ret.compressor
+	}
+
 	return ret, nil
 }
 
@@ -157,6 +200,10 @@ func (f *fileBackEnd) cleanStats() {
 	if pool != nil {
 		atomic.AddInt64(&pool.onDiskDataSize, -f.size)
 	}
+	if f.tableDiskUse != nil {
+		atomic.AddInt64(f.tableDiskUse, -f.size)
+		f.tableDiskUse = nil
+	}
 	f.size = 0
 }
 
@@ -164,7 +211,12 @@ type fileBackEndReader struct {
 	backEnd *fileBackEnd
 	f       *os.File
 	reader  *bufio.Reader
-	isEOF   bool
+	// stream reads the events, it decrypts and decompresses the events read
+	// from reader if the file is encrypted or compressed.
+	stream        io.Reader
+	releaseStream func()
+	compressed    bool
+	isEOF         bool
 
 	// to prevent truncation-like corruption
 	totalEvents uint64
@@ -192,15 +244,33 @@ func (r *fileBackEndReader) readHeader() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if m != fileMagic {
+	encrypted := m&encryptedFileMagicFlag != 0
+	compression, ok := compressionOf(m &^ encryptedFileMagicFlag)
+	if !ok {
 		log.Panic("fileSorterBackEnd: wrong fileMagic. Damaged file or bug?", zap.Uint32("actual", m))
 	}
+	if encrypted && r.backEnd.cipher == nil {
+		log.Panic("fileSorterBackEnd: encrypted file without a data key. Bug?",
+			zap.String("fileName", r.backEnd.fileName))
+	}
 
 	err = binary.Read(r.reader, binary.LittleEndian, &r.totalEvents)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
+	var decrypted io.Reader = r.reader
+	if encrypted {
+		decrypted, err = r.backEnd.cipher.NewReader(r.reader)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	r.stream, r.releaseStream, err = newDecompressReader(compression, decrypted)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.compressed = compression != config.SorterCompressionNone
 	return nil
 }
 
@@ -211,7 +281,7 @@ func (r *fileBackEndReader) readNext() (*model.PolymorphicEvent, error) {
 	}
 
 	var m uint32
-	err := binary.Read(r.reader, binary.LittleEndian, &m)
+	err := binary.Read(r.stream, binary.LittleEndian, &m)
 	if err != nil {
 		if err == io.EOF {
 			r.isEOF = true
@@ -232,7 +302,7 @@ func (r *fileBackEndReader) readNext() (*model.PolymorphicEvent, error) {
 	}
 
 	var size uint32
-	err = binary.Read(r.reader, binary.LittleEndian, &size)
+	err = binary.Read(r.stream, binary.LittleEndian, &size)
 	if err != nil {
 		return nil, errors.Trace(wrapIOError(err))
 	}
@@ -241,7 +311,7 @@ func (r *fileBackEndReader) readNext() (*model.PolymorphicEvent, error) {
 	rawBytesBuf := make([]byte, size)
 
 	// short reads are possible with bufio, hence the need for io.ReadFull
-	n, err := io.ReadFull(r.reader, rawBytesBuf)
+	n, err := io.ReadFull(r.stream, rawBytesBuf)
 	if err != nil {
 		return nil, errors.Trace(wrapIOError(err))
 	}
@@ -259,8 +329,9 @@ func (r *fileBackEndReader) readNext() (*model.PolymorphicEvent, error) {
 	r.readEvents++
 
 	failpoint.Inject("sorterDebug", func() {
-		r.readBytes += int64(4 + 4 + int(size))
-		if r.readBytes > r.totalSize {
+		r.readBytes += int64(blockHeaderSize + int(size))
+		// the bytes read are larger than the file if it's compressed.
+		if !r.compressed && r.readBytes > r.totalSize {
 			log.Panic("fileSorterBackEnd: read more bytes than expected, check concurrent use of file",
 				zap.String("fileName", r.backEnd.fileName))
 		}
@@ -289,6 +360,8 @@ func (r *fileBackEndReader) resetAndClose() error {
 		return nil
 	}
 
+	r.releaseStream()
+
 	err := r.f.Truncate(0)
 	if err != nil {
 		failpoint.Inject("sorterDebug", func() {
@@ -322,14 +395,24 @@ func (r *fileBackEndReader) resetAndClose() error {
 type fileBackEndWriter struct {
 	backEnd *fileBackEnd
 	f       *os.File
+	// counter counts the physical bytes written to f.
+	counter *countingWriter
 	writer  *bufio.Writer
+	// stream writes the events, it compresses and encrypts the events before
+	// they are written to writer if the file is compressed or encrypted.
+	stream     io.Writer
+	compressor io.WriteCloser
 
 	bytesWritten  int64
 	eventsWritten int64
 }
 
 func (w *fileBackEndWriter) writeFileHeader() error {
-	err := binary.Write(w.writer, binary.LittleEndian, uint32(fileMagic))
+	magic := fileMagicOf(w.backEnd.compression)
+	if w.backEnd.cipher != nil {
+		magic |= encryptedFileMagicFlag
+	}
+	err := binary.Write(w.writer, binary.LittleEndian, magic)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -357,12 +440,12 @@ func (w *fileBackEndWriter) writeNext(event *model.PolymorphicEvent) error {
 		log.Panic("fileSorterBackEnd: serialized to empty byte array. Bug?")
 	}
 
-	err = binary.Write(w.writer, binary.LittleEndian, uint32(blockMagic))
+	err = binary.Write(w.stream, binary.LittleEndian, uint32(blockMagic))
 	if err != nil {
 		return errors.Trace(wrapIOError(err))
 	}
 
-	err = binary.Write(w.writer, binary.LittleEndian, uint32(size))
+	err = binary.Write(w.stream, binary.LittleEndian, uint32(size))
 	if err != nil {
 		return errors.Trace(wrapIOError(err))
 	}
@@ -370,7 +453,7 @@ func (w *fileBackEndWriter) writeNext(event *model.PolymorphicEvent) error {
 	// short writes are possible with bufio
 	offset := 0
 	for offset < size {
-		n, err := w.writer.Write(rawBytesBuf[offset:])
+		n, err := w.stream.Write(rawBytesBuf[offset:])
 		if err != nil {
 			return errors.Trace(wrapIOError(err))
 		}
@@ -399,10 +482,19 @@ func (w *fileBackEndWriter) flushAndClose() error {
 		w.f = nil
 	}()
 
+	if w.compressor != nil {
+		err := w.compressor.Close()
+		if err != nil {
+			return errors.Trace(wrapIOError(err))
+		}
+	}
+
 	err := w.writer.Flush()
 	if err != nil {
 		return errors.Trace(wrapIOError(err))
 	}
+	// the number of entries is written in place, it doesn't change the size.
+	physicalBytes := w.counter.n
 
 	_, err = w.f.Seek(numFileEntriesOffset, 0 /* relative to the beginning of the file */)
 	if err != nil {
@@ -425,8 +517,16 @@ func (w *fileBackEndWriter) flushAndClose() error {
 	}
 
 	atomic.AddInt64(&openFDCount, -1)
-	w.backEnd.size = w.bytesWritten
-	atomic.AddInt64(&pool.onDiskDataSize, w.bytesWritten)
+	w.backEnd.size = physicalBytes
+	if pool != nil {
+		atomic.AddInt64(&pool.onDiskDataSize, physicalBytes)
+	}
+	if w.backEnd.tableDiskUse != nil {
+		atomic.AddInt64(w.backEnd.tableDiskUse, physicalBytes)
+	}
+	logicalBytes := fileHeaderSize + blockHeaderSize*w.eventsWritten + w.bytesWritten
+	sorterSpillLogicalBytes.WithLabelValues(w.backEnd.changefeedID).Add(float64(logicalBytes))
+	sorterSpillPhysicalBytes.WithLabelValues(w.backEnd.changefeedID).Add(float64(physicalBytes))
 
 	failpoint.Inject("sorterDebug", func() {
 		atomic.StoreInt32(&w.backEnd.borrowed, 0)
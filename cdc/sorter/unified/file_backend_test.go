@@ -14,13 +14,17 @@
 package unified
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter/encoding"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
@@ -89,3 +93,80 @@ func TestDataSize(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, uint64(71), w.dataSize())
 }
+
+func TestCompression(t *testing.T) {
+	sizes := make(map[string]int64)
+	for _, compression := range []string{
+		config.SorterCompressionNone, config.SorterCompressionSnappy, config.SorterCompressionZstd,
+	} {
+		fb, err := newFileBackEnd(filepath.Join(t.TempDir(), "sort-1.tmp"), &encoding.MsgPackGenSerde{})
+		require.Nil(t, err)
+		fb.compression = compression
+
+		w, err := fb.writer()
+		require.Nil(t, err)
+		for i := 1; i <= 1000; i++ {
+			err = w.writeNext(model.NewPolymorphicEvent(generateMockRawKV(uint64(i) + 5)))
+			require.Nil(t, err)
+		}
+		require.Nil(t, w.flushAndClose())
+		info, err := os.Stat(fb.fileName)
+		require.Nil(t, err)
+		require.Equal(t, info.Size(), fb.size)
+		sizes[compression] = fb.size
+
+		r, err := fb.reader()
+		require.Nil(t, err)
+		for i := 1; i <= 1000; i++ {
+			event, err := r.readNext()
+			require.Nil(t, err)
+			require.Equal(t, uint64(i)+5, event.CRTs)
+		}
+		event, err := r.readNext()
+		require.Nil(t, err)
+		require.Nil(t, event)
+		require.Nil(t, r.resetAndClose())
+		require.Nil(t, fb.free())
+	}
+	require.Less(t, sizes[config.SorterCompressionSnappy], sizes[config.SorterCompressionNone])
+	require.Less(t, sizes[config.SorterCompressionZstd], sizes[config.SorterCompressionNone])
+}
+
+func TestEncryption(t *testing.T) {
+	cipher, err := encryption.NewCipher(bytes.Repeat([]byte{1}, encryption.KeySize))
+	require.Nil(t, err)
+	for _, compression := range []string{config.SorterCompressionNone, config.SorterCompressionZstd} {
+		fb, err := newFileBackEnd(filepath.Join(t.TempDir(), "sort-1.tmp"), &encoding.MsgPackGenSerde{})
+		require.Nil(t, err)
+		fb.compression = compression
+		fb.cipher = cipher
+
+		w, err := fb.writer()
+		require.Nil(t, err)
+		for i := 1; i <= 1000; i++ {
+			rawKV := generateMockRawKV(uint64(i) + 5)
+			rawKV.Value = []byte("row data")
+			err = w.writeNext(model.NewPolymorphicEvent(rawKV))
+			require.Nil(t, err)
+		}
+		require.Nil(t, w.flushAndClose())
+		content, err := ioutil.ReadFile(fb.fileName)
+		require.Nil(t, err)
+		require.Equal(t, int64(len(content)), fb.size)
+		require.False(t, bytes.Contains(content, []byte("row data")))
+
+		r, err := fb.reader()
+		require.Nil(t, err)
+		for i := 1; i <= 1000; i++ {
+			event, err := r.readNext()
+			require.Nil(t, err)
+			require.Equal(t, uint64(i)+5, event.CRTs)
+			require.Equal(t, []byte("row data"), event.RawKV.Value)
+		}
+		event, err := r.readNext()
+		require.Nil(t, err)
+		require.Nil(t, event)
+		require.Nil(t, r.resetAndClose())
+		require.Nil(t, fb.free())
+	}
+}
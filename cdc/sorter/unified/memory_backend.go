@@ -26,6 +26,10 @@ type memoryBackEnd struct {
 	events        []*model.PolymorphicEvent
 	estimatedSize int64
 	borrowed      int32
+	// changefeedMemoryUse is the memory usage of the changefeed using the
+	// backEnd, which should be accessed atomically. It's nil if the backEnd
+	// is not used by a sorter.
+	changefeedMemoryUse *int64
 }
 
 func newMemoryBackEnd() *memoryBackEnd {
@@ -65,6 +69,9 @@ func (m *memoryBackEnd) free() error {
 	if pool != nil {
 		atomic.AddInt64(&pool.memoryUseEstimate, -m.estimatedSize)
 	}
+	if m.changefeedMemoryUse != nil {
+		atomic.AddInt64(m.changefeedMemoryUse, -m.estimatedSize)
+	}
 
 	return nil
 }
@@ -95,6 +102,9 @@ func (r *memoryBackEndReader) resetAndClose() error {
 	if pool != nil {
 		atomic.AddInt64(&pool.memoryUseEstimate, -r.backEnd.estimatedSize)
 	}
+	if r.backEnd.changefeedMemoryUse != nil {
+		atomic.AddInt64(r.backEnd.changefeedMemoryUse, -r.backEnd.estimatedSize)
+	}
 	r.backEnd.estimatedSize = 0
 
 	return nil
@@ -141,6 +151,9 @@ func (w *memoryBackEndWriter) flushAndClose() error {
 	if pool != nil {
 		atomic.AddInt64(&pool.memoryUseEstimate, w.bytesWritten)
 	}
+	if w.backEnd.changefeedMemoryUse != nil {
+		atomic.AddInt64(w.backEnd.changefeedMemoryUse, w.bytesWritten)
+	}
 
 	return nil
 }
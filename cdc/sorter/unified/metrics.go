@@ -47,6 +47,20 @@ var (
 		Help:      "Bucketed histogram of the number of events in individual merges performed by the sorter",
 		Buckets:   prometheus.ExponentialBuckets(16, 4, 10),
 	}, []string{"changefeed"})
+
+	sorterSpillLogicalBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "spill_logical_bytes",
+		Help:      "the number of bytes of the events spilled to files by the sorter before compression",
+	}, []string{"changefeed"})
+
+	sorterSpillPhysicalBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "spill_physical_bytes",
+		Help:      "the number of bytes written to files by the sorter after compression",
+	}, []string{"changefeed"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -55,4 +69,6 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterMergerStartTsGauge)
 	registry.MustRegister(sorterFlushCountHistogram)
 	registry.MustRegister(sorterMergeCountHistogram)
+	registry.MustRegister(sorterSpillLogicalBytes)
+	registry.MustRegister(sorterSpillPhysicalBytes)
 }
@@ -16,6 +16,7 @@ package unified
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -40,6 +42,21 @@ type Sorter struct {
 	dir         string
 	metricsInfo *metricsInfo
 
+	// cfg overrides the spill thresholds of the server, it's never nil.
+	cfg *config.ChangefeedSorterConfig
+	// sortDir is the sort-dir set by the changefeed, nil means the sort-dir
+	// of the server. It's acquired from sortDirPool and released after the
+	// sorter exits.
+	sortDir     *changefeedSortDir
+	sortDirPool *backEndPool
+	// onDiskDataSize is the size of the files written by the sorter, it
+	// should be accessed atomically.
+	onDiskDataSize int64
+	// changefeedMemoryUse is the memory used by the in-memory backEnds of the
+	// changefeed, it's shared by the sorters of the changefeed and should be
+	// accessed atomically.
+	changefeedMemoryUse *int64
+
 	closeCh chan struct{}
 }
 
@@ -54,6 +71,15 @@ type ctxKey struct{}
 // NewUnifiedSorter creates a new Sorter
 func NewUnifiedSorter(
 	dir string, changeFeedID model.ChangeFeedID, tableName string, tableID model.TableID,
+) (*Sorter, error) {
+	return NewUnifiedSorterWithConfig(dir, changeFeedID, tableName, tableID, nil)
+}
+
+// NewUnifiedSorterWithConfig creates a new Sorter, whose spill thresholds and
+// sort-dir are overridden by the sorter config of the changefeed if cfg is not nil.
+func NewUnifiedSorterWithConfig(
+	dir string, changeFeedID model.ChangeFeedID, tableName string, tableID model.TableID,
+	cfg *config.ChangefeedSorterConfig,
 ) (*Sorter, error) {
 	poolMu.Lock()
 	defer poolMu.Unlock()
@@ -66,6 +92,18 @@ func NewUnifiedSorter(
 		}
 	}
 
+	if cfg == nil {
+		cfg = &config.ChangefeedSorterConfig{}
+	}
+	var sortDir *changefeedSortDir
+	if cfg.SortDir != "" {
+		var err error
+		sortDir, err = pool.acquireSortDir(cfg.SortDir)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	lazyInitWorkerPool()
 	return &Sorter{
 		inputCh:  make(chan *model.PolymorphicEvent, inputChSize),
@@ -76,10 +114,47 @@ func NewUnifiedSorter(
 			tableName:    tableName,
 			tableID:      tableID,
 		},
-		closeCh: make(chan struct{}, 1),
+		cfg:                 cfg,
+		sortDir:             sortDir,
+		sortDirPool:         pool,
+		changefeedMemoryUse: pool.changefeedMemoryUsage(changeFeedID),
+		closeCh:             make(chan struct{}, 1),
 	}, nil
 }
 
+// changefeedMemoryExceeded returns true if the in-memory backEnds of the
+// changefeed use more memory than the limit of the changefeed.
+func (s *Sorter) changefeedMemoryExceeded() bool {
+	return s.cfg.MaxMemoryConsumption > 0 &&
+		atomic.LoadInt64(s.changefeedMemoryUse) >= int64(s.cfg.MaxMemoryConsumption)
+}
+
+// checkDiskQuota returns an error if the files written by the sorter exceed
+// the disk quota of a table. It's checked before writing a file, so the files
+// may exceed the quota by the data flushed concurrently.
+func (s *Sorter) checkDiskQuota() error {
+	if s.cfg.MaxDiskConsumptionPerTable == 0 {
+		return nil
+	}
+	used := atomic.LoadInt64(&s.onDiskDataSize)
+	if used < int64(s.cfg.MaxDiskConsumptionPerTable) {
+		return nil
+	}
+	log.Warn("Unified Sorter: disk quota of table exceeded",
+		zap.String("changefeed", s.metricsInfo.changeFeedID),
+		zap.String("tableName", s.metricsInfo.tableName),
+		zap.Int64("tableID", s.metricsInfo.tableID),
+		zap.Int64("used", used),
+		zap.Uint64("quota", s.cfg.MaxDiskConsumptionPerTable))
+	return cerror.ErrUnifiedSorterDiskQuotaExceeded.GenWithStackByArgs(
+		s.metricsInfo.tableName, used, s.cfg.MaxDiskConsumptionPerTable, s.metricsInfo.changeFeedID)
+}
+
+// OnDiskDataSize returns the size of the files written by the sorter.
+func (s *Sorter) OnDiskDataSize() int64 {
+	return atomic.LoadInt64(&s.onDiskDataSize)
+}
+
 // CleanUp cleans up the files that might have been used.
 func CleanUp() {
 	poolMu.Lock()
@@ -108,6 +183,9 @@ func (s *Sorter) Run(ctx context.Context) error {
 	})
 
 	defer close(s.closeCh)
+	if s.sortDir != nil {
+		defer s.sortDirPool.releaseSortDir(s.sortDir)
+	}
 
 	finish, startCancel := util.MonitorCancelLatency(ctx, "Unified Sorter")
 	defer finish()
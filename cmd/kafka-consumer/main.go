@@ -499,6 +499,12 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 			decoder codec.EventBatchDecoder
 			err     error
 		)
+		if codec.IsEndOfStreamMessage(message.Key) {
+			log.Info("end-of-stream message received, the changefeed is removed",
+				zap.ByteString("value", message.Value), zap.Int32("partition", partition))
+			session.MarkMessage(message, "")
+			continue
+		}
 		switch c.protocol {
 		case config.ProtocolOpen, config.ProtocolDefault:
 			decoder, err = codec.NewJSONEventBatchDecoder(message.Key, message.Value)
@@ -61,6 +61,8 @@ func ignoreExcept(itemMap map[string]struct{}) []string {
 		config.ShardAutoIncrementIDChecking,
 		config.OnlineDDLChecking,
 		config.BinlogDBChecking,
+		config.CrossTaskConflictChecking,
+		config.TypeCompatibilityChecking,
 	}
 	ignoreCheckingItems := make([]string, 0, len(items)-len(itemMap))
 	for _, i := range items {
@@ -470,6 +472,76 @@ func (s *testCheckerSuite) TestSameTargetTableDetection(c *tc.C) {
 	c.Assert(len(msg), tc.Equals, 0)
 }
 
+func (s *testCheckerSuite) TestCrossTaskConflictChecking(c *tc.C) {
+	ignoreCheckingItems := ignoreExcept(map[string]struct{}{config.CrossTaskConflictChecking: {}})
+	newCfg := func(task, source string, rules []*router.TableRule) *config.SubTaskConfig {
+		return &config.SubTaskConfig{
+			Name:                task,
+			SourceID:            source,
+			IgnoreCheckingItems: ignoreCheckingItems,
+			EnableCheckingItems: []string{config.CrossTaskConflictChecking},
+			RouteRules:          rules,
+		}
+	}
+	expectFetchTables := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SHOW DATABASES").WillReturnRows(sqlmock.NewRows([]string{"DATABASE"}).AddRow(schema))
+		mock.ExpectQuery("SHOW FULL TABLES").WillReturnRows(sqlmock.NewRows([]string{"Tables_in_" + schema, "Table_type"}).AddRow(tb1, "BASE TABLE").AddRow(tb2, "BASE TABLE"))
+	}
+	extendRules := []*router.TableRule{{
+		SchemaPattern: schema,
+		TablePattern:  "t_*",
+		TargetSchema:  schema,
+		TargetTable:   "t",
+		SourceExtractor: &router.SourceExtractor{
+			TargetColumn: "source_name",
+			SourceRegexp: "(.*)",
+		},
+	}}
+
+	// no other tasks
+	cfgs := []*config.SubTaskConfig{newCfg(taskName, "source-1", nil)}
+	msg, err := CheckCrossTaskConflict(context.Background(), cfgs, nil)
+	c.Assert(err, tc.IsNil)
+	c.Assert(msg, tc.Equals, "")
+
+	// same task is not a conflict
+	msg, err = CheckCrossTaskConflict(context.Background(), cfgs, []*config.SubTaskConfig{newCfg(taskName, "source-2", nil)})
+	c.Assert(err, tc.IsNil)
+	c.Assert(msg, tc.Equals, "")
+
+	// other task writes into the same tables
+	otherCfgs := []*config.SubTaskConfig{newCfg("other-task", "source-2", nil)}
+	mock := conn.InitMockDB(c)
+	expectFetchTables(mock)
+	expectFetchTables(mock)
+	msg, err = CheckCrossTaskConflict(context.Background(), cfgs, otherCfgs)
+	c.Assert(err, tc.ErrorMatches, "(.|\n)*downstream tables are written by other tasks(.|\n)*source source-2 of task other-task(.|\n)*")
+	c.Assert(msg, tc.Equals, "")
+
+	// all the tables are distinguished by extended columns
+	cfgs = []*config.SubTaskConfig{newCfg(taskName, "source-1", extendRules)}
+	otherCfgs = []*config.SubTaskConfig{newCfg("other-task", "source-2", extendRules)}
+	mock = conn.InitMockDB(c)
+	expectFetchTables(mock)
+	expectFetchTables(mock)
+	msg, err = CheckCrossTaskConflict(context.Background(), cfgs, otherCfgs)
+	c.Assert(err, tc.IsNil)
+	c.Assert(msg, tc.Matches, "(.|\n)*no errors but some warnings(.|\n)*`db_1`.`t` is written by(.|\n)*")
+
+	// the checking item is ignored
+	cfgs[0].IgnoreCheckingItems = []string{config.CrossTaskConflictChecking}
+	msg, err = CheckCrossTaskConflict(context.Background(), cfgs, otherCfgs)
+	c.Assert(err, tc.IsNil)
+	c.Assert(msg, tc.Equals, "")
+
+	// the checking item is not enabled
+	cfgs[0].IgnoreCheckingItems = nil
+	cfgs[0].EnableCheckingItems = nil
+	msg, err = CheckCrossTaskConflict(context.Background(), cfgs, otherCfgs)
+	c.Assert(err, tc.IsNil)
+	c.Assert(msg, tc.Equals, "")
+}
+
 func initMockDB(c *tc.C) sqlmock.Sqlmock {
 	mock := conn.InitMockDB(c)
 	mock.ExpectQuery("SHOW DATABASES").WillReturnRows(sqlmock.NewRows([]string{"DATABASE"}).AddRow(schema))
@@ -218,6 +218,11 @@ func (c *Checker) Init(ctx context.Context) (err error) {
 			if _, ok := c.checkingItems[config.BinlogDBChecking]; ok {
 				c.checkList = append(c.checkList, checker.NewBinlogDBChecker(instance.sourceDB.DB, instance.sourceDBinfo, checkSchemas, instance.cfg.CaseSensitive))
 			}
+			// the binlog events are written into the existing downstream tables, check whether their
+			// column types can hold the upstream data before the incremental replication starts.
+			if _, ok := c.checkingItems[config.TypeCompatibilityChecking]; ok {
+				c.checkList = append(c.checkList, checker.NewTypeCompatibilityChecker(instance.cfg.SourceID, instance.sourceDB.DB, instance.targetDB.DB, mapping))
+			}
 		}
 	}
 
@@ -0,0 +1,173 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb/util/filter"
+	regexprrouter "github.com/pingcap/tidb/util/regexpr-router"
+	"github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/pkg/conn"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// CheckCrossTaskConflictFunc holds the CheckCrossTaskConflict function.
+var CheckCrossTaskConflictFunc = CheckCrossTaskConflict
+
+// targetTableWriter is a source of a task which writes into a downstream table.
+type targetTableWriter struct {
+	task   string
+	source string
+	// mergeSafe is true when all the upstream tables routed to the downstream table
+	// have extended columns, so rows from different upstreams can be distinguished.
+	mergeSafe bool
+}
+
+// CheckCrossTaskConflict checks whether the sub tasks in cfgs route upstream tables into
+// downstream tables which are also written by the sub tasks of other tasks in otherCfgs.
+// if all the writers of a conflicting table use extended columns in route rules, it's
+// reported as a warning in the returned message, otherwise an error is returned.
+func CheckCrossTaskConflict(ctx context.Context, cfgs, otherCfgs []*config.SubTaskConfig) (string, error) {
+	if len(cfgs) == 0 {
+		return "", nil
+	}
+	if !config.IsOptInCheckingItemEnabled(config.CrossTaskConflictChecking,
+		cfgs[0].IgnoreCheckingItems, cfgs[0].EnableCheckingItems) {
+		return "", nil
+	}
+
+	// only the tasks replicating to the same downstream may conflict
+	downstreams := make(map[string]struct{}, len(cfgs))
+	for _, cfg := range cfgs {
+		downstreams[downstreamID(cfg)] = struct{}{}
+	}
+	relatedCfgs := make([]*config.SubTaskConfig, 0, len(otherCfgs))
+	for _, cfg := range otherCfgs {
+		if _, ok := downstreams[downstreamID(cfg)]; ok && cfg.Name != cfgs[0].Name {
+			relatedCfgs = append(relatedCfgs, cfg)
+		}
+	}
+	if len(relatedCfgs) == 0 {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	allCfgs := append(append(make([]*config.SubTaskConfig, 0, len(cfgs)+len(relatedCfgs)), cfgs...), relatedCfgs...)
+	writers, err := fetchTargetTableWriters(ctx, allCfgs)
+	if err != nil {
+		return "", err
+	}
+
+	var conflicts, warnings []string
+	for target, ws := range writers {
+		for _, w := range ws {
+			if w.task != cfgs[0].Name {
+				continue
+			}
+			for _, other := range ws {
+				if other.task == cfgs[0].Name {
+					continue
+				}
+				msg := fmt.Sprintf("%s is written by source %s of task %s and source %s of task %s",
+					target, w.source, w.task, other.source, other.task)
+				if w.mergeSafe && other.mergeSafe {
+					warnings = append(warnings, msg)
+				} else {
+					conflicts = append(conflicts, msg)
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return "", terror.ErrTaskCheckCrossTaskConflict.Generate(strings.Join(conflicts, "; "))
+	}
+	if len(warnings) > 0 {
+		sort.Strings(warnings)
+		return fmt.Sprintf("%s: no errors but some warnings\n detail: downstream tables are written by other tasks with extended columns: %s",
+			CheckTaskMsgHeader, strings.Join(warnings, "; ")), nil
+	}
+	return "", nil
+}
+
+// downstreamID returns the identity of the downstream of a sub task.
+func downstreamID(cfg *config.SubTaskConfig) string {
+	return fmt.Sprintf("%s:%d", cfg.To.Host, cfg.To.Port)
+}
+
+// fetchTargetTableWriters returns the writers of every downstream table of cfgs,
+// the key is the downstream identity and the lower-cased target table name.
+func fetchTargetTableWriters(ctx context.Context, cfgs []*config.SubTaskConfig) (map[string][]*targetTableWriter, error) {
+	sourceDBs := make([]*conn.BaseDB, 0, len(cfgs))
+	defer func() {
+		for _, db := range sourceDBs {
+			if err := db.Close(); err != nil {
+				log.L().Warn("fail to close source DB", zap.Error(err))
+			}
+		}
+	}()
+
+	writers := make(map[string][]*targetTableWriter)
+	for _, cfg := range cfgs {
+		cfg, err := cfg.DecryptPassword()
+		if err != nil {
+			return nil, err
+		}
+		bAList, err := filter.New(cfg.CaseSensitive, cfg.BAList)
+		if err != nil {
+			return nil, terror.ErrTaskCheckGenBAList.Delegate(err)
+		}
+		r, err := regexprrouter.NewRegExprRouter(cfg.CaseSensitive, cfg.RouteRules)
+		if err != nil {
+			return nil, terror.ErrTaskCheckGenTableRouter.Delegate(err)
+		}
+
+		dbCfg := cfg.From
+		dbCfg.RawDBCfg = config.DefaultRawDBConfig().SetReadTimeout(readTimeout)
+		sourceDB, err := conn.DefaultDBProvider.Apply(&dbCfg)
+		if err != nil {
+			return nil, terror.WithScope(terror.ErrTaskCheckFailedOpenDB.Delegate(err, cfg.From.User, cfg.From.Host, cfg.From.Port), terror.ScopeUpstream)
+		}
+		sourceDBs = append(sourceDBs, sourceDB)
+		mapping, err := utils.FetchTargetDoTables(ctx, sourceDB.DB, bAList, r)
+		if err != nil {
+			return nil, err
+		}
+
+		for targetTable, tables := range mapping {
+			mergeSafe := true
+			for _, table := range tables {
+				if cols, _ := r.FetchExtendColumn(table.Schema, table.Name, cfg.SourceID); len(cols) == 0 {
+					mergeSafe = false
+					break
+				}
+			}
+			key := downstreamID(cfg) + "/" + strings.ToLower(targetTable)
+			writers[key] = append(writers[key], &targetTableWriter{
+				task:      cfg.Name,
+				source:    cfg.SourceID,
+				mergeSafe: mergeSafe,
+			})
+		}
+	}
+	return writers, nil
+}
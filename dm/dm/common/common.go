@@ -29,6 +29,9 @@ var (
 	// ClusterIDKey is used to store the cluster id of the whole dm cluster. Cluster id is the unique identification of dm cluster
 	// After leader of dm master bootstraped, the leader will get the id from etcd or generate fresh one, and backfill to etcd.
 	ClusterIDKey = "/dm-cluster/id"
+	// LoadQuotaLimitKey is used to store how many subtasks may run the load unit concurrently in the cluster,
+	// it's put by the DM-master leader, and the load quota is not limited if it doesn't exist.
+	LoadQuotaLimitKey = "/dm-master/load-quota/limit"
 	// WorkerRegisterKeyAdapter is used to encode and decode register key.
 	// k/v: Encode(worker-name) -> the information of the DM-worker node.
 	WorkerRegisterKeyAdapter KeyAdapter = keyHexEncoderDecoder("/dm-worker/r/")
@@ -38,6 +41,12 @@ var (
 	// LoadTaskKeyAdapter is used to store the worker which in load stage for the source of the subtask.
 	// k/v: Encode(task, source-id) -> worker-name.
 	LoadTaskKeyAdapter KeyAdapter = keyHexEncoderDecoder("/dm-master/load-task/")
+	// LoadQuotaRequestKeyAdapter is used to store the requests of the subtasks for the load quota.
+	// k/v: Encode(task-name, source-id) -> the request of the DM-worker running the subtask.
+	LoadQuotaRequestKeyAdapter KeyAdapter = keyHexEncoderDecoder("/dm-worker/load-quota/request/")
+	// LoadQuotaGrantKeyAdapter is used to store the load quota granted by the DM-master leader.
+	// k/v: Encode(task-name, source-id) -> the name of the DM-worker running the subtask.
+	LoadQuotaGrantKeyAdapter KeyAdapter = keyHexEncoderDecoder("/dm-master/load-quota/grant/")
 	// UpstreamConfigKeyAdapter stores all config of which MySQL-task has not stopped.
 	// k/v: Encode(source-id) -> config.
 	UpstreamConfigKeyAdapter KeyAdapter = keyHexEncoderDecoder("/dm-master/v2/upstream/config/")
@@ -117,7 +126,8 @@ func keyAdapterKeysLen(s KeyAdapter) int {
 		return 1
 	case UpstreamSubTaskKeyAdapter, StageSubTaskKeyAdapter, StageValidatorKeyAdapter,
 		ShardDDLPessimismInfoKeyAdapter, ShardDDLPessimismOperationKeyAdapter,
-		ShardDDLOptimismSourceTablesKeyAdapter, LoadTaskKeyAdapter, TaskCliArgsKeyAdapter:
+		ShardDDLOptimismSourceTablesKeyAdapter, LoadTaskKeyAdapter, TaskCliArgsKeyAdapter,
+		LoadQuotaRequestKeyAdapter, LoadQuotaGrantKeyAdapter:
 		return 2
 	case ShardDDLOptimismInfoKeyAdapter, ShardDDLOptimismOperationKeyAdapter:
 		return 4
@@ -35,6 +35,8 @@ const (
 	ShardAutoIncrementIDChecking = "auto_increment_ID"
 	OnlineDDLChecking            = "online_ddl"
 	BinlogDBChecking             = "binlog_db"
+	CrossTaskConflictChecking    = "cross_task_conflict"
+	TypeCompatibilityChecking    = "type_compatibility"
 )
 
 // AllCheckingItems contains all checking items.
@@ -52,6 +54,14 @@ var AllCheckingItems = map[string]string{
 	ShardAutoIncrementIDChecking: "conflict auto increment ID of shard tables checking item",
 	OnlineDDLChecking:            "online ddl checking item",
 	BinlogDBChecking:             "binlog db checking item",
+	CrossTaskConflictChecking:    "conflict downstream tables of different tasks checking item",
+	TypeCompatibilityChecking:    "column type compatibility between upstream and downstream checking item",
+}
+
+// OptInCheckingItems are the checking items which are not run by default, they
+// are only run when enabled by `enable-checking-items` and not ignored.
+var OptInCheckingItems = map[string]struct{}{
+	CrossTaskConflictChecking: {},
 }
 
 // MaxSourceIDLength is the max length for dm-worker source id.
@@ -77,10 +87,21 @@ func SupportCheckingItems() string {
 	return buf.String()
 }
 
-// FilterCheckingItems filters ignored items from all checking items.
+// ValidateOptInCheckingItem validates the checking item can be enabled.
+func ValidateOptInCheckingItem(item string) error {
+	if _, ok := OptInCheckingItems[item]; ok {
+		return nil
+	}
+	return terror.ErrConfigCheckItemNotSupport.Generate(item, SupportCheckingItems())
+}
+
+// FilterCheckingItems filters ignored items and opt-in items from all checking items.
 func FilterCheckingItems(ignoredItems []string) map[string]string {
 	checkingItems := make(map[string]string)
 	for item, desc := range AllCheckingItems {
+		if _, ok := OptInCheckingItems[item]; ok {
+			continue
+		}
 		checkingItems[item] = desc
 	}
 	delete(checkingItems, AllChecking)
@@ -95,3 +116,19 @@ func FilterCheckingItems(ignoredItems []string) map[string]string {
 
 	return checkingItems
 }
+
+// IsOptInCheckingItemEnabled returns whether the opt-in checking item is
+// enabled and not ignored.
+func IsOptInCheckingItemEnabled(item string, ignoredItems, enabledItems []string) bool {
+	for _, ignored := range ignoredItems {
+		if ignored == AllChecking || ignored == item {
+			return false
+		}
+	}
+	for _, enabled := range enabledItems {
+		if enabled == item {
+			return true
+		}
+	}
+	return false
+}
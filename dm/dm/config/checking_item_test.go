@@ -45,6 +45,8 @@ func (t *testConfig) TestCheckingItems(c *C) {
 		checkingItems[item] = desc
 	}
 	delete(checkingItems, AllChecking)
+	// the opt-in checking items are not run by default
+	delete(checkingItems, CrossTaskConflictChecking)
 
 	c.Assert(FilterCheckingItems(ignoredCheckingItems[:0]), DeepEquals, checkingItems)
 
@@ -55,3 +57,14 @@ func (t *testConfig) TestCheckingItems(c *C) {
 	delete(checkingItems, ShardAutoIncrementIDChecking)
 	c.Assert(FilterCheckingItems(ignoredCheckingItems[1:]), DeepEquals, checkingItems)
 }
+
+func (t *testConfig) TestOptInCheckingItems(c *C) {
+	c.Assert(ValidateOptInCheckingItem(CrossTaskConflictChecking), IsNil)
+	c.Assert(ValidateOptInCheckingItem(VersionChecking), NotNil)
+
+	c.Assert(IsOptInCheckingItemEnabled(CrossTaskConflictChecking, nil, nil), IsFalse)
+	enabled := []string{CrossTaskConflictChecking}
+	c.Assert(IsOptInCheckingItemEnabled(CrossTaskConflictChecking, nil, enabled), IsTrue)
+	c.Assert(IsOptInCheckingItemEnabled(CrossTaskConflictChecking, []string{AllChecking}, enabled), IsFalse)
+	c.Assert(IsOptInCheckingItemEnabled(CrossTaskConflictChecking, enabled, enabled), IsFalse)
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+)
+
+// Cases of the names of downstream schemas and tables.
+const (
+	// NameCaseKeep keeps the names after routing.
+	NameCaseKeep = "keep"
+	// NameCaseLower converts the names to lower case.
+	NameCaseLower = "lower"
+	// NameCaseAuto converts the names to lower case only when the
+	// `lower_case_table_names` of the downstream is 1.
+	NameCaseAuto = "auto"
+)
+
+// NormalizationConfig normalizes the names of schemas and tables, the charsets
+// and the collations of the upstream to fit the downstream. It's applied to
+// both DDLs and DMLs of incremental replication.
+type NormalizationConfig struct {
+	// NameCase is the case of the names of downstream schemas and tables,
+	// the default is "keep".
+	NameCase string `yaml:"name-case" toml:"name-case" json:"name-case"`
+	// CharsetMapping maps upstream charsets to downstream ones in DDLs.
+	CharsetMapping map[string]string `yaml:"charset-mapping" toml:"charset-mapping" json:"charset-mapping"`
+	// CollationMapping maps upstream collations to downstream ones in DDLs.
+	CollationMapping map[string]string `yaml:"collation-mapping" toml:"collation-mapping" json:"collation-mapping"`
+	// Overrides overrides the settings above for the matched upstream tables,
+	// only the first matched rule is used.
+	Overrides []*NormalizationRule `yaml:"overrides" toml:"overrides" json:"overrides"`
+}
+
+// NormalizationRule overrides the normalization of the upstream tables matched
+// by the patterns. Mappings are merged into the task level ones.
+type NormalizationRule struct {
+	SchemaPattern    string            `yaml:"schema-pattern" toml:"schema-pattern" json:"schema-pattern"`
+	TablePattern     string            `yaml:"table-pattern" toml:"table-pattern" json:"table-pattern"`
+	NameCase         string            `yaml:"name-case" toml:"name-case" json:"name-case"`
+	CharsetMapping   map[string]string `yaml:"charset-mapping" toml:"charset-mapping" json:"charset-mapping"`
+	CollationMapping map[string]string `yaml:"collation-mapping" toml:"collation-mapping" json:"collation-mapping"`
+}
+
+// Adjust checks and normalizes the NormalizationConfig.
+func (c *NormalizationConfig) Adjust() error {
+	if c == nil {
+		return nil
+	}
+	if c.NameCase == "" {
+		c.NameCase = NameCaseKeep
+	}
+	if err := adjustNormalization(c.NameCase, c.CharsetMapping, c.CollationMapping); err != nil {
+		return err
+	}
+	for i, rule := range c.Overrides {
+		if rule.SchemaPattern == "" {
+			return terror.ErrConfigInvalidNormalization.Generate(
+				fmt.Sprintf("schema-pattern of override %d is empty", i))
+		}
+		if err := adjustNormalization(rule.NameCase, rule.CharsetMapping, rule.CollationMapping); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func adjustNormalization(nameCase string, charsetMapping, collationMapping map[string]string) error {
+	switch nameCase {
+	case "", NameCaseKeep, NameCaseLower, NameCaseAuto:
+	default:
+		return terror.ErrConfigInvalidNormalization.Generate(fmt.Sprintf(
+			"name-case %s is not supported, valid values are %s, %s and %s",
+			nameCase, NameCaseKeep, NameCaseLower, NameCaseAuto))
+	}
+	for _, m := range []map[string]string{charsetMapping, collationMapping} {
+		for from, to := range m {
+			if from == "" || to == "" {
+				return terror.ErrConfigInvalidNormalization.Generate(
+					fmt.Sprintf("empty name in mapping %q: %q", from, to))
+			}
+			// charsets and collations are case-insensitive
+			delete(m, from)
+			m[strings.ToLower(from)] = strings.ToLower(to)
+		}
+	}
+	return nil
+}
@@ -60,6 +60,15 @@ type PurgeConfig struct {
 	RemainSpace int64 `yaml:"remain-space" toml:"remain-space" json:"remain-space"` // if remain space in @RelayBaseDir less than @RemainSpace (GB), then it can be purged
 }
 
+// RelayIOConfig is the configuration for IO of relay log files.
+type RelayIOConfig struct {
+	// drop the page cache of relay log files after they are written or read, so relay won't
+	// evict the page cache needed by other processes on the same machine, like loaders.
+	DropPageCache bool `yaml:"drop-page-cache" toml:"drop-page-cache" json:"drop-page-cache"`
+	// readahead size (bytes) when reading relay log files, 0 means using the default of OS.
+	ReadaheadSize int64 `yaml:"readahead-size" toml:"readahead-size" json:"readahead-size"`
+}
+
 // SourceConfig is the configuration for source.
 type SourceConfig struct {
 	Enable     bool `yaml:"enable" toml:"enable" json:"enable"`
@@ -75,6 +84,8 @@ type SourceConfig struct {
 	// relay synchronous starting point (if specified)
 	RelayBinLogName string `yaml:"relay-binlog-name" toml:"relay-binlog-name" json:"relay-binlog-name"`
 	RelayBinlogGTID string `yaml:"relay-binlog-gtid" toml:"relay-binlog-gtid" json:"relay-binlog-gtid"`
+	// config items for IO of relay log files
+	RelayIO RelayIOConfig `yaml:"relay-io" toml:"relay-io" json:"relay-io"`
 	// only use when worker bound source, do not marsh it
 	UUIDSuffix int `yaml:"-" toml:"-" json:"-"`
 
@@ -428,6 +439,7 @@ type SourceConfigForDowngrade struct {
 	// any new config item, we mark it omitempty
 	CaseSensitive bool                  `yaml:"case-sensitive,omitempty"`
 	Filters       []*bf.BinlogEventRule `yaml:"filters,omitempty"`
+	RelayIO       RelayIOConfig         `yaml:"relay-io,omitempty"`
 }
 
 // NewSourceConfigForDowngrade creates a new base config for downgrade.
@@ -451,6 +463,7 @@ func NewSourceConfigForDowngrade(sourceCfg *SourceConfig) *SourceConfigForDowngr
 		Tracer:          sourceCfg.Tracer,
 		CaseSensitive:   sourceCfg.CaseSensitive,
 		Filters:         sourceCfg.Filters,
+		RelayIO:         sourceCfg.RelayIO,
 	}
 }
 
@@ -205,10 +205,14 @@ type SubTaskConfig struct {
 	// "strict" will add default collation as upstream, and downstream will occur error when downstream don't support
 	CollationCompatible string `yaml:"collation_compatible" toml:"collation_compatible" json:"collation_compatible"`
 
+	// normalizes names, charsets and collations between upstream and downstream
+	Normalization *NormalizationConfig `yaml:"normalization" toml:"normalization" json:"normalization"`
+
 	Name string `toml:"name" json:"name"`
 	Mode string `toml:"mode" json:"mode"`
 	//  treat it as hidden configuration
 	IgnoreCheckingItems []string `toml:"ignore-checking-items" json:"ignore-checking-items"`
+	EnableCheckingItems []string `toml:"enable-checking-items" json:"enable-checking-items"`
 	// it represents a MySQL/MariaDB instance or a replica group
 	SourceID   string `toml:"source-id" json:"source-id"`
 	ServerID   uint32 `toml:"server-id" json:"server-id"`
@@ -435,6 +439,9 @@ func (c *SubTaskConfig) Adjust(verifyDecryptPassword bool) error {
 	if c.SyncerConfig.CheckpointFlushInterval == 0 {
 		c.SyncerConfig.CheckpointFlushInterval = defaultCheckpointFlushInterval
 	}
+	if _, err := c.SyncerConfig.RelaxedOrderFilter(c.CaseSensitive); err != nil {
+		return err
+	}
 
 	c.From.AdjustWithTimeZone(c.Timezone)
 	c.To.AdjustWithTimeZone(c.Timezone)
@@ -474,6 +481,9 @@ func (c *SubTaskConfig) Adjust(verifyDecryptPassword bool) error {
 	if err := c.ValidatorCfg.Adjust(); err != nil {
 		return err
 	}
+	if err := c.Normalization.Adjust(); err != nil {
+		return err
+	}
 
 	// TODO: check every member
 	// TODO: since we checked here, we could remove other terror like ErrSyncerUnitGenBAList
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb-tools/pkg/column-mapping"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/util/filter"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	router "github.com/pingcap/tidb/util/table-router"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
@@ -251,6 +252,9 @@ type LoaderConfig struct {
 	SQLMode     string               `yaml:"-" toml:"-" json:"-"` // wrote by dump unit
 	ImportMode  LoadMode             `yaml:"import-mode" toml:"import-mode" json:"import-mode"`
 	OnDuplicate DuplicateResolveType `yaml:"on-duplicate" toml:"on-duplicate" json:"on-duplicate"`
+	// Priority is used when DM-master limits how many subtasks run the load unit concurrently,
+	// the subtasks with higher priority run first.
+	Priority int `yaml:"priority" toml:"priority" json:"priority"`
 }
 
 // DefaultLoaderConfig return default loader config for task.
@@ -306,6 +310,9 @@ type SyncerConfig struct {
 	// TODO: add this two new config items for openapi.
 	Compact      bool `yaml:"compact" toml:"compact" json:"compact"`
 	MultipleRows bool `yaml:"multiple-rows" toml:"multiple-rows" json:"multiple-rows"`
+	// RelaxedOrderTables are the table-filter rules of source tables whose rows have no
+	// cross-row constraints, rows of them are applied out of order except the changes of the same row.
+	RelaxedOrderTables []string `yaml:"relaxed-order-tables" toml:"relaxed-order-tables" json:"relaxed-order-tables"`
 
 	// deprecated
 	MaxRetry int `yaml:"max-retry" toml:"max-retry" json:"max-retry"`
@@ -330,6 +337,22 @@ func DefaultSyncerConfig() SyncerConfig {
 	}
 }
 
+// RelaxedOrderFilter returns the filter of source tables whose rows can be applied
+// out of order, it returns nil if no table is configured.
+func (m *SyncerConfig) RelaxedOrderFilter(caseSensitive bool) (tfilter.Filter, error) {
+	if len(m.RelaxedOrderTables) == 0 {
+		return nil, nil
+	}
+	f, err := tfilter.Parse(m.RelaxedOrderTables)
+	if err != nil {
+		return nil, terror.ErrConfigInvalidRelaxedOrderTables.Delegate(err)
+	}
+	if !caseSensitive {
+		f = tfilter.CaseInsensitive(f)
+	}
+	return f, nil
+}
+
 // alias to avoid infinite recursion for UnmarshalYAML.
 type rawSyncerConfig SyncerConfig
 
@@ -389,6 +412,8 @@ type TaskConfig struct {
 	ShardMode  string `yaml:"shard-mode" toml:"shard-mode" json:"shard-mode"` // when `shard-mode` set, we always enable sharding support.
 	// treat it as hidden configuration
 	IgnoreCheckingItems []string `yaml:"ignore-checking-items" toml:"ignore-checking-items" json:"ignore-checking-items"`
+	// the checking items which are not run by default, e.g. cross_task_conflict
+	EnableCheckingItems []string `yaml:"enable-checking-items" toml:"enable-checking-items" json:"enable-checking-items"`
 	// we store detail status in meta
 	// don't save configuration into it
 	MetaSchema string `yaml:"meta-schema" toml:"meta-schema" json:"meta-schema"`
@@ -408,6 +433,9 @@ type TaskConfig struct {
 	// "strict" will add default collation as upstream, and downstream will occur error when downstream don't support
 	CollationCompatible string `yaml:"collation_compatible" toml:"collation_compatible" json:"collation_compatible"`
 
+	// normalizes names, charsets and collations between upstream and downstream
+	Normalization *NormalizationConfig `yaml:"normalization" toml:"normalization" json:"normalization"`
+
 	TargetDB *DBConfig `yaml:"target-database" toml:"target-database" json:"target-database"`
 
 	MySQLInstances []*MySQLInstance `yaml:"mysql-instances" toml:"mysql-instances" json:"mysql-instances"`
@@ -566,11 +594,20 @@ func (c *TaskConfig) adjust() error {
 		c.CollationCompatible = LooseCollationCompatible
 	}
 
+	if err := c.Normalization.Adjust(); err != nil {
+		return err
+	}
+
 	for _, item := range c.IgnoreCheckingItems {
 		if err := ValidateCheckingItem(item); err != nil {
 			return err
 		}
 	}
+	for _, item := range c.EnableCheckingItems {
+		if err := ValidateOptInCheckingItem(item); err != nil {
+			return err
+		}
+	}
 
 	if c.OnlineDDLScheme != "" && c.OnlineDDLScheme != PT && c.OnlineDDLScheme != GHOST {
 		return terror.ErrConfigOnlineSchemeNotSupport.Generate(c.OnlineDDLScheme)
@@ -20,6 +20,7 @@ import (
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	"github.com/pingcap/tidb-tools/pkg/column-mapping"
 	"github.com/pingcap/tidb/util/filter"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	router "github.com/pingcap/tidb/util/table-router"
 	"go.uber.org/zap"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/storage"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
+	cdcconfig "github.com/pingcap/tiflow/pkg/config"
 )
 
 // TaskConfigToSubTaskConfigs generates sub task configs by TaskConfig.
@@ -45,6 +47,7 @@ func TaskConfigToSubTaskConfigs(c *TaskConfig, sources map[string]DBConfig) ([]*
 		cfg.TrashTableRules = c.TrashTableRules
 		cfg.ShadowTableRules = c.ShadowTableRules
 		cfg.IgnoreCheckingItems = c.IgnoreCheckingItems
+		cfg.EnableCheckingItems = c.EnableCheckingItems
 		cfg.Name = c.Name
 		cfg.Mode = c.TaskMode
 		cfg.CaseSensitive = c.CaseSensitive
@@ -55,6 +58,7 @@ func TaskConfigToSubTaskConfigs(c *TaskConfig, sources map[string]DBConfig) ([]*
 		cfg.Timezone = c.Timezone
 		cfg.Meta = inst.Meta
 		cfg.CollationCompatible = c.CollationCompatible
+		cfg.Normalization = c.Normalization
 		cfg.Experimental = c.Experimental
 
 		fromClone := dbCfg.Clone()
@@ -309,6 +313,7 @@ func SubTaskConfigsToTaskConfig(stCfgs ...*SubTaskConfig) *TaskConfig {
 	c.IsSharding = stCfg0.IsSharding
 	c.ShardMode = stCfg0.ShardMode
 	c.IgnoreCheckingItems = stCfg0.IgnoreCheckingItems
+	c.EnableCheckingItems = stCfg0.EnableCheckingItems
 	c.MetaSchema = stCfg0.MetaSchema
 	c.EnableHeartbeat = stCfg0.EnableHeartbeat
 	c.HeartbeatUpdateInterval = stCfg0.HeartbeatUpdateInterval
@@ -320,6 +325,7 @@ func SubTaskConfigsToTaskConfig(stCfgs ...*SubTaskConfig) *TaskConfig {
 	c.OnlineDDLScheme = stCfg0.OnlineDDLScheme
 	c.CleanDumpFile = stCfg0.CleanDumpFile
 	c.CollationCompatible = stCfg0.CollationCompatible
+	c.Normalization = stCfg0.Normalization
 	c.MySQLInstances = make([]*MySQLInstance, 0, len(stCfgs))
 	c.BAList = make(map[string]*filter.Rules)
 	c.Routes = make(map[string]*router.TableRule)
@@ -684,3 +690,372 @@ func genFilterRuleName(sourceName string, idx int) string {
 	// NOTE that we don't have user input filter rule name in sub task config, so we make one by ourself
 	return fmt.Sprintf("%s-filter-rule-%d", sourceName, idx)
 }
+
+// SubTaskConfigToChangefeedConfig generates the replica config of a TiCDC
+// changefeed which replicates the tables migrated by the subtask from the
+// downstream TiDB back to the source, e.g. to fall back after the cutover.
+// The block-allow list and the routes are applied to the downstream tables
+// inversely, so the routes must not merge tables, and the names in the
+// block-allow list matching the routed tables must be literal. The rules of
+// the subtask which can't be converted are returned as warnings.
+func SubTaskConfigToChangefeedConfig(stCfg *SubTaskConfig) (*cdcconfig.ReplicaConfig, []string, error) {
+	if stCfg.IsSharding {
+		return nil, nil, terror.ErrConfigReverseChangefeedNotSupport.Generate(stCfg.SourceID,
+			"the tables are merged by the sharding task")
+	}
+	routes, err := newReverseRoutes(stCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := cdcconfig.GetDefaultReplicaConfig()
+	cfg.CaseSensitive = stCfg.CaseSensitive
+	cfg.Sink.Routes = routes.inverse()
+	// the checkpoints of DM in the downstream must not be replicated back.
+	if stCfg.BAList != nil {
+		rules, err := routes.routeBAList(stCfg.BAList)
+		if err != nil {
+			return nil, nil, err
+		}
+		rules.IgnoreDBs = append(rules.IgnoreDBs, stCfg.MetaSchema)
+		cfg.Filter.Rules = nil
+		cfg.Filter.MySQLReplicationRules = rules
+	} else {
+		cfg.Filter.Rules = []string{"*.*", "!" + escapeTableFilterPattern(stCfg.MetaSchema) + ".*"}
+	}
+
+	var warnings []string
+	for _, rule := range stCfg.FilterRules {
+		eventFilter, warning := binlogEventRuleToEventFilter(rule)
+		if eventFilter != nil {
+			cfg.Filter.EventFilters = append(cfg.Filter.EventFilters, eventFilter)
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	if len(stCfg.ColumnMappingRules) > 0 {
+		warnings = append(warnings, "column mapping rules are ignored")
+	}
+	if len(stCfg.ExprFilter) > 0 {
+		warnings = append(warnings, "expression filters are ignored")
+	}
+	return cfg, warnings, nil
+}
+
+// reverseRoute is a route of the subtask which renames the tables without
+// merging them, so the downstream tables can be routed back to the source.
+type reverseRoute struct {
+	rule *router.TableRule
+	// source matches the source tables routed by the rule.
+	source tfilter.Filter
+}
+
+// reverseRoutes are the routes of a subtask changing the names of the tables,
+// the table level ones are before the schema level ones, as DM does.
+type reverseRoutes struct {
+	sourceID      string
+	caseSensitive bool
+	routes        []*reverseRoute
+}
+
+// newReverseRoutes checks the routes of the subtask can be inverted. A route
+// can be inverted if it routes a schema or a table of literal names, or the
+// tables of a literal schema to another schema without renaming them.
+func newReverseRoutes(stCfg *SubTaskConfig) (*reverseRoutes, error) {
+	r := &reverseRoutes{sourceID: stCfg.SourceID, caseSensitive: stCfg.CaseSensitive}
+	var schemaRoutes []*reverseRoute
+	targets := make(map[string]*router.TableRule)
+	for _, rule := range stCfg.RouteRules {
+		if isIdentityRoute(rule, stCfg.CaseSensitive) {
+			continue
+		}
+		notSupport := func(reason string) error {
+			return terror.ErrConfigReverseChangefeedNotSupport.Generate(stCfg.SourceID,
+				fmt.Sprintf("route %s.%s to %s.%s %s",
+					rule.SchemaPattern, rule.TablePattern, rule.TargetSchema, rule.TargetTable, reason))
+		}
+		if rule.TableExtractor != nil || rule.SchemaExtractor != nil || rule.SourceExtractor != nil {
+			return nil, notSupport("extracts the names of the tables")
+		}
+		if isWildcardPattern(rule.SchemaPattern) {
+			return nil, notSupport("may merge the tables of several schemas")
+		}
+		if rule.TablePattern != "" && isWildcardPattern(rule.TablePattern) && rule.TargetTable != "" {
+			return nil, notSupport("may merge several tables")
+		}
+
+		target := rule.TargetSchema
+		pattern := escapeTableFilterPattern(rule.SchemaPattern) + ".*"
+		if rule.TablePattern != "" {
+			target += "." + rule.TablePattern
+			if rule.TargetTable != "" {
+				target = rule.TargetSchema + "." + rule.TargetTable
+			}
+			pattern = escapeTableFilterPattern(rule.SchemaPattern) + "." + escapeTableFilterPattern(rule.TablePattern)
+		}
+		if !stCfg.CaseSensitive {
+			target = strings.ToLower(target)
+		}
+		if other, ok := targets[target]; ok {
+			return nil, notSupport(fmt.Sprintf("merges the tables with route %s.%s to %s.%s",
+				other.SchemaPattern, other.TablePattern, other.TargetSchema, other.TargetTable))
+		}
+		targets[target] = rule
+
+		source, err := r.newFilter(tfilter.Parse([]string{pattern}))
+		if err != nil {
+			return nil, err
+		}
+		route := &reverseRoute{rule: rule, source: source}
+		if rule.TablePattern == "" {
+			schemaRoutes = append(schemaRoutes, route)
+		} else {
+			r.routes = append(r.routes, route)
+		}
+	}
+	r.routes = append(r.routes, schemaRoutes...)
+	return r, nil
+}
+
+func (r *reverseRoutes) newFilter(f tfilter.Filter, err error) (tfilter.Filter, error) {
+	if err != nil {
+		return nil, terror.ErrConfigReverseChangefeedNotSupport.Generate(r.sourceID, err.Error())
+	}
+	if !r.caseSensitive {
+		f = tfilter.CaseInsensitive(f)
+	}
+	return f, nil
+}
+
+// inverse returns the TiCDC routes renaming the downstream tables back to the
+// source tables.
+func (r *reverseRoutes) inverse() []*cdcconfig.TableRoute {
+	if len(r.routes) == 0 {
+		return nil
+	}
+	routes := make([]*cdcconfig.TableRoute, 0, len(r.routes))
+	for _, route := range r.routes {
+		rule := route.rule
+		inverse := &cdcconfig.TableRoute{TargetSchema: rule.SchemaPattern}
+		switch {
+		case rule.TablePattern == "":
+			inverse.Matcher = []string{escapeTableFilterPattern(rule.TargetSchema) + ".*"}
+		case rule.TargetTable == "":
+			inverse.Matcher = []string{
+				escapeTableFilterPattern(rule.TargetSchema) + "." + escapeTableFilterPattern(rule.TablePattern),
+			}
+		default:
+			inverse.Matcher = []string{
+				escapeTableFilterPattern(rule.TargetSchema) + "." + escapeTableFilterPattern(rule.TargetTable),
+			}
+			inverse.TargetTable = rule.TablePattern
+		}
+		routes = append(routes, inverse)
+	}
+	return routes
+}
+
+// routeSchema returns the name of the schema in the downstream.
+func (r *reverseRoutes) routeSchema(schema string) string {
+	for _, route := range r.routes {
+		if route.rule.TablePattern == "" && route.source.MatchSchema(schema) {
+			return route.rule.TargetSchema
+		}
+	}
+	return schema
+}
+
+// routeTable returns the names of the table in the downstream.
+func (r *reverseRoutes) routeTable(schema, table string) (string, string) {
+	for _, route := range r.routes {
+		if !route.source.MatchTable(schema, table) {
+			continue
+		}
+		if route.rule.TargetTable != "" {
+			table = route.rule.TargetTable
+		}
+		return route.rule.TargetSchema, table
+	}
+	return schema, table
+}
+
+// routeBAList renames the schemas and the tables in the block-allow list to
+// the ones in the downstream. The wildcards and the regular expressions can't
+// be renamed, so they must not match the routed tables.
+func (r *reverseRoutes) routeBAList(rules *filter.Rules) (*filter.Rules, error) {
+	notSupport := func(name string) error {
+		return terror.ErrConfigReverseChangefeedNotSupport.Generate(r.sourceID,
+			fmt.Sprintf("the pattern %s of the block-allow list matches the tables renamed by the routes", name))
+	}
+	routeSchemas := func(schemas []string) ([]string, error) {
+		routed := make([]string, 0, len(schemas))
+		for _, schema := range schemas {
+			if !isLiteralBAListName(schema) {
+				f, err := r.newFilter(tfilter.ParseMySQLReplicationRules(&filter.Rules{DoDBs: []string{schema}}))
+				if err != nil {
+					return nil, err
+				}
+				for _, route := range r.routes {
+					if f.MatchSchema(route.rule.SchemaPattern) {
+						return nil, notSupport(schema)
+					}
+				}
+				routed = append(routed, schema)
+				continue
+			}
+			routed = append(routed, r.routeSchema(schema))
+		}
+		return routed, nil
+	}
+	routeTables := func(tables []*filter.Table) ([]*filter.Table, error) {
+		routed := make([]*filter.Table, 0, len(tables))
+		for _, table := range tables {
+			if !isLiteralBAListName(table.Schema) || !isLiteralBAListName(table.Name) {
+				f, err := r.newFilter(tfilter.ParseMySQLReplicationRules(&filter.Rules{DoTables: []*filter.Table{table}}))
+				if err != nil {
+					return nil, err
+				}
+				for _, route := range r.routes {
+					if f.MatchSchema(route.rule.SchemaPattern) {
+						return nil, notSupport(table.String())
+					}
+				}
+				routed = append(routed, table.Clone())
+				continue
+			}
+			schema, name := r.routeTable(table.Schema, table.Name)
+			routed = append(routed, &filter.Table{Schema: schema, Name: name})
+		}
+		return routed, nil
+	}
+
+	var (
+		routed = &filter.Rules{}
+		err    error
+	)
+	if routed.DoDBs, err = routeSchemas(rules.DoDBs); err != nil {
+		return nil, err
+	}
+	if routed.IgnoreDBs, err = routeSchemas(rules.IgnoreDBs); err != nil {
+		return nil, err
+	}
+	if routed.DoTables, err = routeTables(rules.DoTables); err != nil {
+		return nil, err
+	}
+	if routed.IgnoreTables, err = routeTables(rules.IgnoreTables); err != nil {
+		return nil, err
+	}
+
+	// the tables routed to other schemas must be allowed by the schemas of
+	// the block-allow list as their source tables.
+	sourceDBs, err := r.newFilter(tfilter.ParseMySQLReplicationRules(
+		&filter.Rules{DoDBs: rules.DoDBs, IgnoreDBs: rules.IgnoreDBs}))
+	if err != nil {
+		return nil, err
+	}
+	targetDBs, err := r.newFilter(tfilter.ParseMySQLReplicationRules(
+		&filter.Rules{DoDBs: routed.DoDBs, IgnoreDBs: routed.IgnoreDBs}))
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range r.routes {
+		rule := route.rule
+		if sourceDBs.MatchSchema(rule.SchemaPattern) != targetDBs.MatchSchema(rule.TargetSchema) {
+			return nil, terror.ErrConfigReverseChangefeedNotSupport.Generate(r.sourceID,
+				fmt.Sprintf("route %s.%s to %s.%s moves the tables across the schemas of the block-allow list",
+					rule.SchemaPattern, rule.TablePattern, rule.TargetSchema, rule.TargetTable))
+		}
+	}
+	return routed, nil
+}
+
+// isWildcardPattern returns whether the pattern of a route has wildcards.
+func isWildcardPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?")
+}
+
+// isLiteralBAListName returns whether the name in the block-allow list is
+// neither a regular expression nor a wildcard.
+func isLiteralBAListName(name string) bool {
+	return !strings.HasPrefix(name, "~") && !strings.ContainsAny(name, "*?[")
+}
+
+// isIdentityRoute returns whether the route keeps the names of the tables.
+func isIdentityRoute(rule *router.TableRule, caseSensitive bool) bool {
+	if rule.TableExtractor != nil || rule.SchemaExtractor != nil || rule.SourceExtractor != nil {
+		return false
+	}
+	equal := func(pattern, target string) bool {
+		if isWildcardPattern(pattern) {
+			return false
+		}
+		if caseSensitive {
+			return pattern == target
+		}
+		return strings.EqualFold(pattern, target)
+	}
+	if !equal(rule.SchemaPattern, rule.TargetSchema) {
+		return false
+	}
+	return rule.TargetTable == "" || equal(rule.TablePattern, rule.TargetTable)
+}
+
+// binlogEventRuleToEventFilter converts the DML events ignored by the binlog
+// event filter rule to a TiCDC event filter.
+func binlogEventRuleToEventFilter(rule *bf.BinlogEventRule) (*cdcconfig.EventFilterRule, string) {
+	name := rule.SchemaPattern
+	if rule.TablePattern != "" {
+		name += "." + rule.TablePattern
+	}
+	if rule.Action != bf.Ignore {
+		return nil, fmt.Sprintf("binlog event filter of %s with action %s is ignored", name, rule.Action)
+	}
+	if len(rule.SQLPattern) > 0 {
+		return nil, fmt.Sprintf("binlog event filter of %s with sql-pattern is ignored", name)
+	}
+	var events []string
+	var ignored []string
+	for _, event := range rule.Events {
+		switch e := bf.EventType(strings.ToLower(string(event))); e {
+		case bf.InsertEvent, bf.UpdateEvent, bf.DeleteEvent:
+			events = append(events, string(e))
+		case bf.AllDML:
+			events = append(events, string(bf.InsertEvent), string(bf.UpdateEvent), string(bf.DeleteEvent))
+		case bf.AllEvent:
+			events = append(events, string(bf.InsertEvent), string(bf.UpdateEvent), string(bf.DeleteEvent))
+			ignored = append(ignored, string(bf.AllDDL))
+		default:
+			ignored = append(ignored, string(event))
+		}
+	}
+	var warning string
+	if len(ignored) > 0 {
+		warning = fmt.Sprintf("binlog event filter of %s for events %s is ignored", name, strings.Join(ignored, ", "))
+	}
+	if len(events) == 0 {
+		return nil, warning
+	}
+	matcher := escapeTableFilterPattern(rule.SchemaPattern) + ".*"
+	if rule.TablePattern != "" {
+		matcher = escapeTableFilterPattern(rule.SchemaPattern) + "." + escapeTableFilterPattern(rule.TablePattern)
+	}
+	return &cdcconfig.EventFilterRule{
+		Matcher:     []string{matcher},
+		IgnoreEvent: removeDuplication(events),
+	}, warning
+}
+
+// escapeTableFilterPattern escapes the special characters of the table filter
+// syntax in a wildcard pattern, except the wildcards.
+func escapeTableFilterPattern(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		if !(r == '*' || r == '?' || r == '_' || r == '$' || r >= 0x80 ||
+			'0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
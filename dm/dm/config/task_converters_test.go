@@ -19,11 +19,14 @@ import (
 	"github.com/pingcap/check"
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	"github.com/pingcap/tidb/util/filter"
+	router "github.com/pingcap/tidb/util/table-router"
 	"github.com/stretchr/testify/require"
 
 	"github.com/pingcap/tiflow/dm/openapi"
 	"github.com/pingcap/tiflow/dm/openapi/fixtures"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
+	cdcconfig "github.com/pingcap/tiflow/pkg/config"
+	cdcfilter "github.com/pingcap/tiflow/pkg/filter"
 )
 
 func (t *testConfig) TestTaskGetTargetDBCfg(c *check.C) {
@@ -589,3 +592,131 @@ func TestConvertBetweenOpenAPITaskAndTaskConfig(t *testing.T) {
 		require.EqualValues(t, taskAfterConvert, &task)
 	}
 }
+
+func TestSubTaskConfigToChangefeedConfig(t *testing.T) {
+	t.Parallel()
+
+	stCfg := &SubTaskConfig{
+		SourceID:   "mysql-replica-01",
+		MetaSchema: "dm_meta",
+		BAList: &filter.Rules{
+			DoDBs:        []string{"~^db_\\d+$"},
+			IgnoreTables: []*filter.Table{{Schema: "db_1", Name: "log"}},
+		},
+		RouteRules: []*router.TableRule{
+			{SchemaPattern: "db_1", TablePattern: "t_*", TargetSchema: "DB_1"},
+		},
+		FilterRules: []*bf.BinlogEventRule{
+			{SchemaPattern: "db_*", TablePattern: "t-?", Events: []bf.EventType{bf.DeleteEvent, bf.AllDML}, Action: bf.Ignore},
+			{SchemaPattern: "db_2", Events: []bf.EventType{bf.AllEvent}, Action: bf.Ignore},
+			{SchemaPattern: "db_3", Events: []bf.EventType{bf.AllDDL}, Action: bf.Ignore},
+			{SchemaPattern: "db_4", Events: []bf.EventType{bf.InsertEvent}, Action: bf.Do},
+		},
+	}
+	cfg, warnings, err := SubTaskConfigToChangefeedConfig(stCfg)
+	require.NoError(t, err)
+	require.Equal(t, []*cdcconfig.EventFilterRule{
+		{Matcher: []string{"db_*.t\\-?"}, IgnoreEvent: []string{"delete", "insert", "update"}},
+		{Matcher: []string{"db_2.*"}, IgnoreEvent: []string{"insert", "update", "delete"}},
+	}, cfg.Filter.EventFilters)
+	require.Equal(t, []string{
+		"binlog event filter of db_2 for events all ddl is ignored",
+		"binlog event filter of db_3 for events all ddl is ignored",
+		"binlog event filter of db_4 with action Do is ignored",
+	}, warnings)
+	// the block-allow list of the subtask is not changed.
+	require.Empty(t, stCfg.BAList.IgnoreDBs)
+
+	f, err := cdcfilter.VerifyRules(cfg)
+	require.NoError(t, err)
+	require.True(t, f.MatchTable("db_1", "t_1"))
+	require.True(t, f.MatchTable("db_2", "t_1"))
+	require.False(t, f.MatchTable("db_1", "log"))
+	require.False(t, f.MatchTable("db_x", "t_1"))
+	require.False(t, f.MatchTable("dm_meta", "task_syncer_checkpoint"))
+
+	// all the tables are replicated without the block-allow list.
+	stCfg.BAList = nil
+	stCfg.FilterRules = nil
+	cfg, warnings, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	f, err = cdcfilter.VerifyRules(cfg)
+	require.NoError(t, err)
+	require.True(t, f.MatchTable("db_x", "t_1"))
+	require.False(t, f.MatchTable("dm_meta", "task_syncer_checkpoint"))
+
+	// the tables renamed by the routes are routed back.
+	stCfg.CaseSensitive = true
+	cfg, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.NoError(t, err)
+	require.Equal(t, []*cdcconfig.TableRoute{
+		{Matcher: []string{"DB_1.t_*"}, TargetSchema: "db_1"},
+	}, cfg.Sink.Routes)
+	stCfg.CaseSensitive = false
+
+	// routes merging the tables can't be inverted.
+	stCfg.RouteRules = []*router.TableRule{
+		{SchemaPattern: "db_*", TablePattern: "t", TargetSchema: "db", TargetTable: "t"},
+	}
+	_, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.True(t, terror.ErrConfigReverseChangefeedNotSupport.Equal(err))
+	stCfg.RouteRules = []*router.TableRule{
+		{SchemaPattern: "db_1", TablePattern: "t_*", TargetSchema: "db", TargetTable: "t"},
+	}
+	_, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.True(t, terror.ErrConfigReverseChangefeedNotSupport.Equal(err))
+	stCfg.RouteRules = []*router.TableRule{
+		{SchemaPattern: "db_1", TargetSchema: "db"},
+		{SchemaPattern: "db_2", TargetSchema: "DB"},
+	}
+	_, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.True(t, terror.ErrConfigReverseChangefeedNotSupport.Equal(err))
+	stCfg.RouteRules = nil
+	stCfg.IsSharding = true
+	_, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.True(t, terror.ErrConfigReverseChangefeedNotSupport.Equal(err))
+}
+
+func TestSubTaskConfigToChangefeedConfigWithRoutes(t *testing.T) {
+	t.Parallel()
+
+	stCfg := &SubTaskConfig{
+		SourceID:   "mysql-replica-01",
+		MetaSchema: "dm_meta",
+		BAList: &filter.Rules{
+			DoDBs:        []string{"db_1", "db_2"},
+			IgnoreTables: []*filter.Table{{Schema: "db_1", Name: "log"}},
+		},
+		RouteRules: []*router.TableRule{
+			{SchemaPattern: "db_1", TargetSchema: "db_a"},
+			{SchemaPattern: "db_2", TablePattern: "t_1", TargetSchema: "db_2", TargetTable: "t_one"},
+		},
+	}
+	cfg, _, err := SubTaskConfigToChangefeedConfig(stCfg)
+	require.NoError(t, err)
+	// the table level routes are before the schema level ones.
+	require.Equal(t, []*cdcconfig.TableRoute{
+		{Matcher: []string{"db_2.t_one"}, TargetSchema: "db_2", TargetTable: "t_1"},
+		{Matcher: []string{"db_a.*"}, TargetSchema: "db_1"},
+	}, cfg.Sink.Routes)
+	// the block-allow list is applied to the downstream tables.
+	f, err := cdcfilter.VerifyRules(cfg)
+	require.NoError(t, err)
+	require.True(t, f.MatchTable("db_a", "t_1"))
+	require.False(t, f.MatchTable("db_a", "log"))
+	require.False(t, f.MatchTable("db_1", "t_1"))
+	require.True(t, f.MatchTable("db_2", "t_one"))
+	require.Equal(t, []string{"db_1", "db_2"}, stCfg.BAList.DoDBs)
+
+	// the regular expression can't be renamed.
+	stCfg.BAList.DoDBs = []string{"~^db_\\d+$"}
+	_, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.True(t, terror.ErrConfigReverseChangefeedNotSupport.Equal(err))
+
+	// the table is moved out of the schemas of the block-allow list.
+	stCfg.BAList.DoDBs = []string{"db_1", "db_2"}
+	stCfg.RouteRules[1].TargetSchema = "db_b"
+	_, _, err = SubTaskConfigToChangefeedConfig(stCfg)
+	require.True(t, terror.ErrConfigReverseChangefeedNotSupport.Equal(err))
+}
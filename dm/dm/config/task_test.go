@@ -1044,7 +1044,7 @@ func (t *testConfig) TestTaskConfigForDowngrade(c *C) {
 	// make sure all new field were added
 	cfgReflect := reflect.Indirect(reflect.ValueOf(cfg))
 	cfgForDowngradeReflect := reflect.Indirect(reflect.ValueOf(cfgForDowngrade))
-	c.Assert(cfgReflect.NumField(), Equals, cfgForDowngradeReflect.NumField()+4) // without flag, collation_compatible, experimental, validator
+	c.Assert(cfgReflect.NumField(), Equals, cfgForDowngradeReflect.NumField()+5) // without flag, collation_compatible, normalization, experimental, validator
 
 	// make sure all field were copied
 	cfgForClone := &TaskConfigForDowngrade{}
@@ -1123,3 +1123,29 @@ func cloneValues(dest, src reflect.Value) {
 		}
 	}
 }
+
+func (t *testConfig) TestNormalizationConfig(c *C) {
+	var cfg *NormalizationConfig
+	c.Assert(cfg.Adjust(), IsNil)
+
+	cfg = &NormalizationConfig{
+		CharsetMapping: map[string]string{"UTF8": "UTF8MB4"},
+		Overrides: []*NormalizationRule{
+			{SchemaPattern: "db*", NameCase: NameCaseLower},
+		},
+	}
+	c.Assert(cfg.Adjust(), IsNil)
+	c.Assert(cfg.NameCase, Equals, NameCaseKeep)
+	c.Assert(cfg.CharsetMapping, DeepEquals, map[string]string{"utf8": "utf8mb4"})
+
+	cfg.NameCase = "upper"
+	c.Assert(terror.ErrConfigInvalidNormalization.Equal(cfg.Adjust()), IsTrue)
+
+	cfg.NameCase = NameCaseAuto
+	cfg.CollationMapping = map[string]string{"utf8_bin": ""}
+	c.Assert(terror.ErrConfigInvalidNormalization.Equal(cfg.Adjust()), IsTrue)
+
+	cfg.CollationMapping = nil
+	cfg.Overrides = append(cfg.Overrides, &NormalizationRule{TablePattern: "tb"})
+	c.Assert(terror.ErrConfigInvalidNormalization.Equal(cfg.Adjust()), IsTrue)
+}
@@ -53,6 +53,7 @@ func NewConfigCmd() *cobra.Command {
 		newExportCfgsCmd(),
 		newImportCfgsCmd(),
 		newConfigTaskTemplateCmd(),
+		newConfigChangefeedCmd(),
 	)
 	cmd.PersistentFlags().StringP("path", "p", "", "specify the file path to export/import`")
 	return cmd
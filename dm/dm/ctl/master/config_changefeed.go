@@ -0,0 +1,220 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/dm/ctl/common"
+	"github.com/pingcap/tiflow/dm/dm/pb"
+	"github.com/pingcap/tiflow/dm/pkg/conn"
+	"github.com/pingcap/tiflow/dm/pkg/ha"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+	cdcconfig "github.com/pingcap/tiflow/pkg/config"
+)
+
+type changefeedResult struct {
+	Result      bool              `json:"result"`
+	Msg         string            `json:"msg"`
+	Changefeeds []*changefeedInfo `json:"changefeeds"`
+}
+
+type changefeedInfo struct {
+	Source     string   `json:"source"`
+	SinkURI    string   `json:"sink-uri"`
+	StartTs    uint64   `json:"start-ts"`
+	ConfigFile string   `json:"config-file"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// changefeedConfigFile is the part of the TiCDC changefeed config file
+// generated from the task, the other configs are the default ones.
+type changefeedConfigFile struct {
+	CaseSensitive bool                    `toml:"case-sensitive"`
+	Filter        *cdcconfig.FilterConfig `toml:"filter"`
+}
+
+func newConfigChangefeedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changefeed <task-name> [--start-ts ts]",
+		Short: "generate the TiCDC changefeed configs to replicate the tables of a task back to the sources",
+		RunE:  configChangefeedFunc,
+	}
+	cmd.Flags().Uint64("start-ts", 0, "the start ts of the changefeeds, default is the current TSO of the downstream once all the subtasks are synced")
+	return cmd
+}
+
+// configChangefeedFunc generates a TiCDC changefeed config file for each
+// source of the task into the directory specified by --path, the changefeeds
+// replicate the changes in the downstream TiDB back to the sources after the
+// cutover, so the applications can fall back to the sources.
+func configChangefeedFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+	taskName := args[0]
+	dir, err := cmd.Flags().GetString("path")
+	if err != nil {
+		common.PrintLinesf("can not get path")
+		return err
+	}
+	if dir == "" {
+		dir = "."
+	}
+	startTs, err := cmd.Flags().GetUint64("start-ts")
+	if err != nil {
+		common.PrintLinesf("can not get start-ts")
+		return err
+	}
+
+	subTaskCfgsMap, _, err := ha.GetAllSubTaskCfg(common.GlobalCtlClient.EtcdClient)
+	if err != nil {
+		common.PrintLinesf("can not get subtask configs from etcd")
+		return err
+	}
+	stCfgs := make([]*config.SubTaskConfig, 0, len(subTaskCfgsMap))
+	for _, subTaskCfgs := range subTaskCfgsMap {
+		if stCfg, ok := subTaskCfgs[taskName]; ok {
+			clone := stCfg
+			stCfgs = append(stCfgs, &clone)
+		}
+	}
+	if len(stCfgs) == 0 {
+		common.PrintLinesf("task %s not found", taskName)
+		return errors.New("please check output to see error")
+	}
+	sort.Slice(stCfgs, func(i, j int) bool {
+		return stCfgs[i].SourceID < stCfgs[j].SourceID
+	})
+
+	replicaCfgs := make([]*cdcconfig.ReplicaConfig, 0, len(stCfgs))
+	warnings := make([][]string, 0, len(stCfgs))
+	for _, stCfg := range stCfgs {
+		replicaCfg, warning, err2 := config.SubTaskConfigToChangefeedConfig(stCfg)
+		if err2 != nil {
+			common.PrintLinesf("can not generate the changefeed config of source %s", stCfg.SourceID)
+			return err2
+		}
+		replicaCfgs = append(replicaCfgs, replicaCfg)
+		warnings = append(warnings, warning)
+	}
+
+	if startTs == 0 {
+		startTs, err = getCutoverTs(taskName, &stCfgs[0].To)
+		if err != nil {
+			common.PrintLinesf("can not get the cutover ts of task %s", taskName)
+			return err
+		}
+	}
+
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		common.PrintLinesf("can not create directory `%s`", dir)
+		return err
+	}
+	result := &changefeedResult{
+		Result: true,
+		Msg: "fill in the passwords of the sink URIs and create the changefeeds by " +
+			"`cdc cli changefeed create --sink-uri=<sink-uri> --start-ts=<start-ts> --config=<config-file>` " +
+			"once the writes are switched to the downstream",
+	}
+	for i, stCfg := range stCfgs {
+		var buf bytes.Buffer
+		err = toml.NewEncoder(&buf).Encode(&changefeedConfigFile{
+			CaseSensitive: replicaCfgs[i].CaseSensitive,
+			Filter:        replicaCfgs[i].Filter,
+		})
+		if err != nil {
+			common.PrintLinesf("fail to marshal the changefeed config of source %s", stCfg.SourceID)
+			return err
+		}
+		file := path.Join(dir, fmt.Sprintf("%s.%s.toml", taskName, stCfg.SourceID))
+		if err = os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+			common.PrintLinesf("can not write the changefeed config to file `%s`", file)
+			return err
+		}
+		sinkURI := url.URL{
+			Scheme: "mysql",
+			User:   url.User(stCfg.From.User),
+			Host:   net.JoinHostPort(stCfg.From.Host, strconv.Itoa(stCfg.From.Port)),
+			Path:   "/",
+		}
+		result.Changefeeds = append(result.Changefeeds, &changefeedInfo{
+			Source:     stCfg.SourceID,
+			SinkURI:    sinkURI.String(),
+			StartTs:    startTs,
+			ConfigFile: file,
+			Warnings:   warnings[i],
+		})
+	}
+	common.PrettyPrintInterface(result)
+	return nil
+}
+
+// getCutoverTs gets the current TSO of the downstream as the cutover point
+// once all the subtasks of the task are synced, so the changefeeds don't
+// replicate the changes migrated from the sources back.
+func getCutoverTs(taskName string, targetDB *config.DBConfig) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), common.GlobalConfig().RPCTimeout)
+	defer cancel()
+
+	resp := &pb.QueryStatusListResponse{}
+	err := common.SendRequest(
+		ctx,
+		"QueryStatus",
+		&pb.QueryStatusListRequest{
+			Name: taskName,
+		},
+		&resp,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Result {
+		return 0, errors.New(resp.Msg)
+	}
+	for _, source := range resp.Sources {
+		if !source.Result {
+			return 0, errors.New(source.Msg)
+		}
+		for _, st := range source.SubTaskStatus {
+			if st.Unit != pb.UnitType_Sync || !st.GetSync().GetSynced() {
+				return 0, errors.Errorf("subtask %s of source %s is not synced, "+
+					"please stop writing the source and wait for the subtask to catch up, or specify --start-ts",
+					st.Name, source.GetSourceStatus().GetSource())
+			}
+		}
+	}
+
+	dbCfg := *targetDB
+	dbCfg.Password = utils.DecryptOrPlaintext(dbCfg.Password)
+	db, err := conn.DefaultDBProvider.Apply(&dbCfg)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+	return utils.GetTiDBCurrentTSO(ctx, db.DB)
+}
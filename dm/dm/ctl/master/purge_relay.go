@@ -36,7 +36,7 @@ func NewPurgeRelayCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		// Use:   "purge-relay <-w worker> [--inactive] [--time] [--filename] [--sub-dir]",
 		// Short: "purge dm-worker's relay log files, choose 1 of 2 methods",
-		Use:   "purge-relay <-s source> <-f filename> [--sub-dir directory]",
+		Use:   "purge-relay <-s source> <-f filename> [--sub-dir directory] [--dry-run]",
 		Short: "Purges relay log files of the DM-worker according to the specified filename",
 		RunE:  purgeRelayFunc,
 	}
@@ -44,6 +44,7 @@ func NewPurgeRelayCmd() *cobra.Command {
 	// cmd.Flags().StringP("time", "t", "", fmt.Sprintf("whether try to purge relay log files before this time, the format is \"%s\"(_ between date and time)", timeFormat))
 	cmd.Flags().StringP("filename", "f", "", "name of the terminal file before which to purge relay log files. Sample format: \"mysql-bin.000006\"")
 	cmd.Flags().StringP("sub-dir", "", "", "specify relay sub directory for --filename. If not specified, the latest one will be used. Sample format: \"2ae76434-f79f-11e8-bde2-0242ac130008.000001\"")
+	cmd.Flags().Bool("dry-run", false, "only list the relay log files to be purged, the tasks still using them and the reclaimed bytes, without removing any file")
 
 	return cmd
 }
@@ -93,6 +94,11 @@ func purgeRelayFunc(cmd *cobra.Command, _ []string) error {
 		fmt.Println("[warn] no --sub-dir specify for --filename, the latest one will be used")
 	}
 
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -106,6 +112,7 @@ func purgeRelayFunc(cmd *cobra.Command, _ []string) error {
 			// Time:     time2.Unix(),
 			Filename: filename,
 			SubDir:   subDir,
+			DryRun:   dryRun,
 		},
 		&resp,
 	)
@@ -86,6 +86,8 @@ func NewConfig() *Config {
 	fs.StringVar(&cfg.SSLKey, "ssl-key", "", "path of file that contains X509 key in PEM format for connection")
 	fs.Var(&cfg.CertAllowedCN, "cert-allowed-cn", "the trusted common name that allowed to visit")
 
+	fs.IntVar(&cfg.MaxConcurrentLoads, "max-concurrent-loads", 0, "how many subtasks may run the load unit concurrently in the cluster, 0 means no limit")
+
 	fs.StringVar(&cfg.V1SourcesPath, "v1-sources-path", "", "directory path used to store source config files when upgrading from v1.0.x")
 
 	return cfg
@@ -131,6 +133,10 @@ type Config struct {
 	QuotaBackendBytes       int64  `toml:"quota-backend-bytes" json:"quota-backend-bytes"`
 	OpenAPI                 bool   `toml:"openapi" json:"openapi"`
 
+	// MaxConcurrentLoads limits how many subtasks may run the load unit concurrently in the cluster,
+	// it's not limited if it's 0.
+	MaxConcurrentLoads int `toml:"max-concurrent-loads" json:"max-concurrent-loads"`
+
 	// directory path used to store source config files when upgrading from v1.0.x.
 	// if this path set, DM-master leader will try to upgrade from v1.0.x to the current version.
 	V1SourcesPath string `toml:"v1-sources-path" json:"v1-sources-path"`
@@ -281,6 +287,10 @@ func (c *Config) adjust() error {
 		log.L().Warn("invalid rpc-rate-burst, default value use", zap.Int("specified rpc-rate-burst", c.RPCRateBurst), zap.Int("default rpc-rate-burst", DefaultBurst))
 		c.RPCRateBurst = DefaultBurst
 	}
+	if c.MaxConcurrentLoads < 0 {
+		log.L().Warn("invalid max-concurrent-loads, the load quota is not limited", zap.Int("specified max-concurrent-loads", c.MaxConcurrentLoads))
+		c.MaxConcurrentLoads = 0
+	}
 
 	if c.Name == "" {
 		var hostname string
@@ -188,6 +188,12 @@ func (s *Server) startLeaderComponent(ctx context.Context) bool {
 		return false
 	}
 
+	err = s.loadQuota.Start(ctx, s.etcdClient)
+	if err != nil {
+		log.L().Error("load quota manager do not started", zap.Error(err))
+		return false
+	}
+
 	err = s.initClusterID(ctx)
 	if err != nil {
 		log.L().Error("init cluster id failed", zap.Error(err))
@@ -206,6 +212,7 @@ func (s *Server) startLeaderComponent(ctx context.Context) bool {
 }
 
 func (s *Server) retireLeader() {
+	s.loadQuota.Close()
 	s.pessimist.Close()
 	s.optimist.Close()
 	s.scheduler.Close()
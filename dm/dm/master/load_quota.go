@@ -0,0 +1,172 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tiflow/dm/pkg/ha"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+)
+
+// loadQuotaScheduleInterval is the interval of granting the load quota.
+var loadQuotaScheduleInterval = time.Second
+
+// loadQuotaManager limits how many subtasks run the load unit concurrently in the cluster, to
+// protect the shared downstream from being overwhelmed by simultaneous imports. Before running
+// the load unit, a subtask puts a load quota request in etcd and waits until it's granted, the
+// manager of the DM-master leader grants the pending requests while the granted ones are fewer
+// than the limit, in the order of priority and then the order they are put.
+type loadQuotaManager struct {
+	limit  int
+	logger log.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newLoadQuotaManager(limit int) *loadQuotaManager {
+	return &loadQuotaManager{
+		limit:  limit,
+		logger: log.With(zap.String("component", "load quota manager")),
+	}
+}
+
+// Start puts the limit of the load quota, and starts granting the load quota if it's limited.
+// The DM-workers run the load unit without waiting for the quota if it's not limited.
+func (m *loadQuotaManager) Start(pCtx context.Context, etcdCli *clientv3.Client) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := ha.PutLoadQuotaLimit(etcdCli, m.limit); err != nil {
+		return err
+	}
+	if m.limit <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(pCtx)
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx, etcdCli)
+	}()
+	m.logger.Info("the load quota manager has started", zap.Int("limit", m.limit))
+	return nil
+}
+
+func (m *loadQuotaManager) run(ctx context.Context, etcdCli *clientv3.Client) {
+	ticker := time.NewTicker(loadQuotaScheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.schedule(etcdCli); err != nil {
+				m.logger.Warn("fail to grant the load quota, will retry later", zap.Error(err))
+			}
+		}
+	}
+}
+
+// schedule revokes the grants whose requests are gone, and grants the pending requests.
+func (m *loadQuotaManager) schedule(etcdCli *clientv3.Client) error {
+	requests, _, err := ha.GetAllLoadQuotaRequests(etcdCli)
+	if err != nil {
+		return err
+	}
+	grants, _, err := ha.GetAllLoadQuotaGrants(etcdCli)
+	if err != nil {
+		return err
+	}
+
+	toGrant, toRevoke := pickLoadQuotaGrants(requests, grants, m.limit)
+	for _, req := range toRevoke {
+		if _, err = ha.DelLoadQuotaGrant(etcdCli, req.Task, req.Source); err != nil {
+			return err
+		}
+	}
+	for _, req := range toGrant {
+		if _, err = ha.PutLoadQuotaGrant(etcdCli, req.Task, req.Source, req.Worker); err != nil {
+			return err
+		}
+		m.logger.Info("grant the load quota", zap.String("task", req.Task),
+			zap.String("source", req.Source), zap.String("worker", req.Worker), zap.Int("priority", req.Priority))
+	}
+	return nil
+}
+
+// pickLoadQuotaGrants returns the requests to grant and the grants to revoke. The grants
+// without requests are revoked, and the pending requests are granted by priority while the
+// granted requests are fewer than the limit.
+func pickLoadQuotaGrants(
+	requests []ha.LoadQuotaRequest, grants map[string]map[string]string, limit int,
+) (toGrant, toRevoke []ha.LoadQuotaRequest) {
+	requested := make(map[string]map[string]struct{}, len(requests))
+	pending := make([]ha.LoadQuotaRequest, 0, len(requests))
+	granted := 0
+	for _, req := range requests {
+		if _, ok := requested[req.Task]; !ok {
+			requested[req.Task] = make(map[string]struct{})
+		}
+		requested[req.Task][req.Source] = struct{}{}
+		if _, ok := grants[req.Task][req.Source]; ok {
+			granted++
+		} else {
+			pending = append(pending, req)
+		}
+	}
+	for task, sources := range grants {
+		for source, worker := range sources {
+			if _, ok := requested[task][source]; !ok {
+				toRevoke = append(toRevoke, ha.NewLoadQuotaRequest(task, source, worker, 0))
+			}
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].Priority != pending[j].Priority {
+			return pending[i].Priority > pending[j].Priority
+		}
+		return pending[i].Revision < pending[j].Revision
+	})
+	if n := limit - granted; n > 0 {
+		if n > len(pending) {
+			n = len(pending)
+		}
+		toGrant = pending[:n]
+	}
+	return toGrant, toRevoke
+}
+
+// Close stops granting the load quota.
+func (m *loadQuotaManager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.wg.Wait()
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/dm/pkg/ha"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickLoadQuotaGrants(t *testing.T) {
+	t.Parallel()
+
+	request := func(task, source string, priority int, revision int64) ha.LoadQuotaRequest {
+		req := ha.NewLoadQuotaRequest(task, source, "worker-"+source, priority)
+		req.Revision = revision
+		return req
+	}
+	requests := []ha.LoadQuotaRequest{
+		request("task1", "source1", 0, 10),
+		request("task1", "source2", 0, 11),
+		request("task2", "source1", 0, 12),
+		request("task2", "source2", 5, 13),
+		request("task3", "source1", 5, 14),
+	}
+
+	// the requests of higher priority are granted first, then the earlier ones.
+	toGrant, toRevoke := pickLoadQuotaGrants(requests, nil, 3)
+	require.Equal(t, []ha.LoadQuotaRequest{requests[3], requests[4], requests[0]}, toGrant)
+	require.Empty(t, toRevoke)
+
+	// the granted requests occupy the quota, and the grants without requests are revoked.
+	grants := map[string]map[string]string{
+		"task1": {"source1": "worker-source1"},
+		"task4": {"source1": "worker-source1"},
+	}
+	toGrant, toRevoke = pickLoadQuotaGrants(requests, grants, 3)
+	require.Equal(t, []ha.LoadQuotaRequest{requests[3], requests[4]}, toGrant)
+	require.Equal(t, []ha.LoadQuotaRequest{ha.NewLoadQuotaRequest("task4", "source1", "worker-source1", 0)}, toRevoke)
+
+	// no quota is left.
+	toGrant, _ = pickLoadQuotaGrants(requests, grants, 1)
+	require.Empty(t, toGrant)
+	toGrant, _ = pickLoadQuotaGrants(requests, grants, 10)
+	require.Len(t, toGrant, 4)
+}
@@ -20,8 +20,14 @@ package master
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 
+	toolutils "github.com/pingcap/tidb-tools/pkg/utils"
 	"github.com/pingcap/tiflow/dm/checker"
 	"github.com/pingcap/tiflow/dm/dm/config"
 	"github.com/pingcap/tiflow/dm/dm/ctl/common"
@@ -57,6 +63,9 @@ func (s *Server) getSourceStatusListFromWorker(ctx context.Context, sourceName s
 				RelayDir:           relayStatus.RelaySubDir,
 				Stage:              relayStatus.Stage.String(),
 			}
+			if relayStatus.BinlogRowImage != "" {
+				sourceStatus.RelayStatus.BinlogRowImage = &relayStatus.BinlogRowImage
+			}
 		}
 		// add error if some error happen
 		if workerStatus.SourceStatus.Result != nil && len(workerStatus.SourceStatus.Result.Errors) > 0 {
@@ -157,6 +166,56 @@ func (s *Server) getSourceStatus(ctx context.Context, sourceName string) ([]open
 	return s.getSourceStatusListFromWorker(ctx, sourceName, true)
 }
 
+// getSourceBinlogStatistics gets the binlog event statistics of the source from the status server of its bound worker.
+func (s *Server) getSourceBinlogStatistics(ctx context.Context, sourceName string, req openapi.DMAPIGetSourceBinlogStatisticsParams) (*openapi.SourceBinlogStatistics, error) {
+	if sourceCfg := s.scheduler.GetSourceCfgByID(sourceName); sourceCfg == nil {
+		return nil, terror.ErrSchedulerSourceCfgNotExist.Generate(sourceName)
+	}
+	worker := s.scheduler.GetWorkerBySource(sourceName)
+	if worker == nil {
+		return nil, terror.ErrOpenAPICommonError.Generatef("source %s is not bound to any worker", sourceName)
+	}
+
+	scheme, client := "http", &http.Client{}
+	if len(s.cfg.SSLCA) != 0 {
+		inner, err := toolutils.ToTLSConfigWithVerify(s.cfg.SSLCA, s.cfg.SSLCert, s.cfg.SSLKey, s.cfg.CertAllowedCN)
+		if err != nil {
+			return nil, err
+		}
+		scheme, client = "https", toolutils.ClientWithTLS(inner)
+	}
+	client.Timeout = s.cfg.RPCTimeout
+
+	query := url.Values{}
+	query.Set("source", sourceName)
+	if req.Top != nil {
+		query.Set("top", strconv.Itoa(*req.Top))
+	}
+	statsURL := url.URL{Scheme: scheme, Host: worker.BaseInfo().Addr, Path: "/binlog-statistics", RawQuery: query.Encode()}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, statsURL.String(), nil)
+	if err != nil {
+		return nil, terror.ErrOpenAPICommonError.Delegate(err)
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, terror.ErrOpenAPICommonError.Delegate(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, terror.ErrOpenAPICommonError.Delegate(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, terror.ErrOpenAPICommonError.Generatef("fail to get binlog statistics of source %s from worker %s: %s",
+			sourceName, worker.BaseInfo().Name, string(body))
+	}
+	var statistics openapi.SourceBinlogStatistics
+	if err := json.Unmarshal(body, &statistics); err != nil {
+		return nil, terror.ErrOpenAPICommonError.Delegate(err)
+	}
+	return &statistics, nil
+}
+
 func (s *Server) listSource(ctx context.Context, req openapi.DMAPIGetSourceListParams) ([]openapi.Source, error) {
 	sourceCfgM := s.scheduler.GetSourceCfgs()
 	openapiSourceList := make([]openapi.Source, 0, len(sourceCfgM))
@@ -271,6 +330,38 @@ func (s *Server) purgeRelay(ctx context.Context, sourceName string, req openapi.
 	return nil
 }
 
+// importRelayBackup seeds the relay log of every relay worker of the source
+// with the binlog backup set, the backup set should be accessible to all of them.
+func (s *Server) importRelayBackup(ctx context.Context, sourceName string, req openapi.ImportRelayBackupRequest) ([]string, error) {
+	if req.BackupDir == "" {
+		return nil, terror.ErrOpenAPICommonError.Generate("backup_dir is required")
+	}
+	importReq := &workerrpc.Request{
+		Type:              workerrpc.CmdImportRelayBackup,
+		ImportRelayBackup: &pb.ImportRelayBackupRequest{Dir: req.BackupDir},
+	}
+	// NOTE not all worker that enabled relay is recorded in scheduler, we need refine this later
+	workers, err := s.scheduler.GetRelayWorkers(sourceName)
+	if err != nil {
+		return nil, err
+	}
+	if len(workers) == 0 {
+		return nil, terror.ErrOpenAPICommonError.Generatef("relay worker for source %s not found, please `enable-relay` first", sourceName)
+	}
+	var files []string
+	for _, w := range workers {
+		resp, err := w.SendRequest(ctx, importReq, s.cfg.RPCTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.ImportRelayBackup.Result {
+			return nil, terror.ErrOpenAPICommonError.Generate(resp.ImportRelayBackup.Msg)
+		}
+		files = resp.ImportRelayBackup.Files
+	}
+	return files, nil
+}
+
 func (s *Server) enableSource(ctx context.Context, sourceName string) error {
 	cfg := s.scheduler.GetSourceCfgByID(sourceName)
 	if cfg == nil {
@@ -319,7 +410,15 @@ func (s *Server) transferSource(ctx context.Context, sourceName, workerName stri
 
 func (s *Server) checkTask(ctx context.Context, subtaskCfgList []*config.SubTaskConfig, errCnt, warnCnt int64) (string, error) {
 	// TODO(ehco) no api for this task now
-	return checker.CheckSyncConfigFunc(ctx, subtaskCfgList, errCnt, warnCnt)
+	msg, err := checker.CheckSyncConfigFunc(ctx, subtaskCfgList, errCnt, warnCnt)
+	if err != nil {
+		return "", err
+	}
+	conflictMsg, err := s.checkCrossTaskConflict(ctx, subtaskCfgList)
+	if err != nil {
+		return "", err
+	}
+	return joinCheckMsg(msg, conflictMsg), nil
 }
 
 func (s *Server) checkOpenAPITaskBeforeOperate(ctx context.Context, task *openapi.Task) ([]*config.SubTaskConfig, error) {
@@ -526,19 +625,50 @@ func (s *Server) getTaskStatus(ctx context.Context, taskName string, req openapi
 				TotalTables:       dumpS.TotalTables,
 			}
 		}
-		// add error if some error happens
+		// add error and its remediation hints if some error happens
 		if subTaskStatus.Result != nil && len(subTaskStatus.Result.Errors) > 0 {
 			var errorMsgs string
+			var hints []openapi.ErrorRemediationHint
 			for _, err := range subTaskStatus.Result.Errors {
 				errorMsgs += fmt.Sprintf("%s\n", err.Message)
+				hints = append(hints, hintsToOpenAPI(terror.HintsByCode(terror.ErrCode(err.ErrCode)))...)
 			}
 			openapiSubTaskStatus.ErrorMsg = &errorMsgs
+			if len(hints) > 0 {
+				openapiSubTaskStatus.RemediationHints = &hints
+			}
 		}
 		subTaskStatusList = append(subTaskStatusList, openapiSubTaskStatus)
 	}
 	return subTaskStatusList, nil
 }
 
+func (s *Server) getTaskRepairActions(ctx context.Context, taskName string, req openapi.DMAPIGetTaskRepairActionsParams) ([]openapi.RepairAction, error) {
+	subTaskConfigM := s.scheduler.GetSubTaskCfgsByTask(taskName)
+	if subTaskConfigM == nil {
+		return nil, terror.ErrSchedulerTaskNotExist.Generate(taskName)
+	}
+	if req.SourceNameList == nil || len(*req.SourceNameList) == 0 {
+		sourceNameList := openapi.SourceNameList(s.getTaskSourceNameList(taskName))
+		req.SourceNameList = &sourceNameList
+	}
+	workerStatusList := s.getStatusFromWorkers(ctx, *req.SourceNameList, taskName, true)
+	actions := make([]openapi.RepairAction, 0)
+	for _, workerStatus := range workerStatusList {
+		if workerStatus == nil || workerStatus.SourceStatus == nil || !workerStatus.Result {
+			continue
+		}
+		subTaskCfg, ok := subTaskConfigM[workerStatus.SourceStatus.GetSource()]
+		if !ok {
+			continue
+		}
+		for _, subTaskStatus := range workerStatus.SubTaskStatus {
+			actions = append(actions, repairActions(taskName, subTaskCfg, subTaskStatus)...)
+		}
+	}
+	return actions, nil
+}
+
 func (s *Server) listTask(ctx context.Context, req openapi.DMAPIGetTaskListParams) ([]openapi.Task, error) {
 	subTaskConfigMap := s.scheduler.GetALlSubTaskCfgs()
 	taskList := config.SubTaskConfigsToOpenAPITaskList(subTaskConfigMap)
@@ -695,3 +825,18 @@ func (s *Server) convertTaskConfig(ctx context.Context, req openapi.ConverterTas
 	}
 	return task, taskCfg, nil
 }
+
+func hintsToOpenAPI(hints []terror.Hint) []openapi.ErrorRemediationHint {
+	res := make([]openapi.ErrorRemediationHint, len(hints))
+	for idx, hint := range hints {
+		res[idx] = openapi.ErrorRemediationHint{
+			Action:      string(hint.Action),
+			Description: hint.Description,
+		}
+		if hint.Target != "" {
+			target := hint.Target
+			res[idx].Target = &target
+		}
+	}
+	return res
+}
@@ -60,6 +60,7 @@ func (s *OpenAPIControllerSuite) SetupSuite() {
 	s.testTask = &task
 
 	checker.CheckSyncConfigFunc = mockCheckSyncConfig
+	checker.CheckCrossTaskConflictFunc = mockCheckCrossTaskConflict
 	checkAndAdjustSourceConfigFunc = checkAndNoAdjustSourceConfigMock
 	s.Nil(failpoint.Enable("github.com/pingcap/tiflow/dm/dm/master/MockSkipAdjustTargetDB", `return(true)`))
 	s.Nil(failpoint.Enable("github.com/pingcap/tiflow/dm/dm/master/MockSkipRemoveMetaData", `return(true)`))
@@ -68,6 +69,7 @@ func (s *OpenAPIControllerSuite) SetupSuite() {
 func (s *OpenAPIControllerSuite) TearDownSuite() {
 	checkAndAdjustSourceConfigFunc = checkAndAdjustSourceConfig
 	checker.CheckSyncConfigFunc = checker.CheckSyncConfig
+	checker.CheckCrossTaskConflictFunc = checker.CheckCrossTaskConflict
 	s.Nil(failpoint.Disable("github.com/pingcap/tiflow/dm/dm/master/MockSkipAdjustTargetDB"))
 	s.Nil(failpoint.Disable("github.com/pingcap/tiflow/dm/dm/master/MockSkipRemoveMetaData"))
 }
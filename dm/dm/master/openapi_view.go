@@ -230,6 +230,20 @@ func (s *Server) DMAPIGetSourceStatus(c *gin.Context, sourceName string) {
 	c.IndentedJSON(http.StatusOK, resp)
 }
 
+// DMAPIGetSourceBinlogStatistics url is:(GET /api/v1/sources/{source-name}/binlog-statistics).
+func (s *Server) DMAPIGetSourceBinlogStatistics(c *gin.Context, sourceName string, params openapi.DMAPIGetSourceBinlogStatisticsParams) {
+	statistics, err := s.getSourceBinlogStatistics(c.Request.Context(), sourceName, params)
+	if err != nil {
+		if terror.ErrSchedulerSourceCfgNotExist.Equal(err) {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, statistics)
+}
+
 // DMAPIUpdateSource url is:(PUT /api/v1/sources/{source-name}).
 func (s *Server) DMAPIUpdateSource(c *gin.Context, sourceName string) {
 	var req openapi.UpdateSourceRequest
@@ -359,6 +373,21 @@ func (s *Server) DMAPIDisableRelay(c *gin.Context, sourceName string) {
 	c.Status(http.StatusOK)
 }
 
+// DMAPIImportRelayBackup url is:(POST /api/v1/sources/{source-name}/relay/import).
+func (s *Server) DMAPIImportRelayBackup(c *gin.Context, sourceName string) {
+	var req openapi.ImportRelayBackupRequest
+	if err := c.Bind(&req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	files, err := s.importRelayBackup(c.Request.Context(), sourceName, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, openapi.ImportRelayBackupResponse{Files: files})
+}
+
 // DMAPIPurgeRelay url is:(POST /api/v1/relay/purge).
 func (s *Server) DMAPIPurgeRelay(c *gin.Context, sourceName string) {
 	var req openapi.PurgeRelayRequest
@@ -453,6 +482,17 @@ func (s *Server) DMAPIGetTaskStatus(c *gin.Context, taskName string, params open
 	c.IndentedJSON(http.StatusOK, resp)
 }
 
+// DMAPIGetTaskRepairActions url is:(GET /api/v1/tasks/{task-name}/repair-actions).
+func (s *Server) DMAPIGetTaskRepairActions(c *gin.Context, taskName string, params openapi.DMAPIGetTaskRepairActionsParams) {
+	actions, err := s.getTaskRepairActions(c.Request.Context(), taskName, params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := openapi.GetTaskRepairActionListResponse{Total: len(actions), Data: actions}
+	c.IndentedJSON(http.StatusOK, resp)
+}
+
 // DMAPIGetTaskList url is:(GET /api/v1/tasks).
 func (s *Server) DMAPIGetTaskList(c *gin.Context, params openapi.DMAPIGetTaskListParams) {
 	ctx := c.Request.Context()
@@ -851,6 +891,28 @@ func (s *Server) DMAPIGetClusterInfo(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, r)
 }
 
+// DMAPIGetErrorCatalog return the error code catalog url is: (GET /api/v1/cluster/errors).
+func (s *Server) DMAPIGetErrorCatalog(c *gin.Context) {
+	items := terror.Catalog()
+	data := make([]openapi.ErrorCatalogItem, len(items))
+	for idx, item := range items {
+		data[idx] = openapi.ErrorCatalogItem{
+			Code:       int(item.Code),
+			Class:      item.Class,
+			Scope:      item.Scope,
+			Level:      item.Level,
+			Message:    item.Message,
+			Workaround: item.Workaround,
+		}
+		if len(item.Hints) > 0 {
+			hints := hintsToOpenAPI(item.Hints)
+			data[idx].Hints = &hints
+		}
+	}
+	resp := &openapi.GetErrorCatalogResponse{Total: len(data), Data: data}
+	c.IndentedJSON(http.StatusOK, resp)
+}
+
 func terrorHTTPErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
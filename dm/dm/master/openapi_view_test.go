@@ -237,6 +237,13 @@ func (t *openAPISuite) TestSourceAPI(c *check.C) {
 	c.Assert(source1Status.Data[0].SourceName, check.Equals, source1.SourceName)
 	c.Assert(source1Status.Data[0].WorkerName, check.Equals, "") // no worker now
 
+	// get binlog statistics of a source not bound to any worker
+	source1BinlogStatisticsURL := fmt.Sprintf("%s/%s/binlog-statistics", baseURL, source1Name)
+	result = testutil.NewRequest().Get(source1BinlogStatisticsURL).GoWithHTTPHandler(t.testT, s.openapiHandles)
+	c.Assert(result.Code(), check.Equals, http.StatusBadRequest)
+	result = testutil.NewRequest().Get(sourceNotExistedURL+"/binlog-statistics").GoWithHTTPHandler(t.testT, s.openapiHandles)
+	c.Assert(result.Code(), check.Equals, http.StatusNotFound)
+
 	// list source
 	result = testutil.NewRequest().Get(baseURL).GoWithHTTPHandler(t.testT, s.openapiHandles)
 	// check http status code
@@ -370,6 +377,19 @@ func (t *openAPISuite) TestSourceAPI(c *check.C) {
 	result = testutil.NewRequest().Post(purgeRelay).WithJsonBody(purgeRelayReq).GoWithHTTPHandler(t.testT, s.openapiHandles)
 	c.Assert(result.Code(), check.Equals, http.StatusOK)
 
+	// import relay backup
+	importRelay := fmt.Sprintf("%s/relay/import", source1URL)
+	importRelayReq := openapi.ImportRelayBackupRequest{BackupDir: "/data/binlog-backup"}
+	mockWorkerClient = pbmock.NewMockWorkerClient(ctrl)
+	mockWorkerClient.EXPECT().ImportRelayBackup(gomock.Any(), &pb.ImportRelayBackupRequest{Dir: importRelayReq.BackupDir}).Return(
+		&pb.ImportRelayBackupResponse{Result: true, Files: []string{"mysql-bin.000001"}}, nil).MaxTimes(maxRetryNum)
+	s.scheduler.SetWorkerClientForTest(workerName1, newMockRPCClient(mockWorkerClient))
+	result = testutil.NewRequest().Post(importRelay).WithJsonBody(importRelayReq).GoWithHTTPHandler(t.testT, s.openapiHandles)
+	c.Assert(result.Code(), check.Equals, http.StatusOK)
+	var importRelayResp openapi.ImportRelayBackupResponse
+	c.Assert(result.UnmarshalBodyToObject(&importRelayResp), check.IsNil)
+	c.Assert(importRelayResp.Files, check.DeepEquals, []string{"mysql-bin.000001"})
+
 	// test disable relay
 	disableRelayURL := fmt.Sprintf("%s/relay/disable", source1URL)
 	disableRelayReq := openapi.DisableRelayRequest{}
@@ -423,9 +443,11 @@ func (t *openAPISuite) TestTaskAPI(c *check.C) {
 	c.Assert(failpoint.Enable("github.com/pingcap/tiflow/dm/dm/master/MockSkipAdjustTargetDB", `return(true)`), check.IsNil)
 	c.Assert(failpoint.Enable("github.com/pingcap/tiflow/dm/dm/master/MockSkipRemoveMetaData", `return(true)`), check.IsNil)
 	checker.CheckSyncConfigFunc = mockCheckSyncConfig
+	checker.CheckCrossTaskConflictFunc = mockCheckCrossTaskConflict
 	ctrl := gomock.NewController(c)
 	defer func() {
 		checker.CheckSyncConfigFunc = checker.CheckSyncConfig
+		checker.CheckCrossTaskConflictFunc = checker.CheckCrossTaskConflict
 		cancel()
 		s.Close()
 		ctrl.Finish()
@@ -551,6 +573,19 @@ func (t *openAPISuite) TestTaskAPI(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(resultTaskStatusWithStatus, check.DeepEquals, resultTaskStatus)
 
+	// a running task has no repair action
+	taskRepairActionsURL := fmt.Sprintf("%s/%s/repair-actions", taskURL, task.Name)
+	result = testutil.NewRequest().Get(taskRepairActionsURL).GoWithHTTPHandler(t.testT, s.openapiHandles)
+	c.Assert(result.Code(), check.Equals, http.StatusOK)
+	var resultRepairActions openapi.GetTaskRepairActionListResponse
+	err = result.UnmarshalBodyToObject(&resultRepairActions)
+	c.Assert(err, check.IsNil)
+	c.Assert(resultRepairActions.Total, check.Equals, 0)
+
+	// get repair actions of a not exist task
+	result = testutil.NewRequest().Get(fmt.Sprintf("%s/not-exist/repair-actions", taskURL)).GoWithHTTPHandler(t.testT, s.openapiHandles)
+	c.Assert(result.Code(), check.Equals, http.StatusBadRequest)
+
 	// list task with status
 	result = testutil.NewRequest().Get(taskURL+"?with_status=true").GoWithHTTPHandler(t.testT, s.openapiHandles)
 	c.Assert(result.Code(), check.Equals, http.StatusOK)
@@ -721,6 +756,17 @@ func (t *openAPISuite) TestClusterAPI(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(clusterIDResp.ClusterId, check.Greater, uint64(0))
 
+	// check error catalog
+	errorCatalogURL := baseURL + "errors"
+	resp = testutil.NewRequest().Get(errorCatalogURL).GoWithHTTPHandler(t.testT, s1.openapiHandles)
+	c.Assert(resp.Code(), check.Equals, http.StatusOK)
+	var errorCatalogResp openapi.GetErrorCatalogResponse
+	err = resp.UnmarshalBodyToObject(&errorCatalogResp)
+	c.Assert(err, check.IsNil)
+	c.Assert(errorCatalogResp.Total, check.Equals, len(terror.Catalog()))
+	c.Assert(errorCatalogResp.Data, check.HasLen, errorCatalogResp.Total)
+	c.Assert(errorCatalogResp.Data[0].Code, check.Equals, int(terror.ErrDBDriverError.Code()))
+
 	// offline master-2 with retry
 	// operate etcd cluster may met `etcdserver: unhealthy cluster`, add some retry
 	for i := 0; i < 20; i++ {
@@ -773,8 +819,10 @@ func (t *openAPISuite) TestTaskTemplatesAPI(c *check.C) {
 	s := setupTestServer(ctx, t.testT)
 	c.Assert(failpoint.Enable("github.com/pingcap/tiflow/dm/dm/master/MockSkipAdjustTargetDB", `return(true)`), check.IsNil)
 	checker.CheckSyncConfigFunc = mockCheckSyncConfig
+	checker.CheckCrossTaskConflictFunc = mockCheckCrossTaskConflict
 	defer func() {
 		checker.CheckSyncConfigFunc = checker.CheckSyncConfig
+		checker.CheckCrossTaskConflictFunc = checker.CheckCrossTaskConflict
 		cancel()
 		s.Close()
 		c.Assert(failpoint.Disable("github.com/pingcap/tiflow/dm/dm/master/MockSkipAdjustTargetDB"), check.IsNil)
@@ -1029,3 +1077,7 @@ func mockTaskQueryStatus(
 func mockCheckSyncConfig(ctx context.Context, cfgs []*config.SubTaskConfig, errCnt, warnCnt int64) (string, error) {
 	return "", nil
 }
+
+func mockCheckCrossTaskConflict(ctx context.Context, cfgs, otherCfgs []*config.SubTaskConfig) (string, error) {
+	return "", nil
+}
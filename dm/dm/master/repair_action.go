@@ -0,0 +1,168 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/dm/pb"
+	"github.com/pingcap/tiflow/dm/openapi"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+)
+
+// execFailedMsgPrefix is the message prefix of terror.ErrDBExecuteFailed, the failed statement follows it.
+const execFailedMsgPrefix = "execute statement failed: "
+
+// ddlErrorCodes are the error codes of the sync unit caused by a DDL event, they can be
+// repaired by skipping or replacing the DDL.
+var ddlErrorCodes = map[terror.ErrCode]struct{}{
+	terror.ErrSyncerParseDDL.Code():            {},
+	terror.ErrSyncerUnitHandleDDLFailed.Code(): {},
+	terror.ErrSyncerShardDDLConflict.Code():    {},
+}
+
+// repairActions enumerates the safe repair actions of a paused sub task, every action
+// comes with a preview of its effect and the equivalent dmctl commands. Only the errors
+// of the sync unit can be repaired, nil is returned for other sub tasks.
+func repairActions(taskName string, cfg *config.SubTaskConfig, st *pb.SubTaskStatus) []openapi.RepairAction {
+	if st == nil || st.Stage != pb.Stage_Paused || st.Unit != pb.UnitType_Sync ||
+		st.Result == nil || len(st.Result.Errors) == 0 {
+		return nil
+	}
+
+	source := cfg.SourceID
+	checkpoint := ""
+	if syncStatus := st.GetSync(); syncStatus != nil {
+		checkpoint = syncStatus.SyncerBinlog
+		if syncStatus.SyncerBinlogGtid != "" {
+			checkpoint = fmt.Sprintf("%s (GTID %s)", checkpoint, syncStatus.SyncerBinlogGtid)
+		}
+	}
+
+	var (
+		actions        []openapi.RepairAction
+		ddlFailed      bool
+		dmlFailed      bool
+		columnMismatch bool
+		failedDDL      string
+	)
+	for _, e := range st.Result.Errors {
+		code := terror.ErrCode(e.ErrCode)
+		switch {
+		case code == terror.ErrSyncerUnitDMLColumnNotMatch.Code():
+			columnMismatch = true
+		case code == terror.ErrDBExecuteFailed.Code():
+			if stmt, ok := failedStatement(e.Message); ok && isDDL(stmt) {
+				ddlFailed = true
+				failedDDL = stmt
+			} else if strings.Contains(e.RawCause, "Duplicate entry") {
+				dmlFailed = true
+			}
+		default:
+			if _, ok := ddlErrorCodes[code]; ok {
+				ddlFailed = true
+			}
+		}
+	}
+
+	if ddlFailed {
+		ddlDesc := "the failed DDL"
+		if failedDDL != "" {
+			ddlDesc = fmt.Sprintf("the failed DDL `%s`", failedDDL)
+		}
+		actions = append(actions,
+			openapi.RepairAction{
+				Action:      openapi.RepairActionTypeSkipEvent,
+				SourceName:  source,
+				Description: "skip the failed DDL event and resume the sub task",
+				Preview: fmt.Sprintf("%s is not executed in downstream, the downstream table schema keeps unchanged "+
+					"and the following DMLs are replicated with the schema before the DDL", ddlDesc),
+				Commands: []string{fmt.Sprintf("binlog skip %s -s %s", taskName, source)},
+			},
+			openapi.RepairAction{
+				Action:      openapi.RepairActionTypeInjectDdl,
+				SourceName:  source,
+				Description: "replace the failed DDL event with statements supported by downstream and resume the sub task",
+				Preview: fmt.Sprintf("%s is not executed in downstream, the given statements are executed instead, "+
+					"the following DMLs are replicated with the schema after the given statements", ddlDesc),
+				Commands: []string{fmt.Sprintf("binlog replace %s -s %s \"<statements>\"", taskName, source)},
+			},
+		)
+	}
+	if columnMismatch {
+		actions = append(actions, openapi.RepairAction{
+			Action:      openapi.RepairActionTypeInjectDdl,
+			SourceName:  source,
+			Description: "inject DDLs before the failed DML event to correct the table schema and resume the sub task",
+			Preview: "the given DDLs are executed in downstream before the failed DML event, " +
+				"then the DML event is replicated with the corrected schema",
+			Commands: []string{fmt.Sprintf("binlog inject %s -s %s \"<statements>\"", taskName, source)},
+		})
+	}
+	if dmlFailed && !cfg.SafeMode {
+		actions = append(actions, openapi.RepairAction{
+			Action:      openapi.RepairActionTypeToggleSafeMode,
+			SourceName:  source,
+			Description: "enable safe mode to overwrite the conflicting rows in downstream",
+			Preview: fmt.Sprintf("after restarting from checkpoint %s, INSERT is replicated as REPLACE and UPDATE is "+
+				"replicated as DELETE and REPLACE, the conflicting rows in downstream are overwritten by upstream, "+
+				"the replication becomes slower until safe mode is disabled", checkpoint),
+			// safe mode is set in the task config, the task must be restarted to apply it.
+			Commands: []string{
+				fmt.Sprintf("stop-task %s", taskName),
+				"start-task <task config file with `syncers.safe-mode: true`>",
+			},
+		})
+	}
+	actions = append(actions, openapi.RepairAction{
+		Action:      openapi.RepairActionTypeAdjustCheckpoint,
+		SourceName:  source,
+		Description: "restart the replication of the task from a specified time",
+		Preview: fmt.Sprintf("the checkpoint %s is dropped, the binlog events before the start time are not replicated "+
+			"and the events after it are replicated in safe mode for a while", checkpoint),
+		Commands: []string{
+			fmt.Sprintf("stop-task %s", taskName),
+			"start-task <task config file> --start-time \"<YYYY-MM-DD HH:MM:SS>\"",
+		},
+	})
+	if checkpoint != "" {
+		for i := range actions {
+			actions[i].Checkpoint = &checkpoint
+		}
+	}
+	return actions
+}
+
+// failedStatement extracts the failed statement from the message of terror.ErrDBExecuteFailed.
+func failedStatement(msg string) (string, bool) {
+	idx := strings.Index(msg, execFailedMsgPrefix)
+	if idx < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(msg[idx+len(execFailedMsgPrefix):]), true
+}
+
+// isDDL returns whether the statement is a DDL.
+func isDDL(stmt string) bool {
+	node, err := parser.New().ParseOneStmt(stmt, "", "")
+	if err != nil {
+		return false
+	}
+	_, ok := node.(ast.DDLNode)
+	return ok
+}
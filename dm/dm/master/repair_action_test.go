@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/dm/pb"
+	"github.com/pingcap/tiflow/dm/dm/unit"
+	"github.com/pingcap/tiflow/dm/openapi"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/stretchr/testify/require"
+)
+
+func pausedSyncSubTask(errs ...error) *pb.SubTaskStatus {
+	processErrors := make([]*pb.ProcessError, 0, len(errs))
+	for _, err := range errs {
+		processErrors = append(processErrors, unit.NewProcessError(err))
+	}
+	return &pb.SubTaskStatus{
+		Stage:  pb.Stage_Paused,
+		Unit:   pb.UnitType_Sync,
+		Result: &pb.ProcessResult{Errors: processErrors},
+		Status: &pb.SubTaskStatus_Sync{Sync: &pb.SyncStatus{SyncerBinlog: "(mysql-bin.000001, 2022)"}},
+	}
+}
+
+func actionTypes(actions []openapi.RepairAction) []openapi.RepairActionType {
+	types := make([]openapi.RepairActionType, 0, len(actions))
+	for _, action := range actions {
+		types = append(types, action.Action)
+	}
+	return types
+}
+
+func TestRepairActions(t *testing.T) {
+	t.Parallel()
+
+	taskName := "test"
+	cfg := &config.SubTaskConfig{SourceID: source1Name}
+
+	// no action for running or non-sync sub tasks
+	st := pausedSyncSubTask(terror.ErrSyncerParseDDL.Generate("create xxx"))
+	st.Stage = pb.Stage_Running
+	require.Nil(t, repairActions(taskName, cfg, st))
+	st = pausedSyncSubTask(terror.ErrSyncerParseDDL.Generate("create xxx"))
+	st.Unit = pb.UnitType_Load
+	require.Nil(t, repairActions(taskName, cfg, st))
+
+	// the failed DDL can be skipped or replaced
+	st = pausedSyncSubTask(terror.ErrDBExecuteFailed.Delegate(
+		errors.New("Error 8200: Unsupported modify column"), "ALTER TABLE `db`.`tb` MODIFY COLUMN `c` varchar(10)"))
+	actions := repairActions(taskName, cfg, st)
+	require.Equal(t, []openapi.RepairActionType{
+		openapi.RepairActionTypeSkipEvent,
+		openapi.RepairActionTypeInjectDdl,
+		openapi.RepairActionTypeAdjustCheckpoint,
+	}, actionTypes(actions))
+	require.Contains(t, actions[0].Preview, "MODIFY COLUMN")
+	require.Equal(t, []string{"binlog skip " + taskName + " -s " + source1Name}, actions[0].Commands)
+	require.Equal(t, "(mysql-bin.000001, 2022)", *actions[0].Checkpoint)
+	for _, action := range actions {
+		require.Equal(t, source1Name, action.SourceName)
+	}
+
+	// duplicate entry can be repaired by safe mode
+	st = pausedSyncSubTask(terror.ErrDBExecuteFailed.Delegate(
+		errors.New("Error 1062: Duplicate entry '1' for key 'PRIMARY'"), "INSERT INTO `db`.`tb` VALUES (1)"))
+	require.Equal(t, []openapi.RepairActionType{
+		openapi.RepairActionTypeToggleSafeMode,
+		openapi.RepairActionTypeAdjustCheckpoint,
+	}, actionTypes(repairActions(taskName, cfg, st)))
+	// safe mode is already enabled
+	require.Equal(t, []openapi.RepairActionType{
+		openapi.RepairActionTypeAdjustCheckpoint,
+	}, actionTypes(repairActions(taskName, &config.SubTaskConfig{
+		SourceID:     source1Name,
+		SyncerConfig: config.SyncerConfig{SafeMode: true},
+	}, st)))
+
+	// column mismatch can be repaired by injecting DDLs
+	st = pausedSyncSubTask(terror.ErrSyncerUnitDMLColumnNotMatch.Generate(2, 3))
+	require.Equal(t, []openapi.RepairActionType{
+		openapi.RepairActionTypeInjectDdl,
+		openapi.RepairActionTypeAdjustCheckpoint,
+	}, actionTypes(repairActions(taskName, cfg, st)))
+}
@@ -59,6 +59,7 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/terror"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
 	"github.com/pingcap/tiflow/dm/ui"
+	"github.com/pingcap/tiflow/pkg/logutil"
 )
 
 const (
@@ -114,6 +115,8 @@ type Server struct {
 	pessimist *shardddl.Pessimist
 	// shard DDL optimist
 	optimist *shardddl.Optimist
+	// load quota manager
+	loadQuota *loadQuotaManager
 
 	// agent pool
 	ap *AgentPool
@@ -135,6 +138,7 @@ func NewServer(cfg *Config) *Server {
 		cfg:       cfg,
 		scheduler: scheduler.NewScheduler(&logger, cfg.Security),
 		ap:        NewAgentPool(&RateLimitConfig{rate: cfg.RPCRateLimit, burst: cfg.RPCRateBurst}),
+		loadQuota: newLoadQuotaManager(cfg.MaxConcurrentLoads),
 	}
 	server.pessimist = shardddl.NewPessimist(&logger, server.getTaskSourceNameList)
 	server.optimist = shardddl.NewOptimist(&logger, server.scheduler.GetDownstreamMetaByTask)
@@ -196,6 +200,8 @@ func (s *Server) Start(ctx context.Context) (err error) {
 		"/apis/":  apiHandler,
 		"/status": getStatusHandle(),
 		"/debug/": getDebugHandler(),
+		// change the log level of components at runtime.
+		"/log/components": logutil.ComponentLogHandler(),
 	}
 	if s.cfg.OpenAPI {
 		if initOpenAPIErr := s.InitOpenAPIHandles(); initOpenAPIErr != nil {
@@ -494,7 +500,12 @@ func (s *Server) StartTask(ctx context.Context, req *pb.StartTaskRequest) (*pb.S
 		resp.CheckResult = terror.WithClass(err, terror.ClassDMMaster).Error()
 		return resp, nil
 	}
-	resp.CheckResult = msg
+	conflictMsg, err := s.checkCrossTaskConflict(ctx, stCfgs)
+	if err != nil {
+		resp.CheckResult = terror.WithClass(err, terror.ClassDMMaster).Error()
+		return resp, nil
+	}
+	resp.CheckResult = joinCheckMsg(msg, conflictMsg)
 
 	log.L().Info("", zap.String("task name", cfg.Name), zap.String("task", cfg.JSON()), zap.String("request", "StartTask"))
 
@@ -959,6 +970,7 @@ func (s *Server) PurgeWorkerRelay(ctx context.Context, req *pb.PurgeWorkerRelayR
 			Time:     req.Time,
 			Filename: req.Filename,
 			SubDir:   req.SubDir,
+			DryRun:   req.DryRun,
 		},
 	}
 
@@ -1298,12 +1310,47 @@ func (s *Server) CheckTask(ctx context.Context, req *pb.CheckTaskRequest) (*pb.C
 		resp.Msg = terror.WithClass(err, terror.ClassDMMaster).Error()
 		return resp, nil
 	}
-	resp.Msg = msg
+	conflictMsg, err := s.checkCrossTaskConflict(ctx, stCfgs)
+	if err != nil {
+		resp.Msg = terror.WithClass(err, terror.ClassDMMaster).Error()
+		return resp, nil
+	}
+	resp.Msg = joinCheckMsg(msg, conflictMsg)
 	resp.Result = true
 
 	return resp, nil
 }
 
+// checkCrossTaskConflict checks whether the sub tasks write into the same downstream tables
+// as the sub tasks of other tasks, to avoid rows being overwritten silently.
+func (s *Server) checkCrossTaskConflict(ctx context.Context, stCfgs []*config.SubTaskConfig) (string, error) {
+	if len(stCfgs) == 0 {
+		return "", nil
+	}
+	var otherCfgs []*config.SubTaskConfig
+	for taskName, sourceM := range s.scheduler.GetSubTaskCfgs() {
+		if taskName == stCfgs[0].Name {
+			continue
+		}
+		for _, cfg := range sourceM {
+			cfg := cfg
+			otherCfgs = append(otherCfgs, &cfg)
+		}
+	}
+	return checker.CheckCrossTaskConflictFunc(ctx, stCfgs, otherCfgs)
+}
+
+// joinCheckMsg joins the messages of different checks, empty messages are skipped.
+func joinCheckMsg(msgs ...string) string {
+	nonEmpty := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg != "" {
+			nonEmpty = append(nonEmpty, msg)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
+
 func parseAndAdjustSourceConfig(ctx context.Context, contents []string) ([]*config.SourceConfig, error) {
 	cfgs := make([]*config.SourceConfig, len(contents))
 	for i, content := range contents {
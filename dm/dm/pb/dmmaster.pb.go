@@ -1182,6 +1182,7 @@ type PurgeWorkerRelayRequest struct {
 	Time     int64    `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
 	Filename string   `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
 	SubDir   string   `protobuf:"bytes,5,opt,name=subDir,proto3" json:"subDir,omitempty"`
+	DryRun   bool     `protobuf:"varint,6,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 }
 
 func (m *PurgeWorkerRelayRequest) Reset()         { *m = PurgeWorkerRelayRequest{} }
@@ -1252,6 +1253,13 @@ func (m *PurgeWorkerRelayRequest) GetSubDir() string {
 	return ""
 }
 
+func (m *PurgeWorkerRelayRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type PurgeWorkerRelayResponse struct {
 	Result  bool                    `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
 	Msg     string                  `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
@@ -5505,6 +5513,16 @@ func (m *PurgeWorkerRelayRequest) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	if m.DryRun {
+		i--
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.SubDir) > 0 {
 		i -= len(m.SubDir)
 		copy(dAtA[i:], m.SubDir)
@@ -7737,6 +7755,9 @@ func (m *PurgeWorkerRelayRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovDmmaster(uint64(l))
 	}
+	if m.DryRun {
+		n += 2
+	}
 	return n
 }
 
@@ -10991,6 +11012,26 @@ func (m *PurgeWorkerRelayRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.SubDir = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmmaster
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DryRun = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDmmaster(dAtA[iNdEx:])
@@ -1136,6 +1136,7 @@ type RelayStatus struct {
 	RelayCatchUpMaster bool           `protobuf:"varint,6,opt,name=relayCatchUpMaster,proto3" json:"relayCatchUpMaster,omitempty"`
 	Stage              Stage          `protobuf:"varint,7,opt,name=stage,proto3,enum=pb.Stage" json:"stage,omitempty"`
 	Result             *ProcessResult `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	BinlogRowImage     string         `protobuf:"bytes,9,opt,name=binlogRowImage,proto3" json:"binlogRowImage,omitempty"`
 }
 
 func (m *RelayStatus) Reset()         { *m = RelayStatus{} }
@@ -1227,6 +1228,13 @@ func (m *RelayStatus) GetResult() *ProcessResult {
 	return nil
 }
 
+func (m *RelayStatus) GetBinlogRowImage() string {
+	if m != nil {
+		return m.BinlogRowImage
+	}
+	return ""
+}
+
 // SubTaskStatus represents status for a sub task
 // name: sub task'name, when starting a sub task the name should be unique
 // stage: sub task's current stage
@@ -2171,6 +2179,7 @@ type PurgeRelayRequest struct {
 	Time     int64  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
 	Filename string `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
 	SubDir   string `protobuf:"bytes,4,opt,name=subDir,proto3" json:"subDir,omitempty"`
+	DryRun   bool   `protobuf:"varint,5,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 }
 
 func (m *PurgeRelayRequest) Reset()         { *m = PurgeRelayRequest{} }
@@ -2234,6 +2243,13 @@ func (m *PurgeRelayRequest) GetSubDir() string {
 	return ""
 }
 
+func (m *PurgeRelayRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type OperateWorkerSchemaRequest struct {
 	Op         SchemaOp `protobuf:"varint,1,opt,name=op,proto3,enum=pb.SchemaOp" json:"op,omitempty"`
 	Task       string   `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
@@ -3303,6 +3319,110 @@ func (m *OperateValidationErrorResponse) GetMsg() string {
 	return ""
 }
 
+type ImportRelayBackupRequest struct {
+	Dir string `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+}
+
+func (m *ImportRelayBackupRequest) Reset()         { *m = ImportRelayBackupRequest{} }
+func (m *ImportRelayBackupRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportRelayBackupRequest) ProtoMessage()    {}
+func (*ImportRelayBackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51a1b9e17fd67b10, []int{41}
+}
+func (m *ImportRelayBackupRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ImportRelayBackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ImportRelayBackupRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ImportRelayBackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRelayBackupRequest.Merge(m, src)
+}
+func (m *ImportRelayBackupRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ImportRelayBackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRelayBackupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRelayBackupRequest proto.InternalMessageInfo
+
+func (m *ImportRelayBackupRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type ImportRelayBackupResponse struct {
+	Result bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	Msg    string   `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Files  []string `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+}
+
+func (m *ImportRelayBackupResponse) Reset()         { *m = ImportRelayBackupResponse{} }
+func (m *ImportRelayBackupResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportRelayBackupResponse) ProtoMessage()    {}
+func (*ImportRelayBackupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51a1b9e17fd67b10, []int{42}
+}
+func (m *ImportRelayBackupResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ImportRelayBackupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ImportRelayBackupResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ImportRelayBackupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRelayBackupResponse.Merge(m, src)
+}
+func (m *ImportRelayBackupResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ImportRelayBackupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRelayBackupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRelayBackupResponse proto.InternalMessageInfo
+
+func (m *ImportRelayBackupResponse) GetResult() bool {
+	if m != nil {
+		return m.Result
+	}
+	return false
+}
+
+func (m *ImportRelayBackupResponse) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func (m *ImportRelayBackupResponse) GetFiles() []string {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("pb.TaskOp", TaskOp_name, TaskOp_value)
 	proto.RegisterEnum("pb.Stage", Stage_name, Stage_value)
@@ -3356,181 +3476,187 @@ func init() {
 	proto.RegisterType((*GetValidationErrorResponse)(nil), "pb.GetValidationErrorResponse")
 	proto.RegisterType((*OperateValidationErrorRequest)(nil), "pb.OperateValidationErrorRequest")
 	proto.RegisterType((*OperateValidationErrorResponse)(nil), "pb.OperateValidationErrorResponse")
+	proto.RegisterType((*ImportRelayBackupRequest)(nil), "pb.ImportRelayBackupRequest")
+	proto.RegisterType((*ImportRelayBackupResponse)(nil), "pb.ImportRelayBackupResponse")
 }
 
 func init() { proto.RegisterFile("dmworker.proto", fileDescriptor_51a1b9e17fd67b10) }
 
 var fileDescriptor_51a1b9e17fd67b10 = []byte{
-	// 2702 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4b, 0x73, 0x24, 0x47,
-	0xf1, 0x9f, 0xee, 0x79, 0x68, 0x26, 0x67, 0xa4, 0xed, 0xad, 0x95, 0xf7, 0x3f, 0x96, 0x77, 0xc7,
-	0x72, 0xaf, 0xc3, 0x7f, 0x59, 0x61, 0x14, 0xb6, 0x30, 0x61, 0xc2, 0x11, 0x80, 0xbd, 0xd2, 0x5a,
-	0xbb, 0x46, 0x6b, 0xed, 0xb6, 0xe4, 0xe5, 0x44, 0x04, 0xad, 0xee, 0x9a, 0x51, 0xa3, 0x9e, 0xee,
-	0xde, 0x7e, 0x48, 0xa1, 0x03, 0xc1, 0x8d, 0x2b, 0x5c, 0x20, 0x02, 0x02, 0x0e, 0x1c, 0x88, 0xf0,
-	0x89, 0x23, 0x1f, 0x01, 0x38, 0x3a, 0x38, 0x71, 0x24, 0xec, 0xaf, 0xc1, 0x81, 0xc8, 0xac, 0xaa,
-	0xee, 0xea, 0x79, 0xc8, 0xe8, 0xc0, 0xad, 0xf3, 0x51, 0x59, 0x59, 0x99, 0xbf, 0xcc, 0xca, 0x9a,
-	0x81, 0x35, 0x7f, 0x7a, 0x19, 0xa7, 0xe7, 0x3c, 0xdd, 0x49, 0xd2, 0x38, 0x8f, 0x99, 0x99, 0x9c,
-	0xda, 0x5b, 0xc0, 0x9e, 0x17, 0x3c, 0xbd, 0x3a, 0xce, 0xdd, 0xbc, 0xc8, 0x1c, 0xfe, 0xb2, 0xe0,
-	0x59, 0xce, 0x18, 0xb4, 0x22, 0x77, 0xca, 0x87, 0xc6, 0xa6, 0xb1, 0xd5, 0x73, 0xe8, 0xdb, 0x4e,
-	0x60, 0x7d, 0x2f, 0x9e, 0x4e, 0xe3, 0xe8, 0x47, 0x64, 0xc3, 0xe1, 0x59, 0x12, 0x47, 0x19, 0x67,
-	0x77, 0xa1, 0x93, 0xf2, 0xac, 0x08, 0x73, 0xd2, 0xee, 0x3a, 0x92, 0x62, 0x16, 0x34, 0xa7, 0xd9,
-	0x64, 0x68, 0x92, 0x09, 0xfc, 0x44, 0xcd, 0x2c, 0x2e, 0x52, 0x8f, 0x0f, 0x9b, 0xc4, 0x94, 0x14,
-	0xf2, 0x85, 0x5f, 0xc3, 0x96, 0xe0, 0x0b, 0xca, 0xfe, 0xb3, 0x01, 0x77, 0x6a, 0xce, 0xdd, 0x78,
-	0xc7, 0xf7, 0x61, 0x20, 0xf6, 0x10, 0x16, 0x68, 0xdf, 0xfe, 0xae, 0xb5, 0x93, 0x9c, 0xee, 0x1c,
-	0x6b, 0x7c, 0xa7, 0xa6, 0xc5, 0x3e, 0x80, 0xd5, 0xac, 0x38, 0x3d, 0x71, 0xb3, 0x73, 0xb9, 0xac,
-	0xb5, 0xd9, 0xdc, 0xea, 0xef, 0xde, 0xa6, 0x65, 0xba, 0xc0, 0xa9, 0xeb, 0xd9, 0x7f, 0x32, 0xa0,
-	0xbf, 0x77, 0xc6, 0x3d, 0x49, 0xa3, 0xa3, 0x89, 0x9b, 0x65, 0xdc, 0x57, 0x8e, 0x0a, 0x8a, 0xad,
-	0x43, 0x3b, 0x8f, 0x73, 0x37, 0x24, 0x57, 0xdb, 0x8e, 0x20, 0xd8, 0x08, 0x20, 0x2b, 0x3c, 0x8f,
-	0x67, 0xd9, 0xb8, 0x08, 0xc9, 0xd5, 0xb6, 0xa3, 0x71, 0xd0, 0xda, 0xd8, 0x0d, 0x42, 0xee, 0x53,
-	0x98, 0xda, 0x8e, 0xa4, 0xd8, 0x10, 0x56, 0x2e, 0xdd, 0x34, 0x0a, 0xa2, 0xc9, 0xb0, 0x4d, 0x02,
-	0x45, 0xe2, 0x0a, 0x9f, 0xe7, 0x6e, 0x10, 0x0e, 0x3b, 0x9b, 0xc6, 0xd6, 0xc0, 0x91, 0x94, 0xfd,
-	0xa5, 0x01, 0xb0, 0x5f, 0x4c, 0x13, 0xe9, 0xe6, 0x26, 0xf4, 0xc9, 0x83, 0x13, 0xf7, 0x34, 0xe4,
-	0x19, 0xf9, 0xda, 0x74, 0x74, 0x16, 0xdb, 0x82, 0x5b, 0x5e, 0x3c, 0x4d, 0x42, 0x9e, 0x73, 0x5f,
-	0x6a, 0xa1, 0xeb, 0x86, 0x33, 0xcb, 0x66, 0x6f, 0xc2, 0xea, 0x38, 0x88, 0x82, 0xec, 0x8c, 0xfb,
-	0x0f, 0xaf, 0x72, 0x2e, 0x42, 0x6e, 0x38, 0x75, 0x26, 0xb3, 0x61, 0xa0, 0x18, 0x4e, 0x7c, 0x99,
-	0xd1, 0x81, 0x0c, 0xa7, 0xc6, 0x63, 0xef, 0xc0, 0x6d, 0x9e, 0xe5, 0xc1, 0xd4, 0xcd, 0xf9, 0x09,
-	0xba, 0x42, 0x8a, 0x6d, 0x52, 0x9c, 0x17, 0xd8, 0x7f, 0x31, 0x00, 0x0e, 0x63, 0xd7, 0x97, 0x47,
-	0x9a, 0x73, 0x43, 0x1c, 0x6a, 0xc6, 0x8d, 0x11, 0x00, 0x9d, 0x52, 0xa8, 0x98, 0xa4, 0xa2, 0x71,
-	0xd8, 0x06, 0x74, 0x93, 0x34, 0x9e, 0xa4, 0x3c, 0xcb, 0x24, 0x64, 0x4b, 0x1a, 0xd7, 0x4e, 0x79,
-	0xee, 0x3e, 0x0c, 0xa2, 0x30, 0x9e, 0x48, 0xe0, 0x6a, 0x1c, 0xf6, 0x16, 0xac, 0x55, 0xd4, 0xc1,
-	0xc9, 0x93, 0x7d, 0xf2, 0xbd, 0xe7, 0xcc, 0x70, 0xed, 0x5f, 0x1b, 0xb0, 0x7a, 0x7c, 0xe6, 0xa6,
-	0x7e, 0x10, 0x4d, 0x0e, 0xd2, 0xb8, 0x48, 0x30, 0x6b, 0xb9, 0x9b, 0x4e, 0x78, 0x2e, 0xcb, 0x4f,
-	0x52, 0x58, 0x94, 0xfb, 0xfb, 0x87, 0xe8, 0x67, 0x13, 0x8b, 0x12, 0xbf, 0xc5, 0x39, 0xd3, 0x2c,
-	0x3f, 0x8c, 0x3d, 0x37, 0x0f, 0xe2, 0x48, 0xba, 0x59, 0x67, 0x52, 0xe1, 0x5d, 0x45, 0x1e, 0x21,
-	0xa7, 0x49, 0x85, 0x47, 0x14, 0x9e, 0xaf, 0x88, 0xa4, 0xa4, 0x4d, 0x92, 0x92, 0xb6, 0xff, 0xd0,
-	0x02, 0x38, 0xbe, 0x8a, 0xbc, 0x19, 0x8c, 0x3c, 0xba, 0xe0, 0x51, 0x5e, 0xc7, 0x88, 0x60, 0xa1,
-	0x31, 0x01, 0x99, 0x44, 0x85, 0xb2, 0xa4, 0xd9, 0x3d, 0xe8, 0xa5, 0xdc, 0xe3, 0x51, 0x8e, 0xc2,
-	0x26, 0x09, 0x2b, 0x06, 0xa2, 0x61, 0xea, 0x66, 0x39, 0x4f, 0x6b, 0xc1, 0xac, 0xf1, 0xd8, 0x36,
-	0x58, 0x3a, 0x7d, 0x90, 0x07, 0xbe, 0x0c, 0xe8, 0x1c, 0x1f, 0xed, 0xd1, 0x21, 0x94, 0xbd, 0x8e,
-	0xb0, 0xa7, 0xf3, 0xd0, 0x9e, 0x4e, 0x93, 0xbd, 0x15, 0x61, 0x6f, 0x96, 0x8f, 0xf6, 0x4e, 0xc3,
-	0xd8, 0x3b, 0x0f, 0xa2, 0x09, 0x25, 0xa0, 0x4b, 0xa1, 0xaa, 0xf1, 0xd8, 0xf7, 0xc0, 0x2a, 0xa2,
-	0x94, 0x67, 0x71, 0x78, 0xc1, 0x7d, 0xca, 0x63, 0x36, 0xec, 0x69, 0x6d, 0x43, 0xcf, 0xb0, 0x33,
-	0xa7, 0xaa, 0x65, 0x08, 0x44, 0xa7, 0x90, 0x19, 0x1a, 0x01, 0x9c, 0x92, 0x23, 0x27, 0x57, 0x09,
-	0x1f, 0xf6, 0x05, 0xca, 0x2a, 0x0e, 0x7b, 0x17, 0xee, 0x64, 0xdc, 0x8b, 0x23, 0x3f, 0x7b, 0xc8,
-	0xcf, 0x82, 0xc8, 0x7f, 0x4a, 0xb1, 0x18, 0x0e, 0x28, 0xc4, 0x8b, 0x44, 0x88, 0x18, 0x72, 0x7c,
-	0x7f, 0xff, 0xf0, 0xe8, 0x32, 0xe2, 0xe9, 0x70, 0x55, 0x20, 0xa6, 0xc6, 0xc4, 0x74, 0x7b, 0x71,
-	0x34, 0x0e, 0x03, 0x2f, 0x7f, 0x9a, 0x4d, 0x86, 0x6b, 0xa4, 0xa3, 0xb3, 0xec, 0xdf, 0x1b, 0x30,
-	0xd0, 0x7b, 0xa8, 0xd6, 0xdd, 0x8d, 0x25, 0xdd, 0xdd, 0xd4, 0xbb, 0x3b, 0x7b, 0xbb, 0xec, 0xe2,
-	0xa2, 0x2b, 0x53, 0x9c, 0x9e, 0xa5, 0x31, 0xb6, 0x3b, 0x87, 0x04, 0x65, 0x63, 0x7f, 0x0f, 0xfa,
-	0x29, 0x0f, 0xdd, 0xab, 0xb2, 0x1d, 0xa3, 0xfe, 0x2d, 0xd4, 0x77, 0x2a, 0xb6, 0xa3, 0xeb, 0xd8,
-	0x7f, 0x33, 0xa1, 0xaf, 0x09, 0xe7, 0x30, 0x66, 0xfc, 0x97, 0x18, 0x33, 0x97, 0x60, 0x6c, 0x53,
-	0xb9, 0x54, 0x9c, 0xee, 0x07, 0xa9, 0x2c, 0x3b, 0x9d, 0x55, 0x6a, 0xd4, 0x40, 0xad, 0xb3, 0xb0,
-	0xab, 0x6a, 0xa4, 0x06, 0xe9, 0x59, 0x36, 0xdb, 0x01, 0x46, 0xac, 0x3d, 0x37, 0xf7, 0xce, 0x3e,
-	0x4f, 0x64, 0x96, 0x3b, 0x04, 0x95, 0x05, 0x12, 0xf6, 0x3a, 0xb4, 0xb3, 0xdc, 0x9d, 0x70, 0x82,
-	0xf4, 0xda, 0x6e, 0x8f, 0x20, 0x88, 0x0c, 0x47, 0xf0, 0xb5, 0xe0, 0x77, 0xbf, 0x21, 0xf8, 0xf6,
-	0xbf, 0x4d, 0x58, 0xad, 0xdd, 0x7a, 0x8b, 0xa6, 0x83, 0x6a, 0x47, 0x73, 0xc9, 0x8e, 0x9b, 0xd0,
-	0x2a, 0xa2, 0x40, 0x24, 0x7b, 0x6d, 0x77, 0x80, 0xf2, 0xcf, 0xa3, 0x20, 0x47, 0x14, 0x3b, 0x24,
-	0xd1, 0x7c, 0x6a, 0x7d, 0x13, 0x20, 0xde, 0x85, 0x3b, 0x55, 0x09, 0xed, 0xef, 0x1f, 0x1e, 0xc6,
-	0xde, 0x79, 0xd9, 0x61, 0x17, 0x89, 0x18, 0x13, 0xb3, 0x01, 0xb5, 0x82, 0xc7, 0x0d, 0x31, 0x1d,
-	0xfc, 0x3f, 0xb4, 0x3d, 0xbc, 0xad, 0x29, 0x4a, 0x12, 0x50, 0xda, 0xf5, 0xfd, 0xb8, 0xe1, 0x08,
-	0x39, 0x7b, 0x13, 0x5a, 0x7e, 0x31, 0x4d, 0x64, 0xac, 0xd6, 0x50, 0xaf, 0xba, 0x3e, 0x1f, 0x37,
-	0x1c, 0x92, 0xa2, 0x56, 0x18, 0xbb, 0xfe, 0xb0, 0x57, 0x69, 0x55, 0x37, 0x12, 0x6a, 0xa1, 0x14,
-	0xb5, 0xb0, 0xb6, 0xa9, 0xce, 0xa5, 0x56, 0xd5, 0x66, 0x51, 0x0b, 0xa5, 0x0f, 0xbb, 0xd0, 0xc9,
-	0x04, 0x90, 0xbf, 0x0f, 0xb7, 0x6b, 0xd1, 0x3f, 0x0c, 0x32, 0x0a, 0x95, 0x10, 0x0f, 0x8d, 0x65,
-	0xa3, 0x89, 0x5a, 0x3f, 0x02, 0xa0, 0x33, 0x3d, 0x4a, 0xd3, 0x38, 0x55, 0x23, 0x92, 0x51, 0x8e,
-	0x48, 0xf6, 0x7d, 0xe8, 0xe1, 0x59, 0xae, 0x11, 0xe3, 0x21, 0x96, 0x89, 0x13, 0x18, 0x90, 0xf7,
-	0xcf, 0x0f, 0x97, 0x68, 0xb0, 0x5d, 0x58, 0x17, 0x73, 0x8a, 0x80, 0xf3, 0xb3, 0x38, 0x0b, 0xe8,
-	0xa2, 0x12, 0x85, 0xb5, 0x50, 0x86, 0x57, 0x09, 0x47, 0x73, 0xc7, 0xcf, 0x0f, 0xd5, 0xbd, 0xab,
-	0x68, 0xfb, 0x3b, 0xd0, 0xc3, 0x1d, 0xc5, 0x76, 0x5b, 0xd0, 0x21, 0x81, 0x8a, 0x83, 0x55, 0x86,
-	0x53, 0x3a, 0xe4, 0x48, 0xb9, 0xfd, 0x4b, 0x03, 0xfa, 0xa2, 0x5d, 0x89, 0x95, 0x37, 0xed, 0x56,
-	0x9b, 0xb5, 0xe5, 0xaa, 0xde, 0x75, 0x8b, 0x3b, 0x00, 0xd4, 0x70, 0x84, 0x42, 0xab, 0x4a, 0x6f,
-	0xc5, 0x75, 0x34, 0x0d, 0x4c, 0x4c, 0x45, 0x2d, 0x08, 0xed, 0x6f, 0x4d, 0x18, 0xc8, 0x94, 0x0a,
-	0x95, 0xff, 0x51, 0xd9, 0xc9, 0xca, 0x68, 0xe9, 0x95, 0xf1, 0x96, 0xaa, 0x8c, 0x76, 0x75, 0x8c,
-	0x0a, 0x45, 0x55, 0x61, 0x3c, 0x90, 0x85, 0xd1, 0x21, 0xb5, 0x55, 0x55, 0x18, 0x4a, 0x4b, 0xd4,
-	0xc5, 0x03, 0x59, 0x17, 0x2b, 0x95, 0x52, 0x09, 0xa9, 0xb2, 0x2c, 0x1e, 0xc8, 0xb2, 0xe8, 0x56,
-	0x4a, 0x65, 0x9a, 0xcb, 0xaa, 0x58, 0x81, 0x36, 0xa5, 0xd3, 0xfe, 0x10, 0x2c, 0x3d, 0x34, 0x54,
-	0x13, 0x6f, 0x49, 0x61, 0x0d, 0x0a, 0x9a, 0x92, 0x23, 0xd7, 0xbe, 0x84, 0xd5, 0x5a, 0x53, 0xc1,
-	0x3b, 0x36, 0xc8, 0xf6, 0xdc, 0xc8, 0xe3, 0x61, 0x39, 0xa9, 0x6b, 0x1c, 0x0d, 0x64, 0x66, 0x65,
-	0x59, 0x9a, 0xa8, 0x81, 0x4c, 0x9b, 0xb7, 0x9b, 0xb5, 0x79, 0xfb, 0x1f, 0x06, 0x0c, 0xf4, 0x05,
-	0x38, 0xb2, 0x3f, 0x4a, 0xd3, 0xbd, 0xd8, 0x17, 0xd9, 0x6c, 0x3b, 0x8a, 0x44, 0xe8, 0xe3, 0x67,
-	0xe8, 0x66, 0x99, 0x44, 0x60, 0x49, 0x4b, 0xd9, 0xb1, 0x17, 0x27, 0xea, 0x05, 0x55, 0xd2, 0x52,
-	0x76, 0xc8, 0x2f, 0x78, 0x28, 0xaf, 0x9a, 0x92, 0xc6, 0xdd, 0x9e, 0xf2, 0x2c, 0x43, 0x98, 0x88,
-	0x0e, 0xa9, 0x48, 0x5c, 0xe5, 0xb8, 0x97, 0x7b, 0x6e, 0x91, 0x71, 0x39, 0x25, 0x95, 0x34, 0x86,
-	0x05, 0x5f, 0x7a, 0x6e, 0x1a, 0x17, 0x91, 0x9a, 0x8d, 0x34, 0x8e, 0x7d, 0x09, 0xb7, 0x9f, 0x15,
-	0xe9, 0x84, 0x13, 0x88, 0xd5, 0xc3, 0x71, 0x03, 0xba, 0x41, 0xe4, 0x7a, 0x79, 0x70, 0xc1, 0x65,
-	0x24, 0x4b, 0x1a, 0xf1, 0x9b, 0x07, 0x53, 0x2e, 0x87, 0x43, 0xfa, 0x46, 0xfd, 0x71, 0x10, 0x72,
-	0xc2, 0xb5, 0x3c, 0x92, 0xa2, 0xa9, 0x44, 0xc5, 0xed, 0x2a, 0x9f, 0x85, 0x82, 0xb2, 0x7f, 0x67,
-	0xc2, 0xc6, 0x51, 0xc2, 0x53, 0x37, 0xe7, 0xe2, 0x29, 0x7a, 0xec, 0x9d, 0xf1, 0xa9, 0xab, 0x5c,
-	0xb8, 0x07, 0x66, 0x9c, 0xd0, 0xe6, 0x12, 0xef, 0x42, 0x7c, 0x94, 0x38, 0x66, 0x9c, 0x90, 0x13,
-	0x6e, 0x76, 0x2e, 0x63, 0x4b, 0xdf, 0x4b, 0xdf, 0xa5, 0x1b, 0xd0, 0xf5, 0xdd, 0xdc, 0x3d, 0x75,
-	0x33, 0xae, 0x62, 0xaa, 0x68, 0x7a, 0xc2, 0xe1, 0x8b, 0x47, 0x46, 0x54, 0x10, 0x64, 0x89, 0x76,
-	0x93, 0xd1, 0x94, 0x14, 0x6a, 0x8f, 0xc3, 0x22, 0x3b, 0xa3, 0x30, 0x76, 0x1d, 0x41, 0xa0, 0x2f,
-	0x25, 0xe6, 0xbb, 0x02, 0xe2, 0x18, 0xf5, 0x71, 0x1a, 0x4f, 0x45, 0x63, 0xa1, 0xab, 0xa4, 0xeb,
-	0x68, 0x1c, 0x25, 0x3f, 0x11, 0x0f, 0x04, 0xa8, 0xe4, 0x82, 0x63, 0xe7, 0xb0, 0xfa, 0xe2, 0x3d,
-	0x09, 0xfb, 0xa7, 0x3c, 0x77, 0xd9, 0x86, 0x16, 0x0e, 0xc0, 0x70, 0xa0, 0x44, 0x06, 0xe3, 0x1b,
-	0xbb, 0x87, 0x6a, 0x39, 0x4d, 0xad, 0xe5, 0xa8, 0x08, 0xb6, 0x08, 0xe2, 0xf4, 0x6d, 0xbf, 0x0f,
-	0xeb, 0x32, 0x23, 0x2f, 0xde, 0xc3, 0x5d, 0x97, 0xe6, 0x42, 0x88, 0xc5, 0xf6, 0xf6, 0x5f, 0x0d,
-	0x78, 0x65, 0x66, 0xd9, 0x8d, 0x5f, 0xf8, 0x1f, 0x40, 0x0b, 0x1f, 0x54, 0xc3, 0x26, 0x95, 0xe6,
-	0x03, 0xdc, 0x63, 0xa1, 0xc9, 0x1d, 0x24, 0x1e, 0x45, 0x79, 0x7a, 0xe5, 0xd0, 0x82, 0x8d, 0x4f,
-	0xa1, 0x57, 0xb2, 0xd0, 0xee, 0x39, 0xbf, 0x52, 0xdd, 0xf7, 0x9c, 0x5f, 0xe1, 0x6c, 0x70, 0xe1,
-	0x86, 0x85, 0x08, 0x8d, 0xbc, 0x60, 0x6b, 0x81, 0x75, 0x84, 0xfc, 0x43, 0xf3, 0xbb, 0x86, 0xfd,
-	0x33, 0x18, 0x3e, 0x76, 0x23, 0x3f, 0x94, 0x78, 0x14, 0x4d, 0x41, 0x86, 0xe0, 0x35, 0x2d, 0x04,
-	0x7d, 0xb4, 0x42, 0xd2, 0x6b, 0xd0, 0x78, 0x0f, 0x7a, 0xa7, 0xea, 0x3a, 0x94, 0x81, 0xaf, 0x18,
-	0x84, 0x99, 0x97, 0x61, 0x26, 0x1f, 0x72, 0xf4, 0x6d, 0xbf, 0x02, 0x77, 0x0e, 0x78, 0x2e, 0xf6,
-	0xde, 0x1b, 0x4f, 0xe4, 0xce, 0xf6, 0x16, 0xac, 0xd7, 0xd9, 0x32, 0xb8, 0x16, 0x34, 0xbd, 0x71,
-	0x79, 0xd5, 0x78, 0xe3, 0x89, 0x7d, 0x0c, 0xf7, 0xc5, 0xdc, 0x53, 0x9c, 0xa2, 0x0b, 0xd8, 0xfa,
-	0x3e, 0x4f, 0x7c, 0x37, 0xe7, 0xea, 0x10, 0xbb, 0xb0, 0x9e, 0x09, 0xd9, 0xde, 0x78, 0x72, 0x12,
-	0x4f, 0xc3, 0xe3, 0x3c, 0x0d, 0x22, 0x65, 0x63, 0xa1, 0xcc, 0x3e, 0x84, 0xd1, 0x32, 0xa3, 0xd2,
-	0x91, 0x21, 0xac, 0xc8, 0x9f, 0x37, 0x64, 0x9a, 0x15, 0x39, 0x9f, 0x67, 0x7b, 0x02, 0x1b, 0x07,
-	0x3c, 0x7f, 0xe1, 0x86, 0x81, 0x4f, 0x6f, 0xda, 0xfa, 0xef, 0x55, 0xf8, 0xf6, 0x74, 0xb3, 0xf3,
-	0xcf, 0xaa, 0xeb, 0xb1, 0xa4, 0xd9, 0xb7, 0x60, 0x30, 0x0e, 0xc2, 0x9c, 0xa7, 0xf2, 0xf5, 0x30,
-	0x87, 0xf5, 0x9a, 0xd8, 0xfe, 0xc2, 0x00, 0x6b, 0x76, 0x9b, 0xa5, 0xd3, 0xc2, 0x06, 0x74, 0xb3,
-	0xd4, 0xa3, 0x9f, 0x3e, 0x54, 0xb7, 0x56, 0x34, 0x75, 0x8f, 0x2c, 0x17, 0x32, 0xd9, 0xda, 0x14,
-	0x8d, 0x2f, 0x8d, 0x8b, 0x99, 0x3d, 0x64, 0x87, 0x99, 0xe3, 0x63, 0x94, 0xa6, 0xf5, 0xee, 0x2d,
-	0x49, 0xbb, 0x80, 0xd7, 0x16, 0xc6, 0xe4, 0xc6, 0x45, 0xf4, 0x4e, 0x39, 0x4e, 0x8a, 0x32, 0x5a,
-	0x27, 0xb4, 0xcf, 0xda, 0x55, 0x13, 0xe5, 0x39, 0xbc, 0x5a, 0xdb, 0xb6, 0x06, 0xf7, 0x5d, 0x1a,
-	0xdd, 0x70, 0x05, 0x97, 0xa0, 0xbf, 0xab, 0x19, 0x13, 0xa3, 0x12, 0x49, 0x9d, 0x52, 0xaf, 0x96,
-	0x3d, 0xb3, 0x9e, 0x3d, 0xfb, 0x8f, 0x26, 0xdc, 0x9a, 0xd9, 0x8a, 0xad, 0x81, 0x19, 0xf8, 0x32,
-	0x13, 0x66, 0xe0, 0x6b, 0xd9, 0x31, 0x97, 0x66, 0xa7, 0x39, 0x93, 0x1d, 0xc4, 0x5e, 0xea, 0xed,
-	0xbb, 0xb9, 0x2b, 0x03, 0xaf, 0xc8, 0x5a, 0xde, 0xda, 0x33, 0x79, 0x1b, 0xc2, 0x8a, 0x9f, 0xe5,
-	0xb4, 0x4a, 0x34, 0x78, 0x45, 0x62, 0xd5, 0xd2, 0x10, 0x40, 0xef, 0x74, 0x71, 0x59, 0x56, 0x0c,
-	0xb6, 0x53, 0x06, 0xb8, 0x7b, 0x6d, 0x4c, 0xa4, 0x56, 0x79, 0x55, 0xf6, 0x64, 0x5f, 0xc0, 0xab,
-	0x52, 0xc3, 0x01, 0xd4, 0x71, 0xf0, 0x72, 0xa6, 0x36, 0x64, 0x42, 0x6e, 0x0c, 0x83, 0xb7, 0xd5,
-	0x04, 0x25, 0x50, 0x70, 0xa7, 0x8e, 0x82, 0xda, 0x10, 0xf5, 0x1b, 0x03, 0xee, 0xab, 0x3e, 0xbb,
-	0x18, 0x08, 0x0f, 0xb4, 0xbe, 0x37, 0x6f, 0x49, 0xf6, 0x3f, 0x1a, 0xbd, 0x3e, 0x0e, 0x43, 0x31,
-	0x33, 0x9b, 0x6a, 0xf4, 0x52, 0x9c, 0x1a, 0x32, 0x9a, 0x33, 0x75, 0xbd, 0x4e, 0xde, 0x3e, 0x11,
-	0xbf, 0x86, 0xb6, 0x1c, 0x41, 0xd8, 0x9f, 0xc2, 0x68, 0x99, 0x5f, 0x37, 0x8d, 0xc7, 0xf6, 0x39,
-	0x74, 0xc4, 0x65, 0xc9, 0x56, 0xa1, 0xf7, 0x24, 0xa2, 0xca, 0x3c, 0x4a, 0xac, 0x06, 0xeb, 0x42,
-	0xeb, 0x38, 0x8f, 0x13, 0xcb, 0x60, 0x3d, 0x68, 0x3f, 0xc3, 0x69, 0xc9, 0x32, 0x19, 0x40, 0x07,
-	0x07, 0xca, 0x29, 0xb7, 0x9a, 0xc8, 0x3e, 0xce, 0xdd, 0x34, 0xb7, 0x5a, 0xc8, 0x16, 0x6d, 0xcf,
-	0x6a, 0xb3, 0x35, 0x80, 0x8f, 0x8b, 0x3c, 0x96, 0x6a, 0x1d, 0x94, 0xed, 0xf3, 0x90, 0xe7, 0xdc,
-	0x5a, 0xd9, 0xfe, 0x39, 0x2d, 0x99, 0x60, 0x7b, 0x1e, 0xc8, 0xbd, 0x88, 0xb6, 0x1a, 0x6c, 0x05,
-	0x9a, 0x9f, 0xf1, 0x4b, 0xcb, 0x60, 0x7d, 0x58, 0x71, 0x8a, 0x28, 0x0a, 0xa2, 0x89, 0xd8, 0x8f,
-	0xb6, 0xf6, 0xad, 0x26, 0x0a, 0xd0, 0xa1, 0x84, 0xfb, 0x56, 0x8b, 0x0d, 0xa0, 0xfb, 0x89, 0xfc,
-	0x99, 0xd3, 0x6a, 0xa3, 0x08, 0xd5, 0x70, 0x4d, 0x07, 0x45, 0xb4, 0x39, 0x52, 0x2b, 0x48, 0xd1,
-	0x2a, 0xa4, 0xba, 0xdb, 0x47, 0xd0, 0x55, 0x2f, 0x03, 0x76, 0x0b, 0xfa, 0xd2, 0x07, 0x64, 0x59,
-	0x0d, 0x3c, 0x10, 0x35, 0x73, 0xcb, 0xc0, 0xc3, 0xe3, 0x8c, 0x6f, 0x99, 0xf8, 0x85, 0x83, 0xbc,
-	0xd5, 0xa4, 0x80, 0x5c, 0x45, 0x9e, 0xd5, 0x42, 0x45, 0x1a, 0x08, 0x2d, 0x7f, 0xfb, 0x29, 0xac,
-	0xd0, 0xe7, 0x11, 0xde, 0x73, 0x6b, 0xd2, 0x9e, 0xe4, 0x58, 0x0d, 0x8c, 0x29, 0xee, 0x2e, 0xb4,
-	0x0d, 0x8c, 0x0d, 0x1d, 0x47, 0xd0, 0x26, 0xba, 0x20, 0xe2, 0x24, 0x18, 0xcd, 0xed, 0x5f, 0x18,
-	0xd0, 0x55, 0xa3, 0x1c, 0xbb, 0x03, 0xb7, 0x54, 0x90, 0x24, 0x4b, 0x58, 0x3c, 0xe0, 0xb9, 0x60,
-	0x58, 0x06, 0x6d, 0x50, 0x92, 0x26, 0xc6, 0xd5, 0xe1, 0xd3, 0xf8, 0x82, 0x4b, 0x4e, 0x13, 0xb7,
-	0xc4, 0x97, 0x83, 0xa4, 0x5b, 0xb8, 0x00, 0x69, 0x2a, 0x75, 0xab, 0xcd, 0xee, 0x02, 0x43, 0xf2,
-	0x69, 0x30, 0x41, 0x38, 0x89, 0xf9, 0x2a, 0xb3, 0x3a, 0xdb, 0x1f, 0x41, 0x57, 0x8d, 0x31, 0x9a,
-	0x1f, 0x8a, 0x55, 0xfa, 0x21, 0x18, 0x96, 0x51, 0x6d, 0x2c, 0x39, 0xe6, 0xf6, 0x0b, 0x1a, 0xff,
-	0x71, 0x0a, 0xd0, 0x22, 0x23, 0x39, 0x12, 0x5e, 0xe7, 0x41, 0x22, 0x13, 0xce, 0x93, 0xd0, 0xf5,
-	0x4a, 0x80, 0x5d, 0xf0, 0x34, 0xb7, 0x9a, 0xf8, 0xfd, 0x24, 0xfa, 0x29, 0xf7, 0x10, 0x61, 0x98,
-	0x86, 0x20, 0xcb, 0xad, 0xf6, 0xf6, 0x21, 0xf4, 0x25, 0xea, 0xc9, 0xf6, 0x5d, 0x60, 0xca, 0xb9,
-	0x8a, 0x6b, 0x35, 0x70, 0x4f, 0x42, 0x67, 0xc9, 0xb5, 0x0c, 0x76, 0x1b, 0x56, 0x31, 0x1b, 0x15,
-	0xcb, 0xdc, 0x7e, 0x0e, 0x6c, 0xbe, 0x45, 0x61, 0xd0, 0x2a, 0x87, 0xad, 0x06, 0x7a, 0xf2, 0x19,
-	0xbf, 0xc4, 0x6f, 0xca, 0xe1, 0x93, 0x49, 0x14, 0xa7, 0x9c, 0x64, 0x2a, 0x87, 0xf4, 0x4b, 0x0c,
-	0x32, 0x9a, 0xdb, 0x2f, 0x66, 0x9a, 0xf9, 0x51, 0xa2, 0xc1, 0x9d, 0x68, 0xab, 0x41, 0xe0, 0x23,
-	0x2b, 0x82, 0x21, 0x03, 0x48, 0x66, 0x04, 0xc7, 0xc4, 0x8d, 0xf6, 0x42, 0xee, 0xa6, 0x82, 0x6e,
-	0xee, 0x7e, 0xd1, 0x81, 0x8e, 0x18, 0x74, 0xd8, 0x47, 0xd0, 0xd7, 0xfe, 0x33, 0x62, 0xd4, 0x69,
-	0xe7, 0xff, 0xe1, 0xda, 0xf8, 0xbf, 0x39, 0xbe, 0x68, 0x0f, 0x76, 0x83, 0xfd, 0x00, 0xa0, 0x7a,
-	0xd8, 0xb0, 0x57, 0xe8, 0xb5, 0x37, 0xfb, 0xd0, 0xd9, 0x18, 0xd2, 0x93, 0x78, 0xc1, 0xff, 0x61,
-	0x76, 0x83, 0xfd, 0x10, 0x56, 0x65, 0x0f, 0x12, 0xd0, 0x62, 0x23, 0x6d, 0x2c, 0x5d, 0xf0, 0x64,
-	0xb9, 0xd6, 0xd8, 0x27, 0xa5, 0x31, 0x01, 0x1f, 0x36, 0x5c, 0x30, 0xe3, 0x0a, 0x33, 0xaf, 0x2e,
-	0x9d, 0x7e, 0xed, 0x06, 0x3b, 0x80, 0xbe, 0x98, 0x51, 0x45, 0x67, 0xbd, 0x87, 0xba, 0xcb, 0x86,
-	0xd6, 0x6b, 0x1d, 0xda, 0x83, 0x81, 0x3e, 0x56, 0x32, 0x8a, 0xe4, 0x82, 0xf9, 0x53, 0x18, 0x59,
-	0x34, 0x81, 0xda, 0x0d, 0xe6, 0xc2, 0xdd, 0xc5, 0xc3, 0x21, 0x7b, 0xa3, 0xfa, 0x15, 0x6e, 0xc9,
-	0x34, 0xba, 0x61, 0x5f, 0xa7, 0x52, 0x6e, 0xf1, 0x63, 0x18, 0x96, 0x9b, 0x97, 0xb0, 0x96, 0xa8,
-	0x18, 0x49, 0xd7, 0x96, 0xcc, 0x93, 0x1b, 0xaf, 0x2f, 0x95, 0x97, 0xe6, 0x4f, 0xe0, 0x76, 0xa5,
-	0x10, 0x8b, 0xf0, 0xb1, 0xfb, 0x73, 0xeb, 0x6a, 0x61, 0x1d, 0x2d, 0x13, 0x97, 0x56, 0x7f, 0x52,
-	0xbd, 0x88, 0xea, 0x96, 0xdf, 0xd0, 0x73, 0xbb, 0xd8, 0xba, 0x7d, 0x9d, 0x8a, 0xda, 0xe1, 0xe1,
-	0xf0, 0xef, 0x5f, 0x8d, 0x8c, 0x2f, 0xbf, 0x1a, 0x19, 0xff, 0xfa, 0x6a, 0x64, 0xfc, 0xea, 0xeb,
-	0x51, 0xe3, 0xcb, 0xaf, 0x47, 0x8d, 0x7f, 0x7e, 0x3d, 0x6a, 0x9c, 0x76, 0xe8, 0x5f, 0xe1, 0x6f,
-	0xff, 0x27, 0x00, 0x00, 0xff, 0xff, 0x24, 0x54, 0xe3, 0x4a, 0x27, 0x1e, 0x00, 0x00,
+	// 2755 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb5, 0x59, 0xcd, 0x6f, 0x1c, 0x49,
+	0x15, 0xcf, 0xf4, 0x7c, 0x78, 0xe6, 0x8d, 0x3f, 0xc6, 0x1d, 0x27, 0x4c, 0xbc, 0x89, 0xd7, 0xdb,
+	0x46, 0x21, 0x6b, 0x05, 0x8b, 0x98, 0x45, 0x8b, 0x56, 0x02, 0x76, 0x6d, 0x67, 0xb3, 0x5e, 0xec,
+	0x75, 0xd2, 0x76, 0xc2, 0x01, 0x21, 0xd1, 0x9e, 0x29, 0xdb, 0x8d, 0x7b, 0xba, 0x3b, 0xdd, 0x3d,
+	0xb6, 0x7c, 0x40, 0xdc, 0xb8, 0xc2, 0x05, 0x24, 0x10, 0x1c, 0x38, 0x20, 0x71, 0xe2, 0xc8, 0x9f,
+	0x80, 0x38, 0x46, 0x9c, 0x38, 0x22, 0xf8, 0x03, 0xf8, 0x07, 0xf6, 0xc0, 0x7b, 0xaf, 0xaa, 0xba,
+	0xab, 0xe7, 0xc3, 0xc1, 0x07, 0x0e, 0x23, 0xd5, 0xfb, 0xa8, 0xf7, 0x5e, 0xbd, 0xfa, 0xd5, 0xab,
+	0x57, 0x3d, 0x30, 0xdf, 0x1f, 0x5c, 0x46, 0xc9, 0xb9, 0x48, 0x36, 0xe2, 0x24, 0xca, 0x22, 0xdb,
+	0x8a, 0x8f, 0x9d, 0x47, 0x60, 0xbf, 0x18, 0x8a, 0xe4, 0xea, 0x30, 0xf3, 0xb2, 0x61, 0xea, 0x8a,
+	0xd7, 0x43, 0x91, 0x66, 0xb6, 0x0d, 0xb5, 0xd0, 0x1b, 0x88, 0x6e, 0x65, 0xb5, 0xf2, 0xa8, 0xe5,
+	0xf2, 0xd8, 0x89, 0x61, 0x69, 0x3b, 0x1a, 0x0c, 0xa2, 0xf0, 0x07, 0x6c, 0xc3, 0x15, 0x69, 0x1c,
+	0x85, 0xa9, 0xb0, 0xef, 0x42, 0x23, 0x11, 0xe9, 0x30, 0xc8, 0x58, 0xbb, 0xe9, 0x2a, 0xca, 0xee,
+	0x40, 0x75, 0x90, 0x9e, 0x76, 0x2d, 0x36, 0x41, 0x43, 0xd2, 0x4c, 0xa3, 0x61, 0xd2, 0x13, 0xdd,
+	0x2a, 0x33, 0x15, 0x45, 0x7c, 0x19, 0x57, 0xb7, 0x26, 0xf9, 0x92, 0x72, 0xfe, 0x5c, 0x81, 0xdb,
+	0xa5, 0xe0, 0x6e, 0xec, 0xf1, 0x03, 0x98, 0x95, 0x3e, 0xa4, 0x05, 0xf6, 0xdb, 0xde, 0xec, 0x6c,
+	0xc4, 0xc7, 0x1b, 0x87, 0x06, 0xdf, 0x2d, 0x69, 0xd9, 0x1f, 0xc2, 0x5c, 0x3a, 0x3c, 0x3e, 0xf2,
+	0xd2, 0x73, 0x35, 0xad, 0xb6, 0x5a, 0xc5, 0x69, 0x8b, 0x3c, 0xcd, 0x14, 0xb8, 0x65, 0x3d, 0xe7,
+	0x8f, 0x15, 0x68, 0x6f, 0x9f, 0x89, 0x9e, 0xa2, 0x29, 0xd0, 0xd8, 0x4b, 0x53, 0xd1, 0xd7, 0x81,
+	0x4a, 0xca, 0x5e, 0x82, 0x7a, 0x16, 0x65, 0x5e, 0xc0, 0xa1, 0xd6, 0x5d, 0x49, 0xd8, 0x2b, 0x00,
+	0xe9, 0xb0, 0xd7, 0x13, 0x69, 0x7a, 0x32, 0x0c, 0x38, 0xd4, 0xba, 0x6b, 0x70, 0xc8, 0xda, 0x89,
+	0xe7, 0x07, 0x68, 0xad, 0xc6, 0x32, 0x45, 0xd9, 0x5d, 0x98, 0xb9, 0xf4, 0x92, 0xd0, 0x0f, 0x4f,
+	0xbb, 0x75, 0x16, 0x68, 0x92, 0x66, 0xf4, 0x45, 0x86, 0x5a, 0xdd, 0x06, 0x0a, 0x66, 0x5d, 0x45,
+	0x39, 0x6f, 0x2a, 0x00, 0x3b, 0xc3, 0x41, 0xac, 0xc2, 0x5c, 0x85, 0x36, 0x47, 0x70, 0xe4, 0x1d,
+	0x07, 0x22, 0xe5, 0x58, 0xab, 0xae, 0xc9, 0xb2, 0x1f, 0xc1, 0x42, 0x2f, 0x1a, 0xc4, 0x81, 0xc8,
+	0x44, 0x5f, 0x69, 0x51, 0xe8, 0x15, 0x77, 0x94, 0x6d, 0x7f, 0x15, 0xe6, 0x4e, 0xfc, 0xd0, 0x4f,
+	0xcf, 0x44, 0x7f, 0xeb, 0x2a, 0x13, 0x32, 0xe5, 0x15, 0xb7, 0xcc, 0xb4, 0x1d, 0x98, 0xd5, 0x0c,
+	0x37, 0xba, 0x4c, 0x79, 0x41, 0x15, 0xb7, 0xc4, 0xb3, 0x1f, 0xc3, 0x22, 0x42, 0xd1, 0x1f, 0x78,
+	0x99, 0x38, 0xa2, 0x50, 0x58, 0xb1, 0xce, 0x8a, 0xe3, 0x02, 0xe7, 0x2f, 0xb8, 0xa4, 0xbd, 0xc8,
+	0xeb, 0xab, 0x25, 0x8d, 0x85, 0x21, 0x17, 0x35, 0x12, 0x06, 0x66, 0x9c, 0x57, 0x29, 0x55, 0x2c,
+	0x56, 0x31, 0x38, 0xf6, 0x32, 0x34, 0xf1, 0xa4, 0x9c, 0x22, 0xbc, 0x52, 0x05, 0xd9, 0x9c, 0xa6,
+	0xb9, 0x03, 0xcc, 0xe6, 0x96, 0x1f, 0x06, 0xd1, 0xa9, 0x02, 0xae, 0xc1, 0xb1, 0x1f, 0xc2, 0x7c,
+	0x41, 0x3d, 0x3b, 0xda, 0xdd, 0xe1, 0xd8, 0x5b, 0xee, 0x08, 0xd7, 0xf9, 0x55, 0x05, 0xe6, 0x0e,
+	0xcf, 0xbc, 0xa4, 0x8f, 0x1b, 0xf6, 0x2c, 0x89, 0x86, 0x31, 0xed, 0x5a, 0xe6, 0x25, 0xa7, 0x22,
+	0x53, 0xc7, 0x4f, 0x51, 0x74, 0x28, 0x77, 0x76, 0xf6, 0x28, 0xce, 0x2a, 0x1d, 0x4a, 0x1a, 0xcb,
+	0x75, 0x26, 0x69, 0xb6, 0x17, 0xf5, 0xbc, 0xcc, 0x8f, 0x42, 0x15, 0x66, 0x99, 0xc9, 0x07, 0xef,
+	0x2a, 0xec, 0x31, 0x72, 0xaa, 0x7c, 0xf0, 0x98, 0xa2, 0xf5, 0x0d, 0x43, 0x25, 0xa9, 0xb3, 0x24,
+	0xa7, 0x9d, 0xdf, 0xd7, 0x00, 0x0e, 0x71, 0x38, 0x82, 0x91, 0xa7, 0x17, 0x22, 0xcc, 0xca, 0x18,
+	0x91, 0x2c, 0x32, 0x26, 0x21, 0x13, 0xeb, 0x54, 0xe6, 0xb4, 0x7d, 0x1f, 0x5a, 0x89, 0xe8, 0xa1,
+	0x1a, 0x09, 0xab, 0x2c, 0x2c, 0x18, 0x84, 0x86, 0x81, 0x97, 0x66, 0x22, 0x29, 0x25, 0xb3, 0xc4,
+	0xb3, 0xd7, 0xa1, 0x63, 0xd2, 0xcf, 0x32, 0xbf, 0xaf, 0x12, 0x3a, 0xc6, 0x27, 0x7b, 0xbc, 0x08,
+	0x6d, 0xaf, 0x21, 0xed, 0x99, 0x3c, 0xb2, 0x67, 0xd2, 0x6c, 0x6f, 0x46, 0xda, 0x1b, 0xe5, 0x93,
+	0xbd, 0xe3, 0x20, 0xea, 0x9d, 0xe3, 0x0e, 0xf1, 0x06, 0x34, 0x39, 0x55, 0x25, 0x9e, 0xfd, 0x1d,
+	0xe8, 0x0c, 0x43, 0x04, 0x46, 0x14, 0x5c, 0x88, 0x3e, 0xef, 0x63, 0xda, 0x6d, 0x19, 0x65, 0xc3,
+	0xdc, 0x61, 0x77, 0x4c, 0xd5, 0xd8, 0x21, 0x90, 0x95, 0x42, 0xed, 0x10, 0xa2, 0xec, 0x98, 0x03,
+	0x39, 0xba, 0x8a, 0x45, 0xb7, 0x2d, 0x51, 0x56, 0x70, 0xec, 0x6f, 0xc0, 0xed, 0x54, 0xf4, 0xa2,
+	0xb0, 0x9f, 0x6e, 0x89, 0x33, 0x3f, 0xec, 0xef, 0x73, 0x2e, 0xba, 0xb3, 0x9c, 0xe2, 0x49, 0x22,
+	0x42, 0x0c, 0x07, 0x8e, 0x51, 0x1f, 0x5c, 0x86, 0xa8, 0x3b, 0x27, 0x11, 0x53, 0x62, 0xd2, 0x76,
+	0xe3, 0xd4, 0x93, 0xc0, 0xef, 0x65, 0xfb, 0x58, 0x52, 0xe7, 0x59, 0xc7, 0x64, 0x39, 0xbf, 0xab,
+	0xc0, 0xac, 0x59, 0x43, 0x8d, 0xea, 0x5e, 0x99, 0x52, 0xdd, 0x2d, 0xb3, 0xba, 0xdb, 0xef, 0xe7,
+	0x55, 0x5c, 0x56, 0x65, 0xce, 0xd3, 0xf3, 0x24, 0xa2, 0x72, 0xe7, 0xb2, 0x20, 0x2f, 0xec, 0x4f,
+	0xa0, 0x9d, 0x88, 0xc0, 0xbb, 0xca, 0xcb, 0x31, 0xe9, 0x2f, 0x90, 0xbe, 0x5b, 0xb0, 0x5d, 0x53,
+	0xc7, 0xf9, 0x8f, 0x05, 0x6d, 0x43, 0x38, 0x86, 0xb1, 0xca, 0xff, 0x88, 0x31, 0x6b, 0x0a, 0xc6,
+	0x56, 0x75, 0x48, 0xc3, 0xe3, 0x1d, 0x3f, 0x51, 0xc7, 0xce, 0x64, 0xe5, 0x1a, 0x25, 0x50, 0x9b,
+	0x2c, 0xaa, 0xaa, 0x06, 0x69, 0x40, 0x7a, 0x94, 0x6d, 0x6f, 0x80, 0xcd, 0xac, 0x6d, 0x2f, 0xeb,
+	0x9d, 0xbd, 0x8c, 0xd5, 0x2e, 0x37, 0x18, 0x2a, 0x13, 0x24, 0xf6, 0xbb, 0x50, 0x4f, 0x33, 0xef,
+	0x54, 0x30, 0xa4, 0xe7, 0x37, 0x5b, 0x0c, 0x41, 0x62, 0xb8, 0x92, 0x6f, 0x24, 0xbf, 0xf9, 0xb6,
+	0xe4, 0x63, 0x21, 0x93, 0x80, 0xc3, 0x3a, 0xbb, 0x3b, 0x20, 0xa3, 0x2d, 0x59, 0xc8, 0xca, 0x5c,
+	0xe7, 0x4b, 0x0b, 0x0b, 0x99, 0x79, 0x1d, 0x4e, 0xea, 0x22, 0x8a, 0xc8, 0xac, 0x29, 0x91, 0xad,
+	0x42, 0x6d, 0x18, 0xfa, 0x12, 0x14, 0xf3, 0x9b, 0xb3, 0x24, 0x7f, 0x89, 0x34, 0xa1, 0xdd, 0x65,
+	0x89, 0x11, 0x7b, 0xed, 0x6d, 0xb1, 0xe3, 0xf1, 0x28, 0x8e, 0x1a, 0x82, 0x1b, 0x2b, 0xe2, 0x79,
+	0x5e, 0x89, 0x27, 0x89, 0x30, 0x66, 0xee, 0x21, 0xb8, 0x64, 0x7c, 0x76, 0x4b, 0x76, 0x11, 0x5f,
+	0x83, 0x7a, 0x8f, 0x6e, 0x75, 0xce, 0xa6, 0x02, 0x9e, 0x71, 0xcd, 0xa3, 0x9a, 0x94, 0xe3, 0xd9,
+	0xaa, 0xf5, 0xf1, 0x5a, 0x55, 0x39, 0x9d, 0x27, 0xbd, 0xe2, 0x9a, 0x45, 0x35, 0x96, 0x92, 0x56,
+	0x80, 0x37, 0x15, 0xa7, 0x51, 0x69, 0x15, 0x37, 0x17, 0x69, 0x91, 0x94, 0xb4, 0xa8, 0x06, 0x70,
+	0x3d, 0x50, 0x5a, 0x45, 0x39, 0x26, 0x2d, 0x92, 0x6e, 0x35, 0xf1, 0xd0, 0x49, 0xc0, 0x7f, 0x17,
+	0x16, 0x4b, 0xd9, 0xdf, 0xf3, 0x53, 0x4e, 0x95, 0x14, 0xe3, 0x1e, 0x4c, 0x69, 0x61, 0xf4, 0x7c,
+	0xac, 0x34, 0xbc, 0xa6, 0xa7, 0x49, 0x12, 0x25, 0xba, 0x95, 0xaa, 0xe4, 0xad, 0x94, 0xf3, 0x00,
+	0x5a, 0xb4, 0x96, 0x6b, 0xc4, 0xb4, 0x88, 0x69, 0xe2, 0x18, 0x8b, 0x05, 0x45, 0xff, 0x62, 0x6f,
+	0x8a, 0x86, 0xbd, 0x09, 0x4b, 0xb2, 0x9f, 0x91, 0xb0, 0x7f, 0x1e, 0xa5, 0x3e, 0x5f, 0x68, 0xf2,
+	0x00, 0x4e, 0x94, 0xd1, 0x95, 0x23, 0xc8, 0x1c, 0x9a, 0xd5, 0xf7, 0xb3, 0xa6, 0x9d, 0x6f, 0x41,
+	0x8b, 0x3c, 0x4a, 0x77, 0x8f, 0xa0, 0xc1, 0x02, 0x9d, 0x87, 0x4e, 0x9e, 0x4e, 0x15, 0x90, 0xab,
+	0xe4, 0xce, 0x2f, 0xb0, 0x85, 0x93, 0x65, 0x4d, 0xce, 0xbc, 0x69, 0x55, 0x5b, 0x2d, 0x4d, 0xd7,
+	0x75, 0xc1, 0xb4, 0xb8, 0x01, 0xc0, 0x85, 0x49, 0x2a, 0xd4, 0x8a, 0xed, 0x2d, 0xb8, 0xae, 0xa1,
+	0x41, 0x1b, 0x53, 0x50, 0x13, 0x52, 0xfb, 0x1b, 0x0b, 0x73, 0x2b, 0xb7, 0x54, 0xaa, 0xfc, 0x9f,
+	0x8e, 0x9d, 0x3a, 0x19, 0x35, 0xf3, 0x64, 0x3c, 0xd4, 0x27, 0xa3, 0x5e, 0x2c, 0xa3, 0x40, 0x51,
+	0x71, 0x30, 0xd6, 0xd4, 0xc1, 0x68, 0xb0, 0xda, 0x9c, 0x3e, 0x18, 0x5a, 0x4b, 0x9e, 0x8b, 0x35,
+	0x75, 0x2e, 0x66, 0x0a, 0xa5, 0x1c, 0x52, 0xf9, 0xb1, 0x58, 0x53, 0xc7, 0xa2, 0x59, 0x28, 0xe5,
+	0xdb, 0x9c, 0x9f, 0x8a, 0x19, 0xa8, 0xf3, 0x76, 0x3a, 0x1f, 0x41, 0xc7, 0x4c, 0x0d, 0x9f, 0x89,
+	0x87, 0x4a, 0x58, 0x82, 0x82, 0xa1, 0xe4, 0xaa, 0xb9, 0xaf, 0x61, 0xae, 0x54, 0x54, 0xe8, 0x2e,
+	0xf6, 0xd3, 0x6d, 0x0f, 0xef, 0xe5, 0x20, 0xef, 0xe8, 0x0d, 0x8e, 0x01, 0x32, 0xab, 0xb0, 0xac,
+	0x4c, 0x94, 0x40, 0x66, 0xf4, 0xe5, 0xd5, 0x52, 0x5f, 0xfe, 0x77, 0xbc, 0x53, 0xcd, 0x09, 0xd4,
+	0xda, 0xe3, 0x60, 0x3b, 0xea, 0xcb, 0xdd, 0xc4, 0xd6, 0x5e, 0x91, 0x04, 0x7d, 0x1a, 0x06, 0xf8,
+	0xa0, 0x50, 0x08, 0xcc, 0x69, 0x25, 0x3b, 0xec, 0x45, 0xb1, 0x7e, 0x69, 0xe5, 0xb4, 0x92, 0xed,
+	0x89, 0x0b, 0x11, 0xa8, 0x2b, 0x29, 0xa7, 0xc9, 0xdb, 0x3e, 0xba, 0x26, 0x98, 0xc8, 0x0a, 0xa9,
+	0x49, 0x9a, 0xe5, 0x7a, 0x97, 0xdb, 0xde, 0x30, 0x15, 0xaa, 0x9b, 0xca, 0x69, 0x4a, 0x0b, 0xbd,
+	0x08, 0x3d, 0x6c, 0x64, 0x42, 0xdd, 0x43, 0x19, 0x1c, 0xe7, 0x12, 0x16, 0x9f, 0x0f, 0xb1, 0x81,
+	0x65, 0x10, 0xeb, 0x07, 0x26, 0x1a, 0xf4, 0x43, 0xaf, 0x97, 0xf9, 0x17, 0x42, 0x65, 0x32, 0xa7,
+	0x09, 0xbf, 0xd8, 0xdd, 0x0b, 0xd5, 0x44, 0xf2, 0x98, 0xf4, 0x4f, 0xb0, 0x00, 0x30, 0xae, 0xd5,
+	0x92, 0x34, 0xcd, 0x47, 0x54, 0xde, 0xc2, 0xea, 0xf9, 0x28, 0x29, 0xe7, 0xb7, 0x16, 0x2c, 0x1f,
+	0xc4, 0x22, 0xc1, 0x77, 0x82, 0x7c, 0xb2, 0x1e, 0x22, 0x18, 0x07, 0x9e, 0x0e, 0xe1, 0x3e, 0x58,
+	0x51, 0xcc, 0xce, 0x15, 0xde, 0xa5, 0xf8, 0x20, 0x76, 0x91, 0xcf, 0x41, 0x20, 0x22, 0x54, 0x6e,
+	0x79, 0x3c, 0xf5, 0xfd, 0x8a, 0xc1, 0xf5, 0xbd, 0xcc, 0x3b, 0xf6, 0x30, 0x3b, 0x2a, 0xa7, 0x9a,
+	0xe6, 0xa7, 0x1e, 0xbd, 0x8c, 0x54, 0x46, 0x25, 0xc1, 0x96, 0xd8, 0x9b, 0xca, 0xa6, 0xa2, 0x48,
+	0xfb, 0x24, 0x18, 0xa6, 0x67, 0x9c, 0xc6, 0xa6, 0x2b, 0x09, 0x8a, 0x25, 0xc7, 0x7c, 0x53, 0x42,
+	0x9c, 0xb2, 0x7e, 0x92, 0x44, 0x03, 0x59, 0x58, 0xf8, 0x2a, 0x41, 0x30, 0x16, 0x1c, 0x2d, 0x3f,
+	0x92, 0x0f, 0x09, 0x28, 0xe4, 0x92, 0xe3, 0x64, 0x30, 0xf7, 0xea, 0x89, 0x82, 0xfd, 0x3e, 0xa2,
+	0x0f, 0x17, 0x51, 0xa4, 0x03, 0x28, 0x1d, 0x24, 0x51, 0xc9, 0x78, 0x6b, 0xf5, 0xd0, 0x25, 0xa7,
+	0x6a, 0x94, 0x1c, 0x9d, 0xc1, 0x1a, 0x43, 0x9c, 0xc7, 0xce, 0x07, 0xb0, 0xa4, 0x76, 0xe4, 0xd5,
+	0x13, 0xf2, 0x3a, 0x75, 0x2f, 0xa4, 0x58, 0xba, 0x77, 0xfe, 0x5a, 0x81, 0x3b, 0x23, 0xd3, 0x6e,
+	0xfc, 0x25, 0xe0, 0x43, 0xa8, 0xd1, 0xc3, 0x0b, 0x23, 0xa4, 0xa3, 0xb9, 0x46, 0x3e, 0x26, 0x9a,
+	0xdc, 0x20, 0xe2, 0x69, 0x98, 0x25, 0x57, 0x2e, 0x4f, 0x58, 0xfe, 0x1c, 0x5a, 0x39, 0x8b, 0xec,
+	0x9e, 0x8b, 0x2b, 0x5d, 0x7d, 0x71, 0x48, 0xbd, 0xc1, 0x85, 0x17, 0x0c, 0x65, 0x6a, 0xd4, 0x05,
+	0x5b, 0x4a, 0xac, 0x2b, 0xe5, 0x1f, 0x59, 0xdf, 0xae, 0x38, 0x3f, 0x85, 0xee, 0x67, 0x5e, 0xd8,
+	0x0f, 0x14, 0x1e, 0x65, 0x51, 0x50, 0x29, 0x78, 0xc7, 0x48, 0x41, 0x9b, 0xac, 0xb0, 0xf4, 0x1a,
+	0x34, 0xe2, 0x9b, 0xea, 0x58, 0x5f, 0x87, 0x2a, 0xf1, 0x05, 0x83, 0x31, 0xf3, 0x3a, 0x48, 0xd5,
+	0x83, 0x8f, 0xc7, 0xce, 0x1d, 0xb8, 0xfd, 0x4c, 0x64, 0xd2, 0xf7, 0xf6, 0xc9, 0xa9, 0xf2, 0xec,
+	0x3c, 0x82, 0xa5, 0x32, 0x5b, 0x25, 0x17, 0x17, 0xdb, 0x3b, 0xc9, 0xaf, 0x1a, 0x1c, 0x3a, 0x87,
+	0xf0, 0x40, 0xf6, 0x3d, 0xc3, 0x63, 0x0a, 0x81, 0x4a, 0xdf, 0xcb, 0x18, 0xa1, 0x2e, 0xf4, 0x22,
+	0xf0, 0x12, 0x4f, 0xa5, 0x0c, 0x0d, 0x1d, 0x45, 0x83, 0xe0, 0x30, 0x4b, 0xe8, 0xbb, 0x84, 0xb4,
+	0x31, 0x51, 0xe6, 0xec, 0xc1, 0xca, 0x34, 0xa3, 0x2a, 0x10, 0xac, 0x4b, 0xea, 0x33, 0x88, 0xda,
+	0x66, 0x4d, 0x8e, 0xef, 0xb3, 0x73, 0x0a, 0xcb, 0xb8, 0x98, 0x57, 0x5e, 0xe0, 0xf7, 0xf9, 0xed,
+	0x5b, 0xfe, 0xae, 0x45, 0x6f, 0x54, 0xf4, 0xf1, 0x45, 0x71, 0x3d, 0xe6, 0xb4, 0xfd, 0x75, 0xfa,
+	0x26, 0x11, 0x60, 0xf7, 0xac, 0x5e, 0x19, 0x63, 0x58, 0x2f, 0x89, 0x9d, 0x3f, 0x55, 0xa0, 0x33,
+	0xea, 0x66, 0x6a, 0xb7, 0x80, 0x7e, 0xd3, 0xa4, 0xc7, 0x9f, 0x48, 0x74, 0xb5, 0xd6, 0x34, 0x57,
+	0x8f, 0x34, 0x93, 0x32, 0x55, 0xda, 0x34, 0x4d, 0x2f, 0x92, 0x8b, 0x11, 0x1f, 0xaa, 0xc2, 0x8c,
+	0xf1, 0x29, 0x4b, 0x83, 0x72, 0xf5, 0x56, 0xa4, 0x33, 0x84, 0x77, 0x26, 0xe6, 0xe4, 0xc6, 0x87,
+	0xe8, 0x71, 0xde, 0x4e, 0xca, 0x63, 0xb4, 0xc4, 0x68, 0x1f, 0xb5, 0xab, 0x3b, 0xca, 0x73, 0xb8,
+	0x57, 0x72, 0x5b, 0x82, 0xfb, 0x26, 0xb7, 0x6e, 0x34, 0x43, 0x28, 0xd0, 0xdf, 0x35, 0x8c, 0xc9,
+	0x56, 0x89, 0xa5, 0x6e, 0xae, 0x57, 0xda, 0x3d, 0xab, 0xbc, 0x7b, 0xce, 0x1f, 0x2c, 0x58, 0x18,
+	0x71, 0x65, 0xcf, 0x83, 0xe5, 0xf7, 0xd5, 0x4e, 0xe0, 0xc8, 0xd8, 0x1d, 0x6b, 0xea, 0xee, 0x54,
+	0x47, 0x76, 0x87, 0xb0, 0x97, 0xf4, 0x76, 0xb0, 0x9c, 0xab, 0xc4, 0x6b, 0xb2, 0xb4, 0x6f, 0xf5,
+	0x91, 0x7d, 0xc3, 0x59, 0x38, 0xe6, 0x59, 0xb2, 0xc0, 0x6b, 0x92, 0x4e, 0x2d, 0x37, 0x01, 0xfc,
+	0x9e, 0x97, 0x97, 0x65, 0xc1, 0xc0, 0xde, 0x50, 0x27, 0xb8, 0x79, 0x6d, 0x4e, 0x94, 0x56, 0x7e,
+	0x55, 0xb6, 0x54, 0x5d, 0xa0, 0xab, 0xd2, 0xc0, 0x01, 0x94, 0x71, 0xf0, 0x7a, 0xe4, 0x6c, 0xa8,
+	0x0d, 0xb9, 0x31, 0x0c, 0xde, 0xd7, 0x1d, 0x94, 0x44, 0xc1, 0xed, 0x32, 0x0a, 0x4a, 0x4d, 0xd4,
+	0xaf, 0x2b, 0xf0, 0x40, 0xd7, 0xd9, 0xc9, 0x40, 0x58, 0x33, 0xea, 0xde, 0xb8, 0x25, 0x55, 0xff,
+	0xb8, 0xf5, 0xfa, 0x24, 0x08, 0x64, 0xcf, 0x6c, 0xe9, 0xd6, 0x4b, 0x73, 0x4a, 0xc8, 0xa8, 0x8e,
+	0x9c, 0xeb, 0x25, 0x8e, 0x76, 0x57, 0x7e, 0x35, 0xad, 0xb9, 0x92, 0x70, 0x3e, 0x87, 0x95, 0x69,
+	0x71, 0xdd, 0x34, 0x1f, 0xce, 0x63, 0xe8, 0xee, 0x0e, 0xe2, 0x28, 0xc9, 0xb8, 0xc5, 0xd9, 0xf2,
+	0x7a, 0xe7, 0xc3, 0x58, 0x2f, 0x0f, 0xb5, 0xfb, 0x7e, 0xa2, 0x8b, 0x28, 0x0e, 0x9d, 0x1f, 0xc2,
+	0xbd, 0x09, 0xda, 0x37, 0xde, 0x04, 0x6a, 0x15, 0xfc, 0x40, 0xc8, 0xa3, 0x88, 0x8d, 0x05, 0x13,
+	0xeb, 0xe7, 0xd0, 0x90, 0xf7, 0xb6, 0x3d, 0x07, 0xad, 0xdd, 0x90, 0x8b, 0xc4, 0x41, 0xdc, 0xb9,
+	0x65, 0x37, 0xa1, 0x76, 0x98, 0x45, 0x71, 0xa7, 0x62, 0xb7, 0xa0, 0xfe, 0x9c, 0x1a, 0xb7, 0x8e,
+	0x65, 0x03, 0x34, 0xa8, 0xb7, 0x1d, 0x88, 0x4e, 0x95, 0xd8, 0x88, 0xad, 0x24, 0xeb, 0xd4, 0x88,
+	0x2d, 0x2b, 0x70, 0xa7, 0x8e, 0x67, 0x08, 0x3e, 0x19, 0x66, 0x91, 0x52, 0x6b, 0x90, 0x6c, 0x47,
+	0xd0, 0x07, 0xdf, 0xce, 0xcc, 0xfa, 0xcf, 0x78, 0xca, 0x29, 0xdd, 0x14, 0xb3, 0xca, 0x17, 0xd3,
+	0xe8, 0x6e, 0x06, 0xaa, 0x5f, 0x88, 0x4b, 0xf4, 0xd6, 0x86, 0x19, 0x77, 0x18, 0xd2, 0xd7, 0x68,
+	0xe9, 0x8f, 0x5d, 0xf7, 0xd1, 0x1f, 0x0a, 0x28, 0xa0, 0x18, 0x89, 0x9a, 0x3d, 0x0b, 0xcd, 0x4f,
+	0xd5, 0x97, 0x59, 0xf4, 0x89, 0x22, 0x52, 0xa3, 0x39, 0x0d, 0x12, 0xb1, 0x73, 0xa2, 0x66, 0x88,
+	0xe2, 0x59, 0x44, 0x35, 0xd7, 0x0f, 0xa0, 0xa9, 0x1f, 0x29, 0xf6, 0x02, 0xb4, 0x55, 0x0c, 0xc4,
+	0xc2, 0x10, 0x70, 0x41, 0x7c, 0xaf, 0x60, 0x10, 0xb8, 0x78, 0x7a, 0x6e, 0x60, 0x04, 0x38, 0xa2,
+	0x37, 0x05, 0xfa, 0xa7, 0x84, 0x60, 0x23, 0x85, 0xce, 0x51, 0x91, 0xb7, 0xa2, 0xd3, 0x5f, 0xdf,
+	0xc7, 0x68, 0x69, 0x78, 0x40, 0x57, 0xee, 0xbc, 0xb2, 0xa7, 0x38, 0x68, 0x12, 0x73, 0x4a, 0xde,
+	0xa5, 0x76, 0x85, 0x72, 0xc3, 0xcb, 0x91, 0xb4, 0x45, 0x21, 0xc8, 0x3c, 0x49, 0x46, 0x75, 0xfd,
+	0xe7, 0x15, 0x0c, 0x57, 0x75, 0x95, 0xf6, 0x6d, 0x58, 0xd0, 0x49, 0x52, 0x2c, 0x69, 0x11, 0x8f,
+	0xa4, 0x64, 0xa0, 0x45, 0x72, 0x90, 0x93, 0x16, 0xe5, 0xd5, 0x15, 0x83, 0xe8, 0x42, 0x28, 0x4e,
+	0x95, 0x5c, 0xd2, 0x23, 0x46, 0xd1, 0x35, 0x9a, 0x40, 0x34, 0x57, 0x1d, 0xcc, 0xdc, 0x5d, 0xb0,
+	0x89, 0xdc, 0xf7, 0x4f, 0x09, 0xd9, 0xb2, 0xd5, 0x4b, 0x3b, 0x8d, 0xf5, 0x8f, 0xa1, 0xa9, 0x3b,
+	0x2a, 0x23, 0x0e, 0xcd, 0xca, 0xe3, 0x90, 0x0c, 0x8c, 0x23, 0x77, 0xac, 0x38, 0xd6, 0xfa, 0x2b,
+	0x7e, 0x89, 0x50, 0x43, 0x62, 0x64, 0x46, 0x71, 0x14, 0xbc, 0xce, 0xfd, 0x58, 0x6d, 0xb8, 0x88,
+	0x03, 0xaf, 0x97, 0x03, 0xec, 0x42, 0x20, 0xaa, 0xaa, 0x34, 0xde, 0x0d, 0x7f, 0x22, 0x7a, 0x84,
+	0x30, 0xda, 0x06, 0x8c, 0xb3, 0x53, 0x5f, 0xdf, 0x83, 0xb6, 0x3a, 0x80, 0x6c, 0x1b, 0x17, 0xa0,
+	0x83, 0x2b, 0xb8, 0x68, 0x1f, 0x7d, 0x32, 0x3a, 0x73, 0x2e, 0x7a, 0x5a, 0x84, 0x39, 0xda, 0x8d,
+	0x82, 0x65, 0xad, 0xbf, 0x00, 0x7b, 0xbc, 0x5a, 0x52, 0xd2, 0x8a, 0x80, 0xd1, 0x18, 0x46, 0x82,
+	0xe0, 0xa4, 0x31, 0xef, 0xe1, 0xee, 0x69, 0x18, 0x25, 0x82, 0x65, 0x7a, 0x0f, 0xf9, 0xa3, 0x10,
+	0x31, 0xaa, 0xb8, 0xf0, 0x85, 0x91, 0x8a, 0x64, 0xc0, 0x9d, 0x69, 0xb4, 0x48, 0xe0, 0x63, 0x2b,
+	0x92, 0xa1, 0x12, 0xc8, 0x66, 0x24, 0xc7, 0x22, 0x47, 0xdb, 0x81, 0xf0, 0x12, 0x49, 0x57, 0x37,
+	0xbf, 0x6c, 0x40, 0x43, 0xf6, 0x5c, 0xf6, 0xc7, 0xd0, 0x36, 0xfe, 0xe6, 0xb2, 0xb9, 0xe8, 0x8f,
+	0xff, 0x29, 0xb7, 0xfc, 0x95, 0x31, 0xbe, 0x2c, 0x1a, 0xce, 0x2d, 0xfb, 0x7b, 0x88, 0xc4, 0xfc,
+	0x8d, 0x65, 0xdf, 0xe1, 0x87, 0xe7, 0xe8, 0x9b, 0x6b, 0xb9, 0xcb, 0xaf, 0xf3, 0x09, 0x7f, 0xe1,
+	0xa1, 0x81, 0xef, 0xc3, 0x9c, 0x2a, 0x87, 0x12, 0x5a, 0xf6, 0x8a, 0xd1, 0x21, 0x4f, 0x78, 0x3d,
+	0x5d, 0x6b, 0xec, 0xd3, 0xdc, 0x98, 0x84, 0x8f, 0xdd, 0x9d, 0xd0, 0x6e, 0x4b, 0x33, 0xf7, 0xa6,
+	0x36, 0xe2, 0x68, 0xe7, 0x19, 0xb4, 0x65, 0xbb, 0x2c, 0x8b, 0xfc, 0x7d, 0xd2, 0x9d, 0xd6, 0x3f,
+	0x5f, 0x1b, 0xd0, 0x36, 0xcc, 0x9a, 0x1d, 0xae, 0xcd, 0x99, 0x9c, 0xd0, 0x0a, 0x4b, 0x23, 0x93,
+	0x9a, 0x61, 0x34, 0xe2, 0xc1, 0xdd, 0xc9, 0x7d, 0xaa, 0xfd, 0x5e, 0xf1, 0x41, 0x70, 0x4a, 0x63,
+	0xbc, 0xec, 0x5c, 0xa7, 0x92, 0xbb, 0xf8, 0x11, 0x74, 0x73, 0xe7, 0x39, 0xac, 0x15, 0x2a, 0x56,
+	0x54, 0x68, 0x53, 0x5a, 0xdb, 0xe5, 0x77, 0xa7, 0xca, 0x73, 0xf3, 0x47, 0xb0, 0x58, 0x28, 0x44,
+	0x32, 0x7d, 0xf6, 0x83, 0xb1, 0x79, 0xa5, 0xb4, 0xae, 0x4c, 0x13, 0xe7, 0x56, 0x7f, 0x5c, 0x3c,
+	0xce, 0xca, 0x96, 0xdf, 0x33, 0xf7, 0x76, 0xb2, 0x75, 0xe7, 0x3a, 0x95, 0xdc, 0x83, 0x0b, 0x8b,
+	0x63, 0x37, 0xa6, 0x44, 0xc3, 0xb4, 0x6b, 0x77, 0xf9, 0xc1, 0x14, 0xa9, 0xb6, 0xb9, 0xd5, 0xfd,
+	0xdb, 0xbf, 0x56, 0x2a, 0x6f, 0xf0, 0xf7, 0x4f, 0xfc, 0xfd, 0xf2, 0xdf, 0x2b, 0xb7, 0xde, 0xe0,
+	0xef, 0x1f, 0xf8, 0x3b, 0x6e, 0xf0, 0x9f, 0xe3, 0xdf, 0xfc, 0x2f, 0xfb, 0xb5, 0xd4, 0x6f, 0x2e,
+	0x1f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3560,6 +3686,8 @@ type WorkerClient interface {
 	GetWorkerValidatorStatus(ctx context.Context, in *GetValidationStatusRequest, opts ...grpc.CallOption) (*GetValidationStatusResponse, error)
 	GetValidatorError(ctx context.Context, in *GetValidationErrorRequest, opts ...grpc.CallOption) (*GetValidationErrorResponse, error)
 	OperateValidatorError(ctx context.Context, in *OperateValidationErrorRequest, opts ...grpc.CallOption) (*OperateValidationErrorResponse, error)
+	// ImportRelayBackup seeds the relay log with the binlog files of a backup set
+	ImportRelayBackup(ctx context.Context, in *ImportRelayBackupRequest, opts ...grpc.CallOption) (*ImportRelayBackupResponse, error)
 }
 
 type workerClient struct {
@@ -3660,6 +3788,15 @@ func (c *workerClient) OperateValidatorError(ctx context.Context, in *OperateVal
 	return out, nil
 }
 
+func (c *workerClient) ImportRelayBackup(ctx context.Context, in *ImportRelayBackupRequest, opts ...grpc.CallOption) (*ImportRelayBackupResponse, error) {
+	out := new(ImportRelayBackupResponse)
+	err := c.cc.Invoke(ctx, "/pb.Worker/ImportRelayBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkerServer is the server API for Worker service.
 type WorkerServer interface {
 	QueryStatus(context.Context, *QueryStatusRequest) (*QueryStatusResponse, error)
@@ -3677,6 +3814,8 @@ type WorkerServer interface {
 	GetWorkerValidatorStatus(context.Context, *GetValidationStatusRequest) (*GetValidationStatusResponse, error)
 	GetValidatorError(context.Context, *GetValidationErrorRequest) (*GetValidationErrorResponse, error)
 	OperateValidatorError(context.Context, *OperateValidationErrorRequest) (*OperateValidationErrorResponse, error)
+	// ImportRelayBackup seeds the relay log with the binlog files of a backup set
+	ImportRelayBackup(context.Context, *ImportRelayBackupRequest) (*ImportRelayBackupResponse, error)
 }
 
 // UnimplementedWorkerServer can be embedded to have forward compatible implementations.
@@ -3713,6 +3852,9 @@ func (*UnimplementedWorkerServer) GetValidatorError(ctx context.Context, req *Ge
 func (*UnimplementedWorkerServer) OperateValidatorError(ctx context.Context, req *OperateValidationErrorRequest) (*OperateValidationErrorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OperateValidatorError not implemented")
 }
+func (*UnimplementedWorkerServer) ImportRelayBackup(ctx context.Context, req *ImportRelayBackupRequest) (*ImportRelayBackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportRelayBackup not implemented")
+}
 
 func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
 	s.RegisterService(&_Worker_serviceDesc, srv)
@@ -3898,6 +4040,24 @@ func _Worker_OperateValidatorError_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_ImportRelayBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRelayBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).ImportRelayBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Worker/ImportRelayBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).ImportRelayBackup(ctx, req.(*ImportRelayBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Worker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Worker",
 	HandlerType: (*WorkerServer)(nil),
@@ -3942,6 +4102,10 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 			MethodName: "OperateValidatorError",
 			Handler:    _Worker_OperateValidatorError_Handler,
 		},
+		{
+			MethodName: "ImportRelayBackup",
+			Handler:    _Worker_ImportRelayBackup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "dmworker.proto",
@@ -4533,6 +4697,13 @@ func (m *RelayStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.BinlogRowImage) > 0 {
+		i -= len(m.BinlogRowImage)
+		copy(dAtA[i:], m.BinlogRowImage)
+		i = encodeVarintDmworker(dAtA, i, uint64(len(m.BinlogRowImage)))
+		i--
+		dAtA[i] = 0x4a
+	}
 	if m.Result != nil {
 		{
 			size, err := m.Result.MarshalToSizedBuffer(dAtA[:i])
@@ -5386,6 +5557,16 @@ func (m *PurgeRelayRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DryRun {
+		i--
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
 	if len(m.SubDir) > 0 {
 		i -= len(m.SubDir)
 		copy(dAtA[i:], m.SubDir)
@@ -6253,6 +6434,85 @@ func (m *OperateValidationErrorResponse) MarshalToSizedBuffer(dAtA []byte) (int,
 	return len(dAtA) - i, nil
 }
 
+func (m *ImportRelayBackupRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ImportRelayBackupRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ImportRelayBackupRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Dir) > 0 {
+		i -= len(m.Dir)
+		copy(dAtA[i:], m.Dir)
+		i = encodeVarintDmworker(dAtA, i, uint64(len(m.Dir)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ImportRelayBackupResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ImportRelayBackupResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ImportRelayBackupResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Files) > 0 {
+		for iNdEx := len(m.Files) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Files[iNdEx])
+			copy(dAtA[i:], m.Files[iNdEx])
+			i = encodeVarintDmworker(dAtA, i, uint64(len(m.Files[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Msg) > 0 {
+		i -= len(m.Msg)
+		copy(dAtA[i:], m.Msg)
+		i = encodeVarintDmworker(dAtA, i, uint64(len(m.Msg)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Result {
+		i--
+		if m.Result {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintDmworker(dAtA []byte, offset int, v uint64) int {
 	offset -= sovDmworker(v)
 	base := offset
@@ -6566,6 +6826,10 @@ func (m *RelayStatus) Size() (n int) {
 		l = m.Result.Size()
 		n += 1 + l + sovDmworker(uint64(l))
 	}
+	l = len(m.BinlogRowImage)
+	if l > 0 {
+		n += 1 + l + sovDmworker(uint64(l))
+	}
 	return n
 }
 
@@ -6958,6 +7222,9 @@ func (m *PurgeRelayRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovDmworker(uint64(l))
 	}
+	if m.DryRun {
+		n += 2
+	}
 	return n
 }
 
@@ -7337,6 +7604,41 @@ func (m *OperateValidationErrorResponse) Size() (n int) {
 	return n
 }
 
+func (m *ImportRelayBackupRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Dir)
+	if l > 0 {
+		n += 1 + l + sovDmworker(uint64(l))
+	}
+	return n
+}
+
+func (m *ImportRelayBackupResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Result {
+		n += 2
+	}
+	l = len(m.Msg)
+	if l > 0 {
+		n += 1 + l + sovDmworker(uint64(l))
+	}
+	if len(m.Files) > 0 {
+		for _, s := range m.Files {
+			l = len(s)
+			n += 1 + l + sovDmworker(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovDmworker(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -9336,6 +9638,38 @@ func (m *RelayStatus) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BinlogRowImage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmworker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BinlogRowImage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDmworker(dAtA[iNdEx:])
@@ -11448,6 +11782,26 @@ func (m *PurgeRelayRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.SubDir = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmworker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DryRun = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDmworker(dAtA[iNdEx:])
@@ -13996,6 +14350,222 @@ func (m *OperateValidationErrorResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ImportRelayBackupRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDmworker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ImportRelayBackupRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ImportRelayBackupRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dir", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmworker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Dir = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDmworker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ImportRelayBackupResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDmworker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ImportRelayBackupResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ImportRelayBackupResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Result", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmworker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Result = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Msg", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmworker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Msg = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Files", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDmworker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Files = append(m.Files, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDmworker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDmworker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipDmworker(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
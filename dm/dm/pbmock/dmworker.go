@@ -136,6 +136,26 @@ func (mr *MockWorkerClientMockRecorder) HandleError(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleError", reflect.TypeOf((*MockWorkerClient)(nil).HandleError), varargs...)
 }
 
+// ImportRelayBackup mocks base method.
+func (m *MockWorkerClient) ImportRelayBackup(arg0 context.Context, arg1 *pb.ImportRelayBackupRequest, arg2 ...grpc.CallOption) (*pb.ImportRelayBackupResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ImportRelayBackup", varargs...)
+	ret0, _ := ret[0].(*pb.ImportRelayBackupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportRelayBackup indicates an expected call of ImportRelayBackup.
+func (mr *MockWorkerClientMockRecorder) ImportRelayBackup(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRelayBackup", reflect.TypeOf((*MockWorkerClient)(nil).ImportRelayBackup), varargs...)
+}
+
 // OperateSchema mocks base method.
 func (m *MockWorkerClient) OperateSchema(arg0 context.Context, arg1 *pb.OperateWorkerSchemaRequest, arg2 ...grpc.CallOption) (*pb.CommonWorkerResponse, error) {
 	m.ctrl.T.Helper()
@@ -334,6 +354,21 @@ func (mr *MockWorkerServerMockRecorder) HandleError(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleError", reflect.TypeOf((*MockWorkerServer)(nil).HandleError), arg0, arg1)
 }
 
+// ImportRelayBackup mocks base method.
+func (m *MockWorkerServer) ImportRelayBackup(arg0 context.Context, arg1 *pb.ImportRelayBackupRequest) (*pb.ImportRelayBackupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRelayBackup", arg0, arg1)
+	ret0, _ := ret[0].(*pb.ImportRelayBackupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportRelayBackup indicates an expected call of ImportRelayBackup.
+func (mr *MockWorkerServerMockRecorder) ImportRelayBackup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRelayBackup", reflect.TypeOf((*MockWorkerServer)(nil).ImportRelayBackup), arg0, arg1)
+}
+
 // OperateSchema mocks base method.
 func (m *MockWorkerServer) OperateSchema(arg0 context.Context, arg1 *pb.OperateWorkerSchemaRequest) (*pb.CommonWorkerResponse, error) {
 	m.ctrl.T.Helper()
@@ -15,9 +15,11 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"strconv"
 	"time"
 
 	cpu "github.com/pingcap/tidb-tools/pkg/utils"
@@ -27,11 +29,13 @@ import (
 	"github.com/pingcap/tiflow/dm/dm/common"
 	"github.com/pingcap/tiflow/dm/dumpling"
 	"github.com/pingcap/tiflow/dm/loader"
+	"github.com/pingcap/tiflow/dm/pkg/binlog/stats"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/metricsproxy"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
 	"github.com/pingcap/tiflow/dm/relay"
 	syncer "github.com/pingcap/tiflow/dm/syncer/metrics"
+	"github.com/pingcap/tiflow/pkg/logutil"
 )
 
 const (
@@ -78,6 +82,31 @@ func (h *statusHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// binlogStatisticsHandler serves the binlog event statistics of the source specified by the `source` query parameter.
+type binlogStatisticsHandler struct{}
+
+func (h *binlogStatisticsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	source := req.URL.Query().Get("source")
+	top := stats.DefaultTopTables
+	if v := req.URL.Query().Get("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid top "+v, http.StatusBadRequest)
+			return
+		}
+		top = n
+	}
+	c := stats.Get(source)
+	if c == nil {
+		http.Error(w, "no binlog statistics of source "+source, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.Snapshot(top)); err != nil && !common.IsErrNetClosing(err) {
+		log.L().Error("fail to write binlog statistics response", log.ShortError(err))
+	}
+}
+
 // Note: handle error inside the function with returning it.
 func (s *Server) collectMetrics() {
 	// CPU usage metric
@@ -115,6 +144,7 @@ func RegistryMetrics() {
 	dumpling.RegisterMetrics(registry)
 	loader.RegisterMetrics(registry)
 	syncer.RegisterMetrics(registry)
+	stats.RegisterMetrics(registry)
 	prometheus.DefaultGatherer = registry
 }
 
@@ -123,6 +153,8 @@ func InitStatus(lis net.Listener) {
 	mux := http.NewServeMux()
 	mux.Handle("/status", &statusHandler{})
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/binlog-statistics", &binlogStatisticsHandler{})
+	mux.Handle("/log/components", logutil.ComponentLogHandler())
 
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
@@ -144,6 +144,11 @@ func (d *DummyRelay) PurgeRelayDir() error {
 	return nil
 }
 
+// ImportBinlogBackup implements Process interface.
+func (d *DummyRelay) ImportBinlogBackup(backupDir string) ([]string, error) {
+	return nil, nil
+}
+
 func (t *testRelay) TestRelay(c *C) {
 	originNewRelay := relay.NewRelay
 	relay.NewRelay = NewDummyRelay
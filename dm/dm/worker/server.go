@@ -778,11 +778,34 @@ func (s *Server) PurgeRelay(ctx context.Context, req *pb.PurgeRelayRequest) (*pb
 		return makeCommonWorkerResponse(terror.ErrWorkerNoStart.Generate()), nil
 	}
 
-	err := w.PurgeRelay(ctx, req)
+	preview, err := w.PurgeRelay(ctx, req)
 	if err != nil {
 		log.L().Error("fail to purge relay", zap.String("request", "PurgeRelay"), zap.Stringer("payload", req), zap.Error(err))
+		return makeCommonWorkerResponse(err), nil
+	}
+	resp := makeCommonWorkerResponse(nil)
+	if preview != nil {
+		// the preview of a dry-run purge is placed in the `msg` field.
+		resp.Msg = preview.String()
+	}
+	return resp, nil
+}
+
+// ImportRelayBackup seeds the relay log with the binlog files of a backup set.
+func (s *Server) ImportRelayBackup(ctx context.Context, req *pb.ImportRelayBackupRequest) (*pb.ImportRelayBackupResponse, error) {
+	log.L().Info("", zap.String("request", "ImportRelayBackup"), zap.Stringer("payload", req))
+	w := s.getSourceWorker(true)
+	if w == nil {
+		log.L().Warn("fail to call ImportRelayBackup, because no mysql source is being handled in the worker")
+		return &pb.ImportRelayBackupResponse{Msg: terror.ErrWorkerNoStart.Generate().Error()}, nil
+	}
+
+	files, err := w.ImportRelayBackup(req.Dir)
+	if err != nil {
+		log.L().Error("fail to import binlog backup into relay log", zap.String("request", "ImportRelayBackup"), zap.Stringer("payload", req), zap.Error(err))
+		return &pb.ImportRelayBackupResponse{Msg: err.Error()}, nil
 	}
-	return makeCommonWorkerResponse(err), nil
+	return &pb.ImportRelayBackupResponse{Result: true, Files: files}, nil
 }
 
 // OperateSchema operates schema for an upstream table.
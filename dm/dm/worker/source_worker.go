@@ -240,6 +240,7 @@ func (w *SourceWorker) Stop(graceful bool) {
 
 	w.sourceDB.Close()
 	w.sourceDB = nil
+	utils.RemoveUpstreamMetaFetcher(w.cfg.From.Host, w.cfg.From.Port)
 
 	w.closed.Store(true)
 
@@ -262,7 +263,8 @@ func (w *SourceWorker) updateSourceStatus(ctx context.Context) error {
 	var status binlog.SourceStatus
 	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
 	defer cancel()
-	pos, gtidSet, err := utils.GetPosAndGs(ctx, w.sourceDB.DB, w.cfg.Flavor)
+	pos, gtidSet, err := utils.GetUpstreamMetaFetcher(w.cfg.From.Host, w.cfg.From.Port).
+		GetPosAndGs(ctx, w.sourceDB.DB, w.cfg.Flavor)
 	if err != nil {
 		return err
 	}
@@ -274,6 +276,13 @@ func (w *SourceWorker) updateSourceStatus(ctx context.Context) error {
 		return err
 	}
 	status.Binlogs = binlogs
+	ctx3, cancel3 := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel3()
+	// the status is still useful without binlog_row_image.
+	status.BinlogRowImage, err = utils.GetBinlogRowImage(ctx3, w.sourceDB.DB)
+	if err != nil {
+		w.l.Warn("failed to get binlog_row_image of the source", zap.Error(err))
+	}
 
 	status.UpdateTime = time.Now()
 
@@ -977,15 +986,31 @@ func (w *SourceWorker) operateRelay(ctx context.Context, op pb.RelayOp) error {
 	return nil
 }
 
+// ImportRelayBackup seeds the relay log with the binlog files of a backup set,
+// and returns the names of the imported files.
+func (w *SourceWorker) ImportRelayBackup(backupDir string) ([]string, error) {
+	if w.closed.Load() {
+		return nil, terror.ErrWorkerAlreadyClosed.Generate()
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	if !w.relayEnabled.Load() {
+		return nil, terror.ErrRelayImportBinlogBackup.Generate(backupDir, "relay is not enabled")
+	}
+	return w.relayHolder.Relay().ImportBinlogBackup(backupDir)
+}
+
 // PurgeRelay purges relay log files.
-func (w *SourceWorker) PurgeRelay(ctx context.Context, req *pb.PurgeRelayRequest) error {
+// For a dry-run request, no file is removed and the preview of the purge is returned.
+func (w *SourceWorker) PurgeRelay(ctx context.Context, req *pb.PurgeRelayRequest) (*relay.PurgePreview, error) {
 	if w.closed.Load() {
-		return terror.ErrWorkerAlreadyClosed.Generate()
+		return nil, terror.ErrWorkerAlreadyClosed.Generate()
 	}
 
 	if !w.relayEnabled.Load() {
 		w.l.Warn("enable-relay is false, ignore purge relay")
-		return nil
+		return nil, nil
 	}
 
 	if !w.subTaskEnabled.Load() {
@@ -995,12 +1020,12 @@ func (w *SourceWorker) PurgeRelay(ctx context.Context, req *pb.PurgeRelayRequest
 
 		_, _, subTaskCfgs, _, err := w.fetchSubTasksAndAdjust()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, subTaskCfg := range subTaskCfgs {
 			loc, err2 := getMinLocForSubTaskFunc(ctx, subTaskCfg)
 			if err2 != nil {
-				return err2
+				return nil, err2
 			}
 			w.l.Info("update active relay log with",
 				zap.String("task name", subTaskCfg.Name),
@@ -1011,7 +1036,10 @@ func (w *SourceWorker) PurgeRelay(ctx context.Context, req *pb.PurgeRelayRequest
 			}
 		}
 	}
-	return w.relayPurger.Do(ctx, req)
+	if req.DryRun {
+		return w.relayPurger.Preview(ctx, req)
+	}
+	return nil, w.relayPurger.Do(ctx, req)
 }
 
 // ForbidPurge implements PurgeInterceptor.ForbidPurge.
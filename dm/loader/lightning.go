@@ -233,6 +233,11 @@ func (l *LightningLoader) restore(ctx context.Context) error {
 	}
 
 	if status < lightningStatusFinished {
+		release, err2 := acquireLoadQuota(ctx, l.cli, l.cfg, l.workerName)
+		if err2 != nil {
+			return err2
+		}
+		defer release()
 		if err = l.checkPointList.RegisterCheckPoint(ctx); err != nil {
 			return err
 		}
@@ -737,6 +737,13 @@ func (l *Loader) Restore(ctx context.Context) error {
 		return err
 	}
 	l.loadFinishedSize()
+	if !l.checkPoint.AllFinished() {
+		release, err2 := acquireLoadQuota(ctx, l.cli, l.cfg, l.workerName)
+		if err2 != nil {
+			return err2
+		}
+		defer release()
+	}
 	if err2 := l.initAndStartWorkerPool(ctx); err2 != nil {
 		l.logger.Error("initial and start worker pools failed", log.ShortError(err))
 		return err2
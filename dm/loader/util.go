@@ -21,6 +21,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
@@ -28,6 +29,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tiflow/dm/dm/config"
 	"github.com/pingcap/tiflow/dm/pkg/dumpling"
+	"github.com/pingcap/tiflow/dm/pkg/etcdutil"
 	"github.com/pingcap/tiflow/dm/pkg/ha"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/storage"
@@ -217,6 +219,72 @@ func delLoadTask(cli *clientv3.Client, cfg *config.SubTaskConfig, workerName str
 	return nil
 }
 
+// loadQuotaLeaseTTL is the TTL in seconds of the lease of the load quota request, the request is
+// deleted if the DM-worker crashes and doesn't keep the lease alive.
+const loadQuotaLeaseTTL = 10
+
+// acquireLoadQuota waits until DM-master grants the quota of running the load unit to the subtask
+// when DM-master limits how many subtasks run the load unit concurrently, the returned function
+// releases the quota.
+// This is no-op when the `cli` argument is nil.
+func acquireLoadQuota(ctx context.Context, cli *clientv3.Client, cfg *config.SubTaskConfig, workerName string) (func(), error) {
+	noop := func() {}
+	// some usage like DM as a library, we don't support this feature
+	if cli == nil {
+		return noop, nil
+	}
+	limit, err := ha.GetLoadQuotaLimit(cli)
+	if err != nil || limit <= 0 {
+		return noop, err
+	}
+
+	lease, err := cli.Grant(ctx, loadQuotaLeaseTTL)
+	if err != nil {
+		return nil, err
+	}
+	keepAliveCtx, cancel := context.WithCancel(context.Background())
+	keepAliveCh, err := cli.KeepAlive(keepAliveCtx, lease.ID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go func() {
+		// drain the responses, otherwise the etcd client warns that the queue is full.
+		for {
+			if _, ok := <-keepAliveCh; !ok {
+				return
+			}
+		}
+	}()
+	release := func() {
+		cancel()
+		if _, err2 := ha.DelLoadQuota(cli, cfg.Name, cfg.SourceID); err2 != nil {
+			log.L().Warn("fail to release load quota", zap.String("task", cfg.Name), zap.String("source", cfg.SourceID), zap.Error(err2))
+		}
+		revokeCtx, revokeCancel := context.WithTimeout(context.Background(), etcdutil.DefaultRequestTimeout)
+		defer revokeCancel()
+		if _, err2 := cli.Revoke(revokeCtx, lease.ID); err2 != nil {
+			log.L().Warn("fail to revoke the lease of load quota request", zap.String("task", cfg.Name), zap.String("source", cfg.SourceID), zap.Error(err2))
+		}
+	}
+
+	req := ha.NewLoadQuotaRequest(cfg.Name, cfg.SourceID, workerName, cfg.LoaderConfig.Priority)
+	if _, err = ha.PutLoadQuotaRequest(cli, req, lease.ID); err != nil {
+		release()
+		return nil, err
+	}
+	log.L().Info("wait for load quota", zap.String("task", cfg.Name), zap.String("source", cfg.SourceID),
+		zap.Int("priority", req.Priority), zap.Int("limit", limit))
+	begin := time.Now()
+	if err = ha.WaitLoadQuotaGranted(ctx, cli, cfg.Name, cfg.SourceID); err != nil {
+		release()
+		return nil, err
+	}
+	log.L().Info("load quota granted", zap.String("task", cfg.Name), zap.String("source", cfg.SourceID),
+		zap.Duration("wait time", time.Since(begin)))
+	return release, nil
+}
+
 // getLoadTask gets the worker which in load stage for the source of the subtask.
 // It will return "" and no error when the `cli` argument is nil.
 func getLoadTask(cli *clientv3.Client, task, sourceID string) (string, error) {
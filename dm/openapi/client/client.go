@@ -0,0 +1,119 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client provides a typed Go client of the DM-master OpenAPI, the
+// errors returned by DM-master are mapped back to the terror of their codes.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tiflow/dm/openapi"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+)
+
+// Client is a typed client of the DM-master OpenAPI.
+type Client struct {
+	api openapi.ClientWithResponsesInterface
+}
+
+// New creates a Client connected to the DM-master at endpoint, e.g.
+// "http://127.0.0.1:8261". The DM-master forwards the requests to the leader,
+// so any member of the cluster can be used.
+func New(endpoint string, opts ...openapi.ClientOption) (*Client, error) {
+	api, err := openapi.NewClientWithResponses(endpoint, opts...)
+	if err != nil {
+		return nil, terror.ErrOpenAPICommonError.Delegate(err)
+	}
+	return &Client{api: api}, nil
+}
+
+// WithBasicAuth makes the client authenticate with the HTTP basic authentication.
+func WithBasicAuth(username, password string) openapi.ClientOption {
+	return openapi.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// Page is the pagination of the list operations. DM-master always returns all
+// the items, so the pagination is done on the client side.
+type Page struct {
+	// Offset is the number of items to skip.
+	Offset int
+	// Limit is the maximum number of items to return, zero means no limit.
+	Limit int
+}
+
+func paginate[T any](items []T, page *Page) []T {
+	if page == nil {
+		return items
+	}
+	if page.Offset >= len(items) {
+		return nil
+	}
+	items = items[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(items) {
+		items = items[:page.Limit]
+	}
+	return items
+}
+
+// checkResponse returns the error of a non-2xx response. If the error code is
+// a registered terror code, the error is generated from that terror, so callers
+// can check it with terror.ErrXXX.Equal.
+func checkResponse(resp *http.Response, body []byte, errResp *openapi.ErrorWithMessage) error {
+	if resp != nil && resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	if errResp == nil {
+		errResp = &openapi.ErrorWithMessage{}
+		if err := json.Unmarshal(body, errResp); err != nil || errResp.ErrorMsg == "" {
+			status := "no response"
+			if resp != nil {
+				status = resp.Status
+			}
+			return terror.ErrOpenAPICommonError.Generatef("unexpected response %s: %s", status, string(body))
+		}
+	}
+	if errResp.ErrorCode != 0 {
+		if tErr, ok := terror.ErrorByCode(terror.ErrCode(errResp.ErrorCode)); ok {
+			return tErr.Generatef("%s", terrorMessage(tErr, errResp.ErrorMsg))
+		}
+	}
+	return terror.ErrOpenAPICommonError.Generatef("%s", errResp.ErrorMsg)
+}
+
+// terrorPrefix matches the code, class, scope and level that terror.Error
+// prefixes to its message.
+var terrorPrefix = regexp.MustCompile(`^\[code=\d+:class=[^:\]]*:scope=[^:\]]*:level=[^\]]*\](, Message: |, )?`)
+
+// terrorMessage strips the prefix and the workaround of tErr from msg, which
+// is formatted by terror.Error.Error on DM-master, so they aren't repeated in
+// the error generated from tErr again.
+func terrorMessage(tErr *terror.Error, msg string) string {
+	msg = terrorPrefix.ReplaceAllString(msg, "")
+	if workaround := tErr.Workaround(); workaround != "" {
+		msg = strings.TrimSuffix(msg, ", Workaround: "+workaround)
+	}
+	return msg
+}
+
+// requestError wraps the error of sending a request.
+func requestError(err error) error {
+	return terror.ErrOpenAPICommonError.Delegate(err)
+}
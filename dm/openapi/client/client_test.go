@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pingcap/tiflow/dm/openapi"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	items := []int{1, 2, 3, 4, 5}
+	require.Equal(t, items, paginate(items, nil))
+	require.Equal(t, []int{3, 4, 5}, paginate(items, &Page{Offset: 2}))
+	require.Equal(t, []int{2, 3}, paginate(items, &Page{Offset: 1, Limit: 2}))
+	require.Equal(t, []int{5}, paginate(items, &Page{Offset: 4, Limit: 2}))
+	require.Nil(t, paginate(items, &Page{Offset: 5}))
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "root", username)
+		require.Equal(t, "secret", password)
+
+		rw.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/sources":
+			rw.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(rw).Encode(openapi.GetSourceListResponse{
+				Total: 3,
+				Data:  []openapi.Source{{SourceName: "s1"}, {SourceName: "s2"}, {SourceName: "s3"}},
+			})
+		case "/api/v1/sources/s4":
+			rw.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(rw).Encode(openapi.ErrorWithMessage{
+				ErrorCode: int(terror.ErrSchedulerSourceCfgNotExist.Code()),
+				ErrorMsg:  terror.ErrSchedulerSourceCfgNotExist.Generate("s4").Error(),
+			})
+		case "/api/v1/tasks/t1/start":
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, _ = rw.Write([]byte("internal error"))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cli, err := New(server.URL, WithBasicAuth("root", "secret"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	sources, total, err := cli.ListSources(ctx, false, &Page{Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Len(t, sources, 1)
+	require.Equal(t, "s2", sources[0].SourceName)
+
+	// the error code is mapped to the terror.
+	err = cli.DeleteSource(ctx, "s4", false)
+	require.True(t, terror.ErrSchedulerSourceCfgNotExist.Equal(err))
+	// the error isn't wrapped by the code of the error again.
+	require.Equal(t, terror.ErrSchedulerSourceCfgNotExist.Generate("s4").Error(), err.Error())
+
+	err = cli.StartTask(ctx, "t1", openapi.StartTaskRequest{})
+	require.True(t, terror.ErrOpenAPICommonError.Equal(err))
+	require.Contains(t, err.Error(), "internal error")
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/dm/openapi"
+)
+
+// ListSources returns the sources in the page, and the total number of sources.
+func (c *Client) ListSources(ctx context.Context, withStatus bool, page *Page) ([]openapi.Source, int, error) {
+	resp, err := c.api.DMAPIGetSourceListWithResponse(ctx, &openapi.DMAPIGetSourceListParams{WithStatus: &withStatus})
+	if err != nil {
+		return nil, 0, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, nil); err != nil {
+		return nil, 0, err
+	}
+	return paginate(resp.JSON200.Data, page), resp.JSON200.Total, nil
+}
+
+// GetSource returns the source.
+func (c *Client) GetSource(ctx context.Context, sourceName string, withStatus bool) (*openapi.Source, error) {
+	resp, err := c.api.DMAPIGetSourceWithResponse(ctx, sourceName, &openapi.DMAPIGetSourceParams{WithStatus: &withStatus})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, nil); err != nil {
+		return nil, err
+	}
+	return resp.JSON200, nil
+}
+
+// CreateSource creates a source, and binds it to workerName if it's not empty.
+func (c *Client) CreateSource(ctx context.Context, source openapi.Source, workerName string) (*openapi.Source, error) {
+	req := openapi.DMAPICreateSourceJSONRequestBody{Source: source}
+	if workerName != "" {
+		req.WorkerName = &workerName
+	}
+	resp, err := c.api.DMAPICreateSourceWithResponse(ctx, req)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400); err != nil {
+		return nil, err
+	}
+	return resp.JSON201, nil
+}
+
+// DeleteSource deletes a source, the related tasks are stopped if force is true.
+func (c *Client) DeleteSource(ctx context.Context, sourceName string, force bool) error {
+	resp, err := c.api.DMAPIDeleteSourceWithResponse(ctx, sourceName, &openapi.DMAPIDeleteSourceParams{Force: &force})
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// EnableSource enables a source.
+func (c *Client) EnableSource(ctx context.Context, sourceName string) error {
+	resp, err := c.api.DMAPIEnableSourceWithResponse(ctx, sourceName)
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// DisableSource disables a source.
+func (c *Client) DisableSource(ctx context.Context, sourceName string) error {
+	resp, err := c.api.DMAPIDisableSourceWithResponse(ctx, sourceName)
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// GetSourceStatus returns the status of the source on each worker.
+func (c *Client) GetSourceStatus(ctx context.Context, sourceName string) ([]openapi.SourceStatus, error) {
+	resp, err := c.api.DMAPIGetSourceStatusWithResponse(ctx, sourceName)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400); err != nil {
+		return nil, err
+	}
+	return resp.JSON200.Data, nil
+}
+
+// EnableRelay enables the relay log of a source on the workers.
+func (c *Client) EnableRelay(ctx context.Context, sourceName string, req openapi.EnableRelayRequest) error {
+	resp, err := c.api.DMAPIEnableRelayWithResponse(ctx, sourceName, openapi.DMAPIEnableRelayJSONRequestBody(req))
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// DisableRelay disables the relay log of a source on the workers.
+func (c *Client) DisableRelay(ctx context.Context, sourceName string, req openapi.DisableRelayRequest) error {
+	resp, err := c.api.DMAPIDisableRelayWithResponse(ctx, sourceName, openapi.DMAPIDisableRelayJSONRequestBody(req))
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// PurgeRelay purges the relay log files of a source.
+func (c *Client) PurgeRelay(ctx context.Context, sourceName string, req openapi.PurgeRelayRequest) error {
+	resp, err := c.api.DMAPIPurgeRelayWithResponse(ctx, sourceName, openapi.DMAPIPurgeRelayJSONRequestBody(req))
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
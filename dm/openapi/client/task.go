@@ -0,0 +1,101 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/dm/openapi"
+)
+
+// ListTasks returns the tasks in the page, and the total number of tasks.
+func (c *Client) ListTasks(ctx context.Context, params openapi.DMAPIGetTaskListParams, page *Page) ([]openapi.Task, int, error) {
+	resp, err := c.api.DMAPIGetTaskListWithResponse(ctx, &params)
+	if err != nil {
+		return nil, 0, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400); err != nil {
+		return nil, 0, err
+	}
+	return paginate(resp.JSON200.Data, page), resp.JSON200.Total, nil
+}
+
+// GetTask returns the task.
+func (c *Client) GetTask(ctx context.Context, taskName string, withStatus bool) (*openapi.Task, error) {
+	resp, err := c.api.DMAPIGetTaskWithResponse(ctx, taskName, &openapi.DMAPIGetTaskParams{WithStatus: &withStatus})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, nil); err != nil {
+		return nil, err
+	}
+	return resp.JSON200, nil
+}
+
+// CreateTask creates a task, the task is not started until StartTask is called.
+func (c *Client) CreateTask(ctx context.Context, task openapi.Task) (*openapi.Task, error) {
+	resp, err := c.api.DMAPICreateTaskWithResponse(ctx, openapi.DMAPICreateTaskJSONRequestBody{Task: task})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400); err != nil {
+		return nil, err
+	}
+	return resp.JSON201, nil
+}
+
+// DeleteTask deletes a task, the running subtasks are stopped if force is true.
+func (c *Client) DeleteTask(ctx context.Context, taskName string, force bool) error {
+	resp, err := c.api.DMAPIDeleteTaskWithResponse(ctx, taskName, &openapi.DMAPIDeleteTaskParams{Force: &force})
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// StartTask starts a task.
+func (c *Client) StartTask(ctx context.Context, taskName string, req openapi.StartTaskRequest) error {
+	resp, err := c.api.DMAPIStartTaskWithResponse(ctx, taskName, openapi.DMAPIStartTaskJSONRequestBody(req))
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// StopTask stops a task.
+func (c *Client) StopTask(ctx context.Context, taskName string, req openapi.StopTaskRequest) error {
+	resp, err := c.api.DMAPIStopTaskWithResponse(ctx, taskName, openapi.DMAPIStopTaskJSONRequestBody(req))
+	if err != nil {
+		return requestError(err)
+	}
+	return checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400)
+}
+
+// GetTaskStatus returns the status of the subtasks of the given sources, all
+// the subtasks are returned if sourceNames is empty.
+func (c *Client) GetTaskStatus(ctx context.Context, taskName string, sourceNames ...string) ([]openapi.SubTaskStatus, error) {
+	params := &openapi.DMAPIGetTaskStatusParams{}
+	if len(sourceNames) > 0 {
+		list := openapi.SourceNameList(sourceNames)
+		params.SourceNameList = &list
+	}
+	resp, err := c.api.DMAPIGetTaskStatusWithResponse(ctx, taskName, params)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if err = checkResponse(resp.HTTPResponse, resp.Body, resp.JSON400); err != nil {
+		return nil, err
+	}
+	return resp.JSON200.Data, nil
+}
@@ -90,6 +90,9 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// DMAPIGetErrorCatalog request
+	DMAPIGetErrorCatalog(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// DMAPIGetClusterInfo request
 	DMAPIGetClusterInfo(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -130,6 +133,9 @@ type ClientInterface interface {
 
 	DMAPIUpdateSource(ctx context.Context, sourceName string, body DMAPIUpdateSourceJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// DMAPIGetSourceBinlogStatistics request
+	DMAPIGetSourceBinlogStatistics(ctx context.Context, sourceName string, params *DMAPIGetSourceBinlogStatisticsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// DMAPIDisableSource request
 	DMAPIDisableSource(ctx context.Context, sourceName string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -211,6 +217,9 @@ type ClientInterface interface {
 
 	DMAPIUpdateTask(ctx context.Context, taskName string, body DMAPIUpdateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// DMAPIGetTaskRepairActions request
+	DMAPIGetTaskRepairActions(ctx context.Context, taskName string, params *DMAPIGetTaskRepairActionsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// DMAPIGetTaskMigrateTargets request
 	DMAPIGetTaskMigrateTargets(ctx context.Context, taskName string, sourceName string, params *DMAPIGetTaskMigrateTargetsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -245,6 +254,18 @@ type ClientInterface interface {
 	DMAPIStopTask(ctx context.Context, taskName string, body DMAPIStopTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
+func (c *Client) DMAPIGetErrorCatalog(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDMAPIGetErrorCatalogRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) DMAPIGetClusterInfo(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewDMAPIGetClusterInfoRequest(c.Server)
 	if err != nil {
@@ -413,6 +434,18 @@ func (c *Client) DMAPIUpdateSource(ctx context.Context, sourceName string, body
 	return c.Client.Do(req)
 }
 
+func (c *Client) DMAPIGetSourceBinlogStatistics(ctx context.Context, sourceName string, params *DMAPIGetSourceBinlogStatisticsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDMAPIGetSourceBinlogStatisticsRequest(c.Server, sourceName, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) DMAPIDisableSource(ctx context.Context, sourceName string, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewDMAPIDisableSourceRequest(c.Server, sourceName)
 	if err != nil {
@@ -773,6 +806,18 @@ func (c *Client) DMAPIUpdateTask(ctx context.Context, taskName string, body DMAP
 	return c.Client.Do(req)
 }
 
+func (c *Client) DMAPIGetTaskRepairActions(ctx context.Context, taskName string, params *DMAPIGetTaskRepairActionsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDMAPIGetTaskRepairActionsRequest(c.Server, taskName, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) DMAPIGetTaskMigrateTargets(ctx context.Context, taskName string, sourceName string, params *DMAPIGetTaskMigrateTargetsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewDMAPIGetTaskMigrateTargetsRequest(c.Server, taskName, sourceName, params)
 	if err != nil {
@@ -917,6 +962,33 @@ func (c *Client) DMAPIStopTask(ctx context.Context, taskName string, body DMAPIS
 	return c.Client.Do(req)
 }
 
+// NewDMAPIGetErrorCatalogRequest generates requests for DMAPIGetErrorCatalog
+func NewDMAPIGetErrorCatalogRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/cluster/errors")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewDMAPIGetClusterInfoRequest generates requests for DMAPIGetClusterInfo
 func NewDMAPIGetClusterInfoRequest(server string) (*http.Request, error) {
 	var err error
@@ -1370,6 +1442,58 @@ func NewDMAPIUpdateSourceRequestWithBody(server string, sourceName string, conte
 	return req, nil
 }
 
+// NewDMAPIGetSourceBinlogStatisticsRequest generates requests for DMAPIGetSourceBinlogStatistics
+func NewDMAPIGetSourceBinlogStatisticsRequest(server string, sourceName string, params *DMAPIGetSourceBinlogStatisticsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "source-name", runtime.ParamLocationPath, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/sources/%s/binlog-statistics", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.Top != nil {
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "top", runtime.ParamLocationQuery, *params.Top); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewDMAPIDisableSourceRequest generates requests for DMAPIDisableSource
 func NewDMAPIDisableSourceRequest(server string, sourceName string) (*http.Request, error) {
 	var err error
@@ -2248,6 +2372,58 @@ func NewDMAPIUpdateTaskRequestWithBody(server string, taskName string, contentTy
 	return req, nil
 }
 
+// NewDMAPIGetTaskRepairActionsRequest generates requests for DMAPIGetTaskRepairActions
+func NewDMAPIGetTaskRepairActionsRequest(server string, taskName string, params *DMAPIGetTaskRepairActionsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "task-name", runtime.ParamLocationPath, taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/tasks/%s/repair-actions", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.SourceNameList != nil {
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "source_name_list", runtime.ParamLocationQuery, *params.SourceNameList); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewDMAPIGetTaskMigrateTargetsRequest generates requests for DMAPIGetTaskMigrateTargets
 func NewDMAPIGetTaskMigrateTargetsRequest(server string, taskName string, sourceName string, params *DMAPIGetTaskMigrateTargetsParams) (*http.Request, error) {
 	var err error
@@ -2777,6 +2953,9 @@ func WithBaseURL(baseURL string) ClientOption {
 
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
+	// DMAPIGetErrorCatalog request
+	DMAPIGetErrorCatalogWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DMAPIGetErrorCatalogResponse, error)
+
 	// DMAPIGetClusterInfo request
 	DMAPIGetClusterInfoWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DMAPIGetClusterInfoResponse, error)
 
@@ -2817,6 +2996,9 @@ type ClientWithResponsesInterface interface {
 
 	DMAPIUpdateSourceWithResponse(ctx context.Context, sourceName string, body DMAPIUpdateSourceJSONRequestBody, reqEditors ...RequestEditorFn) (*DMAPIUpdateSourceResponse, error)
 
+	// DMAPIGetSourceBinlogStatistics request
+	DMAPIGetSourceBinlogStatisticsWithResponse(ctx context.Context, sourceName string, params *DMAPIGetSourceBinlogStatisticsParams, reqEditors ...RequestEditorFn) (*DMAPIGetSourceBinlogStatisticsResponse, error)
+
 	// DMAPIDisableSource request
 	DMAPIDisableSourceWithResponse(ctx context.Context, sourceName string, reqEditors ...RequestEditorFn) (*DMAPIDisableSourceResponse, error)
 
@@ -2898,6 +3080,9 @@ type ClientWithResponsesInterface interface {
 
 	DMAPIUpdateTaskWithResponse(ctx context.Context, taskName string, body DMAPIUpdateTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*DMAPIUpdateTaskResponse, error)
 
+	// DMAPIGetTaskRepairActions request
+	DMAPIGetTaskRepairActionsWithResponse(ctx context.Context, taskName string, params *DMAPIGetTaskRepairActionsParams, reqEditors ...RequestEditorFn) (*DMAPIGetTaskRepairActionsResponse, error)
+
 	// DMAPIGetTaskMigrateTargets request
 	DMAPIGetTaskMigrateTargetsWithResponse(ctx context.Context, taskName string, sourceName string, params *DMAPIGetTaskMigrateTargetsParams, reqEditors ...RequestEditorFn) (*DMAPIGetTaskMigrateTargetsResponse, error)
 
@@ -2932,6 +3117,28 @@ type ClientWithResponsesInterface interface {
 	DMAPIStopTaskWithResponse(ctx context.Context, taskName string, body DMAPIStopTaskJSONRequestBody, reqEditors ...RequestEditorFn) (*DMAPIStopTaskResponse, error)
 }
 
+type DMAPIGetErrorCatalogResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetErrorCatalogResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r DMAPIGetErrorCatalogResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DMAPIGetErrorCatalogResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type DMAPIGetClusterInfoResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3198,6 +3405,28 @@ func (r DMAPIUpdateSourceResponse) StatusCode() int {
 	return 0
 }
 
+type DMAPIGetSourceBinlogStatisticsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SourceBinlogStatistics
+}
+
+// Status returns HTTPResponse.Status
+func (r DMAPIGetSourceBinlogStatisticsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DMAPIGetSourceBinlogStatisticsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type DMAPIDisableSourceResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3672,6 +3901,29 @@ func (r DMAPIUpdateTaskResponse) StatusCode() int {
 	return 0
 }
 
+type DMAPIGetTaskRepairActionsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GetTaskRepairActionListResponse
+	JSON400      *ErrorWithMessage
+}
+
+// Status returns HTTPResponse.Status
+func (r DMAPIGetTaskRepairActionsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DMAPIGetTaskRepairActionsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type DMAPIGetTaskMigrateTargetsResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3875,6 +4127,15 @@ func (r DMAPIStopTaskResponse) StatusCode() int {
 	return 0
 }
 
+// DMAPIGetErrorCatalogWithResponse request returning *DMAPIGetErrorCatalogResponse
+func (c *ClientWithResponses) DMAPIGetErrorCatalogWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DMAPIGetErrorCatalogResponse, error) {
+	rsp, err := c.DMAPIGetErrorCatalog(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDMAPIGetErrorCatalogResponse(rsp)
+}
+
 // DMAPIGetClusterInfoWithResponse request returning *DMAPIGetClusterInfoResponse
 func (c *ClientWithResponses) DMAPIGetClusterInfoWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DMAPIGetClusterInfoResponse, error) {
 	rsp, err := c.DMAPIGetClusterInfo(ctx, reqEditors...)
@@ -3999,6 +4260,15 @@ func (c *ClientWithResponses) DMAPIUpdateSourceWithResponse(ctx context.Context,
 	return ParseDMAPIUpdateSourceResponse(rsp)
 }
 
+// DMAPIGetSourceBinlogStatisticsWithResponse request returning *DMAPIGetSourceBinlogStatisticsResponse
+func (c *ClientWithResponses) DMAPIGetSourceBinlogStatisticsWithResponse(ctx context.Context, sourceName string, params *DMAPIGetSourceBinlogStatisticsParams, reqEditors ...RequestEditorFn) (*DMAPIGetSourceBinlogStatisticsResponse, error) {
+	rsp, err := c.DMAPIGetSourceBinlogStatistics(ctx, sourceName, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDMAPIGetSourceBinlogStatisticsResponse(rsp)
+}
+
 // DMAPIDisableSourceWithResponse request returning *DMAPIDisableSourceResponse
 func (c *ClientWithResponses) DMAPIDisableSourceWithResponse(ctx context.Context, sourceName string, reqEditors ...RequestEditorFn) (*DMAPIDisableSourceResponse, error) {
 	rsp, err := c.DMAPIDisableSource(ctx, sourceName, reqEditors...)
@@ -4260,6 +4530,15 @@ func (c *ClientWithResponses) DMAPIUpdateTaskWithResponse(ctx context.Context, t
 	return ParseDMAPIUpdateTaskResponse(rsp)
 }
 
+// DMAPIGetTaskRepairActionsWithResponse request returning *DMAPIGetTaskRepairActionsResponse
+func (c *ClientWithResponses) DMAPIGetTaskRepairActionsWithResponse(ctx context.Context, taskName string, params *DMAPIGetTaskRepairActionsParams, reqEditors ...RequestEditorFn) (*DMAPIGetTaskRepairActionsResponse, error) {
+	rsp, err := c.DMAPIGetTaskRepairActions(ctx, taskName, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDMAPIGetTaskRepairActionsResponse(rsp)
+}
+
 // DMAPIGetTaskMigrateTargetsWithResponse request returning *DMAPIGetTaskMigrateTargetsResponse
 func (c *ClientWithResponses) DMAPIGetTaskMigrateTargetsWithResponse(ctx context.Context, taskName string, sourceName string, params *DMAPIGetTaskMigrateTargetsParams, reqEditors ...RequestEditorFn) (*DMAPIGetTaskMigrateTargetsResponse, error) {
 	rsp, err := c.DMAPIGetTaskMigrateTargets(ctx, taskName, sourceName, params, reqEditors...)
@@ -4365,6 +4644,31 @@ func (c *ClientWithResponses) DMAPIStopTaskWithResponse(ctx context.Context, tas
 	return ParseDMAPIStopTaskResponse(rsp)
 }
 
+// ParseDMAPIGetErrorCatalogResponse parses an HTTP response from a DMAPIGetErrorCatalogWithResponse call
+func ParseDMAPIGetErrorCatalogResponse(rsp *http.Response) (*DMAPIGetErrorCatalogResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DMAPIGetErrorCatalogResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetErrorCatalogResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+	}
+
+	return response, nil
+}
+
 // ParseDMAPIGetClusterInfoResponse parses an HTTP response from a DMAPIGetClusterInfoWithResponse call
 func ParseDMAPIGetClusterInfoResponse(rsp *http.Response) (*DMAPIGetClusterInfoResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
@@ -4679,6 +4983,31 @@ func ParseDMAPIUpdateSourceResponse(rsp *http.Response) (*DMAPIUpdateSourceRespo
 	return response, nil
 }
 
+// ParseDMAPIGetSourceBinlogStatisticsResponse parses an HTTP response from a DMAPIGetSourceBinlogStatisticsWithResponse call
+func ParseDMAPIGetSourceBinlogStatisticsResponse(rsp *http.Response) (*DMAPIGetSourceBinlogStatisticsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DMAPIGetSourceBinlogStatisticsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SourceBinlogStatistics
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+	}
+
+	return response, nil
+}
+
 // ParseDMAPIDisableSourceResponse parses an HTTP response from a DMAPIDisableSourceWithResponse call
 func ParseDMAPIDisableSourceResponse(rsp *http.Response) (*DMAPIDisableSourceResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
@@ -5300,6 +5629,39 @@ func ParseDMAPIUpdateTaskResponse(rsp *http.Response) (*DMAPIUpdateTaskResponse,
 	return response, nil
 }
 
+// ParseDMAPIGetTaskRepairActionsResponse parses an HTTP response from a DMAPIGetTaskRepairActionsWithResponse call
+func ParseDMAPIGetTaskRepairActionsResponse(rsp *http.Response) (*DMAPIGetTaskRepairActionsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DMAPIGetTaskRepairActionsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GetTaskRepairActionListResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorWithMessage
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseDMAPIGetTaskMigrateTargetsResponse parses an HTTP response from a DMAPIGetTaskMigrateTargetsWithResponse call
 func ParseDMAPIGetTaskMigrateTargetsResponse(rsp *http.Response) (*DMAPIGetTaskMigrateTargetsResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
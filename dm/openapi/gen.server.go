@@ -20,6 +20,9 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// get the error code catalog
+	// (GET /api/v1/cluster/errors)
+	DMAPIGetErrorCatalog(c *gin.Context)
 	// get cluster info such as cluster id
 	// (GET /api/v1/cluster/info)
 	DMAPIGetClusterInfo(c *gin.Context)
@@ -56,6 +59,9 @@ type ServerInterface interface {
 	// update a data source
 	// (PUT /api/v1/sources/{source-name})
 	DMAPIUpdateSource(c *gin.Context, sourceName string)
+	// get the binlog event statistics of the data source
+	// (GET /api/v1/sources/{source-name}/binlog-statistics)
+	DMAPIGetSourceBinlogStatistics(c *gin.Context, sourceName string, params DMAPIGetSourceBinlogStatisticsParams)
 	// disable a data source
 	// (POST /api/v1/sources/{source-name}/disable)
 	DMAPIDisableSource(c *gin.Context, sourceName string)
@@ -68,6 +74,9 @@ type ServerInterface interface {
 	// enable relay log function for the data source
 	// (POST /api/v1/sources/{source-name}/relay/enable)
 	DMAPIEnableRelay(c *gin.Context, sourceName string)
+	// seed relay log with the binlog files of a backup set
+	// (POST /api/v1/sources/{source-name}/relay/import)
+	DMAPIImportRelayBackup(c *gin.Context, sourceName string)
 	// purge relay log
 	// (POST /api/v1/sources/{source-name}/relay/purge)
 	DMAPIPurgeRelay(c *gin.Context, sourceName string)
@@ -119,6 +128,9 @@ type ServerInterface interface {
 	// update a task
 	// (PUT /api/v1/tasks/{task-name})
 	DMAPIUpdateTask(c *gin.Context, taskName string)
+	// get the safe repair actions of a paused task
+	// (GET /api/v1/tasks/{task-name}/repair-actions)
+	DMAPIGetTaskRepairActions(c *gin.Context, taskName string, params DMAPIGetTaskRepairActionsParams)
 	// get task source table and target table route relation
 	// (GET /api/v1/tasks/{task-name}/sources/{source-name}/migrate_targets)
 	DMAPIGetTaskMigrateTargets(c *gin.Context, taskName string, sourceName string, params DMAPIGetTaskMigrateTargetsParams)
@@ -156,6 +168,15 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// DMAPIGetErrorCatalog operation middleware
+func (siw *ServerInterfaceWrapper) DMAPIGetErrorCatalog(c *gin.Context) {
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.DMAPIGetErrorCatalog(c)
+}
+
 // DMAPIGetClusterInfo operation middleware
 func (siw *ServerInterfaceWrapper) DMAPIGetClusterInfo(c *gin.Context) {
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -370,6 +391,39 @@ func (siw *ServerInterfaceWrapper) DMAPIUpdateSource(c *gin.Context) {
 	siw.Handler.DMAPIUpdateSource(c, sourceName)
 }
 
+// DMAPIGetSourceBinlogStatistics operation middleware
+func (siw *ServerInterfaceWrapper) DMAPIGetSourceBinlogStatistics(c *gin.Context) {
+	var err error
+
+	// ------------- Path parameter "source-name" -------------
+	var sourceName string
+
+	err = runtime.BindStyledParameter("simple", false, "source-name", c.Param("source-name"), &sourceName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"msg": fmt.Sprintf("Invalid format for parameter source-name: %s", err)})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DMAPIGetSourceBinlogStatisticsParams
+
+	// ------------- Optional query parameter "top" -------------
+	if paramValue := c.Query("top"); paramValue != "" {
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "top", c.Request.URL.Query(), &params.Top)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"msg": fmt.Sprintf("Invalid format for parameter top: %s", err)})
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.DMAPIGetSourceBinlogStatistics(c, sourceName, params)
+}
+
 // DMAPIDisableSource operation middleware
 func (siw *ServerInterfaceWrapper) DMAPIDisableSource(c *gin.Context) {
 	var err error
@@ -450,6 +504,26 @@ func (siw *ServerInterfaceWrapper) DMAPIEnableRelay(c *gin.Context) {
 	siw.Handler.DMAPIEnableRelay(c, sourceName)
 }
 
+// DMAPIImportRelayBackup operation middleware
+func (siw *ServerInterfaceWrapper) DMAPIImportRelayBackup(c *gin.Context) {
+	var err error
+
+	// ------------- Path parameter "source-name" -------------
+	var sourceName string
+
+	err = runtime.BindStyledParameter("simple", false, "source-name", c.Param("source-name"), &sourceName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"msg": fmt.Sprintf("Invalid format for parameter source-name: %s", err)})
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.DMAPIImportRelayBackup(c, sourceName)
+}
+
 // DMAPIPurgeRelay operation middleware
 func (siw *ServerInterfaceWrapper) DMAPIPurgeRelay(c *gin.Context) {
 	var err error
@@ -794,6 +868,39 @@ func (siw *ServerInterfaceWrapper) DMAPIUpdateTask(c *gin.Context) {
 	siw.Handler.DMAPIUpdateTask(c, taskName)
 }
 
+// DMAPIGetTaskRepairActions operation middleware
+func (siw *ServerInterfaceWrapper) DMAPIGetTaskRepairActions(c *gin.Context) {
+	var err error
+
+	// ------------- Path parameter "task-name" -------------
+	var taskName string
+
+	err = runtime.BindStyledParameter("simple", false, "task-name", c.Param("task-name"), &taskName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"msg": fmt.Sprintf("Invalid format for parameter task-name: %s", err)})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DMAPIGetTaskRepairActionsParams
+
+	// ------------- Optional query parameter "source_name_list" -------------
+	if paramValue := c.Query("source_name_list"); paramValue != "" {
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "source_name_list", c.Request.URL.Query(), &params.SourceNameList)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"msg": fmt.Sprintf("Invalid format for parameter source_name_list: %s", err)})
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+	}
+
+	siw.Handler.DMAPIGetTaskRepairActions(c, taskName, params)
+}
+
 // DMAPIGetTaskMigrateTargets operation middleware
 func (siw *ServerInterfaceWrapper) DMAPIGetTaskMigrateTargets(c *gin.Context) {
 	var err error
@@ -1145,6 +1252,8 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 		HandlerMiddlewares: options.Middlewares,
 	}
 
+	router.GET(options.BaseURL+"/api/v1/cluster/errors", wrapper.DMAPIGetErrorCatalog)
+
 	router.GET(options.BaseURL+"/api/v1/cluster/info", wrapper.DMAPIGetClusterInfo)
 
 	router.GET(options.BaseURL+"/api/v1/cluster/masters", wrapper.DMAPIGetClusterMasterList)
@@ -1169,6 +1278,8 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 
 	router.PUT(options.BaseURL+"/api/v1/sources/:source-name", wrapper.DMAPIUpdateSource)
 
+	router.GET(options.BaseURL+"/api/v1/sources/:source-name/binlog-statistics", wrapper.DMAPIGetSourceBinlogStatistics)
+
 	router.POST(options.BaseURL+"/api/v1/sources/:source-name/disable", wrapper.DMAPIDisableSource)
 
 	router.POST(options.BaseURL+"/api/v1/sources/:source-name/enable", wrapper.DMAPIEnableSource)
@@ -1177,6 +1288,8 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 
 	router.POST(options.BaseURL+"/api/v1/sources/:source-name/relay/enable", wrapper.DMAPIEnableRelay)
 
+	router.POST(options.BaseURL+"/api/v1/sources/:source-name/relay/import", wrapper.DMAPIImportRelayBackup)
+
 	router.POST(options.BaseURL+"/api/v1/sources/:source-name/relay/purge", wrapper.DMAPIPurgeRelay)
 
 	router.GET(options.BaseURL+"/api/v1/sources/:source-name/schemas", wrapper.DMAPIGetSourceSchemaList)
@@ -1211,6 +1324,8 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 
 	router.PUT(options.BaseURL+"/api/v1/tasks/:task-name", wrapper.DMAPIUpdateTask)
 
+	router.GET(options.BaseURL+"/api/v1/tasks/:task-name/repair-actions", wrapper.DMAPIGetTaskRepairActions)
+
 	router.GET(options.BaseURL+"/api/v1/tasks/:task-name/sources/:source-name/migrate_targets", wrapper.DMAPIGetTaskMigrateTargets)
 
 	router.GET(options.BaseURL+"/api/v1/tasks/:task-name/sources/:source-name/schemas", wrapper.DMAPIGetSchemaListByTaskAndSource)
@@ -1234,103 +1349,125 @@ func RegisterHandlersWithOptions(router *gin.Engine, si ServerInterface, options
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
-	"H4sIAAAAAAAC/+w9XXPbOJJ/Bae7h5kpyZJsx0l8tQ9J7Mn6zvko21NzW1M5BSJBCWsSYADQHm3K/30L",
-	"HyRBEiAp23KssfdhxxFBoNHo7240vw8CmqSUICL44PD7gAdLlED157s44wKxD1D+v/whZTRFTGCkHsMw",
-	"VL+GiAcMpwJTMjhUvyLOAY2AWCIQZIwhIkCiJgGEhmgwHKA/YZLGaHA4mO6+3JnsTHamh692D6aD4UCs",
-	"Uvk7FwyTxeBmOIAxvkLNdSiJMUGACygysxrmZhl7BcEyVMw6pzRGkMhpYwRD5IAfc3smtQcztMekBCYK",
-	"1HJ/ehrHxm6GA4a+ZZihcHD4h34z32wB3VAj+UvxNp3/EwVCLmUO53fKLn/g4cxpRsIZpxkL0CzffXVN",
-	"NQToIUAOKQ7rWsHeXDZZ8W/xaNK2oIAL/1LyYeciaqxrheYZ6in6n6FEfRVSF6Kch0rJFWICsQvIL8/Q",
-	"twxx0TxbAfml/O9/MRQNDgf/OS45eGzYdywnkDPKsbOAkggvZhGOHUjTD4F8CDABK5jEIKIsgQIshUj5",
-	"4Xgc0oDvpJgsApjuBDQZ/2s5Fjicj7mA8xiN5SIjPU/GoJx3JKcbRVkc7zjR1rVznlLC0V9y6zbFqO04",
-	"IHXSBkNQoHNFQV7S0ATWhSE9iZxUk/bMR/OjbqI3K/ohvidSdmHOtegR5vJgzlAMV9ayNTkYyD+AoIAL",
-	"mgIImBwOmBk/rEFpYWkWYz1hG8RaKH+ECTqVo50Ef5Ql6blSXk3wSqUWZkkKMoKbMMllYyRQOFOEqH7T",
-	"tDs4HIQ0m8eoPDuSJXPE5LKIC5xAgWaCChjPGL3u+2aECeZLFM7mK4HWfmmNhTRkjl1hIg72yzcwEWgh",
-	"X6mThv3+sImoxlbqYLqx5CK2Y7IerUEmOolNPZ3NMYnpYrYQOHTSBxOYLMD7i5OjXJlnKRcMwQToVyvK",
-	"Dr2G0yjY3R2hYPJqNJ2i16P5LgxGk939XRhMp5PJZO9wOnr5av/1YDggWRzLfdXsnFJFVkB0a/0CRCnP",
-	"Sq3fDqZW/HNMdibyf7v9YQmxsXYimMWSVnbG+oFeogqbBCPEDAWCshW4XiKGFGj6XGK6AJhLwSDpqQcE",
-	"m5AOx4xR9jsWyw+Ic6etI0lG6RuA5NgGGalfZ4E0exrvqmcg0CZRnZuG5tWEL3xvJgaoLt1QTjS04XFx",
-	"0nskjEV7QiLqNwACPWjmYgvzDGB5bIXUyHqKDWvmdgC1PyTPzg9mCAWU/8UCJbyLIKp+VkkOkDG4KiSi",
-	"Upc9JJ8kd7l6+yY0Ed7/Jow/suFNaAPmHqEvLaLNg621/r0CbgyJDYMvDbF7xHlhp28Y5A94wZQdyhZI",
-	"8HsEvjLxQ+zkfiknm5dzPgT0F1KLnguWBSJjyL8LDeAsUN7DjH+Lq57Ju7PjNxfH4OLN29Nj8FVMv4Kf",
-	"vuLwK8BE/DSd/gw+froAH387PQVvfrv4NDv5+O7s+MPxx4vh57OTD2/O/gH+9/gf+o2fwfiXi//4w8h9",
-	"FM4wCdGfX8C709/OL47Pjo/AL+OfwfHH9ycfj/92Qgg9eguOjn9989vpBXj39zdn58cXf8tE9CqZ74N3",
-	"n05P31wc5/+WtpErtmC21nS3wrkz2qEsVsdw9fu0h3tZvJ7PZWHVdVSnFIbdXklMYej2SlqcBJ8aHg4S",
-	"JKCxJi1yK7FgPS8M4saglNEFQ5w7H2ozvj9MNTw2/AV7Pmvp6lYcgLtQ/kkZcsjFIZ0ehTYCEVBn6/Uo",
-	"ojjjy4p5rC3Z6qy/MywQV4awJlO5gIoSLlFwmVJMBODyFyjA0QcQQKLpAAsAI2l2MVQY/fK1PLbRCJLy",
-	"b/EsoEQg4tgb/xaDFc3ANSTC2mHFTXBIAPA1mJYiIOdSKQaG4Guw63+05350B77/byfjr0jQ3OxvaQhz",
-	"nNNU4ARzgQPAl5CFEo2SfqRUBddYLHU40xwNJfEKZByF0n0hABovANAgyBgHmHjnPDo6BUnF8i+Oph7Z",
-	"sc7JRbifM+ZyTEovKpDTZilIaYyDFahEyZr+yp8pZkaN5WQ6qdOoGqS9HoG1T1ksZ9v8OV97fDdL9sg/",
-	"2ZXWc8W6eweTxtIXSwTywZIwU8QwDXEA43ilWQTgqOlG6m2FQ2AmB1cwztAhUEvIc+IooCTkt4OeoQRi",
-	"MuMpDFBlB9MXdfg/YIKTLAERQ9L75ZdAvaVgeP/2Nsvf+GjiXmNvDxhr6IotVNZMUYCjlQGeZ3MrohBR",
-	"Bhpg74CTCBAqgH4TS5pQySUpAQSgBIFrHMdgjhRf74BzBamJRx+CXYheHuzv7Y+il6+j0XSKXo3mIdrN",
-	"Qzh7k8nkld7KtDtoUeP0Jo5d/K6O9Z1i4iY+lKLQ4fScKZssrqJlM/2wVNOWaniOfW1V7OvGRyXdJqQt",
-	"tqtUopORlj1YnaKGwzx5o9lEK5YSqT/VsDodgunrl69/djF7ZV0P8blo7g7E1k5cbhA04vLMrQTo/gEI",
-	"oAiWsyydJUXqvwrE9RKJJWJSiKuxIEu1jVKcjmUT+9jcKVfXo89y3ztjns3VlC7ry50uzpGoqbIy3VlG",
-	"iHy5S3JWidVJRPZ2XSfsQ3oOtksUnysrsAghN/lMW4lK9qiQ9LCMCXQ7nbU4wDkKMobFqrmMsk1NZp/z",
-	"uGrhafUWYRSHhWZb4jBERNusCyQKX8GeqDIJiBhN1BBle0XSzmmKpVqIGDExg3FMr1E4C0gT7Hc0SSgB",
-	"H41kPj8/BfIdHOEAao+uQFYncjiPZwH0+zPWxFpU5SNtanPSrJxY7sQ79a/WdHIfn48/GGth/H8vJq/z",
-	"THZta92rXqKVf9F35XryVFKGr+TWLtGqSKNbi3esV3c4qrh04KAJoJM7jK/zntEsdUTJwrjI0/Q/6Agz",
-	"LmYxDbSWcb0inTwUrjet0MFD19CMrD9hIwCkZh+We25spADbWtCJ1KKyoCZq9O9uW69il0Qw5o2oQ6FJ",
-	"lHOrJYB0m9TrFRFvXm9qE2NWluqy13pUmtnaiJTeXCYFlJLKXMscl3r3ghDF8Io6tJn+vahFKnBVM/tc",
-	"nLikTsGuMWTquNzFWq7ZUsj5NWWhd8ZiQHXKvf0XB875KPNDpx5a8+ztTQ5c3muaBxDawtQ6ylCaJoX/",
-	"0faS7apIxrQ0WGtIPB8n32mrabOq2XpXrmkroyl57pTdgfyyf+L5AvLLMu08HGTcZduZvcmHjf0xSkXP",
-	"iqA87Kzo2BCMWdIixyr35v9qEUAtNo9VYei3efSoUT/Dx8a+b73CeHSl37tz6NoW4jRBYimtoWtGXWZn",
-	"Tv68AKaT/EuquQMpM5TGOIAekq5VjzUDZqb40hja8QroCk0TWHaIwzXLznIiswFx0o70yFsL0RhK6BWa",
-	"JUhn0HorEf2eitQqK3YOuTKCQnpNjCuU/+wOhpf76MXDNS5wuuDngqatm93IopV0YtPoypK0J/ValXFr",
-	"FKP0ZqSYwrAnJFY2zCoMrjmskF/m3NOU+f05z++0don0czXQ2J89d3a+IkG5M5XKc+9MPgIKNptNVTpj",
-	"6DJZGeI0vkLhTFmcNLicefJ1rbIjL9p2osZdde0XCDkqzT6d8qFER0vMSu7anfY0/rye17HZucQEJguJ",
-	"FdcSdnLmeomDZRHgwRzkL6/llzaiaD3jXQ69EyAiZiLtm801CY3ZHC0xCa0QUp93C4fHISnls9YdVUb4",
-	"d6STt+gqv/HSAy5TUtobBxYfLKQT2nbmekDt2CFDICOjfBb76FvZuuL5dnqHNiLsTVZOfdgvyFU9Hudh",
-	"1PnAhSfLHbWZykdWLmZWWfS7xsZ8FRZNTrswJexN4ekTExGOJf5Yph1kGIZYvgXjz5XRXXL/LSandPGr",
-	"muxMzuVSy4gsIQnQTF+XmuW1NUtIFqizJMCyc7RhDniWSktepbhUhlnfwgrDGKRxtsCkzy0pVRahIakm",
-	"LsJkpAywOhwOY0pBwAVleZ7cmwMoJ/Ve9fFrdJsg+KXbbaBkFmbKTBaO2Zb0WuJvCUmow3VRjAOBQrUT",
-	"5fFkic7BpbGObub1vHhBKLPtWUvSST6fJc7iXnks13ClYvOUSnEABZKaxVosRZybyoDBcFCWCbgX05q1",
-	"n+etDBL1guV+38bz7axOU25jokvwCl6qn6SkWTMGqDHD/uV9So6YGr8af9XCd2vgRhcLHkEB30qXIPfh",
-	"3UeZQ27qNfLTi7I4lhshAUMJIrr6DsaqoqskWBjHfW2nEoQOgVEj9vr+nadSJyC3yHaIM1fAWyDF8HJi",
-	"DqDIk4AxukJxQ9xqBtIKzuE9yJ9z07YgipYxFdSCMIn7aAcDg6libNY6pVAIxFQ5hFYLfmB8w0u4/v+I",
-	"0bQbqhvPCfyaxbGhd8m8vvtyeWqGRkBSYsFfkoq4454S4ZgLRAJHAknJKCIYjUEutjAxppDKCekKGsqk",
-	"wIzUnYViNgA5z5ik1erZZIK6UCCn85RyCMqkAxli1hT7O+N8/ZkR2I2Z9YCZWDIEw2oB035dkymE6Rck",
-	"/gJKjMXnNCNx4p15euCcWr/RObWPAk5IwNajAEsIeQhAKrbZHIqgWoI4bZZY2XNJK3DJKMH/KpZScwD0",
-	"Jwoy9ZPkh28ZJAKrpdz1UWncE331jdwah9WqcLd1UbKMqklv4MxIzNJG6kzamjdEnnWxDBdfabGS3Gss",
-	"Yd7ou4Q7YGfWqwFcB6e2mE9l+I38woZrNfH5ZW8Lv7RpmrGtmsNZrjDZi4LJ7sHeaPdV8HI0naKXI3jw",
-	"Ym90EEzmr/bDF6+jvcnhdPRysj/d390bTl7sv9wP9wJr+Ku9F7uj3cleON/dPwjDvfBwOpq+nDhvxldL",
-	"kqyb7upBWRvmezOlVQTtOz30zcSSW6K7vsOvWJkeUEYMxVDqjvbaUyk6C6MlMGfcZcnVteWNtsjWnqcu",
-	"c6sWtxfJ9R31NmstSu4KENhweI8hD1Pm1um5oGmqHPiyiOZXU0Dv9C+ctra/7ksb9YLaIXbbxOc93e6a",
-	"9lQP1QQ5/TpEhnzcL3fEW9PlPenS9pE9IYwhuMZxGEAW5r551fmdj365Y2C6kTvzBaxFmfFvOmE9YBVO",
-	"WFvzPpa68OkJ4dHDJfXc52GEFHFd5WsCJfmOee1YprfEYM8FfBq5hp7+vRwcvmsLSsswTTtOn0CRw21q",
-	"DzaUmG9PxXtPHSWp5A9vNpFeIXbNsEBrJU6Lt7S1LcwqxR/d91PKdbtB9901jCCOVWcIftmMT7Vk9B2s",
-	"ayoxHE8drUtyAVZO6pRddaWSBQHi3APueqVhzbmGTWy4gNKXlu61D01/MaQXf+CWMrWGDW3ZyhZ3w1/a",
-	"0DzockXvNRpzX4aDXHsJasoteFv/mq5c6y1KMdqLL25UhERImRUf0cAR1zr6AD6liLz5fAKOPr2TkonF",
-	"g8NBVwemkdQxI235YUpMQyZthkdUUQIWaieNBa4Q43rtA6lNVBA/RQSmeHA42FM/ScEolgraMUzx+Go6",
-	"NheFx/n0xqwoGnGchGqtN59Pqs0sVDpNCyA13+5kYgJjeYktTHVEVW7jn1xXl5bmRhshe9pmKKzXtIfm",
-	"d3V6PEsSyFaDQ7kHULTNIBEFPAuWAHJQ6aUh4IJbLTIGX+QkdbTojBzvi5myi8bD4MfRtaMNS8PB/j2C",
-	"0Wjn4lhaC9+W87G69OViZp2DGX/Xfyj7/UazYYy01nac1KcoijFBGm0fdW4ghQwmSJ/yH41khQVe7kHJ",
-	"3yUfDfJc28CCYWCLEZ0rdEWj/B0UvzQIZ99hNT2yE6Uar7Wei70OMhfvPTmsbPHyMBzmaCmzZRxm9Ypc",
-	"i8PMwYy/G525FocZXd+Dw2zw/BxmwfC0Oaza+bP1IMNkJwfOyVnvkTiiwf+cf/roYaUqWHKu4qJPk9xC",
-	"GgC1XAlVSIMaRMZUagHn7xcfTnuBIwd2gLMUOp3pA0eb5N2ip2zM1EXMkr/yCx/q6mBRSK1o+luG2Moi",
-	"aiyWs2KEg4jdtSY3Q0fb4BVgSGRMt0jQdS0jc407L0h2gVC5vbwODF82K30dvbAcnGLfsIvzIt4aHdSH",
-	"lPSQe2QqKMJ95293KDWiB3Hxloare9uvqwmqY7dmaTCXa9808D+9N3gKD/bR6znd+AdAEua1XBAQdG2f",
-	"uuvAmzJg/N2KA3druSP1sCCKVpmwiOlc9dPICP6WVa+F+hVeNSzdS+F5r+k0BUZE9S0PmuaQwJib3hX5",
-	"xWTlfpvkt0t0qDnuKDO2QPFqOgCwi6aGfXTINtLKw+i0TeqTFnlWdEjcd9KiwTwVIFKd1Zv6pY0g0sxH",
-	"EHa4cQto4stm9J4r6HpTDcRJcG9+DGk8MjmUKWR1y6Eu3TYOdS9xFWP2mz2m4/h2kWiXz/DodItG8j0c",
-	"ankzveVMdWPv5yPd5JEWZuhdT1S5ZOsx61neoOppqhPXRxJujD7ZVslQdgiKMqJ7zOW3VO6HwNYQHE+c",
-	"vByfRdhW6jJCauPEVfTCaKGtsrni0yWtZoPJ/mbw46Y0RQGVvnjr05L18bYeLrbuItYnWLsB0vE34tis",
-	"g1vtnLYlCaq814guNfQFZ/uSx/i7/qOM4PUgFlWh+/hoZdhSjulZvtx7z+Wd1ZobpdLqFebtIlJdrXp7",
-	"Gi3aMPSRYEXzncejDVuvOTxILqj2mYgtIR/7g5T2Vz3vbmEJBgmPzFcy/ebVhRn21GONzeLDv4qJlRNC",
-	"IaoogLohuq4V6KAuneLpkkz5V3I6CUjSPOSXD5n9Nrdc5iu9ct4Xx7Vm/qyvwir6ELWt6uCP+rL11lTD",
-	"tcLTls7csKhtfAzJQYQKybHptvd4BG0BVUnuuva5T3r/Qjc22Vxy3y7u/pGpfVMjvjWJ/eKDK9Ujrcuv",
-	"cZB/aZd36EPzSd5NHrjrc8eOfeNIEy3mAJM0E7qbqhGeurN0vivdXLD8nq7uSkwZuMIBAleIcbhRqnF/",
-	"x3gLyOhCVUQpLBPTn9E0kKYRgPWu3A2k7vSgvPxqTz8dml/eeYAC1i2X5cXdqTsJ9Yvy4tUmeN0nTp/l",
-	"uVueV052HeYa6x4gHcL9RA16oHOvXyFcnwx2NwTP9shn09nl9mTxXXUwW6dor0Yda7nDdhM1hx9cwNLT",
-	"C/Z1X9vqQjn/xde6AO+tLLfnmCZPTrA39XXbkXsr4sorsM+HvjW1aH3PvSG/bye1HytFtFVXKxjQFSIA",
-	"R6qPN+DZPHf7WNFK5rm+2ufp91ATW0MXDxAc/RHSqeZE7vsal7VUUftPv6uG+jETwEbLpu8WUXyyOqt3",
-	"RNHSUZ4MXN4TLe932Cf+U/1s/9ZIrgcvf3BmUXTXXtMtduAra/il/4y6sW/7hGrMLw+f9W5Sy9blvlU2",
-	"zq6fgCQ03UHzj7vTTJjbZrhydfj2XNm7WqyoE3u7krh+Q8Lb5cifCFM+16+10be7iO3OVLxmUVtRzvZM",
-	"0s9ldlvLS85au3tmJfnePEZrxiDmMToXLAtExp556rHx1NDfYdSH8pwCeuPc/fmc7Y/XVziPWyS+bjTm",
-	"mUOeOWT6Y5ylKvFtv7PUyob+sNgn9dOzsrrN4k+FEe8/JllQXZMP/1rV1prj1lSb7VargJ2FLcWnfp9Y",
-	"qLvxieNtvXGrDvmWwed+d4esD8ttobAvWkxve/X8ll5TMhcnNPWsR5007RRe+svdT052VT9Yvr2ii6Z+",
-	"yaU+BsGu8hOtdjlf0WwnpAnERPU4H0hUmwncsmDQ1VY9pEHvXuqmefr4W4aDy5GSwCNdhzoq+35VZMzA",
-	"ZZmpbW8WqmsslqMwseBRyzahyfu8FuPyH26+3Pw7AAD//2uF9Jr0rAAA",
+	"H4sIAAAAAAACA+09bXPbRnN/5ar2w5MMKZKSLL90ng+2JSdqZTsjKU2fybgMCIAiIhBA8CJF8ei/d3fv",
+	"AByAPQCUSdmM1GemkYnD3d7e7t6+4/OOHS6jMHCDNNl59XknsRfu0qI/33iBH14eX8OjMyt18SfHTezY",
+	"i1IvDHZe7aQLV8TwRIRzYYn0NqK/ZvSacK9pysFOFIeRG6eeS5PSz1Mcy8ynZsB567O4f1rLyIeXdrwg",
+	"gengJznJTpLGXnC5czeQcydTWG2auHYYOM0lrGs3ti5dNa+AoUIOFV5A6yaplXpJ6tmJuPECJ7yBheZh",
+	"vLRSeNsJsxnAUCwdZMuZG+PSaZhaPrMj/DlfLPEC260vAn/Gqevoq3hBenhQLgL/dC9xFVgmdv/IvBiG",
+	"v/pVxyS39RymT8VE4ex3104RWnmyFxZs5n9CP1saztZeWAHg6pqGqEPGd7p2Uj3x2W0q/6gukHh/uYh0",
+	"epyfehze5NhSv9CCfbADyAlvmHXkJhyc+j6TSn6YBpZEUkmGzmzC0SBNzQxPueG1E9XXqsyk9jZQuGw5",
+	"0tgKEsvGrZ8Dft9k9pWbMlwgZvSkwEf5mqBzcTwEcZbR+PqB2mEWMJPSz8yMST9EZxES7wwmcaYGkgGq",
+	"87PEu3YFDRY0uEFD9c0MhDVLgKQEAEEDfCtJRRbQ20AYEhW6iJmM9w5W50eJFu5s3gLUqRu/t/D/47aq",
+	"+LQcJ2bOCH51k2JXdhbHuIklTSKC0HErUnGy93x3DP+bvHqxd8hSpuUD5prrhIHvBZKRM7Wal6hl9BXS",
+	"OHOLWWdh6LtWgNPCfx2XgR8m0WaSiJdDe0za5B85TTcPKX6Rmy2gG0gktxzOL2F89RUPR9J9EmaxXUqP",
+	"6pqS2uUQgUOKw7oh2JvLLm+TP/zhuG3BFC5D41L4sHMRGsut0DxDOUX/M0TUVyHlEMUeahjARQ9ncWEl",
+	"V2cwtZukzbNN4SH+9z9idw6v//uoVIVGSg8a4QRSridXU7hY597ldO75Lif/8KHAhyiTbq2lL6QMEYs0",
+	"jZJXo5ET2sluBFu2rWgXFhv9tRilnjMbJSTsR7jIUM6TgWIF8w5xuuE88/1dFm1dO09gP4n7t9y6TjG0",
+	"HQZSljZiF3TWc6IgI2lIAuvCkJwEJ5WkPTXR/LDH5S/nMkO8JlLmMMcteuQleDBnrm/dasvW5KC8ZtMQ",
+	"hEUYgX4Y43ARq/F1zUHD0tT35IRtEEuh/AGGn+JoluCPsmV0TpcXo2MWl5oDo+DO91JGm8GTAsV1SoRI",
+	"v/VQ+GF7Hoxxp6RlT3Pds8ebcy/wkoWraTmrvLTCQhIyZlc9FZrK+4MmohpbqYPJY4kjtuNgNVoDS6OT",
+	"2OjpVNqR00uQNSx9wPDgUvxwcXKUX+ZZBBzqWktlglYuO/elNZnbe3tD1x6/GE4m7svhbM+yh6Awwn8m",
+	"k/F4vP9qMnz+4uAlvBeA7CJLo6rnlFdkBUT+1i9ARHlW3vrtYMqLHx7sjvH/9vrD4nhK25lbmY+0sjuS",
+	"D+QSTRsRXoAzDONbcbNwY2kUynNB891DszCMySrshGAT0uE4jsP4rQXEF16epO6yKTltMAeSqsxObgN7",
+	"qIRFA0obFSB9+P7h+PA5Z9EsPOVT8WDhpGs3BOmZu3Qdj66/H+HtnXJLVhxbt1Lfvnb9KrwL73LBgboE",
+	"FVXpd41niR1GtasKQY8Dy98xnI0Vk6rXnK1hCJGKKBGbr5QDXkJVmfST6ezqGGkwyNKyAdHuEHjBIedE",
+	"XL4h8AjIcREIF2drCAkpVJqzXnmgACtOS7LLSxAwYCqq0TqrxVkAastyafFKcGXWz5y7IL7kLHRnaae+",
+	"UBMLMFyVioWkREApaYgohAuuCpPynSVXXtSpdhRb0tc3nsYvXrp4X1JVzZIEvEq087imX6c5/1TfpWdC",
+	"UU6TleSry+TS9GZJVO3bLSca6PBwG/7BTZVteBLMQ7MqbctBU+6CUc+EV3HwZX09CuXM7QBKzwJKQTOY",
+	"DgjB3sKo6rFgpFDh7+yjQyB14ertm5DifP2bUJb9hjehXzRrgr9xd214C9KaWeMBlObR5sGWJsBaAVdW",
+	"xYbBR6tsjTgvjPYNg/zeu4zJKMX7K1kj8JWJH2InZ25kefFrugfXeBD6tA+xjfUyQDYr53wI6CkWdQ6G",
+	"gZ1msWvehQqR2OQRmYKVU1Vh354dv744Fhev35wei9/SyW/iH795zm8CwPvHZPKd+PDxQnz4+fRUvP75",
+	"4uP05AOMf3/84WLw09nJ+9dn/xL/ffwv+cZ3YvT9xb/9qm5gMGhBH3T//CTenv58fnF8dnwkvh99J44/",
+	"/HDy4fifJ0EQHr0RR8fvXv98eiHe/vj67Pz44p9ZOn+xnB2Itx9PTwGq/N9o73Gq4toiTfj7pIfLTAsv",
+	"yX9oWOWO6gTIJE7JSH9j2VdZ1MdUd0FtrtqEN6BDgvGulNQZzQTjmga8fFSapBX9uDA7q/HicjoRyoCu",
+	"sxxKw3IAyrNYhL6T6C+gWZ0IVLLxRzpl+q2iUI+QfEfyjaFcohO/GvQ9kWmieQKxiQI8uiIC4dF0GM3S",
+	"tgUwFtzeJJ8KR9eAl69zcJ+GltPtbvNhFO9ua/F+mWODSze1lJuE3Yv2vPD0NAYBHJcYtOGxQf6p/jA1",
+	"8FVzhOnzaUtXt8IAzqH8I9lVLicmO/lP2mQq4m10lc1BzC0qfh/poqnO+kvsYZiVTGKSVbiAzBNw7aso",
+	"RFs7wV+sVBy9FzYY3UQHwHbWHK0gQEHuzZJReOm0b0T/QAChFz91OXsfHorbMBM3FixX7rDCr8w1IH6z",
+	"J+U9kItqvAsG8GjP/Giff/QFwv8/Wel/G9jNzf4cgeRROA/hxyXlWohkYcUOohHpB2WTFKoUp1NHEwb+",
+	"rcgSkAg3CzcoHCAitO0sTvJsF27Oo6NTsawY4sXR1EMW2jlxhPtTFnN+gvIqsHFaENdR6Hv2raiEf5ru",
+	"gz8jWDipkOm4TqM0SDohYGfkLC2W4/IQDE5JTfaQV+w6T+5R6+4fjhtLX9D9IQcjYQLoXuh4tuXDSRCL",
+	"CG/e9I/KbTkDoSYX8HrmvhK0BJ6TzOdJ7gd9DLTgBdMksmy3soPJszr870GGLbOlmMcuJYBcCXqLYPjh",
+	"zX2WvzPRxFqDSg/oRO9ymlfWjFzbm98q4JNsprnKMROlAfauOJmLIAQZSm96SBMyXQUkAKozLnC574uZ",
+	"S3y9K84JUhVofSX2LPf54cH+wXD+/OUcYxMvhjPH3ctjE/uwhxdyK5Nub3yN05s45vidjvUtMbEhoUw6",
+	"MXOmbLI4hYGm8mF5TWtXw1NQZ6uCOncmKulWIXWxXbMLJGrj8GbqLfn8ldqIOnoreH0HtzkbPSHnp6Z2",
+	"VteoHVWe/CC5sR4l+Eft8CYDMXn5/OV3nesaaJwj7S+g6XYa5kGQ55NnPiFA6wfAtlJ7MQUralmkzlWB",
+	"APIEFMR4V9BYQIZUhYrT0VRvkzRhxfdqbFDue3cEop6m5JQ8Pt0qR6Ik/sp0Z1kQ4MtdArpKrCwR6dvl",
+	"TtiE9BxsXuJrTi4mzTSx5ogyHJRHrCiNOLJINcVbUVkBprBcXxfbBYKG4dnCFuHPVHGrZrIUUT4Ji9SX",
+	"tRAb6mhBtiQrw+ni6r3x3t53fNiYQnlJR6gvQVK2oghUxhIEqQdEPmhj6L9wpf5uBZcgcWaxhemrQIuL",
+	"MPMd1A5AEvku5sXqwP6qRwVpo8OJGCaiyBH81N9Z0AhraqFzmh2gnVseAoHGBCV0k4sFGBEzyXV0c7iK",
+	"Yvfac2/4A3TncyC+/NSK8GWT19pyKbUsyp4Zk2y2Vu4+Y4Oo5T600+9ioovOwogKNyHwQJsEEWB+SKhG",
+	"t0+Akw8dx6c8yt+zJB1qnIH+iUugniHy53BZjYCWKDwnPisSLJpYlKYmKTCUsKGRULf7skZS5y6IQS+9",
+	"ZRgEDVx1YkniV81EyRugLQPt5+rxwnMc4GFi5Es3LRwO+kSVScDmCZfKAQgib47GUlO3qYV94c8p2Hbh",
+	"jetMbUb6vYUzh6k/KPXu/PxU4Dug2dtWupJzDog5AUvbMjtFtImlIpKP1Imb5RKcGHdinPqdNh3u46fj",
+	"98rkGP3vs/HLPM+ztrXuVa/cW/Oib8v1SPTF3jVuDd4pkky1xTvWq7NuFZcMDpoAcmx7rhwmP8RhFjHx",
+	"Fscvspj6H/Tci5MU9HLbMqaMoKfIdVabtkwzaQzNgtUnbIQSaPZBuefGRgqwtQVZpBZ5t5zANhiMFeNm",
+	"bvlJw3VZ6InkIZMSgO51fL1yqavXm7qisk1LZbjXeiHa6tISRZdQhgKK7uFEyhxOeTeCMPet65DRVeXv",
+	"RaZ+gavavcZx4iJkBbvEkKpy4EsZ2IvbSpKbMHaMMxYDqlPuHzw7ZOcLYzN09FCbZ39/fMi5wKLcC9mm",
+	"SkpXZWl4FE6Mdv2z9HcgY2o3WGtwNR+3fi1loAzopuT5onQHTKvvnZaJcYoyKRP4PeEsN7U3fNjYXxyG",
+	"6YoaGNGxIhi1pEaOVe7N/9UigGQ13XlR3GhyMSjdVquCVDeWpmrwBZG9qlSLurZKNez6qlZlCWlssdV2",
+	"9DNbjgtwCVV32ou+6oXEnIJzfz4AhdgHvcPAD2kYaSnwNbWafhdAIRjix11VK14BufiCG1BchIattmW9",
+	"wpbjq7JScYqVihyIHZWZqwDD1oYyYEkKmuZxhwZMvhtcpgstNJETiXwRH9wsPHsh8vrwRFgx6pDLKKuZ",
+	"0IfjztBqlc9rsA2azFSl6goBcBg3y4AWu0erwTPbPXLUsJ/xo0tg03qFe4hLq+3OjZX2UBIuQTVBer6J",
+	"Q86xlF+BSQFM5xVY3hwbY+NafVUz8qbKE5Urzb9VxcEqQs2oRCsWZhUEqAHC0g669ltLtWJ3GV67Uwzd",
+	"rqRIyvco5EvXy8xKpIgKbwLl7Mx/5qPq5T563eM1LmB9+efAXa2b3ciileS0puGVLaOe1KvVjq2QZN6b",
+	"kTD7oSckWlqNVjpbv66Sq5x7GKYtqh6mReFJ3WNfLYwo5DbtLul7m/QtVFlBFpgd5V2K5jkNVFZxT1yf",
+	"w9AS15SlxOMaH+WKTrU6iIM5C8BYCv1r15mSHRzaV1NDKlKrNMsLrVnU8JXSZhGVo1Ltk5VYJTpawnG4",
+	"az6jS8UQ8pYuzUpzxAT8jVjhltDzTqTekMdDwJbNX17JW9aI3PWMsTFMZVOPlahvoprSS6YzFxjM0cJW",
+	"fd4t3DCM7MZnrTuqjDDvSOalqa46/eBSZaC9caDxwSW6xtrOXA6oHTsqi1kwzGfpK5eq/rhOn5WOCH2T",
+	"lVMf9AusVY+HPYw6H3B40pxkOlOZyIpjZrI4vtRjb8ogbnLahSo7bwpPk5iYez7iL86k285yHA/fsvyf",
+	"KqO75D6YNKfh5Tua7Azn4hQFNwBzDuSibHEyzXPHycbrzHbUNC/pLhBJFqF/oegjozqnwHGKyM8uq/E2",
+	"U2cTyviUkFRzMpzlkFTCOhyMekcQYOw5TwE05h2Ukxrbc5h1DJ0gMFzIzQLahpOR4s41CFuANQj4A4Q7",
+	"Mogwh5GYpUzlAGXsCnV/GXPJKwe9yyCM+agUCY/pki0jJCvUuqV8gDBEcYBhA7hZtMUi0OJU0iP8WmZA",
+	"8ovJm7WfP5AUEnpBcwrexx/XWX1BzqylrJQpeIlxbgg1RtCYQf8qHJIjqhSnxl+1oMIKuJE1PUdw9m/Q",
+	"SMk9i/xR5pCrVNT89LAfCAU4bVRnA1ldYvlUsVASrOX7fXWnEoQOgVEj9vr+2VOpExAvshlxxoXh4Bky",
+	"PE6cCCvNfXJ5XXVV3EoGkhccY8+Q27Luw2sZU0GtcJZ+n9tBwaCqdJpp3JGVYsU5cqu8FszAmIaXcP3f",
+	"Eey/G6o7wwm8A7pS9I7Ma+pxkweMQSNGSiz4C6koYXqLBAkwvhvYt1xvQAqrxqEvcrFVeM7IfSyTg2VZ",
+	"x1yWgOezCStJAJSg5tOwsjRkq9BhOkOWKtwiaNLCw6bY3x3l60+VwG62XKQB03SB5ffV3OyD+k1GCJMv",
+	"IP5gO0rjY9VIWdHCzjw5ZKeWb3RObaKAExApq1GAJoQMBIAX23SGCVXVDTSzx/W5UAtcxGHg/VUsRXMA",
+	"8lw7k75g4Ic/MitIPVqKT/2Gtfuhr76Re+OwWrzJaxcly1DpaANnSmKWOlJnKol6I81jwR1dFwvJvcIS",
+	"6o2+S/AuRLVeDeA6OLXFTFeGWckvdLhWFT+56q3hlzpN09tWMzjLFcb7c3u8d7g/3HthP8cE0OdD6/DZ",
+	"/vDQHs9eHDjPXs73x5gAOj6YHOztD8bPDp4fOPu2NvzF/rO94d5435ntHRw6zr4DwyfPx2w3u2q2db0V",
+	"Rpn2bnozCqsIOmAt9M14t1v8zabDr2iZBlCG6MfHu6O9rAZFZ6G02OqMuzS5+m15JzWyleepy9yqxm1E",
+	"cn1HvdVajZK7HAQ6HMZjyN2UuXaK/vCIDPgycfedqg1k7QtW1zbnmkulHjQHzSrUVfykp9lduz3pIU2Q",
+	"0y8jMvBxv2hW0prE05MudRvZ4MIYYMafY4M5mNvmVeN3Nvx+bZmhCnaTwzot85CaRlgPWFMW1tZIlHZd",
+	"mO4JY3ehgnrWeRhOCHYJFTApR0m+46R2LJN7YrDnAqYbuYae/v0XGdu1BaWlm6Ydp48g9eo+GVEbShdq",
+	"TxAynroLa6BYNsU3w2s3vsG66JVCucVbUttO1SrFH92lt+W63aAb+wpQiv6UHCAN/1RLjgHDuio/rLs3",
+	"XDlUj7eysqt+qWS2DRxhAHe1hNXmXIMmNjigZD32WnvH9hdDcvEHbgNba7LYFq1sMTfMyRbNgy5XNFYI",
+	"q1LgROS3F/CUXCJp6znbFWu9R3JIezrIHXlIZFfFo5BLIzx6Lz5GbvD6pxNx9PEtSqbYR8d5R9fkId4x",
+	"Q6n5wUSqibJUw+chUYKX0k4aC4DgSOTah3ibkBMfBliRBz/t008oGNMFQTuC30fXk5FqhDNSqQLwRCkW",
+	"RdO/E4dWg4Vq3dAooiZlEL24Nx4r31ie+4/1Tp5MFB/9nsi091LjaKNlU+M1wnztBpE8TyeYZMulFcO1",
+	"hLsoUyCo/yAWEiqwQbtPtD58O5/w3TpGcoS34kNrJLhhdHAtC1fCRtGyEGYARcteCCsRlT6GvdAiY5RJ",
+	"X8yUHQwfBj9Mx8Q2LA12DtYIRqOVJrO0vI5azkf71kAueFc5mNFn+QdZNHdSMGF3Z8NJfZzPMSQi0fZB",
+	"RksiK4Z35Sn/2mzLWoKX25T4O0qWnTz6uKPBsKMLVhk95fxz5u9AfGoQzgGjR35jJxpKvNa+HNHrIPML",
+	"ryeHle01H4bDmHaeW8Zh2hcvVuIwdTCjz0qLWInDlPbTg8N08MwcpsHwuDms+v2S1oN0lrs5cCxnAZGD",
+	"Nvdf5x8/GFipChbOVRRkNskNdDxBy5VQwU81iJTy2ALOjxfvT3uBgwM7wFmkMsBrAkcaKd2ip+wo20XM",
+	"yF95YR41cCiS3YmmQd2PbzWihhHTYgRDxHz2zd2A+fgRduhJs1jW0chMn6Hq2ZMnjXMgVFrVrALDp81K",
+	"X6aJL8MpeiW0nyda1+igPqSkh9xGJTdRYjp//TsrSvSAzfYmdG7Xtl/uUy7MbtXSYoZr3zXwP1kbPIVN",
+	"/83fc7LVJzVlUNltlgjcm1rdWuPAmzJg9FnzjHffckf0sCCKVplw6Yczap6WBR4cYIUizRde1VHf68Jr",
+	"afrQCJWEshInjHJILD9Rjcry9jDkkFDpAJzooDm+UGZswcUr6UBYXTQ16HOHbCOtPMydtsn7pEWeFa3d",
+	"D1haVJgPMVsWv+/RvF/aCCLKTAShO2C3gCY+bebe49zQd1XXJIJ793VI4xuTQxkhq1sOdd1tef/npFIV",
+	"3kN2NYrJvym6bc9M4bs46h96xYtP1lHX66cNgi6lNE0GoAlXDLx58dY4ni1xU9TL8ju7EKxO8Y78BiDF",
+	"mcyKvvpS4HYJ5S4r+ZvTpiSS1yDGyp45LWcqP8j3dKSbPNLC8PrSEyUnxGrMepb3332cChT3cdM7pUFt",
+	"q2QoO5POs0B2DMkr1dZDYCsIjkdOXsznTLeVupSQ2jhxyeKNDuJqfDTl8ZKY8WM8D2wHmr9jswVqNH2d",
+	"qPZlosa3gahhceUzRfel8aITXQuJl99HeLy03fxGRH+i/rYJjiig0tp+dVpSQPR0Psgevn1CcBsgHXML",
+	"rM3a9dW+xVtiz+ddvmRKvSnk1pc84Ff6o4zL9CAWqkT59mhl0FJ2YFi+3HvP5dmqhI1SabVVx3YRqazK",
+	"uD+NFu2G+kiwou3dljhNHyTCX/vc5xb5LKtfu1iXq5I6O85l6UiLenWhhj32CFIzyf7vomLlhFCIqhDU",
+	"d/qmmcwA66AuGbjvkkz5R5s7CQhpnj4q8nA5TaqaE3v04sp5/zduzfxZ3wur6LfXtirDH/Vl600hBysF",
+	"bLQ7c8OitvFtboYICcm+6nX97QjaAqqS3GWNT5+krQvZwGtzKVt6EdPXTNhStVBbk65VfDKneqR1+QVr",
+	"B9dunBdgtJ23HLjJA89B6Thz/DYmEq2HHzeKslR+y0AJT/ldl3xXsq0vFuqpDwrSN0HCWFx7IHiwwMna",
+	"KNXUtrQ9KtgF5bkSlgPVGVl9voWcXLVv4jSQutuD8vIS1n53aF6k+gBlCVsuy4sa4S8S6hdlgfEmeN0k",
+	"Tp/kOS/PKye7CnOtEi55oHOvl8qvTgZ7G4Jne+Sz6mB2f7L4TJ06V0nFrlHHSuaw3iyUsYMLWHpawaYu",
+	"o1ud/mxu8FAX4L0vy+05pvGjE+zN+7rtyI15zmWrh6dD35oM477n3pDf95Pa3ypFtNXMEAyYrSrA3MMv",
+	"aOB3YHOzLy5apj1VzZgs/R7XxNbQxQM4R7+GdKoZkQemBp0ttTHm0++qjPmWCWCjxTBf5lF8tHdWb4+i",
+	"dkeN5Ceoh/I7av0cPfo3rpOtkVDch962PZ6hn8Q2duegz7lZ89qH0FWaXmRliaq+XZGm+ahy3s8271Xd",
+	"h9QrPbC3ktYfJqWH5ST5xQXV6X/HlKrzff8Z5UcZ2iekMd8/fCZHk1q2jhkpwqznBGFHAckt6oc4zFJV",
+	"F+9Vmpzcnyt7Z0AWuY9vbhHXrwPnfnkfj4Qpn3Iy2+ibT8z8YipeMVGzSNF8Iumn1NGt5SU2f3TNrITv",
+	"YTOn1fxqWPUKR22nWfzEU98aTw3M3eFNKM8poDfO+U8fbn8MqsJ5iUbiq3oYnzjkiUMmX8dYqhLf9htL",
+	"rWxodvV+pJ+eLqv7LP5YGHH9fvaC6pp8+PeqIJAct+K12a61plZnstY5jnmE4Zti39veKYEO+X4BlZ71",
+	"cNpHgZ8iKF8vgrKlpXeqGEhSz2rUGUadwiuMHqXsktveftEVRmbJRR/yiq/zE61+oeY2zHadcGl5AX2f",
+	"ZgdRrSbgZcFO1ydxsNt43+/gqA/fjIA07KshSeChzK0elh1KKzJmh9PMaNubhQozWIbOUoOHlm1Ck3ek",
+	"L8blP9x9uvt/OZJ5XK3LAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -33,6 +33,17 @@ const (
 	TaskTaskModeIncremental TaskTaskMode = "incremental"
 )
 
+// Defines values for RepairActionType.
+const (
+	RepairActionTypeAdjustCheckpoint RepairActionType = "adjust-checkpoint"
+
+	RepairActionTypeInjectDdl RepairActionType = "inject-ddl"
+
+	RepairActionTypeSkipEvent RepairActionType = "skip-event"
+
+	RepairActionTypeToggleSafeMode RepairActionType = "toggle-safe-mode"
+)
+
 // Defines values for TaskStage.
 const (
 	TaskStageFinished TaskStage = "Finished"
@@ -42,6 +53,38 @@ const (
 	TaskStageStopped TaskStage = "Stopped"
 )
 
+// the rate of a type of binlog events
+type BinlogEventRate struct {
+	// type of the binlog events
+	EventType string `json:"event_type"`
+
+	// average events per second in the statistics window
+	EventsPerSecond float64 `json:"events_per_second"`
+
+	// total events since the statistics started
+	Total int64 `json:"total"`
+}
+
+// the change volume of a table since the statistics started
+type BinlogTableVolume struct {
+	// size in bytes of the row events of the table
+	Bytes int64 `json:"bytes"`
+
+	// changed rows of the table
+	Rows       int64  `json:"rows"`
+	SchemaName string `json:"schema_name"`
+	TableName  string `json:"table_name"`
+}
+
+// a bucket of the transaction size distribution
+type BinlogTransactionSizeBucket struct {
+	// count of the transactions
+	Count int64 `json:"count"`
+
+	// inclusive upper bound in bytes of the transaction size, absent for the last unbounded bucket
+	UpperBoundBytes *int64 `json:"upper_bound_bytes,omitempty"`
+}
+
 // ClusterMaster defines model for ClusterMaster.
 type ClusterMaster struct {
 	// address of the current master node
@@ -129,6 +172,27 @@ type EnableRelayRequest struct {
 	WorkerNameList *WorkerNameList `json:"worker_name_list,omitempty"`
 }
 
+// ErrorCatalogItem defines model for ErrorCatalogItem.
+type ErrorCatalogItem struct {
+	Class      string                  `json:"class"`
+	Code       int                     `json:"code"`
+	Hints      *[]ErrorRemediationHint `json:"hints,omitempty"`
+	Level      string                  `json:"level"`
+	Message    string                  `json:"message"`
+	Scope      string                  `json:"scope"`
+	Workaround string                  `json:"workaround"`
+}
+
+// machine-readable remediation hint of an error
+type ErrorRemediationHint struct {
+	// kind of the suggested action
+	Action      string `json:"action"`
+	Description string `json:"description"`
+
+	// dmctl command or config item the action works on
+	Target *string `json:"target,omitempty"`
+}
+
 // operation error
 type ErrorWithMessage struct {
 	// error code
@@ -156,6 +220,12 @@ type GetClusterWorkerListResponse struct {
 	Total int             `json:"total"`
 }
 
+// GetErrorCatalogResponse defines model for GetErrorCatalogResponse.
+type GetErrorCatalogResponse struct {
+	Data  []ErrorCatalogItem `json:"data"`
+	Total int                `json:"total"`
+}
+
 // GetSourceListResponse defines model for GetSourceListResponse.
 type GetSourceListResponse struct {
 	Data  []Source `json:"data"`
@@ -180,6 +250,12 @@ type GetTaskMigrateTargetsResponse struct {
 	Total int                 `json:"total"`
 }
 
+// GetTaskRepairActionListResponse defines model for GetTaskRepairActionListResponse.
+type GetTaskRepairActionListResponse struct {
+	Data  []RepairAction `json:"data"`
+	Total int            `json:"total"`
+}
+
 // GetTaskStatusResponse defines model for GetTaskStatusResponse.
 type GetTaskStatusResponse struct {
 	Data  []SubTaskStatus `json:"data"`
@@ -193,6 +269,18 @@ type GetTaskTableStructureResponse struct {
 	TableName       string  `json:"table_name"`
 }
 
+// action to seed the relay log with a binlog backup set
+type ImportRelayBackupRequest struct {
+	// directory of the binlog backup set on the dm-worker, it holds the binlog files and the index file
+	BackupDir string `json:"backup_dir"`
+}
+
+// ImportRelayBackupResponse defines model for ImportRelayBackupResponse.
+type ImportRelayBackupResponse struct {
+	// names of the imported binlog files
+	Files []string `json:"files"`
+}
+
 // status of load unit
 type LoadStatus struct {
 	FinishedBytes  int64  `json:"finished_bytes"`
@@ -251,6 +339,9 @@ type RelayConfig struct {
 
 // status of relay log
 type RelayStatus struct {
+	// binlog_row_image of the upstream
+	BinlogRowImage *string `json:"binlog_row_image,omitempty"`
+
 	// upstream binlog file information
 	MasterBinlog string `json:"master_binlog"`
 
@@ -270,6 +361,28 @@ type RelayStatus struct {
 	Stage string `json:"stage"`
 }
 
+// a safe repair action of a paused sub task
+type RepairAction struct {
+	// type of the repair action
+	Action RepairActionType `json:"action"`
+
+	// the binlog checkpoint of the sub task when the action is enumerated
+	Checkpoint *string `json:"checkpoint,omitempty"`
+
+	// dmctl commands to apply the action, the placeholders in angle brackets should be filled in
+	Commands    []string `json:"commands"`
+	Description string   `json:"description"`
+
+	// the effect of the action
+	Preview string `json:"preview"`
+
+	// source name
+	SourceName string `json:"source_name"`
+}
+
+// type of the repair action
+type RepairActionType string
+
 // schema name list
 type SchemaNameList []string
 
@@ -337,6 +450,27 @@ type Source struct {
 	User string `json:"user"`
 }
 
+// binlog event statistics of the data source
+type SourceBinlogStatistics struct {
+	// average bytes of binlog events per second in the statistics window
+	BytesPerSecond float64 `json:"bytes_per_second"`
+
+	// rates of the binlog events by type
+	EventRates []BinlogEventRate `json:"event_rates"`
+
+	// source name
+	SourceName string `json:"source_name"`
+
+	// tables ordered by change volume in descending order
+	TopTables []BinlogTableVolume `json:"top_tables"`
+
+	// transaction size distribution
+	TransactionSizes []BinlogTransactionSizeBucket `json:"transaction_sizes"`
+
+	// length in seconds of the window in which the rates are computed
+	WindowSeconds int `json:"window_seconds"`
+}
+
 // source name list
 type SourceNameList []string
 
@@ -384,6 +518,9 @@ type SubTaskStatus struct {
 	// task name
 	Name string `json:"name"`
 
+	// remediation hints of the errors
+	RemediationHints *[]ErrorRemediationHint `json:"remediation_hints,omitempty"`
+
 	// source name
 	SourceName string    `json:"source_name"`
 	Stage      TaskStage `json:"stage"`
@@ -646,12 +783,21 @@ type DMAPIGetSourceParams struct {
 // DMAPIUpdateSourceJSONBody defines parameters for DMAPIUpdateSource.
 type DMAPIUpdateSourceJSONBody UpdateSourceRequest
 
+// DMAPIGetSourceBinlogStatisticsParams defines parameters for DMAPIGetSourceBinlogStatistics.
+type DMAPIGetSourceBinlogStatisticsParams struct {
+	// the count of the top tables by change volume
+	Top *int `json:"top,omitempty"`
+}
+
 // DMAPIDisableRelayJSONBody defines parameters for DMAPIDisableRelay.
 type DMAPIDisableRelayJSONBody DisableRelayRequest
 
 // DMAPIEnableRelayJSONBody defines parameters for DMAPIEnableRelay.
 type DMAPIEnableRelayJSONBody EnableRelayRequest
 
+// DMAPIImportRelayBackupJSONBody defines parameters for DMAPIImportRelayBackup.
+type DMAPIImportRelayBackupJSONBody ImportRelayBackupRequest
+
 // DMAPIPurgeRelayJSONBody defines parameters for DMAPIPurgeRelay.
 type DMAPIPurgeRelayJSONBody PurgeRelayRequest
 
@@ -697,6 +843,12 @@ type DMAPIGetTaskParams struct {
 // DMAPIUpdateTaskJSONBody defines parameters for DMAPIUpdateTask.
 type DMAPIUpdateTaskJSONBody UpdateTaskRequest
 
+// DMAPIGetTaskRepairActionsParams defines parameters for DMAPIGetTaskRepairActions.
+type DMAPIGetTaskRepairActionsParams struct {
+	// source name list
+	SourceNameList *SourceNameList `json:"source_name_list,omitempty"`
+}
+
 // DMAPIGetTaskMigrateTargetsParams defines parameters for DMAPIGetTaskMigrateTargets.
 type DMAPIGetTaskMigrateTargetsParams struct {
 	SchemaPattern *string `json:"schema_pattern,omitempty"`
@@ -730,6 +882,9 @@ type DMAPIDisableRelayJSONRequestBody DMAPIDisableRelayJSONBody
 // DMAPIEnableRelayJSONRequestBody defines body for DMAPIEnableRelay for application/json ContentType.
 type DMAPIEnableRelayJSONRequestBody DMAPIEnableRelayJSONBody
 
+// DMAPIImportRelayBackupJSONRequestBody defines body for DMAPIImportRelayBackup for application/json ContentType.
+type DMAPIImportRelayBackupJSONRequestBody DMAPIImportRelayBackupJSONBody
+
 // DMAPIPurgeRelayJSONRequestBody defines body for DMAPIPurgeRelay for application/json ContentType.
 type DMAPIPurgeRelayJSONRequestBody DMAPIPurgeRelayJSONBody
 
@@ -0,0 +1,60 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pingcap/tiflow/dm/pkg/metricsproxy"
+)
+
+var (
+	binlogEvents = metricsproxy.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dm",
+			Subsystem: "source",
+			Name:      "binlog_events_total",
+			Help:      "total number of binlog events of the source by type",
+		}, []string{"source_id", "type"})
+
+	binlogEventBytes = metricsproxy.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dm",
+			Subsystem: "source",
+			Name:      "binlog_event_bytes_total",
+			Help:      "total size in bytes of binlog events of the source",
+		}, []string{"source_id"})
+
+	binlogTxnSize = metricsproxy.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "dm",
+			Subsystem: "source",
+			Name:      "binlog_transaction_size_bytes",
+			Help:      "size distribution in bytes of binlog transactions of the source",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+		}, []string{"source_id"})
+)
+
+// RegisterMetrics registers metrics of the binlog statistics.
+func RegisterMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(binlogEvents)
+	registry.MustRegister(binlogEventBytes)
+	registry.MustRegister(binlogTxnSize)
+}
+
+func removeLabelValuesWithSource(source string) {
+	binlogEvents.DeleteAllAboutLabels(prometheus.Labels{"source_id": source})
+	binlogEventBytes.DeleteAllAboutLabels(prometheus.Labels{"source_id": source})
+	binlogTxnSize.DeleteAllAboutLabels(prometheus.Labels{"source_id": source})
+}
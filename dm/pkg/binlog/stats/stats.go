@@ -0,0 +1,514 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WindowSeconds is the length of the sliding window in which the event rates are computed.
+const WindowSeconds = 60
+
+// DefaultTopTables is the default count of tables returned in the top tables by change volume.
+const DefaultTopTables = 10
+
+// maxTables is the max count of tables whose change volume is kept, the least recently changed
+// table is evicted once it's exceeded, e.g. the tables dropped or no longer replicated.
+var maxTables = 10000
+
+// types of the binlog events in the statistics.
+const (
+	EventTypeInsert = "insert"
+	EventTypeUpdate = "update"
+	EventTypeDelete = "delete"
+	EventTypeDDL    = "ddl"
+	EventTypeCommit = "commit"
+	EventTypeOther  = "other"
+)
+
+var eventTypes = [...]string{EventTypeInsert, EventTypeUpdate, EventTypeDelete, EventTypeDDL, EventTypeCommit, EventTypeOther}
+
+// txnSizeBounds are the upper bounds in bytes of the buckets of the transaction size distribution,
+// the last bucket which holds the larger transactions is unbounded.
+var txnSizeBounds = []int64{1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20}
+
+// Statistics is a snapshot of the binlog event statistics of a source.
+type Statistics struct {
+	SourceName       string                  `json:"source_name"`
+	WindowSeconds    int                     `json:"window_seconds"`
+	BytesPerSecond   float64                 `json:"bytes_per_second"`
+	EventRates       []EventRate             `json:"event_rates"`
+	TopTables        []TableVolume           `json:"top_tables"`
+	TransactionSizes []TransactionSizeBucket `json:"transaction_sizes"`
+}
+
+// EventRate is the rate of a type of binlog events.
+type EventRate struct {
+	EventType       string  `json:"event_type"`
+	EventsPerSecond float64 `json:"events_per_second"`
+	Total           int64   `json:"total"`
+}
+
+// TableVolume is the change volume of a table since the statistics started.
+type TableVolume struct {
+	SchemaName string `json:"schema_name"`
+	TableName  string `json:"table_name"`
+	Rows       int64  `json:"rows"`
+	Bytes      int64  `json:"bytes"`
+}
+
+// TransactionSizeBucket is a bucket of the transaction size distribution.
+type TransactionSizeBucket struct {
+	// UpperBoundBytes is nil for the last unbounded bucket.
+	UpperBoundBytes *int64 `json:"upper_bound_bytes,omitempty"`
+	Count           int64  `json:"count"`
+}
+
+type secondBucket struct {
+	sec    int64
+	events [len(eventTypes)]int64
+	bytes  int64
+}
+
+type tableKey struct {
+	schema string
+	table  string
+}
+
+type tableVolume struct {
+	rows  int64
+	bytes int64
+	// lastSec is the second the table is changed last time.
+	lastSec int64
+}
+
+// feeder is a reader of the binlog of a source, either a sub task or the relay.
+type feeder struct {
+	task  string
+	relay bool
+	// raw means the events are not parsed, so they can't be attributed to tables.
+	raw bool
+}
+
+// Collector aggregates the statistics of the binlog events of a source.
+// The relay and several sub tasks may read the binlog of the same source, to not count an
+// event more than once only the events observed by the first feeder are aggregated. The relay
+// is always the first feeder if it's enabled, because it reads the binlog once for all tasks.
+// The change volume of tables is aggregated from the first feeder which parses the events.
+type Collector struct {
+	source string
+	now    func() time.Time
+
+	mu        sync.Mutex
+	feeders   []*feeder
+	startSec  int64
+	buckets   [WindowSeconds]secondBucket
+	totals    [len(eventTypes)]int64
+	tables    map[tableKey]*tableVolume
+	txnSizes  []int64
+	inTxn     bool
+	txnBytes  int64
+	eventsCnt []prometheus.Counter
+	bytesCnt  prometheus.Counter
+	txnHist   prometheus.Observer
+}
+
+func newCollector(source string) *Collector {
+	c := &Collector{
+		source:    source,
+		now:       time.Now,
+		tables:    make(map[tableKey]*tableVolume),
+		txnSizes:  make([]int64, len(txnSizeBounds)+1),
+		eventsCnt: make([]prometheus.Counter, len(eventTypes)),
+		bytesCnt:  binlogEventBytes.WithLabelValues(source),
+		txnHist:   binlogTxnSize.WithLabelValues(source),
+	}
+	for i, tp := range eventTypes {
+		c.eventsCnt[i] = binlogEvents.WithLabelValues(source, tp)
+	}
+	c.startSec = c.now().Unix()
+	return c
+}
+
+// Recorder records the binlog events read by a sub task or the relay into the collector of its source.
+type Recorder struct {
+	c *Collector
+	f *feeder
+}
+
+// Observe records a binlog event. Observe on a nil Recorder is a no-op.
+func (r *Recorder) Observe(e *replication.BinlogEvent) {
+	if r == nil {
+		return
+	}
+	r.c.observe(r.f, e)
+}
+
+var rowsEventTypes = map[replication.EventType]string{
+	replication.WRITE_ROWS_EVENTv0:  EventTypeInsert,
+	replication.WRITE_ROWS_EVENTv1:  EventTypeInsert,
+	replication.WRITE_ROWS_EVENTv2:  EventTypeInsert,
+	replication.UPDATE_ROWS_EVENTv0: EventTypeUpdate,
+	replication.UPDATE_ROWS_EVENTv1: EventTypeUpdate,
+	replication.UPDATE_ROWS_EVENTv2: EventTypeUpdate,
+	replication.DELETE_ROWS_EVENTv0: EventTypeDelete,
+	replication.DELETE_ROWS_EVENTv1: EventTypeDelete,
+	replication.DELETE_ROWS_EVENTv2: EventTypeDelete,
+}
+
+// observe aggregates a binlog event. The events are classified by their header, so the
+// events not parsed by the relay in raw mode are counted too.
+func (c *Collector) observe(f *feeder, e *replication.BinlogEvent) {
+	if e.Header.EventType == replication.HEARTBEAT_EVENT {
+		return
+	}
+	size := int64(e.Header.EventSize)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	countEvent := len(c.feeders) != 0 && c.feeders[0] == f
+	countTable := c.tableFeeder() == f
+	if !countEvent && !countTable {
+		return
+	}
+
+	if tp, ok := rowsEventTypes[e.Header.EventType]; ok {
+		if ev, ok := e.Event.(*replication.RowsEvent); ok && countTable && ev.Table != nil {
+			rows := int64(len(ev.Rows))
+			if tp == EventTypeUpdate {
+				// an updated row has both the before and after image.
+				rows /= 2
+			}
+			key := tableKey{schema: string(ev.Table.Schema), table: string(ev.Table.Table)}
+			vol, ok := c.tables[key]
+			if !ok {
+				if len(c.tables) >= maxTables {
+					c.evictTable()
+				}
+				vol = &tableVolume{}
+				c.tables[key] = vol
+			}
+			vol.rows += rows
+			vol.bytes += size
+			vol.lastSec = c.now().Unix()
+		}
+		if countEvent {
+			if c.inTxn {
+				c.txnBytes += size
+			}
+			c.count(tp, size)
+		}
+		return
+	}
+	if !countEvent {
+		return
+	}
+
+	tp := EventTypeOther
+	switch e.Header.EventType {
+	case replication.GTID_EVENT, replication.MARIADB_GTID_EVENT:
+		c.inTxn = true
+		c.txnBytes = size
+	case replication.QUERY_EVENT:
+		query, ok := queryOf(e)
+		switch {
+		case !ok:
+			if c.inTxn {
+				c.txnBytes += size
+			}
+		case strings.EqualFold(query, "BEGIN"):
+			c.inTxn = true
+			c.txnBytes += size
+		case strings.EqualFold(query, "COMMIT"):
+			tp = EventTypeCommit
+			c.txnBytes += size
+			c.endTxn()
+		default:
+			// a DDL is committed by itself.
+			tp = EventTypeDDL
+			c.txnBytes += size
+			c.endTxn()
+		}
+	case replication.XID_EVENT:
+		tp = EventTypeCommit
+		c.txnBytes += size
+		c.endTxn()
+	default:
+		if c.inTxn {
+			c.txnBytes += size
+		}
+	}
+	c.count(tp, size)
+}
+
+// queryOf returns the trimmed query of a query event, the query is decoded from the
+// raw data if the event is not parsed.
+func queryOf(e *replication.BinlogEvent) (string, bool) {
+	switch ev := e.Event.(type) {
+	case *replication.QueryEvent:
+		return strings.TrimSpace(string(ev.Query)), true
+	case *replication.GenericEvent:
+		qe := &replication.QueryEvent{}
+		if err := qe.Decode(ev.Data); err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(string(qe.Query)), true
+	}
+	return "", false
+}
+
+func (c *Collector) count(tp string, size int64) {
+	idx := eventTypeIndex(tp)
+	b := c.bucket(c.now().Unix())
+	b.events[idx]++
+	b.bytes += size
+	c.totals[idx]++
+	c.eventsCnt[idx].Inc()
+	c.bytesCnt.Add(float64(size))
+}
+
+// tableFeeder returns the first feeder which parses the events, or nil if there is none.
+func (c *Collector) tableFeeder() *feeder {
+	for _, f := range c.feeders {
+		if !f.raw {
+			return f
+		}
+	}
+	return nil
+}
+
+// evictTable evicts the least recently changed table.
+func (c *Collector) evictTable() {
+	var (
+		oldest    tableKey
+		oldestSec int64
+		found     bool
+	)
+	for key, vol := range c.tables {
+		if !found || vol.lastSec < oldestSec {
+			oldest, oldestSec, found = key, vol.lastSec, true
+		}
+	}
+	delete(c.tables, oldest)
+}
+
+func (c *Collector) endTxn() {
+	i := sort.Search(len(txnSizeBounds), func(i int) bool { return c.txnBytes <= txnSizeBounds[i] })
+	c.txnSizes[i]++
+	c.txnHist.Observe(float64(c.txnBytes))
+	c.inTxn = false
+	c.txnBytes = 0
+}
+
+// bucket returns the bucket of the second sec, the bucket is reset if it holds an expired second.
+func (c *Collector) bucket(sec int64) *secondBucket {
+	b := &c.buckets[sec%WindowSeconds]
+	if b.sec != sec {
+		*b = secondBucket{sec: sec}
+	}
+	return b
+}
+
+func eventTypeIndex(tp string) int {
+	for i, t := range eventTypes {
+		if t == tp {
+			return i
+		}
+	}
+	return len(eventTypes) - 1
+}
+
+// Snapshot returns the current statistics, with at most topN tables in the top tables.
+func (c *Collector) Snapshot(topN int) *Statistics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nowSec := c.now().Unix()
+	span := nowSec - c.startSec + 1
+	if span > WindowSeconds {
+		span = WindowSeconds
+	}
+	var (
+		events [len(eventTypes)]int64
+		bytes  int64
+	)
+	for _, b := range c.buckets {
+		if b.sec > nowSec-span && b.sec <= nowSec {
+			for i := range events {
+				events[i] += b.events[i]
+			}
+			bytes += b.bytes
+		}
+	}
+
+	st := &Statistics{
+		SourceName:       c.source,
+		WindowSeconds:    WindowSeconds,
+		BytesPerSecond:   float64(bytes) / float64(span),
+		EventRates:       make([]EventRate, 0, len(eventTypes)),
+		TopTables:        make([]TableVolume, 0, len(c.tables)),
+		TransactionSizes: make([]TransactionSizeBucket, 0, len(c.txnSizes)),
+	}
+	for i, tp := range eventTypes {
+		st.EventRates = append(st.EventRates, EventRate{
+			EventType:       tp,
+			EventsPerSecond: float64(events[i]) / float64(span),
+			Total:           c.totals[i],
+		})
+	}
+	for key, vol := range c.tables {
+		st.TopTables = append(st.TopTables, TableVolume{
+			SchemaName: key.schema,
+			TableName:  key.table,
+			Rows:       vol.rows,
+			Bytes:      vol.bytes,
+		})
+	}
+	sort.Slice(st.TopTables, func(i, j int) bool {
+		a, b := st.TopTables[i], st.TopTables[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		if a.SchemaName != b.SchemaName {
+			return a.SchemaName < b.SchemaName
+		}
+		return a.TableName < b.TableName
+	})
+	if topN >= 0 && len(st.TopTables) > topN {
+		st.TopTables = st.TopTables[:topN]
+	}
+	for i, cnt := range c.txnSizes {
+		bucket := TransactionSizeBucket{Count: cnt}
+		if i < len(txnSizeBounds) {
+			bound := txnSizeBounds[i]
+			bucket.UpperBoundBytes = &bound
+		}
+		st.TransactionSizes = append(st.TransactionSizes, bucket)
+	}
+	return st
+}
+
+var (
+	collectorsMu sync.Mutex
+	collectors   = make(map[string]*Collector)
+)
+
+func getOrNewCollector(source string) *Collector {
+	c, ok := collectors[source]
+	if !ok {
+		c = newCollector(source)
+		collectors[source] = c
+	}
+	return c
+}
+
+// Register registers task as a reader of the binlog of source and returns the Recorder for it.
+func Register(source, task string) *Recorder {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	c := getOrNewCollector(source)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.feeders {
+		if !f.relay && f.task == task {
+			return &Recorder{c: c, f: f}
+		}
+	}
+	f := &feeder{task: task}
+	c.feeders = append(c.feeders, f)
+	return &Recorder{c: c, f: f}
+}
+
+// RegisterRelay registers the relay of source as a reader of its binlog and returns the
+// Recorder for it, the relay takes precedence over the sub tasks. raw means the relay doesn't
+// parse the events, then the change volume of tables is still aggregated from a sub task.
+func RegisterRelay(source string, raw bool) *Recorder {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	c := getOrNewCollector(source)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.feeders {
+		if f.relay {
+			f.raw = raw
+			return &Recorder{c: c, f: f}
+		}
+	}
+	f := &feeder{relay: true, raw: raw}
+	if len(c.feeders) != 0 {
+		// the relay may be at another position of the binlog than the task.
+		c.inTxn = false
+		c.txnBytes = 0
+	}
+	c.feeders = append([]*feeder{f}, c.feeders...)
+	return &Recorder{c: c, f: f}
+}
+
+// Unregister unregisters task from the readers of the binlog of source. The statistics of
+// source are dropped after its last reader is unregistered, and the change volume of tables
+// is dropped once the reader it's aggregated from is unregistered, since the next one may
+// replicate other tables.
+func Unregister(source, task string) {
+	unregister(source, func(f *feeder) bool { return !f.relay && f.task == task })
+}
+
+// UnregisterRelay unregisters the relay from the readers of the binlog of source.
+func UnregisterRelay(source string) {
+	unregister(source, func(f *feeder) bool { return f.relay })
+}
+
+func unregister(source string, match func(*feeder) bool) {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	c, ok := collectors[source]
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	tableFeeder := c.tableFeeder()
+	for i, f := range c.feeders {
+		if !match(f) {
+			continue
+		}
+		if f == tableFeeder {
+			c.tables = make(map[tableKey]*tableVolume)
+		}
+		c.feeders = append(c.feeders[:i], c.feeders[i+1:]...)
+		if i == 0 {
+			// the next reader may be at another position of the binlog.
+			c.inTxn = false
+			c.txnBytes = 0
+		}
+		break
+	}
+	empty := len(c.feeders) == 0
+	c.mu.Unlock()
+	if empty {
+		delete(collectors, source)
+		removeLabelValuesWithSource(source)
+	}
+}
+
+// Get returns the collector of source, or nil if no task reads the binlog of source.
+func Get(source string) *Collector {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	return collectors[source]
+}
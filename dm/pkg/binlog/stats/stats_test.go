@@ -0,0 +1,222 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent(tp replication.EventType, size uint32, ev replication.Event) *replication.BinlogEvent {
+	return &replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: tp, EventSize: size},
+		Event:  ev,
+	}
+}
+
+func rowsEvent(tp replication.EventType, size uint32, schema, table string, rows int) *replication.BinlogEvent {
+	return newEvent(tp, size, &replication.RowsEvent{
+		Table: &replication.TableMapEvent{Schema: []byte(schema), Table: []byte(table)},
+		Rows:  make([][]interface{}, rows),
+	})
+}
+
+func TestCollector(t *testing.T) {
+	source := "mysql-replica-01"
+	rec := Register(source, "task-1")
+	other := Register(source, "task-2")
+	c := Get(source)
+	require.NotNil(t, c)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+	c.startSec = now.Unix() - WindowSeconds
+
+	gtid := newEvent(replication.GTID_EVENT, 100, &replication.GTIDEvent{})
+	begin := newEvent(replication.QUERY_EVENT, 100, &replication.QueryEvent{Query: []byte("BEGIN")})
+	xid := newEvent(replication.XID_EVENT, 100, &replication.XIDEvent{})
+	ddl := newEvent(replication.QUERY_EVENT, 200, &replication.QueryEvent{Query: []byte("ALTER TABLE tb ADD COLUMN c INT")})
+
+	// a 2000 bytes transaction.
+	for _, e := range []*replication.BinlogEvent{
+		gtid, begin,
+		rowsEvent(replication.WRITE_ROWS_EVENTv2, 1000, "db", "tb1", 10),
+		rowsEvent(replication.UPDATE_ROWS_EVENTv2, 700, "db", "tb2", 4),
+		xid,
+	} {
+		rec.Observe(e)
+		// events of the other task are not counted again.
+		other.Observe(e)
+	}
+	// a DDL and a heartbeat.
+	rec.Observe(gtid)
+	rec.Observe(ddl)
+	rec.Observe(newEvent(replication.HEARTBEAT_EVENT, 100, &replication.GenericEvent{}))
+	// a delete transaction without GTID.
+	for _, e := range []*replication.BinlogEvent{
+		begin,
+		rowsEvent(replication.DELETE_ROWS_EVENTv2, 5000, "db", "tb2", 50),
+		xid,
+	} {
+		rec.Observe(e)
+	}
+
+	st := c.Snapshot(1)
+	require.Equal(t, source, st.SourceName)
+	require.Equal(t, WindowSeconds, st.WindowSeconds)
+	require.InDelta(t, float64(7500)/WindowSeconds, st.BytesPerSecond, 1e-9)
+	totals := make(map[string]int64)
+	for _, rate := range st.EventRates {
+		totals[rate.EventType] = rate.Total
+	}
+	require.Equal(t, map[string]int64{
+		EventTypeInsert: 1,
+		EventTypeUpdate: 1,
+		EventTypeDelete: 1,
+		EventTypeDDL:    1,
+		EventTypeCommit: 2,
+		EventTypeOther:  4,
+	}, totals)
+	require.Equal(t, []TableVolume{{SchemaName: "db", TableName: "tb2", Rows: 52, Bytes: 5700}}, st.TopTables)
+
+	counts := make([]int64, 0, len(st.TransactionSizes))
+	for _, b := range st.TransactionSizes {
+		counts = append(counts, b.Count)
+	}
+	// 300 bytes DDL, 2000 bytes and 5200 bytes transactions.
+	require.Equal(t, []int64{1, 1, 1, 0, 0, 0, 0, 0, 0}, counts)
+	require.Nil(t, st.TransactionSizes[len(st.TransactionSizes)-1].UpperBoundBytes)
+
+	// the events fall out of the window.
+	now = now.Add(WindowSeconds * time.Second)
+	st = c.Snapshot(DefaultTopTables)
+	require.Zero(t, st.BytesPerSecond)
+	require.Len(t, st.TopTables, 2)
+
+	// the second task takes over after the first one is unregistered, the change volume of
+	// the tables of the first one is dropped.
+	Unregister(source, "task-1")
+	other.Observe(xid)
+	require.Equal(t, int64(3), c.totals[eventTypeIndex(EventTypeCommit)])
+	require.Len(t, c.Snapshot(DefaultTopTables).TopTables, 0)
+
+	Unregister(source, "task-2")
+	require.Nil(t, Get(source))
+}
+
+func rawQueryEvent(size uint32, query string) *replication.BinlogEvent {
+	// slave proxy id, execution time, schema length, error code, status vars length, schema and query.
+	data := []byte{0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 'd', 'b', 0}
+	data = append(data, query...)
+	return newEvent(replication.QUERY_EVENT, size, &replication.GenericEvent{Data: data})
+}
+
+func TestCollectorRelay(t *testing.T) {
+	source := "mysql-replica-02"
+	task := Register(source, "task-1")
+	// the relay in raw mode takes precedence over the task.
+	relay := RegisterRelay(source, true)
+	c := Get(source)
+	require.NotNil(t, c)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+	c.startSec = now.Unix() - WindowSeconds
+
+	// the relay doesn't parse the events in raw mode.
+	for _, e := range []*replication.BinlogEvent{
+		rawQueryEvent(100, "BEGIN"),
+		newEvent(replication.WRITE_ROWS_EVENTv2, 1000, &replication.GenericEvent{}),
+		newEvent(replication.XID_EVENT, 100, &replication.GenericEvent{}),
+		rawQueryEvent(200, "CREATE TABLE tb3 (c INT)"),
+	} {
+		relay.Observe(e)
+	}
+	// the task only counts the change volume of tables.
+	begin := newEvent(replication.QUERY_EVENT, 100, &replication.QueryEvent{Query: []byte("BEGIN")})
+	for _, e := range []*replication.BinlogEvent{
+		begin,
+		rowsEvent(replication.WRITE_ROWS_EVENTv2, 1000, "db", "tb1", 10),
+		newEvent(replication.XID_EVENT, 100, &replication.XIDEvent{}),
+	} {
+		task.Observe(e)
+	}
+
+	st := c.Snapshot(DefaultTopTables)
+	require.InDelta(t, float64(1400)/WindowSeconds, st.BytesPerSecond, 1e-9)
+	totals := make(map[string]int64)
+	for _, rate := range st.EventRates {
+		totals[rate.EventType] = rate.Total
+	}
+	require.Equal(t, map[string]int64{
+		EventTypeInsert: 1,
+		EventTypeUpdate: 0,
+		EventTypeDelete: 0,
+		EventTypeDDL:    1,
+		EventTypeCommit: 1,
+		EventTypeOther:  1,
+	}, totals)
+	require.Equal(t, []TableVolume{{SchemaName: "db", TableName: "tb1", Rows: 10, Bytes: 1000}}, st.TopTables)
+	counts := make([]int64, 0, len(st.TransactionSizes))
+	for _, b := range st.TransactionSizes {
+		counts = append(counts, b.Count)
+	}
+	// 1200 bytes transaction and 200 bytes DDL.
+	require.Equal(t, []int64{1, 1, 0, 0, 0, 0, 0, 0, 0}, counts)
+
+	// a relay parsing the events counts the change volume of tables as well.
+	relay = RegisterRelay(source, false)
+	relay.Observe(rowsEvent(replication.DELETE_ROWS_EVENTv2, 500, "db", "tb2", 5))
+	task.Observe(rowsEvent(replication.DELETE_ROWS_EVENTv2, 500, "db", "tb2", 5))
+	st = c.Snapshot(DefaultTopTables)
+	require.Len(t, st.TopTables, 2)
+	require.Equal(t, TableVolume{SchemaName: "db", TableName: "tb2", Rows: 5, Bytes: 500}, st.TopTables[1])
+
+	// the task takes over after the relay is unregistered.
+	UnregisterRelay(source)
+	task.Observe(begin)
+	require.Equal(t, int64(2), c.totals[eventTypeIndex(EventTypeOther)])
+
+	Unregister(source, "task-1")
+	require.Nil(t, Get(source))
+}
+
+func TestCollectorEvictTables(t *testing.T) {
+	maxTablesBak := maxTables
+	maxTables = 2
+	defer func() {
+		maxTables = maxTablesBak
+	}()
+	source := "mysql-replica-03"
+	rec := Register(source, "task-1")
+	c := Get(source)
+	require.NotNil(t, c)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	for _, table := range []string{"tb1", "tb2", "tb1", "tb3"} {
+		rec.Observe(rowsEvent(replication.WRITE_ROWS_EVENTv2, 100, "db", table, 1))
+		now = now.Add(time.Second)
+	}
+	// the least recently changed table is evicted.
+	st := c.Snapshot(DefaultTopTables)
+	require.Equal(t, []TableVolume{
+		{SchemaName: "db", TableName: "tb1", Rows: 2, Bytes: 200},
+		{SchemaName: "db", TableName: "tb3", Rows: 1, Bytes: 100},
+	}, st.TopTables)
+
+	Unregister(source, "task-1")
+	require.Nil(t, Get(source))
+}
@@ -113,7 +113,10 @@ func GetLocalBinaryLogs(dir string) (FileSizes, error) {
 
 // SourceStatus collects all information of upstream.
 type SourceStatus struct {
-	Location   Location
-	Binlogs    FileSizes
-	UpdateTime time.Time
+	Location Location
+	Binlogs  FileSizes
+	// BinlogRowImage is `binlog_row_image` of the upstream, it's empty if
+	// it's not fetched.
+	BinlogRowImage string
+	UpdateTime     time.Time
 }
@@ -0,0 +1,417 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/util/dbutil"
+	"github.com/pingcap/tidb/util/filter"
+
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+)
+
+// TypeCompatibilityChecker checks whether the column types of the upstream tables are compatible
+// with the column types of the downstream tables they are routed to, so the lossy mappings
+// (e.g. BIGINT UNSIGNED -> BIGINT) are reported before the replication starts instead of
+// discovering truncation errors in the middle of the replication.
+// The downstream tables which don't exist yet are skipped, they will be created by DM.
+type TypeCompatibilityChecker struct {
+	sourceID string
+	sourceDB *sql.DB
+	targetDB *sql.DB
+	tableMap map[string][]*filter.Table // targetTableID => [table1, table2, ...]
+}
+
+// NewTypeCompatibilityChecker returns a RealChecker.
+func NewTypeCompatibilityChecker(sourceID string, sourceDB, targetDB *sql.DB, tableMap map[string][]*filter.Table) RealChecker {
+	return &TypeCompatibilityChecker{
+		sourceID: sourceID,
+		sourceDB: sourceDB,
+		targetDB: targetDB,
+		tableMap: tableMap,
+	}
+}
+
+// Name implements RealChecker interface.
+func (c *TypeCompatibilityChecker) Name() string {
+	return "column type compatibility check"
+}
+
+// Check implements RealChecker interface.
+func (c *TypeCompatibilityChecker) Check(ctx context.Context) *Result {
+	r := &Result{
+		Name:  c.Name(),
+		Desc:  "check compatibility of column types between upstream and downstream tables",
+		State: StateSuccess,
+		Extra: fmt.Sprintf("sourceID %s", c.sourceID),
+	}
+
+	startTime := time.Now()
+	sourceParser, err := dbutil.GetParserForDB(ctx, c.sourceDB)
+	if err != nil {
+		markCheckError(r, err)
+		return r
+	}
+	targetParser, err := dbutil.GetParserForDB(ctx, c.targetDB)
+	if err != nil {
+		markCheckError(r, err)
+		return r
+	}
+
+	targetTableIDs := make([]string, 0, len(c.tableMap))
+	for targetTableID := range c.tableMap {
+		targetTableIDs = append(targetTableIDs, targetTableID)
+	}
+	sort.Strings(targetTableIDs)
+	for _, targetTableID := range targetTableIDs {
+		targetTable := utils.UnpackTableID(targetTableID)
+		targetStmt, err := fetchCreateTableStmt(ctx, c.targetDB, targetParser, targetTable)
+		if err != nil {
+			markCheckError(r, err)
+			return r
+		}
+		if targetStmt == nil {
+			// the downstream table will be created by DM.
+			continue
+		}
+		for _, table := range c.tableMap[targetTableID] {
+			sourceStmt, err := fetchCreateTableStmt(ctx, c.sourceDB, sourceParser, table)
+			if err != nil {
+				markCheckError(r, err)
+				return r
+			}
+			if sourceStmt == nil {
+				// continue if table was deleted when checking
+				continue
+			}
+			for _, e := range compareTableColumnTypes(sourceStmt, targetStmt) {
+				e.Self = fmt.Sprintf("sourceID %s table %s %s", c.sourceID, table, e.Self)
+				e.Other = fmt.Sprintf("table %s %s", targetTable, e.Other)
+				r.Errors = append(r.Errors, e)
+				switch e.Severity {
+				case StateFailure:
+					r.State = StateFailure
+				case StateWarning:
+					if r.State != StateFailure {
+						r.State = StateWarning
+					}
+				}
+			}
+		}
+	}
+	if r.State != StateSuccess {
+		r.Instruction = "please alter the downstream columns to compatible types, or make sure the upstream data never exceed the downstream types and ignore the type_compatibility checking item"
+	}
+
+	log.L().Logger.Info("check column type compatibility over", zap.Duration("spend time", time.Since(startTime)))
+	return r
+}
+
+// fetchCreateTableStmt returns nil if the table doesn't exist.
+func fetchCreateTableStmt(ctx context.Context, db *sql.DB, p *parser.Parser, table *filter.Table) (*ast.CreateTableStmt, error) {
+	statement, err := dbutil.GetCreateTableSQL(ctx, db, table.Schema, table.Name)
+	if err != nil {
+		if isMySQLError(err, mysql.ErrNoSuchTable) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return getCreateTableStmt(p, statement)
+}
+
+// compareTableColumnTypes compares the columns of the upstream table with the columns of the
+// downstream table of the same names, and returns the incompatible ones. The Severity of the
+// returned errors is StateFailure if the replication will fail or the data will be truncated,
+// and it's StateWarning if the data may lose precision or change their semantics.
+func compareTableColumnTypes(source, target *ast.CreateTableStmt) []*Error {
+	sourceNotNull := notNullColumns(source)
+	targetNotNull := notNullColumns(target)
+	targetCols := make(map[string]*ast.ColumnDef, len(target.Cols))
+	for _, col := range target.Cols {
+		targetCols[col.Name.Name.L] = col
+	}
+
+	var errs []*Error
+	for _, sourceCol := range source.Cols {
+		name := sourceCol.Name.Name.L
+		targetCol, ok := targetCols[name]
+		if !ok {
+			e := NewError("column %s doesn't exist in downstream", name)
+			e.Self = fmt.Sprintf("column %s %s", name, sourceCol.Tp)
+			e.Other = "columns " + strings.Join(columnNames(target), ",")
+			errs = append(errs, e)
+			continue
+		}
+
+		state, reason := compareColumnType(sourceCol.Tp, targetCol.Tp)
+		if state == StateSuccess {
+			if _, ok := sourceNotNull[name]; !ok {
+				if _, ok := targetNotNull[name]; ok {
+					state, reason = StateFailure, "NULL values can't be written into NOT NULL column"
+				}
+			}
+		}
+		if state == StateSuccess {
+			continue
+		}
+		e := &Error{Severity: state, ShortErr: fmt.Sprintf("column %s %s", name, reason)}
+		e.Self = fmt.Sprintf("column %s %s%s", name, sourceCol.Tp, nullability(sourceNotNull, name))
+		e.Other = fmt.Sprintf("column %s %s%s", name, targetCol.Tp, nullability(targetNotNull, name))
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// typeClass is the class of column types which can be compared with each other.
+type typeClass int
+
+const (
+	typeClassOther typeClass = iota
+	typeClassInteger
+	typeClassDecimal
+	typeClassFloat
+	typeClassString
+	typeClassBinary
+	typeClassTime
+	typeClassEnum
+	typeClassSet
+	typeClassBit
+	typeClassJSON
+)
+
+func classOfType(ft *types.FieldType) typeClass {
+	switch ft.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		return typeClassInteger
+	case mysql.TypeNewDecimal, mysql.TypeDecimal:
+		return typeClassDecimal
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return typeClassFloat
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString,
+		mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		if ft.Charset == charset.CharsetBin {
+			return typeClassBinary
+		}
+		return typeClassString
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+		return typeClassTime
+	case mysql.TypeEnum:
+		return typeClassEnum
+	case mysql.TypeSet:
+		return typeClassSet
+	case mysql.TypeBit:
+		return typeClassBit
+	case mysql.TypeJSON:
+		return typeClassJSON
+	}
+	return typeClassOther
+}
+
+// compareColumnType returns whether the values of the source type can be written into
+// the target type without loss, and the reason if they can't.
+func compareColumnType(source, target *types.FieldType) (State, string) {
+	sourceClass, targetClass := classOfType(source), classOfType(target)
+	if sourceClass != targetClass {
+		switch {
+		case sourceClass == typeClassInteger && (targetClass == typeClassDecimal || targetClass == typeClassFloat),
+			sourceClass == typeClassDecimal && targetClass == typeClassFloat:
+			return StateWarning, "may lose precision"
+		case sourceClass == typeClassString && targetClass == typeClassBinary,
+			sourceClass == typeClassBinary && targetClass == typeClassString:
+			return StateWarning, "changes the comparison and collation semantics"
+		}
+		return StateFailure, "has incompatible types"
+	}
+
+	switch sourceClass {
+	case typeClassInteger:
+		return compareIntegerType(source, target)
+	case typeClassDecimal:
+		sourcePrecision, sourceScale := decimalPrecisionAndScale(source)
+		targetPrecision, targetScale := decimalPrecisionAndScale(target)
+		if targetPrecision-targetScale < sourcePrecision-sourceScale {
+			return StateFailure, "may overflow"
+		}
+		if mysql.HasUnsignedFlag(target.Flag) && !mysql.HasUnsignedFlag(source.Flag) {
+			return StateFailure, "can't hold negative values"
+		}
+		if targetScale < sourceScale {
+			return StateWarning, "may be rounded"
+		}
+	case typeClassFloat:
+		if source.Tp == mysql.TypeDouble && target.Tp == mysql.TypeFloat {
+			return StateWarning, "may lose precision"
+		}
+	case typeClassString, typeClassBinary:
+		if stringTypeLength(target) < stringTypeLength(source) {
+			return StateFailure, "may be truncated"
+		}
+	case typeClassTime:
+		return compareTimeType(source, target)
+	case typeClassEnum, typeClassSet:
+		targetElems := make(map[string]struct{}, len(target.Elems))
+		for _, elem := range target.Elems {
+			targetElems[strings.ToLower(elem)] = struct{}{}
+		}
+		for _, elem := range source.Elems {
+			if _, ok := targetElems[strings.ToLower(elem)]; !ok {
+				return StateFailure, fmt.Sprintf("misses element '%s'", elem)
+			}
+		}
+	case typeClassBit:
+		if target.Flen < source.Flen {
+			return StateFailure, "may be truncated"
+		}
+	}
+	return StateSuccess, ""
+}
+
+// integerTypeBytes is the storage size of the integer types.
+var integerTypeBytes = map[byte]int{
+	mysql.TypeTiny:     1,
+	mysql.TypeYear:     1,
+	mysql.TypeShort:    2,
+	mysql.TypeInt24:    3,
+	mysql.TypeLong:     4,
+	mysql.TypeLonglong: 8,
+}
+
+func compareIntegerType(source, target *types.FieldType) (State, string) {
+	sourceBytes, targetBytes := integerTypeBytes[source.Tp], integerTypeBytes[target.Tp]
+	sourceUnsigned, targetUnsigned := mysql.HasUnsignedFlag(source.Flag), mysql.HasUnsignedFlag(target.Flag)
+	switch {
+	case source.Tp == mysql.TypeYear || target.Tp == mysql.TypeYear:
+		if source.Tp != target.Tp {
+			return StateFailure, "has incompatible types"
+		}
+	case !sourceUnsigned && targetUnsigned:
+		return StateFailure, "can't hold negative values"
+	case sourceUnsigned && !targetUnsigned && targetBytes <= sourceBytes:
+		return StateFailure, "may overflow"
+	case targetBytes < sourceBytes:
+		return StateFailure, "may overflow"
+	}
+	return StateSuccess, ""
+}
+
+func compareTimeType(source, target *types.FieldType) (State, string) {
+	if source.Tp != target.Tp {
+		switch {
+		case source.Tp == mysql.TypeDate && (target.Tp == mysql.TypeDatetime || target.Tp == mysql.TypeTimestamp):
+			// a date can be always converted to a datetime or a timestamp
+		case source.Tp == mysql.TypeTimestamp && target.Tp == mysql.TypeDatetime:
+			return StateWarning, "is not converted by time zone any more"
+		case source.Tp == mysql.TypeDatetime && target.Tp == mysql.TypeTimestamp:
+			return StateFailure, "may be out of the range of timestamp"
+		case source.Tp != mysql.TypeDuration && target.Tp == mysql.TypeDate:
+			return StateWarning, "loses the time part"
+		default:
+			return StateFailure, "has incompatible types"
+		}
+	}
+	if source.Tp == mysql.TypeDate || target.Tp == mysql.TypeDate {
+		return StateSuccess, ""
+	}
+	if fsp(target) < fsp(source) {
+		return StateWarning, "may be rounded"
+	}
+	return StateSuccess, ""
+}
+
+func decimalPrecisionAndScale(ft *types.FieldType) (int, int) {
+	precision, scale := ft.Flen, ft.Decimal
+	defaultPrecision, defaultScale := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeNewDecimal)
+	if precision == types.UnspecifiedLength {
+		precision = defaultPrecision
+	}
+	if scale == types.UnspecifiedLength {
+		scale = defaultScale
+	}
+	return precision, scale
+}
+
+// stringTypeLength returns the max length of the string type, the length of char types is
+// in characters and the length of text types is in bytes, it's just an approximation.
+func stringTypeLength(ft *types.FieldType) int {
+	switch ft.Tp {
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		length, _ := mysql.GetDefaultFieldLengthAndDecimal(ft.Tp)
+		return length
+	}
+	if ft.Flen == types.UnspecifiedLength {
+		length, _ := mysql.GetDefaultFieldLengthAndDecimal(ft.Tp)
+		return length
+	}
+	return ft.Flen
+}
+
+func fsp(ft *types.FieldType) int {
+	if ft.Decimal == types.UnspecifiedLength {
+		return 0
+	}
+	return ft.Decimal
+}
+
+// notNullColumns returns the lower-cased names of the NOT NULL columns, including the
+// columns of the primary key.
+func notNullColumns(stmt *ast.CreateTableStmt) map[string]struct{} {
+	notNull := make(map[string]struct{})
+	for _, col := range stmt.Cols {
+		for _, opt := range col.Options {
+			if opt.Tp == ast.ColumnOptionNotNull || opt.Tp == ast.ColumnOptionPrimaryKey {
+				notNull[col.Name.Name.L] = struct{}{}
+			}
+		}
+	}
+	for _, cst := range stmt.Constraints {
+		if cst.Tp != ast.ConstraintPrimaryKey {
+			continue
+		}
+		for _, key := range cst.Keys {
+			if key.Column != nil {
+				notNull[key.Column.Name.L] = struct{}{}
+			}
+		}
+	}
+	return notNull
+}
+
+func nullability(notNull map[string]struct{}, name string) string {
+	if _, ok := notNull[name]; ok {
+		return " NOT NULL"
+	}
+	return ""
+}
+
+func columnNames(stmt *ast.CreateTableStmt) []string {
+	names := make([]string, 0, len(stmt.Cols))
+	for _, col := range stmt.Cols {
+		names = append(names, col.Name.Name.L)
+	}
+	return names
+}
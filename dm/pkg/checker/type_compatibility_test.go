@@ -0,0 +1,128 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gmysql "github.com/go-sql-driver/mysql"
+	tc "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/util/filter"
+)
+
+func (t *testCheckSuite) TestCompareColumnTypes(c *tc.C) {
+	cases := []struct {
+		source string
+		target string
+		state  State
+	}{
+		{"bigint unsigned", "bigint", StateFailure},
+		{"bigint unsigned", "decimal(20,0)", StateWarning},
+		{"int unsigned", "bigint", StateSuccess},
+		{"int", "int unsigned", StateFailure},
+		{"bigint", "int", StateFailure},
+		{"int", "bigint", StateSuccess},
+		{"decimal(10,2)", "decimal(12,2)", StateSuccess},
+		{"decimal(10,2)", "decimal(10,1)", StateFailure},
+		{"decimal(10,2)", "decimal(11,1)", StateWarning},
+		{"double", "float", StateWarning},
+		{"varchar(255)", "varchar(64)", StateFailure},
+		{"varchar(64)", "text", StateSuccess},
+		{"text", "varchar(255)", StateFailure},
+		{"varchar(64)", "varbinary(64)", StateWarning},
+		{"varchar(64)", "int", StateFailure},
+		{"datetime(6)", "datetime(3)", StateWarning},
+		{"datetime", "timestamp", StateFailure},
+		{"timestamp", "datetime", StateWarning},
+		{"date", "datetime", StateSuccess},
+		{"enum('a','b')", "enum('a','b','c')", StateSuccess},
+		{"set('a','b')", "set('a')", StateFailure},
+		{"bit(8)", "bit(4)", StateFailure},
+		{"json", "json", StateSuccess},
+	}
+	p := parser.New()
+	for _, cs := range cases {
+		source, err := getCreateTableStmt(p, "create table t (c "+cs.source+")")
+		c.Assert(err, tc.IsNil)
+		target, err := getCreateTableStmt(p, "create table t (c "+cs.target+")")
+		c.Assert(err, tc.IsNil)
+		state, _ := compareColumnType(source.Cols[0].Tp, target.Cols[0].Tp)
+		c.Assert(state, tc.Equals, cs.state, tc.Commentf("%s -> %s", cs.source, cs.target))
+	}
+}
+
+func (t *testCheckSuite) TestCompareTableColumnTypes(c *tc.C) {
+	p := parser.New()
+	source, err := getCreateTableStmt(p, "create table t (id bigint unsigned primary key, a int, b varchar(10), c int)")
+	c.Assert(err, tc.IsNil)
+	target, err := getCreateTableStmt(p, "create table t (id bigint unsigned not null, a int not null, b varchar(20), primary key (id))")
+	c.Assert(err, tc.IsNil)
+
+	errs := compareTableColumnTypes(source, target)
+	c.Assert(errs, tc.HasLen, 2)
+	c.Assert(errs[0].Severity, tc.Equals, StateFailure)
+	c.Assert(errs[0].ShortErr, tc.Matches, "column a .*NOT NULL.*")
+	c.Assert(errs[1].Severity, tc.Equals, StateFailure)
+	c.Assert(errs[1].ShortErr, tc.Equals, "column c doesn't exist in downstream")
+}
+
+func (t *testCheckSuite) TestTypeCompatibilityChecker(c *tc.C) {
+	sourceDB, sourceMock, err := sqlmock.New()
+	c.Assert(err, tc.IsNil)
+	targetDB, targetMock, err := sqlmock.New()
+	c.Assert(err, tc.IsNil)
+	ctx := context.Background()
+
+	sqlModeRow := sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("sql_mode", "ANSI_QUOTES")
+	sourceMock.ExpectQuery("SHOW VARIABLES LIKE 'sql_mode'").WillReturnRows(sqlModeRow)
+	sqlModeRow = sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("sql_mode", "ANSI_QUOTES")
+	targetMock.ExpectQuery("SHOW VARIABLES LIKE 'sql_mode'").WillReturnRows(sqlModeRow)
+	targetMock.ExpectQuery("SHOW CREATE TABLE `target-db`.`target-table`").WillReturnRows(
+		sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("target-table", `CREATE TABLE "target-table" (
+  "c" bigint(20) NOT NULL,
+  "d" varchar(10) DEFAULT NULL,
+  PRIMARY KEY ("c")
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`))
+	sourceMock.ExpectQuery("SHOW CREATE TABLE `test-db`.`test-table`").WillReturnRows(
+		sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("test-table", `CREATE TABLE "test-table" (
+  "c" bigint(20) unsigned NOT NULL,
+  "d" varchar(10) DEFAULT NULL,
+  PRIMARY KEY ("c")
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`))
+
+	checker := NewTypeCompatibilityChecker("test-source", sourceDB, targetDB,
+		map[string][]*filter.Table{"`target-db`.`target-table`": {{Schema: "test-db", Name: "test-table"}}})
+	result := checker.Check(ctx)
+	c.Assert(result.State, tc.Equals, StateFailure)
+	c.Assert(result.Errors, tc.HasLen, 1)
+	c.Assert(result.Errors[0].ShortErr, tc.Equals, "column c may overflow")
+	c.Assert(sourceMock.ExpectationsWereMet(), tc.IsNil)
+	c.Assert(targetMock.ExpectationsWereMet(), tc.IsNil)
+
+	// the downstream table doesn't exist
+	checker = NewTypeCompatibilityChecker("test-source", sourceDB, targetDB,
+		map[string][]*filter.Table{"`target-db`.`target-table`": {{Schema: "test-db", Name: "test-table"}}})
+	sqlModeRow = sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("sql_mode", "ANSI_QUOTES")
+	sourceMock.ExpectQuery("SHOW VARIABLES LIKE 'sql_mode'").WillReturnRows(sqlModeRow)
+	sqlModeRow = sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("sql_mode", "ANSI_QUOTES")
+	targetMock.ExpectQuery("SHOW VARIABLES LIKE 'sql_mode'").WillReturnRows(sqlModeRow)
+	targetMock.ExpectQuery("SHOW CREATE TABLE `target-db`.`target-table`").WillReturnError(
+		&gmysql.MySQLError{Number: 1146, Message: "Table 'target-db.target-table' doesn't exist"})
+	result = checker.Check(ctx)
+	c.Assert(result.State, tc.Equals, StateSuccess)
+	c.Assert(sourceMock.ExpectationsWereMet(), tc.IsNil)
+	c.Assert(targetMock.ExpectationsWereMet(), tc.IsNil)
+}
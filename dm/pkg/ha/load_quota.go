@@ -0,0 +1,222 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/pingcap/tiflow/dm/dm/common"
+	"github.com/pingcap/tiflow/dm/pkg/etcdutil"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+)
+
+// LoadQuotaRequest is the request of a subtask for the quota of running the load unit.
+// The requests with higher priority are granted first, and the requests with the same
+// priority are granted in the order they are put.
+type LoadQuotaRequest struct {
+	Task     string `json:"-"`
+	Source   string `json:"-"`
+	Worker   string `json:"worker"`
+	Priority int    `json:"priority"`
+
+	// Revision is the create revision of the request in etcd, it's filled when getting the request.
+	Revision int64 `json:"-"`
+}
+
+// NewLoadQuotaRequest creates a new LoadQuotaRequest instance.
+func NewLoadQuotaRequest(task, source, worker string, priority int) LoadQuotaRequest {
+	return LoadQuotaRequest{
+		Task:     task,
+		Source:   source,
+		Worker:   worker,
+		Priority: priority,
+	}
+}
+
+// PutLoadQuotaLimit puts how many subtasks may run the load unit concurrently, the limit is
+// deleted if it's not positive, then the load quota is not limited.
+// This function should often be called by DM-master.
+func PutLoadQuotaLimit(cli *clientv3.Client, limit int) (int64, error) {
+	op := clientv3.OpDelete(common.LoadQuotaLimitKey)
+	if limit > 0 {
+		op = clientv3.OpPut(common.LoadQuotaLimitKey, strconv.Itoa(limit))
+	}
+	_, rev, err := etcdutil.DoOpsInOneTxnWithRetry(cli, op)
+	return rev, err
+}
+
+// GetLoadQuotaLimit gets how many subtasks may run the load unit concurrently, 0 is returned
+// if the load quota is not limited.
+func GetLoadQuotaLimit(cli *clientv3.Client) (int, error) {
+	ctx, cancel := context.WithTimeout(cli.Ctx(), etcdutil.DefaultRequestTimeout)
+	defer cancel()
+	resp, err := cli.Get(ctx, common.LoadQuotaLimitKey)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Count <= 0 {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(string(resp.Kvs[0].Value))
+	if err != nil {
+		return 0, terror.Annotate(err, "illegal value of LoadQuotaLimitKey")
+	}
+	return limit, nil
+}
+
+// PutLoadQuotaRequest puts the load quota request of the subtask. The request is attached to
+// the lease, so it's deleted if the DM-worker crashes.
+// This function should often be called by DM-worker.
+func PutLoadQuotaRequest(cli *clientv3.Client, req LoadQuotaRequest, leaseID clientv3.LeaseID) (int64, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+	key := common.LoadQuotaRequestKeyAdapter.Encode(req.Task, req.Source)
+	_, rev, err := etcdutil.DoOpsInOneTxnWithRetry(cli, clientv3.OpPut(key, string(data), clientv3.WithLease(leaseID)))
+	return rev, err
+}
+
+// GetAllLoadQuotaRequests gets all the load quota requests.
+func GetAllLoadQuotaRequests(cli *clientv3.Client) ([]LoadQuotaRequest, int64, error) {
+	ctx, cancel := context.WithTimeout(cli.Ctx(), etcdutil.DefaultRequestTimeout)
+	defer cancel()
+	resp, err := cli.Get(ctx, common.LoadQuotaRequestKeyAdapter.Path(), clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	requests := make([]LoadQuotaRequest, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		keys, err2 := common.LoadQuotaRequestKeyAdapter.Decode(string(kv.Key))
+		if err2 != nil {
+			return nil, 0, err2
+		}
+		var req LoadQuotaRequest
+		if err2 = json.Unmarshal(kv.Value, &req); err2 != nil {
+			return nil, 0, err2
+		}
+		req.Task, req.Source, req.Revision = keys[0], keys[1], kv.CreateRevision
+		requests = append(requests, req)
+	}
+	return requests, resp.Header.Revision, nil
+}
+
+// PutLoadQuotaGrant grants the load quota to the subtask.
+// k/v: (task, sourceID) -> worker.
+// This function should often be called by DM-master.
+func PutLoadQuotaGrant(cli *clientv3.Client, task, sourceID, worker string) (int64, error) {
+	data, err := json.Marshal(worker)
+	if err != nil {
+		return 0, err
+	}
+	key := common.LoadQuotaGrantKeyAdapter.Encode(task, sourceID)
+	_, rev, err := etcdutil.DoOpsInOneTxnWithRetry(cli, clientv3.OpPut(key, string(data)))
+	return rev, err
+}
+
+// GetAllLoadQuotaGrants gets all the granted load quota.
+// k/v: (task, sourceID) -> worker.
+func GetAllLoadQuotaGrants(cli *clientv3.Client) (map[string]map[string]string, int64, error) {
+	grants := make(map[string]map[string]string)
+	ctx, cancel := context.WithTimeout(cli.Ctx(), etcdutil.DefaultRequestTimeout)
+	defer cancel()
+	resp, err := cli.Get(ctx, common.LoadQuotaGrantKeyAdapter.Path(), clientv3.WithPrefix())
+	if err != nil {
+		return grants, 0, err
+	}
+
+	for _, kv := range resp.Kvs {
+		keys, err2 := common.LoadQuotaGrantKeyAdapter.Decode(string(kv.Key))
+		if err2 != nil {
+			return nil, 0, err2
+		}
+		var worker string
+		if err2 = json.Unmarshal(kv.Value, &worker); err2 != nil {
+			return nil, 0, err2
+		}
+		if _, ok := grants[keys[0]]; !ok {
+			grants[keys[0]] = make(map[string]string)
+		}
+		grants[keys[0]][keys[1]] = worker
+	}
+	return grants, resp.Header.Revision, nil
+}
+
+// DelLoadQuotaGrant revokes the load quota granted to the subtask.
+// This function should often be called by DM-master.
+func DelLoadQuotaGrant(cli *clientv3.Client, task, sourceID string) (int64, error) {
+	key := common.LoadQuotaGrantKeyAdapter.Encode(task, sourceID)
+	_, rev, err := etcdutil.DoOpsInOneTxnWithRetry(cli, clientv3.OpDelete(key))
+	return rev, err
+}
+
+// DelLoadQuota deletes both the load quota request and grant of the subtask, it's called when
+// the subtask finishes or stops running the load unit.
+// This function should often be called by DM-worker.
+func DelLoadQuota(cli *clientv3.Client, task, sourceID string) (int64, error) {
+	_, rev, err := etcdutil.DoOpsInOneTxnWithRetry(cli,
+		clientv3.OpDelete(common.LoadQuotaRequestKeyAdapter.Encode(task, sourceID)),
+		clientv3.OpDelete(common.LoadQuotaGrantKeyAdapter.Encode(task, sourceID)))
+	return rev, err
+}
+
+// WaitLoadQuotaGranted waits until the load quota is granted to the subtask.
+// This function should often be called by DM-worker.
+func WaitLoadQuotaGranted(ctx context.Context, cli *clientv3.Client, task, sourceID string) error {
+	key := common.LoadQuotaGrantKeyAdapter.Encode(task, sourceID)
+	for {
+		getCtx, cancel := context.WithTimeout(ctx, etcdutil.DefaultRequestTimeout)
+		resp, err := cli.Get(getCtx, key)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if resp.Count > 0 {
+			return nil
+		}
+
+		wCtx, wCancel := context.WithCancel(ctx)
+		ch := cli.Watch(wCtx, key, clientv3.WithRev(resp.Header.Revision+1))
+		granted, err := waitGrantPut(ctx, ch)
+		wCancel()
+		if err != nil || granted {
+			return err
+		}
+		// the watch channel is closed or canceled, get the grant again.
+	}
+}
+
+func waitGrantPut(ctx context.Context, ch clientv3.WatchChan) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case resp, ok := <-ch:
+			if !ok || resp.Canceled {
+				return false, nil
+			}
+			for _, ev := range resp.Events {
+				if ev.Type == mvccpb.PUT {
+					return true, nil
+				}
+			}
+		}
+	}
+}
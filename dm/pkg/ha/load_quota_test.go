@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ha
+
+import (
+	"context"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+func (t *testForEtcd) TestLoadQuotaEtcd(c *C) {
+	var (
+		worker1 = "worker1"
+		worker2 = "worker2"
+		source1 = "source1"
+		source2 = "source2"
+		task1   = "task1"
+	)
+	defer clearTestInfoOperation(c)
+
+	// the load quota is not limited by default.
+	limit, err := GetLoadQuotaLimit(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(limit, Equals, 0)
+	_, err = PutLoadQuotaLimit(etcdTestCli, 2)
+	c.Assert(err, IsNil)
+	limit, err = GetLoadQuotaLimit(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(limit, Equals, 2)
+	_, err = PutLoadQuotaLimit(etcdTestCli, 0)
+	c.Assert(err, IsNil)
+	limit, err = GetLoadQuotaLimit(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(limit, Equals, 0)
+
+	// put the requests attached to the leases.
+	lease1, err := etcdTestCli.Grant(context.Background(), 10)
+	c.Assert(err, IsNil)
+	lease2, err := etcdTestCli.Grant(context.Background(), 10)
+	c.Assert(err, IsNil)
+	rev1, err := PutLoadQuotaRequest(etcdTestCli, NewLoadQuotaRequest(task1, source1, worker1, 1), lease1.ID)
+	c.Assert(err, IsNil)
+	rev2, err := PutLoadQuotaRequest(etcdTestCli, NewLoadQuotaRequest(task1, source2, worker2, 2), lease2.ID)
+	c.Assert(err, IsNil)
+	requests, _, err := GetAllLoadQuotaRequests(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(requests, DeepEquals, []LoadQuotaRequest{
+		{Task: task1, Source: source1, Worker: worker1, Priority: 1, Revision: rev1},
+		{Task: task1, Source: source2, Worker: worker2, Priority: 2, Revision: rev2},
+	})
+
+	// wait until the quota is granted.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	c.Assert(WaitLoadQuotaGranted(ctx, etcdTestCli, task1, source1), NotNil)
+	cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- WaitLoadQuotaGranted(context.Background(), etcdTestCli, task1, source1)
+	}()
+	_, err = PutLoadQuotaGrant(etcdTestCli, task1, source2, worker2)
+	c.Assert(err, IsNil)
+	_, err = PutLoadQuotaGrant(etcdTestCli, task1, source1, worker1)
+	c.Assert(err, IsNil)
+	select {
+	case err = <-errCh:
+		c.Assert(err, IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("the load quota is not granted")
+	}
+	// return immediately if the quota is already granted.
+	c.Assert(WaitLoadQuotaGranted(context.Background(), etcdTestCli, task1, source1), IsNil)
+	grants, _, err := GetAllLoadQuotaGrants(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(grants, DeepEquals, map[string]map[string]string{task1: {source1: worker1, source2: worker2}})
+
+	// release the quota.
+	_, err = DelLoadQuota(etcdTestCli, task1, source1)
+	c.Assert(err, IsNil)
+	requests, _, err = GetAllLoadQuotaRequests(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(requests, HasLen, 1)
+	c.Assert(requests[0].Source, Equals, source2)
+	grants, _, err = GetAllLoadQuotaGrants(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(grants, DeepEquals, map[string]map[string]string{task1: {source2: worker2}})
+
+	// the request is deleted with its lease.
+	_, err = etcdTestCli.Revoke(context.Background(), lease2.ID)
+	c.Assert(err, IsNil)
+	requests, _, err = GetAllLoadQuotaRequests(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(requests, HasLen, 0)
+	_, err = DelLoadQuotaGrant(etcdTestCli, task1, source2)
+	c.Assert(err, IsNil)
+	grants, _, err = GetAllLoadQuotaGrants(etcdTestCli)
+	c.Assert(err, IsNil)
+	c.Assert(grants, HasLen, 0)
+}
@@ -136,8 +136,11 @@ func ClearTestInfoOperation(cli *clientv3.Client) error {
 	clearSubTaskStage := clientv3.OpDelete(common.StageSubTaskKeyAdapter.Path(), clientv3.WithPrefix())
 	clearValidatorStage := clientv3.OpDelete(common.StageValidatorKeyAdapter.Path(), clientv3.WithPrefix())
 	clearLoadTasks := clientv3.OpDelete(common.LoadTaskKeyAdapter.Path(), clientv3.WithPrefix())
+	clearLoadQuotaLimit := clientv3.OpDelete(common.LoadQuotaLimitKey)
+	clearLoadQuotaRequests := clientv3.OpDelete(common.LoadQuotaRequestKeyAdapter.Path(), clientv3.WithPrefix())
+	clearLoadQuotaGrants := clientv3.OpDelete(common.LoadQuotaGrantKeyAdapter.Path(), clientv3.WithPrefix())
 	_, _, err := etcdutil.DoOpsInOneTxnWithRetry(cli, clearSource, clearSubTask, clearWorkerInfo, clearBound,
 		clearLastBound, clearWorkerKeepAlive, clearRelayStage, clearRelayConfig, clearSubTaskStage, clearValidatorStage,
-		clearLoadTasks)
+		clearLoadTasks, clearLoadQuotaLimit, clearLoadQuotaRequests, clearLoadQuotaGrants)
 	return err
 }
@@ -26,6 +26,7 @@ import (
 
 	"github.com/pingcap/tiflow/dm/pkg/helper"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
+	tflogutil "github.com/pingcap/tiflow/pkg/logutil"
 )
 
 const (
@@ -77,6 +78,12 @@ func (l Logger) WithFields(fields ...zap.Field) Logger {
 	return Logger{l.With(fields...)}
 }
 
+// WithComponent returns a new Logger whose level and sampling of debug logs follow the
+// runtime log config of the component, which can be changed by the `/log/components` API.
+func (l Logger) WithComponent(component string) Logger {
+	return Logger{tflogutil.WithComponent(l.Logger, component)}
+}
+
 // ErrorFilterContextCanceled wraps Logger.Error() and will filter error log when error is context.Canceled.
 func (l Logger) ErrorFilterContextCanceled(msg string, fields ...zap.Field) {
 	for _, field := range fields {
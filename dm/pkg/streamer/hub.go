@@ -15,6 +15,7 @@ package streamer
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -88,6 +89,19 @@ func (h *relayLogInfoHub) remove(taskName string) {
 	delete(h.logs, taskName)
 }
 
+func (h *relayLogInfoHub) all() []RelayLogInfo {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	infos := make([]RelayLogInfo, 0, len(h.logs))
+	for _, info := range h.logs {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].TaskName < infos[j].TaskName
+	})
+	return infos
+}
+
 func (h *relayLogInfoHub) earliest() (taskName string, earliest *RelayLogInfo) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -137,3 +151,8 @@ func (h *ReaderHub) EarliestActiveRelayLog() *RelayLogInfo {
 	_, rli := h.rlih.earliest()
 	return rli
 }
+
+// ActiveRelayLogs returns active relay logs of all tasks, sorted by task name.
+func (h *ReaderHub) ActiveRelayLogs() []RelayLogInfo {
+	return h.rlih.all()
+}
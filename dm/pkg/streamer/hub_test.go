@@ -154,6 +154,13 @@ func (t *testHubSuite) TestReaderHub(c *C) {
 	c.Assert(erli.UUID, Equals, "c6ae5afe-c7a3-11e8-a19d-0242ac130006.000002")
 	c.Assert(erli.Filename, Equals, "mysql-bin.000002")
 
+	// all active relay logs are sorted by task name
+	rlis := h.ActiveRelayLogs()
+	c.Assert(rlis, HasLen, 2)
+	c.Assert(rlis[0].TaskName, Equals, "task-1")
+	c.Assert(rlis[1].TaskName, Equals, "task-2")
+	c.Assert(rlis[1].Filename, Equals, "mysql-bin.000002")
+
 	// remove the earlier one
 	h.RemoveActiveRelayLog("task-2")
 
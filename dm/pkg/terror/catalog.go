@@ -0,0 +1,176 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terror
+
+import (
+	"sort"
+	"sync"
+)
+
+// HintAction is the kind of action a remediation hint suggests.
+type HintAction string
+
+// Remediation hint actions.
+const (
+	// HintActionRunCommand suggests running a dmctl command.
+	HintActionRunCommand HintAction = "run-command"
+	// HintActionCheckConfig suggests checking a configuration item.
+	HintActionCheckConfig HintAction = "check-config"
+	// HintActionCheckUpstream suggests checking the upstream database.
+	HintActionCheckUpstream HintAction = "check-upstream"
+	// HintActionCheckDownstream suggests checking the downstream database.
+	HintActionCheckDownstream HintAction = "check-downstream"
+	// HintActionRetry suggests retrying the operation later.
+	HintActionRetry HintAction = "retry"
+)
+
+// Hint is a machine-readable remediation hint of an error code, it's used by UIs to
+// render actionable guidance besides the human-readable workaround.
+type Hint struct {
+	Action HintAction `json:"action"`
+	// Target is the dmctl command for HintActionRunCommand, or the config item for
+	// HintActionCheckConfig, it may be empty for other actions.
+	Target      string `json:"target,omitempty"`
+	Description string `json:"description"`
+}
+
+// CatalogItem describes an error code in the error catalog.
+type CatalogItem struct {
+	Code       ErrCode `json:"code"`
+	Class      string  `json:"class"`
+	Scope      string  `json:"scope"`
+	Level      string  `json:"level"`
+	Message    string  `json:"message"`
+	Workaround string  `json:"workaround"`
+	Hints      []Hint  `json:"hints,omitempty"`
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = make(map[ErrCode]*Error)
+)
+
+// register adds e to the error catalog if its code is not registered yet.
+func register(e *Error) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if _, ok := catalog[e.code]; !ok {
+		catalog[e.code] = e
+	}
+}
+
+// ErrorByCode returns the registered *Error of the code.
+func ErrorByCode(code ErrCode) (*Error, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	e, ok := catalog[code]
+	return e, ok
+}
+
+// Catalog returns all registered error codes ordered by code.
+func Catalog() []CatalogItem {
+	catalogMu.RLock()
+	items := make([]CatalogItem, 0, len(catalog))
+	for _, e := range catalog {
+		items = append(items, CatalogItem{
+			Code:       e.code,
+			Class:      e.class.String(),
+			Scope:      e.scope.String(),
+			Level:      e.level.String(),
+			Message:    e.message,
+			Workaround: e.workaround,
+			Hints:      HintsByCode(e.code),
+		})
+	}
+	catalogMu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Code < items[j].Code
+	})
+	return items
+}
+
+// Hints returns the remediation hints of the error.
+func (e *Error) Hints() []Hint {
+	return HintsByCode(e.code)
+}
+
+// HintsByCode returns the remediation hints of the error code, nil if no hint is attached.
+func HintsByCode(code ErrCode) []Hint {
+	hints, ok := code2Hints[code]
+	if !ok {
+		return nil
+	}
+	return append([]Hint(nil), hints...)
+}
+
+// code2Hints holds remediation hints for the most common relay and syncer errors.
+var code2Hints = map[ErrCode][]Hint{
+	codeDBBadConn: {
+		{Action: HintActionCheckDownstream, Description: "check the network between dm-worker and the database"},
+		{Action: HintActionRunCommand, Target: "resume-task", Description: "resume the task after the connection recovers"},
+	},
+	codeDBInvalidConn: {
+		{Action: HintActionCheckDownstream, Description: "check the network between dm-worker and the database"},
+		{Action: HintActionRunCommand, Target: "resume-task", Description: "resume the task after the connection recovers"},
+	},
+	codeNoMasterStatus: {
+		{Action: HintActionCheckUpstream, Description: "check the upstream user has REPLICATION CLIENT privilege and binlog is enabled"},
+	},
+	codeRelayLogFileSizeSmaller: {
+		{Action: HintActionRunCommand, Target: "stop-relay", Description: "stop relay before re-pulling the relay log"},
+		{Action: HintActionRunCommand, Target: "start-relay", Description: "start relay again from the error position"},
+	},
+	codeRelayNoValidRelaySubDir: {
+		{Action: HintActionRunCommand, Target: "query-status", Description: "check the relay status of the source"},
+	},
+	codeRelayBinlogNameNotValid: {
+		{Action: HintActionCheckConfig, Target: "relay-binlog-name", Description: "correct the relay binlog name in source config"},
+	},
+	codeRelayTCPReaderStartSync: {
+		{Action: HintActionCheckUpstream, Description: "check whether the binlog at the start position has been purged in upstream"},
+	},
+	codeRelayTCPReaderStartSyncGTID: {
+		{Action: HintActionCheckUpstream, Description: "check whether the binlog of the GTID set has been purged in upstream"},
+	},
+	codeRelayTCPReaderGetEvent: {
+		{Action: HintActionCheckUpstream, Description: "check the network between dm-worker and upstream"},
+		{Action: HintActionRunCommand, Target: "resume-relay", Description: "resume relay after upstream recovers"},
+	},
+	codeRelayPurgeIsForbidden: {
+		{Action: HintActionRetry, Description: "purge relay log again later"},
+	},
+	codeSyncerGetEvent: {
+		{Action: HintActionCheckUpstream, Description: "check whether the binlog file could be parsed by mysqlbinlog"},
+	},
+	codeSyncerParseDDL: {
+		{Action: HintActionRunCommand, Target: "binlog skip", Description: "skip the DDL if it's not needed downstream"},
+		{Action: HintActionRunCommand, Target: "binlog replace", Description: "replace the DDL with statements TiDB supports"},
+	},
+	codeSyncerUnitHandleDDLFailed: {
+		{Action: HintActionCheckDownstream, Description: "check whether the DDL is supported by downstream"},
+		{Action: HintActionRunCommand, Target: "binlog skip", Description: "skip the failed DDL"},
+		{Action: HintActionRunCommand, Target: "binlog replace", Description: "replace the failed DDL"},
+	},
+	codeSyncerShardDDLConflict: {
+		{Action: HintActionRunCommand, Target: "shard-ddl-lock", Description: "check the conflicting shard DDL locks"},
+		{Action: HintActionRunCommand, Target: "binlog replace", Description: "replace the conflicting DDL"},
+	},
+	codeSyncerUnitDMLColumnNotMatch: {
+		{Action: HintActionRunCommand, Target: "binlog-schema update", Description: "set the table schema to match the binlog event"},
+	},
+	codeSyncUnitDDLWrongSequence: {
+		{Action: HintActionRunCommand, Target: "shard-ddl-lock", Description: "check the DDL sequence of the sharding group"},
+	},
+}
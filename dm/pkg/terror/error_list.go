@@ -258,6 +258,9 @@ const (
 	codeConfigStartTimeTooLate
 	codeConfigLoaderDirInvalid
 	codeConfigLoaderS3NotSupport
+	codeConfigInvalidNormalization
+	codeConfigInvalidRelaxedOrderTables
+	codeConfigReverseChangefeedNotSupport
 )
 
 // Binlog operation error code list.
@@ -286,6 +289,7 @@ const (
 	codeTaskCheckSyncConfigError
 	codeTaskCheckGenBAList
 	codeSourceCheckGTID
+	codeTaskCheckCrossTaskConflict
 )
 
 // Relay log utils error code.
@@ -343,6 +347,7 @@ const (
 	codeRelayPurgeArgsNotValid
 	codePreviousGTIDsNotValid
 	codeRotateEventWithDifferentServerID
+	codeRelayImportBinlogBackup
 )
 
 // Dump unit error code.
@@ -446,6 +451,7 @@ const (
 	codeSyncerParseDDL
 	codeSyncerUnsupportedStmt
 	codeSyncerGetEvent
+	codeSyncerBinlogRowImageNotFull
 )
 
 // DM-master error code.
@@ -933,6 +939,9 @@ var (
 	ErrConfigStartTimeTooLate              = New(codeConfigStartTimeTooLate, ClassConfig, ScopeInternal, LevelHigh, "start-time %s is too late, no binlog location matches it", "Please check the `--start-time` is expected or try again later.")
 	ErrConfigLoaderDirInvalid              = New(codeConfigLoaderDirInvalid, ClassConfig, ScopeInternal, LevelHigh, "loader's dir %s is invalid", "Please check the `dir` config in task configuration file.")
 	ErrConfigLoaderS3NotSupport            = New(codeConfigLoaderS3NotSupport, ClassConfig, ScopeInternal, LevelHigh, "loader's dir %s is s3 dir, but s3 is not supported", "Please check the `dir` config in task configuration file and you can use `Lightning` by set config `import-mode` be `sql` which supports s3 instead.")
+	ErrConfigInvalidNormalization          = New(codeConfigInvalidNormalization, ClassConfig, ScopeInternal, LevelHigh, "invalid normalization config: %s", "Please check the `normalization` config in task configuration file.")
+	ErrConfigInvalidRelaxedOrderTables     = New(codeConfigInvalidRelaxedOrderTables, ClassConfig, ScopeInternal, LevelHigh, "invalid relaxed-order-tables config", "Please check the `relaxed-order-tables` config of syncer in task configuration file.")
+	ErrConfigReverseChangefeedNotSupport   = New(codeConfigReverseChangefeedNotSupport, ClassConfig, ScopeInternal, LevelMedium, "can't replicate the tables of source %s back by TiCDC: %s", "Please replicate the tables merged by routes or the sharding task back to the source manually.")
 
 	// Binlog operation error.
 	ErrBinlogExtractPosition = New(codeBinlogExtractPosition, ClassBinlogOp, ScopeInternal, LevelHigh, "", "")
@@ -948,13 +957,14 @@ var (
 	ErrCheckpointRestoreCountGreater = New(codeCheckpointRestoreCountGreater, ClassCheckpoint, ScopeInternal, LevelMedium, "restoring count greater than total count for table[%v]", "")
 
 	// Task check error.
-	ErrTaskCheckSameTableName    = New(codeTaskCheckSameTableName, ClassTaskCheck, ScopeInternal, LevelMedium, "same table name in case-insensitive %v", "Please check `target-table` config in task configuration file.")
-	ErrTaskCheckFailedOpenDB     = New(codeTaskCheckFailedOpenDB, ClassTaskCheck, ScopeInternal, LevelHigh, "failed to open DSN %s:***@%s:%d", "Please check the database config in configuration file.")
-	ErrTaskCheckGenTableRouter   = New(codeTaskCheckGenTableRouter, ClassTaskCheck, ScopeInternal, LevelMedium, "generate table router error", "Please check the `routes` config in task configuration file.")
-	ErrTaskCheckGenColumnMapping = New(codeTaskCheckGenColumnMapping, ClassTaskCheck, ScopeInternal, LevelMedium, "generate column mapping error", "Please check the `column-mappings` config in task configuration file.")
-	ErrTaskCheckSyncConfigError  = New(codeTaskCheckSyncConfigError, ClassTaskCheck, ScopeInternal, LevelMedium, "%s: %v\n detail: %v", "")
-	ErrTaskCheckGenBAList        = New(codeTaskCheckGenBAList, ClassTaskCheck, ScopeInternal, LevelMedium, "generate block allow list error", "Please check the `block-allow-list` config in task configuration file.")
-	ErrSourceCheckGTID           = New(codeSourceCheckGTID, ClassTaskCheck, ScopeInternal, LevelMedium, "%s has GTID_MODE = %s instead of ON", "Please check the `enable-gtid` config in source configuration file.")
+	ErrTaskCheckSameTableName     = New(codeTaskCheckSameTableName, ClassTaskCheck, ScopeInternal, LevelMedium, "same table name in case-insensitive %v", "Please check `target-table` config in task configuration file.")
+	ErrTaskCheckFailedOpenDB      = New(codeTaskCheckFailedOpenDB, ClassTaskCheck, ScopeInternal, LevelHigh, "failed to open DSN %s:***@%s:%d", "Please check the database config in configuration file.")
+	ErrTaskCheckGenTableRouter    = New(codeTaskCheckGenTableRouter, ClassTaskCheck, ScopeInternal, LevelMedium, "generate table router error", "Please check the `routes` config in task configuration file.")
+	ErrTaskCheckGenColumnMapping  = New(codeTaskCheckGenColumnMapping, ClassTaskCheck, ScopeInternal, LevelMedium, "generate column mapping error", "Please check the `column-mappings` config in task configuration file.")
+	ErrTaskCheckSyncConfigError   = New(codeTaskCheckSyncConfigError, ClassTaskCheck, ScopeInternal, LevelMedium, "%s: %v\n detail: %v", "")
+	ErrTaskCheckGenBAList         = New(codeTaskCheckGenBAList, ClassTaskCheck, ScopeInternal, LevelMedium, "generate block allow list error", "Please check the `block-allow-list` config in task configuration file.")
+	ErrSourceCheckGTID            = New(codeSourceCheckGTID, ClassTaskCheck, ScopeInternal, LevelMedium, "%s has GTID_MODE = %s instead of ON", "Please check the `enable-gtid` config in source configuration file.")
+	ErrTaskCheckCrossTaskConflict = New(codeTaskCheckCrossTaskConflict, ClassTaskCheck, ScopeInternal, LevelHigh, "downstream tables are written by other tasks: %s", "Please check the `routes` config in task configuration file, or use `extract-table`/`extract-schema`/`extract-source` in route rules to distinguish the rows of different tasks.")
 
 	// Relay log basic API error.
 	ErrRelayParseUUIDIndex         = New(codeRelayParseUUIDIndex, ClassRelayEventLib, ScopeInternal, LevelHigh, "parse server-uuid.index", "")
@@ -1008,6 +1018,7 @@ var (
 	ErrRelayPurgeArgsNotValid            = New(codeRelayPurgeArgsNotValid, ClassRelayUnit, ScopeInternal, LevelHigh, "args (%T) %+v not valid", "")
 	ErrPreviousGTIDsNotValid             = New(codePreviousGTIDsNotValid, ClassRelayUnit, ScopeInternal, LevelHigh, "previousGTIDs %s not valid", "")
 	ErrRotateEventWithDifferentServerID  = New(codeRotateEventWithDifferentServerID, ClassRelayUnit, ScopeInternal, LevelHigh, "receive fake rotate event with different server_id", "Please use `resume-relay` command if upstream database has changed")
+	ErrRelayImportBinlogBackup           = New(codeRelayImportBinlogBackup, ClassRelayUnit, ScopeInternal, LevelHigh, "import binlog backup %s: %s", "Please check the binlog files and the index file of the backup, the backup should end right before the first relay log file.")
 
 	// Dump unit error.
 	ErrDumpUnitRuntime        = New(codeDumpUnitRuntime, ClassDumpUnit, ScopeInternal, LevelHigh, "mydumper/dumpling runs with error, with output (may empty): %s", "")
@@ -1106,6 +1117,7 @@ var (
 	ErrSyncerParseDDL                       = New(codeSyncerParseDDL, ClassSyncUnit, ScopeInternal, LevelHigh, "parse DDL: %s", "Please confirm your DDL statement is correct and needed. For TiDB compatible DDL, see https://docs.pingcap.com/tidb/stable/mysql-compatibility#ddl. You can use `handle-error` command to skip or replace the DDL or add a binlog filter rule to ignore it if the DDL is not needed.")
 	ErrSyncerUnsupportedStmt                = New(codeSyncerUnsupportedStmt, ClassSyncUnit, ScopeInternal, LevelHigh, "`%s` statement not supported in %s mode", "")
 	ErrSyncerGetEvent                       = New(codeSyncerGetEvent, ClassSyncUnit, ScopeUpstream, LevelHigh, "get binlog event error: %v", "Please check if the binlog file could be parsed by `mysqlbinlog`.")
+	ErrSyncerBinlogRowImageNotFull          = New(codeSyncerBinlogRowImageNotFull, ClassSyncUnit, ScopeUpstream, LevelHigh, "upstream binlog_row_image is %s, but the sync unit requires FULL row images to replicate UPDATE and DELETE, e.g. an UPDATE is replicated as a DELETE and a REPLACE in safe mode", "Please execute `set global binlog_row_image = FULL;` on the upstream, and restart the task from a location after it.")
 
 	// DM-master error.
 	ErrMasterSQLOpNilRequest        = New(codeMasterSQLOpNilRequest, ClassDMMaster, ScopeInternal, LevelMedium, "nil request not valid", "")
@@ -150,8 +150,9 @@ type Error struct {
 }
 
 // New creates a new *Error instance.
+// The first *Error created with a given code is registered into the error catalog.
 func New(code ErrCode, class ErrClass, scope ErrScope, level ErrLevel, message string, workaround string) *Error {
-	return &Error{
+	e := &Error{
 		code:       code,
 		class:      class,
 		scope:      scope,
@@ -159,6 +160,8 @@ func New(code ErrCode, class ErrClass, scope ErrScope, level ErrLevel, message s
 		message:    message,
 		workaround: workaround,
 	}
+	register(e)
+	return e
 }
 
 // Code returns ErrCode.
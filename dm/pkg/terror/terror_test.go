@@ -199,3 +199,31 @@ func (t *testTErrorSuite) TestTerrorWithOperate(c *check.C) {
 	c.Assert(err.Equal(err2), check.IsTrue)
 	c.Assert(err2.Error(), check.Equals, fmt.Sprintf(errFormatWithArg, code, newClass, scope, level, "message with args", arg, workaround))
 }
+
+func (t *testTErrorSuite) TestCatalog(c *check.C) {
+	items := Catalog()
+	c.Assert(len(items), check.Greater, 0)
+	for i := 1; i < len(items); i++ {
+		c.Assert(items[i-1].Code, check.Less, items[i].Code)
+	}
+
+	// the first error created with a code is kept in the catalog
+	e, ok := ErrorByCode(codeDBBadConn)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(e, check.Equals, ErrDBBadConn)
+	New(codeDBBadConn, ClassFunctional, ScopeInternal, LevelLow, "", "")
+	e, ok = ErrorByCode(codeDBBadConn)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(e, check.Equals, ErrDBBadConn)
+
+	_, ok = ErrorByCode(ErrCode(-1))
+	c.Assert(ok, check.IsFalse)
+
+	// hints
+	c.Assert(ErrSyncerParseDDL.Hints(), check.HasLen, 2)
+	c.Assert(ErrSyncerParseDDL.Hints()[0].Action, check.Equals, HintActionRunCommand)
+	c.Assert(ErrNotSet.Hints(), check.IsNil)
+	hints := HintsByCode(codeSyncerParseDDL)
+	hints[0].Target = "changed"
+	c.Assert(ErrSyncerParseDDL.Hints()[0].Target, check.Equals, "binlog skip")
+}
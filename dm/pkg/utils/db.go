@@ -291,6 +291,23 @@ func GetMariaDBGTID(ctx context.Context, db *sql.DB) (gtid.Set, error) {
 	return gs, nil
 }
 
+// GetTiDBCurrentTSO gets the current TSO of TiDB, which is the start ts of
+// a new transaction.
+func GetTiDBCurrentTSO(ctx context.Context, db *sql.DB) (uint64, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, terror.DBErrorAdapt(err, terror.ErrDBDriverError)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var ts uint64
+	if err = tx.QueryRowContext(ctx, "SELECT @@tidb_current_ts").Scan(&ts); err != nil {
+		return 0, terror.DBErrorAdapt(err, terror.ErrDBDriverError)
+	}
+	return ts, nil
+}
+
 // GetGlobalVariable gets server's global variable.
 func GetGlobalVariable(ctx context.Context, db *sql.DB, variable string) (value string, err error) {
 	failpoint.Inject("GetGlobalVariableFailed", func(val failpoint.Value) {
@@ -526,6 +543,27 @@ func GetGTIDMode(ctx context.Context, db *sql.DB) (string, error) {
 	return val, err
 }
 
+// The values of `binlog_row_image`.
+const (
+	BinlogRowImageFull    = "FULL"
+	BinlogRowImageMinimal = "MINIMAL"
+	BinlogRowImageNoBlob  = "NOBLOB"
+)
+
+// GetBinlogRowImage returns `binlog_row_image` of the server in upper case. It's
+// FULL for the servers without the variable, i.e. MySQL before 5.6.2 and MariaDB
+// before 10.1.6, because they always log full row images.
+func GetBinlogRowImage(ctx context.Context, db *sql.DB) (string, error) {
+	val, err := GetGlobalVariable(ctx, db, "binlog_row_image")
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return BinlogRowImageFull, nil
+		}
+		return "", err
+	}
+	return strings.ToUpper(val), nil
+}
+
 // ExtractTiDBVersion extract tidb's version
 // version format: "5.7.25-TiDB-v3.0.0-beta-211-g09beefbe0-dirty"
 //                               ^~~~~~~~~^
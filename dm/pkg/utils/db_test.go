@@ -173,6 +173,22 @@ func (t *testDBSuite) TestGetMariaDBGtidDomainID(c *C) {
 	c.Assert(mock.ExpectationsWereMet(), IsNil)
 }
 
+func (t *testDBSuite) TestGetTiDBCurrentTSO(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDBTimeout)
+	defer cancel()
+
+	db, mock, err := sqlmock.New()
+	c.Assert(err, IsNil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT @@tidb_current_ts`).WillReturnRows(mock.NewRows([]string{"@@tidb_current_ts"}).AddRow(432154418460966913))
+	mock.ExpectRollback()
+	ts, err := GetTiDBCurrentTSO(ctx, db)
+	c.Assert(err, IsNil)
+	c.Assert(ts, Equals, uint64(432154418460966913))
+	c.Assert(mock.ExpectationsWereMet(), IsNil)
+}
+
 func (t *testDBSuite) TestGetServerUUID(c *C) {
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultDBTimeout)
 	defer cancel()
@@ -304,6 +320,27 @@ func (t *testDBSuite) TestGetGTID(c *C) {
 	c.Assert(mock.ExpectationsWereMet(), IsNil)
 }
 
+func (t *testDBSuite) TestGetBinlogRowImage(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDBTimeout)
+	defer cancel()
+
+	db, mock, err := sqlmock.New()
+	c.Assert(err, IsNil)
+
+	rows := mock.NewRows([]string{"Variable_name", "Value"}).AddRow("binlog_row_image", "minimal")
+	mock.ExpectQuery(`SHOW GLOBAL VARIABLES LIKE 'binlog_row_image'`).WillReturnRows(rows)
+	image, err := GetBinlogRowImage(ctx, db)
+	c.Assert(err, IsNil)
+	c.Assert(image, Equals, BinlogRowImageMinimal)
+
+	// the variable doesn't exist before MySQL 5.6.2.
+	mock.ExpectQuery(`SHOW GLOBAL VARIABLES LIKE 'binlog_row_image'`).WillReturnRows(mock.NewRows([]string{"Variable_name", "Value"}))
+	image, err = GetBinlogRowImage(ctx, db)
+	c.Assert(err, IsNil)
+	c.Assert(image, Equals, BinlogRowImageFull)
+	c.Assert(mock.ExpectationsWereMet(), IsNil)
+}
+
 func (t *testDBSuite) TestMySQLError(c *C) {
 	err := newMysqlErr(tmysql.ErrNoSuchThread, "Unknown thread id: 111")
 	c.Assert(IsNoSuchThreadError(err), Equals, true)
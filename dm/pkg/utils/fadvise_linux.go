@@ -0,0 +1,45 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// AdviseSequential tells the kernel that f will be read sequentially, so it can
+// use a larger readahead window. If readahead is positive, it also starts to
+// read [offset, offset+readahead) of f into the page cache in background.
+func AdviseSequential(f *os.File, offset, readahead int64) error {
+	fd := int(f.Fd())
+	if err := unix.Fadvise(fd, offset, 0, unix.FADV_SEQUENTIAL); err != nil {
+		return err
+	}
+	if readahead > 0 {
+		return unix.Fadvise(fd, offset, readahead, unix.FADV_WILLNEED)
+	}
+	return nil
+}
+
+// DropPageCache tells the kernel that [offset, offset+length) of f won't be
+// accessed in the near future, so its page cache could be dropped. length 0
+// means to the end of the file. Note that dirty pages can't be dropped, caller
+// should sync the file first.
+func DropPageCache(f *os.File, offset, length int64) error {
+	return unix.Fadvise(int(f.Fd()), offset, length, unix.FADV_DONTNEED)
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package utils
+
+import "os"
+
+// AdviseSequential is a no-op on platforms without posix_fadvise.
+func AdviseSequential(f *os.File, offset, readahead int64) error {
+	return nil
+}
+
+// DropPageCache is a no-op on platforms without posix_fadvise.
+func DropPageCache(f *os.File, offset, length int64) error {
+	return nil
+}
@@ -80,10 +80,15 @@ func WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
 		return err
 	}
 	n, err := f.Write(data)
-	f.Close()
 	if err == nil && n < len(data) {
 		err = io.ErrShortWrite
-	} else {
+	}
+	if err == nil {
+		// sync before rename, otherwise the renamed file may be empty after a power loss.
+		err = f.Sync()
+	}
+	f.Close()
+	if err == nil {
 		err = os.Chmod(f.Name(), perm)
 	}
 	if err != nil {
@@ -93,7 +98,20 @@ func WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
 			zap.Error(err2))
 		return err
 	}
-	return os.Rename(f.Name(), filename)
+	if err = os.Rename(f.Name(), filename); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir fsyncs the directory to persist the entries in it.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
 }
 
 // CollectDirFiles gets files in path.
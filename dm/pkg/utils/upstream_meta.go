@@ -0,0 +1,134 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	gmysql "github.com/go-mysql-org/go-mysql/mysql"
+
+	"github.com/pingcap/tiflow/dm/pkg/gtid"
+)
+
+const (
+	// masterStatusCacheTTL is how long a SHOW MASTER STATUS result is reused,
+	// it's much shorter than the intervals of the periodic queries of a single unit.
+	masterStatusCacheTTL = 5 * time.Second
+	// serverUnixTSCacheTTL is how long a UNIX_TIMESTAMP() result is reused, the
+	// cached ts is moved forward by the local elapsed time when it's returned.
+	serverUnixTSCacheTTL = time.Minute
+)
+
+var (
+	upstreamMetaFetchersMu sync.Mutex
+	upstreamMetaFetchers   = make(map[string]*UpstreamMetaFetcher)
+)
+
+// UpstreamMetaFetcher caches the metadata periodically queried from an upstream
+// database, such as SHOW MASTER STATUS and UNIX_TIMESTAMP(). The relay, the
+// source worker and the syncers of all subtasks on a DM-worker share one fetcher
+// per upstream, so the upstream is queried at most once per TTL instead of once
+// per unit. Concurrent callers wait for the ongoing query instead of issuing an
+// identical one.
+//
+// A nil *UpstreamMetaFetcher queries the upstream directly without caching.
+type UpstreamMetaFetcher struct {
+	masterStatusMu   sync.Mutex
+	masterStatusTime time.Time
+	flavor           string
+	pos              gmysql.Position
+	gs               gtid.Set
+
+	serverTSMu   sync.Mutex
+	serverTSTime time.Time
+	serverTS     int64
+}
+
+// GetUpstreamMetaFetcher returns the fetcher shared by all units on this
+// process for the upstream at host:port.
+func GetUpstreamMetaFetcher(host string, port int) *UpstreamMetaFetcher {
+	key := fmt.Sprintf("%s:%d", host, port)
+	upstreamMetaFetchersMu.Lock()
+	defer upstreamMetaFetchersMu.Unlock()
+	f, ok := upstreamMetaFetchers[key]
+	if !ok {
+		f = &UpstreamMetaFetcher{}
+		upstreamMetaFetchers[key] = f
+	}
+	return f
+}
+
+// RemoveUpstreamMetaFetcher removes the fetcher of the upstream at host:port,
+// it's called once the source is removed from this process so the fetchers of
+// the sources ever bound don't pile up. The units still holding the removed
+// fetcher keep using it, the others get a new one.
+func RemoveUpstreamMetaFetcher(host string, port int) {
+	key := fmt.Sprintf("%s:%d", host, port)
+	upstreamMetaFetchersMu.Lock()
+	defer upstreamMetaFetchersMu.Unlock()
+	delete(upstreamMetaFetchers, key)
+}
+
+// GetPosAndGs is like the package level GetPosAndGs, but returns the cached
+// result if it's queried within masterStatusCacheTTL. The returned gtid.Set is
+// a copy and can be modified by the caller.
+func (f *UpstreamMetaFetcher) GetPosAndGs(ctx context.Context, db *sql.DB, flavor string) (
+	gmysql.Position, gtid.Set, error,
+) {
+	if f == nil {
+		return GetPosAndGs(ctx, db, flavor)
+	}
+
+	f.masterStatusMu.Lock()
+	defer f.masterStatusMu.Unlock()
+	if f.flavor != flavor || time.Since(f.masterStatusTime) >= masterStatusCacheTTL {
+		pos, gs, err := GetPosAndGs(ctx, db, flavor)
+		if err != nil {
+			return pos, gs, err
+		}
+		f.flavor, f.pos, f.gs = flavor, pos, gs
+		f.masterStatusTime = time.Now()
+	}
+
+	var gs gtid.Set
+	if f.gs != nil {
+		gs = f.gs.Clone()
+	}
+	return f.pos, gs, nil
+}
+
+// GetServerUnixTS is like the package level GetServerUnixTS, but returns the
+// cached result moved forward by the elapsed time if it's queried within
+// serverUnixTSCacheTTL.
+func (f *UpstreamMetaFetcher) GetServerUnixTS(ctx context.Context, db *sql.DB) (int64, error) {
+	if f == nil {
+		return GetServerUnixTS(ctx, db)
+	}
+
+	f.serverTSMu.Lock()
+	defer f.serverTSMu.Unlock()
+	if time.Since(f.serverTSTime) >= serverUnixTSCacheTTL {
+		ts, err := GetServerUnixTS(ctx, db)
+		if err != nil {
+			return ts, err
+		}
+		f.serverTS = ts
+		f.serverTSTime = time.Now()
+	}
+	return f.serverTS + int64(time.Since(f.serverTSTime)/time.Second), nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gmysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamMetaFetcherGetPosAndGs(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+
+	f := GetUpstreamMetaFetcher("127.0.0.1", 3306)
+	require.Same(t, f, GetUpstreamMetaFetcher("127.0.0.1", 3306))
+	require.NotSame(t, f, GetUpstreamMetaFetcher("127.0.0.1", 3307))
+	// a new fetcher is returned after the source is removed.
+	RemoveUpstreamMetaFetcher("127.0.0.1", 3307)
+	upstreamMetaFetchersMu.Lock()
+	require.NotContains(t, upstreamMetaFetchers, "127.0.0.1:3307")
+	upstreamMetaFetchersMu.Unlock()
+	require.Same(t, f, GetUpstreamMetaFetcher("127.0.0.1", 3306))
+
+	// only the first call queries the upstream.
+	rows := mock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}).AddRow(
+		"mysql-bin.000009", 11232, "", "", "074be7f4-f0f1-11ea-95bd-0242ac120002:1-699",
+	)
+	mock.ExpectQuery(`SHOW MASTER STATUS`).WillReturnRows(rows)
+	for i := 0; i < 3; i++ {
+		pos, gs, err2 := f.GetPosAndGs(ctx, db, "mysql")
+		require.Nil(t, err2)
+		require.Equal(t, gmysql.Position{Name: "mysql-bin.000009", Pos: 11232}, pos)
+		require.Equal(t, "074be7f4-f0f1-11ea-95bd-0242ac120002:1-699", gs.String())
+		// the caller can't modify the cached gtid set.
+		require.Nil(t, gs.Update("074be7f4-f0f1-11ea-95bd-0242ac120002:1-700"))
+	}
+	require.Nil(t, mock.ExpectationsWereMet())
+
+	// query again after the cache expires.
+	f.masterStatusTime = time.Now().Add(-masterStatusCacheTTL)
+	rows = mock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}).AddRow(
+		"mysql-bin.000010", 4, "", "", "074be7f4-f0f1-11ea-95bd-0242ac120002:1-800",
+	)
+	mock.ExpectQuery(`SHOW MASTER STATUS`).WillReturnRows(rows)
+	pos, gs, err := f.GetPosAndGs(ctx, db, "mysql")
+	require.Nil(t, err)
+	require.Equal(t, gmysql.Position{Name: "mysql-bin.000010", Pos: 4}, pos)
+	require.Equal(t, "074be7f4-f0f1-11ea-95bd-0242ac120002:1-800", gs.String())
+	require.Nil(t, mock.ExpectationsWereMet())
+
+	// errors are not cached.
+	f.masterStatusTime = time.Time{}
+	mock.ExpectQuery(`SHOW MASTER STATUS`).WillReturnRows(
+		mock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}))
+	_, _, err = f.GetPosAndGs(ctx, db, "mysql")
+	require.NotNil(t, err)
+	rows = mock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}).AddRow(
+		"mysql-bin.000010", 100, "", "", "074be7f4-f0f1-11ea-95bd-0242ac120002:1-801",
+	)
+	mock.ExpectQuery(`SHOW MASTER STATUS`).WillReturnRows(rows)
+	pos, _, err = f.GetPosAndGs(ctx, db, "mysql")
+	require.Nil(t, err)
+	require.Equal(t, uint32(100), pos.Pos)
+	require.Nil(t, mock.ExpectationsWereMet())
+
+	// a nil fetcher always queries the upstream.
+	var nilFetcher *UpstreamMetaFetcher
+	rows = mock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}).AddRow(
+		"mysql-bin.000011", 4, "", "", "",
+	)
+	mock.ExpectQuery(`SHOW MASTER STATUS`).WillReturnRows(rows)
+	pos, _, err = nilFetcher.GetPosAndGs(ctx, db, "mysql")
+	require.Nil(t, err)
+	require.Equal(t, "mysql-bin.000011", pos.Name)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpstreamMetaFetcherGetServerUnixTS(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+
+	f := GetUpstreamMetaFetcher("127.0.0.2", 3306)
+	mock.ExpectQuery("SELECT UNIX_TIMESTAMP()").WillReturnRows(sqlmock.NewRows([]string{"UNIX_TIMESTAMP()"}).AddRow(1000))
+	ts, err := f.GetServerUnixTS(ctx, db)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000), ts)
+
+	// the cached ts is moved forward by the elapsed time.
+	f.serverTSTime = f.serverTSTime.Add(-10 * time.Second)
+	ts, err = f.GetServerUnixTS(ctx, db)
+	require.Nil(t, err)
+	require.Equal(t, int64(1010), ts)
+	require.Nil(t, mock.ExpectationsWereMet())
+
+	f.serverTSTime = time.Now().Add(-serverUnixTSCacheTTL)
+	mock.ExpectQuery("SELECT UNIX_TIMESTAMP()").WillReturnRows(sqlmock.NewRows([]string{"UNIX_TIMESTAMP()"}).AddRow(2000))
+	ts, err = f.GetServerUnixTS(ctx, db)
+	require.Nil(t, err)
+	require.Equal(t, int64(2000), ts)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"testing"
 
 	. "github.com/pingcap/check"
 
@@ -103,3 +104,67 @@ func (t *testBinlogWriterSuite) TestWrite(c *C) {
 		c.Assert(dataInFile, DeepEquals, allData.Bytes())
 	}
 }
+
+func (t *testBinlogWriterSuite) TestWriteDropPageCache(c *C) {
+	dir := c.MkDir()
+	uuid := "3ccc475b-2343-11e7-be21-6c0b84d59f30.000001"
+	c.Assert(os.Mkdir(filepath.Join(dir, uuid), 0o755), IsNil)
+
+	w := NewBinlogWriter(log.L(), dir)
+	w.dropPageCache = true
+	c.Assert(w.Open(uuid, "test-mysql-bin.000001"), IsNil)
+
+	data := make([]byte, dropPageCacheInterval/2)
+	c.Assert(w.Write(data), IsNil)
+	c.Assert(w.droppedOffset, Equals, int64(0))
+	c.Assert(w.Write(data), IsNil)
+	c.Assert(w.droppedOffset, Equals, int64(dropPageCacheInterval))
+	c.Assert(w.Write(data), IsNil)
+	c.Assert(w.droppedOffset, Equals, int64(dropPageCacheInterval))
+
+	// the page cache of a file being read is left to its last reader.
+	fullName := filepath.Join(dir, uuid, "test-mysql-bin.000001")
+	w.pageCache = newPageCacheTracker()
+	w.pageCache.open(fullName)
+	c.Assert(w.Write(data), IsNil)
+	c.Assert(w.Write(data), IsNil)
+	c.Assert(w.droppedOffset, Equals, int64(dropPageCacheInterval))
+	c.Assert(w.pageCache.close(fullName), IsTrue)
+	c.Assert(w.Write(data), IsNil)
+	c.Assert(w.droppedOffset, Equals, int64(3*dropPageCacheInterval))
+
+	c.Assert(w.Close(), IsNil)
+}
+
+func benchmarkBinlogWriterWrite(b *testing.B, dropPageCache bool) {
+	dir := b.TempDir()
+	uuid := "3ccc475b-2343-11e7-be21-6c0b84d59f30.000001"
+	if err := os.Mkdir(filepath.Join(dir, uuid), 0o755); err != nil {
+		b.Fatal(err)
+	}
+
+	w := NewBinlogWriter(log.L(), dir)
+	w.dropPageCache = dropPageCache
+	if err := w.Open(uuid, "test-mysql-bin.000001"); err != nil {
+		b.Fatal(err)
+	}
+	defer w.Close()
+
+	// size of a common row event
+	data := make([]byte, 4096)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := w.Write(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBinlogWriterWrite(b *testing.B) {
+	benchmarkBinlogWriterWrite(b, false)
+}
+
+func BenchmarkBinlogWriterWriteDropPageCache(b *testing.B) {
+	benchmarkBinlogWriterWrite(b, true)
+}
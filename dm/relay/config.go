@@ -22,7 +22,8 @@ import (
 
 // Config is the configuration for Relay.
 type Config struct {
-	EnableGTID bool `toml:"enable-gtid" json:"enable-gtid"`
+	SourceID   string `toml:"source-id" json:"source-id"`
+	EnableGTID bool   `toml:"enable-gtid" json:"enable-gtid"`
 	// deprecated
 	AutoFixGTID bool            `toml:"auto-fix-gtid" json:"auto-fix-gtid"`
 	RelayDir    string          `toml:"relay-dir" json:"relay-dir"`
@@ -39,6 +40,9 @@ type Config struct {
 
 	// for binlog reader retry
 	ReaderRetry ReaderRetryConfig `toml:"reader-retry" json:"reader-retry"`
+
+	// for relay log file IO
+	IO config.RelayIOConfig `toml:"relay-io" json:"relay-io"`
 }
 
 func (c *Config) String() string {
@@ -53,6 +57,7 @@ func (c *Config) String() string {
 func FromSourceCfg(sourceCfg *config.SourceConfig) *Config {
 	clone := sourceCfg.DecryptPassword()
 	cfg := &Config{
+		SourceID:   clone.SourceID,
 		EnableGTID: clone.EnableGTID,
 		Flavor:     clone.Flavor,
 		RelayDir:   clone.RelayDir,
@@ -69,6 +74,7 @@ func FromSourceCfg(sourceCfg *config.SourceConfig) *Config {
 			BackoffJitter:   clone.Checker.BackoffJitter,
 			BackoffFactor:   clone.Checker.BackoffFactor,
 		},
+		IO: clone.RelayIO,
 	}
 	return cfg
 }
@@ -0,0 +1,316 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tiflow/dm/pkg/binlog"
+	"github.com/pingcap/tiflow/dm/pkg/binlog/event"
+	"github.com/pingcap/tiflow/dm/pkg/gtid"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+)
+
+// binlogIndexSuffix is the suffix of the index file of binlog files, like
+// `mysql-bin.index` of MySQL.
+const binlogIndexSuffix = ".index"
+
+// ImportBinlogBackup seeds the relay log directory of the upstream server with
+// the binlog files of a backup set, so the relay log can serve binlog events
+// earlier than the position the relay started from. The backup set is a
+// directory holding the binlog files and the index file listing them in order.
+// The files must be contiguous, and the last one must be right before the
+// first relay log file, or before the start position if no relay log file has
+// been written yet. A file is contiguous to the next one if its rotate event
+// points to the next one, and the GTID sets at its end and at the start of the
+// next one are the same if GTID is enabled. The names of the imported files
+// are returned.
+func (r *Relay) ImportBinlogBackup(backupDir string) ([]string, error) {
+	r.importMu.Lock()
+	defer r.importMu.Unlock()
+
+	genErr := func(format string, args ...interface{}) error {
+		return terror.ErrRelayImportBinlogBackup.Generate(backupDir, fmt.Sprintf(format, args...))
+	}
+
+	uuid := r.meta.UUID()
+	if uuid == "" {
+		return nil, genErr("relay has not started")
+	}
+	uuids, err := utils.ParseUUIDIndex(filepath.Join(r.cfg.RelayDir, utils.UUIDIndexFilename))
+	if err != nil {
+		return nil, err
+	}
+	if len(uuids) > 0 && uuids[0] != uuid {
+		return nil, genErr("relay has switched the upstream server from %s to %s, "+
+			"only the relay log of the first upstream server can be seeded", uuids[0], uuid)
+	}
+
+	files, err := readBinlogBackupIndex(backupDir)
+	if err != nil {
+		return nil, genErr("%s", err)
+	}
+	if len(files) == 0 {
+		return nil, genErr("no binlog file in the index file")
+	}
+	last, lastBoundary, err := verifyBinlogBackupFiles(backupDir, files)
+	if err != nil {
+		return nil, genErr("%s", err)
+	}
+
+	relayDir := r.meta.Dir()
+	relayFiles, err := binlog.ReadSortedBinlogFromDir(relayDir)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		first      string
+		firstGTIDs gtid.Set
+	)
+	if len(relayFiles) > 0 {
+		first = relayFiles[0]
+	} else {
+		_, pos := r.meta.Pos()
+		first = pos.Name
+		// the relay starts from the GTID set if it's not empty
+		if _, gs := r.meta.GTID(); gs != nil && gs.String() != "" {
+			firstGTIDs = gs
+		}
+	}
+	firstName, err := binlog.ParseFilename(first)
+	if err != nil {
+		return nil, genErr("the first relay log file %s is unknown", first)
+	}
+	lastFile := binlog.ConstructFilename(last.BaseName, last.Seq)
+	if firstName.BaseName != last.BaseName || firstName.SeqInt64 != last.SeqInt64+1 {
+		return nil, genErr("the last binlog file %s is not right before the first relay log file %s",
+			lastFile, first)
+	}
+	if len(relayFiles) > 0 {
+		firstBoundary, err2 := readBinlogBoundary(filepath.Join(relayDir, first), true)
+		if err2 != nil {
+			return nil, genErr("%s", err2)
+		}
+		firstGTIDs = firstBoundary.startGTIDs
+	}
+	if err = checkBinlogContiguous(lastFile, lastBoundary, first, firstGTIDs); err != nil {
+		return nil, genErr("%s", err)
+	}
+
+	for _, file := range files {
+		if err = copyBinlogFile(filepath.Join(backupDir, file), filepath.Join(relayDir, file)); err != nil {
+			return nil, genErr("%s", err)
+		}
+	}
+	r.logger.Info("imported binlog backup into relay log",
+		zap.String("backup dir", backupDir),
+		zap.String("relay dir", relayDir),
+		zap.Strings("files", files))
+	return files, nil
+}
+
+// readBinlogBackupIndex reads the names of binlog files from the index file in
+// the directory. The entries of the index file may be paths of the files, like
+// `./mysql-bin.000001`, only their base names are returned.
+func readBinlogBackupIndex(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+binlogIndexSuffix))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("there should be exactly one index file, found %d", len(matches))
+	}
+	f, err := os.Open(matches[0])
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var files []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		files = append(files, filepath.Base(line))
+	}
+	return files, scanner.Err()
+}
+
+// verifyBinlogBackupFiles checks the binlog files are valid and contiguous,
+// and returns the name and the boundary of the last one.
+func verifyBinlogBackupFiles(dir string, files []string) (binlog.Filename, *binlogBoundary, error) {
+	var (
+		prev         binlog.Filename
+		prevBoundary *binlogBoundary
+	)
+	for i, file := range files {
+		name, err := binlog.ParseFilename(file)
+		if err != nil {
+			return prev, nil, err
+		}
+		prevFile := binlog.ConstructFilename(prev.BaseName, prev.Seq)
+		if i > 0 && (name.BaseName != prev.BaseName || name.SeqInt64 != prev.SeqInt64+1) {
+			return prev, nil, fmt.Errorf("binlog file %s doesn't follow %s", file, prevFile)
+		}
+		exist, err := checkFormatDescriptionEventExist(filepath.Join(dir, file))
+		if err != nil {
+			return prev, nil, err
+		}
+		if !exist {
+			return prev, nil, fmt.Errorf("binlog file %s has no format description event", file)
+		}
+		boundary, err := readBinlogBoundary(filepath.Join(dir, file), false)
+		if err != nil {
+			return prev, nil, err
+		}
+		if i > 0 {
+			if err = checkBinlogContiguous(prevFile, prevBoundary, file, boundary.startGTIDs); err != nil {
+				return prev, nil, err
+			}
+		}
+		prev, prevBoundary = name, boundary
+	}
+	return prev, prevBoundary, nil
+}
+
+// binlogBoundary is the boundary of a binlog file, it's made up of the GTID
+// sets before and after the events in the file, and the next file the rotate
+// event at the end of the file points to. The GTID sets are nil if the file
+// has no PreviousGTIDsEvent or MariadbGTIDListEvent.
+type binlogBoundary struct {
+	startGTIDs gtid.Set
+	endGTIDs   gtid.Set
+	nextFile   string
+}
+
+// readBinlogBoundary reads the boundary of the binlog file, only the GTID set
+// before the events in the file is read if startOnly is true.
+func readBinlogBoundary(filename string, startOnly bool) (*binlogBoundary, error) {
+	var (
+		b       = &binlogBoundary{}
+		stopped bool
+	)
+	onEventFunc := func(e *replication.BinlogEvent) error {
+		// only the rotate event at the end of the file points to the next file
+		b.nextFile = ""
+		var err error
+		switch ev := e.Event.(type) {
+		case *replication.FormatDescriptionEvent:
+			return nil
+		case *replication.PreviousGTIDsEvent:
+			b.startGTIDs, err = event.GTIDsFromPreviousGTIDsEvent(e)
+		case *replication.MariadbGTIDListEvent:
+			b.startGTIDs, err = event.GTIDsFromMariaDBGTIDListEvent(e)
+		case *replication.GTIDEvent, *replication.MariadbGTIDEvent:
+			if b.endGTIDs == nil || e.Header.EventType == replication.ANONYMOUS_GTID_EVENT {
+				return nil
+			}
+			var gtidStr string
+			if gtidStr, err = event.GetGTIDStr(e); err != nil {
+				return err
+			}
+			return b.endGTIDs.Update(gtidStr)
+		case *replication.RotateEvent:
+			b.nextFile = string(ev.NextLogName)
+			return nil
+		default:
+			if startOnly {
+				stopped = true
+				return errStopParseBinlog
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if startOnly {
+			stopped = true
+			return errStopParseBinlog
+		}
+		b.endGTIDs = b.startGTIDs.Clone()
+		return nil
+	}
+	err := replication.NewBinlogParser().ParseFile(filename, 0, onEventFunc)
+	if err != nil && !stopped {
+		return nil, fmt.Errorf("parse binlog file %s: %w", filepath.Base(filename), err)
+	}
+	return b, nil
+}
+
+// errStopParseBinlog stops parsing a binlog file once the needed events are read.
+var errStopParseBinlog = errors.New("stop parsing binlog file")
+
+// checkBinlogContiguous checks the binlog file is right before the next file,
+// nextGTIDs is the GTID set at the start of the next file, it's nil if unknown.
+func checkBinlogContiguous(file string, boundary *binlogBoundary, next string, nextGTIDs gtid.Set) error {
+	if boundary.nextFile != "" && boundary.nextFile != next {
+		return fmt.Errorf("binlog file %s rotates to %s rather than %s", file, boundary.nextFile, next)
+	}
+	if boundary.endGTIDs != nil && nextGTIDs != nil {
+		if !boundary.endGTIDs.Equal(nextGTIDs) {
+			return fmt.Errorf("GTID set %s at the end of binlog file %s doesn't match GTID set %s at the start of %s",
+				boundary.endGTIDs, file, nextGTIDs, next)
+		}
+		return nil
+	}
+	if boundary.nextFile == "" {
+		return fmt.Errorf("binlog file %s doesn't end with a rotate event, it may be incomplete", file)
+	}
+	return nil
+}
+
+// copyBinlogFile copies the binlog file to the relay log directory, the file
+// is written to a temporary file first so readers never see a partial file.
+func copyBinlogFile(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("relay log file %s already exists", dst)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".importing"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gmysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	. "github.com/pingcap/check"
+
+	"github.com/pingcap/tiflow/dm/pkg/binlog"
+	"github.com/pingcap/tiflow/dm/pkg/binlog/event"
+	"github.com/pingcap/tiflow/dm/pkg/gtid"
+)
+
+const binlogBackupServerUUID = "3ccc475b-2343-11e7-be21-6c0b84d59f30"
+
+// genBinlogBackupFile generates a binlog file starting from the GTID set
+// `uuid:1-gno`, with a transaction of the next GNO and a rotate event to next.
+func genBinlogBackupFile(c *C, gno int64, next string) []byte {
+	header := &replication.EventHeader{
+		Timestamp: uint32(time.Now().Unix()),
+		ServerID:  11,
+	}
+	formatDescEv, err := event.GenFormatDescriptionEvent(header, 4)
+	c.Assert(err, IsNil)
+	gSet, err := gtid.ParserGTID(gmysql.MySQLFlavor, fmt.Sprintf("%s:1-%d", binlogBackupServerUUID, gno))
+	c.Assert(err, IsNil)
+	prevGTIDsEv, err := event.GenPreviousGTIDsEvent(header, formatDescEv.Header.LogPos, gSet)
+	c.Assert(err, IsNil)
+	gtidEv, err := event.GenGTIDEvent(header, prevGTIDsEv.Header.LogPos, 0, binlogBackupServerUUID, gno+1, 0, 0)
+	c.Assert(err, IsNil)
+	rotateEv, err := event.GenRotateEvent(header, gtidEv.Header.LogPos, []byte(next), 4)
+	c.Assert(err, IsNil)
+
+	var buf bytes.Buffer
+	buf.Write(replication.BinLogFileHeader)
+	for _, ev := range []*replication.BinlogEvent{formatDescEv, prevGTIDsEv, gtidEv, rotateEv} {
+		buf.Write(ev.RawData)
+	}
+	return buf.Bytes()
+}
+
+// writeBinlogBackup writes a binlog backup set with the contiguous files into
+// the dir, the last one rotates to the file right after it.
+func writeBinlogBackup(c *C, dir string, files ...string) {
+	for i, file := range files {
+		name, err := binlog.ParseFilename(file)
+		c.Assert(err, IsNil)
+		next := binlog.ConstructFilename(name.BaseName, fmt.Sprintf("%06d", name.SeqInt64+1))
+		data := genBinlogBackupFile(c, int64(i+1), next)
+		c.Assert(os.WriteFile(filepath.Join(dir, file), data, 0o600), IsNil)
+	}
+	index := "./" + strings.Join(files, "\n./") + "\n"
+	c.Assert(os.WriteFile(filepath.Join(dir, "mysql-bin.index"), []byte(index), 0o600), IsNil)
+}
+
+func (t *testRelaySuite) TestImportBinlogBackup(c *C) {
+	var (
+		uuid     = "24ecd093-8cec-11e9-aa0d-0242ac170002"
+		startPos = gmysql.Position{Name: "mysql-bin.000003", Pos: 4}
+		relayCfg = newRelayCfg(c, gmysql.MySQLFlavor)
+		r        = NewRelay(relayCfg).(*Relay)
+	)
+	backupDir := c.MkDir()
+	writeBinlogBackup(c, backupDir, "mysql-bin.000001", "mysql-bin.000002")
+
+	// relay has not started
+	_, err := r.ImportBinlogBackup(backupDir)
+	c.Assert(err, ErrorMatches, ".*relay has not started.*")
+
+	c.Assert(r.meta.Load(), IsNil)
+	c.Assert(r.meta.AddDir(uuid, &startPos, nil, 0), IsNil)
+
+	// files are not contiguous
+	gapDir := c.MkDir()
+	writeBinlogBackup(c, gapDir, "mysql-bin.000001", "mysql-bin.000003")
+	_, err = r.ImportBinlogBackup(gapDir)
+	c.Assert(err, ErrorMatches, ".*binlog file mysql-bin.000003 doesn't follow mysql-bin.000001.*")
+
+	// files are not right before the start position
+	earlyDir := c.MkDir()
+	writeBinlogBackup(c, earlyDir, "mysql-bin.000001")
+	_, err = r.ImportBinlogBackup(earlyDir)
+	c.Assert(err, ErrorMatches, ".*not right before the first relay log file mysql-bin.000003.*")
+
+	// file without format description event
+	invalidDir := c.MkDir()
+	writeBinlogBackup(c, invalidDir, "mysql-bin.000002")
+	c.Assert(os.WriteFile(filepath.Join(invalidDir, "mysql-bin.000002"), replication.BinLogFileHeader, 0o600), IsNil)
+	_, err = r.ImportBinlogBackup(invalidDir)
+	c.Assert(err, ErrorMatches, ".*has no format description event.*")
+
+	// file rotates to another file
+	rotateDir := c.MkDir()
+	writeBinlogBackup(c, rotateDir, "mysql-bin.000001", "mysql-bin.000002")
+	c.Assert(os.WriteFile(filepath.Join(rotateDir, "mysql-bin.000001"),
+		genBinlogBackupFile(c, 1, "mysql-bin.000005"), 0o600), IsNil)
+	_, err = r.ImportBinlogBackup(rotateDir)
+	c.Assert(err, ErrorMatches, ".*binlog file mysql-bin.000001 rotates to mysql-bin.000005 rather than mysql-bin.000002.*")
+
+	// GTID sets of the files are not contiguous
+	gtidGapDir := c.MkDir()
+	writeBinlogBackup(c, gtidGapDir, "mysql-bin.000001", "mysql-bin.000002")
+	c.Assert(os.WriteFile(filepath.Join(gtidGapDir, "mysql-bin.000002"),
+		genBinlogBackupFile(c, 5, "mysql-bin.000003"), 0o600), IsNil)
+	_, err = r.ImportBinlogBackup(gtidGapDir)
+	c.Assert(err, ErrorMatches, ".*GTID set .*:1-2 at the end of binlog file mysql-bin.000001 doesn't match GTID set .*:1-5 at the start of mysql-bin.000002.*")
+
+	// the last file doesn't rotate to the start position
+	noRotateDir := c.MkDir()
+	writeBinlogBackup(c, noRotateDir, "mysql-bin.000002")
+	data, err := os.ReadFile(filepath.Join(noRotateDir, "mysql-bin.000002"))
+	c.Assert(err, IsNil)
+	rotateEv, err := event.GenRotateEvent(&replication.EventHeader{}, 0, []byte("mysql-bin.000003"), 4)
+	c.Assert(err, IsNil)
+	data = data[:len(data)-len(rotateEv.RawData)]
+	c.Assert(os.WriteFile(filepath.Join(noRotateDir, "mysql-bin.000002"), data, 0o600), IsNil)
+	_, err = r.ImportBinlogBackup(noRotateDir)
+	c.Assert(err, ErrorMatches, ".*binlog file mysql-bin.000002 doesn't end with a rotate event.*")
+
+	files, err := r.ImportBinlogBackup(backupDir)
+	c.Assert(err, IsNil)
+	c.Assert(files, DeepEquals, []string{"mysql-bin.000001", "mysql-bin.000002"})
+	for _, file := range files {
+		expected, err2 := os.ReadFile(filepath.Join(backupDir, file))
+		c.Assert(err2, IsNil)
+		imported, err2 := os.ReadFile(filepath.Join(r.meta.Dir(), file))
+		c.Assert(err2, IsNil)
+		c.Assert(imported, DeepEquals, expected)
+	}
+
+	// the backup is not right before the imported files anymore
+	_, err = r.ImportBinlogBackup(backupDir)
+	c.Assert(err, ErrorMatches, ".*not right before the first relay log file mysql-bin.000001.*")
+}
@@ -0,0 +1,265 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/pingcap/errors"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tiflow/dm/pkg/binlog"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+)
+
+// defaultListenerQueueSize is the number of events queued for each listener,
+// the events beyond it are replayed from the relay log files.
+const defaultListenerQueueSize = 1024
+
+var errReplayReachEnd = errors.New("reach the end of spilled events")
+
+// relayFilePos is a position in the relay log files.
+type relayFilePos struct {
+	uuid     string // relay sub directory, with suffix
+	filename string
+	pos      uint32
+}
+
+// listenerDispatcher dispatches events to a Listener in a background
+// goroutine, so a slow listener never blocks the relay from writing events.
+// When the queue is full, the dispatcher stops queueing and only records the
+// range of the spilled events, which are replayed from the relay log files
+// after the queued events are consumed.
+type listenerDispatcher struct {
+	listener Listener
+	relayDir string
+	logger   log.Logger
+
+	queue   chan *replication.BinlogEvent
+	spillCh chan struct{}
+
+	mu        sync.Mutex
+	spilling  bool
+	spillFrom relayFilePos // start position of the first spilled event
+	spillTo   relayFilePos // end position of the last spilled event
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newListenerDispatcher(logger log.Logger, relayDir string, listener Listener, queueSize int) *listenerDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &listenerDispatcher{
+		listener: listener,
+		relayDir: relayDir,
+		logger:   logger,
+		queue:    make(chan *replication.BinlogEvent, queueSize),
+		spillCh:  make(chan struct{}, 1),
+		cancel:   cancel,
+	}
+	d.wg.Add(1)
+	go d.run(ctx)
+	return d
+}
+
+// dispatch queues the event which has been written into the relay log file
+// `filename` in sub directory `uuid`, it never blocks.
+func (d *listenerDispatcher) dispatch(e *replication.BinlogEvent, uuid, filename string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	end := relayFilePos{uuid: uuid, filename: filename, pos: e.Header.LogPos}
+	if d.spilling {
+		d.spillTo = end
+		return
+	}
+	select {
+	case d.queue <- e:
+		return
+	default:
+	}
+
+	d.logger.Warn("relay listener falls behind, replay events from relay log files later",
+		zap.String("uuid", uuid), zap.String("file", filename), zap.Uint32("position", e.Header.LogPos))
+	relayListenerSpillCounter.Inc()
+	d.spilling = true
+	d.spillFrom = relayFilePos{uuid: uuid, filename: filename, pos: e.Header.LogPos - e.Header.EventSize}
+	d.spillTo = end
+	select {
+	case d.spillCh <- struct{}{}:
+	default:
+	}
+}
+
+// close stops dispatching, the queued events are discarded.
+func (d *listenerDispatcher) close() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *listenerDispatcher) run(ctx context.Context) {
+	defer d.wg.Done()
+	for {
+		// consume the queued events first, they are before the spilled events.
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-d.queue:
+			d.listener.OnEvent(e)
+			continue
+		default:
+		}
+
+		replayed, err := d.replaySpilled(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				// the listener can't skip the lost events, so it must stop and
+				// report the error, no more events are dispatched to it.
+				d.logger.Error("fail to replay events for relay listener", zap.Error(err))
+				d.listener.OnError(err)
+			}
+			return
+		}
+		if replayed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-d.queue:
+			d.listener.OnEvent(e)
+		case <-d.spillCh:
+		}
+	}
+}
+
+// replaySpilled replays the spilled events from the relay log files until it
+// catches up with the relay, it returns false if there's no spilled event.
+func (d *listenerDispatcher) replaySpilled(ctx context.Context) (bool, error) {
+	d.mu.Lock()
+	if !d.spilling {
+		d.mu.Unlock()
+		return false, nil
+	}
+	from, to := d.spillFrom, d.spillTo
+	d.mu.Unlock()
+
+	for {
+		if err := d.replay(ctx, from, to); err != nil {
+			return false, terror.Annotatef(err, "replay relay log from %s:%d to %s:%d",
+				from.filename, from.pos, to.filename, to.pos)
+		}
+		d.mu.Lock()
+		if d.spillTo == to {
+			// following events will be queued again.
+			d.spilling = false
+			d.mu.Unlock()
+			return true, nil
+		}
+		from, to = to, d.spillTo
+		d.mu.Unlock()
+	}
+}
+
+// replay replays the events in [from, to) from the relay log files.
+func (d *listenerDispatcher) replay(ctx context.Context, from, to relayFilePos) error {
+	pos := from
+	for {
+		last := pos.uuid == to.uuid && pos.filename == to.filename
+		if err := d.replayFile(ctx, pos, last, to.pos); err != nil || last {
+			return err
+		}
+		next, err := d.nextFile(pos)
+		if err != nil {
+			return err
+		}
+		pos = next
+	}
+}
+
+// replayFile replays the events starting from pos in the file, it stops at
+// endPos if it's the last file.
+func (d *listenerDispatcher) replayFile(ctx context.Context, pos relayFilePos, last bool, endPos uint32) error {
+	if last && pos.pos >= endPos {
+		return nil
+	}
+	fullPath := filepath.Join(d.relayDir, pos.uuid, pos.filename)
+	if last {
+		// the relay is still writing the last file, only the events which have
+		// been written completely before endPos can be replayed.
+		fi, err := os.Stat(fullPath)
+		if err != nil {
+			return terror.ErrGetRelayLogStat.Delegate(err, fullPath)
+		}
+		if fi.Size() < int64(endPos) {
+			return terror.ErrRelayLogFileSizeSmaller.Generate(fullPath)
+		}
+	}
+	err := replication.NewBinlogParser().ParseFile(fullPath, int64(pos.pos), func(e *replication.BinlogEvent) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// ParseFile always parses the FormatDescriptionEvent at the beginning of the file
+		if e.Header.LogPos-e.Header.EventSize < pos.pos {
+			return nil
+		}
+		if last && e.Header.LogPos > endPos {
+			return errReplayReachEnd
+		}
+		d.listener.OnEvent(e)
+		if last && e.Header.LogPos == endPos {
+			return errReplayReachEnd
+		}
+		return nil
+	})
+	if errors.Cause(err) == errReplayReachEnd {
+		return nil
+	}
+	return err
+}
+
+// nextFile returns the beginning of the relay log file after pos, which may
+// be in the next relay sub directory.
+func (d *listenerDispatcher) nextFile(pos relayFilePos) (relayFilePos, error) {
+	files, err := CollectBinlogFilesCmp(filepath.Join(d.relayDir, pos.uuid), pos.filename, FileCmpBigger)
+	if err != nil {
+		return pos, err
+	}
+	if len(files) > 0 {
+		return relayFilePos{uuid: pos.uuid, filename: files[0], pos: binlog.FileHeaderLen}, nil
+	}
+
+	uuids, err := utils.ParseUUIDIndex(filepath.Join(d.relayDir, utils.UUIDIndexFilename))
+	if err != nil {
+		return pos, err
+	}
+	nextUUID, _, err := getNextUUID(pos.uuid, uuids)
+	if err != nil {
+		return pos, err
+	}
+	if nextUUID == "" {
+		return pos, errors.Errorf("no relay log file after %s in %s", pos.filename, pos.uuid)
+	}
+	filename, err := getFirstBinlogName(d.relayDir, nextUUID)
+	if err != nil {
+		return pos, err
+	}
+	return relayFilePos{uuid: nextUUID, filename: filename, pos: binlog.FileHeaderLen}, nil
+}
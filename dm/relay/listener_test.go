@@ -0,0 +1,153 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	gmysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	. "github.com/pingcap/check"
+
+	"github.com/pingcap/tiflow/dm/pkg/gtid"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+)
+
+var _ = Suite(&testListenerSuite{})
+
+type testListenerSuite struct{}
+
+type blockingListener struct {
+	release chan struct{}
+
+	mu        sync.Mutex
+	positions []uint32
+	err       error
+}
+
+func (l *blockingListener) OnEvent(e *replication.BinlogEvent) {
+	<-l.release
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.positions = append(l.positions, e.Header.LogPos)
+}
+
+func (l *blockingListener) OnError(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.err = err
+}
+
+func (l *blockingListener) error() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+func (l *blockingListener) received() []uint32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]uint32(nil), l.positions...)
+}
+
+func (t *testListenerSuite) TestDispatchSpillAndReplay(c *C) {
+	var (
+		relayDir = c.MkDir()
+		uuid     = "3ccc475b-2343-11e7-be21-6c0b84d59f30.000001"
+		filename = "mysql-bin.000001"
+		flavor   = gmysql.MySQLFlavor
+	)
+	previousGTIDSet, err := gtid.ParserGTID(flavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:1-14,53bfca22-690d-11e7-8a62-18ded7a37b78:1-495")
+	c.Assert(err, IsNil)
+	latestGTID1, err := gtid.ParserGTID(flavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:14")
+	c.Assert(err, IsNil)
+	latestGTID2, err := gtid.ParserGTID(flavor, "53bfca22-690d-11e7-8a62-18ded7a37b78:495")
+	c.Assert(err, IsNil)
+	_, events, data := genBinlogEventsWithGTIDs(c, flavor, previousGTIDSet, latestGTID1, latestGTID2)
+	c.Assert(os.MkdirAll(filepath.Join(relayDir, uuid), 0o700), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(relayDir, uuid, filename), data, 0o600), IsNil)
+
+	lis := &blockingListener{release: make(chan struct{})}
+	d := newListenerDispatcher(log.L(), relayDir, lis, 1)
+	defer d.close()
+
+	// the first event is blocked in the listener, the second one is queued and
+	// all others are spilled.
+	expected := make([]uint32, 0, len(events))
+	for _, e := range events {
+		d.dispatch(e, uuid, filename)
+		expected = append(expected, e.Header.LogPos)
+	}
+	d.mu.Lock()
+	c.Assert(d.spilling, IsTrue)
+	c.Assert(d.spillTo, Equals, relayFilePos{uuid: uuid, filename: filename, pos: uint32(len(data))})
+	d.mu.Unlock()
+
+	close(lis.release)
+	c.Assert(utils.WaitSomething(50, 100*time.Millisecond, func() bool {
+		return len(lis.received()) == len(expected)
+	}), IsTrue)
+	c.Assert(lis.received(), DeepEquals, expected)
+
+	// the events are queued again after catching up.
+	c.Assert(utils.WaitSomething(50, 100*time.Millisecond, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return !d.spilling
+	}), IsTrue)
+	d.dispatch(events[len(events)-1], uuid, filename)
+	c.Assert(utils.WaitSomething(50, 100*time.Millisecond, func() bool {
+		return len(lis.received()) == len(expected)+1
+	}), IsTrue)
+}
+
+func (t *testListenerSuite) TestReplayUnflushedEvents(c *C) {
+	var (
+		relayDir = c.MkDir()
+		uuid     = "3ccc475b-2343-11e7-be21-6c0b84d59f30.000001"
+		filename = "mysql-bin.000001"
+		flavor   = gmysql.MySQLFlavor
+	)
+	previousGTIDSet, err := gtid.ParserGTID(flavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:1-14,53bfca22-690d-11e7-8a62-18ded7a37b78:1-495")
+	c.Assert(err, IsNil)
+	latestGTID1, err := gtid.ParserGTID(flavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:14")
+	c.Assert(err, IsNil)
+	latestGTID2, err := gtid.ParserGTID(flavor, "53bfca22-690d-11e7-8a62-18ded7a37b78:495")
+	c.Assert(err, IsNil)
+	_, events, data := genBinlogEventsWithGTIDs(c, flavor, previousGTIDSet, latestGTID1, latestGTID2)
+	c.Assert(os.MkdirAll(filepath.Join(relayDir, uuid), 0o700), IsNil)
+	// the last event is not in the file yet.
+	c.Assert(os.WriteFile(filepath.Join(relayDir, uuid, filename), data[:len(data)-1], 0o600), IsNil)
+
+	lis := &blockingListener{release: make(chan struct{})}
+	d := newListenerDispatcher(log.L(), relayDir, lis, 1)
+	defer d.close()
+
+	for _, e := range events {
+		d.dispatch(e, uuid, filename)
+	}
+	close(lis.release)
+
+	// the replay fails instead of losing events silently.
+	c.Assert(utils.WaitSomething(50, 100*time.Millisecond, func() bool {
+		return lis.error() != nil
+	}), IsTrue)
+	c.Assert(terror.ErrRelayLogFileSizeSmaller.Equal(lis.error()), IsTrue)
+	c.Assert(len(lis.received()) < len(events), IsTrue)
+}
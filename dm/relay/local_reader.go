@@ -27,8 +27,10 @@ import (
 	"github.com/go-mysql-org/go-mysql/mysql"
 	"github.com/go-mysql-org/go-mysql/replication"
 	"github.com/pingcap/errors"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
+	"github.com/pingcap/tiflow/dm/dm/config"
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	"github.com/pingcap/tiflow/dm/pkg/binlog/event"
 	"github.com/pingcap/tiflow/dm/pkg/binlog/reader"
@@ -48,6 +50,8 @@ type BinlogReaderConfig struct {
 	RelayDir string
 	Timezone *time.Location
 	Flavor   string
+	// IO config of relay log files, it's filled by the relay which the reader reads from.
+	IO config.RelayIOConfig
 }
 
 // BinlogReader is a binlog reader.
@@ -71,6 +75,10 @@ type BinlogReader struct {
 	// ch with size = 1, we only need to be notified whether binlog file of relay changed, not how many times
 	notifyCh chan interface{}
 	relay    Process
+	// the error of replaying the events the reader falls behind, the reader can't be notified any more
+	listenerErr atomic.Error
+	// readers of relay log files, it's shared with the relay so the page cache of a file is dropped by its last reader
+	pageCache *pageCacheTracker
 
 	currentUUID string // current UUID(with suffix)
 
@@ -468,6 +476,21 @@ func (r *BinlogReader) parseFileAsPossible(ctx context.Context, s *LocalStreamer
 	}
 	defer f.Close()
 
+	// the page cache is dropped when the last reader of the file has finished it.
+	var finished bool
+	r.pageCache.open(fullPath)
+	defer func() {
+		if r.pageCache.close(fullPath) && finished && r.cfg.IO.DropPageCache {
+			if err2 := utils.DropPageCache(f, 0, 0); err2 != nil {
+				r.tctx.L().Warn("fail to drop page cache of relay log file", zap.String("file", fullPath), zap.Error(err2))
+			}
+		}
+	}()
+
+	if err2 := utils.AdviseSequential(f, offset, r.cfg.IO.ReadaheadSize); err2 != nil {
+		r.tctx.L().Warn("fail to advise sequential read of relay log file", zap.String("file", fullPath), zap.Error(err2))
+	}
+
 	state := &binlogFileParseState{
 		possibleLast: possibleLast,
 		fullPath:     fullPath,
@@ -493,6 +516,8 @@ func (r *BinlogReader) parseFileAsPossible(ctx context.Context, s *LocalStreamer
 			r.tctx.L().Debug("continue to re-parse relay log file", zap.String("file", relayLogFile), zap.String("directory", relayLogDir))
 			continue // should continue to parse this file
 		}
+		// the file won't be read by this reader again.
+		finished = needSwitch
 		return needSwitch, state.latestPos, nil
 	}
 }
@@ -726,6 +751,9 @@ func (r *BinlogReader) waitBinlogChanged(ctx context.Context, state *binlogFileP
 		case <-ctx.Done():
 			return false, false, nil
 		case <-r.Notified():
+			if err := r.listenerErr.Load(); err != nil {
+				return false, false, err
+			}
 			active, relayOffset = r.relay.IsActive(r.currentUUID, state.relayLogFile)
 			if active {
 				if relayOffset > state.latestPos {
@@ -842,3 +870,13 @@ func (r *BinlogReader) OnEvent(_ *replication.BinlogEvent) {
 	default:
 	}
 }
+
+// OnError implements Listener.OnError, the error is returned when the reader
+// waits for the relay next time, so the subtask pauses instead of waiting forever.
+func (r *BinlogReader) OnError(err error) {
+	r.listenerErr.Store(err)
+	select {
+	case r.notifyCh <- struct{}{}:
+	default:
+	}
+}
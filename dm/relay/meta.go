@@ -15,16 +15,22 @@ package relay
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"hash/crc32"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/pingcap/tidb/parser"
+	"go.uber.org/zap"
 
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	"github.com/pingcap/tiflow/dm/pkg/gtid"
+	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
 )
@@ -34,6 +40,10 @@ var (
 	minCheckpoint = mysql.Position{Pos: 4}
 )
 
+// metaChecksumPrefix starts the last line of a relay meta file, which records the crc32 checksum
+// of the content before it. It's a TOML comment so the file can still be decoded by older versions.
+const metaChecksumPrefix = "# checksum: "
+
 // Meta represents binlog meta information for sync source
 // when re-syncing, we should reload meta info to guarantee continuous transmission
 // in order to support master-slave switching, Meta should support switching binlog meta info to newer master
@@ -241,6 +251,8 @@ func (lm *LocalMeta) doFlush() error {
 		return terror.ErrRelayFlushLocalMeta.Delegate(err)
 	}
 
+	fmt.Fprintf(&buf, "%s%08x\n", metaChecksumPrefix, crc32.ChecksumIEEE(buf.Bytes()))
+
 	filename := filepath.Join(lm.baseDir, lm.currentUUID, utils.MetaFilename)
 	err = utils.WriteFileAtomic(filename, buf.Bytes(), 0o644)
 	if err != nil {
@@ -461,17 +473,21 @@ func (lm *LocalMeta) loadMetaData() error {
 
 	filename := filepath.Join(lm.baseDir, lm.currentUUID, utils.MetaFilename)
 
-	fd, err := os.Open(filename)
+	data, err := os.ReadFile(filename)
 	if os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return terror.ErrRelayLoadMetaData.Delegate(err)
 	}
-	defer fd.Close()
 
-	_, err = toml.DecodeReader(fd, lm)
-	if err != nil {
-		return terror.ErrRelayLoadMetaData.Delegate(err)
+	content, ok := verifyMetaChecksum(data)
+	if ok {
+		_, err = toml.Decode(string(content), lm)
+	}
+	if !ok || err != nil {
+		log.L().Warn("relay meta file is corrupted, try to repair it from relay log files",
+			zap.String("file", filename), zap.ByteString("content", data), zap.Error(err))
+		return lm.repairMetaData()
 	}
 
 	if len(lm.BinlogGTID) != 0 {
@@ -484,3 +500,56 @@ func (lm *LocalMeta) loadMetaData() error {
 
 	return nil
 }
+
+// verifyMetaChecksum verifies the checksum line of the meta file data and returns the content before it.
+// data without checksum line is written by older versions and treated as valid unless it's empty.
+func verifyMetaChecksum(data []byte) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	idx := bytes.LastIndex(data, []byte(metaChecksumPrefix))
+	if idx < 0 {
+		return data, true
+	}
+	content := data[:idx]
+	sum, err := strconv.ParseUint(string(bytes.TrimSpace(data[idx+len(metaChecksumPrefix):])), 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return content, uint32(sum) == crc32.ChecksumIEEE(content)
+}
+
+// repairMetaData rebuilds the meta data of current UUID by scanning the latest relay log file,
+// it's used when the meta file was torn, e.g. by a power loss.
+// the trailing incomplete transaction in relay log file will be handled by later recovering of relay.
+func (lm *LocalMeta) repairMetaData() error {
+	lm.BinLogName = minCheckpoint.Name
+	lm.BinLogPos = minCheckpoint.Pos
+	lm.BinlogGTID = ""
+	lm.gset = lm.emptyGSet.Clone()
+
+	dir := filepath.Join(lm.baseDir, lm.currentUUID)
+	files, err := CollectAllBinlogFiles(dir)
+	if err != nil {
+		return terror.ErrRelayLoadMetaData.Delegate(err)
+	}
+	if len(files) > 0 {
+		latest := files[len(files)-1]
+		pos, gset, err2 := getTxnPosGTIDs(context.Background(), filepath.Join(dir, latest), parser.New())
+		if err2 != nil {
+			return terror.Annotatef(err2, "repair relay meta from %s", latest)
+		}
+		lm.BinLogName = latest
+		if pos > int64(minCheckpoint.Pos) {
+			lm.BinLogPos = uint32(pos)
+		}
+		if gset != nil {
+			lm.gset = gset
+			lm.BinlogGTID = gset.String()
+		}
+	}
+
+	log.L().Warn("relay meta repaired", zap.String("dir", dir), zap.String("binlog name", lm.BinLogName),
+		zap.Uint32("binlog pos", lm.BinLogPos), zap.String("binlog gtid", lm.BinlogGTID))
+	return lm.doFlush()
+}
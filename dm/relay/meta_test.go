@@ -23,6 +23,7 @@ import (
 	. "github.com/pingcap/check"
 
 	"github.com/pingcap/tiflow/dm/pkg/gtid"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
 )
 
 var _ = Suite(&testMetaSuite{})
@@ -297,3 +298,61 @@ func (r *testMetaSuite) TestLocalMetaPotentialDataRace(c *C) {
 	c.Assert(ch2Err, IsNil)
 	c.Logf("GTID string from the go routine: %s", gtidString)
 }
+
+func (r *testMetaSuite) TestLocalMetaRepairTornMeta(c *C) {
+	dir := c.MkDir()
+	gset1, _ := gtid.ParserGTID("mysql", "85ab69d1-b21f-11e6-9c5e-64006a8978d2:1-12")
+
+	lm := NewLocalMeta("mysql", dir)
+	c.Assert(lm.Load(), IsNil)
+	c.Assert(lm.AddDir("server-a-uuid", &mysql.Position{Name: "mysql-bin.000001", Pos: 1234}, gset1, 0), IsNil)
+	uuid := lm.UUID()
+	metaFile := path.Join(dir, uuid, utils.MetaFilename)
+
+	// the flushed meta carries a valid checksum and can be loaded again.
+	data, err := os.ReadFile(metaFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Matches, "(?s).*\n"+metaChecksumPrefix+"[0-9a-f]{8}\n")
+	lm = NewLocalMeta("mysql", dir)
+	c.Assert(lm.Load(), IsNil)
+	_, pos := lm.Pos()
+	c.Assert(pos, DeepEquals, mysql.Position{Name: "mysql-bin.000001", Pos: 1234})
+
+	// meta written by older versions has no checksum line.
+	idx := strings.LastIndex(string(data), metaChecksumPrefix)
+	c.Assert(os.WriteFile(metaFile, data[:idx], 0o644), IsNil)
+	lm = NewLocalMeta("mysql", dir)
+	c.Assert(lm.Load(), IsNil)
+	_, pos = lm.Pos()
+	c.Assert(pos, DeepEquals, mysql.Position{Name: "mysql-bin.000001", Pos: 1234})
+
+	// torn meta without relay log files falls back to the min checkpoint.
+	c.Assert(os.WriteFile(metaFile, data[:idx+len(metaChecksumPrefix)+3], 0o644), IsNil)
+	lm = NewLocalMeta("mysql", dir)
+	c.Assert(lm.Load(), IsNil)
+	_, pos = lm.Pos()
+	c.Assert(pos, DeepEquals, minCheckpoint)
+	_, gs := lm.GTID()
+	c.Assert(gs.String(), Equals, "")
+
+	// torn meta is repaired from the tail of the latest relay log file.
+	previousGTIDSet, _ := gtid.ParserGTID(mysql.MySQLFlavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:1-14,53bfca22-690d-11e7-8a62-18ded7a37b78:1-495")
+	latestGTID1, _ := gtid.ParserGTID(mysql.MySQLFlavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:14")
+	latestGTID2, _ := gtid.ParserGTID(mysql.MySQLFlavor, "53bfca22-690d-11e7-8a62-18ded7a37b78:495")
+	_, _, baseData := genBinlogEventsWithGTIDs(c, mysql.MySQLFlavor, previousGTIDSet, latestGTID1, latestGTID2)
+	c.Assert(os.WriteFile(path.Join(dir, uuid, "mysql-bin.000002"), baseData, 0o644), IsNil)
+	c.Assert(os.WriteFile(metaFile, []byte("binlog-name = \"mysql-bin.0000"), 0o644), IsNil)
+	lm = NewLocalMeta("mysql", dir)
+	c.Assert(lm.Load(), IsNil)
+	_, pos = lm.Pos()
+	c.Assert(pos, DeepEquals, mysql.Position{Name: "mysql-bin.000002", Pos: uint32(len(baseData))})
+	expectedGTIDs, _ := gtid.ParserGTID(mysql.MySQLFlavor, "3ccc475b-2343-11e7-be21-6c0b84d59f30:1-17,53bfca22-690d-11e7-8a62-18ded7a37b78:1-505")
+	_, gs = lm.GTID()
+	c.Assert(gs, DeepEquals, expectedGTIDs)
+
+	// the repaired meta is flushed.
+	data, err = os.ReadFile(metaFile)
+	c.Assert(err, IsNil)
+	_, ok := verifyMetaChecksum(data)
+	c.Assert(ok, IsTrue)
+}
@@ -133,6 +133,14 @@ var (
 			Name:      "exit_with_error_count",
 			Help:      "counter of relay unit exits with error",
 		})
+
+	relayListenerSpillCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "dm",
+			Subsystem: "relay",
+			Name:      "listener_spill_count",
+			Help:      "counter of relay listeners falling behind and replaying events from relay log files",
+		})
 )
 
 // RegisterMetrics register metrics.
@@ -149,6 +157,7 @@ func RegisterMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(binlogReadDurationHistogram)
 	registry.MustRegister(binlogTransformDurationHistogram)
 	registry.MustRegister(relayExitWithErrorCounter)
+	registry.MustRegister(relayListenerSpillCounter)
 }
 
 func reportRelayLogSpaceInBackground(ctx context.Context, dirpath string) error {
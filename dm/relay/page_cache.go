@@ -0,0 +1,69 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// pageCacheTracker tracks the readers of every relay log file, so the page cache
+// of a file is only dropped after the last reader of it is closed, rather than
+// when any of the subtasks reading it has finished.
+// a nil *pageCacheTracker means the file has no other readers.
+type pageCacheTracker struct {
+	mu      sync.Mutex
+	readers map[string]int
+}
+
+func newPageCacheTracker() *pageCacheTracker {
+	return &pageCacheTracker{readers: make(map[string]int)}
+}
+
+// open registers a reader of the relay log file.
+func (t *pageCacheTracker) open(path string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.readers[filepath.Clean(path)]++
+}
+
+// close unregisters a reader of the relay log file, and returns whether it's the
+// last reader, only then the page cache of the file can be dropped.
+func (t *pageCacheTracker) close(path string) bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path = filepath.Clean(path)
+	if t.readers[path] <= 1 {
+		delete(t.readers, path)
+		return true
+	}
+	t.readers[path]--
+	return false
+}
+
+// hasReaders returns whether the relay log file is being read.
+func (t *pageCacheTracker) hasReaders(path string) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.readers[filepath.Clean(path)] > 0
+}
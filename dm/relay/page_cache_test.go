@@ -0,0 +1,49 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testPageCacheSuite{})
+
+type testPageCacheSuite struct{}
+
+func (t *testPageCacheSuite) TestPageCacheTracker(c *C) {
+	var nilTracker *pageCacheTracker
+	nilTracker.open("/relay/uuid.000001/mysql-bin.000001")
+	c.Assert(nilTracker.hasReaders("/relay/uuid.000001/mysql-bin.000001"), IsFalse)
+	c.Assert(nilTracker.close("/relay/uuid.000001/mysql-bin.000001"), IsTrue)
+
+	tracker := newPageCacheTracker()
+	file1 := "/relay/uuid.000001/mysql-bin.000001"
+	file2 := "/relay/uuid.000001/mysql-bin.000002"
+	c.Assert(tracker.hasReaders(file1), IsFalse)
+
+	// two subtasks read file1, and one of them reads file2.
+	tracker.open(file1)
+	tracker.open("/relay/uuid.000001/../uuid.000001/mysql-bin.000001")
+	tracker.open(file2)
+	c.Assert(tracker.hasReaders(file1), IsTrue)
+	c.Assert(tracker.hasReaders(file2), IsTrue)
+
+	// the page cache can only be dropped by the last reader.
+	c.Assert(tracker.close(file1), IsFalse)
+	c.Assert(tracker.hasReaders(file1), IsTrue)
+	c.Assert(tracker.close(file1), IsTrue)
+	c.Assert(tracker.hasReaders(file1), IsFalse)
+	c.Assert(tracker.close(file2), IsTrue)
+	c.Assert(tracker.readers, HasLen, 0)
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pingcap/tiflow/dm/dm/pb"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/streamer"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+)
+
+// PurgeReader represents a reader of relay log files, like a sub task or the relay unit.
+type PurgeReader struct {
+	// Name is the task name of the reader, or `relay` for the relay unit.
+	Name string `json:"name"`
+	// RelayLog is the earliest relay log file still used by the reader.
+	RelayLog string `json:"relayLog"`
+	// HoldsPurge indicates that the reader still references relay log files
+	// requested to be purged, so they are kept.
+	HoldsPurge bool `json:"holdsPurge"`
+}
+
+// PurgePreview represents the impact of a purge process, it's returned by a dry-run purge.
+type PurgePreview struct {
+	Strategy string `json:"strategy"`
+	// Forbidden is the reason why the purge is forbidden currently, empty if it's allowed.
+	Forbidden string `json:"forbidden,omitempty"`
+	// SafeRelayLog is the relay log file before which relay log files would be purged.
+	SafeRelayLog string `json:"safeRelayLog"`
+	// Files are relay log files would be removed.
+	Files []string `json:"files"`
+	// Dirs are relay sub directories would be removed with all files in them.
+	Dirs []string `json:"dirs"`
+	// ReclaimedBytes is the total size of files would be removed.
+	ReclaimedBytes int64 `json:"reclaimedBytes"`
+	// Readers are all readers of relay log files.
+	Readers []PurgeReader `json:"readers"`
+}
+
+// String implements Stringer.String.
+func (p *PurgePreview) String() string {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Sprintf("%+v", *p)
+	}
+	return string(data)
+}
+
+// activeRelayLogsLister is an Operator which can list active relay logs of all readers.
+type activeRelayLogsLister interface {
+	ActiveRelayLogs() []streamer.RelayLogInfo
+}
+
+// Preview returns the impact of the purge process without removing any file.
+func (p *relayPurger) Preview(ctx context.Context, req *pb.PurgeRelayRequest) (*PurgePreview, error) {
+	ps, args, err := p.parseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &PurgePreview{Strategy: ps.Type().String()}
+	for _, inter := range p.interceptors {
+		forbidden, msg := inter.ForbidPurge()
+		if forbidden {
+			preview.Forbidden = msg
+			break
+		}
+	}
+
+	earliest := p.earliestActiveRelayLog()
+	if earliest == nil {
+		return nil, terror.ErrRelayNoActiveRelayLog.Generate()
+	}
+	args.SetActiveRelayLog(earliest)
+
+	safeRelayLog, files, err := relayFilesToPurge(p.logger, args)
+	if err != nil {
+		return nil, err
+	}
+	preview.SafeRelayLog = safeRelayLog.String()
+	preview.Files = make([]string, 0)
+	preview.Dirs = make([]string, 0)
+	for _, subRelay := range files {
+		preview.Files = append(preview.Files, subRelay.files...)
+		if subRelay.hasAll {
+			preview.Dirs = append(preview.Dirs, subRelay.dir)
+		}
+		size, err := subRelay.size()
+		if err != nil {
+			return nil, err
+		}
+		preview.ReclaimedBytes += size
+	}
+
+	// readers earlier than the requested relay log hold the purge, the
+	// earliest one holds it if no relay log file is requested.
+	fa, isFilename := args.(*filenameArgs)
+	for _, info := range p.activeRelayLogs() {
+		info := info
+		var holds bool
+		if isFilename {
+			holds = info.Earlier(fa.requestedRelayLog)
+		} else {
+			holds = !safeRelayLog.Earlier(&info)
+		}
+		preview.Readers = append(preview.Readers, PurgeReader{
+			Name:       info.TaskName,
+			RelayLog:   info.String(),
+			HoldsPurge: holds,
+		})
+	}
+	return preview, nil
+}
+
+// activeRelayLogs returns active relay logs of all readers.
+func (p *relayPurger) activeRelayLogs() []streamer.RelayLogInfo {
+	infos := make([]streamer.RelayLogInfo, 0, len(p.operators))
+	for _, op := range p.operators {
+		if lister, ok := op.(activeRelayLogsLister); ok {
+			infos = append(infos, lister.ActiveRelayLogs()...)
+		} else if info := op.EarliestActiveRelayLog(); info != nil {
+			infos = append(infos, *info)
+		}
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].TaskName < infos[j].TaskName
+	})
+	return infos
+}
+
+// relayFilesToPurge returns the safe relay log and relay log files would be purged with the args.
+func relayFilesToPurge(logger log.Logger, args StrategyArgs) (*streamer.RelayLogInfo, []*subRelayFiles, error) {
+	var (
+		safeRelayLog *streamer.RelayLogInfo
+		files        []*subRelayFiles
+		err          error
+	)
+	switch a := args.(type) {
+	case *filenameArgs:
+		safeRelayLog = a.safeRelayLog
+		files, err = getRelayFilesBeforeFile(logger, a.relayBaseDir, a.uuids, safeRelayLog)
+	case *inactiveArgs:
+		safeRelayLog = a.activeRelayLog
+		files, err = getRelayFilesBeforeFile(logger, a.relayBaseDir, a.uuids, safeRelayLog)
+	case *spaceArgs:
+		safeRelayLog = a.activeRelayLog
+		files, err = getRelayFilesBeforeFile(logger, a.relayBaseDir, a.uuids, safeRelayLog)
+	case *timeArgs:
+		safeRelayLog = a.activeRelayLog
+		files, err = getRelayFilesBeforeFileAndTime(logger, a.relayBaseDir, a.uuids, safeRelayLog, a.safeTime)
+	default:
+		return nil, nil, terror.ErrRelayPurgeArgsNotValid.Generate(args, args)
+	}
+	if err != nil {
+		return nil, nil, terror.Annotatef(err, "get relay files to purge with args %+v", args)
+	}
+	return safeRelayLog, files, nil
+}
+
+// size returns the total size of files would be removed when purging subRelay.
+func (s *subRelayFiles) size() (int64, error) {
+	var total int64
+	if s.hasAll {
+		// the whole directory would be removed
+		err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				total += info.Size()
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, terror.ErrGetRelayLogStat.Delegate(err, s.dir)
+		}
+		return total, nil
+	}
+	for _, f := range s.files {
+		fs, err := os.Stat(f)
+		if err != nil {
+			return 0, terror.ErrGetRelayLogStat.Delegate(err, f)
+		}
+		total += fs.Size()
+	}
+	return total, nil
+}
@@ -88,6 +88,8 @@ type filenameArgs struct {
 	subDir       string // sub dir for @filename, empty indicates latest sub dir
 	uuids        []string
 	safeRelayLog *streamer.RelayLogInfo // all relay log files prior to this should be purged
+	// the relay log file specified by @filename and @subDir, safeRelayLog may be earlier than it
+	requestedRelayLog *streamer.RelayLogInfo
 }
 
 func (fa *filenameArgs) SetActiveRelayLog(active *streamer.RelayLogInfo) {
@@ -104,6 +106,7 @@ func (fa *filenameArgs) SetActiveRelayLog(active *streamer.RelayLogInfo) {
 		UUIDSuffix: endSuffix,
 		Filename:   fa.filename,
 	}
+	fa.requestedRelayLog = safeRelayLog
 
 	if active.Earlier(safeRelayLog) {
 		safeRelayLog = active
@@ -58,6 +58,8 @@ type Purger interface {
 	Purging() bool
 	// Do does the purge process one time
 	Do(ctx context.Context, req *pb.PurgeRelayRequest) error
+	// Preview returns the impact of the purge process without removing any file
+	Preview(ctx context.Context, req *pb.PurgeRelayRequest) (*PurgePreview, error)
 }
 
 // NewPurger creates a new purger.
@@ -165,9 +167,22 @@ func (p *relayPurger) Purging() bool {
 
 // Do does the purge process one time.
 func (p *relayPurger) Do(ctx context.Context, req *pb.PurgeRelayRequest) error {
+	if req.DryRun {
+		_, err := p.Preview(ctx, req)
+		return err
+	}
+	ps, args, err := p.parseRequest(req)
+	if err != nil {
+		return err
+	}
+	return p.doPurge(ps, args)
+}
+
+// parseRequest returns the strategy and its args for the purge request.
+func (p *relayPurger) parseRequest(req *pb.PurgeRelayRequest) (PurgeStrategy, StrategyArgs, error) {
 	uuids, err := utils.ParseUUIDIndex(p.indexPath)
 	if err != nil {
-		return terror.Annotatef(err, "parse UUID index file %s", p.indexPath)
+		return nil, nil, terror.Annotatef(err, "parse UUID index file %s", p.indexPath)
 	}
 
 	switch {
@@ -177,7 +192,7 @@ func (p *relayPurger) Do(ctx context.Context, req *pb.PurgeRelayRequest) error {
 			relayBaseDir: p.baseRelayDir,
 			uuids:        uuids,
 		}
-		return p.doPurge(ps, args)
+		return ps, args, nil
 	case req.Time > 0:
 		ps := p.strategies[strategyTime]
 		args := &timeArgs{
@@ -185,7 +200,7 @@ func (p *relayPurger) Do(ctx context.Context, req *pb.PurgeRelayRequest) error {
 			safeTime:     time.Unix(req.Time, 0),
 			uuids:        uuids,
 		}
-		return p.doPurge(ps, args)
+		return ps, args, nil
 	case len(req.Filename) > 0:
 		ps := p.strategies[strategyFilename]
 		args := &filenameArgs{
@@ -194,9 +209,9 @@ func (p *relayPurger) Do(ctx context.Context, req *pb.PurgeRelayRequest) error {
 			subDir:       req.SubDir,
 			uuids:        uuids,
 		}
-		return p.doPurge(ps, args)
+		return ps, args, nil
 	default:
-		return terror.ErrRelayPurgeRequestNotValid.Generate(req)
+		return nil, nil, terror.ErrRelayPurgeRequestNotValid.Generate(req)
 	}
 }
 
@@ -328,3 +343,8 @@ func (d *dummyPurger) Purging() bool {
 func (d *dummyPurger) Do(ctx context.Context, req *pb.PurgeRelayRequest) error {
 	return nil
 }
+
+// Preview implements interface of Purger.
+func (d *dummyPurger) Preview(ctx context.Context, req *pb.PurgeRelayRequest) (*PurgePreview, error) {
+	return &PurgePreview{}, nil
+}
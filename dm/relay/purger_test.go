@@ -179,6 +179,59 @@ func (t *testPurgerSuite) TestPurgeManuallyFilename(c *C) {
 	}
 }
 
+func (t *testPurgerSuite) TestPurgeDryRun(c *C) {
+	// create relay log dir
+	baseDir := c.MkDir()
+
+	// prepare files and directories
+	relayDirsPath, relayFilesPath, _ := t.genRelayLogFiles(c, baseDir, -1, -1)
+	c.Assert(t.genUUIDIndexFile(baseDir), IsNil)
+
+	cfg := config.PurgeConfig{
+		Interval: 0, // disable automatically
+	}
+	interceptor := newFakeInterceptor()
+	purger := NewPurger(cfg, baseDir, []Operator{t}, []PurgeInterceptor{interceptor})
+
+	// request to purge files which are still used by the active relay log
+	req := &pb.PurgeRelayRequest{
+		Filename: t.relayFiles[2][1],
+		SubDir:   t.uuids[2],
+		DryRun:   true,
+	}
+	preview, err := purger.Preview(context.Background(), req)
+	c.Assert(err, IsNil)
+	c.Assert(preview.Strategy, Equals, strategyFilename.String())
+	c.Assert(preview.Forbidden, Equals, interceptor.msg)
+	c.Assert(preview.SafeRelayLog, Equals, t.activeRelayLog.String())
+	c.Assert(preview.Dirs, DeepEquals, []string{relayDirsPath[0]})
+	c.Assert(preview.Files, DeepEquals, append(append([]string{}, relayFilesPath[0]...), relayFilesPath[1][:2]...))
+	c.Assert(preview.ReclaimedBytes, Equals, int64(5*len("meaningless file content")))
+	c.Assert(preview.Readers, DeepEquals, []PurgeReader{{
+		Name:       t.activeRelayLog.TaskName,
+		RelayLog:   t.activeRelayLog.String(),
+		HoldsPurge: true,
+	}})
+
+	// nothing is removed
+	c.Assert(purger.Do(context.Background(), req), IsNil)
+	for _, fps := range relayFilesPath {
+		for _, fp := range fps {
+			c.Assert(utils.IsFileExists(fp), IsTrue)
+		}
+	}
+
+	// the active relay log doesn't hold the purge before an earlier file
+	req.Filename = t.relayFiles[0][1]
+	req.SubDir = t.uuids[0]
+	preview, err = purger.Preview(context.Background(), req)
+	c.Assert(err, IsNil)
+	c.Assert(preview.Dirs, HasLen, 0)
+	c.Assert(preview.Files, DeepEquals, relayFilesPath[0][:1])
+	c.Assert(preview.Readers, HasLen, 1)
+	c.Assert(preview.Readers[0].HoldsPurge, IsFalse)
+}
+
 func (t *testPurgerSuite) TestPurgeAutomaticallyTime(c *C) {
 	// create relay log dir
 	baseDir, err := os.MkdirTemp("", "test_purge_automatically_time")
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	"github.com/pingcap/tiflow/dm/pkg/binlog/common"
 	binlogReader "github.com/pingcap/tiflow/dm/pkg/binlog/reader"
+	"github.com/pingcap/tiflow/dm/pkg/binlog/stats"
 	"github.com/pingcap/tiflow/dm/pkg/conn"
 	"github.com/pingcap/tiflow/dm/pkg/gtid"
 	"github.com/pingcap/tiflow/dm/pkg/log"
@@ -45,6 +46,7 @@ import (
 	pkgstreamer "github.com/pingcap/tiflow/dm/pkg/streamer"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/logutil"
 )
 
 // used to fill RelayLogInfo.
@@ -68,7 +70,12 @@ var _ Process = &Relay{}
 // Listener defines a binlog event listener of relay log.
 type Listener interface {
 	// OnEvent get called when relay processed an event successfully.
+	// It's called in a separate goroutine for each listener, so a slow listener never blocks the relay.
+	// The events which the listener falls behind are replayed from the relay log files.
 	OnEvent(e *replication.BinlogEvent)
+	// OnError get called when the events the listener falls behind can't be replayed,
+	// no more events are dispatched to the listener after it.
+	OnError(err error)
 }
 
 // Process defines mysql-like relay log process unit.
@@ -109,6 +116,8 @@ type Process interface {
 	NewReader(logger log.Logger, cfg *BinlogReaderConfig) *BinlogReader
 	// IsActive check whether given uuid+filename is active binlog file, if true return current file offset
 	IsActive(uuid, filename string) (bool, int64)
+	// ImportBinlogBackup seeds the relay log with the binlog files of a backup set
+	ImportBinlogBackup(backupDir string) ([]string, error)
 }
 
 // Relay relays mysql binlog to local file.
@@ -129,7 +138,13 @@ type Relay struct {
 	}
 
 	writer    Writer
-	listeners map[Listener]struct{} // make it a set to make it easier to remove listener
+	listeners map[Listener]*listenerDispatcher
+	// readers of relay log files, shared by the writer and readers of this relay
+	pageCache *pageCacheTracker
+	// binlogStats records the binlog events into the binlog statistics of the source.
+	binlogStats *stats.Recorder
+	// importMu serializes the imports of binlog backups.
+	importMu sync.Mutex
 }
 
 // NewRealRelay creates an instance of Relay.
@@ -137,16 +152,19 @@ func NewRealRelay(cfg *Config) Process {
 	r := &Relay{
 		cfg:       cfg,
 		meta:      NewLocalMeta(cfg.Flavor, cfg.RelayDir),
-		logger:    log.With(zap.String("component", "relay log")),
-		listeners: make(map[Listener]struct{}),
+		logger:    log.With(zap.String("component", "relay log")).WithComponent(logutil.ComponentRelay),
+		listeners: make(map[Listener]*listenerDispatcher),
+		pageCache: newPageCacheTracker(),
 	}
-	r.writer = NewFileWriter(r.logger, cfg.RelayDir)
+	r.writer = newFileWriter(r.logger, cfg.RelayDir, cfg.IO, r.pageCache)
 	return r
 }
 
 // Init implements the dm.Unit interface.
 // NOTE when Init encounters an error, it will make DM-worker exit when it boots up and assigned relay.
 func (r *Relay) Init(ctx context.Context) (err error) {
+	// the events are not parsed without GTID, see setSyncConfig.
+	r.binlogStats = stats.RegisterRelay(r.cfg.SourceID, !r.cfg.EnableGTID)
 	return reportRelayLogSpaceInBackground(ctx, r.cfg.RelayDir)
 }
 
@@ -186,6 +204,7 @@ func (r *Relay) process(ctx context.Context) error {
 		return terror.WithScope(err, terror.ScopeUpstream)
 	}
 	r.db = db
+	r.checkBinlogRowImage(ctx)
 
 	if err2 := os.MkdirAll(r.cfg.RelayDir, 0o700); err2 != nil {
 		return terror.ErrRelayMkdir.Delegate(err2)
@@ -329,6 +348,23 @@ func (r *Relay) process(ctx context.Context) error {
 	}
 }
 
+// checkBinlogRowImage warns if the upstream doesn't log FULL row images. The
+// relay log stores the binlog events as they are, but the sync units reading
+// them fail if the row images are MINIMAL.
+func (r *Relay) checkBinlogRowImage(ctx context.Context) {
+	ctx2, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+	image, err := utils.GetBinlogRowImage(ctx2, r.db.DB)
+	if err != nil {
+		r.logger.Warn("failed to get binlog_row_image of upstream", zap.Error(err))
+		return
+	}
+	if image != utils.BinlogRowImageFull {
+		r.logger.Warn("upstream binlog_row_image is not FULL, the tasks replicating the relay log require FULL row images",
+			zap.String("binlog_row_image", image))
+	}
+}
+
 // PurgeRelayDir implements the dm.Unit interface.
 func (r *Relay) PurgeRelayDir() error {
 	dir := r.cfg.RelayDir
@@ -618,6 +654,8 @@ func (r *Relay) handleEvents(
 		transformTimer := time.Now()
 		tResult := r.preprocessEvent(e, parser2)
 		binlogTransformDurationHistogram.Observe(time.Since(transformTimer).Seconds())
+		// a RotateEvent is written into the file before rotating
+		writtenFile := lastPos.Name
 		if len(tResult.NextLogName) > 0 && tResult.NextLogName > lastPos.Name {
 			lastPos = mysql.Position{
 				Name: tResult.NextLogName,
@@ -674,7 +712,8 @@ func (r *Relay) handleEvents(
 			continue
 		}
 
-		r.notify(e)
+		r.notify(e, writtenFile)
+		r.binlogStats.Observe(e)
 
 		relayLogWriteDurationHistogram.Observe(time.Since(writeTimer).Seconds())
 		r.tryUpdateActiveRelayLog(e, lastPos.Name) // wrote a event, try update the current active relay log.
@@ -864,7 +903,8 @@ func (r *Relay) doIntervalOps(ctx context.Context) {
 				return
 			}
 			ctx2, cancel2 := context.WithTimeout(ctx, utils.DefaultDBTimeout)
-			pos, _, err := utils.GetPosAndGs(ctx2, r.db.DB, r.cfg.Flavor)
+			pos, _, err := utils.GetUpstreamMetaFetcher(r.cfg.From.Host, r.cfg.From.Port).
+				GetPosAndGs(ctx2, r.db.DB, r.cfg.Flavor)
 			cancel2()
 			if err != nil {
 				r.logger.Warn("get master status", zap.Error(err))
@@ -987,6 +1027,9 @@ func (r *Relay) Close() {
 	r.stopSync()
 
 	r.closeDB()
+	if r.binlogStats != nil {
+		stats.UnregisterRelay(r.cfg.SourceID)
+	}
 
 	r.closed.Store(true)
 	r.logger.Info("relay unit closed")
@@ -1011,6 +1054,7 @@ func (r *Relay) Status(sourceStatus *binlog.SourceStatus) interface{} {
 	}
 
 	if sourceStatus != nil {
+		rs.BinlogRowImage = sourceStatus.BinlogRowImage
 		masterPos, masterGTID := sourceStatus.Location.Position, sourceStatus.Location.GetGTID()
 		rs.MasterBinlog = masterPos.String()
 		if masterGTID != nil { // masterGTID maybe a nil interface
@@ -1184,28 +1228,43 @@ func (r *Relay) adjustGTID(ctx context.Context, gset gtid.Set) (gtid.Set, error)
 	return utils.AddGSetWithPurged(ctx, resultGs, dbConn)
 }
 
-func (r *Relay) notify(e *replication.BinlogEvent) {
+// notify dispatches the event written into the relay log file `filename` to
+// all listeners, it never blocks on listeners.
+func (r *Relay) notify(e *replication.BinlogEvent, filename string) {
 	r.RLock()
 	defer r.RUnlock()
-	for el := range r.listeners {
-		el.OnEvent(e)
+	uuid := r.meta.UUID()
+	for _, d := range r.listeners {
+		d.dispatch(e, uuid, filename)
 	}
 }
 
 func (r *Relay) NewReader(logger log.Logger, cfg *BinlogReaderConfig) *BinlogReader {
-	return newBinlogReader(logger, cfg, r)
+	readerCfg := *cfg
+	readerCfg.IO = r.cfg.IO
+	reader := newBinlogReader(logger, &readerCfg, r)
+	reader.pageCache = r.pageCache
+	return reader
 }
 
 // RegisterListener implements Process.RegisterListener.
 func (r *Relay) RegisterListener(el Listener) {
 	r.Lock()
 	defer r.Unlock()
-	r.listeners[el] = struct{}{}
+	if _, ok := r.listeners[el]; ok {
+		return
+	}
+	r.listeners[el] = newListenerDispatcher(r.logger, r.cfg.RelayDir, el, defaultListenerQueueSize)
 }
 
 // UnRegisterListener implements Process.UnRegisterListener.
 func (r *Relay) UnRegisterListener(el Listener) {
 	r.Lock()
-	defer r.Unlock()
+	d, ok := r.listeners[el]
 	delete(r.listeners, el)
+	r.Unlock()
+	// close outside the lock, the listener may be calling the relay in OnEvent
+	if ok {
+		d.close()
+	}
 }
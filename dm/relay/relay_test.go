@@ -310,28 +310,39 @@ func (t *testRelaySuite) TestTryRecoverMeta(c *C) {
 	c.Assert(latestGTIDs.Equal(recoverGTIDSet), IsTrue)
 }
 
-type dummyListener bool
+type dummyListener chan *replication.BinlogEvent
 
-func (d *dummyListener) OnEvent(e *replication.BinlogEvent) {
-	*d = true
+func (d dummyListener) OnEvent(e *replication.BinlogEvent) {
+	d <- e
 }
 
+func (d dummyListener) OnError(err error) {}
+
 func (t *testRelaySuite) TestListener(c *C) {
 	relay := NewRelay(&Config{}).(*Relay)
 	c.Assert(len(relay.listeners), Equals, 0)
 
-	lis := dummyListener(false)
-	relay.RegisterListener(&lis)
+	lis := make(dummyListener, 1)
+	relay.RegisterListener(lis)
 	c.Assert(len(relay.listeners), Equals, 1)
 
-	relay.notify(nil)
-	c.Assert(bool(lis), Equals, true)
+	e := &replication.BinlogEvent{Header: &replication.EventHeader{LogPos: 100, EventSize: 10}}
+	relay.notify(e, "mysql-bin.000001")
+	select {
+	case received := <-lis:
+		c.Assert(received, Equals, e)
+	case <-time.After(5 * time.Second):
+		c.Fatal("listener is not notified")
+	}
 
-	relay.UnRegisterListener(&lis)
+	relay.UnRegisterListener(lis)
 	c.Assert(len(relay.listeners), Equals, 0)
-	lis = false
-	relay.notify(nil)
-	c.Assert(bool(lis), Equals, false)
+	relay.notify(e, "mysql-bin.000001")
+	select {
+	case <-lis:
+		c.Fatal("unregistered listener is notified")
+	case <-time.After(100 * time.Millisecond):
+	}
 }
 
 // genBinlogEventsWithGTIDs generates some binlog events used by testFileUtilSuite and testFileWriterSuite.
@@ -23,6 +23,7 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
+	"github.com/pingcap/tiflow/dm/dm/config"
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	"github.com/pingcap/tiflow/dm/pkg/binlog/event"
 	"github.com/pingcap/tiflow/dm/pkg/log"
@@ -76,11 +77,19 @@ type FileWriter struct {
 
 // NewFileWriter creates a FileWriter instances.
 func NewFileWriter(logger log.Logger, relayDir string) Writer {
+	return newFileWriter(logger, relayDir, config.RelayIOConfig{}, nil)
+}
+
+// newFileWriter creates a FileWriter instances with the IO config of relay log files,
+// and the tracker of the readers of them.
+func newFileWriter(logger log.Logger, relayDir string, ioCfg config.RelayIOConfig, pageCache *pageCacheTracker) *FileWriter {
 	w := &FileWriter{
 		relayDir: relayDir,
 		logger:   logger.WithFields(zap.String("sub component", "relay writer")),
 	}
 	w.out = NewBinlogWriter(w.logger, relayDir)
+	w.out.dropPageCache = ioCfg.DropPageCache
+	w.out.pageCache = pageCache
 	return w
 }
 
@@ -20,6 +20,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/pingcap/tidb/sessionctx"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/syncer/metrics"
@@ -38,6 +39,10 @@ type causality struct {
 	sessCtx     sessionctx.Context
 	workerCount int
 
+	// relaxedOrder matches the tables whose rows only keep the changes of the
+	// same row in order, the conflicts across rows are not detected.
+	relaxedOrder tfilter.Filter
+
 	// for metrics
 	task   string
 	source string
@@ -54,6 +59,8 @@ func causalityWrap(inCh chan *job, syncer *Syncer) chan *job {
 		outCh:       make(chan *job, syncer.cfg.QueueSize),
 		sessCtx:     syncer.sessCtx,
 		workerCount: syncer.cfg.WorkerCount,
+
+		relaxedOrder: syncer.relaxedOrderFilter,
 	}
 
 	go func() {
@@ -81,6 +88,11 @@ func (c *causality) run() {
 			continue
 		default:
 			keys := j.dml.CausalityKeys()
+			if c.isRelaxedOrder(j) {
+				// rows of tables without cross-row constraints can be applied in any order,
+				// only the changes of the same row are kept in order by its handle keys.
+				keys = j.dml.HandleKeys()
+			}
 
 			// detectConflict before add
 			if c.detectConflict(keys) {
@@ -97,6 +109,15 @@ func (c *causality) run() {
 	}
 }
 
+// isRelaxedOrder returns whether the rows of the job's source table can be applied out of order across rows.
+func (c *causality) isRelaxedOrder(j *job) bool {
+	if c.relaxedOrder == nil || j.dml == nil {
+		return false
+	}
+	table := j.dml.GetSourceTable()
+	return c.relaxedOrder.MatchTable(table.Schema, table.Table)
+}
+
 // close closes outer channel.
 func (c *causality) close() {
 	close(c.outCh)
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	tcontext "github.com/pingcap/tiflow/dm/pkg/context"
 	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
 )
@@ -124,6 +125,74 @@ func TestCausality(t *testing.T) {
 	}
 }
 
+func TestCausalityRelaxedOrder(t *testing.T) {
+	t.Parallel()
+
+	schemaStr := "create table tb(a int primary key, b int unique);"
+	ti := mockTableInfo(t, schemaStr)
+
+	syncerCfg := config.SyncerConfig{
+		QueueSize:          1024,
+		WorkerCount:        2,
+		RelaxedOrderTables: []string{"test.log_*"},
+	}
+	relaxedOrderFilter, err := syncerCfg.RelaxedOrderFilter(false)
+	require.NoError(t, err)
+
+	jobCh := make(chan *job, 10)
+	syncer := &Syncer{
+		cfg: &config.SubTaskConfig{
+			SyncerConfig: syncerCfg,
+			Name:         "task",
+			SourceID:     "source",
+		},
+		tctx:               tcontext.Background().WithLogger(log.L()),
+		sessCtx:            utils.NewSessionCtx(map[string]string{"time_zone": "UTC"}),
+		relaxedOrderFilter: relaxedOrderFilter,
+	}
+	causalityCh := causalityWrap(jobCh, syncer)
+	// the rows conflict with each other by the unique key, but no conflict job
+	// is generated for the relaxed table
+	table := &cdcmodel.TableName{Schema: "test", Table: "LOG_1"}
+	location := binlog.NewLocation("")
+	ec := &eventContext{startLocation: &location, currentLocation: &location, lastLocation: &location}
+	testCases := []struct {
+		preVals  []interface{}
+		postVals []interface{}
+	}{
+		{nil, []interface{}{1, 2}},
+		{nil, []interface{}{2, 3}},
+		{[]interface{}{1, 2}, []interface{}{1, 3}},
+		{nil, []interface{}{3, 2}},
+		{[]interface{}{1, 3}, nil},
+	}
+	for _, tc := range testCases {
+		change := sqlmodel.NewRowChange(table, nil, tc.preVals, tc.postVals, ti, nil, nil)
+		jobCh <- newDMLJob(change, ec)
+	}
+
+	require.Eventually(t, func() bool {
+		return len(causalityCh) == len(testCases)
+	}, 3*time.Second, 100*time.Millisecond)
+
+	queueKeys := make([]string, 0, len(testCases))
+	for range testCases {
+		job := <-causalityCh
+		require.Equal(t, dml, job.tp)
+		queueKeys = append(queueKeys, job.dmlQueueKey)
+	}
+	// the insert, update and delete of the same row are dispatched to the same worker
+	require.Equal(t, queueKeys[0], queueKeys[2])
+	require.Equal(t, queueKeys[0], queueKeys[4])
+	require.NotEqual(t, queueKeys[0], queueKeys[1])
+	require.NotEqual(t, queueKeys[0], queueKeys[3])
+
+	// invalid rules are rejected
+	syncerCfg.RelaxedOrderTables = []string{"test.["}
+	_, err = syncerCfg.RelaxedOrderFilter(false)
+	require.True(t, terror.ErrConfigInvalidRelaxedOrderTables.Equal(err))
+}
+
 func (s *testSyncerSuite) TestCasualityRelation(c *C) {
 	rm := newCausalityRelation()
 	c.Assert(rm.len(), Equals, 0)
@@ -39,6 +39,9 @@ import (
 // maybe change to one connection some day.
 type UpStreamConn struct {
 	BaseDB *conn.BaseDB
+	// MetaFetcher caches the periodically queried metadata, it's shared by the
+	// units of the same upstream. nil means no caching.
+	MetaFetcher *utils.UpstreamMetaFetcher
 }
 
 // NewUpStreamConn creates an UpStreamConn from config.
@@ -47,7 +50,10 @@ func NewUpStreamConn(dbCfg *config.DBConfig) (*UpStreamConn, error) {
 	if err != nil {
 		return nil, terror.WithScope(terror.DBErrorAdapt(err, terror.ErrDBDriverError), terror.ScopeUpstream)
 	}
-	return &UpStreamConn{BaseDB: baseDB}, nil
+	return &UpStreamConn{
+		BaseDB:      baseDB,
+		MetaFetcher: utils.GetUpstreamMetaFetcher(dbCfg.Host, dbCfg.Port),
+	}, nil
 }
 
 // GetMasterStatus returns binlog location that extracted from SHOW MASTER STATUS.
@@ -69,7 +75,7 @@ func (conn *UpStreamConn) GetServerUUID(ctx context.Context, flavor string) (str
 
 // GetServerUnixTS returns the result of current timestamp in upstream.
 func (conn *UpStreamConn) GetServerUnixTS(ctx context.Context) (int64, error) {
-	return utils.GetServerUnixTS(ctx, conn.BaseDB.DB)
+	return conn.MetaFetcher.GetServerUnixTS(ctx, conn.BaseDB.DB)
 }
 
 // GetCharsetAndDefaultCollation returns charset and collation info.
@@ -133,6 +133,11 @@ func (s *Syncer) genDDLInfo(qec *queryEventContext, sql string) (*ddlInfo, error
 		adjustCollation(s.tctx, ddlInfo, qec.eventStatusVars, s.charsetAndDefaultCollation, s.idAndCollationMap)
 	}
 
+	// map the charsets and collations after adjusting, so the collations added by "strict" can be mapped too
+	if len(sourceTables) > 0 {
+		s.normalizer.normalizeDDL(ddlInfo.originStmt, sourceTables[0])
+	}
+
 	routedDDL, err := parserpkg.RenameDDLTable(ddlInfo.originStmt, ddlInfo.targetTables)
 	ddlInfo.routedDDL = routedDDL
 	return ddlInfo, err
@@ -0,0 +1,240 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/util/filter"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+
+	"github.com/pingcap/tiflow/dm/dm/config"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+)
+
+// normalizer normalizes the names of downstream schemas and tables, and the
+// charsets and collations in DDLs according to the normalization config. The
+// names are normalized in `route`, so DDLs, DMLs, checkpoints and sharding
+// groups always see the same downstream tables.
+type normalizer struct {
+	global normalization
+	rules  []*normalizationRule
+	// downstreamLowerCase is whether the `lower_case_table_names` of the
+	// downstream is 1, it's used by NameCaseAuto.
+	downstreamLowerCase bool
+}
+
+type normalization struct {
+	nameCase         string
+	charsetMapping   map[string]string
+	collationMapping map[string]string
+}
+
+type normalizationRule struct {
+	tfilter.Filter
+	normalization
+}
+
+// newNormalizer creates a normalizer, it returns nil if cfg is nil.
+func newNormalizer(
+	cfg *config.NormalizationConfig, caseSensitive bool, downstreamFlavor utils.LowerCaseTableNamesFlavor,
+) (*normalizer, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	n := &normalizer{
+		global: normalization{
+			nameCase:         cfg.NameCase,
+			charsetMapping:   cfg.CharsetMapping,
+			collationMapping: cfg.CollationMapping,
+		},
+		rules:               make([]*normalizationRule, 0, len(cfg.Overrides)),
+		downstreamLowerCase: downstreamFlavor == utils.LCTableNamesInsensitive,
+	}
+	for _, override := range cfg.Overrides {
+		tablePattern := override.TablePattern
+		if tablePattern == "" {
+			tablePattern = "*"
+		}
+		f, err := tfilter.Parse([]string{escapeFilterPattern(override.SchemaPattern) + "." + escapeFilterPattern(tablePattern)})
+		if err != nil {
+			return nil, terror.ErrConfigInvalidNormalization.Generate(err.Error())
+		}
+		if !caseSensitive {
+			f = tfilter.CaseInsensitive(f)
+		}
+		rule := &normalizationRule{
+			Filter: f,
+			normalization: normalization{
+				nameCase:         n.global.nameCase,
+				charsetMapping:   mergeMapping(n.global.charsetMapping, override.CharsetMapping),
+				collationMapping: mergeMapping(n.global.collationMapping, override.CollationMapping),
+			},
+		}
+		if override.NameCase != "" {
+			rule.nameCase = override.NameCase
+		}
+		n.rules = append(n.rules, rule)
+	}
+	return n, nil
+}
+
+// needDownstreamFlavor returns whether the `lower_case_table_names` of the
+// downstream is needed by the config.
+func needDownstreamFlavor(cfg *config.NormalizationConfig) bool {
+	if cfg == nil {
+		return false
+	}
+	if cfg.NameCase == config.NameCaseAuto {
+		return true
+	}
+	for _, override := range cfg.Overrides {
+		if override.NameCase == config.NameCaseAuto {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeFilterPattern escapes the dots in the pattern, which separate schemas
+// and tables in table filters.
+func escapeFilterPattern(pattern string) string {
+	return strings.ReplaceAll(pattern, ".", `\.`)
+}
+
+func mergeMapping(global, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(global)+len(override))
+	for k, v := range global {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// match returns the normalization of the upstream table, the table name is
+// empty for schema level objects.
+func (n *normalizer) match(source *filter.Table) *normalization {
+	for _, rule := range n.rules {
+		if source.Name == "" {
+			if rule.MatchSchema(source.Schema) {
+				return &rule.normalization
+			}
+		} else if rule.MatchTable(source.Schema, source.Name) {
+			return &rule.normalization
+		}
+	}
+	return &n.global
+}
+
+// normalizeName normalizes the name of the downstream table which the
+// upstream table is routed to.
+func (n *normalizer) normalizeName(source, target *filter.Table) *filter.Table {
+	if n == nil {
+		return target
+	}
+	switch n.match(source).nameCase {
+	case config.NameCaseLower:
+	case config.NameCaseAuto:
+		if !n.downstreamLowerCase {
+			return target
+		}
+	default:
+		return target
+	}
+	return &filter.Table{Schema: strings.ToLower(target.Schema), Name: strings.ToLower(target.Name)}
+}
+
+// normalizeDDL maps the charsets and collations in the DDL of the upstream
+// table in place.
+func (n *normalizer) normalizeDDL(stmt ast.StmtNode, source *filter.Table) {
+	if n == nil {
+		return
+	}
+	m := n.match(source)
+	if len(m.charsetMapping) == 0 && len(m.collationMapping) == 0 {
+		return
+	}
+	switch st := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		m.normalizeDatabaseOptions(st.Options)
+	case *ast.AlterDatabaseStmt:
+		m.normalizeDatabaseOptions(st.Options)
+	case *ast.CreateTableStmt:
+		m.normalizeTableOptions(st.Options)
+		m.normalizeColumns(st.Cols)
+	case *ast.AlterTableStmt:
+		for _, spec := range st.Specs {
+			m.normalizeTableOptions(spec.Options)
+			m.normalizeColumns(spec.NewColumns)
+		}
+	}
+}
+
+func (m *normalization) charset(charset string) string {
+	if to, ok := m.charsetMapping[strings.ToLower(charset)]; ok {
+		return to
+	}
+	return charset
+}
+
+func (m *normalization) collation(collation string) string {
+	if to, ok := m.collationMapping[strings.ToLower(collation)]; ok {
+		return to
+	}
+	return collation
+}
+
+func (m *normalization) normalizeDatabaseOptions(options []*ast.DatabaseOption) {
+	for _, option := range options {
+		switch option.Tp {
+		case ast.DatabaseOptionCharset:
+			option.Value = m.charset(option.Value)
+		case ast.DatabaseOptionCollate:
+			option.Value = m.collation(option.Value)
+		}
+	}
+}
+
+func (m *normalization) normalizeTableOptions(options []*ast.TableOption) {
+	for _, option := range options {
+		switch option.Tp {
+		case ast.TableOptionCharset:
+			option.StrValue = m.charset(option.StrValue)
+		case ast.TableOptionCollate:
+			option.StrValue = m.collation(option.StrValue)
+		}
+	}
+}
+
+func (m *normalization) normalizeColumns(cols []*ast.ColumnDef) {
+	for _, col := range cols {
+		if col.Tp != nil {
+			if col.Tp.Charset != "" {
+				col.Tp.Charset = m.charset(col.Tp.Charset)
+			}
+			if col.Tp.Collate != "" {
+				col.Tp.Collate = m.collation(col.Tp.Collate)
+			}
+		}
+		for _, option := range col.Options {
+			if option.Tp == ast.ColumnOptionCollate {
+				option.StrValue = m.collation(option.StrValue)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/util/filter"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tiflow/dm/dm/config"
+	parserpkg "github.com/pingcap/tiflow/dm/pkg/parser"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+)
+
+func TestNormalizeName(t *testing.T) {
+	t.Parallel()
+
+	var nilNormalizer *normalizer
+	target := &filter.Table{Schema: "DB", Name: "TB"}
+	require.Equal(t, target, nilNormalizer.normalizeName(target, target))
+
+	cfg := &config.NormalizationConfig{
+		NameCase: config.NameCaseAuto,
+		Overrides: []*config.NormalizationRule{
+			{SchemaPattern: "keep_*", NameCase: config.NameCaseKeep},
+			{SchemaPattern: "lower_db", TablePattern: "t*", NameCase: config.NameCaseLower},
+		},
+	}
+	require.NoError(t, cfg.Adjust())
+	require.True(t, needDownstreamFlavor(cfg))
+
+	// auto keeps the names when the downstream is case sensitive
+	n, err := newNormalizer(cfg, false, utils.LCTableNamesSensitive)
+	require.NoError(t, err)
+	source := &filter.Table{Schema: "DB", Name: "TB"}
+	require.Equal(t, &filter.Table{Schema: "DB", Name: "TB"}, n.normalizeName(source, target))
+	source = &filter.Table{Schema: "LOWER_DB", Name: "T1"}
+	require.Equal(t, &filter.Table{Schema: "db", Name: "tb"}, n.normalizeName(source, target))
+
+	n, err = newNormalizer(cfg, false, utils.LCTableNamesInsensitive)
+	require.NoError(t, err)
+	source = &filter.Table{Schema: "DB", Name: "TB"}
+	require.Equal(t, &filter.Table{Schema: "db", Name: "tb"}, n.normalizeName(source, target))
+	source = &filter.Table{Schema: "Keep_1", Name: "TB"}
+	require.Equal(t, &filter.Table{Schema: "DB", Name: "TB"}, n.normalizeName(source, target))
+	// schema level objects only match the schema pattern
+	source = &filter.Table{Schema: "keep_1"}
+	require.Equal(t, &filter.Table{Schema: "DB"}, n.normalizeName(source, &filter.Table{Schema: "DB"}))
+
+	// patterns are case sensitive if the task is case sensitive
+	n, err = newNormalizer(cfg, true, utils.LCTableNamesInsensitive)
+	require.NoError(t, err)
+	source = &filter.Table{Schema: "Keep_1", Name: "TB"}
+	require.Equal(t, &filter.Table{Schema: "db", Name: "tb"}, n.normalizeName(source, target))
+}
+
+func TestNormalizeDDL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.NormalizationConfig{
+		CharsetMapping:   map[string]string{"UTF8": "utf8mb4"},
+		CollationMapping: map[string]string{"utf8_general_ci": "utf8mb4_general_ci"},
+		Overrides: []*config.NormalizationRule{
+			{
+				SchemaPattern:    "db2",
+				CollationMapping: map[string]string{"utf8_general_ci": "utf8mb4_bin"},
+			},
+		},
+	}
+	require.NoError(t, cfg.Adjust())
+	require.False(t, needDownstreamFlavor(cfg))
+	n, err := newNormalizer(cfg, false, utils.LCTableNamesSensitive)
+	require.NoError(t, err)
+
+	cases := []struct {
+		schema   string
+		sql      string
+		expected string
+	}{
+		{
+			"db1",
+			"CREATE DATABASE db1 CHARACTER SET utf8 COLLATE utf8_general_ci",
+			"CREATE DATABASE `db1` CHARACTER SET = utf8mb4 COLLATE = utf8mb4_general_ci",
+		},
+		{
+			"db1",
+			"ALTER DATABASE db1 CHARACTER SET latin1",
+			"ALTER DATABASE `db1` CHARACTER SET = latin1",
+		},
+		{
+			"db1",
+			"CREATE TABLE db1.tb (c1 VARCHAR(10) CHARACTER SET utf8 COLLATE utf8_general_ci) DEFAULT CHARSET=utf8",
+			"CREATE TABLE `db1`.`tb` (`c1` VARCHAR(10) CHARACTER SET UTF8MB4 COLLATE utf8mb4_general_ci) DEFAULT CHARACTER SET = UTF8MB4",
+		},
+		{
+			"db2",
+			"ALTER TABLE db2.tb ADD COLUMN c2 VARCHAR(10) COLLATE utf8_general_ci",
+			"ALTER TABLE `db2`.`tb` ADD COLUMN `c2` VARCHAR(10) COLLATE utf8mb4_bin",
+		},
+	}
+	p := parser.New()
+	for _, cs := range cases {
+		stmt, err := p.ParseOneStmt(cs.sql, "", "")
+		require.NoError(t, err)
+		tables, err := parserpkg.FetchDDLTables(cs.schema, stmt, utils.LCTableNamesSensitive)
+		require.NoError(t, err)
+		n.normalizeDDL(stmt, tables[0])
+		sql, err := parserpkg.RenameDDLTable(stmt, tables)
+		require.NoError(t, err)
+		require.Equal(t, cs.expected, sql, cs.sql)
+	}
+}
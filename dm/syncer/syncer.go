@@ -43,6 +43,7 @@ import (
 	"go.uber.org/zap"
 
 	regexprrouter "github.com/pingcap/tidb/util/regexpr-router"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	router "github.com/pingcap/tidb/util/table-router"
 	"github.com/pingcap/tiflow/dm/dm/config"
 	"github.com/pingcap/tiflow/dm/dm/pb"
@@ -50,6 +51,7 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	"github.com/pingcap/tiflow/dm/pkg/binlog/event"
 	"github.com/pingcap/tiflow/dm/pkg/binlog/reader"
+	"github.com/pingcap/tiflow/dm/pkg/binlog/stats"
 	"github.com/pingcap/tiflow/dm/pkg/conn"
 	tcontext "github.com/pingcap/tiflow/dm/pkg/context"
 	fr "github.com/pingcap/tiflow/dm/pkg/func-rollback"
@@ -70,6 +72,7 @@ import (
 	sm "github.com/pingcap/tiflow/dm/syncer/safe-mode"
 	"github.com/pingcap/tiflow/dm/syncer/shardddl"
 	"github.com/pingcap/tiflow/pkg/errorutil"
+	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
 )
 
@@ -162,12 +165,16 @@ type Syncer struct {
 	waitTransactionLock sync.Mutex
 
 	tableRouter     *regexprrouter.RouteTable
+	normalizer      *normalizer
 	binlogFilter    *bf.BinlogEvent
 	columnMapping   *cm.Mapping
 	baList          *filter.Filter
 	exprFilterGroup *ExprFilterGroup
 	sessCtx         sessionctx.Context
 
+	// relaxedOrderFilter matches the source tables whose rows skip cross-row causality detection
+	relaxedOrderFilter tfilter.Filter
+
 	running      atomic.Bool
 	closed       atomic.Bool
 	schemaLoaded atomic.Bool
@@ -184,6 +191,8 @@ type Syncer struct {
 
 	binlogSizeCount     atomic.Int64
 	lastBinlogSizeCount atomic.Int64
+	// binlogStats records the binlog events into the binlog statistics of the source.
+	binlogStats *stats.Recorder
 
 	lastCount atomic.Int64
 	count     atomic.Int64
@@ -247,7 +256,8 @@ type Syncer struct {
 
 // NewSyncer creates a new Syncer.
 func NewSyncer(cfg *config.SubTaskConfig, etcdClient *clientv3.Client, relay relay.Process) *Syncer {
-	logger := log.With(zap.String("task", cfg.Name), zap.String("unit", "binlog replication"))
+	logger := log.With(zap.String("task", cfg.Name), zap.String("unit", "binlog replication")).
+		WithComponent(logutil.ComponentSyncer)
 	syncer := &Syncer{
 		pessimist: shardddl.NewPessimist(&logger, etcdClient, cfg.Name, cfg.SourceID),
 		optimist:  shardddl.NewOptimist(&logger, etcdClient, cfg.Name, cfg.SourceID),
@@ -352,6 +362,11 @@ func (s *Syncer) Init(ctx context.Context) (err error) {
 	}
 	rollbackHolder.Add(fr.FuncRollback{Name: "close-DBs", Fn: s.closeDBs})
 
+	err = s.checkBinlogRowImage(ctx)
+	if err != nil {
+		return err
+	}
+
 	s.schemaTracker, err = schema.NewTracker(ctx, s.cfg.Name, s.cfg.To.Session, s.downstreamTrackConn)
 	if err != nil {
 		return terror.ErrSchemaTrackerInit.Delegate(err)
@@ -376,6 +391,11 @@ func (s *Syncer) Init(ctx context.Context) (err error) {
 		return terror.ErrSyncerUnitGenBinlogEventFilter.Delegate(err)
 	}
 
+	s.relaxedOrderFilter, err = s.cfg.RelaxedOrderFilter(s.cfg.CaseSensitive)
+	if err != nil {
+		return err
+	}
+
 	vars := map[string]string{
 		"time_zone": s.timezone.String(),
 	}
@@ -402,6 +422,11 @@ func (s *Syncer) Init(ctx context.Context) (err error) {
 		return err
 	}
 
+	err = s.genNormalizer(ctx)
+	if err != nil {
+		return err
+	}
+
 	var schemaMap map[string]string
 	var tableMap map[string]map[string]string
 	if s.SourceTableNamesFlavor == utils.LCTableNamesSensitive {
@@ -461,6 +486,7 @@ func (s *Syncer) Init(ctx context.Context) (err error) {
 		}
 		rollbackHolder.Add(fr.FuncRollback{Name: "remove-active-realylog", Fn: s.removeActiveRelayLog})
 	}
+	s.binlogStats = stats.Register(s.cfg.SourceID, s.cfg.Name)
 	s.reset()
 	return nil
 }
@@ -1947,6 +1973,7 @@ func (s *Syncer) Run(ctx context.Context) (err error) {
 		}
 		s.binlogSizeCount.Add(int64(e.Header.EventSize))
 		metrics.BinlogEventSizeHistogram.WithLabelValues(s.cfg.Name, s.cfg.WorkerName, s.cfg.SourceID).Observe(float64(e.Header.EventSize))
+		s.binlogStats.Observe(e)
 
 		failpoint.Inject("ProcessBinlogSlowDown", nil)
 
@@ -3245,6 +3272,24 @@ func (s *Syncer) genRouter() error {
 	return nil
 }
 
+func (s *Syncer) genNormalizer(ctx context.Context) error {
+	downstreamFlavor := utils.LCTableNamesSensitive
+	if needDownstreamFlavor(s.cfg.Normalization) {
+		baseConn, err := s.toDB.GetBaseConn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.CloseBaseConnWithoutErr(s.toDB, baseConn)
+		downstreamFlavor, err = utils.FetchLowerCaseTableNamesSetting(ctx, baseConn.DBConn)
+		if err != nil {
+			return err
+		}
+	}
+	var err error
+	s.normalizer, err = newNormalizer(s.cfg.Normalization, s.cfg.CaseSensitive, downstreamFlavor)
+	return err
+}
+
 func (s *Syncer) loadTableStructureFromDump(ctx context.Context) error {
 	logger := s.tctx.L()
 	files, err := storage.CollectDirFiles(ctx, s.cfg.LoaderConfig.Dir, nil)
@@ -3325,7 +3370,10 @@ func (s *Syncer) createDBs(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	s.fromDB = &dbconn.UpStreamConn{BaseDB: fromDB}
+	s.fromDB = &dbconn.UpStreamConn{
+		BaseDB:      fromDB,
+		MetaFetcher: utils.GetUpstreamMetaFetcher(s.cfg.From.Host, s.cfg.From.Port),
+	}
 	s.fromConn = fromConns[0]
 	conn, err := s.fromDB.BaseDB.GetBaseConn(ctx)
 	if err != nil {
@@ -3390,6 +3438,28 @@ func (s *Syncer) createDBs(ctx context.Context) error {
 	return nil
 }
 
+// checkBinlogRowImage fails fast if the upstream logs MINIMAL row images, so the
+// task doesn't fail when it meets the first UPDATE or DELETE, which requires the
+// full rows, e.g. an UPDATE is replicated as a DELETE and a REPLACE in safe mode.
+// NOBLOB only omits the unchanged BLOB and TEXT columns, so it's a warning.
+func (s *Syncer) checkBinlogRowImage(ctx context.Context) error {
+	ctx2, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+	image, err := utils.GetBinlogRowImage(ctx2, s.fromDB.BaseDB.DB)
+	if err != nil {
+		return err
+	}
+	switch image {
+	case utils.BinlogRowImageFull:
+	case utils.BinlogRowImageMinimal:
+		return terror.ErrSyncerBinlogRowImageNotFull.Generate(image)
+	default:
+		s.tctx.L().Warn("upstream binlog_row_image is not FULL, the task fails if a BLOB or TEXT column is not logged",
+			zap.String("binlog_row_image", image))
+	}
+	return nil
+}
+
 // closeBaseDB closes all opened DBs, rollback for createConns.
 func (s *Syncer) closeDBs() {
 	dbconn.CloseUpstreamConn(s.tctx, s.fromDB)
@@ -3424,13 +3494,13 @@ func (s *Syncer) route(table *filter.Table) *filter.Table {
 		s.tctx.L().Error("fail to route table", zap.Stringer("table", table), zap.Error(err)) // log the error, but still continue
 	}
 	if targetSchema == "" {
-		return table
+		return s.normalizer.normalizeName(table, table)
 	}
 	if targetTable == "" {
 		targetTable = table.Name
 	}
 
-	return &filter.Table{Schema: targetSchema, Name: targetTable}
+	return s.normalizer.normalizeName(table, &filter.Table{Schema: targetSchema, Name: targetTable})
 }
 
 func (s *Syncer) IsRunning() bool {
@@ -3462,6 +3532,7 @@ func (s *Syncer) Close() {
 	// when closing syncer by `stop-task`, remove active relay log from hub
 	s.removeActiveRelayLog()
 	metrics.RemoveLabelValuesWithTaskInMetrics(s.cfg.Name)
+	stats.Unregister(s.cfg.SourceID, s.cfg.Name)
 
 	s.runWg.Wait()
 	s.closed.Store(true)
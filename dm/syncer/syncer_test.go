@@ -1824,6 +1824,34 @@ func TestWaitBeforeRunExit(t *testing.T) {
 	require.NoError(t, failpoint.Disable("github.com/pingcap/tiflow/dm/syncer/recordAndIgnorePrepareTime"))
 }
 
+func TestCheckBinlogRowImage(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	syncer := NewSyncer(cfg, nil, nil)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	syncer.fromDB = &dbconn.UpStreamConn{BaseDB: conn.NewBaseDB(db)}
+
+	for _, tc := range []struct {
+		image string
+		fail  bool
+	}{
+		{utils.BinlogRowImageFull, false},
+		{utils.BinlogRowImageNoBlob, false},
+		{utils.BinlogRowImageMinimal, true},
+	} {
+		mock.ExpectQuery("SHOW GLOBAL VARIABLES LIKE 'binlog_row_image'").WillReturnRows(
+			sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("binlog_row_image", tc.image))
+		err = syncer.checkBinlogRowImage(context.Background())
+		if tc.fail {
+			require.True(t, terror.ErrSyncerBinlogRowImageNotFull.Equal(err))
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestSyncerGetTableInfo(t *testing.T) {
 	cfg := genDefaultSubTaskConfig4Test()
 	cfg.WorkerCount = 0
@@ -25,6 +25,10 @@ function config_wrong_arg() {
 		"config source haha" \
 		"source not found" 1
 
+	run_dm_ctl $WORK_DIR "127.0.0.1:$MASTER_PORT" \
+		"config changefeed haha" \
+		"task haha not found" 1
+
 	# test alias
 	run_dm_ctl $WORK_DIR "127.0.0.1:$MASTER_PORT" \
 		"get-config haha" \
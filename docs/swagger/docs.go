@@ -186,7 +186,7 @@ var doc = `{
                 }
             },
             "put": {
-                "description": "Update a changefeed",
+                "description": "Update a changefeed\nthe changefeed must be stopped unless only the throttle of sink is updated.",
                 "consumes": [
                     "application/json"
                 ],
@@ -315,6 +315,142 @@ var doc = `{
                 }
             }
         },
+        "/api/v1/changefeeds/{changefeed_id}/clone": {
+            "post": {
+                "description": "create a new changefeed with the configuration of an existing changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Clone a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "changefeed clone config",
+                        "name": "changefeed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedCloneConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/changefeeds/{changefeed_id}/consistency_report": {
+            "get": {
+                "description": "get the latest consistency report of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Get the consistency report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsistencyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "block the changefeed at the current ts, record the corresponding downstream ts\nand checksum the downstream tables at it, the report is generated in background.\nthe report in progress is returned if there is one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Request a consistency report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.ConsistencyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/changefeeds/{changefeed_id}/pause": {
             "post": {
                 "description": "Pause a changefeed",
@@ -397,6 +533,109 @@ var doc = `{
                 }
             }
         },
+        "/api/v1/changefeeds/{changefeed_id}/rewind": {
+            "post": {
+                "description": "rewind the checkpoint of a stopped changefeed to an earlier ts and resume it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Rewind a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "changefeed rewind config",
+                        "name": "rewind",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangefeedRewindConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/changefeeds/{changefeed_id}/tables/approve": {
+            "post": {
+                "description": "approve tables to be replicated by a changefeed created with freeze_tables,\nthe changefeed must be stopped and the tables take effect after it is resumed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed"
+                ],
+                "summary": "Approve tables of a frozen changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "tables in the form of schema.table",
+                        "name": "tables",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/changefeeds/{changefeed_id}/tables/move_table": {
             "post": {
                 "description": "move one table to the target capture",
@@ -523,6 +762,26 @@ var doc = `{
                 }
             }
         },
+        "/api/v1/hotspots": {
+            "get": {
+                "description": "get the tables and regions with the highest row change event rates observed by the pullers of the capture",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "Get the upstream write hotspot report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.HotspotReport"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/log": {
             "post": {
                 "description": "change TiCDC log level dynamically",
@@ -560,6 +819,64 @@ var doc = `{
                 }
             }
         },
+        "/api/v1/log/components": {
+            "get": {
+                "description": "list the runtime log level and debug log sampling of components",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "List the log configs of components",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/logutil.ComponentLogConfig"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "change the log level and debug log sampling of a component dynamically",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common"
+                ],
+                "summary": "Change the log config of a component",
+                "parameters": [
+                    {
+                        "description": "component log config",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logutil.ComponentLogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/owner/resign": {
             "post": {
                 "description": "notify the current owner to resign",
@@ -756,6 +1073,35 @@ var doc = `{
                 }
             }
         },
+        "logutil.ComponentLogConfig": {
+            "type": "object",
+            "properties": {
+                "debug-samples-per-second": {
+                    "description": "DebugSamplesPerSecond is the max number of debug logs with the same\nmessage written by the component per second, 0 means unlimited.",
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "Level is the log level of the component, empty means the global level.",
+                    "type": "string"
+                }
+            }
+        },
+        "logutil.ComponentLogRequest": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "type": "string"
+                },
+                "debug-samples-per-second": {
+                    "description": "DebugSamplesPerSecond is the max number of debug logs with the same\nmessage written by the component per second, 0 means unlimited.",
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "Level is the log level of the component, empty means the global level.",
+                    "type": "string"
+                }
+            }
+        },
         "model.Capture": {
             "type": "object",
             "properties": {
@@ -791,6 +1137,18 @@ var doc = `{
                 }
             }
         },
+        "model.ChangeFeedBarrier": {
+            "type": "object",
+            "properties": {
+                "ts": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type is the barrier type, ddl, sync-point, finish or consistency-report",
+                    "type": "string"
+                }
+            }
+        },
         "model.ChangefeedCommonInfo": {
             "type": "object",
             "properties": {
@@ -811,6 +1169,37 @@ var doc = `{
                 }
             }
         },
+        "model.ChangefeedCloneConfig": {
+            "type": "object",
+            "properties": {
+                "changefeed_id": {
+                    "description": "ID is the ID of the new changefeed",
+                    "type": "string"
+                },
+                "ignore_ineligible_table": {
+                    "type": "boolean",
+                    "default": false
+                },
+                "paused": {
+                    "description": "Paused indicates whether the new changefeed is created in stopped state",
+                    "type": "boolean",
+                    "default": false
+                },
+                "sink_uri": {
+                    "description": "SinkURI is the sink uri of the new changefeed, the original one is used if it's empty",
+                    "type": "string"
+                },
+                "start_ts": {
+                    "description": "StartTS is the start ts of the new changefeed, the current ts is used if it's 0",
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "timezone used when checking sink uri",
+                    "type": "string",
+                    "default": "system"
+                }
+            }
+        },
         "model.ChangefeedConfig": {
             "type": "object",
             "properties": {
@@ -828,6 +1217,11 @@ var doc = `{
                     "type": "boolean",
                     "default": false
                 },
+                "freeze_tables": {
+                    "description": "if true, only the tables matched at creation are replicated,\ntables created later must be approved explicitly.",
+                    "type": "boolean",
+                    "default": false
+                },
                 "ignore_ineligible_table": {
                     "type": "boolean",
                     "default": false
@@ -864,6 +1258,9 @@ var doc = `{
         "model.ChangefeedDetail": {
             "type": "object",
             "properties": {
+                "barrier": {
+                    "$ref": "#/definitions/model.ChangeFeedBarrier"
+                },
                 "checkpoint_time": {
                     "type": "string"
                 },
@@ -894,6 +1291,9 @@ var doc = `{
                 "sink_uri": {
                     "type": "string"
                 },
+                "slowest_capture": {
+                    "type": "string"
+                },
                 "sort_engine": {
                     "type": "string"
                 },
@@ -903,6 +1303,13 @@ var doc = `{
                 "state": {
                     "type": "string"
                 },
+                "table_diagnoses": {
+                    "description": "TableDiagnoses explain the tables which are not replicating or keep\nbeing rescheduled.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TableDiagnosis"
+                    }
+                },
                 "target_ts": {
                     "type": "integer"
                 },
@@ -914,6 +1321,49 @@ var doc = `{
                 }
             }
         },
+        "model.ChangefeedRewindConfig": {
+            "type": "object",
+            "properties": {
+                "checkpoint_ts": {
+                    "description": "CheckpointTs is the new checkpoint, it must be less than the current one",
+                    "type": "integer"
+                },
+                "use_redo": {
+                    "description": "UseRedo indicates whether to rewind the redo logs together",
+                    "type": "boolean",
+                    "default": false
+                }
+            }
+        },
+        "model.ConsistencyReport": {
+            "type": "object",
+            "properties": {
+                "downstream_ts": {
+                    "description": "DownstreamTs is the ts of downstream when the changefeed reaches UpstreamTs,\nit's recorded in the syncpoint table of downstream",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "request_time": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "tables": {
+                    "description": "Tables is the checksum of the tables in downstream at DownstreamTs, the users\ncan compare it with ADMIN CHECKSUM TABLE in upstream at UpstreamTs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TableChecksum"
+                    }
+                },
+                "upstream_ts": {
+                    "description": "UpstreamTs is the ts of upstream which the report is requested at",
+                    "type": "integer"
+                }
+            }
+        },
         "model.HTTPError": {
             "type": "object",
             "properties": {
@@ -925,6 +1375,22 @@ var doc = `{
                 }
             }
         },
+        "model.HotspotReport": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "description": "GeneratedAt is the time the report is generated at",
+                    "type": "string"
+                },
+                "tables": {
+                    "description": "Tables are the tables sorted by the event rate in the descending order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TableHotspot"
+                    }
+                }
+            }
+        },
         "model.ProcessorCommonInfo": {
             "type": "object",
             "properties": {
@@ -964,6 +1430,17 @@ var doc = `{
                 }
             }
         },
+        "model.RegionHotspot": {
+            "type": "object",
+            "properties": {
+                "events_per_second": {
+                    "type": "number"
+                },
+                "region_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.RunningError": {
             "type": "object",
             "properties": {
@@ -998,6 +1475,80 @@ var doc = `{
                 }
             }
         },
+        "model.TableChecksum": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "type": "integer"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "table": {
+                    "type": "string"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                },
+                "total_kvs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TableDiagnosis": {
+            "type": "object",
+            "properties": {
+                "bouncing": {
+                    "description": "Bouncing is true if the table is added and removed repeatedly recently.",
+                    "type": "boolean"
+                },
+                "capture_id": {
+                    "type": "string"
+                },
+                "history": {
+                    "description": "History is the recent scheduling operations of the table, the oldest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TableScheduleRecord"
+                    }
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "state": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TableHotspot": {
+            "type": "object",
+            "properties": {
+                "changefeed_id": {
+                    "type": "string"
+                },
+                "events_per_second": {
+                    "type": "number"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RegionHotspot"
+                    }
+                },
+                "table_id": {
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                }
+            }
+        },
         "model.TableOperation": {
             "type": "object",
             "properties": {
@@ -1015,6 +1566,23 @@ var doc = `{
                     "type": "integer"
                 }
             }
+        },
+        "model.TableScheduleRecord": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -51,6 +51,18 @@ type Actor[T any] interface {
 	OnClose()
 }
 
+// Priority is the priority of a message sent to a mailbox.
+type Priority int
+
+const (
+	// PriorityNormal is the priority of data messages.
+	PriorityNormal Priority = iota
+	// PriorityHigh is the priority of control messages, e.g. Stop and
+	// Barrier. They are received ahead of normal ones, and they are not
+	// blocked by a mailbox full of normal ones.
+	PriorityHigh
+)
+
 // Mailbox sends messages to an actor.
 // Mailbox is threadsafe.
 type Mailbox[T any] interface {
@@ -64,6 +76,10 @@ type Mailbox[T any] interface {
 	// Retruns context.Canceled or context.DeadlineExceeded
 	// when context is canceled or deadline exceeded.
 	SendB(ctx context.Context, msg message.Message[T]) error
+	// SendPriority is like Send, but sends the message at the priority.
+	SendPriority(msg message.Message[T], priority Priority) error
+	// SendPriorityB is like SendB, but sends the message at the priority.
+	SendPriorityB(ctx context.Context, msg message.Message[T], priority Priority) error
 
 	// Receive a message.
 	// It must be nonblocking and should only be called by System.
@@ -76,15 +92,27 @@ type Mailbox[T any] interface {
 	close()
 }
 
+// maxHighPriorityCap is the max capacity of the high priority lane of a
+// mailbox, control messages are rare, so it doesn't need to be as large as
+// the normal lane.
+const maxHighPriorityCap = 16
+
 // NewMailbox creates a fixed capacity mailbox.
 // The minimum capacity is 1.
+// The high priority lane has its own capacity, which is no more than
+// the capacity of the mailbox.
 func NewMailbox[T any](id ID, cap int) Mailbox[T] {
 	if cap <= 0 {
 		cap = 1
 	}
+	highCap := cap
+	if highCap > maxHighPriorityCap {
+		highCap = maxHighPriorityCap
+	}
 	return &mailbox[T]{
 		id:      id,
 		msgCh:   make(chan message.Message[T], cap),
+		highCh:  make(chan message.Message[T], highCap),
 		closeCh: make(chan struct{}),
 		state:   mailboxStateRunning,
 	}
@@ -103,6 +131,8 @@ type mailbox[T any] struct {
 
 	id    ID
 	msgCh chan message.Message[T]
+	// highCh is the lane of high priority messages.
+	highCh chan message.Message[T]
 }
 
 func (m *mailbox[T]) ID() ID {
@@ -110,29 +140,52 @@ func (m *mailbox[T]) ID() ID {
 }
 
 func (m *mailbox[T]) Send(msg message.Message[T]) error {
+	return m.SendPriority(msg, PriorityNormal)
+}
+
+func (m *mailbox[T]) SendB(ctx context.Context, msg message.Message[T]) error {
+	return m.SendPriorityB(ctx, msg, PriorityNormal)
+}
+
+func (m *mailbox[T]) lane(priority Priority) chan message.Message[T] {
+	if priority == PriorityHigh {
+		return m.highCh
+	}
+	return m.msgCh
+}
+
+func (m *mailbox[T]) SendPriority(msg message.Message[T], priority Priority) error {
 	if atomic.LoadUint64(&m.state) == mailboxStateClosed {
 		return errActorStopped
 	}
 	select {
-	case m.msgCh <- msg:
+	case m.lane(priority) <- msg:
 		return nil
 	default:
 		return errMailboxFull
 	}
 }
 
-func (m *mailbox[T]) SendB(ctx context.Context, msg message.Message[T]) error {
+func (m *mailbox[T]) SendPriorityB(
+	ctx context.Context, msg message.Message[T], priority Priority,
+) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-m.closeCh:
 		return errActorStopped
-	case m.msgCh <- msg:
+	case m.lane(priority) <- msg:
 		return nil
 	}
 }
 
+// Receive receives high priority messages first.
 func (m *mailbox[T]) Receive() (message.Message[T], bool) {
+	select {
+	case msg, ok := <-m.highCh:
+		return msg, ok
+	default:
+	}
 	select {
 	case msg, ok := <-m.msgCh:
 		return msg, ok
@@ -142,7 +195,7 @@ func (m *mailbox[T]) Receive() (message.Message[T], bool) {
 }
 
 func (m *mailbox[T]) len() int {
-	return len(m.msgCh)
+	return len(m.highCh) + len(m.msgCh)
 }
 
 func (m *mailbox[T]) close() {
@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ActorInfo is the debug info of an actor.
+type ActorInfo struct {
+	ID ID `json:"id"`
+	// MailboxLength is the number of pending messages in the mailbox.
+	MailboxLength int `json:"mailbox_length"`
+	// PollCount is the number of times the actor has been polled.
+	PollCount uint64 `json:"poll_count"`
+	// PollDuration is the total time spent polling the actor.
+	PollDuration time.Duration `json:"poll_duration"`
+	// LastPollTime is the time the actor was polled last time, it's zero if
+	// the actor has never been polled.
+	LastPollTime time.Time `json:"last_poll_time"`
+}
+
+// SystemInfo is the debug info of a system.
+type SystemInfo struct {
+	Name       string `json:"name"`
+	NumWorker  int    `json:"num_worker"`
+	NumActor   int    `json:"num_actor"`
+	NumPending int    `json:"num_pending_message"`
+	// Actors are sorted by mailbox length in descending order.
+	Actors []ActorInfo `json:"actors"`
+}
+
+type debugInfoProvider interface {
+	debugInfo() SystemInfo
+}
+
+// runningSystems are systems that have been started and not stopped.
+var runningSystems sync.Map // map[debugInfoProvider]struct{}
+
+// DebugInfo returns the debug info of all running systems, at most limit
+// busiest actors are returned for each system, all actors are returned if
+// limit is not positive.
+func DebugInfo(limit int) []SystemInfo {
+	infos := make([]SystemInfo, 0)
+	runningSystems.Range(func(key, _ interface{}) bool {
+		info := key.(debugInfoProvider).debugInfo()
+		if limit > 0 && len(info.Actors) > limit {
+			info.Actors = info.Actors[:limit]
+		}
+		infos = append(infos, info)
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+func (s *System[T]) debugInfo() SystemInfo {
+	info := SystemInfo{
+		Name:      s.name,
+		NumWorker: s.numWorker,
+		Actors:    s.actorInfos(),
+	}
+	info.NumActor = len(info.Actors)
+	for _, a := range info.Actors {
+		info.NumPending += a.MailboxLength
+	}
+	return info
+}
+
+// actorInfos returns the infos of all actors in the system, sorted by
+// mailbox length in descending order.
+func (s *System[T]) actorInfos() []ActorInfo {
+	infos := make([]ActorInfo, 0)
+	s.router.procs.Range(func(_, value interface{}) bool {
+		infos = append(infos, value.(*proc[T]).info())
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].MailboxLength != infos[j].MailboxLength {
+			return infos[i].MailboxLength > infos[j].MailboxLength
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
@@ -24,6 +24,11 @@ const (
 	// Prometheus collects metrics every 15 seconds, we use a smaller interval
 	// to improve accuracy.
 	metricsInterval = 5 * time.Second
+	// The number of the busiest actors whose mailbox lengths are recorded.
+	busiestActorCount = 5
+	// Only one in every pollDurationSampleInterval polls is observed by the
+	// poll duration histogram, so the hot loop of polling is not slowed down.
+	pollDurationSampleInterval = 64
 )
 
 var (
@@ -77,6 +82,36 @@ var (
 			Name:      "drop_message_total",
 			Help:      "The total number of dropped messages in an actor system.",
 		}, []string{"name"})
+	dropSendCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "actor",
+			Name:      "drop_send_total",
+			Help:      "The total number of messages failed to send because mailboxes are full.",
+		}, []string{"name"})
+	pollActorDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "actor",
+			Name:      "poll_duration_seconds",
+			Help:      "Bucketed histogram of sampled actor poll time (s).",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16), // 100us ~ 3.2s
+		}, []string{"name"})
+	mailboxLength = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "actor",
+			Name:      "mailbox_length",
+			Help:      "Bucketed histogram of the number of pending messages in mailboxes.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
+		}, []string{"name"})
+	busiestActorMailboxLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "actor",
+			Name:      "busiest_actor_mailbox_length",
+			Help:      "The number of pending messages in mailboxes of the busiest actors.",
+		}, []string{"name", "id"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -88,4 +123,8 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(pollCounter)
 	registry.MustRegister(slowPollActorDuration)
 	registry.MustRegister(dropMsgCount)
+	registry.MustRegister(dropSendCount)
+	registry.MustRegister(pollActorDuration)
+	registry.MustRegister(mailboxLength)
+	registry.MustRegister(busiestActorMailboxLength)
 }
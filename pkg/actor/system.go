@@ -75,6 +75,47 @@ type proc[T any] struct {
 	state uint64
 	mb    Mailbox[T]
 	actor Actor[T]
+	// rd is the *ready[T] of the system that polls the proc, it changes when
+	// the proc is transferred to another system.
+	rd atomic.Value
+
+	// Statistics for debugging, they are updated by the system and may be
+	// read by others concurrently.
+	pollCount    uint64
+	pollDuration int64 // in nanoseconds
+	lastPollTime int64 // in unix nanoseconds
+}
+
+// recordPoll records a poll of the proc that takes d and ends at now.
+func (p *proc[T]) recordPoll(d time.Duration, now time.Time) {
+	atomic.AddUint64(&p.pollCount, 1)
+	atomic.AddInt64(&p.pollDuration, int64(d))
+	atomic.StoreInt64(&p.lastPollTime, now.UnixNano())
+}
+
+// info returns the debug info of the proc.
+// info is threadsafe.
+func (p *proc[T]) info() ActorInfo {
+	info := ActorInfo{
+		ID:            p.mb.ID(),
+		MailboxLength: p.mb.len(),
+		PollCount:     atomic.LoadUint64(&p.pollCount),
+		PollDuration:  time.Duration(atomic.LoadInt64(&p.pollDuration)),
+	}
+	if t := atomic.LoadInt64(&p.lastPollTime); t != 0 {
+		info.LastPollTime = time.Unix(0, t)
+	}
+	return info
+}
+
+// ready returns the ready of the system that polls the proc, or def if the
+// proc is not spawned by a system, e.g., inserted by tests.
+// ready is threadsafe.
+func (p *proc[T]) ready(def *ready[T]) *ready[T] {
+	if rd, ok := p.rd.Load().(*ready[T]); ok {
+		return rd
+	}
+	return def
 }
 
 // batchReceiveMsgs receives messages into batchMsg.
@@ -191,28 +232,22 @@ func (rd *ready[T]) enqueueLocked(p *proc[T], force bool) error {
 	return nil
 }
 
-// schedule schedules the proc to system.
-func (rd *ready[T]) schedule(p *proc[T]) error {
-	rd.Lock()
-	err := rd.enqueueLocked(p, false)
-	rd.Unlock()
-	if err != nil {
-		return err
-	}
-	// Notify system to poll the proc.
-	rd.cond.Signal()
-	return nil
-}
-
 // scheduleN schedules a slice of procs to system.
-// It ignores stopped procs.
-func (rd *ready[T]) scheduleN(procs []*proc[T]) {
+// It ignores stopped procs, and returns the procs that have been transferred
+// to other systems.
+func (rd *ready[T]) scheduleN(procs []*proc[T]) []*proc[T] {
+	var transferred []*proc[T]
 	rd.Lock()
 	for _, p := range procs {
+		if p.ready(rd) != rd {
+			transferred = append(transferred, p)
+			continue
+		}
 		_ = rd.enqueueLocked(p, false)
 	}
 	rd.Unlock()
 	rd.cond.Broadcast()
+	return transferred
 }
 
 // batchReceiveProcs receives ready procs into batchP.
@@ -239,6 +274,8 @@ type Router[T any] struct {
 
 	// Map of ID to proc
 	procs sync.Map
+
+	metricDropSend prometheus.Counter
 }
 
 // NewRouter returns a new router.
@@ -250,6 +287,7 @@ func NewRouter[T any](name string) *Router[T] {
 	r.rd.procs = make(map[ID]struct{})
 	r.rd.queue.Init()
 	r.rd.metricDropMessage = dropMsgCount.WithLabelValues(name)
+	r.metricDropSend = dropSendCount.WithLabelValues(name)
 	return r
 }
 
@@ -257,32 +295,53 @@ func NewRouter[T any](name string) *Router[T] {
 // ErrMailboxFull when the actor full.
 // ErrActorNotFound when the actor not found.
 func (r *Router[T]) Send(id ID, msg message.Message[T]) error {
+	return r.SendPriority(id, msg, PriorityNormal)
+}
+
+// SendB sends a message to an actor, blocks when it's full.
+// ErrActorNotFound when the actor not found.
+// Canceled or DeadlineExceeded when the context is canceled or done.
+func (r *Router[T]) SendB(ctx context.Context, id ID, msg message.Message[T]) error {
+	return r.SendPriorityB(ctx, id, msg, PriorityNormal)
+}
+
+// SendPriority sends a message to an actor at the priority.
+// It's a non-blocking send.
+// ErrMailboxFull when the lane of the priority is full.
+// ErrActorNotFound when the actor not found.
+func (r *Router[T]) SendPriority(id ID, msg message.Message[T], priority Priority) error {
 	value, ok := r.procs.Load(id)
 	if !ok {
 		return errActorNotFound
 	}
 	p := value.(*proc[T])
-	err := p.mb.Send(msg)
+	err := p.mb.SendPriority(msg, priority)
 	if err != nil {
+		if err == errMailboxFull {
+			r.metricDropSend.Inc()
+		}
 		return err
 	}
-	return r.rd.schedule(p)
+	return r.schedule(p)
 }
 
-// SendB sends a message to an actor, blocks when it's full.
+// SendPriorityB sends a message to an actor at the priority, blocks when
+// the lane of the priority is full.
 // ErrActorNotFound when the actor not found.
 // Canceled or DeadlineExceeded when the context is canceled or done.
-func (r *Router[T]) SendB(ctx context.Context, id ID, msg message.Message[T]) error {
+func (r *Router[T]) SendPriorityB(
+	ctx context.Context, id ID, msg message.Message[T], priority Priority,
+) error {
 	value, ok := r.procs.Load(id)
 	if !ok {
 		return errActorNotFound
 	}
 	p := value.(*proc[T])
-	err := p.mb.SendB(ctx, msg)
+	err := p.mb.SendPriorityB(ctx, msg, priority)
 	if err != nil {
 		return err
 	}
-	return r.rd.schedule(p)
+	return r.schedule(p)
 }
 
 // Broadcast a message to all actors in the router.
@@ -301,14 +360,43 @@ func (r *Router[T]) Broadcast(ctx context.Context, msg message.Message[T]) {
 		}
 		ps = append(ps, p)
 		if len(ps) == batchSize {
-			r.rd.scheduleN(ps)
+			r.scheduleN(ps)
 			ps = ps[:0]
 		}
 		return true
 	})
 
 	if len(ps) != 0 {
-		r.rd.scheduleN(ps)
+		r.scheduleN(ps)
+	}
+}
+
+// schedule schedules the proc to the system that polls it.
+func (r *Router[T]) schedule(p *proc[T]) error {
+	for {
+		rd := p.ready(r.rd)
+		rd.Lock()
+		if p.ready(r.rd) != rd {
+			// The proc has been transferred to another system, retry with
+			// the ready of that system.
+			rd.Unlock()
+			continue
+		}
+		err := rd.enqueueLocked(p, false)
+		rd.Unlock()
+		if err != nil {
+			return err
+		}
+		// Notify system to poll the proc.
+		rd.cond.Signal()
+		return nil
+	}
+}
+
+// scheduleN schedules a slice of procs to the systems that poll them.
+func (r *Router[T]) scheduleN(procs []*proc[T]) {
+	for _, p := range r.rd.scheduleN(procs) {
+		_ = r.schedule(p)
 	}
 }
 
@@ -410,6 +498,8 @@ func (b *SystemBuilder[T]) Build() (*System[T], *Router[T]) {
 		metricSystemPollLoop:   pollCounter.WithLabelValues(b.name, "system"),
 		metricActorPollLoop:    pollCounter.WithLabelValues(b.name, "actor"),
 		metricSlowPollDuration: slowPollActorDuration.WithLabelValues(b.name),
+		metricPollDuration:     pollActorDuration.WithLabelValues(b.name),
+		metricMailboxLength:    mailboxLength.WithLabelValues(b.name),
 		metricProcBatch:        batchSizeCounter.WithLabelValues(b.name, "proc"),
 		metricMsgBatch:         batchSizeCounter.WithLabelValues(b.name, "msg"),
 	}, router
@@ -436,8 +526,12 @@ type System[T any] struct {
 	metricSystemPollLoop   prometheus.Counter
 	metricActorPollLoop    prometheus.Counter
 	metricSlowPollDuration prometheus.Observer
+	metricPollDuration     prometheus.Observer
+	metricMailboxLength    prometheus.Observer
 	metricProcBatch        prometheus.Counter
 	metricMsgBatch         prometheus.Counter
+	// IDs of the busiest actors whose mailbox lengths are recorded.
+	busiestActorIDs []string
 }
 
 // Start the system. Cancelling the context to stop the system.
@@ -463,6 +557,11 @@ func (s *System[T]) Start(ctx context.Context) {
 			return nil
 		})
 	}
+	s.wg.Go(func() error {
+		s.collectMetrics(ctx)
+		return nil
+	})
+	runningSystems.Store(debugInfoProvider(s), struct{}{})
 }
 
 // Stop the system, cancels all actors. It should be called after Start.
@@ -483,6 +582,42 @@ func (s *System[T]) Stop() {
 	s.metricTotalWorkers.Add(-float64(s.numWorker))
 	// Worker goroutines never return errors.
 	_ = s.wg.Wait()
+	runningSystems.Delete(debugInfoProvider(s))
+	for _, id := range s.busiestActorIDs {
+		busiestActorMailboxLength.DeleteLabelValues(s.name, id)
+	}
+	s.busiestActorIDs = nil
+}
+
+// collectMetrics records the mailbox lengths of actors periodically until
+// the context is canceled.
+func (s *System[T]) collectMetrics(ctx context.Context) {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		infos := s.actorInfos()
+		for _, info := range infos {
+			s.metricMailboxLength.Observe(float64(info.MailboxLength))
+		}
+		for _, id := range s.busiestActorIDs {
+			busiestActorMailboxLength.DeleteLabelValues(s.name, id)
+		}
+		s.busiestActorIDs = s.busiestActorIDs[:0]
+		for i := 0; i < len(infos) && i < busiestActorCount; i++ {
+			if infos[i].MailboxLength == 0 {
+				break
+			}
+			id := strconv.FormatUint(uint64(infos[i].ID), 10)
+			busiestActorMailboxLength.WithLabelValues(s.name, id).
+				Set(float64(infos[i].MailboxLength))
+			s.busiestActorIDs = append(s.busiestActorIDs, id)
+		}
+	}
 }
 
 // Spawn spawns an actor in the system.
@@ -490,9 +625,69 @@ func (s *System[T]) Stop() {
 func (s *System[T]) Spawn(mb Mailbox[T], actor Actor[T]) error {
 	id := mb.ID()
 	p := &proc[T]{mb: mb, actor: actor}
+	p.rd.Store(s.rd)
 	return s.router.insert(id, p)
 }
 
+// ActorIDs returns the IDs of the actors in the system.
+// ActorIDs is threadsafe.
+func (s *System[T]) ActorIDs() []ID {
+	var ids []ID
+	s.router.procs.Range(func(key, value interface{}) bool {
+		ids = append(ids, key.(ID))
+		return true
+	})
+	return ids
+}
+
+// Transfer moves an idle actor to another system, the actor is polled by the
+// workers of the other system since then. onTransferred is called after the
+// actor is added to the router of the other system and before it's removed
+// from the router of this system, so callers can update their routes without
+// losing messages.
+//
+// It returns false if the actor is being polled or has pending messages,
+// callers may try again later.
+// ErrActorNotFound when the actor is not in the system.
+// ErrActorStopped when the actor or the system is stopped.
+// Transfer is threadsafe.
+func (s *System[T]) Transfer(id ID, to *System[T], onTransferred func()) (bool, error) {
+	value, ok := s.router.procs.Load(id)
+	if !ok {
+		return false, errActorNotFound
+	}
+	p := value.(*proc[T])
+	rd := s.rd
+	rd.Lock()
+	if p.ready(rd) != rd {
+		// The actor has been transferred by others.
+		rd.Unlock()
+		return false, errActorNotFound
+	}
+	if rd.state != readyStateRunning || p.isClosed() {
+		rd.Unlock()
+		return false, errActorStopped
+	}
+	if _, busy := rd.procs[id]; busy {
+		rd.Unlock()
+		return false, nil
+	}
+	if err := to.router.insert(id, p); err != nil {
+		rd.Unlock()
+		return false, err
+	}
+	// The proc is neither polled nor pending, messages sent since now are
+	// scheduled to the other system.
+	p.rd.Store(to.rd)
+	rd.Unlock()
+
+	if onTransferred != nil {
+		onTransferred()
+	}
+	s.router.remove(id)
+	return true, nil
+}
+
 // The main poll of actor system.
 func (s *System[T]) poll(ctx context.Context, id int) {
 	batchPBuf := make([]*proc[T], s.actorBatchSize)
@@ -512,6 +707,8 @@ func (s *System[T]) poll(ctx context.Context, id int) {
 	lastRecordMetricTime := systemPollStartTime
 	procBatchCnt, systemPollLoopCnt := 0, 0
 	msgBatchCnt, actorPollLoopCnt := 0, 0
+	// The number of polls since the poll duration is sampled last time.
+	pollSampleCnt := 0
 	s.metricWorkingWorkers.Inc()
 	for {
 		// Recording batch and loop metrics.
@@ -577,8 +774,14 @@ func (s *System[T]) poll(ctx context.Context, id int) {
 			}
 			actorPollDuration := now().Sub(actorPollStartTime)
 			actorPollStartTime = approximateCurrentTime
+			p.recordPoll(actorPollDuration, approximateCurrentTime)
+			pollSampleCnt++
+			if pollSampleCnt >= pollDurationSampleInterval {
+				pollSampleCnt = 0
+				s.metricPollDuration.Observe(actorPollDuration.Seconds())
+			}
 			if actorPollDuration > slowPollThreshold {
-				// Prometheus histogram is expensive, we only record slow poll.
+				// Slow polls are recorded with finer buckets as well.
 				s.metricSlowPollDuration.Observe(actorPollDuration.Seconds())
 				if actorPollDuration > 10*slowPollThreshold { // 1s
 					log.Warn("actor poll received messages too slow",
@@ -106,6 +106,9 @@ func TestRouterSendAndSendB(t *testing.T) {
 
 	err = router.Send(id, message.ValueMessage[any](nil))
 	require.True(t, strings.Contains(err.Error(), "mailbox is full"))
+	m := &dto.Metric{}
+	require.Nil(t, router.metricDropSend.Write(m))
+	require.Equal(t, float64(1), m.Counter.GetValue())
 
 	msg, ok := mb.Receive()
 	require.Equal(t, true, ok)
@@ -126,6 +129,62 @@ func TestRouterSendAndSendB(t *testing.T) {
 	require.Equal(t, context.Canceled, <-ch)
 }
 
+func TestMailboxPriority(t *testing.T) {
+	t.Parallel()
+	mb := NewMailbox[int](ID(0), 2)
+	require.Nil(t, mb.Send(message.ValueMessage(1)))
+	require.Nil(t, mb.Send(message.ValueMessage(2)))
+	require.True(t, strings.Contains(
+		mb.Send(message.ValueMessage(3)).Error(), "mailbox is full"))
+
+	// High priority messages are not blocked by a full normal lane,
+	// and they are received first.
+	require.Nil(t, mb.SendPriority(message.ValueMessage(4), PriorityHigh))
+	require.Nil(t, mb.SendPriorityB(
+		context.Background(), message.ValueMessage(5), PriorityHigh))
+	require.True(t, strings.Contains(
+		mb.SendPriority(message.ValueMessage(6), PriorityHigh).Error(),
+		"mailbox is full"))
+	require.Equal(t, 4, mb.len())
+	for _, expected := range []int{4, 5, 1, 2} {
+		msg, ok := mb.Receive()
+		require.True(t, ok)
+		require.Equal(t, message.ValueMessage(expected), msg)
+	}
+	_, ok := mb.Receive()
+	require.False(t, ok)
+
+	mb.close()
+	require.True(t, strings.Contains(
+		mb.SendPriority(message.ValueMessage(7), PriorityHigh).Error(),
+		"actor stopped"))
+}
+
+func TestRouterSendPriority(t *testing.T) {
+	t.Parallel()
+	id := ID(0)
+	mb := NewMailbox[int](id, 1)
+	router := NewRouter[int](t.Name())
+	require.Nil(t, router.insert(id, &proc[int]{mb: mb}))
+	require.Nil(t, router.Send(id, message.ValueMessage(1)))
+	require.Nil(t, router.SendPriority(id, message.ValueMessage(2), PriorityHigh))
+
+	// Test SendPriorityB can be canceled by context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled,
+		router.SendPriorityB(ctx, id, message.ValueMessage(3), PriorityHigh))
+
+	msg, ok := mb.Receive()
+	require.True(t, ok)
+	require.Equal(t, message.ValueMessage(2), msg)
+	msg, ok = mb.Receive()
+	require.True(t, ok)
+	require.Equal(t, message.ValueMessage(1), msg)
+
+	require.NotNil(t, router.SendPriority(ID(1), message.ValueMessage(4), PriorityHigh))
+}
+
 func wait(t *testing.T, f func()) {
 	wait := make(chan int)
 	go func() {
@@ -148,6 +207,68 @@ func TestSystemStartStop(t *testing.T) {
 	sys.Stop()
 }
 
+func TestDebugInfo(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	sys, router := makeTestSystem[int](t.Name())
+	sys.Start(ctx)
+
+	// The busy actor blocks polling until the system stops.
+	busyID, idleID := ID(1), ID(2)
+	busy := &forwardActor[int]{contextAware: true, ch: make(chan message.Message[int])}
+	busyMb := NewMailbox[int](busyID, 4)
+	require.Nil(t, sys.Spawn(busyMb, busy))
+	idle := &forwardActor[int]{ch: make(chan message.Message[int], 1)}
+	require.Nil(t, sys.Spawn(NewMailbox[int](idleID, 4), idle))
+
+	require.Nil(t, router.Send(busyID, message.ValueMessage(0)))
+	require.Eventually(t, func() bool {
+		return busyMb.len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	for i := 1; i <= 3; i++ {
+		require.Nil(t, router.Send(busyID, message.ValueMessage(i)))
+	}
+
+	var info *SystemInfo
+	for _, i := range DebugInfo(0) {
+		if i.Name == t.Name() {
+			i := i
+			info = &i
+		}
+	}
+	require.NotNil(t, info)
+	require.Equal(t, 2, info.NumWorker)
+	require.Equal(t, 2, info.NumActor)
+	require.Equal(t, 3, info.NumPending)
+	require.Equal(t, busyID, info.Actors[0].ID)
+	require.Equal(t, 3, info.Actors[0].MailboxLength)
+	require.Equal(t, idleID, info.Actors[1].ID)
+	require.Equal(t, 0, info.Actors[1].MailboxLength)
+
+	for _, i := range DebugInfo(1) {
+		if i.Name == t.Name() {
+			require.Len(t, i.Actors, 1)
+			require.Equal(t, busyID, i.Actors[0].ID)
+		}
+	}
+
+	// The idle actor has been polled.
+	require.Nil(t, router.Send(idleID, message.ValueMessage(0)))
+	require.Eventually(t, func() bool {
+		for _, a := range sys.actorInfos() {
+			if a.ID == idleID {
+				return a.PollCount == 1 && !a.LastPollTime.IsZero()
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	wait(t, sys.Stop)
+	for _, i := range DebugInfo(0) {
+		require.NotEqual(t, t.Name(), i.Name)
+	}
+}
+
 func TestSystemSpawnDuplicateActor(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -679,6 +800,61 @@ func TestSendAfterMailboxClosed(t *testing.T) {
 
 // Run the benchmark
 // go test -benchmem -run='^$' -bench '^(BenchmarkActorSendReceive)$' github.com/pingcap/tiflow/pkg/actor
+func TestTransferActor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	sys1, router1 := makeTestSystem[int](t.Name() + "1")
+	sys1.Start(ctx)
+	sys2, router2 := makeTestSystem[int](t.Name() + "2")
+	sys2.Start(ctx)
+
+	id := ID(777)
+	ch := make(chan message.Message[int], 1)
+	require.Nil(t, sys1.Spawn(NewMailbox[int](id, 1), &forwardActor[int]{ch: ch}))
+	require.Nil(t, router1.Send(id, message.ValueMessage(1)))
+	require.Equal(t, message.ValueMessage(1), <-ch)
+	require.Equal(t, []ID{id}, sys1.ActorIDs())
+
+	// Transfer the idle actor.
+	transferred := false
+	ok, err := sys1.Transfer(id, sys2, func() {
+		// The actor is in both routers until the callback returns.
+		require.ElementsMatch(t, []ID{id}, sys2.ActorIDs())
+		require.ElementsMatch(t, []ID{id}, sys1.ActorIDs())
+		transferred = true
+	})
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.True(t, transferred)
+	require.Empty(t, sys1.ActorIDs())
+	require.Equal(t, errActorNotFound, router1.Send(id, message.ValueMessage(2)))
+	require.Nil(t, router2.Send(id, message.ValueMessage(3)))
+	require.Equal(t, message.ValueMessage(3), <-ch)
+
+	_, err = sys1.Transfer(id, sys2, nil)
+	require.Equal(t, errActorNotFound, err)
+
+	wait(t, sys1.Stop)
+	wait(t, sys2.Stop)
+
+	// A busy actor can not be transferred.
+	sys3, router3 := makeTestSystem[any](t.Name() + "3")
+	sys3.Start(ctx)
+	sys4, _ := makeTestSystem[any](t.Name() + "4")
+	sys4.Start(ctx)
+	slow := &slowActor{ch: make(chan struct{})}
+	require.Nil(t, sys3.Spawn(NewMailbox[any](id, 1), slow))
+	require.Nil(t, router3.Send(id, message.ValueMessage[any](nil)))
+	<-slow.ch
+	ok, err = sys3.Transfer(id, sys4, nil)
+	require.Nil(t, err)
+	require.False(t, ok)
+	slow.ch <- struct{}{}
+
+	wait(t, sys3.Stop)
+	wait(t, sys4.Stop)
+}
+
 func BenchmarkActorSendReceive(b *testing.B) {
 	ctx := context.Background()
 	sys, router := makeTestSystem[any](b.Name())
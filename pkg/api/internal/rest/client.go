@@ -66,6 +66,14 @@ type CDCRESTClient struct {
 
 	// Client is a wrapped http client.
 	Client *httputil.Client
+
+	// username and password are used for the HTTP basic authentication.
+	username string
+	password string
+
+	// maxRetries is the default retry times of GET requests, other requests
+	// are not idempotent and never retried by default.
+	maxRetries int64
 }
 
 // NewCDCRESTClient creates a new CDCRESTClient.
@@ -105,5 +113,9 @@ func (c *CDCRESTClient) Delete() *Request {
 
 // Get begins a GET request. Short for c.Method(HTTPMethodGet).
 func (c *CDCRESTClient) Get() *Request {
-	return c.Method(HTTPMethodGet)
+	req := c.Method(HTTPMethodGet)
+	if c.maxRetries > 0 {
+		req.WithMaxRetries(c.maxRetries)
+	}
+	return req
 }
@@ -17,7 +17,9 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -101,3 +103,95 @@ func TestHTTPMethods(t *testing.T) {
 	req = c.Delete()
 	require.NotNil(t, req)
 }
+
+func TestRestRequestBasicAuth(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "root" || password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	c, err := CDCRESTClientFromConfig(&Config{
+		Host:     testServer.URL,
+		APIPath:  "/api",
+		Version:  "v1",
+		Username: "root",
+		Password: "secret",
+	})
+	require.Nil(t, err)
+	require.Nil(t, c.Post().WithPrefix("test").Do(context.Background()).Error())
+
+	c, err = restClient(testServer)
+	require.Nil(t, err)
+	require.NotNil(t, c.Post().WithPrefix("test").Do(context.Background()).Error())
+}
+
+func TestRestRequestRetry(t *testing.T) {
+	var requests int32
+	statusCode := int32(http.StatusServiceUnavailable)
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.WriteHeader(int(atomic.LoadInt32(&statusCode)))
+	}))
+	defer testServer.Close()
+
+	c, err := CDCRESTClientFromConfig(&Config{
+		Host:       testServer.URL,
+		APIPath:    "/api",
+		Version:    "v1",
+		MaxRetries: 3,
+	})
+	require.Nil(t, err)
+
+	// server errors of GET requests are retried.
+	err = c.Get().WithBackoffBaseDelay(time.Millisecond).Do(context.Background()).Error()
+	require.NotNil(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// write requests are never retried by default.
+	atomic.StoreInt32(&requests, 0)
+	err = c.Post().Do(context.Background()).Error()
+	require.NotNil(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// client errors are not retried.
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&statusCode, http.StatusNotFound)
+	err = c.Get().WithBackoffBaseDelay(time.Millisecond).Do(context.Background()).Error()
+	require.NotNil(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRestRequestRetryResetsResult(t *testing.T) {
+	var requests int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		// the connection is closed before any response is written.
+		conn, _, err := rw.(http.Hijacker).Hijack()
+		require.Nil(t, err)
+		conn.Close()
+	}))
+	defer testServer.Close()
+
+	c, err := CDCRESTClientFromConfig(&Config{
+		Host:       testServer.URL,
+		APIPath:    "/api",
+		Version:    "v1",
+		MaxRetries: 2,
+	})
+	require.Nil(t, err)
+
+	// the result of the last attempt is returned rather than the response of
+	// the first one.
+	res := c.Get().WithBackoffBaseDelay(time.Millisecond).Do(context.Background())
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	require.NotNil(t, res.Error())
+	require.Zero(t, res.statusCode)
+}
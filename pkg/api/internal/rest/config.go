@@ -33,6 +33,12 @@ type Config struct {
 	Credential *security.Credential
 	// API verion
 	Version string
+	// Username and Password are used for the HTTP basic authentication,
+	// it's skipped if Username is empty.
+	Username string
+	Password string
+	// MaxRetries is the maximum times a GET request will retry by default.
+	MaxRetries int64
 }
 
 // defaultServerURLFromConfig is used to build base URL and api path.
@@ -83,6 +89,9 @@ func CDCRESTClientFromConfig(config *Config) (*CDCRESTClient, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	restClient.username = config.Username
+	restClient.password = config.Password
+	restClient.maxRetries = config.MaxRetries
 
 	return restClient, nil
 }
@@ -252,6 +252,9 @@ func (r *Request) newHTTPRequest(ctx context.Context) (*http.Request, error) {
 	}
 	req = req.WithContext(ctx)
 	req.Header = r.headers
+	if r.c.username != "" {
+		req.SetBasicAuth(r.c.username, r.c.password)
+	}
 	return req, nil
 }
 
@@ -287,6 +290,9 @@ func (r *Request) Do(ctx context.Context) (res *Result) {
 	}
 
 	fn := func() error {
+		// the result of the previous attempt must not be taken as the one of
+		// this attempt if it fails before a response is received.
+		res = nil
 		req, err := r.newHTTPRequest(ctx)
 		if err != nil {
 			return err
@@ -326,7 +332,14 @@ func (r *Request) Do(ctx context.Context) (res *Result) {
 			retry.WithBackoffBaseDelay(baseDelay),
 			retry.WithBackoffMaxDelay(maxDelay),
 			retry.WithMaxTries(maxRetries),
-			retry.WithIsRetryableErr(cerrors.IsRetryableError),
+			retry.WithIsRetryableErr(func(err error) bool {
+				// client errors like 404 won't be fixed by retrying.
+				if res != nil && res.statusCode >= http.StatusBadRequest &&
+					res.statusCode < http.StatusInternalServerError {
+					return false
+				}
+				return cerrors.IsRetryableError(err)
+			}),
 		)
 	} else {
 		err = fn()
@@ -14,8 +14,13 @@
 package v1
 
 import (
+	"context"
+
+	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
+	"go.uber.org/zap"
 )
 
 // APIV1Interface is an abstraction for TiCDC capture/changefeed/processor operations.
@@ -25,6 +30,7 @@ type APIV1Interface interface {
 	CapturesGetter
 	ChangefeedsGetter
 	ProcessorsGetter
+	OwnerGetter
 }
 
 // APIV1Client implements APIV1Interface and it is used to interact with cdc owner http api.
@@ -42,6 +48,11 @@ func (c *APIV1Client) Changefeeds() ChangefeedInterface {
 	return newChangefeeds(c)
 }
 
+// Owner returns an OwnerInterface which abstracts owner operations.
+func (c *APIV1Client) Owner() OwnerInterface {
+	return newOwner(c)
+}
+
 // Processors returns a ProcessorInterface which abstracts processor operations.
 func (c *APIV1Client) Processors() ProcessorInterface {
 	return newProcessors(c)
@@ -56,13 +67,37 @@ func (c *APIV1Client) RESTClient() rest.CDCRESTInterface {
 	return c.restClient
 }
 
+// ClientOption configures the api client.
+type ClientOption func(c *rest.Config)
+
+// WithBasicAuth makes the client authenticate with the HTTP basic authentication.
+func WithBasicAuth(username, password string) ClientOption {
+	return func(c *rest.Config) {
+		c.Username = username
+		c.Password = password
+	}
+}
+
+// WithMaxRetries sets the maximum times a read-only request will retry.
+// Write requests are not idempotent, so they are never retried.
+func WithMaxRetries(maxRetries int64) ClientOption {
+	return func(c *rest.Config) {
+		c.MaxRetries = maxRetries
+	}
+}
+
 // NewAPIClient creates a new APIV1Client.
-func NewAPIClient(ownerAddr string, credential *security.Credential) (*APIV1Client, error) {
+func NewAPIClient(
+	ownerAddr string, credential *security.Credential, opts ...ClientOption,
+) (*APIV1Client, error) {
 	c := &rest.Config{}
 	c.APIPath = "/api"
 	c.Version = "v1"
 	c.Host = ownerAddr
 	c.Credential = credential
+	for _, opt := range opts {
+		opt(c)
+	}
 	client, err := rest.CDCRESTClientFromConfig(c)
 	if err != nil {
 		return nil, err
@@ -70,3 +105,34 @@ func NewAPIClient(ownerAddr string, credential *security.Credential) (*APIV1Clie
 
 	return &APIV1Client{client}, nil
 }
+
+// NewOwnerAPIClient finds the owner from the given server addresses and
+// creates an APIV1Client connected to the owner directly. Every capture
+// forwards the requests to the owner, but connecting to the owner saves
+// the forwarding and keeps working when the forwarding capture is down.
+func NewOwnerAPIClient(
+	ctx context.Context, addrs []string, credential *security.Credential, opts ...ClientOption,
+) (*APIV1Client, error) {
+	var lastErr error
+	for _, addr := range addrs {
+		client, err := NewAPIClient(addr, credential, opts...)
+		if err != nil {
+			return nil, err
+		}
+		captures, err := client.Captures().List(ctx)
+		if err != nil {
+			log.Warn("failed to list captures", zap.String("addr", addr), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		for _, capture := range *captures {
+			if capture.IsOwner {
+				return NewAPIClient(capture.AdvertiseAddr, credential, opts...)
+			}
+		}
+	}
+	if lastErr != nil {
+		return nil, cerror.ErrOwnerNotFound.Wrap(lastErr)
+	}
+	return nil, cerror.ErrOwnerNotFound.GenWithStackByArgs()
+}
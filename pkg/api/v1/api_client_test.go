@@ -0,0 +1,54 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOwnerAPIClient(t *testing.T) {
+	t.Parallel()
+
+	owner := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusAccepted)
+	}))
+	defer owner.Close()
+	ownerAddr := strings.TrimPrefix(owner.URL, "http://")
+
+	capture := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/captures", r.URL.Path)
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(fmt.Sprintf(`[
+			{"id": "capture-1", "is_owner": false, "address": "127.0.0.1:1"},
+			{"id": "capture-2", "is_owner": true, "address": "%s"}
+		]`, ownerAddr)))
+	}))
+	defer capture.Close()
+
+	ctx := context.Background()
+	// the unreachable address is skipped.
+	client, err := NewOwnerAPIClient(ctx, []string{"127.0.0.1:1", capture.URL}, nil)
+	require.Nil(t, err)
+	require.Nil(t, client.Owner().Resign(ctx))
+
+	_, err = NewOwnerAPIClient(ctx, []string{"127.0.0.1:1"}, nil)
+	require.Regexp(t, "owner not found", err)
+}
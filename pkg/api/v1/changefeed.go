@@ -31,6 +31,11 @@ type ChangefeedsGetter interface {
 type ChangefeedInterface interface {
 	Get(ctx context.Context, name string) (*model.ChangefeedDetail, error)
 	List(ctx context.Context) (*[]model.ChangeFeedInfo, error)
+	Create(ctx context.Context, config *model.ChangefeedConfig) error
+	Update(ctx context.Context, name string, config *model.ChangefeedConfig) error
+	Pause(ctx context.Context, name string) error
+	Resume(ctx context.Context, name string) error
+	Remove(ctx context.Context, name string) error
 }
 
 // changefeeds implements ChangefeedInterface
@@ -66,3 +71,49 @@ func (c *changefeeds) List(ctx context.Context) (*[]model.ChangeFeedInfo, error)
 		Into(result)
 	return result, err
 }
+
+// Create creates a changefeed.
+func (c *changefeeds) Create(ctx context.Context, config *model.ChangefeedConfig) error {
+	return c.client.Post().
+		WithURI("changefeeds").
+		WithBody(config).
+		Do(ctx).
+		Error()
+}
+
+// Update updates the config of a stopped changefeed.
+func (c *changefeeds) Update(ctx context.Context, name string, config *model.ChangefeedConfig) error {
+	u := fmt.Sprintf("changefeeds/%s", name)
+	return c.client.Put().
+		WithURI(u).
+		WithBody(config).
+		Do(ctx).
+		Error()
+}
+
+// Pause pauses a changefeed.
+func (c *changefeeds) Pause(ctx context.Context, name string) error {
+	u := fmt.Sprintf("changefeeds/%s/pause", name)
+	return c.client.Post().
+		WithURI(u).
+		Do(ctx).
+		Error()
+}
+
+// Resume resumes a changefeed.
+func (c *changefeeds) Resume(ctx context.Context, name string) error {
+	u := fmt.Sprintf("changefeeds/%s/resume", name)
+	return c.client.Post().
+		WithURI(u).
+		Do(ctx).
+		Error()
+}
+
+// Remove removes a changefeed.
+func (c *changefeeds) Remove(ctx context.Context, name string) error {
+	u := fmt.Sprintf("changefeeds/%s", name)
+	return c.client.Delete().
+		WithURI(u).
+		Do(ctx).
+		Error()
+}
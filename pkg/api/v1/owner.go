@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// OwnerGetter has a method to return a OwnerInterface.
+type OwnerGetter interface {
+	Owner() OwnerInterface
+}
+
+// OwnerInterface has methods to work with the owner.
+// We can also mock the owner operations by implement this interface.
+type OwnerInterface interface {
+	Resign(ctx context.Context) error
+}
+
+// owner implements OwnerInterface
+type owner struct {
+	client rest.CDCRESTInterface
+}
+
+// newOwner returns owner
+func newOwner(c *APIV1Client) *owner {
+	return &owner{
+		client: c.RESTClient(),
+	}
+}
+
+// Resign makes the current owner resign, a new owner will be elected.
+func (c *owner) Resign(ctx context.Context) error {
+	return c.client.Post().
+		WithURI("owner/resign").
+		Do(ctx).
+		Error()
+}
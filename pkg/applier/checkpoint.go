@@ -0,0 +1,61 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"encoding/json"
+	"os"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// applyCheckpoint is the progress of applying the redo logs, it's persisted
+// to the checkpoint file, so an interrupted apply resumes from it rather than
+// the checkpoint ts of the redo logs.
+type applyCheckpoint struct {
+	// AppliedTs is the ts all the logs before which have been applied.
+	AppliedTs uint64 `json:"applied-ts"`
+	// ResolvedTs is the resolved ts of the redo logs being applied.
+	ResolvedTs uint64 `json:"resolved-ts"`
+}
+
+// loadApplyCheckpoint reads the checkpoint file, it returns nil if the file
+// doesn't exist.
+func loadApplyCheckpoint(path string) (*applyCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	cp := &applyCheckpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	return cp, nil
+}
+
+// saveApplyCheckpoint writes the checkpoint file atomically.
+func saveApplyCheckpoint(path string, cp *applyCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	return cerror.WrapError(cerror.ErrRedoFileOp, os.Rename(tmpPath, path))
+}
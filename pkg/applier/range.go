@@ -0,0 +1,161 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/redo"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// RedoLogRange is the range of ts the redo logs in a storage can be applied
+// in, a downstream restored from a snapshot at any ts in [StartTs, ResolvedTs]
+// can be brought to any later ts not after ResolvedTs by applying them.
+type RedoLogRange struct {
+	Storage string
+	// StartTs is the smallest ts the logs can be applied from. It's before the
+	// checkpoint ts if the logs passed by the checkpoint are retained.
+	StartTs      uint64
+	CheckpointTs uint64
+	ResolvedTs   uint64
+}
+
+// Covers returns whether the logs after startTs and not after endTs are all in
+// the range.
+func (r *RedoLogRange) Covers(startTs, endTs uint64) bool {
+	return r.StartTs <= startTs && startTs <= endTs && endTs <= r.ResolvedTs
+}
+
+// ReadRange reads the range the redo logs can be applied in.
+func (ra *RedoApplier) ReadRange(ctx context.Context) (*RedoLogRange, error) {
+	checkpointTs, resolvedTs, err := ra.ReadMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	startTs := checkpointTs
+	earliestTs, gcTs, ok, err := scanLogTs(ctx, ra.cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if ok && earliestTs < startTs {
+		startTs = earliestTs
+	}
+	// the logs not after the gc ts may have been removed, even if some files
+	// before it are retained.
+	if gcTs > startTs {
+		startTs = gcTs
+	}
+	return &RedoLogRange{
+		Storage:      ra.cfg.Storage,
+		StartTs:      startTs,
+		CheckpointTs: checkpointTs,
+		ResolvedTs:   resolvedTs,
+	}, nil
+}
+
+// scanLogTs returns the smallest commit ts of the log files in the storage,
+// and the largest gc ts recorded in the metas of the captures, the logs not
+// after it may have been removed. It returns false if there is no log file.
+func scanLogTs(ctx context.Context, storageURI string) (earliestTs, gcTs uint64, found bool, err error) {
+	uri, err := url.Parse(storageURI)
+	if err != nil {
+		return 0, 0, false, cerror.WrapError(cerror.ErrConsistentStorage, err)
+	}
+
+	var metaFiles []string
+	add := func(name string) {
+		if filepath.Ext(name) == common.MetaEXT {
+			metaFiles = append(metaFiles, name)
+			return
+		}
+		if filepath.Ext(name) != common.LogEXT {
+			return
+		}
+		commitTs, _, err := common.ParseLogFileName(filepath.Base(name))
+		if err != nil {
+			return
+		}
+		if !found || commitTs < earliestTs {
+			earliestTs = commitTs
+			found = true
+		}
+	}
+
+	addMeta := func(data []byte) error {
+		meta := &common.LogMeta{}
+		if _, err := meta.UnmarshalMsg(data); err != nil {
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+		if meta.GCTs > gcTs {
+			gcTs = meta.GCTs
+		}
+		return nil
+	}
+
+	if redo.IsExternalStorageEnabled(uri.Scheme) {
+		extStorage, err := common.InitExternalStorage(ctx, *uri)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		err = extStorage.WalkDir(ctx, &storage.WalkOption{}, func(path string, size int64) error {
+			add(path)
+			return nil
+		})
+		if err != nil {
+			return 0, 0, false, cerror.WrapError(cerror.ErrS3StorageAPI, err)
+		}
+		for _, name := range metaFiles {
+			data, err := extStorage.ReadFile(ctx, name)
+			if err != nil {
+				return 0, 0, false, cerror.WrapError(cerror.ErrS3StorageAPI, err)
+			}
+			if err := addMeta(data); err != nil {
+				return 0, 0, false, err
+			}
+		}
+		return earliestTs, gcTs, found, nil
+	}
+
+	infos, err := ioutil.ReadDir(uri.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, false, nil
+		}
+		return 0, 0, false, cerror.WrapError(cerror.ErrRedoFileOp,
+			errors.Annotatef(err, "can't read log file directory: %s", uri.Path))
+	}
+	for _, info := range infos {
+		if !info.IsDir() {
+			add(info.Name())
+		}
+	}
+	for _, name := range metaFiles {
+		data, err := os.ReadFile(filepath.Join(uri.Path, name))
+		if err != nil {
+			return 0, 0, false, cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+		if err := addMeta(data); err != nil {
+			return 0, 0, false, err
+		}
+	}
+	return earliestTs, gcTs, found, nil
+}
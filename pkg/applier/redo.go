@@ -15,6 +15,7 @@ package applier
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 
 	"github.com/pingcap/errors"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo/reader"
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -44,9 +46,40 @@ type RedoApplierConfig struct {
 	SinkURI string
 	Storage string
 	Dir     string
+	// DataEncryption is the encryption of the redo logs, it should be the
+	// one of the captures writing them.
+	DataEncryption *config.DataEncryptionConfig
+	// WorkerCount is the number of workers applying the redo logs in
+	// parallel, each of which has a sink of its own. The logs of a table are
+	// always applied by the same worker. Defaults to 1.
+	WorkerCount int
+	// CheckpointFile is the file the progress of the apply is saved to, the
+	// apply resumes from the progress in it if it exists. The progress isn't
+	// saved if it's empty.
+	CheckpointFile string
+	// OnProgress is called with the ts all the logs before which have been
+	// applied, each time the ts is advanced.
+	OnProgress func(appliedTs uint64)
+	// ReconstructSchema creates the tables in the latest schema snapshot of
+	// the redo logs before the apply starts, and executes the DDLs in the
+	// redo logs after the snapshot in commit ts order, so the redo logs can be applied to a
+	// downstream without the tables.
+	ReconstructSchema bool
+	// TargetTs is the ts the redo logs are applied to, they are applied to
+	// the resolved ts of them if it's 0 or after the resolved ts.
+	TargetTs uint64
+	// StartTs is the ts the redo logs are applied from, e.g. the ts of the
+	// backup the downstream is restored from. The logs before the checkpoint
+	// ts are only available if they're retained, see ReadRange. They are
+	// applied from the checkpoint ts of them if it's 0.
+	StartTs uint64
+	// ExecuteDDLs executes the DDLs in the redo logs in commit ts order,
+	// it's implied by ReconstructSchema.
+	ExecuteDDLs bool
 }
 
-// RedoApplier implements a redo log applier
+// RedoApplier implements a redo log applier, it can be embedded to apply redo
+// logs to a downstream without the cdc binary.
 type RedoApplier struct {
 	cfg *RedoApplierConfig
 
@@ -69,12 +102,12 @@ func (rac *RedoApplierConfig) toLogReaderConfig() (string, *reader.LogReaderConf
 		return "", nil, cerror.WrapError(cerror.ErrConsistentStorage, err)
 	}
 	cfg := &reader.LogReaderConfig{
-		Dir:       uri.Path,
-		S3Storage: redo.IsS3StorageEnabled(uri.Scheme),
+		Dir:                uri.Path,
+		UseExternalStorage: redo.IsExternalStorageEnabled(uri.Scheme),
 	}
-	if cfg.S3Storage {
-		cfg.S3URI = *uri
-		// If use s3 as backend, applier will download redo logs to local dir.
+	if cfg.UseExternalStorage {
+		cfg.URI = *uri
+		// If use external storage as backend, applier will download redo logs to local dir.
 		cfg.Dir = rac.Dir
 	}
 	return uri.Scheme, cfg, nil
@@ -96,12 +129,70 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	err = ra.rd.ResetReader(ctx, checkpointTs, resolvedTs)
+	if ra.cfg.TargetTs != 0 && ra.cfg.TargetTs < resolvedTs {
+		resolvedTs = ra.cfg.TargetTs
+	}
+	startTs, err := ra.loadProgress(checkpointTs, resolvedTs)
+	if err != nil {
+		return err
+	}
+	if startTs >= resolvedTs {
+		log.Info("redo logs have been applied", zap.Uint64("resolvedTs", resolvedTs))
+		ra.rd.Close() //nolint:errcheck
+		return errApplyFinished
+	}
+	err = ra.rd.ResetReader(ctx, startTs, resolvedTs)
 	if err != nil {
 		return err
 	}
-	log.Info("apply redo log starts", zap.Uint64("checkpointTs", checkpointTs), zap.Uint64("resolvedTs", resolvedTs))
+	log.Info("apply redo log starts", zap.Uint64("checkpointTs", checkpointTs),
+		zap.Uint64("startTs", startTs), zap.Uint64("resolvedTs", resolvedTs))
 
+	workers, err := ra.newWorkers(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ra.rd.Close() //nolint:errcheck
+		for _, w := range workers {
+			w.sink.Close(ctx) //nolint:errcheck
+		}
+	}()
+
+	var ddls []*model.DDLEvent
+	// the schema has been reconstructed if the apply is resumed from the
+	// checkpoint file, since no progress is saved before it.
+	if ra.cfg.ReconstructSchema && !ra.resumed(checkpointTs, startTs) {
+		ddls, err = ra.reconstructSchema(ctx, workers[0].sink, startTs, resolvedTs)
+		if err != nil {
+			return err
+		}
+	} else if ra.cfg.ReconstructSchema || ra.cfg.ExecuteDDLs {
+		ddls, err = readDDLs(ctx, ra.rd)
+		if err != nil {
+			return err
+		}
+	}
+
+	wg, wctx := errgroup.WithContext(ctx)
+	for _, w := range workers {
+		w := w
+		wg.Go(func() error {
+			return w.run(wctx)
+		})
+	}
+	err = ra.dispatchLogs(wctx, workers, ddls, startTs, resolvedTs)
+	for _, w := range workers {
+		close(w.taskCh)
+	}
+	// the error of a worker cancels the dispatching, so it takes precedence
+	if werr := wg.Wait(); werr != nil {
+		return werr
+	}
+	return err
+}
+
+func (ra *RedoApplier) newWorkers(ctx context.Context) ([]*applyWorker, error) {
 	// MySQL sink will use the following replication config
 	// - EnableOldValue: default true
 	// - ForceReplicate: default false
@@ -109,29 +200,93 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	replicaConfig := config.GetDefaultReplicaConfig()
 	ft, err := filter.NewFilter(replicaConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	opts := map[string]string{}
 	ctx = util.PutRoleInCtx(ctx, util.RoleRedoLogApplier)
-	s, err := sink.New(ctx, applierChangefeed, ra.cfg.SinkURI, ft, replicaConfig, opts, ra.errCh)
+
+	workerCount := ra.cfg.WorkerCount
+	if workerCount <= 0 {
+		workerCount = 1
+	}
+	workers := make([]*applyWorker, 0, workerCount)
+	for i := 0; i < workerCount; i++ {
+		changefeedID := applierChangefeed
+		if i > 0 {
+			changefeedID = fmt.Sprintf("%s-%d", applierChangefeed, i)
+		}
+		s, err := sink.New(ctx, changefeedID, ra.cfg.SinkURI, ft, replicaConfig, map[string]string{}, ra.errCh)
+		if err != nil {
+			for _, w := range workers {
+				w.sink.Close(ctx) //nolint:errcheck
+			}
+			return nil, err
+		}
+		workers = append(workers, newApplyWorker(s))
+	}
+	return workers, nil
+}
+
+// reconstructSchema creates the tables in the latest schema snapshot not
+// after startTs, executes the DDLs between the snapshot and startTs, and
+// returns the DDLs after startTs to be executed. The snapshots are rate
+// limited, so the snapshot can be older than the schema at startTs.
+func (ra *RedoApplier) reconstructSchema(
+	ctx context.Context, s sink.Sink, startTs, resolvedTs uint64,
+) ([]*model.DDLEvent, error) {
+	snap, err := ra.rd.ReadSchemaSnapshot(ctx, startTs)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		ra.rd.Close() //nolint:errcheck
-		s.Close(ctx)  //nolint:errcheck
-	}()
+	if snap == nil {
+		log.Warn("no redo schema snapshot before the start ts, tables are not created",
+			zap.Uint64("startTs", startTs))
+		return readDDLs(ctx, ra.rd)
+	}
+	if err := createTables(ctx, s, snap); err != nil {
+		return nil, err
+	}
+	if snap.Ts < startTs {
+		if err := ra.rd.ResetReader(ctx, snap.Ts, startTs); err != nil {
+			return nil, err
+		}
+		ddls, err := readDDLs(ctx, ra.rd)
+		if err != nil {
+			return nil, err
+		}
+		for _, ddl := range ddls {
+			if err := execDDL(ctx, s, ddl); err != nil {
+				return nil, err
+			}
+		}
+		if err := ra.rd.ResetReader(ctx, startTs, resolvedTs); err != nil {
+			return nil, err
+		}
+		log.Info("DDLs after redo schema snapshot executed",
+			zap.Uint64("snapshotTs", snap.Ts), zap.Uint64("startTs", startTs),
+			zap.Int("ddls", len(ddls)))
+	}
+	return readDDLs(ctx, ra.rd)
+}
 
+// dispatchLogs reads the redo logs and dispatches them to the workers by
+// table. The workers are flushed after each batch of logs read, to the ts all
+// the logs before which have been read, so the events in one transaction are
+// always flushed together. The DDLs are executed once all the logs before
+// them are flushed to the downstream.
+func (ra *RedoApplier) dispatchLogs(
+	ctx context.Context, workers []*applyWorker, ddls []*model.DDLEvent, startTs, resolvedTs uint64,
+) error {
 	// TODO: split events for large transaction
-	// We use lastSafeResolvedTs and lastResolvedTs to ensure the events in one
-	// transaction are flushed in a single batch.
-	// lastSafeResolvedTs records the max resolved ts of a closed transaction.
-	// Closed transaction means all events of this transaction have been received.
-	lastSafeResolvedTs := checkpointTs - 1
-	// lastResolvedTs records the max resolved ts we have seen from redo logs.
-	lastResolvedTs := checkpointTs
-	cachedRows := make([]*model.RowChangedEvent, 0, emitBatch)
-	tableResolvedTsMap := make(map[model.TableID]model.Ts)
+	// lastResolvedTs records the max commit ts we have seen from redo logs,
+	// all the logs before it have been read since the logs are read in commit
+	// ts order.
+	lastResolvedTs := startTs
+	// appliedTs is the ts the workers have been asked to flush to, and
+	// savedTs is the one they have actually flushed to, which is saved as
+	// the progress.
+	appliedTs := startTs
+	savedTs := startTs
+	tableWorkers := make(map[model.TableID]*applyWorker)
 	for {
 		redoLogs, err := ra.rd.ReadNextLog(ctx, readBatch)
 		if err != nil {
@@ -141,48 +296,183 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 			break
 		}
 
+		rows := make(map[*applyWorker][]*model.RowChangedEvent, len(workers))
 		for _, redoLog := range redoLogs {
-			tableID := redoLog.Row.Table.TableID
-			if _, ok := tableResolvedTsMap[redoLog.Row.Table.TableID]; !ok {
-				tableResolvedTsMap[tableID] = lastSafeResolvedTs
-			}
-			if len(cachedRows) >= emitBatch {
-				err := s.EmitRowChangedEvents(ctx, cachedRows...)
+			for len(ddls) > 0 && redoLog.Row.CommitTs > ddls[0].CommitTs {
+				err := ra.applyDDL(ctx, workers, rows, ddls[0], resolvedTs)
 				if err != nil {
 					return err
 				}
-				cachedRows = make([]*model.RowChangedEvent, 0, emitBatch)
+				rows = make(map[*applyWorker][]*model.RowChangedEvent, len(workers))
+				appliedTs = ddls[0].CommitTs
+				savedTs = appliedTs
+				if appliedTs > lastResolvedTs {
+					lastResolvedTs = appliedTs
+				}
+				ddls = ddls[1:]
 			}
-			cachedRows = append(cachedRows, redo.LogToRow(redoLog))
-
-			if redoLog.Row.CommitTs > tableResolvedTsMap[tableID] {
-				tableResolvedTsMap[tableID], lastResolvedTs = lastResolvedTs, redoLog.Row.CommitTs
+			tableID := redoLog.Row.Table.TableID
+			w, ok := tableWorkers[tableID]
+			if !ok {
+				// the tables are assigned to the workers in turn
+				w = workers[len(tableWorkers)%len(workers)]
+				tableWorkers[tableID] = w
+			}
+			rows[w] = append(rows[w], redo.LogToRow(redoLog))
+			if redoLog.Row.CommitTs > lastResolvedTs {
+				lastResolvedTs = redoLog.Row.CommitTs
 			}
 		}
 
-		for tableID, tableLastResolvedTs := range tableResolvedTsMap {
-			_, err = s.FlushRowChangedEvents(ctx, tableID, tableLastResolvedTs)
-			if err != nil {
+		// the transaction of lastResolvedTs may not be read entirely
+		if lastResolvedTs-1 <= appliedTs {
+			for w, rs := range rows {
+				if err := sendApplyTask(ctx, w, applyTask{rows: rs}); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		flushedTs, err := ra.flushWorkers(ctx, workers, rows, lastResolvedTs-1, false)
+		if err != nil {
+			return err
+		}
+		appliedTs = lastResolvedTs - 1
+		// the sinks flush asynchronously, so only the ts all the workers
+		// have flushed to is saved.
+		if flushedTs > savedTs {
+			savedTs = flushedTs
+			if err := ra.saveProgress(savedTs, resolvedTs); err != nil {
 				return err
 			}
 		}
 	}
-	err = s.EmitRowChangedEvents(ctx, cachedRows...)
-	if err != nil {
+
+	for _, ddl := range ddls {
+		if err := ra.applyDDL(ctx, workers, nil, ddl, resolvedTs); err != nil {
+			return err
+		}
+	}
+	if _, err := ra.flushWorkers(ctx, workers, nil, resolvedTs, true); err != nil {
+		return err
+	}
+	if err := ra.saveProgress(resolvedTs, resolvedTs); err != nil {
 		return err
 	}
+	return errApplyFinished
+}
 
-	for tableID := range tableResolvedTsMap {
-		_, err = s.FlushRowChangedEvents(ctx, tableID, resolvedTs)
-		if err != nil {
-			return err
+// applyDDL flushes all the logs before the DDL to the downstream, and then
+// executes the DDL, the apply resumes after the DDL once it's executed.
+func (ra *RedoApplier) applyDDL(
+	ctx context.Context, workers []*applyWorker,
+	rows map[*applyWorker][]*model.RowChangedEvent, ddl *model.DDLEvent, resolvedTs uint64,
+) error {
+	if _, err := ra.flushWorkers(ctx, workers, rows, ddl.CommitTs-1, true); err != nil {
+		return err
+	}
+	if err := execDDL(ctx, workers[0].sink, ddl); err != nil {
+		return err
+	}
+	log.Info("DDL in redo logs executed",
+		zap.String("query", ddl.Query), zap.Uint64("commitTs", ddl.CommitTs))
+	return ra.saveProgress(ddl.CommitTs, resolvedTs)
+}
+
+// flushWorkers sends the rows to the workers, and asks all the workers to
+// flush their tables to the resolved ts. It returns the smallest ts the
+// workers have flushed to, which is the resolved ts if barrier is true, since
+// the workers wait until all the events are written to the downstream then.
+func (ra *RedoApplier) flushWorkers(
+	ctx context.Context, workers []*applyWorker,
+	rows map[*applyWorker][]*model.RowChangedEvent, resolvedTs uint64, barrier bool,
+) (uint64, error) {
+	dones := make([]chan applyResult, 0, len(workers))
+	for _, w := range workers {
+		done := make(chan applyResult, 1)
+		task := applyTask{rows: rows[w], resolvedTs: resolvedTs, barrier: barrier, done: done}
+		if err := sendApplyTask(ctx, w, task); err != nil {
+			return 0, err
+		}
+		dones = append(dones, done)
+	}
+	checkpointTs := resolvedTs
+	for _, done := range dones {
+		select {
+		case <-ctx.Done():
+			return 0, errors.Trace(ctx.Err())
+		case res := <-done:
+			if res.err != nil {
+				return 0, res.err
+			}
+			if res.checkpointTs < checkpointTs {
+				checkpointTs = res.checkpointTs
+			}
 		}
-		err = s.Barrier(ctx, tableID)
+	}
+	return checkpointTs, nil
+}
+
+func sendApplyTask(ctx context.Context, w *applyWorker, task applyTask) error {
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case w.taskCh <- task:
+		return nil
+	}
+}
+
+// resumed returns whether the apply is resumed from the checkpoint file, i.e.
+// startTs is after the ts the logs are applied from without it.
+func (ra *RedoApplier) resumed(checkpointTs, startTs uint64) bool {
+	if ra.cfg.StartTs != 0 {
+		return startTs > ra.cfg.StartTs
+	}
+	return startTs > checkpointTs
+}
+
+// loadProgress returns the ts the apply starts from, it's the applied ts in
+// the checkpoint file if it's ahead of the start ts, which is the checkpoint
+// ts of the redo logs unless it's set in the config.
+func (ra *RedoApplier) loadProgress(checkpointTs, resolvedTs uint64) (uint64, error) {
+	startTs := checkpointTs
+	if ra.cfg.StartTs != 0 {
+		startTs = ra.cfg.StartTs
+	}
+	if ra.cfg.CheckpointFile == "" {
+		return startTs, nil
+	}
+	cp, err := loadApplyCheckpoint(ra.cfg.CheckpointFile)
+	if err != nil {
+		return 0, err
+	}
+	if cp == nil || cp.AppliedTs <= startTs {
+		return startTs, nil
+	}
+	if cp.AppliedTs > resolvedTs {
+		return 0, cerror.ErrRedoConfigInvalid.GenWithStack(
+			"applied ts %d in checkpoint file %s is ahead of the resolved ts %d of redo logs",
+			cp.AppliedTs, ra.cfg.CheckpointFile, resolvedTs)
+	}
+	log.Info("resume applying redo logs from checkpoint file",
+		zap.String("file", ra.cfg.CheckpointFile), zap.Uint64("appliedTs", cp.AppliedTs))
+	return cp.AppliedTs, nil
+}
+
+func (ra *RedoApplier) saveProgress(appliedTs, resolvedTs uint64) error {
+	if ra.cfg.CheckpointFile != "" {
+		err := saveApplyCheckpoint(ra.cfg.CheckpointFile, &applyCheckpoint{
+			AppliedTs:  appliedTs,
+			ResolvedTs: resolvedTs,
+		})
 		if err != nil {
 			return err
 		}
 	}
-	return errApplyFinished
+	if ra.cfg.OnProgress != nil {
+		ra.cfg.OnProgress(appliedTs)
+	}
+	return nil
 }
 
 var createRedoReader = createRedoReaderImpl
@@ -192,6 +482,10 @@ func createRedoReaderImpl(ctx context.Context, cfg *RedoApplierConfig) (reader.R
 	if err != nil {
 		return nil, err
 	}
+	readerCfg.Cipher, err = encryption.LoadCipher(ctx, cfg.DataEncryption)
+	if err != nil {
+		return nil, err
+	}
 	return redo.NewRedoReader(ctx, storageType, readerCfg)
 }
 
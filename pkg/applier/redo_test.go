@@ -17,10 +17,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/phayes/freeport"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/redo/reader"
@@ -32,8 +36,17 @@ import (
 type MockReader struct {
 	checkpointTs uint64
 	resolvedTs   uint64
+	startTs      uint64
+	endTs        uint64
 	redoLogCh    chan *model.RedoRowChangedEvent
 	ddlEventCh   chan *model.RedoDDLEvent
+	// ddls are the DDLs read from ddlEventCh, they are filtered by the range
+	// the reader is reset to and read from ddlIdx.
+	ddls   []*model.RedoDDLEvent
+	ddlIdx int
+	// schemaSnapshot is returned by ReadSchemaSnapshot if it's not after the
+	// ts to read.
+	schemaSnapshot *model.RedoSchemaSnapshot
 }
 
 // NewMockReader creates a new MockReader
@@ -53,6 +66,9 @@ func NewMockReader(
 
 // ResetReader implements LogReader.ReadLog
 func (br *MockReader) ResetReader(ctx context.Context, startTs, endTs uint64) error {
+	br.startTs = startTs
+	br.endTs = endTs
+	br.ddlIdx = 0
 	return nil
 }
 
@@ -77,21 +93,20 @@ func (br *MockReader) ReadNextLog(ctx context.Context, maxNumberOfMessages uint6
 
 // ReadNextDDL implements LogReader.ReadNextDDL
 func (br *MockReader) ReadNextDDL(ctx context.Context, maxNumberOfDDLs uint64) ([]*model.RedoDDLEvent, error) {
+	if br.ddls == nil {
+		br.ddls = make([]*model.RedoDDLEvent, 0)
+		for ddl := range br.ddlEventCh {
+			br.ddls = append(br.ddls, ddl)
+		}
+	}
 	cached := make([]*model.RedoDDLEvent, 0)
-	for {
-		select {
-		case <-ctx.Done():
-			return cached, nil
-		case ddl, ok := <-br.ddlEventCh:
-			if !ok {
-				return cached, nil
-			}
+	for ; br.ddlIdx < len(br.ddls) && len(cached) < int(maxNumberOfDDLs); br.ddlIdx++ {
+		ddl := br.ddls[br.ddlIdx]
+		if ddl.DDL.CommitTs > br.startTs && ddl.DDL.CommitTs <= br.endTs {
 			cached = append(cached, ddl)
-			if len(cached) >= int(maxNumberOfDDLs) {
-				return cached, nil
-			}
 		}
 	}
+	return cached, nil
 }
 
 // ReadMeta implements LogReader.ReadMeta
@@ -99,6 +114,14 @@ func (br *MockReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs ui
 	return br.checkpointTs, br.resolvedTs, nil
 }
 
+// ReadSchemaSnapshot implements LogReader.ReadSchemaSnapshot
+func (br *MockReader) ReadSchemaSnapshot(ctx context.Context, ts uint64) (*model.RedoSchemaSnapshot, error) {
+	if br.schemaSnapshot == nil || br.schemaSnapshot.Ts > ts {
+		return nil, nil
+	}
+	return br.schemaSnapshot, nil
+}
+
 // Close implements LogReader.Close.
 func (br *MockReader) Close() error {
 	return nil
@@ -255,3 +278,199 @@ func TestApplyMeetSinkError(t *testing.T) {
 	err = ap.Apply(ctx)
 	require.Regexp(t, "CDC:ErrMySQLConnectionError", err)
 }
+
+func TestApplyInParallelWithCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	var rd *MockReader
+	newReader := func(rows ...*model.RowChangedEvent) {
+		redoLogCh := make(chan *model.RedoRowChangedEvent, 1024)
+		ddlEventCh := make(chan *model.RedoDDLEvent, 1024)
+		for _, row := range rows {
+			redoLogCh <- redo.RowToRedo(row)
+		}
+		close(redoLogCh)
+		close(ddlEventCh)
+		rd = NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh)
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return rd, nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	var progress []uint64
+	cfg := &RedoApplierConfig{
+		SinkURI:        "blackhole://",
+		WorkerCount:    2,
+		CheckpointFile: filepath.Join(t.TempDir(), "apply-checkpoint"),
+		OnProgress: func(appliedTs uint64) {
+			progress = append(progress, appliedTs)
+		},
+	}
+	var rows []*model.RowChangedEvent
+	for i := 0; i < 6; i++ {
+		rows = append(rows, &model.RowChangedEvent{
+			StartTs:  uint64(1100 + i*100),
+			CommitTs: uint64(1150 + i*100),
+			Table:    &model.TableName{Schema: "test", Table: fmt.Sprintf("t%d", i%3), TableID: int64(i % 3)},
+			Columns:  []*model.Column{{Name: "a", Value: i, Flag: model.HandleKeyFlag}},
+		})
+	}
+
+	// the logs read are flushed to the ts before the last transaction, which
+	// may not be read entirely, and then to the resolved ts.
+	newReader(rows...)
+	require.Nil(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Equal(t, checkpointTs, rd.startTs)
+	require.Equal(t, []uint64{1649, resolvedTs}, progress)
+	cp, err := loadApplyCheckpoint(cfg.CheckpointFile)
+	require.Nil(t, err)
+	require.Equal(t, &applyCheckpoint{AppliedTs: resolvedTs, ResolvedTs: resolvedTs}, cp)
+
+	// nothing is applied again once the redo logs have been applied.
+	progress = nil
+	newReader(rows...)
+	require.Nil(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Empty(t, progress)
+
+	// the apply resumes from the applied ts in the checkpoint file.
+	require.Nil(t, saveApplyCheckpoint(cfg.CheckpointFile, &applyCheckpoint{AppliedTs: 1449, ResolvedTs: resolvedTs}))
+	newReader(rows[4:]...)
+	require.Nil(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Equal(t, uint64(1449), rd.startTs)
+	require.Equal(t, []uint64{1649, resolvedTs}, progress)
+
+	// the checkpoint file ahead of the redo logs is invalid.
+	require.Nil(t, saveApplyCheckpoint(cfg.CheckpointFile, &applyCheckpoint{AppliedTs: 3000, ResolvedTs: 3000}))
+	newReader(rows...)
+	require.Regexp(t, "ErrRedoConfigInvalid", NewRedoApplier(cfg).Apply(ctx))
+}
+
+func TestApplyWithReconstructSchema(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	redoLogCh := make(chan *model.RedoRowChangedEvent, 1024)
+	ddlEventCh := make(chan *model.RedoDDLEvent, 1024)
+	for i := 0; i < 6; i++ {
+		redoLogCh <- redo.RowToRedo(&model.RowChangedEvent{
+			StartTs:  uint64(1100 + i*100),
+			CommitTs: uint64(1150 + i*100),
+			Table:    &model.TableName{Schema: "test", Table: "t1", TableID: 1},
+			Columns:  []*model.Column{{Name: "a", Value: i, Flag: model.HandleKeyFlag}},
+		})
+	}
+	close(redoLogCh)
+	// the DDL after the snapshot and before the checkpoint is executed
+	// before the apply starts.
+	ddlEventCh <- redo.DDLToRedo(&model.DDLEvent{
+		StartTs:   940,
+		CommitTs:  950,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+		Query:     "alter table t1 add column c int",
+		Type:      timodel.ActionAddColumn,
+	})
+	ddlEventCh <- redo.DDLToRedo(&model.DDLEvent{
+		StartTs:   1290,
+		CommitTs:  1300,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+		Query:     "alter table t1 add column b int",
+		Type:      timodel.ActionAddColumn,
+	})
+	close(ddlEventCh)
+	rd := NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh)
+	rd.schemaSnapshot = &model.RedoSchemaSnapshot{
+		Ts: 900,
+		Tables: []*model.RedoTableSchema{{
+			Schema: "test",
+			Table:  "t1",
+			TableInfo: &timodel.TableInfo{
+				ID:   1,
+				Name: timodel.NewCIStr("t1"),
+				Columns: []*timodel.ColumnInfo{{
+					Name:      timodel.NewCIStr("a"),
+					FieldType: *types.NewFieldType(mysql.TypeLong),
+				}},
+			},
+		}},
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return rd, nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	var progress []uint64
+	cfg := &RedoApplierConfig{
+		SinkURI:           "blackhole://",
+		ReconstructSchema: true,
+		OnProgress: func(appliedTs uint64) {
+			progress = append(progress, appliedTs)
+		},
+	}
+	// the DDL is executed once the logs before it are applied.
+	require.Nil(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Equal(t, []uint64{1300, 1649, resolvedTs}, progress)
+	require.Equal(t, checkpointTs, rd.startTs)
+}
+
+func TestApplyFromStartTs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	redoLogCh := make(chan *model.RedoRowChangedEvent, 1024)
+	ddlEventCh := make(chan *model.RedoDDLEvent, 1024)
+	for i := 0; i < 4; i++ {
+		redoLogCh <- redo.RowToRedo(&model.RowChangedEvent{
+			StartTs:  uint64(800 + i*100),
+			CommitTs: uint64(850 + i*100),
+			Table:    &model.TableName{Schema: "test", Table: "t1", TableID: 1},
+			Columns:  []*model.Column{{Name: "a", Value: i, Flag: model.HandleKeyFlag}},
+		})
+	}
+	close(redoLogCh)
+	ddlEventCh <- redo.DDLToRedo(&model.DDLEvent{
+		StartTs:   990,
+		CommitTs:  1000,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+		Query:     "alter table t1 add column b int",
+		Type:      timodel.ActionAddColumn,
+	})
+	close(ddlEventCh)
+	rd := NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh)
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return rd, nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	var progress []uint64
+	cfg := &RedoApplierConfig{
+		SinkURI:     "blackhole://",
+		StartTs:     800,
+		TargetTs:    1500,
+		ExecuteDDLs: true,
+		OnProgress: func(appliedTs uint64) {
+			progress = append(progress, appliedTs)
+		},
+	}
+	// the logs before the checkpoint ts are applied from the start ts, and
+	// the DDL is executed without reconstructing the schema.
+	require.Nil(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Equal(t, uint64(800), rd.startTs)
+	require.Equal(t, []uint64{1000, 1149, 1500}, progress)
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/errors"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/metautil"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// restoreReadTimeout is the read timeout of the connection restoring the
+// backup, unless it's set in the sink uri.
+const restoreReadTimeout = "24h"
+
+// RedoRestorerConfig is the configuration of a point-in-time restore of a
+// downstream from a BR full backup and the redo logs of the changefeeds
+// replicating to it.
+type RedoRestorerConfig struct {
+	// SinkURI is the downstream to restore, it must be a TiDB if the backup
+	// is restored by the restorer.
+	SinkURI string
+	// BackupStorage is the storage of the BR full backup of the upstream.
+	BackupStorage string
+	// BackupTs is the ts of the backup, it's read from the backup meta in the
+	// backup storage if it's 0.
+	BackupTs uint64
+	// SkipRestore skips restoring the backup, the downstream must have been
+	// restored from it, e.g. by the br command line tool. The apply resumes
+	// from the checkpoint files if they exist.
+	SkipRestore bool
+	// TargetTs is the ts the downstream is restored to, it's the smallest
+	// resolved ts of the redo logs if it's 0.
+	TargetTs uint64
+	// Redo is the configuration of the redo logs of each changefeed, the
+	// tables replicated by the changefeeds must not overlap. SinkURI, StartTs,
+	// TargetTs and ExecuteDDLs are set by the restorer.
+	Redo []*RedoApplierConfig
+	// DryRun only reports the restore plan, nothing is restored or applied.
+	DryRun bool
+	// OnPlan is called with the restore plan before the restore starts.
+	OnPlan func(plan *RestorePlan)
+}
+
+// RestorePlan correlates the backup with the redo logs of the changefeeds,
+// the downstream is restored from the backup at BackupTs, and then the redo
+// logs in (BackupTs, TargetTs] are applied.
+type RestorePlan struct {
+	BackupTs uint64
+	TargetTs uint64
+	Ranges   []*RedoLogRange
+}
+
+// RedoRestorer restores a downstream to a point in time.
+type RedoRestorer struct {
+	cfg *RedoRestorerConfig
+}
+
+// NewRedoRestorer creates a new RedoRestorer instance.
+func NewRedoRestorer(cfg *RedoRestorerConfig) *RedoRestorer {
+	return &RedoRestorer{cfg: cfg}
+}
+
+// Plan reads the backup ts and the ranges of the redo logs, and checks the
+// redo logs cover the range from the backup ts to the target ts.
+func (r *RedoRestorer) Plan(ctx context.Context) (*RestorePlan, error) {
+	if len(r.cfg.Redo) == 0 {
+		return nil, cerror.ErrRedoConfigInvalid.GenWithStack("no redo log storage is specified")
+	}
+	backupTs := r.cfg.BackupTs
+	if backupTs == 0 {
+		if r.cfg.BackupStorage == "" {
+			return nil, cerror.ErrRedoConfigInvalid.GenWithStack(
+				"either the backup storage or the backup ts must be specified")
+		}
+		var err error
+		backupTs, err = readBackupTs(ctx, r.cfg.BackupStorage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ranges := make([]*RedoLogRange, 0, len(r.cfg.Redo))
+	for _, redoCfg := range r.cfg.Redo {
+		rg, err := NewRedoApplier(redoCfg).ReadRange(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rg)
+	}
+	return newRestorePlan(backupTs, r.cfg.TargetTs, ranges)
+}
+
+// newRestorePlan checks all the ranges cover the logs from the backup ts to
+// the target ts, the target ts is the smallest resolved ts if it's 0.
+func newRestorePlan(backupTs, targetTs uint64, ranges []*RedoLogRange) (*RestorePlan, error) {
+	if targetTs == 0 {
+		for i, rg := range ranges {
+			if i == 0 || rg.ResolvedTs < targetTs {
+				targetTs = rg.ResolvedTs
+			}
+		}
+	}
+	if backupTs > targetTs {
+		return nil, cerror.ErrRedoConfigInvalid.GenWithStack(
+			"target ts %d is before the backup ts %d", targetTs, backupTs)
+	}
+	for _, rg := range ranges {
+		if !rg.Covers(backupTs, targetTs) {
+			return nil, cerror.ErrRedoConfigInvalid.GenWithStack(
+				"redo logs in %s can only be applied in [%d, %d], "+
+					"which doesn't cover the backup ts %d and the target ts %d",
+				MaskStorageURI(rg.Storage), rg.StartTs, rg.ResolvedTs, backupTs, targetTs)
+		}
+	}
+	return &RestorePlan{BackupTs: backupTs, TargetTs: targetTs, Ranges: ranges}, nil
+}
+
+// Run restores the downstream from the backup, and applies the redo logs of
+// the changefeeds one by one up to the target ts.
+func (r *RedoRestorer) Run(ctx context.Context) error {
+	plan, err := r.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if r.cfg.OnPlan != nil {
+		r.cfg.OnPlan(plan)
+	}
+	if r.cfg.DryRun {
+		return nil
+	}
+
+	if !r.cfg.SkipRestore {
+		if r.cfg.BackupStorage == "" {
+			return cerror.ErrRedoConfigInvalid.GenWithStack(
+				"the backup storage must be specified to restore the backup")
+		}
+		// the progress of a previous restore doesn't apply to the downstream
+		// restored again.
+		for _, redoCfg := range r.cfg.Redo {
+			if redoCfg.CheckpointFile == "" {
+				continue
+			}
+			if err := os.Remove(redoCfg.CheckpointFile); err != nil && !os.IsNotExist(err) {
+				return cerror.WrapError(cerror.ErrRedoFileOp, err)
+			}
+		}
+		if err := r.restoreBackup(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, redoCfg := range r.cfg.Redo {
+		redoCfg.SinkURI = r.cfg.SinkURI
+		redoCfg.StartTs = plan.BackupTs
+		redoCfg.TargetTs = plan.TargetTs
+		redoCfg.ExecuteDDLs = true
+		if err := NewRedoApplier(redoCfg).Apply(ctx); err != nil {
+			return err
+		}
+		log.Info("redo logs applied for point-in-time restore",
+			zap.String("storage", MaskStorageURI(redoCfg.Storage)),
+			zap.Uint64("backupTs", plan.BackupTs),
+			zap.Uint64("targetTs", plan.TargetTs))
+	}
+	return nil
+}
+
+// restoreBackup restores the backup to the downstream by the RESTORE
+// statement of TiDB.
+func (r *RedoRestorer) restoreBackup(ctx context.Context) error {
+	uri, err := url.Parse(r.cfg.SinkURI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	// the RESTORE statement returns after the backup is restored, which takes
+	// much longer than the default read timeout of the mysql sink.
+	if !uri.Query().Has("read-timeout") {
+		query := uri.Query()
+		query.Set("read-timeout", restoreReadTimeout)
+		uri.RawQuery = query.Encode()
+	}
+	db, err := openDB(ctx, "redo-restore", uri.String())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	log.Info("restore the backup to the downstream",
+		zap.String("backup", MaskStorageURI(r.cfg.BackupStorage)))
+	return restoreBackup(ctx, db, r.cfg.BackupStorage)
+}
+
+func restoreBackup(ctx context.Context, db *sql.DB, backupStorage string) error {
+	// the storage of the RESTORE statement can't be a placeholder
+	_, err := db.ExecContext(ctx, "RESTORE DATABASE * FROM "+quoteString(backupStorage))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLQueryError,
+			errors.Annotatef(err, "restore backup %s", MaskStorageURI(backupStorage)))
+	}
+	return nil
+}
+
+// sensitiveStorageParams are the query parameters of a storage uri carrying
+// the credentials of the storage, with `_` normalized to `-`.
+var sensitiveStorageParams = map[string]struct{}{
+	"access-key":        {},
+	"secret-access-key": {},
+	"session-token":     {},
+	"account-key":       {},
+	"sas-token":         {},
+}
+
+// MaskStorageURI masks the password and the credentials in the query of a
+// storage uri, so it can be logged or printed.
+func MaskStorageURI(storageURI string) string {
+	uri, err := url.Parse(storageURI)
+	if err != nil {
+		// the uri can't be parsed, so the credentials can't be located
+		return "<invalid storage uri>"
+	}
+	query := uri.Query()
+	masked := false
+	for key := range query {
+		normalized := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		if _, ok := sensitiveStorageParams[normalized]; ok {
+			query.Set(key, "xxxxx")
+			masked = true
+		}
+	}
+	if masked {
+		uri.RawQuery = query.Encode()
+	}
+	return uri.Redacted()
+}
+
+// readBackupTs reads the ts of the full backup from the backup meta.
+func readBackupTs(ctx context.Context, backupStorage string) (uint64, error) {
+	maskedStorage := MaskStorageURI(backupStorage)
+	backend, err := storage.ParseBackend(backupStorage, &storage.BackendOptions{})
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrS3StorageAPI, err)
+	}
+	s, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{})
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrS3StorageAPI, err)
+	}
+	data, err := s.ReadFile(ctx, metautil.MetaFile)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrS3StorageAPI,
+			errors.Annotatef(err, "read backup meta of %s", maskedStorage))
+	}
+	meta := &backuppb.BackupMeta{}
+	if err := proto.Unmarshal(data, meta); err != nil {
+		return 0, cerror.ErrRedoConfigInvalid.Wrap(err).GenWithStack(
+			"can't decode the backup meta of %s, specify the backup ts if it's encrypted", maskedStorage)
+	}
+	if meta.StartVersion != 0 {
+		return 0, cerror.ErrRedoConfigInvalid.GenWithStack(
+			"backup %s is an incremental backup, a full backup is required", maskedStorage)
+	}
+	return meta.EndVersion, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gogo/protobuf/proto"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tidb/br/pkg/metautil"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRestorePlan(t *testing.T) {
+	ranges := []*RedoLogRange{
+		{Storage: "s3://bucket/cf1", StartTs: 100, CheckpointTs: 300, ResolvedTs: 500},
+		{Storage: "s3://bucket/cf2", StartTs: 200, CheckpointTs: 300, ResolvedTs: 400},
+	}
+
+	// the target ts is the smallest resolved ts by default
+	plan, err := newRestorePlan(250, 0, ranges)
+	require.Nil(t, err)
+	require.Equal(t, &RestorePlan{BackupTs: 250, TargetTs: 400, Ranges: ranges}, plan)
+
+	plan, err = newRestorePlan(200, 350, ranges)
+	require.Nil(t, err)
+	require.Equal(t, uint64(350), plan.TargetTs)
+
+	// the backup is before the logs retained in the storage of cf2
+	_, err = newRestorePlan(150, 350, ranges)
+	require.Regexp(t, "redo logs in s3://bucket/cf2 can only be applied in \\[200, 400\\]", err)
+	// the target is after the resolved ts of cf2
+	_, err = newRestorePlan(250, 450, ranges)
+	require.Regexp(t, "redo logs in s3://bucket/cf2 can only be applied in \\[200, 400\\]", err)
+	_, err = newRestorePlan(350, 300, ranges)
+	require.Regexp(t, "target ts 300 is before the backup ts 350", err)
+}
+
+func TestScanLogTs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	_, _, ok, err := scanLogTs(ctx, "local://"+filepath.Join(dir, "not-exist"))
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	for _, name := range []string{
+		fmt.Sprintf("cp1_test-cf_1_%s_300%s", common.DefaultRowLogFileType, common.LogEXT),
+		fmt.Sprintf("cp2_test-cf_1_%s_200%s", common.DefaultRowLogFileType, common.LogEXT),
+		fmt.Sprintf("cp1_test-cf_1_%s_400%s", common.DefaultDDLLogFileType, common.LogEXT),
+		// the files being written and the meta are not counted
+		fmt.Sprintf("cp1_test-cf_1_%s_100%s%s", common.DefaultRowLogFileType, common.LogEXT, common.TmpEXT),
+	} {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+	// the largest gc ts of the captures is returned
+	for capture, gcTs := range map[string]uint64{"cp1": 250, "cp2": 150} {
+		data, err := (&common.LogMeta{CheckPointTs: 300, ResolvedTs: 500, GCTs: gcTs}).MarshalMsg(nil)
+		require.Nil(t, err)
+		name := fmt.Sprintf("%s_test-cf_%s%s", capture, common.DefaultMetaFileType, common.MetaEXT)
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), data, 0o644))
+	}
+	earliestTs, gcTs, ok, err := scanLogTs(ctx, "local://"+dir)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(200), earliestTs)
+	require.Equal(t, uint64(250), gcTs)
+}
+
+func TestMaskStorageURI(t *testing.T) {
+	require.Equal(t, "s3://bucket/cf1", MaskStorageURI("s3://bucket/cf1"))
+	masked := MaskStorageURI("s3://bucket/backup?access-key=ak&secret_access_key=sk&endpoint=http%3A%2F%2Fs3")
+	require.NotContains(t, masked, "ak&")
+	require.NotContains(t, masked, "=sk")
+	require.Contains(t, masked, "endpoint=http%3A%2F%2Fs3")
+	require.Equal(t, "s3://user:xxxxx@bucket/backup", MaskStorageURI("s3://user:pass@bucket/backup"))
+}
+
+func TestReadBackupTs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeMeta := func(meta *backuppb.BackupMeta) {
+		data, err := proto.Marshal(meta)
+		require.Nil(t, err)
+		require.Nil(t, os.WriteFile(filepath.Join(dir, metautil.MetaFile), data, 0o644))
+	}
+
+	writeMeta(&backuppb.BackupMeta{EndVersion: 1000})
+	backupTs, err := readBackupTs(ctx, "local://"+dir)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1000), backupTs)
+
+	writeMeta(&backuppb.BackupMeta{StartVersion: 500, EndVersion: 1000})
+	_, err = readBackupTs(ctx, "local://"+dir)
+	require.Regexp(t, "is an incremental backup", err)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer db.Close()
+	mock.ExpectExec(regexp.QuoteMeta("RESTORE DATABASE * FROM 's3://bucket/backup?endpoint=http://a''b'")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.Nil(t, restoreBackup(ctx, db, "s3://bucket/backup?endpoint=http://a'b"))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/charset"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/pingcap/tiflow/cdc/sink"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"go.uber.org/zap"
+)
+
+const readDDLBatch = 256
+
+// createTables creates the databases and tables in the schema snapshot if
+// they don't exist in the downstream.
+func createTables(ctx context.Context, s sink.Sink, snap *model.RedoSchemaSnapshot) error {
+	schemas := make(map[string]struct{})
+	for _, table := range snap.Tables {
+		if table.TableInfo == nil {
+			continue
+		}
+		if _, ok := schemas[table.Schema]; !ok {
+			schemas[table.Schema] = struct{}{}
+			err := execDDL(ctx, s, &model.DDLEvent{
+				StartTs:   snap.Ts,
+				CommitTs:  snap.Ts,
+				TableInfo: &model.SimpleTableInfo{Schema: table.Schema},
+				Query:     "CREATE DATABASE IF NOT EXISTS " + quotes.QuoteName(table.Schema),
+				Type:      timodel.ActionCreateSchema,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		err := execDDL(ctx, s, &model.DDLEvent{
+			StartTs:   snap.Ts,
+			CommitTs:  snap.Ts,
+			TableInfo: &model.SimpleTableInfo{Schema: table.Schema, Table: table.Table},
+			Query:     createTableQuery(table.Table, table.TableInfo),
+			Type:      timodel.ActionCreateTable,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Info("tables created from redo schema snapshot",
+		zap.Uint64("ts", snap.Ts), zap.Int("tables", len(snap.Tables)))
+	return nil
+}
+
+// readDDLs reads all the DDL logs the reader is reset to, in commit ts order.
+func readDDLs(ctx context.Context, rd reader.RedoLogReader) ([]*model.DDLEvent, error) {
+	var ddls []*model.DDLEvent
+	for {
+		redoDDLs, err := rd.ReadNextDDL(ctx, readDDLBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(redoDDLs) == 0 {
+			return ddls, nil
+		}
+		for _, redoDDL := range redoDDLs {
+			ddls = append(ddls, redo.LogToDDL(redoDDL))
+		}
+	}
+}
+
+func execDDL(ctx context.Context, s sink.Sink, ddl *model.DDLEvent) error {
+	err := s.EmitDDLEvent(ctx, ddl)
+	if err != nil && !cerror.ErrDDLEventIgnored.Equal(errors.Cause(err)) {
+		return err
+	}
+	return nil
+}
+
+// createTableQuery builds the CREATE TABLE statement of the table. The
+// partitions of the table are not kept, the rows of all the partitions are
+// applied to the table.
+func createTableQuery(name string, tableInfo *timodel.TableInfo) string {
+	var defs []string
+	for _, col := range tableInfo.Columns {
+		if col.Hidden {
+			continue
+		}
+		defs = append(defs, "  "+columnDefinition(col))
+	}
+	if tableInfo.PKIsHandle {
+		for _, col := range tableInfo.Columns {
+			if mysql.HasPriKeyFlag(col.Flag) {
+				defs = append(defs, fmt.Sprintf("  PRIMARY KEY (%s) /*T![clustered_index] CLUSTERED */",
+					quotes.QuoteName(col.Name.O)))
+				break
+			}
+		}
+	}
+	for _, idx := range tableInfo.Indices {
+		if def, ok := indexDefinition(tableInfo, idx); ok {
+			defs = append(defs, "  "+def)
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "CREATE TABLE IF NOT EXISTS %s (\n%s\n)",
+		quotes.QuoteName(name), strings.Join(defs, ",\n"))
+	if tableInfo.Charset != "" {
+		fmt.Fprintf(&buf, " DEFAULT CHARSET=%s", tableInfo.Charset)
+	}
+	if tableInfo.Collate != "" {
+		fmt.Fprintf(&buf, " COLLATE=%s", tableInfo.Collate)
+	}
+	if tableInfo.Comment != "" {
+		fmt.Fprintf(&buf, " COMMENT=%s", quoteString(tableInfo.Comment))
+	}
+	return buf.String()
+}
+
+func columnDefinition(col *timodel.ColumnInfo) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%s %s", quotes.QuoteName(col.Name.O), col.GetTypeDesc())
+	if col.Charset != "" && col.Charset != charset.CharsetBin {
+		fmt.Fprintf(&buf, " CHARACTER SET %s", col.Charset)
+		if col.Collate != "" {
+			fmt.Fprintf(&buf, " COLLATE %s", col.Collate)
+		}
+	}
+	if col.IsGenerated() {
+		fmt.Fprintf(&buf, " GENERATED ALWAYS AS (%s)", col.GeneratedExprString)
+		if col.GeneratedStored {
+			buf.WriteString(" STORED")
+		} else {
+			buf.WriteString(" VIRTUAL")
+		}
+	}
+	if mysql.HasNotNullFlag(col.Flag) {
+		buf.WriteString(" NOT NULL")
+	} else if col.Tp == mysql.TypeTimestamp {
+		// the timestamp column is not null by default in some versions
+		buf.WriteString(" NULL")
+	}
+	if def := col.GetDefaultValue(); def != nil && !col.IsGenerated() {
+		fmt.Fprintf(&buf, " DEFAULT %s", defaultValue(col, def))
+	}
+	if mysql.HasOnUpdateNowFlag(col.Flag) {
+		buf.WriteString(" ON UPDATE " + currentTimestamp(col))
+	}
+	if mysql.HasAutoIncrementFlag(col.Flag) {
+		buf.WriteString(" AUTO_INCREMENT")
+	}
+	if col.Comment != "" {
+		fmt.Fprintf(&buf, " COMMENT %s", quoteString(col.Comment))
+	}
+	return buf.String()
+}
+
+func defaultValue(col *timodel.ColumnInfo, def interface{}) string {
+	value := fmt.Sprintf("%v", def)
+	switch {
+	case col.Tp == mysql.TypeBit:
+		return fmt.Sprintf("x'%x'", value)
+	case strings.EqualFold(value, "CURRENT_TIMESTAMP") &&
+		(col.Tp == mysql.TypeTimestamp || col.Tp == mysql.TypeDatetime):
+		return currentTimestamp(col)
+	default:
+		return quoteString(value)
+	}
+}
+
+func currentTimestamp(col *timodel.ColumnInfo) string {
+	if col.Decimal > 0 {
+		return fmt.Sprintf("CURRENT_TIMESTAMP(%d)", col.Decimal)
+	}
+	return "CURRENT_TIMESTAMP"
+}
+
+// indexDefinition returns the definition of the index, it returns false if
+// the index can't be created, e.g. it's an expression index.
+func indexDefinition(tableInfo *timodel.TableInfo, idx *timodel.IndexInfo) (string, bool) {
+	if idx.State != timodel.StatePublic {
+		return "", false
+	}
+	cols := make([]string, 0, len(idx.Columns))
+	for _, idxCol := range idx.Columns {
+		col := tableInfo.Columns[idxCol.Offset]
+		if col.Hidden {
+			log.Warn("expression index is not created from redo schema snapshot",
+				zap.String("table", tableInfo.Name.O), zap.String("index", idx.Name.O))
+			return "", false
+		}
+		colDef := quotes.QuoteName(col.Name.O)
+		if idxCol.Length != types.UnspecifiedLength {
+			colDef += fmt.Sprintf("(%d)", idxCol.Length)
+		}
+		cols = append(cols, colDef)
+	}
+
+	var def string
+	switch {
+	case idx.Primary:
+		def = "PRIMARY KEY"
+	case idx.Unique:
+		def = "UNIQUE KEY " + quotes.QuoteName(idx.Name.O)
+	default:
+		def = "KEY " + quotes.QuoteName(idx.Name.O)
+	}
+	def += fmt.Sprintf(" (%s)", strings.Join(cols, ","))
+	if idx.Primary {
+		if tableInfo.IsCommonHandle {
+			def += " /*T![clustered_index] CLUSTERED */"
+		} else {
+			def += " /*T![clustered_index] NONCLUSTERED */"
+		}
+	}
+	if idx.Comment != "" {
+		def += " COMMENT " + quoteString(idx.Comment)
+	}
+	return def, true
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+}
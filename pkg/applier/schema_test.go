@@ -0,0 +1,52 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTableQuery(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	job := helper.DDL2Job("create table test.t1(" +
+		"id int primary key, " +
+		"name varchar(20) not null default 'a''b' comment 'the name', " +
+		"age int unsigned, " +
+		"key idx_name(name(10)), unique key uk_age(age))")
+	require.Equal(t, "CREATE TABLE IF NOT EXISTS `t1` (\n"+
+		"  `id` int(11) NOT NULL,\n"+
+		"  `name` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT 'a''b' COMMENT 'the name',\n"+
+		"  `age` int(10) unsigned,\n"+
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  KEY `idx_name` (`name`(10)),\n"+
+		"  UNIQUE KEY `uk_age` (`age`)\n"+
+		") DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+		createTableQuery("t1", job.BinlogInfo.TableInfo))
+
+	job = helper.DDL2Job("create table test.t2(" +
+		"a varchar(10), b int, ts timestamp default current_timestamp on update current_timestamp, " +
+		"primary key(a, b) nonclustered)")
+	require.Equal(t, "CREATE TABLE IF NOT EXISTS `t2` (\n"+
+		"  `a` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,\n"+
+		"  `b` int(11) NOT NULL,\n"+
+		"  `ts` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n"+
+		"  PRIMARY KEY (`a`,`b`) /*T![clustered_index] NONCLUSTERED */\n"+
+		") DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+		createTableQuery("t2", job.BinlogInfo.TableInfo))
+}
@@ -0,0 +1,360 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/pkg/cyclic/mark"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"go.uber.org/zap"
+)
+
+// RedoVerifierConfig is the configuration used by a redo log verifier
+type RedoVerifierConfig struct {
+	// RedoApplierConfig is the config applying the redo logs to the shadow
+	// database, the SinkURI of it is the shadow database, which must be a
+	// copy of the downstream at the applied ts in CheckpointFile.
+	RedoApplierConfig
+	// DownstreamURI is the sink uri of the downstream of the changefeed, the
+	// shadow database is compared against it.
+	DownstreamURI string
+	// ChangefeedID is the changefeed writing the redo logs. If it's set and
+	// the syncpoint of it is enabled, the redo logs are applied to the latest
+	// syncpoint, and the downstream is read at the snapshot of it, otherwise
+	// the downstream is read as it is, which is only consistent with the
+	// shadow database if the changefeed is paused.
+	ChangefeedID string
+	// Interval is the interval the redo logs are verified, they are verified
+	// only once if it's 0.
+	Interval time.Duration
+	// OnReport is called with the report of each verification.
+	OnReport func(report *VerifyReport)
+}
+
+// TableVerifyResult is the result of verifying a table
+type TableVerifyResult struct {
+	Schema             string
+	Table              string
+	ShadowRows         int64
+	ShadowChecksum     uint64
+	DownstreamRows     int64
+	DownstreamChecksum uint64
+	// Error is the error verifying the table, e.g. the table doesn't exist
+	// in the downstream.
+	Error string
+}
+
+// Consistent returns whether the table in the shadow database is the same as
+// the one in the downstream.
+func (r *TableVerifyResult) Consistent() bool {
+	return r.Error == "" && r.ShadowRows == r.DownstreamRows && r.ShadowChecksum == r.DownstreamChecksum
+}
+
+// VerifyReport is the report of a verification
+type VerifyReport struct {
+	// AppliedTs is the ts the redo logs are applied to in the shadow database.
+	AppliedTs uint64
+	// SnapshotTs is the ts of the downstream snapshot compared, it's 0 if the
+	// downstream is read as it is.
+	SnapshotTs uint64
+	Tables     []*TableVerifyResult
+}
+
+// Diverged returns the tables diverged from the downstream.
+func (r *VerifyReport) Diverged() []*TableVerifyResult {
+	var diverged []*TableVerifyResult
+	for _, t := range r.Tables {
+		if !t.Consistent() {
+			diverged = append(diverged, t)
+		}
+	}
+	return diverged
+}
+
+// RedoVerifier verifies the redo logs by replaying them to a shadow database
+// continuously, and comparing the checksums of the tables in it against the
+// downstream of the changefeed.
+type RedoVerifier struct {
+	cfg *RedoVerifierConfig
+}
+
+// NewRedoVerifier creates a new RedoVerifier instance
+func NewRedoVerifier(cfg *RedoVerifierConfig) *RedoVerifier {
+	return &RedoVerifier{cfg: cfg}
+}
+
+// Run verifies the redo logs every interval until the context is canceled,
+// or only once if the interval is 0.
+func (v *RedoVerifier) Run(ctx context.Context) error {
+	if v.cfg.CheckpointFile == "" {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"checkpoint file is required to verify the redo logs")
+	}
+	for {
+		report, err := v.verify(ctx)
+		if err != nil {
+			return err
+		}
+		if report != nil && v.cfg.OnReport != nil {
+			v.cfg.OnReport(report)
+		}
+		if v.cfg.Interval == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-time.After(v.cfg.Interval):
+		}
+	}
+}
+
+// verify applies the redo logs to the shadow database, and compares it
+// against the downstream. It returns nil if there is nothing new to verify.
+func (v *RedoVerifier) verify(ctx context.Context) (*VerifyReport, error) {
+	downstream, err := openDB(ctx, "redo-verifier-downstream", v.cfg.DownstreamURI)
+	if err != nil {
+		return nil, err
+	}
+	defer downstream.Close()
+	shadow, err := openDB(ctx, "redo-verifier-shadow", v.cfg.SinkURI)
+	if err != nil {
+		return nil, err
+	}
+	defer shadow.Close()
+
+	_, resolvedTs, err := NewRedoApplier(&v.cfg.RedoApplierConfig).ReadMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetTs, snapshotTs := resolvedTs, uint64(0)
+	if v.cfg.ChangefeedID != "" {
+		targetTs, snapshotTs, err = latestSyncpoint(ctx, downstream, v.cfg.ChangefeedID, resolvedTs)
+		if err != nil {
+			return nil, err
+		}
+		if targetTs == 0 {
+			log.Info("no syncpoint before the resolved ts of redo logs, skip verification",
+				zap.String("changefeed", v.cfg.ChangefeedID), zap.Uint64("resolvedTs", resolvedTs))
+			return nil, nil
+		}
+	}
+	cp, err := loadApplyCheckpoint(v.cfg.CheckpointFile)
+	if err != nil {
+		return nil, err
+	}
+	if cp != nil && cp.AppliedTs > targetTs {
+		log.Info("shadow database is ahead of the syncpoint, skip verification",
+			zap.Uint64("appliedTs", cp.AppliedTs), zap.Uint64("syncpointTs", targetTs))
+		return nil, nil
+	}
+
+	applierCfg := v.cfg.RedoApplierConfig
+	applierCfg.ReconstructSchema = true
+	applierCfg.TargetTs = targetTs
+	if err := NewRedoApplier(&applierCfg).Apply(ctx); err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{AppliedTs: targetTs, SnapshotTs: snapshotTs}
+	// the tables missing on either side are reported as diverged.
+	tables, err := listTables(ctx, shadow, downstream, snapshotTs)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		report.Tables = append(report.Tables,
+			verifyTable(ctx, shadow, downstream, snapshotTs, table[0], table[1]))
+	}
+	diverged := report.Diverged()
+	if len(diverged) > 0 {
+		for _, t := range diverged {
+			log.Warn("table in the shadow database diverges from the downstream",
+				zap.String("schema", t.Schema), zap.String("table", t.Table),
+				zap.Int64("shadowRows", t.ShadowRows), zap.Int64("downstreamRows", t.DownstreamRows),
+				zap.Uint64("shadowChecksum", t.ShadowChecksum),
+				zap.Uint64("downstreamChecksum", t.DownstreamChecksum), zap.String("error", t.Error))
+		}
+	}
+	log.Info("redo logs verified", zap.Uint64("appliedTs", targetTs),
+		zap.Uint64("snapshotTs", snapshotTs), zap.Int("tables", len(report.Tables)),
+		zap.Int("diverged", len(diverged)))
+	return report, nil
+}
+
+// latestSyncpoint returns the latest syncpoint of the changefeed not after
+// ts, it returns 0 if there is no such syncpoint.
+func latestSyncpoint(
+	ctx context.Context, db *sql.DB, changefeedID string, ts uint64,
+) (primaryTs, secondaryTs uint64, err error) {
+	query := fmt.Sprintf("SELECT CAST(primary_ts AS UNSIGNED), CAST(secondary_ts AS UNSIGNED) FROM %s "+
+		"WHERE cf = ? AND CAST(primary_ts AS UNSIGNED) <= ? ORDER BY CAST(primary_ts AS UNSIGNED) DESC LIMIT 1",
+		quotes.QuoteSchema(mark.SchemaName, sink.SyncpointTableName))
+	err = db.QueryRowContext(ctx, query, changefeedID, ts).Scan(&primaryTs, &secondaryTs)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return primaryTs, secondaryTs, nil
+}
+
+// listTables returns the schema and name of the user tables in the shadow
+// database or the downstream, the downstream is read at snapshotTs if it's
+// not 0.
+func listTables(
+	ctx context.Context, shadow, downstream *sql.DB, snapshotTs uint64,
+) ([][2]string, error) {
+	shadowTables, err := queryTables(ctx, shadow, 0)
+	if err != nil {
+		return nil, err
+	}
+	downstreamTables, err := queryTables(ctx, downstream, snapshotTs)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[[2]string]struct{}, len(shadowTables))
+	tables := make([][2]string, 0, len(shadowTables))
+	for _, table := range append(shadowTables, downstreamTables...) {
+		if _, ok := seen[table]; ok {
+			continue
+		}
+		seen[table] = struct{}{}
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i][0] != tables[j][0] {
+			return tables[i][0] < tables[j][0]
+		}
+		return tables[i][1] < tables[j][1]
+	})
+	return tables, nil
+}
+
+// queryTables returns the schema and name of the user tables in the database,
+// it's read at snapshotTs if it's not 0.
+func queryTables(ctx context.Context, db *sql.DB, snapshotTs uint64) ([][2]string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLConnectionError, err)
+	}
+	defer conn.Close()
+	if snapshotTs != 0 {
+		if _, err := conn.ExecContext(ctx, "SET @@tidb_snapshot = ?", snapshotTs); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+	}
+	rows, err := conn.QueryContext(ctx, "SELECT TABLE_SCHEMA, TABLE_NAME FROM information_schema.tables "+
+		"WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA NOT IN "+
+		"('mysql', 'information_schema', 'performance_schema', 'sys', 'metrics_schema', ?) "+
+		"ORDER BY TABLE_SCHEMA, TABLE_NAME", mark.SchemaName)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+	var tables [][2]string
+	for rows.Next() {
+		var table [2]string
+		if err := rows.Scan(&table[0], &table[1]); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, cerror.WrapError(cerror.ErrMySQLQueryError, rows.Err())
+}
+
+// verifyTable compares the row count and checksum of the table in the shadow
+// database and the downstream, the downstream is read at snapshotTs if it's
+// not 0.
+func verifyTable(
+	ctx context.Context, shadow, downstream *sql.DB, snapshotTs uint64, schema, table string,
+) *TableVerifyResult {
+	result := &TableVerifyResult{Schema: schema, Table: table}
+	query, err := checksumQuery(ctx, shadow, schema, table)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	err = shadow.QueryRowContext(ctx, query).Scan(&result.ShadowRows, &result.ShadowChecksum)
+	if err != nil {
+		result.Error = fmt.Sprintf("checksum shadow table: %s", err)
+		return result
+	}
+
+	conn, err := downstream.Conn(ctx)
+	if err != nil {
+		result.Error = fmt.Sprintf("connect downstream: %s", err)
+		return result
+	}
+	defer conn.Close()
+	if snapshotTs != 0 {
+		if _, err := conn.ExecContext(ctx, "SET @@tidb_snapshot = ?", snapshotTs); err != nil {
+			result.Error = fmt.Sprintf("read downstream snapshot %d: %s", snapshotTs, err)
+			return result
+		}
+	}
+	err = conn.QueryRowContext(ctx, query).Scan(&result.DownstreamRows, &result.DownstreamChecksum)
+	if err != nil {
+		result.Error = fmt.Sprintf("checksum downstream table: %s", err)
+	}
+	return result
+}
+
+// checksumQuery builds the query returning the row count and the checksum of
+// the table by the columns of it in the shadow database, the checksum is the
+// xor of the crc32 of each row, so it's independent of the order of rows.
+func checksumQuery(ctx context.Context, db *sql.DB, schema, table string) (string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT COLUMN_NAME FROM information_schema.columns "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION", schema, table)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+	var columns, isNulls []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		columns = append(columns, quotes.QuoteName(column))
+		isNulls = append(isNulls, fmt.Sprintf("ISNULL(%s)", quotes.QuoteName(column)))
+	}
+	if err := rows.Err(); err != nil {
+		return "", cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	if len(columns) == 0 {
+		return "", errors.Errorf("no column in table %s", quotes.QuoteSchema(schema, table))
+	}
+	// the null columns are skipped by CONCAT_WS, they're distinguished from
+	// the empty strings by the ISNULL flags.
+	return fmt.Sprintf("SELECT COUNT(*), COALESCE(BIT_XOR(CRC32(CONCAT_WS(',', %s, CONCAT(%s)))), 0) FROM %s",
+		strings.Join(columns, ", "), strings.Join(isNulls, ", "), quotes.QuoteSchema(schema, table)), nil
+}
+
+// openDB opens the database of the mysql sink uri with the dsn of the mysql
+// sink, the id distinguishes the TLS config registered for it.
+func openDB(ctx context.Context, id string, sinkURI string) (*sql.DB, error) {
+	return sink.OpenMySQLDB(ctx, id, sinkURI)
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTable(t *testing.T) {
+	ctx := context.Background()
+	checksum := regexp.QuoteMeta("SELECT COUNT(*), COALESCE(BIT_XOR(CRC32(CONCAT_WS(',', `id`, `v`, " +
+		"CONCAT(ISNULL(`id`), ISNULL(`v`))))), 0) FROM `test`.`t1`")
+	mockColumns := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT COLUMN_NAME FROM information_schema.columns").
+			WithArgs("test", "t1").
+			WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME"}).AddRow("id").AddRow("v"))
+	}
+
+	testCases := []struct {
+		downstreamRows     int64
+		downstreamChecksum uint64
+		consistent         bool
+	}{
+		{downstreamRows: 2, downstreamChecksum: 100, consistent: true},
+		{downstreamRows: 2, downstreamChecksum: 101, consistent: false},
+		{downstreamRows: 3, downstreamChecksum: 100, consistent: false},
+	}
+	for _, tc := range testCases {
+		shadow, shadowMock, err := sqlmock.New()
+		require.Nil(t, err)
+		mockColumns(shadowMock)
+		shadowMock.ExpectQuery(checksum).
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).AddRow(2, 100))
+		downstream, downstreamMock, err := sqlmock.New()
+		require.Nil(t, err)
+		downstreamMock.ExpectExec(regexp.QuoteMeta("SET @@tidb_snapshot = ?")).
+			WithArgs(1000).WillReturnResult(sqlmock.NewResult(0, 0))
+		downstreamMock.ExpectQuery(checksum).
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).
+				AddRow(tc.downstreamRows, tc.downstreamChecksum))
+
+		result := verifyTable(ctx, shadow, downstream, 1000, "test", "t1")
+		require.Equal(t, "", result.Error)
+		require.Equal(t, tc.consistent, result.Consistent())
+		require.EqualValues(t, 2, result.ShadowRows)
+		require.EqualValues(t, 100, result.ShadowChecksum)
+		require.Equal(t, tc.downstreamRows, result.DownstreamRows)
+		require.Equal(t, tc.downstreamChecksum, result.DownstreamChecksum)
+		require.Nil(t, shadowMock.ExpectationsWereMet())
+		require.Nil(t, downstreamMock.ExpectationsWereMet())
+		shadow.Close()
+		downstream.Close()
+	}
+
+	// the table missing in the downstream diverges
+	shadow, shadowMock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer shadow.Close()
+	mockColumns(shadowMock)
+	shadowMock.ExpectQuery(checksum).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "checksum"}).AddRow(2, 100))
+	downstream, downstreamMock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer downstream.Close()
+	downstreamMock.ExpectQuery(checksum).WillReturnError(&dmysql.MySQLError{
+		Number:  1146,
+		Message: "Table 'test.t1' doesn't exist",
+	})
+	result := verifyTable(ctx, shadow, downstream, 0, "test", "t1")
+	require.Regexp(t, "checksum downstream table", result.Error)
+	require.False(t, result.Consistent())
+	report := &VerifyReport{Tables: []*TableVerifyResult{result, {Schema: "test", Table: "t2"}}}
+	require.Equal(t, []*TableVerifyResult{result}, report.Diverged())
+}
+
+func TestLatestSyncpoint(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer db.Close()
+	query := regexp.QuoteMeta("SELECT CAST(primary_ts AS UNSIGNED), CAST(secondary_ts AS UNSIGNED) " +
+		"FROM `tidb_cdc`.`syncpoint_v1` WHERE cf = ?")
+	mock.ExpectQuery(query).WithArgs("test-cf", 2000).
+		WillReturnRows(sqlmock.NewRows([]string{"primary_ts", "secondary_ts"}).AddRow(1500, 1600))
+	mock.ExpectQuery(query).WithArgs("test-cf", 1000).
+		WillReturnRows(sqlmock.NewRows([]string{"primary_ts", "secondary_ts"}))
+
+	primaryTs, secondaryTs, err := latestSyncpoint(ctx, db, "test-cf", 2000)
+	require.Nil(t, err)
+	require.EqualValues(t, 1500, primaryTs)
+	require.EqualValues(t, 1600, secondaryTs)
+	primaryTs, secondaryTs, err = latestSyncpoint(ctx, db, "test-cf", 1000)
+	require.Nil(t, err)
+	require.EqualValues(t, 0, primaryTs)
+	require.EqualValues(t, 0, secondaryTs)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListTables(t *testing.T) {
+	ctx := context.Background()
+	query := regexp.QuoteMeta("SELECT TABLE_SCHEMA, TABLE_NAME FROM information_schema.tables")
+	shadow, shadowMock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer shadow.Close()
+	shadowMock.ExpectQuery(query).WithArgs("tidb_cdc").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME"}).
+			AddRow("test", "t1").AddRow("test", "t3"))
+	downstream, downstreamMock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer downstream.Close()
+	downstreamMock.ExpectExec(regexp.QuoteMeta("SET @@tidb_snapshot = ?")).
+		WithArgs(1000).WillReturnResult(sqlmock.NewResult(0, 0))
+	downstreamMock.ExpectQuery(query).WithArgs("tidb_cdc").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME"}).
+			AddRow("test", "t1").AddRow("test", "t2"))
+
+	// the tables missing on either side are listed to be reported as diverged.
+	tables, err := listTables(ctx, shadow, downstream, 1000)
+	require.Nil(t, err)
+	require.Equal(t, [][2]string{{"test", "t1"}, {"test", "t2"}, {"test", "t3"}}, tables)
+	require.Nil(t, shadowMock.ExpectationsWereMet())
+	require.Nil(t, downstreamMock.ExpectationsWereMet())
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+)
+
+// applyTask is a task of an apply worker, the rows are emitted to the sink
+// first, and then the tables of the worker are flushed if done is not nil.
+type applyTask struct {
+	rows []*model.RowChangedEvent
+	// resolvedTs is the ts the tables are flushed to.
+	resolvedTs model.Ts
+	// barrier waits until all the events of the tables are flushed to the
+	// downstream.
+	barrier bool
+	// done receives the result of the flush.
+	done chan applyResult
+}
+
+// applyResult is the result of the flush of an apply task.
+type applyResult struct {
+	// checkpointTs is the ts all the tables of the worker have been flushed
+	// to the downstream, it may be before the resolved ts of the task unless
+	// the task is a barrier.
+	checkpointTs model.Ts
+	err          error
+}
+
+// applyWorker applies the redo logs of some tables to a sink of its own. The
+// logs of a table are always applied by the same worker in commit ts order,
+// so the rows applied by different workers never conflict with each other.
+type applyWorker struct {
+	sink   sink.Sink
+	taskCh chan applyTask
+
+	tables     map[model.TableID]struct{}
+	cachedRows []*model.RowChangedEvent
+}
+
+func newApplyWorker(s sink.Sink) *applyWorker {
+	return &applyWorker{
+		sink:       s,
+		taskCh:     make(chan applyTask, 16),
+		tables:     make(map[model.TableID]struct{}),
+		cachedRows: make([]*model.RowChangedEvent, 0, emitBatch),
+	}
+}
+
+// run handles the tasks until the context is canceled or taskCh is closed.
+func (w *applyWorker) run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case task, ok := <-w.taskCh:
+			if !ok {
+				return nil
+			}
+			checkpointTs, err := w.handle(ctx, task)
+			if task.done != nil {
+				task.done <- applyResult{checkpointTs: checkpointTs, err: err}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *applyWorker) handle(ctx context.Context, task applyTask) (model.Ts, error) {
+	for _, row := range task.rows {
+		w.tables[row.Table.TableID] = struct{}{}
+		if len(w.cachedRows) >= emitBatch {
+			if err := w.emit(ctx); err != nil {
+				return 0, err
+			}
+		}
+		w.cachedRows = append(w.cachedRows, row)
+	}
+	if task.done == nil {
+		return 0, nil
+	}
+
+	// the cached rows must be emitted before the flush, otherwise they are
+	// not flushed even if they are before the resolved ts.
+	if err := w.emit(ctx); err != nil {
+		return 0, err
+	}
+	checkpointTs := task.resolvedTs
+	for tableID := range w.tables {
+		flushedTs, err := w.sink.FlushRowChangedEvents(ctx, tableID, task.resolvedTs)
+		if err != nil {
+			return 0, err
+		}
+		if task.barrier {
+			if err := w.sink.Barrier(ctx, tableID); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if flushedTs < checkpointTs {
+			checkpointTs = flushedTs
+		}
+	}
+	return checkpointTs, nil
+}
+
+func (w *applyWorker) emit(ctx context.Context) error {
+	if len(w.cachedRows) == 0 {
+		return nil
+	}
+	if err := w.sink.EmitRowChangedEvents(ctx, w.cachedRows...); err != nil {
+		return err
+	}
+	w.cachedRows = make([]*model.RowChangedEvent, 0, emitBatch)
+	return nil
+}
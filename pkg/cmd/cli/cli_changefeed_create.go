@@ -23,8 +23,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/transform"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	"github.com/pingcap/tiflow/pkg/cmd/factory"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
@@ -33,6 +35,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/secret"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	ticdcutil "github.com/pingcap/tiflow/pkg/util"
@@ -57,6 +60,7 @@ type changefeedCommonOptions struct {
 	cyclicSyncDDL          bool
 	syncPointEnabled       bool
 	syncPointInterval      time.Duration
+	labels                 map[string]string
 }
 
 // newChangefeedCommonOptions creates new changefeed common options.
@@ -83,6 +87,7 @@ func (o *changefeedCommonOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&o.cyclicSyncDDL, "cyclic-sync-ddl", true, "(Experimental) Cyclic replication sync DDL of changefeed")
 	cmd.PersistentFlags().BoolVar(&o.syncPointEnabled, "sync-point", false, "(Experimental) Set and Record syncpoint in replication(default off)")
 	cmd.PersistentFlags().DurationVar(&o.syncPointInterval, "sync-interval", 10*time.Minute, "(Experimental) Set the interval for syncpoint in replication(default 10min)")
+	cmd.PersistentFlags().StringToStringVar(&o.labels, "labels", nil, "Labels attached to the metrics of changefeed, in the `key=value` format")
 	_ = cmd.PersistentFlags().MarkHidden("sort-dir")
 }
 
@@ -94,6 +99,18 @@ func (o *changefeedCommonOptions) strictDecodeConfig(component string, cfg *conf
 	}
 
 	_, err = filter.VerifyRules(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = filter.NewColumnSelector(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = filter.NewEventFilter(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = transform.NewTransformer(cfg)
 
 	return err
 }
@@ -194,7 +211,7 @@ func (o *createChangefeedOptions) completeCfg(
 	}
 
 	if !cfg.EnableOldValue {
-		sinkURIParsed, err := url.Parse(o.commonChangefeedOptions.sinkURI)
+		sinkURIParsed, err := url.Parse(secret.MaskURI(o.commonChangefeedOptions.sinkURI))
 		if err != nil {
 			return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 		}
@@ -292,6 +309,10 @@ func (o *createChangefeedOptions) validate(ctx context.Context, cmd *cobra.Comma
 		return err
 	}
 
+	if err := model.ValidateChangefeedLabels(o.commonChangefeedOptions.labels); err != nil {
+		return err
+	}
+
 	// user is not allowed to set sort-dir at changefeed level
 	if o.commonChangefeedOptions.sortDir != "" {
 		cmd.Printf(color.HiYellowString("[WARN] --sort-dir is deprecated in changefeed settings. " +
@@ -327,6 +348,7 @@ func (o *createChangefeedOptions) getInfo(cmd *cobra.Command) *model.ChangeFeedI
 		SyncPointEnabled:  o.commonChangefeedOptions.syncPointEnabled,
 		SyncPointInterval: o.commonChangefeedOptions.syncPointInterval,
 		CreatorVersion:    version.ReleaseVersion,
+		Labels:            o.commonChangefeedOptions.labels,
 	}
 
 	if info.Engine == model.SortInFile {
@@ -426,6 +448,9 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 	}
 
 	info := o.getInfo(cmd)
+	if o.cfg.Filter.FreezeTables {
+		info.FrozenTables = api.FrozenTables(o.cfg, ineligibleTables, eligibleTables)
+	}
 
 	tz, err := ticdcutil.GetTimezone(o.timezone)
 	if err != nil {
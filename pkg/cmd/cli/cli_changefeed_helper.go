@@ -77,14 +77,23 @@ func confirmIgnoreIneligibleTables(cmd *cobra.Command) error {
 	return nil
 }
 
-// getTables returns ineligibleTables and eligibleTables by filter.
+// getTables returns ineligibleTables and eligibleTables by filter, and
+// verifies the transforms of the replicated tables.
 func getTables(cliPdAddr string, credential *security.Credential, cfg *config.ReplicaConfig, startTs uint64) (ineligibleTables, eligibleTables []model.TableName, err error) {
 	kvStore, err := kv.CreateTiStore(cliPdAddr, credential)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return api.VerifyTables(cfg, kvStore, startTs)
+	ineligibleTables, eligibleTables, err = api.VerifyTables(cfg, kvStore, startTs)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the transforms are verified against the same snapshot of tables.
+	if err := api.VerifyTransforms(cfg, kvStore, startTs); err != nil {
+		return nil, nil, err
+	}
+	return ineligibleTables, eligibleTables, nil
 }
 
 // sendOwnerChangefeedQuery sends owner changefeed query request.
@@ -149,10 +158,16 @@ func sendOwnerAdminChangeQuery(ctx context.Context, etcdClient *etcd.CDCEtcdClie
 		forceRemoveOpt = "true"
 	}
 
+	finalBarrierOpt := "false"
+	if job.Opts != nil && job.Opts.FinalBarrier {
+		finalBarrierOpt = "true"
+	}
+
 	resp, err := httpClient.PostForm(url, map[string][]string{
 		api.OpVarAdminJob:           {fmt.Sprint(int(job.Type))},
 		api.OpVarChangefeedID:       {job.CfID},
 		api.OpForceRemoveChangefeed: {forceRemoveOpt},
+		api.OpFinalBarrier:          {finalBarrierOpt},
 	})
 	if err != nil {
 		return err
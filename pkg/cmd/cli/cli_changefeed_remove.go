@@ -28,8 +28,9 @@ type removeChangefeedOptions struct {
 
 	credential *security.Credential
 
-	changefeedID   string
-	optForceRemove bool
+	changefeedID    string
+	optForceRemove  bool
+	optFinalBarrier bool
 }
 
 // newRemoveChangefeedOptions creates new options for the `cli changefeed remove` command.
@@ -42,6 +43,8 @@ func newRemoveChangefeedOptions() *removeChangefeedOptions {
 func (o *removeChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	cmd.PersistentFlags().BoolVarP(&o.optForceRemove, "force", "f", false, "remove all information of the changefeed")
+	cmd.PersistentFlags().BoolVar(&o.optFinalBarrier, "final-barrier", false,
+		"remove the changefeed after all events before its resolved ts are flushed and an end marker is written")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
 
@@ -65,7 +68,8 @@ func (o *removeChangefeedOptions) run() error {
 		CfID: o.changefeedID,
 		Type: model.AdminRemove,
 		Opts: &model.AdminJobOption{
-			ForceRemove: o.optForceRemove,
+			ForceRemove:  o.optForceRemove,
+			FinalBarrier: o.optFinalBarrier,
 		},
 	}
 
@@ -189,6 +189,12 @@ func (o *updateChangefeedOptions) applyChanges(oldInfo *model.ChangeFeedInfo, cm
 			newInfo.SyncPointEnabled = o.commonChangefeedOptions.syncPointEnabled
 		case "sync-interval":
 			newInfo.SyncPointInterval = o.commonChangefeedOptions.syncPointInterval
+		case "labels":
+			// do not overwrite the error of other flags with nil
+			if labelErr := model.ValidateChangefeedLabels(o.commonChangefeedOptions.labels); labelErr != nil {
+				err = labelErr
+			}
+			newInfo.Labels = o.commonChangefeedOptions.labels
 		case "sort-dir":
 			log.Warn("this flag cannot be updated and will be ignored", zap.String("flagName", flag.Name))
 		case "changefeed-id", "no-confirm", "cyclic-filter-replica-ids":
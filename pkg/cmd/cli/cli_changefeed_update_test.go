@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/check"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util/testleak"
 )
 
@@ -69,6 +70,17 @@ func (s *changefeedUpdateSuite) TestApplyChanges(c *check.C) {
 	file, err := os.ReadFile(filename)
 	c.Assert(err, check.IsNil)
 	c.Assert(strings.Contains(string(file), "this flag cannot be updated and will be ignored"), check.IsTrue)
+
+	// Test for labels.
+	oldInfo = &model.ChangeFeedInfo{Labels: map[string]string{"team": "a"}}
+	c.Assert(cmd.ParseFlags([]string{"--labels=team=b,env=prod"}), check.IsNil)
+	newInfo, err = o.applyChanges(oldInfo, cmd)
+	c.Assert(err, check.IsNil)
+	c.Assert(newInfo.Labels, check.DeepEquals, map[string]string{"team": "b", "env": "prod"})
+
+	c.Assert(cmd.ParseFlags([]string{"--labels=team-name=b"}), check.IsNil)
+	_, err = o.applyChanges(oldInfo, cmd)
+	c.Assert(cerror.ErrInvalidChangefeedLabel.Equal(err), check.IsTrue)
 }
 
 func initTestLogger(filename string) (func(), error) {
@@ -16,13 +16,19 @@ package redo
 import (
 	"github.com/pingcap/tiflow/pkg/applier"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 // applyRedoOptions defines flags for the `redo apply` command.
 type applyRedoOptions struct {
 	options
-	sinkURI string
+	sinkURI        string
+	dataEncryption config.DataEncryptionConfig
+	workerCount    int
+	checkpointFile string
+	// reconstructSchema creates the tables from the redo logs
+	reconstructSchema bool
 }
 
 // newapplyRedoOptions creates new applyRedoOptions for the `redo apply` command.
@@ -34,6 +40,21 @@ func newapplyRedoOptions() *applyRedoOptions {
 // flags related to template printing to it.
 func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "target database sink-uri")
+	cmd.Flags().StringVar(&o.dataEncryption.KeyFile, "data-encryption-key-file", "",
+		"the data key file of the captures if the redo logs are encrypted")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSKeyID, "data-encryption-kms-key-id", "",
+		"the ID of the KMS key encrypting the data key")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSRegion, "data-encryption-kms-region", "",
+		"the region of the KMS key encrypting the data key")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSEndpoint, "data-encryption-kms-endpoint", "",
+		"the endpoint of KMS")
+	cmd.Flags().IntVar(&o.workerCount, "worker-count", 1,
+		"the number of workers applying the redo logs in parallel, each of which has a sink of its own")
+	cmd.Flags().StringVar(&o.checkpointFile, "checkpoint-file", "",
+		"the file the apply progress is saved to, an interrupted apply resumes from the progress in it")
+	cmd.Flags().BoolVar(&o.reconstructSchema, "reconstruct-schema", false,
+		"create the tables from the schema snapshot in the redo logs and execute the DDLs in them, "+
+			"for a downstream without the tables")
 	// the possible error returned from MarkFlagRequired is `no such flag`
 	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
 }
@@ -42,10 +63,20 @@ func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
 func (o *applyRedoOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
+	if o.dataEncryption.KeyFile != "" {
+		o.dataEncryption.Method = config.DataEncryptionMethodAES256
+	}
 	cfg := &applier.RedoApplierConfig{
-		Storage: o.storage,
-		SinkURI: o.sinkURI,
-		Dir:     o.dir,
+		Storage:           o.storage,
+		SinkURI:           o.sinkURI,
+		Dir:               o.dir,
+		DataEncryption:    &o.dataEncryption,
+		WorkerCount:       o.workerCount,
+		CheckpointFile:    o.checkpointFile,
+		ReconstructSchema: o.reconstructSchema,
+		OnProgress: func(appliedTs uint64) {
+			cmd.Printf("Applied redo log to %d\n", appliedTs)
+		},
 	}
 	ap := applier.NewRedoApplier(cfg)
 	err := ap.Apply(ctx)
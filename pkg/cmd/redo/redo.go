@@ -63,6 +63,8 @@ func NewCmdRedo() *cobra.Command {
 	// Add subcommands.
 	cmds.AddCommand(newCmdApply(o))
 	cmds.AddCommand(newCmdMeta(o))
+	cmds.AddCommand(newCmdVerify(o))
+	cmds.AddCommand(newCmdRestoreTo(o))
 
 	return cmds
 }
@@ -0,0 +1,153 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pingcap/tiflow/pkg/applier"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// restoreToOptions defines flags for the `redo restore-to` command.
+type restoreToOptions struct {
+	options
+	sinkURI        string
+	backupStorage  string
+	backupTs       uint64
+	skipRestore    bool
+	targetTs       uint64
+	moreStorages   []string
+	dataEncryption config.DataEncryptionConfig
+	workerCount    int
+	checkpointDir  string
+	dryRun         bool
+}
+
+// newRestoreToOptions creates new restoreToOptions for the `redo restore-to` command.
+func newRestoreToOptions() *restoreToOptions {
+	return &restoreToOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *restoreToOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "",
+		"sink-uri of the downstream to restore, it must be a TiDB unless --skip-restore is set")
+	cmd.Flags().StringVar(&o.backupStorage, "backup-storage", "",
+		"storage of the BR full backup of the upstream the downstream is restored from")
+	cmd.Flags().Uint64Var(&o.backupTs, "backup-ts", 0,
+		"ts of the backup, it's read from the backup meta in the backup storage if it's 0")
+	cmd.Flags().BoolVar(&o.skipRestore, "skip-restore", false,
+		"skip restoring the backup, the downstream must have been restored from it, "+
+			"e.g. by `br restore full`")
+	cmd.Flags().Uint64Var(&o.targetTs, "target-ts", 0,
+		"ts the downstream is restored to, it's the smallest resolved ts of the redo logs if it's 0")
+	cmd.Flags().StringArrayVar(&o.moreStorages, "changefeed-storage", nil,
+		"storage of the redo logs of another changefeed replicating to the downstream, "+
+			"it can be specified multiple times")
+	cmd.Flags().StringVar(&o.dataEncryption.KeyFile, "data-encryption-key-file", "",
+		"the data key file of the captures if the redo logs are encrypted")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSKeyID, "data-encryption-kms-key-id", "",
+		"the ID of the KMS key encrypting the data key")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSRegion, "data-encryption-kms-region", "",
+		"the region of the KMS key encrypting the data key")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSEndpoint, "data-encryption-kms-endpoint", "",
+		"the endpoint of KMS")
+	cmd.Flags().IntVar(&o.workerCount, "worker-count", 1,
+		"the number of workers applying the redo logs in parallel, each of which has a sink of its own")
+	cmd.Flags().StringVar(&o.checkpointDir, "checkpoint-dir", "",
+		"the directory the apply progress of each changefeed is saved to, "+
+			"an interrupted apply resumes from it if --skip-restore is set")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false,
+		"only check the redo logs cover the range from the backup ts to the target ts")
+	// the possible error returned from MarkFlagRequired is `no such flag`
+	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
+}
+
+// run runs the `redo restore-to` command.
+func (o *restoreToOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	if o.dataEncryption.KeyFile != "" {
+		o.dataEncryption.Method = config.DataEncryptionMethodAES256
+	}
+	storages := append([]string{o.storage}, o.moreStorages...)
+	redoCfgs := make([]*applier.RedoApplierConfig, 0, len(storages))
+	for i, storage := range storages {
+		storage := storage
+		redoCfg := &applier.RedoApplierConfig{
+			Storage:        storage,
+			Dir:            o.dir,
+			DataEncryption: &o.dataEncryption,
+			WorkerCount:    o.workerCount,
+			OnProgress: func(appliedTs uint64) {
+				cmd.Printf("Applied redo log in %s to %d\n", applier.MaskStorageURI(storage), appliedTs)
+			},
+		}
+		// the download directory is cleaned up by each reader
+		if len(storages) > 1 && o.dir != "" {
+			redoCfg.Dir = filepath.Join(o.dir, strconv.Itoa(i))
+		}
+		if o.checkpointDir != "" {
+			redoCfg.CheckpointFile = filepath.Join(o.checkpointDir, fmt.Sprintf("restore-%d.checkpoint", i))
+		}
+		redoCfgs = append(redoCfgs, redoCfg)
+	}
+
+	cfg := &applier.RedoRestorerConfig{
+		SinkURI:       o.sinkURI,
+		BackupStorage: o.backupStorage,
+		BackupTs:      o.backupTs,
+		SkipRestore:   o.skipRestore,
+		TargetTs:      o.targetTs,
+		Redo:          redoCfgs,
+		DryRun:        o.dryRun,
+		OnPlan: func(plan *applier.RestorePlan) {
+			cmd.Printf("backup-ts:%d, target-ts:%d\n", plan.BackupTs, plan.TargetTs)
+			for _, rg := range plan.Ranges {
+				cmd.Printf("  %s: start-ts:%d, checkpoint-ts:%d, resolved-ts:%d\n",
+					applier.MaskStorageURI(rg.Storage), rg.StartTs, rg.CheckpointTs, rg.ResolvedTs)
+			}
+		},
+	}
+	err := applier.NewRedoRestorer(cfg).Run(ctx)
+	if err != nil {
+		return err
+	}
+	if !o.dryRun {
+		cmd.Println("Restore to target ts successfully")
+	}
+	return nil
+}
+
+// newCmdRestoreTo creates the `redo restore-to` command.
+func newCmdRestoreTo(opt *options) *cobra.Command {
+	o := newRestoreToOptions()
+	command := &cobra.Command{
+		Use:   "restore-to",
+		Short: "Restore the downstream to a point in time from a BR full backup and redo logs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
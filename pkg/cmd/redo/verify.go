@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/applier"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// verifyRedoOptions defines flags for the `redo verify` command.
+type verifyRedoOptions struct {
+	options
+	shadowURI      string
+	downstreamURI  string
+	changefeedID   string
+	dataEncryption config.DataEncryptionConfig
+	workerCount    int
+	checkpointFile string
+	interval       time.Duration
+}
+
+// newVerifyRedoOptions creates new verifyRedoOptions for the `redo verify` command.
+func newVerifyRedoOptions() *verifyRedoOptions {
+	return &verifyRedoOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *verifyRedoOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.shadowURI, "sink-uri", "",
+		"sink-uri of the shadow database the redo logs are applied to, "+
+			"it must be a copy of the downstream at the checkpoint of the redo logs")
+	cmd.Flags().StringVar(&o.downstreamURI, "downstream-uri", "",
+		"sink-uri of the downstream of the changefeed the shadow database is compared against")
+	cmd.Flags().StringVar(&o.changefeedID, "changefeed-id", "",
+		"the changefeed writing the redo logs, the downstream is compared at the latest syncpoint of it if it's set")
+	cmd.Flags().StringVar(&o.dataEncryption.KeyFile, "data-encryption-key-file", "",
+		"the data key file of the captures if the redo logs are encrypted")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSKeyID, "data-encryption-kms-key-id", "",
+		"the ID of the KMS key encrypting the data key")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSRegion, "data-encryption-kms-region", "",
+		"the region of the KMS key encrypting the data key")
+	cmd.Flags().StringVar(&o.dataEncryption.KMSEndpoint, "data-encryption-kms-endpoint", "",
+		"the endpoint of KMS")
+	cmd.Flags().IntVar(&o.workerCount, "worker-count", 1,
+		"the number of workers applying the redo logs in parallel, each of which has a sink of its own")
+	cmd.Flags().StringVar(&o.checkpointFile, "checkpoint-file", "",
+		"the file the progress of the shadow database is saved to")
+	cmd.Flags().DurationVar(&o.interval, "interval", 0,
+		"the interval the redo logs are verified, they are verified only once if it's 0")
+	// the possible error returned from MarkFlagRequired is `no such flag`
+	cmd.MarkFlagRequired("sink-uri")        //nolint:errcheck
+	cmd.MarkFlagRequired("downstream-uri")  //nolint:errcheck
+	cmd.MarkFlagRequired("checkpoint-file") //nolint:errcheck
+}
+
+// run runs the `redo verify` command.
+func (o *verifyRedoOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	if o.dataEncryption.KeyFile != "" {
+		o.dataEncryption.Method = config.DataEncryptionMethodAES256
+	}
+	cfg := &applier.RedoVerifierConfig{
+		RedoApplierConfig: applier.RedoApplierConfig{
+			Storage:        o.storage,
+			SinkURI:        o.shadowURI,
+			Dir:            o.dir,
+			DataEncryption: &o.dataEncryption,
+			WorkerCount:    o.workerCount,
+			CheckpointFile: o.checkpointFile,
+		},
+		DownstreamURI: o.downstreamURI,
+		ChangefeedID:  o.changefeedID,
+		Interval:      o.interval,
+		OnReport: func(report *applier.VerifyReport) {
+			cmd.Printf("Verified redo log at %d, downstream snapshot %d, %d tables, %d diverged\n",
+				report.AppliedTs, report.SnapshotTs, len(report.Tables), len(report.Diverged()))
+			for _, t := range report.Tables {
+				status := "consistent"
+				if !t.Consistent() {
+					status = "DIVERGED"
+				}
+				cmd.Printf("  %s.%s: %s, shadow rows %d checksum %d, downstream rows %d checksum %d",
+					t.Schema, t.Table, status, t.ShadowRows, t.ShadowChecksum,
+					t.DownstreamRows, t.DownstreamChecksum)
+				if t.Error != "" {
+					cmd.Printf(", error: %s", t.Error)
+				}
+				cmd.Println()
+			}
+		},
+	}
+	return applier.NewRedoVerifier(cfg).Run(ctx)
+}
+
+// newCmdVerify creates the `redo verify` command.
+func newCmdVerify(opt *options) *cobra.Command {
+	o := newVerifyRedoOptions()
+	command := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify redo logs by applying them to a shadow database and comparing it with the downstream",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
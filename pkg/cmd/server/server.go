@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
+	"github.com/pingcap/tiflow/pkg/secret"
 	"github.com/pingcap/tiflow/pkg/security"
 	ticdcutil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
@@ -126,6 +127,7 @@ func (o *options) run(cmd *cobra.Command) error {
 	}
 
 	config.StoreGlobalServerConfig(o.serverConfig)
+	secret.SetAllowlist(o.serverConfig.Secret.VaultPaths, o.serverConfig.Secret.K8sSecrets)
 	ctx := ticdcutil.PutTimezoneInCtx(cmdcontext.GetDefaultContext(), tz)
 	ctx = ticdcutil.PutCaptureAddrInCtx(ctx, o.serverConfig.AdvertiseAddr)
 
@@ -155,6 +155,7 @@ func TestParseCfg(t *testing.T) {
 		CaptureSessionTTL:      10,
 		OwnerFlushInterval:     config.TomlDuration(150 * time.Millisecond),
 		ProcessorFlushInterval: config.TomlDuration(150 * time.Millisecond),
+		PDStalenessBudget:      config.TomlDuration(time.Minute),
 		Sorter: &config.SorterConfig{
 			NumConcurrentWorker:    80,
 			ChunkSizeLimit:         50000000,
@@ -162,18 +163,27 @@ func TestParseCfg(t *testing.T) {
 			MaxMemoryConsumption:   60000,
 			NumWorkerPoolGoroutine: 90,
 			SortDir:                config.DefaultSortDir,
+			Compression:            config.SorterCompressionNone,
+		},
+		DataEncryption: &config.DataEncryptionConfig{
+			Method: config.DataEncryptionMethodPlaintext,
 		},
 		Security: &config.SecurityConfig{
 			CertPath:      "bb",
 			KeyPath:       "cc",
 			CertAllowedCN: []string{"dd", "ee"},
 		},
+		Secret:              &config.SecretConfig{},
 		PerTableMemoryQuota: 10 * 1024 * 1024, // 10M
 		KVClient: &config.KVClientConfig{
 			WorkerConcurrent: 8,
 			WorkerPoolSize:   0,
 			RegionScanLimit:  40,
 		},
+		MounterPool: &config.MounterPoolConfig{
+			MinWorkerNum:       1,
+			TargetQueueLatency: config.TomlDuration(5 * time.Millisecond),
+		},
 		Debug: &config.DebugConfig{
 			EnableTableActor: true,
 			TableActor: &config.TableActorConfig{
@@ -182,6 +192,7 @@ func TestParseCfg(t *testing.T) {
 			EnableDBSorter:     true,
 			EnableNewScheduler: true,
 			DB: &config.DBConfig{
+				Engine:                      config.DBEnginePebble,
 				Count:                       8,
 				Concurrency:                 128,
 				MaxOpenFiles:                10000,
@@ -193,6 +204,7 @@ func TestParseCfg(t *testing.T) {
 				WriteL0SlowdownTrigger:      math.MaxInt32,
 				WriteL0PauseTrigger:         math.MaxInt32,
 				CompactionL0Trigger:         160,
+				MaxConcurrentCompactions:    6,
 				CompactionDeletionThreshold: 10485760,
 				CompactionPeriod:            1800,
 				IteratorMaxAliveDuration:    10000,
@@ -230,6 +242,7 @@ capture-session-ttl = 10
 
 owner-flush-interval = "600ms"
 processor-flush-interval = "600ms"
+max-concurrent-ddl-per-downstream = 2
 
 [log.file]
 max-size = 200
@@ -244,6 +257,10 @@ num-concurrent-worker = 4
 num-workerpool-goroutine = 5
 sort-dir = "/tmp/just_a_test"
 
+[secret]
+vault-paths = ["secret/data/tidb"]
+k8s-secrets = ["tidb/*"]
+
 [debug]
 enable-db-sorter = false
 [debug.db]
@@ -296,12 +313,14 @@ server-worker-pool-size = 16
 			},
 			InternalErrOutput: "stderr",
 		},
-		DataDir:                dataDir,
-		GcTTL:                  500,
-		TZ:                     "US",
-		CaptureSessionTTL:      10,
-		OwnerFlushInterval:     config.TomlDuration(600 * time.Millisecond),
-		ProcessorFlushInterval: config.TomlDuration(600 * time.Millisecond),
+		DataDir:                       dataDir,
+		GcTTL:                         500,
+		TZ:                            "US",
+		CaptureSessionTTL:             10,
+		OwnerFlushInterval:            config.TomlDuration(600 * time.Millisecond),
+		ProcessorFlushInterval:        config.TomlDuration(600 * time.Millisecond),
+		PDStalenessBudget:             config.TomlDuration(time.Minute),
+		MaxConcurrentDDLPerDownstream: 2,
 		Sorter: &config.SorterConfig{
 			NumConcurrentWorker:    4,
 			ChunkSizeLimit:         10000000,
@@ -309,14 +328,26 @@ server-worker-pool-size = 16
 			MaxMemoryConsumption:   2000000,
 			NumWorkerPoolGoroutine: 5,
 			SortDir:                config.DefaultSortDir,
+			Compression:            config.SorterCompressionNone,
+		},
+		DataEncryption: &config.DataEncryptionConfig{
+			Method: config.DataEncryptionMethodPlaintext,
+		},
+		Security: &config.SecurityConfig{},
+		Secret: &config.SecretConfig{
+			VaultPaths: []string{"secret/data/tidb"},
+			K8sSecrets: []string{"tidb/*"},
 		},
-		Security:            &config.SecurityConfig{},
 		PerTableMemoryQuota: 10 * 1024 * 1024, // 10M
 		KVClient: &config.KVClientConfig{
 			WorkerConcurrent: 8,
 			WorkerPoolSize:   0,
 			RegionScanLimit:  40,
 		},
+		MounterPool: &config.MounterPoolConfig{
+			MinWorkerNum:       1,
+			TargetQueueLatency: config.TomlDuration(5 * time.Millisecond),
+		},
 		Debug: &config.DebugConfig{
 			EnableTableActor: true,
 			TableActor: &config.TableActorConfig{
@@ -325,6 +356,7 @@ server-worker-pool-size = 16
 			EnableDBSorter:     false,
 			EnableNewScheduler: true,
 			DB: &config.DBConfig{
+				Engine:                      config.DBEnginePebble,
 				Count:                       5,
 				Concurrency:                 6,
 				MaxOpenFiles:                7,
@@ -334,6 +366,7 @@ server-worker-pool-size = 16
 				Compression:                 "none",
 				TargetFileSizeBase:          10,
 				CompactionL0Trigger:         11,
+				MaxConcurrentCompactions:    6,
 				WriteL0SlowdownTrigger:      12,
 				WriteL0PauseTrigger:         13,
 				IteratorMaxAliveDuration:    10000,
@@ -439,6 +472,7 @@ cert-allowed-cn = ["dd","ee"]
 		CaptureSessionTTL:      10,
 		OwnerFlushInterval:     config.TomlDuration(150 * time.Millisecond),
 		ProcessorFlushInterval: config.TomlDuration(150 * time.Millisecond),
+		PDStalenessBudget:      config.TomlDuration(time.Minute),
 		Sorter: &config.SorterConfig{
 			NumConcurrentWorker:    3,
 			ChunkSizeLimit:         50000000,
@@ -446,18 +480,27 @@ cert-allowed-cn = ["dd","ee"]
 			MaxMemoryConsumption:   60000000,
 			NumWorkerPoolGoroutine: 5,
 			SortDir:                config.DefaultSortDir,
+			Compression:            config.SorterCompressionNone,
+		},
+		DataEncryption: &config.DataEncryptionConfig{
+			Method: config.DataEncryptionMethodPlaintext,
 		},
 		Security: &config.SecurityConfig{
 			CertPath:      "bb",
 			KeyPath:       "cc",
 			CertAllowedCN: []string{"dd", "ee"},
 		},
+		Secret:              &config.SecretConfig{},
 		PerTableMemoryQuota: 10 * 1024 * 1024, // 10M
 		KVClient: &config.KVClientConfig{
 			WorkerConcurrent: 8,
 			WorkerPoolSize:   0,
 			RegionScanLimit:  40,
 		},
+		MounterPool: &config.MounterPoolConfig{
+			MinWorkerNum:       1,
+			TargetQueueLatency: config.TomlDuration(5 * time.Millisecond),
+		},
 		Debug: &config.DebugConfig{
 			EnableTableActor: true,
 			TableActor: &config.TableActorConfig{
@@ -466,6 +509,7 @@ cert-allowed-cn = ["dd","ee"]
 			EnableDBSorter:     true,
 			EnableNewScheduler: true,
 			DB: &config.DBConfig{
+				Engine:                      config.DBEnginePebble,
 				Count:                       8,
 				Concurrency:                 128,
 				MaxOpenFiles:                10000,
@@ -477,6 +521,7 @@ cert-allowed-cn = ["dd","ee"]
 				WriteL0SlowdownTrigger:      math.MaxInt32,
 				WriteL0PauseTrigger:         math.MaxInt32,
 				CompactionL0Trigger:         160,
+				MaxConcurrentCompactions:    6,
 				CompactionDeletionThreshold: 10485760,
 				CompactionPeriod:            1800,
 				IteratorMaxAliveDuration:    10000,
@@ -526,6 +571,7 @@ unknown3 = 3
 		EnableDBSorter:     true,
 		EnableNewScheduler: true,
 		DB: &config.DBConfig{
+			Engine:                      config.DBEnginePebble,
 			Count:                       8,
 			Concurrency:                 128,
 			MaxOpenFiles:                10000,
@@ -537,6 +583,7 @@ unknown3 = 3
 			WriteL0SlowdownTrigger:      math.MaxInt32,
 			WriteL0PauseTrigger:         math.MaxInt32,
 			CompactionL0Trigger:         160,
+			MaxConcurrentCompactions:    6,
 			CompactionDeletionThreshold: 10485760,
 			CompactionPeriod:            1800,
 			IteratorMaxAliveDuration:    10000,
@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"time"
+
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Table priorities of admission control.
+const (
+	// TablePriorityHigh tables are never paused by admission control.
+	TablePriorityHigh = "high"
+	// TablePriorityNormal tables are paused when the changefeed is overloaded
+	// for twice overload-duration-in-sec.
+	TablePriorityNormal = "normal"
+	// TablePriorityLow tables are paused when the changefeed is overloaded
+	// for overload-duration-in-sec.
+	TablePriorityLow = "low"
+)
+
+// AdmissionControlConfig represents the admission control config for a changefeed.
+// When the events of a changefeed are ingested faster than the sink drains them for
+// a sustained period, they pile up in the sorter. Admission control pauses pulling
+// tables by their priorities then, instead of letting the sorter grow unboundedly.
+type AdmissionControlConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// OverloadDurationInSec is how long the ingest rate of the changefeed exceeds
+	// the drain rate of the sink before the low priority tables are paused.
+	OverloadDurationInSec int64 `toml:"overload-duration-in-sec" json:"overload-duration-in-sec"`
+	// TablePriorities are the priorities of tables, the first matched one is used,
+	// tables matching no rule are TablePriorityNormal.
+	TablePriorities []*TablePriorityRule `toml:"table-priorities" json:"table-priorities"`
+}
+
+// TablePriorityRule represents the priority of tables.
+type TablePriorityRule struct {
+	Matcher  []string `toml:"matcher" json:"matcher"`
+	Priority string   `toml:"priority" json:"priority"`
+}
+
+func (c *AdmissionControlConfig) validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.OverloadDurationInSec <= 0 {
+		return cerror.ErrInvalidAdmissionControlConfig.GenWithStackByArgs(
+			"overload-duration-in-sec should be greater than 0")
+	}
+	for _, rule := range c.TablePriorities {
+		if _, err := filter.Parse(rule.Matcher); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+		switch rule.Priority {
+		case TablePriorityHigh, TablePriorityNormal, TablePriorityLow:
+		default:
+			return cerror.ErrInvalidAdmissionControlConfig.GenWithStackByArgs(
+				"unknown table priority " + rule.Priority)
+		}
+	}
+	return nil
+}
+
+// OverloadDuration returns how long the changefeed is overloaded before the low
+// priority tables are paused, 0 means admission control is disabled.
+func (c *AdmissionControlConfig) OverloadDuration() time.Duration {
+	if c == nil || !c.Enable {
+		return 0
+	}
+	return time.Duration(c.OverloadDurationInSec) * time.Second
+}
+
+// TablePriority returns the priority of the table.
+func (c *AdmissionControlConfig) TablePriority(caseSensitive bool, schema, table string) string {
+	if c == nil {
+		return TablePriorityNormal
+	}
+	for _, rule := range c.TablePriorities {
+		f, err := filter.Parse(rule.Matcher)
+		if err != nil {
+			// the rules have been validated.
+			continue
+		}
+		if !caseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		if f.MatchTable(schema, table) {
+			return rule.Priority
+		}
+	}
+	return TablePriorityNormal
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionControlValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &AdmissionControlConfig{OverloadDurationInSec: -1}
+	require.Nil(t, cfg.validate())
+
+	cfg.Enable = true
+	require.Regexp(t, ".*overload-duration-in-sec should be greater than 0.*", cfg.validate())
+
+	cfg.OverloadDurationInSec = 60
+	cfg.TablePriorities = []*TablePriorityRule{
+		{Matcher: []string{"test.*"}, Priority: TablePriorityHigh},
+	}
+	require.Nil(t, cfg.validate())
+
+	cfg.TablePriorities[0].Priority = "urgent"
+	require.Regexp(t, ".*unknown table priority urgent.*", cfg.validate())
+
+	cfg.TablePriorities[0] = &TablePriorityRule{Matcher: []string{"[test.t"}, Priority: TablePriorityLow}
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", cfg.validate())
+}
+
+func TestAdmissionControlOverloadDuration(t *testing.T) {
+	t.Parallel()
+
+	var cfg *AdmissionControlConfig
+	require.Equal(t, time.Duration(0), cfg.OverloadDuration())
+
+	cfg = &AdmissionControlConfig{OverloadDurationInSec: 60}
+	require.Equal(t, time.Duration(0), cfg.OverloadDuration())
+	cfg.Enable = true
+	require.Equal(t, time.Minute, cfg.OverloadDuration())
+}
+
+func TestTablePriority(t *testing.T) {
+	t.Parallel()
+
+	var cfg *AdmissionControlConfig
+	require.Equal(t, TablePriorityNormal, cfg.TablePriority(true, "test", "t"))
+
+	cfg = &AdmissionControlConfig{
+		Enable:                true,
+		OverloadDurationInSec: 60,
+		TablePriorities: []*TablePriorityRule{
+			{Matcher: []string{"test.high"}, Priority: TablePriorityHigh},
+			{Matcher: []string{"test.*"}, Priority: TablePriorityLow},
+		},
+	}
+	require.Equal(t, TablePriorityHigh, cfg.TablePriority(true, "test", "high"))
+	require.Equal(t, TablePriorityLow, cfg.TablePriority(true, "test", "t"))
+	require.Equal(t, TablePriorityNormal, cfg.TablePriority(true, "TEST", "t"))
+	require.Equal(t, TablePriorityLow, cfg.TablePriority(false, "TEST", "t"))
+	require.Equal(t, TablePriorityNormal, cfg.TablePriority(true, "other", "t"))
+}
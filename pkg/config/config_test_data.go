@@ -65,6 +65,42 @@ const (
     "max-log-size": 64,
     "flush-interval": 1000,
     "storage": ""
+  },
+  "admission-control": {
+    "enable": false,
+    "overload-duration-in-sec": 60,
+    "table-priorities": null
+  },
+  "incremental-scan": {
+    "region-concurrency": 0,
+    "bytes-per-second": 0
+  },
+  "schema-drift": {
+    "enable": false,
+    "check-interval-in-sec": 600
+  },
+  "data-contract": {
+    "registry": "",
+    "tables": null
+  },
+  "watermark-alignment": {
+    "group": ""
+  },
+  "sorter": {
+    "max-memory-consumption": 0,
+    "max-disk-consumption-per-table": 0,
+    "sort-dir": ""
+  },
+  "health-check": {
+    "enable": false,
+    "upstream-dsn": "",
+    "table": "tidb_cdc.canary",
+    "interval-in-sec": 10,
+    "max-latency-in-sec": 60
+  },
+  "hibernation": {
+    "enable": false,
+    "idle-timeout-in-sec": 300
   }
 }`
 
@@ -87,33 +123,60 @@ const (
   "capture-session-ttl": 10,
   "owner-flush-interval": 200000000,
   "processor-flush-interval": 100000000,
+  "pd-staleness-budget": 60000000000,
+  "max-concurrent-ddl-per-downstream": 0,
   "sorter": {
     "num-concurrent-worker": 4,
     "chunk-size-limit": 999,
     "max-memory-percentage": 30,
     "max-memory-consumption": 17179869184,
     "num-workerpool-goroutine": 16,
-    "sort-dir": "/tmp/sorter"
+    "sort-dir": "/tmp/sorter",
+    "compression": "none"
+  },
+  "data-encryption": {
+    "method": "plaintext",
+    "key-file": "",
+    "kms-key-id": "",
+    "kms-region": "",
+    "kms-endpoint": ""
   },
   "security": {
     "ca-path": "",
     "cert-path": "",
     "key-path": "",
-    "cert-allowed-cn": null
+    "cert-allowed-cn": null,
+    "cert-allowed-san": null,
+    "cert-allowed-spki": null
+  },
+  "secret": {
+    "vault-paths": null,
+    "k8s-secrets": null
   },
   "per-table-memory-quota": 10485760,
+  "capture-memory-quota": 0,
   "kv-client": {
     "worker-concurrent": 8,
     "worker-pool-size": 0,
-    "region-scan-limit": 40
+    "region-scan-limit": 40,
+    "enable-shared-puller": false
+  },
+  "mounter-pool": {
+    "worker-num": 0,
+    "enable-auto-tune": false,
+    "min-worker-num": 1,
+    "max-worker-num": 0,
+    "target-queue-latency": 5000000
   },
   "debug": {
     "enable-table-actor": true,
     "table-actor": {
-      "event-batch-size": 32
+      "event-batch-size": 32,
+      "system-count": 0
     },
     "enable-db-sorter": true,
     "db": {
+      "engine": "pebble",
       "count": 8,
       "concurrency": 128,
       "max-open-files": 10000,
@@ -125,6 +188,7 @@ const (
       "write-l0-slowdown-trigger": 2147483647,
       "write-l0-pause-trigger": 2147483647,
       "compaction-l0-trigger": 160,
+      "max-concurrent-compactions": 6,
       "compaction-deletion-threshold": 10485760,
       "compaction-period": 1800,
       "iterator-max-alive-duration": 10000,
@@ -139,7 +203,8 @@ const (
       "server-max-pending-message-count": 102400,
       "server-ack-interval": 100000000,
       "server-worker-pool-size": 4
-    }
+    },
+    "enable-two-phase-table-move": false
   }
 }`
 
@@ -170,7 +235,11 @@ const (
           "b"
         ]
       }
-    ]
+    ],
+    "extra-sink-uris": null,
+    "ddl-compatibility": "",
+    "field-mappings": null,
+    "transforms": null
   },
   "cyclic-replication": {
     "enable": false,
@@ -187,7 +256,47 @@ const (
     "level": "none",
     "max-log-size": 64,
     "flush-interval": 1000,
-    "storage": ""
+    "storage": "",
+    "max-batch-size": 4,
+    "retention-hours": 0,
+    "retention-size": 0,
+    "archive-storage": ""
+  },
+  "admission-control": {
+    "enable": false,
+    "overload-duration-in-sec": 60,
+    "table-priorities": null
+  },
+  "incremental-scan": {
+    "region-concurrency": 0,
+    "bytes-per-second": 0
+  },
+  "schema-drift": {
+    "enable": false,
+    "check-interval-in-sec": 600
+  },
+  "data-contract": {
+    "registry": "",
+    "tables": null
+  },
+  "watermark-alignment": {
+    "group": ""
+  },
+  "sorter": {
+    "max-memory-consumption": 0,
+    "max-disk-consumption-per-table": 0,
+    "sort-dir": ""
+  },
+  "health-check": {
+    "enable": false,
+    "upstream-dsn": "",
+    "table": "tidb_cdc.canary",
+    "interval-in-sec": 10,
+    "max-latency-in-sec": 60
+  },
+  "hibernation": {
+    "enable": false,
+    "idle-timeout-in-sec": 300
   }
 }`
 
@@ -218,7 +327,11 @@ const (
           "b"
         ]
       }
-    ]
+    ],
+    "extra-sink-uris": null,
+    "ddl-compatibility": "",
+    "field-mappings": null,
+    "transforms": null
   },
   "cyclic-replication": {
     "enable": false,
@@ -235,7 +348,47 @@ const (
     "level": "none",
     "max-log-size": 64,
     "flush-interval": 1000,
-    "storage": ""
+    "storage": "",
+    "max-batch-size": 4,
+    "retention-hours": 0,
+    "retention-size": 0,
+    "archive-storage": ""
+  },
+  "admission-control": {
+    "enable": false,
+    "overload-duration-in-sec": 60,
+    "table-priorities": null
+  },
+  "incremental-scan": {
+    "region-concurrency": 0,
+    "bytes-per-second": 0
+  },
+  "schema-drift": {
+    "enable": false,
+    "check-interval-in-sec": 600
+  },
+  "data-contract": {
+    "registry": "",
+    "tables": null
+  },
+  "watermark-alignment": {
+    "group": ""
+  },
+  "sorter": {
+    "max-memory-consumption": 0,
+    "max-disk-consumption-per-table": 0,
+    "sort-dir": ""
+  },
+  "health-check": {
+    "enable": false,
+    "upstream-dsn": "",
+    "table": "tidb_cdc.canary",
+    "interval-in-sec": 10,
+    "max-latency-in-sec": 60
+  },
+  "hibernation": {
+    "enable": false,
+    "idle-timeout-in-sec": 300
   }
 }`
 )
@@ -13,10 +13,51 @@
 
 package config
 
+import (
+	"net/url"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
 // ConsistentConfig represents replication consistency config for a changefeed
 type ConsistentConfig struct {
 	Level             string `toml:"level" json:"level"`
 	MaxLogSize        int64  `toml:"max-log-size" json:"max-log-size"`
 	FlushIntervalInMs int64  `toml:"flush-interval" json:"flush-interval"`
 	Storage           string `toml:"storage" json:"storage"`
+	// MaxBatchSize is the size in megabyte of the redo logs buffered since the
+	// last flush, which triggers a flush before the flush interval elapses.
+	// The logs are only flushed by the flush interval if it's 0.
+	MaxBatchSize int64 `toml:"max-batch-size" json:"max-batch-size"`
+	// RetentionHours is how long redo logs are kept after the checkpoint passes
+	// them, 0 means they are removed as soon as the checkpoint passes them.
+	RetentionHours int64 `toml:"retention-hours" json:"retention-hours"`
+	// RetentionSize is the max size in megabyte of the redo logs in the
+	// storage, the oldest redo logs passed by the checkpoint are removed once
+	// it's exceeded even if they're in the retention, 0 means unlimited.
+	RetentionSize int64 `toml:"retention-size" json:"retention-size"`
+	// ArchiveStorage is the external storage the expired redo logs are moved to,
+	// they are removed directly if it's empty.
+	ArchiveStorage string `toml:"archive-storage" json:"archive-storage"`
+}
+
+func (c *ConsistentConfig) validate() error {
+	if c.MaxBatchSize < 0 {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"max-batch-size %d of redo logs must not be negative", c.MaxBatchSize)
+	}
+	if c.RetentionHours < 0 {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"retention-hours %d of redo logs must not be negative", c.RetentionHours)
+	}
+	if c.RetentionSize < 0 {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"retention-size %d of redo logs must not be negative", c.RetentionSize)
+	}
+	if c.ArchiveStorage != "" {
+		if _, err := url.Parse(c.ArchiveStorage); err != nil {
+			return cerror.WrapError(cerror.ErrRedoConfigInvalid, err)
+		}
+	}
+	return nil
 }
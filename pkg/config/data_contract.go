@@ -0,0 +1,84 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/url"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// DataContractConfig represents the data contracts of the downstream of a
+// changefeed, a contract declares the columns the downstream consumers of a
+// table require. The owner validates every DDL against the contracts, and the
+// changefeed fails before the DDL is emitted if it would break any contract.
+type DataContractConfig struct {
+	// Registry is the http(s) or file URI of an external schema registry
+	// serving the contracts in JSON, e.g. {"tables": [...]}. The contracts
+	// from the registry are used together with the Tables.
+	Registry string           `toml:"registry" json:"registry"`
+	Tables   []*TableContract `toml:"tables" json:"tables"`
+}
+
+// TableContract is the contract of the tables matched by the Matcher.
+type TableContract struct {
+	Matcher []string          `toml:"matcher" json:"matcher"`
+	Columns []*ColumnContract `toml:"columns" json:"columns"`
+}
+
+// ColumnContract is a column required by a contract. The column may be of any
+// type if the Type is empty, otherwise it must be of the Type, e.g. "bigint".
+type ColumnContract struct {
+	Name string `toml:"name" json:"name"`
+	Type string `toml:"type" json:"type"`
+}
+
+// IsEnabled returns true if any contract is declared.
+func (c *DataContractConfig) IsEnabled() bool {
+	return c != nil && (c.Registry != "" || len(c.Tables) > 0)
+}
+
+func (c *DataContractConfig) validate() error {
+	if c.Registry != "" {
+		uri, err := url.Parse(c.Registry)
+		if err != nil {
+			return cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(err.Error())
+		}
+		switch uri.Scheme {
+		case "http", "https", "file":
+		default:
+			return cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(
+				"the scheme of registry should be one of http, https and file")
+		}
+	}
+	return ValidateTableContracts(c.Tables)
+}
+
+// ValidateTableContracts verifies the table contracts, it's also used to
+// verify the contracts served by the registries.
+func ValidateTableContracts(contracts []*TableContract) error {
+	for _, contract := range contracts {
+		if len(contract.Matcher) == 0 {
+			return cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(
+				"matcher of a table contract should not be empty")
+		}
+		for _, col := range contract.Columns {
+			if col.Name == "" {
+				return cerror.ErrInvalidDataContractConfig.GenWithStackByArgs(
+					"name of a column contract should not be empty")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataContractValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &DataContractConfig{}
+	require.False(t, cfg.IsEnabled())
+	require.Nil(t, cfg.validate())
+
+	cfg.Registry = "kafka://127.0.0.1:9092"
+	require.True(t, cfg.IsEnabled())
+	require.Regexp(t, ".*the scheme of registry should be one of.*", cfg.validate())
+	cfg.Registry = "https://registry.example.com/contracts"
+	require.Nil(t, cfg.validate())
+
+	cfg.Tables = []*TableContract{{Columns: []*ColumnContract{{Name: "id"}}}}
+	require.Regexp(t, ".*matcher of a table contract should not be empty.*", cfg.validate())
+	cfg.Tables[0].Matcher = []string{"test.*"}
+	require.Nil(t, cfg.validate())
+	cfg.Tables[0].Columns = append(cfg.Tables[0].Columns, &ColumnContract{Type: "int"})
+	require.Regexp(t, ".*name of a column contract should not be empty.*", cfg.validate())
+}
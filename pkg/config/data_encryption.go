@@ -0,0 +1,66 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The methods of data encryption.
+const (
+	// DataEncryptionMethodPlaintext doesn't encrypt data.
+	DataEncryptionMethodPlaintext = "plaintext"
+	// DataEncryptionMethodAES256 encrypts data by AES with a 256 bits key.
+	DataEncryptionMethodAES256 = "aes256"
+)
+
+// DataEncryptionConfig represents the encryption of the temporary data written
+// to local disks by a capture, i.e. the files of the sorters and redo logs.
+type DataEncryptionConfig struct {
+	// Method is plaintext or aes256.
+	Method string `toml:"method" json:"method"`
+	// KeyFile is the path of the file storing the data key. The file contains
+	// the hex encoded key, or the key encrypted by KMS if KMSKeyID is set.
+	KeyFile string `toml:"key-file" json:"key-file"`
+	// KMSKeyID is the ID of the AWS KMS key which encrypts the data key.
+	KMSKeyID string `toml:"kms-key-id" json:"kms-key-id"`
+	// KMSRegion is the region of the KMS key.
+	KMSRegion string `toml:"kms-region" json:"kms-region"`
+	// KMSEndpoint is the endpoint of KMS, it's the default endpoint of the
+	// region if it's empty.
+	KMSEndpoint string `toml:"kms-endpoint" json:"kms-endpoint"`
+}
+
+// ValidateAndAdjust validates and adjusts the data encryption configuration.
+func (c *DataEncryptionConfig) ValidateAndAdjust() error {
+	switch c.Method {
+	case DataEncryptionMethodPlaintext:
+	case "":
+		c.Method = DataEncryptionMethodPlaintext
+	case DataEncryptionMethodAES256:
+		if c.KeyFile == "" {
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"data-encryption.key-file must be set if the method is aes256")
+		}
+	default:
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"data-encryption.method should be plaintext or aes256")
+	}
+	return nil
+}
+
+// IsEnabled returns whether data is encrypted.
+func (c *DataEncryptionConfig) IsEnabled() bool {
+	return c != nil && c.Method == DataEncryptionMethodAES256
+}
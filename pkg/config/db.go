@@ -17,8 +17,19 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
+// The storage engines of the db sorter.
+const (
+	DBEnginePebble  = "pebble"
+	DBEngineLevelDB = "leveldb"
+)
+
 // DBConfig represents leveldb sorter config.
 type DBConfig struct {
+	// Engine is the storage engine of the db sorter.
+	// Valid values are "pebble" or "leveldb".
+	//
+	// The default value is "pebble".
+	Engine string `toml:"engine" json:"engine"`
 	// Count is the number of leveldb count.
 	//
 	// The default value is 16.
@@ -68,6 +79,12 @@ type DBConfig struct {
 	//
 	// The default value is 160.
 	CompactionL0Trigger int `toml:"compaction-l0-trigger" json:"compaction-l0-trigger"`
+	// MaxConcurrentCompactions bounds the number of concurrent compactions of
+	// each pebble db, so compactions don't starve memtable flushes and stall
+	// writes when a lot of data is written, e.g. in a catch-up.
+	//
+	// The default value is 6.
+	MaxConcurrentCompactions int `toml:"max-concurrent-compactions" json:"max-concurrent-compactions"`
 	// CompactionDeletionThreshold defines the threshold of the number of deletion that
 	// trigger compaction.
 	//
@@ -94,6 +111,12 @@ type DBConfig struct {
 
 // ValidateAndAdjust validates and adjusts the db configuration
 func (c *DBConfig) ValidateAndAdjust() error {
+	if c.Engine != DBEnginePebble && c.Engine != DBEngineLevelDB {
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("sorter.leveldb.engine must be \"pebble\" or \"leveldb\"")
+	}
+	if c.MaxConcurrentCompactions < 1 {
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("sorter.leveldb.max-concurrent-compactions should be at least 1")
+	}
 	if c.Compression != "none" && c.Compression != "snappy" {
 		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("sorter.leveldb.compression must be \"none\" or \"snappy\"")
 	}
@@ -31,6 +31,19 @@ type DebugConfig struct {
 	// The default value is true.
 	EnableNewScheduler bool            `toml:"enable-new-scheduler" json:"enable-new-scheduler"`
 	Messages           *MessagesConfig `toml:"messages" json:"messages"`
+
+	// EnableTwoPhaseTableMove makes the new scheduler start the pipeline of a
+	// table being moved on the target capture in advance, and stop it on the
+	// source capture only after the target has caught up. It must not be
+	// enabled until all captures are upgraded to a version that supports it.
+	//
+	// The default value is false.
+	EnableTwoPhaseTableMove bool `toml:"enable-two-phase-table-move" json:"enable-two-phase-table-move"`
+
+	// Recorder records the raw events fed to table pipelines into local files,
+	// so the pipelines can be replayed to reproduce sorter or sink bugs.
+	// It's disabled if nil.
+	Recorder *RecorderConfig `toml:"recorder" json:"recorder,omitempty"`
 }
 
 // ValidateAndAdjust validates and adjusts the debug configuration
@@ -41,5 +54,10 @@ func (c *DebugConfig) ValidateAndAdjust() error {
 	if err := c.DB.ValidateAndAdjust(); err != nil {
 		return errors.Trace(err)
 	}
+	if c.Recorder != nil {
+		if err := c.Recorder.ValidateAndAdjust(); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
@@ -24,4 +24,17 @@ type FilterConfig struct {
 	*filter.MySQLReplicationRules
 	IgnoreTxnStartTs []uint64           `toml:"ignore-txn-start-ts" json:"ignore-txn-start-ts"`
 	DDLAllowlist     []model.ActionType `toml:"ddl-allow-list" json:"ddl-allow-list,omitempty"`
+	// FreezeTables pins the replicated tables to the ones matched when the
+	// changefeed is created. Tables created later are not replicated unless
+	// they are approved explicitly through the OpenAPI.
+	FreezeTables bool `toml:"freeze-tables" json:"freeze-tables,omitempty"`
+	// EventFilters drop the DML events of specific types of tables.
+	EventFilters []*EventFilterRule `toml:"event-filters" json:"event-filters,omitempty"`
+}
+
+// EventFilterRule ignores the DML events of types IgnoreEvent of the tables
+// matched by Matcher, the valid event types are insert, update and delete.
+type EventFilterRule struct {
+	Matcher     []string `toml:"matcher" json:"matcher"`
+	IgnoreEvent []string `toml:"ignore-event" json:"ignore-event"`
 }
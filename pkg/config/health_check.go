@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// HealthCheckConfig represents the canary health check of a changefeed. When
+// enabled, the owner writes a canary row to a table of the upstream TiDB
+// periodically, and measures the end-to-end latency until the checkpoint of
+// the changefeed passes the commit ts of the row, i.e. the row has been
+// written to the downstream. The changefeed is unhealthy if the latency
+// exceeds MaxLatencyInSec.
+type HealthCheckConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// UpstreamDSN is the DSN of the upstream TiDB the canary rows are written
+	// through, e.g. "root:password@tcp(127.0.0.1:4000)/". If it's empty, no
+	// canary row is written and the latency is the lag of the checkpoint,
+	// which works with an existing heartbeat table keeping the changefeed busy.
+	UpstreamDSN string `toml:"upstream-dsn" json:"upstream-dsn"`
+	// Table is the table the canary rows are written to, in the form of
+	// schema.table. It's created if it doesn't exist, and it must be
+	// replicated by the changefeed.
+	Table string `toml:"table" json:"table"`
+	// IntervalInSec is the interval of writing canary rows in seconds.
+	IntervalInSec int64 `toml:"interval-in-sec" json:"interval-in-sec"`
+	// MaxLatencyInSec is the max end-to-end latency of a healthy changefeed
+	// in seconds.
+	MaxLatencyInSec int64 `toml:"max-latency-in-sec" json:"max-latency-in-sec"`
+}
+
+func (c *HealthCheckConfig) validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.IntervalInSec <= 0 {
+		return cerror.ErrInvalidHealthCheckConfig.GenWithStackByArgs(
+			"interval-in-sec should be greater than 0")
+	}
+	if c.MaxLatencyInSec <= 0 {
+		return cerror.ErrInvalidHealthCheckConfig.GenWithStackByArgs(
+			"max-latency-in-sec should be greater than 0")
+	}
+	if c.UpstreamDSN != "" {
+		if _, _, ok := c.CanaryTable(); !ok {
+			return cerror.ErrInvalidHealthCheckConfig.GenWithStackByArgs(
+				"table should be in the form of schema.table")
+		}
+	}
+	return nil
+}
+
+// CanaryTable returns the schema and the name of the canary table, ok is
+// false if Table is malformed.
+func (c *HealthCheckConfig) CanaryTable() (schema, table string, ok bool) {
+	parts := strings.Split(c.Table, ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
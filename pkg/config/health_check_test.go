@@ -0,0 +1,45 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &HealthCheckConfig{}
+	require.Nil(t, cfg.validate())
+	cfg.Enable = true
+	require.Regexp(t, ".*interval-in-sec should be greater than 0.*", cfg.validate())
+	cfg.IntervalInSec = 10
+	require.Regexp(t, ".*max-latency-in-sec should be greater than 0.*", cfg.validate())
+	cfg.MaxLatencyInSec = 60
+	require.Nil(t, cfg.validate())
+
+	// The table is checked only if canary rows are written.
+	cfg.Table = "canary"
+	require.Nil(t, cfg.validate())
+	cfg.UpstreamDSN = "root@tcp(127.0.0.1:4000)/"
+	require.Regexp(t, ".*table should be in the form of schema.table.*", cfg.validate())
+	cfg.Table = "tidb_cdc.canary"
+	require.Nil(t, cfg.validate())
+	schema, table, ok := cfg.CanaryTable()
+	require.True(t, ok)
+	require.Equal(t, "tidb_cdc", schema)
+	require.Equal(t, "canary", table)
+}
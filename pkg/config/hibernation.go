@@ -0,0 +1,52 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"time"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// HibernationConfig represents the idle table hibernation config for a changefeed.
+// A table receiving no row changes for IdleTimeoutInSec is hibernated, its sorter
+// is closed and its memory quota is given back to the busy tables, and its puller
+// is closed too. The resolved ts is forwarded by a short-lived puller started every
+// few seconds, and the table wakes up with a new sorter on the next row change.
+// It only works with the table actor.
+type HibernationConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// IdleTimeoutInSec is how long a table receives no row changes before it's hibernated.
+	IdleTimeoutInSec int64 `toml:"idle-timeout-in-sec" json:"idle-timeout-in-sec"`
+}
+
+func (c *HibernationConfig) validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.IdleTimeoutInSec <= 0 {
+		return cerror.ErrInvalidHibernationConfig.GenWithStackByArgs(
+			"idle-timeout-in-sec should be greater than 0")
+	}
+	return nil
+}
+
+// IdleTimeout returns how long a table is idle before it's hibernated,
+// 0 means tables are never hibernated.
+func (c *HibernationConfig) IdleTimeout() time.Duration {
+	if c == nil || !c.Enable {
+		return 0
+	}
+	return time.Duration(c.IdleTimeoutInSec) * time.Second
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHibernationValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &HibernationConfig{}
+	require.Nil(t, cfg.validate())
+
+	cfg.Enable = true
+	require.Regexp(t, ".*idle-timeout-in-sec should be greater than 0.*", cfg.validate())
+
+	cfg.IdleTimeoutInSec = 60
+	require.Nil(t, cfg.validate())
+}
+
+func TestHibernationIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	var cfg *HibernationConfig
+	require.Equal(t, time.Duration(0), cfg.IdleTimeout())
+
+	cfg = &HibernationConfig{IdleTimeoutInSec: 60}
+	require.Equal(t, time.Duration(0), cfg.IdleTimeout())
+
+	cfg.Enable = true
+	require.Equal(t, time.Minute, cfg.IdleTimeout())
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// IncrementalScanConfig represents the config of incremental scans of table pullers.
+type IncrementalScanConfig struct {
+	// RegionConcurrency limits the regions of the changefeed scanned
+	// concurrently on a capture, the other regions wait in a queue. 0 means
+	// unlimited. It can be adjusted when the changefeed is running.
+	RegionConcurrency int `toml:"region-concurrency" json:"region-concurrency"`
+	// BytesPerSecond limits the bytes scanned per second for the changefeed on
+	// a capture, 0 means unlimited. It can be adjusted when the changefeed is
+	// running.
+	BytesPerSecond int64 `toml:"bytes-per-second" json:"bytes-per-second"`
+}
+
+func (c *IncrementalScanConfig) validate() error {
+	if c.RegionConcurrency < 0 {
+		return cerror.ErrInvalidIncrementalScanConfig.GenWithStackByArgs(
+			"region-concurrency must not be negative")
+	}
+	if c.BytesPerSecond < 0 {
+		return cerror.ErrInvalidIncrementalScanConfig.GenWithStackByArgs(
+			"bytes-per-second must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrementalScanValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &IncrementalScanConfig{}
+	require.Nil(t, cfg.validate())
+
+	cfg.RegionConcurrency = -1
+	require.Regexp(t, ".*region-concurrency must not be negative.*", cfg.validate())
+	cfg.RegionConcurrency = 4
+	cfg.BytesPerSecond = -1
+	require.Regexp(t, ".*bytes-per-second must not be negative.*", cfg.validate())
+	cfg.BytesPerSecond = 1 << 20
+	require.Nil(t, cfg.validate())
+}
@@ -21,4 +21,7 @@ type KVClientConfig struct {
 	WorkerPoolSize int `toml:"worker-pool-size" json:"worker-pool-size"`
 	// region incremental scan limit for one table in a single store
 	RegionScanLimit int `toml:"region-scan-limit" json:"region-scan-limit"`
+	// whether the table pullers of the same tables share one region subscription,
+	// instead of each changefeed pulling the same region deltas from TiKV.
+	EnableSharedPuller bool `toml:"enable-shared-puller" json:"enable-shared-puller"`
 }
@@ -13,7 +13,53 @@
 
 package config
 
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
 // MounterConfig represents mounter config for a changefeed
 type MounterConfig struct {
 	WorkerNum int `toml:"worker-num" json:"worker-num"`
 }
+
+// MounterPoolConfig represents the config of the mounter worker pool, which
+// limits the number of events decoded concurrently by all changefeeds of a capture.
+type MounterPoolConfig struct {
+	// WorkerNum is the number of workers, it's the initial number if the auto
+	// tuning is enabled. 0 means the concurrency is not limited, or the number
+	// of CPUs if the auto tuning is enabled.
+	WorkerNum int `toml:"worker-num" json:"worker-num"`
+	// EnableAutoTune enables tuning the number of workers between MinWorkerNum
+	// and MaxWorkerNum by the latency the events wait for a worker.
+	EnableAutoTune bool `toml:"enable-auto-tune" json:"enable-auto-tune"`
+	MinWorkerNum   int  `toml:"min-worker-num" json:"min-worker-num"`
+	// MaxWorkerNum is the max number of workers, 0 means 4 times the number of CPUs.
+	MaxWorkerNum int `toml:"max-worker-num" json:"max-worker-num"`
+	// TargetQueueLatency is the average latency the events wait for a worker,
+	// workers are added if it's exceeded.
+	TargetQueueLatency TomlDuration `toml:"target-queue-latency" json:"target-queue-latency"`
+}
+
+// ValidateAndAdjust validates and adjusts the mounter worker pool configuration.
+func (c *MounterPoolConfig) ValidateAndAdjust() error {
+	if c.WorkerNum < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"mounter-pool.worker-num should be greater than or equal to 0")
+	}
+	if !c.EnableAutoTune {
+		return nil
+	}
+	if c.MinWorkerNum <= 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"mounter-pool.min-worker-num should be greater than 0")
+	}
+	if c.MaxWorkerNum != 0 && c.MaxWorkerNum < c.MinWorkerNum {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"mounter-pool.max-worker-num should be greater than or equal to min-worker-num")
+	}
+	if c.TargetQueueLatency <= 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			"mounter-pool.target-queue-latency should be greater than 0")
+	}
+	return nil
+}
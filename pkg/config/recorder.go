@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"path/filepath"
+
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// defaultRecorderMaxFileSize is the default max size of a record file.
+const defaultRecorderMaxFileSize = 1024 * 1024 * 1024 // 1GB
+
+// RecorderConfig represents config for recording the raw events fed to table
+// pipelines. Every table pipeline is recorded into its own file in Dir.
+// In the replay mode, the table pipelines are replayed from the files instead.
+type RecorderConfig struct {
+	// Dir is the directory of the record files.
+	Dir string `toml:"dir" json:"dir"`
+	// Changefeeds are the IDs of the changefeeds recorded, empty means all.
+	Changefeeds []string `toml:"changefeeds" json:"changefeeds"`
+	// MaxFileSize is the max size of a record file in bytes, the recording
+	// of a table pipeline stops once its file reaches it.
+	MaxFileSize int64 `toml:"max-file-size" json:"max-file-size"`
+	// Replay replays the table pipelines of the changefeeds from the record
+	// files in Dir instead of pulling the events from TiKV, nothing is
+	// recorded in the replay mode.
+	Replay bool `toml:"replay" json:"replay"`
+}
+
+// ValidateAndAdjust validates and adjusts the recorder configuration
+func (c *RecorderConfig) ValidateAndAdjust() error {
+	if !filepath.IsAbs(c.Dir) {
+		return cerrors.ErrInvalidServerOption.GenWithStackByArgs(
+			"debug.recorder.dir must be an absolute path")
+	}
+	if c.MaxFileSize < 0 {
+		return cerrors.ErrInvalidServerOption.GenWithStackByArgs(
+			"debug.recorder.max-file-size must not be negative")
+	}
+	if c.MaxFileSize == 0 {
+		c.MaxFileSize = defaultRecorderMaxFileSize
+	}
+	return nil
+}
+
+// IsRecorded returns whether the table pipelines of the changefeed are recorded.
+func (c *RecorderConfig) IsRecorded(changefeedID string) bool {
+	return c != nil && !c.Replay && c.selects(changefeedID)
+}
+
+// IsReplayed returns whether the table pipelines of the changefeed are replayed
+// from the record files.
+func (c *RecorderConfig) IsReplayed(changefeedID string) bool {
+	return c != nil && c.Replay && c.selects(changefeedID)
+}
+
+func (c *RecorderConfig) selects(changefeedID string) bool {
+	if len(c.Changefeeds) == 0 {
+		return true
+	}
+	for _, id := range c.Changefeeds {
+		if id == changefeedID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+
+	cfg := &RecorderConfig{Dir: "record"}
+	require.Regexp(t, ".*must be an absolute path.*", cfg.ValidateAndAdjust())
+	cfg.Dir = "/tmp/record"
+	cfg.MaxFileSize = -1
+	require.Regexp(t, ".*must not be negative.*", cfg.ValidateAndAdjust())
+	cfg.MaxFileSize = 0
+	require.Nil(t, cfg.ValidateAndAdjust())
+	require.EqualValues(t, defaultRecorderMaxFileSize, cfg.MaxFileSize)
+}
+
+func TestRecorderConfigIsRecorded(t *testing.T) {
+	t.Parallel()
+
+	var cfg *RecorderConfig
+	require.False(t, cfg.IsRecorded("cf1"))
+	cfg = &RecorderConfig{Dir: "/tmp/record"}
+	require.True(t, cfg.IsRecorded("cf1"))
+	cfg.Changefeeds = []string{"cf2"}
+	require.False(t, cfg.IsRecorded("cf1"))
+	require.True(t, cfg.IsRecorded("cf2"))
+	require.False(t, cfg.IsReplayed("cf2"))
+
+	// nothing is recorded in the replay mode
+	cfg.Replay = true
+	require.False(t, cfg.IsRecorded("cf2"))
+	require.True(t, cfg.IsReplayed("cf2"))
+	require.False(t, cfg.IsReplayed("cf1"))
+	cfg = nil
+	require.False(t, cfg.IsReplayed("cf1"))
+}
@@ -49,6 +49,29 @@ var defaultReplicaConfig = &ReplicaConfig{
 		MaxLogSize:        64,
 		FlushIntervalInMs: 1000,
 		Storage:           "",
+		MaxBatchSize:      4,
+	},
+	AdmissionControl: &AdmissionControlConfig{
+		Enable:                false,
+		OverloadDurationInSec: 60,
+	},
+	IncrementalScan: &IncrementalScanConfig{},
+	SchemaDrift: &SchemaDriftConfig{
+		Enable:             false,
+		CheckIntervalInSec: 600,
+	},
+	DataContract:       &DataContractConfig{},
+	WatermarkAlignment: &WatermarkAlignmentConfig{},
+	Sorter:             &ChangefeedSorterConfig{},
+	HealthCheck: &HealthCheckConfig{
+		Enable:          false,
+		Table:           "tidb_cdc.canary",
+		IntervalInSec:   10,
+		MaxLatencyInSec: 60,
+	},
+	Hibernation: &HibernationConfig{
+		Enable:           false,
+		IdleTimeoutInSec: 300,
 	},
 }
 
@@ -66,6 +89,22 @@ type replicaConfig struct {
 	Cyclic           *CyclicConfig     `toml:"cyclic-replication" json:"cyclic-replication"`
 	Scheduler        *SchedulerConfig  `toml:"scheduler" json:"scheduler"`
 	Consistent       *ConsistentConfig `toml:"consistent" json:"consistent"`
+	// AdmissionControl pauses pulling tables when the sink can't keep up with them.
+	AdmissionControl *AdmissionControlConfig `toml:"admission-control" json:"admission-control"`
+	// IncrementalScan limits the incremental scans of table pullers.
+	IncrementalScan *IncrementalScanConfig `toml:"incremental-scan" json:"incremental-scan"`
+	// SchemaDrift detects the downstream schema changed out-of-band.
+	SchemaDrift *SchemaDriftConfig `toml:"schema-drift" json:"schema-drift"`
+	// DataContract declares the columns required by the downstream consumers.
+	DataContract *DataContractConfig `toml:"data-contract" json:"data-contract"`
+	// WatermarkAlignment aligns the emitted checkpoint ts of a group of changefeeds.
+	WatermarkAlignment *WatermarkAlignmentConfig `toml:"watermark-alignment" json:"watermark-alignment"`
+	// Sorter overrides the unified sorter config of the server for the changefeed.
+	Sorter *ChangefeedSorterConfig `toml:"sorter" json:"sorter"`
+	// HealthCheck checks the end-to-end latency of the changefeed by canary rows.
+	HealthCheck *HealthCheckConfig `toml:"health-check" json:"health-check"`
+	// Hibernation releases the resources of idle tables.
+	Hibernation *HibernationConfig `toml:"hibernation" json:"hibernation"`
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -136,6 +175,54 @@ func (c *ReplicaConfig) Validate() error {
 			return err
 		}
 	}
+	if c.AdmissionControl != nil {
+		err := c.AdmissionControl.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.IncrementalScan != nil {
+		err := c.IncrementalScan.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.SchemaDrift != nil {
+		err := c.SchemaDrift.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.DataContract != nil {
+		err := c.DataContract.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.Consistent != nil {
+		err := c.Consistent.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.Sorter != nil {
+		err := c.Sorter.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.HealthCheck != nil {
+		err := c.HealthCheck.validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.Hibernation != nil {
+		err := c.Hibernation.validate()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -94,4 +94,24 @@ func TestReplicaConfigValidate(t *testing.T) {
 	conf.Sink.Protocol = "canal"
 	conf.EnableOldValue = false
 	require.Regexp(t, ".*canal protocol requires old value to be enabled.*", conf.Validate())
+
+	// Incorrect consistent configuration.
+	conf = GetDefaultReplicaConfig()
+	conf.Consistent.RetentionHours = -1
+	require.Regexp(t, ".*retention-hours -1 of redo logs must not be negative.*", conf.Validate())
+	conf = GetDefaultReplicaConfig()
+	conf.Consistent.MaxBatchSize = -1
+	require.Regexp(t, ".*max-batch-size -1 of redo logs must not be negative.*", conf.Validate())
+	conf = GetDefaultReplicaConfig()
+	conf.Consistent.RetentionSize = -1
+	require.Regexp(t, ".*retention-size -1 of redo logs must not be negative.*", conf.Validate())
+
+	// Incorrect sorter configuration.
+	conf = GetDefaultReplicaConfig()
+	for _, dir := range []string{"/tmp/sorter", ".", "../sorter", "a/../../sorter"} {
+		conf.Sorter.SortDir = dir
+		require.Regexp(t, ".*sorter.sort-dir should be a sub directory.*", conf.Validate())
+	}
+	conf.Sorter.SortDir = "changefeed/dir"
+	require.Nil(t, conf.Validate())
 }
@@ -0,0 +1,35 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import cerror "github.com/pingcap/tiflow/pkg/errors"
+
+// SchemaDriftConfig represents the config of the downstream schema drift
+// detection of a changefeed. When enabled, the owner compares the schema of
+// the replicated tables with the downstream schema periodically, and warns
+// about the differences caused by out-of-band changes to the downstream.
+// Only MySQL compatible sinks are supported.
+type SchemaDriftConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// CheckIntervalInSec is the interval of the checks in seconds.
+	CheckIntervalInSec int64 `toml:"check-interval-in-sec" json:"check-interval-in-sec"`
+}
+
+func (c *SchemaDriftConfig) validate() error {
+	if c.Enable && c.CheckIntervalInSec <= 0 {
+		return cerror.ErrInvalidSchemaDriftConfig.GenWithStackByArgs(
+			"check-interval-in-sec should be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDriftValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := &SchemaDriftConfig{}
+	require.Nil(t, cfg.validate())
+	cfg.Enable = true
+	require.Regexp(t, ".*check-interval-in-sec should be greater than 0.*", cfg.validate())
+	cfg.CheckIntervalInSec = 60
+	require.Nil(t, cfg.validate())
+}
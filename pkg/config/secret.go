@@ -0,0 +1,24 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// SecretConfig represents the secrets the changefeeds can reference in their
+// sink URIs, no secret of vault or kubernetes can be referenced by default.
+type SecretConfig struct {
+	// VaultPaths are the paths of vault, the secrets under them can be referenced.
+	VaultPaths []string `toml:"vault-paths" json:"vault-paths"`
+	// K8sSecrets are the kubernetes secrets can be referenced, in the format of
+	// `<namespace>/<name>`, the name can be `*` to allow all the secrets in the namespace.
+	K8sSecrets []string `toml:"k8s-secrets" json:"k8s-secrets"`
+}
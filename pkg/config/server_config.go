@@ -82,6 +82,7 @@ var defaultServerConfig = &ServerConfig{
 	CaptureSessionTTL:      10,
 	OwnerFlushInterval:     TomlDuration(200 * time.Millisecond),
 	ProcessorFlushInterval: TomlDuration(100 * time.Millisecond),
+	PDStalenessBudget:      TomlDuration(time.Minute),
 	Sorter: &SorterConfig{
 		NumConcurrentWorker:    4,
 		ChunkSizeLimit:         128 * 1024 * 1024,       // 128MB
@@ -89,14 +90,26 @@ var defaultServerConfig = &ServerConfig{
 		MaxMemoryConsumption:   16 * 1024 * 1024 * 1024, // 16GB
 		NumWorkerPoolGoroutine: 16,
 		SortDir:                DefaultSortDir,
+		Compression:            SorterCompressionNone,
+	},
+	DataEncryption: &DataEncryptionConfig{
+		Method: DataEncryptionMethodPlaintext,
 	},
 	Security:            &SecurityConfig{},
+	Secret:              &SecretConfig{},
 	PerTableMemoryQuota: 10 * 1024 * 1024, // 10MB
 	KVClient: &KVClientConfig{
 		WorkerConcurrent: 8,
 		WorkerPoolSize:   0, // 0 will use NumCPU() * 2
 		RegionScanLimit:  40,
 	},
+	MounterPool: &MounterPoolConfig{
+		WorkerNum:          0, // 0 means unlimited, or NumCPU() if auto tuned
+		EnableAutoTune:     false,
+		MinWorkerNum:       1,
+		MaxWorkerNum:       0, // 0 will use NumCPU() * 4
+		TargetQueueLatency: TomlDuration(5 * time.Millisecond),
+	},
 	Debug: &DebugConfig{
 		EnableTableActor: true,
 		TableActor: &TableActorConfig{
@@ -106,7 +119,8 @@ var defaultServerConfig = &ServerConfig{
 		// Default leveldb sorter config
 		EnableDBSorter: true,
 		DB: &DBConfig{
-			Count: 8,
+			Engine: DBEnginePebble,
+			Count:  8,
 			// Following configs are optimized for write/read throughput.
 			// Users should not change them.
 			Concurrency:                 128,
@@ -119,6 +133,7 @@ var defaultServerConfig = &ServerConfig{
 			WriteL0SlowdownTrigger:      math.MaxInt32,
 			WriteL0PauseTrigger:         math.MaxInt32,
 			CompactionL0Trigger:         160,
+			MaxConcurrentCompactions:    6,
 			CompactionDeletionThreshold: 10485760,
 			CompactionPeriod:            1800,
 			IteratorMaxAliveDuration:    10000,
@@ -146,12 +161,30 @@ type ServerConfig struct {
 
 	OwnerFlushInterval     TomlDuration `toml:"owner-flush-interval" json:"owner-flush-interval"`
 	ProcessorFlushInterval TomlDuration `toml:"processor-flush-interval" json:"processor-flush-interval"`
-
-	Sorter              *SorterConfig   `toml:"sorter" json:"sorter"`
-	Security            *SecurityConfig `toml:"security" json:"security"`
-	PerTableMemoryQuota uint64          `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
-	KVClient            *KVClientConfig `toml:"kv-client" json:"kv-client"`
-	Debug               *DebugConfig    `toml:"debug" json:"debug"`
+	// PDStalenessBudget is how long the changefeeds keep running with the
+	// cached PD time and GC safepoint once PD is unavailable.
+	PDStalenessBudget TomlDuration `toml:"pd-staleness-budget" json:"pd-staleness-budget"`
+	// MaxConcurrentDDLPerDownstream is the max number of DDLs executed
+	// concurrently by the changefeeds replicating to the same downstream,
+	// 0 means unlimited.
+	MaxConcurrentDDLPerDownstream int `toml:"max-concurrent-ddl-per-downstream" json:"max-concurrent-ddl-per-downstream"`
+
+	Sorter *SorterConfig `toml:"sorter" json:"sorter"`
+	// DataEncryption is the encryption of the files of the sorters and redo
+	// logs written to local disks.
+	DataEncryption *DataEncryptionConfig `toml:"data-encryption" json:"data-encryption"`
+	Security       *SecurityConfig       `toml:"security" json:"security"`
+	// Secret is the allowlist of the secrets referenced in sink URIs.
+	Secret              *SecretConfig `toml:"secret" json:"secret"`
+	PerTableMemoryQuota uint64        `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
+	// CaptureMemoryQuota is the memory quota shared by all tables of a capture,
+	// it's distributed dynamically and replaces PerTableMemoryQuota if it's not 0.
+	CaptureMemoryQuota uint64          `toml:"capture-memory-quota" json:"capture-memory-quota"`
+	KVClient           *KVClientConfig `toml:"kv-client" json:"kv-client"`
+	// MounterPool limits the number of events decoded concurrently by all
+	// changefeeds of the capture.
+	MounterPool *MounterPoolConfig `toml:"mounter-pool" json:"mounter-pool"`
+	Debug       *DebugConfig       `toml:"debug" json:"debug"`
 }
 
 // Marshal returns the json marshal format of a ServerConfig
@@ -220,6 +253,12 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		log.Warn("capture session ttl too small, set to default value 10s")
 		c.CaptureSessionTTL = 10
 	}
+	if c.PDStalenessBudget < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack("pd-staleness-budget must not be negative")
+	}
+	if c.MaxConcurrentDDLPerDownstream < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStack("max-concurrent-ddl-per-downstream must not be negative")
+	}
 
 	if c.Security != nil && c.Security.IsTLSEnabled() {
 		var err error
@@ -234,6 +273,9 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	}
 
 	defaultCfg := GetDefaultServerConfig()
+	if c.Secret == nil {
+		c.Secret = defaultCfg.Secret
+	}
 	if c.Sorter == nil {
 		c.Sorter = defaultCfg.Sorter
 	}
@@ -243,6 +285,13 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		return err
 	}
 
+	if c.DataEncryption == nil {
+		c.DataEncryption = defaultCfg.DataEncryption
+	}
+	if err = c.DataEncryption.ValidateAndAdjust(); err != nil {
+		return err
+	}
+
 	if c.PerTableMemoryQuota == 0 {
 		c.PerTableMemoryQuota = defaultCfg.PerTableMemoryQuota
 	}
@@ -257,6 +306,13 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		return cerror.ErrInvalidServerOption.GenWithStackByArgs("region-scan-limit should be at least 1")
 	}
 
+	if c.MounterPool == nil {
+		c.MounterPool = defaultCfg.MounterPool
+	}
+	if err = c.MounterPool.ValidateAndAdjust(); err != nil {
+		return err
+	}
+
 	if c.Debug == nil {
 		c.Debug = defaultCfg.Debug
 	}
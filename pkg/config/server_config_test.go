@@ -90,4 +90,68 @@ func TestDBConfigValidateAndAdjust(t *testing.T) {
 	require.Nil(t, conf.ValidateAndAdjust())
 	conf.Compression = "invalid"
 	require.Error(t, conf.ValidateAndAdjust())
+	conf.Compression = "snappy"
+
+	conf.Engine = DBEngineLevelDB
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Engine = "invalid"
+	require.Error(t, conf.ValidateAndAdjust())
+	conf.Engine = DBEnginePebble
+
+	conf.MaxConcurrentCompactions = 0
+	require.Error(t, conf.ValidateAndAdjust())
+}
+
+func TestSorterConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Sorter
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Compression = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, SorterCompressionNone, conf.Compression)
+	conf.Compression = SorterCompressionSnappy
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Compression = SorterCompressionZstd
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Compression = "gzip"
+	require.Regexp(t, ".*compression should be none, snappy or zstd.*", conf.ValidateAndAdjust())
+}
+
+func TestDataEncryptionConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().DataEncryption
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.False(t, conf.IsEnabled())
+	conf.Method = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, DataEncryptionMethodPlaintext, conf.Method)
+	conf.Method = DataEncryptionMethodAES256
+	require.Regexp(t, ".*key-file must be set.*", conf.ValidateAndAdjust())
+	conf.KeyFile = "/tmp/data.key"
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.True(t, conf.IsEnabled())
+	conf.Method = "sm4"
+	require.Regexp(t, ".*method should be plaintext or aes256.*", conf.ValidateAndAdjust())
+}
+
+func TestMounterPoolConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().MounterPool
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.WorkerNum = -1
+	require.Regexp(t, ".*worker-num should be greater than or equal to 0.*", conf.ValidateAndAdjust())
+	conf.WorkerNum = 8
+	conf.EnableAutoTune = true
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.MinWorkerNum = 0
+	require.Regexp(t, ".*min-worker-num should be greater than 0.*", conf.ValidateAndAdjust())
+	conf.MinWorkerNum = 4
+	conf.MaxWorkerNum = 2
+	require.Regexp(t, ".*max-worker-num should be greater than or equal to min-worker-num.*", conf.ValidateAndAdjust())
+	conf.MaxWorkerNum = 16
+	conf.TargetQueueLatency = 0
+	require.Regexp(t, ".*target-queue-latency should be greater than 0.*", conf.ValidateAndAdjust())
 }
@@ -15,10 +15,15 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	filter "github.com/pingcap/tidb/util/table-filter"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/secret"
 )
 
 // DefaultMaxMessageBytes sets the default value for max-message-bytes
@@ -29,6 +34,7 @@ var ForceEnableOldValueProtocols = []string{
 	ProtocolCanal.String(),
 	ProtocolCanalJSON.String(),
 	ProtocolMaxwell.String(),
+	ProtocolDebezium.String(),
 }
 
 // SinkConfig represents sink config for a changefeed
@@ -36,13 +42,264 @@ type SinkConfig struct {
 	DispatchRules   []*DispatchRule   `toml:"dispatchers" json:"dispatchers"`
 	Protocol        string            `toml:"protocol" json:"protocol"`
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors"`
+	// ExtraSinkURIs are the sinks the changefeed replicates to besides its sink-uri,
+	// every event is written to all of them at least once, the events may be written
+	// to a sink again if another sink fails.
+	ExtraSinkURIs []string `toml:"extra-sink-uris" json:"extra-sink-uris"`
+	// DDLCompatibility is the compatibility mode of DDLs replicated to the downstream,
+	// empty means DDLCompatibilityTiDB.
+	DDLCompatibility string `toml:"ddl-compatibility" json:"ddl-compatibility"`
+	// FieldMappings are the column to field mappings of document sinks like
+	// Elasticsearch, columns not mapped use their names as field names.
+	FieldMappings []*FieldMapping `toml:"field-mappings" json:"field-mappings"`
+	// Transforms transform the column values before they are written to the sink,
+	// e.g. to mask sensitive data.
+	Transforms []*ColumnTransform `toml:"transforms" json:"transforms"`
+	// Throttle limits the throughput of rows written to the sink, it can be
+	// adjusted when the changefeed is running.
+	Throttle *ThrottleConfig `toml:"throttle" json:"throttle,omitempty"`
+	// ErrorPolicy decides which errors of the MySQL sink are retried and which
+	// fail the changefeed immediately.
+	ErrorPolicy *SinkErrorPolicy `toml:"error-policy" json:"error-policy,omitempty"`
+	// DeadLetterQueue receives the rows the MySQL sink fails to apply, so the
+	// changefeed continues instead of being stalled by them.
+	DeadLetterQueue *DeadLetterQueueConfig `toml:"dead-letter-queue" json:"dead-letter-queue,omitempty"`
+	// IgnoreErrors skips the rows the MySQL sink fails to apply with the
+	// ignored errors, until too many rows are skipped.
+	IgnoreErrors *IgnoreErrorsConfig `toml:"ignore-errors" json:"ignore-errors,omitempty"`
+	// SchemaManagedTables are the filter rules of the tables whose schemas are
+	// managed in the downstream. The MySQL sink doesn't execute their DDLs, and
+	// pauses a table until the downstream schema matches its rows.
+	SchemaManagedTables []string `toml:"schema-managed-tables" json:"schema-managed-tables,omitempty"`
+	// SkipNoopUpdates drops the update events that don't change any replicated
+	// column, e.g. only the columns removed by the column selectors are changed.
+	// It requires old value to be enabled.
+	SkipNoopUpdates bool `toml:"skip-noop-updates" json:"skip-noop-updates,omitempty"`
+	// Routes rename the tables in the sink, the first route matching a table
+	// decides its name in the downstream.
+	Routes []*TableRoute `toml:"routes" json:"routes,omitempty"`
 }
 
+// ThrottleConfig represents the throughput limits of a sink, 0 means unlimited.
+type ThrottleConfig struct {
+	RowsPerSecond  int64 `toml:"rows-per-second" json:"rows-per-second"`
+	BytesPerSecond int64 `toml:"bytes-per-second" json:"bytes-per-second"`
+}
+
+// SinkErrorPolicy classifies the errors of the MySQL sink. The errors of the
+// Retriable classes are retried with the backoff, the errors of the Fatal
+// classes fail the changefeed at once, and the other errors follow the
+// default policy of the sink. A class is one of the SinkErrorClass constants
+// or a MySQL error code like "1062".
+type SinkErrorPolicy struct {
+	Retriable []string `toml:"retriable" json:"retriable"`
+	Fatal     []string `toml:"fatal" json:"fatal"`
+	// MaxTries is the max number of tries of a transaction or DDL, 0 means
+	// the default of the sink.
+	MaxTries int64 `toml:"max-tries" json:"max-tries"`
+	// BackoffBaseDelayInMs and BackoffMaxDelayInMs are the exponential backoff
+	// between the tries, 0 means the defaults of the sink.
+	BackoffBaseDelayInMs int64 `toml:"backoff-base-delay-in-ms" json:"backoff-base-delay-in-ms"`
+	BackoffMaxDelayInMs  int64 `toml:"backoff-max-delay-in-ms" json:"backoff-max-delay-in-ms"`
+}
+
+const (
+	// SinkErrorClassDeadlock is the deadlock found when trying to get a lock.
+	SinkErrorClassDeadlock = "deadlock"
+	// SinkErrorClassLockWaitTimeout is the lock wait timeout.
+	SinkErrorClassLockWaitTimeout = "lock-wait-timeout"
+	// SinkErrorClassDuplicateKey is the duplicate entry of a unique key.
+	SinkErrorClassDuplicateKey = "duplicate-key"
+	// SinkErrorClassConnection is the broken or reset connection.
+	SinkErrorClassConnection = "connection"
+	// SinkErrorClassDataTooLong is the data too long for a column.
+	SinkErrorClassDataTooLong = "data-too-long"
+	// SinkErrorClassForeignKey is the foreign key constraint failure.
+	SinkErrorClassForeignKey = "foreign-key"
+	// SinkErrorClassNoSuchTable is the unknown database or table.
+	SinkErrorClassNoSuchTable = "no-such-table"
+	// SinkErrorClassUnknownColumn is the unknown column, e.g. it's dropped in
+	// the downstream.
+	SinkErrorClassUnknownColumn = "unknown-column"
+)
+
+var sinkErrorClasses = []string{
+	SinkErrorClassDeadlock, SinkErrorClassLockWaitTimeout, SinkErrorClassDuplicateKey,
+	SinkErrorClassConnection, SinkErrorClassDataTooLong, SinkErrorClassForeignKey,
+	SinkErrorClassNoSuchTable, SinkErrorClassUnknownColumn,
+}
+
+func isSinkErrorClass(class string) bool {
+	if _, err := strconv.ParseUint(class, 10, 16); err == nil {
+		return true
+	}
+	for _, c := range sinkErrorClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *SinkErrorPolicy) validate() error {
+	classes := make(map[string]string, len(p.Retriable)+len(p.Fatal))
+	check := func(kind string, class string) error {
+		if !isSinkErrorClass(class) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"error class %s of error-policy is invalid, valid values are MySQL error codes and %s",
+				class, strings.Join(sinkErrorClasses, ", "))
+		}
+		if other, ok := classes[class]; ok {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"error class %s of error-policy is both %s and %s", class, other, kind)
+		}
+		classes[class] = kind
+		return nil
+	}
+	for _, class := range p.Retriable {
+		if err := check("retriable", class); err != nil {
+			return err
+		}
+	}
+	for _, class := range p.Fatal {
+		if err := check("fatal", class); err != nil {
+			return err
+		}
+	}
+	if p.MaxTries < 0 || p.BackoffBaseDelayInMs < 0 || p.BackoffMaxDelayInMs < 0 ||
+		(p.BackoffMaxDelayInMs > 0 && p.BackoffMaxDelayInMs < p.BackoffBaseDelayInMs) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid error-policy, max-tries: %d, backoff-base-delay-in-ms: %d, backoff-max-delay-in-ms: %d",
+			p.MaxTries, p.BackoffBaseDelayInMs, p.BackoffMaxDelayInMs)
+	}
+	return nil
+}
+
+// DeadLetterQueueConfig represents the dead letter queue of the MySQL sink. A
+// row failed with an error of the Errors classes, after the retries of the
+// error policy, is written to the queue and skipped. The classes are the same
+// as the ones of SinkErrorPolicy.
+type DeadLetterQueueConfig struct {
+	// Table is the downstream table the rows are written to, in the form of
+	// `schema.table`, it's created if it doesn't exist.
+	Table  string   `toml:"table" json:"table"`
+	Errors []string `toml:"errors" json:"errors"`
+}
+
+// SchemaTable returns the schema and table name of the queue.
+func (c *DeadLetterQueueConfig) SchemaTable() (string, string) {
+	parts := strings.SplitN(c.Table, ".", 2)
+	if len(parts) != 2 {
+		return "", c.Table
+	}
+	return parts[0], parts[1]
+}
+
+func (c *DeadLetterQueueConfig) validate(policy *SinkErrorPolicy) error {
+	if schema, table := c.SchemaTable(); schema == "" || table == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"table %s of dead-letter-queue should be in the form of schema.table", c.Table)
+	}
+	if len(c.Errors) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("errors of dead-letter-queue is empty")
+	}
+	for _, class := range c.Errors {
+		if !isSinkErrorClass(class) || class == SinkErrorClassConnection {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"error class %s of dead-letter-queue is invalid, valid values are MySQL error codes and %s",
+				class, strings.Join(sinkErrorClasses, ", "))
+		}
+		if policy == nil {
+			continue
+		}
+		for _, fatal := range policy.Fatal {
+			if class == fatal {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"error class %s is both fatal in error-policy and in dead-letter-queue", class)
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultMaxIgnoredRows is the default hard cap of the rows skipped by
+// IgnoreErrorsConfig.
+const DefaultMaxIgnoredRows = 1000
+
+// IgnoreErrorsConfig represents the errors of the MySQL sink that are counted
+// and skipped instead of failing the changefeed, e.g. the duplicate entries
+// when re-applying rows after a restart, or the unknown columns dropped in the
+// downstream. A row failed with an error of the Errors classes, after the
+// retries of the error policy, is skipped. The classes are the same as the
+// ones of SinkErrorPolicy.
+type IgnoreErrorsConfig struct {
+	Errors []string `toml:"errors" json:"errors"`
+	// MaxIgnoredRows is the hard cap of the skipped rows since the sink is
+	// created, the changefeed fails once it's exceeded. 0 means
+	// DefaultMaxIgnoredRows.
+	MaxIgnoredRows int64 `toml:"max-ignored-rows" json:"max-ignored-rows"`
+}
+
+func (c *IgnoreErrorsConfig) validate(
+	policy *SinkErrorPolicy, deadLetterQueue *DeadLetterQueueConfig,
+) error {
+	if len(c.Errors) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("errors of ignore-errors is empty")
+	}
+	if c.MaxIgnoredRows < 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid ignore-errors, max-ignored-rows: %d", c.MaxIgnoredRows)
+	}
+	for _, class := range c.Errors {
+		if !isSinkErrorClass(class) || class == SinkErrorClassConnection {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"error class %s of ignore-errors is invalid, valid values are MySQL error codes and %s",
+				class, strings.Join(sinkErrorClasses, ", "))
+		}
+		if policy != nil {
+			for _, fatal := range policy.Fatal {
+				if class == fatal {
+					return cerror.ErrSinkInvalidConfig.GenWithStack(
+						"error class %s is both fatal in error-policy and in ignore-errors", class)
+				}
+			}
+		}
+		if deadLetterQueue != nil {
+			for _, dlq := range deadLetterQueue.Errors {
+				if class == dlq {
+					return cerror.ErrSinkInvalidConfig.GenWithStack(
+						"error class %s is both in dead-letter-queue and in ignore-errors", class)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	// DDLCompatibilityTiDB keeps TiDB specific clauses of DDLs as special comments.
+	DDLCompatibilityTiDB = "tidb"
+	// DDLCompatibilityMySQL additionally strips the clauses MySQL doesn't support,
+	// and skips the DDLs which can't be executed by MySQL at all.
+	DDLCompatibilityMySQL = "mysql"
+)
+
 // DispatchRule represents partition rule for a table
 type DispatchRule struct {
 	Matcher       []string `toml:"matcher" json:"matcher"`
 	PartitionRule string   `toml:"dispatcher" json:"dispatcher"`
 	TopicRule     string   `toml:"topic" json:"topic"`
+	// TopicConfig is applied when TiCDC auto-creates the topics of TopicRule.
+	TopicConfig *TopicConfig `toml:"topic-config" json:"topic-config"`
+}
+
+// TopicConfig represents the settings of auto-created topics, the zero values
+// fall back to the settings in the sink URI or the defaults of the broker.
+type TopicConfig struct {
+	PartitionNum      int32 `toml:"partition-num" json:"partition-num"`
+	ReplicationFactor int16 `toml:"replication-factor" json:"replication-factor"`
+	// RetentionMs is the `retention.ms` of topics, -1 means no time limit.
+	RetentionMs int64 `toml:"retention-ms" json:"retention-ms"`
 }
 
 // ColumnSelector represents a column selector for a table.
@@ -51,6 +308,49 @@ type ColumnSelector struct {
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+// FieldMapping maps columns of tables to fields of documents, Fields is keyed
+// by column names.
+type FieldMapping struct {
+	Matcher []string          `toml:"matcher" json:"matcher"`
+	Fields  map[string]string `toml:"fields" json:"fields"`
+}
+
+const (
+	// TransformHash replaces string values with their hex encoded SHA-256 hashes.
+	TransformHash = "hash"
+	// TransformRedact replaces every character of string values with `*`.
+	TransformRedact = "redact"
+	// TransformTruncate keeps the first Length characters of string values.
+	TransformTruncate = "truncate"
+	// TransformConstant replaces values with Value.
+	TransformConstant = "constant"
+)
+
+// ColumnTransform transforms the values of columns of tables, NULL values are
+// never transformed.
+type ColumnTransform struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Columns []string `toml:"columns" json:"columns"`
+	Type    string   `toml:"type" json:"type"`
+	// Length is the number of characters kept by the truncate transform.
+	Length int `toml:"length" json:"length"`
+	// Value is the value set by the constant transform.
+	Value string `toml:"value" json:"value"`
+	// HashKey is the secret key of the HMAC used by the hash transform, so the
+	// hashed values can't be reversed by hashing guessed values.
+	HashKey string `toml:"hash-key" json:"hash-key"`
+}
+
+// TableRoute routes the tables matched by Matcher to TargetSchema.TargetTable
+// in the downstream, an empty TargetSchema or TargetTable keeps the name of
+// the schema or the table. The DDLs of schemas are only routed by the routes
+// keeping the names of the tables.
+type TableRoute struct {
+	Matcher      []string `toml:"matcher" json:"matcher"`
+	TargetSchema string   `toml:"target-schema" json:"target-schema"`
+	TargetTable  string   `toml:"target-table" json:"target-table"`
+}
+
 func (s *SinkConfig) validate(enableOldValue bool) error {
 	if !enableOldValue {
 		for _, protocolStr := range ForceEnableOldValueProtocols {
@@ -62,6 +362,127 @@ func (s *SinkConfig) validate(enableOldValue bool) error {
 			}
 		}
 	}
+	if s.SkipNoopUpdates && !enableOldValue {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"skip-noop-updates requires old value to be enabled")
+	}
+
+	uris := make(map[string]struct{}, len(s.ExtraSinkURIs))
+	for _, uri := range s.ExtraSinkURIs {
+		if _, err := url.Parse(secret.MaskURI(uri)); err != nil {
+			return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+		}
+		if _, ok := uris[uri]; ok {
+			return cerror.ErrSinkURIInvalid.GenWithStack("duplicated extra sink uri %s", uri)
+		}
+		uris[uri] = struct{}{}
+	}
+
+	switch s.DDLCompatibility {
+	case "", DDLCompatibilityTiDB, DDLCompatibilityMySQL:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"ddl-compatibility %s is not supported, valid values are %s and %s",
+			s.DDLCompatibility, DDLCompatibilityTiDB, DDLCompatibilityMySQL)
+	}
+
+	for _, rule := range s.DispatchRules {
+		if rule.TopicConfig == nil {
+			continue
+		}
+		if rule.TopicRule == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"topic-config of dispatcher %v requires the topic to be set", rule.Matcher)
+		}
+		cfg := rule.TopicConfig
+		if cfg.PartitionNum < 0 || cfg.ReplicationFactor < 0 || cfg.RetentionMs < -1 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid topic-config of dispatcher %v, partition-num: %d, replication-factor: %d, retention-ms: %d",
+				rule.Matcher, cfg.PartitionNum, cfg.ReplicationFactor, cfg.RetentionMs)
+		}
+	}
+
+	for _, mapping := range s.FieldMappings {
+		if len(mapping.Matcher) == 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack("matcher of field mapping is empty")
+		}
+		fields := make(map[string]string, len(mapping.Fields))
+		for column, field := range mapping.Fields {
+			if field == "" {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"field of column %s in field mapping is empty", column)
+			}
+			if other, ok := fields[field]; ok {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"columns %s and %s are mapped to the same field %s", other, column, field)
+			}
+			fields[field] = column
+		}
+	}
+
+	if s.Throttle != nil && (s.Throttle.RowsPerSecond < 0 || s.Throttle.BytesPerSecond < 0) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid throttle, rows-per-second: %d, bytes-per-second: %d",
+			s.Throttle.RowsPerSecond, s.Throttle.BytesPerSecond)
+	}
+
+	if s.ErrorPolicy != nil {
+		if err := s.ErrorPolicy.validate(); err != nil {
+			return err
+		}
+	}
+	if s.DeadLetterQueue != nil {
+		if err := s.DeadLetterQueue.validate(s.ErrorPolicy); err != nil {
+			return err
+		}
+	}
+	if s.IgnoreErrors != nil {
+		if err := s.IgnoreErrors.validate(s.ErrorPolicy, s.DeadLetterQueue); err != nil {
+			return err
+		}
+	}
+
+	if len(s.SchemaManagedTables) > 0 {
+		if _, err := filter.Parse(s.SchemaManagedTables); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+	}
+
+	for _, route := range s.Routes {
+		if len(route.Matcher) == 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack("matcher of route is empty")
+		}
+		if route.TargetSchema == "" && route.TargetTable == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"route %v has neither target-schema nor target-table", route.Matcher)
+		}
+		if _, err := filter.Parse(route.Matcher); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+		}
+	}
+
+	for _, transform := range s.Transforms {
+		if len(transform.Matcher) == 0 || len(transform.Columns) == 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack("matcher or columns of transform is empty")
+		}
+		switch transform.Type {
+		case TransformHash:
+			if transform.HashKey == "" {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"hash-key of hash transform %v is empty", transform.Matcher)
+			}
+		case TransformRedact, TransformConstant:
+		case TransformTruncate:
+			if transform.Length <= 0 {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"length of truncate transform %v must be positive", transform.Matcher)
+			}
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"transform type %s is not supported, valid values are %s, %s, %s and %s",
+				transform.Type, TransformHash, TransformRedact, TransformTruncate, TransformConstant)
+		}
+	}
 
 	return nil
 }
@@ -79,3 +79,243 @@ func TestValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateExtraSinkURIs(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol:      "default",
+		ExtraSinkURIs: []string{"blackhole://", "kafka://127.0.0.1:9092/topic"},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.ExtraSinkURIs = append(cfg.ExtraSinkURIs, "blackhole://")
+	require.Regexp(t, ".*duplicated extra sink uri.*", cfg.validate(true))
+
+	cfg.ExtraSinkURIs = []string{"mysql://root@127.0.0.1:3306/%gh"}
+	require.Regexp(t, ".*ErrSinkURIInvalid.*", cfg.validate(true))
+}
+
+func TestValidateDDLCompatibility(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{Protocol: "default"}
+	for _, mode := range []string{"", DDLCompatibilityTiDB, DDLCompatibilityMySQL} {
+		cfg.DDLCompatibility = mode
+		require.Nil(t, cfg.validate(true))
+	}
+
+	cfg.DDLCompatibility = "oracle"
+	require.Regexp(t, ".*ddl-compatibility oracle is not supported.*", cfg.validate(true))
+}
+
+func TestValidateFieldMappings(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		FieldMappings: []*FieldMapping{
+			{Matcher: []string{"test.*"}, Fields: map[string]string{"a": "x", "b": "y"}},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.FieldMappings[0].Fields["c"] = "x"
+	require.Regexp(t, ".*are mapped to the same field x.*", cfg.validate(true))
+
+	cfg.FieldMappings[0].Fields["c"] = ""
+	require.Regexp(t, ".*field of column c in field mapping is empty.*", cfg.validate(true))
+
+	cfg.FieldMappings[0].Matcher = nil
+	require.Regexp(t, ".*matcher of field mapping is empty.*", cfg.validate(true))
+}
+
+func TestValidateTransforms(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		Transforms: []*ColumnTransform{
+			{Matcher: []string{"test.*"}, Columns: []string{"a"}, Type: TransformHash, HashKey: "key"},
+			{Matcher: []string{"test.*"}, Columns: []string{"b"}, Type: TransformTruncate, Length: 4},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.Transforms[0].HashKey = ""
+	require.Regexp(t, ".*hash-key of hash transform.*is empty.*", cfg.validate(true))
+	cfg.Transforms[0].HashKey = "key"
+
+	cfg.Transforms[1].Length = 0
+	require.Regexp(t, ".*length of truncate transform.*must be positive.*", cfg.validate(true))
+
+	cfg.Transforms[1].Type = "mask"
+	require.Regexp(t, ".*transform type mask is not supported.*", cfg.validate(true))
+
+	cfg.Transforms[0].Columns = nil
+	require.Regexp(t, ".*matcher or columns of transform is empty.*", cfg.validate(true))
+}
+
+func TestValidateRoutes(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		Routes: []*TableRoute{
+			{Matcher: []string{"db1.*"}, TargetSchema: "db2"},
+			{Matcher: []string{"db3.t1"}, TargetTable: "t2"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.Routes[1].Matcher = []string{"db3.t1["}
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", cfg.validate(true))
+
+	cfg.Routes[1].TargetTable = ""
+	require.Regexp(t, ".*route \\[db3.t1\\[\\] has neither target-schema nor target-table.*", cfg.validate(true))
+
+	cfg.Routes[1].Matcher = nil
+	require.Regexp(t, ".*matcher of route is empty.*", cfg.validate(true))
+}
+
+func TestValidateTopicConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		DispatchRules: []*DispatchRule{
+			{Matcher: []string{"test.*"}, PartitionRule: "ts"},
+			{
+				Matcher:     []string{"test1.*"},
+				TopicRule:   "cdc_{schema}_{table}",
+				TopicConfig: &TopicConfig{PartitionNum: 3, ReplicationFactor: 2, RetentionMs: -1},
+			},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.DispatchRules[1].TopicConfig.RetentionMs = -2
+	require.Regexp(t, ".*invalid topic-config of dispatcher.*", cfg.validate(true))
+
+	cfg.DispatchRules[1].TopicConfig = &TopicConfig{PartitionNum: -1}
+	require.Regexp(t, ".*invalid topic-config of dispatcher.*", cfg.validate(true))
+
+	cfg.DispatchRules[1].TopicConfig = &TopicConfig{}
+	cfg.DispatchRules[1].TopicRule = ""
+	require.Regexp(t, ".*requires the topic to be set.*", cfg.validate(true))
+}
+
+func TestValidateErrorPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		ErrorPolicy: &SinkErrorPolicy{
+			Retriable: []string{SinkErrorClassDeadlock, SinkErrorClassConnection},
+			Fatal:     []string{SinkErrorClassDataTooLong, "1452"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.ErrorPolicy.BackoffBaseDelayInMs = 1000
+	cfg.ErrorPolicy.BackoffMaxDelayInMs = 100
+	require.Regexp(t, ".*invalid error-policy.*", cfg.validate(true))
+	cfg.ErrorPolicy.BackoffMaxDelayInMs = 0
+	require.Nil(t, cfg.validate(true))
+	cfg.ErrorPolicy.MaxTries = -1
+	require.Regexp(t, ".*invalid error-policy.*", cfg.validate(true))
+	cfg.ErrorPolicy.MaxTries = 3
+	require.Nil(t, cfg.validate(true))
+
+	cfg.ErrorPolicy.Fatal = append(cfg.ErrorPolicy.Fatal, SinkErrorClassDeadlock)
+	require.Regexp(t, ".*error class deadlock of error-policy is both retriable and fatal.*",
+		cfg.validate(true))
+	cfg.ErrorPolicy.Fatal = []string{"timeout"}
+	require.Regexp(t, ".*error class timeout of error-policy is invalid.*", cfg.validate(true))
+}
+
+func TestValidateDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		DeadLetterQueue: &DeadLetterQueueConfig{
+			Table:  "dlq.rows",
+			Errors: []string{SinkErrorClassDataTooLong, "1452"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+	schema, table := cfg.DeadLetterQueue.SchemaTable()
+	require.Equal(t, "dlq", schema)
+	require.Equal(t, "rows", table)
+
+	cfg.DeadLetterQueue.Table = "rows"
+	require.Regexp(t, ".*should be in the form of schema.table.*", cfg.validate(true))
+	cfg.DeadLetterQueue.Table = "dlq."
+	require.Regexp(t, ".*should be in the form of schema.table.*", cfg.validate(true))
+	cfg.DeadLetterQueue.Table = "dlq.rows"
+
+	cfg.DeadLetterQueue.Errors = nil
+	require.Regexp(t, ".*errors of dead-letter-queue is empty.*", cfg.validate(true))
+	cfg.DeadLetterQueue.Errors = []string{SinkErrorClassConnection}
+	require.Regexp(t, ".*error class connection of dead-letter-queue is invalid.*", cfg.validate(true))
+	cfg.DeadLetterQueue.Errors = []string{SinkErrorClassDataTooLong}
+	cfg.ErrorPolicy = &SinkErrorPolicy{Fatal: []string{SinkErrorClassDataTooLong}}
+	require.Regexp(t, ".*error class data-too-long is both fatal in error-policy and in dead-letter-queue.*",
+		cfg.validate(true))
+}
+
+func TestValidateSchemaManagedTables(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol:            "default",
+		SchemaManagedTables: []string{"app.*", "!app.logs"},
+	}
+	require.Nil(t, cfg.validate(true))
+	cfg.SchemaManagedTables = []string{"app.t["}
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", cfg.validate(true))
+}
+
+func TestValidateSkipNoopUpdates(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{Protocol: "default", SkipNoopUpdates: true}
+	require.Nil(t, cfg.validate(true))
+	require.Regexp(t, ".*skip-noop-updates requires old value to be enabled.*", cfg.validate(false))
+}
+
+func TestValidateIgnoreErrors(t *testing.T) {
+	t.Parallel()
+
+	cfg := SinkConfig{
+		Protocol: "default",
+		IgnoreErrors: &IgnoreErrorsConfig{
+			Errors: []string{SinkErrorClassDuplicateKey, SinkErrorClassUnknownColumn, "1054"},
+		},
+	}
+	require.Nil(t, cfg.validate(true))
+
+	cfg.IgnoreErrors.MaxIgnoredRows = -1
+	require.Regexp(t, ".*invalid ignore-errors, max-ignored-rows: -1.*", cfg.validate(true))
+	cfg.IgnoreErrors.MaxIgnoredRows = 100
+
+	cfg.IgnoreErrors.Errors = nil
+	require.Regexp(t, ".*errors of ignore-errors is empty.*", cfg.validate(true))
+	cfg.IgnoreErrors.Errors = []string{SinkErrorClassConnection}
+	require.Regexp(t, ".*error class connection of ignore-errors is invalid.*", cfg.validate(true))
+	cfg.IgnoreErrors.Errors = []string{"timeout"}
+	require.Regexp(t, ".*error class timeout of ignore-errors is invalid.*", cfg.validate(true))
+
+	cfg.IgnoreErrors.Errors = []string{SinkErrorClassDuplicateKey}
+	cfg.ErrorPolicy = &SinkErrorPolicy{Fatal: []string{SinkErrorClassDuplicateKey}}
+	require.Regexp(t, ".*error class duplicate-key is both fatal in error-policy and in ignore-errors.*",
+		cfg.validate(true))
+	cfg.ErrorPolicy = nil
+	cfg.DeadLetterQueue = &DeadLetterQueueConfig{
+		Table:  "dlq.rows",
+		Errors: []string{SinkErrorClassDuplicateKey},
+	}
+	require.Regexp(t, ".*error class duplicate-key is both in dead-letter-queue and in ignore-errors.*",
+		cfg.validate(true))
+}
@@ -13,7 +13,12 @@
 
 package config
 
-import cerror "github.com/pingcap/tiflow/pkg/errors"
+import (
+	"path/filepath"
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
 
 // SorterConfig represents sorter config for a changefeed
 type SorterConfig struct {
@@ -29,8 +34,18 @@ type SorterConfig struct {
 	NumWorkerPoolGoroutine int `toml:"num-workerpool-goroutine" json:"num-workerpool-goroutine"`
 	// the directory used to store the temporary files generated by the sorter
 	SortDir string `toml:"sort-dir" json:"sort-dir"`
+	// the compression of the temporary files generated by the sorter, it's
+	// none, snappy or zstd
+	Compression string `toml:"compression" json:"compression"`
 }
 
+// The compressions of the temporary files generated by the unified sorter.
+const (
+	SorterCompressionNone   = "none"
+	SorterCompressionSnappy = "snappy"
+	SorterCompressionZstd   = "zstd"
+)
+
 // ValidateAndAdjust validates and adjusts the sorter configuration
 func (c *SorterConfig) ValidateAndAdjust() error {
 	if c.ChunkSizeLimit < 1*1024*1024 {
@@ -51,6 +66,49 @@ func (c *SorterConfig) ValidateAndAdjust() error {
 	if c.MaxMemoryPercentage <= 0 || c.MaxMemoryPercentage > 80 {
 		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("max-memory-percentage should be a percentage and within (0, 80]")
 	}
+	switch c.Compression {
+	case SorterCompressionNone, SorterCompressionSnappy, SorterCompressionZstd:
+	case "":
+		c.Compression = SorterCompressionNone
+	default:
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs("compression should be none, snappy or zstd")
+	}
+
+	return nil
+}
 
+// ChangefeedSorterConfig represents the unified sorter config of a changefeed,
+// which overrides the sorter config of the server, so a changefeed sorting a
+// lot of data, e.g. in a backfill, can be limited without affecting others.
+type ChangefeedSorterConfig struct {
+	// MaxMemoryConsumption is the max memory in bytes used by the unified
+	// sorters of the changefeed for in-memory sorting, the sorters spill data
+	// to files once it's exceeded.
+	//
+	// The default value is 0, which means only the limits of the server apply.
+	MaxMemoryConsumption uint64 `toml:"max-memory-consumption" json:"max-memory-consumption"`
+	// MaxDiskConsumptionPerTable is the max size in bytes of the files written
+	// by the unified sorter of a table, the changefeed fails once it's exceeded.
+	//
+	// The default value is 0, which means unlimited.
+	MaxDiskConsumptionPerTable uint64 `toml:"max-disk-consumption-per-table" json:"max-disk-consumption-per-table"`
+	// SortDir is the directory used to store the temporary files of the
+	// changefeed, it's a path relative to the sort-dir of the server, so the
+	// files of the changefeed never leave the directory of the server.
+	//
+	// The default value is "", which means the sort-dir of the server.
+	SortDir string `toml:"sort-dir" json:"sort-dir"`
+}
+
+func (c *ChangefeedSorterConfig) validate() error {
+	if c.SortDir == "" {
+		return nil
+	}
+	dir := filepath.Clean(c.SortDir)
+	if filepath.IsAbs(dir) || dir == "." || dir == ".." ||
+		strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return cerror.ErrIllegalSorterParameter.GenWithStackByArgs(
+			"sorter.sort-dir should be a sub directory relative to the sort-dir of the server")
+	}
 	return nil
 }
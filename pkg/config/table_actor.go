@@ -17,4 +17,7 @@ package config
 type TableActorConfig struct {
 	// EventBatchSize represents the batch size of events that table actor processed per Poll
 	EventBatchSize uint32 `toml:"event-batch-size" json:"event-batch-size"`
+	// SystemCount is the count of actor systems that table actors are sharded
+	// across, 0 means it follows GOMAXPROCS and grows with it.
+	SystemCount int `toml:"system-count" json:"system-count"`
 }
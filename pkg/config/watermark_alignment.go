@@ -0,0 +1,29 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// WatermarkAlignmentConfig represents the watermark alignment config of a
+// changefeed. The changefeeds of the same group, which usually write to the
+// same downstream, emit the same checkpoint ts, the minimal checkpoint ts of
+// the group, so the consumers get a single consistent watermark across the
+// topics or tables written by the changefeeds.
+type WatermarkAlignmentConfig struct {
+	// Group is the name of the group, the watermark is not aligned if it's empty.
+	Group string `toml:"group" json:"group"`
+}
+
+// IsEnabled returns true if the changefeed belongs to a group.
+func (c *WatermarkAlignmentConfig) IsEnabled() bool {
+	return c != nil && c.Group != ""
+}
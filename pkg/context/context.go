@@ -19,9 +19,12 @@ import (
 
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
+	"github.com/pingcap/tiflow/cdc/puller"
+	"github.com/pingcap/tiflow/cdc/sink/common"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/leveldb/system"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
@@ -47,6 +50,13 @@ type GlobalVars struct {
 	PDClock          pdtime.Clock
 	TableActorSystem *system.System
 	SorterSystem     *ssystem.System
+	// MemoryQuotaManager is nil if the capture memory quota is disabled.
+	MemoryQuotaManager *common.MemoryQuotaManager
+	// MounterWorkerPool is shared by the mounters of all changefeeds.
+	MounterWorkerPool *entry.MounterWorkerPool
+	// SharedPullerManager is nil if the table pullers don't share region
+	// subscriptions.
+	SharedPullerManager *puller.SharedPullerManager
 
 	// OwnerRevision is the Etcd revision when the owner got elected.
 	OwnerRevision int64
@@ -62,6 +72,9 @@ type GlobalVars struct {
 type ChangefeedVars struct {
 	ID   model.ChangeFeedID
 	Info *model.ChangeFeedInfo
+	// ScanLimiter limits the incremental scans of the tables of the changefeed
+	// on this capture, it's nil if the scans are not limited.
+	ScanLimiter *kv.IncrementalScanLimiter
 }
 
 // Context contains Vars(), Done(), Throw(error) and StdContext() context.Context
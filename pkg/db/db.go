@@ -13,6 +13,13 @@
 
 package db
 
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+)
+
 // DB is an interface of a leveldb-like database.
 type DB interface {
 	Iterator(lowerBound, upperBound []byte) Iterator
@@ -43,3 +50,15 @@ type Iterator interface {
 	Error() error
 	Release() error
 }
+
+// Open opens a db of the engine in the config, memInByte is the memory used
+// by the block cache of pebble, leveldb uses the block cache size in the config.
+func Open(ctx context.Context, id int, path string, memInByte int, cfg *config.DBConfig) (DB, error) {
+	switch cfg.Engine {
+	case config.DBEnginePebble:
+		return OpenPebble(ctx, id, path, memInByte, cfg)
+	case config.DBEngineLevelDB:
+		return OpenLevelDB(ctx, id, path, cfg)
+	}
+	return nil, cerrors.ErrIllegalSorterParameter.GenWithStackByArgs("unknown db engine " + cfg.Engine)
+}
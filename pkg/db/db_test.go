@@ -46,6 +46,16 @@ func TestDB(t *testing.T) {
 	db, err = OpenPebble(ctx, 1, filepath.Join(t.TempDir(), "2"), 0, cfg)
 	require.Nil(t, err)
 	testDB(t, db)
+
+	for _, engine := range []string{config.DBEnginePebble, config.DBEngineLevelDB} {
+		cfg.Engine = engine
+		db, err = Open(ctx, 1, filepath.Join(t.TempDir(), engine), 0, cfg)
+		require.Nil(t, err)
+		testDB(t, db)
+	}
+	cfg.Engine = "invalid"
+	_, err = Open(ctx, 1, filepath.Join(t.TempDir(), "3"), 0, cfg)
+	require.Error(t, err)
 }
 
 func testDB(t *testing.T, db DB) {
@@ -53,7 +53,7 @@ func buildPebbleOption(id int, memInByte int, cfg *config.DBConfig) (pebble.Opti
 	option.DisableWAL = false // Delete range requires WAL.
 	option.MaxOpenFiles = cfg.MaxOpenFiles / cfg.Count
 	option.Cache = pebble.NewCache(int64(memInByte))
-	option.MaxConcurrentCompactions = 6
+	option.MaxConcurrentCompactions = cfg.MaxConcurrentCompactions
 	option.L0CompactionThreshold = cfg.CompactionL0Trigger
 	option.L0StopWritesThreshold = cfg.WriteL0PauseTrigger
 	option.LBaseMaxBytes = 64 << 20 // 64 MB
@@ -0,0 +1,220 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// KeySize is the size of a data key in bytes.
+	KeySize = 32
+	// IVSize is the size of the IV written before the data by a stream
+	// writer in bytes.
+	IVSize = aes.BlockSize
+)
+
+// Cipher encrypts and decrypts data by AES with a data key. Streams are
+// encrypted in CTR mode, while records, which are decrypted individually,
+// are sealed in GCM mode. It's safe for concurrent use.
+type Cipher struct {
+	block cipher.Block
+	aead  cipher.AEAD
+}
+
+// NewCipher creates a Cipher with the data key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, cerror.ErrInvalidDataEncryptionKey.GenWithStackByArgs(
+			"the key should be 32 bytes for aes256")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrInvalidDataEncryptionKey, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrInvalidDataEncryptionKey, err)
+	}
+	return &Cipher{block: block, aead: aead}, nil
+}
+
+// NewWriter returns a writer encrypting data written to w. It writes a random
+// IV of IVSize bytes to w first.
+func (c *Cipher) NewWriter(w io.Writer) (io.Writer, error) {
+	iv := make([]byte, IVSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := w.Write(iv); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &cipher.StreamWriter{S: cipher.NewCTR(c.block, iv), W: w}, nil
+}
+
+// NewReader returns a reader decrypting data read from r, which is written by
+// a writer returned by NewWriter.
+func (c *Cipher) NewReader(r io.Reader) (io.Reader, error) {
+	iv := make([]byte, IVSize)
+	if _, err := io.ReadFull(r, iv); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &cipher.StreamReader{S: cipher.NewCTR(c.block, iv), R: r}, nil
+}
+
+// Overhead returns the difference between the lengths of a sealed record and
+// its plaintext.
+func (c *Cipher) Overhead() int {
+	return c.aead.NonceSize() + c.aead.Overhead()
+}
+
+// Seal encrypts a record with a random nonce, and appends the nonce and the
+// encrypted record to dst.
+func (c *Cipher) Seal(dst, plaintext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	ret, out := sliceForAppend(dst, nonceSize)
+	if _, err := rand.Read(out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.aead.Seal(ret, out, plaintext, nil), nil
+}
+
+// Open decrypts a record sealed by Seal, and appends the plaintext to dst.
+func (c *Cipher) Open(dst, sealed []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, cerror.ErrDataDecryptionFailed.GenWithStackByArgs()
+	}
+	plaintext, err := c.aead.Open(dst, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDataDecryptionFailed, err)
+	}
+	return plaintext, nil
+}
+
+// sliceForAppend extends in by n bytes, it returns the extended slice and the
+// extended part.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
+
+// LoadCipher loads the data key configured by cfg and creates a Cipher with
+// it. It returns nil if data isn't encrypted.
+func LoadCipher(ctx context.Context, cfg *config.DataEncryptionConfig) (*Cipher, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrInvalidDataEncryptionKey, err)
+	}
+	content = []byte(strings.TrimSpace(string(content)))
+
+	var key []byte
+	if cfg.KMSKeyID == "" {
+		key = make([]byte, hex.DecodedLen(len(content)))
+		if _, err := hex.Decode(key, content); err != nil {
+			return nil, cerror.WrapError(cerror.ErrInvalidDataEncryptionKey, err)
+		}
+	} else {
+		// The key file stores the CiphertextBlob returned by KMS, which is
+		// base64 encoded.
+		ciphertext := make([]byte, base64.StdEncoding.DecodedLen(len(content)))
+		n, err := base64.StdEncoding.Decode(ciphertext, content)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrInvalidDataEncryptionKey, err)
+		}
+		key, err = kmsDecrypt(ctx, cfg, ciphertext[:n])
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrInvalidDataEncryptionKey, err)
+		}
+	}
+	log.Info("data encryption key loaded",
+		zap.String("keyFile", cfg.KeyFile), zap.String("kmsKeyID", cfg.KMSKeyID))
+	return NewCipher(key)
+}
+
+// kmsDecrypt decrypts the data key by AWS KMS, it's a variable for tests.
+var kmsDecrypt = func(
+	ctx context.Context, cfg *config.DataEncryptionConfig, ciphertext []byte,
+) ([]byte, error) {
+	awsConfig := aws.NewConfig()
+	if cfg.KMSRegion != "" {
+		awsConfig.WithRegion(cfg.KMSRegion)
+	}
+	if cfg.KMSEndpoint != "" {
+		awsConfig.WithEndpoint(cfg.KMSEndpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	output, err := kms.New(sess).DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob: ciphertext,
+		KeyId:          aws.String(cfg.KMSKeyID),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return output.Plaintext, nil
+}
+
+// globalCipher is the Cipher of the data written to local disks by the
+// capture, it's nil if data isn't encrypted.
+var globalCipher atomic.Value
+
+// InitGlobalCipher loads the data key by cfg when the server starts.
+func InitGlobalCipher(ctx context.Context, cfg *config.DataEncryptionConfig) error {
+	c, err := LoadCipher(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	globalCipher.Store(c)
+	return nil
+}
+
+// GlobalCipher returns the Cipher of the data written to local disks by the
+// capture, it returns nil if data isn't encrypted.
+func GlobalCipher() *Cipher {
+	c, _ := globalCipher.Load().(*Cipher)
+	return c
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCipher(t *testing.T) *Cipher {
+	c, err := NewCipher(bytes.Repeat([]byte{1}, KeySize))
+	require.Nil(t, err)
+	return c
+}
+
+func TestNewCipher(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCipher(make([]byte, 16))
+	require.Regexp(t, ".*the key should be 32 bytes.*", err)
+	_, err = NewCipher(make([]byte, KeySize))
+	require.Nil(t, err)
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t)
+	data := bytes.Repeat([]byte("row data"), 1000)
+
+	var buf bytes.Buffer
+	w, err := c.NewWriter(&buf)
+	require.Nil(t, err)
+	// Write in pieces of odd sizes to cross the blocks.
+	for i := 0; i < len(data); i += 333 {
+		end := i + 333
+		if end > len(data) {
+			end = len(data)
+		}
+		_, err = w.Write(data[i:end])
+		require.Nil(t, err)
+	}
+	require.Equal(t, IVSize+len(data), buf.Len())
+	require.False(t, bytes.Contains(buf.Bytes(), []byte("row data")))
+
+	r, err := c.NewReader(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	decrypted, err := ioutil.ReadAll(r)
+	require.Nil(t, err)
+	require.Equal(t, data, decrypted)
+}
+
+func TestSealAndOpen(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t)
+	sealed, err := c.Seal([]byte("prefix"), []byte("row data"))
+	require.Nil(t, err)
+	require.Equal(t, len("prefix")+len("row data")+c.Overhead(), len(sealed))
+	require.Equal(t, []byte("prefix"), sealed[:len("prefix")])
+
+	plaintext, err := c.Open(nil, sealed[len("prefix"):])
+	require.Nil(t, err)
+	require.Equal(t, []byte("row data"), plaintext)
+
+	// Tampered or truncated records can't be opened.
+	sealed[len(sealed)-1] ^= 1
+	_, err = c.Open(nil, sealed[len("prefix"):])
+	require.Regexp(t, ".*ErrDataDecryptionFailed.*", err)
+	_, err = c.Open(nil, sealed[:4])
+	require.Regexp(t, ".*ErrDataDecryptionFailed.*", err)
+
+	// Records can't be opened by another key.
+	other, err := NewCipher(bytes.Repeat([]byte{2}, KeySize))
+	require.Nil(t, err)
+	sealed, err = c.Seal(nil, []byte("row data"))
+	require.Nil(t, err)
+	_, err = other.Open(nil, sealed)
+	require.Regexp(t, ".*ErrDataDecryptionFailed.*", err)
+}
+
+func TestLoadCipher(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{1}, KeySize)
+	keyFile := filepath.Join(t.TempDir(), "data.key")
+
+	// Plaintext.
+	c, err := LoadCipher(ctx, &config.DataEncryptionConfig{
+		Method: config.DataEncryptionMethodPlaintext,
+	})
+	require.Nil(t, err)
+	require.Nil(t, c)
+
+	// Hex encoded key.
+	cfg := &config.DataEncryptionConfig{
+		Method:  config.DataEncryptionMethodAES256,
+		KeyFile: keyFile,
+	}
+	require.Nil(t, ioutil.WriteFile(keyFile, []byte(hex.EncodeToString(key)+"\n"), 0o600))
+	c, err = LoadCipher(ctx, cfg)
+	require.Nil(t, err)
+	sealed, err := newTestCipher(t).Seal(nil, []byte("row data"))
+	require.Nil(t, err)
+	_, err = c.Open(nil, sealed)
+	require.Nil(t, err)
+
+	require.Nil(t, ioutil.WriteFile(keyFile, []byte("0102"), 0o600))
+	_, err = LoadCipher(ctx, cfg)
+	require.Regexp(t, ".*ErrInvalidDataEncryptionKey.*", err)
+
+	// Key encrypted by KMS.
+	old := kmsDecrypt
+	defer func() { kmsDecrypt = old }()
+	kmsDecrypt = func(
+		ctx context.Context, cfg *config.DataEncryptionConfig, ciphertext []byte,
+	) ([]byte, error) {
+		require.Equal(t, "test-key-id", cfg.KMSKeyID)
+		require.Equal(t, []byte("encrypted"), ciphertext)
+		return key, nil
+	}
+	cfg.KMSKeyID = "test-key-id"
+	require.Nil(t, ioutil.WriteFile(keyFile,
+		[]byte(base64.StdEncoding.EncodeToString([]byte("encrypted"))), 0o600))
+	c, err = LoadCipher(ctx, cfg)
+	require.Nil(t, err)
+	_, err = c.Open(nil, sealed)
+	require.Nil(t, err)
+}
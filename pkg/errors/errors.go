@@ -175,6 +175,14 @@ var (
 		"filter rule is invalid",
 		errors.RFCCodeText("CDC:ErrFilterRuleInvalid"),
 	)
+	ErrColumnSelectorFailed = errors.Normalize(
+		"columns of table %s are not selected by column selector, at least one primary key or unique key must be selected",
+		errors.RFCCodeText("CDC:ErrColumnSelectorFailed"),
+	)
+	ErrColumnTransformFailed = errors.Normalize(
+		"failed to transform column %s of table %s",
+		errors.RFCCodeText("CDC:ErrColumnTransformFailed"),
+	)
 
 	// internal errors
 	ErrAdminStopProcessor = errors.Normalize(
@@ -244,6 +252,14 @@ var (
 		"invalid topic expression",
 		errors.RFCCodeText("CDC:ErrKafkaTopicExprInvalid"),
 	)
+	ErrKafkaInvalidPartitionExpression = errors.Normalize(
+		"invalid partition expression: %s",
+		errors.RFCCodeText("CDC:ErrKafkaPartitionExprInvalid"),
+	)
+	ErrPulsarInvalidConfig = errors.Normalize(
+		"pulsar config invalid",
+		errors.RFCCodeText("CDC:ErrPulsarInvalidConfig"),
+	)
 	ErrPulsarNewProducer = errors.Normalize(
 		"new pulsar producer",
 		errors.RFCCodeText("CDC:ErrPulsarNewProducer"),
@@ -252,6 +268,58 @@ var (
 		"pulsar send message failed",
 		errors.RFCCodeText("CDC:ErrPulsarSendMessage"),
 	)
+	ErrPubSubInvalidConfig = errors.Normalize(
+		"pubsub config invalid",
+		errors.RFCCodeText("CDC:ErrPubSubInvalidConfig"),
+	)
+	ErrPubSubNewProducer = errors.Normalize(
+		"new pubsub producer",
+		errors.RFCCodeText("CDC:ErrPubSubNewProducer"),
+	)
+	ErrPubSubCreateTopic = errors.Normalize(
+		"pubsub create topic failed",
+		errors.RFCCodeText("CDC:ErrPubSubCreateTopic"),
+	)
+	ErrPubSubSendMessage = errors.Normalize(
+		"pubsub send message failed",
+		errors.RFCCodeText("CDC:ErrPubSubSendMessage"),
+	)
+	ErrCloudStorageInvalidConfig = errors.Normalize(
+		"cloud storage sink config invalid",
+		errors.RFCCodeText("CDC:ErrCloudStorageInvalidConfig"),
+	)
+	ErrCloudStorageWrite = errors.Normalize(
+		"cloud storage sink write failed",
+		errors.RFCCodeText("CDC:ErrCloudStorageWrite"),
+	)
+	ErrElasticsearchInvalidConfig = errors.Normalize(
+		"elasticsearch sink config invalid",
+		errors.RFCCodeText("CDC:ErrElasticsearchInvalidConfig"),
+	)
+	ErrElasticsearchBulk = errors.Normalize(
+		"elasticsearch bulk request failed",
+		errors.RFCCodeText("CDC:ErrElasticsearchBulk"),
+	)
+	ErrWebhookInvalidConfig = errors.Normalize(
+		"webhook sink config invalid",
+		errors.RFCCodeText("CDC:ErrWebhookInvalidConfig"),
+	)
+	ErrWebhookRequest = errors.Normalize(
+		"webhook request failed",
+		errors.RFCCodeText("CDC:ErrWebhookRequest"),
+	)
+	ErrPluginSinkInvalidConfig = errors.Normalize(
+		"plugin sink config invalid",
+		errors.RFCCodeText("CDC:ErrPluginSinkInvalidConfig"),
+	)
+	ErrPluginSinkRequest = errors.Normalize(
+		"sink plugin request failed",
+		errors.RFCCodeText("CDC:ErrPluginSinkRequest"),
+	)
+	ErrPluginSinkUnhealthy = errors.Normalize(
+		"sink plugin is unhealthy: %s",
+		errors.RFCCodeText("CDC:ErrPluginSinkUnhealthy"),
+	)
 	ErrRedoConfigInvalid = errors.Normalize(
 		"redo log config invalid",
 		errors.RFCCodeText("CDC:ErrRedoConfigInvalid"),
@@ -264,6 +332,10 @@ var (
 		"redo log writer stopped",
 		errors.RFCCodeText("CDC:ErrRedoWriterStopped"),
 	)
+	ErrRedoNotEnabled = errors.Normalize(
+		"redo log is not enabled for changefeed %s",
+		errors.RFCCodeText("CDC:ErrRedoNotEnabled"),
+	)
 	ErrRedoFileOp = errors.Normalize(
 		"redo file operation",
 		errors.RFCCodeText("CDC:ErrRedoFileOp"),
@@ -281,13 +353,17 @@ var (
 		errors.RFCCodeText("CDC:ErrFileSizeExceed"),
 	)
 	ErrS3StorageAPI = errors.Normalize(
-		"s3 storage api",
+		"external storage api",
 		errors.RFCCodeText("CDC:ErrS3StorageAPI"),
 	)
 	ErrS3StorageInitialize = errors.Normalize(
-		"new s3 storage for redo log",
+		"new external storage for redo log",
 		errors.RFCCodeText("CDC:ErrS3StorageInitialize"),
 	)
+	ErrRedoArchive = errors.Normalize(
+		"archive redo log %s",
+		errors.RFCCodeText("CDC:ErrRedoArchive"),
+	)
 	ErrMQCodecInvalidConfig = errors.Normalize(
 		"MQ Codec invalid config",
 		errors.RFCCodeText("CDC:ErrMQCodecInvalidConfig"),
@@ -304,6 +380,14 @@ var (
 		"unknown '%s' protocol for Message Queue sink",
 		errors.RFCCodeText("CDC:ErrMQSinkUnknownProtocol"),
 	)
+	ErrSinkFatalByErrorPolicy = errors.Normalize(
+		"the %s error of sink is fatal by the error policy: %s",
+		errors.RFCCodeText("CDC:ErrSinkFatalByErrorPolicy"),
+	)
+	ErrSinkIgnoredRowsExceeded = errors.Normalize(
+		"more than %d rows failed with the ignored errors are skipped, the last error: %s",
+		errors.RFCCodeText("CDC:ErrSinkIgnoredRowsExceeded"),
+	)
 	ErrMySQLTxnError = errors.Normalize(
 		"MySQL txn error",
 		errors.RFCCodeText("CDC:ErrMySQLTxnError"),
@@ -326,6 +410,11 @@ var (
 	)
 	ErrMQWorkerClosed = errors.Normalize("MQ worker has closed",
 		errors.RFCCodeText("CDC:ErrMQWorkerClosed"))
+	ErrMQRowTooLarge = errors.Normalize(
+		"the message of a single row of table %s exceeds max-message-bytes %d, "+
+			"set large-message-handle to truncate it or send it to a dead letter topic",
+		errors.RFCCodeText("CDC:ErrMQRowTooLarge"),
+	)
 	ErrAvroToEnvelopeError = errors.Normalize(
 		"to envelope failed",
 		errors.RFCCodeText("CDC:ErrAvroToEnvelopeError"),
@@ -350,6 +439,18 @@ var (
 		"schema manager API error",
 		errors.RFCCodeText("CDC:ErrAvroSchemaAPIError"),
 	)
+	ErrProtobufEncodeFailed = errors.Normalize(
+		"encode to protobuf failed",
+		errors.RFCCodeText("CDC:ErrProtobufEncodeFailed"),
+	)
+	ErrProtobufUnknownType = errors.Normalize(
+		"unknown type for Protobuf: %v",
+		errors.RFCCodeText("CDC:ErrProtobufUnknownType"),
+	)
+	ErrDebeziumEncodeFailed = errors.Normalize(
+		"debezium encode failed",
+		errors.RFCCodeText("CDC:ErrDebeziumEncodeFailed"),
+	)
 	ErrMaxwellEncodeFailed = errors.Normalize(
 		"maxwell encode failed",
 		errors.RFCCodeText("CDC:ErrMaxwellEncodeFailed"),
@@ -396,6 +497,10 @@ var (
 		"generate tls config failed",
 		errors.RFCCodeText("CDC:ErrToTLSConfigFailed"),
 	)
+	ErrTLSPeerCertNotPinned = errors.Normalize(
+		"the certificate of the remote doesn't match the pinning, %s",
+		errors.RFCCodeText("CDC:ErrTLSPeerCertNotPinned"),
+	)
 	ErrCheckClusterVersionFromPD = errors.Normalize(
 		"failed to request PD %s, please try again later",
 		errors.RFCCodeText("CDC:ErrCheckClusterVersionFromPD"),
@@ -486,6 +591,10 @@ var (
 			`eg, "simple-changefeed-task"`),
 		errors.RFCCodeText("CDC:ErrInvalidChangefeedID"),
 	)
+	ErrInvalidChangefeedLabel = errors.Normalize(
+		`bad changefeed label name %s, please match the pattern "^[a-zA-Z_][a-zA-Z0-9_]*$" and do not start with "__"`,
+		errors.RFCCodeText("CDC:ErrInvalidChangefeedLabel"),
+	)
 	ErrInvalidEtcdKey = errors.Normalize(
 		"invalid key: %s",
 		errors.RFCCodeText("CDC:ErrInvalidEtcdKey"),
@@ -636,6 +745,18 @@ var (
 		"changefeed update error: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateRefused"),
 	)
+	ErrChangefeedRewindRefused = errors.Normalize(
+		"changefeed rewind error: %s",
+		errors.RFCCodeText("CDC:ErrChangefeedRewindRefused"),
+	)
+	ErrConsistencyReportNotExists = errors.Normalize(
+		"consistency report of changefeed %s not exists",
+		errors.RFCCodeText("CDC:ErrConsistencyReportNotExists"),
+	)
+	ErrConsistencyReportRefused = errors.Normalize(
+		"consistency report of changefeed %s is refused: %s",
+		errors.RFCCodeText("CDC:ErrConsistencyReportRefused"),
+	)
 	ErrChangefeedAbnormalState = errors.Normalize(
 		"changefeed in abnormal state: %s, replication status: %+v",
 		errors.RFCCodeText("CDC:ErrChangefeedAbnormalState"),
@@ -817,6 +938,11 @@ var (
 		"unified sorter backend is terminating",
 		errors.RFCCodeText("CDC:ErrUnifiedSorterBackendTerminating"),
 	)
+	ErrUnifiedSorterDiskQuotaExceeded = errors.Normalize(
+		"the unified sorter of table %s uses %d bytes of disk, exceeding "+
+			"sorter.max-disk-consumption-per-table %d of changefeed %s",
+		errors.RFCCodeText("CDC:ErrUnifiedSorterDiskQuotaExceeded"),
+	)
 	ErrUnifiedSorterIOError = errors.Normalize(
 		"unified sorter IO error. Make sure your sort-dir is "+
 			"configured correctly by passing a valid argument or toml file to"+
@@ -828,6 +954,38 @@ var (
 		"illegal parameter for sorter: %s",
 		errors.RFCCodeText("CDC:ErrIllegalSorterParameter"),
 	)
+	ErrInvalidAdmissionControlConfig = errors.Normalize(
+		"invalid admission control config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidAdmissionControlConfig"),
+	)
+	ErrInvalidIncrementalScanConfig = errors.Normalize(
+		"invalid incremental scan config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidIncrementalScanConfig"),
+	)
+	ErrInvalidSchemaDriftConfig = errors.Normalize(
+		"invalid schema drift config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidSchemaDriftConfig"),
+	)
+	ErrInvalidDataContractConfig = errors.Normalize(
+		"invalid data contract config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidDataContractConfig"),
+	)
+	ErrInvalidHealthCheckConfig = errors.Normalize(
+		"invalid health check config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidHealthCheckConfig"),
+	)
+	ErrInvalidHibernationConfig = errors.Normalize(
+		"invalid hibernation config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidHibernationConfig"),
+	)
+	ErrHealthCheckNotEnabled = errors.Normalize(
+		"health check is not enabled for changefeed %s",
+		errors.RFCCodeText("CDC:ErrHealthCheckNotEnabled"),
+	)
+	ErrDataContractViolation = errors.Normalize(
+		"DDL %s breaks the data contracts of the downstream: %s",
+		errors.RFCCodeText("CDC:ErrDataContractViolation"),
+	)
 	ErrAsyncIOCancelled = errors.Normalize(
 		"asynchronous IO operation is cancelled. Internal use only, "+
 			"report a bug if seen in log",
@@ -849,6 +1007,22 @@ var (
 		"sorter is closed",
 		errors.RFCCodeText("CDC:ErrSorterClosed"),
 	)
+	ErrInvalidDataEncryptionKey = errors.Normalize(
+		"invalid data encryption key: %s",
+		errors.RFCCodeText("CDC:ErrInvalidDataEncryptionKey"),
+	)
+	ErrDataDecryptionFailed = errors.Normalize(
+		"failed to decrypt data, make sure the data encryption key is the one used to write it",
+		errors.RFCCodeText("CDC:ErrDataDecryptionFailed"),
+	)
+	ErrSecretResolveFailed = errors.Normalize(
+		"resolve secret %s:%s failed: %s",
+		errors.RFCCodeText("CDC:ErrSecretResolveFailed"),
+	)
+	ErrSecretNotFound = errors.Normalize(
+		"secret %s not found",
+		errors.RFCCodeText("CDC:ErrSecretNotFound"),
+	)
 
 	// processor errors
 	ErrProcessorDuplicateOperations = errors.Normalize(
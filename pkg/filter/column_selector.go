@@ -0,0 +1,127 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"strings"
+
+	filterV2 "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// ColumnSelector selects the columns of tables to replicate by the column
+// selectors in the sink config. A table uses the first selector matching it,
+// all the columns of tables matched by no selector are replicated.
+type ColumnSelector struct {
+	selectors []*columnSelector
+}
+
+type columnSelector struct {
+	tableFilter filterV2.Filter
+	// includes are the patterns of selected columns, all the columns are
+	// selected if it's empty.
+	includes *ColumnMatcher
+	// excludes are the patterns prefixed with `!`, matched columns are never
+	// selected.
+	excludes *ColumnMatcher
+}
+
+// NewColumnSelector creates a ColumnSelector, it returns nil if no column
+// selector is configured.
+func NewColumnSelector(cfg *config.ReplicaConfig) (*ColumnSelector, error) {
+	if cfg.Sink == nil || len(cfg.Sink.ColumnSelectors) == 0 {
+		return nil, nil
+	}
+	selectors := make([]*columnSelector, 0, len(cfg.Sink.ColumnSelectors))
+	for _, rule := range cfg.Sink.ColumnSelectors {
+		f, err := NewTableMatcher(rule.Matcher, cfg.CaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		var includes, excludes []string
+		for _, column := range rule.Columns {
+			if strings.HasPrefix(column, "!") {
+				excludes = append(excludes, strings.TrimPrefix(column, "!"))
+			} else {
+				includes = append(includes, column)
+			}
+		}
+		s := &columnSelector{tableFilter: f}
+		if s.includes, err = NewColumnMatcher(includes, cfg.CaseSensitive); err != nil {
+			return nil, err
+		}
+		if s.excludes, err = NewColumnMatcher(excludes, cfg.CaseSensitive); err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+	}
+	return &ColumnSelector{selectors: selectors}, nil
+}
+
+func (s *columnSelector) selectColumn(name string) bool {
+	if s.excludes.Match(name) {
+		return false
+	}
+	return s.includes.Empty() || s.includes.Match(name)
+}
+
+// Apply removes the columns of the row which are not selected, the removed
+// columns are set to nil so the offsets of index columns are kept. It fails
+// if no primary key or unique key of the table is fully selected, since the
+// downstream can't identify the rows then.
+func (s *ColumnSelector) Apply(row *model.RowChangedEvent) error {
+	if s == nil {
+		return nil
+	}
+	var selector *columnSelector
+	for _, sel := range s.selectors {
+		if sel.tableFilter.MatchTable(row.Table.Schema, row.Table.Table) {
+			selector = sel
+			break
+		}
+	}
+	if selector == nil {
+		return nil
+	}
+
+	removed := make(map[int]struct{})
+	removeColumns := func(cols []*model.Column) {
+		for i, col := range cols {
+			if col != nil && !selector.selectColumn(col.Name) {
+				cols[i] = nil
+				removed[i] = struct{}{}
+			}
+		}
+	}
+	removeColumns(row.Columns)
+	removeColumns(row.PreColumns)
+	if len(removed) == 0 || len(row.IndexColumns) == 0 {
+		return nil
+	}
+	for _, index := range row.IndexColumns {
+		kept := true
+		for _, offset := range index {
+			if _, ok := removed[offset]; ok {
+				kept = false
+				break
+			}
+		}
+		if kept {
+			return nil
+		}
+	}
+	return cerror.ErrColumnSelectorFailed.GenWithStackByArgs(row.Table.String())
+}
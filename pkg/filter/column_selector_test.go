@@ -0,0 +1,106 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnSelector(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	selector, err := NewColumnSelector(cfg)
+	require.Nil(t, err)
+	require.Nil(t, selector)
+	// a nil selector keeps all the columns.
+	require.Nil(t, selector.Apply(&model.RowChangedEvent{}))
+
+	cfg.CaseSensitive = false
+	cfg.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"test.users"}, Columns: []string{"!phone", "!card_*"}},
+		{Matcher: []string{"test.*"}, Columns: []string{"id", "name"}},
+	}
+	selector, err = NewColumnSelector(cfg)
+	require.Nil(t, err)
+
+	newRow := func(table string) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: table},
+			Columns: []*model.Column{
+				{Name: "id", Value: 1},
+				{Name: "Name", Value: "a"},
+				{Name: "phone", Value: "123"},
+				{Name: "card_no", Value: "456"},
+			},
+			PreColumns: []*model.Column{
+				{Name: "id", Value: 1},
+				{Name: "Name", Value: "b"},
+				{Name: "phone", Value: "123"},
+				{Name: "card_no", Value: "456"},
+			},
+			IndexColumns: [][]int{{0}},
+		}
+	}
+
+	// the first matched selector is used.
+	row := newRow("users")
+	require.Nil(t, selector.Apply(row))
+	require.NotNil(t, row.Columns[0])
+	require.NotNil(t, row.Columns[1])
+	require.Nil(t, row.Columns[2])
+	require.Nil(t, row.Columns[3])
+	require.Nil(t, row.PreColumns[2])
+	require.Nil(t, row.PreColumns[3])
+
+	row = newRow("orders")
+	require.Nil(t, selector.Apply(row))
+	require.NotNil(t, row.Columns[0])
+	require.NotNil(t, row.Columns[1])
+	require.Nil(t, row.Columns[2])
+	require.Nil(t, row.Columns[3])
+
+	// the rows of tables matched by no selector are kept.
+	row = newRow("orders")
+	row.Table.Schema = "other"
+	require.Nil(t, selector.Apply(row))
+	require.NotNil(t, row.Columns[3])
+
+	// the handle key must be selected.
+	row = newRow("orders")
+	row.IndexColumns = [][]int{{2}}
+	require.Regexp(t, ".*at least one primary key or unique key must be selected.*", selector.Apply(row))
+
+	// the column patterns are case sensitive if the config is.
+	cfg.CaseSensitive = true
+	cfg.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"test.*"}, Columns: []string{"id", "name"}},
+	}
+	selector, err = NewColumnSelector(cfg)
+	require.Nil(t, err)
+	row = newRow("orders")
+	require.Nil(t, selector.Apply(row))
+	require.NotNil(t, row.Columns[0])
+	require.Nil(t, row.Columns[1])
+
+	cfg.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"test.*"}, Columns: []string{"[id"}},
+	}
+	_, err = NewColumnSelector(cfg)
+	require.Regexp(t, ".*invalid column pattern.*", err)
+}
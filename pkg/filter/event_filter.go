@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"strings"
+
+	filterV2 "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The DML event types which can be ignored by event filters.
+const (
+	eventTypeInsert = "insert"
+	eventTypeUpdate = "update"
+	eventTypeDelete = "delete"
+)
+
+// EventFilter drops the DML events of the types ignored by the event filter
+// rules in the filter config, a row is dropped if any rule matching its table
+// ignores its type.
+type EventFilter struct {
+	rules []*eventFilterRule
+}
+
+type eventFilterRule struct {
+	tableFilter  filterV2.Filter
+	ignoreInsert bool
+	ignoreUpdate bool
+	ignoreDelete bool
+}
+
+// NewEventFilter creates an EventFilter from the event filter rules of cfg,
+// or returns nil if there are none.
+func NewEventFilter(cfg *config.ReplicaConfig) (*EventFilter, error) {
+	if cfg.Filter == nil || len(cfg.Filter.EventFilters) == 0 {
+		return nil, nil
+	}
+	rules := make([]*eventFilterRule, 0, len(cfg.Filter.EventFilters))
+	for _, rule := range cfg.Filter.EventFilters {
+		f, err := NewTableMatcher(rule.Matcher, cfg.CaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		r := &eventFilterRule{tableFilter: f}
+		for _, tp := range rule.IgnoreEvent {
+			switch strings.ToLower(tp) {
+			case eventTypeInsert:
+				r.ignoreInsert = true
+			case eventTypeUpdate:
+				r.ignoreUpdate = true
+			case eventTypeDelete:
+				r.ignoreDelete = true
+			default:
+				return nil, cerror.ErrFilterRuleInvalid.GenWithStack(
+					"invalid event type %s of event filter %v, valid values are %s, %s and %s",
+					tp, rule.Matcher, eventTypeInsert, eventTypeUpdate, eventTypeDelete)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return &EventFilter{rules: rules}, nil
+}
+
+// ShouldIgnoreRow returns true if the row should be dropped. Updates are
+// distinguished from inserts by their old values, so it must be called before
+// the old values of rows are removed.
+func (f *EventFilter) ShouldIgnoreRow(row *model.RowChangedEvent) bool {
+	if f == nil {
+		return false
+	}
+	for _, rule := range f.rules {
+		if !rule.tableFilter.MatchTable(row.Table.Schema, row.Table.Table) {
+			continue
+		}
+		if (rule.ignoreInsert && row.IsInsert()) ||
+			(rule.ignoreUpdate && row.IsUpdate()) ||
+			(rule.ignoreDelete && row.IsDelete()) {
+			return true
+		}
+	}
+	return false
+}
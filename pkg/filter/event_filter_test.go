@@ -0,0 +1,77 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFilter(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	f, err := NewEventFilter(cfg)
+	require.Nil(t, err)
+	require.Nil(t, f)
+	// a nil event filter keeps all the rows.
+	require.False(t, f.ShouldIgnoreRow(&model.RowChangedEvent{}))
+
+	cfg.Filter.EventFilters = []*config.EventFilterRule{
+		{Matcher: []string{"test.users"}, IgnoreEvent: []string{"Delete"}},
+		{Matcher: []string{"test.log_*"}, IgnoreEvent: []string{"update", "delete"}},
+	}
+	f, err = NewEventFilter(cfg)
+	require.Nil(t, err)
+
+	cols := []*model.Column{{Name: "id", Value: 1}}
+	newRow := func(table string, preCols, cols []*model.Column) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table:      &model.TableName{Schema: "test", Table: table},
+			Columns:    cols,
+			PreColumns: preCols,
+		}
+	}
+	testCases := []struct {
+		row    *model.RowChangedEvent
+		ignore bool
+	}{
+		{row: newRow("users", nil, cols), ignore: false},
+		{row: newRow("users", cols, cols), ignore: false},
+		{row: newRow("users", cols, nil), ignore: true},
+		{row: newRow("USERS", cols, nil), ignore: false},
+		{row: newRow("log_1", nil, cols), ignore: false},
+		{row: newRow("log_1", cols, cols), ignore: true},
+		{row: newRow("log_1", cols, nil), ignore: true},
+		{row: newRow("orders", cols, nil), ignore: false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.ignore, f.ShouldIgnoreRow(tc.row), "table %s", tc.row.Table)
+	}
+
+	// the tables are matched case insensitively if the config is.
+	cfg.CaseSensitive = false
+	f, err = NewEventFilter(cfg)
+	require.Nil(t, err)
+	require.True(t, f.ShouldIgnoreRow(newRow("USERS", cols, nil)))
+
+	cfg.Filter.EventFilters = []*config.EventFilterRule{
+		{Matcher: []string{"test.*"}, IgnoreEvent: []string{"truncate"}},
+	}
+	_, err = NewEventFilter(cfg)
+	require.Regexp(t, "invalid event type truncate", err)
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"path"
+	"strings"
+
+	filterV2 "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// NewTableMatcher parses the table-filter rules of a per-table config item,
+// such as a column selector or an event filter. The names of tables are
+// matched case insensitively unless caseSensitive is set.
+func NewTableMatcher(matcher []string, caseSensitive bool) (filterV2.Filter, error) {
+	f, err := filterV2.Parse(matcher)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err)
+	}
+	if !caseSensitive {
+		f = filterV2.CaseInsensitive(f)
+	}
+	return f, nil
+}
+
+// ColumnMatcher matches the names of columns by glob patterns, the names are
+// matched case insensitively unless it's case sensitive.
+type ColumnMatcher struct {
+	patterns      []string
+	caseSensitive bool
+}
+
+// NewColumnMatcher creates a ColumnMatcher of the glob patterns.
+func NewColumnMatcher(patterns []string, caseSensitive bool) (*ColumnMatcher, error) {
+	m := &ColumnMatcher{
+		patterns:      make([]string, 0, len(patterns)),
+		caseSensitive: caseSensitive,
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, cerror.ErrFilterRuleInvalid.GenWithStack("invalid column pattern %s", pattern)
+		}
+		if !caseSensitive {
+			pattern = strings.ToLower(pattern)
+		}
+		m.patterns = append(m.patterns, pattern)
+	}
+	return m, nil
+}
+
+// Empty returns true if there is no pattern in the matcher.
+func (m *ColumnMatcher) Empty() bool {
+	return len(m.patterns) == 0
+}
+
+// Match returns true if the column name matches any pattern.
+func (m *ColumnMatcher) Match(name string) bool {
+	if !m.caseSensitive {
+		name = strings.ToLower(name)
+	}
+	for _, pattern := range m.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
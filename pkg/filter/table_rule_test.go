@@ -0,0 +1,61 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableMatcher(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewTableMatcher([]string{"Test.T*"}, true)
+	require.Nil(t, err)
+	require.True(t, f.MatchTable("Test", "T1"))
+	require.False(t, f.MatchTable("test", "t1"))
+
+	f, err = NewTableMatcher([]string{"Test.T*"}, false)
+	require.Nil(t, err)
+	require.True(t, f.MatchTable("test", "t1"))
+
+	_, err = NewTableMatcher([]string{"test.["}, false)
+	require.True(t, cerror.ErrFilterRuleInvalid.Equal(err))
+}
+
+func TestColumnMatcher(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewColumnMatcher([]string{"Card_*", "id"}, true)
+	require.Nil(t, err)
+	require.False(t, m.Empty())
+	require.True(t, m.Match("Card_no"))
+	require.False(t, m.Match("card_no"))
+	require.True(t, m.Match("id"))
+	require.False(t, m.Match("name"))
+
+	m, err = NewColumnMatcher([]string{"Card_*"}, false)
+	require.Nil(t, err)
+	require.True(t, m.Match("CARD_NO"))
+
+	m, err = NewColumnMatcher(nil, false)
+	require.Nil(t, err)
+	require.True(t, m.Empty())
+	require.False(t, m.Match("id"))
+
+	_, err = NewColumnMatcher([]string{"["}, false)
+	require.Regexp(t, ".*invalid column pattern.*", err)
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logutil
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The components whose log configs can be changed at runtime.
+const (
+	ComponentKVClient = "kvclient"
+	ComponentSink     = "sink"
+	ComponentRelay    = "relay"
+	ComponentSyncer   = "syncer"
+)
+
+// ComponentLogConfig is the runtime log config of a component.
+type ComponentLogConfig struct {
+	// Level is the log level of the component, empty means the global level.
+	Level string `json:"level"`
+	// DebugSamplesPerSecond is the max number of debug logs with the same
+	// message written by the component per second, 0 means unlimited.
+	DebugSamplesPerSecond uint64 `json:"debug-samples-per-second"`
+}
+
+// sampleBuckets is the number of buckets the messages of the debug logs are
+// hashed into for sampling.
+const sampleBuckets = 1024
+
+type component struct {
+	// config is a *componentConfig, it's replaced as a whole when changed.
+	config atomic.Value
+	// logger is a *cachedLogger of the global logger.
+	logger atomic.Value
+	counts [sampleBuckets]sampleCounter
+}
+
+type componentConfig struct {
+	ComponentLogConfig
+	level zapcore.Level
+}
+
+type cachedLogger struct {
+	base   *zap.Logger
+	logger *zap.Logger
+}
+
+type sampleCounter struct {
+	resetAt int64
+	n       uint64
+}
+
+// incr increases the counter and returns the count within the second.
+func (c *sampleCounter) incr(now int64) uint64 {
+	resetAt := atomic.LoadInt64(&c.resetAt)
+	if resetAt > now {
+		return atomic.AddUint64(&c.n, 1)
+	}
+	if !atomic.CompareAndSwapInt64(&c.resetAt, resetAt, now+int64(time.Second)) {
+		return atomic.AddUint64(&c.n, 1)
+	}
+	atomic.StoreUint64(&c.n, 1)
+	return 1
+}
+
+var components = func() map[string]*component {
+	m := make(map[string]*component)
+	for _, name := range []string{ComponentKVClient, ComponentSink, ComponentRelay, ComponentSyncer} {
+		c := &component{}
+		c.config.Store(&componentConfig{})
+		m[name] = c
+	}
+	return m
+}()
+
+func (c *component) getConfig() *componentConfig {
+	return c.config.Load().(*componentConfig)
+}
+
+// componentCore follows the runtime log config of the component instead of
+// the level of the wrapped core. The wrapped core must not check the level
+// again when writing, which holds for the cores of zap and pingcap/log.
+type componentCore struct {
+	zapcore.Core
+	c *component
+}
+
+func (core *componentCore) Enabled(lvl zapcore.Level) bool {
+	if cfg := core.c.getConfig(); cfg.Level != "" {
+		return cfg.level.Enabled(lvl)
+	}
+	return core.Core.Enabled(lvl)
+}
+
+func (core *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{Core: core.Core.With(fields), c: core.c}
+}
+
+func (core *componentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !core.Enabled(ent.Level) {
+		return ce
+	}
+	if samples := core.c.getConfig().DebugSamplesPerSecond; samples > 0 && ent.Level == zapcore.DebugLevel {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(ent.Message))
+		if core.c.counts[h.Sum32()%sampleBuckets].incr(ent.Time.UnixNano()) > samples {
+			return ce
+		}
+	}
+	return ce.AddCore(ent, core)
+}
+
+// WithComponent returns a logger whose level and sampling of debug logs follow
+// the runtime log config of the component, see SetComponentLogConfig. The
+// logger is returned as is if the component is unknown.
+func WithComponent(lg *zap.Logger, name string) *zap.Logger {
+	c, ok := components[name]
+	if !ok {
+		return lg
+	}
+	return lg.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &componentCore{Core: core, c: c}
+	}))
+}
+
+// ComponentLogger returns the global logger of the component, it's cached
+// until the global logger is replaced.
+func ComponentLogger(name string) *zap.Logger {
+	base := log.L()
+	c, ok := components[name]
+	if !ok {
+		return base
+	}
+	if cached, _ := c.logger.Load().(*cachedLogger); cached != nil && cached.base == base {
+		return cached.logger
+	}
+	lg := WithComponent(base, name)
+	c.logger.Store(&cachedLogger{base: base, logger: lg})
+	return lg
+}
+
+// SetComponentLogConfig changes the log config of the component at runtime.
+func SetComponentLogConfig(name string, cfg ComponentLogConfig) error {
+	c, ok := components[name]
+	if !ok {
+		return errors.Errorf("unknown log component %s", name)
+	}
+	newCfg := &componentConfig{ComponentLogConfig: cfg}
+	if cfg.Level != "" {
+		if err := newCfg.level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return errors.Trace(err)
+		}
+		newCfg.Level = newCfg.level.String()
+	}
+	c.config.Store(newCfg)
+	return nil
+}
+
+// ComponentLogConfigs returns the runtime log configs of all the components.
+func ComponentLogConfigs() map[string]ComponentLogConfig {
+	configs := make(map[string]ComponentLogConfig, len(components))
+	for name, c := range components {
+		configs[name] = c.getConfig().ComponentLogConfig
+	}
+	return configs
+}
+
+// ComponentLogRequest is the request body to change the log config of a
+// component.
+type ComponentLogRequest struct {
+	Component string `json:"component"`
+	ComponentLogConfig
+}
+
+// ComponentLogHandler returns a HTTP handler which lists the runtime log
+// configs of the components on GET, and changes the log config of a component
+// on POST with a JSON body like
+// `{"component": "sink", "level": "debug", "debug-samples-per-second": 10}`.
+func ComponentLogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var body ComponentLogRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := SetComponentLogConfig(body.Component, body.ComponentLogConfig); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Warn("component log config changed", zap.String("component", body.Component),
+				zap.String("level", body.Level), zap.Uint64("debugSamplesPerSecond", body.DebugSamplesPerSecond))
+		default:
+			http.Error(w, "only GET and POST are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ComponentLogConfigs())
+	})
+}
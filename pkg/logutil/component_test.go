@@ -0,0 +1,107 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestComponentLogConfig(t *testing.T) {
+	defer func() {
+		require.Nil(t, SetComponentLogConfig(ComponentSink, ComponentLogConfig{}))
+	}()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	lg := WithComponent(zap.New(core), ComponentSink).With(zap.String("changefeed", "test"))
+	other := WithComponent(zap.New(core), ComponentKVClient)
+
+	lg.Debug("debug")
+	require.Equal(t, 0, logs.Len())
+
+	// the level of the component is lower than the global level.
+	require.Nil(t, SetComponentLogConfig(ComponentSink, ComponentLogConfig{Level: "DEBUG"}))
+	lg.Debug("debug")
+	other.Debug("debug")
+	require.Equal(t, 1, logs.Len())
+	require.Equal(t, "test", logs.All()[0].ContextMap()["changefeed"])
+
+	// the level of the component is higher than the global level.
+	require.Nil(t, SetComponentLogConfig(ComponentSink, ComponentLogConfig{Level: "error"}))
+	lg.Warn("warn")
+	other.Warn("warn")
+	require.Equal(t, 2, logs.Len())
+	require.Equal(t, map[string]ComponentLogConfig{
+		ComponentKVClient: {},
+		ComponentSink:     {Level: "error"},
+		ComponentRelay:    {},
+		ComponentSyncer:   {},
+	}, ComponentLogConfigs())
+
+	// the debug logs with the same message are sampled.
+	require.Nil(t, SetComponentLogConfig(ComponentSink,
+		ComponentLogConfig{Level: "debug", DebugSamplesPerSecond: 2}))
+	logs.TakeAll()
+	for i := 0; i < 5; i++ {
+		lg.Debug("hot")
+		lg.Info("info")
+	}
+	lg.Debug("cold")
+	require.Equal(t, 2, logs.FilterMessage("hot").Len())
+	require.Equal(t, 5, logs.FilterMessage("info").Len())
+	require.Equal(t, 1, logs.FilterMessage("cold").Len())
+
+	require.Regexp(t, ".*unknown log component unknown.*",
+		SetComponentLogConfig("unknown", ComponentLogConfig{}))
+	require.Error(t, SetComponentLogConfig(ComponentSink, ComponentLogConfig{Level: "verbose"}))
+	nop := zap.NewNop()
+	require.Same(t, nop, WithComponent(nop, "unknown"))
+}
+
+func TestComponentLogHandler(t *testing.T) {
+	defer func() {
+		require.Nil(t, SetComponentLogConfig(ComponentRelay, ComponentLogConfig{}))
+	}()
+
+	handler := ComponentLogHandler()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/log/components",
+		strings.NewReader(`{"component": "relay", "level": "debug", "debug-samples-per-second": 10}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	configs := make(map[string]ComponentLogConfig)
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &configs))
+	require.Equal(t, ComponentLogConfig{Level: "debug", DebugSamplesPerSecond: 10}, configs[ComponentRelay])
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/log/components",
+		strings.NewReader(`{"component": "unknown"}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/log/components", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/log/components", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"relay":{"level":"debug","debug-samples-per-second":10}`)
+}
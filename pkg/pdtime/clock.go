@@ -37,9 +37,15 @@ type Clock interface {
 // PDClock cache time get from PD periodically and cache it
 type PDClock struct {
 	pdClient pd.Client
-	mu       struct {
+	// stalenessBudget is how long the cached time is extrapolated by the local
+	// clock without an error once PD is unavailable, so a short PD outage
+	// doesn't fail the changefeeds.
+	stalenessBudget time.Duration
+	mu              struct {
 		sync.RWMutex
 		timeCache time.Time
+		// updatedAt is the local time when timeCache is got from PD.
+		updatedAt time.Time
 		err       error
 	}
 	cancel context.CancelFunc
@@ -47,16 +53,18 @@ type PDClock struct {
 }
 
 // NewClock return a new PDClock
-func NewClock(ctx context.Context, pdClient pd.Client) (*PDClock, error) {
+func NewClock(ctx context.Context, pdClient pd.Client, stalenessBudget time.Duration) (*PDClock, error) {
 	ret := &PDClock{
-		pdClient: pdClient,
-		stopCh:   make(chan struct{}, 1),
+		pdClient:        pdClient,
+		stalenessBudget: stalenessBudget,
+		stopCh:          make(chan struct{}, 1),
 	}
 	physical, _, err := pdClient.GetTS(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	ret.mu.timeCache = oracle.GetTimeFromTS(oracle.ComposeTS(physical, 0))
+	ret.mu.updatedAt = time.Now()
 	return ret, nil
 }
 
@@ -79,15 +87,21 @@ func (c *PDClock) Run(ctx context.Context) {
 					return err
 				}
 				c.mu.Lock()
+				if c.mu.err != nil {
+					log.Info("get time from pd recovered",
+						zap.Duration("unavailable", time.Since(c.mu.updatedAt)))
+				}
 				c.mu.timeCache = oracle.GetTimeFromTS(oracle.ComposeTS(physical, 0))
+				c.mu.updatedAt = time.Now()
 				c.mu.err = nil
 				c.mu.Unlock()
 				return nil
 			}, retry.WithBackoffBaseDelay(200), retry.WithMaxTries(10))
 			if err != nil {
-				log.Warn("get time from pd failed, will use local time as pd time")
 				c.mu.Lock()
-				c.mu.timeCache = time.Now()
+				log.Warn("get time from pd failed, extrapolate the cached pd time by local time",
+					zap.Duration("staleness", time.Since(c.mu.updatedAt)),
+					zap.Duration("stalenessBudget", c.stalenessBudget), zap.Error(err))
 				c.mu.err = err
 				c.mu.Unlock()
 			}
@@ -95,13 +109,23 @@ func (c *PDClock) Run(ctx context.Context) {
 	}
 }
 
-// CurrentTime returns current time from timeCache
+// CurrentTime returns current time from timeCache. If PD is unavailable, the
+// cached time is extrapolated by the local time, and an error is returned only
+// after the staleness exceeds the staleness budget.
 func (c *PDClock) CurrentTime() (time.Time, error) {
 	c.mu.RLock()
 	err := c.mu.err
 	cacheTime := c.mu.timeCache
+	updatedAt := c.mu.updatedAt
 	c.mu.RUnlock()
-	return cacheTime, errors.Trace(err)
+	if err == nil {
+		return cacheTime, nil
+	}
+	staleness := time.Since(updatedAt)
+	if staleness <= c.stalenessBudget {
+		return cacheTime.Add(staleness), nil
+	}
+	return cacheTime.Add(staleness), errors.Annotatef(err, "pd is unavailable for %s", staleness)
 }
 
 // Stop PDClock.
@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
@@ -36,7 +37,7 @@ func (m *MockPDClient) GetTS(ctx context.Context) (int64, int64, error) {
 func TestTimeFromPD(t *testing.T) {
 	t.Parallel()
 	mockPDClient := &MockPDClient{}
-	clock, err := NewClock(context.Background(), mockPDClient)
+	clock, err := NewClock(context.Background(), mockPDClient, time.Minute)
 	require.NoError(t, err)
 
 	go clock.Run(context.Background())
@@ -53,3 +54,29 @@ func TestTimeFromPD(t *testing.T) {
 	// should return new time
 	require.NotEqual(t, t1, t2)
 }
+
+func TestTimeExtrapolatedWhenPDUnavailable(t *testing.T) {
+	t.Parallel()
+	clock, err := NewClock(context.Background(), &MockPDClient{}, time.Minute)
+	require.NoError(t, err)
+
+	cached, err := clock.CurrentTime()
+	require.NoError(t, err)
+
+	// pd is unavailable for a while within the staleness budget.
+	clock.mu.Lock()
+	clock.mu.updatedAt = time.Now().Add(-30 * time.Second)
+	clock.mu.err = errors.New("pd is unavailable")
+	clock.mu.Unlock()
+	t1, err := clock.CurrentTime()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, t1.Sub(cached), 30*time.Second)
+
+	// pd is unavailable for longer than the staleness budget.
+	clock.mu.Lock()
+	clock.mu.updatedAt = time.Now().Add(-2 * time.Minute)
+	clock.mu.Unlock()
+	t2, err := clock.CurrentTime()
+	require.Regexp(t, "pd is unavailable", err)
+	require.GreaterOrEqual(t, t2.Sub(cached), 2*time.Minute)
+}
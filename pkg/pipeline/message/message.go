@@ -80,9 +80,14 @@ const (
 	CommandTypeUnknown CommandType = iota
 	// CommandTypeStop means the table pipeline should stop at once
 	CommandTypeStop
+	// CommandTypeStopAtTs means the table pipeline should stop after the sink
+	// is flushed exactly to StopTs
+	CommandTypeStopAtTs
 )
 
 // Command is the command about table pipeline
 type Command struct {
 	Tp CommandType
+	// StopTs is the boundary ts of CommandTypeStopAtTs
+	StopTs model.Ts
 }
@@ -0,0 +1,74 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// awsResolver resolves secrets from AWS Secrets Manager. The ref is in the
+// format `<secret-id>[#<key>]`, the secret string is parsed as a JSON object
+// if the key is specified, and the whole secret string is used otherwise.
+//
+// The region and the credentials are read from the standard environment
+// variables and shared config files of AWS SDK.
+type awsResolver struct{}
+
+func (awsResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitRef(ref)
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *aws.NewConfig(),
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	output, err := secretsmanager.New(sess).GetSecretValueWithContext(ctx,
+		&secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	value := aws.StringValue(output.SecretString)
+	if output.SecretString == nil {
+		value = string(output.SecretBinary)
+	}
+	if key == "" {
+		return value, nil
+	}
+	return lookupJSONKey(value, key, ref)
+}
+
+// lookupJSONKey returns the value of the key in a JSON object.
+func lookupJSONKey(object, key, ref string) (string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(object), &fields); err != nil {
+		return "", errors.Annotate(err, "secret is not a JSON object")
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", cerror.ErrSecretNotFound.GenWithStackByArgs(ref)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// k8sResolver resolves secrets by the Kubernetes API from inside the cluster,
+// with the service account of the pod. The ref is in the format
+// `[<namespace>/]<name>#<key>`, the namespace of the pod is used if it's not
+// specified.
+//
+// Secrets mounted as volumes can be resolved by the file resolver as well,
+// without granting the service account the permission to read secrets.
+type k8sResolver struct {
+	// apiServer is the address of the API server, it's read from the
+	// environment variables if it's empty.
+	apiServer string
+	// saDir is the directory of the service account token, CA certificate
+	// and namespace.
+	saDir string
+	// allowedSecrets are the secrets can be resolved, in the format of
+	// `<namespace>/<name>`, the name can be `*`.
+	allowedSecrets []string
+}
+
+func newK8sResolver(allowedSecrets []string) *k8sResolver {
+	return &k8sResolver{saDir: serviceAccountDir, allowedSecrets: allowedSecrets}
+}
+
+func (r *k8sResolver) isAllowed(namespace, name string) bool {
+	for _, allowed := range r.allowedSecrets {
+		if allowed == namespace+"/"+name || allowed == namespace+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *k8sResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	if key == "" {
+		return "", errors.Errorf("key is not specified in %s", ref)
+	}
+	namespace, name := "", path
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		namespace, name = path[:i], path[i+1:]
+	}
+	if namespace == "" {
+		ns, err := ioutil.ReadFile(r.saDir + "/namespace")
+		if err != nil {
+			return "", errors.Annotate(err, "read namespace of the pod")
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+	if !r.isAllowed(namespace, name) {
+		return "", errors.Errorf("kubernetes secret %s/%s is not allowed by the server config", namespace, name)
+	}
+
+	apiServer := r.apiServer
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return "", errors.New("not running in a Kubernetes cluster")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+	}
+	token, err := ioutil.ReadFile(r.saDir + "/token")
+	if err != nil {
+		return "", errors.Annotate(err, "read service account token")
+	}
+	client, err := r.newClient()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s",
+			apiServer, url.PathEscape(namespace), url.PathEscape(name)), nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", cerror.ErrSecretNotFound.GenWithStackByArgs(ref)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("kubernetes API server responds %s", resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Trace(err)
+	}
+	encoded, ok := secret.Data[key]
+	if !ok {
+		return "", cerror.ErrSecretNotFound.GenWithStackByArgs(ref)
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(value), nil
+}
+
+// newClient creates a client trusting the CA of the cluster.
+func (r *k8sResolver) newClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	ca, err := ioutil.ReadFile(r.saDir + "/ca.crt")
+	if err == nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("invalid CA certificate of the service account")
+		}
+		tlsConfig.RootCAs = pool
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	return &http.Client{
+		Timeout: secretRequestTimeout,
+		// the client is created for each secret, so the connections are
+		// not kept alive
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: true},
+	}, nil
+}
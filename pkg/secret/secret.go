@@ -0,0 +1,139 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Resolver resolves the secrets stored in a secret manager.
+type Resolver interface {
+	// Resolve returns the value of the secret referenced by ref, the format
+	// of ref is defined by the resolver.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+var (
+	resolversMu sync.RWMutex
+	// The secrets of vault and k8s can't be referenced until they're allowed
+	// by SetAllowlist.
+	resolvers = map[string]Resolver{
+		"vault": newVaultResolver(nil),
+		"k8s":   newK8sResolver(nil),
+		"aws":   awsResolver{},
+	}
+)
+
+// Register registers the resolver of a secret provider, the resolver of an
+// existing provider is replaced.
+func Register(provider string, r Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	resolvers[provider] = r
+}
+
+// SetAllowlist sets the secrets can be referenced in URIs. vaultPaths are the
+// paths of vault the secrets under which are allowed, and k8sSecrets are the
+// kubernetes secrets in the format of `<namespace>/<name>`, the name can be
+// `*` to allow all the secrets in the namespace.
+func SetAllowlist(vaultPaths, k8sSecrets []string) {
+	Register("vault", newVaultResolver(vaultPaths))
+	Register("k8s", newK8sResolver(k8sSecrets))
+}
+
+func getResolver(provider string) (Resolver, bool) {
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	r, ok := resolvers[provider]
+	return r, ok
+}
+
+// placeholderRegexp matches the secret placeholders in URIs, the format is
+// `${secret:<provider>:<ref>}`, e.g.
+// `mysql://root:${secret:vault:secret/data/tidb#password}@127.0.0.1:4000/`.
+var placeholderRegexp = regexp.MustCompile(`\$\{secret:([A-Za-z0-9_-]+):([^}]+)\}`)
+
+// maskedSecret replaces the placeholders in MaskURI.
+const maskedSecret = "secret"
+
+// HasPlaceholder returns whether the uri contains secret placeholders.
+func HasPlaceholder(uri string) bool {
+	return placeholderRegexp.MatchString(uri)
+}
+
+// ResolveURI replaces the secret placeholders in the uri with the values of
+// the secrets. The values are escaped, so they can be used in any part of
+// the uri.
+func ResolveURI(ctx context.Context, uri string) (string, error) {
+	matches := placeholderRegexp.FindAllStringSubmatchIndex(uri, -1)
+	if len(matches) == 0 {
+		return uri, nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		provider, ref := uri[m[2]:m[3]], uri[m[4]:m[5]]
+		value, err := Resolve(ctx, provider, ref)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(uri[last:m[0]])
+		b.WriteString(escape(value))
+		last = m[1]
+	}
+	b.WriteString(uri[last:])
+	return b.String(), nil
+}
+
+// Resolve returns the value of the secret referenced by ref of the provider.
+func Resolve(ctx context.Context, provider, ref string) (string, error) {
+	r, ok := getResolver(provider)
+	if !ok {
+		return "", cerror.ErrSecretResolveFailed.GenWithStackByArgs(
+			provider, ref, "unknown secret provider")
+	}
+	value, err := r.Resolve(ctx, ref)
+	if err != nil {
+		return "", cerror.ErrSecretResolveFailed.GenWithStackByArgs(
+			provider, ref, err.Error())
+	}
+	return value, nil
+}
+
+// MaskURI replaces the secret placeholders in the uri with a constant, so the
+// uri can be parsed without resolving the secrets, e.g. to get its scheme.
+func MaskURI(uri string) string {
+	return placeholderRegexp.ReplaceAllLiteralString(uri, maskedSecret)
+}
+
+// escape escapes the value like url.QueryEscape, except that spaces are
+// escaped to %20, which is decoded as space in both user info and query.
+func escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// splitRef splits a ref in the format `<path>#<key>`, key is empty if it's
+// not specified.
+func splitRef(ref string) (path, key string) {
+	if i := strings.LastIndexByte(ref, '#'); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockResolver map[string]string
+
+func (r mockResolver) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := r[ref]
+	if !ok {
+		return "", cerror.ErrSecretNotFound.GenWithStackByArgs(ref)
+	}
+	return value, nil
+}
+
+func TestResolveURI(t *testing.T) {
+	Register("mock", mockResolver{
+		"user":     "root",
+		"password": "p@ss word/#?",
+		"topic":    "test",
+	})
+	ctx := context.Background()
+
+	uri := "mysql://${secret:mock:user}:${secret:mock:password}@127.0.0.1:3306/?topic=${secret:mock:topic}"
+	require.True(t, HasPlaceholder(uri))
+	resolved, err := ResolveURI(ctx, uri)
+	require.Nil(t, err)
+	require.False(t, HasPlaceholder(resolved))
+	u, err := url.Parse(resolved)
+	require.Nil(t, err)
+	require.Equal(t, "root", u.User.Username())
+	password, _ := u.User.Password()
+	require.Equal(t, "p@ss word/#?", password)
+	require.Equal(t, "127.0.0.1:3306", u.Host)
+	require.Equal(t, "test", u.Query().Get("topic"))
+
+	// uri without placeholders is returned as is
+	uri = "kafka://127.0.0.1:9092/topic?protocol=canal-json"
+	resolved, err = ResolveURI(ctx, uri)
+	require.Nil(t, err)
+	require.Equal(t, uri, resolved)
+
+	_, err = ResolveURI(ctx, "mysql://root:${secret:mock:unknown}@127.0.0.1:3306/")
+	require.True(t, cerror.ErrSecretResolveFailed.Equal(err))
+	_, err = ResolveURI(ctx, "mysql://root:${secret:unknown:password}@127.0.0.1:3306/")
+	require.True(t, cerror.ErrSecretResolveFailed.Equal(err))
+}
+
+func TestMaskURI(t *testing.T) {
+	uri := "mysql://${secret:vault:secret/data/tidb#user}:${secret:vault:secret/data/tidb#password}@127.0.0.1:3306/"
+	_, err := url.Parse(uri)
+	require.NotNil(t, err)
+	u, err := url.Parse(MaskURI(uri))
+	require.Nil(t, err)
+	require.Equal(t, "mysql", u.Scheme)
+	require.Equal(t, "127.0.0.1:3306", u.Host)
+	require.Equal(t, "secret", u.User.Username())
+}
+
+func TestSetAllowlist(t *testing.T) {
+	defer SetAllowlist(nil, nil)
+	ctx := context.Background()
+
+	// the secrets of vault and k8s are not allowed by default
+	_, err := Resolve(ctx, "vault", "secret/data/tidb#password")
+	require.Regexp(t, "vault path secret/data/tidb is not allowed", err)
+	_, err = Resolve(ctx, "k8s", "tidb/tidb-secret#password")
+	require.Regexp(t, "kubernetes secret tidb/tidb-secret is not allowed", err)
+	// env and file are not providers
+	_, err = Resolve(ctx, "env", "HOME")
+	require.Regexp(t, "unknown secret provider", err)
+
+	SetAllowlist([]string{"secret/data/tidb"}, []string{"tidb/*"})
+	r, _ := getResolver("vault")
+	require.True(t, r.(*vaultResolver).isAllowed("secret/data/tidb"))
+	r, _ = getResolver("k8s")
+	require.True(t, r.(*k8sResolver).isAllowed("tidb", "tidb-secret"))
+}
+
+func TestVaultResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/tidb":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"v2-secret"},"metadata":{"version":1}}}`))
+		case "/v1/kv/tidb":
+			_, _ = w.Write([]byte(`{"data":{"password":"v1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := newVaultResolver([]string{"/secret/data/tidb/", "kv/tidb", "kv/not-exist"})
+	defer r.client.CloseIdleConnections()
+	env := map[string]string{"VAULT_ADDR": server.URL, "VAULT_TOKEN": "token"}
+	r.getenv = func(key string) string { return env[key] }
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "secret/data/tidb#password")
+	require.Nil(t, err)
+	require.Equal(t, "v2-secret", value)
+	value, err = r.Resolve(ctx, "kv/tidb#password")
+	require.Nil(t, err)
+	require.Equal(t, "v1-secret", value)
+	_, err = r.Resolve(ctx, "kv/tidb#user")
+	require.True(t, cerror.ErrSecretNotFound.Equal(err))
+	_, err = r.Resolve(ctx, "kv/not-exist#password")
+	require.True(t, cerror.ErrSecretNotFound.Equal(err))
+	_, err = r.Resolve(ctx, "kv/tidb")
+	require.NotNil(t, err)
+
+	// the paths not in the allowlist are rejected
+	for _, ref := range []string{
+		"kv/tidb2#password", "kv#password", "secret/data/other#password",
+		"kv/tidb/../other#password", "kv/tidb/a?b#password",
+	} {
+		_, err = r.Resolve(ctx, ref)
+		require.Regexp(t, "is not allowed", err, ref)
+	}
+
+	env["VAULT_TOKEN"] = "invalid"
+	_, err = r.Resolve(ctx, "kv/tidb#password")
+	require.Regexp(t, "403", err)
+}
+
+func TestK8sResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/namespaces/tidb/secrets/tidb-secret":
+			// base64 of "k8s-secret"
+			_, _ = w.Write([]byte(`{"data":{"password":"azhzLXNlY3JldA=="}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	saDir := t.TempDir()
+	require.Nil(t, ioutil.WriteFile(filepath.Join(saDir, "token"), []byte("token\n"), 0o600))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(saDir, "namespace"), []byte("tidb"), 0o600))
+	r := &k8sResolver{
+		apiServer:      server.URL,
+		saDir:          saDir,
+		allowedSecrets: []string{"tidb/tidb-secret", "default/*"},
+	}
+	ctx := context.Background()
+
+	value, err := r.Resolve(ctx, "tidb/tidb-secret#password")
+	require.Nil(t, err)
+	require.Equal(t, "k8s-secret", value)
+	// the namespace of the pod is used
+	value, err = r.Resolve(ctx, "tidb-secret#password")
+	require.Nil(t, err)
+	require.Equal(t, "k8s-secret", value)
+	_, err = r.Resolve(ctx, "tidb-secret#user")
+	require.True(t, cerror.ErrSecretNotFound.Equal(err))
+	_, err = r.Resolve(ctx, "default/tidb-secret#password")
+	require.True(t, cerror.ErrSecretNotFound.Equal(err))
+	_, err = r.Resolve(ctx, "tidb/other-secret#password")
+	require.Regexp(t, "kubernetes secret tidb/other-secret is not allowed", err)
+	_, err = r.Resolve(ctx, "kube-system/tidb-secret#password")
+	require.Regexp(t, "kubernetes secret kube-system/tidb-secret is not allowed", err)
+}
+
+func TestLookupJSONKey(t *testing.T) {
+	value, err := lookupJSONKey(`{"password":"aws-secret","port":3306}`, "password", "tidb#password")
+	require.Nil(t, err)
+	require.Equal(t, "aws-secret", value)
+	value, err = lookupJSONKey(`{"password":"aws-secret","port":3306}`, "port", "tidb#port")
+	require.Nil(t, err)
+	require.Equal(t, "3306", value)
+	_, err = lookupJSONKey(`{"password":"aws-secret"}`, "user", "tidb#user")
+	require.True(t, cerror.ErrSecretNotFound.Equal(err))
+	_, err = lookupJSONKey("aws-secret", "password", "tidb#password")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const secretRequestTimeout = 10 * time.Second
+
+// vaultResolver resolves secrets from the KV secrets engine of HashiCorp
+// Vault. The ref is in the format `<path>#<key>`, e.g.
+// `secret/data/tidb#password`. Both version 1 and version 2 of the engine are
+// supported, the path of version 2 must contain the `data/` segment.
+//
+// The address and the token of Vault are read from the standard environment
+// variables VAULT_ADDR and VAULT_TOKEN, and VAULT_NAMESPACE if any.
+type vaultResolver struct {
+	client *http.Client
+	getenv func(string) string
+	// allowedPaths are the paths the secrets under which can be resolved.
+	allowedPaths []string
+}
+
+func newVaultResolver(allowedPaths []string) *vaultResolver {
+	return &vaultResolver{
+		client:       &http.Client{Timeout: secretRequestTimeout},
+		getenv:       os.Getenv,
+		allowedPaths: allowedPaths,
+	}
+}
+
+// isAllowed returns whether the path is under one of the allowed paths.
+func (r *vaultResolver) isAllowed(path string) bool {
+	path = strings.Trim(path, "/")
+	if strings.ContainsAny(path, "?#") {
+		return false
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	for _, allowed := range r.allowedPaths {
+		allowed = strings.Trim(allowed, "/")
+		if allowed != "" && (path == allowed || strings.HasPrefix(path, allowed+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *vaultResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	if key == "" {
+		return "", errors.Errorf("key is not specified in %s", ref)
+	}
+	if !r.isAllowed(path) {
+		return "", errors.Errorf("vault path %s is not allowed by the server config", path)
+	}
+	addr, token := r.getenv("VAULT_ADDR"), r.getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", errors.New("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/v1/%s", strings.TrimRight(addr, "/"), strings.TrimLeft(path, "/")), nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := r.getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", cerror.ErrSecretNotFound.GenWithStackByArgs(ref)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("vault responds %s", resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Trace(err)
+	}
+	data := secret.Data
+	// The data of KV version 2 are nested in data.data.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", cerror.ErrSecretNotFound.GenWithStackByArgs(ref)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
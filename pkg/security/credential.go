@@ -14,10 +14,13 @@
 package security
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"os"
+	"strings"
 
 	"github.com/pingcap/tidb-tools/pkg/utils"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -32,6 +35,12 @@ type Credential struct {
 	CertPath      string   `toml:"cert-path" json:"cert-path"`
 	KeyPath       string   `toml:"key-path" json:"key-path"`
 	CertAllowedCN []string `toml:"cert-allowed-cn" json:"cert-allowed-cn"`
+	// CertAllowedSAN pins the Subject Alternative Names (DNS names, IPs or URIs),
+	// the certificate of the remote must contain one of them.
+	CertAllowedSAN []string `toml:"cert-allowed-san" json:"cert-allowed-san"`
+	// CertAllowedSPKI pins the base64 encoded SHA-256 digests of the Subject Public
+	// Key Info, one of the certificates in the verified chain must match one of them.
+	CertAllowedSPKI []string `toml:"cert-allowed-spki" json:"cert-allowed-spki"`
 }
 
 // IsTLSEnabled checks whether TLS is enabled or not.
@@ -57,12 +66,109 @@ func (s *Credential) ToGRPCDialOption() (grpc.DialOption, error) {
 	return grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)), nil
 }
 
+// ToGRPCDialOptionWithPinning constructs a gRPC dial option which pins the
+// certificate of the remote, it should only be used to dial PD and TiKV.
+func (s *Credential) ToGRPCDialOptionWithPinning() (grpc.DialOption, error) {
+	tlsCfg, err := s.ToTLSConfigWithPinning()
+	if err != nil || tlsCfg == nil {
+		return grpc.WithInsecure(), err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)), nil
+}
+
 // ToTLSConfig generates tls's config from *Security
 func (s *Credential) ToTLSConfig() (*tls.Config, error) {
 	cfg, err := utils.ToTLSConfig(s.CAPath, s.CertPath, s.KeyPath)
 	return cfg, cerror.WrapError(cerror.ErrToTLSConfigFailed, err)
 }
 
+// ToTLSConfigWithPinning generates tls's config from *Security, the SAN and
+// SPKI pinning is applied to the certificate of the remote if configured.
+// The pins are only meant for the endpoint they are configured for (PD, TiKV
+// or a sink), so clients of other endpoints must use ToTLSConfig.
+func (s *Credential) ToTLSConfigWithPinning() (*tls.Config, error) {
+	cfg, err := s.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil && s.hasPinning() {
+		cfg.VerifyPeerCertificate = s.verifyPinning
+	}
+	return cfg, nil
+}
+
+func (s *Credential) hasPinning() bool {
+	return len(s.CertAllowedSAN) != 0 || len(s.CertAllowedSPKI) != 0
+}
+
+// verifyPinning is called after the normal certificate verification, it checks
+// the remote certificate against the pinned SANs and SPKI digests.
+func (s *Credential) verifyPinning(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+		return cerror.ErrTLSPeerCertNotPinned.GenWithStackByArgs("no verified certificate")
+	}
+	leaf := verifiedChains[0][0]
+
+	if len(s.CertAllowedSAN) != 0 && !matchSAN(leaf, s.CertAllowedSAN) {
+		return cerror.ErrTLSPeerCertNotPinned.GenWithStackByArgs("SAN mismatch")
+	}
+
+	if len(s.CertAllowedSPKI) != 0 {
+		for _, chain := range verifiedChains {
+			for _, cert := range chain {
+				if matchSPKI(cert, s.CertAllowedSPKI) {
+					return nil
+				}
+			}
+		}
+		return cerror.ErrTLSPeerCertNotPinned.GenWithStackByArgs("SPKI mismatch")
+	}
+	return nil
+}
+
+// SplitPins splits the comma separated SANs or SPKI digests, which are usually
+// carried in the sink uri. Note that the `+` in base64 digests should be escaped
+// as `%2B` in uri.
+func SplitPins(s string) []string {
+	var pins []string
+	for _, pin := range strings.Split(s, ",") {
+		if pin = strings.TrimSpace(pin); pin != "" {
+			pins = append(pins, pin)
+		}
+	}
+	return pins
+}
+
+func matchSAN(cert *x509.Certificate, allowed []string) bool {
+	names := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses)+len(cert.URIs))
+	names = append(names, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, name := range names {
+		for _, san := range allowed {
+			if name == san {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSPKI(cert *x509.Certificate, allowed []string) bool {
+	digest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(digest[:])
+	for _, spki := range allowed {
+		if pin == spki {
+			return true
+		}
+	}
+	return false
+}
+
 // ToTLSConfigWithVerify generates tls's config from *Security and requires
 // the remote common name to be verified.
 func (s *Credential) ToTLSConfigWithVerify() (*tls.Config, error) {
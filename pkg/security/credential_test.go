@@ -14,6 +14,11 @@
 package security
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -34,3 +39,58 @@ func TestGetCommonName(t *testing.T) {
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), "failed to decode PEM block to certificate")
 }
+
+func TestVerifyPinning(t *testing.T) {
+	data, err := os.ReadFile("../../tests/integration_tests/_certificates/server.pem")
+	require.Nil(t, err)
+	block, _ := pem.Decode(data)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.Nil(t, err)
+	digest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	spki := base64.StdEncoding.EncodeToString(digest[:])
+	chains := [][]*x509.Certificate{{cert}}
+
+	cd := &Credential{}
+	require.False(t, cd.hasPinning())
+
+	cd.CertAllowedSAN = []string{"127.0.0.1"}
+	require.True(t, cd.hasPinning())
+	require.Nil(t, cd.verifyPinning(nil, chains))
+	cd.CertAllowedSAN = []string{"tidb.example.com"}
+	require.Regexp(t, ".*SAN mismatch.*", cd.verifyPinning(nil, chains))
+
+	cd.CertAllowedSAN = nil
+	cd.CertAllowedSPKI = []string{spki}
+	require.Nil(t, cd.verifyPinning(nil, chains))
+	cd.CertAllowedSPKI = []string{"AAAA"}
+	require.Regexp(t, ".*SPKI mismatch.*", cd.verifyPinning(nil, chains))
+
+	require.Regexp(t, ".*no verified certificate.*", cd.verifyPinning(nil, nil))
+}
+
+func TestToTLSConfigWithPinning(t *testing.T) {
+	cd := &Credential{
+		CAPath:         "../../tests/integration_tests/_certificates/ca.pem",
+		CertPath:       "../../tests/integration_tests/_certificates/server.pem",
+		KeyPath:        "../../tests/integration_tests/_certificates/server-key.pem",
+		CertAllowedSAN: []string{"127.0.0.1"},
+	}
+	cfg, err := cd.ToTLSConfigWithPinning()
+	require.Nil(t, err)
+	require.NotNil(t, cfg.VerifyPeerCertificate)
+
+	// the pins are not applied to clients of other endpoints
+	cfg, err = cd.ToTLSConfig()
+	require.Nil(t, err)
+	require.Nil(t, cfg.VerifyPeerCertificate)
+
+	cd.CertAllowedSAN = nil
+	cfg, err = cd.ToTLSConfigWithPinning()
+	require.Nil(t, err)
+	require.Nil(t, cfg.VerifyPeerCertificate)
+}
+
+func TestSplitPins(t *testing.T) {
+	require.Nil(t, SplitPins(""))
+	require.Equal(t, []string{"a", "b"}, SplitPins("a, b,"))
+}
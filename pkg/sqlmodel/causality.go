@@ -42,6 +42,26 @@ func (r *RowChange) CausalityKeys() []string {
 	return ret
 }
 
+// HandleKeys returns the causality keys of the handle of the row, which is the
+// primary key or the not null unique key, or all the values if there is no such
+// key. Unlike CausalityKeys, the keys of the other unique indexes are excluded,
+// so the RowChange only conflicts with the changes of the same row.
+func (r *RowChange) HandleKeys() []string {
+	r.lazyInitWhereHandle()
+
+	ret := make([]string, 0, 2)
+	if r.preValues != nil {
+		ret = append(ret, r.getHandleString(r.preValues))
+	}
+	if r.postValues != nil {
+		key := r.getHandleString(r.postValues)
+		if len(ret) == 0 || ret[0] != key {
+			ret = append(ret, key)
+		}
+	}
+	return ret
+}
+
 func columnValue2String(value interface{}) string {
 	var data string
 	switch v := value.(type) {
@@ -135,6 +155,17 @@ func truncateIndexValues(
 	return values
 }
 
+func (r *RowChange) getHandleString(values []interface{}) string {
+	indexCols := r.whereHandle.UniqueNotNullIdx
+	if indexCols == nil {
+		return genKeyString(r.sourceTable.String(), r.sourceTableInfo.Columns, values)
+	}
+	cols, vals := getColsAndValuesOfIdx(r.sourceTableInfo.Columns, indexCols, values)
+	// handle prefix index
+	truncVals := truncateIndexValues(r.tiSessionCtx, r.sourceTableInfo, indexCols, cols, vals)
+	return genKeyString(r.sourceTable.String(), cols, truncVals)
+}
+
 func (r *RowChange) getCausalityString(values []interface{}) []string {
 	pkAndUks := r.whereHandle.UniqueIdxs
 	if len(pkAndUks) == 0 {
@@ -87,6 +87,47 @@ func TestCausalityKeys(t *testing.T) {
 	}
 }
 
+func TestHandleKeys(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb1"}
+
+	cases := []struct {
+		createSQL string
+		preValue  []interface{}
+		postValue []interface{}
+
+		handleKeys []string
+	}{
+		{
+			"CREATE TABLE tb1 (c INT PRIMARY KEY, c2 INT, c3 VARCHAR(10) UNIQUE)",
+			[]interface{}{1, 2, "abc"},
+			[]interface{}{3, 4, "abc"},
+			[]string{"1.c.db.tb1", "3.c.db.tb1"},
+		},
+		// test the handle is not changed
+		{
+			"CREATE TABLE tb1 (c INT PRIMARY KEY, c2 INT, c3 VARCHAR(10) UNIQUE)",
+			[]interface{}{1, 2, "abc"},
+			[]interface{}{1, 4, "def"},
+			[]string{"1.c.db.tb1"},
+		},
+		// test no primary key or not null unique key
+		{
+			"CREATE TABLE tb1 (a INT, b INT, UNIQUE KEY a(a))",
+			nil,
+			[]interface{}{100, 200},
+			[]string{"100.a.200.b.db.tb1"},
+		},
+	}
+
+	for _, ca := range cases {
+		ti := mockTableInfo(t, ca.createSQL)
+		change := NewRowChange(source, nil, ca.preValue, ca.postValue, ti, nil, nil)
+		require.Equal(t, ca.handleKeys, change.HandleKeys())
+	}
+}
+
 func TestCausalityKeysNoRace(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,152 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"fmt"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"go.uber.org/zap"
+
+	cdcmodel "github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+)
+
+const (
+	// sqlOverheadBytes covers the keywords of a DML, like "INSERT INTO",
+	// " ON DUPLICATE KEY UPDATE " and " LIMIT 1".
+	sqlOverheadBytes = 64
+	// columnOverheadBytes covers the separators and placeholders around a
+	// column name, like ", ", " = ?" and " AND ".
+	columnOverheadBytes = 16
+	// numberBytes is the max length of an integer in text.
+	numberBytes = 20
+	// floatBytes is the max length of a float in text.
+	floatBytes = 24
+	// nullBytes is the length of NULL.
+	nullBytes = 4
+)
+
+// ApproxArgsBytes returns the approximate size of the arguments of the SQL
+// generated by this RowChange, it's the sum of the size of preValues and
+// postValues in their text form, so it doesn't underestimate the arguments of
+// any DMLType.
+func (r *RowChange) ApproxArgsBytes() int {
+	return approxValuesBytes(r.preValues) + approxValuesBytes(r.postValues)
+}
+
+// EstimatedSize returns the estimated size of the SQL and its arguments
+// generated by GenSQL with the DMLType of the same type of this RowChange,
+// i.e. DMLInsert for RowChangeInsert, DMLUpdate for RowChangeUpdate and
+// DMLDelete for RowChangeDelete. Callers can use it to limit the size of a
+// transaction before generating SQL.
+func (r *RowChange) EstimatedSize() int {
+	size := sqlOverheadBytes + tableNameBytes(r.targetTable)
+	switch r.tp {
+	case RowChangeUpdate:
+		// the columns are in both SET and WHERE.
+		size += columnsBytes(r.sourceTableInfo.Columns, 2)
+	default:
+		size += columnsBytes(r.sourceTableInfo.Columns, 1)
+	}
+	return size + r.ApproxArgsBytes()
+}
+
+// EstimatedBatchSize returns the estimated size of the SQL and its arguments
+// generated by GenInsertSQL or GenDeleteSQL for the row changes. For DMLUpdate
+// it returns the sum of EstimatedSize of the row changes, because they are
+// generated one by one.
+// Input `changes` should meet the same requirements of GenInsertSQL and
+// GenDeleteSQL, otherwise the result is undefined.
+func EstimatedBatchSize(tp DMLType, changes ...*RowChange) int {
+	if len(changes) == 0 {
+		return 0
+	}
+
+	first := changes[0]
+	columns := first.sourceTableInfo.Columns
+	size := sqlOverheadBytes + tableNameBytes(first.targetTable)
+	// every row has a values holder like (?,?,?), and a comma between them.
+	rowHolderBytes := 2*len(columns) + 2
+
+	switch tp {
+	case DMLInsert, DMLReplace, DMLInsertOnDuplicateUpdate:
+		if tp == DMLInsertOnDuplicateUpdate {
+			// `c`=VALUES(`c`) for every column.
+			size += columnsBytes(columns, 3)
+		} else {
+			size += columnsBytes(columns, 1)
+		}
+		for _, change := range changes {
+			size += rowHolderBytes + approxValuesBytes(change.postValues)
+		}
+	case DMLDelete:
+		size += columnsBytes(columns, 1)
+		for _, change := range changes {
+			size += rowHolderBytes + approxValuesBytes(change.preValues)
+		}
+	case DMLUpdate:
+		size = 0
+		for _, change := range changes {
+			size += change.EstimatedSize()
+		}
+	default:
+		log.L().DPanic("illegal type for EstimatedBatchSize",
+			zap.String("sourceTable", first.sourceTable.String()),
+			zap.Stringer("DMLType", tp))
+		return 0
+	}
+	return size
+}
+
+func tableNameBytes(table *cdcmodel.TableName) int {
+	// `schema`.`table`
+	return len(table.Schema) + len(table.Table) + 5
+}
+
+func columnsBytes(columns []*timodel.ColumnInfo, nameCount int) int {
+	size := 0
+	for _, col := range columns {
+		// the quoted name is written nameCount times.
+		size += nameCount*(len(col.Name.O)+2) + columnOverheadBytes
+	}
+	return size
+}
+
+func approxValuesBytes(values []interface{}) int {
+	size := 0
+	for _, v := range values {
+		size += approxValueBytes(v)
+	}
+	return size
+}
+
+func approxValueBytes(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return nullBytes
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case bool:
+		return 1
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return numberBytes
+	case float32, float64:
+		return floatBytes
+	default:
+		return len(fmt.Sprintf("%v", v))
+	}
+}
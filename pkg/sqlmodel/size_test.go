@@ -0,0 +1,111 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cdcmodel "github.com/pingcap/tiflow/cdc/model"
+)
+
+func argsTextBytes(args []interface{}) int {
+	size := 0
+	for _, arg := range args {
+		size += len(fmt.Sprintf("%v", arg))
+	}
+	return size
+}
+
+func TestApproxArgsBytes(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb"}
+	sourceTI := mockTableInfo(t, "CREATE TABLE tb (id INT PRIMARY KEY, name VARCHAR(20), data BLOB, score DOUBLE)")
+
+	insert := NewRowChange(source, nil, nil, []interface{}{1, "abc", []byte("12345"), nil}, sourceTI, nil, nil)
+	require.Equal(t, numberBytes+3+5+nullBytes, insert.ApproxArgsBytes())
+
+	del := NewRowChange(source, nil, []interface{}{1, "abc", nil, 1.5}, nil, sourceTI, nil, nil)
+	require.Equal(t, numberBytes+3+nullBytes+floatBytes, del.ApproxArgsBytes())
+
+	update := NewRowChange(source, nil,
+		[]interface{}{1, "abc", nil, nil}, []interface{}{1, "abcd", nil, nil}, sourceTI, nil, nil)
+	require.Equal(t, 2*numberBytes+7+4*nullBytes, update.ApproxArgsBytes())
+}
+
+func TestEstimatedSize(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb1"}
+	target := &cdcmodel.TableName{Schema: "db", Table: "a_long_table_name"}
+	sourceTI := mockTableInfo(t, "CREATE TABLE tb1 (id INT PRIMARY KEY, a_long_column_name VARCHAR(20), c INT)")
+
+	changes := []*RowChange{
+		NewRowChange(source, target, nil, []interface{}{1, "abc", 2}, sourceTI, nil, nil),
+		NewRowChange(source, target, []interface{}{1, "abc", 2}, []interface{}{1, "abcd", 3}, sourceTI, nil, nil),
+		NewRowChange(source, target, []interface{}{1, "abc", 2}, nil, sourceTI, nil, nil),
+	}
+	for _, change := range changes {
+		var tp DMLType
+		switch change.Type() {
+		case RowChangeInsert:
+			tp = DMLInsert
+		case RowChangeUpdate:
+			tp = DMLUpdate
+		case RowChangeDelete:
+			tp = DMLDelete
+		}
+		sql, args := change.GenSQL(tp)
+		require.GreaterOrEqual(t, change.EstimatedSize(), len(sql)+argsTextBytes(args), sql)
+	}
+}
+
+func TestEstimatedBatchSize(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb1"}
+	target := &cdcmodel.TableName{Schema: "db", Table: "tb"}
+	sourceTI := mockTableInfo(t, "CREATE TABLE tb1 (id INT PRIMARY KEY, a_long_column_name VARCHAR(20), c INT)")
+
+	require.Equal(t, 0, EstimatedBatchSize(DMLInsert))
+
+	inserts := make([]*RowChange, 0, 10)
+	deletes := make([]*RowChange, 0, 10)
+	updates := make([]*RowChange, 0, 10)
+	for i := 0; i < 10; i++ {
+		inserts = append(inserts, NewRowChange(source, target, nil, []interface{}{i, "abc", i}, sourceTI, nil, nil))
+		deletes = append(deletes, NewRowChange(source, target, []interface{}{i, "abc", i}, nil, sourceTI, nil, nil))
+		updates = append(updates, NewRowChange(source, target,
+			[]interface{}{i, "abc", i}, []interface{}{i, "abcd", i}, sourceTI, nil, nil))
+	}
+
+	for _, tp := range []DMLType{DMLInsert, DMLReplace, DMLInsertOnDuplicateUpdate} {
+		sql, args := GenInsertSQL(tp, inserts...)
+		size := EstimatedBatchSize(tp, inserts...)
+		require.GreaterOrEqual(t, size, len(sql)+argsTextBytes(args), sql)
+		require.Greater(t, size, EstimatedBatchSize(tp, inserts[:5]...))
+	}
+
+	sql, args := GenDeleteSQL(deletes...)
+	require.GreaterOrEqual(t, EstimatedBatchSize(DMLDelete, deletes...), len(sql)+argsTextBytes(args), sql)
+
+	expected := 0
+	for _, change := range updates {
+		expected += change.EstimatedSize()
+	}
+	require.Equal(t, expected, EstimatedBatchSize(DMLUpdate, updates...))
+}
@@ -44,6 +44,11 @@ type Manager interface {
 	// Set `forceUpdate` to force Manager update.
 	TryUpdateGCSafePoint(ctx context.Context, checkpointTs model.Ts, forceUpdate bool) error
 	CheckStaleCheckpointTs(ctx context.Context, changefeedID model.ChangeFeedID, checkpointTs model.Ts) error
+	// EnsureChangefeedStartTsSafety is EnsureChangefeedStartTsSafety, except
+	// that the safepoint cached by the last update is used if PD is unavailable,
+	// so the changefeeds can be initialized during a short PD outage.
+	EnsureChangefeedStartTsSafety(
+		ctx context.Context, changefeedID model.ChangeFeedID, TTL int64, startTs model.Ts) error
 }
 
 type gcManager struct {
@@ -54,6 +59,15 @@ type gcManager struct {
 	lastSucceededTime time.Time
 	lastSafePointTs   uint64
 	isTiCDCBlockGC    bool
+
+	// lastSafePointTime is when lastSafePointTs is got from PD, it's zero
+	// before the first successful update.
+	lastSafePointTime time.Time
+	// safePointCacheTTL is how long lastSafePointTs is used once PD is
+	// unavailable. The safepoint is refreshed every gcSafepointUpdateInterval,
+	// so it's the staleness budget after a missed refresh, which is bounded by
+	// gc-ttl since the service safepoint of TiCDC expires after it.
+	safePointCacheTTL time.Duration
 }
 
 // NewManager creates a new Manager.
@@ -62,10 +76,15 @@ func NewManager(pdClient pd.Client) Manager {
 	failpoint.Inject("InjectGcSafepointUpdateInterval", func(val failpoint.Value) {
 		gcSafepointUpdateInterval = time.Duration(val.(int) * int(time.Millisecond))
 	})
+	safePointCacheTTL := gcSafepointUpdateInterval + time.Duration(serverConfig.PDStalenessBudget)
+	if gcTTL := time.Duration(serverConfig.GcTTL) * time.Second; safePointCacheTTL > gcTTL {
+		safePointCacheTTL = gcTTL
+	}
 	return &gcManager{
 		pdClient:          pdClient,
 		lastSucceededTime: time.Now(),
 		gcTTL:             serverConfig.GcTTL,
+		safePointCacheTTL: safePointCacheTTL,
 	}
 }
 
@@ -103,6 +122,41 @@ func (m *gcManager) TryUpdateGCSafePoint(
 	m.isTiCDCBlockGC = actual == checkpointTs
 	m.lastSafePointTs = actual
 	m.lastSucceededTime = time.Now()
+	m.lastSafePointTime = m.lastSucceededTime
+	return nil
+}
+
+// cachedSafePoint returns the min service GC safepoint got by the last
+// successful update, it returns false if there is none or it has expired.
+func (m *gcManager) cachedSafePoint() (uint64, bool) {
+	if m.lastSafePointTime.IsZero() || time.Since(m.lastSafePointTime) > m.safePointCacheTTL {
+		return 0, false
+	}
+	return m.lastSafePointTs, true
+}
+
+func (m *gcManager) EnsureChangefeedStartTsSafety(
+	ctx context.Context, changefeedID model.ChangeFeedID, TTL int64, startTs model.Ts,
+) error {
+	err := EnsureChangefeedStartTsSafety(ctx, m.pdClient, changefeedID, TTL, startTs)
+	if err == nil || cerror.ErrStartTsBeforeGC.Equal(err) || ctx.Err() != nil {
+		return err
+	}
+	safePoint, ok := m.cachedSafePoint()
+	if !ok {
+		return err
+	}
+	// the service safepoint of TiCDC, which is not after the checkpoints of
+	// the changefeeds, blocks GC until gc-ttl after the last update.
+	if startTs < safePoint {
+		return cerror.ErrStartTsBeforeGC.GenWithStackByArgs(startTs, safePoint)
+	}
+	log.Warn("ensure changefeed start ts safety failed, check it with the cached gc safe point",
+		zap.String("changefeed", changefeedID),
+		zap.Uint64("startTs", startTs),
+		zap.Uint64("cachedGCSafePoint", safePoint),
+		zap.Duration("staleness", time.Since(m.lastSafePointTime)),
+		zap.Error(err))
 	return nil
 }
 
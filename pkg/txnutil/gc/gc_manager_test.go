@@ -96,7 +96,7 @@ func (s *gcManagerSuite) TestCheckStaleCheckpointTs(c *check.C) {
 	gcManager.isTiCDCBlockGC = true
 	ctx := context.Background()
 
-	clock, err := pdtime.NewClock(context.Background(), mockPDClient)
+	clock, err := pdtime.NewClock(context.Background(), mockPDClient, time.Minute)
 	c.Assert(err, check.IsNil)
 
 	go clock.Run(ctx)
@@ -120,3 +120,38 @@ func (s *gcManagerSuite) TestCheckStaleCheckpointTs(c *check.C) {
 	c.Assert(cerror.ErrSnapshotLostByGC.Equal(errors.Cause(err)), check.IsTrue)
 	c.Assert(cerror.ChangefeedFastFailError(err), check.IsTrue)
 }
+
+func (s *gcManagerSuite) TestEnsureChangefeedStartTsSafety(c *check.C) {
+	defer testleak.AfterTest(c)()
+	mockPDClient := &MockPDClient{}
+	gcManager := NewManager(mockPDClient).(*gcManager)
+	ctx := context.Background()
+
+	// a request timeout of PD isn't retried.
+	mockPDClient.UpdateServiceGCSafePointFunc = func(ctx context.Context, serviceID string, ttl int64, safePoint uint64) (uint64, error) {
+		return 0, context.DeadlineExceeded
+	}
+	// there is no cached safepoint before the first update.
+	err := gcManager.EnsureChangefeedStartTsSafety(ctx, "cfID", 600, 30)
+	c.Assert(errors.Cause(err), check.Equals, context.DeadlineExceeded)
+
+	gcManager.lastSafePointTs = 20
+	gcManager.lastSafePointTime = time.Now()
+	err = gcManager.EnsureChangefeedStartTsSafety(ctx, "cfID", 600, 30)
+	c.Assert(err, check.IsNil)
+	err = gcManager.EnsureChangefeedStartTsSafety(ctx, "cfID", 600, 10)
+	c.Assert(cerror.ErrStartTsBeforeGC.Equal(errors.Cause(err)), check.IsTrue)
+
+	// the cached safepoint has expired.
+	gcManager.lastSafePointTime = time.Now().Add(-gcManager.safePointCacheTTL - time.Second)
+	err = gcManager.EnsureChangefeedStartTsSafety(ctx, "cfID", 600, 30)
+	c.Assert(errors.Cause(err), check.Equals, context.DeadlineExceeded)
+
+	// the safepoint of PD is used once it's available.
+	mockPDClient.UpdateServiceGCSafePointFunc = func(ctx context.Context, serviceID string, ttl int64, safePoint uint64) (uint64, error) {
+		c.Assert(serviceID, check.Equals, cdcChangefeedCreatingServiceGCSafePointID+"cfID")
+		return 40, nil
+	}
+	err = gcManager.EnsureChangefeedStartTsSafety(ctx, "cfID", 600, 30)
+	c.Assert(cerror.ErrStartTsBeforeGC.Equal(errors.Cause(err)), check.IsTrue)
+}
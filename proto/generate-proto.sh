@@ -9,3 +9,4 @@ protoc --gofast_out=./canal EntryProtocol.proto
 protoc --gofast_out=./canal CanalProtocol.proto
 protoc --gofast_out=./benchmark CraftBenchmark.proto
 protoc --gofast_out=plugins=grpc:./p2p CDCPeerToPeer.proto
+protoc --gofast_out=plugins=grpc:./sinkplugin CDCSinkPlugin.proto